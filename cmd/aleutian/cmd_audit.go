@@ -0,0 +1,138 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/audit"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	auditKeyEnv     string
+	auditExpectHead string
+	auditJSON       bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// auditCmd is the parent audit command.
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the agent audit log",
+	Long: `Inspect the hash-chained audit log of agent actions.
+
+The trace server writes the log when AGENT_AUDIT_LOG is set. Every tool
+call that touches files or executes commands is recorded with its actor,
+session, tool, arguments digest, and outcome.
+
+Subcommands:
+  verify  Check the log's hash chain for tampering`,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify FILE",
+	Short: "Verify the audit log hash chain",
+	Long: `Verify that no entry in the audit log was edited, removed, or reordered.
+
+If the log was written with AGENT_AUDIT_KEY, the same key must be available
+in the environment variable named by --key-env. Pass a head hash recorded
+earlier with --expect-head to also detect truncation or a rebuilt chain;
+entries appended since that hash are allowed.
+
+Exit Codes:
+  0 = The chain verified
+  1 = Tampering detected or the log could not be read
+
+Examples:
+  aleutian audit verify ~/.aleutian/audit/agent.jsonl
+  aleutian audit verify agent.jsonl --key-env AGENT_AUDIT_KEY --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAuditVerify,
+}
+
+func init() {
+	auditCmd.PersistentFlags().BoolVar(&auditJSON, "json", false,
+		"Output as JSON for scripting")
+
+	auditVerifyCmd.Flags().StringVar(&auditKeyEnv, "key-env", "AGENT_AUDIT_KEY",
+		"Environment variable holding the chain's HMAC key (unset = unkeyed)")
+	auditVerifyCmd.Flags().StringVar(&auditExpectHead, "expect-head", "",
+		"Head hash from an earlier verify that must still be in the chain")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// runAuditVerify verifies an audit log and exits non-zero on failure.
+func runAuditVerify(cmd *cobra.Command, args []string) {
+	var key []byte
+	if auditKeyEnv != "" {
+		key = []byte(os.Getenv(auditKeyEnv))
+	}
+
+	report, err := audit.VerifyFile(args[0], key, auditExpectHead)
+
+	outputAuditReport(args[0], report, err)
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// =============================================================================
+// OUTPUT FUNCTIONS
+// =============================================================================
+
+func outputAuditReport(path string, report *audit.VerifyReport, err error) {
+	if auditJSON {
+		result := map[string]interface{}{
+			"success": err == nil,
+			"path":    path,
+			"report":  report,
+		}
+		if err != nil {
+			result["error"] = err.Error()
+			result["tampered"] = errors.Is(err, audit.ErrTampered)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "%d entries verified before the failure.\n", report.Entries)
+		return
+	}
+
+	fmt.Printf("OK: %s\n", path)
+	fmt.Printf("  Entries:   %d\n", report.Entries)
+	if report.Entries > 0 {
+		fmt.Printf("  First:     %s\n", time.UnixMilli(report.FirstTimestamp).UTC().Format(time.RFC3339))
+		fmt.Printf("  Last:      %s\n", time.UnixMilli(report.LastTimestamp).UTC().Format(time.RFC3339))
+	}
+	fmt.Printf("  Head hash: %s\n", report.HeadHash)
+}
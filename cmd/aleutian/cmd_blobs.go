@@ -0,0 +1,161 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	blobsDir  string
+	blobsJSON bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// blobsCmd is the parent blob store command.
+var blobsCmd = &cobra.Command{
+	Use:   "blobs",
+	Short: "Inspect the content-addressable blob store",
+	Long: `Inspect the blob store that holds file backups, DAG checkpoints, and
+TDG artifacts. Identical content is stored once no matter how many backups
+or checkpoints refer to it.
+
+Subcommands:
+  report  Show disk usage and deduplication savings
+  gc      Remove blobs nothing refers to`,
+}
+
+var blobsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show blob store disk usage",
+	Long: `Show how much space the blob store uses, how much deduplication saves,
+and usage per namespace (backups, checkpoints, tdg).
+
+Examples:
+  aleutian blobs report
+  aleutian blobs report --dir /var/lib/aleutian/blobs --json`,
+	Args: cobra.NoArgs,
+	Run:  runBlobsReport,
+}
+
+var blobsGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove unreferenced blobs",
+	Long: `Remove blobs that no backup, checkpoint, or artifact refers to.
+
+Blobs are normally removed when their last reference goes away; gc cleans
+up after interrupted writes. Blobs written in the last minute are kept.`,
+	Args: cobra.NoArgs,
+	Run:  runBlobsGC,
+}
+
+func init() {
+	blobsCmd.PersistentFlags().StringVar(&blobsDir, "dir", DefaultReliabilityConfig().BlobDir,
+		"Blob store directory")
+	blobsCmd.PersistentFlags().BoolVar(&blobsJSON, "json", false,
+		"Output as JSON for scripting")
+
+	blobsCmd.AddCommand(blobsReportCmd)
+	blobsCmd.AddCommand(blobsGCCmd)
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// runBlobsReport prints the blob store usage report.
+func runBlobsReport(cmd *cobra.Command, args []string) {
+	store, err := cas.Open(blobsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	report, err := store.Report()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if blobsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"dir":         blobsDir,
+			"report":      report,
+			"saved_bytes": report.Saved(),
+		})
+		return
+	}
+
+	fmt.Printf("Blob store: %s\n", blobsDir)
+	fmt.Printf("  Objects:      %d (%s)\n", report.Objects, formatBytesHuman(report.Bytes))
+	fmt.Printf("  References:   %d (%s logical)\n", report.Refs, formatBytesHuman(report.LogicalBytes))
+	fmt.Printf("  Dedup saved:  %s\n", formatBytesHuman(report.Saved()))
+	if report.UnreferencedObjects > 0 {
+		fmt.Printf("  Unreferenced: %d (%s, reclaim with 'aleutian blobs gc')\n",
+			report.UnreferencedObjects, formatBytesHuman(report.UnreferencedBytes))
+	}
+
+	if len(report.Namespaces) == 0 {
+		return
+	}
+	names := make([]string, 0, len(report.Namespaces))
+	for name := range report.Namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	fmt.Printf("  %-14s %8s %12s\n", "NAMESPACE", "REFS", "LOGICAL")
+	for _, name := range names {
+		usage := report.Namespaces[name]
+		fmt.Printf("  %-14s %8d %12s\n", name, usage.Refs, formatBytesHuman(usage.LogicalBytes))
+	}
+}
+
+// runBlobsGC removes unreferenced blobs.
+func runBlobsGC(cmd *cobra.Command, args []string) {
+	store, err := cas.Open(blobsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	stats, err := store.GC()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if blobsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"dir":             blobsDir,
+			"removed_objects": stats.Objects,
+			"removed_bytes":   stats.Bytes,
+		})
+		return
+	}
+	fmt.Printf("Removed %d unreferenced blobs (%s)\n", stats.Objects, formatBytesHuman(stats.Bytes))
+}
@@ -0,0 +1,275 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/impact"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/commitmsg"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	// Message flags
+	commitMsgStyle             string
+	commitMsgTemplate          string
+	commitMsgChangelogTemplate string
+	commitMsgType              string
+	commitMsgScope             string
+
+	// Output flags
+	commitMsgChangelog bool
+	commitMsgJSON      bool
+	commitMsgOutput    string
+)
+
+// =============================================================================
+// COMMAND DEFINITION
+// =============================================================================
+
+var commitMsgCmd = &cobra.Command{
+	Use:   "commit-msg",
+	Short: "Generate a commit message from staged changes",
+	Long: `Generate a commit message and changelog fragment for the staged changes.
+
+The staged diff is mapped to code symbols and their blast radius. The commit
+type and scope are inferred from what changed, the subject names the symbols
+with the most callers, and removing exported symbols is flagged as breaking.
+
+Styles:
+  conventional  type(scope): subject, with a short body (default)
+  simple        Subject line only
+  detailed      Conventional header plus per-symbol impact and tests
+
+Custom templates are Go text/template files executed with the generated
+output (.Message, .Input). See --template and --changelog-template.
+
+Examples:
+  aleutian commit-msg
+  aleutian commit-msg --style detailed --changelog
+  aleutian commit-msg --type fix --scope auth
+  aleutian commit-msg --output .git/COMMIT_EDITMSG
+
+prepare-commit-msg hook:
+  aleutian commit-msg --output "$1"`,
+	Args: cobra.NoArgs,
+	Run:  runCommitMsg,
+}
+
+func init() {
+	// Message flags
+	commitMsgCmd.Flags().StringVar(&commitMsgStyle, "style", commitmsg.StyleConventional,
+		"Message style: "+strings.Join(commitmsg.Styles(), ", "))
+	commitMsgCmd.Flags().StringVar(&commitMsgTemplate, "template", "",
+		"Commit message template file (overrides --style)")
+	commitMsgCmd.Flags().StringVar(&commitMsgChangelogTemplate, "changelog-template", "",
+		"Changelog fragment template file")
+	commitMsgCmd.Flags().StringVar(&commitMsgType, "type", "",
+		"Override the inferred commit type (feat, fix, refactor, ...)")
+	commitMsgCmd.Flags().StringVar(&commitMsgScope, "scope", "",
+		"Override the inferred scope ('-' for none)")
+
+	// Output flags
+	commitMsgCmd.Flags().BoolVar(&commitMsgChangelog, "changelog", false,
+		"Also print the changelog fragment")
+	commitMsgCmd.Flags().BoolVar(&commitMsgJSON, "json", false,
+		"Output as JSON for scripting")
+	commitMsgCmd.Flags().StringVarP(&commitMsgOutput, "output", "o", "",
+		"Write the commit message to a file instead of stdout")
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATION
+// =============================================================================
+
+func runCommitMsg(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := commitmsg.Options{
+		Style: commitMsgStyle,
+		Type:  commitMsgType,
+		Scope: commitMsgScope,
+	}
+	if commitMsgTemplate != "" {
+		data, err := os.ReadFile(commitMsgTemplate)
+		if err != nil {
+			outputCommitMsgError("Failed to read template", err)
+			os.Exit(exitCodeFor(err, 1))
+		}
+		opts.CommitTemplate = string(data)
+	}
+	if commitMsgChangelogTemplate != "" {
+		data, err := os.ReadFile(commitMsgChangelogTemplate)
+		if err != nil {
+			outputCommitMsgError("Failed to read changelog template", err)
+			os.Exit(exitCodeFor(err, 1))
+		}
+		opts.ChangelogTemplate = string(data)
+	}
+
+	gen, err := commitmsg.NewGenerator(opts)
+	if err != nil {
+		outputCommitMsgError("Invalid message options", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	// Load index
+	cwd, err := os.Getwd()
+	if err != nil {
+		outputCommitMsgError("Failed to get working directory", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	storage := initializer.NewStorage(cwd)
+	if !storage.Exists() {
+		outputCommitMsgError("Cannot load index", initializer.ErrIndexNotFound)
+		os.Exit(exitCodeFor(initializer.ErrIndexNotFound, 1))
+	}
+
+	index, err := storage.LoadIndex(ctx)
+	if err != nil {
+		outputCommitMsgError("Failed to load index", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	// Analyze staged changes
+	cfg := impact.DefaultConfig()
+	cfg.Mode = impact.ChangeModeStaged
+	cfg.IncludeTests = true
+
+	result, err := impact.NewAnalyzer(index, cwd).Analyze(ctx, cfg)
+	if err != nil {
+		outputCommitMsgError("Analysis failed", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	output, err := gen.Generate(commitInputFromImpact(result))
+	if err != nil {
+		outputCommitMsgError("Failed to generate message", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	if commitMsgOutput != "" {
+		if err := os.WriteFile(commitMsgOutput, []byte(output.CommitText), 0644); err != nil {
+			outputCommitMsgError("Failed to write message", err)
+			os.Exit(exitCodeFor(err, 1))
+		}
+	}
+
+	switch {
+	case commitMsgJSON:
+		outputCommitMsgJSON(output)
+	case commitMsgOutput != "":
+		if commitMsgChangelog {
+			fmt.Print(output.Changelog)
+		}
+	default:
+		outputCommitMsgText(output, commitMsgChangelog)
+	}
+}
+
+// commitInputFromImpact converts an impact analysis result into generator
+// input. Symbol status follows the change type of the file containing it,
+// and callers are the affected symbols traced back to each changed symbol.
+func commitInputFromImpact(result *impact.Result) commitmsg.Input {
+	in := commitmsg.Input{
+		RiskLevel:     string(result.RiskLevel),
+		AffectedTests: result.AffectedTests,
+	}
+
+	for _, f := range result.ChangedFiles {
+		in.Files = append(in.Files, commitmsg.FileChange{
+			Path:    f.Path,
+			OldPath: f.OldPath,
+			Status:  commitmsg.ParseStatus(string(f.ChangeType)),
+		})
+	}
+
+	callers := make(map[string]int)
+	for _, a := range result.AffectedSymbols {
+		callers[a.SourceID]++
+	}
+
+	for _, cs := range result.ChangedSymbols {
+		in.Symbols = append(in.Symbols, commitmsg.SymbolChange{
+			Name:     cs.Symbol.Name,
+			Kind:     cs.Symbol.Kind,
+			FilePath: cs.FilePath,
+			Exported: isExportedName(cs.Symbol.Name),
+			Status:   commitmsg.ParseStatus(string(cs.ChangeType)),
+			Callers:  callers[cs.Symbol.ID],
+		})
+	}
+
+	return in
+}
+
+// isExportedName reports whether a Go identifier is exported.
+func isExportedName(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// =============================================================================
+// OUTPUT FUNCTIONS
+// =============================================================================
+
+func outputCommitMsgError(msg string, err error) {
+	if commitMsgJSON {
+		result := map[string]interface{}{
+			"success": false,
+			"error":   msg,
+		}
+		if err != nil {
+			result["error"] = fmt.Sprintf("%s: %v", msg, err)
+			for k, v := range errorFields(err) {
+				result[k] = v
+			}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+	} else {
+		printError(msg, err)
+	}
+}
+
+func outputCommitMsgJSON(output *commitmsg.Output) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func outputCommitMsgText(output *commitmsg.Output, withChangelog bool) {
+	fmt.Print(output.CommitText)
+	if withChangelog {
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Print(output.Changelog)
+	}
+}
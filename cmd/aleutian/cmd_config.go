@@ -0,0 +1,202 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	configProject string
+	configJSON    bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// configCmd is the parent project settings command.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change project settings",
+	Long: `View and change the settings stored in .aleutian/config.yaml.
+
+The trace service reads the same file and reloads it when it changes, so
+settings take effect for the next request without restarting the stack.
+
+Settings:
+  languages             Languages indexed for the project
+  exclude_patterns      Globs of paths ignored when indexing
+  lint_profile          default, strict (warnings block) or relaxed (nothing blocks)
+  tool_policy.enabled   If set, the only agent tools sessions may use
+  tool_policy.disabled  Agent tools sessions may not use
+
+List settings take comma-separated values; an empty value clears them.
+
+Subcommands:
+  get  Show one or all settings
+  set  Change a setting`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show project settings",
+	Long: `Show one setting, or all settings if no key is given.
+
+Examples:
+  aleutian config get
+  aleutian config get lint_profile
+  aleutian config get --project ./myproject --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a project setting",
+	Long: `Change a setting in .aleutian/config.yaml, creating the file if needed.
+
+Examples:
+  aleutian config set lint_profile strict
+  aleutian config set tool_policy.disabled write_file,edit_file
+  aleutian config set exclude_patterns "vendor/*,*_test.go"
+  aleutian config set tool_policy.enabled ""`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSet,
+}
+
+func init() {
+	configCmd.PersistentFlags().StringVar(&configProject, "project", ".",
+		"Project root directory")
+	configCmd.PersistentFlags().BoolVar(&configJSON, "json", false,
+		"Output as JSON for scripting")
+
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// runConfigGet prints project settings.
+func runConfigGet(cmd *cobra.Command, args []string) {
+	root, cfg, err := loadProjectSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	keys := projectconfig.Keys
+	if len(args) == 1 {
+		keys = []string{args[0]}
+	}
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, err := projectconfig.Get(cfg, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		values[key] = value
+	}
+
+	if configJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"project":  root,
+			"settings": values,
+		})
+		return
+	}
+
+	if len(args) == 1 {
+		fmt.Println(values[args[0]])
+		return
+	}
+	for _, key := range keys {
+		fmt.Printf("%-22s %s\n", key, values[key])
+	}
+}
+
+// runConfigSet changes one project setting and saves the file.
+func runConfigSet(cmd *cobra.Command, args []string) {
+	root, cfg, err := loadProjectSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	key, value := args[0], args[1]
+	if err := projectconfig.Set(cfg, key, value); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	now := time.Now().Format(time.RFC3339)
+	if cfg.CreatedAt == "" {
+		cfg.CreatedAt = now
+	}
+	cfg.UpdatedAt = now
+
+	if err := projectconfig.Save(root, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	current, _ := projectconfig.Get(cfg, key)
+	if configJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"project": root,
+			"key":     key,
+			"value":   current,
+		})
+		return
+	}
+	fmt.Printf("%s = %s (%s)\n", key, current, projectconfig.Path(root))
+}
+
+// loadProjectSettings resolves --project and loads its settings. A project
+// without a settings file gets empty settings.
+func loadProjectSettings() (string, *projectconfig.Config, error) {
+	root, err := filepath.Abs(configProject)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving project path: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return "", nil, fmt.Errorf("project %s is not a directory", root)
+	}
+
+	cfg, err := projectconfig.Load(root)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return root, &projectconfig.Config{FormatVersion: initializer.FormatVersion}, nil
+	case err != nil:
+		return "", nil, fmt.Errorf("%w\nfix the file by hand or rerun 'aleutian init --force'", err)
+	}
+	return root, cfg, nil
+}
@@ -185,7 +185,7 @@ func runGraphCallers(cmd *cobra.Command, args []string) {
 	index, err := loadGraphIndex()
 	if err != nil {
 		outputGraphError("Failed to load index", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Build config
@@ -208,13 +208,13 @@ func runGraphCallers(cmd *cobra.Command, args []string) {
 	result, err := querier.FindCallers(ctx, symbol, cfg)
 	if err != nil {
 		outputGraphError("Query failed", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Check for empty results
 	if cfg.FailIfEmpty && result.TotalCount == 0 {
 		outputGraphError("No callers found", graph.ErrNoResults)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(graph.ErrNoResults, graph.ExitError))
 	}
 
 	// Output
@@ -238,7 +238,7 @@ func runGraphCallees(cmd *cobra.Command, args []string) {
 	index, err := loadGraphIndex()
 	if err != nil {
 		outputGraphError("Failed to load index", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Build config
@@ -261,13 +261,13 @@ func runGraphCallees(cmd *cobra.Command, args []string) {
 	result, err := querier.FindCallees(ctx, symbol, cfg)
 	if err != nil {
 		outputGraphError("Query failed", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Check for empty results
 	if cfg.FailIfEmpty && result.TotalCount == 0 {
 		outputGraphError("No callees found", graph.ErrNoResults)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(graph.ErrNoResults, graph.ExitError))
 	}
 
 	// Output
@@ -292,7 +292,7 @@ func runGraphPath(cmd *cobra.Command, args []string) {
 	index, err := loadGraphIndex()
 	if err != nil {
 		outputGraphError("Failed to load index", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Build config
@@ -308,13 +308,13 @@ func runGraphPath(cmd *cobra.Command, args []string) {
 	result, err := querier.FindPath(ctx, fromSymbol, toSymbol, cfg, graphAllPaths, graphMaxPaths)
 	if err != nil {
 		outputGraphError("Query failed", err)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(err, graph.ExitError))
 	}
 
 	// Check for empty results
 	if cfg.FailIfEmpty && !result.PathFound {
 		outputGraphError("No path found", graph.ErrNoResults)
-		os.Exit(graph.ExitError)
+		os.Exit(exitCodeFor(graph.ErrNoResults, graph.ExitError))
 	}
 
 	// Output
@@ -361,11 +361,14 @@ func outputGraphError(msg string, err error) {
 			"success":     false,
 			"error":       err.Error(),
 		}
+		for k, v := range errorFields(err) {
+			result[k] = v
+		}
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		encoder.Encode(result)
 	} else {
-		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
+		printError(msg, err)
 	}
 }
 
@@ -0,0 +1,351 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/hooks"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	// Install flags
+	hooksInstallList         []string
+	hooksPreCommitLevel      string
+	hooksPrePushLevel        string
+	hooksInstallAllowSecrets bool
+	hooksInstallDaemon       string
+	hooksBinary              string
+
+	// Run flags
+	hooksRunThreshold    string
+	hooksRunAllowSecrets bool
+	hooksRunDaemonAddr   string
+	hooksRunNoDaemon     bool
+	hooksRunBase         string
+	hooksRunTimeout      int
+
+	// Shared
+	hooksJSON bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// hooksCmd is the parent hooks command.
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that gate commits and pushes on risk",
+	Long: `Install git hooks that run impact, risk, and secret-scan checks.
+
+pre-commit assesses staged changes; pre-push assesses changes since the
+upstream branch. If 'aleutian watch' is running, hooks reuse its warm index
+and typically finish in under two seconds.
+
+Subcommands:
+  install    Install hooks into the repository
+  uninstall  Remove Aleutian hooks and restore any previous hooks
+  status     Show which hooks are installed
+  run        Run a hook's checks (called by the installed scripts)
+
+Examples:
+  aleutian hooks install
+  aleutian hooks install --hooks pre-commit --pre-commit-level medium
+  aleutian hooks status
+  aleutian hooks uninstall`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install pre-commit and pre-push hooks",
+	Long: `Install Aleutian git hooks into the repository's hooks directory.
+
+Existing hooks that Aleutian did not write are kept: they are renamed to
+<hook>.aleutian-backup and run first by the installed hook.
+
+Blocking levels (low, medium, high, critical) set the highest risk that is
+still allowed through. Critical secret-scan findings block regardless of
+level unless --allow-secrets is given.`,
+	Args: cobra.NoArgs,
+	Run:  runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove Aleutian hooks",
+	Args:  cobra.NoArgs,
+	Run:   runHooksUninstall,
+}
+
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show installed hooks",
+	Args:  cobra.NoArgs,
+	Run:   runHooksStatus,
+}
+
+var hooksRunCmd = &cobra.Command{
+	Use:   "run HOOK",
+	Short: "Run the checks for a hook",
+	Long: `Run the checks for pre-commit or pre-push.
+
+Exit Codes:
+  0 = Allowed (including when the analysis could not run)
+  1 = Blocked: risk above the blocking level or critical secrets found`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHooksRun,
+}
+
+func init() {
+	hooksCmd.PersistentFlags().BoolVar(&hooksJSON, "json", false,
+		"Output as JSON for scripting")
+
+	hooksInstallCmd.Flags().StringSliceVar(&hooksInstallList, "hooks", []string{"pre-commit", "pre-push"},
+		"Hooks to install")
+	hooksInstallCmd.Flags().StringVar(&hooksPreCommitLevel, "pre-commit-level",
+		strings.ToLower(string(hooks.DefaultThreshold(hooks.HookPreCommit))),
+		"Blocking level for pre-commit: low, medium, high, critical")
+	hooksInstallCmd.Flags().StringVar(&hooksPrePushLevel, "pre-push-level",
+		strings.ToLower(string(hooks.DefaultThreshold(hooks.HookPrePush))),
+		"Blocking level for pre-push: low, medium, high, critical")
+	hooksInstallCmd.Flags().BoolVar(&hooksInstallAllowSecrets, "allow-secrets", false,
+		"Do not block on critical secret-scan findings")
+	hooksInstallCmd.Flags().StringVar(&hooksInstallDaemon, "daemon-addr", "",
+		"Watch daemon address for the fast path (host:port or unix:/path)")
+	hooksInstallCmd.Flags().StringVar(&hooksBinary, "binary", "aleutian",
+		"Command the hook scripts use to invoke aleutian")
+
+	hooksUninstallCmd.Flags().StringSliceVar(&hooksInstallList, "hooks", []string{"pre-commit", "pre-push"},
+		"Hooks to remove")
+
+	hooksRunCmd.Flags().StringVar(&hooksRunThreshold, "threshold", "",
+		"Blocking level (default: high for pre-commit, medium for pre-push)")
+	hooksRunCmd.Flags().BoolVar(&hooksRunAllowSecrets, "allow-secrets", false,
+		"Do not block on critical secret-scan findings")
+	hooksRunCmd.Flags().StringVar(&hooksRunDaemonAddr, "daemon-addr", "",
+		"Watch daemon address (host:port or unix:/path)")
+	hooksRunCmd.Flags().BoolVar(&hooksRunNoDaemon, "no-daemon", false,
+		"Skip the watch daemon fast path")
+	hooksRunCmd.Flags().StringVar(&hooksRunBase, "base", "",
+		"Base ref for pre-push (default: upstream branch)")
+	hooksRunCmd.Flags().IntVar(&hooksRunTimeout, "timeout", 60,
+		"Timeout in seconds for the local assessment")
+
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksCmd.AddCommand(hooksStatusCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// runHooksInstall installs the selected hooks.
+func runHooksInstall(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg := hooksConfigFromFlags()
+	cfg.Thresholds[hooks.HookPreCommit] = risk.ParseRiskLevel(hooksPreCommitLevel)
+	cfg.Thresholds[hooks.HookPrePush] = risk.ParseRiskLevel(hooksPrePushLevel)
+	cfg.BlockSecrets = !hooksInstallAllowSecrets
+	cfg.DaemonAddr = hooksInstallDaemon
+	cfg.Binary = hooksBinary
+
+	statuses, err := hooks.Install(ctx, cfg)
+	if err != nil {
+		outputHooksError("Install failed", err)
+		os.Exit(1)
+	}
+	outputHookStatuses("Installed", statuses)
+}
+
+// runHooksUninstall removes the selected hooks.
+func runHooksUninstall(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	statuses, err := hooks.Uninstall(ctx, hooksConfigFromFlags())
+	if err != nil {
+		outputHooksError("Uninstall failed", err)
+		os.Exit(1)
+	}
+	outputHookStatuses("Uninstalled", statuses)
+}
+
+// runHooksStatus prints hook status.
+func runHooksStatus(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		outputHooksError("Failed to get working directory", err)
+		os.Exit(1)
+	}
+	statuses, err := hooks.Status(ctx, cwd)
+	if err != nil {
+		outputHooksError("Status failed", err)
+		os.Exit(1)
+	}
+	outputHookStatuses("Status", statuses)
+}
+
+// runHooksRun runs a hook's checks. Analysis errors fail open.
+func runHooksRun(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(hooksRunTimeout)*time.Second)
+	defer cancel()
+
+	hook, err := hooks.ParseHookType(args[0])
+	if err != nil {
+		outputHooksError("Invalid hook", err)
+		os.Exit(1)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aleutian %s: skipped: %v\n", hook, err)
+		os.Exit(0)
+	}
+
+	cfg := hooks.RunConfig{
+		Hook:         hook,
+		ProjectRoot:  cwd,
+		BlockSecrets: !hooksRunAllowSecrets,
+		BaseRef:      hooksRunBase,
+		DaemonAddr:   hooksRunDaemonAddr,
+		NoDaemon:     hooksRunNoDaemon,
+		Timeout:      time.Duration(hooksRunTimeout) * time.Second,
+	}
+	if hooksRunThreshold != "" {
+		cfg.Threshold = risk.ParseRiskLevel(hooksRunThreshold)
+	}
+
+	out, err := hooks.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aleutian %s: skipped: %v\n", hook, err)
+		os.Exit(0)
+	}
+
+	if hooksJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(out)
+	} else {
+		outputHookOutcome(out)
+	}
+
+	if out.Blocked {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// hooksConfigFromFlags builds an install/uninstall config for the cwd.
+func hooksConfigFromFlags() hooks.Config {
+	cwd, err := os.Getwd()
+	if err != nil {
+		outputHooksError("Failed to get working directory", err)
+		os.Exit(1)
+	}
+
+	cfg := hooks.DefaultConfig(cwd)
+	cfg.Hooks = cfg.Hooks[:0]
+	for _, name := range hooksInstallList {
+		h, err := hooks.ParseHookType(strings.TrimSpace(name))
+		if err != nil {
+			outputHooksError("Invalid hook", err)
+			os.Exit(1)
+		}
+		cfg.Hooks = append(cfg.Hooks, h)
+	}
+	return cfg
+}
+
+// =============================================================================
+// OUTPUT FUNCTIONS
+// =============================================================================
+
+func outputHooksError(msg string, err error) {
+	if hooksJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("%s: %v", msg, err),
+		})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
+}
+
+func outputHookStatuses(title string, statuses []hooks.HookStatus) {
+	if hooksJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(statuses)
+		return
+	}
+
+	fmt.Printf("%s:\n", title)
+	for _, st := range statuses {
+		state := "not installed"
+		switch {
+		case st.Managed:
+			state = "aleutian"
+		case st.Installed:
+			state = "other (not managed by aleutian)"
+		}
+		if st.HasBackup {
+			state += ", chains previous hook"
+		}
+		fmt.Printf("  %-11s %s\n", st.Hook, state)
+	}
+}
+
+func outputHookOutcome(out *hooks.Outcome) {
+	for _, w := range out.Warnings {
+		fmt.Fprintf(os.Stderr, "aleutian %s: warning: %s\n", out.Hook, w)
+	}
+
+	level := "unknown"
+	if out.Result != nil {
+		level = string(out.Result.RiskLevel)
+	}
+	fmt.Fprintf(os.Stderr, "aleutian %s: risk %s (block above %s, %s, %dms)\n",
+		out.Hook, level, out.Threshold, out.Source, out.DurationMs)
+
+	if !out.Blocked {
+		return
+	}
+	for _, r := range out.Reasons {
+		fmt.Fprintf(os.Stderr, "  ✗ %s\n", r)
+	}
+	if out.Result != nil {
+		for _, f := range out.Result.Factors {
+			fmt.Fprintf(os.Stderr, "    - [%s] %s\n", f.Signal, f.Message)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Blocked. Review the findings above or bypass with --no-verify.\n")
+}
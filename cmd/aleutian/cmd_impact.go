@@ -176,19 +176,19 @@ func runImpact(cmd *cobra.Command, args []string) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		outputImpactError("Failed to get working directory", err)
-		os.Exit(impact.ExitError)
+		os.Exit(exitCodeFor(err, impact.ExitError))
 	}
 
 	storage := initializer.NewStorage(cwd)
 	if !storage.Exists() {
-		outputImpactError("Index not found", fmt.Errorf("run 'aleutian init' first"))
-		os.Exit(impact.ExitError)
+		outputImpactError("Cannot load index", initializer.ErrIndexNotFound)
+		os.Exit(exitCodeFor(initializer.ErrIndexNotFound, impact.ExitError))
 	}
 
 	index, err := storage.LoadIndex(ctx)
 	if err != nil {
 		outputImpactError("Failed to load index", err)
-		os.Exit(impact.ExitError)
+		os.Exit(exitCodeFor(err, impact.ExitError))
 	}
 
 	// Run analysis
@@ -196,7 +196,7 @@ func runImpact(cmd *cobra.Command, args []string) {
 	result, err := analyzer.Analyze(ctx, cfg)
 	if err != nil {
 		outputImpactError("Analysis failed", err)
-		os.Exit(impact.ExitError)
+		os.Exit(exitCodeFor(err, impact.ExitError))
 	}
 
 	// Output
@@ -228,16 +228,15 @@ func outputImpactError(msg string, err error) {
 		}
 		if err != nil {
 			result["error"] = fmt.Sprintf("%s: %v", msg, err)
+			for k, v := range errorFields(err) {
+				result[k] = v
+			}
 		}
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		encoder.Encode(result)
 	} else {
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
-		}
+		printError(msg, err)
 	}
 }
 
@@ -27,14 +27,15 @@ import (
 // =============================================================================
 
 var (
-	initForce      bool     // Rebuild index even if exists
-	initLanguages  []string // Limit to specific languages
-	initExcludes   []string // Glob patterns to exclude
-	initJSONOutput bool     // Output as JSON
-	initQuiet      bool     // Suppress progress output
-	initVerbose    bool     // Show detailed output
-	initDryRun     bool     // Show what would be indexed without writing
-	initMaxWorkers int      // Maximum parallel workers
+	initForce        bool     // Rebuild index even if exists
+	initLanguages    []string // Limit to specific languages
+	initExcludes     []string // Glob patterns to exclude
+	initJSONOutput   bool     // Output as JSON
+	initQuiet        bool     // Suppress progress output
+	initVerbose      bool     // Show detailed output
+	initDryRun       bool     // Show what would be indexed without writing
+	initMaxWorkers   int      // Maximum parallel workers
+	initFixedWorkers bool     // Disable worker auto-tuning
 )
 
 // =============================================================================
@@ -81,7 +82,9 @@ and stores results in a .aleutian/ directory for fast code analysis.
 The initialization process:
   1. Detects programming languages in the project
   2. Scans for source files (excluding vendor, node_modules, etc.)
-  3. Parses files in parallel to extract symbols and relationships
+  3. Parses files in parallel to extract symbols and relationships. The
+     number of workers is tuned from measured parse throughput and file
+     read time, up to --max-workers.
   4. Builds call graph with caller/callee relationships
   5. Stores index in .aleutian/ directory
 
@@ -117,8 +120,10 @@ func init() {
 		"Show detailed per-file progress")
 	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false,
 		"Show what would be indexed without writing")
-	initCmd.Flags().IntVar(&initMaxWorkers, "max-workers", initializer.OptimalWorkerCount(),
+	initCmd.Flags().IntVar(&initMaxWorkers, "max-workers", initializer.DefaultMaxWorkers,
 		"Maximum parallel workers for parsing")
+	initCmd.Flags().BoolVar(&initFixedWorkers, "fixed-workers", false,
+		"Always use --max-workers workers instead of tuning the count")
 }
 
 // =============================================================================
@@ -166,23 +171,25 @@ func runInitCommand(cmd *cobra.Command, args []string) {
 	// Validate path exists
 	info, err := os.Stat(absPath)
 	if os.IsNotExist(err) {
-		outputError("Path does not exist", fmt.Errorf("%s", absPath))
-		os.Exit(initializer.ExitBadArgs)
+		err = initializer.ErrPathNotExist.Withf("path does not exist: %s", absPath)
+		outputError("Invalid path", err)
+		os.Exit(exitCodeFor(err, initializer.ExitBadArgs))
 	}
 	if err != nil {
 		outputError("Cannot access path", err)
 		os.Exit(initializer.ExitBadArgs)
 	}
 	if !info.IsDir() {
-		outputError("Path is not a directory", fmt.Errorf("%s", absPath))
-		os.Exit(initializer.ExitBadArgs)
+		err = initializer.ErrPathNotDirectory.Withf("path is not a directory: %s", absPath)
+		outputError("Invalid path", err)
+		os.Exit(exitCodeFor(err, initializer.ExitBadArgs))
 	}
 
 	// Check if index already exists
 	storage := initializer.NewStorage(absPath)
 	if storage.Exists() && !initForce && !initDryRun {
-		outputError("Index already exists", fmt.Errorf("use --force to rebuild"))
-		os.Exit(initializer.ExitBadArgs)
+		outputError("Cannot initialize", initializer.ErrIndexExists)
+		os.Exit(exitCodeFor(initializer.ErrIndexExists, initializer.ExitBadArgs))
 	}
 
 	// Build configuration
@@ -196,6 +203,7 @@ func runInitCommand(cmd *cobra.Command, args []string) {
 	cfg.Quiet = initQuiet
 	cfg.Verbose = initVerbose
 	cfg.MaxWorkers = initMaxWorkers
+	cfg.AdaptiveWorkers = !initFixedWorkers
 
 	// Create progress callback
 	var progressCb initializer.ProgressCallback
@@ -233,7 +241,7 @@ func runInitCommand(cmd *cobra.Command, args []string) {
 		} else {
 			outputError("Initialization failed", err)
 		}
-		os.Exit(initializer.ExitFailure)
+		os.Exit(exitCodeFor(err, initializer.ExitFailure))
 	}
 
 	// Output results
@@ -270,6 +278,8 @@ func outputInitResultText(result *initializer.Result) {
 	fmt.Printf("║  Symbols found:   %10d                                      ║\n", result.SymbolsFound)
 	fmt.Printf("║  Call edges:      %10d                                      ║\n", result.EdgesBuilt)
 	fmt.Printf("║  Duration:        %10.2fs                                     ║\n", float64(result.DurationMs)/1000)
+	fmt.Printf("║  %-64s║\n", formatWorkers(result.Workers))
+	fmt.Printf("║  %-64s║\n", formatStageTimings(result.Timings))
 	fmt.Printf("╠══════════════════════════════════════════════════════════════════╣\n")
 	fmt.Printf("║  Index path:  %-52s║\n", result.IndexPath)
 	fmt.Printf("╚══════════════════════════════════════════════════════════════════╝\n")
@@ -288,9 +298,24 @@ func outputInitResultText(result *initializer.Result) {
 	}
 }
 
+// formatWorkers formats the parse concurrency for the init summary.
+func formatWorkers(w initializer.WorkerStats) string {
+	mode := "fixed"
+	if w.Adaptive {
+		mode = "adaptive"
+	}
+	return fmt.Sprintf("Workers:         %10d  (%s, peak %d, max %d)", w.Final, mode, w.Peak, w.Max)
+}
+
+// formatStageTimings formats per-stage timings for the init summary.
+func formatStageTimings(t initializer.StageTimings) string {
+	return fmt.Sprintf("Stages:  detect %dms, scan %dms, parse %dms, write %dms",
+		t.DetectMs, t.ScanMs, t.ParseMs, t.WriteMs)
+}
+
 // outputError outputs an error message.
 func outputError(msg string, err error) {
-	fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
+	printError(msg, err)
 }
 
 // outputErrorJSON outputs an error as JSON.
@@ -300,6 +325,9 @@ func outputErrorJSON(err error) {
 		"success":     false,
 		"error":       err.Error(),
 	}
+	for k, v := range errorFields(err) {
+		result[k] = v
+	}
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	encoder.Encode(result)
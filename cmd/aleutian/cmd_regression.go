@@ -0,0 +1,349 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval/regression"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	regressionDB          string
+	regressionComponent   string
+	regressionWindow      int
+	regressionMinR2       float64
+	regressionFailOnTrend bool
+	regressionJSON        bool
+
+	regressionRequireBaseline bool
+	regressionFailOnWarnings  bool
+	regressionNoTrend         bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// regressionCmd is the parent regression gate command.
+var regressionCmd = &cobra.Command{
+	Use:   "regression",
+	Short: "Inspect performance regression history",
+	Long: `Inspect the baseline history recorded by the regression gate.
+
+The gate records a run in the SQLite baseline database each time a check
+passes. The history keeps the last 100 runs per component.
+
+Subcommands:
+  check  Check benchmark results against the baseline and record the run
+  trend  Report metrics drifting across recent runs`,
+}
+
+var regressionCheckCmd = &cobra.Command{
+	Use:   "check <metrics.json>",
+	Short: "Check benchmark results against the recorded baseline",
+	Long: `Compare benchmark results against the latest recorded run of each
+component. A passing result is recorded as a new run, so it becomes the
+baseline for the next check and a data point for 'aleutian regression trend'.
+A component with no recorded runs passes and starts its history.
+
+The metrics file is a JSON object mapping component names to results:
+
+  {"search": {"latency": {"p50": 1200000, "p95": 3100000, "p99": 5000000},
+              "throughput": {"ops_per_second": 830},
+              "memory": {"alloc_bytes_per_op": 2048},
+              "error_rate": 0, "sample_count": 1000}}
+
+Latencies are in nanoseconds. Drift across recent runs is reported as a
+warning unless --no-trend is set.
+
+Exit Codes:
+  0 = Every component passed
+  1 = A component regressed, or the metrics or database could not be read
+
+Examples:
+  aleutian regression check bench.json
+  aleutian regression check bench.json --db ci/baselines.db --fail-on-warnings`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRegressionCheck,
+}
+
+var regressionTrendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Report slow performance drift across runs",
+	Long: `Fit a trend line to each metric over the most recent runs and report
+metrics whose drift across the window exceeds the threshold a single run is
+held to. This finds slow creep, such as 1% slower per build, that never
+fails a single gate check.
+
+Exit Codes:
+  0 = Report printed (or no drift, with --fail-on-trend)
+  1 = Drift found with --fail-on-trend, or the database could not be read
+
+Examples:
+  aleutian regression trend
+  aleutian regression trend --component search --window 20
+  aleutian regression trend --db ci/baselines.db --fail-on-trend --json`,
+	Args: cobra.NoArgs,
+	Run:  runRegressionTrend,
+}
+
+func init() {
+	regressionCmd.PersistentFlags().StringVar(&regressionDB, "db",
+		filepath.Join(DefaultReliabilityConfig().DataDir, "regression", "baselines.db"),
+		"Baseline history database")
+	regressionCmd.PersistentFlags().BoolVar(&regressionJSON, "json", false,
+		"Output as JSON for scripting")
+
+	defaults := regression.DefaultTrendConfig()
+	regressionTrendCmd.Flags().StringVar(&regressionComponent, "component", "",
+		"Component to report (default: all)")
+	regressionTrendCmd.Flags().IntVar(&regressionWindow, "window", defaults.Window,
+		"Number of recent runs to analyze")
+	regressionTrendCmd.Flags().Float64Var(&regressionMinR2, "min-r2", defaults.MinR2,
+		"Minimum fit quality (0-1) for drift to count as a trend")
+	regressionTrendCmd.Flags().BoolVar(&regressionFailOnTrend, "fail-on-trend", false,
+		"Exit 1 if any component is drifting")
+
+	regressionCheckCmd.Flags().BoolVar(&regressionRequireBaseline, "require-baseline", false,
+		"Fail components that have no recorded runs")
+	regressionCheckCmd.Flags().BoolVar(&regressionFailOnWarnings, "fail-on-warnings", false,
+		"Fail on warnings, including trend drift")
+	regressionCheckCmd.Flags().BoolVar(&regressionNoTrend, "no-trend", false,
+		"Skip trend detection")
+
+	regressionCmd.AddCommand(regressionCheckCmd)
+	regressionCmd.AddCommand(regressionTrendCmd)
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// runRegressionCheck runs the regression gate over a metrics file,
+// recording each passing component as a new run.
+func runRegressionCheck(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		printError("cannot read metrics", err)
+		os.Exit(1)
+	}
+	var metrics map[string]*regression.CurrentMetrics
+	if err := json.Unmarshal(raw, &metrics); err != nil {
+		printError("invalid metrics file", err)
+		os.Exit(1)
+	}
+	if len(metrics) == 0 {
+		printError("metrics file has no components", nil)
+		os.Exit(1)
+	}
+
+	store, err := regression.OpenSQLiteBaseline(regressionDB, 0)
+	if err != nil {
+		printError("cannot open baseline database", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	opts := []regression.GateOption{
+		regression.WithUpdateBaseline(true),
+		regression.WithRequireBaseline(regressionRequireBaseline),
+		regression.WithFailOnWarnings(regressionFailOnWarnings),
+		regression.WithGateLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}
+	if !regressionNoTrend {
+		opts = append(opts, regression.WithTrendDetection(nil))
+	}
+	gate := regression.NewGate(store, opts...)
+
+	decisions, err := gate.CheckAll(ctx, metrics)
+	if err != nil {
+		printError("regression check failed", err)
+		os.Exit(1)
+	}
+
+	components := make([]string, 0, len(decisions))
+	pass := true
+	for component, decision := range decisions {
+		components = append(components, component)
+		pass = pass && decision.Pass
+	}
+	sort.Strings(components)
+
+	if regressionJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"db":        regressionDB,
+			"pass":      pass,
+			"decisions": decisions,
+		})
+	} else {
+		for i, component := range components {
+			if i > 0 {
+				fmt.Println()
+			}
+			printGateDecision(decisions[component])
+		}
+	}
+
+	if !pass {
+		os.Exit(1)
+	}
+}
+
+// printGateDecision prints one component's gate result.
+func printGateDecision(decision *regression.GateDecision) {
+	status := "PASS"
+	if !decision.Pass {
+		status = "FAIL"
+	}
+	recorded := ""
+	if decision.BaselineUpdated {
+		recorded = " (run recorded)"
+	}
+	fmt.Printf("Component: %s %s%s\n", decision.Component, status, recorded)
+	for _, r := range decision.Regressions {
+		fmt.Printf("  regression: %s\n", r.Message)
+	}
+	for _, w := range decision.Warnings {
+		fmt.Printf("  warning:    %s\n", w.Message)
+	}
+}
+
+// runRegressionTrend prints the trend report for one or all components.
+func runRegressionTrend(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := os.Stat(regressionDB); err != nil {
+		printError("baseline database not found", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+	store, err := regression.OpenSQLiteBaseline(regressionDB, 0)
+	if err != nil {
+		printError("cannot open baseline database", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	components := []string{regressionComponent}
+	if regressionComponent == "" {
+		if components, err = store.List(ctx); err != nil {
+			printError("cannot list components", err)
+			os.Exit(1)
+		}
+	}
+
+	config := regression.DefaultTrendConfig()
+	config.Window = regressionWindow
+	config.MinR2 = regressionMinR2
+
+	reports := make([]*regression.TrendReport, 0, len(components))
+	drifting := false
+	for _, component := range components {
+		runs, err := store.Runs(ctx, component, regressionWindow)
+		if err != nil {
+			printError(fmt.Sprintf("cannot read runs for %s", component), err)
+			os.Exit(1)
+		}
+		report := regression.AnalyzeTrend(component, runs, config)
+		drifting = drifting || report.HasRegressions()
+		reports = append(reports, report)
+	}
+
+	if regressionJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"db":       regressionDB,
+			"window":   regressionWindow,
+			"drifting": drifting,
+			"reports":  reports,
+		})
+	} else {
+		for i, report := range reports {
+			if i > 0 {
+				fmt.Println()
+			}
+			printTrendReport(report, config.MinRuns)
+		}
+	}
+
+	if drifting && regressionFailOnTrend {
+		os.Exit(1)
+	}
+}
+
+// printTrendReport prints one component's trend as a table.
+func printTrendReport(report *regression.TrendReport, minRuns int) {
+	fmt.Printf("Component: %s (%d runs", report.Component, report.Runs)
+	if report.Runs > 0 {
+		fmt.Printf(", %s to %s", report.From.Format(time.DateTime), report.To.Format(time.DateTime))
+	}
+	fmt.Println(")")
+
+	if len(report.Metrics) == 0 {
+		fmt.Printf("  Not enough runs for a trend (need %d)\n", minRuns)
+		return
+	}
+
+	drifting := make(map[regression.RegressionType]bool, len(report.Regressions))
+	for _, r := range report.Regressions {
+		drifting[r.Type] = true
+	}
+
+	fmt.Printf("  %-12s %12s %12s %9s %6s\n", "METRIC", "START", "END", "CHANGE", "R²")
+	for _, m := range report.Metrics {
+		if m.Start == 0 && m.End == 0 {
+			continue // not measured
+		}
+		status := ""
+		if drifting[m.Type] {
+			status = "  DRIFTING"
+		}
+		fmt.Printf("  %-12s %12s %12s %+8.1f%% %6.2f%s\n",
+			m.Type, formatTrendValue(m.Type, m.Start), formatTrendValue(m.Type, m.End),
+			m.Change*100, m.R2, status)
+	}
+}
+
+// formatTrendValue formats a fitted metric value in its unit.
+func formatTrendValue(t regression.RegressionType, v float64) string {
+	switch t {
+	case regression.RegressionLatencyP50, regression.RegressionLatencyP95, regression.RegressionLatencyP99:
+		return time.Duration(v).Round(time.Microsecond).String()
+	case regression.RegressionThroughput:
+		return fmt.Sprintf("%.1f ops/s", v)
+	case regression.RegressionMemory:
+		return formatBytesHuman(int64(v)) + "/op"
+	case regression.RegressionErrorRate:
+		return fmt.Sprintf("%.2f%%", v*100)
+	default:
+		return fmt.Sprintf("%g", v)
+	}
+}
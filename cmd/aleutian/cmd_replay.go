@@ -0,0 +1,62 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import "github.com/spf13/cobra"
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	replayProject string
+	replayJSON    bool
+)
+
+// =============================================================================
+// COMMAND DEFINITIONS
+// =============================================================================
+
+// Replay runs the trace engine in-process, which needs cgo for tree-sitter.
+// runReplay is implemented in cmd_replay_cgo.go; CGO_ENABLED=0 builds get
+// the stub in cmd_replay_nocgo.go.
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <bundle.json>",
+	Short: "Re-run a captured failed agent session against this build",
+	Long: `Re-run an agent session captured in a replay bundle, with the LLM
+responses stubbed from the recording.
+
+The trace server writes a bundle for every failed agent run when
+AGENT_REPLAY_DIR is set. A bundle holds the request, the LLM exchanges and
+the tool outputs, with secrets redacted. Replay builds the code graph and
+runs the session in-process: the agent receives the recorded LLM responses
+in order, while its tools run for real against the local build. Places
+where the tools now return something different from the recording are
+reported as divergences. The tool router is disabled during replay.
+
+Exit Codes:
+  0 = The replayed run completed
+  1 = The replayed run failed, or the bundle could not be replayed
+
+Examples:
+  aleutian replay ~/.aleutian/replays/3f2c9a.json
+  aleutian replay bundle.json --project ./checkout --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayProject, "project", "",
+		"Project root to replay against (default: the recorded project root)")
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false,
+		"Output as JSON for scripting")
+}
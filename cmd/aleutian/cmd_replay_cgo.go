@@ -0,0 +1,185 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	code_buddy "github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND IMPLEMENTATIONS
+// =============================================================================
+
+// replayReport is the result of a replay.
+type replayReport struct {
+	Bundle      string                `json:"bundle"`
+	SessionID   string                `json:"session_id"`
+	Original    replay.Outcome        `json:"original"`
+	Replayed    replay.Outcome        `json:"replayed"`
+	Served      int                   `json:"llm_responses_served"`
+	Responses   int                   `json:"llm_responses_recorded"`
+	Divergences []replay.Divergence   `json:"divergences"`
+	Response    string                `json:"response,omitempty"`
+	Clarify     *agent.ClarifyRequest `json:"needs_clarify,omitempty"`
+}
+
+// runReplay replays a bundle and reports how the run compares.
+func runReplay(cmd *cobra.Command, args []string) {
+	report, err := replayBundle(args[0])
+	if err != nil {
+		printError("replay failed", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	if replayJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		printReplayReport(report)
+	}
+
+	if report.Replayed.State == string(agent.StateError) {
+		os.Exit(1)
+	}
+}
+
+// replayBundle runs the session recorded in a bundle.
+//
+// # Description
+//
+// Builds the code graph for the project and runs the recorded query with
+// the recorded session configuration, the tool router disabled, and a
+// replay.StubClient as the LLM.
+//
+// # Inputs
+//
+//   - path: The bundle file.
+//
+// # Outputs
+//
+//   - *replayReport: The recorded and replayed outcomes and divergences.
+//   - error: Non-nil if the bundle cannot be read or the graph built.
+func replayBundle(path string) (*replayReport, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	bundle, err := replay.ReadBundle(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := bundle.Request.ProjectRoot
+	if replayProject != "" {
+		root = replayProject
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return nil, fmt.Errorf("resolving project root: %w", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("project root (use --project): %w", err)
+	}
+
+	stub := replay.NewStubClient(bundle)
+	svc := code_buddy.NewService(code_buddy.DefaultServiceConfig())
+	engine := code_buddy.NewLocalEngine(svc, code_buddy.NewAgentLoop(svc, code_buddy.AgentLoopConfig{
+		LLMClient:      stub,
+		ContextEnabled: true,
+		ToolsEnabled:   true,
+	}))
+	defer func() {
+		if err := engine.Close(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: closing trace engine: %v\n", err)
+		}
+	}()
+
+	if !replayJSON {
+		fmt.Printf("Building code graph for %s...\n", root)
+	}
+	if _, err := engine.Init(ctx, &code_buddy.InitRequest{ProjectRoot: root}); err != nil {
+		return nil, fmt.Errorf("building code graph: %w", err)
+	}
+
+	config := agent.DefaultSessionConfig()
+	if bundle.Request.Config != nil {
+		recorded := *bundle.Request.Config
+		config = &recorded
+	}
+	config.ToolRouterEnabled = false
+
+	report := &replayReport{
+		Bundle:    path,
+		SessionID: bundle.SessionID,
+		Original:  bundle.Outcome,
+		Responses: len(bundle.Exchanges),
+	}
+
+	resp, err := engine.RunAgent(ctx, &code_buddy.AgentRunRequest{
+		ProjectRoot: root,
+		Query:       bundle.Request.Query,
+		Config:      config,
+	})
+	if err != nil {
+		report.Replayed = replay.Outcome{State: string(agent.StateError), Error: err.Error()}
+	} else {
+		report.Replayed = replay.Outcome{State: resp.State, Error: resp.Error, StepsTaken: resp.StepsTaken}
+		report.Response = resp.Response
+		report.Clarify = resp.NeedsClarify
+	}
+	report.Served = stub.Served()
+	report.Divergences = stub.Divergences()
+	return report, nil
+}
+
+// printReplayReport prints a replay report for humans.
+func printReplayReport(r *replayReport) {
+	fmt.Printf("\nSession %s\n", r.SessionID)
+	fmt.Printf("  Recorded: %-9s %d steps  %s\n", r.Original.State, r.Original.StepsTaken, r.Original.Error)
+	fmt.Printf("  Replayed: %-9s %d steps  %s\n", r.Replayed.State, r.Replayed.StepsTaken, r.Replayed.Error)
+	fmt.Printf("  LLM responses served: %d of %d\n", r.Served, r.Responses)
+
+	if len(r.Divergences) == 0 {
+		fmt.Println("\nNo divergences: the tools returned what they returned when recorded.")
+	} else {
+		fmt.Printf("\nDivergences (%d):\n", len(r.Divergences))
+		for _, d := range r.Divergences {
+			fmt.Printf("  exchange %d, %s\n", d.Exchange, d.Field)
+			fmt.Printf("    recorded: %s\n", replaySnippet(d.Recorded))
+			fmt.Printf("    replayed: %s\n", replaySnippet(d.Replayed))
+		}
+	}
+
+	if r.Response != "" {
+		fmt.Printf("\nAnswer:\n%s\n", r.Response)
+	}
+}
+
+// replaySnippet shortens a value to one line for display.
+func replaySnippet(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 120 {
+		return s[:117] + "..."
+	}
+	return s
+}
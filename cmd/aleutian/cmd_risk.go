@@ -129,7 +129,7 @@ func runRiskCommand(cmd *cobra.Command, args []string) {
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		outputRiskError("Failed to get working directory", err)
-		os.Exit(risk.ExitError)
+		os.Exit(exitCodeFor(err, risk.ExitError))
 	}
 
 	// Load index (optional - impact analysis needs it)
@@ -156,7 +156,7 @@ func runRiskCommand(cmd *cobra.Command, args []string) {
 	result, err := aggregator.Assess(ctx, cfg)
 	if err != nil {
 		outputRiskError("Risk assessment failed", err)
-		os.Exit(risk.ExitError)
+		os.Exit(exitCodeFor(err, risk.ExitError))
 	}
 
 	// Output result
@@ -0,0 +1,184 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/graph"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/search"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	searchKinds []string
+	searchPath  string
+	searchLimit int
+	searchJSON  bool
+)
+
+// =============================================================================
+// COMMAND DEFINITION
+// =============================================================================
+
+// searchCmd performs hybrid lexical + structural symbol search.
+var searchCmd = &cobra.Command{
+	Use:   "search QUERY",
+	Short: "Search symbols by name, words, or fuzzy match",
+	Long: `Search the code index for symbols matching a query.
+
+Matches are found by exact, prefix, substring, identifier-word, and trigram
+(typo-tolerant) matching on symbol names, then ranked with a boost for
+symbols that are referenced more often in the call graph.
+
+Prerequisites:
+  Run 'aleutian init' first to build the code index.
+
+Examples:
+  aleutian search ValidateToken
+  aleutian search "parse request" --kind function
+  aleutian search handlr --path services/api/
+  aleutian search config --limit 5 --json`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchKinds, "kind", nil,
+		"Filter by symbol kind (function, method, struct, interface, ...); repeatable")
+	searchCmd.Flags().StringVar(&searchPath, "path", "",
+		"Only return symbols in files under this path prefix")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", search.DefaultLimit,
+		"Maximum results")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false,
+		"Output as JSON for scripting")
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATION
+// =============================================================================
+
+// searchOutput is the JSON output of the search command.
+type searchOutput struct {
+	APIVersion string          `json:"api_version"`
+	Success    bool            `json:"success"`
+	Query      string          `json:"query"`
+	Results    []search.Result `json:"results"`
+}
+
+// runSearch executes the search command.
+func runSearch(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := strings.Join(args, " ")
+
+	index, err := loadGraphIndex()
+	if err != nil {
+		outputSearchError("Failed to load index", err)
+		os.Exit(exitCodeFor(err, graph.ExitError))
+	}
+
+	idx := search.NewIndex(buildSearchDocuments(index), search.DefaultOptions())
+	results, err := idx.Search(ctx, search.Query{
+		Text:       query,
+		Kinds:      searchKinds,
+		PathPrefix: searchPath,
+		Limit:      searchLimit,
+	})
+	if err != nil {
+		outputSearchError("Search failed", err)
+		os.Exit(exitCodeFor(err, graph.ExitError))
+	}
+
+	if searchJSON {
+		outputGraphJSON(searchOutput{
+			APIVersion: graph.APIVersion,
+			Success:    true,
+			Query:      query,
+			Results:    results,
+		})
+	} else {
+		outputSearchText(query, results)
+	}
+
+	os.Exit(graph.ExitSuccess)
+}
+
+// buildSearchDocuments converts indexed symbols to search documents.
+//
+// Centrality is the number of incoming edges, so symbols referenced from
+// many places rank above rarely used symbols with similar names.
+func buildSearchDocuments(index *initializer.MemoryIndex) []search.Document {
+	inDegree := make(map[string]int, len(index.Symbols))
+	for _, e := range index.Edges {
+		inDegree[e.ToID]++
+	}
+
+	docs := make([]search.Document, 0, len(index.Symbols))
+	for _, s := range index.Symbols {
+		if s.Kind == "import" || s.Kind == "parameter" {
+			continue
+		}
+		docs = append(docs, search.Document{
+			ID:         s.ID,
+			Name:       s.Name,
+			Kind:       strings.ToLower(s.Kind),
+			FilePath:   s.FilePath,
+			Line:       s.StartLine,
+			Signature:  s.Signature,
+			Centrality: float64(inDegree[s.ID]),
+		})
+	}
+	return docs
+}
+
+// outputSearchError outputs an error message in the selected format.
+func outputSearchError(msg string, err error) {
+	if searchJSON {
+		result := map[string]interface{}{
+			"api_version": graph.APIVersion,
+			"success":     false,
+			"error":       err.Error(),
+		}
+		for k, v := range errorFields(err) {
+			result[k] = v
+		}
+		outputGraphJSON(result)
+		return
+	}
+	printError(msg, err)
+}
+
+// outputSearchText outputs search results as text.
+func outputSearchText(query string, results []search.Result) {
+	fmt.Printf("Search results for %q:\n\n", query)
+
+	if len(results) == 0 {
+		fmt.Println("  No matching symbols found.")
+		return
+	}
+
+	for _, r := range results {
+		fmt.Printf("  %-40s %-10s %s:%d  (%.2f %s)\n",
+			r.Name, r.Kind, r.FilePath, r.Line, r.Score, r.Match)
+	}
+	fmt.Printf("\n%d result(s)\n", len(results))
+}
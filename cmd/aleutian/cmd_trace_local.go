@@ -0,0 +1,31 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+// The embedded engine parses with tree-sitter, which needs cgo. runTraceLocal
+// is implemented in cmd_trace_local_cgo.go; builds with CGO_ENABLED=0 get
+// the stub in cmd_trace_local_nocgo.go, which reports errLocalEngineRequiresCgo.
+
+var (
+	traceLocal   bool
+	traceProject string
+)
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceLocal, "local", false,
+		"Run the trace engine in-process (no server, no containers); only Ollama is needed")
+	traceCmd.Flags().StringVar(&traceProject, "project", ".",
+		"Project root to analyze with --local")
+}
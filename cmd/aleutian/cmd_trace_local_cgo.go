@@ -0,0 +1,136 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+	"github.com/AleutianAI/AleutianFOSS/services/llm"
+	code_buddy "github.com/AleutianAI/AleutianFOSS/services/trace"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// =============================================================================
+// COMMAND IMPLEMENTATION
+// =============================================================================
+
+// runTraceLocal runs the trace engine embedded in the CLI.
+//
+// Description:
+//
+//	Builds the code graph for --project and, if a query is given, runs an
+//	agent session over it. The engine shares the trace server's code paths
+//	(code_buddy.LocalEngine), so results match the containerized stack.
+//	The agent uses Ollama at OLLAMA_BASE_URL (default
+//	http://localhost:11434) with OLLAMA_MODEL.
+//
+// Inputs:
+//
+//	query - The agent query. Empty builds the graph only.
+//
+// Outputs:
+//
+//	error - Non-nil if the graph could not be built or the agent failed.
+func runTraceLocal(query string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	root, err := filepath.Abs(traceProject)
+	if err != nil {
+		return fmt.Errorf("resolving project root: %w", err)
+	}
+
+	svc := code_buddy.NewService(code_buddy.DefaultServiceConfig())
+	var engine code_buddy.Engine = code_buddy.NewLocalEngine(svc, code_buddy.NewAgentLoop(svc, localAgentLoopConfig()))
+	defer func() {
+		if err := engine.Close(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: closing trace engine: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("Building code graph for %s...\n", root)
+	initResp, err := engine.Init(ctx, &code_buddy.InitRequest{ProjectRoot: root})
+	if err != nil {
+		return fmt.Errorf("building code graph: %w", err)
+	}
+	fmt.Printf("Graph built: %d files, %d symbols, %d edges (%dms)\n",
+		initResp.FilesParsed, initResp.SymbolsExtracted, initResp.EdgesBuilt, initResp.ParseTimeMs)
+	for _, e := range initResp.Errors {
+		fmt.Fprintf(os.Stderr, "  warning: %s\n", e)
+	}
+
+	if query == "" {
+		return nil
+	}
+
+	resp, err := engine.RunAgent(ctx, &code_buddy.AgentRunRequest{ProjectRoot: root, Query: query})
+	if err != nil {
+		return fmt.Errorf("agent run: %w", err)
+	}
+
+	if resp.DegradedMode {
+		fmt.Fprintln(os.Stderr, "Note: the session ran in degraded mode")
+	}
+	switch {
+	case resp.NeedsClarify != nil:
+		fmt.Printf("\nThe agent needs clarification: %s\n", resp.NeedsClarify.Question)
+		for _, opt := range resp.NeedsClarify.Options {
+			fmt.Printf("  - %s\n", opt)
+		}
+	case resp.Error != "":
+		return fmt.Errorf("agent session %s ended in %s: %s", resp.SessionID, resp.State, resp.Error)
+	default:
+		fmt.Printf("\nAnswer:\n%s\n", resp.Response)
+	}
+	return nil
+}
+
+// localAgentLoopConfig connects the embedded agent to Ollama, falling back
+// to a loop without an LLM if the client cannot be created.
+//
+// Edit history is kept in the blob store the BackupManager uses, so edits
+// made in-process can be undone with 'aleutian undo' after the command
+// exits.
+func localAgentLoopConfig() code_buddy.AgentLoopConfig {
+	editStore, err := cas.Open(DefaultReliabilityConfig().BlobDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: agent edits cannot be undone after exit: %v\n", err)
+	}
+
+	if os.Getenv("OLLAMA_BASE_URL") == "" {
+		_ = os.Setenv("OLLAMA_BASE_URL", DefaultOllamaBaseURL)
+	}
+	client, err := llm.NewOllamaClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Ollama unavailable, agent runs without an LLM: %v\n", err)
+		return code_buddy.AgentLoopConfig{EditStore: editStore}
+	}
+
+	model := strings.TrimSpace(os.Getenv("OLLAMA_MODEL"))
+	if model == "" {
+		model = DefaultLLMModel
+	}
+	return code_buddy.AgentLoopConfig{
+		LLMClient:      agentllm.NewOllamaAdapter(client, model),
+		ContextEnabled: true,
+		ToolsEnabled:   true,
+		ContextWindow:  agentllm.DefaultContextWindow,
+		EditStore:      editStore,
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	undoCount int
+	undoRedo  bool
+	undoJSON  bool
+)
+
+// =============================================================================
+// COMMAND DEFINITION
+// =============================================================================
+
+var undoCmd = &cobra.Command{
+	Use:   "undo SESSION_ID",
+	Short: "Revert file edits made by an agent session",
+	Long: `Revert the last file edits made by an agent session, newest first.
+
+An edit is only reverted if the file still holds what the agent wrote.
+If a file was changed since, undo stops there and leaves it untouched.
+Files the agent created are removed.
+
+The trace service address is taken from ALEUTIAN_TRACE_URL
+(default ` + DefaultTraceURL + `).
+
+Examples:
+  aleutian undo 3f2a9c1e
+  aleutian undo 3f2a9c1e -n 3
+  aleutian undo 3f2a9c1e --redo`,
+	Args: cobra.ExactArgs(1),
+	Run:  runUndo,
+}
+
+func init() {
+	undoCmd.Flags().IntVarP(&undoCount, "count", "n", 1,
+		"Number of edits to revert")
+	undoCmd.Flags().BoolVar(&undoRedo, "redo", false,
+		"Re-apply undone edits instead")
+	undoCmd.Flags().BoolVar(&undoJSON, "json", false,
+		"Output as JSON for scripting")
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATION
+// =============================================================================
+
+// undoResponse mirrors the trace service's AgentUndoResponse.
+type undoResponse struct {
+	SessionID     string           `json:"session_id"`
+	Operations    []undo.Operation `json:"operations"`
+	UndoAvailable int              `json:"undo_available"`
+	RedoAvailable int              `json:"redo_available"`
+	Error         string           `json:"error,omitempty"`
+}
+
+// runUndo reverts or re-applies edits and reports which.
+func runUndo(cmd *cobra.Command, args []string) {
+	action := "undo"
+	if undoRedo {
+		action = "redo"
+	}
+	result, err := requestUndo(args[0], action, undoCount)
+	if err != nil {
+		printError(action+" failed", err)
+		os.Exit(exitCodeFor(err, 1))
+	}
+
+	if undoJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+	} else {
+		printUndoResult(action, result)
+	}
+
+	// Some edits were processed before a conflict stopped the rest.
+	if result.Error != "" {
+		printError(action+" stopped", errors.New(result.Error))
+		os.Exit(1)
+	}
+}
+
+// requestUndo asks the trace service to undo or redo count edits of a
+// session.
+//
+// # Outputs
+//
+//   - *undoResponse: The edits processed. Error is set if the service
+//     stopped early after processing some.
+//   - error: Non-nil if nothing was processed.
+func requestUndo(sessionID, action string, count int) (*undoResponse, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("--count must be positive")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/v1/trace/agent/%s/%s", getTraceBaseURL(), sessionID, action)
+	body, _ := json.Marshal(map[string]int{"count": count})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("contacting trace service: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	var result undoResponse
+	if json.Unmarshal(data, &result) == nil && result.SessionID != "" {
+		return &result, nil
+	}
+	var apiErr struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+		return nil, fmt.Errorf("%s (%s)", apiErr.Error, apiErr.Code)
+	}
+	return nil, fmt.Errorf("trace service returned HTTP %d", resp.StatusCode)
+}
+
+// printUndoResult prints the processed edits and what is left.
+func printUndoResult(action string, result *undoResponse) {
+	verb := "Reverted"
+	if action == "redo" {
+		verb = "Re-applied"
+	}
+	for _, op := range result.Operations {
+		note := ""
+		switch {
+		case op.Created && action == "undo":
+			note = " (removed)"
+		case op.Created:
+			note = " (recreated)"
+		}
+		fmt.Printf("%s #%d %s%s\n", verb, op.Seq, op.Path, note)
+	}
+	fmt.Printf("%d edit(s) can be undone, %d redone\n", result.UndoAvailable, result.RedoAvailable)
+}
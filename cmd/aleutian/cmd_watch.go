@@ -0,0 +1,187 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// COMMAND FLAGS
+// =============================================================================
+
+var (
+	watchPipelines []string
+	watchDebounce  time.Duration
+	watchAddr      string
+	watchSocket    string
+	watchJSON      bool
+	watchQuiet     bool
+)
+
+// =============================================================================
+// COMMAND DEFINITION
+// =============================================================================
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [path]",
+	Short: "Continuously analyze the project as files are saved",
+	Long: `Watch the project for changes, keep the code index warm, and re-run
+analysis pipelines on every save.
+
+Results are published as Server-Sent Events so editors and other tools can
+subscribe:
+
+  GET /events   event stream (ready, change, indexed, result, error)
+  GET /status   JSON snapshot of the latest results
+  POST /assess  risk assessment on the warm index (used by git hooks)
+
+Pipelines:
+  lint     Run the language linter on changed files
+  impact   Compute the blast radius of changed files
+  risk     Aggregate impact, policy, and complexity risk
+
+Examples:
+  aleutian watch
+  aleutian watch --pipelines lint,impact
+  aleutian watch --addr 127.0.0.1:9000
+  aleutian watch --socket /tmp/aleutian.sock
+  curl -N http://127.0.0.1:7878/events`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runWatch,
+}
+
+func init() {
+	watchCmd.Flags().StringSliceVar(&watchPipelines, "pipelines", watch.AvailablePipelines(),
+		"Pipelines to run on change: "+strings.Join(watch.AvailablePipelines(), ", "))
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", watch.DefaultDebounce,
+		"Quiet period after the last change before re-running")
+	watchCmd.Flags().StringVar(&watchAddr, "addr", watch.DefaultAddr,
+		"TCP address for the event server")
+	watchCmd.Flags().StringVar(&watchSocket, "socket", "",
+		"Serve events on this unix socket instead of TCP")
+	watchCmd.Flags().BoolVar(&watchJSON, "json", false,
+		"Print events to stdout as JSON lines")
+	watchCmd.Flags().BoolVar(&watchQuiet, "quiet", false,
+		"Do not print events to stdout")
+}
+
+// =============================================================================
+// COMMAND IMPLEMENTATION
+// =============================================================================
+
+// runWatch starts the watch daemon and event server and blocks until
+// interrupted.
+func runWatch(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	projectRoot := "."
+	if len(args) > 0 {
+		projectRoot = args[0]
+	}
+	absPath, err := filepath.Abs(projectRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid path: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := watch.DefaultConfig(absPath)
+	cfg.Pipelines = watchPipelines
+	cfg.Debounce = watchDebounce
+	cfg.Addr = watchAddr
+	cfg.SocketPath = watchSocket
+
+	pipelines, err := watch.NewPipelines(cfg.Pipelines, cfg.ProjectRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	broker := watch.NewBroker(watch.DefaultSubscriberBuffer)
+	daemon, err := watch.NewDaemon(cfg, broker, pipelines, watch.DefaultIndexer(cfg.ProjectRoot))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ln, err := watch.Listen(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: starting event server: %v\n", err)
+		os.Exit(1)
+	}
+	server := &http.Server{Handler: watch.NewHandler(broker, daemon)}
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: event server: %v\n", err)
+			stop()
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if !watchQuiet {
+		fmt.Fprintf(os.Stderr, "Watching %s (events on %s)\n", cfg.ProjectRoot, ln.Addr())
+		events, unsubscribe := broker.Subscribe()
+		defer unsubscribe()
+		go printWatchEvents(events)
+	}
+
+	if err := daemon.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printWatchEvents prints events to stdout until the channel closes.
+func printWatchEvents(events <-chan watch.Event) {
+	encoder := json.NewEncoder(os.Stdout)
+	for e := range events {
+		if watchJSON {
+			encoder.Encode(e)
+			continue
+		}
+
+		ts := e.Time.Format("15:04:05")
+		switch e.Type {
+		case watch.EventReady:
+			fmt.Printf("[%s] ready\n", ts)
+		case watch.EventChange:
+			fmt.Printf("[%s] changed: %s\n", ts, strings.Join(e.Files, ", "))
+		case watch.EventIndexed:
+			fmt.Printf("[%s] reindexed (%dms)\n", ts, e.DurationMs)
+		case watch.EventResult:
+			fmt.Printf("[%s] %s done (%dms)\n", ts, e.Pipeline, e.DurationMs)
+		case watch.EventError:
+			if e.Pipeline != "" {
+				fmt.Printf("[%s] %s failed: %s\n", ts, e.Pipeline, e.Error)
+			} else {
+				fmt.Printf("[%s] error: %s\n", ts, e.Error)
+			}
+		}
+	}
+}
@@ -468,6 +468,16 @@ func init() {
 
 	// Code Analysis (Phase CLI-01)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(impactCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(hooksCmd)
+	rootCmd.AddCommand(commitMsgCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(blobsCmd)
+	rootCmd.AddCommand(regressionCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(undoCmd)
 }
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/validation"
 	"gopkg.in/yaml.v3"
 )
 
@@ -52,9 +53,15 @@ func loadInternal() error {
 	if err != nil {
 		return fmt.Errorf("failed to read the config file %w", err)
 	}
-	// parse the config in to the Global struct
-	if err = yaml.Unmarshal(data, &Global); err != nil {
-		return fmt.Errorf("failed to marshal the config to the Global singleton: %w", err)
+	// parse the config in to the Global struct, rejecting unknown fields,
+	// mistyped values and out-of-range settings with their line numbers
+	return parseConfig(configPath, data, &Global)
+}
+
+// parseConfig strictly decodes data into cfg, naming source in any error.
+func parseConfig(source string, data []byte, cfg *AleutianConfig) error {
+	if err := validation.DecodeYAML(source, data, cfg); err != nil {
+		return fmt.Errorf("failed to load the config: %w", err)
 	}
 	return nil
 }
@@ -13,6 +13,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -110,3 +111,52 @@ func TestBuildDefaultDrives(t *testing.T) {
 		t.Errorf("First drive should be home directory, got %q", drives[0])
 	}
 }
+
+// TestParseConfig_DefaultRoundTrip verifies the generated default config
+// passes strict validation.
+func TestParseConfig_DefaultRoundTrip(t *testing.T) {
+	data, err := yaml.Marshal(DefaultConfig())
+	if err != nil {
+		t.Fatalf("marshal default config: %v", err)
+	}
+	var cfg AleutianConfig
+	if err := parseConfig("aleutian.yaml", data, &cfg); err != nil {
+		t.Fatalf("parseConfig() rejected the default config: %v", err)
+	}
+	if cfg.ModelBackend.Type != "ollama" {
+		t.Errorf("ModelBackend.Type = %q, want %q", cfg.ModelBackend.Type, "ollama")
+	}
+}
+
+// TestParseConfig_Rejects verifies malformed configs fail with line numbers.
+func TestParseConfig_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"unknown field", "machine:\n  cpu_cout: 4\n", `aleutian.yaml:2:3: machine: unknown field "cpu_cout" (did you mean "cpu_count"?)`},
+		{"wrong type", "features:\n  rag_engine: sometimes\n", `aleutian.yaml:2:15: features.rag_engine: expected true or false`},
+		{"bad duration", "secrets:\n  timeout: 5 minutes\n", `aleutian.yaml:2:12: secrets.timeout: expected a duration`},
+		{"negative", "machine:\n  memory_amount: -1\n", `aleutian.yaml:2:18: machine.memory_amount: must not be negative`},
+		{"backend type", "model_backend:\n  type: llamacpp\n", `aleutian.yaml:2:9: model_backend.type: must be one of`},
+		{"forecast mode", "forecast:\n  mode: hybrid\n", `aleutian.yaml:2:9: forecast.mode: must be one of`},
+		{"port range", "observability:\n  grafana_port: 70000\n", `aleutian.yaml:2:17: observability.grafana_port: must be a port`},
+		{"profile name", "profiles:\n  - max_tokens: 10\n", `aleutian.yaml:2:5: profiles[0].name: is required`},
+		{"hmac algorithm", "session_integrity:\n  enterprise:\n    hmac:\n      enabled: true\n      algorithm: sha1\n",
+			`aleutian.yaml:5:18: session_integrity.enterprise.hmac.algorithm: must be one of`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg AleutianConfig
+			err := parseConfig("aleutian.yaml", []byte(tt.yaml), &cfg)
+			if err == nil {
+				t.Fatal("parseConfig() succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/validation"
+)
+
+// ValidBackendTypes lists the accepted model_backend.type values.
+var ValidBackendTypes = map[string]bool{
+	"ollama":     true,
+	"openai":     true,
+	"anthropic":  true,
+	"remote_tgi": true,
+}
+
+// ValidVerificationModes lists the accepted session_integrity.verification_mode values.
+var ValidVerificationModes = map[string]bool{
+	"quick": true,
+	"full":  true,
+}
+
+// ValidateFields checks value ranges and enumerations in the configuration.
+//
+// # Description
+//
+// Called by validation.DecodeYAML after the document's shape (known keys,
+// scalar types) has been checked, so every error points at a line in the
+// file. Empty values are accepted where the loader falls back to a default.
+//
+// # Outputs
+//
+//   - []validation.FieldError: One entry per invalid value, nil if valid
+func (c *AleutianConfig) ValidateFields() []validation.FieldError {
+	v := &fieldChecker{}
+
+	v.nonNegative("machine.cpu_count", int64(c.Machine.CPUCount))
+	v.nonNegative("machine.memory_amount", int64(c.Machine.MemoryAmount))
+	v.nonNegative("secrets.timeout", int64(c.Secrets.Timeout))
+
+	if c.ModelBackend.Type != "" && !ValidBackendTypes[c.ModelBackend.Type] {
+		v.add("model_backend.type", "must be one of: ollama, openai, anthropic, remote_tgi, got %q", c.ModelBackend.Type)
+	}
+	v.nonNegative("model_backend.ollama.disk_limit_gb", c.ModelBackend.Ollama.DiskLimitGB)
+
+	if c.Forecast.Mode != "" && !c.Forecast.Mode.IsValid() {
+		v.add("forecast.mode", "must be one of: standalone, sapheneia, got %q", c.Forecast.Mode)
+	}
+
+	mm := &c.ModelManagement
+	v.nonNegative("model_management.disk_limit_gb", mm.DiskLimitGB)
+	v.nonNegative("model_management.parallel.max_concurrent", int64(mm.Parallel.MaxConcurrent))
+	v.nonNegative("model_management.parallel.bandwidth_limit_mbps", int64(mm.Parallel.BandwidthLimitMbps))
+	v.nonNegative("model_management.auto_selection.min_context_window", int64(mm.AutoSelection.MinContextWindow))
+	v.nonNegative("model_management.size_estimation.warn_threshold_gb", int64(mm.SizeEstimation.WarnThresholdGB))
+	v.nonNegative("model_management.size_estimation.require_confirmation_gb", int64(mm.SizeEstimation.RequireConfirmationGB))
+	for _, name := range slices.Sorted(maps.Keys(mm.FallbackChains)) {
+		if mm.FallbackChains[name].Primary == "" {
+			v.add("model_management.fallback_chains."+name+".primary", "is required")
+		}
+	}
+
+	si := &c.SessionIntegrity
+	if si.VerificationMode != "" && !ValidVerificationModes[si.VerificationMode] {
+		v.add("session_integrity.verification_mode", "must be one of: quick, full, got %q", si.VerificationMode)
+	}
+	ent := &si.Enterprise
+	for _, err := range []error{ent.HMAC.Validate(), ent.Signatures.Validate(), ent.TSA.Validate(), ent.HSM.Validate()} {
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			v.add("session_integrity.enterprise."+ve.Field, "%s", ve.Message)
+		}
+	}
+	v.nonNegative("session_integrity.enterprise.tsa.timeout", int64(ent.TSA.Timeout))
+	v.nonNegative("session_integrity.enterprise.audit.retention_days", int64(ent.Audit.RetentionDays))
+	v.nonNegative("session_integrity.enterprise.rate_limiting.requests_per_minute", int64(ent.RateLimiting.RequestsPerMinute))
+	v.nonNegative("session_integrity.enterprise.rate_limiting.requests_per_hour", int64(ent.RateLimiting.RequestsPerHour))
+	v.nonNegative("session_integrity.enterprise.rate_limiting.burst_size", int64(ent.RateLimiting.BurstSize))
+	v.nonNegative("session_integrity.enterprise.caching.ttl", int64(ent.Caching.TTL))
+	v.nonNegative("session_integrity.enterprise.scheduling.default_interval", int64(ent.Scheduling.DefaultInterval))
+	v.nonNegative("session_integrity.enterprise.scheduling.priority_session_interval", int64(ent.Scheduling.PrioritySessionInterval))
+	v.nonNegative("session_integrity.enterprise.scheduling.max_concurrent", int64(ent.Scheduling.MaxConcurrent))
+	v.nonNegative("session_integrity.enterprise.alerting.throttle_minutes", int64(ent.Alerting.ThrottleMinutes))
+
+	v.port("observability.prometheus_port", c.Observability.PrometheusPort)
+	v.port("observability.grafana_port", c.Observability.GrafanaPort)
+
+	seen := make(map[string]bool, len(c.Profiles))
+	for i, p := range c.Profiles {
+		prefix := fmt.Sprintf("profiles[%d]", i)
+		switch {
+		case p.Name == "":
+			v.add(prefix+".name", "is required")
+		case seen[p.Name]:
+			v.add(prefix+".name", "duplicate profile %q", p.Name)
+		}
+		seen[p.Name] = true
+		v.nonNegative(prefix+".max_tokens", int64(p.MaxTokens))
+		v.nonNegative(prefix+".min_ram_mb", p.MinRAM_MB)
+	}
+
+	return v.errs
+}
+
+// fieldChecker accumulates field errors for ValidateFields.
+type fieldChecker struct {
+	errs []validation.FieldError
+}
+
+func (v *fieldChecker) add(path, format string, args ...any) {
+	v.errs = append(v.errs, validation.FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *fieldChecker) nonNegative(path string, n int64) {
+	if n < 0 {
+		v.add(path, "must not be negative, got %d", n)
+	}
+}
+
+func (v *fieldChecker) port(path string, port int) {
+	if port < 0 || port > 65535 {
+		v.add(path, "must be a port between 1 and 65535 (0 for the default), got %d", port)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+//go:build js && wasm
+
+// Command graphwasm exposes the graph query core to JavaScript, so a
+// browser-based viewer can run callers/callees/path queries on an exported
+// index without a backend.
+//
+// # Build
+//
+//	GOOS=js GOARCH=wasm go build -o graph.wasm ./cmd/aleutian/graphwasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// # JavaScript API
+//
+// After the module starts, globalThis.aleutianGraph provides:
+//
+//	load(indexJSON)                 // contents of .aleutian/index.json
+//	callers(symbol, configJSON?)    // QueryResult
+//	callees(symbol, configJSON?)    // QueryResult
+//	path(from, to, configJSON?)     // PathQueryResult
+//
+// Every function returns a JSON string. Failures return {"error": "..."}.
+// configJSON is an optional object with the fields of graph.QueryConfig
+// plus "all" and "max_paths" for path queries, e.g.
+// {"max_depth": 3, "include_tests": true}.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"syscall/js"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/graph"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+)
+
+// queryConfig is the JSON form of graph.QueryConfig.
+type queryConfig struct {
+	MaxDepth      int  `json:"max_depth"`
+	MaxResults    int  `json:"max_results"`
+	IncludeTests  bool `json:"include_tests"`
+	IncludeStdlib bool `json:"include_stdlib"`
+	Exact         bool `json:"exact"`
+	All           bool `json:"all"`
+	MaxPaths      int  `json:"max_paths"`
+}
+
+// loadResult is returned by load.
+type loadResult struct {
+	Symbols int `json:"symbols"`
+	Edges   int `json:"edges"`
+}
+
+var (
+	mu      sync.RWMutex
+	querier *graph.Querier
+
+	errNotLoaded = errors.New("no index loaded: call load(indexJSON) first")
+)
+
+func main() {
+	js.Global().Set("aleutianGraph", js.ValueOf(map[string]any{
+		"load":    js.FuncOf(load),
+		"callers": js.FuncOf(callers),
+		"callees": js.FuncOf(callees),
+		"path":    js.FuncOf(path),
+	}))
+
+	// Keep the module alive to serve calls.
+	select {}
+}
+
+// load parses an exported index and makes it the queried graph.
+func load(_ js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return errorJSON(errors.New("load(indexJSON) requires the index contents"))
+	}
+	index, err := initializer.ParseIndex([]byte(args[0].String()))
+	if err != nil {
+		return errorJSON(err)
+	}
+
+	mu.Lock()
+	querier = graph.NewQuerier(index)
+	mu.Unlock()
+	return resultJSON(loadResult{Symbols: index.SymbolCount(), Edges: index.EdgeCount()})
+}
+
+// callers runs a callers query.
+func callers(_ js.Value, args []js.Value) any {
+	return runQuery(args, 1, func(q *graph.Querier, cfg queryConfig) (any, error) {
+		return q.FindCallers(context.Background(), args[0].String(), cfg.toGraph())
+	})
+}
+
+// callees runs a callees query.
+func callees(_ js.Value, args []js.Value) any {
+	return runQuery(args, 1, func(q *graph.Querier, cfg queryConfig) (any, error) {
+		return q.FindCallees(context.Background(), args[0].String(), cfg.toGraph())
+	})
+}
+
+// path runs a path query.
+func path(_ js.Value, args []js.Value) any {
+	return runQuery(args, 2, func(q *graph.Querier, cfg queryConfig) (any, error) {
+		return q.FindPath(context.Background(), args[0].String(), args[1].String(), cfg.toGraph(), cfg.All, cfg.MaxPaths)
+	})
+}
+
+// runQuery checks the arguments, parses the optional config following the
+// nargs positional arguments, and runs query on the loaded graph.
+func runQuery(args []js.Value, nargs int, query func(*graph.Querier, queryConfig) (any, error)) any {
+	if len(args) < nargs {
+		return errorJSON(errors.New("missing symbol argument"))
+	}
+
+	cfg := defaultQueryConfig()
+	if len(args) > nargs && args[nargs].Type() == js.TypeString {
+		if err := json.Unmarshal([]byte(args[nargs].String()), &cfg); err != nil {
+			return errorJSON(err)
+		}
+	}
+
+	mu.RLock()
+	q := querier
+	mu.RUnlock()
+	if q == nil {
+		return errorJSON(errNotLoaded)
+	}
+
+	result, err := query(q, cfg)
+	if err != nil {
+		return errorJSON(err)
+	}
+	return resultJSON(result)
+}
+
+// defaultQueryConfig mirrors graph.DefaultQueryConfig.
+func defaultQueryConfig() queryConfig {
+	d := graph.DefaultQueryConfig()
+	return queryConfig{
+		MaxDepth:      d.MaxDepth,
+		MaxResults:    d.MaxResults,
+		IncludeTests:  d.IncludeTests,
+		IncludeStdlib: d.IncludeStdlib,
+		Exact:         d.Exact,
+		MaxPaths:      10,
+	}
+}
+
+// toGraph converts the JSON config to a graph.QueryConfig.
+func (c queryConfig) toGraph() graph.QueryConfig {
+	return graph.QueryConfig{
+		MaxDepth:      c.MaxDepth,
+		MaxResults:    c.MaxResults,
+		IncludeTests:  c.IncludeTests,
+		IncludeStdlib: c.IncludeStdlib,
+		Exact:         c.Exact,
+	}
+}
+
+// resultJSON encodes a result for JavaScript.
+func resultJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errorJSON(err)
+	}
+	return string(data)
+}
+
+// errorJSON encodes an error for JavaScript.
+func errorJSON(err error) any {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
@@ -37,6 +37,7 @@ import (
 const (
 	DefaultOrchestratorPort = 12210
 	DefaultOrchestratorHost = "localhost"
+	DefaultTraceURL         = "http://localhost:8080"
 )
 
 // --- Global Variables ---
@@ -210,6 +211,14 @@ func getOrchestratorBaseURL() string {
 	return fmt.Sprintf("http://%s:%d", DefaultOrchestratorHost, DefaultOrchestratorPort)
 }
 
+// getTraceBaseURL returns the address of the trace service.
+func getTraceBaseURL() string {
+	if url := os.Getenv("ALEUTIAN_TRACE_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return DefaultTraceURL
+}
+
 func getStackDir() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -53,6 +53,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -652,12 +653,13 @@ func NewOTelDiagnosticsTracer(ctx context.Context, config OTelTracerConfig) (*OT
 		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
 	}
 
-	// Create resource with service name
+	// Create resource with service name and build
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(config.ServiceName),
 			attribute.String("deployment.environment", getEnvironment()),
 		),
+		resource.WithAttributes(buildinfo.Get().Attributes()...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
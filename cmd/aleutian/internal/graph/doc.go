@@ -32,6 +32,13 @@
 //	│                                                                          │
 //	└─────────────────────────────────────────────────────────────────────────┘
 //
+// # WebAssembly
+//
+// The package and its index (initializer.ParseIndex) depend on no
+// filesystem or process APIs at query time, so they compile to js/wasm.
+// Command graphwasm exposes the queries to a browser-based viewer over an
+// exported .aleutian/index.json.
+//
 // # Thread Safety
 //
 // Query operations are safe for concurrent use with the same MemoryIndex.
@@ -11,31 +11,44 @@
 package graph
 
 import (
-	"errors"
 	"fmt"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
 )
 
 // Exit codes for graph commands.
 const (
 	ExitSuccess = 0 // Query successful (even if no results)
-	ExitError   = 1 // Error (no index, invalid symbol, etc.)
+	ExitError   = 1 // Unclassified error; apperr errors use their category's code
 	ExitBadArgs = 2 // Invalid arguments
 )
 
 // Sentinel errors for graph queries.
 var (
-	// Index errors
-	ErrIndexNotFound = errors.New("index not found: run 'aleutian init' first")
-	ErrIndexStale    = errors.New("index is stale: consider running 'aleutian init'")
-
-	// Symbol errors
-	ErrSymbolNotFound  = errors.New("symbol not found")
-	ErrSymbolAmbiguous = errors.New("symbol is ambiguous")
-
-	// Query errors
-	ErrNoResults     = errors.New("no results found")
-	ErrDepthExceeded = errors.New("maximum depth exceeded")
-	ErrTimeout       = errors.New("query timed out")
+	// ErrIndexNotFound indicates no index exists for the project.
+	ErrIndexNotFound = initializer.ErrIndexNotFound
+
+	// ErrIndexStale indicates the index is older than the source.
+	ErrIndexStale = apperr.New("INDEX_STALE", apperr.CategoryConflict,
+		"index is stale").WithRetryable(false).WithRemediation("Consider running 'aleutian init --force'")
+
+	// ErrSymbolNotFound indicates no symbol matched the input.
+	ErrSymbolNotFound = apperr.New("SYMBOL_NOT_FOUND", apperr.CategoryNotFound, "symbol not found")
+
+	// ErrSymbolAmbiguous indicates several symbols matched the input.
+	ErrSymbolAmbiguous = apperr.New("SYMBOL_AMBIGUOUS", apperr.CategoryInvalidInput,
+		"symbol is ambiguous").WithRemediation("Use --exact or provide the full path")
+
+	// ErrNoResults indicates the query found nothing.
+	ErrNoResults = apperr.New("NO_RESULTS", apperr.CategoryNotFound, "no results found")
+
+	// ErrDepthExceeded indicates the traversal hit its depth limit.
+	ErrDepthExceeded = apperr.New("DEPTH_EXCEEDED", apperr.CategoryInvalidInput,
+		"maximum depth exceeded").WithRemediation("Lower --depth")
+
+	// ErrTimeout indicates the query ran out of time.
+	ErrTimeout = apperr.New("QUERY_TIMEOUT", apperr.CategoryTimeout, "query timed out")
 )
 
 // SymbolNotFoundError provides details about a missing symbol.
@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package hooks installs and runs Aleutian git hooks.
+//
+// # Overview
+//
+// `aleutian hooks install` writes pre-commit and pre-push scripts into the
+// repository's hooks directory (respecting core.hooksPath). Each script
+// calls `aleutian hooks run <hook>`, which assesses the change set with the
+// risk aggregator (impact + policy/secret scan + complexity) and blocks the
+// commit or push when the configured level is exceeded.
+//
+// # Fast Path
+//
+// When `aleutian watch` is running, the hook asks the daemon to assess the
+// change against its warm in-memory index (POST /assess) with a short
+// timeout. Otherwise it falls back to loading the on-disk index.
+//
+// # Existing Hooks
+//
+// A pre-existing hook that Aleutian did not write is renamed to
+// "<hook>.aleutian-backup" and is still run first by the installed script,
+// so installation never silently disables a team's hooks. Uninstall
+// restores the backup.
+//
+// # Failure Policy
+//
+// Hooks fail open: if the analysis itself errors (no git, no index, timeout),
+// a warning is printed and the commit proceeds. Only a completed assessment
+// that exceeds the blocking level blocks.
+package hooks
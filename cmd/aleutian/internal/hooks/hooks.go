@@ -0,0 +1,294 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+)
+
+// HookType is a git hook name.
+type HookType string
+
+const (
+	// HookPreCommit runs on `git commit` and assesses staged changes.
+	HookPreCommit HookType = "pre-commit"
+
+	// HookPrePush runs on `git push` and assesses changes since upstream.
+	HookPrePush HookType = "pre-push"
+)
+
+// ManagedMarker identifies hook scripts written by Aleutian.
+const ManagedMarker = "# aleutian-managed-hook"
+
+// BackupSuffix is appended to pre-existing hooks moved aside on install.
+const BackupSuffix = ".aleutian-backup"
+
+// Errors returned by the hooks package.
+var (
+	// ErrNotGitRepo indicates the project is not inside a git repository.
+	ErrNotGitRepo = errors.New("not a git repository")
+
+	// ErrUnknownHook indicates an unsupported hook name.
+	ErrUnknownHook = errors.New("unknown hook")
+
+	// ErrHookExists indicates an unmanaged hook with a backup already present.
+	ErrHookExists = errors.New("hook already exists")
+)
+
+// SupportedHooks returns the hooks Aleutian can install.
+func SupportedHooks() []HookType {
+	return []HookType{HookPreCommit, HookPrePush}
+}
+
+// ParseHookType validates a hook name.
+func ParseHookType(s string) (HookType, error) {
+	for _, h := range SupportedHooks() {
+		if string(h) == s {
+			return h, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownHook, s)
+}
+
+// Config configures hook installation.
+//
+// # Fields
+//
+//   - ProjectRoot: Directory inside the git repository.
+//   - Hooks: Hooks to install.
+//   - Binary: Command used to invoke aleutian from the hook script.
+//   - Thresholds: Blocking level per hook. A commit is blocked when risk
+//     exceeds the level; missing hooks use DefaultThreshold.
+//   - BlockSecrets: Block whenever the secret scan reports a critical finding,
+//     regardless of the overall level.
+//   - DaemonAddr: Address of a running `aleutian watch` for the fast path.
+type Config struct {
+	ProjectRoot  string
+	Hooks        []HookType
+	Binary       string
+	Thresholds   map[HookType]risk.RiskLevel
+	BlockSecrets bool
+	DaemonAddr   string
+}
+
+// DefaultThreshold returns the default blocking level for a hook. Pushes
+// are gated more strictly than local commits.
+func DefaultThreshold(h HookType) risk.RiskLevel {
+	if h == HookPrePush {
+		return risk.RiskMedium
+	}
+	return risk.RiskHigh
+}
+
+// DefaultConfig returns a Config installing both hooks with default levels.
+func DefaultConfig(projectRoot string) Config {
+	return Config{
+		ProjectRoot:  projectRoot,
+		Hooks:        SupportedHooks(),
+		Binary:       "aleutian",
+		Thresholds:   map[HookType]risk.RiskLevel{},
+		BlockSecrets: true,
+	}
+}
+
+// threshold returns the configured blocking level for h.
+func (c Config) threshold(h HookType) risk.RiskLevel {
+	if t, ok := c.Thresholds[h]; ok && t != "" {
+		return t
+	}
+	return DefaultThreshold(h)
+}
+
+// HookStatus describes one hook in the repository.
+type HookStatus struct {
+	Hook      HookType `json:"hook"`
+	Path      string   `json:"path"`
+	Installed bool     `json:"installed"`
+	Managed   bool     `json:"managed"`
+	HasBackup bool     `json:"has_backup"`
+}
+
+// HooksDir returns the repository's hooks directory.
+//
+// # Description
+//
+// Uses `git rev-parse --git-path hooks`, which honors core.hooksPath and
+// worktrees.
+func HooksDir(ctx context.Context, projectRoot string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotGitRepo
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectRoot, dir)
+	}
+	return dir, nil
+}
+
+// Script returns the hook script for h.
+//
+// The script runs a backed-up pre-existing hook first (propagating its
+// failure), then delegates to `aleutian hooks run`. If aleutian is not on
+// PATH the hook is skipped rather than blocking the user.
+func Script(h HookType, cfg Config) string {
+	binary := cfg.Binary
+	if binary == "" {
+		binary = "aleutian"
+	}
+
+	args := []string{"hooks", "run", string(h), "--threshold", strings.ToLower(string(cfg.threshold(h)))}
+	if !cfg.BlockSecrets {
+		args = append(args, "--allow-secrets")
+	}
+	if cfg.DaemonAddr != "" {
+		args = append(args, "--daemon-addr", shellQuote(cfg.DaemonAddr))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(ManagedMarker + "\n")
+	sb.WriteString("# Installed by `aleutian hooks install`. Remove with `aleutian hooks uninstall`.\n\n")
+	sb.WriteString("hook_dir=$(dirname \"$0\")\n")
+	sb.WriteString(fmt.Sprintf("if [ -x \"$hook_dir/%s%s\" ]; then\n", h, BackupSuffix))
+	sb.WriteString(fmt.Sprintf("\t\"$hook_dir/%s%s\" \"$@\" || exit $?\n", h, BackupSuffix))
+	sb.WriteString("fi\n\n")
+	sb.WriteString(fmt.Sprintf("if ! command -v %s >/dev/null 2>&1; then\n", shellQuote(binary)))
+	sb.WriteString("\techo \"aleutian: not found on PATH, skipping " + string(h) + " checks\" >&2\n")
+	sb.WriteString("\texit 0\n")
+	sb.WriteString("fi\n\n")
+	sb.WriteString(fmt.Sprintf("exec %s %s\n", shellQuote(binary), strings.Join(args, " ")))
+	return sb.String()
+}
+
+// Install writes the configured hooks.
+//
+// # Description
+//
+// Managed hooks are overwritten. An unmanaged hook is moved to
+// "<hook>.aleutian-backup" and chained; if a backup already exists the
+// hook is left alone and ErrHookExists is returned so nothing is lost.
+//
+// # Outputs
+//
+//   - []HookStatus: Status of each installed hook.
+//   - error: Non-nil if any hook could not be installed.
+func Install(ctx context.Context, cfg Config) ([]HookStatus, error) {
+	dir, err := HooksDir(ctx, cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating hooks dir: %w", err)
+	}
+
+	statuses := make([]HookStatus, 0, len(cfg.Hooks))
+	for _, h := range cfg.Hooks {
+		path := filepath.Join(dir, string(h))
+		backup := path + BackupSuffix
+
+		if data, err := os.ReadFile(path); err == nil && !isManaged(data) {
+			if _, err := os.Stat(backup); err == nil {
+				return statuses, fmt.Errorf("%w: %s (backup %s already present)", ErrHookExists, path, filepath.Base(backup))
+			}
+			if err := os.Rename(path, backup); err != nil {
+				return statuses, fmt.Errorf("backing up %s: %w", path, err)
+			}
+		}
+
+		if err := os.WriteFile(path, []byte(Script(h, cfg)), 0o755); err != nil {
+			return statuses, fmt.Errorf("writing %s: %w", path, err)
+		}
+		statuses = append(statuses, hookStatus(h, dir))
+	}
+	return statuses, nil
+}
+
+// Uninstall removes managed hooks and restores any backups.
+//
+// Unmanaged hooks are never removed.
+func Uninstall(ctx context.Context, cfg Config) ([]HookStatus, error) {
+	dir, err := HooksDir(ctx, cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]HookStatus, 0, len(cfg.Hooks))
+	for _, h := range cfg.Hooks {
+		path := filepath.Join(dir, string(h))
+		if data, err := os.ReadFile(path); err == nil && isManaged(data) {
+			if err := os.Remove(path); err != nil {
+				return statuses, fmt.Errorf("removing %s: %w", path, err)
+			}
+			if _, err := os.Stat(path + BackupSuffix); err == nil {
+				if err := os.Rename(path+BackupSuffix, path); err != nil {
+					return statuses, fmt.Errorf("restoring %s: %w", path, err)
+				}
+			}
+		}
+		statuses = append(statuses, hookStatus(h, dir))
+	}
+	return statuses, nil
+}
+
+// Status reports the state of every supported hook.
+func Status(ctx context.Context, projectRoot string) ([]HookStatus, error) {
+	dir, err := HooksDir(ctx, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]HookStatus, 0, len(SupportedHooks()))
+	for _, h := range SupportedHooks() {
+		statuses = append(statuses, hookStatus(h, dir))
+	}
+	return statuses, nil
+}
+
+// hookStatus inspects one hook file.
+func hookStatus(h HookType, dir string) HookStatus {
+	path := filepath.Join(dir, string(h))
+	st := HookStatus{Hook: h, Path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		st.Installed = true
+		st.Managed = isManaged(data)
+	}
+	if _, err := os.Stat(path + BackupSuffix); err == nil {
+		st.HasBackup = true
+	}
+	return st
+}
+
+// isManaged reports whether a hook script was written by Aleutian.
+func isManaged(data []byte) bool {
+	return bytes.Contains(data, []byte(ManagedMarker))
+}
+
+// shellQuote quotes s for a POSIX shell if it contains special characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./:@=", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/watch"
+)
+
+// initRepo creates a temporary git repository.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestParseHookType(t *testing.T) {
+	if h, err := ParseHookType("pre-push"); err != nil || h != HookPrePush {
+		t.Errorf("ParseHookType(pre-push) = %v, %v", h, err)
+	}
+	if _, err := ParseHookType("post-merge"); !errors.Is(err, ErrUnknownHook) {
+		t.Errorf("ParseHookType(post-merge) error = %v, want ErrUnknownHook", err)
+	}
+}
+
+func TestScript(t *testing.T) {
+	cfg := DefaultConfig("/repo")
+	cfg.Thresholds[HookPreCommit] = risk.RiskCritical
+	cfg.BlockSecrets = false
+	cfg.DaemonAddr = "unix:/tmp/my sock"
+
+	s := Script(HookPreCommit, cfg)
+	for _, want := range []string{
+		"#!/bin/sh",
+		ManagedMarker,
+		"pre-commit" + BackupSuffix,
+		"exec aleutian hooks run pre-commit --threshold critical --allow-secrets --daemon-addr 'unix:/tmp/my sock'",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("script missing %q:\n%s", want, s)
+		}
+	}
+
+	if s := Script(HookPrePush, DefaultConfig("/repo")); !strings.Contains(s, "--threshold medium") {
+		t.Errorf("pre-push should default to medium:\n%s", s)
+	}
+}
+
+func TestInstallUninstall(t *testing.T) {
+	root := initRepo(t)
+	ctx := context.Background()
+	dir, err := HooksDir(ctx, root)
+	if err != nil {
+		t.Fatalf("HooksDir: %v", err)
+	}
+
+	// An existing team hook must be preserved and chained.
+	existing := filepath.Join(dir, "pre-commit")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("#!/bin/sh\necho team\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig(root)
+	statuses, err := Install(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+	for _, st := range statuses {
+		if !st.Installed || !st.Managed {
+			t.Errorf("%s: installed=%v managed=%v", st.Hook, st.Installed, st.Managed)
+		}
+	}
+	if !statuses[0].HasBackup {
+		t.Error("expected pre-commit backup")
+	}
+
+	// Reinstall overwrites managed hooks without touching the backup.
+	if _, err := Install(ctx, cfg); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+
+	if _, err := Uninstall(ctx, cfg); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	data, err := os.ReadFile(existing)
+	if err != nil || !strings.Contains(string(data), "echo team") {
+		t.Errorf("team hook not restored: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-push")); !os.IsNotExist(err) {
+		t.Error("managed pre-push should be removed")
+	}
+}
+
+func TestInstall_RefusesToClobberBackup(t *testing.T) {
+	root := initRepo(t)
+	ctx := context.Background()
+	dir, _ := HooksDir(ctx, root)
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "pre-commit"), []byte("#!/bin/sh\n"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pre-commit"+BackupSuffix), []byte("#!/bin/sh\n"), 0o755)
+
+	cfg := DefaultConfig(root)
+	cfg.Hooks = []HookType{HookPreCommit}
+	if _, err := Install(ctx, cfg); !errors.Is(err, ErrHookExists) {
+		t.Errorf("Install error = %v, want ErrHookExists", err)
+	}
+}
+
+func TestHooksDir_NotGitRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	if _, err := HooksDir(context.Background(), t.TempDir()); !errors.Is(err, ErrNotGitRepo) {
+		t.Errorf("HooksDir error = %v, want ErrNotGitRepo", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	result := risk.NewResult()
+	result.RiskLevel = risk.RiskMedium
+
+	if blocked, _ := Evaluate(result, risk.RiskHigh, true); blocked {
+		t.Error("medium risk should not block at high threshold")
+	}
+	if blocked, reasons := Evaluate(result, risk.RiskLow, true); !blocked || len(reasons) != 1 {
+		t.Errorf("medium risk should block at low threshold: %v", reasons)
+	}
+
+	result.Signals.Policy = &risk.PolicySignal{HasCritical: true, CriticalCount: 2}
+	if blocked, reasons := Evaluate(result, risk.RiskCritical, true); !blocked || !strings.Contains(reasons[0], "2 critical") {
+		t.Errorf("critical secrets should block: %v", reasons)
+	}
+	if blocked, _ := Evaluate(result, risk.RiskCritical, false); blocked {
+		t.Error("secrets should not block when allowed")
+	}
+	if blocked, _ := Evaluate(nil, risk.RiskLow, true); blocked {
+		t.Error("nil result should not block")
+	}
+}
+
+func TestRun_UsesDaemonFastPath(t *testing.T) {
+	var got watch.AssessRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/assess" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		result := risk.NewResult()
+		result.RiskLevel = risk.RiskCritical
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer srv.Close()
+
+	out, err := Run(context.Background(), RunConfig{
+		Hook:         HookPreCommit,
+		ProjectRoot:  t.TempDir(),
+		BlockSecrets: true,
+		DaemonAddr:   strings.TrimPrefix(srv.URL, "http://"),
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out.Source != SourceDaemon {
+		t.Errorf("Source = %q, want daemon", out.Source)
+	}
+	if got.Mode != string(risk.ChangeModeStaged) {
+		t.Errorf("request mode = %q, want staged", got.Mode)
+	}
+	if !out.Blocked || out.Threshold != string(risk.RiskHigh) {
+		t.Errorf("critical risk should block at default threshold: %+v", out)
+	}
+}
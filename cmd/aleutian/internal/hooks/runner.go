@@ -0,0 +1,284 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/watch"
+)
+
+// Default runner values.
+const (
+	// DefaultDaemonTimeout bounds the fast-path request so a hook backed by
+	// the watch daemon finishes in under two seconds.
+	DefaultDaemonTimeout = 1500 * time.Millisecond
+
+	// DefaultRunTimeout bounds the local (cold index) assessment.
+	DefaultRunTimeout = 60 * time.Second
+)
+
+// Assessment sources reported in Outcome.Source.
+const (
+	SourceDaemon = "daemon"
+	SourceLocal  = "local"
+)
+
+// RunConfig configures a single hook run.
+type RunConfig struct {
+	// Hook is the hook being run.
+	Hook HookType
+
+	// ProjectRoot is the repository root.
+	ProjectRoot string
+
+	// Threshold is the blocking level; risk above it blocks.
+	Threshold risk.RiskLevel
+
+	// BlockSecrets blocks on any critical secret-scan finding.
+	BlockSecrets bool
+
+	// BaseRef is the base for pre-push. Empty resolves the upstream branch.
+	BaseRef string
+
+	// DaemonAddr is the watch daemon address ("host:port" or "unix:/path").
+	// Empty uses watch.DefaultAddr.
+	DaemonAddr string
+
+	// DaemonTimeout bounds the fast-path request.
+	DaemonTimeout time.Duration
+
+	// NoDaemon disables the fast path.
+	NoDaemon bool
+
+	// Timeout bounds the local assessment. Zero uses the risk default.
+	Timeout time.Duration
+}
+
+// Outcome is the result of a hook run.
+type Outcome struct {
+	Hook       HookType     `json:"hook"`
+	Source     string       `json:"source"`
+	Threshold  string       `json:"threshold"`
+	Blocked    bool         `json:"blocked"`
+	Reasons    []string     `json:"reasons,omitempty"`
+	Warnings   []string     `json:"warnings,omitempty"`
+	Result     *risk.Result `json:"result"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+// Run assesses the change set for a hook.
+//
+// # Description
+//
+// Tries the watch daemon first (unless disabled), then falls back to a
+// local assessment using the on-disk index. If no index exists the impact
+// signal is skipped and the policy and complexity signals still run.
+//
+// # Outputs
+//
+//   - *Outcome: The assessment and blocking decision.
+//   - error: Non-nil if no assessment could be completed. Callers should
+//     fail open on error.
+func Run(ctx context.Context, cfg RunConfig) (*Outcome, error) {
+	start := time.Now()
+	if cfg.Threshold == "" {
+		cfg.Threshold = DefaultThreshold(cfg.Hook)
+	}
+
+	req, err := assessRequest(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Outcome{Hook: cfg.Hook, Threshold: string(cfg.Threshold)}
+
+	if !cfg.NoDaemon {
+		result, err := assessViaDaemon(ctx, cfg, req)
+		if err == nil {
+			out.Source = SourceDaemon
+			out.Result = result
+		}
+	}
+
+	if out.Result == nil {
+		result, warnings, err := assessLocally(ctx, cfg, req)
+		if err != nil {
+			return nil, err
+		}
+		out.Source = SourceLocal
+		out.Result = result
+		out.Warnings = warnings
+	}
+
+	out.Blocked, out.Reasons = Evaluate(out.Result, cfg.Threshold, cfg.BlockSecrets)
+	out.DurationMs = time.Since(start).Milliseconds()
+	return out, nil
+}
+
+// Evaluate decides whether a result blocks the hook.
+//
+// # Outputs
+//
+//   - bool: True if the hook should block.
+//   - []string: Human-readable reasons for blocking.
+func Evaluate(result *risk.Result, threshold risk.RiskLevel, blockSecrets bool) (bool, []string) {
+	if result == nil {
+		return false, nil
+	}
+
+	var reasons []string
+	if result.RiskLevel.Exceeds(threshold) {
+		reasons = append(reasons, fmt.Sprintf("risk %s exceeds blocking level %s", result.RiskLevel, threshold))
+	}
+	if blockSecrets {
+		if p := result.Signals.Policy; p != nil && p.HasCritical {
+			reasons = append(reasons, fmt.Sprintf("secret scan found %d critical finding(s)", p.CriticalCount))
+		}
+	}
+	return len(reasons) > 0, reasons
+}
+
+// assessRequest builds the change-set description for the hook.
+func assessRequest(ctx context.Context, cfg RunConfig) (watch.AssessRequest, error) {
+	switch cfg.Hook {
+	case HookPreCommit:
+		return watch.AssessRequest{Mode: string(risk.ChangeModeStaged)}, nil
+	case HookPrePush:
+		base, err := resolveBaseRef(ctx, cfg.ProjectRoot, cfg.BaseRef)
+		if err != nil {
+			return watch.AssessRequest{}, err
+		}
+		return watch.AssessRequest{Mode: string(risk.ChangeModeBranch), BaseBranch: base}, nil
+	default:
+		return watch.AssessRequest{}, fmt.Errorf("%w: %q", ErrUnknownHook, cfg.Hook)
+	}
+}
+
+// resolveBaseRef returns base, or the upstream branch, or origin/HEAD.
+func resolveBaseRef(ctx context.Context, projectRoot, base string) (string, error) {
+	if base != "" {
+		return base, nil
+	}
+	for _, ref := range []string{"@{upstream}", "origin/HEAD"} {
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", ref)
+		cmd.Dir = projectRoot
+		if out, err := cmd.Output(); err == nil {
+			if name := strings.TrimSpace(string(out)); name != "" {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no upstream branch; pass --base")
+}
+
+// assessViaDaemon asks a running watch daemon to assess the change.
+func assessViaDaemon(ctx context.Context, cfg RunConfig, req watch.AssessRequest) (*risk.Result, error) {
+	timeout := cfg.DaemonTimeout
+	if timeout <= 0 {
+		timeout = DefaultDaemonTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, url := daemonClient(cfg.DaemonAddr)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/assess", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("daemon returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result risk.Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding daemon response: %w", err)
+	}
+	return &result, nil
+}
+
+// daemonClient returns an HTTP client and base URL for addr. Addresses of
+// the form "unix:/path" dial a unix socket.
+func daemonClient(addr string) (*http.Client, string) {
+	if addr == "" {
+		addr = watch.DefaultAddr
+	}
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+		return &http.Client{Transport: transport}, "http://aleutian"
+	}
+	return &http.Client{}, "http://" + addr
+}
+
+// assessLocally runs the risk aggregator in-process with the on-disk index.
+func assessLocally(ctx context.Context, cfg RunConfig, req watch.AssessRequest) (*risk.Result, []string, error) {
+	var warnings []string
+
+	rcfg := risk.DefaultConfig()
+	rcfg.ProjectRoot = cfg.ProjectRoot
+	rcfg.Mode = risk.ChangeMode(req.Mode)
+	rcfg.BaseBranch = req.BaseBranch
+	rcfg.Quiet = true
+	rcfg.BestEffort = true
+	if cfg.Timeout > 0 {
+		rcfg.Timeout = int(cfg.Timeout.Seconds())
+	}
+
+	var index *initializer.MemoryIndex
+	storage := initializer.NewStorage(cfg.ProjectRoot)
+	if storage.Exists() {
+		loaded, err := storage.LoadIndex(ctx)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not load index, skipping impact: %v", err))
+		} else {
+			index = loaded
+		}
+	} else {
+		warnings = append(warnings, "no index found (run 'aleutian init'), skipping impact")
+	}
+	rcfg.SkipImpact = index == nil
+
+	result, err := risk.NewAggregator(index, cfg.ProjectRoot).Assess(ctx, rcfg)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return result, warnings, nil
+}
@@ -39,4 +39,12 @@
 //	┌─────────┐     ┌───────────────────┐     ┌───────────────────┐
 //	│ Writer  │◀────│ resultChan (buf)  │◀────│ Parse Results     │
 //	└─────────┘     └───────────────────┘     └───────────────────┘
+//
+// # Worker Tuning
+//
+// With Config.AdaptiveWorkers set, N starts at GOMAXPROCS and is tuned by
+// hill climbing on measured files per second. N stays within MaxWorkers
+// and GOMAXPROCS, or 2×GOMAXPROCS while most per-file time is spent
+// reading. Result.Workers reports the chosen N and Result.Timings the
+// time spent in each stage.
 package initializer
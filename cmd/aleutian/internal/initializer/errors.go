@@ -13,6 +13,8 @@ package initializer
 import (
 	"errors"
 	"fmt"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
 )
 
 // Exit codes for the init command.
@@ -22,24 +24,42 @@ const (
 	ExitBadArgs = 2 // Invalid arguments
 )
 
+// remediationRebuild tells the user how to recover from a bad index.
+const remediationRebuild = "Rebuild the index with 'aleutian init --force'"
+
 // Sentinel errors for initialization.
 var (
 	// Configuration errors
 	ErrEmptyProjectRoot   = errors.New("project root must not be empty")
 	ErrInvalidMaxWorkers  = errors.New("max workers must be greater than 0")
 	ErrInvalidMaxFileSize = errors.New("max file size must be greater than 0")
-	ErrPathNotExist       = errors.New("path does not exist")
-	ErrPathNotDirectory   = errors.New("path is not a directory")
+	ErrPathNotExist       = apperr.New("PATH_NOT_FOUND", apperr.CategoryInvalidInput, "path does not exist")
+	ErrPathNotDirectory   = apperr.New("PATH_NOT_DIRECTORY", apperr.CategoryInvalidInput, "path is not a directory")
 	ErrPathTraversal      = errors.New("path traversal detected")
 
 	// Lock errors
 	ErrLockAcquireFailed = errors.New("failed to acquire lock")
-	ErrLockHeld          = errors.New("another init operation is in progress")
+
+	// ErrLockHeld indicates another init holds the project lock.
+	ErrLockHeld = apperr.New("INIT_LOCKED", apperr.CategoryConflict,
+		"another init operation is in progress").
+		WithRemediation("Wait for the other 'aleutian init' to finish")
+
+	// ErrIndexNotFound indicates the project has no index.
+	ErrIndexNotFound = apperr.New("INDEX_NOT_FOUND", apperr.CategoryNotFound,
+		"index not found").WithRemediation("Run 'aleutian init' first")
+
+	// ErrIndexExists indicates init would overwrite an existing index.
+	ErrIndexExists = apperr.New("INDEX_EXISTS", apperr.CategoryInvalidInput,
+		"index already exists").WithRemediation("Use --force to rebuild")
 
 	// Storage errors
-	ErrIndexCorrupted     = errors.New("index file is corrupted")
-	ErrChecksumMismatch   = errors.New("checksum validation failed")
-	ErrVersionMismatch    = errors.New("index format version mismatch")
+	ErrIndexCorrupted = apperr.New("INDEX_CORRUPTED", apperr.CategoryInternal,
+		"index file is corrupted").WithRemediation(remediationRebuild)
+	ErrChecksumMismatch = apperr.New("INDEX_CHECKSUM_MISMATCH", apperr.CategoryInternal,
+		"checksum validation failed").WithRemediation(remediationRebuild)
+	ErrVersionMismatch = apperr.New("INDEX_VERSION_MISMATCH", apperr.CategoryInternal,
+		"index format version mismatch").WithRemediation(remediationRebuild)
 	ErrAtomicSwapFailed   = errors.New("atomic directory swap failed")
 	ErrDatabaseOpenFailed = errors.New("failed to open database")
 
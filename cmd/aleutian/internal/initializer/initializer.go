@@ -21,6 +21,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/util"
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
 )
 
 // IndexBuilder defines the interface for building the code index.
@@ -38,13 +41,12 @@ type IndexBuilder interface {
 // # Description
 //
 // Initializer orchestrates the parsing and indexing of source files.
-// It uses buffered channels for work distribution to achieve
-// parallel file processing.
+// It uses a bounded util.Pool for parallel file processing.
 //
 // # Architecture
 //
 //	┌─────────┐     ┌───────────────────┐     ┌───────────────────┐
-//	│ Scanner │────▶│ fileChan (buffer) │────▶│ Worker Pool (N)   │
+//	│ Scanner │────▶│ util.Pool queue   │────▶│ Worker Pool (N)   │
 //	└─────────┘     └───────────────────┘     └───────────────────┘
 //	                                                   │
 //	                                                   ▼
@@ -168,6 +170,7 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	}
 
 	// Detect languages if not specified
+	stageStart := time.Now()
 	languages := cfg.Languages
 	if len(languages) == 0 {
 		languages = detectLanguages(cfg.ProjectRoot)
@@ -176,13 +179,16 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 		return nil, ErrNoLanguages
 	}
 	result.Languages = languages
+	result.Timings.DetectMs = time.Since(stageStart).Milliseconds()
 
 	// Check for dry run
 	if cfg.DryRun {
+		stageStart = time.Now()
 		files, err := scanFiles(ctx, cfg.ProjectRoot, languages, cfg.ExcludePatterns, cfg.MaxFileSize)
 		if err != nil {
 			return nil, fmt.Errorf("scanning files: %w", err)
 		}
+		result.Timings.ScanMs = time.Since(stageStart).Milliseconds()
 		result.FilesIndexed = len(files)
 		result.DurationMs = time.Since(start).Milliseconds()
 		result.IndexPath = filepath.Join(cfg.ProjectRoot, AleutianDir)
@@ -198,6 +204,7 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	}
 
 	// Scan for files
+	stageStart = time.Now()
 	files, err := scanFiles(ctx, cfg.ProjectRoot, languages, cfg.ExcludePatterns, cfg.MaxFileSize)
 	if err != nil {
 		return nil, fmt.Errorf("scanning files: %w", err)
@@ -205,6 +212,7 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	if len(files) == 0 {
 		return nil, ErrNoSupportedFiles
 	}
+	result.Timings.ScanMs = time.Since(stageStart).Milliseconds()
 
 	// Report progress: parsing
 	if progress != nil {
@@ -216,11 +224,16 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	}
 
 	// Parse files in parallel using buffered channels
-	symbols, edges, warnings, err := i.parseFilesParallel(ctx, cfg, files, progress)
+	stageStart = time.Now()
+	symbols, edges, warnings, parseStats, err := i.parseFilesParallel(ctx, cfg, files, progress)
 	if err != nil {
 		return nil, fmt.Errorf("parsing files: %w", err)
 	}
 	result.Warnings = warnings
+	result.Workers = parseStats.Workers
+	result.Timings.ParseMs = time.Since(stageStart).Milliseconds()
+	result.Timings.FileReadMs = parseStats.ReadTime.Milliseconds()
+	result.Timings.FileParseMs = parseStats.ParseTime.Milliseconds()
 
 	// Report progress: writing
 	if progress != nil {
@@ -231,6 +244,7 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	}
 
 	// Create manifest
+	stageStart = time.Now()
 	manifest := &ManifestFile{
 		FormatVersion:  FormatVersion,
 		ProjectRoot:    cfg.ProjectRoot,
@@ -265,12 +279,20 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 		UpdatedAt:       now,
 	}
 
+	// Keep settings made with 'aleutian config set' across re-indexing
+	if prev, err := projectconfig.Load(cfg.ProjectRoot); err == nil {
+		projectConfig.LintProfile = prev.LintProfile
+		projectConfig.ToolPolicy = prev.ToolPolicy
+	}
+
 	// Write index
 	writeResult, err := i.storage.WriteIndex(ctx, symbols, edges, manifest, projectConfig)
 	if err != nil {
 		return nil, fmt.Errorf("writing index: %w", err)
 	}
 
+	result.Timings.WriteMs = time.Since(stageStart).Milliseconds()
+
 	// Suggest adding .aleutian to .gitignore
 	gitignorePath := filepath.Join(cfg.ProjectRoot, ".gitignore")
 	if shouldSuggestGitignore(gitignorePath) {
@@ -296,21 +318,23 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 	return result, nil
 }
 
-// parseFilesParallel parses files using buffered channels for concurrency.
+// parseFilesParallel parses files on a bounded worker pool.
 //
 // # Description
 //
-// Uses a worker pool pattern with buffered channels:
-//   - fileChan: buffered channel for distributing files to workers
-//   - resultChan: buffered channel for collecting parse results
+// Files are submitted to a util.Pool of up to MaxWorkers workers, which
+// send their results on a buffered channel. The collector reads results
+// until the pool has drained. A parser panic is reported as a warning
+// instead of crashing the command.
 //
-// Workers read from fileChan until it's closed, then exit.
-// The collector reads from resultChan until all workers are done.
+// A workerTuner caps how many files are in flight. With AdaptiveWorkers
+// set it tunes the cap from measured throughput and read time; otherwise
+// the cap is MaxWorkers.
 //
 // # Inputs
 //
 //   - ctx: Context for cancellation.
-//   - cfg: Configuration with MaxWorkers and FileTimeout.
+//   - cfg: Configuration with MaxWorkers, AdaptiveWorkers and FileTimeout.
 //   - files: List of file paths to parse.
 //   - progress: Optional progress callback.
 //
@@ -319,64 +343,57 @@ func (i *Initializer) Init(ctx context.Context, cfg Config, progress ProgressCal
 //   - []Symbol: All extracted symbols.
 //   - []Edge: All extracted edges.
 //   - []string: Warnings for files that failed to parse.
+//   - tunerStats: Chosen concurrency and summed read and parse time.
 //   - error: Non-nil only on fatal errors or context cancellation.
 func (i *Initializer) parseFilesParallel(
 	ctx context.Context,
 	cfg Config,
 	files []string,
 	progress ProgressCallback,
-) ([]Symbol, []Edge, []string, error) {
-	numWorkers := cfg.MaxWorkers
-	if numWorkers > len(files) {
-		numWorkers = len(files)
-	}
-	if numWorkers <= 0 {
-		numWorkers = 1
-	}
-
-	// Buffered channels for work distribution
-	fileChan := make(chan string, DefaultChannelBuffer)
+) ([]Symbol, []Edge, []string, tunerStats, error) {
+	tuner := newWorkerTuner(cfg.MaxWorkers, len(files), cfg.AdaptiveWorkers)
 	resultChan := make(chan ParseResult, DefaultChannelBuffer)
 
-	// WaitGroup for workers
-	var wg sync.WaitGroup
-	wg.Add(numWorkers)
+	var (
+		panicMu       sync.Mutex
+		panicWarnings []string
+	)
+	pool := util.NewPool(util.PoolConfig{
+		MinWorkers: 1,
+		MaxWorkers: tuner.maxWorkers(),
+		QueueSize:  DefaultChannelBuffer,
+		OnPanic: func(r util.SafeGoResult) {
+			panicMu.Lock()
+			panicWarnings = append(panicWarnings, fmt.Sprintf("parser panic: %v", r.PanicValue))
+			panicMu.Unlock()
+		},
+	})
+
+	// Submit files, then close resultChan once the pool has drained
+	go func() {
+		defer close(resultChan)
+		for _, f := range files {
+			// Wait for a slot so no more than the tuned limit run at once
+			if !tuner.acquire(ctx) {
+				break
+			}
+			err := pool.Submit(ctx, func() {
+				var result ParseResult
+				defer func() { tuner.release(result.ReadTime, result.ParseTime) }()
 
-	// Start workers
-	for w := 0; w < numWorkers; w++ {
-		go func() {
-			defer wg.Done()
-			for filePath := range fileChan {
 				// Check for cancellation
-				select {
-				case <-ctx.Done():
+				if ctx.Err() != nil {
 					return
-				default:
 				}
-
-				// Parse with timeout
-				result := parseFileWithTimeout(ctx, filePath, cfg.FileTimeout)
+				result = parseFileWithTimeout(ctx, f, cfg.FileTimeout)
 				resultChan <- result
-			}
-		}()
-	}
-
-	// Goroutine to close resultChan when workers are done
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Send files to workers
-	go func() {
-		defer close(fileChan)
-		for _, f := range files {
-			select {
-			case <-ctx.Done():
-				return
-			case fileChan <- f:
+			})
+			if err != nil {
+				tuner.release(0, 0)
+				break
 			}
 		}
+		_ = pool.Shutdown(context.Background())
 	}()
 
 	// Collect results
@@ -411,8 +428,9 @@ func (i *Initializer) parseFilesParallel(
 		symbols = append(symbols, result.Symbols...)
 		edges = append(edges, result.Edges...)
 	}
+	warnings = append(warnings, panicWarnings...)
 
-	return symbols, edges, warnings, nil
+	return symbols, edges, warnings, tuner.stats(), nil
 }
 
 // parseFileWithTimeout parses a single file with optional timeout.
@@ -427,7 +445,9 @@ func parseFileWithTimeout(ctx context.Context, filePath string, timeout time.Dur
 	}
 
 	// Read file
+	readStart := time.Now()
 	content, err := os.ReadFile(filePath)
+	result.ReadTime = time.Since(readStart)
 	if err != nil {
 		result.Error = fmt.Errorf("reading file: %w", err)
 		return result
@@ -437,7 +457,9 @@ func parseFileWithTimeout(ctx context.Context, filePath string, timeout time.Dur
 	lang := languageFromExtension(filepath.Ext(filePath))
 
 	// Parse using simple extraction (tree-sitter would be used in production)
+	parseStart := time.Now()
 	symbols, edges := extractSymbolsSimple(ctx, filePath, content, lang)
+	result.ParseTime = time.Since(parseStart)
 	result.Symbols = symbols
 	result.Edges = edges
 
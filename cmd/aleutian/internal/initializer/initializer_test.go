@@ -16,6 +16,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
 )
 
 // TestInitializer_Init_BasicGo tests initialization of a simple Go project.
@@ -276,6 +278,47 @@ func TestInitializer_Init_Force(t *testing.T) {
 	}
 }
 
+// TestInitializer_Init_KeepsProjectSettings tests that re-indexing keeps
+// the lint profile and tool policy set with 'aleutian config set'.
+func TestInitializer_Init_KeepsProjectSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create Go file: %v", err)
+	}
+
+	storage := NewStorage(tempDir)
+	init := NewInitializer(storage)
+	ctx := context.Background()
+	cfg := DefaultConfig(tempDir)
+
+	if _, err := init.Init(ctx, cfg, nil); err != nil {
+		t.Fatalf("First init failed: %v", err)
+	}
+
+	settings, err := storage.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	settings.LintProfile = "strict"
+	settings.ToolPolicy.Disabled = []string{"write_file"}
+	if err := projectconfig.Save(tempDir, settings); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cfg.Force = true
+	if _, err := init.Init(ctx, cfg, nil); err != nil {
+		t.Fatalf("Force init failed: %v", err)
+	}
+
+	settings, err = storage.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig after re-init failed: %v", err)
+	}
+	if settings.LintProfile != "strict" || len(settings.ToolPolicy.Disabled) != 1 {
+		t.Errorf("settings after re-init = %+v, want lint profile and tool policy kept", settings)
+	}
+}
+
 // TestInitializer_Init_Cancellation tests context cancellation.
 func TestInitializer_Init_Cancellation(t *testing.T) {
 	tempDir := t.TempDir()
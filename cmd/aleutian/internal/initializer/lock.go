@@ -15,7 +15,6 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"syscall"
 	"time"
 )
 
@@ -27,7 +26,8 @@ import (
 //
 // # Platform Support
 //
-// Uses flock(2) on Unix systems. On Windows, uses LockFileEx.
+// Uses flock(2) on Unix systems. On js/wasm and wasip1 there are no other
+// processes to exclude, so locking always succeeds (see lock_nolock.go).
 type FileLock struct {
 	path string
 	file *os.File
@@ -90,10 +90,10 @@ func (l *FileLock) Acquire() error {
 	}
 
 	// Try to acquire exclusive lock (non-blocking)
-	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-	if err != nil {
+	held, err := tryLock(file)
+	if err != nil || held {
 		file.Close()
-		if err == syscall.EWOULDBLOCK {
+		if held {
 			return ErrLockHeld
 		}
 		return fmt.Errorf("%w: flock: %v", ErrLockAcquireFailed, err)
@@ -135,7 +135,7 @@ func (l *FileLock) Release() error {
 	}
 
 	// Release the lock
-	_ = syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	unlock(l.file)
 
 	// Close the file
 	err := l.file.Close()
@@ -184,16 +184,13 @@ func (l *FileLock) IsHeld() (bool, error) {
 	}
 	defer file.Close()
 
-	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-	if err == syscall.EWOULDBLOCK {
-		return true, nil
-	}
-	if err != nil {
-		return false, err
+	held, err := tryLock(file)
+	if held || err != nil {
+		return held, err
 	}
 
 	// Lock was acquired, release it
-	_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	unlock(file)
 	return false, nil
 }
 
@@ -258,16 +255,8 @@ func (l *FileLock) IsStale() bool {
 
 	// Check if holder process exists
 	pid := l.HolderPID()
-	if pid > 0 {
-		// Check if process exists by sending signal 0
-		process, err := os.FindProcess(pid)
-		if err != nil {
-			return true
-		}
-		err = process.Signal(syscall.Signal(0))
-		if err != nil {
-			return true // Process doesn't exist
-		}
+	if pid > 0 && !processExists(pid) {
+		return true
 	}
 
 	return false
@@ -0,0 +1,30 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+//go:build js || wasip1
+
+package initializer
+
+import "os"
+
+// tryLock always succeeds: a WebAssembly module has no other processes to
+// exclude.
+func tryLock(*os.File) (bool, error) {
+	return false, nil
+}
+
+// unlock is a no-op.
+func unlock(*os.File) {}
+
+// processExists reports true: liveness cannot be checked, so locks are
+// only considered stale by age.
+func processExists(int) bool {
+	return true
+}
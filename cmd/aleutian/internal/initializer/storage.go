@@ -21,6 +21,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
 )
 
 // StorageWriter defines the interface for writing index data.
@@ -404,6 +406,45 @@ func (s *Storage) LoadManifest(validateChecksums bool) (*ManifestFile, error) {
 	return &manifest, nil
 }
 
+// LoadConfig loads the project config.yaml from disk.
+//
+// # Description
+//
+// Strictly parses config.yaml: unknown keys, mistyped values, unsupported
+// languages and invalid exclude globs are all reported together with the
+// line they appear on, instead of surfacing later as indexing failures.
+//
+// # Outputs
+//
+//   - *ProjectConfig: The loaded config. Never nil on success.
+//   - error: Non-nil on failure. Wraps ErrIndexCorrupted with a
+//     *validation.YAMLError if the file is invalid, ErrVersionMismatch if
+//     the format version differs.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (s *Storage) LoadConfig() (*ProjectConfig, error) {
+	path := filepath.Join(s.AleutianPath(), ConfigFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	config, err := projectconfig.Parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIndexCorrupted, err)
+	}
+
+	if config.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: expected %s, got %s",
+			ErrVersionMismatch, FormatVersion, config.FormatVersion)
+	}
+
+	return config, nil
+}
+
 // validateChecksums verifies the index file checksum.
 func (s *Storage) validateChecksums(manifest *ManifestFile) error {
 	indexPath := filepath.Join(s.AleutianPath(), IndexFileName)
@@ -465,6 +506,26 @@ func (s *Storage) LoadIndex(ctx context.Context) (*MemoryIndex, error) {
 		return nil, fmt.Errorf("reading index: %w", err)
 	}
 
+	return ParseIndex(data)
+}
+
+// ParseIndex parses the contents of an index.json file.
+//
+// # Description
+//
+// Decodes symbols and edges and builds the in-memory lookup indexes.
+// Needs no filesystem access, so an exported index can be queried where
+// there is none (e.g. a browser running the WebAssembly build).
+//
+// # Inputs
+//
+//   - data: The index.json contents.
+//
+// # Outputs
+//
+//   - *MemoryIndex: The index with built lookup maps.
+//   - error: ErrIndexCorrupted if data cannot be parsed.
+func ParseIndex(data []byte) (*MemoryIndex, error) {
 	var index MemoryIndex
 	if err := json.Unmarshal(data, &index); err != nil {
 		return nil, fmt.Errorf("%w: parsing index: %v", ErrIndexCorrupted, err)
@@ -660,27 +721,12 @@ func (s *Storage) writeManifest(path string, manifest *ManifestFile) error {
 
 // writeConfig writes the project config to a YAML file.
 func (s *Storage) writeConfig(path string, config *ProjectConfig) error {
-	// Simple YAML serialization (avoiding external dependency)
-	content := fmt.Sprintf(`# Aleutian Trace Configuration
-# Auto-generated - do not edit manually
-
-format_version: %q
-created_at: %q
-updated_at: %q
-
-languages:
-`, config.FormatVersion, config.CreatedAt, config.UpdatedAt)
-
-	for _, lang := range config.Languages {
-		content += fmt.Sprintf("  - %q\n", lang)
-	}
-
-	content += "\nexclude_patterns:\n"
-	for _, pattern := range config.ExcludePatterns {
-		content += fmt.Sprintf("  - %q\n", pattern)
+	data, err := projectconfig.Marshal(config)
+	if err != nil {
+		return &StorageError{Op: "write_config", Err: err}
 	}
 
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := os.WriteFile(path, data, 0644); err != nil {
 		return &StorageError{Op: "write_config", Err: err}
 	}
 
@@ -12,8 +12,10 @@ package initializer
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -236,6 +238,29 @@ func TestStorage_LoadIndex(t *testing.T) {
 	}
 }
 
+// TestParseIndex tests parsing an exported index without a Storage.
+func TestParseIndex(t *testing.T) {
+	data := []byte(`{
+		"symbols": [
+			{"id": "sym1", "name": "main", "kind": "function", "file_path": "main.go"},
+			{"id": "sym2", "name": "helper", "kind": "function", "file_path": "main.go"}
+		],
+		"edges": [{"from_id": "sym1", "to_id": "sym2", "kind": "calls", "file_path": "main.go", "line": 5}]
+	}`)
+
+	index, err := ParseIndex(data)
+	if err != nil {
+		t.Fatalf("ParseIndex failed: %v", err)
+	}
+	if index.GetByID("sym2") == nil || len(index.GetCallers("sym2", 0)) != 1 {
+		t.Error("lookup indexes were not built")
+	}
+
+	if _, err := ParseIndex([]byte("{not json")); !errors.Is(err, ErrIndexCorrupted) {
+		t.Errorf("ParseIndex(invalid) error = %v, want ErrIndexCorrupted", err)
+	}
+}
+
 // TestStorage_Exists tests existence check.
 func TestStorage_Exists(t *testing.T) {
 	tempDir := t.TempDir()
@@ -310,3 +335,68 @@ func TestStorage_WriteIndex_NilConfig(t *testing.T) {
 		t.Error("WriteIndex should fail with nil config")
 	}
 }
+
+// TestStorage_LoadConfig tests that a written config.yaml loads back.
+func TestStorage_LoadConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := NewStorage(tempDir)
+
+	now := time.Now().Format(time.RFC3339)
+	manifest := &ManifestFile{FormatVersion: FormatVersion, Files: make(map[string]FileEntry)}
+	config := &ProjectConfig{
+		FormatVersion:   FormatVersion,
+		Languages:       []string{"go", "python"},
+		ExcludePatterns: []string{"vendor/**"},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if _, err := storage.WriteIndex(context.Background(), nil, nil, manifest, config); err != nil {
+		t.Fatalf("WriteIndex failed: %v", err)
+	}
+
+	loaded, err := storage.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(loaded.Languages) != 2 || loaded.Languages[1] != "python" || loaded.CreatedAt != now {
+		t.Errorf("LoadConfig = %+v, want %+v", loaded, config)
+	}
+}
+
+// TestStorage_LoadConfig_Invalid tests strict validation of config.yaml.
+func TestStorage_LoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+		wantMsg string
+	}{
+		{"unknown field", "format_version: \"1.0\"\nlangauges: [go]\n", ErrIndexCorrupted, `2:1: unknown field "langauges" (did you mean "languages"?)`},
+		{"wrong type", "format_version: \"1.0\"\nlanguages: go\n", ErrIndexCorrupted, "2:12: languages: expected a list"},
+		{"unsupported language", "format_version: \"1.0\"\nlanguages:\n  - go\n  - cobol\n", ErrIndexCorrupted, `4:5: languages[1]: unsupported language "cobol"`},
+		{"bad glob", "format_version: \"1.0\"\nexclude_patterns: [\"[\"]\n", ErrIndexCorrupted, "2:20: exclude_patterns[0]: invalid glob"},
+		{"bad timestamp", "format_version: \"1.0\"\ncreated_at: yesterday\n", ErrIndexCorrupted, "2:13: created_at: expected an RFC 3339 timestamp"},
+		{"version", "format_version: \"0.9\"\n", ErrVersionMismatch, "expected 1.0, got 0.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := NewStorage(t.TempDir())
+			if err := os.MkdirAll(storage.AleutianPath(), 0755); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(storage.AleutianPath(), ConfigFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := storage.LoadConfig()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadConfig error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
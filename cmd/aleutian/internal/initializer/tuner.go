@@ -0,0 +1,243 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package initializer
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Worker tuning parameters.
+const (
+	// tuneInterval is the minimum length of a measurement window.
+	tuneInterval = 100 * time.Millisecond
+
+	// tuneMinSamples is the minimum number of files in a measurement window.
+	tuneMinSamples = 16
+
+	// tuneTolerance is the relative throughput change treated as noise.
+	tuneTolerance = 0.05
+
+	// ioWaitThreshold is the fraction of per-file time spent reading above
+	// which parsing is considered I/O bound.
+	ioWaitThreshold = 0.5
+
+	// ioWorkerFactor scales GOMAXPROCS to the worker ceiling when parsing is
+	// I/O bound. Workers waiting on the disk do not occupy a CPU.
+	ioWorkerFactor = 2
+)
+
+// workerTuner limits how many files are parsed at once and, when adaptive,
+// adjusts the limit from measured throughput.
+//
+// # Description
+//
+// The tuner is a hill climber. Files are measured in windows of at least
+// tuneInterval and tuneMinSamples files. After each window the limit moves
+// one step: further in the same direction if throughput improved, back if
+// it got worse, and not at all if it stayed within tuneTolerance. The limit
+// stays between 1 and a ceiling of min(MaxWorkers, GOMAXPROCS), raised to
+// min(MaxWorkers, ioWorkerFactor*GOMAXPROCS) while more than ioWaitThreshold of
+// the per-file time is spent reading.
+//
+// # Thread Safety
+//
+// workerTuner is safe for concurrent use.
+type workerTuner struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	adaptive bool
+	cpuMax   int
+	ioMax    int
+	limit    int
+	inFlight int
+	peak     int
+
+	// Hill climbing state
+	dir            int
+	lastThroughput float64
+	adjustments    int
+
+	// Current measurement window
+	windowStart time.Time
+	windowFiles int
+	windowRead  time.Duration
+	windowParse time.Duration
+
+	// Totals over all files
+	totalRead  time.Duration
+	totalParse time.Duration
+}
+
+// tunerStats summarizes a tuner after parsing.
+type tunerStats struct {
+	Workers   WorkerStats
+	ReadTime  time.Duration
+	ParseTime time.Duration
+}
+
+// newWorkerTuner creates a tuner for parsing the given number of files.
+//
+// # Inputs
+//
+//   - workerCap: Maximum workers (Config.MaxWorkers).
+//   - files: Number of files to parse. The limit never exceeds it.
+//   - adaptive: If false, the limit is fixed at min(workerCap, files).
+func newWorkerTuner(workerCap, files int, adaptive bool) *workerTuner {
+	return newWorkerTunerProcs(workerCap, files, runtime.GOMAXPROCS(0), adaptive)
+}
+
+// newWorkerTunerProcs is newWorkerTuner with an explicit GOMAXPROCS.
+func newWorkerTunerProcs(workerCap, files, procs int, adaptive bool) *workerTuner {
+	workerCap = max(1, min(workerCap, files))
+	t := &workerTuner{
+		adaptive:    adaptive,
+		cpuMax:      workerCap,
+		ioMax:       workerCap,
+		limit:       workerCap,
+		dir:         1,
+		windowStart: time.Now(),
+	}
+	if adaptive {
+		t.cpuMax = max(1, min(workerCap, procs))
+		t.ioMax = max(1, min(workerCap, ioWorkerFactor*procs))
+		t.limit = t.cpuMax
+	}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// maxWorkers returns the most workers the tuner will ever allow.
+func (t *workerTuner) maxWorkers() int {
+	return max(t.cpuMax, t.ioMax)
+}
+
+// acquire blocks until a file may be parsed. It returns false if ctx is
+// done first.
+func (t *workerTuner) acquire(ctx context.Context) bool {
+	stop := context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		t.cond.Broadcast()
+		t.mu.Unlock()
+	})
+	defer stop()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.inFlight >= t.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		t.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	t.inFlight++
+	t.peak = max(t.peak, t.inFlight)
+	return true
+}
+
+// release records a parsed file's read and parse time and frees its slot.
+func (t *workerTuner) release(read, parse time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inFlight--
+	t.totalRead += read
+	t.totalParse += parse
+	t.windowFiles++
+	t.windowRead += read
+	t.windowParse += parse
+
+	if t.adaptive && t.windowFiles >= tuneMinSamples {
+		if elapsed := time.Since(t.windowStart); elapsed >= tuneInterval {
+			throughput := float64(t.windowFiles) / elapsed.Seconds()
+			ioWait := 0.0
+			if busy := t.windowRead + t.windowParse; busy > 0 {
+				ioWait = float64(t.windowRead) / float64(busy)
+			}
+			t.step(throughput, ioWait)
+			t.windowStart = time.Now()
+			t.windowFiles = 0
+			t.windowRead = 0
+			t.windowParse = 0
+		}
+	}
+	t.cond.Broadcast()
+}
+
+// step moves the limit after a measurement window. Caller must hold t.mu.
+//
+// # Inputs
+//
+//   - throughput: Files parsed per second in the window.
+//   - ioWait: Fraction of per-file time spent reading, in [0, 1].
+func (t *workerTuner) step(throughput, ioWait float64) {
+	ceiling := t.cpuMax
+	if ioWait > ioWaitThreshold {
+		ceiling = t.ioMax
+	}
+
+	switch {
+	case t.lastThroughput == 0:
+		// First window: probe upwards
+	case throughput > t.lastThroughput*(1+tuneTolerance):
+		// The last step helped: keep going
+	case throughput < t.lastThroughput*(1-tuneTolerance):
+		t.dir = -t.dir
+	default:
+		t.lastThroughput = throughput
+		t.setLimit(min(t.limit, ceiling))
+		return
+	}
+	t.lastThroughput = throughput
+
+	next := t.limit + t.dir
+	if next > ceiling {
+		// Cannot go higher: probe whether fewer workers do as well
+		t.dir = -1
+		next = t.limit - 1
+	}
+	if next < 1 {
+		t.dir = 1
+		next = 1
+	}
+	t.setLimit(min(next, ceiling))
+}
+
+// setLimit changes the limit, counting the change. Caller must hold t.mu.
+func (t *workerTuner) setLimit(limit int) {
+	if limit != t.limit {
+		t.limit = limit
+		t.adjustments++
+	}
+}
+
+// stats returns the tuner's summary.
+func (t *workerTuner) stats() tunerStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return tunerStats{
+		Workers: WorkerStats{
+			Final:       t.limit,
+			Peak:        t.peak,
+			Max:         t.maxWorkers(),
+			Adaptive:    t.adaptive,
+			Adjustments: t.adjustments,
+		},
+		ReadTime:  t.totalRead,
+		ParseTime: t.totalParse,
+	}
+}
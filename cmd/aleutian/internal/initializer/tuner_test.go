@@ -0,0 +1,168 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWorkerTuner_Bounds tests the worker ceilings.
+func TestWorkerTuner_Bounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		workerCap int
+		files     int
+		procs     int
+		adaptive  bool
+		wantLimit int
+		wantMax   int
+	}{
+		{"fixed uses the cap", 8, 100, 2, false, 8, 8},
+		{"fixed never exceeds files", 8, 3, 2, false, 3, 3},
+		{"adaptive starts at GOMAXPROCS", 8, 100, 2, true, 2, 4},
+		{"adaptive respects the cap", 3, 100, 4, true, 3, 3},
+		{"adaptive never exceeds files", 8, 1, 4, true, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tuner := newWorkerTunerProcs(tt.workerCap, tt.files, tt.procs, tt.adaptive)
+			if tuner.limit != tt.wantLimit || tuner.maxWorkers() != tt.wantMax {
+				t.Errorf("limit = %d, max = %d; want %d, %d",
+					tuner.limit, tuner.maxWorkers(), tt.wantLimit, tt.wantMax)
+			}
+		})
+	}
+}
+
+// TestWorkerTuner_Step tests the hill climbing decisions.
+func TestWorkerTuner_Step(t *testing.T) {
+	t.Run("CPU bound stays at or below GOMAXPROCS", func(t *testing.T) {
+		tuner := newWorkerTunerProcs(16, 1000, 4, true)
+		tuner.step(100, 0.1)
+		if tuner.limit != 3 {
+			t.Fatalf("limit = %d, want 3 (probe down from the ceiling)", tuner.limit)
+		}
+		tuner.step(70, 0.1)
+		if tuner.limit != 4 {
+			t.Errorf("limit = %d, want 4 after throughput dropped", tuner.limit)
+		}
+	})
+
+	t.Run("I/O bound grows past GOMAXPROCS", func(t *testing.T) {
+		tuner := newWorkerTunerProcs(16, 1000, 4, true)
+		throughput := 100.0
+		for i := 0; i < 4; i++ {
+			tuner.step(throughput, 0.8)
+			throughput *= 1.2
+		}
+		if tuner.limit != 8 {
+			t.Errorf("limit = %d, want the I/O ceiling of 8", tuner.limit)
+		}
+
+		// Reads got fast: fall back under the CPU ceiling
+		tuner.step(throughput, 0.1)
+		if tuner.limit > 4 {
+			t.Errorf("limit = %d, want <= 4 once CPU bound", tuner.limit)
+		}
+	})
+
+	t.Run("flat throughput holds", func(t *testing.T) {
+		tuner := newWorkerTunerProcs(16, 1000, 4, true)
+		tuner.step(100, 0.8)
+		tuner.step(101, 0.8)
+		tuner.step(99, 0.8)
+		if tuner.limit != 5 || tuner.adjustments != 1 {
+			t.Errorf("limit = %d after %d adjustments, want 5 after 1", tuner.limit, tuner.adjustments)
+		}
+	})
+
+	t.Run("never below one", func(t *testing.T) {
+		tuner := newWorkerTunerProcs(1, 1000, 4, true)
+		tuner.step(100, 0.1)
+		tuner.step(50, 0.1)
+		if tuner.limit != 1 {
+			t.Errorf("limit = %d, want 1", tuner.limit)
+		}
+	})
+}
+
+// TestWorkerTuner_Acquire tests that acquire enforces the limit.
+func TestWorkerTuner_Acquire(t *testing.T) {
+	tuner := newWorkerTunerProcs(2, 10, 4, false)
+	ctx := context.Background()
+
+	if !tuner.acquire(ctx) || !tuner.acquire(ctx) {
+		t.Fatal("acquire failed below the limit")
+	}
+
+	acquired := make(chan bool)
+	go func() { acquired <- tuner.acquire(ctx) }()
+	select {
+	case <-acquired:
+		t.Fatal("acquire did not block at the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tuner.release(time.Millisecond, 2*time.Millisecond)
+	if !<-acquired {
+		t.Fatal("acquire failed after release")
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	go func() { acquired <- tuner.acquire(cancelCtx) }()
+	cancel()
+	if <-acquired {
+		t.Error("acquire succeeded after cancellation")
+	}
+
+	stats := tuner.stats()
+	if stats.Workers.Peak != 2 || stats.ReadTime != time.Millisecond || stats.ParseTime != 2*time.Millisecond {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+// TestInitializer_Init_ReportsWorkersAndTimings tests the concurrency and
+// stage timings in the result.
+func TestInitializer_Init_ReportsWorkersAndTimings(t *testing.T) {
+	tempDir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		content := fmt.Sprintf("package main\n\nfunc f%d() {}\n", i)
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("f%d.go", i)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	cfg := DefaultConfig(tempDir)
+	cfg.MaxWorkers = 4
+	result, err := NewInitializer(NewStorage(tempDir)).Init(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	workers := result.Workers
+	if !workers.Adaptive {
+		t.Error("DefaultConfig should tune workers")
+	}
+	if workers.Max < 1 || workers.Max > 4 {
+		t.Errorf("Workers.Max = %d, want 1..4", workers.Max)
+	}
+	if workers.Final < 1 || workers.Final > workers.Max || workers.Peak > workers.Max {
+		t.Errorf("Workers = %+v, want final and peak within max", workers)
+	}
+	if result.Timings.ParseMs > result.DurationMs || result.Timings.WriteMs > result.DurationMs {
+		t.Errorf("Timings = %+v exceed DurationMs %d", result.Timings, result.DurationMs)
+	}
+}
@@ -12,6 +12,8 @@ package initializer
 
 import (
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
 )
 
 // FormatVersion is the current index format version.
@@ -44,6 +46,8 @@ const (
 //   - Languages: Languages to parse. Empty means auto-detect.
 //   - ExcludePatterns: Glob patterns to exclude.
 //   - MaxWorkers: Maximum parallel workers. Must be > 0.
+//   - AdaptiveWorkers: If true, tune the worker count between 1 and
+//     MaxWorkers from measured parse throughput and read time.
 //   - MaxFileSize: Maximum file size in bytes. Files larger are skipped.
 //   - FileTimeout: Per-file parse timeout. Zero means no timeout.
 //   - Force: If true, rebuild index even if exists.
@@ -55,6 +59,7 @@ type Config struct {
 	Languages       []string
 	ExcludePatterns []string
 	MaxWorkers      int
+	AdaptiveWorkers bool
 	MaxFileSize     int64
 	FileTimeout     time.Duration
 	Force           bool
@@ -70,6 +75,7 @@ func DefaultConfig(projectRoot string) Config {
 		Languages:       nil, // auto-detect
 		ExcludePatterns: []string{"vendor/**", "node_modules/**", ".git/**", "*.min.js"},
 		MaxWorkers:      DefaultMaxWorkers,
+		AdaptiveWorkers: true,
 		MaxFileSize:     DefaultMaxFileSize,
 		FileTimeout:     DefaultFileTimeout,
 		Force:           false,
@@ -107,18 +113,50 @@ func (c Config) Validate() error {
 //   - Warnings: Non-fatal issues encountered.
 //   - Incremental: True if this was an incremental update.
 //   - FilesChanged: Number of files changed (for incremental).
+//   - Workers: Parse concurrency chosen by the worker tuner.
+//   - Timings: Time spent in each stage.
 type Result struct {
-	APIVersion   string   `json:"api_version"`
-	ProjectRoot  string   `json:"project_root"`
-	Languages    []string `json:"languages"`
-	FilesIndexed int      `json:"files_indexed"`
-	SymbolsFound int      `json:"symbols_found"`
-	EdgesBuilt   int      `json:"edges_built"`
-	DurationMs   int64    `json:"duration_ms"`
-	IndexPath    string   `json:"index_path"`
-	Warnings     []string `json:"warnings,omitempty"`
-	Incremental  bool     `json:"incremental"`
-	FilesChanged int      `json:"files_changed,omitempty"`
+	APIVersion   string       `json:"api_version"`
+	ProjectRoot  string       `json:"project_root"`
+	Languages    []string     `json:"languages"`
+	FilesIndexed int          `json:"files_indexed"`
+	SymbolsFound int          `json:"symbols_found"`
+	EdgesBuilt   int          `json:"edges_built"`
+	DurationMs   int64        `json:"duration_ms"`
+	IndexPath    string       `json:"index_path"`
+	Warnings     []string     `json:"warnings,omitempty"`
+	Incremental  bool         `json:"incremental"`
+	FilesChanged int          `json:"files_changed,omitempty"`
+	Workers      WorkerStats  `json:"workers"`
+	Timings      StageTimings `json:"timings"`
+}
+
+// WorkerStats describes the parse worker concurrency.
+//
+// # Fields
+//
+//   - Final: Concurrency limit when parsing finished.
+//   - Peak: Most files parsed at once.
+//   - Max: Upper bound, min(MaxWorkers, files).
+//   - Adaptive: True if the limit was tuned during parsing.
+//   - Adjustments: Number of times the limit changed.
+type WorkerStats struct {
+	Final       int  `json:"final"`
+	Peak        int  `json:"peak"`
+	Max         int  `json:"max"`
+	Adaptive    bool `json:"adaptive"`
+	Adjustments int  `json:"adjustments"`
+}
+
+// StageTimings holds wall-clock time per initialization stage, plus the
+// read and parse time summed over all parse workers.
+type StageTimings struct {
+	DetectMs    int64 `json:"detect_ms"`
+	ScanMs      int64 `json:"scan_ms"`
+	ParseMs     int64 `json:"parse_ms"`
+	WriteMs     int64 `json:"write_ms"`
+	FileReadMs  int64 `json:"file_read_ms"`
+	FileParseMs int64 `json:"file_parse_ms"`
 }
 
 // NewResult creates a new Result with the API version set.
@@ -131,13 +169,11 @@ func NewResult() *Result {
 }
 
 // ProjectConfig holds project-specific settings stored in config.yaml.
-type ProjectConfig struct {
-	FormatVersion   string   `yaml:"format_version" json:"format_version"`
-	Languages       []string `yaml:"languages" json:"languages"`
-	ExcludePatterns []string `yaml:"exclude_patterns" json:"exclude_patterns"`
-	CreatedAt       string   `yaml:"created_at" json:"created_at"`
-	UpdatedAt       string   `yaml:"updated_at" json:"updated_at"`
-}
+//
+// The schema is shared with the trace service, which reads the same file to
+// pick up the project's languages, exclude patterns, lint profile and tool
+// policy.
+type ProjectConfig = projectconfig.Config
 
 // ManifestFile holds the file manifest with checksums.
 type ManifestFile struct {
@@ -167,10 +203,12 @@ type ParseJob struct {
 
 // ParseResult holds the result of parsing a single file.
 type ParseResult struct {
-	FilePath string
-	Symbols  []Symbol
-	Edges    []Edge
-	Error    error
+	FilePath  string
+	Symbols   []Symbol
+	Edges     []Edge
+	Error     error
+	ReadTime  time.Duration
+	ParseTime time.Duration
 }
 
 // Symbol represents a code symbol (function, type, etc.).
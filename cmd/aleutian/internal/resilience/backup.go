@@ -14,10 +14,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 // BackupManager defines the interface for backup operations.
@@ -60,6 +63,10 @@ type BackupInfo struct {
 
 	// IsDir indicates if this is a directory backup.
 	IsDir bool
+
+	// Digest identifies the content in the blob store for backups kept
+	// there, otherwise empty.
+	Digest cas.Digest
 }
 
 // BackupConfig configures backup behavior.
@@ -90,6 +97,12 @@ type BackupConfig struct {
 
 	// BackupDir overrides backup location (if empty, backup is alongside original).
 	BackupDir string
+
+	// Store keeps file backups in a content-addressable blob store, so
+	// repeated backups of an unchanged file take no extra space. The
+	// backup path then names the backup but no file is written there.
+	// Directory backups are unaffected. If nil, backups are plain copies.
+	Store *cas.Store
 }
 
 // DefaultBackupConfig returns sensible defaults.
@@ -231,11 +244,16 @@ func (m *DefaultBackupManager) BackupBeforeOverwrite(path string) (string, error
 	backupPath := m.generateBackupPath(path)
 
 	// Create backup
-	if info.IsDir() {
+	switch {
+	case info.IsDir():
 		if err := m.backupDirectory(path, backupPath); err != nil {
 			return "", err
 		}
-	} else {
+	case m.config.Store != nil:
+		if err := m.storeFile(path, backupPath); err != nil {
+			return "", err
+		}
+	default:
 		if err := m.backupFile(path, backupPath); err != nil {
 			return "", err
 		}
@@ -299,14 +317,7 @@ func (m *DefaultBackupManager) ListBackups(originalPath string) ([]BackupInfo, e
 			continue
 		}
 
-		// Parse timestamp from name
-		timestampStr := strings.TrimPrefix(name, prefix)
-		createdAt, err := time.Parse(m.config.TimeFormat, timestampStr)
-		if err != nil {
-			// Try with directory marker stripped
-			timestampStr = strings.TrimSuffix(timestampStr, ".dir")
-			createdAt, _ = time.Parse(m.config.TimeFormat, timestampStr)
-		}
+		createdAt := m.parseBackupTime(strings.TrimPrefix(name, prefix))
 
 		size := info.Size()
 		if info.IsDir() {
@@ -322,6 +333,12 @@ func (m *DefaultBackupManager) ListBackups(originalPath string) ([]BackupInfo, e
 		})
 	}
 
+	stored, err := m.listStoredBackups(originalPath, dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+	backups = append(backups, stored...)
+
 	// Sort by creation time (newest first)
 	sort.Slice(backups, func(i, j int) bool {
 		return backups[i].CreatedAt.After(backups[j].CreatedAt)
@@ -357,6 +374,13 @@ func (m *DefaultBackupManager) RestoreBackup(backupPath string) error {
 		return fmt.Errorf("cannot determine original path from backup: %s", backupPath)
 	}
 
+	if m.config.Store != nil {
+		ref := backupRef(backupPath)
+		if _, err := m.config.Store.Resolve(ref); err == nil {
+			return m.restoreStoredFile(ref, originalPath)
+		}
+	}
+
 	// Remove current file/directory if exists
 	if err := os.RemoveAll(originalPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove current %s: %w", originalPath, err)
@@ -405,7 +429,7 @@ func (m *DefaultBackupManager) CleanOldBackups(originalPath string, maxAge time.
 
 	for _, backup := range backups {
 		if backup.CreatedAt.Before(cutoff) {
-			if err := os.RemoveAll(backup.Path); err != nil {
+			if err := m.removeBackup(backup); err != nil {
 				// Continue trying to remove others
 				continue
 			}
@@ -468,6 +492,103 @@ func (m *DefaultBackupManager) backupFile(src, dst string) error {
 	return nil
 }
 
+// storeFile backs up a file into the blob store under the reference for
+// backupPath.
+func (m *DefaultBackupManager) storeFile(src, backupPath string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer srcFile.Close()
+
+	if _, err := m.config.Store.Save(backupRef(backupPath), srcFile); err != nil {
+		return fmt.Errorf("failed to store backup: %w", err)
+	}
+	return nil
+}
+
+// restoreStoredFile writes a stored backup to originalPath and releases it.
+func (m *DefaultBackupManager) restoreStoredFile(ref, originalPath string) error {
+	content, err := m.config.Store.GetRef(ref)
+	if err != nil {
+		return fmt.Errorf("failed to read stored backup: %w", err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(originalPath); err == nil && !info.IsDir() {
+		mode = info.Mode().Perm()
+	}
+	if err := os.RemoveAll(originalPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove current %s: %w", originalPath, err)
+	}
+
+	tempPath := originalPath + ".restore.tmp"
+	if err := os.WriteFile(tempPath, content, mode); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	if err := os.Rename(tempPath, originalPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	// Restoring consumes the backup, as it does for plain copies
+	return m.config.Store.Unlink(ref)
+}
+
+// listStoredBackups returns the blob store backups named like files in dir
+// starting with prefix.
+func (m *DefaultBackupManager) listStoredBackups(originalPath, dir, prefix string) ([]BackupInfo, error) {
+	if m.config.Store == nil {
+		return nil, nil
+	}
+
+	refs, err := m.config.Store.Refs(backupRef(filepath.Join(dir, prefix)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored backups: %w", err)
+	}
+
+	backups := make([]BackupInfo, 0, len(refs))
+	for _, ref := range refs {
+		name := path.Base(ref.Name)
+		size, err := m.config.Store.Size(ref.Digest)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupInfo{
+			Path:         filepath.Join(dir, name),
+			OriginalPath: originalPath,
+			CreatedAt:    m.parseBackupTime(strings.TrimPrefix(name, prefix)),
+			Size:         size,
+			Digest:       ref.Digest,
+		})
+	}
+	return backups, nil
+}
+
+// removeBackup deletes a backup, releasing its content if it is stored.
+func (m *DefaultBackupManager) removeBackup(backup BackupInfo) error {
+	if backup.Digest != "" && m.config.Store != nil {
+		return m.config.Store.Unlink(backupRef(backup.Path))
+	}
+	return os.RemoveAll(backup.Path)
+}
+
+// parseBackupTime parses the timestamp at the end of a backup name.
+func (m *DefaultBackupManager) parseBackupTime(timestampStr string) time.Time {
+	createdAt, err := time.Parse(m.config.TimeFormat, timestampStr)
+	if err != nil {
+		// Try with directory marker stripped
+		timestampStr = strings.TrimSuffix(timestampStr, ".dir")
+		createdAt, _ = time.Parse(m.config.TimeFormat, timestampStr)
+	}
+	return createdAt
+}
+
+// backupRef returns the blob store reference for a backup path.
+func backupRef(backupPath string) string {
+	return cas.RefName("backups", backupPath)
+}
+
 // backupDirectory creates a backup of a directory.
 func (m *DefaultBackupManager) backupDirectory(src, dst string) error {
 	// Rename is atomic and efficient for directories
@@ -487,7 +608,7 @@ func (m *DefaultBackupManager) rotateBackups(originalPath string) error {
 
 	// Remove oldest backups (list is sorted newest first)
 	for i := m.config.MaxBackups; i < len(backups); i++ {
-		os.RemoveAll(backups[i].Path)
+		m.removeBackup(backups[i])
 	}
 
 	return nil
@@ -17,6 +17,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 // =============================================================================
@@ -385,6 +387,85 @@ func TestBackupManager_BackupRotation(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Blob Store Tests
+// =============================================================================
+
+func TestBackupManager_Store(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	store, err := cas.Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("cas.Open failed: %v", err)
+	}
+	mgr := NewBackupManager(BackupConfig{
+		MaxBackups:   2,
+		BackupSuffix: ".backup",
+		TimeFormat:   "2006-01-02_150405.000",
+		Store:        store,
+	})
+
+	// Back up the unchanged file several times
+	var backupPath string
+	for i := 0; i < 3; i++ {
+		backupPath, err = mgr.BackupBeforeOverwrite(testFile)
+		if err != nil {
+			t.Fatalf("BackupBeforeOverwrite failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("Backups should not be written beside the original, found %d entries", len(entries))
+	}
+
+	backups, err := mgr.ListBackups(testFile)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Should have 2 backups after rotation, got %d", len(backups))
+	}
+	if backups[0].Digest == "" || backups[0].Size != int64(len("original")) {
+		t.Errorf("Stored backup info = %+v", backups[0])
+	}
+
+	report, err := store.Report()
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if report.Objects != 1 || report.Refs != 2 {
+		t.Errorf("Store has %d objects and %d refs, want 1 and 2", report.Objects, report.Refs)
+	}
+
+	// Restore the newest backup
+	if err := os.WriteFile(testFile, []byte("modified"), 0600); err != nil {
+		t.Fatalf("Failed to modify test file: %v", err)
+	}
+	if err := mgr.RestoreBackup(backupPath); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	content, _ := os.ReadFile(testFile)
+	if string(content) != "original" {
+		t.Errorf("Restored content = %q, want %q", content, "original")
+	}
+
+	// The remaining backup is released by cleanup
+	removed, err := mgr.CleanOldBackups(testFile, 0)
+	if err != nil || removed != 1 {
+		t.Errorf("CleanOldBackups = %d, %v; want 1", removed, err)
+	}
+	report, _ = store.Report()
+	if report.Objects != 0 || report.Refs != 0 {
+		t.Errorf("Store has %d objects and %d refs after cleanup, want none", report.Objects, report.Refs)
+	}
+}
+
 // =============================================================================
 // Backup Path Format Tests
 // =============================================================================
@@ -47,6 +47,9 @@
 //	// If something goes wrong:
 //	mgr.RestoreBackup(backupPath)
 //
+// Setting BackupConfig.Store keeps file backups in a pkg/cas blob store,
+// so repeated backups of an unchanged file are stored once.
+//
 // # Thread Safety
 //
 // All types in this package are safe for concurrent use.
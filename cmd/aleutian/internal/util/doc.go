@@ -16,7 +16,7 @@
 //
 // # Overview
 //
-// The util package provides seven categories of utilities:
+// The util package provides eight categories of utilities:
 //
 //   - Timeout Management: Enforce minimum and default timeouts to prevent hangs
 //   - Environment Variables: Type-safe environment variable handling with validation
@@ -25,6 +25,7 @@
 //   - Progress Indicators: CLI spinners for long-running operations
 //   - Saga Pattern: Multi-step transactions with automatic rollback
 //   - Goroutine Safety: Panic recovery for background goroutines
+//   - Worker Pool: Bounded, dynamically sized pool built on SafeGo
 //
 // # Thread Safety
 //
@@ -33,6 +34,7 @@
 // Specifically:
 //
 //   - [RingBuffer] is fully thread-safe (protected by mutex)
+//   - [Pool] is fully thread-safe
 //   - [Spinner] is thread-safe for Start/Stop/SetMessage
 //   - [Saga] is NOT thread-safe (use from single goroutine)
 //   - [EnvVars] is NOT thread-safe (do not modify concurrently)
@@ -90,6 +92,12 @@
 //	    log.Printf("Panic recovered: %v\n%s", r.PanicValue, r.Stack)
 //	})
 //
+// Worker pool:
+//
+//	pool := util.NewPool(util.PoolConfig{MaxWorkers: 8})
+//	_ = pool.Submit(ctx, func() { parse(file) })
+//	err := pool.Shutdown(ctx) // drains the queue
+//
 // # File Mapping
 //
 // This package was extracted from cmd/aleutian/ as part of the codebase
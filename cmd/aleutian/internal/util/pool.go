@@ -0,0 +1,347 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrPoolClosed is returned when submitting to a pool that is shutting down.
+var ErrPoolClosed = errors.New("worker pool is closed")
+
+// =============================================================================
+// Configuration
+// =============================================================================
+
+// PoolConfig configures a Pool.
+//
+// # Description
+//
+// The pool keeps MinWorkers goroutines alive and starts more, up to
+// MaxWorkers, when every worker is busy. Workers above MinWorkers exit
+// after IdleTimeout without work. Zero values select the defaults.
+type PoolConfig struct {
+	// MinWorkers is the number of workers kept alive when idle. Default: 1.
+	MinWorkers int
+
+	// MaxWorkers bounds the number of concurrent workers.
+	// Default: runtime.NumCPU().
+	MaxWorkers int
+
+	// QueueSize is the number of tasks that can wait for a worker before
+	// Submit blocks. Default: 4 * MaxWorkers.
+	QueueSize int
+
+	// IdleTimeout is how long a worker above MinWorkers waits for work
+	// before exiting. Default: 5 seconds.
+	IdleTimeout time.Duration
+
+	// OnPanic is called when a task panics (may be nil). The worker that
+	// ran the task is replaced.
+	OnPanic func(SafeGoResult)
+}
+
+// withDefaults returns the config with zero values replaced by defaults.
+func (c PoolConfig) withDefaults() PoolConfig {
+	if c.MaxWorkers <= 0 {
+		c.MaxWorkers = runtime.NumCPU()
+	}
+	if c.MinWorkers <= 0 {
+		c.MinWorkers = 1
+	}
+	if c.MinWorkers > c.MaxWorkers {
+		c.MinWorkers = c.MaxWorkers
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 4 * c.MaxWorkers
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = 5 * time.Second
+	}
+	return c
+}
+
+// PoolStats is a snapshot of a pool's queue and worker counters.
+type PoolStats struct {
+	// Workers is the number of live workers.
+	Workers int
+
+	// Active is the number of workers running a task.
+	Active int
+
+	// Queued is the number of tasks waiting for a worker.
+	Queued int
+
+	// Submitted is the number of tasks accepted by Submit.
+	Submitted int64
+
+	// Completed is the number of tasks that returned normally.
+	Completed int64
+
+	// Panicked is the number of tasks that panicked.
+	Panicked int64
+
+	// Dropped is the number of queued tasks discarded by a Shutdown
+	// whose context expired.
+	Dropped int64
+}
+
+// =============================================================================
+// Pool
+// =============================================================================
+
+// Pool is a bounded, dynamically sized goroutine pool.
+//
+// # Description
+//
+// Tasks submitted to the pool wait in a bounded queue and run on worker
+// goroutines started with SafeGo, so a panicking task is reported to
+// PoolConfig.OnPanic instead of crashing the process. The pool grows
+// from MinWorkers toward MaxWorkers while all workers are busy and
+// shrinks back when workers sit idle.
+//
+// # Thread Safety
+//
+// All methods are safe for concurrent use.
+//
+// # Example
+//
+//	pool := util.NewPool(util.PoolConfig{MaxWorkers: 8})
+//	for _, f := range files {
+//	    f := f
+//	    if err := pool.Submit(ctx, func() { process(f) }); err != nil {
+//	        break
+//	    }
+//	}
+//	if err := pool.Shutdown(ctx); err != nil {
+//	    log.Printf("pool did not drain: %v", err)
+//	}
+//
+// # Limitations
+//
+//   - Tasks cannot be cancelled once started; they should watch their
+//     own context
+//   - Task results must be returned through channels or shared state
+type Pool struct {
+	cfg   PoolConfig
+	queue chan func()
+
+	mu      sync.RWMutex // write-held while closing the queue
+	closing chan struct{}
+	once    sync.Once
+
+	workerMu sync.Mutex
+	workers  int
+	wg       sync.WaitGroup
+
+	active    atomic.Int64
+	submitted atomic.Int64
+	completed atomic.Int64
+	panicked  atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewPool creates a pool and starts its minimum workers.
+//
+// # Inputs
+//
+//   - cfg: Pool configuration. Zero values select defaults.
+//
+// # Outputs
+//
+//   - *Pool: The running pool. Call Shutdown to stop it.
+func NewPool(cfg PoolConfig) *Pool {
+	cfg = cfg.withDefaults()
+	p := &Pool{
+		cfg:     cfg,
+		queue:   make(chan func(), cfg.QueueSize),
+		closing: make(chan struct{}),
+	}
+	p.workerMu.Lock()
+	for p.workers < cfg.MinWorkers {
+		p.spawnLocked()
+	}
+	p.workerMu.Unlock()
+	return p
+}
+
+// Submit queues a task, blocking while the queue is full.
+//
+// # Inputs
+//
+//   - ctx: Bounds how long Submit waits for queue space.
+//   - task: The function to run. Must not be nil.
+//
+// # Outputs
+//
+//   - error: ctx.Err() if ctx ended first, ErrPoolClosed if the pool is
+//     shutting down, nil once the task is queued.
+func (p *Pool) Submit(ctx context.Context, task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	select {
+	case <-p.closing:
+		return ErrPoolClosed
+	default:
+	}
+
+	select {
+	case p.queue <- task:
+	case <-p.closing:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.submitted.Add(1)
+	p.grow()
+	return nil
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (p *Pool) Stats() PoolStats {
+	p.workerMu.Lock()
+	workers := p.workers
+	p.workerMu.Unlock()
+
+	return PoolStats{
+		Workers:   workers,
+		Active:    int(p.active.Load()),
+		Queued:    len(p.queue),
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Panicked:  p.panicked.Load(),
+		Dropped:   p.dropped.Load(),
+	}
+}
+
+// Shutdown stops accepting tasks and waits for queued tasks to finish.
+//
+// # Description
+//
+// New Submit calls fail with ErrPoolClosed. Shutdown returns once every
+// queued task has run and all workers have exited. If ctx ends first,
+// tasks still in the queue are dropped, running tasks are left to
+// finish in the background, and ctx.Err() is returned.
+//
+// # Inputs
+//
+//   - ctx: Bounds how long to wait for the queue to drain.
+//
+// # Outputs
+//
+//   - error: nil if the pool drained, otherwise ctx.Err().
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.closing)
+		p.mu.Lock() // wait for in-flight Submit calls to give up
+		close(p.queue)
+		p.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for range p.queue {
+			p.dropped.Add(1)
+		}
+		return ctx.Err()
+	}
+}
+
+// grow starts a worker if tasks are queued, all workers are busy and
+// the pool is below MaxWorkers. It is called after a task is queued and
+// whenever a worker starts a task.
+func (p *Pool) grow() {
+	p.workerMu.Lock()
+	defer p.workerMu.Unlock()
+	if p.workers < p.cfg.MaxWorkers && len(p.queue) > 0 && int(p.active.Load()) >= p.workers {
+		p.spawnLocked()
+	}
+}
+
+// spawnLocked starts a worker. Caller holds workerMu.
+func (p *Pool) spawnLocked() {
+	p.workers++
+	p.wg.Add(1)
+	SafeGo(p.work, p.recovered)
+}
+
+// work runs tasks until the queue closes or the worker is idle too long.
+func (p *Pool) work() {
+	idle := time.NewTimer(p.cfg.IdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case task, ok := <-p.queue:
+			if !ok {
+				p.exit()
+				return
+			}
+			p.active.Add(1)
+			p.grow()
+			task() // a panic unwinds to SafeGo, which calls recovered
+			p.active.Add(-1)
+			p.completed.Add(1)
+			idle.Reset(p.cfg.IdleTimeout)
+
+		case <-idle.C:
+			p.workerMu.Lock()
+			if p.workers > p.cfg.MinWorkers {
+				p.workers--
+				p.workerMu.Unlock()
+				p.wg.Done()
+				return
+			}
+			p.workerMu.Unlock()
+			idle.Reset(p.cfg.IdleTimeout)
+		}
+	}
+}
+
+// exit records a worker leaving after the queue closed.
+func (p *Pool) exit() {
+	p.workerMu.Lock()
+	p.workers--
+	p.workerMu.Unlock()
+	p.wg.Done()
+}
+
+// recovered replaces a worker whose task panicked.
+func (p *Pool) recovered(r SafeGoResult) {
+	p.active.Add(-1)
+	p.panicked.Add(1)
+	if p.cfg.OnPanic != nil {
+		p.cfg.OnPanic(r)
+	}
+
+	// Start the replacement before releasing the dead worker so the
+	// WaitGroup cannot reach zero while tasks remain queued. If the queue
+	// is closed and empty, the replacement exits immediately.
+	p.workerMu.Lock()
+	p.workers--
+	p.spawnLocked()
+	p.workerMu.Unlock()
+	p.wg.Done()
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Pool Tests
+// =============================================================================
+
+// TestPool_RunsAllTasksWithinBound verifies every task runs and no more
+// than MaxWorkers run at once.
+func TestPool_RunsAllTasksWithinBound(t *testing.T) {
+	pool := NewPool(PoolConfig{MaxWorkers: 3, QueueSize: 2})
+	ctx := context.Background()
+
+	var ran, running, peak atomic.Int64
+	for i := 0; i < 30; i++ {
+		err := pool.Submit(ctx, func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			running.Add(-1)
+			ran.Add(1)
+		})
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if ran.Load() != 30 {
+		t.Errorf("ran %d tasks, want 30", ran.Load())
+	}
+	if peak.Load() > 3 {
+		t.Errorf("peak concurrency %d exceeds MaxWorkers 3", peak.Load())
+	}
+	stats := pool.Stats()
+	if stats.Submitted != 30 || stats.Completed != 30 || stats.Workers != 0 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+// TestPool_PanicRecovery verifies a panicking task is reported and the
+// pool keeps running later tasks.
+func TestPool_PanicRecovery(t *testing.T) {
+	var reported atomic.Value
+	pool := NewPool(PoolConfig{MaxWorkers: 1, OnPanic: func(r SafeGoResult) {
+		reported.Store(r.PanicValue)
+	}})
+	ctx := context.Background()
+
+	var ran atomic.Bool
+	if err := pool.Submit(ctx, func() { panic("boom") }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if err := pool.Submit(ctx, func() { ran.Store(true) }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if reported.Load() != "boom" {
+		t.Errorf("OnPanic value = %v, want boom", reported.Load())
+	}
+	if !ran.Load() {
+		t.Error("task after the panic did not run")
+	}
+	if s := pool.Stats(); s.Panicked != 1 || s.Completed != 1 {
+		t.Errorf("Stats = %+v, want 1 panicked and 1 completed", s)
+	}
+}
+
+// TestPool_Shutdown verifies Submit fails after shutdown and an expired
+// shutdown context drops queued tasks.
+func TestPool_Shutdown(t *testing.T) {
+	pool := NewPool(PoolConfig{MaxWorkers: 1, QueueSize: 10})
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	_ = pool.Submit(ctx, func() { <-release })
+	for i := 0; i < 5; i++ {
+		_ = pool.Submit(ctx, func() {})
+	}
+
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	if err := pool.Submit(ctx, func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Shutdown = %v, want ErrPoolClosed", err)
+	}
+
+	close(release)
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Errorf("second Shutdown = %v", err)
+	}
+	if s := pool.Stats(); s.Dropped != 5 {
+		t.Errorf("Dropped = %d, want 5", s.Dropped)
+	}
+}
+
+// TestPool_ShrinksWhenIdle verifies workers above MinWorkers exit after
+// IdleTimeout.
+func TestPool_ShrinksWhenIdle(t *testing.T) {
+	pool := NewPool(PoolConfig{MinWorkers: 1, MaxWorkers: 4, IdleTimeout: 10 * time.Millisecond})
+	ctx := context.Background()
+	defer pool.Shutdown(ctx)
+
+	release := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		_ = pool.Submit(ctx, func() { <-release })
+	}
+	deadline := time.Now().Add(time.Second)
+	for pool.Stats().Active < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if w := pool.Stats().Workers; w != 4 {
+		t.Fatalf("Workers = %d under load, want 4", w)
+	}
+
+	close(release)
+	for pool.Stats().Workers > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w := pool.Stats().Workers; w != 1 {
+		t.Errorf("Workers = %d after idling, want MinWorkers 1", w)
+	}
+}
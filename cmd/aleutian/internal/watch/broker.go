@@ -0,0 +1,137 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Broker fans events out to subscribers.
+//
+// # Description
+//
+// Publish never blocks: if a subscriber's buffer is full the event is
+// dropped for that subscriber only, so a stalled editor cannot stall the
+// daemon. The broker also remembers the latest event of each kind so new
+// subscribers can catch up immediately.
+//
+// # Thread Safety
+//
+// Broker is safe for concurrent use.
+type Broker struct {
+	mu      sync.RWMutex
+	subs    map[uint64]chan Event
+	nextSub uint64
+	nextID  uint64
+	latest  map[string]Event
+	buffer  int
+	dropped uint64
+}
+
+// NewBroker creates a broker whose subscriber channels hold buffer events.
+//
+// # Inputs
+//
+//   - buffer: Per-subscriber channel capacity. Values <= 0 use
+//     DefaultSubscriberBuffer.
+func NewBroker(buffer int) *Broker {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+	return &Broker{
+		subs:   make(map[uint64]chan Event),
+		latest: make(map[string]Event),
+		buffer: buffer,
+	}
+}
+
+// Subscribe registers a new subscriber.
+//
+// # Outputs
+//
+//   - <-chan Event: Receives events published after the call.
+//   - func(): Unsubscribes and closes the channel. Safe to call more than once.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextSub
+	b.nextSub++
+	ch := make(chan Event, b.buffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Publish assigns the event an ID and timestamp and delivers it to all
+// subscribers.
+//
+// # Outputs
+//
+//   - Event: The event as delivered, with ID and Time set.
+func (b *Broker) Publish(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e.ID = b.nextID
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.latest[e.key()] = e
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			b.dropped++
+		}
+	}
+	return e
+}
+
+// Latest returns the most recent event of each kind, oldest first.
+func (b *Broker) Latest() []Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	events := make([]Event, 0, len(b.latest))
+	for _, e := range b.latest {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events
+}
+
+// SubscriberCount returns the number of active subscribers.
+func (b *Broker) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Dropped returns the total number of events dropped for slow subscribers.
+func (b *Broker) Dropped() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.dropped
+}
@@ -0,0 +1,306 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+	"github.com/AleutianAI/AleutianFOSS/pkg/watcher"
+)
+
+// Daemon watches a project and re-runs pipelines on change.
+//
+// # Description
+//
+// Run builds the index once, then waits for file changes. Changes are
+// batched until the project has been quiet for cfg.Debounce, after which
+// the index is rebuilt and each pipeline runs against the changed files.
+// Every step is published to the broker.
+//
+// # Thread Safety
+//
+// Run must be called at most once. Index is safe to call concurrently.
+type Daemon struct {
+	cfg       Config
+	broker    *Broker
+	pipelines []Pipeline
+	indexer   Indexer
+	ignore    []string
+
+	mu      sync.RWMutex
+	index   *initializer.MemoryIndex
+	running bool
+}
+
+// NewDaemon creates a watch daemon.
+//
+// # Inputs
+//
+//   - cfg: Configuration. Must be valid (cfg.Validate() == nil).
+//   - broker: Destination for events. Must not be nil.
+//   - pipelines: Pipelines to run on each change. May be empty.
+//   - indexer: Rebuilds the index. Must not be nil.
+//
+// # Outputs
+//
+//   - *Daemon: The daemon, ready to Run.
+//   - error: Non-nil if the configuration is invalid.
+func NewDaemon(cfg Config, broker *Broker, pipelines []Pipeline, indexer Indexer) (*Daemon, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if broker == nil {
+		return nil, fmt.Errorf("broker must not be nil")
+	}
+	if indexer == nil {
+		return nil, fmt.Errorf("indexer must not be nil")
+	}
+	if cfg.PipelineTimeout <= 0 {
+		cfg.PipelineTimeout = DefaultPipelineTimeout
+	}
+
+	ignore := append(append([]string(nil), cfg.IgnoreDirs...), watcher.EditorTempPatterns...)
+
+	return &Daemon{
+		cfg:       cfg,
+		broker:    broker,
+		pipelines: pipelines,
+		indexer:   indexer,
+		ignore:    ignore,
+	}, nil
+}
+
+// Index returns the most recently built index, or nil before the first build.
+func (d *Daemon) Index() *initializer.MemoryIndex {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.index
+}
+
+// Run watches the project until ctx is canceled.
+//
+// # Outputs
+//
+//   - error: nil when ctx is canceled; non-nil if watching could not start.
+func (d *Daemon) Run(ctx context.Context) error {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	d.running = true
+	d.mu.Unlock()
+
+	w, err := watcher.New(watcher.Config{
+		Root:     d.cfg.ProjectRoot,
+		Debounce: d.cfg.Debounce,
+		Ignore:   d.ignore,
+		OnError: func(err error) {
+			d.broker.Publish(Event{Type: EventError, Error: err.Error()})
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	d.reindex(ctx, nil)
+	d.broker.Publish(Event{
+		Type: EventReady,
+		Data: map[string]any{
+			"project_root": d.cfg.ProjectRoot,
+			"pipelines":    d.pipelineNames(),
+		},
+	})
+
+	return w.Run(ctx, func(events []watcher.Event) {
+		d.Process(ctx, d.changedFiles(w, events))
+	})
+}
+
+// Process reindexes and runs every pipeline for one batch of changed files.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - files: Project-relative paths of changed files.
+func (d *Daemon) Process(ctx context.Context, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	d.broker.Publish(Event{Type: EventChange, Files: files})
+
+	index := d.reindex(ctx, files)
+	if index == nil {
+		return
+	}
+
+	for _, p := range d.pipelines {
+		if ctx.Err() != nil {
+			return
+		}
+		d.runPipeline(ctx, p, index, files)
+	}
+}
+
+// reindex rebuilds the index and publishes the outcome. Returns nil on failure.
+func (d *Daemon) reindex(ctx context.Context, files []string) *initializer.MemoryIndex {
+	start := time.Now()
+	index, err := d.indexer(ctx)
+	if err != nil {
+		d.broker.Publish(Event{
+			Type:       EventError,
+			Files:      files,
+			Error:      fmt.Sprintf("reindex: %v", err),
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		return nil
+	}
+
+	d.mu.Lock()
+	d.index = index
+	d.mu.Unlock()
+
+	d.broker.Publish(Event{
+		Type:  EventIndexed,
+		Files: files,
+		Data: map[string]int{
+			"symbols": index.SymbolCount(),
+			"edges":   index.EdgeCount(),
+		},
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	return index
+}
+
+// runPipeline runs one pipeline with the configured timeout.
+func (d *Daemon) runPipeline(ctx context.Context, p Pipeline, index *initializer.MemoryIndex, files []string) {
+	pctx, cancel := context.WithTimeout(ctx, d.cfg.PipelineTimeout)
+	defer cancel()
+
+	start := time.Now()
+	data, err := p.Run(pctx, index, files)
+	ev := Event{
+		Type:       EventResult,
+		Pipeline:   p.Name(),
+		Files:      files,
+		Data:       data,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		ev.Type = EventError
+		ev.Data = nil
+		ev.Error = err.Error()
+	}
+	d.broker.Publish(ev)
+}
+
+// changedFiles returns the sorted project-relative paths touched by a
+// batch. Both sides of a rename count as changed.
+func (d *Daemon) changedFiles(w *watcher.Watcher, events []watcher.Event) []string {
+	changed := make(map[string]bool)
+	add := func(path string) {
+		if rel, err := filepath.Rel(d.cfg.ProjectRoot, path); err == nil {
+			changed[filepath.ToSlash(rel)] = true
+		}
+	}
+	for _, batched := range events {
+		for _, ev := range w.Expand(batched) {
+			add(ev.Path)
+			if ev.OldPath != "" {
+				add(ev.OldPath)
+			}
+		}
+	}
+	return sortedKeys(changed)
+}
+
+// isIgnored reports whether a project-relative path is in an ignored
+// directory or is an editor temporary file.
+func (d *Daemon) isIgnored(rel string) bool {
+	return watcher.NewIgnore(d.ignore...).Match(rel)
+}
+
+// pipelineNames returns the configured pipeline names.
+func (d *Daemon) pipelineNames() []string {
+	names := make([]string, len(d.pipelines))
+	for i, p := range d.pipelines {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Assess runs a risk assessment against the warm index.
+//
+// # Description
+//
+// Lets short-lived clients such as git hooks skip loading the index from
+// disk. Git is still consulted for the changed-file list.
+//
+// # Outputs
+//
+//   - *risk.Result: The assessment.
+//   - error: ErrIndexNotReady before the first successful build, or the
+//     assessment error.
+func (d *Daemon) Assess(ctx context.Context, req AssessRequest) (*risk.Result, error) {
+	index := d.Index()
+	if index == nil {
+		return nil, ErrIndexNotReady
+	}
+
+	cfg := risk.DefaultConfig()
+	cfg.ProjectRoot = d.cfg.ProjectRoot
+	cfg.Mode = risk.ChangeModeStaged
+	if req.Mode != "" {
+		cfg.Mode = risk.ChangeMode(req.Mode)
+	}
+	cfg.BaseBranch = req.BaseBranch
+	cfg.CommitHash = req.CommitHash
+	cfg.Files = req.Files
+	cfg.SkipPolicy = req.SkipPolicy
+	cfg.Quiet = true
+	cfg.BestEffort = true
+
+	return risk.NewAggregator(index, d.cfg.ProjectRoot).Assess(ctx, cfg)
+}
+
+// DefaultIndexer returns an Indexer that rebuilds the on-disk index with
+// the same settings as `aleutian init` and loads it into memory.
+func DefaultIndexer(projectRoot string) Indexer {
+	return func(ctx context.Context) (*initializer.MemoryIndex, error) {
+		storage := initializer.NewStorage(projectRoot)
+		cfg := initializer.DefaultConfig(projectRoot)
+		cfg.Force = true
+		cfg.Quiet = true
+
+		if _, err := initializer.NewInitializer(storage).Init(ctx, cfg, nil); err != nil {
+			return nil, err
+		}
+		return storage.LoadIndex(ctx)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package watch provides the continuous analysis daemon behind `aleutian watch`.
+//
+// # Overview
+//
+// The daemon watches the project tree for saved files, keeps the code index
+// warm by rebuilding it after each batch of changes, re-runs the configured
+// analysis pipelines (lint, impact, risk) on the changed files, and
+// publishes the results as Server-Sent Events so editors can subscribe.
+//
+// # Architecture
+//
+//	┌─────────────────────────────────────────────────────────────────────┐
+//	│                         Watch Daemon Flow                            │
+//	├─────────────────────────────────────────────────────────────────────┤
+//	│                                                                      │
+//	│  ┌──────────────┐     ┌──────────────┐     ┌──────────────┐        │
+//	│  │   fsnotify   │────▶│   Debounce   │────▶│   Reindex    │        │
+//	│  │    events    │     │    window    │     │   (Indexer)  │        │
+//	│  └──────────────┘     └──────────────┘     └──────────────┘        │
+//	│                                                   │                 │
+//	│                                                   ▼                 │
+//	│                                           ┌──────────────┐          │
+//	│                                           │  Pipelines   │          │
+//	│                                           │ lint/impact/ │          │
+//	│                                           │     risk     │          │
+//	│                                           └──────────────┘          │
+//	│                                                   │                 │
+//	│                                                   ▼                 │
+//	│  ┌──────────────┐     ┌──────────────┐     ┌──────────────┐        │
+//	│  │    Editor    │◀────│  SSE server  │◀────│    Broker    │        │
+//	│  │  subscriber  │     │ (TCP / unix) │     │              │        │
+//	│  └──────────────┘     └──────────────┘     └──────────────┘        │
+//	└─────────────────────────────────────────────────────────────────────┘
+//
+// # Endpoints
+//
+//	GET /events  Server-Sent Events stream. New subscribers first receive
+//	             the latest event of each kind, then live events.
+//	GET /status  JSON snapshot of the latest events.
+//	POST /assess Risk assessment against the warm index (used by git hooks).
+//
+// # Usage
+//
+//	broker := watch.NewBroker(watch.DefaultSubscriberBuffer)
+//	pipelines, err := watch.NewPipelines(cfg.Pipelines, cfg.ProjectRoot)
+//	daemon, err := watch.NewDaemon(cfg, broker, pipelines, watch.DefaultIndexer(cfg.ProjectRoot))
+//	ln, err := watch.Listen(cfg)
+//	go http.Serve(ln, watch.NewHandler(broker, daemon))
+//	err = daemon.Run(ctx)
+//
+// # Thread Safety
+//
+// Broker is safe for concurrent use. A Daemon processes one batch of
+// changes at a time; Run must be called at most once.
+package watch
@@ -0,0 +1,137 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/impact"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/risk"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lint"
+)
+
+// Pipeline names.
+const (
+	PipelineLint   = "lint"
+	PipelineImpact = "impact"
+	PipelineRisk   = "risk"
+)
+
+// AvailablePipelines returns the names accepted by NewPipelines.
+func AvailablePipelines() []string {
+	return []string{PipelineLint, PipelineImpact, PipelineRisk}
+}
+
+// NewPipelines builds the named pipelines for a project.
+//
+// # Inputs
+//
+//   - names: Pipeline names (see AvailablePipelines). Case-insensitive.
+//   - projectRoot: Absolute path to the project.
+//
+// # Outputs
+//
+//   - []Pipeline: Pipelines in the order given, without duplicates.
+//   - error: ErrUnknownPipeline if a name is not recognized.
+func NewPipelines(names []string, projectRoot string) ([]Pipeline, error) {
+	seen := make(map[string]bool, len(names))
+	pipelines := make([]Pipeline, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case PipelineLint:
+			pipelines = append(pipelines, &lintPipeline{runner: lint.NewLintRunner(), root: projectRoot})
+		case PipelineImpact:
+			pipelines = append(pipelines, &impactPipeline{root: projectRoot})
+		case PipelineRisk:
+			pipelines = append(pipelines, &riskPipeline{root: projectRoot})
+		default:
+			return nil, fmt.Errorf("%w: %q (available: %s)",
+				ErrUnknownPipeline, name, strings.Join(AvailablePipelines(), ", "))
+		}
+	}
+	return pipelines, nil
+}
+
+// lintPipeline lints each changed file with its language's linter.
+type lintPipeline struct {
+	runner *lint.LintRunner
+	root   string
+}
+
+func (p *lintPipeline) Name() string { return PipelineLint }
+
+// Run lints the changed files. Files in unsupported languages or without an
+// installed linter are skipped rather than reported as failures.
+func (p *lintPipeline) Run(ctx context.Context, _ *initializer.MemoryIndex, files []string) (any, error) {
+	results := make([]*lint.LintResult, 0, len(files))
+	for _, f := range files {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		result, err := p.runner.Lint(ctx, filepath.Join(p.root, f))
+		if err != nil {
+			if errors.Is(err, lint.ErrUnsupportedLanguage) || errors.Is(err, lint.ErrLinterNotInstalled) {
+				continue
+			}
+			return nil, fmt.Errorf("linting %s: %w", f, err)
+		}
+		if result == nil {
+			continue
+		}
+		result.FilePath = f
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// impactPipeline computes the blast radius of the changed files.
+type impactPipeline struct {
+	root string
+}
+
+func (p *impactPipeline) Name() string { return PipelineImpact }
+
+func (p *impactPipeline) Run(ctx context.Context, index *initializer.MemoryIndex, files []string) (any, error) {
+	cfg := impact.DefaultConfig()
+	cfg.Mode = impact.ChangeModeFiles
+	cfg.Files = files
+	cfg.Quiet = true
+	return impact.NewAnalyzer(index, p.root).Analyze(ctx, cfg)
+}
+
+// riskPipeline aggregates impact, policy, and complexity risk signals.
+type riskPipeline struct {
+	root string
+}
+
+func (p *riskPipeline) Name() string { return PipelineRisk }
+
+func (p *riskPipeline) Run(ctx context.Context, index *initializer.MemoryIndex, files []string) (any, error) {
+	cfg := risk.DefaultConfig()
+	cfg.Mode = risk.ChangeModeFiles
+	cfg.Files = files
+	cfg.ProjectRoot = p.root
+	cfg.Quiet = true
+	cfg.BestEffort = true
+	return risk.NewAggregator(index, p.root).Assess(ctx, cfg)
+}
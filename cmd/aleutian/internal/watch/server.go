@@ -0,0 +1,177 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// heartbeatInterval is how often an idle SSE stream sends a keep-alive comment.
+const heartbeatInterval = 15 * time.Second
+
+// statusResponse is the body of GET /status.
+type statusResponse struct {
+	APIVersion  string  `json:"api_version"`
+	Subscribers int     `json:"subscribers"`
+	Dropped     uint64  `json:"dropped"`
+	Latest      []Event `json:"latest"`
+}
+
+// NewHandler returns the HTTP handler serving /events, /status, and /assess.
+//
+// # Inputs
+//
+//   - b: The broker to stream from. Must not be nil.
+//   - d: The daemon whose warm index backs /assess. If nil, /assess is not
+//     served.
+func NewHandler(b *Broker, d *Daemon) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		serveEvents(w, r, b)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statusResponse{
+			APIVersion:  APIVersion,
+			Subscribers: b.SubscriberCount(),
+			Dropped:     b.Dropped(),
+			Latest:      b.Latest(),
+		})
+	})
+	if d != nil {
+		mux.HandleFunc("/assess", func(w http.ResponseWriter, r *http.Request) {
+			serveAssess(w, r, d)
+		})
+	}
+	return mux
+}
+
+// serveAssess runs a risk assessment against the daemon's warm index.
+func serveAssess(w http.ResponseWriter, r *http.Request, d *Daemon) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AssessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result, err := d.Assess(r.Context(), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrIndexNotReady) {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// serveEvents streams broker events as Server-Sent Events until the client
+// disconnects.
+func serveEvents(w http.ResponseWriter, r *http.Request, b *Broker) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before taking the snapshot so no event falls between them.
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	var lastID uint64
+	for _, e := range b.Latest() {
+		if err := writeSSE(w, e); err != nil {
+			return
+		}
+		lastID = e.ID
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.ID <= lastID {
+				continue // already sent in the snapshot
+			}
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE writes one event in text/event-stream format.
+func writeSSE(w http.ResponseWriter, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// Listen opens the listener for the event server.
+//
+// # Description
+//
+// Listens on the unix socket at cfg.SocketPath if set, removing a stale
+// socket file first; otherwise listens on TCP cfg.Addr.
+//
+// # Outputs
+//
+//   - net.Listener: The open listener. Caller must close it.
+//   - error: Non-nil if the listener could not be opened.
+func Listen(cfg Config) (net.Listener, error) {
+	if cfg.SocketPath != "" {
+		if err := os.Remove(cfg.SocketPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+		return net.Listen("unix", cfg.SocketPath)
+	}
+	addr := cfg.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	return net.Listen("tcp", addr)
+}
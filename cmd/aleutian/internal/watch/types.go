@@ -0,0 +1,177 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+)
+
+// APIVersion is the version of the event stream format.
+const APIVersion = "1.0"
+
+// Default values.
+const (
+	DefaultDebounce         = 300 * time.Millisecond
+	DefaultAddr             = "127.0.0.1:7878"
+	DefaultPipelineTimeout  = 60 * time.Second
+	DefaultSubscriberBuffer = 64
+)
+
+// Errors returned by the watch package.
+var (
+	// ErrEmptyProjectRoot indicates no project root was configured.
+	ErrEmptyProjectRoot = errors.New("project root must not be empty")
+
+	// ErrInvalidDebounce indicates a non-positive debounce window.
+	ErrInvalidDebounce = errors.New("debounce must be positive")
+
+	// ErrUnknownPipeline indicates a pipeline name that is not registered.
+	ErrUnknownPipeline = errors.New("unknown pipeline")
+
+	// ErrAlreadyRunning indicates Run was called more than once.
+	ErrAlreadyRunning = errors.New("daemon already running")
+
+	// ErrIndexNotReady indicates the daemon has not built its index yet.
+	ErrIndexNotReady = errors.New("daemon index not ready")
+)
+
+// Config configures the watch daemon.
+//
+// # Fields
+//
+//   - ProjectRoot: Absolute path to the project to watch.
+//   - Pipelines: Pipeline names to run on each change (lint, impact, risk).
+//   - Debounce: Quiet period after the last change before processing.
+//   - Addr: TCP address for the event server. Ignored if SocketPath is set.
+//   - SocketPath: Unix socket path for the event server (optional).
+//   - PipelineTimeout: Maximum time for a single pipeline run.
+//   - IgnoreDirs: Directory names that are never watched.
+type Config struct {
+	ProjectRoot     string
+	Pipelines       []string
+	Debounce        time.Duration
+	Addr            string
+	SocketPath      string
+	PipelineTimeout time.Duration
+	IgnoreDirs      []string
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig(projectRoot string) Config {
+	return Config{
+		ProjectRoot:     projectRoot,
+		Pipelines:       []string{PipelineLint, PipelineImpact, PipelineRisk},
+		Debounce:        DefaultDebounce,
+		Addr:            DefaultAddr,
+		PipelineTimeout: DefaultPipelineTimeout,
+		IgnoreDirs:      []string{".git", initializer.AleutianDir, "vendor", "node_modules"},
+	}
+}
+
+// Validate checks that the Config has valid field values.
+func (c Config) Validate() error {
+	if c.ProjectRoot == "" {
+		return ErrEmptyProjectRoot
+	}
+	if c.Debounce <= 0 {
+		return ErrInvalidDebounce
+	}
+	return nil
+}
+
+// EventType identifies the kind of event published by the daemon.
+type EventType string
+
+const (
+	// EventReady is published once the initial index is built and the
+	// daemon is watching for changes.
+	EventReady EventType = "ready"
+
+	// EventChange is published when a debounced batch of changes is detected.
+	EventChange EventType = "change"
+
+	// EventIndexed is published after the index has been rebuilt.
+	EventIndexed EventType = "indexed"
+
+	// EventResult carries the output of one pipeline run.
+	EventResult EventType = "result"
+
+	// EventError is published when reindexing or a pipeline fails.
+	EventError EventType = "error"
+)
+
+// Event is a single message on the event stream.
+type Event struct {
+	// ID is a monotonically increasing sequence number assigned by the broker.
+	ID uint64 `json:"id"`
+
+	// Type is the kind of event.
+	Type EventType `json:"type"`
+
+	// Pipeline is the pipeline that produced the event, if any.
+	Pipeline string `json:"pipeline,omitempty"`
+
+	// Files are the project-relative paths that triggered the event.
+	Files []string `json:"files,omitempty"`
+
+	// Data is the pipeline result or event payload.
+	Data any `json:"data,omitempty"`
+
+	// Error describes the failure for EventError.
+	Error string `json:"error,omitempty"`
+
+	// Time is when the event was published.
+	Time time.Time `json:"time"`
+
+	// DurationMs is how long the step took, if applicable.
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// key identifies the "latest" slot an event occupies in the broker snapshot.
+func (e Event) key() string {
+	switch e.Type {
+	case EventResult, EventError:
+		return string(e.Type) + ":" + e.Pipeline
+	default:
+		return string(e.Type)
+	}
+}
+
+// Pipeline is an analysis re-run on every batch of changes.
+type Pipeline interface {
+	// Name returns the pipeline name used in events and configuration.
+	Name() string
+
+	// Run analyzes the changed files against the freshly built index.
+	//
+	// files are project-relative paths. The returned value is published as
+	// the event payload and must be JSON-serializable.
+	Run(ctx context.Context, index *initializer.MemoryIndex, files []string) (any, error)
+}
+
+// AssessRequest is the body of POST /assess.
+//
+// Mode is a risk change mode (files, diff, staged, commit, branch). An
+// empty mode means staged, the common case for pre-commit hooks.
+type AssessRequest struct {
+	Mode       string   `json:"mode,omitempty"`
+	BaseBranch string   `json:"base_branch,omitempty"`
+	CommitHash string   `json:"commit_hash,omitempty"`
+	Files      []string `json:"files,omitempty"`
+	SkipPolicy bool     `json:"skip_policy,omitempty"`
+}
+
+// Indexer rebuilds and loads the code index.
+type Indexer func(ctx context.Context) (*initializer.MemoryIndex, error)
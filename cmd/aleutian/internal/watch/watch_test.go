@@ -0,0 +1,361 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/initializer"
+)
+
+// fakePipeline records the files it was run with.
+type fakePipeline struct {
+	name  string
+	err   error
+	calls chan []string
+}
+
+func (p *fakePipeline) Name() string { return p.name }
+
+func (p *fakePipeline) Run(_ context.Context, _ *initializer.MemoryIndex, files []string) (any, error) {
+	if p.calls != nil {
+		p.calls <- files
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return map[string]int{"files": len(files)}, nil
+}
+
+func fakeIndexer(ctx context.Context) (*initializer.MemoryIndex, error) {
+	idx := initializer.NewMemoryIndex()
+	idx.Symbols = []initializer.Symbol{{ID: "a", Name: "A"}}
+	idx.BuildIndexes()
+	return idx, nil
+}
+
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	cfg := DefaultConfig(t.TempDir())
+	cfg.Debounce = 20 * time.Millisecond
+	return cfg
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := (Config{}).Validate(); !errors.Is(err, ErrEmptyProjectRoot) {
+		t.Errorf("empty root: got %v", err)
+	}
+	if err := (Config{ProjectRoot: "/x"}).Validate(); !errors.Is(err, ErrInvalidDebounce) {
+		t.Errorf("zero debounce: got %v", err)
+	}
+	if err := DefaultConfig("/x").Validate(); err != nil {
+		t.Errorf("default config: got %v", err)
+	}
+}
+
+func TestBroker_PublishSubscribe(t *testing.T) {
+	b := NewBroker(1)
+	ch, cancel := b.Subscribe()
+
+	first := b.Publish(Event{Type: EventChange})
+	b.Publish(Event{Type: EventIndexed}) // buffer full: dropped for ch
+
+	got := <-ch
+	if got.ID != first.ID || got.Type != EventChange {
+		t.Errorf("got %+v, want change event %d", got, first.ID)
+	}
+	if b.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", b.Dropped())
+	}
+
+	cancel()
+	cancel() // idempotent
+	if b.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount() = %d, want 0", b.SubscriberCount())
+	}
+	if _, ok := <-ch; ok {
+		t.Error("channel should be closed after cancel")
+	}
+}
+
+func TestBroker_LatestKeepsOnePerKind(t *testing.T) {
+	b := NewBroker(0)
+	b.Publish(Event{Type: EventResult, Pipeline: "lint", Data: 1})
+	b.Publish(Event{Type: EventResult, Pipeline: "risk"})
+	b.Publish(Event{Type: EventResult, Pipeline: "lint", Data: 2})
+
+	latest := b.Latest()
+	if len(latest) != 2 {
+		t.Fatalf("len(Latest()) = %d, want 2", len(latest))
+	}
+	if latest[0].Pipeline != "risk" || latest[1].Data != 2 {
+		t.Errorf("unexpected latest: %+v", latest)
+	}
+}
+
+func TestHandler_Status(t *testing.T) {
+	b := NewBroker(0)
+	b.Publish(Event{Type: EventReady})
+
+	rec := httptest.NewRecorder()
+	NewHandler(b, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	var resp statusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.APIVersion != APIVersion || len(resp.Latest) != 1 || resp.Latest[0].Type != EventReady {
+		t.Errorf("unexpected status: %+v", resp)
+	}
+}
+
+func TestHandler_EventsStream(t *testing.T) {
+	b := NewBroker(0)
+	b.Publish(Event{Type: EventReady})
+
+	srv := httptest.NewServer(NewHandler(b, nil))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		t.Helper()
+		var name string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "event: ") {
+				name = strings.TrimPrefix(line, "event: ")
+			}
+			if line == "" && name != "" {
+				return name
+			}
+		}
+	}
+
+	if got := readEvent(); got != string(EventReady) {
+		t.Errorf("snapshot event = %q, want ready", got)
+	}
+
+	// Wait for the stream to be subscribed before publishing.
+	for b.SubscriberCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	b.Publish(Event{Type: EventResult, Pipeline: "lint"})
+	if got := readEvent(); got != string(EventResult) {
+		t.Errorf("live event = %q, want result", got)
+	}
+}
+
+func TestDaemon_Process(t *testing.T) {
+	b := NewBroker(0)
+	failing := &fakePipeline{name: "broken", err: errors.New("boom")}
+	ok := &fakePipeline{name: "ok"}
+
+	d, err := NewDaemon(testConfig(t), b, []Pipeline{failing, ok}, fakeIndexer)
+	if err != nil {
+		t.Fatalf("NewDaemon: %v", err)
+	}
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	d.Process(context.Background(), []string{"a.go"})
+
+	var types []string
+	for len(events) > 0 {
+		e := <-events
+		types = append(types, string(e.Type)+":"+e.Pipeline)
+	}
+	want := "change: indexed: error:broken result:ok"
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+	if d.Index() == nil || d.Index().SymbolCount() != 1 {
+		t.Error("expected index to be kept warm")
+	}
+}
+
+func TestDaemon_ProcessStopsOnIndexError(t *testing.T) {
+	b := NewBroker(0)
+	p := &fakePipeline{name: "ok", calls: make(chan []string, 1)}
+	indexer := func(context.Context) (*initializer.MemoryIndex, error) {
+		return nil, errors.New("parse failure")
+	}
+
+	d, err := NewDaemon(testConfig(t), b, []Pipeline{p}, indexer)
+	if err != nil {
+		t.Fatalf("NewDaemon: %v", err)
+	}
+	d.Process(context.Background(), []string{"a.go"})
+
+	if len(p.calls) != 0 {
+		t.Error("pipelines should not run when reindex fails")
+	}
+	latest := b.Latest()
+	if last := latest[len(latest)-1]; last.Type != EventError || !strings.Contains(last.Error, "parse failure") {
+		t.Errorf("last event = %+v, want reindex error", last)
+	}
+}
+
+func TestDaemon_RunDebouncesChanges(t *testing.T) {
+	cfg := testConfig(t)
+	if err := os.MkdirAll(filepath.Join(cfg.ProjectRoot, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBroker(0)
+	p := &fakePipeline{name: "ok", calls: make(chan []string, 4)}
+	d, err := NewDaemon(cfg, b, []Pipeline{p}, fakeIndexer)
+	if err != nil {
+		t.Fatalf("NewDaemon: %v", err)
+	}
+
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+
+	waitFor := func(typ EventType) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case e := <-events:
+				if e.Type == typ {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %s", typ)
+			}
+		}
+	}
+	waitFor(EventReady)
+
+	write := func(rel string) {
+		if err := os.WriteFile(filepath.Join(cfg.ProjectRoot, rel), []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go")
+	write("b.go")
+	write(".git/index") // ignored
+
+	select {
+	case files := <-p.calls:
+		if strings.Join(files, ",") != "a.go,b.go" {
+			t.Errorf("pipeline files = %v, want [a.go b.go]", files)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline did not run")
+	}
+
+	stop()
+	if err := <-done; err != nil {
+		t.Errorf("Run() = %v", err)
+	}
+	if err := d.Run(context.Background()); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("second Run() = %v, want ErrAlreadyRunning", err)
+	}
+}
+
+func TestNewPipelines(t *testing.T) {
+	ps, err := NewPipelines([]string{"Lint", "impact", "lint", " risk "}, "/x")
+	if err != nil {
+		t.Fatalf("NewPipelines: %v", err)
+	}
+	var names []string
+	for _, p := range ps {
+		names = append(names, p.Name())
+	}
+	if strings.Join(names, ",") != "lint,impact,risk" {
+		t.Errorf("names = %v", names)
+	}
+
+	if _, err := NewPipelines([]string{"format"}, "/x"); !errors.Is(err, ErrUnknownPipeline) {
+		t.Errorf("unknown pipeline: got %v", err)
+	}
+}
+
+func TestDaemon_IsIgnored(t *testing.T) {
+	d, err := NewDaemon(testConfig(t), NewBroker(0), nil, fakeIndexer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"main.go":                     false,
+		"pkg/server.go":               false,
+		".git/HEAD":                   true,
+		".aleutian/index.json":        true,
+		"vendor/x/y.go":               true,
+		"web/node_modules/a/index.js": true,
+		"main.go~":                    true,
+		".main.go.swp":                true,
+	}
+	for path, want := range tests {
+		if got := d.isIgnored(path); got != want {
+			t.Errorf("isIgnored(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestHandler_Assess(t *testing.T) {
+	b := NewBroker(0)
+	d, err := NewDaemon(testConfig(t), b, nil, fakeIndexer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(b, d)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assess", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /assess = %d, want 405", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/assess", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST /assess before index = %d, want 503", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewHandler(b, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/assess", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("POST /assess without daemon = %d, want 404", rec.Code)
+	}
+}
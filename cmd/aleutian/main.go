@@ -11,12 +11,18 @@
 package main
 
 import (
+	"encoding/json"
 	"log"
+	"os"
 
 	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/config"
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
+	"github.com/spf13/cobra"
 )
 
-var version = "dev"
+// versionJSON makes --version print the build information as JSON.
+var versionJSON bool
 
 func main() {
 	log.Println("Starting up Aleutian Deployment")
@@ -27,11 +33,34 @@ func main() {
 	log.Println("Starting the Aleutian Controller")
 	// Execute the root command. Cobra handles parsing the arguments.
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("Error executing command: %v", err)
+		log.Printf("Error executing command: %v", err)
+		if e, ok := apperr.As(err); ok && e.Remediation != "" {
+			log.Printf("Hint: %s", e.Remediation)
+		}
+		os.Exit(exitCodeFor(err, 1))
 	}
 }
 
 func init() {
-	rootCmd.Version = version
+	rootCmd.Version = buildinfo.Version
 	rootCmd.Flags().BoolP("version", "v", false, "print the version number")
+	rootCmd.Flags().BoolVar(&versionJSON, "json", false, "with --version, print build information as JSON")
+
+	// Cobra prints the version template before any Run hook, so the
+	// template itself picks the output format
+	cobra.AddTemplateFunc("buildInfo", formatBuildInfo)
+	rootCmd.SetVersionTemplate(`{{buildInfo .Name}}`)
+}
+
+// formatBuildInfo renders --version output for the named command.
+func formatBuildInfo(name string) string {
+	info := buildinfo.Get()
+	if versionJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return "{}\n"
+		}
+		return string(data) + "\n"
+	}
+	return name + " version " + info.String() + "\n"
 }
@@ -15,9 +15,16 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
 )
 
 // Exit codes for CLI commands.
+//
+// CLIExitError is used for unclassified failures; errors from the shared
+// apperr taxonomy exit with their category's code instead (64 invalid
+// input, 66 not found, 69 unavailable, 70 internal, 75 retry later,
+// 77 permission denied). See exitCodeFor.
 const (
 	CLIExitSuccess  = 0 // Operation completed successfully
 	CLIExitFindings = 1 // Operation completed with findings/violations
@@ -40,6 +47,11 @@ type CommandResult struct {
 	Success    bool        `json:"success"`
 	Data       interface{} `json:"data,omitempty"`
 	Error      string      `json:"error,omitempty"`
+
+	// ErrorCode, Retryable and Remediation describe a classified error.
+	ErrorCode   string `json:"error_code,omitempty"`
+	Retryable   bool   `json:"retryable,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
 }
 
 // OutputJSON writes structured data as JSON to stdout.
@@ -62,6 +74,11 @@ func OutputJSON(data interface{}, compact bool) error {
 
 // OutputError writes an error in the appropriate format.
 //
+// # Description
+//
+// Classified errors (see pkg/apperr) include their code and remediation:
+// as fields in JSON mode, and as a "Hint:" line on stderr otherwise.
+//
 // # Inputs
 //
 //   - jsonMode: If true, output as JSON to stdout.
@@ -75,14 +92,72 @@ func OutputError(jsonMode bool, msg string, err error) {
 			Success:    false,
 			Error:      fmt.Sprintf("%s: %v", msg, err),
 		}
+		addErrorFields(&result, err)
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		encoder.Encode(result)
 	} else {
-		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
+		printError(msg, err)
+	}
+}
+
+// addErrorFields copies err's classification into result.
+func addErrorFields(result *CommandResult, err error) {
+	if e, ok := apperr.As(err); ok {
+		result.ErrorCode = e.Code
+		result.Retryable = e.Retryable
+		result.Remediation = e.Remediation
 	}
 }
 
+// errorFields returns err's classification as map entries for commands
+// that build their JSON errors as maps. Returns nil for unclassified
+// errors.
+func errorFields(err error) map[string]interface{} {
+	e, ok := apperr.As(err)
+	if !ok {
+		return nil
+	}
+	fields := map[string]interface{}{
+		"error_code": e.Code,
+		"retryable":  e.Retryable,
+	}
+	if e.Remediation != "" {
+		fields["remediation"] = e.Remediation
+	}
+	return fields
+}
+
+// printError writes "Error: msg: err" to stderr, followed by the
+// remediation hint if err is classified and has one.
+func printError(msg string, err error) {
+	if err == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
+	if e, ok := apperr.As(err); ok && e.Remediation != "" {
+		fmt.Fprintf(os.Stderr, "Hint: %s\n", e.Remediation)
+	}
+}
+
+// exitCodeFor returns the exit code for a failed command.
+//
+// # Inputs
+//
+//   - err: The error. Must not be nil.
+//   - fallback: Exit code for errors outside the apperr taxonomy.
+//
+// # Outputs
+//
+//   - int: The category's exit code for classified errors, else fallback.
+func exitCodeFor(err error, fallback int) int {
+	if _, ok := apperr.As(err); ok {
+		return apperr.ExitCode(err)
+	}
+	return fallback
+}
+
 // OutputResult handles all output scenarios with proper formatting.
 //
 // # Inputs
@@ -100,7 +175,7 @@ func OutputError(jsonMode bool, msg string, err error) {
 func OutputResult(cfg OutputConfig, cmd string, start time.Time, data interface{}, hasFindings bool, err error) int {
 	if cfg.Quiet {
 		if err != nil {
-			return CLIExitError
+			return exitCodeFor(err, CLIExitError)
 		}
 		if hasFindings {
 			return CLIExitFindings
@@ -110,7 +185,7 @@ func OutputResult(cfg OutputConfig, cmd string, start time.Time, data interface{
 
 	if err != nil {
 		OutputError(cfg.JSON, "Command failed", err)
-		return CLIExitError
+		return exitCodeFor(err, CLIExitError)
 	}
 
 	if cfg.JSON {
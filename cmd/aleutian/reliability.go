@@ -22,6 +22,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/infra/process"
 	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/resilience"
 	"github.com/AleutianAI/AleutianFOSS/cmd/aleutian/internal/sampling"
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 // ReliabilityOrchestrator defines the interface for coordinating reliability subsystems.
@@ -153,6 +154,11 @@ type ReliabilityConfig struct {
 	// Default: ~/.aleutian/backups
 	BackupDir string
 
+	// BlobDir is the content-addressable store for file backups. Empty
+	// keeps file backups as plain copies in BackupDir.
+	// Default: ~/.aleutian/blobs
+	BlobDir string
+
 	// EnableProcessLock enables CLI mutex to prevent concurrent execution.
 	// Default: true
 	EnableProcessLock bool
@@ -237,6 +243,7 @@ func DefaultReliabilityConfig() ReliabilityConfig {
 		DataDir:                    dataDir,
 		LockDir:                    filepath.Join(dataDir, "locks"),
 		BackupDir:                  filepath.Join(dataDir, "backups"),
+		BlobDir:                    filepath.Join(dataDir, "blobs"),
 		EnableProcessLock:          true,
 		EnableRetentionEnforcement: true,
 		RetentionCheckInterval:     24 * time.Hour,
@@ -447,9 +454,18 @@ func (rm *ReliabilityManager) initializeSubsystems() error {
 	rm.metricsSchema = NewMetricsSchema(DefaultMetricsSchemaConfig())
 
 	// Backup manager
+	var blobs *cas.Store
+	if rm.config.BlobDir != "" {
+		store, err := cas.Open(rm.config.BlobDir)
+		if err != nil {
+			return fmt.Errorf("failed to open blob store: %w", err)
+		}
+		blobs = store
+	}
 	rm.backupManager = resilience.NewBackupManager(resilience.BackupConfig{
 		BackupDir:  rm.config.BackupDir,
 		MaxBackups: 10,
+		Store:      blobs,
 	})
 
 	// Retention enforcer
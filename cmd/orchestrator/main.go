@@ -19,6 +19,18 @@
 //   - LLM_BACKEND_TYPE: LLM provider - local, openai, ollama, claude (default: local)
 //   - WEAVIATE_SERVICE_URL: Weaviate vector DB URL (optional)
 //   - OTEL_EXPORTER_OTLP_ENDPOINT: OpenTelemetry collector (default: aleutian-otel-collector:4317)
+//   - DEPENDENCY_POLICY: Dependency policy overrides, e.g. "weaviate=degrade"
+//
+// # Dependency Checks
+//
+// Dependencies are checked before the server starts, each with a policy
+// (see package depcheck):
+//
+//   - port: The listen port is free (fail)
+//   - weaviate: Weaviate is ready, if WEAVIATE_SERVICE_URL is set (fail;
+//     degrade runs in lightweight mode without Weaviate)
+//   - ollama: Ollama answers, if LLM_BACKEND_TYPE is ollama (retry: the
+//     server starts and LLM requests fail until Ollama is reachable)
 //
 // # Usage
 //
@@ -33,11 +45,16 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
+	"github.com/AleutianAI/AleutianFOSS/pkg/depcheck"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator"
 )
 
@@ -54,7 +71,13 @@ func main() {
 		OTelEndpoint: getEnvString("OTEL_EXPORTER_OTLP_ENDPOINT", "aleutian-otel-collector:4317"),
 	}
 
+	// Check dependencies before creating anything that needs them
+	if err := checkDependencies(&cfg); err != nil {
+		log.Fatalf("Dependency check failed: %v", err)
+	}
+
 	slog.Info("Starting orchestrator",
+		"build", buildinfo.Get().String(),
 		"port", cfg.Port,
 		"llm_backend", cfg.LLMBackend,
 		"weaviate_url", cfg.WeaviateURL,
@@ -73,6 +96,46 @@ func main() {
 	}
 }
 
+// checkDependencies probes the orchestrator's dependencies and applies
+// their policies to cfg.
+//
+// A degraded Weaviate is removed from cfg, so the orchestrator runs in
+// lightweight mode instead of failing on schema creation.
+func checkDependencies(cfg *orchestrator.Config) error {
+	checks := []depcheck.Check{
+		{Name: "port", Policy: depcheck.FailFast, Probe: depcheck.PortFree(fmt.Sprintf(":%d", cfg.Port))},
+	}
+	weaviateURL := strings.Trim(cfg.WeaviateURL, "\"' ")
+	if strings.Contains(weaviateURL, "http") {
+		checks = append(checks, depcheck.Check{
+			Name:   "weaviate",
+			Policy: depcheck.FailFast,
+			Probe:  depcheck.HTTPGet(strings.TrimSuffix(weaviateURL, "/") + "/v1/.well-known/ready"),
+		})
+	}
+	if baseURL := os.Getenv("OLLAMA_BASE_URL"); cfg.LLMBackend == "ollama" && baseURL != "" {
+		checks = append(checks, depcheck.Check{
+			Name:    "ollama",
+			Policy:  depcheck.Retry,
+			Probe:   depcheck.HTTPGet(strings.TrimSuffix(baseURL, "/") + "/api/tags"),
+			OnReady: func() { slog.Info("Ollama reachable, LLM requests enabled") },
+		})
+	}
+
+	deps := depcheck.New(checks...)
+	if err := deps.Override(os.Getenv("DEPENDENCY_POLICY")); err != nil {
+		return err
+	}
+	report, err := deps.Run(context.Background())
+	if err != nil {
+		return err
+	}
+	if cfg.WeaviateURL != "" && !report.Available("weaviate") {
+		cfg.WeaviateURL = ""
+	}
+	return nil
+}
+
 // getEnvString returns the environment variable value or a default.
 func getEnvString(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -181,8 +181,8 @@ func TestIntegration_GetTools(t *testing.T) {
 	}
 	json.Unmarshal(resp.Body.Bytes(), &result)
 
-	if len(result.Tools) != 24 {
-		t.Errorf("Expected 24 tools, got %d", len(result.Tools))
+	if len(result.Tools) != 25 {
+		t.Errorf("Expected 25 tools, got %d", len(result.Tools))
 	}
 
 	// Count by category
@@ -191,8 +191,8 @@ func TestIntegration_GetTools(t *testing.T) {
 		categories[tool.Category]++
 	}
 
-	if categories["explore"] != 9 {
-		t.Errorf("Expected 9 explore tools, got %d", categories["explore"])
+	if categories["explore"] != 10 {
+		t.Errorf("Expected 10 explore tools, got %d", categories["explore"])
 	}
 	if categories["reason"] != 6 {
 		t.Errorf("Expected 6 reason tools, got %d", categories["reason"])
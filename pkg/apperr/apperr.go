@@ -0,0 +1,453 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package apperr is the shared error taxonomy for services and the CLI.
+//
+// # Description
+//
+// Every classified error carries a stable machine-readable code (such as
+// "GRAPH_NOT_FOUND"), a category that determines its HTTP status and CLI
+// exit code, whether retrying can help, and a remediation hint for the
+// user. HTTP handlers render errors with ToResponse so every endpoint
+// returns the same JSON shape, and the CLI exits with ExitCode.
+//
+// Errors are declared once as package-level sentinels with New, which also
+// registers the code so Lookup can describe codes received over the wire:
+//
+//	var ErrIndexNotFound = apperr.New("INDEX_NOT_FOUND", apperr.CategoryNotFound,
+//	    "index not found").WithRemediation("Run 'aleutian init' first")
+//
+// Call sites attach context with Wrap or Withf. The result still matches
+// the sentinel with errors.Is, because errors compare by code:
+//
+//	return ErrIndexNotFound.Withf("no index at %s", dir)
+//
+// # Thread Safety
+//
+// Error values are immutable and safe to share. New and Lookup are safe
+// for concurrent use.
+package apperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// =============================================================================
+// Categories
+// =============================================================================
+
+// Category groups error codes by how callers should react to them.
+type Category string
+
+const (
+	// CategoryInvalidInput means the request or arguments were wrong.
+	CategoryInvalidInput Category = "invalid_input"
+
+	// CategoryNotFound means a referenced resource does not exist.
+	CategoryNotFound Category = "not_found"
+
+	// CategoryConflict means the resource is in a state that forbids the
+	// operation, such as an initialization already in progress.
+	CategoryConflict Category = "conflict"
+
+	// CategoryPermission means the caller is not allowed to do this.
+	CategoryPermission Category = "permission_denied"
+
+	// CategoryRateLimited means a quota or budget is exhausted.
+	CategoryRateLimited Category = "rate_limited"
+
+	// CategoryUnavailable means a dependency or feature is unavailable.
+	CategoryUnavailable Category = "unavailable"
+
+	// CategoryTimeout means the operation ran out of time.
+	CategoryTimeout Category = "timeout"
+
+	// CategoryInternal means a bug or unexpected failure.
+	CategoryInternal Category = "internal"
+)
+
+// CLI exit codes by category, following sysexits(3). Exit codes 1 and 2
+// stay reserved for "completed with findings" and unclassified failures.
+const (
+	ExitUsage       = 64 // CategoryInvalidInput
+	ExitNotFound    = 66 // CategoryNotFound
+	ExitUnavailable = 69 // CategoryUnavailable
+	ExitInternal    = 70 // CategoryInternal
+	ExitTempFail    = 75 // CategoryConflict, CategoryRateLimited, CategoryTimeout
+	ExitPermission  = 77 // CategoryPermission
+
+	// ExitUnclassified is used for errors that are not *Error.
+	ExitUnclassified = 2
+)
+
+// HTTPStatus returns the HTTP status code for the category.
+func (c Category) HTTPStatus() int {
+	switch c {
+	case CategoryInvalidInput:
+		return http.StatusBadRequest
+	case CategoryNotFound:
+		return http.StatusNotFound
+	case CategoryConflict:
+		return http.StatusConflict
+	case CategoryPermission:
+		return http.StatusForbidden
+	case CategoryRateLimited:
+		return http.StatusTooManyRequests
+	case CategoryUnavailable:
+		return http.StatusServiceUnavailable
+	case CategoryTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ExitCode returns the CLI exit code for the category.
+func (c Category) ExitCode() int {
+	switch c {
+	case CategoryInvalidInput:
+		return ExitUsage
+	case CategoryNotFound:
+		return ExitNotFound
+	case CategoryUnavailable:
+		return ExitUnavailable
+	case CategoryConflict, CategoryRateLimited, CategoryTimeout:
+		return ExitTempFail
+	case CategoryPermission:
+		return ExitPermission
+	default:
+		return ExitInternal
+	}
+}
+
+// retryable reports whether errors in the category are retryable by
+// default.
+func (c Category) retryable() bool {
+	switch c {
+	case CategoryConflict, CategoryRateLimited, CategoryUnavailable, CategoryTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// =============================================================================
+// Error
+// =============================================================================
+
+// Error is a classified error.
+type Error struct {
+	// Code is the stable machine-readable code, e.g. "GRAPH_NOT_FOUND".
+	Code string
+
+	// Category determines the HTTP status and exit code.
+	Category Category
+
+	// Retryable is true if the same request may succeed later.
+	Retryable bool
+
+	// Remediation tells the user how to fix the problem. May be empty.
+	Remediation string
+
+	// Message is the human-readable description.
+	Message string
+
+	// Cause is the underlying error, if any.
+	Cause error
+
+	// causeInMessage is set when Message already includes Cause.
+	causeInMessage bool
+}
+
+// Common errors shared by all services.
+var (
+	// ErrInvalidRequest indicates a malformed request body or arguments.
+	ErrInvalidRequest = New("INVALID_REQUEST", CategoryInvalidInput, "invalid request")
+
+	// ErrMissingParameter indicates a required parameter was not supplied.
+	ErrMissingParameter = New("MISSING_PARAMETER", CategoryInvalidInput, "missing required parameter")
+
+	// ErrNotFound indicates a resource does not exist.
+	ErrNotFound = New("NOT_FOUND", CategoryNotFound, "not found")
+
+	// ErrTimeout indicates an operation exceeded its deadline.
+	ErrTimeout = New("TIMEOUT", CategoryTimeout, "operation timed out")
+
+	// ErrInternal indicates an unexpected failure.
+	ErrInternal = New("INTERNAL_ERROR", CategoryInternal, "internal error")
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Error)
+)
+
+// New declares a classified error and registers its code.
+//
+// # Description
+//
+// Retryable defaults to true for the conflict, rate-limited, unavailable
+// and timeout categories. Declaring the same code twice keeps the first
+// registration for Lookup.
+//
+// # Inputs
+//
+//   - code: Stable UPPER_SNAKE_CASE code
+//   - category: Error category
+//   - message: Human-readable description
+//
+// # Outputs
+//
+//   - *Error: The error. Never nil.
+func New(code string, category Category, message string) *Error {
+	e := &Error{
+		Code:      code,
+		Category:  category,
+		Retryable: category.retryable(),
+		Message:   message,
+	}
+	registryMu.Lock()
+	if _, ok := registry[code]; !ok {
+		registry[code] = e
+	}
+	registryMu.Unlock()
+	return e
+}
+
+// Lookup returns the registered error for code.
+func Lookup(code string) (*Error, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := registry[code]
+	return e, ok
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Cause != nil && !e.causeInMessage {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithRemediation returns a copy with the remediation hint set. Applied
+// to a freshly declared error, the copy replaces it in the registry.
+func (e *Error) WithRemediation(remediation string) *Error {
+	c := *e
+	c.Remediation = remediation
+	return reregister(e, &c)
+}
+
+// WithRetryable returns a copy with Retryable set. Applied to a freshly
+// declared error, the copy replaces it in the registry.
+func (e *Error) WithRetryable(retryable bool) *Error {
+	c := *e
+	c.Retryable = retryable
+	return reregister(e, &c)
+}
+
+// reregister replaces old with updated in the registry if old is the
+// registered error for its code.
+func reregister(old, updated *Error) *Error {
+	registryMu.Lock()
+	if registry[old.Code] == old {
+		registry[old.Code] = updated
+	}
+	registryMu.Unlock()
+	return updated
+}
+
+// Wrap returns a copy with cause attached.
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.Cause = cause
+	c.causeInMessage = false
+	return &c
+}
+
+// Withf returns a copy whose message is the formatted text. An argument
+// wrapped with %w becomes the cause.
+func (e *Error) Withf(format string, args ...any) *Error {
+	c := *e
+	formatted := fmt.Errorf(format, args...)
+	c.Message = formatted.Error()
+	c.Cause = errors.Unwrap(formatted)
+	c.causeInMessage = c.Cause != nil
+	return &c
+}
+
+// =============================================================================
+// Classification
+// =============================================================================
+
+// As returns the first *Error in err's chain.
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// From classifies any error.
+//
+// # Description
+//
+// Returns the *Error in err's chain if there is one. Context deadline
+// errors map to ErrTimeout; anything else is ErrInternal with err as the
+// cause. Returns nil for a nil error.
+//
+// # Inputs
+//
+//   - err: Error to classify
+//
+// # Outputs
+//
+//   - *Error: Classified error, with err as the cause when wrapped
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := As(err); ok {
+		return e
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.Wrap(err)
+	default:
+		return ErrInternal.Wrap(err)
+	}
+}
+
+// HTTPStatus returns the HTTP status code for err.
+func HTTPStatus(err error) int {
+	return From(err).Category.HTTPStatus()
+}
+
+// ExitCode returns the CLI exit code for err: 0 for nil,
+// ExitUnclassified for errors that are not *Error, and the category's
+// exit code otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	e, ok := As(err)
+	if !ok {
+		return ExitUnclassified
+	}
+	return e.Category.ExitCode()
+}
+
+// IsRetryable reports whether err is a retryable *Error, or a timeout.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return From(err).Retryable
+}
+
+// =============================================================================
+// Wire Format
+// =============================================================================
+
+// Response is the JSON error body returned by HTTP handlers.
+type Response struct {
+	// Error is the human-readable message.
+	Error string `json:"error"`
+
+	// Code is the machine-readable error code.
+	Code string `json:"code,omitempty"`
+
+	// Category is the error category.
+	Category Category `json:"category,omitempty"`
+
+	// Retryable is true if the same request may succeed later.
+	Retryable bool `json:"retryable"`
+
+	// Remediation tells the user how to fix the problem.
+	Remediation string `json:"remediation,omitempty"`
+
+	// Details provides additional error context.
+	Details string `json:"details,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// A response built with only Error and Code gets Category, Retryable and
+// Remediation from the registered error for its code, so every handler
+// returns the full shape without filling it in by hand.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Category == "" {
+		if e, ok := Lookup(r.Code); ok {
+			r.Category = e.Category
+			r.Retryable = e.Retryable
+			if r.Remediation == "" {
+				r.Remediation = e.Remediation
+			}
+		}
+	}
+	type plain Response
+	return json.Marshal(plain(r))
+}
+
+// ToResponse renders err as a Response. Errors that are not *Error are
+// classified with From.
+func ToResponse(err error) Response {
+	e := From(err)
+	return Response{
+		Error:       err.Error(),
+		Code:        e.Code,
+		Category:    e.Category,
+		Retryable:   e.Retryable,
+		Remediation: e.Remediation,
+	}
+}
+
+// Err converts a Response received over the wire back into an *Error.
+//
+// # Description
+//
+// The registered error for the code supplies defaults; fields present in
+// the response win. The result matches the registered sentinel with
+// errors.Is.
+func (r Response) Err() *Error {
+	e := &Error{Code: r.Code, Category: CategoryInternal}
+	if registered, ok := Lookup(r.Code); ok {
+		*e = *registered
+	}
+	if r.Code == "" {
+		e.Code = ErrInternal.Code
+	}
+	if r.Category != "" {
+		e.Category = r.Category
+		e.Retryable = r.Retryable
+	}
+	if r.Remediation != "" {
+		e.Remediation = r.Remediation
+	}
+	e.Message = r.Error
+	return e
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package apperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+var errTestWidgetBusy = New("TEST_WIDGET_BUSY", CategoryConflict, "widget is busy").
+	WithRemediation("Try again after the current run")
+
+func TestError_Matching(t *testing.T) {
+	err := fmt.Errorf("starting run: %w", errTestWidgetBusy.Withf("widget %q is busy: %w", "w1", io.EOF))
+
+	if !errors.Is(err, errTestWidgetBusy) {
+		t.Error("wrapped error does not match its sentinel")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("%w argument to Withf is not the cause")
+	}
+	if got, want := err.Error(), `starting run: widget "w1" is busy: EOF`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	e, ok := As(err)
+	if !ok || e.Remediation != "Try again after the current run" || !e.Retryable {
+		t.Errorf("As = %+v, %v", e, ok)
+	}
+
+	if got := ErrInternal.Wrap(io.EOF).Error(); got != "internal error: EOF" {
+		t.Errorf("Wrap().Error() = %q", got)
+	}
+}
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		exit   int
+		code   string
+	}{
+		{"classified", errTestWidgetBusy, http.StatusConflict, ExitTempFail, "TEST_WIDGET_BUSY"},
+		{"invalid input", ErrInvalidRequest, http.StatusBadRequest, ExitUsage, "INVALID_REQUEST"},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, ExitUnclassified, "TIMEOUT"},
+		{"plain", errors.New("boom"), http.StatusInternalServerError, ExitUnclassified, "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.status {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.status)
+			}
+			if got := ExitCode(tt.err); got != tt.exit {
+				t.Errorf("ExitCode = %d, want %d", got, tt.exit)
+			}
+			if got := From(tt.err).Code; got != tt.code {
+				t.Errorf("From().Code = %q, want %q", got, tt.code)
+			}
+		})
+	}
+
+	if ExitCode(nil) != 0 || From(nil) != nil {
+		t.Error("nil error should classify as success")
+	}
+}
+
+func TestResponse(t *testing.T) {
+	t.Run("marshal fills registered fields", func(t *testing.T) {
+		data, err := json.Marshal(Response{Error: "widget w1 is busy", Code: "TEST_WIDGET_BUSY"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got["category"] != "conflict" || got["retryable"] != true ||
+			got["remediation"] != "Try again after the current run" {
+			t.Errorf("response = %s", data)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := json.Marshal(ToResponse(errTestWidgetBusy.Withf("widget w1 is busy")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp Response
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		back := resp.Err()
+		if !errors.Is(back, errTestWidgetBusy) || back.Error() != "widget w1 is busy" {
+			t.Errorf("Err() = %v (%s)", back, back.Code)
+		}
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		e := Response{Error: "strange", Code: "NOT_REGISTERED"}.Err()
+		if e.Code != "NOT_REGISTERED" || e.Category != CategoryInternal {
+			t.Errorf("Err() = %+v", e)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package buildinfo describes the build of the running binary.
+//
+// Every Aleutian binary links this package, so the CLI, the orchestrator and
+// the trace service report their version the same way: `aleutian --version
+// --json`, GET /v1/<service>/version, and the service.version attribute of
+// their telemetry resources. Comparing them shows whether the parts of a
+// stack were built from the same commit.
+//
+// # Setting the Values
+//
+// Release builds set the variables with -ldflags. Use the commit time, not
+// the wall clock, for Date so that rebuilding a commit produces the same
+// binary:
+//
+//	PKG=github.com/AleutianAI/AleutianFOSS/pkg/buildinfo
+//	go build -ldflags "\
+//	    -X $PKG.Version=$(cat VERSION.txt) \
+//	    -X $PKG.Commit=$(git rev-parse HEAD) \
+//	    -X $PKG.Date=$(git log -1 --format=%cI) \
+//	    -X $PKG.Features=flock" ./cmd/aleutian
+//
+// Values not set with -ldflags fall back to what the Go toolchain embeds
+// from version control (vcs.revision, vcs.time) and the -tags build
+// setting, so `go build` from a checkout still reports its commit.
+//
+// # Thread Safety
+//
+// All functions are safe for concurrent use.
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Values set at link time with -ldflags "-X".
+var (
+	// Version is the release version, e.g. "0.2.0".
+	Version = "dev"
+
+	// Commit is the full git commit hash.
+	Commit = ""
+
+	// Date is the commit time in RFC 3339 format.
+	Date = ""
+
+	// Features is a comma-separated list of enabled feature flags.
+	Features = ""
+)
+
+// Info describes a build.
+type Info struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	Date      string   `json:"date"`
+	Modified  bool     `json:"modified,omitempty"`
+	Features  []string `json:"features"`
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+}
+
+var (
+	infoOnce sync.Once
+	info     Info
+)
+
+// Get returns the build information of the running binary.
+//
+// # Outputs
+//
+//   - Info: The -ldflags values, completed from the toolchain's embedded
+//     build settings. Features is sorted and never nil.
+func Get() Info {
+	infoOnce.Do(func() {
+		var settings []debug.BuildSetting
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			settings = bi.Settings
+		}
+		info = resolve(Version, Commit, Date, Features, settings)
+	})
+	return info
+}
+
+// resolve builds an Info from link-time values and build settings.
+func resolve(version, commit, date, features string, settings []debug.BuildSetting) Info {
+	i := Info{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	tags := ""
+	for _, s := range settings {
+		switch s.Key {
+		case "vcs.revision":
+			if i.Commit == "" {
+				i.Commit = s.Value
+			}
+		case "vcs.time":
+			if i.Date == "" {
+				i.Date = s.Value
+			}
+		case "vcs.modified":
+			i.Modified = s.Value == "true"
+		case "-tags":
+			tags = s.Value
+		}
+	}
+	if features == "" {
+		features = tags
+	}
+	i.Features = splitFeatures(features)
+	return i
+}
+
+// splitFeatures splits a comma-separated feature list, dropping blanks and
+// duplicates.
+func splitFeatures(s string) []string {
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ShortCommit returns the first 12 characters of the commit hash.
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
+
+// String returns a one-line description, e.g.
+// "0.2.0 (commit 1a2b3c4d5e6f, 2025-01-02T15:04:05Z, go1.25.3 linux/amd64)".
+func (i Info) String() string {
+	commit := i.ShortCommit()
+	if commit == "" {
+		commit = "unknown"
+	}
+	if i.Modified {
+		commit += "-dirty"
+	}
+	parts := []string{"commit " + commit}
+	if i.Date != "" {
+		parts = append(parts, i.Date)
+	}
+	parts = append(parts, i.GoVersion+" "+i.Platform)
+	if len(i.Features) > 0 {
+		parts = append(parts, "features "+strings.Join(i.Features, ","))
+	}
+	return fmt.Sprintf("%s (%s)", i.Version, strings.Join(parts, ", "))
+}
+
+// Attributes returns the build as OpenTelemetry resource attributes:
+// service.version plus aleutian.build.* attributes for the commit, date
+// and features. Add them to every telemetry resource so traces and metrics
+// from different services can be matched by build.
+func (i Info) Attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("service.version", i.Version),
+		attribute.String("aleutian.build.commit", i.Commit),
+		attribute.String("aleutian.build.date", i.Date),
+		attribute.Bool("aleutian.build.modified", i.Modified),
+		attribute.StringSlice("aleutian.build.features", i.Features),
+	}
+}
+
+// VersionResponse is the body of the GET /v1/<service>/version endpoints.
+type VersionResponse struct {
+	Service string `json:"service"`
+	Info
+}
+
+// Handler returns an HTTP handler that serves the running binary's build
+// as a VersionResponse.
+//
+// # Inputs
+//
+//   - service: Service name reported in the response, e.g. "trace".
+func Handler(service string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(VersionResponse{Service: service, Info: Get()})
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	settings := []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+		{Key: "vcs.time", Value: "2025-01-02T15:04:05Z"},
+		{Key: "vcs.modified", Value: "true"},
+		{Key: "-tags", Value: "netgo,flock"},
+	}
+
+	t.Run("ldflags win", func(t *testing.T) {
+		i := resolve("0.2.0", "feedface", "2025-02-03T00:00:00Z", "b, a,a", settings)
+		if i.Version != "0.2.0" || i.Commit != "feedface" || i.Date != "2025-02-03T00:00:00Z" {
+			t.Errorf("resolve() = %+v, want the ldflags values", i)
+		}
+		if !reflect.DeepEqual(i.Features, []string{"a", "b"}) {
+			t.Errorf("Features = %v, want [a b]", i.Features)
+		}
+	})
+
+	t.Run("falls back to build settings", func(t *testing.T) {
+		i := resolve("dev", "", "", "", settings)
+		if i.Commit != "0123456789abcdef0123" || i.Date != "2025-01-02T15:04:05Z" || !i.Modified {
+			t.Errorf("resolve() = %+v, want the vcs settings", i)
+		}
+		if !reflect.DeepEqual(i.Features, []string{"flock", "netgo"}) {
+			t.Errorf("Features = %v, want the build tags", i.Features)
+		}
+		if s := i.String(); !strings.HasPrefix(s, "dev (commit 0123456789ab-dirty, 2025-01-02T15:04:05Z, go") {
+			t.Errorf("String() = %q", s)
+		}
+	})
+
+	t.Run("nothing known", func(t *testing.T) {
+		i := resolve("dev", "", "", "", nil)
+		if i.Features == nil || len(i.Features) != 0 {
+			t.Errorf("Features = %#v, want empty and non-nil", i.Features)
+		}
+		if !strings.Contains(i.String(), "commit unknown") {
+			t.Errorf("String() = %q", i.String())
+		}
+	})
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler("trace").ServeHTTP(rec, httptest.NewRequest("GET", "/v1/trace/version", nil))
+
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body.String())
+	}
+	if resp["service"] != "trace" || resp["version"] != Get().Version || resp["go_version"] == "" {
+		t.Errorf("response = %v", resp)
+	}
+}
@@ -0,0 +1,763 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package cas is a content-addressable blob store on the local filesystem.
+//
+// # Description
+//
+// Blobs are stored once per distinct content under their SHA-256 digest.
+// Callers keep blobs alive with named references; a blob is deleted as soon
+// as its last reference is removed, and GC sweeps blobs that were never
+// referenced (for example after a crash between Put and Link).
+//
+// On-disk layout:
+//
+//	<root>/objects/ab/cdef...   blob content, read-only
+//	<root>/refs/<name>          digest of the referenced blob
+//	<root>/tmp/                 staging area for writes
+//
+// Reference names are slash-separated paths such as
+// "backups/home/me/.aleutian/config.yaml/2025-01-02_150405". The first
+// element is the namespace used to group usage in Report.
+//
+// # Basic Usage
+//
+//	store, err := cas.Open(filepath.Join(dataDir, "blobs"))
+//	if err != nil {
+//	    return err
+//	}
+//	digest, err := store.Save("checkpoints/build/latest", bytes.NewReader(data))
+//	...
+//	data, err := store.GetRef("checkpoints/build/latest")
+//
+// # Thread Safety
+//
+// Store is safe for concurrent use within a process. Puts from several
+// processes are safe because objects are immutable and installed by
+// rename, but Link, Unlink and GC are only serialized within one process.
+package cas
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned by the cas package.
+var (
+	// ErrNotFound indicates no object exists for a digest.
+	ErrNotFound = errors.New("cas: object not found")
+
+	// ErrRefNotFound indicates no reference exists with a name.
+	ErrRefNotFound = errors.New("cas: reference not found")
+
+	// ErrInvalidDigest indicates a string is not a SHA-256 hex digest.
+	ErrInvalidDigest = errors.New("cas: invalid digest")
+
+	// ErrInvalidRef indicates a reference name is empty or not a clean
+	// relative slash-separated path.
+	ErrInvalidRef = errors.New("cas: invalid reference name")
+)
+
+// gcGrace protects objects written moments ago from GC before the writer
+// links them.
+const gcGrace = time.Minute
+
+// =============================================================================
+// Digest
+// =============================================================================
+
+// Digest is the lowercase hex SHA-256 of a blob.
+type Digest string
+
+// ParseDigest validates s as a digest.
+//
+// # Inputs
+//
+//   - s: 64 lowercase hex characters
+//
+// # Outputs
+//
+//   - Digest: The digest
+//   - error: ErrInvalidDigest if s is malformed
+func ParseDigest(s string) (Digest, error) {
+	if len(s) != sha256.Size*2 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDigest, s)
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "", fmt.Errorf("%w: %q", ErrInvalidDigest, s)
+		}
+	}
+	return Digest(s), nil
+}
+
+// Short returns the first 12 characters of the digest for display.
+func (d Digest) Short() string {
+	if len(d) < 12 {
+		return string(d)
+	}
+	return string(d[:12])
+}
+
+// Sum returns the digest of data.
+func Sum(data []byte) Digest {
+	sum := sha256.Sum256(data)
+	return Digest(hex.EncodeToString(sum[:]))
+}
+
+// =============================================================================
+// Store
+// =============================================================================
+
+// Store is a content-addressable blob store rooted at a directory.
+type Store struct {
+	root string
+	mu   sync.Mutex
+}
+
+// Ref is a named reference to a blob.
+type Ref struct {
+	// Name is the slash-separated reference name.
+	Name string
+
+	// Digest is the referenced blob.
+	Digest Digest
+
+	// ModTime is when the reference was last written.
+	ModTime time.Time
+}
+
+// Open opens the store at root, creating its directories if needed.
+//
+// # Inputs
+//
+//   - root: Store directory
+//
+// # Outputs
+//
+//   - *Store: The store
+//   - error: Non-nil if the directories cannot be created
+func Open(root string) (*Store, error) {
+	if root == "" {
+		return nil, errors.New("cas: root must not be empty")
+	}
+	for _, dir := range []string{"objects", "refs", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("cas: create %s: %w", dir, err)
+		}
+	}
+	return &Store{root: root}, nil
+}
+
+// Root returns the store directory.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Put stores the content of r and returns its digest and size.
+//
+// # Description
+//
+// Content is streamed to a staging file while hashing and then renamed
+// into place. If an object with the same digest already exists the staged
+// copy is discarded, so identical content is stored once.
+//
+// The new object is unreferenced until Link is called and may be removed
+// by GC after a grace period; use Save to store and reference in one step.
+//
+// # Inputs
+//
+//   - r: Content to store
+//
+// # Outputs
+//
+//   - Digest: SHA-256 of the content
+//   - int64: Content size in bytes
+//   - error: Non-nil on read or write failure
+func (s *Store) Put(r io.Reader) (Digest, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.put(r)
+}
+
+// PutBytes stores data. See Put.
+func (s *Store) PutBytes(data []byte) (Digest, error) {
+	d, _, err := s.Put(bytes.NewReader(data))
+	return d, err
+}
+
+// Save stores the content of r and points the reference name at it.
+//
+// # Inputs
+//
+//   - name: Reference name
+//   - r: Content to store
+//
+// # Outputs
+//
+//   - Digest: SHA-256 of the content
+//   - error: Non-nil on invalid name or write failure
+func (s *Store) Save(name string, r io.Reader) (Digest, error) {
+	if err := validateRef(name); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, _, err := s.put(r)
+	if err != nil {
+		return "", err
+	}
+	if err := s.link(name, d); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+// Has reports whether an object exists for d.
+func (s *Store) Has(d Digest) bool {
+	if _, err := ParseDigest(string(d)); err != nil {
+		return false
+	}
+	_, err := os.Stat(s.objectPath(d))
+	return err == nil
+}
+
+// Size returns the size of the object for d.
+//
+// # Outputs
+//
+//   - int64: Size in bytes
+//   - error: ErrNotFound if no object exists
+func (s *Store) Size(d Digest) (int64, error) {
+	if _, err := ParseDigest(string(d)); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(s.objectPath(d))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, d)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Reader opens the object for d.
+//
+// # Outputs
+//
+//   - io.ReadCloser: Object content; the caller must close it
+//   - error: ErrNotFound if no object exists
+func (s *Store) Reader(d Digest) (io.ReadCloser, error) {
+	if _, err := ParseDigest(string(d)); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.objectPath(d))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, d)
+	}
+	return f, err
+}
+
+// Get returns the content of the object for d.
+//
+// # Outputs
+//
+//   - []byte: Object content
+//   - error: ErrNotFound if no object exists
+func (s *Store) Get(d Digest) ([]byte, error) {
+	rc, err := s.Reader(d)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// GetRef returns the content of the object the reference name points at.
+//
+// # Outputs
+//
+//   - []byte: Object content
+//   - error: ErrRefNotFound or ErrNotFound
+func (s *Store) GetRef(name string) ([]byte, error) {
+	d, err := s.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(d)
+}
+
+// Link points the reference name at the existing object d.
+//
+// # Description
+//
+// Replaces any existing reference with that name. If the replaced
+// reference was the last one to its object, the object is deleted.
+//
+// # Outputs
+//
+//   - error: ErrInvalidRef, ErrNotFound, or a write failure
+func (s *Store) Link(name string, d Digest) error {
+	if err := validateRef(name); err != nil {
+		return err
+	}
+	if _, err := ParseDigest(string(d)); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.link(name, d)
+}
+
+// Resolve returns the digest the reference name points at.
+//
+// # Outputs
+//
+//   - Digest: Referenced object
+//   - error: ErrRefNotFound if the reference does not exist
+func (s *Store) Resolve(name string) (Digest, error) {
+	if err := validateRef(name); err != nil {
+		return "", err
+	}
+	return s.readRef(s.refPath(name))
+}
+
+// Unlink removes the reference name and deletes its object if no other
+// reference points at it.
+//
+// # Outputs
+//
+//   - error: ErrRefNotFound if the reference does not exist
+func (s *Store) Unlink(name string) error {
+	if err := validateRef(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.refPath(name)
+	d, err := s.readRef(p)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		return fmt.Errorf("cas: remove reference %s: %w", name, err)
+	}
+	s.pruneRefDirs(filepath.Dir(p))
+	return s.release(d)
+}
+
+// Refs returns the references whose names start with prefix, sorted by
+// name. An empty prefix returns every reference.
+func (s *Store) Refs(prefix string) ([]Ref, error) {
+	var refs []Ref
+	refsDir := filepath.Join(s.root, "refs")
+	err := filepath.WalkDir(refsDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(refsDir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		d, err := s.readRef(p)
+		if err != nil {
+			return nil // Removed concurrently or corrupt; skip
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		refs = append(refs, Ref{Name: name, Digest: d, ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cas: list references: %w", err)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs, nil
+}
+
+// RefCount returns how many references point at d.
+func (s *Store) RefCount(d Digest) (int, error) {
+	counts, err := s.refCounts()
+	if err != nil {
+		return 0, err
+	}
+	return counts[d], nil
+}
+
+// =============================================================================
+// Garbage Collection and Reporting
+// =============================================================================
+
+// GCStats summarizes a GC pass.
+type GCStats struct {
+	// Objects is the number of objects removed.
+	Objects int
+
+	// Bytes is the total size of the removed objects.
+	Bytes int64
+}
+
+// GC removes objects no reference points at.
+//
+// # Description
+//
+// Objects written in the last minute are kept so a concurrent Put is not
+// collected before its Link. Stale staging files are removed too.
+//
+// # Outputs
+//
+//   - GCStats: What was removed
+//   - error: Non-nil if the store cannot be read
+func (s *Store) GC() (GCStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stats GCStats
+	counts, err := s.refCounts()
+	if err != nil {
+		return stats, err
+	}
+
+	cutoff := time.Now().Add(-gcGrace)
+	err = s.walkObjects(func(d Digest, info fs.FileInfo) {
+		if counts[d] > 0 || info.ModTime().After(cutoff) {
+			return
+		}
+		if err := os.Remove(s.objectPath(d)); err == nil {
+			stats.Objects++
+			stats.Bytes += info.Size()
+		}
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(s.root, "tmp"))
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(s.root, "tmp", entry.Name()))
+		}
+	}
+	return stats, nil
+}
+
+// NamespaceUsage is the usage of references sharing a first name element.
+type NamespaceUsage struct {
+	// Refs is the number of references.
+	Refs int `json:"refs"`
+
+	// LogicalBytes is the sum of referenced object sizes, counting shared
+	// objects once per reference.
+	LogicalBytes int64 `json:"logical_bytes"`
+}
+
+// Report describes the store's disk usage.
+type Report struct {
+	// Objects is the number of stored objects.
+	Objects int `json:"objects"`
+
+	// Bytes is the total size of stored objects.
+	Bytes int64 `json:"bytes"`
+
+	// Refs is the number of references.
+	Refs int `json:"refs"`
+
+	// LogicalBytes is what the references would occupy without
+	// deduplication.
+	LogicalBytes int64 `json:"logical_bytes"`
+
+	// UnreferencedObjects is the number of objects GC would consider.
+	UnreferencedObjects int `json:"unreferenced_objects"`
+
+	// UnreferencedBytes is the total size of unreferenced objects.
+	UnreferencedBytes int64 `json:"unreferenced_bytes"`
+
+	// Namespaces breaks usage down by the first element of reference names.
+	Namespaces map[string]NamespaceUsage `json:"namespaces"`
+}
+
+// Saved returns the bytes deduplication saves.
+func (r *Report) Saved() int64 {
+	return r.LogicalBytes - (r.Bytes - r.UnreferencedBytes)
+}
+
+// Report summarizes disk usage and deduplication.
+//
+// # Outputs
+//
+//   - *Report: Usage summary
+//   - error: Non-nil if the store cannot be read
+func (s *Store) Report() (*Report, error) {
+	refs, err := s.Refs("")
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[Digest]int64)
+	err = s.walkObjects(func(d Digest, info fs.FileInfo) {
+		sizes[d] = info.Size()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Objects:    len(sizes),
+		Refs:       len(refs),
+		Namespaces: make(map[string]NamespaceUsage),
+	}
+	referenced := make(map[Digest]bool)
+	for _, ref := range refs {
+		size := sizes[ref.Digest]
+		referenced[ref.Digest] = true
+		report.LogicalBytes += size
+
+		ns, _, _ := strings.Cut(ref.Name, "/")
+		usage := report.Namespaces[ns]
+		usage.Refs++
+		usage.LogicalBytes += size
+		report.Namespaces[ns] = usage
+	}
+	for d, size := range sizes {
+		report.Bytes += size
+		if !referenced[d] {
+			report.UnreferencedObjects++
+			report.UnreferencedBytes += size
+		}
+	}
+	return report, nil
+}
+
+// =============================================================================
+// Internals
+// =============================================================================
+
+// put stores r. Callers must hold s.mu.
+func (s *Store) put(r io.Reader) (Digest, int64, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "put-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("cas: create staging file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("cas: write staging file: %w", err)
+	}
+
+	d := Digest(hex.EncodeToString(h.Sum(nil)))
+	objPath := s.objectPath(d)
+	if _, err := os.Stat(objPath); err == nil {
+		// Already stored; refresh the mtime so GC's grace period applies.
+		now := time.Now()
+		_ = os.Chtimes(objPath, now, now)
+		return d, n, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(objPath), 0o755); err != nil {
+		return "", 0, fmt.Errorf("cas: create object directory: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0o444); err != nil {
+		return "", 0, fmt.Errorf("cas: protect object: %w", err)
+	}
+	if err := os.Rename(tmpPath, objPath); err != nil {
+		return "", 0, fmt.Errorf("cas: install object: %w", err)
+	}
+	return d, n, nil
+}
+
+// link writes the reference. Callers must hold s.mu.
+func (s *Store) link(name string, d Digest) error {
+	if !s.Has(d) {
+		return fmt.Errorf("%w: %s", ErrNotFound, d)
+	}
+
+	p := s.refPath(name)
+	previous, prevErr := s.readRef(p)
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("cas: create reference directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-ref-*")
+	if err != nil {
+		return fmt.Errorf("cas: write reference %s: %w", name, err)
+	}
+	_, err = tmp.WriteString(string(d) + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cas: write reference %s: %w", name, err)
+	}
+
+	if prevErr == nil && previous != d {
+		return s.release(previous)
+	}
+	return nil
+}
+
+// release deletes the object for d if no reference points at it. Callers
+// must hold s.mu.
+func (s *Store) release(d Digest) error {
+	n, err := s.RefCount(d)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if err := os.Remove(s.objectPath(d)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cas: remove object %s: %w", d.Short(), err)
+	}
+	return nil
+}
+
+// refCounts returns the number of references to each digest.
+func (s *Store) refCounts() (map[Digest]int, error) {
+	refs, err := s.Refs("")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[Digest]int, len(refs))
+	for _, ref := range refs {
+		counts[ref.Digest]++
+	}
+	return counts, nil
+}
+
+// walkObjects calls fn for every stored object.
+func (s *Store) walkObjects(fn func(Digest, fs.FileInfo)) error {
+	objects := filepath.Join(s.root, "objects")
+	prefixes, err := os.ReadDir(objects)
+	if err != nil {
+		return fmt.Errorf("cas: list objects: %w", err)
+	}
+	for _, prefix := range prefixes {
+		if !prefix.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(objects, prefix.Name()))
+		if err != nil {
+			return fmt.Errorf("cas: list objects: %w", err)
+		}
+		for _, entry := range entries {
+			d, err := ParseDigest(prefix.Name() + entry.Name())
+			if err != nil {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			fn(d, info)
+		}
+	}
+	return nil
+}
+
+// readRef reads the digest stored in a reference file.
+func (s *Store) readRef(p string) (Digest, error) {
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		rel, _ := filepath.Rel(filepath.Join(s.root, "refs"), p)
+		return "", fmt.Errorf("%w: %s", ErrRefNotFound, filepath.ToSlash(rel))
+	}
+	if err != nil {
+		return "", fmt.Errorf("cas: read reference: %w", err)
+	}
+	return ParseDigest(strings.TrimSpace(string(data)))
+}
+
+// pruneRefDirs removes empty reference directories from dir upwards.
+func (s *Store) pruneRefDirs(dir string) {
+	refsDir := filepath.Join(s.root, "refs")
+	for dir != refsDir && strings.HasPrefix(dir, refsDir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func (s *Store) objectPath(d Digest) string {
+	return filepath.Join(s.root, "objects", string(d[:2]), string(d[2:]))
+}
+
+func (s *Store) refPath(name string) string {
+	return filepath.Join(s.root, "refs", filepath.FromSlash(name))
+}
+
+// validateRef checks that name is a clean relative slash-separated path.
+func validateRef(name string) error {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") ||
+		path.Clean(name) != name || name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidRef, name)
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if strings.HasPrefix(elem, ".tmp-") {
+			return fmt.Errorf("%w: %q", ErrInvalidRef, name)
+		}
+	}
+	return nil
+}
+
+// RefName joins elements into a reference name, making each element safe:
+// path separators become '/', and empty, "." and ".." elements are dropped.
+func RefName(elems ...string) string {
+	var parts []string
+	for _, elem := range elems {
+		for _, part := range strings.Split(filepath.ToSlash(elem), "/") {
+			if part == "" || part == "." || part == ".." {
+				continue
+			}
+			parts = append(parts, strings.TrimPrefix(part, ".tmp-"))
+		}
+	}
+	return strings.Join(parts, "/")
+}
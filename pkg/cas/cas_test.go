@@ -0,0 +1,167 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cas
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+func TestStore_Dedup(t *testing.T) {
+	s := openStore(t)
+
+	d1, err := s.Save("backups/a/1", strings.NewReader("same content"))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	d2, err := s.Save("backups/a/2", strings.NewReader("same content"))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if d1 != d2 || d1 != Sum([]byte("same content")) {
+		t.Fatalf("digests = %s, %s; want both %s", d1, d2, Sum([]byte("same content")))
+	}
+
+	report, err := s.Report()
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if report.Objects != 1 || report.Refs != 2 {
+		t.Errorf("Objects = %d, Refs = %d; want 1, 2", report.Objects, report.Refs)
+	}
+	if report.Saved() != int64(len("same content")) {
+		t.Errorf("Saved = %d, want %d", report.Saved(), len("same content"))
+	}
+	if ns := report.Namespaces["backups"]; ns.Refs != 2 {
+		t.Errorf("backups namespace = %+v, want 2 refs", ns)
+	}
+
+	got, err := s.GetRef("backups/a/2")
+	if err != nil || string(got) != "same content" {
+		t.Errorf("GetRef = %q, %v", got, err)
+	}
+}
+
+func TestStore_RefCounting(t *testing.T) {
+	s := openStore(t)
+
+	d, err := s.Save("x/one", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.Link("y/two", d); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if n, _ := s.RefCount(d); n != 2 {
+		t.Errorf("RefCount = %d, want 2", n)
+	}
+
+	if err := s.Unlink("x/one"); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	if !s.Has(d) {
+		t.Fatal("object removed while still referenced")
+	}
+	if _, err := os.Stat(filepath.Join(s.Root(), "refs", "x")); !os.IsNotExist(err) {
+		t.Errorf("empty reference directory left behind: %v", err)
+	}
+
+	// Re-pointing the last reference releases the old object.
+	other, err := s.PutBytes([]byte("other"))
+	if err != nil {
+		t.Fatalf("PutBytes: %v", err)
+	}
+	if err := s.Link("y/two", other); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if s.Has(d) {
+		t.Error("object kept after its last reference moved")
+	}
+
+	if err := s.Unlink("y/two"); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	if s.Has(other) {
+		t.Error("object kept after its last reference was removed")
+	}
+	if err := s.Unlink("y/two"); !errors.Is(err, ErrRefNotFound) {
+		t.Errorf("second Unlink = %v, want ErrRefNotFound", err)
+	}
+}
+
+func TestStore_GC(t *testing.T) {
+	s := openStore(t)
+
+	orphan, err := s.PutBytes([]byte("orphan"))
+	if err != nil {
+		t.Fatalf("PutBytes: %v", err)
+	}
+	kept, err := s.Save("keep/me", strings.NewReader("kept"))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Fresh objects are protected by the grace period.
+	stats, err := s.GC()
+	if err != nil || stats.Objects != 0 {
+		t.Fatalf("GC = %+v, %v; want nothing removed", stats, err)
+	}
+
+	old := time.Now().Add(-2 * gcGrace)
+	for _, d := range []Digest{orphan, kept} {
+		if err := os.Chtimes(s.objectPath(d), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats, err = s.GC()
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if stats.Objects != 1 || stats.Bytes != int64(len("orphan")) {
+		t.Errorf("GC = %+v, want the orphan removed", stats)
+	}
+	if s.Has(orphan) || !s.Has(kept) {
+		t.Errorf("Has(orphan) = %v, Has(kept) = %v", s.Has(orphan), s.Has(kept))
+	}
+}
+
+func TestStore_Validation(t *testing.T) {
+	s := openStore(t)
+
+	for _, name := range []string{"", "/abs", "a/../b", "..", ".", "a//b", "a/.tmp-ref-1"} {
+		if _, err := s.Save(name, strings.NewReader("x")); !errors.Is(err, ErrInvalidRef) {
+			t.Errorf("Save(%q) = %v, want ErrInvalidRef", name, err)
+		}
+	}
+	if err := s.Link("a", Sum([]byte("missing"))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Link to missing object = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Get("nothex"); !errors.Is(err, ErrInvalidDigest) {
+		t.Errorf("Get(nothex) = %v, want ErrInvalidDigest", err)
+	}
+
+	if got := RefName("backups", "/home/me/../x", "2025"); got != "backups/home/me/x/2025" {
+		t.Errorf("RefName = %q", got)
+	}
+}
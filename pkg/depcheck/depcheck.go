@@ -0,0 +1,375 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package depcheck runs startup dependency checks with an explicit policy
+// for each dependency.
+//
+// Services depend on things they do not control: an LLM server, a vector
+// database, writable directories, a free port. Each Check probes one
+// dependency and names what happens when it is unavailable:
+//
+//   - FailFast: startup fails with an error naming the dependency
+//   - Degrade: the service starts with the features that need it disabled
+//   - Retry: the service starts, the check keeps probing in the
+//     background, and OnReady runs once the dependency comes up
+//
+// # Usage
+//
+//	checker := depcheck.New(
+//	    depcheck.Check{Name: "port", Policy: depcheck.FailFast, Probe: depcheck.PortFree(":8080")},
+//	    depcheck.Check{Name: "ollama", Policy: depcheck.Degrade, Probe: depcheck.HTTPGet(ollamaURL + "/api/tags")},
+//	)
+//	if err := checker.Override(os.Getenv("DEPENDENCY_POLICY")); err != nil {
+//	    return err
+//	}
+//	report, err := checker.Run(ctx)
+//	if err != nil {
+//	    return err // A FailFast dependency is unavailable
+//	}
+//	if !report.Available("ollama") {
+//	    // Run without the LLM
+//	}
+//
+// # Policy Overrides
+//
+// Operators override default policies with a comma-separated list of
+// name=policy pairs, e.g. "ollama=fail,weaviate=retry".
+//
+// # Thread Safety
+//
+// Checker is safe for concurrent use once Run has been called.
+package depcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy is what a service does when a dependency is unavailable at
+// startup.
+type Policy int
+
+const (
+	// FailFast aborts startup.
+	FailFast Policy = iota
+
+	// Degrade starts without the features that need the dependency.
+	Degrade
+
+	// Retry starts without waiting and keeps probing in the background.
+	Retry
+)
+
+// String returns the policy name used in overrides.
+func (p Policy) String() string {
+	switch p {
+	case FailFast:
+		return "fail"
+	case Degrade:
+		return "degrade"
+	case Retry:
+		return "retry"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// ParsePolicy parses a policy name ("fail", "degrade" or "retry").
+func ParsePolicy(s string) (Policy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "fail", "fail-fast", "failfast":
+		return FailFast, nil
+	case "degrade":
+		return Degrade, nil
+	case "retry":
+		return Retry, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown policy %q", ErrInvalidPolicy, s)
+	}
+}
+
+var (
+	// ErrUnavailable indicates a FailFast dependency failed its check.
+	ErrUnavailable = errors.New("required dependency unavailable")
+
+	// ErrInvalidPolicy indicates a policy override that cannot be applied.
+	ErrInvalidPolicy = errors.New("invalid dependency policy")
+)
+
+// Probe checks a dependency. It returns nil when the dependency is usable.
+type Probe func(ctx context.Context) error
+
+// Check is one dependency.
+type Check struct {
+	// Name identifies the dependency in logs, reports and overrides.
+	Name string
+
+	// Policy is the default policy.
+	Policy Policy
+
+	// Probe checks the dependency.
+	Probe Probe
+
+	// Timeout bounds each probe. Zero uses DefaultTimeout.
+	Timeout time.Duration
+
+	// OnReady runs once when a Retry dependency passes a background
+	// probe. A check without OnReady cannot use the Retry policy.
+	OnReady func()
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name     string        `json:"name"`
+	Policy   string        `json:"policy"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Attempts int           `json:"attempts"`
+}
+
+// Report is the outcome of a Run.
+type Report struct {
+	Results []Result
+}
+
+// Available reports whether the named dependency passed its startup
+// check. Unknown names are unavailable.
+func (r *Report) Available(name string) bool {
+	for _, res := range r.Results {
+		if res.Name == name {
+			return res.OK
+		}
+	}
+	return false
+}
+
+const (
+	// DefaultTimeout bounds a probe when Check.Timeout is zero.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultRetryInterval is the first background retry delay. It
+	// doubles after each failure up to MaxRetryInterval.
+	DefaultRetryInterval = 2 * time.Second
+
+	// MaxRetryInterval caps the background retry delay.
+	MaxRetryInterval = time.Minute
+)
+
+// Checker runs a set of checks.
+type Checker struct {
+	checks        []Check
+	retryInterval time.Duration
+
+	mu      sync.Mutex
+	results map[string]*Result
+}
+
+// New creates a checker for the given checks.
+func New(checks ...Check) *Checker {
+	return &Checker{
+		checks:        checks,
+		retryInterval: DefaultRetryInterval,
+		results:       make(map[string]*Result, len(checks)),
+	}
+}
+
+// Override replaces default policies from a "name=policy,..." list.
+//
+// # Outputs
+//
+//   - error: ErrInvalidPolicy if a name is unknown, a policy cannot be
+//     parsed, or Retry is set for a check without OnReady.
+func (c *Checker) Override(spec string) error {
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%w: %q is not name=policy", ErrInvalidPolicy, pair)
+		}
+		policy, err := ParsePolicy(value)
+		if err != nil {
+			return err
+		}
+		found := false
+		for i := range c.checks {
+			if c.checks[i].Name == strings.TrimSpace(name) {
+				c.checks[i].Policy = policy
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: unknown dependency %q", ErrInvalidPolicy, name)
+		}
+	}
+	return c.validate()
+}
+
+// validate rejects Retry for checks that cannot act on recovery.
+func (c *Checker) validate() error {
+	for _, check := range c.checks {
+		if check.Policy == Retry && check.OnReady == nil {
+			return fmt.Errorf("%w: %s cannot be retried in the background", ErrInvalidPolicy, check.Name)
+		}
+	}
+	return nil
+}
+
+// Run probes every dependency concurrently.
+//
+// # Description
+//
+// Each failure is logged with its policy. Failed Retry checks keep probing
+// in the background until they pass or ctx is done; ctx should therefore
+// live as long as the service.
+//
+// # Outputs
+//
+//   - *Report: The startup result of every check, in declaration order.
+//   - error: ErrUnavailable naming every failed FailFast dependency, or
+//     ErrInvalidPolicy.
+func (c *Checker) Run(ctx context.Context) (*Report, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.probe(ctx, check, 1)
+		}()
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, check := range c.checks {
+		res := results[i]
+		c.record(check, res)
+		if res.OK {
+			continue
+		}
+		attrs := []any{
+			slog.String("dependency", check.Name),
+			slog.String("policy", check.Policy.String()),
+			slog.String("error", res.Error),
+		}
+		switch check.Policy {
+		case FailFast:
+			slog.Error("Required dependency unavailable", attrs...)
+			failed = append(failed, fmt.Sprintf("%s (%s)", check.Name, res.Error))
+		case Degrade:
+			slog.Warn("Dependency unavailable, starting with reduced features", attrs...)
+		case Retry:
+			slog.Warn("Dependency unavailable, retrying in the background", attrs...)
+			go c.retry(ctx, check)
+		}
+	}
+
+	report := &Report{Results: results}
+	if len(failed) > 0 {
+		return report, fmt.Errorf("%w: %s", ErrUnavailable, strings.Join(failed, "; "))
+	}
+	return report, nil
+}
+
+// Policy returns the effective policy of the named check, after
+// overrides. Unknown names are FailFast.
+func (c *Checker) Policy(name string) Policy {
+	for _, check := range c.checks {
+		if check.Name == name {
+			return check.Policy
+		}
+	}
+	return FailFast
+}
+
+// Status returns the latest result of every check, including background
+// retries, in declaration order.
+func (c *Checker) Status() []Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Result, 0, len(c.checks))
+	for _, check := range c.checks {
+		if res, ok := c.results[check.Name]; ok {
+			out = append(out, *res)
+		}
+	}
+	return out
+}
+
+// probe runs one check with its timeout.
+func (c *Checker) probe(ctx context.Context, check Check, attempt int) Result {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Probe(probeCtx)
+	res := Result{
+		Name:     check.Name,
+		Policy:   check.Policy.String(),
+		OK:       err == nil,
+		Duration: time.Since(start),
+		Attempts: attempt,
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// record stores the latest result of a check.
+func (c *Checker) record(check Check, res Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[check.Name] = &res
+}
+
+// retry probes with exponential backoff until the check passes.
+func (c *Checker) retry(ctx context.Context, check Check) {
+	delay := c.retryInterval
+	for attempt := 2; ; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		res := c.probe(ctx, check, attempt)
+		if ctx.Err() != nil {
+			return
+		}
+		c.record(check, res)
+		if res.OK {
+			slog.Info("Dependency available",
+				slog.String("dependency", check.Name),
+				slog.Int("attempts", attempt))
+			check.OnReady()
+			return
+		}
+		delay = min(2*delay, MaxRetryInterval)
+	}
+}
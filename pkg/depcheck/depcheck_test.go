@@ -0,0 +1,149 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package depcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func ok(context.Context) error   { return nil }
+func down(context.Context) error { return errors.New("connection refused") }
+
+func TestChecker_Run(t *testing.T) {
+	checker := New(
+		Check{Name: "port", Policy: FailFast, Probe: ok},
+		Check{Name: "ollama", Policy: Degrade, Probe: down},
+	)
+	report, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.Available("port") || report.Available("ollama") || report.Available("missing") {
+		t.Errorf("report = %+v", report.Results)
+	}
+
+	checker = New(
+		Check{Name: "weaviate", Policy: FailFast, Probe: down},
+		Check{Name: "disk", Policy: FailFast, Probe: ok},
+	)
+	_, err = checker.Run(context.Background())
+	if !errors.Is(err, ErrUnavailable) || !strings.Contains(err.Error(), "weaviate (connection refused)") {
+		t.Errorf("err = %v, want ErrUnavailable naming weaviate", err)
+	}
+}
+
+func TestChecker_Retry(t *testing.T) {
+	var attempts atomic.Int32
+	ready := make(chan struct{})
+	checker := New(Check{
+		Name:   "ollama",
+		Policy: Retry,
+		Probe: func(context.Context) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		},
+		OnReady: func() { close(ready) },
+	})
+	checker.retryInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := checker.Run(ctx)
+	if err != nil || report.Available("ollama") {
+		t.Fatalf("Run = %v, %+v; want no error and ollama unavailable", err, report.Results)
+	}
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		t.Fatal("OnReady was not called")
+	}
+	status := checker.Status()
+	if len(status) != 1 || !status[0].OK || status[0].Attempts != 3 {
+		t.Errorf("status = %+v, want OK after 3 attempts", status)
+	}
+}
+
+func TestChecker_Override(t *testing.T) {
+	checker := New(
+		Check{Name: "ollama", Policy: Degrade, Probe: ok, OnReady: func() {}},
+		Check{Name: "weaviate", Policy: Degrade, Probe: ok},
+	)
+	if err := checker.Override("ollama=retry, weaviate=fail"); err != nil {
+		t.Fatalf("Override: %v", err)
+	}
+	if checker.Policy("ollama") != Retry || checker.Policy("weaviate") != FailFast {
+		t.Errorf("policies = %v, %v", checker.Policy("ollama"), checker.Policy("weaviate"))
+	}
+
+	for _, spec := range []string{"weaviate=retry", "nope=fail", "ollama=sometimes", "ollama"} {
+		if err := checker.Override(spec); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("Override(%q) = %v, want ErrInvalidPolicy", spec, err)
+		}
+	}
+}
+
+func TestProbes(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	if err := HTTPGet(srv.URL + "/api/tags")(ctx); err != nil {
+		t.Errorf("HTTPGet: %v", err)
+	}
+	if err := HTTPGet(srv.URL + "/broken")(ctx); err == nil {
+		t.Error("HTTPGet accepted a 503")
+	}
+	if err := TCPDial(srv.Listener.Addr().String())(ctx); err != nil {
+		t.Errorf("TCPDial: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PortFree(l.Addr().String())(ctx); err == nil {
+		t.Error("PortFree accepted a bound port")
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if err := PortFree(addr)(ctx); err != nil {
+		t.Errorf("PortFree after close: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "jobs")
+	if err := WritableDir(dir)(ctx); err != nil {
+		t.Errorf("WritableDir: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WritableDir(file)(ctx); err == nil {
+		t.Error("WritableDir accepted a regular file")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package projectconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache holds the settings of several projects and reloads a project's
+// file when it changes on disk.
+//
+// # Description
+//
+// Get stats the settings file on every call and rereads it only when its
+// modification time or size changed, so edits made by `aleutian config
+// set` take effect on the next request without restarting the service.
+// If an edited file is invalid, the last valid settings stay in effect.
+//
+// # Thread Safety
+//
+// Cache is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	config  *Config
+	err     error
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]*cacheEntry)}
+}
+
+// Get returns the current settings of the project at root.
+//
+// # Inputs
+//
+//   - root: Project root directory
+//
+// # Outputs
+//
+//   - *Config: The settings. Never nil: an empty Config if the project has
+//     no settings file, or the last valid settings if the file is invalid.
+//     Callers must not modify it.
+//   - error: Non-nil if the file exists but cannot be read or is invalid
+func (c *Cache) Get(root string) (*Config, error) {
+	root = filepath.Clean(root)
+	info, statErr := os.Stat(Path(root))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[root]
+	if statErr != nil {
+		if errors.Is(statErr, fs.ErrNotExist) {
+			delete(c.entries, root)
+			return &Config{}, nil
+		}
+		return lastGood(entry), statErr
+	}
+	if entry != nil && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return lastGood(entry), entry.err
+	}
+
+	cfg, err := Load(root)
+	next := &cacheEntry{modTime: info.ModTime(), size: info.Size(), config: cfg, err: err}
+	if err != nil {
+		next.config = lastGood(entry)
+	}
+	c.entries[root] = next
+	return next.config, err
+}
+
+// lastGood returns the entry's settings, or an empty Config.
+func lastGood(entry *cacheEntry) *Config {
+	if entry == nil || entry.config == nil {
+		return &Config{}
+	}
+	return entry.config
+}
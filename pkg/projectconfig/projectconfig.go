@@ -0,0 +1,405 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package projectconfig reads and writes per-project settings stored in
+// .aleutian/config.yaml.
+//
+// # Description
+//
+// The file is written by `aleutian init` and `aleutian config set` and read
+// by the trace service, so both sides agree on which languages a project
+// uses, which paths are ignored, which agent tools are allowed and how
+// strictly lint findings are treated. The trace service reads it through a
+// Cache, which reloads the file when it changes on disk.
+//
+// Example file:
+//
+//	format_version: "1.0"
+//	languages: [go, python]
+//	exclude_patterns: [vendor/*, "*_test.go"]
+//	lint_profile: strict
+//	tool_policy:
+//	  disabled: [write_file, edit_file]
+//
+// # Basic Usage
+//
+//	cfg, err := projectconfig.Load(projectRoot)
+//	if errors.Is(err, fs.ErrNotExist) {
+//	    cfg = &projectconfig.Config{}
+//	}
+//	if err := projectconfig.Set(cfg, "lint_profile", "strict"); err != nil {
+//	    return err
+//	}
+//	err = projectconfig.Save(projectRoot, cfg)
+//
+// # Thread Safety
+//
+// Config values are not synchronized. Cache is safe for concurrent use.
+package projectconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/validation"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DirName is the per-project directory holding Aleutian state.
+	DirName = ".aleutian"
+
+	// FileName is the settings file inside DirName.
+	FileName = "config.yaml"
+)
+
+// SupportedLanguages lists the languages a project can be indexed as.
+var SupportedLanguages = []string{"go", "python", "typescript", "javascript", "java", "rust"}
+
+// Lint profiles accepted by lint_profile.
+const (
+	// LintProfileDefault applies each linter's rule policy unchanged.
+	LintProfileDefault = "default"
+
+	// LintProfileStrict treats lint warnings as blocking errors.
+	LintProfileStrict = "strict"
+
+	// LintProfileRelaxed reports blocking lint errors as warnings.
+	LintProfileRelaxed = "relaxed"
+)
+
+// header is written above the settings so readers know how to change them.
+const header = `# Aleutian project configuration
+# Written by 'aleutian init'; change settings with 'aleutian config set'.
+`
+
+// =============================================================================
+// Config
+// =============================================================================
+
+// ToolPolicy restricts which agent tools sessions on the project may use.
+type ToolPolicy struct {
+	// Enabled, if non-empty, is the complete list of tools sessions may use.
+	Enabled []string `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Disabled lists tools sessions may not use. Takes precedence over Enabled.
+	Disabled []string `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// Allows reports whether the policy permits the named tool.
+func (p ToolPolicy) Allows(tool string) bool {
+	if slices.Contains(p.Disabled, tool) {
+		return false
+	}
+	return len(p.Enabled) == 0 || slices.Contains(p.Enabled, tool)
+}
+
+// Config holds the settings stored in .aleutian/config.yaml.
+type Config struct {
+	FormatVersion string `yaml:"format_version" json:"format_version"`
+
+	// Languages are the languages indexed for the project.
+	Languages []string `yaml:"languages" json:"languages"`
+
+	// ExcludePatterns are globs of paths ignored when indexing.
+	ExcludePatterns []string `yaml:"exclude_patterns" json:"exclude_patterns"`
+
+	CreatedAt string `yaml:"created_at" json:"created_at"`
+	UpdatedAt string `yaml:"updated_at" json:"updated_at"`
+
+	// LintProfile is one of "default", "strict" or "relaxed". Empty means
+	// "default".
+	LintProfile string `yaml:"lint_profile,omitempty" json:"lint_profile,omitempty"`
+
+	// ToolPolicy restricts the agent tools available to sessions.
+	ToolPolicy ToolPolicy `yaml:"tool_policy,omitempty" json:"tool_policy,omitempty"`
+}
+
+// EffectiveLintProfile returns the lint profile, defaulting to "default".
+func (c *Config) EffectiveLintProfile() string {
+	if c.LintProfile == "" {
+		return LintProfileDefault
+	}
+	return c.LintProfile
+}
+
+// ValidateFields checks the values of a decoded config.yaml.
+//
+// # Description
+//
+// Languages must be ones the initializer can index, exclude patterns must
+// be valid globs, timestamps must be RFC 3339, and the lint profile must be
+// known. A tool may not be both enabled and disabled. The format version is
+// not checked here; callers that care compare it themselves.
+//
+// # Outputs
+//
+//   - []validation.FieldError: One entry per invalid value, nil if valid
+func (c *Config) ValidateFields() []validation.FieldError {
+	var errs []validation.FieldError
+	add := func(path, format string, args ...any) {
+		errs = append(errs, validation.FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i, lang := range c.Languages {
+		if !slices.Contains(SupportedLanguages, lang) {
+			add(fmt.Sprintf("languages[%d]", i), "unsupported language %q", lang)
+		}
+	}
+	for i, pattern := range c.ExcludePatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			add(fmt.Sprintf("exclude_patterns[%d]", i), "invalid glob %q: %v", pattern, err)
+		}
+	}
+	for _, ts := range []struct{ path, value string }{
+		{"created_at", c.CreatedAt},
+		{"updated_at", c.UpdatedAt},
+	} {
+		if _, err := time.Parse(time.RFC3339, ts.value); ts.value != "" && err != nil {
+			add(ts.path, "expected an RFC 3339 timestamp, got %q", ts.value)
+		}
+	}
+	switch c.LintProfile {
+	case "", LintProfileDefault, LintProfileStrict, LintProfileRelaxed:
+	default:
+		add("lint_profile", "must be one of: default, strict, relaxed, got %q", c.LintProfile)
+	}
+	for i, tool := range c.ToolPolicy.Enabled {
+		if tool == "" {
+			add(fmt.Sprintf("tool_policy.enabled[%d]", i), "tool name must not be empty")
+		}
+	}
+	for i, tool := range c.ToolPolicy.Disabled {
+		switch {
+		case tool == "":
+			add(fmt.Sprintf("tool_policy.disabled[%d]", i), "tool name must not be empty")
+		case slices.Contains(c.ToolPolicy.Enabled, tool):
+			add(fmt.Sprintf("tool_policy.disabled[%d]", i), "tool %q is also enabled", tool)
+		}
+	}
+	return errs
+}
+
+// =============================================================================
+// Reading and Writing
+// =============================================================================
+
+// Path returns the settings file path for the project at root.
+func Path(root string) string {
+	return filepath.Join(root, DirName, FileName)
+}
+
+// Load reads the settings of the project at root.
+//
+// # Description
+//
+// The file is parsed strictly: unknown keys, mistyped values and invalid
+// settings are reported with their line numbers.
+//
+// # Inputs
+//
+//   - root: Project root directory
+//
+// # Outputs
+//
+//   - *Config: The settings. Nil on error.
+//   - error: Wraps fs.ErrNotExist if the project has no settings file, or
+//     is a *validation.YAMLError if the file is invalid
+func Load(root string) (*Config, error) {
+	path := Path(root)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading project config: %w", err)
+	}
+	return Parse(path, data)
+}
+
+// Parse strictly decodes settings read from source.
+func Parse(source string, data []byte) (*Config, error) {
+	var cfg Config
+	if err := validation.DecodeYAML(source, data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Marshal renders cfg as a commented config.yaml.
+func Marshal(cfg *Config) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString("\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return nil, fmt.Errorf("encoding project config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding project config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes cfg to the project at root.
+//
+// # Description
+//
+// The file is written to a temporary file and renamed into place, so a
+// concurrent reader (such as the trace service reloading it) never sees a
+// partial file. The .aleutian directory is created if needed.
+//
+// # Inputs
+//
+//   - root: Project root directory
+//   - cfg: Settings to write. Must pass ValidateFields.
+//
+// # Outputs
+//
+//   - error: Non-nil if cfg is invalid or the file cannot be written
+func Save(root string, cfg *Config) error {
+	if errs := cfg.ValidateFields(); len(errs) > 0 {
+		return fmt.Errorf("invalid project config: %s: %s", errs[0].Path, errs[0].Message)
+	}
+	data, err := Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	path := Path(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", DirName, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("writing project config: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing project config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing project config: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("writing project config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing project config: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// Settings by Key
+// =============================================================================
+
+// Keys lists the settings that Get and Set accept.
+var Keys = []string{
+	"languages",
+	"exclude_patterns",
+	"lint_profile",
+	"tool_policy.enabled",
+	"tool_policy.disabled",
+}
+
+// Get returns the value of a setting. Lists are comma-separated.
+//
+// # Inputs
+//
+//   - cfg: Settings to read
+//   - key: One of Keys
+//
+// # Outputs
+//
+//   - string: The value
+//   - error: Non-nil if the key is unknown
+func Get(cfg *Config, key string) (string, error) {
+	switch key {
+	case "lint_profile":
+		return cfg.EffectiveLintProfile(), nil
+	}
+	list, err := listField(cfg, key)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(*list, ","), nil
+}
+
+// Set changes a setting and validates the result.
+//
+// # Description
+//
+// List settings take a comma-separated value; an empty value clears the
+// list. On error cfg is left unchanged.
+//
+// # Inputs
+//
+//   - cfg: Settings to change
+//   - key: One of Keys
+//   - value: The new value
+//
+// # Outputs
+//
+//   - error: Non-nil if the key is unknown or the value is invalid
+func Set(cfg *Config, key, value string) error {
+	updated := *cfg
+	updated.ToolPolicy = ToolPolicy{
+		Enabled:  slices.Clone(cfg.ToolPolicy.Enabled),
+		Disabled: slices.Clone(cfg.ToolPolicy.Disabled),
+	}
+
+	switch key {
+	case "lint_profile":
+		updated.LintProfile = strings.TrimSpace(value)
+	default:
+		list, err := listField(&updated, key)
+		if err != nil {
+			return err
+		}
+		*list = splitList(value)
+	}
+
+	if errs := updated.ValidateFields(); len(errs) > 0 {
+		return fmt.Errorf("%s: %s", errs[0].Path, errs[0].Message)
+	}
+	*cfg = updated
+	return nil
+}
+
+// listField returns the list setting named key.
+func listField(cfg *Config, key string) (*[]string, error) {
+	switch key {
+	case "languages":
+		return &cfg.Languages, nil
+	case "exclude_patterns":
+		return &cfg.ExcludePatterns, nil
+	case "tool_policy.enabled":
+		return &cfg.ToolPolicy.Enabled, nil
+	case "tool_policy.disabled":
+		return &cfg.ToolPolicy.Disabled, nil
+	}
+	return nil, fmt.Errorf("unknown setting %q (valid: %s)", key, strings.Join(Keys, ", "))
+}
+
+// splitList splits a comma-separated value, dropping empty elements.
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package projectconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+
+	if _, err := Load(root); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Load() without a file error = %v, want fs.ErrNotExist", err)
+	}
+
+	cfg := &Config{
+		FormatVersion:   "1.0",
+		Languages:       []string{"go", "python"},
+		ExcludePatterns: []string{"vendor/*", "*_test.go"},
+		CreatedAt:       "2025-01-02T15:04:05Z",
+		UpdatedAt:       "2025-01-02T15:04:05Z",
+		LintProfile:     LintProfileStrict,
+		ToolPolicy:      ToolPolicy{Disabled: []string{"write_file"}},
+	}
+	if err := Save(root, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("Load() = %+v, want %+v", got, cfg)
+	}
+
+	data, _ := os.ReadFile(Path(root))
+	if !strings.HasPrefix(string(data), "# Aleutian project configuration") {
+		t.Errorf("file does not start with the header:\n%s", data)
+	}
+
+	t.Run("empty policy is omitted", func(t *testing.T) {
+		data, err := Marshal(&Config{FormatVersion: "1.0"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "tool_policy") || strings.Contains(string(data), "lint_profile") {
+			t.Errorf("unset settings written:\n%s", data)
+		}
+	})
+
+	t.Run("invalid config is not saved", func(t *testing.T) {
+		if err := Save(root, &Config{LintProfile: "lenient"}); err == nil {
+			t.Error("Save() accepted an unknown lint profile")
+		}
+	})
+}
+
+func TestLoad_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unknown key", "lint_profil: strict\n", `unknown field "lint_profil"`},
+		{"lint profile", "lint_profile: lenient\n", "1:15: lint_profile: must be one of"},
+		{"language", "languages: [go, cobol]\n", `languages[1]: unsupported language "cobol"`},
+		{"enabled and disabled", "tool_policy:\n  enabled: [grep]\n  disabled: [grep]\n", `tool "grep" is also enabled`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("config.yaml", []byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	cfg := &Config{Languages: []string{"go"}}
+
+	if got, _ := Get(cfg, "lint_profile"); got != LintProfileDefault {
+		t.Errorf("default lint_profile = %q", got)
+	}
+	if err := Set(cfg, "tool_policy.disabled", "write_file, edit_file,"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := Get(cfg, "tool_policy.disabled"); got != "write_file,edit_file" {
+		t.Errorf("tool_policy.disabled = %q", got)
+	}
+	if err := Set(cfg, "lint_profile", "relaxed"); err != nil || cfg.LintProfile != "relaxed" {
+		t.Errorf("Set(lint_profile) error = %v, value %q", err, cfg.LintProfile)
+	}
+
+	for _, bad := range [][2]string{
+		{"lint_profile", "lenient"},
+		{"languages", "go,cobol"},
+		{"tool_policy.enabled", "edit_file"},
+		{"nonsense", "x"},
+	} {
+		if err := Set(cfg, bad[0], bad[1]); err == nil {
+			t.Errorf("Set(%q, %q) succeeded", bad[0], bad[1])
+		}
+	}
+	if cfg.LintProfile != "relaxed" || !reflect.DeepEqual(cfg.Languages, []string{"go"}) || len(cfg.ToolPolicy.Enabled) != 0 {
+		t.Errorf("failed Set modified the config: %+v", cfg)
+	}
+
+	if err := Set(cfg, "tool_policy.disabled", ""); err != nil || cfg.ToolPolicy.Disabled != nil {
+		t.Errorf("clearing a list: error = %v, value %v", err, cfg.ToolPolicy.Disabled)
+	}
+}
+
+func TestToolPolicy_Allows(t *testing.T) {
+	tests := []struct {
+		policy ToolPolicy
+		tool   string
+		want   bool
+	}{
+		{ToolPolicy{}, "grep", true},
+		{ToolPolicy{Disabled: []string{"grep"}}, "grep", false},
+		{ToolPolicy{Enabled: []string{"grep"}}, "grep", true},
+		{ToolPolicy{Enabled: []string{"grep"}}, "write_file", false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Allows(tt.tool); got != tt.want {
+			t.Errorf("%+v.Allows(%q) = %v, want %v", tt.policy, tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestCache_Reload(t *testing.T) {
+	root := t.TempDir()
+	cache := NewCache()
+
+	cfg, err := cache.Get(root)
+	if err != nil || cfg == nil || cfg.LintProfile != "" {
+		t.Fatalf("Get() without a file = %+v, %v; want an empty config", cfg, err)
+	}
+
+	if err := Save(root, &Config{LintProfile: LintProfileStrict}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ := cache.Get(root); cfg.LintProfile != LintProfileStrict {
+		t.Errorf("after save lint_profile = %q, want strict", cfg.LintProfile)
+	}
+
+	// An invalid edit keeps the last valid settings.
+	bump := time.Now().Add(time.Second)
+	if err := os.WriteFile(Path(root), []byte("lint_profile: lenient\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(Path(root), bump, bump)
+	cfg, err = cache.Get(root)
+	if err == nil || cfg.LintProfile != LintProfileStrict {
+		t.Errorf("invalid edit: Get() = %q, %v; want strict and an error", cfg.LintProfile, err)
+	}
+
+	bump = bump.Add(time.Second)
+	if err := os.WriteFile(Path(root), []byte("lint_profile: relaxed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(Path(root), bump, bump)
+	if cfg, err := cache.Get(root); err != nil || cfg.LintProfile != LintProfileRelaxed {
+		t.Errorf("fixed edit: Get() = %q, %v; want relaxed", cfg.LintProfile, err)
+	}
+
+	os.Remove(Path(root))
+	if cfg, err := cache.Get(root); err != nil || cfg.LintProfile != "" {
+		t.Errorf("after removal: Get() = %+v, %v; want an empty config", cfg, err)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package util provides small concurrency helpers shared by Aleutian
+// services and the CLI.
+//
+// # Bounded Channels
+//
+// A Bounded channel has a fixed capacity and an Overflow strategy that
+// decides what a producer does when a consumer falls behind:
+//
+//   - DropOldest: evict the oldest queued value to make room (default)
+//   - Block: wait for space, bounded by the caller's context
+//   - Sample: keep every Nth overflowing value and drop the rest
+//
+// DropOldest and Sample never block the producer, so a slow consumer
+// loses data instead of stalling the loop that feeds it.
+//
+// # Fan-Out
+//
+// FanOut delivers each published value to every subscriber through its
+// own Bounded channel, so one slow subscriber does not delay the others:
+//
+//	fan := util.NewFanOut[Event]()
+//	sub := fan.Subscribe(util.BoundedConfig{Size: 256})
+//	go func() {
+//	    for ev := range sub.C() {
+//	        export(ev)
+//	    }
+//	}()
+//	fan.Publish(ctx, ev)
+//	fan.Close() // closes every subscription's channel
+//
+// SubscribeFunc subscribes to the values a filter accepts, so values a
+// subscriber does not want never take space in its channel.
+//
+// # Thread Safety
+//
+// All exported types are safe for concurrent use.
+package util
@@ -0,0 +1,399 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// =============================================================================
+// Configuration
+// =============================================================================
+
+// Overflow selects what a Bounded channel does with a value sent while
+// it is full.
+type Overflow int
+
+const (
+	// DropOldest evicts the oldest queued value to make room.
+	DropOldest Overflow = iota
+
+	// Block waits for space until the send's context ends or the channel
+	// is closed.
+	Block
+
+	// Sample keeps every SampleEvery-th overflowing value, evicting the
+	// oldest queued value for it, and drops the rest.
+	Sample
+)
+
+// String returns the strategy name.
+func (o Overflow) String() string {
+	switch o {
+	case DropOldest:
+		return "drop_oldest"
+	case Block:
+		return "block"
+	case Sample:
+		return "sample"
+	default:
+		return fmt.Sprintf("overflow(%d)", int(o))
+	}
+}
+
+// BoundedConfig configures a Bounded channel. Zero values select the
+// defaults.
+type BoundedConfig struct {
+	// Size is the channel capacity. Default: 64.
+	Size int
+
+	// Overflow is the strategy used when the channel is full.
+	// Default: DropOldest.
+	Overflow Overflow
+
+	// SampleEvery is the sampling interval for Sample. Default: 10.
+	SampleEvery int
+
+	// OnDrop is called with each value that is dropped or evicted
+	// (may be nil). It runs on the sending goroutine and must not block.
+	OnDrop func(v any)
+}
+
+// withDefaults returns the config with zero values replaced by defaults.
+func (c BoundedConfig) withDefaults() BoundedConfig {
+	if c.Size <= 0 {
+		c.Size = 64
+	}
+	if c.SampleEvery <= 0 {
+		c.SampleEvery = 10
+	}
+	return c
+}
+
+// BoundedStats is a snapshot of a Bounded channel's counters.
+type BoundedStats struct {
+	// Sent is the number of values accepted into the channel.
+	Sent int64
+
+	// Dropped is the number of values dropped or evicted.
+	Dropped int64
+
+	// Queued is the number of values waiting to be received.
+	Queued int
+}
+
+// =============================================================================
+// Bounded
+// =============================================================================
+
+// Bounded is a fixed-capacity channel with an overflow strategy.
+//
+// # Description
+//
+// Producers call Send and a consumer ranges over C. When the channel is
+// full, Send applies the configured Overflow strategy instead of always
+// blocking, so a slow consumer cannot stall the producer unless Block is
+// chosen. Close ends the channel; the consumer still receives the values
+// queued before it.
+//
+// # Thread Safety
+//
+// All methods are safe for concurrent use. With DropOldest or Sample and
+// more than one consumer, an eviction may take a value another consumer
+// was about to receive.
+//
+// # Example
+//
+//	ch := util.NewBounded[*Event](util.BoundedConfig{Size: 128})
+//	go func() {
+//	    for ev := range ch.C() {
+//	        handle(ev)
+//	    }
+//	}()
+//	ch.Send(ctx, ev)
+//	ch.Close()
+type Bounded[T any] struct {
+	cfg BoundedConfig
+	ch  chan T
+
+	mu     sync.RWMutex // write-held while closing ch
+	closed chan struct{}
+	once   sync.Once
+
+	sent     atomic.Int64
+	dropped  atomic.Int64
+	overflow atomic.Int64 // overflowing sends seen by Sample
+}
+
+// NewBounded creates an open Bounded channel.
+//
+// # Inputs
+//
+//   - cfg: Channel configuration. Zero values select defaults.
+//
+// # Outputs
+//
+//   - *Bounded[T]: The channel. Call Close when no more values will be sent.
+func NewBounded[T any](cfg BoundedConfig) *Bounded[T] {
+	cfg = cfg.withDefaults()
+	return &Bounded[T]{
+		cfg:    cfg,
+		ch:     make(chan T, cfg.Size),
+		closed: make(chan struct{}),
+	}
+}
+
+// C returns the receive side of the channel. It is closed by Close.
+func (b *Bounded[T]) C() <-chan T {
+	return b.ch
+}
+
+// Send queues v, applying the overflow strategy if the channel is full.
+//
+// # Inputs
+//
+//   - ctx: Bounds how long a Block send waits. Ignored by the other
+//     strategies. Must not be nil.
+//   - v: The value to send.
+//
+// # Outputs
+//
+//   - bool: True if v was queued, false if it was dropped because the
+//     channel was closed, ctx ended, or the strategy discarded it.
+func (b *Bounded[T]) Send(ctx context.Context, v T) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	select {
+	case <-b.closed:
+		b.drop(v)
+		return false
+	default:
+	}
+
+	select {
+	case b.ch <- v:
+		b.sent.Add(1)
+		return true
+	default:
+	}
+
+	switch b.cfg.Overflow {
+	case Block:
+		select {
+		case b.ch <- v:
+			b.sent.Add(1)
+			return true
+		case <-b.closed:
+		case <-ctx.Done():
+		}
+		b.drop(v)
+		return false
+
+	case Sample:
+		if b.overflow.Add(1)%int64(b.cfg.SampleEvery) != 0 {
+			b.drop(v)
+			return false
+		}
+	}
+
+	// Evict until v fits. Concurrent senders may refill the slot, so
+	// this can take more than one round.
+	for {
+		select {
+		case b.ch <- v:
+			b.sent.Add(1)
+			return true
+		default:
+		}
+		select {
+		case old := <-b.ch:
+			b.drop(old)
+		default:
+		}
+	}
+}
+
+// Close closes the channel. Later sends are dropped, and sends blocked
+// by the Block strategy return false. Safe to call more than once.
+func (b *Bounded[T]) Close() {
+	b.once.Do(func() {
+		close(b.closed)
+		b.mu.Lock() // wait for in-flight sends to finish
+		close(b.ch)
+		b.mu.Unlock()
+	})
+}
+
+// Stats returns a snapshot of the channel's counters.
+func (b *Bounded[T]) Stats() BoundedStats {
+	return BoundedStats{
+		Sent:    b.sent.Load(),
+		Dropped: b.dropped.Load(),
+		Queued:  len(b.ch),
+	}
+}
+
+// drop records a discarded value.
+func (b *Bounded[T]) drop(v T) {
+	b.dropped.Add(1)
+	if b.cfg.OnDrop != nil {
+		b.cfg.OnDrop(v)
+	}
+}
+
+// =============================================================================
+// FanOut
+// =============================================================================
+
+// FanOut delivers published values to every subscriber.
+//
+// # Description
+//
+// Each subscriber receives values through its own Bounded channel with
+// its own overflow strategy, so a subscriber that falls behind only
+// loses its own values. A subscriber may filter the values it receives;
+// filtered values never take space in its channel. Publish blocks only
+// on subscribers that chose the Block strategy.
+//
+// # Thread Safety
+//
+// All methods are safe for concurrent use.
+type FanOut[T any] struct {
+	mu     sync.RWMutex
+	subs   []fanOutSub[T]
+	closed bool
+}
+
+// fanOutSub is one subscription of a FanOut.
+type fanOutSub[T any] struct {
+	ch     *Bounded[T]
+	accept func(T) bool // nil accepts every value
+}
+
+// NewFanOut creates a FanOut with no subscribers.
+func NewFanOut[T any]() *FanOut[T] {
+	return &FanOut[T]{}
+}
+
+// Subscribe adds a subscriber.
+//
+// # Inputs
+//
+//   - cfg: Configuration of the subscriber's channel.
+//
+// # Outputs
+//
+//   - *Bounded[T]: The subscriber's channel. It is already closed if the
+//     FanOut is closed.
+func (f *FanOut[T]) Subscribe(cfg BoundedConfig) *Bounded[T] {
+	return f.SubscribeFunc(cfg, nil)
+}
+
+// SubscribeFunc adds a subscriber that receives only the values accept
+// reports true for.
+//
+// # Inputs
+//
+//   - cfg: Configuration of the subscriber's channel.
+//   - accept: Filter run by Publish on the publishing goroutine; it must
+//     not block. Nil accepts every value.
+//
+// # Outputs
+//
+//   - *Bounded[T]: The subscriber's channel. It is already closed if the
+//     FanOut is closed.
+func (f *FanOut[T]) SubscribeFunc(cfg BoundedConfig, accept func(T) bool) *Bounded[T] {
+	sub := NewBounded[T](cfg)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		sub.Close()
+		return sub
+	}
+	f.subs = append(f.subs, fanOutSub[T]{ch: sub, accept: accept})
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel.
+//
+// # Outputs
+//
+//   - bool: True if sub was subscribed.
+func (f *FanOut[T]) Unsubscribe(sub *Bounded[T]) bool {
+	f.mu.Lock()
+	i := slices.IndexFunc(f.subs, func(s fanOutSub[T]) bool { return s.ch == sub })
+	if i >= 0 {
+		// Copy rather than delete in place: Publish may be iterating
+		// over the old slice.
+		f.subs = slices.Concat(f.subs[:i], f.subs[i+1:])
+	}
+	f.mu.Unlock()
+
+	if i < 0 {
+		return false
+	}
+	sub.Close()
+	return true
+}
+
+// Publish sends v to every subscriber.
+//
+// # Inputs
+//
+//   - ctx: Bounds the wait on subscribers using Block. Must not be nil.
+//   - v: The value to publish.
+//
+// # Outputs
+//
+//   - int: The number of subscribers that queued v.
+func (f *FanOut[T]) Publish(ctx context.Context, v T) int {
+	f.mu.RLock()
+	subs := f.subs
+	f.mu.RUnlock()
+
+	n := 0
+	for _, sub := range subs {
+		if sub.accept != nil && !sub.accept(v) {
+			continue
+		}
+		if sub.ch.Send(ctx, v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of subscribers.
+func (f *FanOut[T]) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.subs)
+}
+
+// Close closes every subscriber's channel. Later subscriptions are
+// returned closed. Safe to call more than once.
+func (f *FanOut[T]) Close() {
+	f.mu.Lock()
+	subs := f.subs
+	f.subs = nil
+	f.closed = true
+	f.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.ch.Close()
+	}
+}
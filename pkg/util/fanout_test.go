@@ -0,0 +1,194 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package util
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drain receives everything left in a closed channel.
+func drain[T any](b *Bounded[T]) []T {
+	var out []T
+	for v := range b.C() {
+		out = append(out, v)
+	}
+	return out
+}
+
+// =============================================================================
+// Bounded Tests
+// =============================================================================
+
+func TestBounded_DropOldest(t *testing.T) {
+	var dropped []any
+	b := NewBounded[int](BoundedConfig{Size: 3, OnDrop: func(v any) { dropped = append(dropped, v) }})
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		if !b.Send(ctx, i) {
+			t.Errorf("Send(%d) = false, want true", i)
+		}
+	}
+	b.Close()
+
+	if got := drain(b); !reflect.DeepEqual(got, []int{3, 4, 5}) {
+		t.Errorf("received %v, want [3 4 5]", got)
+	}
+	if !reflect.DeepEqual(dropped, []any{1, 2}) {
+		t.Errorf("OnDrop saw %v, want [1 2]", dropped)
+	}
+	if s := b.Stats(); s.Sent != 5 || s.Dropped != 2 {
+		t.Errorf("Stats = %+v, want 5 sent, 2 dropped", s)
+	}
+}
+
+func TestBounded_Sample(t *testing.T) {
+	b := NewBounded[int](BoundedConfig{Size: 1, Overflow: Sample, SampleEvery: 3})
+	ctx := context.Background()
+
+	// 1 fills the channel; 2..7 overflow and every third (4, 7) is kept.
+	var kept []int
+	for i := 1; i <= 7; i++ {
+		if b.Send(ctx, i) {
+			kept = append(kept, i)
+		}
+	}
+	b.Close()
+
+	if !reflect.DeepEqual(kept, []int{1, 4, 7}) {
+		t.Errorf("kept %v, want [1 4 7]", kept)
+	}
+	if got := drain(b); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("received %v, want [7]", got)
+	}
+}
+
+func TestBounded_Block(t *testing.T) {
+	b := NewBounded[int](BoundedConfig{Size: 1, Overflow: Block})
+	ctx := context.Background()
+	b.Send(ctx, 1)
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if b.Send(short, 2) {
+		t.Error("Send on a full channel succeeded before the context ended")
+	}
+
+	sent := make(chan bool)
+	go func() { sent <- b.Send(ctx, 3) }()
+	if v := <-b.C(); v != 1 {
+		t.Errorf("received %d, want 1", v)
+	}
+	if !<-sent {
+		t.Error("blocked Send did not complete once space was freed")
+	}
+
+	// Close releases a sender blocked on a full channel.
+	go func() { sent <- b.Send(ctx, 4) }()
+	time.Sleep(5 * time.Millisecond)
+	b.Close()
+	if <-sent {
+		t.Error("Send blocked across Close reported success")
+	}
+	if got := drain(b); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("received %v after Close, want [3]", got)
+	}
+	if b.Send(ctx, 5) {
+		t.Error("Send after Close succeeded")
+	}
+}
+
+// =============================================================================
+// FanOut Tests
+// =============================================================================
+
+// TestFanOut_SlowSubscriber verifies a stalled subscriber neither blocks
+// Publish nor costs the other subscribers any values.
+func TestFanOut_SlowSubscriber(t *testing.T) {
+	fan := NewFanOut[int]()
+	fast := fan.Subscribe(BoundedConfig{Size: 100})
+	slow := fan.Subscribe(BoundedConfig{Size: 2})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var got []int
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		got = drain(fast)
+	}()
+
+	for i := 0; i < 50; i++ {
+		fan.Publish(ctx, i)
+	}
+	fan.Close()
+	wg.Wait()
+
+	if len(got) != 50 {
+		t.Errorf("fast subscriber received %d values, want 50", len(got))
+	}
+	if rest := drain(slow); !reflect.DeepEqual(rest, []int{48, 49}) {
+		t.Errorf("slow subscriber kept %v, want [48 49]", rest)
+	}
+	if s := slow.Stats(); s.Dropped != 48 {
+		t.Errorf("slow Dropped = %d, want 48", s.Dropped)
+	}
+}
+
+func TestFanOut_Unsubscribe(t *testing.T) {
+	fan := NewFanOut[string]()
+	a := fan.Subscribe(BoundedConfig{})
+	b := fan.Subscribe(BoundedConfig{})
+
+	if !fan.Unsubscribe(a) || fan.Unsubscribe(a) {
+		t.Error("Unsubscribe should succeed once")
+	}
+	if n := fan.Publish(context.Background(), "x"); n != 1 || fan.Len() != 1 {
+		t.Errorf("Publish reached %d subscribers of %d, want 1 of 1", n, fan.Len())
+	}
+	if _, ok := <-a.C(); ok {
+		t.Error("unsubscribed channel is still open")
+	}
+
+	fan.Close()
+	if got := drain(b); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("received %v, want [x]", got)
+	}
+	if late := fan.Subscribe(BoundedConfig{}); len(drain(late)) != 0 {
+		t.Error("subscription after Close should be closed and empty")
+	}
+}
+
+func TestFanOut_SubscribeFunc(t *testing.T) {
+	fan := NewFanOut[int]()
+	even := fan.SubscribeFunc(BoundedConfig{Size: 2}, func(v int) bool { return v%2 == 0 })
+	all := fan.Subscribe(BoundedConfig{Size: 8})
+	ctx := context.Background()
+
+	for i := 1; i <= 4; i++ {
+		fan.Publish(ctx, i)
+	}
+	fan.Close()
+
+	if got := drain(even); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("filtered subscriber received %v, want [2 4]", got)
+	}
+	if s := even.Stats(); s.Dropped != 0 {
+		t.Errorf("filtered values counted as dropped: %d", s.Dropped)
+	}
+	if got := drain(all); len(got) != 4 {
+		t.Errorf("unfiltered subscriber received %v, want 4 values", got)
+	}
+}
@@ -0,0 +1,537 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxYAMLBytes is the largest document DecodeYAML accepts.
+// Config files are a few kilobytes; anything near this limit is a mistake.
+const MaxYAMLBytes = 1 << 20
+
+// YAMLIssue is a single problem found in a YAML document.
+//
+// Line and Column are 1-based; zero means the position is unknown.
+type YAMLIssue struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+// String formats the issue as "line:col: path: message".
+func (i YAMLIssue) String() string {
+	var b strings.Builder
+	switch {
+	case i.Line > 0 && i.Column > 0:
+		fmt.Fprintf(&b, "%d:%d: ", i.Line, i.Column)
+	case i.Line > 0:
+		fmt.Fprintf(&b, "%d: ", i.Line)
+	}
+	if i.Path != "" {
+		b.WriteString(i.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// YAMLError reports every issue found while decoding a YAML document.
+//
+// Each issue is printed on its own line prefixed with the source name,
+// so editors and terminals can jump straight to the offending line.
+type YAMLError struct {
+	Source string
+	Issues []YAMLIssue
+}
+
+// Error implements the error interface.
+func (e *YAMLError) Error() string {
+	var b strings.Builder
+	source := e.Source
+	if source == "" {
+		source = "<yaml>"
+	}
+	fmt.Fprintf(&b, "invalid config %s:", source)
+	for _, issue := range e.Issues {
+		b.WriteString("\n  ")
+		b.WriteString(source)
+		b.WriteString(":")
+		b.WriteString(issue.String())
+	}
+	return b.String()
+}
+
+// FieldError is a value-level problem reported by a FieldValidator.
+//
+// Path uses the YAML key names joined with dots, with list indices in
+// brackets, e.g. "profiles[1].max_tokens".
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+// FieldValidator is implemented by decoded types that check value ranges
+// and enumerations after the document's shape has been validated.
+type FieldValidator interface {
+	ValidateFields() []FieldError
+}
+
+// DecodeYAML strictly decodes a YAML document into out.
+//
+// Unlike yaml.Unmarshal, the document is checked against the shape of out
+// before anything is decoded:
+//
+//   - Unknown keys are rejected, with a suggestion when one is close.
+//   - Scalars must match the Go type (integers, booleans, durations, ...).
+//   - Duplicate keys, merge keys (<<), anchors and aliases are rejected, so
+//     a document cannot inject or override fields through indirection.
+//   - Only a single document of at most MaxYAMLBytes is accepted.
+//
+// If out implements FieldValidator, its errors are reported after decoding
+// with the line of the offending key. All problems are collected into one
+// *YAMLError rather than stopping at the first.
+//
+// Example:
+//
+//	var cfg Config
+//	if err := validation.DecodeYAML(path, data, &cfg); err != nil {
+//	    return err // "config.yaml:7:13: machine.cpu_count: expected an integer, got \"four\""
+//	}
+func DecodeYAML(source string, data []byte, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode yaml: out must be a non-nil pointer, got %T", out)
+	}
+	if len(data) > MaxYAMLBytes {
+		return &YAMLError{Source: source, Issues: []YAMLIssue{{
+			Message: fmt.Sprintf("document is %d bytes, limit is %d", len(data), MaxYAMLBytes),
+		}}}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return &YAMLError{Source: source, Issues: issuesFromYAMLError(err)}
+	}
+
+	c := &yamlChecker{}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); err == nil {
+		c.add(&extra, "", "multiple documents are not allowed")
+	} else if !errors.Is(err, io.EOF) {
+		c.issues = append(c.issues, issuesFromYAMLError(err)...)
+	}
+	if doc.Kind != 0 {
+		c.check(&doc, rv.Type().Elem(), "")
+	}
+	if len(c.issues) > 0 {
+		return &YAMLError{Source: source, Issues: c.issues}
+	}
+
+	if doc.Kind != 0 {
+		if err := doc.Decode(out); err != nil {
+			return &YAMLError{Source: source, Issues: issuesFromYAMLError(err)}
+		}
+	}
+
+	if v, ok := out.(FieldValidator); ok {
+		for _, fe := range v.ValidateFields() {
+			issue := YAMLIssue{Path: fe.Path, Message: fe.Message}
+			if n := findYAMLPath(&doc, fe.Path); n != nil {
+				issue.Line, issue.Column = n.Line, n.Column
+			}
+			c.issues = append(c.issues, issue)
+		}
+	}
+	if len(c.issues) > 0 {
+		return &YAMLError{Source: source, Issues: c.issues}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Shape Checking
+// -----------------------------------------------------------------------------
+
+var (
+	durationType    = reflect.TypeOf(time.Duration(0))
+	unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+	// yamlLinePattern extracts the line from yaml.v3 parser and type errors.
+	yamlLinePattern = regexp.MustCompile(`line (\d+): (.*)$`)
+)
+
+// yamlChecker walks a node tree against a Go type, collecting issues.
+type yamlChecker struct {
+	issues []YAMLIssue
+}
+
+func (c *yamlChecker) add(n *yaml.Node, path, format string, args ...any) {
+	c.issues = append(c.issues, YAMLIssue{
+		Line:    n.Line,
+		Column:  n.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *yamlChecker) check(n *yaml.Node, t reflect.Type, path string) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			c.check(child, t, path)
+		}
+		return
+	case yaml.AliasNode:
+		c.add(n, path, "aliases (*%s) are not allowed", n.Value)
+		return
+	}
+	if n.Anchor != "" {
+		c.add(n, path, "anchors (&%s) are not allowed", n.Anchor)
+	}
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null" {
+		return
+	}
+
+	// Types with their own unmarshaling decide their own shape; only the
+	// safety rules still apply.
+	if t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType) {
+		c.checkAny(n, path)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		c.check(n, t.Elem(), path)
+	case reflect.Interface:
+		c.checkAny(n, path)
+	case reflect.Struct:
+		c.checkStruct(n, t, path)
+	case reflect.Map:
+		if !c.expectKind(n, yaml.MappingNode, path) {
+			return
+		}
+		c.eachPair(n, path, func(key, value *yaml.Node) {
+			c.check(value, t.Elem(), joinYAMLPath(path, key.Value))
+		})
+	case reflect.Slice, reflect.Array:
+		if !c.expectKind(n, yaml.SequenceNode, path) {
+			return
+		}
+		for i, item := range n.Content {
+			c.check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		c.checkScalar(n, t, path)
+	}
+}
+
+// checkStruct validates a mapping against the yaml-tagged fields of t.
+func (c *yamlChecker) checkStruct(n *yaml.Node, t reflect.Type, path string) {
+	if !c.expectKind(n, yaml.MappingNode, path) {
+		return
+	}
+	fields, rest := yamlFields(t)
+	c.eachPair(n, path, func(key, value *yaml.Node) {
+		fieldPath := joinYAMLPath(path, key.Value)
+		if ft, ok := fields[key.Value]; ok {
+			c.check(value, ft, fieldPath)
+			return
+		}
+		if rest != nil {
+			c.check(value, rest, fieldPath)
+			return
+		}
+		msg := fmt.Sprintf("unknown field %q", key.Value)
+		if s := suggestField(key.Value, fields); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		c.add(key, path, "%s", msg)
+	})
+}
+
+// eachPair walks mapping pairs, rejecting merge, non-scalar and duplicate keys.
+func (c *yamlChecker) eachPair(n *yaml.Node, path string, fn func(key, value *yaml.Node)) {
+	seen := make(map[string]int, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		switch {
+		case key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge":
+			c.add(key, path, "merge keys (<<) are not allowed")
+			continue
+		case key.Kind != yaml.ScalarNode:
+			c.add(key, path, "mapping keys must be scalars")
+			continue
+		}
+		if line, dup := seen[key.Value]; dup {
+			c.add(key, path, "duplicate key %q (first defined on line %d)", key.Value, line)
+			continue
+		}
+		seen[key.Value] = key.Line
+		fn(key, value)
+	}
+}
+
+// checkAny applies only the safety rules to a free-form subtree.
+func (c *yamlChecker) checkAny(n *yaml.Node, path string) {
+	if n.Kind == yaml.AliasNode {
+		c.add(n, path, "aliases (*%s) are not allowed", n.Value)
+		return
+	}
+	if n.Anchor != "" {
+		c.add(n, path, "anchors (&%s) are not allowed", n.Anchor)
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		c.eachPair(n, path, func(key, value *yaml.Node) {
+			c.checkAny(value, joinYAMLPath(path, key.Value))
+		})
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			c.checkAny(item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// checkScalar validates a scalar by decoding it into a fresh value of t.
+func (c *yamlChecker) checkScalar(n *yaml.Node, t reflect.Type, path string) {
+	if !c.expectKind(n, yaml.ScalarNode, path) {
+		return
+	}
+	if t.Kind() == reflect.String {
+		return
+	}
+	if t == durationType && n.ShortTag() == "!!str" {
+		if _, err := time.ParseDuration(n.Value); err != nil {
+			c.add(n, path, "expected a duration such as \"30s\", got %q", n.Value)
+		}
+		return
+	}
+	if err := n.Decode(reflect.New(t).Interface()); err == nil {
+		return
+	}
+	if n.ShortTag() == "!!int" && isIntegerKind(t.Kind()) {
+		c.add(n, path, "integer %s is out of range for %s", n.Value, t.Kind())
+		return
+	}
+	c.add(n, path, "expected %s, got %q", describeYAMLType(t), n.Value)
+}
+
+// expectKind reports a mismatch when n is not of the wanted kind.
+func (c *yamlChecker) expectKind(n *yaml.Node, want yaml.Kind, path string) bool {
+	if n.Kind == want {
+		return true
+	}
+	c.add(n, path, "expected %s, got %s", yamlKindName(want), yamlKindName(n.Kind))
+	return false
+}
+
+// yamlFields maps the YAML keys of struct t to their field types. Inline
+// structs contribute their own keys; an inline map accepts any other key
+// and is returned as rest.
+func yamlFields(t reflect.Type) (fields map[string]reflect.Type, rest reflect.Type) {
+	fields = make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			switch ft.Kind() {
+			case reflect.Struct:
+				inner, innerRest := yamlFields(ft)
+				for k, v := range inner {
+					fields[k] = v
+				}
+				if innerRest != nil {
+					rest = innerRest
+				}
+			case reflect.Map:
+				rest = ft.Elem()
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields, rest
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// findYAMLPath returns the deepest node along path, so a missing key is
+// reported at its parent.
+func findYAMLPath(n *yaml.Node, path string) *yaml.Node {
+	if n == nil || n.Kind == 0 {
+		return nil
+	}
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) == 0 {
+			return nil
+		}
+		n = n.Content[0]
+	}
+	for path != "" {
+		path = strings.TrimPrefix(path, ".")
+		var next *yaml.Node
+		switch {
+		case strings.HasPrefix(path, "[") && n.Kind == yaml.SequenceNode:
+			end := strings.Index(path, "]")
+			idx, err := strconv.Atoi(path[1:max(end, 1)])
+			if end < 0 || err != nil || idx < 0 || idx >= len(n.Content) {
+				return n
+			}
+			next, path = n.Content[idx], path[end+1:]
+		case n.Kind == yaml.MappingNode:
+			// Map keys may contain dots, so match the longest key that
+			// is followed by a separator rather than splitting the path.
+			best := -1
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k := n.Content[i].Value
+				if path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[") {
+					if best < 0 || len(k) > len(n.Content[best].Value) {
+						best = i
+					}
+				}
+			}
+			if best < 0 {
+				return n
+			}
+			next, path = n.Content[best+1], path[len(n.Content[best].Value):]
+		default:
+			return n
+		}
+		n = next
+	}
+	return n
+}
+
+// issuesFromYAMLError converts yaml.v3 parser and type errors into issues.
+func issuesFromYAMLError(err error) []YAMLIssue {
+	var messages []string
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	issues := make([]YAMLIssue, 0, len(messages))
+	for _, msg := range messages {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		issue := YAMLIssue{Message: msg}
+		if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+			issue.Message = m[2]
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// suggestField returns the known field closest to name, if any is close.
+func suggestField(name string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for candidate := range fields {
+		d := editDistance(name, candidate)
+		if d < bestDist || (d == bestDist && best != "" && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func joinYAMLPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
+
+func describeYAMLType(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return `a duration such as "30s"`
+	case t.Kind() == reflect.Bool:
+		return "true or false"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return "an integer"
+	case isIntegerKind(t.Kind()):
+		return "a non-negative integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "a number"
+	}
+	return t.String()
+}
+
+func yamlKindName(k yaml.Kind) string {
+	switch k {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.ScalarNode:
+		return "a scalar value"
+	case yaml.AliasNode:
+		return "an alias"
+	}
+	return "a document"
+}
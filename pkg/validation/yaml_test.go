@@ -0,0 +1,205 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testServer struct {
+	Host    string        `yaml:"host"`
+	Port    uint16        `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+type testBase struct {
+	Name string `yaml:"name"`
+}
+
+type testConfig struct {
+	testBase `yaml:",inline"`
+	Enabled  bool                  `yaml:"enabled"`
+	Workers  int                   `yaml:"workers"`
+	Ratio    float64               `yaml:"ratio"`
+	Server   testServer            `yaml:"server"`
+	Tags     []string              `yaml:"tags"`
+	Backends map[string]testServer `yaml:"backends"`
+	Limit    *int                  `yaml:"limit,omitempty"`
+	Extra    any                   `yaml:"extra"`
+	Ignored  string                `yaml:"-"`
+}
+
+func (c *testConfig) ValidateFields() []FieldError {
+	var errs []FieldError
+	if c.Workers < 0 {
+		errs = append(errs, FieldError{Path: "workers", Message: "must be >= 0"})
+	}
+	for name, b := range c.Backends {
+		if b.Host == "" {
+			errs = append(errs, FieldError{Path: "backends." + name + ".host", Message: "required"})
+		}
+	}
+	return errs
+}
+
+// decodeIssues decodes src and returns the issues, failing on other errors.
+func decodeIssues(t *testing.T, src string) []YAMLIssue {
+	t.Helper()
+	var cfg testConfig
+	err := DecodeYAML("test.yaml", []byte(src), &cfg)
+	if err == nil {
+		return nil
+	}
+	var yerr *YAMLError
+	if !errors.As(err, &yerr) {
+		t.Fatalf("error %v is not a *YAMLError", err)
+	}
+	return yerr.Issues
+}
+
+func TestDecodeYAML_Valid(t *testing.T) {
+	src := `
+name: demo
+enabled: true
+workers: 4
+ratio: 0.5
+server:
+  host: localhost
+  port: 8080
+  timeout: 30s
+tags: [a, b]
+backends:
+  llama3.2:
+    host: ollama
+limit: 3
+extra:
+  anything: [1, 2]
+`
+	var cfg testConfig
+	if err := DecodeYAML("test.yaml", []byte(src), &cfg); err != nil {
+		t.Fatalf("DecodeYAML() error = %v", err)
+	}
+	if cfg.Name != "demo" || cfg.Server.Timeout != 30*time.Second || *cfg.Limit != 3 {
+		t.Errorf("decoded %+v", cfg)
+	}
+	if cfg.Backends["llama3.2"].Host != "ollama" {
+		t.Errorf("backends = %+v", cfg.Backends)
+	}
+
+	t.Run("empty document", func(t *testing.T) {
+		if issues := decodeIssues(t, ""); issues != nil {
+			t.Errorf("issues = %v", issues)
+		}
+	})
+}
+
+func TestDecodeYAML_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		wantLine int
+		wantPath string
+		wantMsg  string
+	}{
+		{"unknown field", "server:\n  hots: x\n", 2, "server", `unknown field "hots" (did you mean "host"?)`},
+		{"unknown top-level", "bogus: 1\n", 1, "", `unknown field "bogus"`},
+		{"ignored field", "ignored: x\n", 1, "", `unknown field "ignored"`},
+		{"bool type", "enabled: maybe\n", 1, "enabled", "expected true or false"},
+		{"int type", "workers: four\n", 1, "workers", `expected an integer, got "four"`},
+		{"int range", "server:\n  port: 70000\n", 2, "server.port", "out of range for uint16"},
+		{"duration", "server:\n  timeout: soon\n", 2, "server.timeout", "expected a duration"},
+		{"mapping expected", "server: localhost\n", 1, "server", "expected a mapping, got a scalar value"},
+		{"list expected", "tags: a\n", 1, "tags", "expected a list"},
+		{"duplicate key", "workers: 1\nworkers: 2\n", 2, "", `duplicate key "workers" (first defined on line 1)`},
+		{"merge key", "base: &b {host: x}\nserver:\n  <<: *b\n", 1, "", "unknown field"},
+		{"alias in free-form", "extra: &e [1]\ntags: *e\n", 1, "extra", "anchors (&e) are not allowed"},
+		{"multiple documents", "workers: 1\n---\nworkers: 2\n", 2, "", "multiple documents"},
+		{"syntax error", "server:\n  host: [x\n", 1, "", ""},
+		{"field validator", "workers: -1\n", 1, "workers", "must be >= 0"},
+		{"validator map path", "backends:\n  a.b:\n    port: 1\n", 3, "backends.a.b.host", "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := decodeIssues(t, tt.src)
+			if len(issues) == 0 {
+				t.Fatal("expected issues, got none")
+			}
+			issue := issues[0]
+			if issue.Line != tt.wantLine {
+				t.Errorf("line = %d, want %d (%v)", issue.Line, tt.wantLine, issues)
+			}
+			if issue.Path != tt.wantPath {
+				t.Errorf("path = %q, want %q", issue.Path, tt.wantPath)
+			}
+			if !strings.Contains(issue.Message, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", issue.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDecodeYAML_MergeAndAliases(t *testing.T) {
+	type server struct {
+		Host string `yaml:"host"`
+	}
+	type cfg struct {
+		Defaults server `yaml:"defaults"`
+		Server   server `yaml:"server"`
+	}
+
+	var out cfg
+	err := DecodeYAML("test.yaml", []byte("defaults: &d\n  host: x\nserver:\n  <<: *d\n"), &out)
+	var yerr *YAMLError
+	if !errors.As(err, &yerr) {
+		t.Fatalf("error = %v, want *YAMLError", err)
+	}
+	var messages []string
+	for _, issue := range yerr.Issues {
+		messages = append(messages, issue.String())
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{"1:11: defaults: anchors (&d) are not allowed", "4:3: server: merge keys (<<) are not allowed"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("issues %q missing %q", joined, want)
+		}
+	}
+	if out.Server.Host != "" {
+		t.Error("nothing should be decoded when the shape is invalid")
+	}
+}
+
+func TestDecodeYAML_CollectsAllIssues(t *testing.T) {
+	issues := decodeIssues(t, "workers: x\nenabled: maybe\nnope: 1\n")
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues %v, want 3", len(issues), issues)
+	}
+
+	var cfg testConfig
+	err := DecodeYAML("/etc/app.yaml", []byte("workers: x\n"), &cfg)
+	want := "invalid config /etc/app.yaml:\n  /etc/app.yaml:1:10: workers: expected an integer, got \"x\""
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
+
+func TestDecodeYAML_Limits(t *testing.T) {
+	var cfg testConfig
+	if err := DecodeYAML("x", make([]byte, MaxYAMLBytes+1), &cfg); err == nil {
+		t.Error("oversized document should be rejected")
+	}
+	if err := DecodeYAML("x", []byte("workers: 1"), cfg); err == nil {
+		t.Error("non-pointer out should be rejected")
+	}
+}
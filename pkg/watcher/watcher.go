@@ -0,0 +1,617 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package watcher watches a directory tree and reports debounced,
+// coalesced batches of file changes.
+//
+// # Description
+//
+// Watcher wraps fsnotify with the behaviour every Aleutian consumer of
+// file events needs:
+//
+//   - Recursive watching, including directories created later
+//   - Ignore patterns matched against every path component
+//   - A debounce window: a batch is delivered once the tree has been
+//     quiet for the configured duration
+//   - Coalescing: repeated events for a path collapse to one, a rename
+//     and its matching create become one Rename event, and events for
+//     the contents of a renamed directory collapse into the directory's
+//     Rename event
+//
+// # Basic Usage
+//
+//	w, err := watcher.New(watcher.Config{Root: projectRoot})
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Close()
+//	return w.Run(ctx, func(events []watcher.Event) {
+//	    for _, ev := range events {
+//	        log.Printf("%s %s", ev.Op, ev.Path)
+//	    }
+//	})
+//
+// # Thread Safety
+//
+// Watcher and Ignore are safe for concurrent use. The handler passed to
+// Run is called from Run's goroutine, one batch at a time.
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Errors returned by the watcher package.
+var (
+	// ErrEmptyRoot indicates no root directory was configured.
+	ErrEmptyRoot = errors.New("watch root must not be empty")
+
+	// ErrClosed indicates the watcher was closed.
+	ErrClosed = errors.New("watcher is closed")
+
+	// ErrRunning indicates Run was called while another Run is active.
+	ErrRunning = errors.New("watcher is already running")
+)
+
+// DefaultDebounce is the debounce window used when Config.Debounce is zero.
+const DefaultDebounce = 100 * time.Millisecond
+
+// EditorTempPatterns match the scratch files editors write while saving.
+var EditorTempPatterns = []string{"*~", "*.swp", "*.swx", "*.tmp", ".#*"}
+
+// DefaultIgnore is used when Config.Ignore is nil.
+var DefaultIgnore = append([]string{".git", "node_modules", ".idea", "__pycache__"}, EditorTempPatterns...)
+
+// =============================================================================
+// Events
+// =============================================================================
+
+// Op is the kind of change reported for a path.
+type Op int
+
+const (
+	// Create indicates a file or directory was created.
+	Create Op = iota
+
+	// Write indicates a file was modified.
+	Write
+
+	// Remove indicates a file or directory was deleted or moved out of
+	// the watched tree.
+	Remove
+
+	// Rename indicates a file or directory moved within the watched tree.
+	Rename
+)
+
+// String returns the operation name.
+func (op Op) String() string {
+	switch op {
+	case Create:
+		return "create"
+	case Write:
+		return "write"
+	case Remove:
+		return "remove"
+	case Rename:
+		return "rename"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is one coalesced change.
+type Event struct {
+	// Path is the absolute path of the changed file or directory. For a
+	// Rename it is the new path.
+	Path string
+
+	// OldPath is the previous absolute path of a Rename, otherwise empty.
+	OldPath string
+
+	// Op is the kind of change.
+	Op Op
+
+	// IsDir is true if Path is a directory. Only known for paths that
+	// exist; always false for Remove.
+	IsDir bool
+
+	// Time is when the first event for this change was received.
+	Time time.Time
+}
+
+// =============================================================================
+// Ignore Patterns
+// =============================================================================
+
+// Ignore matches paths against a set of patterns.
+//
+// # Description
+//
+// A pattern is either a plain name or a filepath.Match glob. A path is
+// ignored if any of its components matches any pattern, so ".git"
+// ignores everything inside a .git directory and "*.swp" ignores swap
+// files anywhere.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+type Ignore struct {
+	mu       sync.RWMutex
+	patterns []string
+}
+
+// NewIgnore creates a matcher for the given patterns.
+func NewIgnore(patterns ...string) *Ignore {
+	return &Ignore{patterns: append([]string(nil), patterns...)}
+}
+
+// Add appends patterns.
+func (i *Ignore) Add(patterns ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.patterns = append(i.patterns, patterns...)
+}
+
+// Match reports whether a relative path is ignored.
+//
+// # Inputs
+//
+//   - rel: Path relative to the watch root, with either separator.
+//
+// # Outputs
+//
+//   - bool: True if any component of rel matches a pattern.
+func (i *Ignore) Match(rel string) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		for _, pattern := range i.patterns {
+			if part == pattern {
+				return true
+			}
+			if ok, _ := filepath.Match(pattern, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Watcher
+// =============================================================================
+
+// Config configures a Watcher.
+type Config struct {
+	// Root is the directory to watch recursively.
+	Root string
+
+	// Debounce is how long the tree must be quiet before a batch is
+	// delivered. Default: DefaultDebounce.
+	Debounce time.Duration
+
+	// Ignore lists patterns for paths that are neither watched nor
+	// reported (see Ignore). Nil selects DefaultIgnore; use an empty
+	// slice to ignore nothing.
+	Ignore []string
+
+	// BufferSize is the capacity of the underlying event queue. Zero uses
+	// the fsnotify default.
+	BufferSize int
+
+	// OnError is called with errors from the underlying watcher (may be
+	// nil). Watching continues after an error.
+	OnError func(error)
+}
+
+// Watcher reports debounced, coalesced changes under a root directory.
+//
+// # Description
+//
+// New starts watching immediately; events that arrive before Run are
+// queued. Run delivers batches until its context is canceled or Close is
+// called.
+//
+// # Limitations
+//
+//   - fsnotify does not expose rename cookies, so a rename is paired with
+//     the create that immediately follows it. A rename with no following
+//     create is reported as a Remove (the path left the tree).
+//   - A batch pending when Run returns is discarded.
+type Watcher struct {
+	cfg    Config
+	root   string
+	fs     *fsnotify.Watcher
+	ignore *Ignore
+
+	running   atomic.Bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a watcher and adds the root directory tree.
+//
+// # Inputs
+//
+//   - cfg: Configuration. Root must name an existing directory.
+//
+// # Outputs
+//
+//   - *Watcher: The watcher. Call Close when done.
+//   - error: Non-nil if the root could not be watched.
+func New(cfg Config) (*Watcher, error) {
+	if cfg.Root == "" {
+		return nil, ErrEmptyRoot
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", cfg.Root, err)
+	}
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = DefaultDebounce
+	}
+	if cfg.Ignore == nil {
+		cfg.Ignore = DefaultIgnore
+	}
+
+	var fsw *fsnotify.Watcher
+	if cfg.BufferSize > 0 {
+		fsw, err = fsnotify.NewBufferedWatcher(uint(cfg.BufferSize))
+	} else {
+		fsw, err = fsnotify.NewWatcher()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating watcher: %w", err)
+	}
+
+	w := &Watcher{
+		cfg:    cfg,
+		root:   root,
+		fs:     fsw,
+		ignore: NewIgnore(cfg.Ignore...),
+		done:   make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("watching %s: %w", root, err)
+	}
+	return w, nil
+}
+
+// Root returns the absolute watch root.
+func (w *Watcher) Root() string {
+	return w.root
+}
+
+// Ignore returns the watcher's ignore patterns. Patterns added later
+// apply to subsequent events and newly created directories.
+func (w *Watcher) Ignore() *Ignore {
+	return w.ignore
+}
+
+// Run delivers batches of changes to handler until ctx is canceled or
+// the watcher is closed.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - handler: Called with each non-empty batch. Must not be nil.
+//
+// # Outputs
+//
+//   - error: nil when ctx is canceled or Close is called, ErrRunning if
+//     another Run is active, ErrClosed if the watcher was already closed.
+func (w *Watcher) Run(ctx context.Context, handler func([]Event)) error {
+	select {
+	case <-w.done:
+		return ErrClosed
+	default:
+	}
+	if !w.running.CompareAndSwap(false, true) {
+		return ErrRunning
+	}
+	defer w.running.Store(false)
+
+	var pending []rawEvent
+	timer := time.NewTimer(w.cfg.Debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.done:
+			return nil
+
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod || w.ignored(ev.Name) {
+				continue
+			}
+			pending = append(pending, rawEvent{path: ev.Name, op: ev.Op, time: time.Now()})
+			timer.Reset(w.cfg.Debounce)
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			if w.cfg.OnError != nil {
+				w.cfg.OnError(err)
+			}
+
+		case <-timer.C:
+			batch := w.coalesce(pending)
+			pending = nil
+			if len(batch) > 0 {
+				handler(batch)
+			}
+		}
+	}
+}
+
+// Close stops watching and makes Run return. Safe to call more than once.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fs.Close()
+	})
+	return err
+}
+
+// Expand lists the files affected by a directory event.
+//
+// # Description
+//
+// A directory Rename is reported as one event; Expand turns it into a
+// Rename per file now under the new path, with OldPath set to where the
+// file used to be. File events are returned unchanged, and other
+// directory events expand to nothing, since their files are reported
+// individually.
+//
+// # Inputs
+//
+//   - ev: An event from a batch.
+//
+// # Outputs
+//
+//   - []Event: The file-level events.
+func (w *Watcher) Expand(ev Event) []Event {
+	if !ev.IsDir {
+		return []Event{ev}
+	}
+	if ev.Op != Rename {
+		return nil
+	}
+	files := w.filesIn(ev.Path)
+	out := make([]Event, 0, len(files))
+	for _, f := range files {
+		rel, err := filepath.Rel(ev.Path, f)
+		if err != nil {
+			continue
+		}
+		out = append(out, Event{
+			Path:    f,
+			OldPath: filepath.Join(ev.OldPath, rel),
+			Op:      Rename,
+			Time:    ev.Time,
+		})
+	}
+	return out
+}
+
+// ignored reports whether an absolute path under the root is ignored.
+// Paths outside the root are ignored.
+func (w *Watcher) ignored(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return true
+	}
+	return w.ignore.Match(rel)
+}
+
+// addTree watches dir and its non-ignored subdirectories.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != w.root && w.ignored(path) {
+			return filepath.SkipDir
+		}
+		return w.fs.Add(path)
+	})
+}
+
+// filesIn returns the non-ignored files already inside a new directory.
+// They may have been written before the directory was watched.
+func (w *Watcher) filesIn(dir string) []string {
+	var files []string
+	_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if w.ignored(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// unwatchTree drops watches on dir and everything under it.
+func (w *Watcher) unwatchTree(dir string) {
+	for _, path := range w.fs.WatchList() {
+		if isWithin(path, dir) {
+			_ = w.fs.Remove(path)
+		}
+	}
+}
+
+// =============================================================================
+// Coalescing
+// =============================================================================
+
+// rawEvent is an fsnotify event waiting for the debounce window to end.
+type rawEvent struct {
+	path string
+	op   fsnotify.Op
+	time time.Time
+}
+
+// coalesce turns one debounce window of raw events into a batch.
+//
+// Renames are paired with the create that follows them, new and renamed
+// directories are watched, events under a renamed directory's old path
+// are folded into its Rename, and repeated events for a path collapse to
+// one, in order of first appearance.
+func (w *Watcher) coalesce(raw []rawEvent) []Event {
+	events := make([]Event, 0, len(raw))
+	var movedDirs []string
+
+	for i := 0; i < len(raw); i++ {
+		ev := raw[i]
+		switch {
+		case ev.op.Has(fsnotify.Rename):
+			if i+1 < len(raw) && raw[i+1].op.Has(fsnotify.Create) && raw[i+1].path != ev.path {
+				to := raw[i+1]
+				i++
+				isDir := isDirectory(to.path)
+				if isDir {
+					// The old watches follow the moved inodes; drop them
+					// before re-adding the tree so the new paths get
+					// fresh watches.
+					w.unwatchTree(ev.path)
+					_ = w.addTree(to.path)
+					movedDirs = append(movedDirs, ev.path)
+				}
+				events = append(events, Event{Path: to.path, OldPath: ev.path, Op: Rename, IsDir: isDir, Time: ev.time})
+				continue
+			}
+			events = append(events, Event{Path: ev.path, Op: Remove, Time: ev.time})
+
+		case ev.op.Has(fsnotify.Create):
+			isDir := isDirectory(ev.path)
+			events = append(events, Event{Path: ev.path, Op: Create, IsDir: isDir, Time: ev.time})
+			if isDir {
+				_ = w.addTree(ev.path)
+				for _, f := range w.filesIn(ev.path) {
+					events = append(events, Event{Path: f, Op: Create, Time: ev.time})
+				}
+			}
+
+		case ev.op.Has(fsnotify.Remove):
+			events = append(events, Event{Path: ev.path, Op: Remove, Time: ev.time})
+
+		case ev.op.Has(fsnotify.Write):
+			events = append(events, Event{Path: ev.path, Op: Write, Time: ev.time})
+		}
+	}
+
+	return dedupe(events, movedDirs)
+}
+
+// dedupe merges events per path and drops events reported under the old
+// path of a renamed directory.
+func dedupe(events []Event, movedDirs []string) []Event {
+	index := make(map[string]int, len(events))
+	out := make([]Event, 0, len(events))
+	dropped := make(map[int]bool)
+
+	for _, ev := range events {
+		if ev.Op != Rename && underAny(ev.Path, movedDirs) {
+			continue
+		}
+
+		i, seen := index[ev.Path]
+		if !seen || dropped[i] {
+			index[ev.Path] = len(out)
+			out = append(out, ev)
+			continue
+		}
+
+		prev := &out[i]
+		switch {
+		case (prev.Op == Create || prev.Op == Rename) && ev.Op == Write:
+			// Still a new or moved file.
+		case prev.Op == Create && ev.Op == Remove:
+			dropped[i] = true // transient file
+		case prev.Op == Remove && ev.Op == Create:
+			prev.Op = Write // replaced in place, e.g. by an atomic save
+			prev.IsDir = ev.IsDir
+		default:
+			t := prev.Time
+			*prev = ev
+			prev.Time = t
+		}
+	}
+
+	if len(dropped) == 0 {
+		return out
+	}
+	kept := out[:0]
+	for i, ev := range out {
+		if !dropped[i] {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// underAny reports whether path is one of dirs or inside one of them.
+func underAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if isWithin(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// isDirectory reports whether path exists and is a directory.
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIgnore_Match(t *testing.T) {
+	ig := NewIgnore(DefaultIgnore...)
+	ig.Add("vendor")
+
+	tests := map[string]bool{
+		"main.go":                     false,
+		"pkg/server.go":               false,
+		".":                           false,
+		".git/HEAD":                   true,
+		"vendor/x/y.go":               true,
+		"web/node_modules/a/index.js": true,
+		"main.go~":                    true,
+		"pkg/.main.go.swp":            true,
+		".#main.go":                   true,
+		"gitignore.go":                false,
+	}
+	for path, want := range tests {
+		if got := ig.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	ev := func(path string, op Op) Event { return Event{Path: path, Op: op} }
+
+	got := dedupe([]Event{
+		ev("/r/a.go", Create),
+		ev("/r/a.go", Write),
+		ev("/r/b.go", Write),
+		ev("/r/b.go", Write),
+		ev("/r/tmp", Create),
+		ev("/r/tmp", Remove),
+		ev("/r/c.go", Remove),
+		ev("/r/c.go", Create),
+		{Path: "/r/new", OldPath: "/r/old", Op: Rename, IsDir: true},
+		ev("/r/old/x.go", Write),
+		ev("/r/old", Remove),
+	}, []string{"/r/old"})
+
+	want := []Event{
+		ev("/r/a.go", Create),
+		ev("/r/b.go", Write),
+		ev("/r/c.go", Write),
+		{Path: "/r/new", OldPath: "/r/old", Op: Rename, IsDir: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("dedupe = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// collect runs w until a batch satisfies done or the deadline passes,
+// and returns every event seen.
+func collect(t *testing.T, w *Watcher, act func(), done func([]Event) bool) []Event {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	batches := make(chan []Event, 16)
+	stopped := make(chan error, 1)
+	go func() { stopped <- w.Run(ctx, func(b []Event) { batches <- b }) }()
+	defer func() {
+		cancel()
+		if err := <-stopped; err != nil {
+			t.Errorf("Run = %v", err)
+		}
+	}()
+	act()
+
+	var all []Event
+	for {
+		select {
+		case b := <-batches:
+			all = append(all, b...)
+			if done(all) {
+				return all
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out; events so far: %+v", all)
+		}
+	}
+}
+
+func find(events []Event, path string) (Event, bool) {
+	for _, ev := range events {
+		if ev.Path == path {
+			return ev, true
+		}
+	}
+	return Event{}, false
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	mustWrite := func(rel string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, rel), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "old", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite("old/deep/f.go")
+	mustWrite("keep.go")
+
+	w, err := New(Config{Root: root, Debounce: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer w.Close()
+
+	t.Run("writes and ignores", func(t *testing.T) {
+		events := collect(t, w, func() {
+			mustWrite("a.go")
+			mustWrite("a.go")
+			mustWrite(".git/index")
+			mustWrite("a.go.swp")
+		}, func(all []Event) bool {
+			_, ok := find(all, filepath.Join(root, "a.go"))
+			return ok
+		})
+		for _, ev := range events {
+			if ev.Path != filepath.Join(root, "a.go") {
+				t.Errorf("unexpected event %+v", ev)
+			}
+		}
+	})
+
+	t.Run("file rename", func(t *testing.T) {
+		from, to := filepath.Join(root, "keep.go"), filepath.Join(root, "moved.go")
+		events := collect(t, w, func() {
+			if err := os.Rename(from, to); err != nil {
+				t.Fatal(err)
+			}
+		}, func(all []Event) bool {
+			_, ok := find(all, to)
+			return ok
+		})
+		ev, _ := find(events, to)
+		if ev.Op != Rename || ev.OldPath != from {
+			t.Errorf("event = %+v, want rename from %s", ev, from)
+		}
+		if _, ok := find(events, from); ok {
+			t.Errorf("old path reported separately: %+v", events)
+		}
+	})
+
+	t.Run("directory rename coalesces", func(t *testing.T) {
+		from, to := filepath.Join(root, "old"), filepath.Join(root, "new")
+		events := collect(t, w, func() {
+			if err := os.Rename(from, to); err != nil {
+				t.Fatal(err)
+			}
+		}, func(all []Event) bool {
+			_, ok := find(all, to)
+			return ok
+		})
+		if len(events) != 1 || events[0].Op != Rename || !events[0].IsDir || events[0].OldPath != from {
+			t.Fatalf("events = %+v, want one directory rename", events)
+		}
+		files := w.Expand(events[0])
+		wantOld := filepath.Join(from, "deep", "f.go")
+		if len(files) != 1 || files[0].Path != filepath.Join(to, "deep", "f.go") || files[0].OldPath != wantOld {
+			t.Errorf("Expand = %+v, want deep/f.go renamed from %s", files, wantOld)
+		}
+
+		// The moved tree is watched under its new name.
+		target := filepath.Join(to, "deep", "f.go")
+		collect(t, w, func() { mustWrite("new/deep/f.go") }, func(all []Event) bool {
+			_, ok := find(all, target)
+			return ok
+		})
+	})
+
+	t.Run("new directory contents", func(t *testing.T) {
+		dir := filepath.Join(root, "made")
+		file := filepath.Join(dir, "inner.go")
+		collect(t, w, func() {
+			if err := os.Mkdir(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}, func(all []Event) bool {
+			_, ok := find(all, file)
+			return ok
+		})
+	})
+
+	w.Close()
+	if err := w.Run(context.Background(), func([]Event) {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Run after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestNew_EmptyRoot(t *testing.T) {
+	if _, err := New(Config{}); !errors.Is(err, ErrEmptyRoot) {
+		t.Errorf("New = %v, want ErrEmptyRoot", err)
+	}
+}
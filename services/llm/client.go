@@ -159,6 +159,10 @@ type GenerationParams struct {
 	// If nil or 0, uses Ollama's default (typically 2048-4096).
 	NumCtx *int `json:"num_ctx,omitempty"`
 
+	// Seed fixes the sampling seed so the same request and temperature
+	// reproduce the same output. If nil, the backend picks a random seed.
+	Seed *int `json:"seed,omitempty"`
+
 	// ToolChoice controls tool selection behavior when tools are provided.
 	// If nil, defaults to "auto" (model decides).
 	// Used to force tool usage at the API level for analytical queries.
@@ -116,6 +116,11 @@ type ModelWarmupConfig struct {
 	// MUST be set to prevent Ollama from using default 4096.
 	// Recommended: 16384 for router, 65536 for main agent.
 	NumCtx int
+
+	// Pull downloads the model before warming it if Ollama does not have
+	// it. The download is bounded by the context passed to WarmModels,
+	// not by MaxWaitMs.
+	Pull bool
 }
 
 // NewMultiModelManager creates a new MultiModelManager.
@@ -197,6 +202,11 @@ func (m *MultiModelManager) WarmModels(ctx context.Context, configs []ModelWarmu
 
 	// Load models sequentially to avoid VRAM contention
 	for _, cfg := range sorted {
+		if cfg.Pull {
+			if _, err := m.EnsureModel(ctx, cfg.Model, nil); err != nil {
+				return fmt.Errorf("pulling model %s: %w", cfg.Model, err)
+			}
+		}
 		if err := m.WarmModel(ctx, cfg.Model, cfg.KeepAlive, cfg.NumCtx); err != nil {
 			m.logger.Error("Failed to warm model",
 				slog.String("model", cfg.Model),
@@ -215,6 +225,67 @@ func (m *MultiModelManager) WarmModels(ctx context.Context, configs []ModelWarmu
 	return nil
 }
 
+// EnsureModel pulls a model if Ollama does not have it yet.
+//
+// # Description
+//
+// Delegates to OllamaClient.EnsureModel on the manager's server. When
+// progress is nil, download progress is logged at every 10% of each
+// layer.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation. Must allow for a full download.
+//   - model: Model name (e.g., "granite4:micro-h").
+//   - progress: Receives pull progress. May be nil.
+//
+// # Outputs
+//
+//   - bool: True if the model was pulled.
+//   - error: Non-nil if the check or the pull failed.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (m *MultiModelManager) EnsureModel(ctx context.Context, model string, progress PullProgressFunc) (bool, error) {
+	if progress == nil {
+		progress = LogPullProgress(m.logger, model)
+	}
+	client := &OllamaClient{httpClient: m.httpClient, baseURL: m.baseURL, model: model}
+	return client.EnsureModel(ctx, model, progress)
+}
+
+// LogPullProgress returns a PullProgressFunc that logs status changes and
+// every 10% of each layer's download.
+//
+// # Inputs
+//
+//   - logger: Destination of the log lines. Uses slog.Default() if nil.
+//   - model: Model name included in each line.
+//
+// # Outputs
+//
+//   - PullProgressFunc: The logging callback. Not safe for concurrent use.
+func LogPullProgress(logger *slog.Logger, model string) PullProgressFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	lastStatus := ""
+	lastDecile := -1
+	return func(p PullProgress) {
+		decile := int(p.Percent()) / 10
+		if p.Status == lastStatus && decile == lastDecile {
+			return
+		}
+		lastStatus, lastDecile = p.Status, decile
+		logger.Info("Pulling model",
+			slog.String("model", model),
+			slog.String("status", p.Status),
+			slog.Float64("percent", p.Percent()),
+		)
+	}
+}
+
 // WarmModel loads a single model into VRAM with keep_alive.
 //
 // # Description
@@ -223,9 +223,11 @@ func (f *OllamaFunctionCall) ArgumentsString() string {
 }
 
 type ollamaChatResponse struct {
-	Message   ollamaChatMessage `json:"message"`
-	CreatedAt string            `json:"created_at"`
-	Done      bool              `json:"done"`
+	Message         ollamaChatMessage `json:"message"`
+	CreatedAt       string            `json:"created_at"`
+	Done            bool              `json:"done"`
+	PromptEvalCount int               `json:"prompt_eval_count,omitempty"`
+	EvalCount       int               `json:"eval_count,omitempty"`
 }
 
 // ollamaChatMessage extends datatypes.Message to include tool calls.
@@ -433,6 +435,9 @@ func (o *OllamaClient) Generate(ctx context.Context, prompt string,
 	if len(params.Stop) > 0 {
 		options["stop"] = params.Stop
 	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
 	payload := ollamaGenerateRequest{
 		Model:   o.model,
 		Prompt:  prompt,
@@ -544,6 +549,9 @@ func (o *OllamaClient) Chat(ctx context.Context, messages []datatypes.Message,
 	if params.NumCtx != nil && *params.NumCtx > 0 {
 		options["num_ctx"] = *params.NumCtx
 	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
 
 	// Use ModelOverride if provided, otherwise use client's default model
 	model := o.model
@@ -618,6 +626,14 @@ type ChatWithToolsResult struct {
 	// StopReason indicates why generation stopped.
 	// Values: "end", "tool_use"
 	StopReason string
+
+	// PromptTokens is the prompt length as tokenized by the model
+	// (Ollama's prompt_eval_count). Zero if Ollama did not report it,
+	// for example when the prompt was served from its cache.
+	PromptTokens int
+
+	// CompletionTokens is the number of generated tokens (eval_count).
+	CompletionTokens int
 }
 
 // ChatWithTools sends a chat request with tools and returns both content and tool calls.
@@ -701,6 +717,9 @@ func (o *OllamaClient) ChatWithTools(ctx context.Context, messages []datatypes.M
 	if params.NumCtx != nil && *params.NumCtx > 0 {
 		options["num_ctx"] = *params.NumCtx
 	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
 
 	// Use ModelOverride if provided, otherwise use client's default model
 	model := o.model
@@ -770,8 +789,10 @@ func (o *OllamaClient) ChatWithTools(ctx context.Context, messages []datatypes.M
 	}
 
 	result := &ChatWithToolsResult{
-		Content:   ollamaResp.Message.Content,
-		ToolCalls: ollamaResp.Message.ToolCalls,
+		Content:          ollamaResp.Message.Content,
+		ToolCalls:        ollamaResp.Message.ToolCalls,
+		PromptTokens:     ollamaResp.PromptEvalCount,
+		CompletionTokens: ollamaResp.EvalCount,
 	}
 
 	// Determine stop reason
@@ -1569,6 +1590,9 @@ func (o *OllamaClient) buildStreamOptions(params GenerationParams) map[string]in
 	if params.NumCtx != nil && *params.NumCtx > 0 {
 		options["num_ctx"] = *params.NumCtx
 	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
 
 	return options
 }
@@ -0,0 +1,450 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ErrModelNotFound is returned when Ollama does not have the requested model.
+var ErrModelNotFound = errors.New("ollama model not found")
+
+// maxPullLine bounds one line of the /api/pull progress stream.
+const maxPullLine = 1 << 20
+
+// =============================================================================
+// Model Lifecycle Types
+// =============================================================================
+
+// OllamaModel is a model available on the Ollama server.
+//
+// # Description
+//
+// One entry of the /api/tags listing.
+type OllamaModel struct {
+	// Name is the model name including its tag (e.g., "granite4:micro-h").
+	Name string `json:"name"`
+
+	// Model is the model identifier; usually the same as Name.
+	Model string `json:"model"`
+
+	// ModifiedAt is when the model was last pulled or created.
+	ModifiedAt time.Time `json:"modified_at"`
+
+	// Size is the model's size on disk in bytes.
+	Size int64 `json:"size"`
+
+	// Digest is the SHA256 digest of the model manifest.
+	Digest string `json:"digest"`
+
+	// Details describes the model's architecture and quantization.
+	Details OllamaModelDetails `json:"details"`
+}
+
+// OllamaModelDetails describes a model's architecture and quantization.
+type OllamaModelDetails struct {
+	ParentModel       string   `json:"parent_model,omitempty"`
+	Format            string   `json:"format,omitempty"`
+	Family            string   `json:"family,omitempty"`
+	Families          []string `json:"families,omitempty"`
+	ParameterSize     string   `json:"parameter_size,omitempty"`
+	QuantizationLevel string   `json:"quantization_level,omitempty"`
+}
+
+// OllamaModelInfo is the detailed description of a model from /api/show.
+type OllamaModelInfo struct {
+	// Modelfile is the Modelfile the model was built from.
+	Modelfile string `json:"modelfile,omitempty"`
+
+	// Parameters are the default runtime parameters, one per line.
+	Parameters string `json:"parameters,omitempty"`
+
+	// Template is the prompt template.
+	Template string `json:"template,omitempty"`
+
+	// License is the model's license text.
+	License string `json:"license,omitempty"`
+
+	// Details describes the model's architecture and quantization.
+	Details OllamaModelDetails `json:"details"`
+
+	// ModelInfo holds architecture metadata such as
+	// "llama.context_length".
+	ModelInfo map[string]any `json:"model_info,omitempty"`
+
+	// Capabilities lists features such as "completion", "tools" and
+	// "thinking". Empty on servers that predate the field.
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// HasCapability reports whether the model advertises a capability.
+func (i *OllamaModelInfo) HasCapability(capability string) bool {
+	for _, c := range i.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// PullProgress is one update of a model download.
+//
+// # Description
+//
+// Ollama reports a status for each step ("pulling manifest",
+// "verifying sha256 digest", "success") and byte counts while a layer
+// downloads.
+type PullProgress struct {
+	// Status is Ollama's description of the current step.
+	Status string `json:"status"`
+
+	// Digest identifies the layer being downloaded, if any.
+	Digest string `json:"digest,omitempty"`
+
+	// Total is the layer size in bytes, or 0 outside layer downloads.
+	Total int64 `json:"total,omitempty"`
+
+	// Completed is the number of layer bytes downloaded so far.
+	Completed int64 `json:"completed,omitempty"`
+}
+
+// Percent returns the completed share of the current layer, 0 to 100.
+func (p PullProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return 100 * float64(p.Completed) / float64(p.Total)
+}
+
+// PullProgressFunc receives progress updates during Pull.
+//
+// # Description
+//
+// Called synchronously from the goroutine running Pull, so it should
+// return quickly.
+type PullProgressFunc func(PullProgress)
+
+// pullLine is one line of the /api/pull stream.
+type pullLine struct {
+	PullProgress
+	Error string `json:"error,omitempty"`
+}
+
+// =============================================================================
+// Model Lifecycle Methods
+// =============================================================================
+
+// List returns the models available on the Ollama server.
+//
+// # Description
+//
+// Queries /api/tags. The result is not cached.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - []OllamaModel: The local models. Empty if none are pulled.
+//   - error: Non-nil if Ollama is unreachable or returns an error.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (o *OllamaClient) List(ctx context.Context) ([]OllamaModel, error) {
+	ctx, span := tracer.Start(ctx, "OllamaClient.List")
+	defer span.End()
+
+	var resp struct {
+		Models []OllamaModel `json:"models"`
+	}
+	if err := o.modelRequest(ctx, http.MethodGet, "/api/tags", nil, &resp); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("listing Ollama models: %w", err)
+	}
+	span.SetAttributes(attribute.Int("llm.model_count", len(resp.Models)))
+	return resp.Models, nil
+}
+
+// Show returns the details of a model.
+//
+// # Description
+//
+// Queries /api/show for the model's Modelfile, parameters, template,
+// architecture metadata and capabilities.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - model: Model name (e.g., "granite4:micro-h").
+//
+// # Outputs
+//
+//   - *OllamaModelInfo: The model details.
+//   - error: Wraps ErrModelNotFound if the model is not pulled.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (o *OllamaClient) Show(ctx context.Context, model string) (*OllamaModelInfo, error) {
+	ctx, span := tracer.Start(ctx, "OllamaClient.Show")
+	defer span.End()
+	span.SetAttributes(attribute.String("llm.model", model))
+
+	var info OllamaModelInfo
+	if err := o.modelRequest(ctx, http.MethodPost, "/api/show", map[string]string{"model": model}, &info); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("showing model %s: %w", model, err)
+	}
+	return &info, nil
+}
+
+// Delete removes a model and its unused layers from the Ollama server.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - model: Model name (e.g., "granite4:micro-h").
+//
+// # Outputs
+//
+//   - error: Wraps ErrModelNotFound if the model is not pulled.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (o *OllamaClient) Delete(ctx context.Context, model string) error {
+	ctx, span := tracer.Start(ctx, "OllamaClient.Delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("llm.model", model))
+
+	if err := o.modelRequest(ctx, http.MethodDelete, "/api/delete", map[string]string{"model": model}, nil); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("deleting model %s: %w", model, err)
+	}
+	slog.Info("Deleted Ollama model", "model", model)
+	return nil
+}
+
+// Pull downloads a model to the Ollama server.
+//
+// # Description
+//
+// Streams /api/pull and calls progress for each update. The download is
+// bounded only by ctx, not by the client's request timeout, since large
+// models take far longer than a chat request. Cancelling ctx stops the
+// download; Ollama keeps the layers fetched so far and resumes from them
+// on the next pull.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - model: Model name (e.g., "granite4:micro-h").
+//   - progress: Receives progress updates. May be nil.
+//
+// # Outputs
+//
+//   - error: Non-nil if the pull fails, is cancelled, or ends without
+//     Ollama reporting success. Wraps ErrModelNotFound if the registry
+//     does not have the model.
+//
+// # Example
+//
+//	err := client.Pull(ctx, "granite4:micro-h", func(p llm.PullProgress) {
+//	    fmt.Printf("%s %.0f%%\n", p.Status, p.Percent())
+//	})
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (o *OllamaClient) Pull(ctx context.Context, model string, progress PullProgressFunc) error {
+	ctx, span := tracer.Start(ctx, "OllamaClient.Pull")
+	defer span.End()
+	span.SetAttributes(attribute.String("llm.model", model))
+
+	err := o.pull(ctx, model, progress)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("pulling model %s: %w", model, err)
+	}
+	slog.Info("Pulled Ollama model", "model", model)
+	return nil
+}
+
+// pull runs the /api/pull stream.
+func (o *OllamaClient) pull(ctx context.Context, model string, progress PullProgressFunc) error {
+	body, err := json.Marshal(map[string]any{"model": model, "stream": true})
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Same transport, no overall timeout: ctx bounds the download.
+	client := &http.Client{Transport: o.httpClient.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxPullLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var update pullLine
+		if err := json.Unmarshal(line, &update); err != nil {
+			return fmt.Errorf("parsing progress %q: %w", line, err)
+		}
+		if update.Error != "" {
+			return classifyOllamaError(update.Error)
+		}
+		if progress != nil {
+			progress(update.PullProgress)
+		}
+		if update.Status == "success" {
+			return nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	return errors.New("progress stream ended before success")
+}
+
+// EnsureModel pulls a model if the Ollama server does not have it.
+//
+// # Description
+//
+// Checks the model with Show and pulls it only if Ollama reports it
+// missing, so callers such as warmup can run it unconditionally.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation. Must allow for a full download.
+//   - model: Model name (e.g., "granite4:micro-h").
+//   - progress: Receives pull progress. May be nil.
+//
+// # Outputs
+//
+//   - bool: True if the model was pulled.
+//   - error: Non-nil if the check or the pull failed.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (o *OllamaClient) EnsureModel(ctx context.Context, model string, progress PullProgressFunc) (bool, error) {
+	_, err := o.Show(ctx, model)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrModelNotFound) {
+		return false, err
+	}
+	slog.Info("Ollama model missing, pulling", "model", model)
+	if err := o.Pull(ctx, model, progress); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// modelRequest sends a JSON request to a model management endpoint and
+// decodes the response into out, unless out is nil.
+func (o *OllamaClient) modelRequest(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, o.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
+
+// statusError converts a failed Ollama response into an error.
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+		message = errResp.Error
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", message, ErrModelNotFound)
+	}
+	return fmt.Errorf("Ollama failed with status %d: %s", resp.StatusCode, message)
+}
+
+// classifyOllamaError converts an error message reported inside a
+// successful response into an error.
+func classifyOllamaError(message string) error {
+	lower := strings.ToLower(message)
+	if strings.Contains(lower, "file does not exist") || strings.Contains(lower, "not found") {
+		return fmt.Errorf("%s: %w", message, ErrModelNotFound)
+	}
+	return errors.New(message)
+}
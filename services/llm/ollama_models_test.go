@@ -0,0 +1,244 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// fakeOllamaRegistry is an in-memory Ollama server for the model
+// management endpoints.
+type fakeOllamaRegistry struct {
+	mu     sync.Mutex
+	local  map[string]bool
+	remote map[string]bool
+	pulls  int
+}
+
+// newFakeOllamaRegistry starts a server that has the local models and can
+// pull the remote ones.
+func newFakeOllamaRegistry(t *testing.T, local, remote []string) (*fakeOllamaRegistry, *httptest.Server) {
+	t.Helper()
+	f := &fakeOllamaRegistry{local: map[string]bool{}, remote: map[string]bool{}}
+	for _, m := range local {
+		f.local[m] = true
+	}
+	for _, m := range remote {
+		f.remote[m] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tags", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var models []OllamaModel
+		for name := range f.local {
+			models = append(models, OllamaModel{Name: name, Model: name, Size: 42})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"models": models})
+	})
+	mux.HandleFunc("POST /api/show", func(w http.ResponseWriter, r *http.Request) {
+		name := f.decodeModel(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.local[name] {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":"model '%s' not found"}`, name)
+			return
+		}
+		fmt.Fprint(w, `{"template":"{{ .Prompt }}","details":{"family":"granite"},"capabilities":["completion","tools"]}`)
+	})
+	mux.HandleFunc("DELETE /api/delete", func(w http.ResponseWriter, r *http.Request) {
+		name := f.decodeModel(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.local[name] {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":"model '%s' not found"}`, name)
+			return
+		}
+		delete(f.local, name)
+	})
+	mux.HandleFunc("POST /api/pull", func(w http.ResponseWriter, r *http.Request) {
+		name := f.decodeModel(r)
+		f.mu.Lock()
+		f.pulls++
+		known := f.remote[name]
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		if !known {
+			fmt.Fprintln(w, `{"error":"pull model manifest: file does not exist"}`)
+			return
+		}
+		for _, done := range []int{0, 50, 100} {
+			fmt.Fprintf(w, `{"status":"pulling abc123","digest":"sha256:abc123","total":100,"completed":%d}`+"\n", done)
+		}
+		fmt.Fprintln(w, `{"status":"success"}`)
+		f.mu.Lock()
+		f.local[name] = true
+		f.mu.Unlock()
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// decodeModel reads the model name from a request body.
+func (f *fakeOllamaRegistry) decodeModel(r *http.Request) string {
+	var body struct {
+		Model string `json:"model"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	return body.Model
+}
+
+// =============================================================================
+// Model Lifecycle Tests
+// =============================================================================
+
+// TestOllamaClient_ListShowDelete tests the model management round trip.
+//
+// # Description
+//
+// Verifies that List, Show and Delete reflect the server's models and that
+// missing models are reported as ErrModelNotFound.
+func TestOllamaClient_ListShowDelete(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeOllamaRegistry(t, []string{"granite4:micro-h"}, nil)
+	client := newTestOllamaClient(server.URL, "granite4:micro-h")
+	ctx := context.Background()
+
+	models, err := client.List(ctx)
+	if err != nil || len(models) != 1 || models[0].Name != "granite4:micro-h" || models[0].Size != 42 {
+		t.Fatalf("List = %+v, %v", models, err)
+	}
+
+	info, err := client.Show(ctx, "granite4:micro-h")
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if info.Details.Family != "granite" || !info.HasCapability("tools") || info.HasCapability("vision") {
+		t.Errorf("Show = %+v", info)
+	}
+
+	if err := client.Delete(ctx, "granite4:micro-h"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := client.Show(ctx, "granite4:micro-h"); !errors.Is(err, ErrModelNotFound) {
+		t.Errorf("Show after delete: expected ErrModelNotFound, got %v", err)
+	}
+	if err := client.Delete(ctx, "granite4:micro-h"); !errors.Is(err, ErrModelNotFound) {
+		t.Errorf("second Delete: expected ErrModelNotFound, got %v", err)
+	}
+}
+
+// TestOllamaClient_Pull tests streaming pull progress.
+//
+// # Description
+//
+// Verifies that every progress line reaches the callback in order and that
+// a registry error is reported as ErrModelNotFound.
+func TestOllamaClient_Pull(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeOllamaRegistry(t, nil, []string{"granite4:micro-h"})
+	client := newTestOllamaClient(server.URL, "")
+
+	var updates []PullProgress
+	err := client.Pull(context.Background(), "granite4:micro-h", func(p PullProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if len(updates) != 5 || updates[0].Status != "pulling manifest" || updates[4].Status != "success" {
+		t.Fatalf("updates = %+v", updates)
+	}
+	if updates[2].Percent() != 50 || updates[2].Digest != "sha256:abc123" {
+		t.Errorf("layer progress = %+v (%.0f%%)", updates[2], updates[2].Percent())
+	}
+
+	if err := client.Pull(context.Background(), "no-such-model", nil); !errors.Is(err, ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound, got %v", err)
+	}
+}
+
+// TestOllamaClient_Pull_ContextCancellation tests cancelling a download.
+//
+// # Description
+//
+// Verifies that a pull stops when its context is cancelled, even though
+// the server keeps the stream open.
+func TestOllamaClient_Pull_ContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := newMockOllamaServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	client := newTestOllamaClient(server.URL, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := client.Pull(ctx, "granite4:micro-h", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestOllamaClient_EnsureModel tests pull-if-missing.
+//
+// # Description
+//
+// Verifies that EnsureModel pulls a missing model once and leaves a
+// present model alone.
+func TestOllamaClient_EnsureModel(t *testing.T) {
+	t.Parallel()
+
+	registry, server := newFakeOllamaRegistry(t, nil, []string{"granite4:micro-h"})
+	client := newTestOllamaClient(server.URL, "")
+	ctx := context.Background()
+
+	pulled, err := client.EnsureModel(ctx, "granite4:micro-h", nil)
+	if err != nil || !pulled {
+		t.Fatalf("first EnsureModel = %v, %v; want a pull", pulled, err)
+	}
+	pulled, err = client.EnsureModel(ctx, "granite4:micro-h", nil)
+	if err != nil || pulled {
+		t.Fatalf("second EnsureModel = %v, %v; want no pull", pulled, err)
+	}
+	if registry.pulls != 1 {
+		t.Errorf("pulls = %d, want 1", registry.pulls)
+	}
+}
@@ -58,6 +58,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
 	"github.com/AleutianAI/AleutianFOSS/pkg/extensions"
 	"github.com/AleutianAI/AleutianFOSS/services/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/datatypes"
@@ -554,7 +555,8 @@ func (s *service) initTracer() (func(context.Context), error) {
 	}
 
 	res, err := resource.New(ctx,
-		resource.WithAttributes(semconv.ServiceNameKey.String("orchestrator-service")))
+		resource.WithAttributes(semconv.ServiceNameKey.String("orchestrator-service")),
+		resource.WithAttributes(buildinfo.Get().Attributes()...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
@@ -11,13 +11,16 @@
 package routes
 
 import (
+	"log/slog"
 	"net/http"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
 	"github.com/AleutianAI/AleutianFOSS/pkg/extensions"
 	"github.com/AleutianAI/AleutianFOSS/services/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/handlers"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/middleware"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/services"
+	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/status"
 	"github.com/AleutianAI/AleutianFOSS/services/policy_engine"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -43,6 +46,8 @@ import (
 // # Endpoints
 //
 //   - GET /health: Health check
+//   - GET /v1/orchestrator/version: Build of the running binary
+//   - GET /status: Aggregated health of the whole stack (JSON or HTML)
 //   - POST /v1/chat/direct: Direct LLM chat (always available)
 //   - POST /v1/chat/rag: Conversational RAG (requires Weaviate)
 //   - And more (see route registration below)
@@ -58,6 +63,12 @@ func SetupRoutes(router *gin.Engine, client *weaviate.Client, globalLLMClient ll
 	policyEngine *policy_engine.PolicyEngine, opts extensions.ServiceOptions) {
 
 	router.GET("/health", handlers.HealthCheck)
+	router.GET("/v1/orchestrator/version", gin.WrapH(buildinfo.Handler("orchestrator")))
+	if aggregator, err := status.NewAggregator(status.DefaultServices(), status.DefaultConfig()); err != nil {
+		slog.Warn("Stack status endpoint disabled", "error", err)
+	} else {
+		router.GET("/status", status.Handler(aggregator))
+	}
 	router.GET("/metrics", gin.WrapH(promhttp.Handler())) // Prometheus metrics
 	router.StaticFS("/ui", http.Dir("/app/ui"))
 
@@ -66,6 +66,7 @@ func TestSetupRoutes_WithoutWeaviateClient(t *testing.T) {
 		path   string
 	}{
 		{"GET", "/health"},
+		{"GET", "/status"},
 		{"GET", "/metrics"},
 		{"GET", "/chat"},
 		{"POST", "/v1/chat/direct"},
@@ -217,6 +218,7 @@ func TestSetupRoutes_RouteCountWithoutWeaviate(t *testing.T) {
 
 	// Expected core routes when Weaviate is not available:
 	// - GET /health
+	// - GET /status
 	// - GET /metrics
 	// - GET /ui/* (StaticFS)
 	// - GET /chat
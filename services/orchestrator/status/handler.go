@@ -0,0 +1,98 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package status
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageTemplate renders a Report as a self-refreshing HTML page.
+var pageTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="10">
+<title>Aleutian stack status: {{.State}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.4rem 0.8rem; border-bottom: 1px solid #ddd; text-align: left; }
+.up { color: #1a7f37; } .degraded { color: #9a6700; } .down { color: #cf222e; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>Stack status: <span class="{{.State}}">{{.State}}</span></h1>
+<p class="muted">Checked {{.CheckedAt.Format "2006-01-02 15:04:05 UTC"}}. Services are listed after their dependencies.</p>
+<table>
+<tr><th>Service</th><th>State</th><th>Latency</th><th>Depends on</th><th>Details</th></tr>
+{{range .Services}}<tr>
+<td>{{.Name}}{{if .Optional}} <span class="muted">(optional)</span>{{end}}</td>
+<td class="{{.State}}">{{.State}}</td>
+<td>{{if .URL}}{{printf "%.1f" .LatencyMs}} ms{{end}}</td>
+<td>{{range $i, $d := .DependsOn}}{{if $i}}, {{end}}{{$d}}{{end}}</td>
+<td>{{if .Cause}}{{.Cause}} is not up. {{end}}{{.Error}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// Handler serves the stack status.
+//
+// # Description
+//
+// Returns the aggregator's report as JSON, or as an HTML page when the
+// client asks for ?format=html or prefers text/html (a browser). The status
+// code is 503 when the stack is down and 200 otherwise, so the endpoint can
+// also back a load balancer or compose health check.
+//
+// # Inputs
+//
+//   - aggregator: The aggregator to report on. Must not be nil.
+//
+// # Outputs
+//
+//   - gin.HandlerFunc: The handler for GET /status.
+func Handler(aggregator *Aggregator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := aggregator.Check(c.Request.Context())
+
+		code := http.StatusOK
+		if report.State == StateDown {
+			code = http.StatusServiceUnavailable
+		}
+
+		if wantsHTML(c) {
+			c.Status(code)
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			if err := pageTemplate.Execute(c.Writer, report); err != nil {
+				_ = c.Error(err)
+			}
+			return
+		}
+		c.JSON(code, report)
+	}
+}
+
+// wantsHTML reports whether the request asks for the HTML page.
+func wantsHTML(c *gin.Context) bool {
+	switch c.Query("format") {
+	case "html":
+		return true
+	case "json":
+		return false
+	}
+	return c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+}
@@ -0,0 +1,411 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package status aggregates the health of every service in the stack.
+//
+// # Description
+//
+// An Aggregator fans out to the health endpoints of the composed services
+// (Weaviate, the RAG engine, Ollama, Jaeger, ...) in parallel and reports
+// them in dependency order, so the first failing service in the report is
+// the root cause and the services that depend on it are marked as such.
+// The orchestrator serves the report at GET /status as JSON or as an HTML
+// page.
+//
+// # Thread Safety
+//
+// Aggregator is safe for concurrent use.
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// State is the health of a service or of the whole stack.
+type State string
+
+const (
+	// StateUp means the service answered its health check.
+	StateUp State = "up"
+
+	// StateDegraded means the service is up but a dependency is not, or
+	// (for the stack) an optional service is down.
+	StateDegraded State = "degraded"
+
+	// StateDown means the service failed its health check.
+	StateDown State = "down"
+)
+
+// Service describes one service to check.
+//
+// # Fields
+//
+//   - Name: Unique service name (e.g., "weaviate").
+//   - URL: Full health check URL. Empty means the service is the caller
+//     itself and is always up.
+//   - DependsOn: Names of the services this one needs.
+//   - Optional: A down optional service degrades the stack instead of
+//     taking it down.
+type Service struct {
+	Name      string   `json:"name"`
+	URL       string   `json:"url,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	Optional  bool     `json:"optional,omitempty"`
+}
+
+// ServiceStatus is the result of checking one service.
+//
+// # Fields
+//
+//   - State: The service's state after applying its dependencies.
+//   - HTTPStatus: The health endpoint's status code, 0 if unreachable.
+//   - LatencyMs: Time taken by the health check.
+//   - Error: Why the check failed, if it did.
+//   - Cause: For a service that is not up because of a dependency, the
+//     name of the first failing dependency.
+type ServiceStatus struct {
+	Service
+	State      State   `json:"state"`
+	HTTPStatus int     `json:"http_status,omitempty"`
+	LatencyMs  float64 `json:"latency_ms"`
+	Error      string  `json:"error,omitempty"`
+	Cause      string  `json:"cause,omitempty"`
+}
+
+// Report is the aggregated status of the stack.
+//
+// # Fields
+//
+//   - State: StateDown if a required service is down, StateDegraded if
+//     anything else is not up, StateUp otherwise.
+//   - CheckedAt: When the checks ran.
+//   - Services: One entry per service, dependencies before dependents.
+type Report struct {
+	State     State           `json:"state"`
+	CheckedAt time.Time       `json:"checked_at"`
+	Services  []ServiceStatus `json:"services"`
+}
+
+// Config configures an Aggregator.
+//
+// # Fields
+//
+//   - Timeout: Per-service health check timeout. Default: 3s.
+//   - CacheTTL: How long a report is reused before checking again, so
+//     a refreshing status page does not hammer the services. Zero
+//     disables caching. Default: 2s.
+//   - Client: HTTP client for the checks. Default: a new http.Client.
+type Config struct {
+	Timeout  time.Duration
+	CacheTTL time.Duration
+	Client   *http.Client
+}
+
+// DefaultConfig returns the default aggregator configuration.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:  3 * time.Second,
+		CacheTTL: 2 * time.Second,
+	}
+}
+
+// =============================================================================
+// Aggregator
+// =============================================================================
+
+// Aggregator checks a fixed set of services and aggregates their health.
+//
+// # Description
+//
+// Services are checked concurrently; results are ordered so that every
+// service comes after its dependencies. A service whose dependency is not
+// up is reported as degraded if its own check passed, and keeps its down
+// state with the dependency recorded as the cause otherwise.
+//
+// # Thread Safety
+//
+// Safe for concurrent use. Concurrent Check calls share one cached report.
+type Aggregator struct {
+	services []Service // in dependency order
+	config   Config
+
+	mu       sync.Mutex
+	cached   *Report
+	cachedAt time.Time
+}
+
+// NewAggregator creates an aggregator for services.
+//
+// # Description
+//
+// Validates the service graph and sorts it so dependencies come first,
+// keeping the given order among independent services.
+//
+// # Inputs
+//
+//   - services: The services to check. Names must be unique and every
+//     dependency must be one of the services.
+//   - config: Aggregator configuration. Zero fields use defaults.
+//
+// # Outputs
+//
+//   - *Aggregator: Ready to use.
+//   - error: Non-nil for duplicate names, unknown dependencies or
+//     dependency cycles.
+func NewAggregator(services []Service, config Config) (*Aggregator, error) {
+	defaults := DefaultConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.CacheTTL < 0 {
+		config.CacheTTL = 0
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+
+	ordered, err := dependencyOrder(services)
+	if err != nil {
+		return nil, err
+	}
+	return &Aggregator{services: ordered, config: config}, nil
+}
+
+// Check returns the current status of the stack.
+//
+// # Description
+//
+// Returns the cached report if it is younger than CacheTTL, otherwise
+// checks every service concurrently and caches the result.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation. Cancelled checks report down.
+//
+// # Outputs
+//
+//   - *Report: The aggregated report. Never nil. Callers must not modify it.
+func (a *Aggregator) Check(ctx context.Context) *Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cached != nil && a.config.CacheTTL > 0 && time.Since(a.cachedAt) < a.config.CacheTTL {
+		return a.cached
+	}
+
+	report := &Report{CheckedAt: time.Now().UTC(), Services: make([]ServiceStatus, len(a.services))}
+	var wg sync.WaitGroup
+	for i, svc := range a.services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Services[i] = a.checkService(ctx, svc)
+		}()
+	}
+	wg.Wait()
+
+	applyDependencies(report.Services)
+	report.State = overallState(report.Services)
+
+	a.cached = report
+	a.cachedAt = time.Now()
+	return report
+}
+
+// checkService runs one health check.
+func (a *Aggregator) checkService(ctx context.Context, svc Service) ServiceStatus {
+	result := ServiceStatus{Service: svc, State: StateUp}
+	if svc.URL == "" {
+		return result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000 }()
+
+	req, err := http.NewRequestWithContext(checkCtx, http.MethodGet, svc.URL, nil)
+	if err != nil {
+		result.State = StateDown
+		result.Error = err.Error()
+		return result
+	}
+	resp, err := a.config.Client.Do(req)
+	if err != nil {
+		result.State = StateDown
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	result.HTTPStatus = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.State = StateDown
+		result.Error = fmt.Sprintf("health check returned %s", resp.Status)
+	}
+	return result
+}
+
+// applyDependencies propagates dependency failures in dependency order.
+func applyDependencies(statuses []ServiceStatus) {
+	byName := make(map[string]*ServiceStatus, len(statuses))
+	for i := range statuses {
+		s := &statuses[i]
+		for _, dep := range s.DependsOn {
+			d := byName[dep]
+			if d == nil || d.State == StateUp {
+				continue
+			}
+			cause := dep
+			if d.Cause != "" {
+				cause = d.Cause
+			}
+			s.Cause = cause
+			if s.State == StateUp {
+				s.State = StateDegraded
+			}
+			break
+		}
+		byName[s.Name] = s
+	}
+}
+
+// overallState reduces service states to the stack state.
+func overallState(statuses []ServiceStatus) State {
+	state := StateUp
+	for _, s := range statuses {
+		switch {
+		case s.State == StateDown && !s.Optional:
+			return StateDown
+		case s.State != StateUp:
+			state = StateDegraded
+		}
+	}
+	return state
+}
+
+// dependencyOrder sorts services so every service follows its dependencies.
+func dependencyOrder(services []Service) ([]Service, error) {
+	index := make(map[string]int, len(services))
+	for i, svc := range services {
+		if svc.Name == "" {
+			return nil, errors.New("service name must not be empty")
+		}
+		if _, dup := index[svc.Name]; dup {
+			return nil, fmt.Errorf("duplicate service %q", svc.Name)
+		}
+		index[svc.Name] = i
+	}
+	for _, svc := range services {
+		for _, dep := range svc.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("service %q depends on unknown service %q", svc.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	marks := make([]int, len(services))
+	ordered := make([]Service, 0, len(services))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch marks[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle at service %q", services[i].Name)
+		}
+		marks[i] = visiting
+		for _, dep := range services[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		marks[i] = done
+		ordered = append(ordered, services[i])
+		return nil
+	}
+	for i := range services {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// =============================================================================
+// Default Stack
+// =============================================================================
+
+// DefaultServices returns the services of the standard compose stack.
+//
+// # Description
+//
+// Builds the service list from the same environment variables the
+// orchestrator uses to reach each service. Services whose variable is unset
+// are left out. The orchestrator itself is listed last with no URL, so the
+// report shows what it depends on.
+//
+// # Environment
+//
+//   - WEAVIATE_SERVICE_URL: Weaviate (checked at /v1/.well-known/ready).
+//   - RAG_ENGINE_URL: RAG engine (checked at /health), needs Weaviate.
+//   - OLLAMA_BASE_URL: Ollama (checked at /api/version).
+//   - JAEGER_UI_URL: Jaeger UI (checked at /), optional.
+//
+// # Outputs
+//
+//   - []Service: The services, possibly just the orchestrator.
+func DefaultServices() []Service {
+	var services []Service
+	var orchestratorDeps []string
+	add := func(env, name, path string, optional bool, deps ...string) {
+		base := strings.TrimRight(os.Getenv(env), "/")
+		if base == "" {
+			return
+		}
+		var present []string
+		for _, dep := range deps {
+			for _, s := range services {
+				if s.Name == dep {
+					present = append(present, dep)
+				}
+			}
+		}
+		services = append(services, Service{Name: name, URL: base + path, DependsOn: present, Optional: optional})
+		if !optional {
+			orchestratorDeps = append(orchestratorDeps, name)
+		}
+	}
+
+	add("WEAVIATE_SERVICE_URL", "weaviate", "/v1/.well-known/ready", false)
+	add("RAG_ENGINE_URL", "rag-engine", "/health", false, "weaviate")
+	add("OLLAMA_BASE_URL", "ollama", "/api/version", false)
+	add("JAEGER_UI_URL", "jaeger", "/", true)
+
+	return append(services, Service{Name: "orchestrator", DependsOn: orchestratorDeps})
+}
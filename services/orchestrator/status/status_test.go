@@ -0,0 +1,209 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// healthServer returns a server answering every request with code.
+func healthServer(t *testing.T, code int, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			hits.Add(1)
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// =============================================================================
+// Dependency Ordering Tests
+// =============================================================================
+
+func TestNewAggregator_DependencyOrder(t *testing.T) {
+	agg, err := NewAggregator([]Service{
+		{Name: "orchestrator", DependsOn: []string{"rag-engine", "ollama"}},
+		{Name: "rag-engine", DependsOn: []string{"weaviate"}},
+		{Name: "ollama"},
+		{Name: "weaviate"},
+	}, Config{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, s := range agg.services {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"weaviate", "rag-engine", "ollama", "orchestrator"}, names)
+}
+
+func TestNewAggregator_InvalidGraph(t *testing.T) {
+	tests := map[string][]Service{
+		"duplicate":  {{Name: "a"}, {Name: "a"}},
+		"unknown":    {{Name: "a", DependsOn: []string{"b"}}},
+		"cycle":      {{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}},
+		"empty name": {{Name: ""}},
+	}
+	for name, services := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAggregator(services, Config{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+// =============================================================================
+// Check Tests
+// =============================================================================
+
+func TestAggregator_Check(t *testing.T) {
+	up := healthServer(t, http.StatusOK, nil)
+	down := healthServer(t, http.StatusServiceUnavailable, nil)
+
+	t.Run("all up", func(t *testing.T) {
+		agg, err := NewAggregator([]Service{
+			{Name: "weaviate", URL: up.URL},
+			{Name: "orchestrator", DependsOn: []string{"weaviate"}},
+		}, Config{})
+		require.NoError(t, err)
+
+		report := agg.Check(context.Background())
+		assert.Equal(t, StateUp, report.State)
+		assert.Equal(t, http.StatusOK, report.Services[0].HTTPStatus)
+		assert.Equal(t, StateUp, report.Services[1].State)
+	})
+
+	t.Run("dependency failure propagates", func(t *testing.T) {
+		agg, err := NewAggregator([]Service{
+			{Name: "weaviate", URL: down.URL},
+			{Name: "rag-engine", URL: down.URL, DependsOn: []string{"weaviate"}},
+			{Name: "orchestrator", DependsOn: []string{"rag-engine"}},
+		}, Config{})
+		require.NoError(t, err)
+
+		report := agg.Check(context.Background())
+		assert.Equal(t, StateDown, report.State)
+		byName := make(map[string]ServiceStatus)
+		for _, s := range report.Services {
+			byName[s.Name] = s
+		}
+		assert.Equal(t, StateDown, byName["weaviate"].State)
+		assert.Contains(t, byName["weaviate"].Error, "503")
+		assert.Equal(t, StateDown, byName["rag-engine"].State)
+		assert.Equal(t, "weaviate", byName["rag-engine"].Cause)
+		assert.Equal(t, StateDegraded, byName["orchestrator"].State)
+		assert.Equal(t, "weaviate", byName["orchestrator"].Cause, "cause should be the root failure")
+	})
+
+	t.Run("optional service degrades", func(t *testing.T) {
+		agg, err := NewAggregator([]Service{
+			{Name: "weaviate", URL: up.URL},
+			{Name: "jaeger", URL: "http://127.0.0.1:1/", Optional: true},
+		}, Config{Timeout: time.Second})
+		require.NoError(t, err)
+
+		report := agg.Check(context.Background())
+		assert.Equal(t, StateDegraded, report.State)
+		assert.Equal(t, StateDown, report.Services[1].State)
+		assert.NotEmpty(t, report.Services[1].Error)
+	})
+
+	t.Run("caches reports", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := healthServer(t, http.StatusOK, &hits)
+		agg, err := NewAggregator([]Service{{Name: "a", URL: srv.URL}}, Config{CacheTTL: time.Minute})
+		require.NoError(t, err)
+
+		first := agg.Check(context.Background())
+		second := agg.Check(context.Background())
+		assert.Same(t, first, second)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+}
+
+func TestDefaultServices(t *testing.T) {
+	t.Setenv("WEAVIATE_SERVICE_URL", "http://weaviate:8080/")
+	t.Setenv("RAG_ENGINE_URL", "http://rag-engine:8000")
+	t.Setenv("OLLAMA_BASE_URL", "")
+	t.Setenv("JAEGER_UI_URL", "http://jaeger:16686")
+
+	services := DefaultServices()
+	require.Len(t, services, 4)
+	assert.Equal(t, "http://weaviate:8080/v1/.well-known/ready", services[0].URL)
+	assert.Equal(t, []string{"weaviate"}, services[1].DependsOn)
+	assert.True(t, services[2].Optional)
+	assert.Equal(t, Service{Name: "orchestrator", DependsOn: []string{"weaviate", "rag-engine"}}, services[3])
+
+	_, err := NewAggregator(services, Config{})
+	assert.NoError(t, err)
+}
+
+// =============================================================================
+// Handler Tests
+// =============================================================================
+
+func TestHandler(t *testing.T) {
+	down := healthServer(t, http.StatusInternalServerError, nil)
+	agg, err := NewAggregator([]Service{{Name: "weaviate", URL: down.URL}}, Config{})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/status", Handler(agg))
+
+	t.Run("json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/status", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		var report Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, StateDown, report.State)
+		assert.Equal(t, "weaviate", report.Services[0].Name)
+	})
+
+	t.Run("html for browsers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/status", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), `<td class="down">down</td>`)
+	})
+
+	t.Run("format query overrides accept", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/status?format=json", nil)
+		req.Header.Set("Accept", "text/html")
+		router.ServeHTTP(w, req)
+
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+}
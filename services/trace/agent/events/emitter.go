@@ -11,10 +11,12 @@
 package events
 
 import (
+	"context"
 	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/util"
 	"github.com/google/uuid"
 )
 
@@ -63,6 +65,7 @@ type Subscription struct {
 type Emitter struct {
 	mu            sync.RWMutex
 	subscriptions map[string]*Subscription
+	async         map[string]*util.Bounded[*Event]
 	buffer        []Event
 	bufferSize    int
 	sessionID     string
@@ -90,6 +93,7 @@ func WithSessionID(id string) EmitterOption {
 func NewEmitter(opts ...EmitterOption) *Emitter {
 	e := &Emitter{
 		subscriptions: make(map[string]*Subscription),
+		async:         make(map[string]*util.Bounded[*Event]),
 		bufferSize:    1000,
 	}
 
@@ -142,6 +146,69 @@ func (e *Emitter) SubscribeWithFilter(handler Handler, filter Filter, types ...T
 	return sub.ID
 }
 
+// SubscribeAsync registers a handler that runs on its own goroutine.
+//
+// Description:
+//
+//	Matching events are copied into a bounded queue and the handler
+//	consumes them in order on a dedicated goroutine, so a slow handler
+//	does not delay EmitWithMetadata. When the queue is full, cfg.Overflow
+//	decides whether the emitter drops the oldest queued event (default),
+//	samples, or blocks. Unsubscribe stops the goroutine after it has
+//	handled the events already queued.
+//
+// Inputs:
+//
+//	handler - Function to call for each event.
+//	cfg - Queue configuration. Zero values select util defaults.
+//	types - Event types to subscribe to (nil = all types).
+//
+// Outputs:
+//
+//	string - Subscription ID for unsubscribing.
+func (e *Emitter) SubscribeAsync(handler Handler, cfg util.BoundedConfig, types ...Type) string {
+	queue := util.NewBounded[*Event](cfg)
+	go func() {
+		for event := range queue.C() {
+			e.safeInvokeHandler(handler, event)
+		}
+	}()
+
+	enqueue := func(event *Event) {
+		ev := *event
+		queue.Send(context.Background(), &ev)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sub := &Subscription{
+		ID:      uuid.NewString(),
+		Handler: enqueue,
+		Types:   types,
+	}
+	e.subscriptions[sub.ID] = sub
+	e.async[sub.ID] = queue
+	return sub.ID
+}
+
+// AsyncStats returns the queue counters of an async subscription.
+//
+// Outputs:
+//
+//	util.BoundedStats - The counters.
+//	bool - False if id is not an active async subscription.
+func (e *Emitter) AsyncStats(id string) (util.BoundedStats, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	queue, ok := e.async[id]
+	if !ok {
+		return util.BoundedStats{}, false
+	}
+	return queue.Stats(), true
+}
+
 // Unsubscribe removes a subscription.
 //
 // Inputs:
@@ -157,6 +224,10 @@ func (e *Emitter) Unsubscribe(id string) bool {
 
 	if _, ok := e.subscriptions[id]; ok {
 		delete(e.subscriptions, id)
+		if queue, ok := e.async[id]; ok {
+			delete(e.async, id)
+			queue.Close()
+		}
 		return true
 	}
 	return false
@@ -360,7 +431,11 @@ func (e *Emitter) Reset() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	for _, queue := range e.async {
+		queue.Close()
+	}
 	e.subscriptions = make(map[string]*Subscription)
+	e.async = make(map[string]*util.Bounded[*Event])
 	e.buffer = make([]Event, 0, e.bufferSize)
 	e.currentStep = 0
 }
@@ -15,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/util"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 )
 
@@ -122,6 +123,67 @@ func TestEmitter_Unsubscribe(t *testing.T) {
 	}
 }
 
+func TestEmitter_SubscribeAsync(t *testing.T) {
+	emitter := NewEmitter()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []int
+	subID := emitter.SubscribeAsync(func(e *Event) {
+		<-release
+		mu.Lock()
+		handled = append(handled, e.Step)
+		mu.Unlock()
+	}, util.BoundedConfig{Size: 2})
+
+	// The handler is stuck, so Emit must still return promptly and the
+	// queue keeps only the newest events.
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 10; i++ {
+			emitter.SetStep(i)
+			emitter.Emit(TypeStepComplete, nil)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Emit blocked on a slow async handler")
+	}
+
+	stats, ok := emitter.AsyncStats(subID)
+	if !ok || stats.Dropped == 0 {
+		t.Errorf("AsyncStats = %+v, %v; want drops", stats, ok)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(handled)
+		last := 0
+		if n > 0 {
+			last = handled[n-1]
+		}
+		mu.Unlock()
+		if last == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handled %d events ending at step %d, want step 10 last", n, last)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if !emitter.Unsubscribe(subID) {
+		t.Error("Unsubscribe should return true")
+	}
+	if _, ok := emitter.AsyncStats(subID); ok {
+		t.Error("AsyncStats should report unsubscribed ID as missing")
+	}
+}
+
 func TestEmitter_SessionID(t *testing.T) {
 	emitter := NewEmitter(WithSessionID("session-123"))
 
@@ -0,0 +1,414 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/util"
+)
+
+// ErrSinkPermanent marks a delivery failure that retrying cannot fix, such
+// as a webhook rejecting the request as malformed. Sinks wrap it so the
+// dispatcher stops retrying.
+var ErrSinkPermanent = errors.New("permanent sink failure")
+
+// ErrDispatcherClosed is returned when adding a sink to a closed dispatcher.
+var ErrDispatcherClosed = errors.New("sink dispatcher closed")
+
+// EventSink delivers events to an external system.
+//
+// Thread Safety: Send is called from a single goroutine per sink, but
+// Close may be called concurrently with an in-flight Send.
+type EventSink interface {
+	// Name identifies the sink in logs and stats.
+	Name() string
+
+	// Send delivers one event. It returns nil only once the external
+	// system has accepted the event. Errors wrapping ErrSinkPermanent are
+	// not retried.
+	Send(ctx context.Context, event *Event) error
+
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// MarshalEvent encodes an event as JSON for external sinks.
+func MarshalEvent(event *Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("%w: marshaling event %s: %v", ErrSinkPermanent, event.ID, err)
+	}
+	return data, nil
+}
+
+// SinkConfig configures delivery to one sink.
+type SinkConfig struct {
+	// Types limits which event types are sent (nil = all types).
+	Types []Type
+
+	// QueueSize is the number of events buffered for the sink (default: 1024).
+	QueueSize int
+
+	// Overflow decides what happens to an event emitted while the queue
+	// is full (default: util.DropOldest, which keeps the newest events).
+	Overflow util.Overflow
+
+	// SampleEvery is the interval kept by util.Sample (default: 10).
+	SampleEvery int
+
+	// BlockOnFull makes Emit wait for queue space instead of dropping
+	// events when the queue is full. It is shorthand for Overflow set to
+	// util.Block and overrides Overflow (default: false).
+	BlockOnFull bool
+
+	// MaxRetries is how many times a failed send is retried. Negative
+	// retries until the dispatcher is closed (default: 5).
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry, doubling on each
+	// further retry (default: 500ms).
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the retry delay (default: 30s).
+	MaxBackoff time.Duration
+
+	// Timeout bounds a single send attempt (default: 10s).
+	Timeout time.Duration
+
+	// OnDeadLetter is called with events that could not be delivered after
+	// all retries (nil = log only).
+	OnDeadLetter func(event *Event, err error)
+}
+
+// DefaultSinkConfig returns sensible defaults.
+func DefaultSinkConfig() SinkConfig {
+	return SinkConfig{
+		QueueSize:      1024,
+		MaxRetries:     5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Timeout:        10 * time.Second,
+	}
+}
+
+// withDefaults fills zero fields from DefaultSinkConfig.
+func (c SinkConfig) withDefaults() SinkConfig {
+	d := DefaultSinkConfig()
+	if c.QueueSize <= 0 {
+		c.QueueSize = d.QueueSize
+	}
+	if c.BlockOnFull {
+		c.Overflow = util.Block
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = d.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = d.MaxBackoff
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = d.Timeout
+	}
+	return c
+}
+
+// SinkStats reports delivery counters for one sink.
+type SinkStats struct {
+	// Name is the sink name.
+	Name string
+
+	// Delivered is the number of events the sink accepted.
+	Delivered int64
+
+	// Retries is the number of retried send attempts.
+	Retries int64
+
+	// Failed is the number of events given up on after retries.
+	Failed int64
+
+	// Dropped is the number of events dropped because the queue was full
+	// or the dispatcher was closed.
+	Dropped int64
+
+	// Queued is the number of events waiting to be sent.
+	Queued int
+}
+
+// SinkDispatcher fans events out to external sinks.
+//
+// Description:
+//
+//	Events are published to a util.FanOut with one subscription per sink,
+//	so each sink has its own bounded queue, overflow strategy and delivery
+//	goroutine, and a slow or failing sink never delays the emitter or the
+//	other sinks. Failed sends
+//	are retried with exponential backoff, giving at-least-once delivery:
+//	an event may be delivered more than once (e.g. when a webhook times
+//	out after processing it), so consumers should deduplicate on Event.ID.
+//
+//	Subscribe the dispatcher to an emitter with Attach, or pass Handle to
+//	Emitter.Subscribe directly.
+//
+// Thread Safety: SinkDispatcher is safe for concurrent use.
+type SinkDispatcher struct {
+	mu      sync.RWMutex
+	fan     *util.FanOut[*Event]
+	workers []*sinkWorker
+	closed  bool
+	logger  *slog.Logger
+}
+
+// NewSinkDispatcher creates a dispatcher with no sinks.
+func NewSinkDispatcher(logger *slog.Logger) *SinkDispatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SinkDispatcher{
+		fan:    util.NewFanOut[*Event](),
+		logger: logger.With(slog.String("component", "event_sinks")),
+	}
+}
+
+// AddSink starts delivering events to sink.
+//
+// Inputs:
+//
+//	sink - The sink. The dispatcher closes it on Close.
+//	cfg - Delivery configuration. Zero sizes and durations take their
+//	      DefaultSinkConfig values; start from DefaultSinkConfig to retry.
+//
+// Outputs:
+//
+//	error - ErrDispatcherClosed if the dispatcher is closed.
+func (d *SinkDispatcher) AddSink(sink EventSink, cfg SinkConfig) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
+	cfg = cfg.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &sinkWorker{
+		sink:   sink,
+		cfg:    cfg,
+		logger: d.logger.With(slog.String("sink", sink.Name())),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	w.queue = d.fan.SubscribeFunc(util.BoundedConfig{
+		Size:        cfg.QueueSize,
+		Overflow:    cfg.Overflow,
+		SampleEvery: cfg.SampleEvery,
+		OnDrop:      w.logDrop,
+	}, w.accepts)
+	d.workers = append(d.workers, w)
+	go w.run()
+	return nil
+}
+
+// Attach subscribes the dispatcher to an emitter.
+//
+// Outputs:
+//
+//	string - Subscription ID for Emitter.Unsubscribe.
+func (d *SinkDispatcher) Attach(e *Emitter) string {
+	return e.Subscribe(d.Handle)
+}
+
+// Handle queues an event for every sink that accepts its type. It is a
+// Handler suitable for Emitter.Subscribe.
+//
+// Handle returns without waiting unless a sink uses util.Block and its
+// queue is full; events published after Close are discarded.
+func (d *SinkDispatcher) Handle(event *Event) {
+	// Other handlers see the emitter's event; queue a copy they cannot change.
+	ev := *event
+	d.fan.Publish(context.Background(), &ev)
+}
+
+// Stats returns delivery counters for every sink, in the order added.
+func (d *SinkDispatcher) Stats() []SinkStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	stats := make([]SinkStats, 0, len(d.workers))
+	for _, w := range d.workers {
+		stats = append(stats, SinkStats{
+			Name:      w.sink.Name(),
+			Delivered: w.delivered.Load(),
+			Retries:   w.retries.Load(),
+			Failed:    w.failed.Load(),
+			Dropped:   w.queue.Stats().Dropped + w.dropped.Load(),
+			Queued:    w.queue.Stats().Queued,
+		})
+	}
+	return stats
+}
+
+// Close stops accepting events, delivers what is queued, and closes the
+// sinks.
+//
+// Description:
+//
+//	Queued events keep their retry budget until ctx is done; after that,
+//	in-flight retries are abandoned and remaining events are dropped.
+//	Safe to call on a nil dispatcher and more than once.
+//
+// Outputs:
+//
+//	error - ctx.Err() if draining was cut short, joined with any sink
+//	        close errors.
+func (d *SinkDispatcher) Close(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	workers := d.workers
+	d.mu.Unlock()
+
+	d.fan.Close()
+
+	var errs []error
+	for _, w := range workers {
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			w.cancel()
+			<-w.done
+			if len(errs) == 0 {
+				errs = append(errs, ctx.Err())
+			}
+		}
+		w.cancel()
+		if err := w.sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing sink %s: %w", w.sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sinkWorker delivers queued events to one sink.
+type sinkWorker struct {
+	sink   EventSink
+	cfg    SinkConfig
+	logger *slog.Logger
+
+	queue *util.Bounded[*Event]
+	done  chan struct{}
+
+	// ctx is cancelled to abandon retries when Close gives up.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	delivered atomic.Int64
+	retries   atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64 // abandoned during Close; queue overflow is counted by queue
+}
+
+// accepts reports whether the sink takes events of this type.
+func (w *sinkWorker) accepts(event *Event) bool {
+	return len(w.cfg.Types) == 0 || slices.Contains(w.cfg.Types, event.Type)
+}
+
+// logDrop logs an event discarded by the queue, either by the overflow
+// strategy or because the dispatcher was closing.
+func (w *sinkWorker) logDrop(v any) {
+	event := v.(*Event)
+	w.logger.Warn("event sink dropped event",
+		slog.String("event_id", event.ID),
+		slog.String("event_type", string(event.Type)),
+		slog.String("overflow", w.cfg.Overflow.String()),
+	)
+}
+
+// run delivers events until the queue is closed and drained.
+func (w *sinkWorker) run() {
+	defer close(w.done)
+
+	for event := range w.queue.C() {
+		if w.ctx.Err() != nil {
+			w.dropped.Add(1)
+			continue
+		}
+		w.deliver(event)
+	}
+}
+
+// deliver sends one event, retrying with backoff.
+func (w *sinkWorker) deliver(event *Event) {
+	backoff := w.cfg.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(w.ctx, w.cfg.Timeout)
+		err := w.sink.Send(ctx, event)
+		cancel()
+		if err == nil {
+			w.delivered.Add(1)
+			return
+		}
+
+		retryable := !errors.Is(err, ErrSinkPermanent) && w.ctx.Err() == nil
+		if !retryable || (w.cfg.MaxRetries >= 0 && attempt >= w.cfg.MaxRetries) {
+			w.deadLetter(event, err)
+			return
+		}
+
+		w.logger.Debug("event sink send failed, retrying",
+			slog.String("event_id", event.ID),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-w.ctx.Done():
+			timer.Stop()
+			w.deadLetter(event, w.ctx.Err())
+			return
+		}
+		w.retries.Add(1)
+		backoff = min(backoff*2, w.cfg.MaxBackoff)
+	}
+}
+
+// deadLetter records an event that could not be delivered.
+func (w *sinkWorker) deadLetter(event *Event, err error) {
+	w.failed.Add(1)
+	w.logger.Error("event sink delivery failed",
+		slog.String("event_id", event.ID),
+		slog.String("event_type", string(event.Type)),
+		slog.String("error", err.Error()),
+	)
+	if w.cfg.OnDeadLetter != nil {
+		w.cfg.OnDeadLetter(event, err)
+	}
+}
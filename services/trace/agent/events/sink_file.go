@@ -0,0 +1,103 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// -----------------------------------------------------------------------------
+// File Sink
+// -----------------------------------------------------------------------------
+
+// FileSink appends events to a JSON Lines file, one event per line.
+//
+// Thread Safety: Safe for concurrent use.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	sync bool
+}
+
+// NewFileSink opens (or creates) a JSONL file for appending.
+//
+// Inputs:
+//
+//	path - The file path. Parent directories are created.
+//	syncWrites - Fsync after every event, so an event is only reported
+//	             delivered once it is on disk.
+//
+// Outputs:
+//
+//	*FileSink - The sink.
+//	error - Non-nil if the file cannot be opened.
+func NewFileSink(path string, syncWrites bool) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("file sink: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("file sink: %w", err)
+	}
+	return &FileSink{path: path, file: f, sync: syncWrites}, nil
+}
+
+// Name implements EventSink.
+func (s *FileSink) Name() string {
+	return "file:" + s.path
+}
+
+// Send implements EventSink.
+func (s *FileSink) Send(_ context.Context, event *Event) error {
+	line, err := MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("%w: file sink closed", ErrSinkPermanent)
+	}
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("file sink: %w", err)
+	}
+	if s.sync {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("file sink: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close implements EventSink.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	if errors.Is(err, os.ErrClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNATSSubjectPrefix is the subject prefix used when none is set.
+const DefaultNATSSubjectPrefix = "aleutian.events"
+
+// NATSSinkConfig configures a NATSSink.
+type NATSSinkConfig struct {
+	// URL is the server address: nats://[user:pass@|token@]host[:port].
+	// The port defaults to 4222.
+	URL string
+
+	// SubjectPrefix is prepended to the event type to form the subject,
+	// e.g. "aleutian.events.tool_result" (default: DefaultNATSSubjectPrefix).
+	SubjectPrefix string
+
+	// ClientName identifies the connection to the server
+	// (default: "aleutian-trace").
+	ClientName string
+
+	// DialTimeout bounds connecting when the send context has no deadline
+	// (default: 5s).
+	DialTimeout time.Duration
+}
+
+// NATSSink publishes events to a NATS server.
+//
+// Description:
+//
+//	Uses the NATS client protocol directly. Each publish is followed by a
+//	PING, and Send returns only after the matching PONG, so a nil error
+//	means the server has processed the message. When the server supports
+//	headers, each message carries a Nats-Msg-Id header set to Event.ID,
+//	which JetStream streams use to deduplicate retried publishes.
+//
+//	The connection is opened on first use and re-dialed after any error.
+//	TLS connections are not supported.
+//
+// Thread Safety: Safe for concurrent use; publishes are serialized.
+type NATSSink struct {
+	cfg  NATSSinkConfig
+	addr string
+	user string
+	pass string
+	tok  string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	headers bool
+}
+
+// natsServerInfo is the subset of the server INFO message the sink uses.
+type natsServerInfo struct {
+	Headers     bool `json:"headers"`
+	TLSRequired bool `json:"tls_required"`
+}
+
+// NewNATSSink creates a NATS sink. It does not connect until the first Send.
+//
+// Outputs:
+//
+//	*NATSSink - The sink.
+//	error - Non-nil if the URL is invalid.
+func NewNATSSink(cfg NATSSinkConfig) (*NATSSink, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("nats sink: parsing URL: %w", err)
+	}
+	if u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("nats sink: URL must be nats://host[:port], got %q", cfg.URL)
+	}
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = DefaultNATSSubjectPrefix
+	}
+	if strings.ContainsAny(cfg.SubjectPrefix, " \t\r\n") {
+		return nil, fmt.Errorf("nats sink: subject prefix %q contains whitespace", cfg.SubjectPrefix)
+	}
+	if cfg.ClientName == "" {
+		cfg.ClientName = "aleutian-trace"
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "4222"
+	}
+	s := &NATSSink{cfg: cfg, addr: net.JoinHostPort(u.Hostname(), port)}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			s.user, s.pass = u.User.Username(), pass
+		} else {
+			s.tok = u.User.Username()
+		}
+	}
+	return s, nil
+}
+
+// Name implements EventSink.
+func (s *NATSSink) Name() string {
+	return "nats:" + s.addr
+}
+
+// Subject returns the subject an event is published on.
+func (s *NATSSink) Subject(event *Event) string {
+	return s.cfg.SubjectPrefix + "." + string(event.Type)
+}
+
+// Send implements EventSink.
+func (s *NATSSink) Send(ctx context.Context, event *Event) error {
+	payload, err := MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.connectLocked(ctx); err != nil {
+		return err
+	}
+
+	// Unblock reads and writes if the context is cancelled mid-publish.
+	conn := s.conn
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	if err := s.publishLocked(ctx, event, payload); err != nil {
+		s.resetLocked()
+		return err
+	}
+	return nil
+}
+
+// Close implements EventSink.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetLocked()
+	return nil
+}
+
+// connectLocked dials and handshakes if there is no open connection.
+func (s *NATSSink) connectLocked(ctx context.Context) error {
+	if s.conn != nil {
+		return nil
+	}
+
+	dialCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, s.cfg.DialTimeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(dialCtx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("nats sink: %w", err)
+	}
+	s.conn = conn
+	s.r = bufio.NewReader(conn)
+
+	if err := s.handshakeLocked(dialCtx); err != nil {
+		s.resetLocked()
+		return err
+	}
+	return nil
+}
+
+// handshakeLocked reads INFO, sends CONNECT, and confirms with PING/PONG.
+func (s *NATSSink) handshakeLocked(ctx context.Context) error {
+	s.setDeadline(ctx)
+
+	line, err := s.readLine()
+	if err != nil {
+		return fmt.Errorf("nats sink: reading INFO: %w", err)
+	}
+	rest, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("nats sink: expected INFO, got %q", line)
+	}
+	var info natsServerInfo
+	if err := json.Unmarshal([]byte(rest), &info); err != nil {
+		return fmt.Errorf("nats sink: parsing INFO: %w", err)
+	}
+	if info.TLSRequired {
+		return fmt.Errorf("%w: nats server requires TLS, which the sink does not support", ErrSinkPermanent)
+	}
+	s.headers = info.Headers
+
+	connect := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"lang":     "go",
+		"name":     s.cfg.ClientName,
+		"protocol": 1,
+		"headers":  info.Headers,
+	}
+	if s.user != "" {
+		connect["user"], connect["pass"] = s.user, s.pass
+	}
+	if s.tok != "" {
+		connect["auth_token"] = s.tok
+	}
+	data, err := json.Marshal(connect)
+	if err != nil {
+		return fmt.Errorf("nats sink: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.conn, "CONNECT %s\r\nPING\r\n", data); err != nil {
+		return fmt.Errorf("nats sink: %w", err)
+	}
+	return s.awaitPong()
+}
+
+// publishLocked publishes one event and waits for the server to confirm.
+func (s *NATSSink) publishLocked(ctx context.Context, event *Event, payload []byte) error {
+	s.setDeadline(ctx)
+
+	subject := s.Subject(event)
+	var b strings.Builder
+	if s.headers {
+		hdr := "NATS/1.0\r\nNats-Msg-Id: " + event.ID + "\r\n\r\n"
+		fmt.Fprintf(&b, "HPUB %s %d %d\r\n%s", subject, len(hdr), len(hdr)+len(payload), hdr)
+	} else {
+		fmt.Fprintf(&b, "PUB %s %d\r\n", subject, len(payload))
+	}
+	b.Write(payload)
+	b.WriteString("\r\nPING\r\n")
+
+	if _, err := s.conn.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("nats sink: %w", err)
+	}
+	return s.awaitPong()
+}
+
+// awaitPong reads until the server's PONG, answering server PINGs.
+func (s *NATSSink) awaitPong() error {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return fmt.Errorf("nats sink: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("nats sink: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			msg := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'")
+			return fmt.Errorf("nats sink: server error: %s", msg)
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+			// Acknowledgements and updated server info need no action.
+		default:
+			return fmt.Errorf("nats sink: unexpected server message %q", line)
+		}
+	}
+}
+
+// readLine reads one CRLF-terminated protocol line.
+func (s *NATSSink) readLine() (string, error) {
+	line, err := s.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// setDeadline applies the context deadline, or DialTimeout, to the connection.
+func (s *NATSSink) setDeadline(ctx context.Context) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.cfg.DialTimeout)
+	}
+	_ = s.conn.SetDeadline(deadline)
+}
+
+// resetLocked closes the connection so the next Send re-dials.
+func (s *NATSSink) resetLocked() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.conn = nil
+	s.r = nil
+}
@@ -0,0 +1,582 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink fails the first failures sends, then records events.
+type recordingSink struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	attempts int
+	events   []Event
+	closed   bool
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Send(_ context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return s.err
+	}
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) snapshot() (int, []Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts, append([]Event(nil), s.events...), s.closed
+}
+
+// gatedSink blocks each send until release is closed.
+type gatedSink struct {
+	recordingSink
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *gatedSink) Send(ctx context.Context, event *Event) error {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return s.recordingSink.Send(ctx, event)
+}
+
+func fastSinkConfig() SinkConfig {
+	cfg := DefaultSinkConfig()
+	cfg.InitialBackoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	return cfg
+}
+
+func TestSinkDispatcher_Delivery(t *testing.T) {
+	t.Run("delivers emitted events and closes sinks", func(t *testing.T) {
+		emitter := NewEmitter()
+		d := NewSinkDispatcher(nil)
+		sink := &recordingSink{}
+		if err := d.AddSink(sink, fastSinkConfig()); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+		d.Attach(emitter)
+
+		emitter.Emit(TypeSessionStart, &SessionStartData{Query: "q"})
+		emitter.Emit(TypeSessionEnd, &SessionEndData{Success: true})
+
+		if err := d.Close(context.Background()); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		_, events, closed := sink.snapshot()
+		if len(events) != 2 {
+			t.Fatalf("delivered %d events, want 2", len(events))
+		}
+		if events[0].Type != TypeSessionStart || events[1].Type != TypeSessionEnd {
+			t.Errorf("event order = %s, %s", events[0].Type, events[1].Type)
+		}
+		if !closed {
+			t.Error("sink not closed")
+		}
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &recordingSink{failures: 2, err: errors.New("unavailable")}
+		if err := d.AddSink(sink, fastSinkConfig()); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		d.Handle(&Event{ID: "e1", Type: TypeError})
+		if err := d.Close(context.Background()); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		attempts, events, _ := sink.snapshot()
+		if attempts != 3 || len(events) != 1 {
+			t.Errorf("attempts = %d, delivered = %d; want 3, 1", attempts, len(events))
+		}
+		stats := d.Stats()[0]
+		if stats.Retries != 2 || stats.Delivered != 1 {
+			t.Errorf("stats = %+v", stats)
+		}
+	})
+
+	t.Run("permanent failures are not retried", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &recordingSink{failures: 1, err: fmt.Errorf("%w: bad request", ErrSinkPermanent)}
+		var dead []string
+		cfg := fastSinkConfig()
+		cfg.OnDeadLetter = func(event *Event, err error) { dead = append(dead, event.ID) }
+		if err := d.AddSink(sink, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		d.Handle(&Event{ID: "e1", Type: TypeError})
+		_ = d.Close(context.Background())
+
+		attempts, _, _ := sink.snapshot()
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+		if len(dead) != 1 || dead[0] != "e1" {
+			t.Errorf("dead letters = %v, want [e1]", dead)
+		}
+		if got := d.Stats()[0].Failed; got != 1 {
+			t.Errorf("Failed = %d, want 1", got)
+		}
+	})
+
+	t.Run("type filter", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &recordingSink{}
+		cfg := fastSinkConfig()
+		cfg.Types = []Type{TypeToolResult}
+		if err := d.AddSink(sink, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		d.Handle(&Event{ID: "e1", Type: TypeToolInvocation})
+		d.Handle(&Event{ID: "e2", Type: TypeToolResult})
+		_ = d.Close(context.Background())
+
+		_, events, _ := sink.snapshot()
+		if len(events) != 1 || events[0].ID != "e2" {
+			t.Errorf("delivered %v, want only e2", events)
+		}
+	})
+
+	t.Run("close abandons retries when context ends", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &recordingSink{failures: 1 << 30, err: errors.New("down")}
+		cfg := fastSinkConfig()
+		cfg.MaxRetries = -1
+		if err := d.AddSink(sink, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+		d.Handle(&Event{ID: "e1", Type: TypeError})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Close error = %v, want deadline exceeded", err)
+		}
+		if err := d.AddSink(&recordingSink{}, cfg); !errors.Is(err, ErrDispatcherClosed) {
+			t.Errorf("AddSink after Close = %v, want ErrDispatcherClosed", err)
+		}
+	})
+
+	t.Run("full queue drops oldest", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &gatedSink{started: make(chan struct{}, 1), release: make(chan struct{})}
+		cfg := fastSinkConfig()
+		cfg.QueueSize = 2
+		if err := d.AddSink(sink, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		d.Handle(&Event{ID: "e1", Type: TypeError})
+		<-sink.started // e1 is in flight; the queue is empty
+		for _, id := range []string{"e2", "e3", "e4"} {
+			d.Handle(&Event{ID: id, Type: TypeError})
+		}
+		if got := d.Stats()[0].Dropped; got != 1 {
+			t.Errorf("Dropped = %d, want 1", got)
+		}
+
+		close(sink.release)
+		_ = d.Close(context.Background())
+		_, events, _ := sink.snapshot()
+		var ids []string
+		for _, ev := range events {
+			ids = append(ids, ev.ID)
+		}
+		if strings.Join(ids, ",") != "e1,e3,e4" {
+			t.Errorf("delivered %v, want [e1 e3 e4]", ids)
+		}
+	})
+
+	t.Run("stalled sink does not cost the others events", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		stalled := &gatedSink{started: make(chan struct{}, 1), release: make(chan struct{})}
+		fast := &recordingSink{}
+		cfg := fastSinkConfig()
+		cfg.QueueSize = 2
+		if err := d.AddSink(stalled, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+		cfg.QueueSize = 100
+		if err := d.AddSink(fast, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		for i := range 50 {
+			d.Handle(&Event{ID: strconv.Itoa(i), Type: TypeError})
+		}
+		close(stalled.release)
+		_ = d.Close(context.Background())
+
+		if _, events, _ := fast.snapshot(); len(events) != 50 {
+			t.Errorf("fast sink received %d events, want 50", len(events))
+		}
+		if stats := d.Stats(); stats[0].Dropped == 0 || stats[1].Dropped != 0 {
+			t.Errorf("Dropped = %d (stalled), %d (fast); want >0, 0", stats[0].Dropped, stats[1].Dropped)
+		}
+	})
+
+	t.Run("filtered events take no queue space", func(t *testing.T) {
+		d := NewSinkDispatcher(nil)
+		sink := &gatedSink{started: make(chan struct{}, 1), release: make(chan struct{})}
+		cfg := fastSinkConfig()
+		cfg.QueueSize = 1
+		cfg.Types = []Type{TypeToolResult}
+		if err := d.AddSink(sink, cfg); err != nil {
+			t.Fatalf("AddSink: %v", err)
+		}
+
+		d.Handle(&Event{ID: "r1", Type: TypeToolResult})
+		<-sink.started
+		d.Handle(&Event{ID: "r2", Type: TypeToolResult})
+		for range 10 {
+			d.Handle(&Event{ID: "skip", Type: TypeToolInvocation})
+		}
+		close(sink.release)
+		_ = d.Close(context.Background())
+
+		if got := d.Stats()[0].Dropped; got != 0 {
+			t.Errorf("Dropped = %d, want 0", got)
+		}
+		if _, events, _ := sink.snapshot(); len(events) != 2 || events[1].ID != "r2" {
+			t.Errorf("delivered %v, want r1, r2", events)
+		}
+	})
+
+	t.Run("nil dispatcher close", func(t *testing.T) {
+		var d *SinkDispatcher
+		if err := d.Close(context.Background()); err != nil {
+			t.Errorf("Close = %v", err)
+		}
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("posts signed JSON", func(t *testing.T) {
+		var gotBody []byte
+		var gotHeader http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotBody, _ = io.ReadAll(r.Body)
+			gotHeader = r.Header.Clone()
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		sink, err := NewWebhookSink(WebhookSinkConfig{
+			URL:     srv.URL,
+			Secret:  "s3cret",
+			Headers: map[string]string{"Authorization": "Bearer t"},
+		})
+		if err != nil {
+			t.Fatalf("NewWebhookSink: %v", err)
+		}
+		defer sink.Close()
+
+		if err := sink.Send(context.Background(), &Event{ID: "e1", Type: TypeToolResult}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+
+		var ev Event
+		if err := json.Unmarshal(gotBody, &ev); err != nil || ev.ID != "e1" {
+			t.Errorf("body = %s (%v)", gotBody, err)
+		}
+		if gotHeader.Get(HeaderEventID) != "e1" || gotHeader.Get(HeaderEventType) != string(TypeToolResult) {
+			t.Errorf("event headers = %v", gotHeader)
+		}
+		if gotHeader.Get("Authorization") != "Bearer t" {
+			t.Errorf("custom header missing")
+		}
+		if want := SignWebhookBody([]byte("s3cret"), gotBody); gotHeader.Get(HeaderSignature) != want {
+			t.Errorf("signature = %q, want %q", gotHeader.Get(HeaderSignature), want)
+		}
+	})
+
+	t.Run("status classification", func(t *testing.T) {
+		tests := []struct {
+			status    int
+			wantErr   bool
+			permanent bool
+		}{
+			{http.StatusOK, false, false},
+			{http.StatusNoContent, false, false},
+			{http.StatusTooManyRequests, true, false},
+			{http.StatusBadGateway, true, false},
+			{http.StatusBadRequest, true, true},
+			{http.StatusNotFound, true, true},
+		}
+		for _, tt := range tests {
+			t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+				}))
+				defer srv.Close()
+
+				sink, err := NewWebhookSink(WebhookSinkConfig{URL: srv.URL})
+				if err != nil {
+					t.Fatalf("NewWebhookSink: %v", err)
+				}
+				err = sink.Send(context.Background(), &Event{ID: "e1", Type: TypeError})
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrSinkPermanent) != tt.permanent {
+					t.Errorf("permanent = %v, want %v (err %v)", errors.Is(err, ErrSinkPermanent), tt.permanent, err)
+				}
+			})
+		}
+	})
+
+	t.Run("rejects invalid URL", func(t *testing.T) {
+		if _, err := NewWebhookSink(WebhookSinkConfig{URL: "ftp://example.com"}); err == nil {
+			t.Error("expected error for non-http URL")
+		}
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "events.jsonl")
+	sink, err := NewFileSink(path, true)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+
+	for _, id := range []string{"e1", "e2"} {
+		if err := sink.Send(context.Background(), &Event{ID: id, Type: TypeStepComplete}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := sink.Send(context.Background(), &Event{ID: "e3"}); !errors.Is(err, ErrSinkPermanent) {
+		t.Errorf("Send after Close = %v, want ErrSinkPermanent", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for i, line := range lines {
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if want := fmt.Sprintf("e%d", i+1); ev.ID != want {
+			t.Errorf("line %d ID = %q, want %q", i, ev.ID, want)
+		}
+	}
+}
+
+// fakeNATSServer accepts one connection at a time and records published
+// messages.
+type fakeNATSServer struct {
+	ln      net.Listener
+	headers bool
+
+	mu   sync.Mutex
+	msgs []fakeNATSMsg
+}
+
+type fakeNATSMsg struct {
+	subject string
+	header  string
+	payload string
+}
+
+func newFakeNATSServer(t *testing.T, headers bool) *fakeNATSServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeNATSServer{ln: ln, headers: headers}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeNATSServer) url() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+func (s *fakeNATSServer) messages() []fakeNATSMsg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeNATSMsg(nil), s.msgs...)
+}
+
+func (s *fakeNATSServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeNATSServer) handle(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"headers\":%t}\r\n", s.headers)
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "CONNECT":
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "PUB":
+			n, _ := strconv.Atoi(fields[2])
+			buf := make([]byte, n+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			s.record(fakeNATSMsg{subject: fields[1], payload: string(buf[:n])})
+		case "HPUB":
+			hdrLen, _ := strconv.Atoi(fields[2])
+			total, _ := strconv.Atoi(fields[3])
+			buf := make([]byte, total+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			s.record(fakeNATSMsg{subject: fields[1], header: string(buf[:hdrLen]), payload: string(buf[hdrLen:total])})
+		default:
+			io.WriteString(conn, "-ERR 'Unknown Protocol Operation'\r\n")
+			return
+		}
+	}
+}
+
+func (s *fakeNATSServer) record(m fakeNATSMsg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, m)
+}
+
+func TestNATSSink(t *testing.T) {
+	for _, headers := range []bool{true, false} {
+		t.Run(fmt.Sprintf("headers=%t", headers), func(t *testing.T) {
+			srv := newFakeNATSServer(t, headers)
+			sink, err := NewNATSSink(NATSSinkConfig{URL: srv.url(), SubjectPrefix: "test.events"})
+			if err != nil {
+				t.Fatalf("NewNATSSink: %v", err)
+			}
+			defer sink.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, id := range []string{"e1", "e2"} {
+				if err := sink.Send(ctx, &Event{ID: id, Type: TypeToolResult}); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+
+			msgs := srv.messages()
+			if len(msgs) != 2 {
+				t.Fatalf("server got %d messages, want 2", len(msgs))
+			}
+			if msgs[0].subject != "test.events.tool_result" {
+				t.Errorf("subject = %q", msgs[0].subject)
+			}
+			var ev Event
+			if err := json.Unmarshal([]byte(msgs[1].payload), &ev); err != nil || ev.ID != "e2" {
+				t.Errorf("payload = %q (%v)", msgs[1].payload, err)
+			}
+			if hasID := strings.Contains(msgs[0].header, "Nats-Msg-Id: e1"); hasID != headers {
+				t.Errorf("header = %q, want Nats-Msg-Id only when headers are supported", msgs[0].header)
+			}
+		})
+	}
+
+	t.Run("reconnects after the connection drops", func(t *testing.T) {
+		srv := newFakeNATSServer(t, true)
+		sink, err := NewNATSSink(NATSSinkConfig{URL: srv.url()})
+		if err != nil {
+			t.Fatalf("NewNATSSink: %v", err)
+		}
+		defer sink.Close()
+
+		ctx := context.Background()
+		if err := sink.Send(ctx, &Event{ID: "e1", Type: TypeError}); err != nil {
+			t.Fatalf("first Send: %v", err)
+		}
+
+		// Break the connection behind the sink's back.
+		sink.mu.Lock()
+		sink.conn.Close()
+		sink.mu.Unlock()
+
+		if err := sink.Send(ctx, &Event{ID: "e2", Type: TypeError}); err == nil {
+			t.Fatal("Send on broken connection should fail")
+		}
+		if err := sink.Send(ctx, &Event{ID: "e2", Type: TypeError}); err != nil {
+			t.Fatalf("Send after reconnect: %v", err)
+		}
+		if got := len(srv.messages()); got != 2 {
+			t.Errorf("server got %d messages, want 2", got)
+		}
+	})
+
+	t.Run("rejects invalid URL", func(t *testing.T) {
+		if _, err := NewNATSSink(NATSSinkConfig{URL: "http://localhost:4222"}); err == nil {
+			t.Error("expected error for non-nats URL")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Headers set on webhook deliveries.
+const (
+	// HeaderEventID carries Event.ID so receivers can deduplicate retries.
+	HeaderEventID = "X-Aleutian-Event-ID"
+
+	// HeaderEventType carries Event.Type.
+	HeaderEventType = "X-Aleutian-Event-Type"
+
+	// HeaderSignature carries "sha256=<hex HMAC of the body>" when a
+	// secret is configured.
+	HeaderSignature = "X-Aleutian-Signature"
+)
+
+// -----------------------------------------------------------------------------
+// Webhook Sink
+// -----------------------------------------------------------------------------
+
+// WebhookSinkConfig configures a WebhookSink.
+type WebhookSinkConfig struct {
+	// URL receives a POST with the JSON event for each delivery.
+	URL string
+
+	// Secret signs each body with HMAC-SHA256 (empty = unsigned).
+	Secret string
+
+	// Headers are added to every request, e.g. for authorization.
+	Headers map[string]string
+
+	// Client sends the requests (nil = a client with default settings;
+	// per-attempt timeouts come from SinkConfig.Timeout).
+	Client *http.Client
+}
+
+// WebhookSink POSTs events as JSON to an HTTP endpoint.
+//
+// Responses in the 2xx range are success. 408, 429, and 5xx responses and
+// transport errors are retried; other statuses are permanent failures.
+//
+// Thread Safety: Safe for concurrent use.
+type WebhookSink struct {
+	cfg    WebhookSinkConfig
+	client *http.Client
+	name   string
+}
+
+// NewWebhookSink creates a webhook sink.
+//
+// Outputs:
+//
+//	*WebhookSink - The sink.
+//	error - Non-nil if the URL is not an absolute http(s) URL.
+func NewWebhookSink(cfg WebhookSinkConfig) (*WebhookSink, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("webhook sink: parsing URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("webhook sink: URL must be absolute http(s), got %q", cfg.URL)
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &WebhookSink{cfg: cfg, client: client, name: "webhook:" + u.Host}, nil
+}
+
+// Name implements EventSink.
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Send implements EventSink.
+func (s *WebhookSink) Send(ctx context.Context, event *Event) error {
+	body, err := MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: building request: %v", ErrSinkPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(HeaderEventID, event.ID)
+	req.Header.Set(HeaderEventType, string(event.Type))
+	if s.cfg.Secret != "" {
+		req.Header.Set(HeaderSignature, SignWebhookBody([]byte(s.cfg.Secret), body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook sink: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return fmt.Errorf("webhook sink: status %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: webhook returned status %d", ErrSinkPermanent, resp.StatusCode)
+	}
+}
+
+// Close implements EventSink.
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// SignWebhookBody returns the HeaderSignature value for body.
+func SignWebhookBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
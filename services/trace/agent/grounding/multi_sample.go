@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -220,6 +221,56 @@ func (v *MultiSampleVerifier) AnalyzeSamples(samples []string) *ConsensusResult
 	return v.findConsensus(sampleResponses)
 }
 
+// ConsensusReached reports whether enough samples agree to stop sampling.
+//
+// Description:
+//
+//	Returns true once ConsensusThreshold samples make exactly the same
+//	set of claims. Every claim in that set is then already consistent,
+//	and further samples could only add claims that appear in too few
+//	samples to matter. Pass it as the early-termination check of a
+//	parallel sampler (e.g., llm.SampleOptions.Stop) so the remaining
+//	samples are cancelled:
+//
+//	samples, err := llm.CompleteN(ctx, client, request, llm.SampleOptions{
+//	    N:            cfg.NumSamples,
+//	    Temperatures: []float64{cfg.Temperature},
+//	    Stop:         verifier.ConsensusReached,
+//	})
+//
+// Inputs:
+//
+//	samples - Contents of the samples completed so far.
+//
+// Outputs:
+//
+//	bool - True if ConsensusThreshold samples agree.
+//
+// Thread Safety: Safe for concurrent use.
+func (v *MultiSampleVerifier) ConsensusReached(samples []string) bool {
+	threshold := max(v.config.ConsensusThreshold, 1)
+	if len(samples) < threshold {
+		return false
+	}
+
+	checker := NewGroundingChecker(nil)
+	counts := make(map[string]int, len(samples))
+	for _, text := range samples {
+		claims := checker.extractClaims(text)
+		keys := make([]string, 0, len(claims))
+		for _, claim := range claims {
+			keys = append(keys, normalizeClaim(claim))
+		}
+		slices.Sort(keys)
+		key := strings.Join(slices.Compact(keys), "\x00")
+		counts[key]++
+		if counts[key] >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
 // findConsensus identifies consistent and inconsistent claims.
 func (v *MultiSampleVerifier) findConsensus(samples []SampleResponse) *ConsensusResult {
 	// Build claim frequency map
@@ -460,3 +460,33 @@ func TestMultiSampleVerifier_Integration(t *testing.T) {
 		t.Error("expected result to be at least 50% consistent")
 	}
 }
+
+func TestMultiSampleVerifier_ConsensusReached(t *testing.T) {
+	verifier := NewMultiSampleVerifier(&MultiSampleConfig{
+		Enabled:            true,
+		NumSamples:         3,
+		ConsensusThreshold: 2,
+	})
+
+	agree := "The main.go file contains the HandleRequest function."
+	same := "In main.go, the HandleRequest function handles requests."
+	differ := "The server.go file uses Flask."
+
+	tests := []struct {
+		name    string
+		samples []string
+		want    bool
+	}{
+		{"too few samples", []string{agree}, false},
+		{"two agree", []string{agree, same}, true},
+		{"two differ", []string{agree, differ}, false},
+		{"agreement after a dissent", []string{agree, differ, same}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifier.ConsensusReached(tt.samples); got != tt.want {
+				t.Errorf("ConsensusReached = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/budget"
+)
+
+// BudgetedClient charges LLM usage to the API key in the request context.
+//
+// Description:
+//
+//	Before each call, the key carried by the context (see budget.WithKey)
+//	is checked against its daily budget; an exhausted budget fails the call
+//	without contacting the provider. After each call the response's token
+//	counts are recorded. Calls without a key pass through uncharged.
+//
+// Thread Safety:
+//
+//	BudgetedClient is safe for concurrent use.
+type BudgetedClient struct {
+	inner   Client
+	tracker *budget.Tracker
+}
+
+// NewBudgetedClient wraps a client with budget enforcement.
+//
+// Inputs:
+//
+//	inner - The client to dispatch to. Must not be nil.
+//	tracker - The budget tracker. If nil, inner is returned unchanged.
+//
+// Outputs:
+//
+//	Client - The wrapped client.
+func NewBudgetedClient(inner Client, tracker *budget.Tracker) Client {
+	if tracker == nil {
+		return inner
+	}
+	return &BudgetedClient{inner: inner, tracker: tracker}
+}
+
+// Complete checks the budget, dispatches, and records usage.
+//
+// Outputs:
+//
+//	*Response - The LLM response.
+//	error - A *budget.ExceededError (matching budget.ErrBudgetExceeded)
+//	        if the key is out of budget, or the inner client's error.
+func (c *BudgetedClient) Complete(ctx context.Context, request *Request) (*Response, error) {
+	key, ok := budget.KeyFrom(ctx)
+	if !ok {
+		return c.inner.Complete(ctx, request)
+	}
+
+	if _, err := c.tracker.Check(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.inner.Complete(ctx, request)
+	if resp != nil {
+		input, output := resp.InputTokens, resp.OutputTokens
+		if input == 0 && output == 0 {
+			output = resp.TokensUsed
+		}
+		c.tracker.Record(key, input, output)
+	}
+	return resp, err
+}
+
+// Name returns the inner client's provider name.
+func (c *BudgetedClient) Name() string {
+	return c.inner.Name()
+}
+
+// Model returns the inner client's model.
+func (c *BudgetedClient) Model() string {
+	return c.inner.Model()
+}
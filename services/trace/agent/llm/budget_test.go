@@ -0,0 +1,59 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/budget"
+)
+
+func TestBudgetedClient(t *testing.T) {
+	tracker := budget.NewTracker(budget.Config{
+		Keys: map[string]budget.Limits{"ci": {DailyTokens: 150}},
+	})
+	mock := NewMockClient()
+	client := NewBudgetedClient(mock, tracker)
+	ctx := budget.WithKey(context.Background(), "ci")
+
+	// Each mock response uses 100 tokens: the first call leaves 50, the
+	// second is allowed and overdraws, the third is refused.
+	for i := range 2 {
+		if _, err := client.Complete(ctx, &Request{}); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if got := tracker.Status("ci").Used.Tokens; got != 200 {
+		t.Errorf("used tokens = %d, want 200", got)
+	}
+
+	_, err := client.Complete(ctx, &Request{})
+	if !errors.Is(err, budget.ErrBudgetExceeded) {
+		t.Fatalf("third call error = %v, want ErrBudgetExceeded", err)
+	}
+	if got := len(mock.GetCalls()); got != 2 {
+		t.Errorf("inner calls = %d, want 2 (exhausted budget must not dispatch)", got)
+	}
+
+	t.Run("no key passes through", func(t *testing.T) {
+		if _, err := client.Complete(context.Background(), &Request{}); err != nil {
+			t.Errorf("Complete without key: %v", err)
+		}
+	})
+
+	t.Run("nil tracker returns inner client", func(t *testing.T) {
+		if NewBudgetedClient(mock, nil) != Client(mock) {
+			t.Error("expected inner client")
+		}
+	})
+}
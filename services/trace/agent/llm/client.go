@@ -108,6 +108,10 @@ type Request struct {
 	// Temperature controls randomness (0.0-1.0).
 	Temperature float64 `json:"temperature,omitempty"`
 
+	// Seed fixes the sampling seed for reproducible output.
+	// If nil, the provider chooses.
+	Seed *int `json:"seed,omitempty"`
+
 	// StopSequences defines sequences that stop generation.
 	StopSequences []string `json:"stop_sequences,omitempty"`
 
@@ -208,6 +212,10 @@ type Response struct {
 	// OutputTokens is the output token count.
 	OutputTokens int `json:"output_tokens"`
 
+	// InputTokensMeasured is true when InputTokens was reported by the
+	// provider rather than estimated.
+	InputTokensMeasured bool `json:"input_tokens_measured,omitempty"`
+
 	// Duration is how long the request took.
 	Duration time.Duration `json:"duration"`
 
@@ -0,0 +1,464 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+const (
+	// DefaultContextWindow is the context size requested from Ollama when
+	// none is configured.
+	DefaultContextWindow = 65536
+
+	// defaultOutputReserve is the room left for the reply when a request
+	// sets no MaxTokens. It is capped at a quarter of the window.
+	defaultOutputReserve = 4096
+
+	// defaultSafetyMargin is the fraction of the window left unused to
+	// absorb counting error.
+	defaultSafetyMargin = 0.05
+
+	// defaultElidedKeep is how many tokens of a truncated message are kept,
+	// split between its head and tail.
+	defaultElidedKeep = 256
+
+	// Calibration bounds, and the weight of an observation that lowers
+	// the observed/estimated ratio.
+	minCalibration    = 0.5
+	maxCalibration    = 3.0
+	calibrationWeight = 0.2
+)
+
+// ContextWindowConfig configures a ContextWindow.
+type ContextWindowConfig struct {
+	// Window is the model's context size in tokens (Ollama's num_ctx).
+	Window int
+
+	// OutputReserve is the room left for the reply when the request sets
+	// no MaxTokens. Zero uses 4096, capped at a quarter of Window.
+	OutputReserve int
+
+	// SafetyMargin is the fraction of Window left unused. Zero uses 0.05.
+	SafetyMargin float64
+
+	// ElidedKeep is how many tokens of a truncated message are kept.
+	// Zero uses 256.
+	ElidedKeep int
+}
+
+// withDefaults fills unset fields.
+func (c ContextWindowConfig) withDefaults() ContextWindowConfig {
+	if c.OutputReserve <= 0 {
+		c.OutputReserve = min(defaultOutputReserve, c.Window/4)
+	}
+	if c.SafetyMargin <= 0 {
+		c.SafetyMargin = defaultSafetyMargin
+	}
+	if c.ElidedKeep <= 0 {
+		c.ElidedKeep = defaultElidedKeep
+	}
+	return c
+}
+
+// FitReport describes what ContextWindow.Fit did to a request.
+type FitReport struct {
+	// Budget is the prompt token budget after the output reserve and
+	// safety margin.
+	Budget int
+
+	// OriginalTokens and FinalTokens are the calibrated prompt sizes
+	// before and after fitting.
+	OriginalTokens int
+	FinalTokens    int
+
+	// TruncatedMessages counts messages whose content was elided.
+	TruncatedMessages int
+
+	// DroppedMessages counts messages removed from the history.
+	DroppedMessages int
+
+	// Fits is false if the request is still over budget, which happens
+	// only when the system prompt and tools alone exceed it.
+	Fits bool
+}
+
+// Changed reports whether Fit modified the request.
+func (r FitReport) Changed() bool {
+	return r.TruncatedMessages > 0 || r.DroppedMessages > 0
+}
+
+// ContextWindow fits requests into a model's context window.
+//
+// Description:
+//
+//	Counts prompt tokens with the model family's tokenizer and, when a
+//	request would overflow, compacts it in a fixed order: elide the middle
+//	of long messages oldest-first, then drop the oldest messages, then
+//	elide the newest message. The system prompt, tool definitions, the
+//	first user message and the newest message are kept. The same request
+//	always produces the same result for the same calibration.
+//
+//	Counts are scaled by a calibration factor learned from the prompt
+//	sizes the provider reports (see Observe). It rises to the largest
+//	observed undercount at once and falls back slowly, so families counted
+//	by a profile rather than their real tokenizer converge from above.
+//
+// Thread Safety:
+//
+//	ContextWindow is safe for concurrent use.
+type ContextWindow struct {
+	tok Tokenizer
+	cfg ContextWindowConfig
+
+	mu    sync.Mutex
+	scale float64
+}
+
+// NewContextWindow creates a budget manager for one model.
+//
+// Inputs:
+//
+//	tok - The model's tokenizer. Must not be nil.
+//	cfg - Window configuration. Window must be positive.
+//
+// Outputs:
+//
+//	*ContextWindow - The budget manager.
+func NewContextWindow(tok Tokenizer, cfg ContextWindowConfig) *ContextWindow {
+	return &ContextWindow{tok: tok, cfg: cfg.withDefaults(), scale: 1}
+}
+
+// Calibration returns the current observed/estimated token ratio.
+func (w *ContextWindow) Calibration() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.scale
+}
+
+// Observe records the provider's prompt token count for a request whose
+// uncalibrated estimate was estimated.
+//
+// Description:
+//
+//	Undercounting overflows the window, so a ratio above the current
+//	calibration is adopted at once, while lower ratios only pull it down
+//	by calibrationWeight per observation. A prompt Ollama partly served
+//	from its cache, which reports fewer tokens, cannot shrink the budget
+//	margin in one step.
+//
+// Inputs:
+//
+//	estimated - CountRequestTokens for the request that was sent.
+//	actual - The prompt tokens the provider reported.
+func (w *ContextWindow) Observe(estimated, actual int) {
+	if estimated <= 0 || actual <= 0 {
+		return
+	}
+	ratio := float64(actual) / float64(estimated)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ratio > w.scale {
+		w.scale = ratio
+	} else {
+		w.scale += calibrationWeight * (ratio - w.scale)
+	}
+	if w.scale < minCalibration {
+		w.scale = minCalibration
+	} else if w.scale > maxCalibration {
+		w.scale = maxCalibration
+	}
+}
+
+// Count returns the calibrated prompt token count of a request.
+func (w *ContextWindow) Count(request *Request) int {
+	return w.calibrate(CountRequestTokens(w.tok, request))
+}
+
+// calibrate scales a raw token count by the calibration factor.
+func (w *ContextWindow) calibrate(tokens int) int {
+	w.mu.Lock()
+	scale := w.scale
+	w.mu.Unlock()
+	return int(float64(tokens)*scale + 0.5)
+}
+
+// Budget returns the prompt token budget for a request.
+func (w *ContextWindow) Budget(request *Request) int {
+	reserve := w.cfg.OutputReserve
+	if request != nil && request.MaxTokens > 0 {
+		reserve = request.MaxTokens
+	}
+	margin := int(float64(w.cfg.Window) * w.cfg.SafetyMargin)
+	return max(w.cfg.Window-reserve-margin, 0)
+}
+
+// Fit returns a request that fits the context window.
+//
+// Description:
+//
+//	Returns request itself when it already fits. Otherwise returns a
+//	compacted copy; request and its messages are never modified.
+//
+// Inputs:
+//
+//	request - The request to fit. Nil is returned as is.
+//
+// Outputs:
+//
+//	*Request - The fitted request.
+//	FitReport - What was changed.
+func (w *ContextWindow) Fit(request *Request) (*Request, FitReport) {
+	report := FitReport{Budget: w.Budget(request)}
+	if request == nil {
+		report.Fits = true
+		return request, report
+	}
+
+	report.OriginalTokens = w.Count(request)
+	if report.OriginalTokens <= report.Budget {
+		report.FinalTokens = report.OriginalTokens
+		report.Fits = true
+		return request, report
+	}
+
+	fitted := *request
+	fitted.Messages = append([]Message(nil), request.Messages...)
+	total := report.OriginalTokens
+	last := len(fitted.Messages) - 1
+
+	// Elide long messages, oldest first, leaving the newest intact.
+	for i := 0; i < last && total > report.Budget; i++ {
+		before := w.calibrate(countMessageTokens(w.tok, &fitted.Messages[i]))
+		if w.elideMessage(&fitted.Messages[i], w.cfg.ElidedKeep) {
+			total -= before - w.calibrate(countMessageTokens(w.tok, &fitted.Messages[i]))
+			report.TruncatedMessages++
+		}
+	}
+
+	// Drop the oldest messages, keeping tool calls with their results.
+	if total > report.Budget {
+		var dropped int
+		fitted.Messages, dropped = w.dropOldest(fitted.Messages, report.Budget, total)
+		report.DroppedMessages = dropped
+		total = w.Count(&fitted)
+	}
+
+	// Elide the newest message to whatever room is left.
+	if total > report.Budget && len(fitted.Messages) > 0 {
+		newest := &fitted.Messages[len(fitted.Messages)-1]
+		size := w.calibrate(countMessageTokens(w.tok, newest))
+		room := size - (total - report.Budget) - messageOverheadTokens
+		if room > 0 && w.elideMessage(newest, room) {
+			report.TruncatedMessages++
+			total = w.Count(&fitted)
+		}
+	}
+
+	report.FinalTokens = total
+	report.Fits = total <= report.Budget
+	return &fitted, report
+}
+
+// dropOldest removes the oldest removable messages until the request fits.
+// The first user message and the newest message stay. An assistant message
+// with tool calls is dropped together with the tool messages answering it,
+// so the model never sees a result without its call.
+func (w *ContextWindow) dropOldest(messages []Message, budget, total int) ([]Message, int) {
+	firstUser := -1
+	for i := range messages {
+		if messages[i].Role == "user" {
+			firstUser = i
+			break
+		}
+	}
+	last := len(messages) - 1
+
+	drop := make([]bool, len(messages))
+	dropped := 0
+	for i := 0; i < last && total > budget; i++ {
+		if i == firstUser || drop[i] {
+			continue
+		}
+		end := i + 1
+		if len(messages[i].ToolCalls) > 0 {
+			for end < last && messages[end].Role == "tool" {
+				end++
+			}
+		}
+		for j := i; j < end; j++ {
+			if j == firstUser {
+				continue
+			}
+			total -= w.calibrate(countMessageTokens(w.tok, &messages[j]))
+			drop[j] = true
+			dropped++
+		}
+	}
+
+	kept := make([]Message, 0, len(messages)-dropped)
+	for i := range messages {
+		if !drop[i] {
+			kept = append(kept, messages[i])
+		}
+	}
+	return kept, dropped
+}
+
+// elideMessage shortens a message's text to about keep calibrated tokens,
+// replacing the message in place with a modified copy. It reports whether
+// anything was removed.
+func (w *ContextWindow) elideMessage(msg *Message, keep int) bool {
+	raw := int(float64(keep) / w.Calibration())
+	if msg.Role == "tool" && len(msg.ToolResults) > 0 {
+		results := append([]ToolCallResult(nil), msg.ToolResults...)
+		per := max(raw/len(results), 1)
+		changed := false
+		for i := range results {
+			if text, ok := elideText(w.tok, results[i].Content, per); ok {
+				results[i].Content = text
+				changed = true
+			}
+		}
+		if changed {
+			msg.ToolResults = results
+		}
+		return changed
+	}
+	text, ok := elideText(w.tok, msg.Content, raw)
+	if ok {
+		msg.Content = text
+	}
+	return ok
+}
+
+// elideText keeps the head and tail of text totalling about keep tokens,
+// marker included, and replaces the middle with a marker naming how much
+// was removed. The cut is refined a few times because token density
+// varies along the text.
+func elideText(tok Tokenizer, text string, keep int) (string, bool) {
+	count := tok.CountTokens(text)
+	if count <= keep {
+		return text, false
+	}
+	runes := []rune(text)
+	marker := fmt.Sprintf("\n[... %d tokens elided ...]\n", count-keep)
+	kept := len(runes) * keep / count
+	var out string
+	for range 4 {
+		head := kept / 2
+		out = string(runes[:head]) + marker + string(runes[len(runes)-(kept-head):])
+		got := tok.CountTokens(out)
+		if got <= keep || kept == 0 {
+			break
+		}
+		kept = kept * keep / got
+		if kept > 0 {
+			kept--
+		}
+	}
+	return out, true
+}
+
+// ContextWindowClient fits every request to the model's context window
+// before dispatching it.
+//
+// Description:
+//
+//	Keeps one ContextWindow per model so requests with a ModelOverride are
+//	counted with that model's tokenizer and calibration. When the provider
+//	reports measured prompt tokens (Response.InputTokensMeasured), the
+//	count calibrates the model's window.
+//
+// Thread Safety:
+//
+//	ContextWindowClient is safe for concurrent use.
+type ContextWindowClient struct {
+	inner Client
+	cfg   ContextWindowConfig
+
+	mu      sync.Mutex
+	windows map[string]*ContextWindow
+}
+
+// NewContextWindowClient wraps a client with context window fitting.
+//
+// Inputs:
+//
+//	inner - The client to dispatch to. Must not be nil.
+//	cfg - Window configuration. If Window is not positive, inner is
+//	      returned unchanged.
+//
+// Outputs:
+//
+//	Client - The wrapped client.
+func NewContextWindowClient(inner Client, cfg ContextWindowConfig) Client {
+	if cfg.Window <= 0 {
+		return inner
+	}
+	return &ContextWindowClient{inner: inner, cfg: cfg, windows: make(map[string]*ContextWindow)}
+}
+
+// Window returns the budget manager for a model, creating it on first use.
+func (c *ContextWindowClient) Window(model string) *ContextWindow {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w, ok := c.windows[model]
+	if !ok {
+		w = NewContextWindow(TokenizerFor(model), c.cfg)
+		c.windows[model] = w
+	}
+	return w
+}
+
+// Complete fits the request, dispatches it, and calibrates on the reply.
+func (c *ContextWindowClient) Complete(ctx context.Context, request *Request) (*Response, error) {
+	if request == nil {
+		return c.inner.Complete(ctx, request)
+	}
+	model := request.ModelOverride
+	if model == "" {
+		model = c.inner.Model()
+	}
+	w := c.Window(model)
+
+	fitted, report := w.Fit(request)
+	if report.Changed() || !report.Fits {
+		slog.Info("Fitted request to context window",
+			slog.String("model", model),
+			slog.Int("budget", report.Budget),
+			slog.Int("original_tokens", report.OriginalTokens),
+			slog.Int("final_tokens", report.FinalTokens),
+			slog.Int("truncated_messages", report.TruncatedMessages),
+			slog.Int("dropped_messages", report.DroppedMessages),
+			slog.Bool("fits", report.Fits),
+		)
+	}
+
+	resp, err := c.inner.Complete(ctx, fitted)
+	if resp != nil && resp.InputTokensMeasured {
+		w.Observe(CountRequestTokens(w.tok, fitted), resp.InputTokens)
+	}
+	return resp, err
+}
+
+// Name implements Client.
+func (c *ContextWindowClient) Name() string {
+	return c.inner.Name()
+}
+
+// Model implements Client.
+func (c *ContextWindowClient) Model() string {
+	return c.inner.Model()
+}
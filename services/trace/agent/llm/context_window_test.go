@@ -0,0 +1,200 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// words returns n distinct one-token words.
+func words(n int) string {
+	var b strings.Builder
+	for i := range n {
+		b.WriteString(" w")
+		b.WriteString(strings.Repeat("x", i%5))
+	}
+	return b.String()
+}
+
+// agentHistory builds a request shaped like an agent session: a task,
+// several tool round trips, and a final user turn.
+func agentHistory(resultTokens int) *Request {
+	request := &Request{
+		SystemPrompt: "You are a code assistant.",
+		Messages:     []Message{{Role: "user", Content: "Find the callers of parseConfig."}},
+	}
+	for i := range 4 {
+		id := string(rune('a' + i))
+		request.Messages = append(request.Messages,
+			Message{Role: "assistant", ToolCalls: []ToolCall{{ID: id, Name: "find_callers", Arguments: `{"symbol":"parseConfig"}`}}},
+			Message{Role: "tool", ToolResults: []ToolCallResult{{ToolCallID: id, Content: words(resultTokens)}}},
+		)
+	}
+	request.Messages = append(request.Messages, Message{Role: "user", Content: "Summarize what you found."})
+	return request
+}
+
+func TestContextWindow_Fit_Unchanged(t *testing.T) {
+	w := NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 8192})
+	request := agentHistory(100)
+
+	fitted, report := w.Fit(request)
+	if fitted != request {
+		t.Error("a request that fits should be returned as is")
+	}
+	if !report.Fits || report.Changed() || report.FinalTokens != report.OriginalTokens {
+		t.Errorf("report = %+v", report)
+	}
+	if want := 8192 - 2048 - 409; report.Budget != want {
+		t.Errorf("budget = %d, want %d", report.Budget, want)
+	}
+}
+
+func TestContextWindow_Fit_ElidesOldestFirst(t *testing.T) {
+	w := NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 8192})
+	request := agentHistory(2000)
+	original := agentHistory(2000)
+
+	fitted, report := w.Fit(request)
+	if !report.Fits || report.DroppedMessages != 0 || report.TruncatedMessages == 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if !reflect.DeepEqual(request, original) {
+		t.Error("Fit modified its input")
+	}
+	if got := w.Count(fitted); got != report.FinalTokens || got > report.Budget {
+		t.Errorf("fitted count = %d, report %d, budget %d", got, report.FinalTokens, report.Budget)
+	}
+
+	// Only the oldest results are elided, and no more than needed.
+	var elided []bool
+	for _, msg := range fitted.Messages {
+		if msg.Role == "tool" {
+			elided = append(elided, strings.Contains(msg.ToolResults[0].Content, "tokens elided"))
+		}
+	}
+	for i, e := range elided {
+		if want := i < report.TruncatedMessages; e != want {
+			t.Errorf("tool result %d elided = %v, want %v", i, e, want)
+		}
+	}
+	if elided[len(elided)-1] {
+		t.Error("the newest tool result should be kept whole")
+	}
+
+	again, _ := w.Fit(agentHistory(2000))
+	if !reflect.DeepEqual(fitted, again) {
+		t.Error("Fit is not deterministic")
+	}
+}
+
+func TestContextWindow_Fit_DropsRoundTrips(t *testing.T) {
+	w := NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 2048, ElidedKeep: 600})
+	request := agentHistory(2000)
+
+	fitted, report := w.Fit(request)
+	if !report.Fits || report.DroppedMessages == 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.DroppedMessages%2 != 0 {
+		t.Errorf("dropped %d messages; tool calls and results must go together", report.DroppedMessages)
+	}
+
+	msgs := fitted.Messages
+	if msgs[0].Content != request.Messages[0].Content {
+		t.Error("the task message was dropped")
+	}
+	if msgs[len(msgs)-1].Content != "Summarize what you found." {
+		t.Error("the newest message was changed")
+	}
+	for i, msg := range msgs {
+		if msg.Role == "tool" && (i == 0 || len(msgs[i-1].ToolCalls) == 0) {
+			t.Errorf("message %d is a tool result without its call", i)
+		}
+	}
+}
+
+func TestContextWindow_Fit_TruncatesNewest(t *testing.T) {
+	w := NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 1024})
+	request := &Request{Messages: []Message{{Role: "user", Content: words(5000)}}}
+
+	fitted, report := w.Fit(request)
+	if !report.Fits || report.TruncatedMessages != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if !strings.Contains(fitted.Messages[0].Content, "tokens elided") {
+		t.Error("newest message was not elided")
+	}
+
+	oversized := &Request{SystemPrompt: words(2000), Messages: []Message{{Role: "user", Content: "hi"}}}
+	if _, report := w.Fit(oversized); report.Fits {
+		t.Error("a system prompt larger than the window cannot fit")
+	}
+}
+
+func TestContextWindow_Observe(t *testing.T) {
+	w := NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 8192})
+	for range 50 {
+		w.Observe(1000, 1500)
+	}
+	if got := w.Calibration(); got < 1.45 || got > 1.5 {
+		t.Errorf("calibration = %.3f, want about 1.5", got)
+	}
+
+	// An undercount is adopted at once; an overcount is only approached.
+	w = NewContextWindow(NewProfileTokenizer(TiktokenProfile), ContextWindowConfig{Window: 8192})
+	w.Observe(1000, 1200)
+	if got := w.Calibration(); math.Abs(got-1.2) > 1e-9 {
+		t.Errorf("calibration after undercount = %.3f, want 1.2", got)
+	}
+	w.Observe(1000, 600)
+	if got := w.Calibration(); math.Abs(got-1.08) > 1e-9 {
+		t.Errorf("calibration after overcount = %.3f, want 1.08", got)
+	}
+
+	w.Observe(1, 100)
+	if got := w.Calibration(); got > maxCalibration {
+		t.Errorf("calibration = %.3f, want at most %.1f", got, maxCalibration)
+	}
+
+	before := w.Calibration()
+	w.Observe(0, 100)
+	w.Observe(100, 0)
+	if w.Calibration() != before {
+		t.Error("missing counts should be ignored")
+	}
+}
+
+func TestContextWindowClient(t *testing.T) {
+	mock := NewMockClient().WithModel("granite4:micro-h")
+	mock.SetDefaultResponse(&Response{Content: "done", StopReason: "end", InputTokens: 1, InputTokensMeasured: true})
+	client := NewContextWindowClient(mock, ContextWindowConfig{Window: 2048})
+
+	if _, err := client.Complete(context.Background(), agentHistory(2000)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	sent := mock.LastRequest()
+	w := client.(*ContextWindowClient).Window("granite4:micro-h")
+	if got := CountRequestTokens(w.tok, sent); got > w.Budget(sent) {
+		t.Errorf("sent %d tokens, budget %d", got, w.Budget(sent))
+	}
+	if w.Calibration() >= 1 {
+		t.Errorf("calibration = %.3f, want it lowered by the measured count", w.Calibration())
+	}
+
+	if NewContextWindowClient(mock, ContextWindowConfig{}) != Client(mock) {
+		t.Error("a zero window should return the inner client")
+	}
+}
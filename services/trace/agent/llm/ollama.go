@@ -14,6 +14,7 @@ import (
 	"context"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/llm"
@@ -33,8 +34,15 @@ import (
 //
 //	OllamaAdapter is safe for concurrent use.
 type OllamaAdapter struct {
-	client *llm.OllamaClient
-	model  string
+	client        *llm.OllamaClient
+	model         string
+	tokenizer     Tokenizer
+	contextWindow int
+
+	// toolFormat is ToolFormatOpenAI (native) or ToolFormatPrompt. Empty
+	// until resolved from the model's capabilities on first tool use.
+	toolFormat     ToolFormat
+	toolFormatOnce sync.Once
 }
 
 // NewOllamaAdapter creates a new OllamaAdapter.
@@ -58,18 +66,82 @@ type OllamaAdapter struct {
 //	adapter := NewOllamaAdapter(ollamaClient, "gpt-oss:20b")
 func NewOllamaAdapter(client *llm.OllamaClient, model string) *OllamaAdapter {
 	return &OllamaAdapter{
-		client: client,
-		model:  model,
+		client:        client,
+		model:         model,
+		tokenizer:     TokenizerFor(model),
+		contextWindow: DefaultContextWindow,
 	}
 }
 
+// WithContextWindow sets the context size requested from Ollama (num_ctx).
+//
+// Inputs:
+//
+//	tokens - The context size. Non-positive values keep the default of
+//	         DefaultContextWindow.
+//
+// Outputs:
+//
+//	*OllamaAdapter - The adapter, for chaining.
+func (a *OllamaAdapter) WithContextWindow(tokens int) *OllamaAdapter {
+	if tokens > 0 {
+		a.contextWindow = tokens
+	}
+	return a
+}
+
+// WithToolFormat fixes how tools are sent instead of asking Ollama whether
+// the model supports tool calling.
+//
+// Inputs:
+//
+//	format - ToolFormatOpenAI for native tool calling or ToolFormatPrompt
+//	         for JSON prompting.
+//
+// Outputs:
+//
+//	*OllamaAdapter - The adapter, for chaining.
+func (a *OllamaAdapter) WithToolFormat(format ToolFormat) *OllamaAdapter {
+	a.toolFormat = format
+	return a
+}
+
+// resolveToolFormat returns how tools are sent to the model, asking Ollama
+// for the model's capabilities the first time. Models whose capabilities
+// are reported without "tools" use prompting; if Ollama cannot be asked,
+// native tool calling is assumed.
+func (a *OllamaAdapter) resolveToolFormat(ctx context.Context) ToolFormat {
+	a.toolFormatOnce.Do(func() {
+		if a.toolFormat != "" {
+			return
+		}
+		a.toolFormat = ToolFormatOpenAI
+		info, err := a.client.Show(ctx, a.model)
+		if err != nil {
+			slog.Debug("OllamaAdapter could not read model capabilities",
+				slog.String("model", a.model),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		if len(info.Capabilities) > 0 && !info.HasCapability("tools") {
+			slog.Info("Model lacks native tool calling, using JSON tool prompting",
+				slog.String("model", a.model),
+			)
+			a.toolFormat = ToolFormatPrompt
+		}
+	})
+	return a.toolFormat
+}
+
 // Complete implements Client.
 //
 // Description:
 //
 //	Sends a completion request to Ollama and returns the response.
 //	Converts between agent message format and Ollama's datatypes.Message format.
-//	If tools are provided in the request, uses ChatWithTools to enable function calling.
+//	If tools are provided in the request, uses ChatWithTools to enable function
+//	calling, or describes them in the prompt for models without tool support.
 //
 // Inputs:
 //
@@ -91,6 +163,11 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 		}, nil
 	}
 
+	if len(request.Tools) > 0 && request.ModelOverride == "" &&
+		a.resolveToolFormat(ctx) == ToolFormatPrompt {
+		return a.completeWithPromptTools(ctx, request)
+	}
+
 	// Convert agent messages to datatypes.Message format
 	messages := a.convertMessages(request)
 
@@ -117,7 +194,7 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 
 	// Use ChatWithTools if tools are provided
 	if len(request.Tools) > 0 {
-		return a.completeWithTools(ctx, messages, params, request.Tools, startTime)
+		return a.completeWithTools(ctx, request, messages, params, startTime)
 	}
 
 	// Call Ollama without tools
@@ -153,12 +230,13 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 	}
 
 	// Build response
+	outputTokens := a.tokenizer.CountTokens(content)
 	return &Response{
 		Content:      content,
 		StopReason:   "end",
-		TokensUsed:   estimateTokens(content),
-		InputTokens:  estimateInputTokens(messages),
-		OutputTokens: estimateTokens(content),
+		TokensUsed:   outputTokens,
+		InputTokens:  CountRequestTokens(a.tokenizer, request),
+		OutputTokens: outputTokens,
 		Duration:     duration,
 		Model:        a.model,
 	}, nil
@@ -174,9 +252,9 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 // Inputs:
 //
 //	ctx - Context for cancellation and timeout.
+//	request - The agent request, for its tool definitions and token counts.
 //	messages - Converted messages in Ollama format.
 //	params - Generation parameters.
-//	toolDefs - Tool definitions from the request.
 //	startTime - When the request started (for duration tracking).
 //
 // Outputs:
@@ -185,13 +263,13 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 //	error - Non-nil if the request failed.
 func (a *OllamaAdapter) completeWithTools(
 	ctx context.Context,
+	request *Request,
 	messages []datatypes.Message,
 	params llm.GenerationParams,
-	toolDefs []tools.ToolDefinition,
 	startTime time.Time,
 ) (*Response, error) {
 	// Convert tool definitions to Ollama format
-	ollamaTools := convertToolDefinitions(toolDefs)
+	ollamaTools := convertToolDefinitions(request.Tools)
 
 	slog.Debug("OllamaAdapter calling ChatWithTools",
 		slog.Int("num_tools", len(ollamaTools)),
@@ -239,19 +317,61 @@ func (a *OllamaAdapter) completeWithTools(
 		})
 	}
 
+	// Prefer the counts Ollama measured; fall back to the tokenizer when
+	// it omits them (e.g., a fully cached prompt reports no prompt_eval_count).
+	inputTokens, measured := result.PromptTokens, result.PromptTokens > 0
+	if !measured {
+		inputTokens = CountRequestTokens(a.tokenizer, request)
+	}
+	outputTokens := result.CompletionTokens
+	if outputTokens == 0 {
+		outputTokens = a.tokenizer.CountTokens(result.Content)
+	}
+
 	duration := time.Since(startTime)
 	return &Response{
-		Content:      result.Content,
-		ToolCalls:    agentToolCalls,
-		StopReason:   result.StopReason,
-		TokensUsed:   estimateTokens(result.Content),
-		InputTokens:  estimateInputTokens(messages),
-		OutputTokens: estimateTokens(result.Content),
-		Duration:     duration,
-		Model:        a.model,
+		Content:             result.Content,
+		ToolCalls:           agentToolCalls,
+		StopReason:          result.StopReason,
+		TokensUsed:          outputTokens,
+		InputTokens:         inputTokens,
+		OutputTokens:        outputTokens,
+		InputTokensMeasured: measured,
+		Duration:            duration,
+		Model:               a.model,
 	}, nil
 }
 
+// completeWithPromptTools handles requests with tools for models without
+// native tool calling.
+//
+// Description:
+//
+//	Sends the request rewritten by PromptToolRequest and turns a JSON tool
+//	call in the reply back into a ToolCall.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout.
+//	request - The request with tool definitions.
+//
+// Outputs:
+//
+//	*Response - The LLM response with a tool call if the model made one.
+//	error - Non-nil if the request failed.
+func (a *OllamaAdapter) completeWithPromptTools(ctx context.Context, request *Request) (*Response, error) {
+	resp, err := a.Complete(ctx, PromptToolRequest(request))
+	if err != nil {
+		return nil, err
+	}
+	if call := ParsePromptToolCall(resp.Content, request.Tools); call != nil {
+		resp.ToolCalls = []ToolCall{*call}
+		resp.Content = ""
+		resp.StopReason = "tool_use"
+	}
+	return resp, nil
+}
+
 // convertToolDefinitions converts agent tool definitions to Ollama format.
 //
 // Description:
@@ -404,6 +524,11 @@ func (a *OllamaAdapter) buildParams(request *Request) llm.GenerationParams {
 		params.Stop = request.StopSequences
 	}
 
+	if request.Seed != nil {
+		seed := *request.Seed
+		params.Seed = &seed
+	}
+
 	// Pass through multi-model support fields
 	if request.ModelOverride != "" {
 		params.ModelOverride = request.ModelOverride
@@ -413,10 +538,10 @@ func (a *OllamaAdapter) buildParams(request *Request) llm.GenerationParams {
 		params.KeepAlive = request.KeepAlive
 	}
 
-	// Set context window size for main agent (64K for analysis tasks).
+	// Set context window size for main agent (64K by default).
 	// This MUST be passed on every request to prevent Ollama from
 	// resetting to default 4096 context window.
-	numCtx := 65536 // 64K tokens for main agent
+	numCtx := a.contextWindow
 	params.NumCtx = &numCtx
 
 	// Set keep_alive to prevent Ollama from unloading the model between requests.
@@ -460,38 +585,3 @@ func convertAgentToolChoice(tc *ToolChoice) *llm.ToolChoice {
 		Name: tc.Name,
 	}
 }
-
-// estimateTokens provides a rough token estimate.
-//
-// Description:
-//
-//	Estimates token count as ~4 characters per token.
-//	This is a rough approximation; actual counts depend on the tokenizer.
-//
-// Inputs:
-//
-//	content - The text to estimate tokens for.
-//
-// Outputs:
-//
-//	int - Estimated token count.
-func estimateTokens(content string) int {
-	return len(content) / 4
-}
-
-// estimateInputTokens estimates input tokens from messages.
-//
-// Inputs:
-//
-//	messages - The input messages.
-//
-// Outputs:
-//
-//	int - Estimated input token count.
-func estimateInputTokens(messages []datatypes.Message) int {
-	total := 0
-	for _, msg := range messages {
-		total += len(msg.Content)
-	}
-	return total / 4
-}
@@ -211,32 +211,25 @@ func TestTruncate(t *testing.T) {
 
 func TestEstimateTokens(t *testing.T) {
 	tests := []struct {
-		name    string
-		content string
-		want    int
+		name     string
+		model    string
+		input    string
+		expected int
 	}{
-		{
-			name:    "empty string",
-			content: "",
-			want:    0,
-		},
-		{
-			name:    "4 chars = 1 token",
-			content: "test",
-			want:    1,
-		},
-		{
-			name:    "16 chars = 4 tokens",
-			content: "this is a test!!", // 16 chars
-			want:    4,
-		},
+		{"empty string", "llama3.1:8b", "", 0},
+		{"single word", "llama3.1:8b", "test", 1},
+		{"sentence", "llama3.1:8b", "this is a test!!", 5},
+		{"sentence o200k", "gpt-oss:20b", "this is a test!!", 5},
+		{"empty string estimated", "mistral:7b", "", 0},
+		{"single word estimated", "mistral:7b", "test", 1},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := estimateTokens(tt.content)
-			if got != tt.want {
-				t.Errorf("estimateTokens(%q) = %d, want %d", tt.content, got, tt.want)
+			adapter := NewOllamaAdapter(nil, tt.model)
+			result := adapter.tokenizer.CountTokens(tt.input)
+			if result != tt.expected {
+				t.Errorf("CountTokens(%q) with %s = %d, want %d", tt.input, tt.model, result, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,179 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNoSamples is returned by CompleteN when every sample failed.
+var ErrNoSamples = errors.New("no samples succeeded")
+
+// SampleOptions configures CompleteN.
+type SampleOptions struct {
+	// N is the number of samples to generate. Must be positive.
+	N int
+
+	// Concurrency limits how many samples run at once. Zero runs all N
+	// in parallel.
+	Concurrency int
+
+	// Temperatures sets each sample's temperature: sample i uses
+	// Temperatures[i % len(Temperatures)]. Empty uses the request's.
+	Temperatures []float64
+
+	// Seeds sets each sample's seed: sample i uses Seeds[i % len(Seeds)].
+	// Empty leaves the request's seed, so the provider chooses.
+	Seeds []int
+
+	// Stop is called with the contents of the samples completed so far,
+	// in completion order, each time a sample succeeds. Returning true
+	// cancels the samples still running. Optional.
+	Stop func(contents []string) bool
+}
+
+// Sample is the outcome of one CompleteN sample.
+type Sample struct {
+	// Index is the sample's position in 0..N-1.
+	Index int
+
+	// Temperature and Seed are the values the sample was sent with.
+	// Seed is nil when the provider chose.
+	Temperature float64
+	Seed        *int
+
+	// Response is the LLM response, nil if Err is set.
+	Response *Response
+
+	// Err is the sample's error.
+	Err error
+}
+
+// CompleteN generates several samples of one request in parallel.
+//
+// Description:
+//
+//	Sends N copies of request, each with its own temperature and seed, at
+//	most Concurrency at a time. Once Stop reports consensus the samples
+//	still running are cancelled and those not yet started are skipped,
+//	so a check that agrees early costs about one sample's latency instead
+//	of N.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	client - The client to sample from. Must be safe for concurrent use.
+//	request - The request to sample. Not modified.
+//	opts - Sampling options.
+//
+// Outputs:
+//
+//	[]Sample - The samples that ran, ordered by Index. Samples cancelled
+//	           by early termination are omitted.
+//	error - ErrNoSamples (wrapping the first sample error) if none
+//	        succeeded, ctx's error if ctx ended, or an error for invalid
+//	        options.
+//
+// Thread Safety: This function is safe for concurrent use.
+func CompleteN(ctx context.Context, client Client, request *Request, opts SampleOptions) ([]Sample, error) {
+	if opts.N <= 0 {
+		return nil, fmt.Errorf("sample count must be positive, got %d", opts.N)
+	}
+	if request == nil {
+		return nil, errors.New("request must not be nil")
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 || concurrency > opts.N {
+		concurrency = opts.N
+	}
+
+	sampleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		samples  []Sample
+		contents []string
+		stopped  bool
+		wg       sync.WaitGroup
+	)
+	slots := make(chan struct{}, concurrency)
+
+	for i := range opts.N {
+		select {
+		case slots <- struct{}{}:
+		case <-sampleCtx.Done():
+		}
+		if sampleCtx.Err() != nil {
+			break
+		}
+
+		sampleReq := *request
+		if len(opts.Temperatures) > 0 {
+			sampleReq.Temperature = opts.Temperatures[i%len(opts.Temperatures)]
+		}
+		if len(opts.Seeds) > 0 {
+			seed := opts.Seeds[i%len(opts.Seeds)]
+			sampleReq.Seed = &seed
+		}
+
+		wg.Add(1)
+		go func(index int, req *Request) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			resp, err := client.Complete(sampleCtx, req)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if stopped && err != nil {
+				// Cancelled by early termination; not a real outcome.
+				return
+			}
+			samples = append(samples, Sample{
+				Index:       index,
+				Temperature: req.Temperature,
+				Seed:        req.Seed,
+				Response:    resp,
+				Err:         err,
+			})
+			if err != nil || stopped || opts.Stop == nil {
+				return
+			}
+			contents = append(contents, resp.Content)
+			if opts.Stop(contents) {
+				stopped = true
+				cancel()
+			}
+		}(i, &sampleReq)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(samples, func(a, b int) bool { return samples[a].Index < samples[b].Index })
+	var firstErr error
+	for _, s := range samples {
+		if s.Err == nil {
+			return samples, nil
+		}
+		if firstErr == nil {
+			firstErr = s.Err
+		}
+	}
+	return samples, fmt.Errorf("%w: %w", ErrNoSamples, firstErr)
+}
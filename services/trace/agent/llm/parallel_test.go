@@ -0,0 +1,121 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCompleteN_PerSampleParameters(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := &funcClient{complete: func(_ context.Context, req *Request) (*Response, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return &Response{Content: fmt.Sprintf("t=%.1f seed=%d", req.Temperature, *req.Seed)}, nil
+	}}
+
+	request := &Request{Messages: []Message{{Role: "user", Content: "q"}}, Temperature: 0.2}
+	samples, err := CompleteN(context.Background(), client, request, SampleOptions{
+		N:            4,
+		Concurrency:  2,
+		Temperatures: []float64{0.5, 0.9},
+		Seeds:        []int{10, 11, 12, 13},
+	})
+	if err != nil {
+		t.Fatalf("CompleteN: %v", err)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("got %d samples, want 4", len(samples))
+	}
+	for i, s := range samples {
+		want := fmt.Sprintf("t=%.1f seed=%d", []float64{0.5, 0.9}[i%2], 10+i)
+		if s.Index != i || s.Response.Content != want {
+			t.Errorf("sample %d = %d %q, want %q", i, s.Index, s.Response.Content, want)
+		}
+	}
+	if peak.Load() != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak.Load())
+	}
+	if request.Temperature != 0.2 || request.Seed != nil {
+		t.Error("CompleteN modified the request")
+	}
+}
+
+func TestCompleteN_StopsOnConsensus(t *testing.T) {
+	var started atomic.Int32
+	client := &funcClient{complete: func(ctx context.Context, req *Request) (*Response, error) {
+		started.Add(1)
+		if *req.Seed < 2 {
+			return &Response{Content: "agree"}, nil
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	start := time.Now()
+	samples, err := CompleteN(context.Background(), client, &Request{}, SampleOptions{
+		N:     5,
+		Seeds: []int{0, 1, 2, 3, 4},
+		Stop: func(contents []string) bool {
+			return len(contents) >= 2
+		},
+	})
+	if err != nil {
+		t.Fatalf("CompleteN: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Index != 0 || samples[1].Index != 1 {
+		t.Errorf("samples = %+v, want the two agreeing samples", samples)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("cancelled samples were waited on")
+	}
+}
+
+func TestCompleteN_Errors(t *testing.T) {
+	failing := NewMockClient().WithError(errors.New("boom"))
+	samples, err := CompleteN(context.Background(), failing, &Request{}, SampleOptions{N: 3})
+	if !errors.Is(err, ErrNoSamples) || len(samples) != 3 {
+		t.Errorf("all failed: %d samples, err %v", len(samples), err)
+	}
+
+	if _, err := CompleteN(context.Background(), failing, &Request{}, SampleOptions{}); err == nil {
+		t.Error("expected an error for N = 0")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CompleteN(ctx, NewMockClient(), &Request{}, SampleOptions{N: 2}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: err = %v", err)
+	}
+}
+
+// funcClient is a Client backed by a function that sees the context.
+type funcClient struct {
+	complete func(ctx context.Context, req *Request) (*Response, error)
+}
+
+func (c *funcClient) Complete(ctx context.Context, req *Request) (*Response, error) {
+	return c.complete(ctx, req)
+}
+func (c *funcClient) Name() string  { return "func" }
+func (c *funcClient) Model() string { return "func" }
@@ -0,0 +1,413 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors returned by ScriptedClient.
+var (
+	// ErrScriptExhausted indicates the agent made more LLM calls than the
+	// scenario scripts.
+	ErrScriptExhausted = errors.New("scripted scenario has no turns left")
+
+	// ErrScriptMismatch indicates a request did not meet the expectations
+	// of its turn.
+	ErrScriptMismatch = errors.New("request does not match scripted turn")
+
+	// ErrInvalidScenario indicates a scenario file that cannot be used.
+	ErrInvalidScenario = errors.New("invalid scenario")
+)
+
+// Scenario is a scripted multi-turn conversation for ScriptedClient.
+//
+// Scenarios are written in YAML:
+//
+//	name: find-callers
+//	model: scripted-model
+//	turns:
+//	  - expect:
+//	      contains: ["callers"]
+//	      tools: ["find_callers"]
+//	    tool_calls:
+//	      - name: find_callers
+//	        arguments: {function_name: parseConfig}
+//	  - content: "parseConfig is called by main and loadDefaults."
+type Scenario struct {
+	// Name identifies the scenario in errors.
+	Name string `yaml:"name"`
+
+	// Description explains what the scenario exercises.
+	Description string `yaml:"description,omitempty"`
+
+	// Model is reported by Model() and set on responses.
+	Model string `yaml:"model,omitempty"`
+
+	// Turns are the responses, served one per Complete call.
+	Turns []Turn `yaml:"turns"`
+
+	// RepeatLast serves the last turn again once the turns run out,
+	// instead of failing with ErrScriptExhausted.
+	RepeatLast bool `yaml:"repeat_last,omitempty"`
+}
+
+// Turn scripts the response to one Complete call.
+//
+// Exactly one outcome applies, checked in this order: Timeout, Error,
+// Empty, then a response built from Content and ToolCalls. Content may be
+// anything, including malformed JSON or ReAct text, to exercise the
+// agent's parsing.
+type Turn struct {
+	// Expect constrains the request. Optional.
+	Expect *Expectation `yaml:"expect,omitempty"`
+
+	// Content is the response text.
+	Content string `yaml:"content,omitempty"`
+
+	// ToolCalls are the tool calls in the response.
+	ToolCalls []ScriptedToolCall `yaml:"tool_calls,omitempty"`
+
+	// StopReason overrides the stop reason. Defaults to "tool_use" when
+	// there are tool calls and "end" otherwise.
+	StopReason string `yaml:"stop_reason,omitempty"`
+
+	// Tokens is the output token count reported. Defaults to a rough
+	// estimate from Content.
+	Tokens int `yaml:"tokens,omitempty"`
+
+	// Delay is waited before responding. Cancelling the context ends
+	// the wait with the context's error.
+	Delay time.Duration `yaml:"delay,omitempty"`
+
+	// Timeout simulates a provider that never answers: the call waits
+	// for the context to end (not at all for a context that cannot end)
+	// and fails with context.DeadlineExceeded.
+	Timeout bool `yaml:"timeout,omitempty"`
+
+	// Error fails the call with this message.
+	Error string `yaml:"error,omitempty"`
+
+	// Empty fails the call with an *EmptyResponseError, as Ollama does
+	// when a model returns nothing.
+	Empty bool `yaml:"empty,omitempty"`
+}
+
+// ScriptedToolCall is a tool call in a scripted response.
+type ScriptedToolCall struct {
+	// ID is the call ID. Defaults to "call_<turn>_<index>".
+	ID string `yaml:"id,omitempty"`
+
+	// Name is the tool name.
+	Name string `yaml:"name"`
+
+	// Arguments are encoded as the call's JSON arguments.
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+
+	// RawArguments is used verbatim instead of Arguments, e.g. to send
+	// malformed JSON.
+	RawArguments string `yaml:"raw_arguments,omitempty"`
+}
+
+// Expectation constrains the request a turn answers.
+type Expectation struct {
+	// Contains lists substrings that must appear in the last message.
+	Contains []string `yaml:"contains,omitempty"`
+
+	// Tools lists tools that must be offered.
+	Tools []string `yaml:"tools,omitempty"`
+
+	// ToolChoice is the required tool choice type ("auto", "any", "tool",
+	// "none"). A request without a tool choice counts as "auto".
+	ToolChoice string `yaml:"tool_choice,omitempty"`
+}
+
+// LoadScenario reads a scenario from a YAML file.
+//
+// Outputs:
+//
+//	*Scenario - The scenario.
+//	error - Non-nil if the file cannot be read, or ErrInvalidScenario.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// ParseScenario parses and validates a YAML scenario.
+//
+// Outputs:
+//
+//	*Scenario - The scenario.
+//	error - ErrInvalidScenario if it cannot be parsed, has no turns, or
+//	        has a tool call without a name.
+func ParseScenario(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScenario, err)
+	}
+	if len(scenario.Turns) == 0 {
+		return nil, fmt.Errorf("%w: no turns", ErrInvalidScenario)
+	}
+	for i, turn := range scenario.Turns {
+		for _, call := range turn.ToolCalls {
+			if call.Name == "" {
+				return nil, fmt.Errorf("%w: turn %d has a tool call without a name", ErrInvalidScenario, i)
+			}
+		}
+	}
+	return &scenario, nil
+}
+
+// ScriptedClient is a deterministic LLM client that serves a Scenario.
+//
+// Description:
+//
+//	Each Complete call is answered by the next turn, regardless of the
+//	request, so agent-loop tests run the same way every time without a
+//	live model. A request that does not meet its turn's Expectation
+//	fails with ErrScriptMismatch; use Verify at the end of a test to
+//	check that every turn was used and matched.
+//
+// Thread Safety:
+//
+//	ScriptedClient is safe for concurrent use. Concurrent calls are
+//	answered in the order they acquire the client.
+type ScriptedClient struct {
+	mu       sync.Mutex
+	scenario *Scenario
+	next     int
+	calls    []CompletionCall
+	failures []string
+}
+
+// NewScriptedClient creates a client that serves a scenario.
+//
+// Inputs:
+//
+//	scenario - The scenario. Must not be nil.
+//
+// Outputs:
+//
+//	*ScriptedClient - The client.
+func NewScriptedClient(scenario *Scenario) *ScriptedClient {
+	return &ScriptedClient{scenario: scenario}
+}
+
+// Complete implements Client.
+func (c *ScriptedClient) Complete(ctx context.Context, request *Request) (*Response, error) {
+	c.mu.Lock()
+	index, turn, err := c.take(request)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if turn.Timeout {
+		if done := ctx.Done(); done != nil {
+			<-done
+		}
+		return nil, fmt.Errorf("scripted turn %d timed out: %w", index, context.DeadlineExceeded)
+	}
+	if turn.Delay > 0 {
+		timer := time.NewTimer(turn.Delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case turn.Error != "":
+		return nil, errors.New(turn.Error)
+	case turn.Empty:
+		emptyErr := &EmptyResponseError{Duration: time.Since(start), Model: c.Model()}
+		if request != nil {
+			emptyErr.MessageCount = len(request.Messages)
+		}
+		return nil, emptyErr
+	}
+	return c.response(index, turn, time.Since(start))
+}
+
+// take records the call and selects its turn. Caller must hold c.mu.
+func (c *ScriptedClient) take(request *Request) (int, Turn, error) {
+	c.calls = append(c.calls, CompletionCall{Request: request, Timestamp: time.Now()})
+
+	index := c.next
+	if index >= len(c.scenario.Turns) {
+		if !c.scenario.RepeatLast {
+			err := fmt.Errorf("%w: %s has %d turns, call %d",
+				ErrScriptExhausted, c.scenario.Name, len(c.scenario.Turns), index+1)
+			c.failures = append(c.failures, err.Error())
+			return index, Turn{}, err
+		}
+		index = len(c.scenario.Turns) - 1
+	}
+	c.next++
+
+	turn := c.scenario.Turns[index]
+	if problem := turn.Expect.check(request); problem != "" {
+		err := fmt.Errorf("%w: %s turn %d: %s", ErrScriptMismatch, c.scenario.Name, index, problem)
+		c.failures = append(c.failures, err.Error())
+		return index, turn, err
+	}
+	return index, turn, nil
+}
+
+// response builds the scripted response for a turn.
+func (c *ScriptedClient) response(index int, turn Turn, d time.Duration) (*Response, error) {
+	resp := &Response{
+		Content:    turn.Content,
+		StopReason: turn.StopReason,
+		Duration:   d,
+		Model:      c.Model(),
+	}
+	for i, call := range turn.ToolCalls {
+		args := call.RawArguments
+		if args == "" {
+			encoded, err := json.Marshal(call.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("encoding arguments of scripted turn %d: %w", index, err)
+			}
+			args = string(encoded)
+		}
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d_%d", index, i)
+		}
+		resp.ToolCalls = append(resp.ToolCalls, ToolCall{ID: id, Name: call.Name, Arguments: args})
+	}
+
+	if resp.StopReason == "" {
+		resp.StopReason = "end"
+		if resp.HasToolCalls() {
+			resp.StopReason = "tool_use"
+		}
+	}
+	resp.OutputTokens = turn.Tokens
+	if resp.OutputTokens == 0 {
+		resp.OutputTokens = len(turn.Content)/4 + 1
+	}
+	resp.TokensUsed = resp.OutputTokens
+	return resp, nil
+}
+
+// check returns why a request fails the expectation, or "" if it meets
+// it. A nil expectation accepts any request.
+func (e *Expectation) check(request *Request) string {
+	if e == nil {
+		return ""
+	}
+	if request == nil {
+		return "nil request"
+	}
+
+	var last string
+	if n := len(request.Messages); n > 0 {
+		msg := request.Messages[n-1]
+		last = msg.Content
+		for _, r := range msg.ToolResults {
+			last += "\n" + r.Content
+		}
+	}
+	for _, s := range e.Contains {
+		if !strings.Contains(last, s) {
+			return fmt.Sprintf("last message does not contain %q", s)
+		}
+	}
+
+	offered := make([]string, 0, len(request.Tools))
+	for _, def := range request.Tools {
+		offered = append(offered, def.Name)
+	}
+	for _, name := range e.Tools {
+		if !slices.Contains(offered, name) {
+			return fmt.Sprintf("tool %q not offered", name)
+		}
+	}
+
+	if e.ToolChoice != "" {
+		choice := "auto"
+		if request.ToolChoice != nil {
+			choice = request.ToolChoice.Type
+		}
+		if choice != e.ToolChoice {
+			return fmt.Sprintf("tool choice is %q, want %q", choice, e.ToolChoice)
+		}
+	}
+	return ""
+}
+
+// Name implements Client.
+func (c *ScriptedClient) Name() string {
+	return "scripted"
+}
+
+// Model implements Client. It returns the scenario's model.
+func (c *ScriptedClient) Model() string {
+	if c.scenario.Model == "" {
+		return "scripted"
+	}
+	return c.scenario.Model
+}
+
+// Calls returns the requests received, in order.
+func (c *ScriptedClient) Calls() []CompletionCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
+
+// Remaining returns the number of turns not yet served.
+func (c *ScriptedClient) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(len(c.scenario.Turns)-c.next, 0)
+}
+
+// Verify returns an error if a turn was not served or a call failed its
+// expectation or ran past the script.
+func (c *ScriptedClient) Verify() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	problems := slices.Clone(c.failures)
+	if left := len(c.scenario.Turns) - c.next; left > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d turns not served", left, len(c.scenario.Turns)))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("scenario %s: %s", c.scenario.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func loadScenario(t *testing.T, name string) *ScriptedClient {
+	t.Helper()
+	scenario, err := LoadScenario("testdata/scenarios/" + name + ".yaml")
+	if err != nil {
+		t.Fatalf("LoadScenario: %v", err)
+	}
+	return NewScriptedClient(scenario)
+}
+
+func userRequest(content string, toolNames ...string) *Request {
+	req := &Request{Messages: []Message{{Role: "user", Content: content}}}
+	for _, name := range toolNames {
+		req.Tools = append(req.Tools, tools.ToolDefinition{Name: name})
+	}
+	return req
+}
+
+func TestScriptedClient_ToolSequence(t *testing.T) {
+	client := loadScenario(t, "tool_sequence")
+	ctx := context.Background()
+
+	resp, err := client.Complete(ctx, userRequest("Who calls parseConfig?", "find_callers", "Read"))
+	if err != nil {
+		t.Fatalf("turn 0: %v", err)
+	}
+	if resp.StopReason != "tool_use" || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "find_callers" {
+		t.Fatalf("turn 0 = %+v", resp)
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(resp.ToolCalls[0].Arguments), &args); err != nil || args["function_name"] != "parseConfig" {
+		t.Errorf("arguments = %s (%v)", resp.ToolCalls[0].Arguments, err)
+	}
+	if resp.ToolCalls[0].ID != "call_0_0" || resp.Model != "scripted-model" {
+		t.Errorf("unexpected id %q or model %q", resp.ToolCalls[0].ID, resp.Model)
+	}
+
+	toolResult := &Request{Messages: []Message{{
+		Role:        "tool",
+		ToolResults: []ToolCallResult{{ToolCallID: "call_0_0", Content: "main, loadDefaults"}},
+	}}}
+	if resp, err = client.Complete(ctx, toolResult); err != nil || resp.ToolCalls[0].Name != "Read" {
+		t.Fatalf("turn 1 = %+v, %v", resp, err)
+	}
+	if resp, err = client.Complete(ctx, userRequest("")); err != nil || resp.StopReason != "end" || resp.HasToolCalls() {
+		t.Fatalf("turn 2 = %+v, %v", resp, err)
+	}
+
+	if err := client.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if _, err := client.Complete(ctx, userRequest("")); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("expected ErrScriptExhausted, got %v", err)
+	}
+	if len(client.Calls()) != 4 || client.Verify() == nil {
+		t.Error("the extra call should be recorded and fail Verify")
+	}
+}
+
+func TestScriptedClient_Mismatch(t *testing.T) {
+	client := loadScenario(t, "tool_sequence")
+
+	_, err := client.Complete(context.Background(), userRequest("Who calls parseConfig?"))
+	if !errors.Is(err, ErrScriptMismatch) {
+		t.Fatalf("expected ErrScriptMismatch when find_callers is not offered, got %v", err)
+	}
+	if client.Remaining() != 2 || client.Verify() == nil {
+		t.Error("a mismatched turn is consumed and fails Verify")
+	}
+}
+
+func TestScriptedClient_MalformedOutput(t *testing.T) {
+	client := loadScenario(t, "malformed_output")
+	ctx := context.Background()
+
+	resp, err := client.Complete(ctx, userRequest("q"))
+	if err != nil {
+		t.Fatalf("turn 0: %v", err)
+	}
+	if json.Valid([]byte(resp.ToolCalls[0].Arguments)) {
+		t.Errorf("expected malformed arguments, got %s", resp.ToolCalls[0].Arguments)
+	}
+
+	if resp, err = client.Complete(ctx, userRequest("q")); err != nil || resp.StopReason != "max_tokens" {
+		t.Fatalf("turn 1 = %+v, %v", resp, err)
+	}
+
+	var empty *EmptyResponseError
+	if _, err = client.Complete(ctx, userRequest("q")); !errors.As(err, &empty) || empty.MessageCount != 1 {
+		t.Fatalf("turn 2: expected *EmptyResponseError, got %v", err)
+	}
+
+	if resp, err = client.Complete(ctx, userRequest("q")); err != nil || resp.Content != "parseConfig is called by main." {
+		t.Fatalf("turn 3 = %+v, %v", resp, err)
+	}
+	if err := client.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestScriptedClient_Timeout(t *testing.T) {
+	client := loadScenario(t, "timeout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Complete(ctx, userRequest("q")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("turn 0: expected DeadlineExceeded, got %v", err)
+	}
+
+	if _, err := client.Complete(context.Background(), userRequest("q")); err == nil {
+		t.Fatal("turn 1: expected the scripted error")
+	}
+
+	start := time.Now()
+	resp, err := client.Complete(context.Background(), userRequest("q"))
+	if err != nil || resp.Content != "Recovered after retries." {
+		t.Fatalf("turn 2 = %+v, %v", resp, err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("turn 2 should wait for its delay")
+	}
+}
+
+func TestParseScenario_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no turns":        "name: empty\n",
+		"unnamed tool":    "turns:\n  - tool_calls:\n      - arguments: {a: 1}\n",
+		"not a scenario":  "turns: 3\n",
+		"repeat no turns": "repeat_last: true\n",
+	} {
+		if _, err := ParseScenario([]byte(data)); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("%s: expected ErrInvalidScenario, got %v", name, err)
+		}
+	}
+}
+
+func TestScriptedClient_RepeatLast(t *testing.T) {
+	scenario, err := ParseScenario([]byte("repeat_last: true\nturns:\n  - content: done\n"))
+	if err != nil {
+		t.Fatalf("ParseScenario: %v", err)
+	}
+	client := NewScriptedClient(scenario)
+	for i := range 3 {
+		if resp, err := client.Complete(context.Background(), &Request{}); err != nil || resp.Content != "done" {
+			t.Fatalf("call %d = %+v, %v", i, resp, err)
+		}
+	}
+	if err := client.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
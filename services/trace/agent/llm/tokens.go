@@ -0,0 +1,438 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	tiktoken "github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// messageOverheadTokens is the chat-template cost of one message: role
+// markers, separators and the end-of-turn token.
+const messageOverheadTokens = 4
+
+// Tokenizer counts the tokens a model would see for a text.
+//
+// Implementations must be deterministic and safe for concurrent use.
+type Tokenizer interface {
+	// CountTokens returns the number of tokens in text.
+	CountTokens(text string) int
+
+	// Family returns the tokenizer family name (e.g., "cl100k_base").
+	Family() string
+}
+
+// Byte-pair encodings bundled with the binary.
+const (
+	// EncodingCL100K is the encoding whose vocabulary Llama 3 and Qwen
+	// extend with further merges, so it counts their text closely and
+	// slightly high.
+	EncodingCL100K = "cl100k_base"
+
+	// EncodingO200K is the encoding of gpt-oss and of OpenAI's GPT-4o
+	// generation models.
+	EncodingO200K = "o200k_base"
+)
+
+// BPETokenizer counts tokens with a real tiktoken byte-pair encoding.
+//
+// Thread Safety:
+//
+//	BPETokenizer is immutable and safe for concurrent use.
+type BPETokenizer struct {
+	name string
+	enc  *tiktoken.Tiktoken
+}
+
+var (
+	// bpeMu guards bpeEncodings. Encodings are loaded once per process:
+	// each holds the full vocabulary.
+	bpeMu        sync.Mutex
+	bpeEncodings = make(map[string]*tiktoken.Tiktoken)
+)
+
+// NewBPETokenizer returns the tokenizer for a bundled encoding.
+//
+// Description:
+//
+//	Loads the encoding's merge ranks from the copy embedded in the binary
+//	on first use; no network access is needed. Later calls share it.
+//
+// Inputs:
+//
+//	encoding - EncodingCL100K or EncodingO200K.
+//
+// Outputs:
+//
+//	*BPETokenizer - The tokenizer.
+//	error - Non-nil for an unknown encoding.
+func NewBPETokenizer(encoding string) (*BPETokenizer, error) {
+	bpeMu.Lock()
+	defer bpeMu.Unlock()
+	enc, ok := bpeEncodings[encoding]
+	if !ok {
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+		var err error
+		enc, err = tiktoken.GetEncoding(encoding)
+		if err != nil {
+			return nil, fmt.Errorf("loading encoding %s: %w", encoding, err)
+		}
+		bpeEncodings[encoding] = enc
+	}
+	return &BPETokenizer{name: encoding, enc: enc}, nil
+}
+
+// Family implements Tokenizer.
+func (t *BPETokenizer) Family() string {
+	return t.name
+}
+
+// CountTokens implements Tokenizer. Special token markers in text are
+// counted as ordinary text.
+func (t *BPETokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len(t.enc.EncodeOrdinary(text))
+}
+
+// TokenizerProfile describes how a tokenizer family splits text.
+//
+// Description:
+//
+//	Byte-pair tokenizers first split text into pre-tokens (words with
+//	their leading space, digit groups, punctuation runs, whitespace) and
+//	then split each pre-token into vocabulary pieces. Large vocabularies
+//	keep most words whole; small ones break them into shorter pieces. A
+//	profile captures those sizes for one family, so counts follow the
+//	family's tokenizer instead of a fixed characters-per-token ratio.
+type TokenizerProfile struct {
+	// Name identifies the family.
+	Name string
+
+	// WholeWordChars is the longest word usually kept as one token.
+	WholeWordChars int
+
+	// CharsPerPiece is the average length of the pieces longer words
+	// split into.
+	CharsPerPiece float64
+
+	// DigitsPerToken is how many digits one token holds: 3 for tokenizers
+	// that group digits, 1 for those that split every digit.
+	DigitsPerToken int
+
+	// SpacesPerToken is how many consecutive spaces or tabs one token holds.
+	SpacesPerToken int
+}
+
+// Tokenizer profiles for the model families the agent runs.
+var (
+	// TiktokenProfile estimates large-vocabulary BPE tokenizers. It is
+	// only used if a bundled encoding fails to load.
+	TiktokenProfile = TokenizerProfile{Name: "tiktoken", WholeWordChars: 8, CharsPerPiece: 4, DigitsPerToken: 3, SpacesPerToken: 8}
+
+	// SentencePieceProfile covers Llama 2, Mistral and Gemma style
+	// tokenizers, which split digits and break words sooner.
+	SentencePieceProfile = TokenizerProfile{Name: "sentencepiece", WholeWordChars: 6, CharsPerPiece: 3, DigitsPerToken: 1, SpacesPerToken: 4}
+
+	// CodeBPEProfile covers StarCoder-derived tokenizers such as Granite.
+	CodeBPEProfile = TokenizerProfile{Name: "code-bpe", WholeWordChars: 6, CharsPerPiece: 3.5, DigitsPerToken: 1, SpacesPerToken: 8}
+)
+
+// modelFamilies maps model name prefixes to a bundled encoding or, for
+// families without one, a tokenizer profile. Unknown models use
+// SentencePieceProfile, which overestimates the others and so errs toward
+// leaving room in the context window.
+var modelFamilies = []struct {
+	prefix   string
+	encoding string
+	profile  TokenizerProfile
+}{
+	{"gpt-oss", EncodingO200K, TiktokenProfile},
+	{"gpt-", EncodingO200K, TiktokenProfile},
+	{"llama3", EncodingCL100K, TiktokenProfile},
+	{"llama4", EncodingO200K, TiktokenProfile},
+	{"qwen", EncodingCL100K, TiktokenProfile},
+	{"glm", EncodingCL100K, TiktokenProfile},
+	{"deepseek", EncodingCL100K, TiktokenProfile},
+	{"granite", "", CodeBPEProfile},
+	{"starcoder", "", CodeBPEProfile},
+	{"llama2", "", SentencePieceProfile},
+	{"mistral", "", SentencePieceProfile},
+	{"mixtral", "", SentencePieceProfile},
+	{"gemma", "", SentencePieceProfile},
+}
+
+// TokenizerFor returns the tokenizer for a model name.
+//
+// Description:
+//
+//	Matches the model name, without any registry namespace, against known
+//	family prefixes. Families with a bundled encoding get a BPETokenizer;
+//	the others get a ProfileTokenizer, whose counts ContextWindow corrects
+//	from the prompt sizes Ollama measures. "granite4:micro-h" and
+//	"ibm/granite4" both use the code BPE profile.
+//
+// Inputs:
+//
+//	model - The model name as given to Ollama.
+//
+// Outputs:
+//
+//	Tokenizer - The family's tokenizer. Never nil.
+func TokenizerFor(model string) Tokenizer {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, f := range modelFamilies {
+		if !strings.HasPrefix(name, f.prefix) {
+			continue
+		}
+		if f.encoding == "" {
+			return NewProfileTokenizer(f.profile)
+		}
+		tok, err := NewBPETokenizer(f.encoding)
+		if err != nil {
+			slog.Warn("Falling back to estimated token counts",
+				slog.String("model", model),
+				slog.String("error", err.Error()),
+			)
+			return NewProfileTokenizer(f.profile)
+		}
+		return tok
+	}
+	return NewProfileTokenizer(SentencePieceProfile)
+}
+
+// ProfileTokenizer counts tokens by pre-tokenizing text the way BPE
+// tokenizers do and sizing each pre-token by a TokenizerProfile.
+//
+// Thread Safety:
+//
+//	ProfileTokenizer is immutable and safe for concurrent use.
+type ProfileTokenizer struct {
+	profile TokenizerProfile
+}
+
+// NewProfileTokenizer creates a tokenizer for a profile.
+//
+// Inputs:
+//
+//	profile - The family profile. Zero sizes are treated as 1.
+//
+// Outputs:
+//
+//	*ProfileTokenizer - The tokenizer.
+func NewProfileTokenizer(profile TokenizerProfile) *ProfileTokenizer {
+	profile.WholeWordChars = max(profile.WholeWordChars, 1)
+	profile.DigitsPerToken = max(profile.DigitsPerToken, 1)
+	profile.SpacesPerToken = max(profile.SpacesPerToken, 1)
+	if profile.CharsPerPiece < 1 {
+		profile.CharsPerPiece = 1
+	}
+	return &ProfileTokenizer{profile: profile}
+}
+
+// Family implements Tokenizer.
+func (t *ProfileTokenizer) Family() string {
+	return t.profile.Name
+}
+
+// CountTokens implements Tokenizer.
+func (t *ProfileTokenizer) CountTokens(text string) int {
+	p := t.profile
+	tokens := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n' || r == '\r':
+			// Each line break run is one token.
+			i = skipWhile(text, i, func(r rune) bool { return r == '\n' || r == '\r' })
+			tokens++
+		case r == ' ' || r == '\t':
+			end := skipWhile(text, i, func(r rune) bool { return r == ' ' || r == '\t' })
+			// A single space is part of the token that follows it.
+			if end-i == 1 && end < len(text) && !unicode.IsSpace(runeAt(text, end)) {
+				i = end
+				continue
+			}
+			tokens += ceilDiv(end-i, p.SpacesPerToken)
+			i = end
+		case unicode.IsDigit(r):
+			end := skipWhile(text, i, unicode.IsDigit)
+			tokens += ceilDiv(utf8.RuneCountInString(text[i:end]), p.DigitsPerToken)
+			i = end
+		case isIdeograph(r):
+			// CJK and similar scripts cost about one token per character.
+			tokens++
+			i += size
+		case isWordRune(r):
+			end := skipWhile(text, i, func(r rune) bool { return isWordRune(r) && !isIdeograph(r) })
+			tokens += t.wordTokens(text[i:end])
+			i = end
+		default:
+			// Punctuation and symbols merge in pairs ("()", ":=", "//").
+			end := skipWhile(text, i, isSymbol)
+			tokens += ceilDiv(utf8.RuneCountInString(text[i:end]), 2)
+			i = end
+		}
+	}
+	return tokens
+}
+
+// wordTokens sizes one word, splitting camelCase and snake_case parts
+// the way identifiers in code are usually tokenized.
+func (t *ProfileTokenizer) wordTokens(word string) int {
+	tokens := 0
+	for _, part := range splitIdentifier(word) {
+		n := utf8.RuneCountInString(part)
+		if n <= t.profile.WholeWordChars {
+			tokens++
+			continue
+		}
+		tokens += int(math.Ceil(float64(n) / t.profile.CharsPerPiece))
+	}
+	return tokens
+}
+
+// splitIdentifier splits at underscores and lower-to-upper case changes.
+func splitIdentifier(word string) []string {
+	var parts []string
+	start := 0
+	var prev rune
+	for i, r := range word {
+		switch {
+		case r == '_':
+			if i > start {
+				parts = append(parts, word[start:i])
+			}
+			parts = append(parts, "_")
+			start = i + 1
+		case i > start && unicode.IsUpper(r) && unicode.IsLower(prev):
+			parts = append(parts, word[start:i])
+			start = i
+		}
+		prev = r
+	}
+	if start < len(word) {
+		parts = append(parts, word[start:])
+	}
+	return parts
+}
+
+// skipWhile returns the index after the run of runes matching f at i.
+func skipWhile(text string, i int, f func(rune) bool) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !f(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// runeAt returns the rune starting at byte offset i.
+func runeAt(text string, i int) rune {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return r
+}
+
+// isWordRune reports whether r belongs to a word pre-token.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || r == '_'
+}
+
+// isIdeograph reports whether r is from a script without word spacing.
+func isIdeograph(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// isSymbol reports whether r is punctuation or a symbol.
+func isSymbol(r rune) bool {
+	return !isWordRune(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && r != utf8.RuneError
+}
+
+// ceilDiv returns a/b rounded up.
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// CountRequestTokens counts the prompt tokens of a request.
+//
+// Description:
+//
+//	Counts the system prompt, every message with its tool calls and tool
+//	results, per-message template overhead, and the tool definitions,
+//	which chat templates render into the prompt as JSON.
+//
+// Inputs:
+//
+//	tok - The model's tokenizer.
+//	request - The request. Nil counts as empty.
+//
+// Outputs:
+//
+//	int - The prompt token count.
+func CountRequestTokens(tok Tokenizer, request *Request) int {
+	if request == nil {
+		return 0
+	}
+	total := 0
+	if request.SystemPrompt != "" {
+		total += tok.CountTokens(request.SystemPrompt) + messageOverheadTokens
+	}
+	for i := range request.Messages {
+		total += countMessageTokens(tok, &request.Messages[i])
+	}
+	return total + countToolTokens(tok, request)
+}
+
+// countMessageTokens counts one message including its template overhead.
+// Tool messages carry their text in ToolResults, which replaces Content
+// when the message is sent.
+func countMessageTokens(tok Tokenizer, msg *Message) int {
+	total := messageOverheadTokens
+	if msg.Role == "tool" && len(msg.ToolResults) > 0 {
+		for _, tr := range msg.ToolResults {
+			total += tok.CountTokens(tr.Content)
+		}
+	} else {
+		total += tok.CountTokens(msg.Content)
+	}
+	for _, tc := range msg.ToolCalls {
+		total += tok.CountTokens(tc.Name) + tok.CountTokens(tc.Arguments)
+	}
+	return total
+}
+
+// countToolTokens counts the rendered tool definitions.
+func countToolTokens(tok Tokenizer, request *Request) int {
+	if len(request.Tools) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(request.Tools)
+	if err != nil {
+		return 0
+	}
+	return tok.CountTokens(string(data))
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func TestTokenizerFor(t *testing.T) {
+	tests := []struct {
+		model  string
+		family string
+	}{
+		{"gpt-oss:20b", EncodingO200K},
+		{"llama3.1:8b", EncodingCL100K},
+		{"llama4:scout", EncodingO200K},
+		{"Qwen3:14b", EncodingCL100K},
+		{"glm-4.7-flash", EncodingCL100K},
+		{"granite4:micro-h", "code-bpe"},
+		{"ibm/granite4:tiny-h", "code-bpe"},
+		{"mistral:7b", "sentencepiece"},
+		{"gemma3:4b", "sentencepiece"},
+		{"some-new-model", "sentencepiece"},
+	}
+	for _, tt := range tests {
+		if got := TokenizerFor(tt.model).Family(); got != tt.family {
+			t.Errorf("TokenizerFor(%q) = %s, want %s", tt.model, got, tt.family)
+		}
+	}
+}
+
+// referenceTexts are prompt-shaped samples with their token counts under the
+// real Llama 3 (byte-level BPE) and Gemma 2 (SentencePiece) tokenizers, as
+// produced by the tokenizer vocabularies in Ollama's test data.
+var referenceTexts = []struct {
+	name   string
+	text   string
+	llama3 int
+	gemma2 int
+}{
+	{"prose", "The agent reads the failing test, finds the function that returns the wrong value, and proposes a minimal fix with an explanation of the root cause.", 29, 29},
+	{"go", "func (s *Server) handle(w http.ResponseWriter, r *http.Request) {\n\tif err := s.db.Ping(r.Context()); err != nil {\n\t\thttp.Error(w, err.Error(), http.StatusInternalServerError)\n\t\treturn\n\t}\n\tw.WriteHeader(http.StatusNoContent)\n}\n", 55, 78},
+	{"python", "def parse_config(path: str) -> dict:\n    with open(path) as f:\n        data = yaml.safe_load(f)\n    return {k.lower(): v for k, v in data.items() if v is not None}\n", 47, 59},
+	{"json", `{"tool":"find_callers","params":{"function_name":"ValidateToken","limit":20,"include_tests":false}}`, 24, 28},
+	{"log line", "2025-01-14T09:32:17.845Z INFO request_id=7f3a9c2e latency_ms=1342 status=200 bytes=48213 path=/v1/trace/agent/run", 48, 67},
+	{"identifiers", "parseHTTPRequest_body getUserByID XMLHttpRequest snake_case_identifier_name", 10, 15},
+	{"mixed cjk", "错误：无法连接到数据库。Please retry after 30 seconds.", 14, 15},
+	{"stack trace", "panic: runtime error: invalid memory address or nil pointer dereference\n[signal SIGSEGV: segmentation violation code=0x1 addr=0x18 pc=0x4a2f1c]\n\ngoroutine 1 [running]:\nmain.(*Server).handle(0x0, {0x7d3e40, 0xc0001a2000})\n\t/app/server.go:42 +0x1c\n", 90, 103},
+}
+
+func TestBPETokenizer_Encode(t *testing.T) {
+	tests := []struct {
+		encoding string
+		want     []int
+	}{
+		// cl100k_base and Llama 3 share their first 100k merges, so the IDs match.
+		{EncodingCL100K, []int{15339, 1917}},
+		{EncodingO200K, []int{24912, 2375}},
+	}
+	for _, tt := range tests {
+		tok, err := NewBPETokenizer(tt.encoding)
+		if err != nil {
+			t.Fatalf("NewBPETokenizer(%s): %v", tt.encoding, err)
+		}
+		got := tok.enc.EncodeOrdinary("hello world")
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: hello world = %v, want %v", tt.encoding, got, tt.want)
+		}
+	}
+
+	if _, err := NewBPETokenizer("no_such_encoding"); err == nil {
+		t.Error("unknown encoding should fail")
+	}
+}
+
+// TestTokenizer_ReferenceCounts compares the counts used for each family with
+// the reference tokenizations. The BPE encodings must match Llama 3 to within
+// a token per ten; the SentencePiece fallback may overcount but never
+// undercount, since an undercount lets a prompt overflow num_ctx.
+func TestTokenizer_ReferenceCounts(t *testing.T) {
+	cl100k, err := NewBPETokenizer(EncodingCL100K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentencepiece := NewProfileTokenizer(SentencePieceProfile)
+
+	for _, ref := range referenceTexts {
+		t.Run(ref.name, func(t *testing.T) {
+			if got := cl100k.CountTokens(ref.text); got < ref.llama3 || got > ref.llama3+ceilDiv(ref.llama3, 10) {
+				t.Errorf("cl100k_base = %d, want %d (llama3)", got, ref.llama3)
+			}
+			if got := sentencepiece.CountTokens(ref.text); got < ref.gemma2 || got > 2*ref.gemma2 {
+				t.Errorf("sentencepiece = %d, want between %d and %d (gemma2)", got, ref.gemma2, 2*ref.gemma2)
+			}
+		})
+	}
+}
+
+func TestProfileTokenizer_CountTokens(t *testing.T) {
+	tiktoken := NewProfileTokenizer(TiktokenProfile)
+	sentencepiece := NewProfileTokenizer(SentencePieceProfile)
+
+	tests := []struct {
+		name string
+		text string
+		tik  int
+		sp   int
+	}{
+		{"empty", "", 0, 0},
+		{"words with leading spaces", "the quick brown fox", 4, 4},
+		{"long word splits", "internationalization", 5, 7},
+		{"digits group", "1234567", 3, 7},
+		{"identifier parts", "parseHTTPRequest_body", 6, 7},
+		{"punctuation pairs", "f(x) := y;", 7, 7},
+		{"indentation", "\n        return", 3, 4},
+		{"cjk per rune", "你好世界", 4, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tiktoken.CountTokens(tt.text); got != tt.tik {
+				t.Errorf("tiktoken = %d, want %d", got, tt.tik)
+			}
+			if got := sentencepiece.CountTokens(tt.text); got != tt.sp {
+				t.Errorf("sentencepiece = %d, want %d", got, tt.sp)
+			}
+		})
+	}
+}
+
+// TestProfileTokenizer_CodeDensity checks the counts against the char/4
+// heuristic they replace: Go source is denser than prose, so counting by
+// characters underestimates it.
+func TestProfileTokenizer_CodeDensity(t *testing.T) {
+	code := strings.Repeat("func (s *Server) handle(w http.ResponseWriter, r *http.Request) {\n\tif err := s.db.Ping(r.Context()); err != nil {\n\t\thttp.Error(w, err.Error(), 500)\n\t}\n}\n", 20)
+	heuristic := len(code) / 4
+	for _, profile := range []TokenizerProfile{TiktokenProfile, SentencePieceProfile, CodeBPEProfile} {
+		got := NewProfileTokenizer(profile).CountTokens(code)
+		if got <= heuristic {
+			t.Errorf("%s: %d tokens, want more than the char/4 estimate %d", profile.Name, got, heuristic)
+		}
+	}
+}
+
+func TestCountRequestTokens(t *testing.T) {
+	tok := NewProfileTokenizer(TiktokenProfile)
+	if got := CountRequestTokens(tok, nil); got != 0 {
+		t.Errorf("nil request = %d, want 0", got)
+	}
+
+	request := &Request{
+		SystemPrompt: "be brief",
+		Messages: []Message{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", ToolCalls: []ToolCall{{Name: "read", Arguments: `{}`}}},
+			{Role: "tool", Content: "ignored", ToolResults: []ToolCallResult{{Content: "ok"}}},
+		},
+	}
+	// system 2+4, user 1+4, assistant 4+1+1, tool 4+1.
+	if got, want := CountRequestTokens(tok, request), 22; got != want {
+		t.Errorf("CountRequestTokens = %d, want %d", got, want)
+	}
+
+	request.Tools = []tools.ToolDefinition{{Name: "read", Description: "Read a file"}}
+	if CountRequestTokens(tok, request) <= 22 {
+		t.Error("tool definitions should count toward the prompt")
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/google/uuid"
+)
+
+// ToolFormat identifies how tool definitions are presented to a model.
+type ToolFormat string
+
+const (
+	// ToolFormatOpenAI is the OpenAI "tools" array of
+	// {"type": "function", "function": {...}} objects. Ollama's native
+	// tool calling uses the same shape.
+	ToolFormatOpenAI ToolFormat = "openai"
+
+	// ToolFormatAnthropic is the Anthropic Messages "tools" array of
+	// {"name", "description", "input_schema"} objects, answered with
+	// tool_use content blocks.
+	ToolFormatAnthropic ToolFormat = "anthropic"
+
+	// ToolFormatPrompt describes the tools in the system prompt and asks
+	// for a JSON object reply, for models without native tool calling.
+	ToolFormatPrompt ToolFormat = "prompt"
+)
+
+// ToolFormatForBackend returns the tool format for an LLM backend.
+//
+// Description:
+//
+//	Maps the backend names used by LLM_BACKEND_TYPE to their native tool
+//	format. Backends without native tool calling (the llama.cpp "local"
+//	server, Hugging Face transformers, unknown backends) use prompting.
+//	A backend that supports tools may still serve a model that does not;
+//	callers that can ask (see OllamaAdapter) should downgrade to
+//	ToolFormatPrompt for such models.
+//
+// Inputs:
+//
+//	backend - The backend name (e.g., "ollama", "openai", "claude").
+//
+// Outputs:
+//
+//	ToolFormat - The format to send tools in.
+func ToolFormatForBackend(backend string) ToolFormat {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "openai", "ollama":
+		return ToolFormatOpenAI
+	case "anthropic", "claude":
+		return ToolFormatAnthropic
+	default:
+		return ToolFormatPrompt
+	}
+}
+
+// OpenAITool is a tool definition in OpenAI (and Ollama) format.
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function inside an OpenAITool.
+type OpenAIFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// AnthropicTool is a tool definition in Anthropic Messages format.
+type AnthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// OpenAITools converts tool definitions to OpenAI format.
+//
+// Inputs:
+//
+//	defs - The tool definitions.
+//
+// Outputs:
+//
+//	[]OpenAITool - One entry per definition, nil if defs is empty.
+func OpenAITools(defs []tools.ToolDefinition) []OpenAITool {
+	if len(defs) == 0 {
+		return nil
+	}
+	result := make([]OpenAITool, 0, len(defs))
+	for _, def := range defs {
+		result = append(result, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunction{
+				Name:        def.Name,
+				Description: def.Description,
+				Parameters:  ToolJSONSchema(def),
+			},
+		})
+	}
+	return result
+}
+
+// AnthropicTools converts tool definitions to Anthropic format.
+//
+// Inputs:
+//
+//	defs - The tool definitions.
+//
+// Outputs:
+//
+//	[]AnthropicTool - One entry per definition, nil if defs is empty.
+func AnthropicTools(defs []tools.ToolDefinition) []AnthropicTool {
+	if len(defs) == 0 {
+		return nil
+	}
+	result := make([]AnthropicTool, 0, len(defs))
+	for _, def := range defs {
+		result = append(result, AnthropicTool{
+			Name:        def.Name,
+			Description: def.Description,
+			InputSchema: ToolJSONSchema(def),
+		})
+	}
+	return result
+}
+
+// ToolJSONSchema returns the JSON Schema object for a tool's parameters.
+//
+// Description:
+//
+//	Produces {"type": "object", "properties": {...}, "required": [...]}
+//	with nested items and properties, enums, defaults and bounds. The
+//	required list is sorted so the output is stable.
+//
+// Inputs:
+//
+//	def - The tool definition.
+//
+// Outputs:
+//
+//	map[string]any - The schema. Never nil.
+func ToolJSONSchema(def tools.ToolDefinition) map[string]any {
+	return objectSchema(def.Parameters)
+}
+
+// objectSchema builds an object schema from named parameters.
+func objectSchema(params map[string]tools.ParamDef) map[string]any {
+	properties := make(map[string]any, len(params))
+	var required []string
+	for name, p := range params {
+		properties[name] = paramSchema(p)
+		if p.Required {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// paramSchema builds the schema of one parameter.
+func paramSchema(p tools.ParamDef) map[string]any {
+	if p.Type == tools.ParamTypeObject && len(p.Properties) > 0 {
+		schema := objectSchema(p.Properties)
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+		return schema
+	}
+
+	schema := map[string]any{"type": string(p.Type)}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if p.MinLength > 0 {
+		schema["minLength"] = p.MinLength
+	}
+	if p.MaxLength > 0 {
+		schema["maxLength"] = p.MaxLength
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.Items != nil {
+		schema["items"] = paramSchema(*p.Items)
+	}
+	return schema
+}
+
+// ToolPrompt renders tool definitions as system prompt instructions for
+// models without native tool calling.
+//
+// Description:
+//
+//	Lists each tool as a JSON object with its parameter schema and asks
+//	the model to call a tool by replying with only
+//	{"tool": "<name>", "arguments": {...}}. ParsePromptToolCall reads
+//	such replies back.
+//
+// Inputs:
+//
+//	defs - The tool definitions.
+//
+// Outputs:
+//
+//	string - The instructions, empty if defs is empty.
+func ToolPrompt(defs []tools.ToolDefinition) string {
+	if len(defs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Tools\n\n")
+	b.WriteString("You can call the tools below. To call a tool, reply with only a JSON object and nothing else:\n")
+	b.WriteString(`{"tool": "<tool name>", "arguments": {<parameters>}}`)
+	b.WriteString("\n\nThe result will be sent back to you. When you have the answer, reply in plain text without JSON.\n\nAvailable tools:\n")
+	for _, def := range defs {
+		line, err := json.Marshal(map[string]any{
+			"name":        def.Name,
+			"description": def.Description,
+			"parameters":  ToolJSONSchema(def),
+		})
+		if err != nil {
+			continue
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// promptToolCall is the reply format ToolPrompt asks for.
+type promptToolCall struct {
+	Tool      string          `json:"tool"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ParsePromptToolCall extracts a tool call from a reply to ToolPrompt.
+//
+// Description:
+//
+//	Looks for a JSON object with a "tool" (or "name") field, either as the
+//	whole reply or inside a ```json fence, and only accepts tools that
+//	were offered.
+//
+// Inputs:
+//
+//	content - The model's reply.
+//	defs - The tools that were offered.
+//
+// Outputs:
+//
+//	*ToolCall - The call, or nil if the reply is not a tool call.
+func ParsePromptToolCall(content string, defs []tools.ToolDefinition) *ToolCall {
+	text := strings.TrimSpace(content)
+	if start := strings.Index(text, "```"); start >= 0 {
+		body := text[start+3:]
+		body = strings.TrimPrefix(body, "json")
+		if end := strings.Index(body, "```"); end >= 0 {
+			text = strings.TrimSpace(body[:end])
+		}
+	}
+	if !strings.HasPrefix(text, "{") {
+		return nil
+	}
+
+	var call promptToolCall
+	dec := json.NewDecoder(strings.NewReader(text))
+	if err := dec.Decode(&call); err != nil {
+		return nil
+	}
+	name := call.Tool
+	if name == "" {
+		name = call.Name
+	}
+	offered := false
+	for _, def := range defs {
+		if def.Name == name {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		return nil
+	}
+
+	args := "{}"
+	if len(call.Arguments) > 0 && string(call.Arguments) != "null" {
+		args = string(call.Arguments)
+	}
+	return &ToolCall{
+		ID:        "json-" + uuid.NewString()[:8],
+		Name:      name,
+		Arguments: args,
+	}
+}
+
+// PromptToolRequest rewrites a request with tools for a model without
+// native tool calling.
+//
+// Description:
+//
+//	Appends ToolPrompt to the system prompt, removes the tool definitions
+//	and tool choice, and replays earlier tool calls and results as plain
+//	assistant and user messages in the same JSON format. The request is
+//	not modified.
+//
+// Inputs:
+//
+//	request - The request. Must not be nil.
+//
+// Outputs:
+//
+//	*Request - The rewritten copy, or request itself if it has no tools.
+func PromptToolRequest(request *Request) *Request {
+	if len(request.Tools) == 0 {
+		return request
+	}
+	out := *request
+	out.SystemPrompt = strings.TrimSpace(request.SystemPrompt + "\n\n" + ToolPrompt(request.Tools))
+	out.Tools = nil
+	out.ToolChoice = nil
+	out.Messages = make([]Message, 0, len(request.Messages))
+
+	for _, msg := range request.Messages {
+		switch {
+		case len(msg.ToolCalls) > 0:
+			var parts []string
+			if msg.Content != "" {
+				parts = append(parts, msg.Content)
+			}
+			for _, tc := range msg.ToolCalls {
+				args := tc.Arguments
+				if !json.Valid([]byte(args)) {
+					args = "{}"
+				}
+				parts = append(parts, fmt.Sprintf(`{"tool": %q, "arguments": %s}`, tc.Name, args))
+			}
+			out.Messages = append(out.Messages, Message{Role: "assistant", Content: strings.Join(parts, "\n")})
+		case msg.Role == "tool":
+			content := msg.Content
+			if len(msg.ToolResults) > 0 {
+				var parts []string
+				for _, tr := range msg.ToolResults {
+					parts = append(parts, tr.Content)
+				}
+				content = strings.Join(parts, "\n")
+			}
+			out.Messages = append(out.Messages, Message{Role: "user", Content: "Tool result:\n" + content})
+		default:
+			out.Messages = append(out.Messages, Message{Role: msg.Role, Content: msg.Content})
+		}
+	}
+	return &out
+}
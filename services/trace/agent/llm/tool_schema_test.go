@@ -0,0 +1,156 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func schemaTestTools() []tools.ToolDefinition {
+	limit := 50.0
+	return []tools.ToolDefinition{{
+		Name:        "find_callers",
+		Description: "Find callers of a function",
+		Parameters: map[string]tools.ParamDef{
+			"symbol": {Type: tools.ParamTypeString, Description: "Function name", Required: true},
+			"limit":  {Type: tools.ParamTypeInt, Default: 10, Maximum: &limit},
+			"kinds": {Type: tools.ParamTypeArray, Items: &tools.ParamDef{
+				Type: tools.ParamTypeString, Enum: []any{"call", "ref"},
+			}},
+			"filter": {Type: tools.ParamTypeObject, Properties: map[string]tools.ParamDef{
+				"package": {Type: tools.ParamTypeString, Required: true},
+			}},
+		},
+	}}
+}
+
+func TestToolFormatForBackend(t *testing.T) {
+	tests := map[string]ToolFormat{
+		"ollama":          ToolFormatOpenAI,
+		"OpenAI":          ToolFormatOpenAI,
+		"claude":          ToolFormatAnthropic,
+		"anthropic":       ToolFormatAnthropic,
+		"local":           ToolFormatPrompt,
+		"hf_transformers": ToolFormatPrompt,
+		"":                ToolFormatPrompt,
+	}
+	for backend, want := range tests {
+		if got := ToolFormatForBackend(backend); got != want {
+			t.Errorf("ToolFormatForBackend(%q) = %s, want %s", backend, got, want)
+		}
+	}
+}
+
+func TestProviderToolFormats(t *testing.T) {
+	wantSchema := `{"properties":{` +
+		`"filter":{"properties":{"package":{"type":"string"}},"required":["package"],"type":"object"},` +
+		`"kinds":{"items":{"enum":["call","ref"],"type":"string"},"type":"array"},` +
+		`"limit":{"default":10,"maximum":50,"type":"integer"},` +
+		`"symbol":{"description":"Function name","type":"string"}},` +
+		`"required":["symbol"],"type":"object"}`
+
+	openai, err := json.Marshal(OpenAITools(schemaTestTools()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOpenAI := `[{"type":"function","function":{"name":"find_callers","description":"Find callers of a function","parameters":` + wantSchema + `}}]`
+	if string(openai) != wantOpenAI {
+		t.Errorf("OpenAI tools =\n%s\nwant\n%s", openai, wantOpenAI)
+	}
+
+	anthropic, err := json.Marshal(AnthropicTools(schemaTestTools()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAnthropic := `[{"name":"find_callers","description":"Find callers of a function","input_schema":` + wantSchema + `}]`
+	if string(anthropic) != wantAnthropic {
+		t.Errorf("Anthropic tools =\n%s\nwant\n%s", anthropic, wantAnthropic)
+	}
+
+	if OpenAITools(nil) != nil || AnthropicTools(nil) != nil || ToolPrompt(nil) != "" {
+		t.Error("no tools should convert to nothing")
+	}
+}
+
+func TestParsePromptToolCall(t *testing.T) {
+	defs := schemaTestTools()
+	tests := []struct {
+		name    string
+		content string
+		tool    string
+		args    string
+	}{
+		{"bare object", `{"tool": "find_callers", "arguments": {"symbol": "main"}}`, "find_callers", `{"symbol": "main"}`},
+		{"fenced", "Calling:\n```json\n{\"name\": \"find_callers\", \"arguments\": {}}\n```", "find_callers", `{}`},
+		{"no arguments", `{"tool": "find_callers"}`, "find_callers", `{}`},
+		{"unknown tool", `{"tool": "rm_rf", "arguments": {}}`, "", ""},
+		{"plain answer", "main is called from init.", "", ""},
+		{"invalid json", `{"tool": "find_callers", `, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := ParsePromptToolCall(tt.content, defs)
+			if tt.tool == "" {
+				if call != nil {
+					t.Errorf("got call %+v, want none", call)
+				}
+				return
+			}
+			if call == nil || call.Name != tt.tool || call.Arguments != tt.args || !strings.HasPrefix(call.ID, "json-") {
+				t.Errorf("call = %+v, want %s %s", call, tt.tool, tt.args)
+			}
+		})
+	}
+}
+
+func TestPromptToolRequest(t *testing.T) {
+	request := &Request{
+		SystemPrompt: "You answer code questions.",
+		Tools:        schemaTestTools(),
+		ToolChoice:   ToolChoiceRequired("find_callers"),
+		Messages: []Message{
+			{Role: "user", Content: "Who calls main?"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "1", Name: "find_callers", Arguments: `{"symbol":"main"}`}}},
+			{Role: "tool", ToolResults: []ToolCallResult{{ToolCallID: "1", Content: "init.go:3"}}},
+		},
+	}
+
+	out := PromptToolRequest(request)
+	if out.Tools != nil || out.ToolChoice != nil {
+		t.Error("tools should move into the prompt")
+	}
+	if !strings.HasPrefix(out.SystemPrompt, "You answer code questions.\n\n## Tools") ||
+		!strings.Contains(out.SystemPrompt, `"name":"find_callers"`) {
+		t.Errorf("system prompt = %q", out.SystemPrompt)
+	}
+	want := []Message{
+		{Role: "user", Content: "Who calls main?"},
+		{Role: "assistant", Content: `{"tool": "find_callers", "arguments": {"symbol":"main"}}`},
+		{Role: "user", Content: "Tool result:\ninit.go:3"},
+	}
+	for i := range want {
+		if out.Messages[i].Role != want[i].Role || out.Messages[i].Content != want[i].Content {
+			t.Errorf("message %d = %+v, want %+v", i, out.Messages[i], want[i])
+		}
+	}
+	if len(request.Tools) == 0 || len(request.Messages[1].ToolCalls) == 0 {
+		t.Error("PromptToolRequest modified its input")
+	}
+
+	plain := &Request{Messages: []Message{{Role: "user", Content: "hi"}}}
+	if PromptToolRequest(plain) != plain {
+		t.Error("a request without tools should be returned as is")
+	}
+}
@@ -19,7 +19,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
 )
 
 // =============================================================================
@@ -302,6 +304,9 @@ type DefaultAgentLoop struct {
 
 	// activeSessions tracks currently running sessions.
 	activeSessions int
+
+	// editStore keeps the undo history of session file edits (optional).
+	editStore *cas.Store
 }
 
 // DefaultLoopOption configures a DefaultAgentLoop.
@@ -337,6 +342,28 @@ func WithSessionStore(store SessionStore) DefaultLoopOption {
 	}
 }
 
+// WithEditStore keeps the undo history of session file edits in a blob
+// store.
+//
+// Description:
+//
+//	Sessions save the content and order of their file edits in store, so
+//	they can still be undone after the process restarts. Without it, edit
+//	history is kept in memory.
+//
+// Inputs:
+//
+//	store - The blob store, typically the one the CLI keeps backups in.
+//
+// Outputs:
+//
+//	DefaultLoopOption - The configuration function.
+func WithEditStore(store *cas.Store) DefaultLoopOption {
+	return func(l *DefaultAgentLoop) {
+		l.editStore = store
+	}
+}
+
 // WithPhaseRegistry sets the phase registry.
 //
 // Inputs:
@@ -459,6 +486,14 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 		return nil, ErrSessionInProgress
 	}
 	defer session.Release()
+	session.setEditStore(l.editStore)
+
+	// Hold the session against other replicas when the store is shared
+	unlock, err := l.lockSession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	// Check concurrent session limit
 	if err := l.acquireSlot(); err != nil {
@@ -467,8 +502,9 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 	}
 	defer l.releaseSlot()
 
-	// Store the session
+	// Store the session, and again with its final state when the run ends
 	l.sessions.Put(session)
+	defer l.sessions.Put(session)
 
 	// Store the query
 	session.LastQuery = query
@@ -507,10 +543,18 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 //
 // Thread Safety: This method is safe for concurrent use.
 func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clarification string) (*RunResult, error) {
+	// Lock before loading so a shared store returns the latest state
+	unlock, err := l.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	session, ok := l.sessions.Get(sessionID)
 	if !ok {
 		return nil, ErrSessionNotFound
 	}
+	session.setEditStore(l.editStore)
 
 	currentState := session.GetState()
 
@@ -529,6 +573,7 @@ func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clari
 		return nil, err
 	}
 	defer l.releaseSlot()
+	defer l.sessions.Put(session)
 
 	// Handle based on current state
 	if currentState == StateComplete {
@@ -617,6 +662,7 @@ func (l *DefaultAgentLoop) Abort(ctx context.Context, sessionID string) error {
 		Type:  "abort",
 		Error: "session aborted by user",
 	})
+	l.sessions.Put(session)
 
 	return nil
 }
@@ -711,10 +757,46 @@ func (l *DefaultAgentLoop) GetSession(sessionID string) (*Session, error) {
 	if !ok {
 		return nil, ErrSessionNotFound
 	}
+	session.setEditStore(l.editStore)
 
 	return session, nil
 }
 
+// OpenEditHistory opens the saved edit history of a session that is no
+// longer loaded.
+//
+// Description:
+//
+//	Sessions kept in process memory are gone after a restart, but their
+//	edit history is still in the edit store. This opens it so the edits
+//	can be undone.
+//
+// Inputs:
+//
+//	sessionID - The session ID.
+//
+// Outputs:
+//
+//	*undo.Stack - The saved history.
+//	error - ErrSessionNotFound if there is no edit store or it holds no
+//	        history for the session; otherwise non-nil if the history
+//	        cannot be read.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (l *DefaultAgentLoop) OpenEditHistory(sessionID string) (*undo.Stack, error) {
+	if l.editStore == nil || sessionID == "" {
+		return nil, ErrSessionNotFound
+	}
+	stack, err := undo.Open(undo.Config{Store: l.editStore, Namespace: sessionID})
+	if err != nil {
+		return nil, err
+	}
+	if done, undone := stack.Len(); done == 0 && undone == 0 {
+		return nil, ErrSessionNotFound
+	}
+	return stack, nil
+}
+
 // ListSessions implements AgentLoop.
 //
 // Description:
@@ -750,6 +832,16 @@ func (l *DefaultAgentLoop) ListSessions() []*SessionSummary {
 	return summaries
 }
 
+// lockSession locks the session against other replicas if the session
+// store is shared, and is a no-op otherwise.
+func (l *DefaultAgentLoop) lockSession(ctx context.Context, sessionID string) (func(), error) {
+	locker, ok := l.sessions.(SessionLocker)
+	if !ok {
+		return func() {}, nil
+	}
+	return locker.Lock(ctx, sessionID)
+}
+
 // validateRunInput validates inputs for Run.
 func (l *DefaultAgentLoop) validateRunInput(session *Session, query string) error {
 	if session == nil {
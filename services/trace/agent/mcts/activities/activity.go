@@ -238,6 +238,10 @@ type ActivityResult struct {
 	// PartialSuccess is true if some algorithms succeeded.
 	PartialSuccess bool
 
+	// PartialApplied names the cancelled algorithms whose partial deltas
+	// met the activity's quality threshold and were applied.
+	PartialApplied []string
+
 	// Metrics contains activity-specific metrics.
 	Metrics map[string]float64
 }
@@ -264,17 +268,19 @@ func (r *ActivityResult) FailureCount() int {
 
 // BaseActivity provides common activity functionality.
 type BaseActivity struct {
-	name       string
-	timeout    time.Duration
-	algorithms []algorithms.Algorithm
+	name              string
+	timeout           time.Duration
+	algorithms        []algorithms.Algorithm
+	minPartialQuality float64
 }
 
 // NewBaseActivity creates a new base activity.
 func NewBaseActivity(name string, timeout time.Duration, algos ...algorithms.Algorithm) *BaseActivity {
 	return &BaseActivity{
-		name:       name,
-		timeout:    timeout,
-		algorithms: algos,
+		name:              name,
+		timeout:           timeout,
+		algorithms:        algos,
+		minPartialQuality: DefaultMinPartialQuality,
 	}
 }
 
@@ -293,12 +299,26 @@ func (a *BaseActivity) Algorithms() []algorithms.Algorithm {
 	return a.algorithms
 }
 
+// MinPartialQuality returns the quality a partial result needs for its
+// delta to be applied.
+func (a *BaseActivity) MinPartialQuality() float64 {
+	return a.minPartialQuality
+}
+
+// SetMinPartialQuality sets the quality a partial result needs for its
+// delta to be applied. Zero or less never applies partial deltas.
+func (a *BaseActivity) SetMinPartialQuality(q float64) {
+	a.minPartialQuality = q
+}
+
 // RunAlgorithms executes all algorithms in parallel.
 //
 // Description:
 //
 //	Runs all configured algorithms in parallel using the Runner,
-//	collects results, and merges deltas.
+//	collects results, and merges deltas. Deltas of algorithms cancelled
+//	with a partial result are merged too when the result's Quality is at
+//	least the activity's minimum partial quality.
 //
 // Inputs:
 //   - ctx: Context for cancellation.
@@ -385,6 +405,8 @@ func (a *BaseActivity) RunAlgorithms(
 		}
 	}
 
+	delta = a.mergePartialDeltas(&result, delta, algoResults)
+
 	result.AlgorithmResults = algoResults
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(startTime)
@@ -398,15 +420,53 @@ func (a *BaseActivity) RunAlgorithms(
 	result.Metrics["algorithms_total"] = float64(len(a.algorithms))
 	result.Metrics["algorithms_success"] = float64(successCount)
 	result.Metrics["algorithms_failed"] = float64(result.FailureCount())
+	result.Metrics["algorithms_partial_applied"] = float64(len(result.PartialApplied))
 	result.Metrics["duration_ms"] = float64(result.Duration.Milliseconds())
 
 	return result, delta, nil
 }
 
+// mergePartialDeltas adds the deltas of trustworthy partial results to
+// delta, recording the algorithms in result.PartialApplied.
+func (a *BaseActivity) mergePartialDeltas(
+	result *ActivityResult,
+	delta crs.Delta,
+	algoResults []*algorithms.Result,
+) crs.Delta {
+	if a.minPartialQuality <= 0 {
+		return delta
+	}
+
+	var deltas []crs.Delta
+	if delta != nil {
+		deltas = append(deltas, delta)
+	}
+	for _, ar := range algoResults {
+		if !ar.Partial || ar.Delta == nil || ar.Quality < a.minPartialQuality {
+			continue
+		}
+		deltas = append(deltas, ar.Delta)
+		result.PartialApplied = append(result.PartialApplied, ar.Name)
+	}
+
+	switch {
+	case len(result.PartialApplied) == 0:
+		return delta
+	case len(deltas) == 1:
+		return deltas[0]
+	default:
+		return crs.NewCompositeDelta(deltas...)
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Configuration
 // -----------------------------------------------------------------------------
 
+// DefaultMinPartialQuality is the default quality a partial algorithm
+// result needs for its delta to be applied.
+const DefaultMinPartialQuality = 0.9
+
 // ActivityConfig provides common configuration for activities.
 type ActivityConfig struct {
 	// Timeout overrides the default activity timeout.
@@ -420,6 +480,11 @@ type ActivityConfig struct {
 
 	// MaxConcurrentAlgorithms limits parallel algorithm execution.
 	MaxConcurrentAlgorithms int
+
+	// MinPartialQuality is the quality (0.0-1.0) a cancelled algorithm's
+	// partial result needs for its delta to be applied. Zero never applies
+	// partial deltas.
+	MinPartialQuality float64
 }
 
 // DefaultActivityConfig returns the default activity configuration.
@@ -429,6 +494,7 @@ func DefaultActivityConfig() *ActivityConfig {
 		EnableMetrics:           true,
 		EnableTracing:           true,
 		MaxConcurrentAlgorithms: 10,
+		MinPartialQuality:       DefaultMinPartialQuality,
 	}
 }
 
@@ -440,5 +506,8 @@ func (c *ActivityConfig) Validate() error {
 	if c.MaxConcurrentAlgorithms < 0 {
 		return ErrInvalidConfig
 	}
+	if c.MinPartialQuality < 0 || c.MinPartialQuality > 1 {
+		return ErrInvalidConfig
+	}
 	return nil
 }
@@ -11,10 +11,14 @@
 package activities
 
 import (
+	"context"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
 )
 
 func TestPriority(t *testing.T) {
@@ -148,3 +152,61 @@ func TestActivityConfig(t *testing.T) {
 		}
 	})
 }
+
+// anytimeAlgorithm blocks until cancelled, then returns a partial delta
+// with a fixed quality.
+type anytimeAlgorithm struct {
+	quality float64
+	delta   crs.Delta
+}
+
+func (a *anytimeAlgorithm) Name() string { return "anytime" }
+
+func (a *anytimeAlgorithm) Process(ctx context.Context, _ crs.Snapshot, _ any) (any, crs.Delta, error) {
+	<-ctx.Done()
+	return "partial", a.delta, ctx.Err()
+}
+
+func (a *anytimeAlgorithm) PartialQuality(_, _ any) float64     { return a.quality }
+func (a *anytimeAlgorithm) Timeout() time.Duration              { return 20 * time.Millisecond }
+func (a *anytimeAlgorithm) InputType() reflect.Type             { return reflect.TypeOf("") }
+func (a *anytimeAlgorithm) OutputType() reflect.Type            { return reflect.TypeOf("") }
+func (a *anytimeAlgorithm) ProgressInterval() time.Duration     { return time.Second }
+func (a *anytimeAlgorithm) SupportsPartialResults() bool        { return true }
+func (a *anytimeAlgorithm) Properties() []eval.Property         { return nil }
+func (a *anytimeAlgorithm) Metrics() []eval.MetricDefinition    { return nil }
+func (a *anytimeAlgorithm) HealthCheck(_ context.Context) error { return nil }
+
+func TestBaseActivity_PartialDeltas(t *testing.T) {
+	snapshot := crs.New(nil).Snapshot()
+	makeInput := func(algorithms.Algorithm) any { return "x" }
+
+	tests := []struct {
+		name       string
+		quality    float64
+		minQuality float64
+		applied    bool
+	}{
+		{"above threshold", 0.95, DefaultMinPartialQuality, true},
+		{"below threshold", 0.5, DefaultMinPartialQuality, false},
+		{"disabled", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := crs.NewProofDelta(crs.SignalSourceHard, nil)
+			activity := NewBaseActivity("test", time.Second, &anytimeAlgorithm{quality: tt.quality, delta: delta})
+			activity.SetMinPartialQuality(tt.minQuality)
+
+			result, got, err := activity.RunAlgorithms(context.Background(), snapshot, makeInput)
+			if err != nil {
+				t.Fatalf("RunAlgorithms: %v", err)
+			}
+			if applied := got == crs.Delta(delta); applied != tt.applied {
+				t.Errorf("delta applied = %v, want %v", applied, tt.applied)
+			}
+			if applied := len(result.PartialApplied) == 1; applied != tt.applied {
+				t.Errorf("PartialApplied = %v", result.PartialApplied)
+			}
+		})
+	}
+}
@@ -89,7 +89,7 @@ func NewAwarenessActivity(config *AwarenessConfig) *AwarenessActivity {
 	dominators := graph.NewDominators(config.DominatorsConfig)
 	vf2 := graph.NewVF2(config.VF2Config)
 
-	return &AwarenessActivity{
+	activity := &AwarenessActivity{
 		BaseActivity: NewBaseActivity(
 			"awareness",
 			config.Timeout,
@@ -102,6 +102,8 @@ func NewAwarenessActivity(config *AwarenessConfig) *AwarenessActivity {
 		dominators: dominators,
 		vf2:        vf2,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
@@ -89,7 +89,7 @@ func NewConstraintActivity(config *ConstraintConfig) *ConstraintActivity {
 	ac3 := constraints.NewAC3(config.AC3Config)
 	semanticBackprop := constraints.NewSemanticBackprop(config.SemanticBackpropConfig)
 
-	return &ConstraintActivity{
+	activity := &ConstraintActivity{
 		BaseActivity: NewBaseActivity(
 			"constraint",
 			config.Timeout,
@@ -102,6 +102,8 @@ func NewConstraintActivity(config *ConstraintConfig) *ConstraintActivity {
 		ac3:              ac3,
 		semanticBackprop: semanticBackprop,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
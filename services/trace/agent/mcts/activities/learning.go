@@ -83,7 +83,7 @@ func NewLearningActivity(config *LearningConfig) *LearningActivity {
 	cdcl := search.NewCDCL(config.CDCLConfig)
 	watched := search.NewWatchedLiterals(config.WatchedConfig)
 
-	return &LearningActivity{
+	activity := &LearningActivity{
 		BaseActivity: NewBaseActivity(
 			"learning",
 			config.Timeout,
@@ -94,6 +94,8 @@ func NewLearningActivity(config *LearningConfig) *LearningActivity {
 		cdcl:    cdcl,
 		watched: watched,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
@@ -82,7 +82,7 @@ func NewPlanningActivity(config *PlanningConfig) *PlanningActivity {
 	htn := planning.NewHTN(config.HTNConfig)
 	blackboard := planning.NewBlackboard(config.BlackboardConfig)
 
-	return &PlanningActivity{
+	activity := &PlanningActivity{
 		BaseActivity: NewBaseActivity(
 			"planning",
 			config.Timeout,
@@ -93,6 +93,8 @@ func NewPlanningActivity(config *PlanningConfig) *PlanningActivity {
 		htn:        htn,
 		blackboard: blackboard,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
@@ -114,6 +116,10 @@ type PlanningInput struct {
 
 	// Constraints are planning constraints.
 	Constraints []string
+
+	// PreviousPlan is the last HTN plan for this goal. When set, HTN
+	// repairs it instead of replanning from scratch.
+	PreviousPlan *planning.HTNOutput
 }
 
 // Type returns the input type name.
@@ -188,6 +194,7 @@ func (a *PlanningActivity) Execute(
 				Tasks:        tasks,
 				Methods:      []planning.HTNMethod{}, // Methods would be populated from configuration
 				InitialState: initialState,
+				Previous:     planningInput.PreviousPlan,
 				Source:       planningInput.Source(),
 			}
 		case "blackboard":
@@ -89,7 +89,7 @@ func NewSearchActivity(config *SearchConfig) *SearchActivity {
 	transposition := search.NewTransposition(config.TranspositionConfig)
 	unitProp := search.NewUnitPropagation(config.UnitPropConfig)
 
-	return &SearchActivity{
+	activity := &SearchActivity{
 		BaseActivity: NewBaseActivity(
 			"search",
 			config.Timeout,
@@ -102,6 +102,8 @@ func NewSearchActivity(config *SearchConfig) *SearchActivity {
 		transposition: transposition,
 		unitProp:      unitProp,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
@@ -96,7 +96,7 @@ func NewSimilarityActivity(config *SimilarityConfig) *SimilarityActivity {
 	weisfeilerLeman := streaming.NewWeisfeilerLeman(config.WeisfeilerLemanConfig)
 	l0Sampling := streaming.NewL0Sampling(config.L0SamplingConfig)
 
-	return &SimilarityActivity{
+	activity := &SimilarityActivity{
 		BaseActivity: NewBaseActivity(
 			"similarity",
 			config.Timeout,
@@ -111,6 +111,8 @@ func NewSimilarityActivity(config *SimilarityConfig) *SimilarityActivity {
 		weisfeilerLeman: weisfeilerLeman,
 		l0Sampling:      l0Sampling,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
@@ -89,7 +89,7 @@ func NewStreamingActivity(config *StreamingConfig) *StreamingActivity {
 	countMin := streaming.NewCountMin(config.CountMinConfig)
 	hyperLogLog := streaming.NewHyperLogLog(config.HyperLogLogConfig)
 
-	return &StreamingActivity{
+	activity := &StreamingActivity{
 		BaseActivity: NewBaseActivity(
 			"streaming",
 			config.Timeout,
@@ -102,6 +102,8 @@ func NewStreamingActivity(config *StreamingConfig) *StreamingActivity {
 		countMin:    countMin,
 		hyperLogLog: hyperLogLog,
 	}
+	activity.SetMinPartialQuality(config.MinPartialQuality)
+	return activity
 }
 
 // -----------------------------------------------------------------------------
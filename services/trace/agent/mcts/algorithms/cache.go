@@ -0,0 +1,274 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package algorithms
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// -----------------------------------------------------------------------------
+// Metrics
+// -----------------------------------------------------------------------------
+
+var (
+	resultCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "algorithm_result_cache_hits_total",
+		Help: "Algorithm runs answered from the result cache",
+	}, []string{"algorithm"})
+
+	resultCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "algorithm_result_cache_misses_total",
+		Help: "Algorithm runs not found in the result cache",
+	}, []string{"algorithm"})
+
+	resultCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "algorithm_result_cache_evictions_total",
+		Help: "Result cache entries evicted by size or TTL",
+	})
+)
+
+// -----------------------------------------------------------------------------
+// Cache Keys
+// -----------------------------------------------------------------------------
+
+// CacheKeyer is implemented by inputs that provide their own cache key.
+//
+// Description:
+//
+//	By default an input is keyed by its JSON encoding, which covers
+//	exported fields only. Inputs whose behaviour depends on unexported
+//	state, or that cannot be encoded, implement CacheKeyer. Two inputs
+//	with the same key must produce the same output on the same snapshot.
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// ResultCacheKey identifies a cached algorithm result.
+type ResultCacheKey struct {
+	// Algorithm is the algorithm name.
+	Algorithm string
+
+	// Generation is the snapshot generation.
+	Generation int64
+
+	// InputHash is the SHA-256 of the input's cache key.
+	InputHash string
+}
+
+// NewResultCacheKey builds the cache key for running algo on snapshot with
+// input.
+//
+// Outputs:
+//   - ResultCacheKey: The key.
+//   - bool: False if the input cannot be keyed (not JSON-encodable and not
+//     a CacheKeyer); such runs are not cached.
+func NewResultCacheKey(algo Algorithm, snapshot crs.Snapshot, input any) (ResultCacheKey, bool) {
+	if snapshot == nil {
+		return ResultCacheKey{}, false
+	}
+	var raw []byte
+	if k, ok := input.(CacheKeyer); ok {
+		raw = []byte(k.CacheKey())
+	} else {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return ResultCacheKey{}, false
+		}
+		raw = data
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%T\x00", input)
+	h.Write(raw)
+	return ResultCacheKey{
+		Algorithm:  algo.Name(),
+		Generation: snapshot.Generation(),
+		InputHash:  hex.EncodeToString(h.Sum(nil)),
+	}, true
+}
+
+// -----------------------------------------------------------------------------
+// Result Cache
+// -----------------------------------------------------------------------------
+
+// ResultCacheConfig configures a ResultCache.
+type ResultCacheConfig struct {
+	// MaxEntries bounds the number of cached results. Default: 256.
+	MaxEntries int
+
+	// TTL is how long a result stays cached. Zero means no expiry; entries
+	// are still evicted least-recently-used. Default: 5 minutes.
+	TTL time.Duration
+}
+
+// DefaultResultCacheConfig returns the default cache configuration.
+func DefaultResultCacheConfig() ResultCacheConfig {
+	return ResultCacheConfig{
+		MaxEntries: 256,
+		TTL:        5 * time.Minute,
+	}
+}
+
+// ResultCacheStats contains result cache statistics.
+type ResultCacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int
+}
+
+// ResultCache memoizes algorithm results by snapshot generation and input.
+//
+// Description:
+//
+//	A snapshot generation identifies the CRS state, and algorithms are
+//	pure functions of snapshot and input, so a successful result can be
+//	reused for the same algorithm, generation and input. Only successful,
+//	non-cancelled results are cached. Cached outputs and deltas are shared
+//	between runs and must not be mutated.
+//
+//	Generations are only unique within one CRS; use one cache per CRS.
+//
+// Thread Safety: Safe for concurrent use.
+type ResultCache struct {
+	mu      sync.Mutex
+	config  ResultCacheConfig
+	entries map[ResultCacheKey]*list.Element
+	lru     *list.List // front = most recently used
+	now     func() time.Time
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// resultCacheEntry is one cached result.
+type resultCacheEntry struct {
+	key      ResultCacheKey
+	output   any
+	delta    crs.Delta
+	storedAt time.Time
+}
+
+// NewResultCache creates a result cache.
+//
+// Inputs:
+//   - config: Cache configuration. MaxEntries <= 0 uses the default.
+//
+// Outputs:
+//   - *ResultCache: The new cache.
+func NewResultCache(config ResultCacheConfig) *ResultCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultResultCacheConfig().MaxEntries
+	}
+	return &ResultCache{
+		config:  config,
+		entries: make(map[ResultCacheKey]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached output and delta for key.
+//
+// Outputs:
+//   - any: The cached output.
+//   - crs.Delta: The cached delta.
+//   - bool: False on a miss or an expired entry.
+func (c *ResultCache) Get(key ResultCacheKey) (any, crs.Delta, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok && c.expired(elem.Value.(*resultCacheEntry)) {
+		c.remove(elem)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		resultCacheMisses.WithLabelValues(key.Algorithm).Inc()
+		return nil, nil, false
+	}
+
+	c.hits++
+	resultCacheHits.WithLabelValues(key.Algorithm).Inc()
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*resultCacheEntry)
+	return entry.output, entry.delta, true
+}
+
+// Put caches a result, evicting the least recently used entry if full.
+func (c *ResultCache) Put(key ResultCacheKey, output any, delta crs.Delta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*resultCacheEntry)
+		entry.output, entry.delta, entry.storedAt = output, delta, c.now()
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&resultCacheEntry{
+		key:      key,
+		output:   output,
+		delta:    delta,
+		storedAt: c.now(),
+	})
+	for c.lru.Len() > c.config.MaxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Purge removes all entries.
+func (c *ResultCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[ResultCacheKey]*list.Element)
+	c.lru.Init()
+}
+
+// Stats returns cache statistics.
+func (c *ResultCache) Stats() ResultCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ResultCacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.lru.Len(),
+	}
+}
+
+// expired reports whether entry is past its TTL. Caller holds c.mu.
+func (c *ResultCache) expired(entry *resultCacheEntry) bool {
+	return c.config.TTL > 0 && c.now().Sub(entry.storedAt) > c.config.TTL
+}
+
+// remove evicts elem. Caller holds c.mu.
+func (c *ResultCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*resultCacheEntry).key)
+	c.evictions++
+	resultCacheEvictions.Inc()
+}
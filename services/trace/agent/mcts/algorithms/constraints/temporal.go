@@ -0,0 +1,251 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package constraints
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// ErrTemporalCycle indicates temporal constraints that no edit order can satisfy.
+var ErrTemporalCycle = errors.New("temporal constraints form a cycle")
+
+// -----------------------------------------------------------------------------
+// Temporal Network
+// -----------------------------------------------------------------------------
+
+// TemporalNetwork is the precedence graph of temporal constraints
+// (Before, After, During) over code edits.
+//
+// Description:
+//
+//	Each edit is an interval with a start and an end endpoint. The network
+//	holds the precedence edges from crs.ConstraintIndexView.TemporalEdges
+//	plus the implicit start < end of every edit, and exposes them to the
+//	propagation algorithms:
+//
+//	- Order: a topological edit order, or ErrTemporalCycle.
+//	- AC3Input: endpoint variables over time slots for AC-3.
+//	- TMSInput.Temporal: retraction of edits whose anchor is retracted.
+//
+//	An edit that must start after an endpoint of another edit is anchored
+//	on that edit: Before(a, b) anchors b on a, After(a, b) anchors a on b,
+//	and During(a, b) anchors a on b.
+//
+// Thread Safety: Safe for concurrent use (immutable after creation).
+type TemporalNetwork struct {
+	nodes []string
+	edges []crs.TemporalEdge
+
+	// anchors maps an edit to the edits it presupposes.
+	anchors map[string][]temporalLink
+
+	// dependents maps an edit to the edits anchored on it.
+	dependents map[string][]temporalLink
+}
+
+// temporalLink relates an edit to another edit via a constraint.
+type temporalLink struct {
+	NodeID       string
+	ConstraintID string
+}
+
+// NewTemporalNetwork builds the network from the active temporal
+// constraints in a constraint index.
+func NewTemporalNetwork(view crs.ConstraintIndexView) *TemporalNetwork {
+	return NewTemporalNetworkFromEdges(view.TemporalEdges())
+}
+
+// NewTemporalNetworkFromEdges builds the network from precedence edges.
+func NewTemporalNetworkFromEdges(edges []crs.TemporalEdge) *TemporalNetwork {
+	n := &TemporalNetwork{
+		edges:      slices.Clone(edges),
+		anchors:    make(map[string][]temporalLink),
+		dependents: make(map[string][]temporalLink),
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range edges {
+		for _, id := range []string{e.From.NodeID, e.To.NodeID} {
+			if !seen[id] {
+				seen[id] = true
+				n.nodes = append(n.nodes, id)
+			}
+		}
+		if e.From.NodeID != e.To.NodeID && e.To.Point == crs.EditStart {
+			n.anchors[e.To.NodeID] = append(n.anchors[e.To.NodeID],
+				temporalLink{NodeID: e.From.NodeID, ConstraintID: e.ConstraintID})
+			n.dependents[e.From.NodeID] = append(n.dependents[e.From.NodeID],
+				temporalLink{NodeID: e.To.NodeID, ConstraintID: e.ConstraintID})
+		}
+	}
+	slices.Sort(n.nodes)
+	return n
+}
+
+// Nodes returns the edited nodes in the network, sorted.
+func (n *TemporalNetwork) Nodes() []string {
+	return slices.Clone(n.nodes)
+}
+
+// Anchors returns the nodes whose edits must happen for nodeID's edit to be
+// placed in time.
+func (n *TemporalNetwork) Anchors(nodeID string) []string {
+	result := make([]string, 0, len(n.anchors[nodeID]))
+	for _, l := range n.anchors[nodeID] {
+		result = append(result, l.NodeID)
+	}
+	return result
+}
+
+// Order returns the nodes in an order in which their edits can start.
+//
+// Description:
+//
+//	Topologically sorts the edit endpoints (Kahn's algorithm, breaking ties
+//	by name so the result is deterministic) and returns the nodes ordered
+//	by their start endpoint.
+//
+// Outputs:
+//
+//	[]string - Nodes in edit order.
+//	error - Wraps ErrTemporalCycle, naming the constraints involved, if the
+//	        constraints cannot all be satisfied.
+func (n *TemporalNetwork) Order() ([]string, error) {
+	succ := make(map[crs.TemporalBound][]crs.TemporalBound)
+	indegree := make(map[crs.TemporalBound]int)
+	for _, id := range n.nodes {
+		start := crs.TemporalBound{NodeID: id, Point: crs.EditStart}
+		end := crs.TemporalBound{NodeID: id, Point: crs.EditEnd}
+		succ[start] = append(succ[start], end)
+		indegree[start] += 0
+		indegree[end]++
+	}
+	for _, e := range n.edges {
+		succ[e.From] = append(succ[e.From], e.To)
+		indegree[e.To]++
+	}
+
+	var ready []crs.TemporalBound
+	for b, d := range indegree {
+		if d == 0 {
+			ready = append(ready, b)
+		}
+	}
+
+	order := make([]string, 0, len(n.nodes))
+	visited := 0
+	for len(ready) > 0 {
+		slices.SortFunc(ready, compareBounds)
+		b := ready[0]
+		ready = ready[1:]
+		visited++
+		if b.Point == crs.EditStart {
+			order = append(order, b.NodeID)
+		}
+		for _, next := range succ[b] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if visited < len(indegree) {
+		var ids []string
+		for _, e := range n.edges {
+			if indegree[e.From] > 0 && indegree[e.To] > 0 && !slices.Contains(ids, e.ConstraintID) {
+				ids = append(ids, e.ConstraintID)
+			}
+		}
+		slices.Sort(ids)
+		return nil, fmt.Errorf("%w: %s", ErrTemporalCycle, strings.Join(ids, ", "))
+	}
+	return order, nil
+}
+
+// AC3Input converts the network into an AC-3 problem over time slots.
+//
+// Description:
+//
+//	Each endpoint becomes a variable named "<node>@start" or "<node>@end"
+//	whose domain is the slots 0..slots-1, and each precedence becomes an
+//	AC3ConstraintLessThan. Slots are zero-padded so AC-3's string
+//	comparison orders them numerically; use ParseTemporalSlot to read
+//	reduced domains. AC-3 then narrows each endpoint to the slots it can
+//	occupy, and reports an empty domain when the edits do not fit.
+//
+// Inputs:
+//
+//	slots - Number of time slots. Values <= 0 use 2 * len(Nodes()), enough
+//	        for any acyclic network.
+//
+// Outputs:
+//
+//	*AC3Input - The AC-3 problem.
+func (n *TemporalNetwork) AC3Input(slots int) *AC3Input {
+	if slots <= 0 {
+		slots = 2 * len(n.nodes)
+	}
+	width := len(strconv.Itoa(max(slots-1, 0)))
+	domain := make([]string, slots)
+	for i := range domain {
+		domain[i] = fmt.Sprintf("%0*d", width, i)
+	}
+
+	in := &AC3Input{
+		Variables:   make(map[string]AC3Variable, 2*len(n.nodes)),
+		Constraints: make([]AC3Constraint, 0, len(n.nodes)+len(n.edges)),
+	}
+	for _, id := range n.nodes {
+		start := crs.TemporalBound{NodeID: id, Point: crs.EditStart}.String()
+		end := crs.TemporalBound{NodeID: id, Point: crs.EditEnd}.String()
+		in.Variables[start] = AC3Variable{NodeID: start, Domain: slices.Clone(domain)}
+		in.Variables[end] = AC3Variable{NodeID: end, Domain: slices.Clone(domain)}
+		in.Constraints = append(in.Constraints, AC3Constraint{
+			ID:   "edit:" + id,
+			X:    start,
+			Y:    end,
+			Type: AC3ConstraintLessThan,
+		})
+	}
+	for _, e := range n.edges {
+		in.Constraints = append(in.Constraints, AC3Constraint{
+			ID:   e.ConstraintID,
+			X:    e.From.String(),
+			Y:    e.To.String(),
+			Type: AC3ConstraintLessThan,
+		})
+	}
+	return in
+}
+
+// ParseTemporalSlot parses a slot value from a domain built by AC3Input.
+func ParseTemporalSlot(value string) (int, error) {
+	slot, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: temporal slot %q", ErrInvalidInput, value)
+	}
+	return slot, nil
+}
+
+// compareBounds orders endpoints by node, then start before end.
+func compareBounds(a, b crs.TemporalBound) int {
+	if c := strings.Compare(a.NodeID, b.NodeID); c != 0 {
+		return c
+	}
+	return int(a.Point) - int(b.Point)
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package constraints
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// temporalSnapshot builds a snapshot holding the given constraints.
+func temporalSnapshot(t *testing.T, constraints ...crs.Constraint) crs.Snapshot {
+	t.Helper()
+	c := crs.New(nil)
+	delta := crs.NewConstraintDelta(crs.SignalSourceHard)
+	for _, con := range constraints {
+		con.Active = true
+		delta.Add = append(delta.Add, con)
+	}
+	if _, err := c.Apply(context.Background(), delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	return c.Snapshot()
+}
+
+func TestTemporalNetwork_Order(t *testing.T) {
+	t.Run("orders before, after, and during", func(t *testing.T) {
+		snap := temporalSnapshot(t,
+			crs.Constraint{ID: "t1", Type: crs.ConstraintTypeBefore, Nodes: []string{"schema", "model"}},
+			crs.Constraint{ID: "t2", Type: crs.ConstraintTypeAfter, Nodes: []string{"handler", "model"}},
+			crs.Constraint{ID: "t3", Type: crs.ConstraintTypeDuring, Nodes: []string{"rename", "handler"}},
+		)
+		network := NewTemporalNetwork(snap.ConstraintIndex())
+
+		order, err := network.Order()
+		if err != nil {
+			t.Fatalf("Order: %v", err)
+		}
+		want := []string{"schema", "model", "handler", "rename"}
+		if !slices.Equal(order, want) {
+			t.Errorf("Order() = %v, want %v", order, want)
+		}
+		if got := network.Anchors("rename"); !slices.Equal(got, []string{"handler"}) {
+			t.Errorf("Anchors(rename) = %v, want [handler]", got)
+		}
+	})
+
+	t.Run("reports cycles", func(t *testing.T) {
+		snap := temporalSnapshot(t,
+			crs.Constraint{ID: "t1", Type: crs.ConstraintTypeBefore, Nodes: []string{"a", "b"}},
+			crs.Constraint{ID: "t2", Type: crs.ConstraintTypeBefore, Nodes: []string{"b", "a"}},
+			crs.Constraint{ID: "t3", Type: crs.ConstraintTypeBefore, Nodes: []string{"x", "a"}},
+		)
+		_, err := NewTemporalNetwork(snap.ConstraintIndex()).Order()
+		if !errors.Is(err, ErrTemporalCycle) {
+			t.Fatalf("Order() error = %v, want ErrTemporalCycle", err)
+		}
+		if !strings.Contains(err.Error(), "t1, t2") || strings.Contains(err.Error(), "t3") {
+			t.Errorf("error %q should name t1 and t2 only", err)
+		}
+	})
+}
+
+func TestTemporalNetwork_AC3Input(t *testing.T) {
+	ctx := context.Background()
+	snap := temporalSnapshot(t,
+		crs.Constraint{ID: "t1", Type: crs.ConstraintTypeBefore, Nodes: []string{"a", "b"}},
+	)
+	network := NewTemporalNetwork(snap.ConstraintIndex())
+
+	t.Run("narrows endpoints to their only slots", func(t *testing.T) {
+		result, _, err := NewAC3(nil).Process(ctx, snap, network.AC3Input(0))
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*AC3Output)
+		if !out.Consistent {
+			t.Fatalf("expected consistent result, empty domains %v", out.EmptyDomains)
+		}
+		want := map[string]int{"a@start": 0, "a@end": 1, "b@start": 2, "b@end": 3}
+		for name, slot := range want {
+			domain := out.ReducedDomains[name].Domain
+			if len(domain) != 1 {
+				t.Errorf("%s domain = %v, want one slot", name, domain)
+				continue
+			}
+			if got, err := ParseTemporalSlot(domain[0]); err != nil || got != slot {
+				t.Errorf("%s slot = %v (err %v), want %d", name, got, err, slot)
+			}
+		}
+	})
+
+	t.Run("too few slots is inconsistent", func(t *testing.T) {
+		result, _, err := NewAC3(nil).Process(ctx, snap, network.AC3Input(3))
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		if result.(*AC3Output).Consistent {
+			t.Error("expected inconsistent result with 3 slots for 4 endpoints")
+		}
+	})
+
+	t.Run("slots are zero padded", func(t *testing.T) {
+		in := network.AC3Input(12)
+		if got := in.Variables["a@start"].Domain[2]; got != "02" {
+			t.Errorf("slot 2 = %q, want %q", got, "02")
+		}
+	})
+}
+
+func TestTMS_Temporal(t *testing.T) {
+	ctx := context.Background()
+	snap := temporalSnapshot(t,
+		crs.Constraint{ID: "t1", Type: crs.ConstraintTypeBefore, Nodes: []string{"a", "b"}},
+		crs.Constraint{ID: "t2", Type: crs.ConstraintTypeDuring, Nodes: []string{"c", "b"}},
+	)
+	network := NewTemporalNetwork(snap.ConstraintIndex())
+
+	t.Run("retracting an anchor retracts dependent edits", func(t *testing.T) {
+		input := &TMSInput{
+			Beliefs: map[string]TMSBelief{
+				"a": {NodeID: "a", Status: TMSStatusIn},
+				"b": {NodeID: "b", Status: TMSStatusIn},
+				"c": {NodeID: "c", Status: TMSStatusIn},
+			},
+			Changes:  []TMSChange{{NodeID: "a", NewStatus: TMSStatusOut}},
+			Temporal: network,
+		}
+
+		result, _, err := NewTMS(nil).Process(ctx, snap, input)
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*TMSOutput)
+
+		out.UpdatedBeliefs = slices.DeleteFunc(out.UpdatedBeliefs, func(b TMSBelief) bool {
+			return b.Status != TMSStatusOut
+		})
+		if len(out.UpdatedBeliefs) != 3 {
+			t.Errorf("expected a, b, c retracted, got %v", out.UpdatedBeliefs)
+		}
+		var chain []string
+		for _, p := range out.PropagationChain {
+			chain = append(chain, p.NodeID+":"+p.Justification)
+		}
+		want := []string{"a:", "b:temporal:t1", "c:temporal:t2"}
+		if !slices.Equal(chain, want) {
+			t.Errorf("PropagationChain = %v, want %v", chain, want)
+		}
+	})
+
+	t.Run("believing an edit with a retracted anchor is a contradiction", func(t *testing.T) {
+		input := &TMSInput{
+			Beliefs: map[string]TMSBelief{
+				"a": {NodeID: "a", Status: TMSStatusOut},
+			},
+			Changes:  []TMSChange{{NodeID: "b", NewStatus: TMSStatusIn}},
+			Temporal: network,
+		}
+
+		result, _, err := NewTMS(nil).Process(ctx, snap, input)
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*TMSOutput)
+		if len(out.Contradictions) != 1 || out.Contradictions[0].NodeID != "b" {
+			t.Errorf("Contradictions = %v, want one for b", out.Contradictions)
+		}
+	})
+
+	t.Run("nil network disables temporal propagation", func(t *testing.T) {
+		input := &TMSInput{
+			Beliefs: map[string]TMSBelief{
+				"a": {NodeID: "a", Status: TMSStatusIn},
+				"b": {NodeID: "b", Status: TMSStatusIn},
+			},
+			Changes: []TMSChange{{NodeID: "a", NewStatus: TMSStatusOut}},
+		}
+
+		result, _, err := NewTMS(nil).Process(ctx, snap, input)
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		if got := len(result.(*TMSOutput).PropagationChain); got != 1 {
+			t.Errorf("PropagationChain length = %d, want 1", got)
+		}
+	})
+}
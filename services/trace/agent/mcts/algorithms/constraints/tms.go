@@ -13,7 +13,9 @@ package constraints
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
@@ -103,6 +105,12 @@ type TMSInput struct {
 
 	// Changes are the belief changes to process.
 	Changes []TMSChange
+
+	// Temporal, if set, orders the edits behind the beliefs. Retracting an
+	// edit retracts the edits anchored on it, and believing an edit whose
+	// anchor is OUT is reported as a contradiction. Nil disables temporal
+	// propagation.
+	Temporal *TemporalNetwork
 }
 
 // TMSBelief represents a belief in the TMS.
@@ -166,6 +174,40 @@ type TMSContradiction struct {
 	Source crs.SignalSource
 }
 
+// Clauses encodes the justifications as clauses for a SAT solver such as
+// search.DPLL, with a true variable meaning IN.
+//
+// Each justification of node n becomes (¬in₁ ∨ … ∨ out₁ ∨ … ∨ n): if its
+// InList is IN and its OutList is OUT, n is IN. Clauses are ordered by node
+// and keep the justification's source.
+func (in *TMSInput) Clauses() []crs.Clause {
+	nodes := make([]string, 0, len(in.Justifications))
+	for nodeID := range in.Justifications {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+
+	var clauses []crs.Clause
+	for _, nodeID := range nodes {
+		for _, just := range in.Justifications[nodeID] {
+			lits := make([]crs.Literal, 0, len(just.InList)+len(just.OutList)+1)
+			for _, id := range just.InList {
+				lits = append(lits, crs.Literal{Variable: id, Negated: true})
+			}
+			for _, id := range just.OutList {
+				lits = append(lits, crs.Literal{Variable: id})
+			}
+			lits = append(lits, crs.Literal{Variable: nodeID})
+			clauses = append(clauses, crs.Clause{
+				ID:       "tms:" + nodeID + ":" + just.ID,
+				Literals: lits,
+				Source:   just.Source,
+			})
+		}
+	}
+	return clauses
+}
+
 // -----------------------------------------------------------------------------
 // Algorithm Interface Implementation
 // -----------------------------------------------------------------------------
@@ -235,10 +277,15 @@ func (t *TMS) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 
 			queue = append(queue, change.NodeID)
 		}
+
+		if change.NewStatus == TMSStatusIn {
+			t.checkAnchors(change.NodeID, change.Source, in.Temporal, beliefs, output)
+		}
 	}
 
 	// Propagation loop
 	processed := make(map[string]bool)
+	retracted := make(map[string]bool) // edits retracted by temporal anchors
 	for len(queue) > 0 && output.Iterations < t.config.MaxIterations {
 		// Check for cancellation
 		select {
@@ -266,6 +313,9 @@ func (t *TMS) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 			}
 
 			newStatus, justID, hasSupport := t.evaluateBelief(depID, in.Justifications, beliefs)
+			if retracted[depID] {
+				newStatus = TMSStatusOut
+			}
 
 			if newStatus != oldStatus {
 				beliefs[depID] = TMSBelief{
@@ -286,6 +336,31 @@ func (t *TMS) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 
 			_ = hasSupport // Used for debugging
 		}
+
+		// Temporal propagation: edits anchored on a retracted edit lose
+		// their place in the edit order.
+		if in.Temporal == nil || beliefs[nodeID].Status != TMSStatusOut {
+			continue
+		}
+		for _, link := range in.Temporal.dependents[nodeID] {
+			b, ok := beliefs[link.NodeID]
+			if !ok || b.Status != TMSStatusIn {
+				continue
+			}
+			retracted[link.NodeID] = true
+			beliefs[link.NodeID] = TMSBelief{
+				NodeID: link.NodeID,
+				Status: TMSStatusOut,
+				Source: b.Source,
+			}
+			output.PropagationChain = append(output.PropagationChain, TMSPropagation{
+				NodeID:        link.NodeID,
+				OldStatus:     TMSStatusIn,
+				NewStatus:     TMSStatusOut,
+				Justification: "temporal:" + link.ConstraintID,
+			})
+			queue = append(queue, link.NodeID)
+		}
 	}
 
 	// Collect updated beliefs
@@ -294,6 +369,22 @@ func (t *TMS) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 	return output, nil, nil
 }
 
+// checkAnchors reports a contradiction for each anchor of nodeID that is OUT.
+func (t *TMS) checkAnchors(nodeID string, source crs.SignalSource, network *TemporalNetwork, beliefs map[string]TMSBelief, output *TMSOutput) {
+	if network == nil {
+		return
+	}
+	for _, link := range network.anchors[nodeID] {
+		if b, ok := beliefs[link.NodeID]; ok && b.Status == TMSStatusOut {
+			output.Contradictions = append(output.Contradictions, TMSContradiction{
+				NodeID: nodeID,
+				Reason: fmt.Sprintf("temporal constraint %s requires edit %s, which is not believed", link.ConstraintID, link.NodeID),
+				Source: source,
+			})
+		}
+	}
+}
+
 // findDependents finds nodes that depend on the given node.
 func (t *TMS) findDependents(nodeID string, justifications map[string][]TMSJustification) []string {
 	dependents := make(map[string]bool)
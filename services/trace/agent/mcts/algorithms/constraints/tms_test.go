@@ -262,3 +262,32 @@ func TestTMS_Evaluable(t *testing.T) {
 		}
 	})
 }
+
+func TestTMSInput_Clauses(t *testing.T) {
+	in := &TMSInput{
+		Justifications: map[string][]TMSJustification{
+			"c": {{ID: "j2", InList: []string{"a"}, OutList: []string{"b"}, Source: crs.SignalSourceHard}},
+			"a": {{ID: "j1", Source: crs.SignalSourceSoft}},
+		},
+	}
+
+	clauses := in.Clauses()
+	if len(clauses) != 2 {
+		t.Fatalf("expected 2 clauses, got %d", len(clauses))
+	}
+	if clauses[0].ID != "tms:a:j1" || len(clauses[0].Literals) != 1 {
+		t.Errorf("premise clause = %+v, want unit clause a", clauses[0])
+	}
+
+	// a ∧ ¬b → c is ¬a ∨ b ∨ c
+	got := clauses[1]
+	want := []crs.Literal{{Variable: "a", Negated: true}, {Variable: "b"}, {Variable: "c"}}
+	if len(got.Literals) != len(want) || got.Source != crs.SignalSourceHard {
+		t.Fatalf("clause = %+v", got)
+	}
+	for i := range want {
+		if got.Literals[i] != want[i] {
+			t.Errorf("literal %d = %v, want %v", i, got.Literals[i], want[i])
+		}
+	}
+}
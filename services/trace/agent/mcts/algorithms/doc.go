@@ -75,10 +75,10 @@
 // Algorithm Categories:
 //
 //	┌─────────────────────────────────────────────────────────────────────────────┐
-//	│  SEARCH      │ PN-MCTS, Transposition, UnitProp                             │
+//	│  SEARCH      │ PN-MCTS, Beam Search, Transposition, UnitProp, DPLL          │
 //	│  LEARNING    │ CDCL, Watched Literals                                       │
 //	│  CONSTRAINTS │ TMS, AC-3, Semantic Backprop                                 │
-//	│  PLANNING    │ HTN, Blackboard                                              │
+//	│  PLANNING    │ HTN, Blackboard, CBS                                         │
 //	│  GRAPH       │ Tarjan SCC, Dominators, VF2                                  │
 //	│  STREAMING   │ AGM Sketch, Count-Min, HyperLogLog, MinHash, LSH, L0, WL     │
 //	└─────────────────────────────────────────────────────────────────────────────┘
@@ -103,4 +103,14 @@
 //
 //	// Apply merged delta
 //	_, err = crs.Apply(ctx, delta)
+//
+// Result Caching:
+//
+// Algorithms are pure functions of snapshot and input, so a runner created
+// with WithResultCache returns the cached output and delta when the same
+// algorithm runs again on the same snapshot generation with an equivalent
+// input, instead of recomputing:
+//
+//	cache := algorithms.NewResultCache(algorithms.DefaultResultCacheConfig())
+//	runner := algorithms.NewRunner(10, algorithms.WithResultCache(cache))
 package algorithms
@@ -60,6 +60,9 @@ func (e *AlgorithmError) Error() string {
 //	- Analyze call graph structure
 //	- Find circular imports
 //
+//	For graphs that change by small dependency deltas, use IncrementalSCC
+//	to maintain the components instead of recomputing them.
+//
 // Thread Safety: Safe for concurrent use.
 type TarjanSCC struct {
 	config *TarjanConfig
@@ -356,6 +359,20 @@ func (t *TarjanSCC) SupportsPartialResults() bool {
 	return true
 }
 
+// PartialQuality returns the fraction of input nodes already assigned to
+// a completed SCC. Nodes still on the Tarjan stack do not count.
+func (t *TarjanSCC) PartialQuality(input, output any) float64 {
+	in, ok := input.(*TarjanInput)
+	out, ok2 := output.(*TarjanOutput)
+	if !ok || !ok2 || out == nil {
+		return 0
+	}
+	if len(in.Nodes) == 0 {
+		return 1
+	}
+	return float64(len(out.NodeToSCC)) / float64(len(in.Nodes))
+}
+
 // -----------------------------------------------------------------------------
 // Evaluable Implementation
 // -----------------------------------------------------------------------------
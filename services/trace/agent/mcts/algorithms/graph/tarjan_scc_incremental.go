@@ -0,0 +1,543 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// -----------------------------------------------------------------------------
+// Incremental SCC Maintenance
+// -----------------------------------------------------------------------------
+
+// sccOrdGap spaces the topological positions of components so that split
+// components can usually be placed without renumbering.
+const sccOrdGap int64 = 1 << 16
+
+// IncrementalSCC maintains strongly connected components under edge
+// insertions and deletions.
+//
+// Description:
+//
+//	TarjanSCC recomputes every component from scratch, which dominates
+//	latency on large graphs when only a few dependencies change. An
+//	IncrementalSCC is built once with full Tarjan and then consumes
+//	DependencyDelta updates:
+//
+//	- Insertion u->v keeps a topological order of the components
+//	  (Pearce-Kelly). An edge consistent with the order costs O(1);
+//	  otherwise only components whose position lies between v and u are
+//	  searched. If u is reachable from v, the components on those paths
+//	  are merged into one.
+//	- Deletion u->v between different components costs O(1). Within one
+//	  component, Tarjan is re-run on that component only, which may split
+//	  it.
+//
+//	Cost is therefore proportional to the region of the graph affected
+//	by the change, not to the graph size.
+//
+// Thread Safety: Safe for concurrent use.
+type IncrementalSCC struct {
+	mu sync.RWMutex
+
+	out map[string]map[string]struct{}
+	in  map[string]map[string]struct{}
+
+	// comp maps each node to its component; members is the inverse.
+	comp    map[string]int
+	members map[int]map[string]struct{}
+
+	// ord is a topological position per component: for every edge
+	// between components, ord[from] < ord[to]. ordOwner is the inverse.
+	ord      map[int]int64
+	ordOwner map[int64]int
+
+	nextComp int
+	nextOrd  int64
+}
+
+// SCCChanges describes how an update changed the components.
+type SCCChanges struct {
+	// Merged lists the members of each component formed by a merge,
+	// i.e. a new or grown cycle.
+	Merged [][]string
+
+	// Split lists the members of each component that broke apart,
+	// before the split.
+	Split [][]string
+}
+
+// NewIncrementalSCC builds the initial components with Tarjan's algorithm.
+//
+// Inputs:
+//   - in: The initial graph. Nil starts empty. Edge endpoints missing
+//     from in.Nodes are added as nodes.
+//
+// Outputs:
+//   - *IncrementalSCC: The maintainer.
+func NewIncrementalSCC(in *TarjanInput) *IncrementalSCC {
+	s := &IncrementalSCC{
+		out:      make(map[string]map[string]struct{}),
+		in:       make(map[string]map[string]struct{}),
+		comp:     make(map[string]int),
+		members:  make(map[int]map[string]struct{}),
+		ord:      make(map[int]int64),
+		ordOwner: make(map[int64]int),
+		nextOrd:  sccOrdGap,
+	}
+	if in == nil {
+		return s
+	}
+
+	nodes := make([]string, 0, len(in.Nodes))
+	for _, n := range in.Nodes {
+		if s.addAdjacency(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	for from, tos := range in.Edges {
+		if s.addAdjacency(from) {
+			nodes = append(nodes, from)
+		}
+		for _, to := range tos {
+			if s.addAdjacency(to) {
+				nodes = append(nodes, to)
+			}
+			s.out[from][to] = struct{}{}
+			s.in[to][from] = struct{}{}
+		}
+	}
+
+	// Tarjan emits components in reverse topological order.
+	sccs := s.tarjan(nodes)
+	for i := len(sccs) - 1; i >= 0; i-- {
+		c := s.newComponent(sccs[i])
+		s.setOrd(c, s.nextOrd)
+		s.nextOrd += sccOrdGap
+	}
+	return s
+}
+
+// ApplyDelta applies a dependency delta: removals first, then additions.
+//
+// Outputs:
+//   - SCCChanges: The merges and splits caused by the delta.
+func (s *IncrementalSCC) ApplyDelta(d *crs.DependencyDelta) SCCChanges {
+	var changes SCCChanges
+	if d == nil {
+		return changes
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range d.RemoveEdges {
+		if split := s.removeEdge(e[0], e[1]); split != nil {
+			changes.Split = append(changes.Split, split)
+		}
+	}
+	for _, e := range d.AddEdges {
+		if merged := s.addEdge(e[0], e[1]); merged != nil {
+			changes.Merged = append(changes.Merged, merged)
+		}
+	}
+	return changes
+}
+
+// AddEdge adds the edge from -> to, adding missing nodes.
+//
+// Outputs:
+//   - []string: Members of the merged component if the edge closed a
+//     cycle across components, nil otherwise.
+func (s *IncrementalSCC) AddEdge(from, to string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addEdge(from, to)
+}
+
+// RemoveEdge removes the edge from -> to if present.
+//
+// Outputs:
+//   - []string: Members of the component before it split, nil if no
+//     component split.
+func (s *IncrementalSCC) RemoveEdge(from, to string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.removeEdge(from, to)
+}
+
+// SameComponent reports whether a and b are strongly connected.
+func (s *IncrementalSCC) SameComponent(a, b string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ca, okA := s.comp[a]
+	cb, okB := s.comp[b]
+	return okA && okB && ca == cb
+}
+
+// Component returns the sorted members of node's component, or nil if
+// the node is unknown.
+func (s *IncrementalSCC) Component(node string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.comp[node]
+	if !ok {
+		return nil
+	}
+	return sortedMembers(s.members[c])
+}
+
+// Output returns the components in the same form as TarjanSCC.Process:
+// SCCs in reverse topological order, each sorted by node ID.
+func (s *IncrementalSCC) Output() *TarjanOutput {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comps := make([]int, 0, len(s.members))
+	for c := range s.members {
+		comps = append(comps, c)
+	}
+	sort.Slice(comps, func(i, j int) bool { return s.ord[comps[i]] > s.ord[comps[j]] })
+
+	output := &TarjanOutput{
+		SCCs:             make([][]string, len(comps)),
+		NodeToSCC:        make(map[string]int, len(s.comp)),
+		NodesProcessed:   len(s.comp),
+		TopologicalOrder: make([]int, len(comps)),
+	}
+	for i, c := range comps {
+		scc := sortedMembers(s.members[c])
+		output.SCCs[i] = scc
+		for _, node := range scc {
+			output.NodeToSCC[node] = i
+		}
+		if len(scc) > output.LargestSCCSize {
+			output.LargestSCCSize = len(scc)
+		}
+		if len(scc) > 1 {
+			output.Cyclic = true
+		}
+		output.TopologicalOrder[i] = i
+	}
+	return output
+}
+
+// addEdge implements AddEdge. Caller holds s.mu.
+func (s *IncrementalSCC) addEdge(from, to string) []string {
+	s.addNode(from)
+	s.addNode(to)
+	if _, exists := s.out[from][to]; exists {
+		return nil
+	}
+	s.out[from][to] = struct{}{}
+	s.in[to][from] = struct{}{}
+
+	cu, cv := s.comp[from], s.comp[to]
+	if cu == cv || s.ord[cu] < s.ord[cv] {
+		return nil
+	}
+
+	// Pearce-Kelly: the order is violated. Only components positioned
+	// in [ord[cv], ord[cu]] can be on a path from cv to cu.
+	lb, ub := s.ord[cv], s.ord[cu]
+	forward := s.reach(cv, s.out, func(c int) bool { return s.ord[c] <= ub })
+	backward := s.reach(cu, s.in, func(c int) bool { return s.ord[c] >= lb })
+
+	// Every component on a path cv ~> cu is in both sets.
+	var cycle []int
+	if _, ok := forward[cu]; ok {
+		for c := range forward {
+			if _, ok := backward[c]; ok {
+				cycle = append(cycle, c)
+			}
+		}
+	}
+	inCycle := make(map[int]bool, len(cycle))
+	for _, c := range cycle {
+		inCycle[c] = true
+	}
+
+	// Reassign the positions of the affected components: predecessors of
+	// cu first, then the merged cycle, then successors of cv, each group
+	// keeping its relative order.
+	pool := make([]int64, 0, len(forward)+len(backward))
+	var before, after []int
+	for c := range backward {
+		pool = append(pool, s.ord[c])
+		if !inCycle[c] {
+			before = append(before, c)
+		}
+	}
+	for c := range forward {
+		if _, dup := backward[c]; !dup {
+			pool = append(pool, s.ord[c])
+		}
+		if !inCycle[c] {
+			after = append(after, c)
+		}
+	}
+	sort.Slice(pool, func(i, j int) bool { return pool[i] < pool[j] })
+	s.sortByOrd(before)
+	s.sortByOrd(after)
+	for _, p := range pool {
+		delete(s.ordOwner, p)
+	}
+
+	for i, c := range before {
+		s.setOrd(c, pool[i])
+	}
+	for i, c := range after {
+		s.setOrd(c, pool[len(pool)-len(after)+i])
+	}
+	if len(cycle) == 0 {
+		return nil
+	}
+
+	merged := s.merge(cycle)
+	s.setOrd(merged, pool[len(before)])
+	return sortedMembers(s.members[merged])
+}
+
+// removeEdge implements RemoveEdge. Caller holds s.mu.
+func (s *IncrementalSCC) removeEdge(from, to string) []string {
+	if _, exists := s.out[from][to]; !exists {
+		return nil
+	}
+	delete(s.out[from], to)
+	delete(s.in[to], from)
+
+	c := s.comp[from]
+	if c != s.comp[to] {
+		return nil // the order stays valid with fewer edges
+	}
+
+	nodes := sortedMembers(s.members[c])
+	sccs := s.tarjan(nodes)
+	if len(sccs) == 1 {
+		return nil
+	}
+
+	// Place the parts at c's position in topological order (Tarjan emits
+	// them reversed), using the free slots after it.
+	base := s.ord[c]
+	s.removeComponent(c)
+	parts := make([]int, 0, len(sccs))
+	for i := len(sccs) - 1; i >= 0; i-- {
+		parts = append(parts, s.newComponent(sccs[i]))
+	}
+	if !s.slotsFree(base, len(parts)) {
+		s.renumber(base, parts)
+		return nodes
+	}
+	for i, p := range parts {
+		s.setOrd(p, base+int64(i))
+	}
+	if last := base + int64(len(parts)-1); last >= s.nextOrd {
+		s.nextOrd = last + sccOrdGap
+	}
+	return nodes
+}
+
+// reach returns the components reachable from start along adj (s.out for
+// successors, s.in for predecessors), visiting only components accepted
+// by within. Caller holds s.mu.
+func (s *IncrementalSCC) reach(start int, adj map[string]map[string]struct{}, within func(int) bool) map[int]struct{} {
+	seen := map[int]struct{}{start: {}}
+	stack := []int{start}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for node := range s.members[c] {
+			for next := range adj[node] {
+				nc := s.comp[next]
+				if _, ok := seen[nc]; ok || !within(nc) {
+					continue
+				}
+				seen[nc] = struct{}{}
+				stack = append(stack, nc)
+			}
+		}
+	}
+	return seen
+}
+
+// tarjan runs Tarjan's algorithm on the subgraph induced by nodes.
+// Caller holds s.mu.
+func (s *IncrementalSCC) tarjan(nodes []string) [][]string {
+	inSet := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		inSet[n] = struct{}{}
+	}
+	edges := make(map[string][]string, len(nodes))
+	for _, n := range nodes {
+		for to := range s.out[n] {
+			if _, ok := inSet[to]; ok {
+				edges[n] = append(edges[n], to)
+			}
+		}
+	}
+
+	state := &tarjanState{
+		nodeIndex: make(map[string]int, len(nodes)),
+		lowlink:   make(map[string]int, len(nodes)),
+		onStack:   make(map[string]bool, len(nodes)),
+		stack:     make([]string, 0),
+		sccs:      make([][]string, 0),
+		edges:     edges,
+		ctx:       context.Background(),
+	}
+	var t TarjanSCC
+	for _, n := range nodes {
+		if _, visited := state.nodeIndex[n]; !visited {
+			t.strongConnect(state, n)
+		}
+	}
+	return state.sccs
+}
+
+// addNode adds an isolated node as its own component at the end of the
+// order. Caller holds s.mu.
+func (s *IncrementalSCC) addNode(n string) {
+	if !s.addAdjacency(n) {
+		return
+	}
+	c := s.newComponent([]string{n})
+	s.setOrd(c, s.nextOrd)
+	s.nextOrd += sccOrdGap
+}
+
+// addAdjacency creates adjacency sets for n, reporting whether n is new.
+func (s *IncrementalSCC) addAdjacency(n string) bool {
+	if _, ok := s.out[n]; ok {
+		return false
+	}
+	s.out[n] = make(map[string]struct{})
+	s.in[n] = make(map[string]struct{})
+	return true
+}
+
+// newComponent creates a component of nodes, without a position.
+func (s *IncrementalSCC) newComponent(nodes []string) int {
+	c := s.nextComp
+	s.nextComp++
+	set := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		set[n] = struct{}{}
+		s.comp[n] = c
+	}
+	s.members[c] = set
+	return c
+}
+
+// merge moves the members of comps into the largest of them and returns
+// it. The survivor has no position.
+func (s *IncrementalSCC) merge(comps []int) int {
+	into := comps[0]
+	for _, c := range comps[1:] {
+		if len(s.members[c]) > len(s.members[into]) {
+			into = c
+		}
+	}
+	for _, c := range comps {
+		if c == into {
+			continue
+		}
+		for n := range s.members[c] {
+			s.members[into][n] = struct{}{}
+			s.comp[n] = into
+		}
+		s.removeComponent(c)
+	}
+	if owner, ok := s.ordOwner[s.ord[into]]; ok && owner == into {
+		delete(s.ordOwner, s.ord[into])
+	}
+	delete(s.ord, into)
+	return into
+}
+
+// removeComponent forgets c and frees its position.
+func (s *IncrementalSCC) removeComponent(c int) {
+	if o, ok := s.ord[c]; ok && s.ordOwner[o] == c {
+		delete(s.ordOwner, o)
+	}
+	delete(s.ord, c)
+	delete(s.members, c)
+}
+
+// setOrd moves c to position o.
+func (s *IncrementalSCC) setOrd(c int, o int64) {
+	s.ord[c] = o
+	s.ordOwner[o] = c
+}
+
+// slotsFree reports whether base and the n-1 positions after it are
+// unused.
+func (s *IncrementalSCC) slotsFree(base int64, n int) bool {
+	for i := int64(0); i < int64(n); i++ {
+		if _, used := s.ordOwner[base+i]; used {
+			return false
+		}
+	}
+	return true
+}
+
+// renumber respaces all positions, inserting parts (in order) where the
+// component at base used to be. O(V log V); only needed when repeated
+// splits exhaust the gap after a position.
+func (s *IncrementalSCC) renumber(base int64, parts []int) {
+	isPart := make(map[int]bool, len(parts))
+	for _, p := range parts {
+		isPart[p] = true
+	}
+	others := make([]int, 0, len(s.members))
+	for c := range s.members {
+		if !isPart[c] {
+			others = append(others, c)
+		}
+	}
+	s.sortByOrd(others)
+
+	order := make([]int, 0, len(s.members))
+	i := 0
+	for ; i < len(others) && s.ord[others[i]] < base; i++ {
+		order = append(order, others[i])
+	}
+	order = append(order, parts...)
+	order = append(order, others[i:]...)
+
+	s.ordOwner = make(map[int64]int, len(order))
+	s.nextOrd = sccOrdGap
+	for _, c := range order {
+		s.setOrd(c, s.nextOrd)
+		s.nextOrd += sccOrdGap
+	}
+}
+
+// sortByOrd sorts comps by position.
+func (s *IncrementalSCC) sortByOrd(comps []int) {
+	sort.Slice(comps, func(i, j int) bool { return s.ord[comps[i]] < s.ord[comps[j]] })
+}
+
+// sortedMembers returns set's members sorted.
+func sortedMembers(set map[string]struct{}) []string {
+	out := make([]string, 0, len(set))
+	for n := range set {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -12,6 +12,9 @@ package graph
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"slices"
 	"testing"
 	"time"
 
@@ -413,3 +416,100 @@ func TestTarjanSCC_Evaluable(t *testing.T) {
 		}
 	})
 }
+
+func TestIncrementalSCC(t *testing.T) {
+	t.Run("merge and split", func(t *testing.T) {
+		s := NewIncrementalSCC(&TarjanInput{
+			Nodes: []string{"A", "B", "C", "D"},
+			Edges: map[string][]string{"A": {"B"}, "B": {"C"}},
+		})
+		if s.SameComponent("A", "C") {
+			t.Fatal("A and C connected before the back edge")
+		}
+
+		delta := crs.NewDependencyDelta(crs.SignalSourceHard)
+		delta.AddEdges = [][2]string{{"C", "A"}, {"C", "D"}}
+		changes := s.ApplyDelta(delta)
+		if len(changes.Merged) != 1 || len(changes.Merged[0]) != 3 {
+			t.Fatalf("Merged = %v, want [[A B C]]", changes.Merged)
+		}
+		if got := s.Component("B"); len(got) != 3 {
+			t.Errorf("Component(B) = %v", got)
+		}
+
+		delta = crs.NewDependencyDelta(crs.SignalSourceHard)
+		delta.RemoveEdges = [][2]string{{"B", "C"}}
+		changes = s.ApplyDelta(delta)
+		if len(changes.Split) != 1 {
+			t.Fatalf("Split = %v, want one split", changes.Split)
+		}
+		if s.SameComponent("A", "B") || s.Output().Cyclic {
+			t.Error("components not split after removing B->C")
+		}
+	})
+
+	t.Run("matches full recomputation", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(7))
+		const n = 60
+		nodes := make([]string, n)
+		for i := range nodes {
+			nodes[i] = fmt.Sprintf("n%02d", i)
+		}
+		edges := make(map[[2]string]bool)
+		s := NewIncrementalSCC(&TarjanInput{Nodes: nodes})
+
+		for step := 0; step < 2000; step++ {
+			e := [2]string{nodes[rng.Intn(n)], nodes[rng.Intn(n)]}
+			if edges[e] && rng.Intn(2) == 0 {
+				delete(edges, e)
+				s.RemoveEdge(e[0], e[1])
+			} else {
+				edges[e] = true
+				s.AddEdge(e[0], e[1])
+			}
+
+			if step%50 != 0 {
+				continue
+			}
+			adj := make(map[string][]string)
+			for e := range edges {
+				adj[e[0]] = append(adj[e[0]], e[1])
+			}
+			full, _, err := NewTarjanSCC(nil).Process(context.Background(), nil, &TarjanInput{Nodes: nodes, Edges: adj})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := full.(*TarjanOutput)
+			got := s.Output()
+			if len(got.SCCs) != len(want.SCCs) {
+				t.Fatalf("step %d: %d SCCs, want %d", step, len(got.SCCs), len(want.SCCs))
+			}
+			for _, node := range nodes {
+				wantSCC := slices.Clone(want.SCCs[want.NodeToSCC[node]])
+				slices.Sort(wantSCC)
+				if got := s.Component(node); !slices.Equal(got, wantSCC) {
+					t.Fatalf("step %d: Component(%s) = %v, want %v", step, node, got, wantSCC)
+				}
+			}
+			for a := range edges {
+				// Reverse topological order: an edge never points to a later SCC.
+				if got.NodeToSCC[a[0]] < got.NodeToSCC[a[1]] {
+					t.Fatalf("step %d: edge %s->%s violates topological order", step, a[0], a[1])
+				}
+			}
+		}
+	})
+}
+
+func TestTarjanSCC_PartialQuality(t *testing.T) {
+	algo := NewTarjanSCC(nil)
+	input := &TarjanInput{Nodes: []string{"a", "b", "c", "d"}}
+
+	partial := &TarjanOutput{NodeToSCC: map[string]int{"a": 0, "b": 1}}
+	if q := algo.PartialQuality(input, partial); q != 0.5 {
+		t.Errorf("PartialQuality = %v, want 0.5", q)
+	}
+	if q := algo.PartialQuality(input, nil); q != 0 {
+		t.Errorf("PartialQuality(nil) = %v, want 0", q)
+	}
+}
@@ -0,0 +1,794 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package planning
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+// CBS constraint ID prefixes. Constraints written by CBS use these so a
+// later run recognizes and updates them instead of duplicating them.
+const (
+	// CBSLockPrefix prefixes the ID of a file lock resource constraint.
+	CBSLockPrefix = "cbs_lock:"
+
+	// CBSBeforePrefix prefixes the ID of an edit ordering constraint.
+	CBSBeforePrefix = "cbs_before:"
+)
+
+// -----------------------------------------------------------------------------
+// Conflict-Based Search (CBS) Algorithm
+// -----------------------------------------------------------------------------
+
+// CBS implements Conflict-Based Search for scheduling multi-file edits.
+//
+// Description:
+//
+//	CBS plans a conflict-free schedule for a set of edits, each of which
+//	holds a lock on every file it touches for its whole duration. It treats
+//	edits as the agents of multi-agent path finding and file locks as the
+//	shared resources:
+//
+//	- Low level: each edit is placed at the earliest time slot after its
+//	  dependencies finish that avoids the intervals forbidden to it.
+//	- High level: a best-first search over a constraint tree. Each node
+//	  holds a set of forbidden intervals and the resulting schedule. When
+//	  two edits hold the same lock at the same time, the node branches
+//	  twice: one child forbids the first edit during the second's interval,
+//	  the other forbids the second during the first's.
+//
+//	Nodes are expanded by lowest cost (sum of edit end times), so the
+//	first conflict-free schedule found is optimal for that cost.
+//
+//	Locks are read from the snapshot as well as the input: an active
+//	ConstraintTypeResource constraint whose Nodes include an edit adds a
+//	lock named by its Expression (or ID if empty). Active ConstraintTypeBefore
+//	constraints between input edits are treated as dependencies.
+//
+//	The delta records the result in the ConstraintIndex: one resource
+//	constraint per file lock, and a ConstraintTypeBefore constraint for
+//	each pair of edits that must run in sequence. These are the ordering
+//	constraints consumed by constraints.TemporalNetwork, and
+//	CBSOutput.HTNMethod turns the schedule into an HTN decomposition.
+//
+// Thread Safety: Safe for concurrent use.
+type CBS struct {
+	config *CBSConfig
+}
+
+// CBSConfig configures the CBS algorithm.
+type CBSConfig struct {
+	// MaxNodes limits the number of constraint tree nodes expanded.
+	MaxNodes int
+
+	// Timeout is the maximum execution time.
+	Timeout time.Duration
+
+	// ProgressInterval is how often to report progress.
+	ProgressInterval time.Duration
+}
+
+// DefaultCBSConfig returns the default configuration.
+func DefaultCBSConfig() *CBSConfig {
+	return &CBSConfig{
+		MaxNodes:         10000,
+		Timeout:          5 * time.Second,
+		ProgressInterval: 1 * time.Second,
+	}
+}
+
+// NewCBS creates a new CBS algorithm.
+func NewCBS(config *CBSConfig) *CBS {
+	if config == nil {
+		config = DefaultCBSConfig()
+	}
+	return &CBS{config: config}
+}
+
+// -----------------------------------------------------------------------------
+// Input/Output Types
+// -----------------------------------------------------------------------------
+
+// CBSInput is the input for CBS planning.
+type CBSInput struct {
+	// Edits are the edits to schedule.
+	Edits []CBSEdit
+
+	// Source indicates where the planning request originated.
+	Source crs.SignalSource
+}
+
+// CBSEdit is one edit to schedule.
+type CBSEdit struct {
+	// ID identifies the edit. Used as the node ID in constraints.
+	ID string
+
+	// Files are the files the edit locks while it runs.
+	Files []string
+
+	// Duration is the number of time slots the edit takes. Values <= 0
+	// mean 1.
+	Duration int
+
+	// DependsOn lists edits that must finish before this one starts.
+	DependsOn []string
+}
+
+// CBSOutput is the output from CBS planning.
+type CBSOutput struct {
+	// Schedule is the edit schedule, ordered by start time then ID.
+	Schedule []CBSSlot
+
+	// Success indicates if a conflict-free schedule was found.
+	Success bool
+
+	// Makespan is the end time of the last edit.
+	Makespan int
+
+	// Cost is the sum of edit end times.
+	Cost int
+
+	// NodesExpanded counts constraint tree nodes expanded.
+	NodesExpanded int
+
+	// ConflictsResolved counts conflicts split in the constraint tree.
+	ConflictsResolved int
+
+	// FailureReason explains why planning failed (if applicable).
+	FailureReason string
+}
+
+// CBSSlot is a scheduled edit.
+type CBSSlot struct {
+	EditID string   // The edit
+	Start  int      // First time slot
+	End    int      // Slot after the last one (exclusive)
+	Locks  []string // Files and resources held, sorted
+}
+
+// Order returns the edit IDs in schedule order.
+func (o *CBSOutput) Order() []string {
+	order := make([]string, len(o.Schedule))
+	for i, s := range o.Schedule {
+		order[i] = s.EditID
+	}
+	return order
+}
+
+// HTNMethod returns an HTN method that decomposes taskName into the
+// scheduled edits, in order, as primitive "apply_edit" tasks.
+//
+// Each subtask's Parameters hold the "files" (comma-separated), "start",
+// and "end" of its slot.
+func (o *CBSOutput) HTNMethod(methodID, taskName string) HTNMethod {
+	subtasks := make([]HTNTask, len(o.Schedule))
+	for i, s := range o.Schedule {
+		subtasks[i] = HTNTask{
+			ID:   s.EditID,
+			Name: "apply_edit",
+			Parameters: map[string]string{
+				"files": strings.Join(s.Locks, ","),
+				"start": strconv.Itoa(s.Start),
+				"end":   strconv.Itoa(s.End),
+			},
+			IsPrimitive: true,
+		}
+	}
+	return HTNMethod{ID: methodID, TaskName: taskName, Subtasks: subtasks}
+}
+
+// -----------------------------------------------------------------------------
+// Algorithm Interface Implementation
+// -----------------------------------------------------------------------------
+
+// Name returns the algorithm name.
+func (c *CBS) Name() string {
+	return "cbs"
+}
+
+// Process plans a conflict-free edit schedule.
+//
+// Description:
+//
+//	Runs conflict-based search over the input edits and returns the
+//	schedule plus a constraint delta recording file locks and the
+//	resulting edit order. Returns a failure output (no error) when the
+//	dependencies form a cycle or MaxNodes is reached.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *CBS) Process(ctx context.Context, snapshot crs.Snapshot, input any) (any, crs.Delta, error) {
+	in, ok := input.(*CBSInput)
+	if !ok {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "cbs",
+			Operation: "Process",
+			Err:       ErrInvalidInput,
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return &CBSOutput{FailureReason: "cancelled"}, nil, ctx.Err()
+	default:
+	}
+
+	problem, err := newCBSProblem(in, snapshot.ConstraintIndex())
+	if err != nil {
+		return nil, nil, &AlgorithmError{Algorithm: "cbs", Operation: "Process", Err: err}
+	}
+
+	output := &CBSOutput{}
+	if problem.topo == nil {
+		output.FailureReason = "dependency cycle among edits"
+		return output, nil, nil
+	}
+
+	root := &cbsNode{forbidden: make(map[string][]cbsInterval)}
+	problem.plan(root)
+	open := &cbsQueue{root}
+	seq := 0
+
+	for open.Len() > 0 {
+		if output.NodesExpanded%64 == 0 {
+			select {
+			case <-ctx.Done():
+				output.FailureReason = "cancelled"
+				return output, nil, ctx.Err()
+			default:
+			}
+		}
+		if output.NodesExpanded >= c.config.MaxNodes {
+			output.FailureReason = "max nodes expanded"
+			return output, nil, nil
+		}
+
+		node := heap.Pop(open).(*cbsNode)
+		output.NodesExpanded++
+
+		conflict, found := problem.firstConflict(node)
+		if !found {
+			problem.fillOutput(output, node)
+			output.Success = true
+			return output, problem.delta(in.Source, snapshot.ConstraintIndex(), node), nil
+		}
+		output.ConflictsResolved++
+
+		for _, split := range [2][2]int{{conflict.a, conflict.b}, {conflict.b, conflict.a}} {
+			edit, other := split[0], split[1]
+			child := node.withForbidden(problem.ids[edit], cbsInterval{
+				lo: node.start[other],
+				hi: node.start[other] + problem.durations[other],
+			})
+			problem.plan(child)
+			seq++
+			child.seq = seq
+			heap.Push(open, child)
+		}
+	}
+
+	output.FailureReason = "no conflict-free schedule"
+	return output, nil, nil
+}
+
+// cbsProblem is the preprocessed input.
+type cbsProblem struct {
+	ids       []string
+	durations []int
+	locks     [][]string // per edit, sorted
+	deps      [][]int    // per edit, indexes of edits that must finish first
+	topo      []int      // dependency order; nil if cyclic
+
+	// holders maps a lock to the edits holding it, in index order.
+	holders map[string][]int
+
+	// fromSnapshot marks locks that only come from existing resource
+	// constraints, not from any input edit's files.
+	fromSnapshot map[string]bool
+}
+
+// cbsInterval is a half-open range of time slots [lo, hi).
+type cbsInterval struct {
+	lo, hi int
+}
+
+// cbsConflict is two edits holding the same lock at the same time.
+type cbsConflict struct {
+	a, b int
+}
+
+func newCBSProblem(in *CBSInput, ci crs.ConstraintIndexView) (*cbsProblem, error) {
+	p := &cbsProblem{
+		holders:      make(map[string][]int),
+		fromSnapshot: make(map[string]bool),
+	}
+	index := make(map[string]int, len(in.Edits))
+	for i, e := range in.Edits {
+		if e.ID == "" {
+			return nil, fmt.Errorf("%w: edit %d has no ID", ErrInvalidInput, i)
+		}
+		if _, dup := index[e.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate edit %s", ErrInvalidInput, e.ID)
+		}
+		index[e.ID] = i
+		p.ids = append(p.ids, e.ID)
+		p.durations = append(p.durations, max(e.Duration, 1))
+	}
+
+	p.locks = make([][]string, len(in.Edits))
+	p.deps = make([][]int, len(in.Edits))
+	for i, e := range in.Edits {
+		p.locks[i] = slices.Clone(e.Files)
+		for _, dep := range e.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("%w: edit %s depends on unknown edit %s", ErrInvalidInput, e.ID, dep)
+			}
+			p.deps[i] = append(p.deps[i], j)
+		}
+	}
+
+	// Locks and orderings already recorded in the constraint index.
+	for _, con := range ci.FindByType(crs.ConstraintTypeResource) {
+		if !con.Active {
+			continue
+		}
+		name := con.Expression
+		if name == "" {
+			name = con.ID
+		}
+		for _, nodeID := range con.Nodes {
+			if i, ok := index[nodeID]; ok {
+				p.locks[i] = append(p.locks[i], name)
+				p.fromSnapshot[name] = true
+			}
+		}
+	}
+	for _, con := range ci.FindByType(crs.ConstraintTypeBefore) {
+		if !con.Active || len(con.Nodes) != 2 {
+			continue
+		}
+		from, okFrom := index[con.Nodes[0]]
+		to, okTo := index[con.Nodes[1]]
+		if okFrom && okTo {
+			p.deps[to] = append(p.deps[to], from)
+		}
+	}
+
+	for _, e := range in.Edits {
+		for _, f := range e.Files {
+			delete(p.fromSnapshot, f)
+		}
+	}
+	for i := range p.locks {
+		slices.Sort(p.locks[i])
+		p.locks[i] = slices.Compact(p.locks[i])
+		for _, lock := range p.locks[i] {
+			p.holders[lock] = append(p.holders[lock], i)
+		}
+		slices.Sort(p.deps[i])
+		p.deps[i] = slices.Compact(p.deps[i])
+	}
+	p.topo = p.topoOrder()
+	return p, nil
+}
+
+// topoOrder orders edits so each follows its dependencies, breaking ties by
+// input order. Returns nil if the dependencies form a cycle.
+func (p *cbsProblem) topoOrder() []int {
+	indegree := make([]int, len(p.ids))
+	dependents := make([][]int, len(p.ids))
+	for i, deps := range p.deps {
+		indegree[i] = len(deps)
+		for _, d := range deps {
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	var ready, order []int
+	for i, d := range indegree {
+		if d == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+		for _, next := range dependents[i] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	if len(order) < len(p.ids) {
+		return nil
+	}
+	return order
+}
+
+// plan runs the low-level search: every edit is placed at the earliest slot
+// after its dependencies that avoids its forbidden intervals.
+func (p *cbsProblem) plan(n *cbsNode) {
+	n.start = make([]int, len(p.ids))
+	n.cost = 0
+	for _, i := range p.topo {
+		t := 0
+		for _, d := range p.deps[i] {
+			t = max(t, n.start[d]+p.durations[d])
+		}
+		forbidden := n.forbidden[p.ids[i]]
+		for moved := true; moved; {
+			moved = false
+			for _, f := range forbidden {
+				if t < f.hi && t+p.durations[i] > f.lo {
+					t = f.hi
+					moved = true
+				}
+			}
+		}
+		n.start[i] = t
+		n.cost += t + p.durations[i]
+	}
+}
+
+// firstConflict returns the earliest pair of edits holding a lock at the
+// same time, breaking ties by lock name then edit order.
+func (p *cbsProblem) firstConflict(n *cbsNode) (cbsConflict, bool) {
+	best, bestAt, found := cbsConflict{}, 0, false
+	locks := make([]string, 0, len(p.holders))
+	for lock := range p.holders {
+		locks = append(locks, lock)
+	}
+	slices.Sort(locks)
+
+	for _, lock := range locks {
+		holders := p.holders[lock]
+		for x := 0; x < len(holders); x++ {
+			for y := x + 1; y < len(holders); y++ {
+				a, b := holders[x], holders[y]
+				at := max(n.start[a], n.start[b])
+				if at >= min(n.start[a]+p.durations[a], n.start[b]+p.durations[b]) {
+					continue
+				}
+				if !found || at < bestAt {
+					best, bestAt, found = cbsConflict{a: a, b: b}, at, true
+				}
+			}
+		}
+	}
+	return best, found
+}
+
+// sortedSlots returns the node's schedule ordered by start time then ID.
+func (p *cbsProblem) sortedSlots(n *cbsNode) []CBSSlot {
+	slots := make([]CBSSlot, len(p.ids))
+	for i, id := range p.ids {
+		slots[i] = CBSSlot{
+			EditID: id,
+			Start:  n.start[i],
+			End:    n.start[i] + p.durations[i],
+			Locks:  slices.Clone(p.locks[i]),
+		}
+	}
+	slices.SortFunc(slots, func(a, b CBSSlot) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return strings.Compare(a.EditID, b.EditID)
+	})
+	return slots
+}
+
+func (p *cbsProblem) fillOutput(out *CBSOutput, n *cbsNode) {
+	out.Schedule = p.sortedSlots(n)
+	out.Cost = n.cost
+	for _, s := range out.Schedule {
+		out.Makespan = max(out.Makespan, s.End)
+	}
+}
+
+// delta records the file locks and the edit order of a solution.
+//
+// Constraints written by an earlier run are updated in place; constraints
+// that already match are left alone. Returns nil if nothing changed.
+func (p *cbsProblem) delta(source crs.SignalSource, ci crs.ConstraintIndexView, n *cbsNode) crs.Delta {
+	d := crs.NewConstraintDelta(source)
+	now := time.Now().UnixMilli()
+
+	put := func(c crs.Constraint) {
+		existing, ok := ci.Get(c.ID)
+		switch {
+		case !ok:
+			d.Add = append(d.Add, c)
+		case existing.Type != c.Type || !existing.Active || !slices.Equal(existing.Nodes, c.Nodes):
+			c.CreatedAt = existing.CreatedAt
+			d.Update[c.ID] = c
+		}
+	}
+
+	locks := make([]string, 0, len(p.holders))
+	for lock := range p.holders {
+		locks = append(locks, lock)
+	}
+	slices.Sort(locks)
+
+	for _, lock := range locks {
+		holders := slices.Clone(p.holders[lock])
+		if !p.fromSnapshot[lock] {
+			nodes := make([]string, len(holders))
+			for i, h := range holders {
+				nodes[i] = p.ids[h]
+			}
+			slices.Sort(nodes)
+			if existing, ok := ci.Get(CBSLockPrefix + lock); ok {
+				nodes = mergeSorted(nodes, existing.Nodes)
+			}
+			put(crs.Constraint{
+				ID:         CBSLockPrefix + lock,
+				Type:       crs.ConstraintTypeResource,
+				Nodes:      nodes,
+				Expression: lock,
+				Active:     true,
+				Source:     source,
+				CreatedAt:  now,
+			})
+		}
+
+		// Consecutive holders of a lock must run in sequence.
+		slices.SortFunc(holders, func(a, b int) int {
+			if n.start[a] != n.start[b] {
+				return n.start[a] - n.start[b]
+			}
+			return strings.Compare(p.ids[a], p.ids[b])
+		})
+		for i := 1; i < len(holders); i++ {
+			put(cbsBefore(p.ids[holders[i-1]], p.ids[holders[i]], source, now))
+		}
+	}
+
+	for i, deps := range p.deps {
+		for _, dep := range deps {
+			put(cbsBefore(p.ids[dep], p.ids[i], source, now))
+		}
+	}
+
+	// A pair ordered by two locks yields the same constraint twice.
+	seen := make(map[string]bool, len(d.Add))
+	d.Add = slices.DeleteFunc(d.Add, func(c crs.Constraint) bool {
+		if seen[c.ID] {
+			return true
+		}
+		seen[c.ID] = true
+		return false
+	})
+
+	if len(d.Add) == 0 && len(d.Update) == 0 {
+		return nil
+	}
+	return d
+}
+
+// cbsBefore builds the ordering constraint "from finishes before to starts".
+func cbsBefore(from, to string, source crs.SignalSource, now int64) crs.Constraint {
+	return crs.Constraint{
+		ID:        CBSBeforePrefix + from + "->" + to,
+		Type:      crs.ConstraintTypeBefore,
+		Nodes:     []string{from, to},
+		Active:    true,
+		Source:    source,
+		CreatedAt: now,
+	}
+}
+
+// mergeSorted returns the sorted union of a and b.
+func mergeSorted(a, b []string) []string {
+	out := append(slices.Clone(a), b...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// -----------------------------------------------------------------------------
+// Constraint Tree
+// -----------------------------------------------------------------------------
+
+// cbsNode is a constraint tree node.
+type cbsNode struct {
+	// forbidden maps an edit ID to intervals it may not overlap.
+	forbidden map[string][]cbsInterval
+
+	start []int
+	cost  int
+	seq   int
+}
+
+// withForbidden returns a child node with one more forbidden interval.
+func (n *cbsNode) withForbidden(editID string, iv cbsInterval) *cbsNode {
+	forbidden := make(map[string][]cbsInterval, len(n.forbidden)+1)
+	for id, ivs := range n.forbidden {
+		forbidden[id] = ivs
+	}
+	forbidden[editID] = append(slices.Clip(n.forbidden[editID]), iv)
+	return &cbsNode{forbidden: forbidden}
+}
+
+// cbsQueue is a min-heap of nodes by cost, then creation order.
+type cbsQueue []*cbsNode
+
+func (q cbsQueue) Len() int { return len(q) }
+func (q cbsQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].seq < q[j].seq
+}
+func (q cbsQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *cbsQueue) Push(x any)   { *q = append(*q, x.(*cbsNode)) }
+func (q *cbsQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// Timeout returns the maximum execution time.
+func (c *CBS) Timeout() time.Duration {
+	return c.config.Timeout
+}
+
+// InputType returns the expected input type.
+func (c *CBS) InputType() reflect.Type {
+	return reflect.TypeOf(&CBSInput{})
+}
+
+// OutputType returns the output type.
+func (c *CBS) OutputType() reflect.Type {
+	return reflect.TypeOf(&CBSOutput{})
+}
+
+// ProgressInterval returns how often to report progress.
+func (c *CBS) ProgressInterval() time.Duration {
+	return c.config.ProgressInterval
+}
+
+// SupportsPartialResults returns false; a schedule with conflicts is not
+// usable.
+func (c *CBS) SupportsPartialResults() bool {
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// Evaluable Implementation
+// -----------------------------------------------------------------------------
+
+// Properties returns the correctness properties.
+func (c *CBS) Properties() []eval.Property {
+	return []eval.Property{
+		{
+			Name:        "locks_not_shared",
+			Description: "No two scheduled edits hold the same lock at the same time",
+			Check: func(input, output any) error {
+				out, ok := output.(*CBSOutput)
+				if !ok || !out.Success {
+					return nil
+				}
+				for i, a := range out.Schedule {
+					for _, b := range out.Schedule[i+1:] {
+						if a.Start < b.End && b.Start < a.End && sharesLock(a.Locks, b.Locks) {
+							return &AlgorithmError{
+								Algorithm: "cbs",
+								Operation: "Property.locks_not_shared",
+								Err:       eval.ErrPropertyFailed,
+							}
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "dependencies_respected",
+			Description: "Every edit starts after the edits it depends on finish",
+			Check: func(input, output any) error {
+				in, okIn := input.(*CBSInput)
+				out, okOut := output.(*CBSOutput)
+				if !okIn || !okOut || !out.Success {
+					return nil
+				}
+				slots := make(map[string]CBSSlot, len(out.Schedule))
+				for _, s := range out.Schedule {
+					slots[s.EditID] = s
+				}
+				for _, e := range in.Edits {
+					for _, dep := range e.DependsOn {
+						if slots[dep].End > slots[e.ID].Start {
+							return &AlgorithmError{
+								Algorithm: "cbs",
+								Operation: "Property.dependencies_respected",
+								Err:       eval.ErrPropertyFailed,
+							}
+						}
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// sharesLock reports whether two sorted lock lists intersect.
+func sharesLock(a, b []string) bool {
+	for _, l := range a {
+		if _, ok := slices.BinarySearch(b, l); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Metrics returns the metrics this algorithm exposes.
+func (c *CBS) Metrics() []eval.MetricDefinition {
+	return []eval.MetricDefinition{
+		{
+			Name:        "cbs_schedules_found_total",
+			Type:        eval.MetricCounter,
+			Description: "Total conflict-free schedules found",
+		},
+		{
+			Name:        "cbs_nodes_expanded",
+			Type:        eval.MetricHistogram,
+			Description: "Distribution of constraint tree nodes expanded per run",
+			Buckets:     []float64{1, 10, 100, 1000, 10000},
+		},
+		{
+			Name:        "cbs_conflicts_resolved_total",
+			Type:        eval.MetricCounter,
+			Description: "Total lock conflicts split in the constraint tree",
+		},
+		{
+			Name:        "cbs_makespan",
+			Type:        eval.MetricHistogram,
+			Description: "Distribution of schedule makespans in time slots",
+			Buckets:     []float64{1, 2, 5, 10, 20, 50},
+		},
+	}
+}
+
+// HealthCheck verifies the algorithm is functioning.
+func (c *CBS) HealthCheck(ctx context.Context) error {
+	if c.config == nil {
+		return &AlgorithmError{
+			Algorithm: "cbs",
+			Operation: "HealthCheck",
+			Err:       ErrInvalidConfig,
+		}
+	}
+	if c.config.MaxNodes <= 0 {
+		return &AlgorithmError{
+			Algorithm: "cbs",
+			Operation: "HealthCheck",
+			Err:       errors.New("max nodes must be positive"),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package planning
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/constraints"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+func runCBS(t *testing.T, c crs.CRS, input *CBSInput) (*CBSOutput, crs.Delta) {
+	t.Helper()
+	result, delta, err := NewCBS(nil).Process(context.Background(), c.Snapshot(), input)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	out := result.(*CBSOutput)
+	for _, p := range NewCBS(nil).Properties() {
+		if err := p.Check(input, out); err != nil {
+			t.Errorf("property %s: %v", p.Name, err)
+		}
+	}
+	return out, delta
+}
+
+func TestCBS_Process(t *testing.T) {
+	t.Run("independent edits run in parallel", func(t *testing.T) {
+		out, _ := runCBS(t, crs.New(nil), &CBSInput{Edits: []CBSEdit{
+			{ID: "a", Files: []string{"a.go"}},
+			{ID: "b", Files: []string{"b.go"}},
+		}})
+		if !out.Success || out.Makespan != 1 || out.ConflictsResolved != 0 {
+			t.Errorf("output = %+v, want both edits in slot 0", out)
+		}
+	})
+
+	t.Run("shared file serializes edits optimally", func(t *testing.T) {
+		out, delta := runCBS(t, crs.New(nil), &CBSInput{
+			Edits: []CBSEdit{
+				{ID: "long", Files: []string{"api.go", "impl.go"}, Duration: 3},
+				{ID: "short", Files: []string{"api.go"}},
+				{ID: "other", Files: []string{"impl.go"}, Duration: 2},
+			},
+			Source: crs.SignalSourceHard,
+		})
+		if !out.Success {
+			t.Fatalf("no schedule: %s", out.FailureReason)
+		}
+		// Shortest-first minimizes total completion time: short(0-1),
+		// other(0-2), long(2-5) => 1 + 2 + 5.
+		if out.Cost != 8 || out.Makespan != 5 {
+			t.Errorf("cost = %d, makespan = %d, want 8 and 5; schedule %+v", out.Cost, out.Makespan, out.Schedule)
+		}
+		if got := out.Order(); !slices.Equal(got, []string{"other", "short", "long"}) {
+			t.Errorf("Order() = %v", got)
+		}
+		if out.ConflictsResolved == 0 {
+			t.Error("expected conflicts to be resolved")
+		}
+
+		cd, ok := delta.(*crs.ConstraintDelta)
+		if !ok {
+			t.Fatalf("delta = %T, want *crs.ConstraintDelta", delta)
+		}
+		ids := make([]string, 0, len(cd.Add))
+		for _, con := range cd.Add {
+			ids = append(ids, con.ID)
+		}
+		want := []string{
+			CBSLockPrefix + "api.go",
+			CBSBeforePrefix + "short->long",
+			CBSLockPrefix + "impl.go",
+			CBSBeforePrefix + "other->long",
+		}
+		if !slices.Equal(ids, want) {
+			t.Errorf("constraints = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("dependencies are respected", func(t *testing.T) {
+		out, _ := runCBS(t, crs.New(nil), &CBSInput{Edits: []CBSEdit{
+			{ID: "caller", Files: []string{"main.go"}, DependsOn: []string{"callee"}},
+			{ID: "callee", Files: []string{"lib.go"}, Duration: 2},
+		}})
+		if got := out.Order(); !slices.Equal(got, []string{"callee", "caller"}) || out.Schedule[1].Start != 2 {
+			t.Errorf("schedule = %+v", out.Schedule)
+		}
+	})
+
+	t.Run("dependency cycle fails", func(t *testing.T) {
+		out, delta := runCBS(t, crs.New(nil), &CBSInput{Edits: []CBSEdit{
+			{ID: "a", DependsOn: []string{"b"}},
+			{ID: "b", DependsOn: []string{"a"}},
+		}})
+		if out.Success || delta != nil {
+			t.Errorf("output = %+v, want failure without delta", out)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		algo := NewCBS(nil)
+		snapshot := crs.New(nil).Snapshot()
+		for name, input := range map[string]any{
+			"wrong type":     &HTNInput{},
+			"duplicate edit": &CBSInput{Edits: []CBSEdit{{ID: "a"}, {ID: "a"}}},
+			"unknown dep":    &CBSInput{Edits: []CBSEdit{{ID: "a", DependsOn: []string{"x"}}}},
+		} {
+			if _, _, err := algo.Process(context.Background(), snapshot, input); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+			}
+		}
+	})
+
+	t.Run("max nodes", func(t *testing.T) {
+		algo := NewCBS(&CBSConfig{MaxNodes: 1})
+		result, _, err := algo.Process(context.Background(), crs.New(nil).Snapshot(), &CBSInput{Edits: []CBSEdit{
+			{ID: "a", Files: []string{"x.go"}},
+			{ID: "b", Files: []string{"x.go"}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := result.(*CBSOutput); out.Success || out.FailureReason != "max nodes expanded" {
+			t.Errorf("output = %+v", out)
+		}
+	})
+}
+
+func TestCBS_ConstraintIndex(t *testing.T) {
+	ctx := context.Background()
+	c := crs.New(nil)
+
+	// A lock the agent already knows about, not named in the input.
+	seed := crs.NewConstraintDelta(crs.SignalSourceHard)
+	seed.Add = []crs.Constraint{{
+		ID: "gen-lock", Type: crs.ConstraintTypeResource,
+		Nodes: []string{"a", "b"}, Expression: "generated.pb.go", Active: true,
+	}}
+	if _, err := c.Apply(ctx, seed); err != nil {
+		t.Fatal(err)
+	}
+
+	input := &CBSInput{
+		Edits: []CBSEdit{
+			{ID: "a", Files: []string{"a.go"}},
+			{ID: "b", Files: []string{"b.go"}},
+		},
+		Source: crs.SignalSourceHard,
+	}
+	out, delta := runCBS(t, c, input)
+	if out.Makespan != 2 {
+		t.Fatalf("snapshot lock ignored: %+v", out.Schedule)
+	}
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	// The ordering is visible to the temporal network.
+	order, err := constraints.NewTemporalNetwork(c.Snapshot().ConstraintIndex()).Order()
+	if err != nil || !slices.Equal(order, out.Order()) {
+		t.Errorf("temporal order = %v, %v; want %v", order, err, out.Order())
+	}
+
+	// Re-running is idempotent and keeps the recorded order.
+	again, delta := runCBS(t, c, input)
+	if delta != nil {
+		t.Errorf("second run delta = %+v, want nil", delta)
+	}
+	if !slices.Equal(again.Order(), out.Order()) {
+		t.Errorf("second run order = %v, want %v", again.Order(), out.Order())
+	}
+
+	// The schedule decomposes into an HTN plan.
+	htnOut, _, err := NewHTN(nil).Process(ctx, c.Snapshot(), &HTNInput{
+		Tasks:   []HTNTask{{ID: "goal", Name: "apply_change"}},
+		Methods: []HTNMethod{out.HTNMethod("cbs", "apply_change")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := htnOut.(*HTNOutput).Plan
+	if len(plan) != 2 || plan[0].ID != out.Order()[0] || plan[1].Parameters["start"] != "1" {
+		t.Errorf("HTN plan = %+v", plan)
+	}
+}
+
+func TestCBS_Evaluable(t *testing.T) {
+	algo := NewCBS(nil)
+	if algo.Name() != "cbs" || len(algo.Properties()) == 0 || len(algo.Metrics()) == 0 {
+		t.Error("incomplete Evaluable implementation")
+	}
+	if err := algo.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck: %v", err)
+	}
+	if err := NewCBS(&CBSConfig{}).HealthCheck(context.Background()); err == nil {
+		t.Error("expected HealthCheck to reject MaxNodes 0")
+	}
+}
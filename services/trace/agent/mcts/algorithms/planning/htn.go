@@ -39,6 +39,11 @@ func (e *AlgorithmError) Error() string {
 	return e.Algorithm + "." + e.Operation + ": " + e.Err.Error()
 }
 
+// Unwrap returns the underlying error.
+func (e *AlgorithmError) Unwrap() error {
+	return e.Err
+}
+
 // -----------------------------------------------------------------------------
 // Hierarchical Task Network (HTN) Algorithm
 // -----------------------------------------------------------------------------
@@ -65,6 +70,13 @@ func (e *AlgorithmError) Error() string {
 //	4. Decompose using the first applicable method
 //	5. Repeat until only primitives remain
 //
+//	Plan Repair:
+//	When HTNInput.Previous holds an earlier plan, HTN repairs it instead
+//	of replanning: each compound task keeps the method it was decomposed
+//	with as long as that method's preconditions still hold, and only
+//	tasks whose method broke are re-decomposed. HTNOutput.Repair lists
+//	just the decompositions that changed.
+//
 // Thread Safety: Safe for concurrent use.
 type HTN struct {
 	config *HTNConfig
@@ -118,6 +130,11 @@ type HTNInput struct {
 	// InitialState is the starting world state.
 	InitialState map[string]bool
 
+	// Previous is an earlier plan for the same tasks. When set, HTN runs
+	// in plan-repair mode and reuses Previous.DecompositionTree wherever
+	// its methods still apply. Nil plans from scratch.
+	Previous *HTNOutput
+
 	// Source indicates where the planning request originated.
 	Source crs.SignalSource
 }
@@ -186,6 +203,28 @@ type HTNOutput struct {
 
 	// FailureReason explains why planning failed (if applicable).
 	FailureReason string
+
+	// Repair describes what changed relative to HTNInput.Previous. Nil
+	// when not repairing.
+	Repair *HTNRepair
+}
+
+// HTNRepair summarizes a plan repair.
+type HTNRepair struct {
+	// Reused counts decompositions kept from the previous plan.
+	Reused int
+
+	// Invalidated lists tasks whose previous method no longer applied
+	// and that were re-decomposed.
+	Invalidated []string
+
+	// Changed holds only the decompositions that differ from the
+	// previous plan, in decomposition order.
+	Changed []HTNDecomposition
+
+	// Removed lists tasks decomposed in the previous plan that are no
+	// longer part of the plan.
+	Removed []string
 }
 
 // HTNDecomposition records a decomposition step.
@@ -256,6 +295,16 @@ func (h *HTN) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 		methodsByTask[taskName] = methods
 	}
 
+	// In repair mode, index the previous decomposition of each task
+	var prior map[string]HTNDecomposition
+	if in.Previous != nil {
+		prior = make(map[string]HTNDecomposition, len(in.Previous.DecompositionTree))
+		for _, d := range in.Previous.DecompositionTree {
+			prior[d.TaskID] = d
+		}
+		output.Repair = &HTNRepair{}
+	}
+
 	// Create task queue from input tasks
 	taskQueue := make([]htnTaskWithDepth, 0, len(in.Tasks))
 	for _, t := range in.Tasks {
@@ -315,15 +364,32 @@ func (h *HTN) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 			return output, nil, nil
 		}
 
-		// Find first applicable method
+		// In repair mode, keep the previous method while it still applies
 		var appliedMethod *HTNMethod
-		for i := range methods {
+		previous, hadPrevious := prior[current.task.ID]
+		if hadPrevious {
+			for i := range methods {
+				if methods[i].ID != previous.MethodID {
+					continue
+				}
+				output.MethodsConsidered++
+				if h.checkPreconditions(methods[i].Preconditions, state) {
+					appliedMethod = &methods[i]
+				}
+				break
+			}
+			if appliedMethod == nil {
+				output.Repair.Invalidated = append(output.Repair.Invalidated, current.task.ID)
+			}
+		}
+
+		// Otherwise find first applicable method
+		for i := 0; appliedMethod == nil && i < len(methods); i++ {
 			m := &methods[i]
 			output.MethodsConsidered++
 
 			if h.checkPreconditions(m.Preconditions, state) {
 				appliedMethod = m
-				break
 			}
 		}
 
@@ -337,12 +403,20 @@ func (h *HTN) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 		for i, st := range appliedMethod.Subtasks {
 			subtaskIDs[i] = st.ID
 		}
-		output.DecompositionTree = append(output.DecompositionTree, HTNDecomposition{
+		decomposition := HTNDecomposition{
 			TaskID:   current.task.ID,
 			MethodID: appliedMethod.ID,
 			Subtasks: subtaskIDs,
 			Depth:    current.depth,
-		})
+		}
+		output.DecompositionTree = append(output.DecompositionTree, decomposition)
+		if output.Repair != nil {
+			if hadPrevious && previous.MethodID == decomposition.MethodID && previous.Depth == decomposition.Depth {
+				output.Repair.Reused++
+			} else {
+				output.Repair.Changed = append(output.Repair.Changed, decomposition)
+			}
+		}
 
 		// Add subtasks to front of queue (depth-first)
 		newQueue := make([]htnTaskWithDepth, 0, len(appliedMethod.Subtasks)+len(taskQueue))
@@ -362,6 +436,18 @@ func (h *HTN) Process(ctx context.Context, snapshot crs.Snapshot, input any) (an
 	output.Success = true
 	output.FinalState = state
 
+	if output.Repair != nil {
+		kept := make(map[string]bool, len(output.DecompositionTree))
+		for _, d := range output.DecompositionTree {
+			kept[d.TaskID] = true
+		}
+		for _, d := range in.Previous.DecompositionTree {
+			if !kept[d.TaskID] {
+				output.Repair.Removed = append(output.Repair.Removed, d.TaskID)
+			}
+		}
+	}
+
 	return output, nil, nil
 }
 
@@ -12,6 +12,7 @@ package planning
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -476,3 +477,95 @@ func TestHTN_Evaluable(t *testing.T) {
 		}
 	})
 }
+
+func TestHTN_Repair(t *testing.T) {
+	algo := NewHTN(nil)
+	ctx := context.Background()
+	snapshot := crs.New(nil).Snapshot()
+
+	methods := []HTNMethod{
+		{
+			ID: "ship", TaskName: "release", Priority: 1,
+			Subtasks: []HTNTask{
+				{ID: "build", Name: "build"},
+				{ID: "verify", Name: "verify"},
+			},
+		},
+		{
+			ID: "build_go", TaskName: "build",
+			Subtasks: []HTNTask{{ID: "go_build", Name: "go_build", IsPrimitive: true}},
+		},
+		{
+			ID: "run_tests", TaskName: "verify", Priority: 2,
+			Preconditions: []HTNPrecondition{{Predicate: "tests_pass", Value: true}},
+			Subtasks:      []HTNTask{{ID: "go_test", Name: "go_test", IsPrimitive: true}},
+		},
+		{
+			ID: "fix_tests", TaskName: "verify", Priority: 1,
+			Subtasks: []HTNTask{
+				{ID: "fix", Name: "fix", IsPrimitive: true},
+				{ID: "retest", Name: "go_test", IsPrimitive: true},
+			},
+		},
+	}
+	plan := func(state map[string]bool, previous *HTNOutput, extra ...HTNMethod) *HTNOutput {
+		t.Helper()
+		result, _, err := algo.Process(ctx, snapshot, &HTNInput{
+			Tasks:        []HTNTask{{ID: "goal", Name: "release"}},
+			Methods:      append(append([]HTNMethod{}, methods...), extra...),
+			InitialState: state,
+			Previous:     previous,
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*HTNOutput)
+		if !out.Success {
+			t.Fatalf("planning failed: %s", out.FailureReason)
+		}
+		return out
+	}
+
+	first := plan(map[string]bool{"tests_pass": true}, nil)
+	if first.Repair != nil {
+		t.Error("Repair set without a previous plan")
+	}
+
+	t.Run("patches only the invalidated subtask", func(t *testing.T) {
+		repaired := plan(map[string]bool{"tests_pass": false}, first)
+		r := repaired.Repair
+		if r == nil {
+			t.Fatal("expected Repair")
+		}
+		if r.Reused != 2 {
+			t.Errorf("Reused = %d, want 2 (goal, build)", r.Reused)
+		}
+		if len(r.Invalidated) != 1 || r.Invalidated[0] != "verify" {
+			t.Errorf("Invalidated = %v, want [verify]", r.Invalidated)
+		}
+		if len(r.Changed) != 1 || r.Changed[0].MethodID != "fix_tests" {
+			t.Errorf("Changed = %+v, want only verify -> fix_tests", r.Changed)
+		}
+		var ids []string
+		for _, task := range repaired.Plan {
+			ids = append(ids, task.ID)
+		}
+		if got := strings.Join(ids, ","); got != "go_build,fix,retest" {
+			t.Errorf("plan = %s, want go_build,fix,retest", got)
+		}
+	})
+
+	t.Run("keeps valid decompositions over new preferred methods", func(t *testing.T) {
+		preferred := HTNMethod{
+			ID: "ship_fast", TaskName: "release", Priority: 10,
+			Subtasks: []HTNTask{{ID: "yolo", Name: "deploy", IsPrimitive: true}},
+		}
+		repaired := plan(map[string]bool{"tests_pass": true}, first, preferred)
+		if repaired.DecompositionTree[0].MethodID != "ship" {
+			t.Errorf("goal method = %s, want ship", repaired.DecompositionTree[0].MethodID)
+		}
+		if len(repaired.Repair.Changed) != 0 || len(repaired.Repair.Removed) != 0 {
+			t.Errorf("Repair = %+v, want no changes", repaired.Repair)
+		}
+	})
+}
@@ -36,6 +36,7 @@ import (
 //	- Enforces timeouts and cancellation
 //	- Collects results via channels
 //	- Merges deltas into a composite
+//	- Reuses cached results if configured with WithResultCache
 //
 // Thread Safety: Safe for concurrent use.
 type Runner struct {
@@ -43,27 +44,53 @@ type Runner struct {
 	results chan *Result
 	wg      sync.WaitGroup
 	logger  *slog.Logger
+	cache   *ResultCache
 
 	// Tracking
 	started   int
 	completed int
 }
 
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithResultCache memoizes algorithm results in cache.
+//
+// Description:
+//
+//	Before running an algorithm, the runner looks up the algorithm name,
+//	snapshot generation and input hash in the cache. On a hit the cached
+//	output and delta are returned without calling Process. Successful
+//	runs are stored. The cache can be shared between runners.
+//
+// Inputs:
+//   - cache: The result cache. Nil disables caching.
+func WithResultCache(cache *ResultCache) RunnerOption {
+	return func(r *Runner) {
+		r.cache = cache
+	}
+}
+
 // NewRunner creates a new algorithm runner.
 //
 // Inputs:
 //   - capacity: Buffer size for results channel. Default: 10.
+//   - opts: Optional configuration.
 //
 // Outputs:
 //   - *Runner: The new runner.
-func NewRunner(capacity int) *Runner {
+func NewRunner(capacity int, opts ...RunnerOption) *Runner {
 	if capacity <= 0 {
 		capacity = 10
 	}
-	return &Runner{
+	r := &Runner{
 		results: make(chan *Result, capacity),
 		logger:  slog.Default().With(slog.String("component", "algorithm_runner")),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Run starts an algorithm in a goroutine.
@@ -116,6 +143,34 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 		Metrics:   make(map[string]float64),
 	}
 
+	// Check the result cache
+	cacheKey, cacheable := ResultCacheKey{}, false
+	if r.cache != nil {
+		cacheKey, cacheable = NewResultCacheKey(algo, snapshot, input)
+	}
+	if cacheable {
+		if output, delta, ok := r.cache.Get(cacheKey); ok {
+			endTime := time.Now()
+			result.EndTime = endTime.UnixMilli()
+			result.Duration = endTime.Sub(startTime)
+			result.Output = output
+			result.Delta = delta
+			result.Cached = true
+			result.Quality = 1
+			span.SetAttributes(attribute.Bool("cached", true))
+			r.logger.Debug("algorithm result cached",
+				slog.String("algorithm", name),
+				slog.Int64("generation", cacheKey.Generation),
+			)
+			r.finish(result)
+			return
+		}
+	}
+
+	if recorder := inputRecorderFrom(ctx); recorder != nil {
+		recorder.RecordInput(ctx, algo, snapshot, input)
+	}
+
 	// Execute algorithm
 	output, delta, err := algo.Process(ctx, snapshot, input)
 
@@ -134,6 +189,11 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 		if err == nil {
 			result.Err = ctx.Err()
 		}
+		if qr, ok := algo.(QualityReporter); ok && result.Partial {
+			result.Quality = clampQuality(qr.PartialQuality(input, output))
+		}
+	} else if err == nil {
+		result.Quality = 1
 	}
 
 	// Record span attributes
@@ -141,6 +201,7 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 		attribute.Int64("duration_ms", result.Duration.Milliseconds()),
 		attribute.Bool("success", result.Success()),
 		attribute.Bool("cancelled", result.Cancelled),
+		attribute.Float64("quality", result.Quality),
 	)
 	if err != nil {
 		span.RecordError(err)
@@ -159,9 +220,67 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 			slog.String("algorithm", name),
 			slog.Duration("duration", result.Duration),
 			slog.Bool("cancelled", result.Cancelled),
+			slog.Float64("quality", result.Quality),
 		)
 	}
 
+	if cacheable && result.Success() {
+		r.cache.Put(cacheKey, output, delta)
+	}
+
+	r.finish(result)
+}
+
+// -----------------------------------------------------------------------------
+// Input Recording
+// -----------------------------------------------------------------------------
+
+// InputRecorder captures the inputs algorithms run on.
+//
+// Description:
+//
+//	A recorder attached to a context with ContextWithInputRecorder sees
+//	every algorithm the runner executes under that context, before it runs,
+//	with the snapshot and input it is given. Cached results are not
+//	recorded. The eval replay package uses this to build a corpus of real
+//	inputs.
+//
+// Thread Safety: Implementations must be safe for concurrent use and
+// should return quickly, since they run on the algorithm's goroutine.
+type InputRecorder interface {
+	// RecordInput records one algorithm execution. It must not modify
+	// snapshot or input.
+	RecordInput(ctx context.Context, algo Algorithm, snapshot crs.Snapshot, input any)
+}
+
+type inputRecorderKey struct{}
+
+// ContextWithInputRecorder returns a context whose algorithm runs are
+// recorded by recorder. A nil recorder disables recording.
+func ContextWithInputRecorder(ctx context.Context, recorder InputRecorder) context.Context {
+	return context.WithValue(ctx, inputRecorderKey{}, recorder)
+}
+
+// inputRecorderFrom returns the recorder attached to ctx, or nil.
+func inputRecorderFrom(ctx context.Context) InputRecorder {
+	recorder, _ := ctx.Value(inputRecorderKey{}).(InputRecorder)
+	return recorder
+}
+
+// clampQuality limits a reported quality score to [0, 1].
+func clampQuality(q float64) float64 {
+	switch {
+	case q > 1:
+		return 1
+	case q >= 0:
+		return q
+	default: // negative or NaN
+		return 0
+	}
+}
+
+// finish counts a completed algorithm and sends its result.
+func (r *Runner) finish(result *Result) {
 	// Update counters
 	r.mu.Lock()
 	r.completed++
@@ -172,7 +291,7 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 	case r.results <- result:
 	default:
 		r.logger.Warn("result channel full, dropping result",
-			slog.String("algorithm", name),
+			slog.String("algorithm", result.Name),
 		)
 	}
 }
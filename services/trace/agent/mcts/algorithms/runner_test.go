@@ -13,6 +13,8 @@ package algorithms
 import (
 	"context"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,6 +30,7 @@ type mockAlgorithm struct {
 	delay      time.Duration
 	output     string
 	delta      crs.Delta
+	calls      atomic.Int32
 }
 
 func (m *mockAlgorithm) Name() string {
@@ -35,6 +38,7 @@ func (m *mockAlgorithm) Name() string {
 }
 
 func (m *mockAlgorithm) Process(ctx context.Context, snapshot crs.Snapshot, input any) (any, crs.Delta, error) {
+	m.calls.Add(1)
 	if m.delay > 0 {
 		select {
 		case <-time.After(m.delay):
@@ -292,3 +296,194 @@ func TestRunParallel(t *testing.T) {
 		t.Errorf("expected 2 results, got %d", len(results))
 	}
 }
+
+func TestRunner_ResultCache(t *testing.T) {
+	ctx := context.Background()
+	c := crs.New(nil)
+	cache := NewResultCache(DefaultResultCacheConfig())
+	algo := &mockAlgorithm{name: "cached", timeout: time.Second, output: "out"}
+
+	run := func(snapshot crs.Snapshot, input any) *Result {
+		t.Helper()
+		runner := NewRunner(1, WithResultCache(cache))
+		runner.Run(ctx, algo, snapshot, input)
+		_, results, err := runner.Collect(ctx)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("collect = %v, %d results", err, len(results))
+		}
+		return results[0]
+	}
+
+	snapshot := c.Snapshot()
+	if res := run(snapshot, map[string]int{"a": 1, "b": 2}); res.Cached || res.Output != "out" {
+		t.Errorf("first run = %+v, want uncached", res)
+	}
+	if res := run(snapshot, map[string]int{"b": 2, "a": 1}); !res.Cached || res.Output != "out" {
+		t.Errorf("equivalent input = %+v, want cached", res)
+	}
+	if res := run(snapshot, map[string]int{"a": 2}); res.Cached {
+		t.Error("different input was served from cache")
+	}
+	if got := algo.calls.Load(); got != 2 {
+		t.Errorf("Process called %d times, want 2", got)
+	}
+
+	delta := crs.NewProofDelta(crs.SignalSourceHard, map[string]crs.ProofNumber{"n": {Proof: 1, Disproof: 1}})
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if res := run(c.Snapshot(), map[string]int{"a": 1, "b": 2}); res.Cached {
+		t.Error("new generation was served from cache")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 3 || stats.Size != 3 {
+		t.Errorf("stats = %+v, want 1 hit, 3 misses, 3 entries", stats)
+	}
+
+	failing := &mockAlgorithm{name: "failing", timeout: time.Second, processErr: ErrInvalidConfig}
+	for range 2 {
+		runner := NewRunner(1, WithResultCache(cache))
+		runner.Run(ctx, failing, snapshot, "x")
+		if _, _, err := runner.Collect(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := failing.calls.Load(); got != 2 {
+		t.Errorf("failed result was cached: Process called %d times", got)
+	}
+}
+
+// recordingRecorder collects the inputs it is given.
+type recordingRecorder struct {
+	mu     sync.Mutex
+	inputs map[string][]any
+}
+
+func (r *recordingRecorder) RecordInput(_ context.Context, algo Algorithm, _ crs.Snapshot, input any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inputs[algo.Name()] = append(r.inputs[algo.Name()], input)
+}
+
+func TestRunner_InputRecorder(t *testing.T) {
+	snapshot := crs.New(nil).Snapshot()
+	recorder := &recordingRecorder{inputs: make(map[string][]any)}
+	ctx := ContextWithInputRecorder(context.Background(), recorder)
+
+	algos := []Algorithm{
+		&mockAlgorithm{name: "a", timeout: time.Second, output: "x"},
+		&mockAlgorithm{name: "b", timeout: time.Second, output: "y"},
+	}
+	if _, _, err := RunParallel(ctx, snapshot,
+		Execution{Algorithm: algos[0], Input: "in-a"},
+		Execution{Algorithm: algos[1], Input: "in-b"},
+	); err != nil {
+		t.Fatalf("RunParallel: %v", err)
+	}
+	if got := recorder.inputs["a"]; len(got) != 1 || got[0] != "in-a" {
+		t.Errorf("recorded for a = %v, want [in-a]", got)
+	}
+	if got := recorder.inputs["b"]; len(got) != 1 || got[0] != "in-b" {
+		t.Errorf("recorded for b = %v, want [in-b]", got)
+	}
+
+	// Runs without a recorder in the context are not recorded.
+	runner := NewRunner(1)
+	runner.Run(context.Background(), algos[0], snapshot, "unrecorded")
+	if _, _, err := runner.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(recorder.inputs["a"]); got != 1 {
+		t.Errorf("a recorded %d times, want 1", got)
+	}
+}
+
+func TestResultCache_Eviction(t *testing.T) {
+	cache := NewResultCache(ResultCacheConfig{MaxEntries: 2, TTL: time.Minute})
+	now := time.Unix(0, 0)
+	cache.now = func() time.Time { return now }
+
+	key := func(id string) ResultCacheKey { return ResultCacheKey{Algorithm: "a", InputHash: id} }
+	cache.Put(key("1"), 1, nil)
+	cache.Put(key("2"), 2, nil)
+	cache.Get(key("1")) // 2 is now least recently used
+	cache.Put(key("3"), 3, nil)
+
+	if _, _, ok := cache.Get(key("2")); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if out, _, ok := cache.Get(key("1")); !ok || out != 1 {
+		t.Errorf("Get(1) = %v, %v", out, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, _, ok := cache.Get(key("3")); ok {
+		t.Error("expired entry was returned")
+	}
+	if stats := cache.Stats(); stats.Evictions != 2 || stats.Size != 1 {
+		t.Errorf("stats = %+v, want 2 evictions, 1 entry", stats)
+	}
+}
+
+// anytimeAlgorithm is a mockAlgorithm that reports partial quality.
+type anytimeAlgorithm struct {
+	mockAlgorithm
+	quality float64
+}
+
+func (a *anytimeAlgorithm) PartialQuality(_, output any) float64 {
+	if output == nil {
+		return 0
+	}
+	return a.quality
+}
+
+func TestRunner_Quality(t *testing.T) {
+	ctx := context.Background()
+	snapshot := crs.New(nil).Snapshot()
+
+	run := func(algo Algorithm) *Result {
+		t.Helper()
+		runner := NewRunner(1)
+		runner.Run(ctx, algo, snapshot, nil)
+		_, results, err := runner.Collect(ctx)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Collect = %v, %d results", err, len(results))
+		}
+		return results[0]
+	}
+
+	t.Run("complete result has quality 1", func(t *testing.T) {
+		r := run(&mockAlgorithm{name: "done", timeout: time.Second, output: "x"})
+		if r.Quality != 1 {
+			t.Errorf("Quality = %v, want 1", r.Quality)
+		}
+	})
+
+	t.Run("partial result without reporter has quality 0", func(t *testing.T) {
+		r := run(&mockAlgorithm{name: "slow", timeout: 20 * time.Millisecond, delay: time.Second})
+		if !r.Partial || r.Quality != 0 {
+			t.Errorf("Partial = %v, Quality = %v, want true, 0", r.Partial, r.Quality)
+		}
+	})
+
+	for _, tc := range []struct {
+		reported, want float64
+	}{
+		{0.75, 0.75},
+		{1.5, 1},
+		{-2, 0},
+	} {
+		r := run(&anytimeAlgorithm{
+			mockAlgorithm: mockAlgorithm{name: "anytime", timeout: 20 * time.Millisecond, delay: time.Second},
+			quality:       tc.reported,
+		})
+		if !r.Cancelled || !r.Partial {
+			t.Fatalf("Cancelled = %v, Partial = %v, want true", r.Cancelled, r.Partial)
+		}
+		if r.Quality != tc.want {
+			t.Errorf("reported %v: Quality = %v, want %v", tc.reported, r.Quality, tc.want)
+		}
+	}
+}
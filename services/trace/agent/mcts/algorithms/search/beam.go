@@ -0,0 +1,513 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package search
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+// -----------------------------------------------------------------------------
+// Beam Search Algorithm
+// -----------------------------------------------------------------------------
+
+// BeamSearch selects the most proving node with a breadth-limited search.
+//
+// Description:
+//
+//	BeamSearch is an alternative to PN-MCTS over the same dependency
+//	graph and proof numbers. Starting from the root, it expands every node
+//	in the beam level by level and keeps only the BeamWidth most promising
+//	paths, ranked by the proof number of their last node. The selected node
+//	is the most promising node whose unsolved children are exhausted, or
+//	that the depth limit stopped at.
+//
+//	Unlike PN-MCTS, BeamSearch does not revisit nodes: it runs one pass,
+//	so its cost is bounded by BeamWidth × depth expansions. It accepts
+//	*PNMCTSInput as well as *BeamSearchInput, so the ab harness can run
+//	both algorithms on the same input and snapshot.
+//
+//	Hard/Soft Signal Boundary:
+//	- Proof number updates are soft; nodes are never marked DISPROVEN
+//
+// Thread Safety: Safe for concurrent use.
+type BeamSearch struct {
+	config *BeamSearchConfig
+}
+
+// BeamSearchConfig configures the beam search algorithm.
+type BeamSearchConfig struct {
+	// BeamWidth is the number of paths kept at each level.
+	BeamWidth int
+
+	// MaxDepth limits search depth when the input does not.
+	MaxDepth int
+
+	// MaxExpansions limits the total number of nodes expanded.
+	MaxExpansions int
+
+	// Timeout is the maximum execution time.
+	Timeout time.Duration
+
+	// ProgressInterval is how often to report progress.
+	ProgressInterval time.Duration
+}
+
+// DefaultBeamSearchConfig returns the default configuration.
+func DefaultBeamSearchConfig() *BeamSearchConfig {
+	return &BeamSearchConfig{
+		BeamWidth:        8,
+		MaxDepth:         32,
+		MaxExpansions:    10000,
+		Timeout:          5 * time.Second,
+		ProgressInterval: 1 * time.Second,
+	}
+}
+
+// NewBeamSearch creates a new beam search algorithm.
+//
+// Inputs:
+//   - config: Configuration. If nil, uses DefaultBeamSearchConfig().
+//
+// Outputs:
+//   - *BeamSearch: The new algorithm.
+func NewBeamSearch(config *BeamSearchConfig) *BeamSearch {
+	if config == nil {
+		config = DefaultBeamSearchConfig()
+	}
+	return &BeamSearch{config: config}
+}
+
+// -----------------------------------------------------------------------------
+// Input/Output Types
+// -----------------------------------------------------------------------------
+
+// BeamSearchInput is the input for BeamSearch.
+type BeamSearchInput struct {
+	// RootNodeID is the starting node for search.
+	RootNodeID string
+
+	// TargetNodes are nodes we're trying to prove/disprove. Their proof
+	// numbers are included in the output.
+	TargetNodes []string
+
+	// MaxDepth limits search depth. Zero uses the configured MaxDepth.
+	MaxDepth int
+
+	// BeamWidth overrides the configured beam width when positive.
+	BeamWidth int
+}
+
+// BeamSearchOutput is the output from BeamSearch.
+type BeamSearchOutput struct {
+	// SelectedNode is the most promising node to expand.
+	SelectedNode string
+
+	// Path is the path from root to selected node.
+	Path []string
+
+	// ProofUpdates contains updated proof numbers.
+	ProofUpdates map[string]crs.ProofNumber
+
+	// MostProvingNode is the node with smallest proof number.
+	MostProvingNode string
+
+	// Depth is the number of levels expanded.
+	Depth int
+
+	// Expanded is the number of nodes expanded.
+	Expanded int
+
+	// Pruned counts candidate paths dropped because the beam was full.
+	Pruned int
+
+	// MaxBeam is the largest beam kept at any level.
+	MaxBeam int
+
+	// Converged is true if every path ended before the depth and expansion
+	// limits.
+	Converged bool
+}
+
+// beamPath is a candidate path and the proof numbers of its last node.
+type beamPath struct {
+	nodes []string
+	pn    crs.ProofNumber
+}
+
+// last returns the path's last node.
+func (b beamPath) last() string {
+	return b.nodes[len(b.nodes)-1]
+}
+
+// -----------------------------------------------------------------------------
+// Algorithm Interface Implementation
+// -----------------------------------------------------------------------------
+
+// Name returns the algorithm name.
+func (b *BeamSearch) Name() string {
+	return "beam_search"
+}
+
+// Process executes beam search.
+//
+// Description:
+//
+//	Expands the beam level by level from the root, selects the most
+//	proving node, and backs up proof numbers over the expanded nodes
+//	(sum of children's proof numbers, minimum of their disproof numbers,
+//	as in PN-MCTS).
+//
+// Thread Safety: Safe for concurrent use.
+func (b *BeamSearch) Process(ctx context.Context, snapshot crs.Snapshot, input any) (any, crs.Delta, error) {
+	var in *BeamSearchInput
+	switch v := input.(type) {
+	case *BeamSearchInput:
+		in = v
+	case *PNMCTSInput:
+		if v != nil {
+			in = &BeamSearchInput{RootNodeID: v.RootNodeID, TargetNodes: v.TargetNodes, MaxDepth: v.MaxDepth}
+		}
+	}
+	if in == nil || in.RootNodeID == "" {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "beam_search",
+			Operation: "Process",
+			Err:       ErrInvalidInput,
+		}
+	}
+	if in.BeamWidth < 0 || in.MaxDepth < 0 {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "beam_search",
+			Operation: "Process",
+			Err:       fmt.Errorf("%w: negative beam width or depth", ErrInvalidInput),
+		}
+	}
+
+	width := b.config.BeamWidth
+	if in.BeamWidth > 0 {
+		width = in.BeamWidth
+	}
+	maxDepth := b.config.MaxDepth
+	if in.MaxDepth > 0 {
+		maxDepth = in.MaxDepth
+	}
+
+	proofIndex := snapshot.ProofIndex()
+	depIndex := snapshot.DependencyIndex()
+
+	output := &BeamSearchOutput{
+		ProofUpdates: make(map[string]crs.ProofNumber),
+		Converged:    true,
+	}
+	// Proof numbers of the root, targets and expanded nodes
+	proofNumbers := make(map[string]crs.ProofNumber)
+	lookup := func(nodeID string) crs.ProofNumber {
+		if pn, ok := proofNumbers[nodeID]; ok {
+			return pn
+		}
+		if pn, ok := proofIndex.Get(nodeID); ok {
+			return pn
+		}
+		return crs.ProofNumber{
+			Proof:     1,
+			Disproof:  1,
+			Status:    crs.ProofStatusUnknown,
+			Source:    crs.SignalSourceUnknown,
+			UpdatedAt: time.Now().UnixMilli(),
+		}
+	}
+	for _, nodeID := range append(slices.Clone(in.TargetNodes), in.RootNodeID) {
+		proofNumbers[nodeID] = lookup(nodeID)
+	}
+
+	var best *beamPath
+	consider := func(p beamPath) {
+		if best == nil || beamLess(p, *best) {
+			best = &p
+		}
+	}
+
+	beam := []beamPath{{nodes: []string{in.RootNodeID}, pn: lookup(in.RootNodeID)}}
+	var expanded []string // In expansion order, for backup
+	for depth := 0; len(beam) > 0; depth++ {
+		select {
+		case <-ctx.Done():
+			b.finish(output, best, expanded, proofNumbers, depIndex, proofIndex, lookup)
+			return output, b.createDelta(output), ctx.Err()
+		default:
+		}
+
+		if depth >= maxDepth || output.Expanded >= b.config.MaxExpansions {
+			// Out of budget: the frontier competes with finished paths
+			output.Converged = false
+			for _, p := range beam {
+				consider(p)
+			}
+			break
+		}
+
+		var candidates []beamPath
+		for _, p := range beam {
+			if output.Expanded >= b.config.MaxExpansions {
+				output.Converged = false
+				consider(p)
+				continue
+			}
+			output.Expanded++
+			expanded = append(expanded, p.last())
+
+			extended := false
+			for _, child := range depIndex.DependsOn(p.last()) {
+				if slices.Contains(p.nodes, child) {
+					continue // Cycle
+				}
+				pn := lookup(child)
+				if pn.Status == crs.ProofStatusProven || pn.Status == crs.ProofStatusDisproven {
+					continue // Solved
+				}
+				extended = true
+				candidates = append(candidates, beamPath{
+					nodes: append(slices.Clip(p.nodes), child),
+					pn:    pn,
+				})
+			}
+			if !extended {
+				consider(p) // Leaf, or every child solved
+			}
+		}
+
+		slices.SortStableFunc(candidates, func(x, y beamPath) int {
+			if beamLess(x, y) {
+				return -1
+			}
+			if beamLess(y, x) {
+				return 1
+			}
+			return 0
+		})
+		if len(candidates) > width {
+			output.Pruned += len(candidates) - width
+			candidates = candidates[:width]
+		}
+		beam = candidates
+		output.MaxBeam = max(output.MaxBeam, len(beam))
+		if len(beam) > 0 {
+			output.Depth = depth + 1
+		}
+	}
+
+	b.finish(output, best, expanded, proofNumbers, depIndex, proofIndex, lookup)
+	return output, b.createDelta(output), nil
+}
+
+// beamLess orders paths by proof number, then disproof number (higher
+// first), then shorter path, then node ID for determinism.
+func beamLess(x, y beamPath) bool {
+	if c := cmp.Compare(x.pn.Proof, y.pn.Proof); c != 0 {
+		return c < 0
+	}
+	if c := cmp.Compare(x.pn.Disproof, y.pn.Disproof); c != 0 {
+		return c > 0
+	}
+	if c := cmp.Compare(len(x.nodes), len(y.nodes)); c != 0 {
+		return c < 0
+	}
+	return x.last() < y.last()
+}
+
+// finish records the selection and backs up proof numbers.
+func (b *BeamSearch) finish(output *BeamSearchOutput, best *beamPath, expanded []string,
+	proofNumbers map[string]crs.ProofNumber, deps crs.DependencyIndexView, proofIndex crs.ProofIndexView,
+	lookup func(string) crs.ProofNumber) {
+	if best != nil {
+		output.Path = best.nodes
+		output.SelectedNode = best.last()
+		output.MostProvingNode = best.last()
+	}
+
+	// Deepest first, so children are updated before their parents
+	for i := len(expanded) - 1; i >= 0; i-- {
+		nodeID := expanded[i]
+		children := deps.DependsOn(nodeID)
+		if len(children) == 0 {
+			continue
+		}
+		var sumProof uint64
+		minDisproof := ^uint64(0)
+		for _, child := range children {
+			pn := lookup(child)
+			sumProof += pn.Proof
+			minDisproof = min(minDisproof, pn.Disproof)
+		}
+		pn := lookup(nodeID)
+		pn.Proof = sumProof
+		pn.Disproof = minDisproof
+		pn.UpdatedAt = time.Now().UnixMilli()
+		proofNumbers[nodeID] = pn
+	}
+
+	for nodeID, pn := range proofNumbers {
+		if orig, exists := proofIndex.Get(nodeID); !exists ||
+			orig.Proof != pn.Proof || orig.Disproof != pn.Disproof || orig.Status != pn.Status {
+			output.ProofUpdates[nodeID] = pn
+		}
+	}
+}
+
+// createDelta creates a proof delta from the output.
+func (b *BeamSearch) createDelta(output *BeamSearchOutput) crs.Delta {
+	if len(output.ProofUpdates) == 0 {
+		return nil
+	}
+	// Soft: beam search estimates, it does not verify
+	return crs.NewProofDelta(crs.SignalSourceSoft, output.ProofUpdates)
+}
+
+// Timeout returns the maximum execution time.
+func (b *BeamSearch) Timeout() time.Duration {
+	return b.config.Timeout
+}
+
+// InputType returns the expected input type.
+func (b *BeamSearch) InputType() reflect.Type {
+	return reflect.TypeOf(&BeamSearchInput{})
+}
+
+// OutputType returns the output type.
+func (b *BeamSearch) OutputType() reflect.Type {
+	return reflect.TypeOf(&BeamSearchOutput{})
+}
+
+// ProgressInterval returns how often to report progress.
+func (b *BeamSearch) ProgressInterval() time.Duration {
+	return b.config.ProgressInterval
+}
+
+// SupportsPartialResults returns true (the best path so far is returned
+// on cancellation).
+func (b *BeamSearch) SupportsPartialResults() bool {
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Evaluable Implementation
+// -----------------------------------------------------------------------------
+
+// Properties returns the correctness properties.
+func (b *BeamSearch) Properties() []eval.Property {
+	return []eval.Property{
+		{
+			Name:        "beam_width_bound",
+			Description: "No level keeps more paths than the beam width",
+			Check: func(input, output any) error {
+				out, ok := output.(*BeamSearchOutput)
+				if !ok {
+					return nil
+				}
+				width := b.config.BeamWidth
+				if in, ok := input.(*BeamSearchInput); ok && in.BeamWidth > 0 {
+					width = in.BeamWidth
+				}
+				if out.MaxBeam > width {
+					return b.propertyError("beam_width_bound", fmt.Errorf("beam of %d exceeds width %d", out.MaxBeam, width))
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "path_from_root",
+			Description: "The path starts at the root and ends at the selected node",
+			Check: func(input, output any) error {
+				out, ok := output.(*BeamSearchOutput)
+				if !ok || out.SelectedNode == "" {
+					return nil
+				}
+				root := ""
+				switch in := input.(type) {
+				case *BeamSearchInput:
+					root = in.RootNodeID
+				case *PNMCTSInput:
+					root = in.RootNodeID
+				}
+				if len(out.Path) == 0 || out.Path[0] != root || out.Path[len(out.Path)-1] != out.SelectedNode {
+					return b.propertyError("path_from_root", fmt.Errorf("path %v does not lead from %s to %s", out.Path, root, out.SelectedNode))
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "no_soft_disproven",
+			Description: "Beam search never marks nodes as DISPROVEN",
+			Check: func(input, output any) error {
+				out, ok := output.(*BeamSearchOutput)
+				if !ok {
+					return nil
+				}
+				for nodeID, pn := range out.ProofUpdates {
+					if pn.Status == crs.ProofStatusDisproven {
+						return b.propertyError("no_soft_disproven", fmt.Errorf("%w: %s", eval.ErrSoftSignalViolation, nodeID))
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// propertyError wraps a property violation.
+func (b *BeamSearch) propertyError(property string, err error) error {
+	return &AlgorithmError{
+		Algorithm: "beam_search",
+		Operation: "Property." + property,
+		Err:       err,
+	}
+}
+
+// Metrics returns the metrics this algorithm exposes.
+func (b *BeamSearch) Metrics() []eval.MetricDefinition {
+	return []eval.MetricDefinition{
+		{
+			Name:        "beam_search_expansions_total",
+			Type:        eval.MetricCounter,
+			Description: "Total nodes expanded",
+		},
+		{
+			Name:        "beam_search_pruned_total",
+			Type:        eval.MetricCounter,
+			Description: "Total candidate paths dropped from a full beam",
+		},
+		{
+			Name:        "beam_search_depth",
+			Type:        eval.MetricHistogram,
+			Description: "Levels expanded per search",
+		},
+	}
+}
+
+// HealthCheck verifies the algorithm is functioning.
+func (b *BeamSearch) HealthCheck(ctx context.Context) error {
+	if b.config == nil || b.config.BeamWidth <= 0 || b.config.MaxDepth <= 0 {
+		return &AlgorithmError{
+			Algorithm: "beam_search",
+			Operation: "HealthCheck",
+			Err:       ErrInvalidConfig,
+		}
+	}
+	return nil
+}
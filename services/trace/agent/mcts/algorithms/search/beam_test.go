@@ -0,0 +1,134 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// beamTestSnapshot builds:
+//
+//	root ─┬─ a (pn 5) ─── a1 (pn 1)
+//	      ├─ b (pn 2) ─┬─ b1 (pn 9)
+//	      │            └─ b2 (proven)
+//	      └─ c (pn 3) ─── c1 (pn 4)
+func beamTestSnapshot() *mockSnapshot {
+	now := time.Now().UnixMilli()
+	pn := func(proof uint64) crs.ProofNumber {
+		return crs.ProofNumber{Proof: proof, Disproof: 1, UpdatedAt: now}
+	}
+	return &mockSnapshot{
+		generation: 1,
+		createdAt:  now,
+		proof: &mockProofIndexView{data: map[string]crs.ProofNumber{
+			"a": pn(5), "a1": pn(1),
+			"b": pn(2), "b1": pn(9), "b2": {Proof: 0, Disproof: 1 << 32, Status: crs.ProofStatusProven},
+			"c": pn(3), "c1": pn(4),
+		}},
+		dependency: &mockDependencyIndexView{edges: map[string][]string{
+			"root": {"a", "b", "c"},
+			"a":    {"a1"},
+			"b":    {"b1", "b2"},
+			"c":    {"c1"},
+		}},
+	}
+}
+
+func TestBeamSearch_Process(t *testing.T) {
+	ctx := context.Background()
+	snapshot := beamTestSnapshot()
+
+	run := func(t *testing.T, algo *BeamSearch, input any) *BeamSearchOutput {
+		t.Helper()
+		result, _, err := algo.Process(ctx, snapshot, input)
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		for _, prop := range algo.Properties() {
+			if err := prop.Check(input, result); err != nil {
+				t.Errorf("property %s: %v", prop.Name, err)
+			}
+		}
+		return result.(*BeamSearchOutput)
+	}
+
+	t.Run("wide beam finds best leaf", func(t *testing.T) {
+		out := run(t, NewBeamSearch(nil), &BeamSearchInput{RootNodeID: "root"})
+		if out.SelectedNode != "a1" || len(out.Path) != 3 || out.Path[1] != "a" {
+			t.Errorf("selected %s via %v, want a1 via a", out.SelectedNode, out.Path)
+		}
+		if !out.Converged || out.Pruned != 0 || out.Depth != 2 {
+			t.Errorf("converged %v, pruned %d, depth %d", out.Converged, out.Pruned, out.Depth)
+		}
+		// Backed up: a=1, b=9+0, c=4
+		if got := out.ProofUpdates["root"].Proof; got != 14 {
+			t.Errorf("root proof = %d, want 14", got)
+		}
+	})
+
+	t.Run("narrow beam prunes", func(t *testing.T) {
+		// Width 1 keeps only b (pn 2), whose only unsolved child is b1
+		out := run(t, NewBeamSearch(nil), &BeamSearchInput{RootNodeID: "root", BeamWidth: 1})
+		if out.SelectedNode != "b1" || out.Pruned != 2 || out.MaxBeam != 1 {
+			t.Errorf("selected %s, pruned %d, max beam %d", out.SelectedNode, out.Pruned, out.MaxBeam)
+		}
+	})
+
+	t.Run("depth limit stops at frontier", func(t *testing.T) {
+		out := run(t, NewBeamSearch(nil), &PNMCTSInput{RootNodeID: "root", MaxDepth: 1})
+		if out.SelectedNode != "b" || out.Converged {
+			t.Errorf("selected %s, converged %v; want b, false", out.SelectedNode, out.Converged)
+		}
+	})
+
+	t.Run("no soft disproven", func(t *testing.T) {
+		out := run(t, NewBeamSearch(nil), &BeamSearchInput{RootNodeID: "root"})
+		for nodeID, pn := range out.ProofUpdates {
+			if pn.Status == crs.ProofStatusDisproven {
+				t.Errorf("%s marked DISPROVEN", nodeID)
+			}
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, input := range []any{nil, &BeamSearchInput{}, &BeamSearchInput{RootNodeID: "root", BeamWidth: -1}, "root"} {
+			if _, _, err := NewBeamSearch(nil).Process(ctx, snapshot, input); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("input %v: err = %v, want ErrInvalidInput", input, err)
+			}
+		}
+	})
+}
+
+func TestBeamSearch_SameInputAsPNMCTS(t *testing.T) {
+	ctx := context.Background()
+	snapshot := beamTestSnapshot()
+	input := &PNMCTSInput{RootNodeID: "root"}
+
+	pnOut, _, err := NewPNMCTS(nil).Process(ctx, snapshot, input)
+	if err != nil {
+		t.Fatalf("PN-MCTS: %v", err)
+	}
+	beamOut, delta, err := NewBeamSearch(nil).Process(ctx, snapshot, input)
+	if err != nil {
+		t.Fatalf("BeamSearch: %v", err)
+	}
+	if pnOut.(*PNMCTSOutput).Path[0] != beamOut.(*BeamSearchOutput).Path[0] {
+		t.Error("paths do not share the root")
+	}
+	if delta == nil || delta.Source() != crs.SignalSourceSoft {
+		t.Errorf("delta = %v, want soft proof delta", delta)
+	}
+}
@@ -0,0 +1,867 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+// -----------------------------------------------------------------------------
+// DPLL Algorithm (SAT Solving)
+// -----------------------------------------------------------------------------
+
+// DPLL decides satisfiability of a full clause set.
+//
+// Description:
+//
+//	DPLL (Davis-Putnam-Logemann-Loveland) searches for an assignment that
+//	satisfies every clause, or proves none exists. Where UnitPropagation
+//	only derives moves forced by single constraints, DPLL considers all
+//	clauses together, so TMS and CDCL can hand it a whole clause set.
+//
+//	The clause set is the CRS's learned clauses, the active mutual
+//	exclusion, implication and ordering constraints (in CNF), and any
+//	clauses in the input.
+//
+//	Key Concepts:
+//	- Unit Propagation: A clause with one unassigned literal forces it
+//	- Pure Literals: A variable with one polarity is set to satisfy it
+//	- Branching: Decide the most frequent unassigned variable, backtrack
+//	  chronologically on conflict
+//	- Probing: A variable whose opposite value is unsatisfiable is forced
+//
+//	Hard/Soft Signal Boundary:
+//	- Results are hard only if every clause came from a hard signal
+//	- Only hard results mark nodes DISPROVEN or produce learned clauses
+//
+// Thread Safety: Safe for concurrent use.
+type DPLL struct {
+	config *DPLLConfig
+}
+
+// DPLLConfig configures the DPLL algorithm.
+type DPLLConfig struct {
+	// MaxDecisions limits branching decisions per solve. A solve that
+	// reaches it reports DPLLUnknown.
+	MaxDecisions int
+
+	// Timeout is the maximum execution time.
+	Timeout time.Duration
+
+	// ProgressInterval is how often to report progress.
+	ProgressInterval time.Duration
+}
+
+// DefaultDPLLConfig returns the default configuration.
+func DefaultDPLLConfig() *DPLLConfig {
+	return &DPLLConfig{
+		MaxDecisions:     100000,
+		Timeout:          5 * time.Second,
+		ProgressInterval: 1 * time.Second,
+	}
+}
+
+// NewDPLL creates a new DPLL algorithm.
+//
+// Inputs:
+//   - config: Configuration. If nil, uses DefaultDPLLConfig().
+//
+// Outputs:
+//   - *DPLL: The new algorithm.
+func NewDPLL(config *DPLLConfig) *DPLL {
+	if config == nil {
+		config = DefaultDPLLConfig()
+	}
+	return &DPLL{config: config}
+}
+
+// -----------------------------------------------------------------------------
+// Input/Output Types
+// -----------------------------------------------------------------------------
+
+// DPLLInput is the input for DPLL.
+type DPLLInput struct {
+	// Assumptions fix variables before solving (true = selected).
+	Assumptions map[string]bool
+
+	// Clauses are solved together with the snapshot's clauses, e.g. a
+	// TMS's justifications (see constraints.TMSClauses) or CDCL clauses
+	// (see CDCLClause.Clause).
+	Clauses []crs.Clause
+
+	// Probe lists variables to test for forced values. Each probe costs up
+	// to one extra solve.
+	Probe []string
+
+	// IgnoreConstraints leaves the snapshot's constraints out of the clause
+	// set. Learned clauses are always included.
+	IgnoreConstraints bool
+}
+
+// DPLLStatus is the result of a solve.
+type DPLLStatus string
+
+const (
+	// DPLLSatisfiable means an assignment satisfying every clause exists.
+	DPLLSatisfiable DPLLStatus = "satisfiable"
+
+	// DPLLUnsatisfiable means no assignment satisfies every clause.
+	DPLLUnsatisfiable DPLLStatus = "unsatisfiable"
+
+	// DPLLUnknown means the decision budget or context ran out.
+	DPLLUnknown DPLLStatus = "unknown"
+)
+
+// DPLLOutput is the output from DPLL.
+type DPLLOutput struct {
+	// Status is the satisfiability of the clause set under the
+	// assumptions.
+	Status DPLLStatus
+
+	// Model assigns every variable when Status is DPLLSatisfiable.
+	Model map[string]bool
+
+	// Forced holds the probed variables that take the same value in every
+	// model.
+	Forced map[string]bool
+
+	// LearnedClause forbids the assumptions when they are unsatisfiable
+	// together and the result is hard. Callers may add it to the CRS.
+	LearnedClause *crs.Clause
+
+	// Hard is true if every clause came from a hard signal.
+	Hard bool
+
+	// Variables and Clauses are the size of the solved problem.
+	Variables int
+	Clauses   int
+
+	// Decisions, Propagations and Conflicts count solver work across all
+	// solves, including probes.
+	Decisions    int
+	Propagations int
+	Conflicts    int
+
+	// Reason explains the output.
+	Reason string
+}
+
+// CDCLClause converts to a crs.Clause for DPLL: a positive literal is a
+// selected node.
+func (c CDCLClause) Clause() crs.Clause {
+	lits := make([]crs.Literal, len(c.Literals))
+	for i, l := range c.Literals {
+		lits[i] = crs.Literal{Variable: l.NodeID, Negated: !l.Positive}
+	}
+	return crs.Clause{ID: c.ID, Literals: lits, Source: c.Source}
+}
+
+// -----------------------------------------------------------------------------
+// Algorithm Interface Implementation
+// -----------------------------------------------------------------------------
+
+// Name returns the algorithm name.
+func (d *DPLL) Name() string {
+	return "dpll"
+}
+
+// Process decides satisfiability of the clause set.
+//
+// Description:
+//
+//	Builds the clause set from the snapshot and input, solves it under
+//	the assumptions, and probes the requested variables. For a hard
+//	result, probed nodes forced to be deselected, and the single assumed
+//	node of an unsatisfiable single-assumption query, are marked DISPROVEN
+//	in a proof delta.
+//
+// Thread Safety: Safe for concurrent use.
+func (d *DPLL) Process(ctx context.Context, snapshot crs.Snapshot, input any) (any, crs.Delta, error) {
+	in, ok := input.(*DPLLInput)
+	if !ok || in == nil {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "dpll",
+			Operation: "Process",
+			Err:       ErrInvalidInput,
+		}
+	}
+
+	p, err := d.buildProblem(snapshot, in)
+	if err != nil {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "dpll",
+			Operation: "Process",
+			Err:       err,
+		}
+	}
+
+	output := &DPLLOutput{
+		Forced:    make(map[string]bool),
+		Hard:      p.hard,
+		Variables: len(p.names),
+		Clauses:   len(p.clauses),
+	}
+
+	assumptions := make([]int, 0, len(in.Assumptions))
+	for _, name := range slices.Sorted(maps.Keys(in.Assumptions)) {
+		assumptions = append(assumptions, p.literal(name, in.Assumptions[name]))
+	}
+
+	status, model := d.solve(ctx, p, assumptions, output)
+	output.Status = status
+	switch status {
+	case DPLLUnknown:
+		output.Reason = "decision budget or time exhausted"
+		if err := ctx.Err(); err != nil {
+			return output, nil, err
+		}
+		return output, nil, nil
+	case DPLLUnsatisfiable:
+		output.Reason = "clauses are unsatisfiable under the assumptions"
+		if len(assumptions) == 0 {
+			output.Reason = "clauses are unsatisfiable"
+		} else if p.hard {
+			output.LearnedClause = p.blockingClause(assumptions)
+		}
+		return output, d.createDelta(snapshot, p, in, output), nil
+	}
+
+	output.Model = make(map[string]bool, len(p.names))
+	for v := 1; v < len(p.names); v++ {
+		output.Model[p.names[v]] = model[v] > 0
+	}
+	output.Reason = "satisfiable"
+
+	// Probe: a variable whose value in the model cannot be flipped is
+	// forced to that value.
+	for _, name := range in.Probe {
+		if _, assumed := in.Assumptions[name]; assumed {
+			output.Forced[name] = in.Assumptions[name]
+			continue
+		}
+		v, ok := p.index[name]
+		if !ok {
+			continue // Unconstrained: never forced
+		}
+		value := model[v] > 0
+		st, _ := d.solve(ctx, p, append(slices.Clone(assumptions), p.literal(name, !value)), output)
+		if st == DPLLUnsatisfiable {
+			output.Forced[name] = value
+		}
+		if err := ctx.Err(); err != nil {
+			return output, d.createDelta(snapshot, p, in, output), err
+		}
+	}
+
+	return output, d.createDelta(snapshot, p, in, output), nil
+}
+
+// createDelta marks nodes that cannot be selected as DISPROVEN.
+func (d *DPLL) createDelta(snapshot crs.Snapshot, p *dpllProblem, in *DPLLInput, output *DPLLOutput) crs.Delta {
+	if !output.Hard {
+		return nil // Soft results never disprove (hard/soft boundary)
+	}
+
+	var impossible []string
+	for name, value := range output.Forced {
+		if !value {
+			impossible = append(impossible, name)
+		}
+	}
+	if output.Status == DPLLUnsatisfiable && len(in.Assumptions) == 1 {
+		for name, value := range in.Assumptions {
+			if value {
+				impossible = append(impossible, name)
+			}
+		}
+	}
+
+	proofs := snapshot.ProofIndex()
+	updates := make(map[string]crs.ProofNumber)
+	for _, name := range impossible {
+		pn, exists := proofs.Get(name)
+		if !exists && !p.nodes[name] {
+			continue // Not a CRS node (e.g. a "tool:" variable)
+		}
+		if !exists {
+			pn = crs.ProofNumber{Proof: 1, Disproof: 1}
+		}
+		pn.Status = crs.ProofStatusDisproven
+		pn.Source = crs.SignalSourceHard
+		pn.UpdatedAt = time.Now().UnixMilli()
+		updates[name] = pn
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return crs.NewProofDelta(crs.SignalSourceHard, updates)
+}
+
+// -----------------------------------------------------------------------------
+// Problem Encoding
+// -----------------------------------------------------------------------------
+
+// dpllProblem is a clause set over integer variables 1..n. A literal is
+// +v or -v.
+type dpllProblem struct {
+	names   []string // names[v] is variable v's name; names[0] unused
+	index   map[string]int
+	clauses [][]int
+	occurs  [][]int // occurs[v] lists clauses containing v or -v
+	nodes   map[string]bool
+	hard    bool
+	empty   bool // some clause has no literals
+}
+
+// buildProblem collects and encodes the clause set.
+func (d *DPLL) buildProblem(snapshot crs.Snapshot, in *DPLLInput) (*dpllProblem, error) {
+	p := &dpllProblem{
+		names: []string{""},
+		index: make(map[string]int),
+		nodes: make(map[string]bool),
+		hard:  true,
+	}
+
+	var clauses []crs.Clause
+	if ci := snapshot.ConstraintIndex(); ci != nil {
+		learned := ci.AllClauses()
+		for _, id := range slices.Sorted(maps.Keys(learned)) {
+			clauses = append(clauses, *learned[id])
+		}
+		if !in.IgnoreConstraints {
+			all := ci.All()
+			for _, id := range slices.Sorted(maps.Keys(all)) {
+				c := all[id]
+				cnf := constraintClauses(c)
+				if len(cnf) == 0 {
+					continue
+				}
+				for _, n := range c.Nodes {
+					p.nodes[n] = true
+				}
+				clauses = append(clauses, cnf...)
+			}
+		}
+	}
+	clauses = append(clauses, in.Clauses...)
+
+	for _, c := range clauses {
+		if !c.Source.IsHard() {
+			p.hard = false
+		}
+		encoded := make([]int, 0, len(c.Literals))
+		for _, lit := range c.Literals {
+			if lit.Variable == "" {
+				return nil, fmt.Errorf("%w: clause %q has an empty variable", ErrInvalidInput, c.ID)
+			}
+			l := p.literal(lit.Variable, !lit.Negated)
+			if slices.Contains(encoded, -l) {
+				encoded = nil // Tautology: always satisfied
+				break
+			}
+			if !slices.Contains(encoded, l) {
+				encoded = append(encoded, l)
+			}
+		}
+		if encoded == nil && len(c.Literals) > 0 {
+			continue
+		}
+		if len(encoded) == 0 {
+			p.empty = true
+		}
+		p.clauses = append(p.clauses, encoded)
+	}
+
+	for name := range in.Assumptions {
+		if name == "" {
+			return nil, fmt.Errorf("%w: empty assumption variable", ErrInvalidInput)
+		}
+		p.variable(name)
+	}
+
+	p.occurs = make([][]int, len(p.names))
+	for ci, c := range p.clauses {
+		for _, l := range c {
+			p.occurs[abs(l)] = append(p.occurs[abs(l)], ci)
+		}
+	}
+	return p, nil
+}
+
+// constraintClauses converts an active constraint to CNF.
+func constraintClauses(c crs.Constraint) []crs.Clause {
+	if !c.Active {
+		return nil
+	}
+	clause := func(suffix string, lits ...crs.Literal) crs.Clause {
+		return crs.Clause{ID: c.ID + suffix, Literals: lits, Source: c.Source}
+	}
+	var out []crs.Clause
+	switch c.Type {
+	case crs.ConstraintTypeMutualExclusion:
+		// At most one node: ¬a ∨ ¬b for every pair
+		for i := 0; i < len(c.Nodes); i++ {
+			for j := i + 1; j < len(c.Nodes); j++ {
+				out = append(out, clause(fmt.Sprintf("#%d,%d", i, j),
+					crs.Literal{Variable: c.Nodes[i], Negated: true},
+					crs.Literal{Variable: c.Nodes[j], Negated: true}))
+			}
+		}
+	case crs.ConstraintTypeImplication:
+		// Nodes[0] → Nodes[1]: ¬a ∨ b
+		if len(c.Nodes) >= 2 {
+			out = append(out, clause("",
+				crs.Literal{Variable: c.Nodes[0], Negated: true},
+				crs.Literal{Variable: c.Nodes[1]}))
+		}
+	case crs.ConstraintTypeOrdering:
+		// Selecting Nodes[i] requires Nodes[i-1]; chained, this requires
+		// every earlier node
+		for i := 1; i < len(c.Nodes); i++ {
+			out = append(out, clause(fmt.Sprintf("#%d", i),
+				crs.Literal{Variable: c.Nodes[i], Negated: true},
+				crs.Literal{Variable: c.Nodes[i-1]}))
+		}
+	}
+	return out
+}
+
+// variable returns the index of a variable, adding it if new.
+func (p *dpllProblem) variable(name string) int {
+	if v, ok := p.index[name]; ok {
+		return v
+	}
+	v := len(p.names)
+	p.names = append(p.names, name)
+	p.index[name] = v
+	return v
+}
+
+// literal returns the literal for name = value.
+func (p *dpllProblem) literal(name string, value bool) int {
+	v := p.variable(name)
+	if !value {
+		return -v
+	}
+	return v
+}
+
+// blockingClause returns the hard clause forbidding all assumptions
+// together. Its ID is derived from its literals, so relearning it is
+// recognized as a duplicate.
+func (p *dpllProblem) blockingClause(assumptions []int) *crs.Clause {
+	lits := make([]crs.Literal, len(assumptions))
+	keys := make([]string, len(assumptions))
+	for i, l := range assumptions {
+		lits[i] = crs.Literal{Variable: p.names[abs(l)], Negated: l > 0}
+		keys[i] = lits[i].String()
+	}
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\x00")))
+	return &crs.Clause{
+		ID:       "dpll_" + hex.EncodeToString(sum[:8]),
+		Literals: lits,
+		Source:   crs.SignalSourceHard,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Solver
+// -----------------------------------------------------------------------------
+
+// dpllState is the assignment during one solve.
+type dpllState struct {
+	p         *dpllProblem
+	value     []int8 // value[v]: 1 true, -1 false, 0 unassigned
+	trail     []int  // assigned literals in order
+	levels    []int  // trail length at each decision
+	decisions []int  // decision literal at each level
+	flipped   []bool // whether the level's decision was already flipped
+}
+
+// solve runs DPLL under the assumptions. The model is valid when the
+// status is DPLLSatisfiable.
+func (d *DPLL) solve(ctx context.Context, p *dpllProblem, assumptions []int, output *DPLLOutput) (DPLLStatus, []int8) {
+	if p.empty {
+		return DPLLUnsatisfiable, nil
+	}
+	s := &dpllState{p: p, value: make([]int8, len(p.names))}
+
+	// Level 0: assumptions and initial units
+	for _, l := range assumptions {
+		if !s.assign(l) {
+			return DPLLUnsatisfiable, nil
+		}
+	}
+	for _, c := range p.clauses {
+		if len(c) == 1 && !s.assign(c[0]) {
+			return DPLLUnsatisfiable, nil
+		}
+	}
+	if !s.propagate(0, output) {
+		return DPLLUnsatisfiable, nil
+	}
+	s.assignPureLiterals()
+	if !s.propagate(0, output) {
+		return DPLLUnsatisfiable, nil
+	}
+
+	decisions := 0
+	for {
+		if decisions%1024 == 0 && ctx.Err() != nil {
+			return DPLLUnknown, nil
+		}
+
+		v, positive := s.branchVariable()
+		if v == 0 {
+			return DPLLSatisfiable, s.value
+		}
+		if decisions >= d.config.MaxDecisions {
+			return DPLLUnknown, nil
+		}
+		decisions++
+		output.Decisions++
+
+		lit := v
+		if !positive {
+			lit = -v
+		}
+		start := len(s.trail)
+		s.levels = append(s.levels, start)
+		s.decisions = append(s.decisions, lit)
+		s.flipped = append(s.flipped, false)
+		s.assign(lit)
+
+		for !s.propagate(start, output) {
+			output.Conflicts++
+			var ok bool
+			if start, ok = s.backtrack(); !ok {
+				return DPLLUnsatisfiable, nil
+			}
+		}
+	}
+}
+
+// assign sets a literal true. It returns false if the literal is
+// already false.
+func (s *dpllState) assign(l int) bool {
+	v := abs(l)
+	want := int8(1)
+	if l < 0 {
+		want = -1
+	}
+	switch s.value[v] {
+	case want:
+		return true
+	case -want:
+		return false
+	}
+	s.value[v] = want
+	s.trail = append(s.trail, l)
+	return true
+}
+
+// litValue returns 1, -1 or 0 for a true, false or unassigned literal.
+func (s *dpllState) litValue(l int) int8 {
+	if l < 0 {
+		return -s.value[-l]
+	}
+	return s.value[l]
+}
+
+// propagate applies unit propagation to the literals assigned since trail
+// position from. It returns false on conflict.
+func (s *dpllState) propagate(from int, output *DPLLOutput) bool {
+	for i := from; i < len(s.trail); i++ {
+		for _, ci := range s.p.occurs[abs(s.trail[i])] {
+			var unit, unassigned int
+			satisfied := false
+			for _, l := range s.p.clauses[ci] {
+				switch s.litValue(l) {
+				case 1:
+					satisfied = true
+				case 0:
+					unassigned++
+					unit = l
+				}
+				if satisfied {
+					break
+				}
+			}
+			switch {
+			case satisfied:
+			case unassigned == 0:
+				return false
+			case unassigned == 1:
+				s.assign(unit)
+				output.Propagations++
+			}
+		}
+	}
+	return true
+}
+
+// backtrack undoes assignments up to the deepest decision not yet
+// flipped and flips it. It returns the trail position to propagate from,
+// or false if every decision has been flipped (unsatisfiable).
+func (s *dpllState) backtrack() (int, bool) {
+	for len(s.levels) > 0 {
+		top := len(s.levels) - 1
+		start := s.levels[top]
+		for _, l := range s.trail[start:] {
+			s.value[abs(l)] = 0
+		}
+		s.trail = s.trail[:start]
+
+		if !s.flipped[top] {
+			s.flipped[top] = true
+			s.decisions[top] = -s.decisions[top]
+			s.assign(s.decisions[top])
+			return start, true
+		}
+		s.levels = s.levels[:top]
+		s.decisions = s.decisions[:top]
+		s.flipped = s.flipped[:top]
+	}
+	return 0, false
+}
+
+// assignPureLiterals sets variables that occur with one polarity in the
+// unsatisfied clauses so those clauses are satisfied.
+func (s *dpllState) assignPureLiterals() {
+	polarity := make([]int8, len(s.value)) // 1 pos, -1 neg, 2 both
+	for _, c := range s.p.clauses {
+		if s.clauseSatisfied(c) {
+			continue
+		}
+		for _, l := range c {
+			v := abs(l)
+			if s.value[v] != 0 {
+				continue
+			}
+			sign := int8(1)
+			if l < 0 {
+				sign = -1
+			}
+			switch polarity[v] {
+			case 0:
+				polarity[v] = sign
+			case -sign:
+				polarity[v] = 2
+			}
+		}
+	}
+	for v, pol := range polarity {
+		if pol == 1 || pol == -1 {
+			s.assign(v * int(pol))
+		}
+	}
+}
+
+// branchVariable picks the unassigned variable occurring most often in
+// unsatisfied clauses, and the polarity it occurs with most. It returns 0
+// when every clause is satisfied.
+func (s *dpllState) branchVariable() (int, bool) {
+	pos := make([]int, len(s.value))
+	neg := make([]int, len(s.value))
+	best, bestCount := 0, 0
+	for _, c := range s.p.clauses {
+		if s.clauseSatisfied(c) {
+			continue
+		}
+		for _, l := range c {
+			v := abs(l)
+			if s.value[v] != 0 {
+				continue
+			}
+			if l > 0 {
+				pos[v]++
+			} else {
+				neg[v]++
+			}
+			if n := pos[v] + neg[v]; n > bestCount || (n == bestCount && v < best) {
+				best, bestCount = v, n
+			}
+		}
+	}
+	if best == 0 {
+		// Every clause is satisfied; fix the rest for a total model
+		for v := 1; v < len(s.value); v++ {
+			if s.value[v] == 0 {
+				s.value[v] = -1
+			}
+		}
+		return 0, false
+	}
+	return best, pos[best] >= neg[best]
+}
+
+// clauseSatisfied reports whether some literal of c is true.
+func (s *dpllState) clauseSatisfied(c []int) bool {
+	for _, l := range c {
+		if s.litValue(l) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Timeout returns the maximum execution time.
+func (d *DPLL) Timeout() time.Duration {
+	return d.config.Timeout
+}
+
+// InputType returns the expected input type.
+func (d *DPLL) InputType() reflect.Type {
+	return reflect.TypeOf(&DPLLInput{})
+}
+
+// OutputType returns the output type.
+func (d *DPLL) OutputType() reflect.Type {
+	return reflect.TypeOf(&DPLLOutput{})
+}
+
+// ProgressInterval returns how often to report progress.
+func (d *DPLL) ProgressInterval() time.Duration {
+	return d.config.ProgressInterval
+}
+
+// SupportsPartialResults returns true (probes completed before
+// cancellation are kept).
+func (d *DPLL) SupportsPartialResults() bool {
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Evaluable Implementation
+// -----------------------------------------------------------------------------
+
+// Properties returns the correctness properties.
+func (d *DPLL) Properties() []eval.Property {
+	return []eval.Property{
+		{
+			Name:        "model_satisfies_input",
+			Description: "A satisfying model agrees with the assumptions and satisfies the input clauses",
+			Check: func(input, output any) error {
+				in, inOk := input.(*DPLLInput)
+				out, outOk := output.(*DPLLOutput)
+				if !inOk || !outOk || out.Status != DPLLSatisfiable {
+					return nil
+				}
+				for name, value := range in.Assumptions {
+					if out.Model[name] != value {
+						return d.propertyError("model_satisfies_input", fmt.Errorf("model sets assumption %s to %v", name, !value))
+					}
+				}
+				for _, c := range in.Clauses {
+					if !c.IsSatisfied(out.Model) {
+						return d.propertyError("model_satisfies_input", fmt.Errorf("model violates clause %s", c.ID))
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "forced_agrees_with_model",
+			Description: "A forced variable has its forced value in the model",
+			Check: func(input, output any) error {
+				out, ok := output.(*DPLLOutput)
+				if !ok || out.Model == nil {
+					return nil
+				}
+				for name, value := range out.Forced {
+					if model, ok := out.Model[name]; ok && model != value {
+						return d.propertyError("forced_agrees_with_model", fmt.Errorf("%s forced %v but model has %v", name, value, model))
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "no_soft_learned_clauses",
+			Description: "DPLL only learns clauses from hard clause sets (Rule #2)",
+			Check: func(input, output any) error {
+				out, ok := output.(*DPLLOutput)
+				if !ok || out.LearnedClause == nil {
+					return nil
+				}
+				if !out.Hard || !out.LearnedClause.Source.IsHard() {
+					return d.propertyError("no_soft_learned_clauses", eval.ErrSoftSignalViolation)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// propertyError wraps a property violation.
+func (d *DPLL) propertyError(property string, err error) error {
+	return &AlgorithmError{
+		Algorithm: "dpll",
+		Operation: "Property." + property,
+		Err:       err,
+	}
+}
+
+// Metrics returns the metrics this algorithm exposes.
+func (d *DPLL) Metrics() []eval.MetricDefinition {
+	return []eval.MetricDefinition{
+		{
+			Name:        "dpll_solves_total",
+			Type:        eval.MetricCounter,
+			Description: "Total solves by status",
+		},
+		{
+			Name:        "dpll_decisions_total",
+			Type:        eval.MetricCounter,
+			Description: "Total branching decisions",
+		},
+		{
+			Name:        "dpll_conflicts_total",
+			Type:        eval.MetricCounter,
+			Description: "Total conflicts (backtracks)",
+		},
+		{
+			Name:        "dpll_clause_count",
+			Type:        eval.MetricHistogram,
+			Description: "Clauses per solved problem",
+		},
+	}
+}
+
+// HealthCheck verifies the algorithm is functioning.
+func (d *DPLL) HealthCheck(ctx context.Context) error {
+	if d.config == nil || d.config.MaxDecisions <= 0 {
+		return &AlgorithmError{
+			Algorithm: "dpll",
+			Operation: "HealthCheck",
+			Err:       ErrInvalidConfig,
+		}
+	}
+	return nil
+}
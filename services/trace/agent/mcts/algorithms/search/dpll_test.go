@@ -0,0 +1,235 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+func lit(v string, negated bool) crs.Literal {
+	return crs.Literal{Variable: v, Negated: negated}
+}
+
+func hardClause(id string, lits ...crs.Literal) crs.Clause {
+	return crs.Clause{ID: id, Literals: lits, Source: crs.SignalSourceHard}
+}
+
+func setupDPLLTestCRS(t *testing.T) crs.CRS {
+	t.Helper()
+	c := crs.New(nil)
+	ctx := context.Background()
+
+	proofDelta := crs.NewProofDelta(crs.SignalSourceHard, map[string]crs.ProofNumber{
+		"node1": {Proof: 10, Disproof: 5, Status: crs.ProofStatusUnknown},
+		"node2": {Proof: 10, Disproof: 5, Status: crs.ProofStatusUnknown},
+		"node3": {Proof: 10, Disproof: 5, Status: crs.ProofStatusUnknown},
+		"node4": {Proof: 10, Disproof: 5, Status: crs.ProofStatusUnknown},
+	})
+	if _, err := c.Apply(ctx, proofDelta); err != nil {
+		t.Fatalf("failed to apply proof delta: %v", err)
+	}
+
+	// At most one of node1..node3, node1 → node4, node3 → node2
+	constraintDelta := crs.NewConstraintDelta(crs.SignalSourceHard)
+	constraintDelta.Add = []crs.Constraint{
+		{ID: "mutex1", Type: crs.ConstraintTypeMutualExclusion, Nodes: []string{"node1", "node2", "node3"}, Active: true, Source: crs.SignalSourceHard},
+		{ID: "impl1", Type: crs.ConstraintTypeImplication, Nodes: []string{"node1", "node4"}, Active: true, Source: crs.SignalSourceHard},
+		{ID: "order1", Type: crs.ConstraintTypeOrdering, Nodes: []string{"node2", "node3"}, Active: true, Source: crs.SignalSourceHard},
+		{ID: "inactive", Type: crs.ConstraintTypeImplication, Nodes: []string{"node4", "node2"}},
+	}
+	if _, err := c.Apply(ctx, constraintDelta); err != nil {
+		t.Fatalf("failed to apply constraint delta: %v", err)
+	}
+	return c
+}
+
+func TestDPLL_Process(t *testing.T) {
+	ctx := context.Background()
+	c := setupDPLLTestCRS(t)
+	algo := NewDPLL(nil)
+
+	t.Run("satisfiable model respects constraints", func(t *testing.T) {
+		result, delta, err := algo.Process(ctx, c.Snapshot(), &DPLLInput{
+			Assumptions: map[string]bool{"node1": true},
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*DPLLOutput)
+		if out.Status != DPLLSatisfiable {
+			t.Fatalf("status = %s, want satisfiable", out.Status)
+		}
+		if !out.Model["node4"] || out.Model["node2"] || out.Model["node3"] {
+			t.Errorf("model = %v, want node4 selected and node2/node3 not", out.Model)
+		}
+		if delta != nil {
+			t.Errorf("expected no delta without probes, got %v", delta)
+		}
+	})
+
+	t.Run("unsatisfiable assumption is disproven", func(t *testing.T) {
+		// node3 requires node2, which excludes node3
+		result, delta, err := algo.Process(ctx, c.Snapshot(), &DPLLInput{
+			Assumptions: map[string]bool{"node3": true},
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*DPLLOutput)
+		if out.Status != DPLLUnsatisfiable {
+			t.Fatalf("status = %s, want unsatisfiable", out.Status)
+		}
+		if out.LearnedClause == nil || len(out.LearnedClause.Literals) != 1 || !out.LearnedClause.Literals[0].Negated {
+			t.Errorf("learned clause = %v, want ¬node3", out.LearnedClause)
+		}
+		pd, ok := delta.(*crs.ProofDelta)
+		if !ok || pd.Updates["node3"].Status != crs.ProofStatusDisproven {
+			t.Errorf("delta = %v, want node3 DISPROVEN", delta)
+		}
+	})
+
+	t.Run("probing finds forced variables", func(t *testing.T) {
+		result, delta, err := algo.Process(ctx, c.Snapshot(), &DPLLInput{
+			Probe: []string{"node1", "node3", "node4"},
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*DPLLOutput)
+		if v, ok := out.Forced["node3"]; !ok || v {
+			t.Errorf("forced = %v, want node3 forced false", out.Forced)
+		}
+		if _, ok := out.Forced["node1"]; ok {
+			t.Errorf("node1 is not forced, got %v", out.Forced)
+		}
+		if pd, ok := delta.(*crs.ProofDelta); !ok || len(pd.Updates) != 1 {
+			t.Errorf("delta = %v, want only node3 disproven", delta)
+		}
+	})
+
+	t.Run("input clauses combine with snapshot", func(t *testing.T) {
+		// Some node must be selected, and node4 is ruled out: only node2
+		// remains
+		result, _, err := algo.Process(ctx, c.Snapshot(), &DPLLInput{
+			Clauses: []crs.Clause{
+				hardClause("some", lit("node1", false), lit("node2", false), lit("node3", false)),
+				hardClause("no4", lit("node4", true)),
+			},
+			Probe: []string{"node2"},
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*DPLLOutput)
+		if !out.Forced["node2"] {
+			t.Errorf("forced = %v, want node2 forced true", out.Forced)
+		}
+	})
+
+	t.Run("soft clauses never disprove", func(t *testing.T) {
+		soft := hardClause("soft", lit("node4", true))
+		soft.Source = crs.SignalSourceSoft
+		result, delta, err := algo.Process(ctx, c.Snapshot(), &DPLLInput{
+			Clauses:     []crs.Clause{soft},
+			Assumptions: map[string]bool{"node1": true},
+		})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		out := result.(*DPLLOutput)
+		if out.Status != DPLLUnsatisfiable || out.Hard || out.LearnedClause != nil || delta != nil {
+			t.Errorf("got status=%s hard=%v learned=%v delta=%v, want soft UNSAT with no effects",
+				out.Status, out.Hard, out.LearnedClause, delta)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, _, err := algo.Process(ctx, c.Snapshot(), "wrong")
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestDPLL_Pigeonhole(t *testing.T) {
+	// 4 pigeons in 3 holes is unsatisfiable and needs real search
+	const pigeons, holes = 4, 3
+	v := func(p, h int) string { return fmt.Sprintf("p%d_h%d", p, h) }
+	var clauses []crs.Clause
+	for p := 0; p < pigeons; p++ {
+		var lits []crs.Literal
+		for h := 0; h < holes; h++ {
+			lits = append(lits, lit(v(p, h), false))
+		}
+		clauses = append(clauses, hardClause(fmt.Sprintf("p%d", p), lits...))
+	}
+	for h := 0; h < holes; h++ {
+		for a := 0; a < pigeons; a++ {
+			for b := a + 1; b < pigeons; b++ {
+				clauses = append(clauses, hardClause(fmt.Sprintf("h%d_%d_%d", h, a, b),
+					lit(v(a, h), true), lit(v(b, h), true)))
+			}
+		}
+	}
+
+	algo := NewDPLL(nil)
+	input := &DPLLInput{Clauses: clauses}
+	result, _, err := algo.Process(context.Background(), crs.New(nil).Snapshot(), input)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	out := result.(*DPLLOutput)
+	if out.Status != DPLLUnsatisfiable || out.Conflicts == 0 {
+		t.Errorf("status = %s with %d conflicts, want unsatisfiable after search", out.Status, out.Conflicts)
+	}
+
+	// Dropping a pigeon makes it satisfiable, and the model must pass the
+	// properties.
+	input.Clauses = clauses[1:]
+	result, _, err = algo.Process(context.Background(), crs.New(nil).Snapshot(), input)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	out = result.(*DPLLOutput)
+	if out.Status != DPLLSatisfiable {
+		t.Fatalf("status = %s, want satisfiable", out.Status)
+	}
+	for _, prop := range algo.Properties() {
+		if err := prop.Check(input, out); err != nil {
+			t.Errorf("property %s: %v", prop.Name, err)
+		}
+	}
+
+	// A tiny decision budget gives up
+	limited := NewDPLL(&DPLLConfig{MaxDecisions: 1})
+	input.Clauses = clauses
+	result, _, _ = limited.Process(context.Background(), crs.New(nil).Snapshot(), input)
+	if got := result.(*DPLLOutput).Status; got != DPLLUnknown {
+		t.Errorf("limited status = %s, want unknown", got)
+	}
+}
+
+func TestCDCLClause_Clause(t *testing.T) {
+	c := CDCLClause{
+		ID:       "c1",
+		Literals: []CDCLLiteral{{NodeID: "a", Positive: true}, {NodeID: "b"}},
+		Source:   crs.SignalSourceHard,
+	}
+	got := c.Clause()
+	if got.ID != "c1" || got.Literals[0].Negated || !got.Literals[1].Negated || got.Source != crs.SignalSourceHard {
+		t.Errorf("Clause() = %+v", got)
+	}
+}
@@ -12,6 +12,8 @@ package search
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"reflect"
 	"time"
 
@@ -37,6 +39,11 @@ import (
 //	- MPN (Most Proving Node): Node with smallest proof number
 //	- Selection: Follow MPN from root to leaf
 //
+//	Progressive Widening:
+//	- With PNMCTSInput.WideningConstant C > 0, a node visited n times
+//	  selects among at most ceil(C * sqrt(n)) of its unsolved children
+//	- Keeps selection tractable at nodes with thousands of dependents
+//
 //	Hard/Soft Signal Boundary:
 //	- Only hard signals (compiler, tests) can mark nodes DISPROVEN
 //	- Soft signals update proof/disproof numbers but not status
@@ -103,6 +110,15 @@ type PNMCTSInput struct {
 
 	// MaxDepth limits search depth.
 	MaxDepth int
+
+	// WideningConstant enables progressive widening when positive.
+	//
+	// A node visited n times selects among only its first
+	// ceil(WideningConstant * sqrt(n)) unsolved children, in the order the
+	// dependency index returns them, so more children come into play as
+	// the node is revisited. Zero considers every child. Must not be
+	// negative.
+	WideningConstant float64
 }
 
 // PNMCTSOutput is the output from PN-MCTS.
@@ -124,6 +140,17 @@ type PNMCTSOutput struct {
 
 	// Converged is true if proof numbers stabilized.
 	Converged bool
+
+	// Visits is how many times selection passed through each node.
+	Visits map[string]int
+
+	// Widened is, for each node selection passed through, the largest
+	// number of its unsolved children that selection considered.
+	Widened map[string]int
+
+	// WideningPruned counts the children skipped by progressive widening,
+	// summed over all selection steps.
+	WideningPruned int
 }
 
 // -----------------------------------------------------------------------------
@@ -161,11 +188,21 @@ func (p *PNMCTS) Process(ctx context.Context, snapshot crs.Snapshot, input any)
 		}
 	}
 
+	if in.WideningConstant < 0 || math.IsNaN(in.WideningConstant) || math.IsInf(in.WideningConstant, 0) {
+		return nil, nil, &AlgorithmError{
+			Algorithm: "pnmcts",
+			Operation: "Process",
+			Err:       fmt.Errorf("%w: widening constant %v", ErrInvalidInput, in.WideningConstant),
+		}
+	}
+
 	proofIndex := snapshot.ProofIndex()
 	depIndex := snapshot.DependencyIndex()
 
 	output := &PNMCTSOutput{
 		ProofUpdates: make(map[string]crs.ProofNumber),
+		Visits:       make(map[string]int),
+		Widened:      make(map[string]int),
 	}
 
 	// Track proof numbers for this search
@@ -204,7 +241,7 @@ func (p *PNMCTS) Process(ctx context.Context, snapshot crs.Snapshot, input any)
 		}
 
 		// Select most proving node (MPN)
-		mpn := p.selectMPN(in.RootNodeID, proofNumbers, depIndex, proofIndex)
+		mpn := p.selectMPN(in.RootNodeID, proofNumbers, depIndex, proofIndex, in.WideningConstant, output)
 		if mpn == "" {
 			output.Converged = true
 			break
@@ -239,17 +276,23 @@ func (p *PNMCTS) Process(ctx context.Context, snapshot crs.Snapshot, input any)
 //	Uses both the local proofs map (for updated values during search)
 //	and the proofIndex parameter (for initial values from CRS snapshot).
 //
+//	Each node traversed is counted in output.Visits. With a positive
+//	widening constant, only the first wideningLimit unsolved children of
+//	a node are candidates; the rest are counted in output.WideningPruned.
+//
 // Inputs:
 //
 //	rootID - Starting node for traversal.
 //	proofs - Local proof number cache (updated during search).
 //	deps - Dependency index for traversing edges.
 //	proofIndex - Snapshot's proof index for looking up initial values.
+//	widening - Progressive widening constant (0 = disabled).
+//	output - Receives visit and widening statistics.
 //
 // Outputs:
 //
 //	string - The most proving node ID, or empty if cycle detected or all solved.
-func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, deps crs.DependencyIndexView, proofIndex crs.ProofIndexView) string {
+func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, deps crs.DependencyIndexView, proofIndex crs.ProofIndexView, widening float64, output *PNMCTSOutput) string {
 	current := rootID
 	visited := make(map[string]bool)
 
@@ -258,6 +301,7 @@ func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, dep
 			return "" // Cycle detected
 		}
 		visited[current] = true
+		output.Visits[current]++
 
 		children := deps.DependsOn(current)
 		if len(children) == 0 {
@@ -267,6 +311,8 @@ func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, dep
 		// Find child with minimum proof number
 		var minChild string
 		var minProof uint64 = p.config.InfinityThreshold
+		limit := wideningLimit(widening, output.Visits[current])
+		considered := 0
 
 		for _, child := range children {
 			// Check local cache first, then snapshot's proof index
@@ -275,10 +321,16 @@ func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, dep
 				pn, exists = proofIndex.Get(child)
 			}
 
+			if exists && (pn.Status == crs.ProofStatusProven || pn.Status == crs.ProofStatusDisproven) {
+				continue // Skip solved nodes
+			}
+			if limit > 0 && considered == limit {
+				output.WideningPruned++
+				continue // Not yet widened to this child
+			}
+			considered++
+
 			if exists {
-				if pn.Status == crs.ProofStatusProven || pn.Status == crs.ProofStatusDisproven {
-					continue // Skip solved nodes
-				}
 				if pn.Proof < minProof {
 					minProof = pn.Proof
 					minChild = child
@@ -291,6 +343,7 @@ func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, dep
 				}
 			}
 		}
+		output.Widened[current] = max(output.Widened[current], considered)
 
 		if minChild == "" {
 			return current // All children solved
@@ -299,6 +352,20 @@ func (p *PNMCTS) selectMPN(rootID string, proofs map[string]crs.ProofNumber, dep
 	}
 }
 
+// wideningLimit returns how many unsolved children a node visited visits
+// times may select among, or 0 for no limit.
+//
+// Description:
+//
+//	Progressive widening admits ceil(c * sqrt(visits)) children, and
+//	always at least one so selection can descend.
+func wideningLimit(c float64, visits int) int {
+	if c <= 0 {
+		return 0
+	}
+	return max(1, int(math.Ceil(c*math.Sqrt(float64(visits)))))
+}
+
 // tracePath traces the path from root to target.
 func (p *PNMCTS) tracePath(rootID, targetID string, deps crs.DependencyIndexView) []string {
 	if rootID == targetID {
@@ -424,6 +491,19 @@ func (p *PNMCTS) SupportsPartialResults() bool {
 	return true
 }
 
+// PartialQuality returns the fraction of MaxIterations completed. A
+// converged search is complete.
+func (p *PNMCTS) PartialQuality(_, output any) float64 {
+	out, ok := output.(*PNMCTSOutput)
+	if !ok || out == nil || p.config.MaxIterations <= 0 {
+		return 0
+	}
+	if out.Converged {
+		return 1
+	}
+	return float64(out.Iterations) / float64(p.config.MaxIterations)
+}
+
 // -----------------------------------------------------------------------------
 // Evaluable Implementation
 // -----------------------------------------------------------------------------
@@ -439,6 +519,30 @@ func (p *PNMCTS) Properties() []eval.Property {
 				return nil
 			},
 		},
+		{
+			Name:        "progressive_widening_bound",
+			Description: "With widening, a node visited n times considers at most ceil(C*sqrt(n)) children",
+			Check: func(input, output any) error {
+				in, ok := input.(*PNMCTSInput)
+				if !ok || in.WideningConstant <= 0 {
+					return nil
+				}
+				out, ok := output.(*PNMCTSOutput)
+				if !ok {
+					return nil
+				}
+				for nodeID, widened := range out.Widened {
+					if limit := wideningLimit(in.WideningConstant, out.Visits[nodeID]); widened > limit {
+						return &AlgorithmError{
+							Algorithm: "pnmcts",
+							Operation: "Property.progressive_widening_bound",
+							Err:       fmt.Errorf("node %s considered %d children, limit %d after %d visits", nodeID, widened, limit, out.Visits[nodeID]),
+						}
+					}
+				}
+				return nil
+			},
+		},
 		{
 			Name:        "no_soft_disproven",
 			Description: "PN-MCTS never marks nodes as DISPROVEN",
@@ -476,6 +580,11 @@ func (p *PNMCTS) Metrics() []eval.MetricDefinition {
 			Type:        eval.MetricCounter,
 			Description: "Total proof number updates",
 		},
+		{
+			Name:        "pnmcts_widening_pruned_total",
+			Type:        eval.MetricCounter,
+			Description: "Total children skipped by progressive widening",
+		},
 		{
 			Name:        "pnmcts_convergence_rate",
 			Type:        eval.MetricGauge,
@@ -507,5 +616,10 @@ func (e *AlgorithmError) Error() string {
 	return e.Algorithm + "." + e.Operation + ": " + e.Err.Error()
 }
 
+// Unwrap returns the underlying error.
+func (e *AlgorithmError) Unwrap() error {
+	return e.Err
+}
+
 var ErrInvalidInput = crs.ErrNilDelta
 var ErrInvalidConfig = crs.ErrDeltaValidation
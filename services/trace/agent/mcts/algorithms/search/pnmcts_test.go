@@ -12,6 +12,8 @@ package search
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -316,6 +318,89 @@ func TestPNMCTS_NeverMarksDisproven(t *testing.T) {
 	}
 }
 
+func TestPNMCTS_ProgressiveWidening(t *testing.T) {
+	// A root with 100 children; later children have smaller proof numbers,
+	// so full selection always picks the last one.
+	now := time.Now().UnixMilli()
+	children := make([]string, 100)
+	proofData := make(map[string]crs.ProofNumber)
+	for i := range children {
+		children[i] = fmt.Sprintf("c%02d", i)
+		proofData[children[i]] = crs.ProofNumber{Proof: uint64(200 - i), Disproof: 1, UpdatedAt: now}
+	}
+	snapshot := &mockSnapshot{
+		generation: 1,
+		createdAt:  now,
+		proof:      &mockProofIndexView{data: proofData},
+		dependency: &mockDependencyIndexView{edges: map[string][]string{"root": children}},
+	}
+	ctx := context.Background()
+	algo := NewPNMCTS(&PNMCTSConfig{MaxIterations: 16, Timeout: time.Second, InfinityThreshold: 1 << 32})
+
+	run := func(t *testing.T, input *PNMCTSInput) *PNMCTSOutput {
+		t.Helper()
+		result, _, err := algo.Process(ctx, snapshot, input)
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		for _, prop := range algo.Properties() {
+			if err := prop.Check(input, result); err != nil {
+				t.Errorf("property %s: %v", prop.Name, err)
+			}
+		}
+		return result.(*PNMCTSOutput)
+	}
+
+	t.Run("disabled considers every child", func(t *testing.T) {
+		out := run(t, &PNMCTSInput{RootNodeID: "root"})
+		if out.SelectedNode != "c99" || out.Widened["root"] != 100 || out.WideningPruned != 0 {
+			t.Errorf("selected %s, widened %d, pruned %d", out.SelectedNode, out.Widened["root"], out.WideningPruned)
+		}
+	})
+
+	t.Run("widens with sqrt of visits", func(t *testing.T) {
+		out := run(t, &PNMCTSInput{RootNodeID: "root", WideningConstant: 1})
+		// 16 visits => ceil(sqrt(16)) = 4 children.
+		if out.Visits["root"] != 16 || out.Widened["root"] != 4 {
+			t.Errorf("visits %d, widened %d; want 16 and 4", out.Visits["root"], out.Widened["root"])
+		}
+		if out.SelectedNode != "c03" {
+			t.Errorf("selected %s, want c03", out.SelectedNode)
+		}
+		if out.WideningPruned == 0 {
+			t.Error("expected pruned children")
+		}
+	})
+
+	t.Run("constant scales the width", func(t *testing.T) {
+		out := run(t, &PNMCTSInput{RootNodeID: "root", WideningConstant: 2.5})
+		if out.Widened["root"] != 10 {
+			t.Errorf("widened %d, want 10", out.Widened["root"])
+		}
+	})
+
+	t.Run("rejects negative constant", func(t *testing.T) {
+		_, _, err := algo.Process(ctx, snapshot, &PNMCTSInput{RootNodeID: "root", WideningConstant: -1})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("property detects overly wide selection", func(t *testing.T) {
+		input := &PNMCTSInput{RootNodeID: "root", WideningConstant: 1}
+		bad := &PNMCTSOutput{Visits: map[string]int{"root": 4}, Widened: map[string]int{"root": 3}}
+		var failed bool
+		for _, prop := range algo.Properties() {
+			if prop.Name == "progressive_widening_bound" {
+				failed = prop.Check(input, bad) != nil
+			}
+		}
+		if !failed {
+			t.Error("expected progressive_widening_bound to fail")
+		}
+	})
+}
+
 func TestPNMCTS_Evaluable(t *testing.T) {
 	algo := NewPNMCTS(nil)
 
@@ -94,7 +94,8 @@ func NewWeisfeilerLeman(config *WLConfig) *WeisfeilerLeman {
 
 // WLInput is the input for Weisfeiler-Leman operations.
 type WLInput struct {
-	// Operation specifies what to do: "color", "compare", or "fingerprint".
+	// Operation specifies what to do: "color", "compare", "fingerprint",
+	// or "signature".
 	Operation string
 
 	// Graph is the graph to process.
@@ -144,6 +145,11 @@ type WLOutput struct {
 
 	// Stabilized is true if coloring stabilized before max iterations.
 	Stabilized bool
+
+	// Signature is the WL subtree-kernel feature vector (for "signature"):
+	// the number of vertices of each color, summed over the initial
+	// coloring and every refinement iteration.
+	Signature map[uint64]int
 }
 
 // -----------------------------------------------------------------------------
@@ -159,10 +165,12 @@ func (w *WeisfeilerLeman) Name() string {
 //
 // Description:
 //
-//	Supports three operations:
+//	Supports four operations:
 //	- "color": Compute stable vertex coloring
 //	- "compare": Compare two graphs for potential isomorphism
 //	- "fingerprint": Compute canonical graph fingerprint
+//	- "signature": Compute the WL subtree-kernel feature vector; see
+//	  WLKernelDistance and NewWLSimilarityDelta
 //
 // Thread Safety: Safe for concurrent use.
 func (w *WeisfeilerLeman) Process(ctx context.Context, snapshot crs.Snapshot, input any) (any, crs.Delta, error) {
@@ -192,6 +200,8 @@ func (w *WeisfeilerLeman) Process(ctx context.Context, snapshot crs.Snapshot, in
 		output, err = w.compare(ctx, in)
 	case "fingerprint":
 		output, err = w.fingerprint(ctx, in)
+	case "signature":
+		output, err = w.signature(ctx, in)
 	default:
 		return nil, nil, &AlgorithmError{
 			Algorithm: "weisfeiler_leman",
@@ -222,22 +232,7 @@ func (w *WeisfeilerLeman) color(ctx context.Context, in *WLInput) (*WLOutput, er
 		}
 	}
 
-	// Initialize colors
-	colors := make(map[string]uint64)
-	if in.InitialColors != nil {
-		for k, v := range in.InitialColors {
-			colors[k] = v
-		}
-	} else {
-		// Use node labels or default color
-		for _, node := range in.Graph.Nodes {
-			if label, ok := in.Graph.NodeLabels[node]; ok {
-				colors[node] = w.hash64(label)
-			} else {
-				colors[node] = 0
-			}
-		}
-	}
+	colors := w.initialColors(in)
 
 	// Iterative refinement
 	iterations := 0
@@ -352,6 +347,74 @@ func (w *WeisfeilerLeman) fingerprint(ctx context.Context, in *WLInput) (*WLOutp
 	return out, nil
 }
 
+// signature computes the WL subtree-kernel feature vector.
+//
+// Description:
+//
+//	Unlike color, signature always runs MaxIterations refinements (no
+//	early stop), so signatures computed with the same config have
+//	features from the same depths and can be compared.
+func (w *WeisfeilerLeman) signature(ctx context.Context, in *WLInput) (*WLOutput, error) {
+	if in.Graph == nil {
+		return &WLOutput{
+			Colors:         make(map[string]uint64),
+			ColorHistogram: make(map[uint64]int),
+			Signature:      make(map[uint64]int),
+			Stabilized:     true,
+		}, nil
+	}
+
+	if len(in.Graph.Nodes) > w.config.MaxNodes {
+		return nil, &AlgorithmError{
+			Algorithm: "weisfeiler_leman",
+			Operation: "signature",
+			Err:       errors.New("too many nodes"),
+		}
+	}
+
+	colors := w.initialColors(in)
+	signature := w.computeHistogram(colors)
+	for i := 0; i < w.config.MaxIterations; i++ {
+		// A partial signature is not comparable with complete ones.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		colors, _ = w.refineColors(in.Graph, colors)
+		for color, count := range w.computeHistogram(colors) {
+			signature[color] += count
+		}
+	}
+
+	histogram := w.computeHistogram(colors)
+	return &WLOutput{
+		Colors:         colors,
+		ColorHistogram: histogram,
+		Signature:      signature,
+		IterationsUsed: w.config.MaxIterations,
+		ColorClasses:   len(histogram),
+	}, nil
+}
+
+// initialColors returns the starting colors: InitialColors if given,
+// otherwise the hashed node label (0 for unlabeled nodes).
+func (w *WeisfeilerLeman) initialColors(in *WLInput) map[string]uint64 {
+	colors := make(map[string]uint64)
+	if in.InitialColors != nil {
+		for k, v := range in.InitialColors {
+			colors[k] = v
+		}
+		return colors
+	}
+	for _, node := range in.Graph.Nodes {
+		if label, ok := in.Graph.NodeLabels[node]; ok {
+			colors[node] = w.hash64(label)
+		} else {
+			colors[node] = 0
+		}
+	}
+	return colors
+}
+
 // refineColors performs one iteration of color refinement.
 func (w *WeisfeilerLeman) refineColors(graph *WLGraph, colors map[string]uint64) (map[string]uint64, bool) {
 	newColors := make(map[string]uint64)
@@ -507,6 +570,19 @@ func (w *WeisfeilerLeman) SupportsPartialResults() bool {
 	return true
 }
 
+// PartialQuality returns the fraction of refinement iterations completed.
+// A stabilized coloring is complete.
+func (w *WeisfeilerLeman) PartialQuality(_, output any) float64 {
+	out, ok := output.(*WLOutput)
+	if !ok || out == nil || w.config.MaxIterations <= 0 {
+		return 0
+	}
+	if out.Stabilized {
+		return 1
+	}
+	return float64(out.IterationsUsed) / float64(w.config.MaxIterations)
+}
+
 // -----------------------------------------------------------------------------
 // Evaluable Implementation
 // -----------------------------------------------------------------------------
@@ -0,0 +1,154 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package streaming
+
+import (
+	"math"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// -----------------------------------------------------------------------------
+// WL Similarity Export
+// -----------------------------------------------------------------------------
+
+// WLSimilarityConfig configures NewWLSimilarityDelta.
+type WLSimilarityConfig struct {
+	// MaxDistance is the largest distance exported. Farther pairs are
+	// structurally unrelated and would only bloat the SimilarityIndex.
+	// Range [0, 1].
+	MaxDistance float64
+
+	// MinChange skips pairs whose stored distance differs from the new
+	// distance by less than this, so re-exporting unchanged signatures
+	// produces no updates.
+	MinChange float64
+}
+
+// DefaultWLSimilarityConfig returns the default configuration.
+func DefaultWLSimilarityConfig() *WLSimilarityConfig {
+	return &WLSimilarityConfig{
+		MaxDistance: 0.3,
+		MinChange:   0.01,
+	}
+}
+
+// WLKernelDistance returns the distance between two WL signatures.
+//
+// Description:
+//
+//	The distance is 1 minus the normalized WL subtree kernel (the cosine
+//	of the two feature vectors): 0 for graphs WL cannot tell apart, 1 for
+//	graphs sharing no colors at any depth. Signatures must come from the
+//	same WLConfig.
+//
+// Inputs:
+//   - a, b: Signatures from the "signature" operation.
+//
+// Outputs:
+//   - float64: Distance in [0, 1]. 1 if either signature is empty.
+func WLKernelDistance(a, b map[uint64]int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 1
+	}
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot, normA, normB float64
+	for color, n := range a {
+		dot += float64(n) * float64(b[color])
+		normA += float64(n) * float64(n)
+	}
+	for _, n := range b {
+		normB += float64(n) * float64(n)
+	}
+
+	distance := 1 - dot/math.Sqrt(normA*normB)
+	return math.Min(1, math.Max(0, distance))
+}
+
+// NewWLSimilarityDelta converts WL signatures into a SimilarityIndex delta.
+//
+// Description:
+//
+//	Computes WLKernelDistance for every pair of signatures and emits the
+//	pairs within config.MaxDistance, so structurally similar code (e.g.
+//	functions with the same call or control-flow shape) can be found via
+//	SimilarityIndexView.NearestNeighbors for pattern transfer. Pairs whose
+//	distance in snapshot already matches within config.MinChange are
+//	skipped. Each pair is emitted once, keyed (smaller ID, larger ID);
+//	the index looks distances up in both directions.
+//
+//	Cost is O(n^2) signature comparisons; export signatures in batches
+//	(e.g. per package) for large codebases.
+//
+// Inputs:
+//   - snapshot: Current CRS snapshot. May be nil (nothing is skipped).
+//   - signatures: Signature per node ID (the IDs the SimilarityIndex uses).
+//   - config: Thresholds. Nil uses DefaultWLSimilarityConfig.
+//   - source: Signal source recorded on the delta.
+//
+// Outputs:
+//   - *crs.SimilarityDelta: The updates, or nil if there are none.
+//
+// Example:
+//
+//	out, _, err := wl.Process(ctx, snapshot, &WLInput{Operation: "signature", Graph: g})
+//	sigs[fnID] = out.(*WLOutput).Signature
+//	...
+//	if delta := NewWLSimilarityDelta(snapshot, sigs, nil, crs.SignalSourceHard); delta != nil {
+//	    _, err = c.Apply(ctx, delta)
+//	}
+//
+// Thread Safety: Safe for concurrent use; signatures are only read.
+func NewWLSimilarityDelta(
+	snapshot crs.Snapshot,
+	signatures map[string]map[uint64]int,
+	config *WLSimilarityConfig,
+	source crs.SignalSource,
+) *crs.SimilarityDelta {
+	if config == nil {
+		config = DefaultWLSimilarityConfig()
+	}
+	var existing crs.SimilarityIndexView
+	if snapshot != nil {
+		existing = snapshot.SimilarityIndex()
+	}
+
+	ids := make([]string, 0, len(signatures))
+	for id := range signatures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	delta := crs.NewSimilarityDelta(source)
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			distance := WLKernelDistance(signatures[a], signatures[b])
+			if distance > config.MaxDistance {
+				continue
+			}
+			if existing != nil {
+				if old, ok := existing.Distance(a, b); ok && math.Abs(old-distance) < config.MinChange {
+					continue
+				}
+			}
+			delta.Updates[[2]string{a, b}] = distance
+		}
+	}
+
+	if len(delta.Updates) == 0 {
+		return nil
+	}
+	return delta
+}
@@ -500,3 +500,70 @@ func TestWeisfeilerLeman_Evaluable(t *testing.T) {
 		}
 	})
 }
+
+func TestWLSimilarityDelta(t *testing.T) {
+	algo := NewWeisfeilerLeman(nil)
+	ctx := context.Background()
+	c := crs.New(nil)
+
+	path := func(a, b, c string) *WLGraph {
+		return &WLGraph{
+			Nodes: []string{a, b, c},
+			Edges: map[string][]string{a: {b}, b: {a, c}, c: {b}},
+		}
+	}
+	graphs := map[string]*WLGraph{
+		"pkg.Load":  path("A", "B", "C"),
+		"pkg.Store": path("X", "Y", "Z"),
+		"pkg.Cycle": {
+			Nodes: []string{"A", "B", "C"},
+			Edges: map[string][]string{"A": {"B", "C"}, "B": {"A", "C"}, "C": {"A", "B"}},
+		},
+	}
+
+	signatures := make(map[string]map[uint64]int)
+	for id, g := range graphs {
+		result, _, err := algo.Process(ctx, c.Snapshot(), &WLInput{Operation: "signature", Graph: g})
+		if err != nil {
+			t.Fatalf("signature %s: %v", id, err)
+		}
+		out := result.(*WLOutput)
+		if out.IterationsUsed != DefaultWLConfig().MaxIterations {
+			t.Errorf("signature %s ran %d iterations, want all", id, out.IterationsUsed)
+		}
+		signatures[id] = out.Signature
+	}
+
+	same := WLKernelDistance(signatures["pkg.Load"], signatures["pkg.Store"])
+	different := WLKernelDistance(signatures["pkg.Load"], signatures["pkg.Cycle"])
+	if same != 0 {
+		t.Errorf("distance between isomorphic graphs = %v, want 0", same)
+	}
+	if different <= same || different > 1 {
+		t.Errorf("distance to non-isomorphic graph = %v, want in (0, 1]", different)
+	}
+
+	config := &WLSimilarityConfig{MaxDistance: different / 2, MinChange: 0.01}
+	delta := NewWLSimilarityDelta(c.Snapshot(), signatures, config, crs.SignalSourceHard)
+	if delta == nil {
+		t.Fatal("expected a delta")
+	}
+	if len(delta.Updates) != 1 {
+		t.Fatalf("updates = %v, want only the isomorphic pair", delta.Updates)
+	}
+	if d, ok := delta.Updates[[2]string{"pkg.Load", "pkg.Store"}]; !ok || d != 0 {
+		t.Errorf("updates = %v, want pkg.Load-pkg.Store at 0", delta.Updates)
+	}
+
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d, ok := c.Snapshot().SimilarityIndex().Distance("pkg.Store", "pkg.Load"); !ok || d != 0 {
+		t.Errorf("Distance after apply = %v, %v", d, ok)
+	}
+
+	// Unchanged signatures produce no further updates.
+	if delta := NewWLSimilarityDelta(c.Snapshot(), signatures, config, crs.SignalSourceHard); delta != nil {
+		t.Errorf("re-export updates = %v, want none", delta.Updates)
+	}
+}
@@ -116,6 +116,32 @@ type Algorithm interface {
 	SupportsPartialResults() bool
 }
 
+// QualityReporter is implemented by anytime algorithms that can say how
+// complete a partial result is.
+//
+// Description:
+//
+//	An algorithm that supports partial results may implement
+//	QualityReporter. When a run is cancelled, the runner calls
+//	PartialQuality with the run's input and partial output and stores the
+//	score in Result.Quality, so callers can decide whether the partial
+//	delta is trustworthy enough to apply. Algorithms that do not implement
+//	it report a quality of 0 for partial results.
+//
+// Thread Safety: Must be safe for concurrent use.
+type QualityReporter interface {
+	// PartialQuality scores a partial output.
+	//
+	// Inputs:
+	//   - input: The input the run was given.
+	//   - output: The partial output Process returned. May be nil.
+	//
+	// Outputs:
+	//   - float64: Completeness from 0.0 (nothing useful) to 1.0 (as good
+	//     as a complete run). Values outside the range are clamped.
+	PartialQuality(input, output any) float64
+}
+
 // -----------------------------------------------------------------------------
 // Algorithm Result
 // -----------------------------------------------------------------------------
@@ -156,6 +182,15 @@ type Result struct {
 	// Partial is true if the result is a partial result.
 	Partial bool
 
+	// Cached is true if the result came from the runner's result cache
+	// instead of running the algorithm.
+	Cached bool
+
+	// Quality is the completeness of the output from 0.0 to 1.0. Complete
+	// results have quality 1.0. Partial results carry the score from
+	// QualityReporter, or 0 if the algorithm does not report one.
+	Quality float64
+
 	// Metrics contains algorithm-specific metrics.
 	Metrics map[string]float64
 }
@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"slices"
+	"sync"
+)
+
+// -----------------------------------------------------------------------------
+// Secondary Constraint Index
+// -----------------------------------------------------------------------------
+
+// constraintSecondaryIndex indexes a snapshot's constraints by node and type.
+//
+// Description:
+//
+//	Built lazily on the first lookup, then reused by every view of the
+//	snapshot and by later snapshots that share the same constraint data.
+//	Constraint IDs in each bucket are sorted so lookups are deterministic.
+//
+// Thread Safety: Safe for concurrent use.
+type constraintSecondaryIndex struct {
+	data map[string]Constraint
+
+	once          sync.Once
+	byNode        map[string][]string
+	byType        map[ConstraintType][]string
+	temporal      map[string][]string // node -> temporal constraint IDs
+	temporalEdges []TemporalEdge
+}
+
+// newConstraintSecondaryIndex creates an unbuilt index over data. The data
+// must not be modified afterwards.
+func newConstraintSecondaryIndex(data map[string]Constraint) *constraintSecondaryIndex {
+	return &constraintSecondaryIndex{data: data}
+}
+
+// build populates the index. Called once via ensure.
+func (x *constraintSecondaryIndex) build() {
+	x.byNode = make(map[string][]string)
+	x.byType = make(map[ConstraintType][]string)
+	x.temporal = make(map[string][]string)
+
+	ids := make([]string, 0, len(x.data))
+	for id := range x.data {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		c := x.data[id]
+		x.byType[c.Type] = append(x.byType[c.Type], id)
+
+		seen := make(map[string]bool, len(c.Nodes))
+		for _, n := range c.Nodes {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			x.byNode[n] = append(x.byNode[n], id)
+			if c.Type.IsTemporal() {
+				x.temporal[n] = append(x.temporal[n], id)
+			}
+		}
+
+		if c.Active {
+			x.temporalEdges = append(x.temporalEdges, c.TemporalEdges()...)
+		}
+	}
+}
+
+// ensure builds the index on first use.
+func (x *constraintSecondaryIndex) ensure() *constraintSecondaryIndex {
+	x.once.Do(x.build)
+	return x
+}
+
+// lookup resolves constraint IDs to constraints.
+func (x *constraintSecondaryIndex) lookup(ids []string) []Constraint {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := make([]Constraint, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, x.data[id])
+	}
+	return result
+}
+
+// findByNode returns the constraints affecting nodeID.
+func (x *constraintSecondaryIndex) findByNode(nodeID string) []Constraint {
+	return x.lookup(x.ensure().byNode[nodeID])
+}
+
+// findByType returns the constraints of type t.
+func (x *constraintSecondaryIndex) findByType(t ConstraintType) []Constraint {
+	return x.lookup(x.ensure().byType[t])
+}
+
+// findTemporal returns the temporal constraints affecting nodeID.
+func (x *constraintSecondaryIndex) findTemporal(nodeID string) []Constraint {
+	return x.lookup(x.ensure().temporal[nodeID])
+}
+
+// edges returns a copy of the temporal edges of active constraints.
+func (x *constraintSecondaryIndex) edges() []TemporalEdge {
+	return slices.Clone(x.ensure().temporalEdges)
+}
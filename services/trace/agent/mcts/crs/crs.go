@@ -15,6 +15,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -66,6 +68,9 @@ type crsImpl struct {
 	// Delta history (GR-35) - channel-based, no lock needed
 	deltaHistory *DeltaHistoryWorker
 
+	// Audit journal of applied deltas - protected by mu
+	deltaJournal *DeltaJournal
+
 	// Current session ID for delta history tracking
 	currentSessionID string
 
@@ -91,6 +96,12 @@ type crsImpl struct {
 
 	// GR-31: Analytics history for tracking analytics queries
 	analyticsData *AnalyticsHistory
+
+	// Copy-on-write snapshot state and memory budget
+	snapshots *snapshotBudget
+
+	// Watch subscriptions; zero value is ready to use
+	watchers watchRegistry
 }
 
 // sessionSteps holds step records for a single session.
@@ -137,6 +148,7 @@ func New(config *Config) CRS {
 		deltaHistory:   NewDeltaHistoryWorker(DefaultMaxDeltaRecords, logger),
 		stepData:       make(map[string]*sessionSteps),
 		analyticsData:  NewAnalyticsHistory(MaxAnalyticsHistoryRecords),
+		snapshots:      newSnapshotBudget(config.SnapshotMemoryBudget, logger),
 	}
 }
 
@@ -145,22 +157,16 @@ func New(config *Config) CRS {
 // -----------------------------------------------------------------------------
 
 // Snapshot returns an immutable view of the current state.
+//
+// Indexes unchanged since the previous snapshot are shared with it rather
+// than copied. If retained snapshot memory exceeds Config.SnapshotMemoryBudget,
+// a compaction runs after the read lock is released.
 func (c *crsImpl) Snapshot() Snapshot {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 
 	c.snapshotCount.Add(1)
 
-	snap := newSnapshot(
-		c.generation.Load(),
-		c.proofData,
-		c.constraintData,
-		c.similarityData,
-		c.dependencyData,
-		c.historyData,
-		c.streamingData,
-		c.clauseData,
-	)
+	snap := c.snapshots.build(c)
 
 	// GR-28: Include graph provider in snapshot
 	snap.setGraphQuery(c.graphProvider)
@@ -173,9 +179,19 @@ func (c *crsImpl) Snapshot() Snapshot {
 		snap.setAnalyticsHistory(c.analyticsData.clone())
 	}
 
+	c.mu.RUnlock()
+
+	c.snapshots.enforce()
 	return snap
 }
 
+// SnapshotMemory returns statistics about memory retained by snapshots.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *crsImpl) SnapshotMemory() SnapshotMemoryStats {
+	return c.snapshots.stats()
+}
+
 // Apply atomically applies a delta to the state.
 func (c *crsImpl) Apply(ctx context.Context, delta Delta) (ApplyMetrics, error) {
 	metrics, err := c.applyCore(ctx, delta, "crs.Apply")
@@ -272,6 +288,12 @@ func (c *crsImpl) applyCore(ctx context.Context, delta Delta, spanName string) (
 		}
 	}
 
+	// Capture touched nodes and prior proof numbers for watchers.
+	var watch *watchCapture
+	if c.watchers.active() {
+		watch = c.captureForWatch(delta)
+	}
+
 	// Apply the delta based on type
 	var err error
 	switch d := delta.(type) {
@@ -288,13 +310,25 @@ func (c *crsImpl) applyCore(ctx context.Context, delta Delta, spanName string) (
 	case *StreamingDelta:
 		err = c.applyStreamingDelta(d, &metrics)
 	case *CompositeDelta:
-		err = c.applyCompositeDelta(ctx, d, &metrics)
+		// Compress after validation so superseded updates are still
+		// checked against the hard/soft boundary.
+		compressed, stats := CompressDelta(d)
+		metrics.Compression = &stats
+		err = c.applyCompositeDelta(ctx, compressed, &metrics)
 	case *AnalyticsDelta:
 		err = c.applyAnalyticsDelta(d, &metrics)
 	default:
 		err = fmt.Errorf("unknown delta type: %T", delta)
 	}
 
+	// A failed composite delta may have partially applied, so invalidate
+	// everything the next snapshot could otherwise share.
+	if err != nil {
+		c.snapshots.markDirty(allParts)
+	} else {
+		c.snapshots.markDirty(partsOf(metrics.IndexesUpdated))
+	}
+
 	if err != nil {
 		c.applyErrorCount.Add(1)
 		span.RecordError(err)
@@ -308,11 +342,27 @@ func (c *crsImpl) applyCore(ctx context.Context, delta Delta, spanName string) (
 	metrics.ApplyDuration = time.Since(startTime)
 	c.applyCount.Add(1)
 
+	// Journal while still holding the lock so journal order matches
+	// generation order.
+	c.journalDelta(ctx, span, metrics.NewGeneration, delta)
+	if watch != nil {
+		c.watchers.publish(watch.event(c, metrics))
+	}
+
 	span.SetAttributes(
 		attribute.Int64("old_generation", metrics.OldGeneration),
 		attribute.Int64("new_generation", metrics.NewGeneration),
 		attribute.Int("entries_modified", metrics.EntriesModified),
 	)
+	if cs := metrics.Compression; cs != nil {
+		span.SetAttributes(
+			attribute.Int("compression.deltas_in", cs.DeltasIn),
+			attribute.Int("compression.deltas_out", cs.DeltasOut),
+			attribute.Int("compression.updates_in", cs.UpdatesIn),
+			attribute.Int("compression.updates_out", cs.UpdatesOut),
+			attribute.Int("compression.superseded_proofs", cs.SupersededProofs),
+		)
+	}
 
 	c.logger.Debug("delta applied",
 		slog.String("type", delta.Type().String()),
@@ -543,17 +593,8 @@ func (c *crsImpl) Checkpoint(ctx context.Context) (Checkpoint, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	// Create deep copy of all state
-	snap := newSnapshot(
-		c.generation.Load(),
-		c.proofData,
-		c.constraintData,
-		c.similarityData,
-		c.dependencyData,
-		c.historyData,
-		c.streamingData,
-		c.clauseData,
-	)
+	// Copy-on-write snapshot of all state; Restore deep copies it back
+	snap := c.snapshots.build(c)
 
 	return Checkpoint{
 		ID:         uuid.New().String(),
@@ -595,30 +636,31 @@ func (c *crsImpl) Restore(ctx context.Context, cp Checkpoint) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Deep copy streaming data to avoid sharing mutex with snapshot
-	// This prevents race conditions if snapshot's streaming stats are still being read
-	newStreaming := snap.streamingData.clone()
-
-	// Deep copy clause data (CRS-04)
-	newClauseData := make(map[string]*Clause, len(snap.clauseData))
-	for k, v := range snap.clauseData {
-		clauseCopy := *v
-		if v.Literals != nil {
-			clauseCopy.Literals = make([]Literal, len(v.Literals))
-			copy(clauseCopy.Literals, v.Literals)
-		}
-		newClauseData[k] = &clauseCopy
-	}
+	// Deep copy the checkpoint so later writes never reach it or any
+	// snapshot sharing its parts. This also avoids sharing the streaming
+	// stats mutex with the snapshot.
+	restored := newSnapshot(
+		cp.Generation,
+		snap.proofData,
+		snap.constraintData,
+		snap.similarityData,
+		snap.dependencyData,
+		snap.historyData,
+		snap.streamingData,
+		snap.clauseData,
+	)
 
-	// Restore all state from checkpoint (using snapshot's immutable copies)
-	c.proofData = snap.proofData
-	c.constraintData = snap.constraintData
-	c.similarityData = snap.similarityData
-	c.dependencyData = snap.dependencyData
-	c.historyData = snap.historyData
-	c.streamingData = newStreaming
-	c.clauseData = newClauseData // CRS-04: Restore clause data
+	// Restore all state from checkpoint
+	c.proofData = restored.proofData
+	c.constraintData = restored.constraintData
+	c.similarityData = restored.similarityData
+	c.dependencyData = restored.dependencyData
+	c.historyData = restored.historyData
+	c.streamingData = restored.streamingData
+	c.clauseData = restored.clauseData // CRS-04: Restore clause data
+	c.snapshots.markDirty(allParts)
 	c.generation.Store(cp.Generation)
+	c.publishRestored(cp.Generation)
 
 	span.SetAttributes(attribute.Bool("success", true))
 
@@ -875,13 +917,17 @@ func (c *crsImpl) applyStreamingDelta(d *StreamingDelta, metrics *ApplyMetrics)
 	c.streamingData.mu.Lock()
 	defer c.streamingData.mu.Unlock()
 
-	// Track new items for cardinality
-	for item, inc := range d.Increments {
+	// Track new items for cardinality. Items are applied in sorted order so
+	// heavy hitter evictions are the same on journal replay.
+	heavy := c.streamingData.heavyHitters()
+	for _, item := range slices.Sorted(maps.Keys(d.Increments)) {
+		inc := d.Increments[item]
 		if c.streamingData.frequencies[item] == 0 {
 			// New item - increment cardinality
 			c.streamingData.cardinality++
 		}
 		c.streamingData.frequencies[item] += inc
+		heavy.add(item, inc)
 		metrics.EntriesModified++
 	}
 
@@ -890,6 +936,7 @@ func (c *crsImpl) applyStreamingDelta(d *StreamingDelta, metrics *ApplyMetrics)
 		if c.streamingData.frequencies[item] == 0 {
 			c.streamingData.frequencies[item] = 1
 			c.streamingData.cardinality++
+			heavy.add(item, 1)
 		}
 		metrics.EntriesModified++
 	}
@@ -1518,6 +1565,7 @@ func (c *crsImpl) UpdateProofNumber(ctx context.Context, update ProofUpdate) err
 	pn.Source = update.Source
 	pn.UpdatedAt = time.Now().UnixMilli()
 	c.proofData[update.NodeID] = pn
+	c.snapshots.markDirty(partSetOf(partProof))
 
 	c.logger.Debug("proof number updated",
 		slog.String("node_id", update.NodeID),
@@ -1748,6 +1796,7 @@ func (c *crsImpl) AddClause(ctx context.Context, clause *Clause) error {
 			// Update existing instead of adding duplicate
 			existing.UseCount++
 			existing.LastUsed = time.Now().UnixMilli()
+			c.snapshots.markDirty(partSetOf(partClauses))
 			span.SetAttributes(attribute.Bool("duplicate", true))
 			c.logger.Debug("clause duplicate detected, updating existing",
 				slog.String("existing_id", existing.ID),
@@ -1767,6 +1816,7 @@ func (c *crsImpl) AddClause(ctx context.Context, clause *Clause) error {
 	clause.LearnedAt = nowMillis
 	clause.LastUsed = nowMillis // CR-2: Initialize LastUsed to prevent immediate LRU eviction
 	c.clauseData[clause.ID] = clause
+	c.snapshots.markDirty(partSetOf(partClauses))
 
 	span.SetAttributes(attribute.Int("total_clauses", len(c.clauseData)))
 	c.logger.Info("clause added",
@@ -1858,6 +1908,7 @@ func (c *crsImpl) CheckDecisionAllowed(sessionID string, tool string) (bool, str
 			// Update usage stats inline (we already hold the write lock)
 			clause.UseCount++
 			clause.LastUsed = time.Now().UnixMilli()
+			c.snapshots.markDirty(partSetOf(partClauses))
 
 			return false, fmt.Sprintf("violates learned clause %s: %s", clause.ID, clause.String())
 		}
@@ -1943,6 +1994,7 @@ func (c *crsImpl) GarbageCollectClauses() int {
 	}
 
 	if removed > 0 {
+		c.snapshots.markDirty(partSetOf(partClauses))
 		c.logger.Info("clauses garbage collected",
 			slog.Int("removed", removed),
 			slog.Int("remaining", len(c.clauseData)),
@@ -485,10 +485,19 @@ func (m *mockCRSForCycleAnalysis) Apply(context.Context, Delta) (ApplyMetrics, e
 	return ApplyMetrics{}, nil
 }
 func (m *mockCRSForCycleAnalysis) Generation() int64 { return 0 }
+func (m *mockCRSForCycleAnalysis) Watch(context.Context, WatchOptions) <-chan WatchEvent {
+	return nil
+}
 func (m *mockCRSForCycleAnalysis) Checkpoint(context.Context) (Checkpoint, error) {
 	return Checkpoint{}, nil
 }
-func (m *mockCRSForCycleAnalysis) Restore(context.Context, Checkpoint) error            { return nil }
+func (m *mockCRSForCycleAnalysis) Restore(context.Context, Checkpoint) error { return nil }
+func (m *mockCRSForCycleAnalysis) Persist(context.Context, string) error     { return nil }
+func (m *mockCRSForCycleAnalysis) RestoreFrom(context.Context, string) error { return nil }
+func (m *mockCRSForCycleAnalysis) SetDeltaJournal(*DeltaJournal)             {}
+func (m *mockCRSForCycleAnalysis) ReplayJournal(context.Context, int64, int64) (CRS, error) {
+	return nil, nil
+}
 func (m *mockCRSForCycleAnalysis) RecordStep(context.Context, StepRecord) error         { return nil }
 func (m *mockCRSForCycleAnalysis) GetLastStep(string) *StepRecord                       { return nil }
 func (m *mockCRSForCycleAnalysis) CountToolExecutions(string, string) int               { return 0 }
@@ -211,10 +211,13 @@ func (d *ConstraintDelta) Validate(snapshot Snapshot) error {
 	}
 
 	// Check that constraints to update exist
-	for id := range d.Update {
+	for id, c := range d.Update {
 		if _, ok := ci.Get(id); !ok {
 			return fmt.Errorf("%w: constraint %s", ErrIndexNotFound, id)
 		}
+		if err := validateTemporalConstraint(c); err != nil {
+			return err
+		}
 	}
 
 	// Check for duplicate IDs in Add
@@ -229,11 +232,28 @@ func (d *ConstraintDelta) Validate(snapshot Snapshot) error {
 		if _, ok := ci.Get(c.ID); ok {
 			return fmt.Errorf("%w: constraint %s already exists", ErrDeltaValidation, c.ID)
 		}
+
+		if err := validateTemporalConstraint(c); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// validateTemporalConstraint checks that a temporal constraint relates
+// exactly two distinct nodes.
+func validateTemporalConstraint(c Constraint) error {
+	if !c.Type.IsTemporal() {
+		return nil
+	}
+	if len(c.Nodes) != 2 || c.Nodes[0] == c.Nodes[1] {
+		return fmt.Errorf("%w: %s constraint %s must relate two distinct nodes, got %v",
+			ErrDeltaValidation, c.Type, c.ID, c.Nodes)
+	}
+	return nil
+}
+
 // Merge combines this delta with another delta.
 func (d *ConstraintDelta) Merge(other Delta) (Delta, error) {
 	otherConstraint, ok := other.(*ConstraintDelta)
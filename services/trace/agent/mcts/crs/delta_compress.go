@@ -0,0 +1,230 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"maps"
+	"slices"
+)
+
+// -----------------------------------------------------------------------------
+// Composite Delta Compression
+// -----------------------------------------------------------------------------
+
+// CompressionStats reports how a composite delta was compressed.
+type CompressionStats struct {
+	// DeltasIn is the number of leaf deltas before compression, after
+	// flattening nested composites.
+	DeltasIn int
+
+	// DeltasOut is the number of deltas actually applied.
+	DeltasOut int
+
+	// UpdatesIn is the number of individual updates before compression.
+	UpdatesIn int
+
+	// UpdatesOut is the number of individual updates actually applied.
+	UpdatesOut int
+
+	// SupersededProofs is the number of proof number updates dropped
+	// because a later update in the batch wrote the same node.
+	SupersededProofs int
+
+	// SupersededSimilarities is the number of similarity updates dropped
+	// because a later update in the batch wrote the same pair.
+	SupersededSimilarities int
+}
+
+// Ratio returns UpdatesOut / UpdatesIn, or 1 for an empty batch.
+// Lower is better.
+func (s CompressionStats) Ratio() float64 {
+	if s.UpdatesIn == 0 {
+		return 1
+	}
+	return float64(s.UpdatesOut) / float64(s.UpdatesIn)
+}
+
+// CompressDelta coalesces redundant updates in a composite delta.
+//
+// Description:
+//
+//	Flattens nested composites, then folds each delta into an earlier delta
+//	of the same type and signal source when no delta between them touches
+//	the same index. Folding follows sequential apply semantics:
+//	  - Proof and similarity updates: the later value wins; the earlier
+//	    one is dropped as superseded.
+//	  - Streaming increments: summed per item; cardinality items appended.
+//	  - History entries: appended in order.
+//	Constraint, dependency, and analytics deltas are kept as-is because
+//	their apply order matters within a single delta.
+//
+//	Applying the result produces the same state as applying d. Only
+//	deltas of the same source are folded, so the hard/soft boundary of
+//	each update is preserved. The input is not modified.
+//
+// Inputs:
+//   - d: The composite delta. Must not be nil.
+//
+// Outputs:
+//   - *CompositeDelta: The compressed delta with d's source and timestamp.
+//   - CompressionStats: What was removed.
+//
+// Thread Safety: Safe for concurrent use; does not modify d.
+func CompressDelta(d *CompositeDelta) (*CompositeDelta, CompressionStats) {
+	flat := flattenDeltas(d.Deltas, nil)
+	stats := CompressionStats{DeltasIn: len(flat)}
+
+	out := make([]Delta, 0, len(flat))
+	owned := make([]bool, 0, len(flat))
+	for _, delta := range flat {
+		stats.UpdatesIn += deltaUpdateCount(delta)
+
+		i := coalesceTarget(out, delta)
+		if i < 0 {
+			out = append(out, delta)
+			owned = append(owned, false)
+			continue
+		}
+		if !owned[i] {
+			out[i] = cloneCoalescible(out[i])
+			owned[i] = true
+		}
+		foldDelta(out[i], delta, &stats)
+	}
+
+	for _, delta := range out {
+		stats.UpdatesOut += deltaUpdateCount(delta)
+	}
+	stats.DeltasOut = len(out)
+
+	return &CompositeDelta{baseDelta: d.baseDelta, Deltas: out}, stats
+}
+
+// flattenDeltas appends the leaf deltas of deltas to dst in apply order.
+func flattenDeltas(deltas []Delta, dst []Delta) []Delta {
+	for _, delta := range deltas {
+		if c, ok := delta.(*CompositeDelta); ok {
+			dst = flattenDeltas(c.Deltas, dst)
+			continue
+		}
+		dst = append(dst, delta)
+	}
+	return dst
+}
+
+// coalescible reports whether deltas of this type can be folded together.
+func coalescible(delta Delta) bool {
+	switch delta.(type) {
+	case *ProofDelta, *SimilarityDelta, *StreamingDelta, *HistoryDelta:
+		return true
+	default:
+		return false
+	}
+}
+
+// coalesceTarget returns the index in out that delta can fold into, or -1.
+// Searching stops at the first delta touching an index delta also touches.
+func coalesceTarget(out []Delta, delta Delta) int {
+	if !coalescible(delta) {
+		return -1
+	}
+	mask := IndexMaskFromDelta(delta)
+	for i := len(out) - 1; i >= 0; i-- {
+		prev := out[i]
+		if prev.Type() == delta.Type() && prev.Source() == delta.Source() {
+			return i
+		}
+		if IndexMaskFromDelta(prev).Has(mask) {
+			return -1
+		}
+	}
+	return -1
+}
+
+// cloneCoalescible copies a coalescible delta so folding never mutates the
+// caller's deltas.
+func cloneCoalescible(delta Delta) Delta {
+	switch d := delta.(type) {
+	case *ProofDelta:
+		return &ProofDelta{baseDelta: d.baseDelta, Updates: maps.Clone(d.Updates)}
+	case *SimilarityDelta:
+		return &SimilarityDelta{baseDelta: d.baseDelta, Updates: maps.Clone(d.Updates)}
+	case *StreamingDelta:
+		return &StreamingDelta{
+			baseDelta:        d.baseDelta,
+			Increments:       maps.Clone(d.Increments),
+			CardinalityItems: slices.Clone(d.CardinalityItems),
+		}
+	case *HistoryDelta:
+		return &HistoryDelta{baseDelta: d.baseDelta, Entries: slices.Clone(d.Entries)}
+	default:
+		return delta
+	}
+}
+
+// foldDelta folds src into dst, which must be an owned delta of the same type.
+func foldDelta(dst, src Delta, stats *CompressionStats) {
+	switch d := dst.(type) {
+	case *ProofDelta:
+		s := src.(*ProofDelta)
+		if d.Updates == nil {
+			d.Updates = make(map[string]ProofNumber, len(s.Updates))
+		}
+		for nodeID, proof := range s.Updates {
+			if _, ok := d.Updates[nodeID]; ok {
+				stats.SupersededProofs++
+			}
+			d.Updates[nodeID] = proof
+		}
+	case *SimilarityDelta:
+		s := src.(*SimilarityDelta)
+		if d.Updates == nil {
+			d.Updates = make(map[[2]string]float64, len(s.Updates))
+		}
+		for pair, dist := range s.Updates {
+			if _, ok := d.Updates[pair]; ok {
+				stats.SupersededSimilarities++
+			}
+			d.Updates[pair] = dist
+		}
+	case *StreamingDelta:
+		s := src.(*StreamingDelta)
+		if d.Increments == nil {
+			d.Increments = make(map[string]uint64, len(s.Increments))
+		}
+		for item, inc := range s.Increments {
+			d.Increments[item] += inc
+		}
+		d.CardinalityItems = append(d.CardinalityItems, s.CardinalityItems...)
+	case *HistoryDelta:
+		d.Entries = append(d.Entries, src.(*HistoryDelta).Entries...)
+	}
+}
+
+// deltaUpdateCount returns the number of individual updates in a leaf delta.
+func deltaUpdateCount(delta Delta) int {
+	switch d := delta.(type) {
+	case *ProofDelta:
+		return len(d.Updates)
+	case *SimilarityDelta:
+		return len(d.Updates)
+	case *StreamingDelta:
+		return len(d.Increments) + len(d.CardinalityItems)
+	case *HistoryDelta:
+		return len(d.Entries)
+	case *ConstraintDelta:
+		return len(d.Add) + len(d.Remove) + len(d.Update)
+	case *DependencyDelta:
+		return len(d.AddEdges) + len(d.RemoveEdges)
+	default:
+		return 1
+	}
+}
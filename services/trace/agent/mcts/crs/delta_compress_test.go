@@ -0,0 +1,156 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// compressionBatch builds a batch with redundant updates across many
+// small algorithm deltas.
+func compressionBatch() []Delta {
+	var deltas []Delta
+	for i := range 50 {
+		node := fmt.Sprintf("node-%d", i%5)
+		deltas = append(deltas, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			node: {Proof: uint64(i), Disproof: uint64(50 - i), Status: ProofStatusExpanded},
+		}))
+
+		streaming := NewStreamingDelta(SignalSourceSoft)
+		streaming.Increments[node] = 1
+		streaming.CardinalityItems = append(streaming.CardinalityItems, node)
+		deltas = append(deltas, streaming)
+
+		if i%10 == 0 {
+			sim := NewSimilarityDelta(SignalSourceHard)
+			sim.Updates[[2]string{"a", "b"}] = float64(i) / 100
+			deltas = append(deltas, sim)
+
+			constraint := NewConstraintDelta(SignalSourceHard)
+			constraint.Add = []Constraint{{
+				ID: fmt.Sprintf("c%d", i), Type: ConstraintTypeMutualExclusion,
+				Nodes: []string{node, "other"}, Active: true,
+			}}
+			deltas = append(deltas, constraint)
+
+			deltas = append(deltas, NewHistoryDelta(SignalSourceHard, []HistoryEntry{
+				{ID: fmt.Sprintf("h%d", i), NodeID: node, Action: "expand"},
+			}))
+		}
+	}
+	// A soft proof update is never folded into the hard ones.
+	deltas = append(deltas, NewProofDelta(SignalSourceSoft, map[string]ProofNumber{
+		"node-0": {Proof: 7, Disproof: 7, Status: ProofStatusUnknown},
+	}))
+	return deltas
+}
+
+func TestCompressDelta_MatchesSequentialApply(t *testing.T) {
+	ctx := context.Background()
+	batch := compressionBatch()
+
+	sequential := New(nil)
+	for _, d := range batch {
+		if _, err := sequential.Apply(ctx, d); err != nil {
+			t.Fatalf("sequential Apply: %v", err)
+		}
+	}
+
+	batched := New(nil)
+	metrics, err := batched.Apply(ctx, NewCompositeDelta(NewCompositeDelta(batch[:40]...), NewCompositeDelta(batch[40:]...)))
+	if err != nil {
+		t.Fatalf("batched Apply: %v", err)
+	}
+
+	if d := Diff(sequential.Snapshot(), batched.Snapshot()); !d.IsEmpty() {
+		t.Errorf("compressed apply diverged from sequential apply:\n%s", d)
+	}
+	seqTop := sequential.Snapshot().StreamingIndex().TopK(5)
+	if got := batched.Snapshot().StreamingIndex().TopK(5); !slices.Equal(got, seqTop) {
+		t.Errorf("TopK = %v, want %v", got, seqTop)
+	}
+	if got := batched.Snapshot().ProofIndex().All()["node-0"]; got.Status != ProofStatusUnknown {
+		t.Errorf("node-0 = %+v, want the final soft update", got)
+	}
+
+	cs := metrics.Compression
+	if cs == nil {
+		t.Fatal("Compression stats missing for composite delta")
+	}
+	if cs.DeltasIn != len(batch) {
+		t.Errorf("DeltasIn = %d, want %d", cs.DeltasIn, len(batch))
+	}
+	if cs.SupersededProofs != 45 {
+		t.Errorf("SupersededProofs = %d, want 45", cs.SupersededProofs)
+	}
+	if cs.SupersededSimilarities != 4 {
+		t.Errorf("SupersededSimilarities = %d, want 4", cs.SupersededSimilarities)
+	}
+	if cs.DeltasOut >= cs.DeltasIn || cs.UpdatesOut >= cs.UpdatesIn || cs.Ratio() >= 0.8 {
+		t.Errorf("weak compression: %+v (ratio %.2f)", *cs, cs.Ratio())
+	}
+	if metrics.EntriesModified != cs.UpdatesOut {
+		t.Errorf("EntriesModified = %d, want %d", metrics.EntriesModified, cs.UpdatesOut)
+	}
+}
+
+func TestCompressDelta(t *testing.T) {
+	t.Run("does not modify input", func(t *testing.T) {
+		first := NewProofDelta(SignalSourceHard, map[string]ProofNumber{"a": {Proof: 1}})
+		second := NewProofDelta(SignalSourceHard, map[string]ProofNumber{"a": {Proof: 2}, "b": {Proof: 3}})
+		out, stats := CompressDelta(NewCompositeDelta(first, second))
+
+		if len(first.Updates) != 1 || first.Updates["a"].Proof != 1 {
+			t.Errorf("input delta modified: %v", first.Updates)
+		}
+		if len(out.Deltas) != 1 || stats.SupersededProofs != 1 || stats.UpdatesOut != 2 {
+			t.Errorf("out = %d deltas, stats = %+v", len(out.Deltas), stats)
+		}
+	})
+
+	t.Run("analytics delta is a barrier for proofs", func(t *testing.T) {
+		record := &AnalyticsRecord{ID: "r1", QueryType: AnalyticsQueryHotspots}
+		out, stats := CompressDelta(NewCompositeDelta(
+			NewProofDelta(SignalSourceHard, map[string]ProofNumber{"x": {Proof: 1}}),
+			NewAnalyticsDelta(SignalSourceHard, record),
+			NewProofDelta(SignalSourceHard, map[string]ProofNumber{"x": {Proof: 2}}),
+		))
+		if len(out.Deltas) != 3 || stats.SupersededProofs != 0 {
+			t.Errorf("folded across analytics delta: %d deltas, %+v", len(out.Deltas), stats)
+		}
+	})
+
+	t.Run("superseded soft disproof is still rejected", func(t *testing.T) {
+		c := New(nil)
+		_, err := c.Apply(context.Background(), NewCompositeDelta(
+			NewProofDelta(SignalSourceSoft, map[string]ProofNumber{"x": {Status: ProofStatusDisproven}}),
+			NewProofDelta(SignalSourceSoft, map[string]ProofNumber{"x": {Status: ProofStatusExpanded}}),
+		))
+		if !errors.Is(err, ErrHardSoftBoundaryViolation) {
+			t.Errorf("Apply error = %v, want ErrHardSoftBoundaryViolation", err)
+		}
+	})
+
+	t.Run("non-composite apply has no stats", func(t *testing.T) {
+		c := New(nil)
+		metrics, err := c.Apply(context.Background(), NewProofDelta(SignalSourceHard, map[string]ProofNumber{"x": {}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metrics.Compression != nil {
+			t.Errorf("Compression = %+v, want nil", metrics.Compression)
+		}
+	})
+}
@@ -0,0 +1,618 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Delta Journal
+// -----------------------------------------------------------------------------
+//
+// The delta journal is an append-only audit WAL: every successful Apply()
+// appends the delta tagged with the generation it produced. Unlike the
+// BadgerJournal (which is truncated at checkpoints and replayed on session
+// restore), it is never truncated, so any historical generation can be
+// reconstructed with ReplayJournal.
+//
+// File format: a sequence of frames
+//
+//	length   uvarint  payload length
+//	payload  []byte   gob-encoded deltaJournalEntry
+//	checksum [4]byte  big-endian CRC-32 (IEEE) of payload
+//
+// A torn final frame (crash mid-append) is truncated when the journal is
+// opened. A checksum mismatch anywhere else is reported as corruption.
+
+var (
+	// ErrDeltaJournalGeneration is returned when a delta is appended with a
+	// generation that is not greater than the last journaled generation.
+	ErrDeltaJournalGeneration = errors.New("delta journal generation must increase")
+
+	// ErrNoDeltaJournal is returned by ReplayJournal when no journal is attached.
+	ErrNoDeltaJournal = errors.New("no delta journal attached")
+
+	// ErrInvalidGenerationRange is returned for an empty or negative replay range.
+	ErrInvalidGenerationRange = errors.New("invalid generation range")
+)
+
+// maxDeltaJournalFrame bounds a single frame so a corrupted length prefix
+// cannot trigger a huge allocation.
+const maxDeltaJournalFrame = 64 << 20
+
+// DeltaJournalConfig configures a DeltaJournal.
+type DeltaJournalConfig struct {
+	// Path is the journal file. Parent directories are created if needed.
+	// Required.
+	Path string
+
+	// SyncWrites fsyncs after every append. Slower, but no journaled delta
+	// is lost on power failure. Default: false.
+	SyncWrites bool
+
+	// Logger for journal events. Default: slog.Default().
+	Logger *slog.Logger
+}
+
+// Validate checks the configuration.
+func (c *DeltaJournalConfig) Validate() error {
+	if c.Path == "" {
+		return errors.New("path is required")
+	}
+	return nil
+}
+
+// DeltaJournalEntry is one journaled delta.
+type DeltaJournalEntry struct {
+	// Generation is the CRS generation produced by applying Delta.
+	Generation int64
+
+	// SessionID is the CRS session ID at the time of the apply.
+	SessionID string
+
+	// Delta is the applied delta, with its signal source and timestamp.
+	Delta Delta
+}
+
+// deltaJournalEntry is the encoded form of DeltaJournalEntry.
+//
+// gob skips the unexported baseDelta fields, so the signal source and
+// timestamp of the delta (and of each nested delta, in pre-order) are
+// stored alongside it and restored on decode.
+type deltaJournalEntry struct {
+	Generation int64
+	SessionID  string
+	Sources    []SignalSource
+	Timestamps []int64
+	Delta      Delta
+}
+
+// DeltaJournal is an append-only, file-backed WAL of applied deltas.
+//
+// Thread Safety: Safe for concurrent use.
+type DeltaJournal struct {
+	config DeltaJournalConfig
+	logger *slog.Logger
+
+	mu             sync.Mutex
+	file           *os.File
+	size           int64
+	lastGeneration int64
+	closed         bool
+}
+
+// OpenDeltaJournal opens or creates a delta journal.
+//
+// Description:
+//
+//	Scans existing frames to find the last journaled generation and
+//	truncates a torn final frame left by a crash.
+//
+// Inputs:
+//   - config: Journal configuration. Must pass Validate().
+//
+// Outputs:
+//   - *DeltaJournal: The open journal.
+//   - error: Non-nil if the file cannot be opened or is corrupted.
+//
+// Thread Safety: Safe for concurrent use.
+func OpenDeltaJournal(config DeltaJournalConfig) (*DeltaJournal, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0750); err != nil {
+		return nil, fmt.Errorf("create journal dir: %w", err)
+	}
+	file, err := os.OpenFile(config.Path, os.O_RDWR|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+
+	j := &DeltaJournal{
+		config: config,
+		file:   file,
+		logger: config.Logger.With(slog.String("component", "delta_journal")),
+	}
+
+	validSize, err := j.scan(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("stat journal: %w", err)
+	}
+	if info.Size() > validSize {
+		j.logger.Warn("truncating torn delta journal tail",
+			slog.String("path", config.Path),
+			slog.Int64("valid_bytes", validSize),
+			slog.Int64("file_bytes", info.Size()),
+		)
+		if err := file.Truncate(validSize); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("truncate torn tail: %w", err)
+		}
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("seek journal end: %w", err)
+	}
+	j.size = validSize
+
+	return j, nil
+}
+
+// scan reads all frames to find the last generation and the end of the
+// last complete frame.
+func (j *DeltaJournal) scan(r io.Reader) (int64, error) {
+	var offset int64
+	err := readDeltaJournalFrames(r, -1, func(entry *deltaJournalEntry, end int64) bool {
+		j.lastGeneration = entry.Generation
+		offset = end
+		return true
+	})
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// Append journals a delta.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - generation: The generation produced by the delta. Must be greater
+//     than LastGeneration().
+//   - sessionID: The CRS session ID (may be empty).
+//   - delta: The applied delta. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if the delta could not be encoded or written.
+//
+// Thread Safety: Safe for concurrent use.
+func (j *DeltaJournal) Append(ctx context.Context, generation int64, sessionID string, delta Delta) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if delta == nil {
+		return ErrNilDeltaJournal
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	frame, err := encodeDeltaJournalFrame(generation, sessionID, delta)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrJournalClosed
+	}
+	if generation <= j.lastGeneration {
+		return fmt.Errorf("%w: got %d, last %d", ErrDeltaJournalGeneration, generation, j.lastGeneration)
+	}
+
+	if _, err := j.file.Write(frame); err != nil {
+		// Drop any partial frame so later appends stay readable.
+		if terr := j.file.Truncate(j.size); terr == nil {
+			_, _ = j.file.Seek(j.size, io.SeekStart)
+		}
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if j.config.SyncWrites {
+		if err := j.file.Sync(); err != nil {
+			return fmt.Errorf("sync journal: %w", err)
+		}
+	}
+
+	j.size += int64(len(frame))
+	j.lastGeneration = generation
+	return nil
+}
+
+// Read returns the journaled entries with from <= Generation <= to, in
+// generation order.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - from: First generation to return.
+//   - to: Last generation to return.
+//
+// Outputs:
+//   - []DeltaJournalEntry: Matching entries. Empty if none.
+//   - error: Non-nil if the journal is closed or corrupted.
+//
+// Thread Safety: Safe for concurrent use with Append.
+func (j *DeltaJournal) Read(ctx context.Context, from, to int64) ([]DeltaJournalEntry, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	j.mu.Lock()
+	if j.closed {
+		j.mu.Unlock()
+		return nil, ErrJournalClosed
+	}
+	size := j.size
+	j.mu.Unlock()
+
+	file, err := os.Open(j.config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []DeltaJournalEntry
+	var ctxErr error
+	err = readDeltaJournalFrames(io.LimitReader(file, size), size, func(e *deltaJournalEntry, _ int64) bool {
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			return false
+		}
+		if e.Generation > to {
+			return false
+		}
+		if e.Generation >= from {
+			entries = append(entries, DeltaJournalEntry{
+				Generation: e.Generation,
+				SessionID:  e.SessionID,
+				Delta:      e.Delta,
+			})
+		}
+		return true
+	})
+	if ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// LastGeneration returns the generation of the last journaled delta, or 0.
+//
+// Thread Safety: Safe for concurrent use.
+func (j *DeltaJournal) LastGeneration() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.lastGeneration
+}
+
+// Path returns the journal file path.
+func (j *DeltaJournal) Path() string {
+	return j.config.Path
+}
+
+// Sync flushes the journal to disk.
+//
+// Thread Safety: Safe for concurrent use.
+func (j *DeltaJournal) Sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return ErrJournalClosed
+	}
+	return j.file.Sync()
+}
+
+// Close syncs and closes the journal. Safe to call more than once.
+//
+// Thread Safety: Safe for concurrent use.
+func (j *DeltaJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return nil
+	}
+	j.closed = true
+	if err := j.file.Sync(); err != nil {
+		j.file.Close()
+		return fmt.Errorf("sync journal: %w", err)
+	}
+	return j.file.Close()
+}
+
+// -----------------------------------------------------------------------------
+// CRS Integration
+// -----------------------------------------------------------------------------
+
+// SetDeltaJournal attaches an audit journal. Every subsequent successful
+// Apply() appends its delta. Pass nil to detach.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *crsImpl) SetDeltaJournal(journal *DeltaJournal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deltaJournal = journal
+}
+
+// journalDelta appends an applied delta to the attached journal.
+// Journal failures are logged but do not fail the apply, which has already
+// taken effect. Caller must hold c.mu.
+func (c *crsImpl) journalDelta(ctx context.Context, span trace.Span, generation int64, delta Delta) {
+	if c.deltaJournal == nil {
+		return
+	}
+	if err := c.deltaJournal.Append(context.WithoutCancel(ctx), generation, c.currentSessionID, delta); err != nil {
+		span.RecordError(err)
+		c.logger.Warn("delta journal append failed",
+			slog.Int64("generation", generation),
+			slog.String("type", delta.Type().String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// ReplayJournal reconstructs a historical state from the delta journal.
+//
+// Description:
+//
+//	Creates a new, detached CRS and applies the journaled deltas for
+//	generations from..to in order. Replaying from generation 1 reproduces
+//	the exact state at generation to; replaying from a later generation
+//	yields only the effects of that range, which is useful for isolating
+//	what a stretch of a session changed. The returned CRS has no journal
+//	attached, and its generation equals to on success.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - from: First generation to replay. Must be >= 1.
+//   - to: Last generation to replay. Must be >= from.
+//
+// Outputs:
+//   - CRS: The reconstructed state.
+//   - error: ErrNoDeltaJournal if no journal is attached,
+//     ErrInvalidGenerationRange for a bad range, ErrJournalSequenceGap if
+//     any generation in the range is missing, or the apply error of the
+//     first delta that fails to replay.
+//
+// Thread Safety: Safe for concurrent use. Does not modify this CRS.
+func (c *crsImpl) ReplayJournal(ctx context.Context, from, to int64) (CRS, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if from < 1 || to < from {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrInvalidGenerationRange, from, to)
+	}
+
+	c.mu.RLock()
+	journal := c.deltaJournal
+	c.mu.RUnlock()
+	if journal == nil {
+		return nil, ErrNoDeltaJournal
+	}
+
+	ctx, span := otel.Tracer("crs").Start(ctx, "crs.ReplayJournal",
+		trace.WithAttributes(
+			attribute.Int64("from", from),
+			attribute.Int64("to", to),
+		),
+	)
+	defer span.End()
+
+	entries, err := journal.Read(ctx, from, to)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read journal failed")
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+
+	replay := New(c.config).(*crsImpl)
+	replay.generation.Store(from - 1)
+
+	for _, entry := range entries {
+		if want := replay.generation.Load() + 1; entry.Generation != want {
+			err := fmt.Errorf("%w: expected generation %d, got %d", ErrJournalSequenceGap, want, entry.Generation)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "journal gap")
+			return nil, err
+		}
+		if _, err := replay.Apply(ctx, entry.Delta); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "replay apply failed")
+			return nil, fmt.Errorf("replay generation %d: %w", entry.Generation, err)
+		}
+	}
+	if got := replay.generation.Load(); got != to {
+		err := fmt.Errorf("%w: journal ends at generation %d, want %d", ErrJournalSequenceGap, got, to)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "journal gap")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("deltas_replayed", len(entries)))
+	loggerWithTrace(ctx, c.logger).Debug("journal replayed",
+		slog.Int64("from", from),
+		slog.Int64("to", to),
+		slog.Int("deltas", len(entries)),
+	)
+
+	return replay, nil
+}
+
+// -----------------------------------------------------------------------------
+// Frame Encoding
+// -----------------------------------------------------------------------------
+
+// deltaBase is implemented by every delta type via the embedded baseDelta.
+type deltaBase interface {
+	base() *baseDelta
+}
+
+func (d *baseDelta) base() *baseDelta {
+	return d
+}
+
+// walkDeltaBases visits the baseDelta of d and of each nested delta in
+// pre-order.
+func walkDeltaBases(d Delta, visit func(*baseDelta)) {
+	if b, ok := d.(deltaBase); ok {
+		visit(b.base())
+	}
+	if comp, ok := d.(*CompositeDelta); ok {
+		for _, nested := range comp.Deltas {
+			walkDeltaBases(nested, visit)
+		}
+	}
+}
+
+var deltaJournalTypesRegistered sync.Once
+
+// registerDeltaJournalTypes registers delta types for gob, including the
+// analytics delta which the BadgerJournal does not journal.
+func registerDeltaJournalTypes() {
+	registerDeltaTypes()
+	deltaJournalTypesRegistered.Do(func() {
+		gob.Register(&AnalyticsDelta{})
+	})
+}
+
+// encodeDeltaJournalFrame encodes one frame.
+func encodeDeltaJournalFrame(generation int64, sessionID string, delta Delta) ([]byte, error) {
+	registerDeltaJournalTypes()
+
+	entry := deltaJournalEntry{
+		Generation: generation,
+		SessionID:  sessionID,
+		Delta:      delta,
+	}
+	walkDeltaBases(delta, func(b *baseDelta) {
+		entry.Sources = append(entry.Sources, b.source)
+		entry.Timestamps = append(entry.Timestamps, b.timestamp)
+	})
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(&entry); err != nil {
+		return nil, fmt.Errorf("gob encode: %w", err)
+	}
+
+	var frame bytes.Buffer
+	var lenBuf [binary.MaxVarintLen64]byte
+	frame.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(payload.Len()))])
+	frame.Write(payload.Bytes())
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(payload.Bytes()))
+	frame.Write(sum[:])
+
+	return frame.Bytes(), nil
+}
+
+// readDeltaJournalFrames decodes frames from r and calls fn with each
+// entry and the offset just past its frame, until fn returns false or the
+// input ends. An incomplete final frame yields io.ErrUnexpectedEOF; a
+// checksum mismatch on a complete frame yields ErrJournalCorrupted.
+// If limit >= 0 it is the number of bytes r will provide.
+func readDeltaJournalFrames(r io.Reader, limit int64, fn func(*deltaJournalEntry, int64) bool) error {
+	registerDeltaJournalTypes()
+
+	br := bufio.NewReader(r)
+	var offset int64
+	for {
+		length, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		if length > maxDeltaJournalFrame || (limit >= 0 && int64(length) > limit-offset) {
+			// A length running past the end is a torn tail; beyond the
+			// frame bound it is corruption.
+			if length > maxDeltaJournalFrame {
+				return fmt.Errorf("%w: frame length %d at offset %d", ErrJournalCorrupted, length, offset)
+			}
+			return io.ErrUnexpectedEOF
+		}
+
+		payload := make([]byte, length+4)
+		if _, err := io.ReadFull(br, payload); err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		sum := binary.BigEndian.Uint32(payload[length:])
+		payload = payload[:length]
+		if crc32.ChecksumIEEE(payload) != sum {
+			return fmt.Errorf("%w: frame at offset %d", ErrJournalCorrupted, offset)
+		}
+
+		var entry deltaJournalEntry
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&entry); err != nil {
+			return fmt.Errorf("%w: frame at offset %d: %v", ErrJournalCorrupted, offset, err)
+		}
+		i := 0
+		walkDeltaBases(entry.Delta, func(b *baseDelta) {
+			if i < len(entry.Sources) {
+				b.source = entry.Sources[i]
+				b.timestamp = entry.Timestamps[i]
+			}
+			i++
+		})
+
+		offset += int64(uvarintLen(length)) + int64(length) + 4
+		if !fn(&entry, offset) {
+			return nil
+		}
+	}
+}
+
+// uvarintLen returns the encoded size of v.
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestDeltaJournal(t *testing.T, path string) *DeltaJournal {
+	t.Helper()
+	j, err := OpenDeltaJournal(DeltaJournalConfig{Path: path})
+	if err != nil {
+		t.Fatalf("OpenDeltaJournal: %v", err)
+	}
+	t.Cleanup(func() { j.Close() })
+	return j
+}
+
+// applyJournaledProofs applies one proof delta per node, in order.
+func applyJournaledProofs(t *testing.T, c CRS, nodes ...string) {
+	t.Helper()
+	for i, node := range nodes {
+		_, err := c.Apply(context.Background(), NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			node: {Proof: uint64(i + 1), Status: ProofStatusDisproven},
+		}))
+		if err != nil {
+			t.Fatalf("Apply(%s): %v", node, err)
+		}
+	}
+}
+
+func TestDeltaJournal_ReplayJournal(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reconstructs historical generations", func(t *testing.T) {
+		c := New(nil)
+		c.SetDeltaJournal(openTestDeltaJournal(t, filepath.Join(t.TempDir(), "deltas.wal")))
+		applyJournaledProofs(t, c, "a", "b", "c")
+
+		past, err := c.ReplayJournal(ctx, 1, 2)
+		if err != nil {
+			t.Fatalf("ReplayJournal: %v", err)
+		}
+		if past.Generation() != 2 {
+			t.Errorf("generation = %d, want 2", past.Generation())
+		}
+		idx := past.Snapshot().ProofIndex()
+		if _, ok := idx.Get("b"); !ok {
+			t.Error("node b missing at generation 2")
+		}
+		if _, ok := idx.Get("c"); ok {
+			t.Error("node c present at generation 2")
+		}
+		// Disproven status requires a hard source, so this also checks the
+		// signal source survives the round trip.
+		if pn, _ := idx.Get("a"); pn.Status != ProofStatusDisproven {
+			t.Errorf("node a status = %v, want disproven", pn.Status)
+		}
+	})
+
+	t.Run("replays a sub-range", func(t *testing.T) {
+		c := New(nil)
+		c.SetDeltaJournal(openTestDeltaJournal(t, filepath.Join(t.TempDir(), "deltas.wal")))
+		applyJournaledProofs(t, c, "a", "b", "c")
+
+		part, err := c.ReplayJournal(ctx, 2, 3)
+		if err != nil {
+			t.Fatalf("ReplayJournal: %v", err)
+		}
+		if got := part.Snapshot().ProofIndex().Size(); got != 2 {
+			t.Errorf("proof entries = %d, want 2", got)
+		}
+		if part.Generation() != 3 {
+			t.Errorf("generation = %d, want 3", part.Generation())
+		}
+	})
+
+	t.Run("range past the journal end is a gap", func(t *testing.T) {
+		c := New(nil)
+		c.SetDeltaJournal(openTestDeltaJournal(t, filepath.Join(t.TempDir(), "deltas.wal")))
+		applyJournaledProofs(t, c, "a")
+
+		if _, err := c.ReplayJournal(ctx, 1, 5); !errors.Is(err, ErrJournalSequenceGap) {
+			t.Errorf("error = %v, want ErrJournalSequenceGap", err)
+		}
+	})
+
+	t.Run("invalid range and missing journal", func(t *testing.T) {
+		c := New(nil)
+		if _, err := c.ReplayJournal(ctx, 1, 1); !errors.Is(err, ErrNoDeltaJournal) {
+			t.Errorf("error = %v, want ErrNoDeltaJournal", err)
+		}
+		if _, err := c.ReplayJournal(ctx, 3, 2); !errors.Is(err, ErrInvalidGenerationRange) {
+			t.Errorf("error = %v, want ErrInvalidGenerationRange", err)
+		}
+	})
+}
+
+func TestDeltaJournal_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "deltas.wal")
+
+	j, err := OpenDeltaJournal(DeltaJournalConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for gen := int64(1); gen <= 3; gen++ {
+		delta := NewProofDelta(SignalSourceSoft, map[string]ProofNumber{"n": {Proof: uint64(gen)}})
+		if err := j.Append(ctx, gen, "s1", delta); err != nil {
+			t.Fatalf("Append(%d): %v", gen, err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash mid-append.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0x40, 0x01, 0x02})
+	f.Close()
+
+	j = openTestDeltaJournal(t, path)
+	if got := j.LastGeneration(); got != 3 {
+		t.Fatalf("LastGeneration = %d, want 3", got)
+	}
+
+	delta := NewProofDelta(SignalSourceSoft, map[string]ProofNumber{"n": {Proof: 9}})
+	if err := j.Append(ctx, 3, "s1", delta); !errors.Is(err, ErrDeltaJournalGeneration) {
+		t.Errorf("Append(3) error = %v, want ErrDeltaJournalGeneration", err)
+	}
+	if err := j.Append(ctx, 4, "s1", delta); err != nil {
+		t.Fatalf("Append(4): %v", err)
+	}
+
+	entries, err := j.Read(ctx, 2, 4)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Generation != 2 || entries[2].Generation != 4 {
+		t.Fatalf("entries = %+v, want generations 2..4", entries)
+	}
+	if entries[0].SessionID != "s1" || entries[0].Delta.Source() != SignalSourceSoft {
+		t.Errorf("entry metadata not preserved: %+v", entries[0])
+	}
+}
@@ -0,0 +1,500 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Snapshot Diff
+// -----------------------------------------------------------------------------
+
+// ChangeKind describes how an index entry differs between two snapshots.
+type ChangeKind int
+
+const (
+	// ChangeAdded means the entry exists only in the newer snapshot.
+	ChangeAdded ChangeKind = iota + 1
+
+	// ChangeRemoved means the entry exists only in the older snapshot.
+	ChangeRemoved
+
+	// ChangeModified means the entry exists in both with different values.
+	ChangeModified
+)
+
+// String returns the string representation of ChangeKind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", k)
+	}
+}
+
+// symbol returns the diff marker for the kind.
+func (k ChangeKind) symbol() string {
+	switch k {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// ProofChange is a proof number that differs between snapshots.
+type ProofChange struct {
+	NodeID string
+	Kind   ChangeKind
+	Before ProofNumber // Zero for ChangeAdded
+	After  ProofNumber // Zero for ChangeRemoved
+}
+
+// ConstraintChange is a constraint that differs between snapshots.
+type ConstraintChange struct {
+	ID     string
+	Kind   ChangeKind
+	Before Constraint // Zero for ChangeAdded
+	After  Constraint // Zero for ChangeRemoved
+}
+
+// ClauseChange is a learned clause added or removed between snapshots.
+// Clauses are immutable once learned, so they are never ChangeModified.
+type ClauseChange struct {
+	ID     string
+	Kind   ChangeKind
+	Clause *Clause
+}
+
+// SimilarityChange is a similarity distance that differs between snapshots.
+// Pairs are reported in the direction they are stored.
+type SimilarityChange struct {
+	Node1  string
+	Node2  string
+	Kind   ChangeKind
+	Before float64 // Zero for ChangeAdded
+	After  float64 // Zero for ChangeRemoved
+}
+
+// DependencyChange is a dependency edge added or removed between snapshots.
+type DependencyChange struct {
+	From string
+	To   string
+	Kind ChangeKind
+}
+
+// HistoryDiff summarizes history changes between snapshots.
+type HistoryDiff struct {
+	// Added are entries present only in the newer snapshot, in order.
+	Added []HistoryEntry
+
+	// Evicted is the number of entries present only in the older snapshot,
+	// normally dropped by the history size limit.
+	Evicted int
+}
+
+// StreamingDiff summarizes streaming statistics changes between snapshots.
+// Individual item counts cannot be enumerated from the sketch.
+type StreamingDiff struct {
+	CardinalityBefore uint64
+	CardinalityAfter  uint64
+	SizeBefore        int
+	SizeAfter         int
+}
+
+// changed reports whether the streaming statistics differ.
+func (d StreamingDiff) changed() bool {
+	return d.CardinalityBefore != d.CardinalityAfter || d.SizeBefore != d.SizeAfter
+}
+
+// SnapshotDiff is a structured diff of every index between two snapshots.
+//
+// Description:
+//
+//	Produced by Diff. Each slice is sorted by key so diffs compare equal
+//	across runs, which lets tests assert the exact effect of a delta.
+//	String renders the diff for display, e.g. to tell the agent what
+//	changed since it last looked.
+type SnapshotDiff struct {
+	// FromGeneration and ToGeneration are the generations compared.
+	FromGeneration int64
+	ToGeneration   int64
+
+	Proofs       []ProofChange
+	Constraints  []ConstraintChange
+	Clauses      []ClauseChange
+	Similarities []SimilarityChange
+	Dependencies []DependencyChange
+	History      HistoryDiff
+	Streaming    StreamingDiff
+}
+
+// Diff compares two snapshots.
+//
+// Description:
+//
+//	Reports every proof number, constraint, learned clause, similarity
+//	distance, dependency edge, and history entry that differs from a to b,
+//	plus changes to the streaming statistics. Either snapshot may be from
+//	any generation; a is treated as "before" and b as "after".
+//
+// Inputs:
+//   - a: The earlier snapshot. Nil is treated as empty.
+//   - b: The later snapshot. Nil is treated as empty.
+//
+// Outputs:
+//   - *SnapshotDiff: The differences. Never nil.
+//
+// Thread Safety: Safe for concurrent use (snapshots are immutable).
+func Diff(a, b Snapshot) *SnapshotDiff {
+	d := &SnapshotDiff{}
+	if a != nil {
+		d.FromGeneration = a.Generation()
+	}
+	if b != nil {
+		d.ToGeneration = b.Generation()
+	}
+
+	d.Proofs = diffProofs(proofsOf(a), proofsOf(b))
+	d.Constraints = diffConstraints(constraintsOf(a), constraintsOf(b))
+	d.Clauses = diffClauses(clausesOf(a), clausesOf(b))
+	d.Similarities = diffSimilarities(similaritiesOf(a), similaritiesOf(b))
+	d.Dependencies = diffDependencies(dependenciesOf(a), dependenciesOf(b))
+	d.History = diffHistory(historyOf(a), historyOf(b))
+	if a != nil {
+		d.Streaming.CardinalityBefore = a.StreamingIndex().Cardinality()
+		d.Streaming.SizeBefore = a.StreamingIndex().Size()
+	}
+	if b != nil {
+		d.Streaming.CardinalityAfter = b.StreamingIndex().Cardinality()
+		d.Streaming.SizeAfter = b.StreamingIndex().Size()
+	}
+	return d
+}
+
+// IsEmpty returns true if the snapshots have identical index contents.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.Proofs) == 0 &&
+		len(d.Constraints) == 0 &&
+		len(d.Clauses) == 0 &&
+		len(d.Similarities) == 0 &&
+		len(d.Dependencies) == 0 &&
+		len(d.History.Added) == 0 &&
+		d.History.Evicted == 0 &&
+		!d.Streaming.changed()
+}
+
+// Summary returns a one-line count of changes per index.
+func (d *SnapshotDiff) Summary() string {
+	if d.IsEmpty() {
+		return fmt.Sprintf("generation %d -> %d: no changes", d.FromGeneration, d.ToGeneration)
+	}
+	var parts []string
+	add := func(n int, what string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, what))
+		}
+	}
+	add(len(d.Proofs), "proof")
+	add(len(d.Constraints), "constraint")
+	add(len(d.Clauses), "clause")
+	add(len(d.Similarities), "similarity")
+	add(len(d.Dependencies), "dependency")
+	add(len(d.History.Added), "history")
+	if d.Streaming.changed() {
+		parts = append(parts, "streaming")
+	}
+	return fmt.Sprintf("generation %d -> %d: %s", d.FromGeneration, d.ToGeneration, strings.Join(parts, ", "))
+}
+
+// String renders the diff as human-readable text, one change per line,
+// grouped by index. Lines start with + (added), - (removed), or ~ (modified).
+func (d *SnapshotDiff) String() string {
+	var b strings.Builder
+	b.WriteString(d.Summary())
+	b.WriteByte('\n')
+
+	section := func(name string, n int) {
+		if n > 0 {
+			fmt.Fprintf(&b, "%s:\n", name)
+		}
+	}
+
+	section("proofs", len(d.Proofs))
+	for _, c := range d.Proofs {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "  + %s %s\n", c.NodeID, formatProof(c.After))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "  - %s %s\n", c.NodeID, formatProof(c.Before))
+		default:
+			fmt.Fprintf(&b, "  ~ %s %s -> %s\n", c.NodeID, formatProof(c.Before), formatProof(c.After))
+		}
+	}
+
+	section("constraints", len(d.Constraints))
+	for _, c := range d.Constraints {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "  + %s %s\n", c.ID, formatConstraint(c.After))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "  - %s %s\n", c.ID, formatConstraint(c.Before))
+		default:
+			fmt.Fprintf(&b, "  ~ %s %s -> %s\n", c.ID, formatConstraint(c.Before), formatConstraint(c.After))
+		}
+	}
+
+	section("clauses", len(d.Clauses))
+	for _, c := range d.Clauses {
+		fmt.Fprintf(&b, "  %s %s %s\n", c.Kind.symbol(), c.ID, c.Clause.String())
+	}
+
+	section("similarities", len(d.Similarities))
+	for _, c := range d.Similarities {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "  + %s <-> %s %.4g\n", c.Node1, c.Node2, c.After)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "  - %s <-> %s %.4g\n", c.Node1, c.Node2, c.Before)
+		default:
+			fmt.Fprintf(&b, "  ~ %s <-> %s %.4g -> %.4g\n", c.Node1, c.Node2, c.Before, c.After)
+		}
+	}
+
+	section("dependencies", len(d.Dependencies))
+	for _, c := range d.Dependencies {
+		fmt.Fprintf(&b, "  %s %s -> %s\n", c.Kind.symbol(), c.From, c.To)
+	}
+
+	section("history", len(d.History.Added)+d.History.Evicted)
+	for _, e := range d.History.Added {
+		fmt.Fprintf(&b, "  + %s %s %s", e.ID, e.NodeID, e.Action)
+		if e.Result != "" {
+			fmt.Fprintf(&b, ": %s", e.Result)
+		}
+		b.WriteByte('\n')
+	}
+	if d.History.Evicted > 0 {
+		fmt.Fprintf(&b, "  - %d older entries evicted\n", d.History.Evicted)
+	}
+
+	if d.Streaming.changed() {
+		fmt.Fprintf(&b, "streaming:\n  ~ cardinality %d -> %d, size %d -> %d\n",
+			d.Streaming.CardinalityBefore, d.Streaming.CardinalityAfter,
+			d.Streaming.SizeBefore, d.Streaming.SizeAfter)
+	}
+
+	return b.String()
+}
+
+// formatProof renders a proof number compactly.
+func formatProof(p ProofNumber) string {
+	return fmt.Sprintf("(proof=%d disproof=%d status=%s source=%s)", p.Proof, p.Disproof, p.Status, p.Source)
+}
+
+// formatConstraint renders a constraint compactly.
+func formatConstraint(c Constraint) string {
+	s := fmt.Sprintf("(%s %s active=%t", c.Type, strings.Join(c.Nodes, ","), c.Active)
+	if c.Expression != "" {
+		s += fmt.Sprintf(" expr=%q", c.Expression)
+	}
+	return s + ")"
+}
+
+// -----------------------------------------------------------------------------
+// Per-index diffs
+// -----------------------------------------------------------------------------
+
+func proofsOf(s Snapshot) map[string]ProofNumber {
+	if s == nil {
+		return nil
+	}
+	return s.ProofIndex().All()
+}
+
+func constraintsOf(s Snapshot) map[string]Constraint {
+	if s == nil {
+		return nil
+	}
+	return s.ConstraintIndex().All()
+}
+
+func clausesOf(s Snapshot) map[string]*Clause {
+	if s == nil {
+		return nil
+	}
+	return s.ConstraintIndex().AllClauses()
+}
+
+func similaritiesOf(s Snapshot) map[string]map[string]float64 {
+	if s == nil {
+		return nil
+	}
+	return s.SimilarityIndex().AllPairs()
+}
+
+func dependenciesOf(s Snapshot) map[string][]string {
+	if s == nil {
+		return nil
+	}
+	return s.DependencyIndex().AllEdges()
+}
+
+func historyOf(s Snapshot) []HistoryEntry {
+	if s == nil {
+		return nil
+	}
+	h := s.HistoryIndex()
+	return h.Recent(h.Size())
+}
+
+// diffMaps compares two keyed maps, returning changed keys in sorted order.
+func diffMaps[K comparable, V any](before, after map[K]V, compare func(a, b K) int, equal func(a, b V) bool, emit func(key K, kind ChangeKind, a, b V)) {
+	keys := make([]K, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, compare)
+
+	for _, k := range keys {
+		a, inBefore := before[k]
+		b, inAfter := after[k]
+		switch {
+		case !inBefore:
+			emit(k, ChangeAdded, a, b)
+		case !inAfter:
+			emit(k, ChangeRemoved, a, b)
+		case !equal(a, b):
+			emit(k, ChangeModified, a, b)
+		}
+	}
+}
+
+func diffProofs(before, after map[string]ProofNumber) []ProofChange {
+	var out []ProofChange
+	diffMaps(before, after, strings.Compare,
+		func(a, b ProofNumber) bool { return a == b },
+		func(id string, kind ChangeKind, a, b ProofNumber) {
+			out = append(out, ProofChange{NodeID: id, Kind: kind, Before: a, After: b})
+		})
+	return out
+}
+
+func diffConstraints(before, after map[string]Constraint) []ConstraintChange {
+	var out []ConstraintChange
+	diffMaps(before, after, strings.Compare,
+		func(a, b Constraint) bool {
+			return a.Type == b.Type && a.Expression == b.Expression && a.Active == b.Active &&
+				a.Source == b.Source && a.CreatedAt == b.CreatedAt && slices.Equal(a.Nodes, b.Nodes)
+		},
+		func(id string, kind ChangeKind, a, b Constraint) {
+			out = append(out, ConstraintChange{ID: id, Kind: kind, Before: a, After: b})
+		})
+	return out
+}
+
+func diffClauses(before, after map[string]*Clause) []ClauseChange {
+	var out []ClauseChange
+	diffMaps(before, after, strings.Compare,
+		func(a, b *Clause) bool { return true },
+		func(id string, kind ChangeKind, a, b *Clause) {
+			c := b
+			if kind == ChangeRemoved {
+				c = a
+			}
+			out = append(out, ClauseChange{ID: id, Kind: kind, Clause: c})
+		})
+	return out
+}
+
+func diffSimilarities(before, after map[string]map[string]float64) []SimilarityChange {
+	var out []SimilarityChange
+	diffMaps(flattenPairs(before), flattenPairs(after), comparePair,
+		func(a, b float64) bool { return a == b },
+		func(key [2]string, kind ChangeKind, a, b float64) {
+			out = append(out, SimilarityChange{Node1: key[0], Node2: key[1], Kind: kind, Before: a, After: b})
+		})
+	return out
+}
+
+// comparePair orders node pairs by first then second element.
+func comparePair(a, b [2]string) int {
+	return cmp.Or(strings.Compare(a[0], b[0]), strings.Compare(a[1], b[1]))
+}
+
+// flattenPairs keys each similarity by its (node1, node2) pair.
+func flattenPairs(pairs map[string]map[string]float64) map[[2]string]float64 {
+	out := make(map[[2]string]float64)
+	for n1, inner := range pairs {
+		for n2, d := range inner {
+			out[[2]string{n1, n2}] = d
+		}
+	}
+	return out
+}
+
+func diffDependencies(before, after map[string][]string) []DependencyChange {
+	var out []DependencyChange
+	diffMaps(flattenEdges(before), flattenEdges(after), comparePair,
+		func(a, b struct{}) bool { return true },
+		func(key [2]string, kind ChangeKind, _, _ struct{}) {
+			out = append(out, DependencyChange{From: key[0], To: key[1], Kind: kind})
+		})
+	return out
+}
+
+// flattenEdges keys each dependency edge by (from, to).
+func flattenEdges(edges map[string][]string) map[[2]string]struct{} {
+	out := make(map[[2]string]struct{})
+	for from, tos := range edges {
+		for _, to := range tos {
+			out[[2]string{from, to}] = struct{}{}
+		}
+	}
+	return out
+}
+
+func diffHistory(before, after []HistoryEntry) HistoryDiff {
+	inBefore := make(map[string]bool, len(before))
+	for _, e := range before {
+		inBefore[e.ID] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	var d HistoryDiff
+	for _, e := range after {
+		inAfter[e.ID] = true
+		if !inBefore[e.ID] {
+			d.Added = append(d.Added, e)
+		}
+	}
+	for _, e := range before {
+		if !inAfter[e.ID] {
+			d.Evicted++
+		}
+	}
+	return d
+}
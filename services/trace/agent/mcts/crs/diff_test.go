@@ -0,0 +1,203 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	c := New(nil)
+
+	apply := func(d Delta) {
+		t.Helper()
+		if _, err := c.Apply(ctx, d); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	}
+
+	apply(NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+		"keep":   {Proof: 1, Disproof: 1},
+		"change": {Proof: 5, Disproof: 5},
+	}))
+	constraints := NewConstraintDelta(SignalSourceHard)
+	constraints.Add = []Constraint{
+		{ID: "c1", Type: ConstraintTypeMutualExclusion, Nodes: []string{"a", "b"}, Active: true},
+		{ID: "c2", Type: ConstraintTypeImplication, Nodes: []string{"a", "c"}, Active: true},
+	}
+	apply(constraints)
+	sim := NewSimilarityDelta(SignalSourceHard)
+	sim.Updates[[2]string{"x", "y"}] = 0.5
+	apply(sim)
+	apply(NewHistoryDelta(SignalSourceHard, []HistoryEntry{{ID: "h1", NodeID: "a", Action: "expand"}}))
+
+	before := c.Snapshot()
+
+	t.Run("identical snapshots", func(t *testing.T) {
+		d := Diff(before, c.Snapshot())
+		if !d.IsEmpty() {
+			t.Errorf("expected empty diff, got:\n%s", d)
+		}
+		if !strings.Contains(d.String(), "no changes") {
+			t.Errorf("String() = %q, want no changes", d.String())
+		}
+	})
+
+	apply(NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+		"change": {Proof: 0, Disproof: 9, Status: ProofStatusProven},
+		"new":    {Proof: 2, Disproof: 3},
+	}))
+	constraints = NewConstraintDelta(SignalSourceHard)
+	constraints.Remove = []string{"c2"}
+	constraints.Update = map[string]Constraint{
+		"c1": {ID: "c1", Type: ConstraintTypeMutualExclusion, Nodes: []string{"a", "b"}, Active: false},
+	}
+	constraints.Add = []Constraint{
+		{ID: "c3", Type: ConstraintTypeBefore, Nodes: []string{"b", "c"}, Active: true},
+	}
+	apply(constraints)
+	sim = NewSimilarityDelta(SignalSourceHard)
+	sim.Updates[[2]string{"x", "y"}] = 0.25
+	sim.Updates[[2]string{"p", "q"}] = 0.75
+	apply(sim)
+	apply(NewHistoryDelta(SignalSourceHard, []HistoryEntry{{ID: "h2", NodeID: "b", Action: "prune"}}))
+
+	after := c.Snapshot()
+	d := Diff(before, after)
+
+	t.Run("generations", func(t *testing.T) {
+		if d.FromGeneration != before.Generation() || d.ToGeneration != after.Generation() {
+			t.Errorf("generations = %d -> %d, want %d -> %d",
+				d.FromGeneration, d.ToGeneration, before.Generation(), after.Generation())
+		}
+	})
+
+	t.Run("proofs", func(t *testing.T) {
+		if len(d.Proofs) != 2 {
+			t.Fatalf("Proofs = %+v, want 2 changes", d.Proofs)
+		}
+		if p := d.Proofs[0]; p.NodeID != "change" || p.Kind != ChangeModified || p.Before.Proof != 5 || p.After.Disproof != 9 {
+			t.Errorf("Proofs[0] = %+v", p)
+		}
+		if p := d.Proofs[1]; p.NodeID != "new" || p.Kind != ChangeAdded || p.After.Proof != 2 {
+			t.Errorf("Proofs[1] = %+v", p)
+		}
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		want := []struct {
+			id   string
+			kind ChangeKind
+		}{{"c1", ChangeModified}, {"c2", ChangeRemoved}, {"c3", ChangeAdded}}
+		if len(d.Constraints) != len(want) {
+			t.Fatalf("Constraints = %+v, want %d changes", d.Constraints, len(want))
+		}
+		for i, w := range want {
+			if got := d.Constraints[i]; got.ID != w.id || got.Kind != w.kind {
+				t.Errorf("Constraints[%d] = %s %s, want %s %s", i, got.ID, got.Kind, w.id, w.kind)
+			}
+		}
+		if !d.Constraints[0].Before.Active || d.Constraints[0].After.Active {
+			t.Error("c1 should change from active to inactive")
+		}
+	})
+
+	t.Run("similarities", func(t *testing.T) {
+		want := []SimilarityChange{
+			{Node1: "p", Node2: "q", Kind: ChangeAdded, After: 0.75},
+			{Node1: "x", Node2: "y", Kind: ChangeModified, Before: 0.5, After: 0.25},
+		}
+		if len(d.Similarities) != len(want) {
+			t.Fatalf("Similarities = %+v, want %+v", d.Similarities, want)
+		}
+		for i := range want {
+			if d.Similarities[i] != want[i] {
+				t.Errorf("Similarities[%d] = %+v, want %+v", i, d.Similarities[i], want[i])
+			}
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		if len(d.History.Added) != 1 || d.History.Added[0].ID != "h2" || d.History.Evicted != 0 {
+			t.Errorf("History = %+v, want h2 added", d.History)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		s := d.String()
+		for _, line := range []string{
+			"~ change (proof=5",
+			"- c2 (implication",
+			"+ c3 (before b,c",
+			"~ x <-> y 0.5 -> 0.25",
+			"+ h2 b prune",
+		} {
+			if !strings.Contains(s, line) {
+				t.Errorf("String() missing %q:\n%s", line, s)
+			}
+		}
+	})
+
+	t.Run("reverse diff swaps kinds", func(t *testing.T) {
+		r := Diff(after, before)
+		if len(r.Constraints) != 3 || r.Constraints[1].Kind != ChangeAdded || r.Constraints[2].Kind != ChangeRemoved {
+			t.Errorf("reverse Constraints = %+v", r.Constraints)
+		}
+		if r.History.Evicted != 1 {
+			t.Errorf("reverse History.Evicted = %d, want 1", r.History.Evicted)
+		}
+	})
+
+	t.Run("nil snapshot is empty", func(t *testing.T) {
+		if !Diff(nil, nil).IsEmpty() {
+			t.Error("Diff(nil, nil) should be empty")
+		}
+		if got := len(Diff(nil, before).Constraints); got != 2 {
+			t.Errorf("Diff(nil, before) constraints = %d, want 2", got)
+		}
+	})
+}
+
+func TestChangeKind_String(t *testing.T) {
+	tests := map[ChangeKind]string{
+		ChangeAdded:    "added",
+		ChangeRemoved:  "removed",
+		ChangeModified: "modified",
+		ChangeKind(99): "ChangeKind(99)",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(kind), got, want)
+		}
+	}
+}
+
+func TestDiffDependencies(t *testing.T) {
+	before := map[string][]string{"a": {"b", "c"}, "b": {"c"}}
+	after := map[string][]string{"a": {"c"}, "b": {"c"}, "c": {"d"}}
+
+	got := diffDependencies(before, after)
+	want := []DependencyChange{
+		{From: "a", To: "b", Kind: ChangeRemoved},
+		{From: "c", To: "d", Kind: ChangeAdded},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("diffDependencies = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diffDependencies[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
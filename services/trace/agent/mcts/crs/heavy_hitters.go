@@ -0,0 +1,180 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// -----------------------------------------------------------------------------
+// Heavy Hitters (SpaceSaving)
+// -----------------------------------------------------------------------------
+
+// HeavyHitterCapacity is the number of counters kept by the streaming
+// index's heavy hitters structure. Any item whose frequency exceeds
+// 1/HeavyHitterCapacity of the total increments is guaranteed to be tracked.
+const HeavyHitterCapacity = 256
+
+// HeavyHitter is one of the most frequent items in the streaming index.
+type HeavyHitter struct {
+	// Item is the tracked item.
+	Item string
+
+	// Count is the estimated frequency. It never underestimates.
+	Count uint64
+
+	// Error bounds the overestimate: the true frequency is at least
+	// Count - Error.
+	Error uint64
+}
+
+// spaceSaving is the SpaceSaving heavy hitters algorithm (Metwally et al.).
+//
+// Description:
+//
+//	Keeps at most capacity counters in a min-heap by count. An increment
+//	to a tracked item adds to its counter. An untracked item takes a free
+//	counter, or replaces the minimum counter and inherits its count as
+//	error. Updates cost O(log capacity).
+//
+// Thread Safety: Not safe for concurrent use; guarded by streamingStats.mu.
+type spaceSaving struct {
+	capacity int
+	counters counterHeap
+	index    map[string]*ssCounter
+}
+
+// ssCounter is one monitored item.
+type ssCounter struct {
+	item  string
+	count uint64
+	err   uint64
+	pos   int // heap position
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		index:    make(map[string]*ssCounter, capacity),
+	}
+}
+
+// newSpaceSavingFrom builds the structure from exact frequencies, keeping
+// the capacity most frequent items with zero error. Used on restore.
+func newSpaceSavingFrom(frequencies map[string]uint64, capacity int) *spaceSaving {
+	s := newSpaceSaving(capacity)
+	items := make([]HeavyHitter, 0, len(frequencies))
+	for item, count := range frequencies {
+		items = append(items, HeavyHitter{Item: item, Count: count})
+	}
+	sortHeavyHitters(items)
+	for _, hh := range items[:min(len(items), capacity)] {
+		s.add(hh.Item, hh.Count)
+	}
+	return s
+}
+
+// add records inc occurrences of item.
+func (s *spaceSaving) add(item string, inc uint64) {
+	if inc == 0 || s.capacity <= 0 {
+		return
+	}
+	if c, ok := s.index[item]; ok {
+		c.count += inc
+		heap.Fix(&s.counters, c.pos)
+		return
+	}
+	if len(s.counters) < s.capacity {
+		c := &ssCounter{item: item, count: inc}
+		s.index[item] = c
+		heap.Push(&s.counters, c)
+		return
+	}
+
+	// Replace the minimum counter; its count becomes the new item's error.
+	c := s.counters[0]
+	delete(s.index, c.item)
+	c.item = item
+	c.err = c.count
+	c.count += inc
+	s.index[item] = c
+	heap.Fix(&s.counters, 0)
+}
+
+// topK returns the k items with the highest estimated counts.
+func (s *spaceSaving) topK(k int) []HeavyHitter {
+	if k <= 0 || len(s.counters) == 0 {
+		return nil
+	}
+	out := make([]HeavyHitter, len(s.counters))
+	for i, c := range s.counters {
+		out[i] = HeavyHitter{Item: c.item, Count: c.count, Error: c.err}
+	}
+	sortHeavyHitters(out)
+	return out[:min(k, len(out))]
+}
+
+func (s *spaceSaving) clone() *spaceSaving {
+	c := &spaceSaving{
+		capacity: s.capacity,
+		counters: make(counterHeap, len(s.counters)),
+		index:    make(map[string]*ssCounter, len(s.index)),
+	}
+	for i, counter := range s.counters {
+		cp := *counter
+		c.counters[i] = &cp
+		c.index[cp.item] = &cp
+	}
+	return c
+}
+
+// sortHeavyHitters orders by count descending, then item for determinism.
+func sortHeavyHitters(items []HeavyHitter) {
+	slices.SortFunc(items, func(a, b HeavyHitter) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Item, b.Item))
+	})
+}
+
+// counterHeap is a min-heap of counters by count, ties broken by item so
+// the evicted counter does not depend on insertion order.
+type counterHeap []*ssCounter
+
+func (h counterHeap) Len() int { return len(h) }
+
+func (h counterHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].item > h[j].item
+}
+
+func (h counterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *counterHeap) Push(x any) {
+	c := x.(*ssCounter)
+	c.pos = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *counterHeap) Pop() any {
+	old := *h
+	n := len(old)
+	c := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return c
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSpaceSaving(t *testing.T) {
+	t.Run("exact below capacity", func(t *testing.T) {
+		s := newSpaceSaving(4)
+		s.add("a", 3)
+		s.add("b", 5)
+		s.add("a", 4)
+		s.add("c", 1)
+
+		got := s.topK(2)
+		want := []HeavyHitter{{Item: "a", Count: 7}, {Item: "b", Count: 5}}
+		if !slices.Equal(got, want) {
+			t.Errorf("topK(2) = %v, want %v", got, want)
+		}
+		if got := s.topK(10); len(got) != 3 {
+			t.Errorf("topK(10) returned %d items, want 3", len(got))
+		}
+		if s.topK(0) != nil {
+			t.Error("topK(0) should be nil")
+		}
+	})
+
+	t.Run("eviction inherits minimum count as error", func(t *testing.T) {
+		s := newSpaceSaving(2)
+		s.add("a", 10)
+		s.add("b", 2)
+		s.add("c", 1) // evicts b
+
+		got := s.topK(2)
+		want := []HeavyHitter{{Item: "a", Count: 10}, {Item: "c", Count: 3, Error: 2}}
+		if !slices.Equal(got, want) {
+			t.Errorf("topK(2) = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("finds heavy hitters in a skewed stream", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		s := newSpaceSaving(16)
+		exact := make(map[string]uint64)
+		for range 10000 {
+			item := fmt.Sprintf("noise-%d", rng.Intn(500))
+			if rng.Intn(4) == 0 {
+				item = fmt.Sprintf("hot-%d", rng.Intn(3))
+			}
+			exact[item]++
+			s.add(item, 1)
+		}
+
+		top := s.topK(3)
+		for _, hh := range top {
+			if hh.Item[:4] != "hot-" {
+				t.Errorf("unexpected heavy hitter %v", hh)
+			}
+			if hh.Count < exact[hh.Item] || hh.Count-hh.Error > exact[hh.Item] {
+				t.Errorf("%s: count %d error %d does not bound true frequency %d",
+					hh.Item, hh.Count, hh.Error, exact[hh.Item])
+			}
+		}
+	})
+
+	t.Run("clone is independent", func(t *testing.T) {
+		s := newSpaceSaving(2)
+		s.add("a", 1)
+		c := s.clone()
+		c.add("a", 5)
+		c.add("b", 1)
+		if got := s.topK(5); len(got) != 1 || got[0].Count != 1 {
+			t.Errorf("original changed: %v", got)
+		}
+	})
+}
+
+func TestStreamingIndex_TopK(t *testing.T) {
+	ctx := context.Background()
+	c := New(nil)
+
+	delta := NewStreamingDelta(SignalSourceHard)
+	delta.Increments["parse"] = 4
+	delta.Increments["lint"] = 9
+	delta.Increments["build"] = 1
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	before := c.Snapshot()
+
+	delta = NewStreamingDelta(SignalSourceHard)
+	delta.Increments["parse"] = 10
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	got := c.Snapshot().StreamingIndex().TopK(2)
+	want := []HeavyHitter{{Item: "parse", Count: 14}, {Item: "lint", Count: 9}}
+	if !slices.Equal(got, want) {
+		t.Errorf("TopK(2) = %v, want %v", got, want)
+	}
+	if got := before.StreamingIndex().TopK(1); len(got) != 1 || got[0].Item != "lint" {
+		t.Errorf("earlier snapshot TopK(1) = %v, want lint", got)
+	}
+
+	t.Run("survives persist and restore", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "crs.snap")
+		if err := c.Persist(ctx, path); err != nil {
+			t.Fatalf("Persist: %v", err)
+		}
+		restored := New(nil)
+		if err := restored.RestoreFrom(ctx, path); err != nil {
+			t.Fatalf("RestoreFrom: %v", err)
+		}
+		if got := restored.Snapshot().StreamingIndex().TopK(2); !slices.Equal(got, want) {
+			t.Errorf("restored TopK(2) = %v, want %v", got, want)
+		}
+	})
+}
@@ -25,8 +25,8 @@
 //	│                                                                              │
 //	│  ┌─────────────┐  Stores constraints on the search space.                   │
 //	│  │ CONSTRAINT  │  Types: MutualExclusion, Implication, Ordering, Resource.  │
-//	│  │   INDEX     │  Used by AC-3, TMS for constraint propagation.             │
-//	│  └─────────────┘                                                            │
+//	│  │   INDEX     │  Temporal: Before, After, During (edit order over time),   │
+//	│  └─────────────┘  secondary-indexed by node and type. Used by AC-3, TMS.    │
 //	│                                                                              │
 //	│  ┌─────────────┐  Stores similarity distances between nodes.                │
 //	│  │ SIMILARITY  │  Used by MinHash, LSH for finding similar code patterns.   │
@@ -139,6 +139,15 @@ type QueryAPI interface {
 	// Outputs:
 	//   - *NodeStats: Statistics about the node. Nil if node not found anywhere.
 	NodeStats(nodeID string) *NodeStats
+
+	// Nodes starts a query that joins the proof, dependency, and history indexes.
+	//
+	// Description:
+	//   Returns a fluent builder. See NodeQuery for predicates and planning.
+	//
+	// Outputs:
+	//   - *NodeQuery: A new query with no predicates. Never nil.
+	Nodes() *NodeQuery
 }
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,446 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Cross-Index Query Planner
+// -----------------------------------------------------------------------------
+
+// QueryIndex names an index a node query can read.
+type QueryIndex string
+
+const (
+	// QueryIndexProof is the proof index.
+	QueryIndexProof QueryIndex = "proof"
+
+	// QueryIndexDependency is the dependency index.
+	QueryIndexDependency QueryIndex = "dependency"
+
+	// QueryIndexHistory is the history index.
+	QueryIndexHistory QueryIndex = "history"
+)
+
+// HistoryFilter selects history entries. Empty fields match anything.
+type HistoryFilter struct {
+	// Action matches HistoryEntry.Action.
+	Action string
+
+	// Result matches HistoryEntry.Result.
+	Result string
+
+	// Source matches HistoryEntry.Source. SignalSourceUnknown matches all.
+	Source SignalSource
+}
+
+// matches returns true if the entry passes the filter.
+func (f HistoryFilter) matches(e HistoryEntry) bool {
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.Result != "" && e.Result != f.Result {
+		return false
+	}
+	if f.Source != SignalSourceUnknown && e.Source != f.Source {
+		return false
+	}
+	return true
+}
+
+// String describes the filter for query plans.
+func (f HistoryFilter) String() string {
+	var parts []string
+	if f.Action != "" {
+		parts = append(parts, "action="+f.Action)
+	}
+	if f.Result != "" {
+		parts = append(parts, "result="+f.Result)
+	}
+	if f.Source != SignalSourceUnknown {
+		parts = append(parts, "source="+f.Source.String())
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, ",")
+}
+
+// QueryPlan describes how a NodeQuery will be executed.
+type QueryPlan struct {
+	// Driver is the index that generates candidate nodes. The proof index is
+	// scanned when no predicate can generate candidates.
+	Driver QueryIndex
+
+	// DriverPredicate describes the predicate that generates candidates.
+	// Empty when the proof index is scanned without a predicate.
+	DriverPredicate string
+
+	// EstimatedCandidates is the estimated number of candidates generated.
+	EstimatedCandidates int
+
+	// Filters describes the remaining predicates in the order they run.
+	Filters []string
+}
+
+// String returns a one-line description of the plan.
+func (p QueryPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "scan %s", p.Driver)
+	if p.DriverPredicate != "" {
+		fmt.Fprintf(&b, " [%s]", p.DriverPredicate)
+	}
+	fmt.Fprintf(&b, " (~%d candidates)", p.EstimatedCandidates)
+	for _, f := range p.Filters {
+		fmt.Fprintf(&b, " -> filter %s", f)
+	}
+	return b.String()
+}
+
+// queryPredicate is one condition of a NodeQuery.
+type queryPredicate struct {
+	// desc describes the predicate for plans.
+	desc string
+
+	// index is the index the predicate reads.
+	index QueryIndex
+
+	// cost is the relative per-node cost of match; cheaper filters run first.
+	cost int
+
+	// estimate returns the number of candidates generate would return.
+	// Nil if the predicate cannot generate candidates.
+	estimate func() int
+
+	// generate returns the nodes that satisfy the predicate.
+	generate func() []string
+
+	// match reports whether a node satisfies the predicate.
+	match func(nodeID string) bool
+}
+
+// Relative per-node costs of predicate evaluation.
+const (
+	predicateCostLookup     = 1 // one map lookup
+	predicateCostNeighbors  = 4 // one lookup per neighbor
+	predicateCostPrecompute = 2 // lookup after a one-time index scan
+)
+
+// NodeQuery is a fluent builder for queries that join the proof,
+// dependency, and history indexes.
+//
+// Description:
+//
+//	Each builder method adds a predicate; a node is returned if it
+//	satisfies all of them. Execute picks the predicate with the smallest
+//	estimated result as the driver, generates candidates from its index,
+//	and applies the remaining predicates as filters, cheapest first.
+//
+// Example:
+//
+//	// Unproven nodes whose dependencies are all proven and that have
+//	// more than 3 failed history entries.
+//	nodes := snapshot.Query().Nodes().
+//	    WithStatus(ProofStatusUnknown, ProofStatusExpanded).
+//	    AllDependencies(ProofStatusProven).
+//	    MinHistory(HistoryFilter{Result: "failure"}, 4).
+//	    Execute()
+//
+// Thread Safety: A NodeQuery must not be used concurrently. The snapshot
+// it reads is immutable, so separate queries may run concurrently.
+type NodeQuery struct {
+	snapshot   Snapshot
+	predicates []*queryPredicate
+	limit      int
+}
+
+// Nodes starts a cross-index node query.
+func (q *queryImpl) Nodes() *NodeQuery {
+	return &NodeQuery{snapshot: q.snapshot}
+}
+
+// WithStatus keeps nodes whose proof status is one of statuses.
+func (nq *NodeQuery) WithStatus(statuses ...ProofStatus) *NodeQuery {
+	want := make(map[ProofStatus]bool, len(statuses))
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		want[s] = true
+		names[i] = s.String()
+	}
+	proofIndex := nq.snapshot.ProofIndex()
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:  "status in (" + strings.Join(names, ",") + ")",
+		index: QueryIndexProof,
+		cost:  predicateCostLookup,
+		estimate: func() int {
+			// Assume statuses are evenly distributed over the 4 values.
+			return proofIndex.Size() * len(want) / 4
+		},
+		generate: func() []string {
+			var result []string
+			for nodeID, pn := range proofIndex.All() {
+				if want[pn.Status] {
+					result = append(result, nodeID)
+				}
+			}
+			return result
+		},
+		match: func(nodeID string) bool {
+			pn, ok := proofIndex.Get(nodeID)
+			return ok && want[pn.Status]
+		},
+	})
+	return nq
+}
+
+// ProofBetween keeps nodes whose proof number is in [minProof, maxProof].
+func (nq *NodeQuery) ProofBetween(minProof, maxProof uint64) *NodeQuery {
+	proofIndex := nq.snapshot.ProofIndex()
+	inRange := func(pn ProofNumber) bool {
+		return pn.Proof >= minProof && pn.Proof <= maxProof
+	}
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:     fmt.Sprintf("proof in [%d,%d]", minProof, maxProof),
+		index:    QueryIndexProof,
+		cost:     predicateCostLookup,
+		estimate: func() int { return proofIndex.Size() / 2 },
+		generate: func() []string {
+			var result []string
+			for nodeID, pn := range proofIndex.All() {
+				if inRange(pn) {
+					result = append(result, nodeID)
+				}
+			}
+			return result
+		},
+		match: func(nodeID string) bool {
+			pn, ok := proofIndex.Get(nodeID)
+			return ok && inRange(pn)
+		},
+	})
+	return nq
+}
+
+// DependsOn keeps nodes that directly depend on target.
+func (nq *NodeQuery) DependsOn(target string) *NodeQuery {
+	depIndex := nq.snapshot.DependencyIndex()
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:     "depends on " + target,
+		index:    QueryIndexDependency,
+		cost:     predicateCostNeighbors,
+		estimate: func() int { return len(depIndex.DependedBy(target)) },
+		generate: func() []string { return depIndex.DependedBy(target) },
+		match: func(nodeID string) bool {
+			for _, dep := range depIndex.DependsOn(nodeID) {
+				if dep == target {
+					return true
+				}
+			}
+			return false
+		},
+	})
+	return nq
+}
+
+// AllDependencies keeps nodes that have at least one direct dependency and
+// whose direct dependencies all have the given proof status.
+func (nq *NodeQuery) AllDependencies(status ProofStatus) *NodeQuery {
+	depIndex := nq.snapshot.DependencyIndex()
+	proofIndex := nq.snapshot.ProofIndex()
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:  "all dependencies " + status.String(),
+		index: QueryIndexDependency,
+		cost:  predicateCostNeighbors,
+		match: func(nodeID string) bool {
+			deps := depIndex.DependsOn(nodeID)
+			if len(deps) == 0 {
+				return false
+			}
+			for _, dep := range deps {
+				pn, ok := proofIndex.Get(dep)
+				if !ok || pn.Status != status {
+					return false
+				}
+			}
+			return true
+		},
+	})
+	return nq
+}
+
+// AnyDependency keeps nodes with at least one direct dependency that has
+// the given proof status.
+func (nq *NodeQuery) AnyDependency(status ProofStatus) *NodeQuery {
+	depIndex := nq.snapshot.DependencyIndex()
+	proofIndex := nq.snapshot.ProofIndex()
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:  "any dependency " + status.String(),
+		index: QueryIndexDependency,
+		cost:  predicateCostNeighbors,
+		match: func(nodeID string) bool {
+			for _, dep := range depIndex.DependsOn(nodeID) {
+				if pn, ok := proofIndex.Get(dep); ok && pn.Status == status {
+					return true
+				}
+			}
+			return false
+		},
+	})
+	return nq
+}
+
+// MinHistory keeps nodes with at least n history entries matching filter.
+//
+// Description:
+//
+//	The history index is scanned once per query, whether the predicate
+//	drives the query or filters it.
+func (nq *NodeQuery) MinHistory(filter HistoryFilter, n int) *NodeQuery {
+	historyIndex := nq.snapshot.HistoryIndex()
+
+	var counts map[string]int
+	countAll := func() map[string]int {
+		if counts == nil {
+			counts = make(map[string]int)
+			for _, e := range historyIndex.Recent(historyIndex.Size()) {
+				if filter.matches(e) {
+					counts[e.NodeID]++
+				}
+			}
+		}
+		return counts
+	}
+
+	nq.predicates = append(nq.predicates, &queryPredicate{
+		desc:  fmt.Sprintf("history(%s) >= %d", filter, n),
+		index: QueryIndexHistory,
+		cost:  predicateCostPrecompute,
+		estimate: func() int {
+			if n <= 1 {
+				return historyIndex.Size()
+			}
+			return historyIndex.Size() / n
+		},
+		generate: func() []string {
+			var result []string
+			for nodeID, c := range countAll() {
+				if c >= n {
+					result = append(result, nodeID)
+				}
+			}
+			return result
+		},
+		match: func(nodeID string) bool {
+			return countAll()[nodeID] >= n
+		},
+	})
+	return nq
+}
+
+// Limit caps the number of results. Zero or negative means no limit.
+func (nq *NodeQuery) Limit(n int) *NodeQuery {
+	nq.limit = n
+	return nq
+}
+
+// plan chooses the driver predicate and orders the filters.
+func (nq *NodeQuery) plan() (*queryPredicate, []*queryPredicate, QueryPlan) {
+	var driver *queryPredicate
+	best := 0
+	for _, p := range nq.predicates {
+		if p.estimate == nil {
+			continue
+		}
+		if est := p.estimate(); driver == nil || est < best {
+			driver, best = p, est
+		}
+	}
+
+	plan := QueryPlan{Driver: QueryIndexProof}
+	if driver != nil {
+		plan.Driver = driver.index
+		plan.DriverPredicate = driver.desc
+		plan.EstimatedCandidates = best
+	} else {
+		plan.EstimatedCandidates = nq.snapshot.ProofIndex().Size()
+	}
+
+	filters := make([]*queryPredicate, 0, len(nq.predicates))
+	for _, p := range nq.predicates {
+		if p != driver {
+			filters = append(filters, p)
+		}
+	}
+	sort.SliceStable(filters, func(i, j int) bool {
+		return filters[i].cost < filters[j].cost
+	})
+	for _, f := range filters {
+		plan.Filters = append(plan.Filters, f.desc)
+	}
+
+	return driver, filters, plan
+}
+
+// Explain returns the plan Execute would use.
+func (nq *NodeQuery) Explain() QueryPlan {
+	_, _, plan := nq.plan()
+	return plan
+}
+
+// Execute runs the query.
+//
+// Outputs:
+//   - []string: Matching node IDs, sorted. Empty if none.
+func (nq *NodeQuery) Execute() []string {
+	driver, filters, _ := nq.plan()
+
+	var candidates []string
+	if driver != nil {
+		candidates = driver.generate()
+	} else {
+		all := nq.snapshot.ProofIndex().All()
+		candidates = make([]string, 0, len(all))
+		for nodeID := range all {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	sort.Strings(candidates)
+
+	result := make([]string, 0)
+	for _, nodeID := range candidates {
+		if matchesAll(filters, nodeID) {
+			result = append(result, nodeID)
+			if nq.limit > 0 && len(result) >= nq.limit {
+				break
+			}
+		}
+	}
+	return result
+}
+
+// matchesAll reports whether nodeID satisfies every predicate.
+func matchesAll(predicates []*queryPredicate, nodeID string) bool {
+	for _, p := range predicates {
+		if !p.match(nodeID) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// setupPlannerCRS extends setupTestCRS with four failed attempts on node-3.
+func setupPlannerCRS(t *testing.T) CRS {
+	t.Helper()
+	c := setupTestCRS(t)
+	var entries []HistoryEntry
+	for i := 0; i < 4; i++ {
+		entries = append(entries, HistoryEntry{
+			ID: fmt.Sprintf("f%d", i), NodeID: "node-3", Action: "expand", Result: "failure", Source: SignalSourceHard,
+		})
+	}
+	if _, err := c.Apply(context.Background(), NewHistoryDelta(SignalSourceHard, entries)); err != nil {
+		t.Fatalf("failed to apply history delta: %v", err)
+	}
+	return c
+}
+
+func TestNodeQuery_Execute(t *testing.T) {
+	query := setupPlannerCRS(t).Snapshot().Query()
+
+	tests := []struct {
+		name  string
+		query *NodeQuery
+		want  []string
+	}{
+		{
+			name: "joins proof, dependency, and history",
+			query: query.Nodes().
+				WithStatus(ProofStatusUnknown, ProofStatusExpanded).
+				AllDependencies(ProofStatusUnknown).
+				MinHistory(HistoryFilter{Result: "failure"}, 4),
+			want: []string{"node-3"},
+		},
+		{
+			name: "history threshold excludes",
+			query: query.Nodes().
+				WithStatus(ProofStatusExpanded).
+				MinHistory(HistoryFilter{Result: "failure"}, 5),
+			want: []string{},
+		},
+		{
+			name:  "history filter by source",
+			query: query.Nodes().MinHistory(HistoryFilter{Source: SignalSourceSoft}, 1),
+			want:  []string{"node-2"},
+		},
+		{
+			name:  "any dependency",
+			query: query.Nodes().AnyDependency(ProofStatusProven),
+			want:  []string{"node-1"},
+		},
+		{
+			name:  "depends on",
+			query: query.Nodes().DependsOn("node-3").WithStatus(ProofStatusDisproven),
+			want:  []string{"node-2"},
+		},
+		{
+			name:  "proof range",
+			query: query.Nodes().ProofBetween(10, 20),
+			want:  []string{"node-1", "node-2", "node-3"},
+		},
+		{
+			name:  "no predicates with limit",
+			query: query.Nodes().Limit(2),
+			want:  []string{"node-1", "node-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Execute(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Execute() = %v, want %v (plan: %s)", got, tt.want, tt.query.Explain())
+			}
+		})
+	}
+}
+
+func TestNodeQuery_Explain(t *testing.T) {
+	query := setupPlannerCRS(t).Snapshot().Query()
+
+	t.Run("most selective index drives", func(t *testing.T) {
+		plan := query.Nodes().
+			AllDependencies(ProofStatusProven).
+			WithStatus(ProofStatusUnknown, ProofStatusExpanded).
+			MinHistory(HistoryFilter{Result: "failure"}, 4).
+			Explain()
+
+		if plan.Driver != QueryIndexHistory {
+			t.Errorf("driver = %s, want history (plan: %s)", plan.Driver, plan)
+		}
+		want := []string{"status in (unknown,expanded)", "all dependencies proven"}
+		if !reflect.DeepEqual(plan.Filters, want) {
+			t.Errorf("filters = %v, want %v", plan.Filters, want)
+		}
+	})
+
+	t.Run("dependency lookup beats proof scan", func(t *testing.T) {
+		plan := query.Nodes().WithStatus(ProofStatusProven, ProofStatusDisproven).DependsOn("node-4").Explain()
+		if plan.Driver != QueryIndexDependency || plan.EstimatedCandidates != 1 {
+			t.Errorf("plan = %s, want dependency driver with 1 candidate", plan)
+		}
+	})
+
+	t.Run("filter-only predicates scan the proof index", func(t *testing.T) {
+		plan := query.Nodes().AllDependencies(ProofStatusProven).Explain()
+		if plan.Driver != QueryIndexProof || plan.DriverPredicate != "" || plan.EstimatedCandidates != 5 {
+			t.Errorf("plan = %s, want unfiltered proof scan of 5", plan)
+		}
+	})
+}
@@ -352,8 +352,21 @@ type StreamingIndexExport struct {
 
 	// ApproximateBytes is the approximate memory usage.
 	ApproximateBytes int `json:"approximate_bytes"`
+
+	// HeavyHitters are the most frequent items, highest count first.
+	HeavyHitters []HeavyHitterExport `json:"heavy_hitters,omitempty"`
+}
+
+// HeavyHitterExport is the serializable form of a HeavyHitter.
+type HeavyHitterExport struct {
+	Item  string `json:"item"`
+	Count uint64 `json:"count"`
+	Error uint64 `json:"error,omitempty"`
 }
 
+// exportHeavyHitterLimit caps heavy hitters included in an export.
+const exportHeavyHitterLimit = 20
+
 // -----------------------------------------------------------------------------
 // Serializer
 // -----------------------------------------------------------------------------
@@ -816,6 +829,13 @@ func (s *Serializer) exportStreamingIndex(idx StreamingIndexView) StreamingIndex
 	if idx != nil {
 		export.Cardinality = idx.Cardinality()
 		export.ApproximateBytes = idx.Size()
+		for _, hh := range idx.TopK(exportHeavyHitterLimit) {
+			export.HeavyHitters = append(export.HeavyHitters, HeavyHitterExport{
+				Item:  hh.Item,
+				Count: hh.Count,
+				Error: hh.Error,
+			})
+		}
 	}
 
 	return export
@@ -1405,6 +1425,12 @@ func parseConstraintType(s string) ConstraintType {
 		return ConstraintTypeOrdering
 	case "resource":
 		return ConstraintTypeResource
+	case "before":
+		return ConstraintTypeBefore
+	case "after":
+		return ConstraintTypeAfter
+	case "during":
+		return ConstraintTypeDuring
 	default:
 		return ConstraintTypeUnknown
 	}
@@ -55,6 +55,14 @@ func (m *mockConstraintIndexView) FindByNode(nodeID string) []Constraint {
 	return nil
 }
 
+func (m *mockConstraintIndexView) FindTemporal(nodeID string) []Constraint {
+	return nil
+}
+
+func (m *mockConstraintIndexView) TemporalEdges() []TemporalEdge {
+	return nil
+}
+
 func (m *mockConstraintIndexView) All() map[string]Constraint {
 	result := make(map[string]Constraint, len(m.data))
 	for k, v := range m.data {
@@ -239,6 +247,10 @@ func (m *mockStreamingIndexView) Size() int {
 	return m.size
 }
 
+func (m *mockStreamingIndexView) TopK(k int) []HeavyHitter {
+	return nil
+}
+
 // mockSnapshot implements Snapshot for testing.
 type mockSnapshot struct {
 	generation int64
@@ -30,6 +30,7 @@ type snapshot struct {
 	// Index data - all maps are copied on snapshot creation for immutability
 	proofData      map[string]ProofNumber
 	constraintData map[string]Constraint
+	constraintIdx  *constraintSecondaryIndex     // lazily built over constraintData
 	similarityData map[string]map[string]float64 // node1 -> node2 -> distance
 	dependencyData *dependencyGraph
 	historyData    []HistoryEntry
@@ -44,6 +45,10 @@ type snapshot struct {
 
 	// GR-31: Analytics history
 	analyticsData *AnalyticsHistory
+
+	// blocks accounts for the memory of each part. Parts shared with other
+	// snapshots share the same block. Nil for snapshots built by newSnapshot.
+	blocks [numSnapshotParts]*retainedBlock
 }
 
 // newSnapshot creates a new immutable snapshot from current state.
@@ -84,75 +89,88 @@ func newSnapshot(
 		graphQuery: nil, // Set via setGraphQuery after creation
 	}
 
-	// Deep copy proof data
-	if proofs != nil {
-		s.proofData = maps.Clone(proofs)
-	} else {
-		s.proofData = make(map[string]ProofNumber)
+	s.proofData = copyProofData(proofs)
+	s.constraintData = copyConstraintData(constraints)
+	s.constraintIdx = newConstraintSecondaryIndex(s.constraintData)
+	s.similarityData = copySimilarityData(similarities)
+	s.dependencyData = copyDependencyData(deps)
+	s.historyData = copyHistoryData(history)
+	s.streamingData = copyStreamingData(streaming)
+	s.clauseData = copyClauseData(clauses) // CRS-04
+
+	return s
+}
+
+// -----------------------------------------------------------------------------
+// Snapshot Part Copies
+// -----------------------------------------------------------------------------
+
+// copyProofData deep copies proof number data. A nil source yields an empty map.
+func copyProofData(proofs map[string]ProofNumber) map[string]ProofNumber {
+	if proofs == nil {
+		return make(map[string]ProofNumber)
 	}
+	return maps.Clone(proofs)
+}
 
-	// Deep copy constraint data
-	if constraints != nil {
-		s.constraintData = maps.Clone(constraints)
-	} else {
-		s.constraintData = make(map[string]Constraint)
+// copyConstraintData deep copies constraint data. A nil source yields an empty map.
+func copyConstraintData(constraints map[string]Constraint) map[string]Constraint {
+	if constraints == nil {
+		return make(map[string]Constraint)
 	}
+	return maps.Clone(constraints)
+}
 
-	// Deep copy similarity data (nested map)
-	if similarities != nil {
-		s.similarityData = make(map[string]map[string]float64, len(similarities))
-		for k, v := range similarities {
-			s.similarityData[k] = maps.Clone(v)
-		}
-	} else {
-		s.similarityData = make(map[string]map[string]float64)
+// copySimilarityData deep copies the nested similarity map.
+func copySimilarityData(similarities map[string]map[string]float64) map[string]map[string]float64 {
+	out := make(map[string]map[string]float64, len(similarities))
+	for k, v := range similarities {
+		out[k] = maps.Clone(v)
 	}
+	return out
+}
 
-	// Deep copy dependency graph
-	if deps != nil {
-		s.dependencyData = deps.clone()
-	} else {
-		s.dependencyData = newDependencyGraph()
+// copyDependencyData deep copies the dependency graph.
+func copyDependencyData(deps *dependencyGraph) *dependencyGraph {
+	if deps == nil {
+		return newDependencyGraph()
 	}
+	return deps.clone()
+}
 
-	// Deep copy history (including Metadata maps for true immutability)
-	if history != nil {
-		s.historyData = make([]HistoryEntry, len(history))
-		for i, entry := range history {
-			s.historyData[i] = entry // struct copy
-			if entry.Metadata != nil {
-				s.historyData[i].Metadata = maps.Clone(entry.Metadata)
-			}
+// copyHistoryData deep copies history, including Metadata maps for true
+// immutability.
+func copyHistoryData(history []HistoryEntry) []HistoryEntry {
+	out := make([]HistoryEntry, len(history))
+	for i, entry := range history {
+		out[i] = entry // struct copy
+		if entry.Metadata != nil {
+			out[i].Metadata = maps.Clone(entry.Metadata)
 		}
-	} else {
-		s.historyData = make([]HistoryEntry, 0)
-	}
-
-	// Deep copy streaming stats
-	if streaming != nil {
-		s.streamingData = streaming.clone()
-	} else {
-		s.streamingData = newStreamingStats()
-	}
-
-	// Deep copy clause data (CRS-04)
-	if clauses != nil {
-		s.clauseData = make(map[string]*Clause, len(clauses))
-		for k, v := range clauses {
-			// Deep copy the clause struct
-			clauseCopy := *v
-			// Deep copy literals slice
-			if v.Literals != nil {
-				clauseCopy.Literals = make([]Literal, len(v.Literals))
-				copy(clauseCopy.Literals, v.Literals)
-			}
-			s.clauseData[k] = &clauseCopy
-		}
-	} else {
-		s.clauseData = make(map[string]*Clause)
 	}
+	return out
+}
+
+// copyStreamingData deep copies streaming stats.
+func copyStreamingData(streaming *streamingStats) *streamingStats {
+	if streaming == nil {
+		return newStreamingStats()
+	}
+	return streaming.clone()
+}
 
-	return s
+// copyClauseData deep copies learned clauses, including their literals. (CRS-04)
+func copyClauseData(clauses map[string]*Clause) map[string]*Clause {
+	out := make(map[string]*Clause, len(clauses))
+	for k, v := range clauses {
+		clauseCopy := *v
+		if v.Literals != nil {
+			clauseCopy.Literals = make([]Literal, len(v.Literals))
+			copy(clauseCopy.Literals, v.Literals)
+		}
+		out[k] = &clauseCopy
+	}
+	return out
 }
 
 // Generation returns the generation when this snapshot was created.
@@ -172,7 +190,11 @@ func (s *snapshot) ProofIndex() ProofIndexView {
 
 // ConstraintIndex returns the constraint index view.
 func (s *snapshot) ConstraintIndex() ConstraintIndexView {
-	return &constraintIndexView{data: s.constraintData, clauses: s.clauseData}
+	idx := s.constraintIdx
+	if idx == nil {
+		idx = newConstraintSecondaryIndex(s.constraintData)
+	}
+	return &constraintIndexView{data: s.constraintData, clauses: s.clauseData, idx: idx}
 }
 
 // SimilarityIndex returns the similarity index view.
@@ -294,6 +316,7 @@ func (v *proofIndexView) Size() int {
 type constraintIndexView struct {
 	data    map[string]Constraint
 	clauses map[string]*Clause // CRS-04: learned clauses
+	idx     *constraintSecondaryIndex
 }
 
 func (v *constraintIndexView) Get(constraintID string) (Constraint, bool) {
@@ -302,26 +325,19 @@ func (v *constraintIndexView) Get(constraintID string) (Constraint, bool) {
 }
 
 func (v *constraintIndexView) FindByType(constraintType ConstraintType) []Constraint {
-	var result []Constraint
-	for _, c := range v.data {
-		if c.Type == constraintType {
-			result = append(result, c)
-		}
-	}
-	return result
+	return v.idx.findByType(constraintType)
 }
 
 func (v *constraintIndexView) FindByNode(nodeID string) []Constraint {
-	var result []Constraint
-	for _, c := range v.data {
-		for _, n := range c.Nodes {
-			if n == nodeID {
-				result = append(result, c)
-				break
-			}
-		}
-	}
-	return result
+	return v.idx.findByNode(nodeID)
+}
+
+func (v *constraintIndexView) FindTemporal(nodeID string) []Constraint {
+	return v.idx.findTemporal(nodeID)
+}
+
+func (v *constraintIndexView) TemporalEdges() []TemporalEdge {
+	return v.idx.edges()
 }
 
 func (v *constraintIndexView) All() map[string]Constraint {
@@ -754,12 +770,14 @@ type streamingStats struct {
 	mu          sync.RWMutex
 	frequencies map[string]uint64
 	cardinality uint64
+	heavy       *spaceSaving // nil until first needed; see heavyHitters
 }
 
 func newStreamingStats() *streamingStats {
 	return &streamingStats{
 		frequencies: make(map[string]uint64),
 		cardinality: 0,
+		heavy:       newSpaceSaving(HeavyHitterCapacity),
 	}
 }
 
@@ -770,9 +788,35 @@ func (s *streamingStats) clone() *streamingStats {
 		frequencies: maps.Clone(s.frequencies),
 		cardinality: s.cardinality,
 	}
+	if s.heavy != nil {
+		c.heavy = s.heavy.clone()
+	}
 	return c
 }
 
+// heavyHitters returns the heavy hitters structure, rebuilding it from the
+// exact frequencies if stats were restored without one.
+// Caller must hold s.mu for writing.
+func (s *streamingStats) heavyHitters() *spaceSaving {
+	if s.heavy == nil {
+		s.heavy = newSpaceSavingFrom(s.frequencies, HeavyHitterCapacity)
+	}
+	return s.heavy
+}
+
+func (s *streamingStats) topK(k int) []HeavyHitter {
+	s.mu.RLock()
+	if s.heavy != nil {
+		defer s.mu.RUnlock()
+		return s.heavy.topK(k)
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heavyHitters().topK(k)
+}
+
 func (s *streamingStats) estimate(item string) uint64 {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -811,3 +855,7 @@ func (v *streamingIndexView) Cardinality() uint64 {
 func (v *streamingIndexView) Size() int {
 	return v.stats.size()
 }
+
+func (v *streamingIndexView) TopK(k int) []HeavyHitter {
+	return v.stats.topK(k)
+}
@@ -0,0 +1,478 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+	"weak"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// -----------------------------------------------------------------------------
+// Snapshot Memory Budget
+// -----------------------------------------------------------------------------
+
+// DefaultSnapshotMemoryBudget is the default cap on memory retained by
+// snapshots (512MB).
+const DefaultSnapshotMemoryBudget int64 = 512 << 20
+
+// minCompactionInterval rate-limits forced compactions, which run a full GC.
+const minCompactionInterval = 5 * time.Second
+
+var (
+	snapshotRetainedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "crs_snapshot_retained_bytes",
+		Help: "Estimated bytes retained by live CRS snapshots",
+	})
+
+	snapshotCompactions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "crs_snapshot_compactions_total",
+		Help: "Total number of snapshot compactions triggered by the memory budget",
+	})
+
+	snapshotPartsShared = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "crs_snapshot_parts_shared_total",
+		Help: "Total number of snapshot parts shared with the previous snapshot instead of copied",
+	})
+)
+
+// snapshotPart identifies one separately copied part of a snapshot.
+//
+// The first six parts follow the IndexMask bit order so a mask of modified
+// indexes converts directly to a partSet.
+type snapshotPart uint8
+
+const (
+	partProof snapshotPart = iota
+	partConstraint
+	partSimilarity
+	partDependency
+	partHistory
+	partStreaming
+	partClauses
+	numSnapshotParts
+)
+
+// partSet is a bitmask of snapshot parts.
+type partSet uint8
+
+// allParts contains every snapshot part.
+const allParts = partSet(1<<numSnapshotParts - 1)
+
+// partsOf converts a mask of modified indexes to the parts they invalidate.
+func partsOf(m IndexMask) partSet {
+	return partSet(m)
+}
+
+// partSetOf returns a set containing only p.
+func partSetOf(p snapshotPart) partSet {
+	return 1 << p
+}
+
+func (s partSet) has(p snapshotPart) bool {
+	return s&partSetOf(p) != 0
+}
+
+// retainedBlock accounts for the memory of one copied snapshot part.
+//
+// Every snapshot that shares the copy references the same block. When the
+// last of them is garbage collected the block becomes unreachable and its
+// bytes are released from the budget by a runtime cleanup.
+//
+// The struct is kept at 16 bytes or more so the runtime never places it in
+// a tiny-allocator block, which could delay its cleanup indefinitely.
+type retainedBlock struct {
+	bytes int64
+
+	// generation is when the part was copied.
+	generation int64
+}
+
+// retainedRelease is the cleanup argument for a retainedBlock. It must not
+// reference the block itself.
+type retainedRelease struct {
+	counter *atomic.Int64
+	bytes   int64
+}
+
+func releaseRetained(r retainedRelease) {
+	r.counter.Add(-r.bytes)
+	snapshotRetainedBytes.Sub(float64(r.bytes))
+}
+
+// SnapshotMemoryStats describes memory retained by snapshots.
+type SnapshotMemoryStats struct {
+	// RetainedBytes is the estimated bytes retained by live snapshots.
+	RetainedBytes int64
+
+	// Budget is the configured limit in bytes (0 = unlimited).
+	Budget int64
+
+	// LiveSnapshots is the number of snapshots not yet garbage collected.
+	LiveSnapshots int
+
+	// OldestLiveGeneration is the generation of the oldest live snapshot
+	// (0 if there are none).
+	OldestLiveGeneration int64
+
+	// Compactions is how many times the budget forced a compaction.
+	Compactions int64
+}
+
+// snapshotBudget implements copy-on-write snapshots with a memory budget.
+//
+// Description:
+//
+//	Snapshot parts that have not changed since the previous snapshot are
+//	shared with it instead of copied, so taking many snapshots of a slowly
+//	changing CRS is cheap. Each copied part is accounted once, however
+//	many snapshots share it, and released when the last of them is
+//	collected. Live snapshots are tracked with weak pointers so tracking
+//	never keeps a snapshot alive.
+//
+//	When retained memory exceeds the budget, the budget compacts: it drops
+//	its own reference to the latest snapshot and forces a GC so that parts
+//	only referenced by abandoned snapshots are reclaimed. Live snapshots
+//	are never mutated; if they alone exceed the budget, a warning names how
+//	many there are and the oldest generation still held.
+//
+// Thread Safety: Safe for concurrent use. Callers that mark parts dirty
+// must hold the CRS write lock; builders must hold at least the read lock.
+type snapshotBudget struct {
+	limit  int64
+	logger *slog.Logger
+
+	// retained is allocated separately so cleanups can reference it
+	// without keeping the budget reachable.
+	retained    *atomic.Int64
+	compactions atomic.Int64
+
+	mu sync.Mutex
+
+	// base is the latest snapshot; parts not in dirty are shared from it.
+	base  *snapshot
+	dirty partSet
+
+	// live tracks snapshots handed out. Collected entries are pruned when
+	// the slice reaches pruneAt.
+	live    []weak.Pointer[snapshot]
+	pruneAt int
+
+	lastCompaction time.Time
+}
+
+// newSnapshotBudget creates a budget. A non-positive limit means unlimited.
+func newSnapshotBudget(limit int64, logger *slog.Logger) *snapshotBudget {
+	return &snapshotBudget{
+		limit:    limit,
+		logger:   logger,
+		retained: new(atomic.Int64),
+		dirty:    allParts,
+		pruneAt:  64,
+	}
+}
+
+// markDirty records that parts changed and must be copied by the next
+// snapshot.
+//
+// Thread Safety: Caller must hold the CRS write lock.
+func (b *snapshotBudget) markDirty(parts partSet) {
+	b.mu.Lock()
+	b.dirty |= parts
+	b.mu.Unlock()
+}
+
+// build creates a snapshot of the CRS state, sharing clean parts with the
+// previous snapshot.
+//
+// Thread Safety: Caller must hold at least the CRS read lock.
+func (b *snapshotBudget) build(c *crsImpl) *snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &snapshot{
+		generation: c.generation.Load(),
+		createdAt:  time.Now().UnixMilli(),
+	}
+
+	base := b.base
+	for p := range numSnapshotParts {
+		if base != nil && !b.dirty.has(p) {
+			b.sharePart(s, base, p)
+			snapshotPartsShared.Inc()
+			continue
+		}
+		bytes := b.copyPart(s, c, p)
+		s.blocks[p] = b.newBlock(bytes, s.generation)
+	}
+
+	b.base = s
+	b.dirty = 0
+	b.track(s)
+	return s
+}
+
+// sharePart points s at base's copy of part p.
+func (b *snapshotBudget) sharePart(s, base *snapshot, p snapshotPart) {
+	switch p {
+	case partProof:
+		s.proofData = base.proofData
+	case partConstraint:
+		s.constraintData = base.constraintData
+		s.constraintIdx = base.constraintIdx
+	case partSimilarity:
+		s.similarityData = base.similarityData
+	case partDependency:
+		s.dependencyData = base.dependencyData
+	case partHistory:
+		s.historyData = base.historyData
+	case partStreaming:
+		s.streamingData = base.streamingData
+	case partClauses:
+		s.clauseData = base.clauseData
+	}
+	s.blocks[p] = base.blocks[p]
+}
+
+// copyPart deep copies part p from the CRS into s and returns its
+// estimated size in bytes.
+func (b *snapshotBudget) copyPart(s *snapshot, c *crsImpl, p snapshotPart) int64 {
+	switch p {
+	case partProof:
+		s.proofData = copyProofData(c.proofData)
+		return estimateProofBytes(s.proofData)
+	case partConstraint:
+		s.constraintData = copyConstraintData(c.constraintData)
+		s.constraintIdx = newConstraintSecondaryIndex(s.constraintData)
+		return estimateConstraintBytes(s.constraintData)
+	case partSimilarity:
+		s.similarityData = copySimilarityData(c.similarityData)
+		return estimateSimilarityBytes(s.similarityData)
+	case partDependency:
+		s.dependencyData = copyDependencyData(c.dependencyData)
+		return estimateDependencyBytes(s.dependencyData)
+	case partHistory:
+		s.historyData = copyHistoryData(c.historyData)
+		return estimateHistoryBytes(s.historyData)
+	case partStreaming:
+		s.streamingData = copyStreamingData(c.streamingData)
+		return estimateStreamingBytes(s.streamingData)
+	case partClauses:
+		s.clauseData = copyClauseData(c.clauseData)
+		return estimateClauseBytes(s.clauseData)
+	}
+	return 0
+}
+
+// newBlock accounts for a newly copied part.
+func (b *snapshotBudget) newBlock(bytes, generation int64) *retainedBlock {
+	blk := &retainedBlock{bytes: bytes, generation: generation}
+	b.retained.Add(bytes)
+	snapshotRetainedBytes.Add(float64(bytes))
+	runtime.AddCleanup(blk, releaseRetained, retainedRelease{counter: b.retained, bytes: bytes})
+	return blk
+}
+
+// track records s as live, pruning collected snapshots when the list grows.
+//
+// Thread Safety: Caller must hold b.mu.
+func (b *snapshotBudget) track(s *snapshot) {
+	b.live = append(b.live, weak.Make(s))
+	if len(b.live) < b.pruneAt {
+		return
+	}
+	b.pruneLocked()
+	b.pruneAt = max(64, 2*len(b.live))
+}
+
+// pruneLocked drops collected snapshots from the live list.
+func (b *snapshotBudget) pruneLocked() {
+	kept := b.live[:0]
+	for _, wp := range b.live {
+		if wp.Value() != nil {
+			kept = append(kept, wp)
+		}
+	}
+	clear(b.live[len(kept):])
+	b.live = kept
+}
+
+// stats returns the current memory statistics.
+func (b *snapshotBudget) stats() SnapshotMemoryStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pruneLocked()
+	stats := SnapshotMemoryStats{
+		RetainedBytes: b.retained.Load(),
+		Budget:        max(b.limit, 0),
+		LiveSnapshots: len(b.live),
+		Compactions:   b.compactions.Load(),
+	}
+	for _, wp := range b.live {
+		if s := wp.Value(); s != nil && (stats.OldestLiveGeneration == 0 || s.generation < stats.OldestLiveGeneration) {
+			stats.OldestLiveGeneration = s.generation
+		}
+	}
+	return stats
+}
+
+// enforce compacts if retained memory exceeds the budget.
+//
+// Description:
+//
+//	Compaction is rate-limited to once per minCompactionInterval because it
+//	runs a full GC. Must be called without holding the CRS lock.
+//
+// Outputs:
+//   - bool: True if a compaction ran.
+func (b *snapshotBudget) enforce() bool {
+	if b.limit <= 0 {
+		return false
+	}
+	before := b.retained.Load()
+	if before <= b.limit {
+		return false
+	}
+
+	b.mu.Lock()
+	if !b.lastCompaction.IsZero() && time.Since(b.lastCompaction) < minCompactionInterval {
+		b.mu.Unlock()
+		return false
+	}
+	b.lastCompaction = time.Now()
+	// Stop pinning the latest snapshot; the next one copies every part.
+	b.base = nil
+	b.dirty = allParts
+	b.mu.Unlock()
+
+	runtime.GC()
+	b.compactions.Add(1)
+	snapshotCompactions.Inc()
+
+	// Weak pointers are cleared by the GC itself, so the live count is
+	// accurate now even though block cleanups run asynchronously.
+	stats := b.stats()
+	if stats.LiveSnapshots > 0 {
+		b.logger.Warn("CRS snapshot memory over budget",
+			slog.Int64("retained_bytes", before),
+			slog.Int64("budget_bytes", b.limit),
+			slog.Int("live_snapshots", stats.LiveSnapshots),
+			slog.Int64("oldest_live_generation", stats.OldestLiveGeneration),
+		)
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Size Estimation
+// -----------------------------------------------------------------------------
+
+// Size estimates are approximate: they count keys, values, and string
+// contents plus a fixed per-entry overhead for map buckets, which is enough
+// to compare against a budget without walking runtime internals.
+
+const (
+	mapEntryOverhead = 16
+	stringHeaderSize = int64(unsafe.Sizeof(""))
+)
+
+func estimateString(s string) int64 {
+	return stringHeaderSize + int64(len(s))
+}
+
+func estimateProofBytes(m map[string]ProofNumber) int64 {
+	var n int64
+	for k := range m {
+		n += estimateString(k) + int64(unsafe.Sizeof(ProofNumber{})) + mapEntryOverhead
+	}
+	return n
+}
+
+func estimateConstraintBytes(m map[string]Constraint) int64 {
+	var n int64
+	for k, v := range m {
+		n += estimateString(k) + int64(unsafe.Sizeof(v)) + mapEntryOverhead
+		n += int64(len(v.ID) + len(v.Expression))
+		for _, node := range v.Nodes {
+			n += estimateString(node)
+		}
+	}
+	return n
+}
+
+func estimateSimilarityBytes(m map[string]map[string]float64) int64 {
+	var n int64
+	for k, inner := range m {
+		n += estimateString(k) + mapEntryOverhead
+		for kk := range inner {
+			n += estimateString(kk) + 8 + mapEntryOverhead
+		}
+	}
+	return n
+}
+
+func estimateDependencyBytes(g *dependencyGraph) int64 {
+	var n int64
+	for _, adj := range []map[string]map[string]struct{}{g.forward, g.reverse} {
+		for k, inner := range adj {
+			n += estimateString(k) + mapEntryOverhead
+			for kk := range inner {
+				n += estimateString(kk) + mapEntryOverhead
+			}
+		}
+	}
+	return n
+}
+
+func estimateHistoryBytes(h []HistoryEntry) int64 {
+	n := int64(len(h)) * int64(unsafe.Sizeof(HistoryEntry{}))
+	for _, e := range h {
+		n += int64(len(e.ID) + len(e.NodeID) + len(e.Action) + len(e.Result))
+		for k, v := range e.Metadata {
+			n += estimateString(k) + estimateString(v) + mapEntryOverhead
+		}
+	}
+	return n
+}
+
+func estimateStreamingBytes(s *streamingStats) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var n int64
+	for k := range s.frequencies {
+		n += estimateString(k) + 8 + mapEntryOverhead
+	}
+	if s.heavy != nil {
+		// Counter struct, heap slot, and index entry; item strings are shared.
+		n += int64(len(s.heavy.counters)) * (48 + 8 + mapEntryOverhead)
+	}
+	return n
+}
+
+func estimateClauseBytes(m map[string]*Clause) int64 {
+	var n int64
+	for k, v := range m {
+		n += estimateString(k) + int64(unsafe.Sizeof(*v)) + mapEntryOverhead
+		n += int64(len(v.ID)+len(v.SessionID)) + int64(len(v.Literals))*int64(unsafe.Sizeof(Literal{}))
+		for _, lit := range v.Literals {
+			n += int64(len(lit.Variable))
+		}
+	}
+	return n
+}
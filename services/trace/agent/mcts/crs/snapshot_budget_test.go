@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSnapshotBudget_CopyOnWrite(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unchanged parts are shared", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		s1 := c.Snapshot().(*snapshot)
+		s2 := c.Snapshot().(*snapshot)
+
+		for p := range numSnapshotParts {
+			if s1.blocks[p] == nil || s1.blocks[p] != s2.blocks[p] {
+				t.Errorf("part %d not shared between unchanged snapshots", p)
+			}
+		}
+	})
+
+	t.Run("modified index is copied, others shared", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		s1 := c.Snapshot().(*snapshot)
+
+		if _, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"node1": {Proof: 1, Disproof: 2},
+		})); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		s2 := c.Snapshot().(*snapshot)
+
+		if s1.blocks[partProof] == s2.blocks[partProof] {
+			t.Error("proof part should be copied after proof delta")
+		}
+		if s1.blocks[partConstraint] != s2.blocks[partConstraint] {
+			t.Error("constraint part should be shared after proof delta")
+		}
+		if _, ok := s1.ProofIndex().Get("node1"); ok {
+			t.Error("old snapshot sees node1 added later")
+		}
+		if _, ok := s2.ProofIndex().Get("node1"); !ok {
+			t.Error("new snapshot missing node1")
+		}
+	})
+
+	t.Run("clause changes invalidate the clause part", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		s1 := c.Snapshot()
+
+		if err := c.AddClause(ctx, &Clause{
+			ID:       "cl1",
+			Literals: []Literal{{Variable: "tool:A", Negated: true}},
+			Source:   SignalSourceHard,
+		}); err != nil {
+			t.Fatalf("AddClause: %v", err)
+		}
+		s2 := c.Snapshot()
+
+		if s1.ConstraintIndex().ClauseCount() != 0 {
+			t.Error("old snapshot sees clause added later")
+		}
+		if s2.ConstraintIndex().ClauseCount() != 1 {
+			t.Errorf("ClauseCount() = %d, want 1", s2.ConstraintIndex().ClauseCount())
+		}
+	})
+
+	t.Run("restore does not alias checkpoint data", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		cp, err := c.Checkpoint(ctx)
+		if err != nil {
+			t.Fatalf("Checkpoint: %v", err)
+		}
+		held := c.Snapshot()
+
+		if err := c.Restore(ctx, cp); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		if _, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"node1": {Proof: 1, Disproof: 2},
+		})); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		if _, ok := cp.data.(*snapshot).ProofIndex().Get("node1"); ok {
+			t.Error("write after restore reached the checkpoint")
+		}
+		if _, ok := held.ProofIndex().Get("node1"); ok {
+			t.Error("write after restore reached a snapshot sharing the checkpoint")
+		}
+	})
+}
+
+func TestSnapshotBudget_Accounting(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retained bytes count shared parts once", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		updates := make(map[string]ProofNumber, 100)
+		for i := range 100 {
+			updates[fmt.Sprintf("node%d", i)] = ProofNumber{Proof: uint64(i)}
+		}
+		if _, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, updates)); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		s1 := c.Snapshot()
+		before := c.SnapshotMemory().RetainedBytes
+		s2 := c.Snapshot()
+		after := c.SnapshotMemory().RetainedBytes
+
+		if before <= 0 {
+			t.Fatalf("RetainedBytes = %d, want > 0", before)
+		}
+		if after != before {
+			t.Errorf("RetainedBytes grew from %d to %d for an unchanged snapshot", before, after)
+		}
+		runtime.KeepAlive(s1)
+		runtime.KeepAlive(s2)
+	})
+
+	t.Run("live snapshots are tracked weakly", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		held := c.Snapshot()
+
+		stats := c.SnapshotMemory()
+		if stats.LiveSnapshots < 1 {
+			t.Errorf("LiveSnapshots = %d, want >= 1", stats.LiveSnapshots)
+		}
+		if stats.OldestLiveGeneration != held.Generation() {
+			t.Errorf("OldestLiveGeneration = %d, want %d", stats.OldestLiveGeneration, held.Generation())
+		}
+		runtime.KeepAlive(held)
+	})
+
+	t.Run("released snapshots return their bytes", func(t *testing.T) {
+		c := New(nil).(*crsImpl)
+		if _, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"node1": {Proof: 1},
+		})); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		_ = c.Snapshot()
+
+		// Drop the budget's own reference so nothing pins the parts.
+		c.snapshots.mu.Lock()
+		c.snapshots.base = nil
+		c.snapshots.mu.Unlock()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for c.SnapshotMemory().RetainedBytes != 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("RetainedBytes = %d after GC, want 0", c.SnapshotMemory().RetainedBytes)
+			}
+			runtime.GC()
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("over budget triggers compaction", func(t *testing.T) {
+		config := DefaultConfig()
+		config.SnapshotMemoryBudget = 1
+		c := New(config).(*crsImpl)
+		if _, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"node1": {Proof: 1},
+		})); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		_ = c.Snapshot()
+
+		if c.SnapshotMemory().Compactions == 0 {
+			t.Error("Compactions = 0, want > 0 after exceeding budget")
+		}
+		c.snapshots.mu.Lock()
+		base := c.snapshots.base
+		c.snapshots.mu.Unlock()
+		if base != nil {
+			t.Error("compaction should drop the shared base snapshot")
+		}
+	})
+
+	t.Run("unlimited budget never compacts", func(t *testing.T) {
+		config := DefaultConfig()
+		config.SnapshotMemoryBudget = 0
+		c := New(config).(*crsImpl)
+		for range 3 {
+			_ = c.Snapshot()
+		}
+		if got := c.SnapshotMemory().Compactions; got != 0 {
+			t.Errorf("Compactions = %d, want 0", got)
+		}
+	})
+}
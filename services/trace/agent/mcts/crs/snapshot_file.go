@@ -0,0 +1,657 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Snapshot File Format
+// -----------------------------------------------------------------------------
+//
+// A snapshot file is a compact binary encoding of the six indexes plus the
+// generation counter:
+//
+//	magic      [4]byte  "CRSS"
+//	version    uvarint  snapshotFileVersion
+//	generation varint
+//	proof      uvarint count, then per entry (sorted by node ID):
+//	           string id, uvarint proof, uvarint disproof, uvarint status,
+//	           uvarint source, varint updatedAt
+//	constraint uvarint count, then per constraint (sorted by ID):
+//	           string id, uvarint type, strings nodes, string expression,
+//	           bool active, uvarint source, varint createdAt
+//	           uvarint count, then per learned clause (sorted by ID):
+//	           string id, uvarint count + (string variable, bool negated)...,
+//	           uvarint source, varint learnedAt, string failureType,
+//	           string sessionID, varint useCount, varint lastUsed
+//	similarity uvarint count, then per node (sorted): string id,
+//	           uvarint count + (string id, float64 bits)...
+//	dependency uvarint count, then per node (sorted): string id, strings deps
+//	history    uvarint count, then per entry (in order): string id,
+//	           string nodeID, string action, string result, uvarint source,
+//	           varint timestamp, uvarint count + (string key, string value)...
+//	streaming  uvarint cardinality, uvarint count, then per item (sorted):
+//	           string item, uvarint frequency
+//	checksum   [4]byte  big-endian CRC-32 (IEEE) of everything above
+//
+// Strings are a uvarint length followed by the bytes. Map entries are
+// written in sorted key order so identical state produces identical files.
+// Only the forward dependency edges are stored; the reverse index is rebuilt
+// on restore.
+
+// snapshotFileMagic identifies a CRS snapshot file.
+var snapshotFileMagic = [4]byte{'C', 'R', 'S', 'S'}
+
+// snapshotFileVersion is the current snapshot file format version.
+const snapshotFileVersion = 1
+
+var (
+	// ErrSnapshotFileCorrupted indicates a snapshot file failed to decode or
+	// its checksum did not match.
+	ErrSnapshotFileCorrupted = errors.New("snapshot file corrupted")
+
+	// ErrSnapshotFileVersion indicates a snapshot file was written by an
+	// incompatible format version.
+	ErrSnapshotFileVersion = errors.New("snapshot file version not supported")
+)
+
+// persistedState holds decoded snapshot file contents before they are
+// swapped into a CRS.
+type persistedState struct {
+	generation     int64
+	proofData      map[string]ProofNumber
+	constraintData map[string]Constraint
+	clauseData     map[string]*Clause
+	similarityData map[string]map[string]float64
+	dependencyData *dependencyGraph
+	historyData    []HistoryEntry
+	streamingData  *streamingStats
+}
+
+// -----------------------------------------------------------------------------
+// Persist / RestoreFrom
+// -----------------------------------------------------------------------------
+
+// Persist writes the current state to a snapshot file.
+//
+// Description:
+//
+//	Encodes all six indexes, the learned clauses, and the generation counter
+//	into the compact binary snapshot format and writes it atomically (temp
+//	file + fsync + rename). Step history, analytics history, and delta
+//	history are not persisted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - path: Destination file. Parent directories are created if needed.
+//
+// Outputs:
+//   - error: Non-nil on failure. An existing file at path is left untouched.
+//
+// Thread Safety: Safe for concurrent use. Holds the read lock while encoding.
+func (c *crsImpl) Persist(ctx context.Context, path string) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if path == "" {
+		return fmt.Errorf("persist: path must not be empty")
+	}
+
+	ctx, span := otel.Tracer("crs").Start(ctx, "crs.Persist",
+		trace.WithAttributes(attribute.String("path", path)),
+	)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	generation := c.generation.Load()
+	data := encodeSnapshotFile(c, generation)
+	c.mu.RUnlock()
+
+	if err := writeFileAtomic(path, data); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "write snapshot failed")
+		return fmt.Errorf("persist: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int64("generation", generation),
+		attribute.Int("bytes", len(data)),
+	)
+
+	loggerWithTrace(ctx, c.logger).Info("CRS snapshot persisted",
+		slog.String("path", path),
+		slog.Int64("generation", generation),
+		slog.Int("bytes", len(data)),
+	)
+
+	return nil
+}
+
+// RestoreFrom replaces the current state with a snapshot file.
+//
+// Description:
+//
+//	Reads and verifies a file written by Persist, then swaps the decoded
+//	indexes and generation counter in atomically. On any error the current
+//	state is left unchanged. Step history, analytics history, and delta
+//	history are kept as they are.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - path: Snapshot file written by Persist.
+//
+// Outputs:
+//   - error: Non-nil on failure. Wraps ErrSnapshotFileCorrupted or
+//     ErrSnapshotFileVersion for invalid files, and os.ErrNotExist if the
+//     file does not exist.
+//
+// Thread Safety: Safe for concurrent use. Acquires write lock for the swap.
+func (c *crsImpl) RestoreFrom(ctx context.Context, path string) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	ctx, span := otel.Tracer("crs").Start(ctx, "crs.RestoreFrom",
+		trace.WithAttributes(attribute.String("path", path)),
+	)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read snapshot failed")
+		return fmt.Errorf("restore from %s: %w", path, err)
+	}
+
+	state, err := decodeSnapshotFile(data)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "decode snapshot failed")
+		return fmt.Errorf("restore from %s: %w", path, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.proofData = state.proofData
+	c.constraintData = state.constraintData
+	c.clauseData = state.clauseData
+	c.similarityData = state.similarityData
+	c.dependencyData = state.dependencyData
+	c.historyData = state.historyData
+	c.streamingData = state.streamingData
+	c.generation.Store(state.generation)
+	c.snapshots.markDirty(allParts)
+	c.publishRestored(state.generation)
+	c.mu.Unlock()
+
+	span.SetAttributes(
+		attribute.Int64("generation", state.generation),
+		attribute.Int("bytes", len(data)),
+	)
+
+	loggerWithTrace(ctx, c.logger).Info("CRS snapshot restored",
+		slog.String("path", path),
+		slog.Int64("generation", state.generation),
+	)
+
+	return nil
+}
+
+// writeFileAtomic writes data to path via a synced temp file and rename.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// -----------------------------------------------------------------------------
+// Encoding
+// -----------------------------------------------------------------------------
+
+// encodeSnapshotFile encodes the CRS state. Caller must hold c.mu (read).
+func encodeSnapshotFile(c *crsImpl, generation int64) []byte {
+	w := &snapshotWriter{}
+	w.buf.Write(snapshotFileMagic[:])
+	w.uvarint(snapshotFileVersion)
+	w.varint(generation)
+
+	// Proof index
+	w.uvarint(uint64(len(c.proofData)))
+	for _, id := range sortedKeys(c.proofData) {
+		pn := c.proofData[id]
+		w.string(id)
+		w.uvarint(pn.Proof)
+		w.uvarint(pn.Disproof)
+		w.uvarint(uint64(pn.Status))
+		w.uvarint(uint64(pn.Source))
+		w.varint(pn.UpdatedAt)
+	}
+
+	// Constraint index, including learned clauses
+	w.uvarint(uint64(len(c.constraintData)))
+	for _, id := range sortedKeys(c.constraintData) {
+		con := c.constraintData[id]
+		w.string(id)
+		w.uvarint(uint64(con.Type))
+		w.strings(con.Nodes)
+		w.string(con.Expression)
+		w.bool(con.Active)
+		w.uvarint(uint64(con.Source))
+		w.varint(con.CreatedAt)
+	}
+	w.uvarint(uint64(len(c.clauseData)))
+	for _, id := range sortedKeys(c.clauseData) {
+		cl := c.clauseData[id]
+		w.string(id)
+		w.uvarint(uint64(len(cl.Literals)))
+		for _, lit := range cl.Literals {
+			w.string(lit.Variable)
+			w.bool(lit.Negated)
+		}
+		w.uvarint(uint64(cl.Source))
+		w.varint(cl.LearnedAt)
+		w.string(string(cl.FailureType))
+		w.string(cl.SessionID)
+		w.varint(cl.UseCount)
+		w.varint(cl.LastUsed)
+	}
+
+	// Similarity index
+	w.uvarint(uint64(len(c.similarityData)))
+	for _, from := range sortedKeys(c.similarityData) {
+		row := c.similarityData[from]
+		w.string(from)
+		w.uvarint(uint64(len(row)))
+		for _, to := range sortedKeys(row) {
+			w.string(to)
+			w.uint64(math.Float64bits(row[to]))
+		}
+	}
+
+	// Dependency index (forward edges only)
+	w.uvarint(uint64(len(c.dependencyData.forward)))
+	for _, from := range sortedKeys(c.dependencyData.forward) {
+		w.string(from)
+		w.strings(sortedKeys(c.dependencyData.forward[from]))
+	}
+
+	// History index
+	w.uvarint(uint64(len(c.historyData)))
+	for _, h := range c.historyData {
+		w.string(h.ID)
+		w.string(h.NodeID)
+		w.string(h.Action)
+		w.string(h.Result)
+		w.uvarint(uint64(h.Source))
+		w.varint(h.Timestamp)
+		w.uvarint(uint64(len(h.Metadata)))
+		for _, k := range sortedKeys(h.Metadata) {
+			w.string(k)
+			w.string(h.Metadata[k])
+		}
+	}
+
+	// Streaming index
+	c.streamingData.mu.RLock()
+	w.uvarint(c.streamingData.cardinality)
+	w.uvarint(uint64(len(c.streamingData.frequencies)))
+	for _, item := range sortedKeys(c.streamingData.frequencies) {
+		w.string(item)
+		w.uvarint(c.streamingData.frequencies[item])
+	}
+	c.streamingData.mu.RUnlock()
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(w.buf.Bytes()))
+	w.buf.Write(sum[:])
+
+	return w.buf.Bytes()
+}
+
+// snapshotWriter appends snapshot primitives to a buffer.
+type snapshotWriter struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *snapshotWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *snapshotWriter) varint(v int64) {
+	n := binary.PutVarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *snapshotWriter) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.scratch[:8], v)
+	w.buf.Write(w.scratch[:8])
+}
+
+func (w *snapshotWriter) bool(v bool) {
+	if v {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+}
+
+func (w *snapshotWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *snapshotWriter) strings(ss []string) {
+	w.uvarint(uint64(len(ss)))
+	for _, s := range ss {
+		w.string(s)
+	}
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// -----------------------------------------------------------------------------
+// Decoding
+// -----------------------------------------------------------------------------
+
+// decodeSnapshotFile verifies and decodes a snapshot file.
+func decodeSnapshotFile(data []byte) (*persistedState, error) {
+	if len(data) < len(snapshotFileMagic)+4 || !bytes.Equal(data[:4], snapshotFileMagic[:]) {
+		return nil, fmt.Errorf("%w: not a CRS snapshot file", ErrSnapshotFileCorrupted)
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrSnapshotFileCorrupted)
+	}
+
+	r := &snapshotReader{data: body, pos: len(snapshotFileMagic)}
+	if version := r.uvarint(); r.err == nil && version != snapshotFileVersion {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrSnapshotFileVersion, version, snapshotFileVersion)
+	}
+
+	state := &persistedState{generation: r.varint()}
+
+	// Proof index
+	n := r.count()
+	state.proofData = make(map[string]ProofNumber, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		id := r.string()
+		state.proofData[id] = ProofNumber{
+			Proof:     r.uvarint(),
+			Disproof:  r.uvarint(),
+			Status:    ProofStatus(r.uvarint()),
+			Source:    SignalSource(r.uvarint()),
+			UpdatedAt: r.varint(),
+		}
+	}
+
+	// Constraint index, including learned clauses
+	n = r.count()
+	state.constraintData = make(map[string]Constraint, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		id := r.string()
+		state.constraintData[id] = Constraint{
+			ID:         id,
+			Type:       ConstraintType(r.uvarint()),
+			Nodes:      r.strings(),
+			Expression: r.string(),
+			Active:     r.bool(),
+			Source:     SignalSource(r.uvarint()),
+			CreatedAt:  r.varint(),
+		}
+	}
+	n = r.count()
+	state.clauseData = make(map[string]*Clause, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		cl := &Clause{ID: r.string()}
+		lits := r.count()
+		cl.Literals = make([]Literal, 0, lits)
+		for j := 0; j < lits && r.err == nil; j++ {
+			cl.Literals = append(cl.Literals, Literal{Variable: r.string(), Negated: r.bool()})
+		}
+		cl.Source = SignalSource(r.uvarint())
+		cl.LearnedAt = r.varint()
+		cl.FailureType = FailureType(r.string())
+		cl.SessionID = r.string()
+		cl.UseCount = r.varint()
+		cl.LastUsed = r.varint()
+		state.clauseData[cl.ID] = cl
+	}
+
+	// Similarity index
+	n = r.count()
+	state.similarityData = make(map[string]map[string]float64, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		from := r.string()
+		m := r.count()
+		row := make(map[string]float64, m)
+		for j := 0; j < m && r.err == nil; j++ {
+			to := r.string()
+			row[to] = math.Float64frombits(r.uint64())
+		}
+		state.similarityData[from] = row
+	}
+
+	// Dependency index
+	n = r.count()
+	state.dependencyData = newDependencyGraph()
+	for i := 0; i < n && r.err == nil; i++ {
+		from := r.string()
+		for _, to := range r.strings() {
+			state.dependencyData.addEdge(from, to)
+		}
+	}
+
+	// History index
+	n = r.count()
+	state.historyData = make([]HistoryEntry, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		h := HistoryEntry{
+			ID:        r.string(),
+			NodeID:    r.string(),
+			Action:    r.string(),
+			Result:    r.string(),
+			Source:    SignalSource(r.uvarint()),
+			Timestamp: r.varint(),
+		}
+		if m := r.count(); m > 0 {
+			h.Metadata = make(map[string]string, m)
+			for j := 0; j < m && r.err == nil; j++ {
+				k := r.string()
+				h.Metadata[k] = r.string()
+			}
+		}
+		state.historyData = append(state.historyData, h)
+	}
+
+	// Streaming index
+	state.streamingData = newStreamingStats()
+	state.streamingData.cardinality = r.uvarint()
+	n = r.count()
+	for i := 0; i < n && r.err == nil; i++ {
+		item := r.string()
+		state.streamingData.frequencies[item] = r.uvarint()
+	}
+	state.streamingData.heavy = newSpaceSavingFrom(state.streamingData.frequencies, HeavyHitterCapacity)
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.pos != len(r.data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrSnapshotFileCorrupted, len(r.data)-r.pos)
+	}
+	return state, nil
+}
+
+// snapshotReader decodes snapshot primitives. The first error is sticky;
+// subsequent reads return zero values.
+type snapshotReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *snapshotReader) fail(what string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: truncated %s at offset %d", ErrSnapshotFileCorrupted, what, r.pos)
+	}
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail("uvarint")
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *snapshotReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail("varint")
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *snapshotReader) uint64() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data)-r.pos < 8 {
+		r.fail("uint64")
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return v
+}
+
+func (r *snapshotReader) bool() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.pos >= len(r.data) {
+		r.fail("bool")
+		return false
+	}
+	v := r.data[r.pos] != 0
+	r.pos++
+	return v
+}
+
+// count reads a collection length. Every element takes at least one byte,
+// so a length larger than the remaining input is rejected before anything
+// is allocated.
+func (r *snapshotReader) count() int {
+	v := r.uvarint()
+	if r.err == nil && v > uint64(len(r.data)-r.pos) {
+		r.fail("collection")
+		return 0
+	}
+	return int(v)
+}
+
+func (r *snapshotReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.data[r.pos : r.pos+n])
+	r.pos += n
+	return s
+}
+
+func (r *snapshotReader) strings() []string {
+	n := r.count()
+	if n == 0 {
+		return nil
+	}
+	ss := make([]string, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		ss = append(ss, r.string())
+	}
+	return ss
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// populatedCRS returns a CRS with data in every index.
+func populatedCRS(t *testing.T) *crsImpl {
+	t.Helper()
+	c := New(nil).(*crsImpl)
+	ctx := context.Background()
+
+	_, err := c.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+		"node1": {Proof: 10, Disproof: 20, Status: ProofStatusExpanded, Source: SignalSourceHard, UpdatedAt: 1700},
+		"node2": {Proof: 0, Disproof: 1 << 40, Status: ProofStatusProven, Source: SignalSourceSoft},
+	}))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	c.mu.Lock()
+	c.constraintData["c1"] = Constraint{
+		ID: "c1", Type: ConstraintTypeOrdering, Nodes: []string{"a", "b"},
+		Expression: "a < b", Active: true, Source: SignalSourceHard, CreatedAt: -5,
+	}
+	c.clauseData["cl1"] = &Clause{
+		ID:          "cl1",
+		Literals:    []Literal{{Variable: "tool:grep", Negated: true}, {Variable: "prev:find"}},
+		Source:      SignalSourceHard,
+		LearnedAt:   42,
+		FailureType: FailureTypeCycleDetected,
+		SessionID:   "s1",
+		UseCount:    3,
+		LastUsed:    99,
+	}
+	c.similarityData["a"] = map[string]float64{"b": 0.25, "c": 1.0 / 3}
+	c.dependencyData.addEdge("a", "b")
+	c.dependencyData.addEdge("a", "c")
+	c.historyData = append(c.historyData,
+		HistoryEntry{ID: "h1", NodeID: "a", Action: "expand", Result: "ok", Source: SignalSourceSoft, Timestamp: 7,
+			Metadata: map[string]string{"k": "v"}},
+		HistoryEntry{ID: "h2", NodeID: "b", Action: "prune"},
+	)
+	c.streamingData.frequencies["grep"] = 12
+	c.streamingData.cardinality = 5
+	c.mu.Unlock()
+
+	return c
+}
+
+func TestCRS_PersistRestoreFrom(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round trips all indexes and generation", func(t *testing.T) {
+		src := populatedCRS(t)
+		path := filepath.Join(t.TempDir(), "state", "crs.snap")
+
+		if err := src.Persist(ctx, path); err != nil {
+			t.Fatalf("Persist: %v", err)
+		}
+
+		dst := New(nil).(*crsImpl)
+		if err := dst.RestoreFrom(ctx, path); err != nil {
+			t.Fatalf("RestoreFrom: %v", err)
+		}
+
+		if dst.Generation() != src.Generation() {
+			t.Errorf("generation = %d, want %d", dst.Generation(), src.Generation())
+		}
+		checks := []struct {
+			name      string
+			got, want any
+		}{
+			{"proof", dst.proofData, src.proofData},
+			{"constraint", dst.constraintData, src.constraintData},
+			{"clause", dst.clauseData, src.clauseData},
+			{"similarity", dst.similarityData, src.similarityData},
+			{"dependency forward", dst.dependencyData.forward, src.dependencyData.forward},
+			{"dependency reverse", dst.dependencyData.reverse, src.dependencyData.reverse},
+			{"history", dst.historyData, src.historyData},
+			{"streaming", dst.streamingData.frequencies, src.streamingData.frequencies},
+		}
+		for _, c := range checks {
+			if !reflect.DeepEqual(c.got, c.want) {
+				t.Errorf("%s index = %+v, want %+v", c.name, c.got, c.want)
+			}
+		}
+		if dst.streamingData.cardinality != 5 {
+			t.Errorf("cardinality = %d, want 5", dst.streamingData.cardinality)
+		}
+	})
+
+	t.Run("output is deterministic", func(t *testing.T) {
+		c := populatedCRS(t)
+		dir := t.TempDir()
+		a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+		if err := c.Persist(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Persist(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		da, _ := os.ReadFile(a)
+		db, _ := os.ReadFile(b)
+		if !bytes.Equal(da, db) {
+			t.Error("identical state produced different files")
+		}
+	})
+
+	t.Run("corrupted file leaves state unchanged", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "crs.snap")
+		if err := populatedCRS(t).Persist(ctx, path); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		data[len(data)/2] ^= 0xFF
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := New(nil).(*crsImpl)
+		_, _ = dst.Apply(ctx, NewProofDelta(SignalSourceHard, map[string]ProofNumber{"keep": {Proof: 1}}))
+		err := dst.RestoreFrom(ctx, path)
+		if !errors.Is(err, ErrSnapshotFileCorrupted) {
+			t.Fatalf("error = %v, want ErrSnapshotFileCorrupted", err)
+		}
+		if _, ok := dst.Snapshot().ProofIndex().Get("keep"); !ok {
+			t.Error("state was modified by failed restore")
+		}
+	})
+
+	t.Run("truncated file is rejected", func(t *testing.T) {
+		data := encodeSnapshotFile(populatedCRS(t), 1)
+		for _, n := range []int{0, 3, 8, len(data) - 1} {
+			if _, err := decodeSnapshotFile(data[:n]); !errors.Is(err, ErrSnapshotFileCorrupted) {
+				t.Errorf("len %d: error = %v, want ErrSnapshotFileCorrupted", n, err)
+			}
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := New(nil).RestoreFrom(ctx, filepath.Join(t.TempDir(), "none"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("error = %v, want os.ErrNotExist", err)
+		}
+	})
+
+	t.Run("nil context returns error", func(t *testing.T) {
+		c := New(nil)
+		if err := c.Persist(nil, "x"); !errors.Is(err, ErrNilContext) { //nolint:staticcheck
+			t.Errorf("Persist error = %v, want %v", err, ErrNilContext)
+		}
+		if err := c.RestoreFrom(nil, "x"); !errors.Is(err, ErrNilContext) { //nolint:staticcheck
+			t.Errorf("RestoreFrom error = %v, want %v", err, ErrNilContext)
+		}
+	})
+}
@@ -12,6 +12,7 @@ package crs
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -190,6 +191,65 @@ func TestConstraintIndexView(t *testing.T) {
 	})
 }
 
+func TestConstraintIndexView_Temporal(t *testing.T) {
+	c := New(nil)
+	ctx := context.Background()
+
+	delta := NewConstraintDelta(SignalSourceHard)
+	delta.Add = []Constraint{
+		{ID: "t1", Type: ConstraintTypeBefore, Nodes: []string{"a", "b"}, Active: true},
+		{ID: "t2", Type: ConstraintTypeDuring, Nodes: []string{"c", "b"}, Active: true},
+		{ID: "t3", Type: ConstraintTypeAfter, Nodes: []string{"d", "a"}, Active: false},
+		{ID: "m1", Type: ConstraintTypeMutualExclusion, Nodes: []string{"a", "c"}, Active: true},
+	}
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	cv := c.Snapshot().ConstraintIndex()
+
+	t.Run("FindTemporal", func(t *testing.T) {
+		got := cv.FindTemporal("a")
+		if len(got) != 2 || got[0].ID != "t1" || got[1].ID != "t3" {
+			t.Errorf("FindTemporal(a) = %v, want [t1 t3]", got)
+		}
+		if got := cv.FindTemporal("x"); len(got) != 0 {
+			t.Errorf("FindTemporal(x) = %v, want empty", got)
+		}
+	})
+
+	t.Run("FindByNode includes temporal", func(t *testing.T) {
+		if got := cv.FindByNode("a"); len(got) != 3 {
+			t.Errorf("FindByNode(a) returned %d constraints, want 3", len(got))
+		}
+	})
+
+	t.Run("TemporalEdges skips inactive", func(t *testing.T) {
+		want := []TemporalEdge{
+			{ConstraintID: "t1", From: TemporalBound{"a", EditEnd}, To: TemporalBound{"b", EditStart}},
+			{ConstraintID: "t2", From: TemporalBound{"b", EditStart}, To: TemporalBound{"c", EditStart}},
+			{ConstraintID: "t2", From: TemporalBound{"c", EditEnd}, To: TemporalBound{"b", EditEnd}},
+		}
+		got := cv.TemporalEdges()
+		if len(got) != len(want) {
+			t.Fatalf("TemporalEdges() = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("edge %d = %v, want %v", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("rejects temporal constraint without two nodes", func(t *testing.T) {
+		bad := NewConstraintDelta(SignalSourceHard)
+		bad.Add = []Constraint{{ID: "t4", Type: ConstraintTypeBefore, Nodes: []string{"a", "a"}}}
+		if _, err := c.Apply(ctx, bad); !errors.Is(err, ErrDeltaValidation) {
+			t.Errorf("Apply error = %v, want ErrDeltaValidation", err)
+		}
+	})
+}
+
 func TestSimilarityIndexView(t *testing.T) {
 	c := New(nil)
 	ctx := context.Background()
@@ -773,6 +773,12 @@ func stringToConstraintType(constraintType string) ConstraintType {
 		return ConstraintTypeOrdering
 	case "resource":
 		return ConstraintTypeResource
+	case "before":
+		return ConstraintTypeBefore
+	case "after":
+		return ConstraintTypeAfter
+	case "during":
+		return ConstraintTypeDuring
 	default:
 		return ConstraintTypeUnknown
 	}
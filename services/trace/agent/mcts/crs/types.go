@@ -110,6 +110,26 @@ type CRS interface {
 	// Thread Safety: Safe for concurrent use.
 	Generation() int64
 
+	// Watch subscribes to generation changes.
+	//
+	// Description:
+	//
+	//   Returns a channel receiving one WatchEvent per successful Apply,
+	//   Restore, or RestoreFrom matching opts, in generation order, so
+	//   callers can react to state changes without polling Generation().
+	//   Delivery never blocks Apply; dropped events are counted in the
+	//   next event's Missed field. The channel closes when ctx is done.
+	//
+	// Inputs:
+	//   - ctx: Controls the subscription lifetime. Must not be nil.
+	//   - opts: Index mask and node prefix filters.
+	//
+	// Outputs:
+	//   - <-chan WatchEvent: The event stream.
+	//
+	// Thread Safety: Safe for concurrent use.
+	Watch(ctx context.Context, opts WatchOptions) <-chan WatchEvent
+
 	// Checkpoint creates a restorable checkpoint for chaos testing.
 	//
 	// Description:
@@ -144,6 +164,70 @@ type CRS interface {
 	// Thread Safety: Safe for concurrent use. Acquires write lock.
 	Restore(ctx context.Context, cp Checkpoint) error
 
+	// Persist writes the six indexes and the generation counter to disk.
+	//
+	// Description:
+	//
+	//   Encodes the state in a compact binary format and writes it
+	//   atomically, so long-running sessions can survive a process restart.
+	//   Step, analytics, and delta history are not included.
+	//
+	// Inputs:
+	//   - ctx: Context for cancellation. Must not be nil.
+	//   - path: Destination file. Parent directories are created if needed.
+	//
+	// Outputs:
+	//   - error: Non-nil if encoding or writing failed.
+	//
+	// Thread Safety: Safe for concurrent use. Acquires read lock.
+	Persist(ctx context.Context, path string) error
+
+	// RestoreFrom replaces the current state with a file written by Persist.
+	//
+	// Inputs:
+	//   - ctx: Context for cancellation. Must not be nil.
+	//   - path: Snapshot file written by Persist.
+	//
+	// Outputs:
+	//   - error: Non-nil if the file is missing, corrupted, or from an
+	//     incompatible version. State is unchanged on error.
+	//
+	// Thread Safety: Safe for concurrent use. Acquires write lock.
+	RestoreFrom(ctx context.Context, path string) error
+
+	// SetDeltaJournal attaches an append-only audit journal.
+	//
+	// Description:
+	//
+	//   Every subsequent successful Apply() appends its delta, tagged with
+	//   the generation it produced. Journal write failures are logged and
+	//   do not fail the apply. Pass nil to detach.
+	//
+	// Thread Safety: Safe for concurrent use.
+	SetDeltaJournal(journal *DeltaJournal)
+
+	// ReplayJournal reconstructs a historical state from the delta journal.
+	//
+	// Description:
+	//
+	//   Applies the journaled deltas for generations from..to to a new,
+	//   detached CRS. Replaying from generation 1 reproduces the exact state
+	//   at generation to. Use for debugging why a search diverged and for
+	//   auditing how a state was reached.
+	//
+	// Inputs:
+	//   - ctx: Context for cancellation. Must not be nil.
+	//   - from: First generation to replay. Must be >= 1.
+	//   - to: Last generation to replay. Must be >= from.
+	//
+	// Outputs:
+	//   - CRS: The reconstructed state, at generation to.
+	//   - error: Non-nil if no journal is attached, the range is invalid or
+	//     incomplete, or a delta fails to replay.
+	//
+	// Thread Safety: Safe for concurrent use. Does not modify this CRS.
+	ReplayJournal(ctx context.Context, from, to int64) (CRS, error)
+
 	// -------------------------------------------------------------------------
 	// StepRecord Methods (CRS-01)
 	// -------------------------------------------------------------------------
@@ -662,6 +746,14 @@ type ConstraintIndexView interface {
 	// FindByNode returns all constraints affecting a node.
 	FindByNode(nodeID string) []Constraint
 
+	// FindTemporal returns the temporal constraints (Before, After, During)
+	// affecting a node.
+	FindTemporal(nodeID string) []Constraint
+
+	// TemporalEdges returns the precedence edges of all active temporal
+	// constraints, sorted by constraint ID. See TemporalEdge.
+	TemporalEdges() []TemporalEdge
+
 	// All returns all constraints.
 	All() map[string]Constraint
 
@@ -818,6 +910,11 @@ type StreamingIndexView interface {
 
 	// Size returns the approximate memory usage in bytes.
 	Size() int
+
+	// TopK returns up to k of the most frequent items, highest count first.
+	// Counts are SpaceSaving estimates; see HeavyHitter for error bounds.
+	// Only the HeavyHitterCapacity most frequent items are tracked.
+	TopK(k int) []HeavyHitter
 }
 
 // -----------------------------------------------------------------------------
@@ -988,6 +1085,18 @@ const (
 
 	// ConstraintTypeResource means nodes share a resource limit.
 	ConstraintTypeResource
+
+	// ConstraintTypeBefore means the edit to Nodes[0] must finish before
+	// the edit to Nodes[1] starts.
+	ConstraintTypeBefore
+
+	// ConstraintTypeAfter means the edit to Nodes[0] must start after the
+	// edit to Nodes[1] finishes.
+	ConstraintTypeAfter
+
+	// ConstraintTypeDuring means the edit to Nodes[0] must start and finish
+	// while the edit to Nodes[1] is in progress.
+	ConstraintTypeDuring
 )
 
 // String returns the string representation of ConstraintType.
@@ -1003,11 +1112,116 @@ func (t ConstraintType) String() string {
 		return "ordering"
 	case ConstraintTypeResource:
 		return "resource"
+	case ConstraintTypeBefore:
+		return "before"
+	case ConstraintTypeAfter:
+		return "after"
+	case ConstraintTypeDuring:
+		return "during"
 	default:
 		return fmt.Sprintf("ConstraintType(%d)", t)
 	}
 }
 
+// IsTemporal returns true for constraints on the order of edits over time
+// (Before, After, During). Temporal constraints relate exactly two nodes.
+func (t ConstraintType) IsTemporal() bool {
+	switch t {
+	case ConstraintTypeBefore, ConstraintTypeAfter, ConstraintTypeDuring:
+		return true
+	default:
+		return false
+	}
+}
+
+// EditPoint identifies one end of the interval in which a node is edited.
+type EditPoint int
+
+const (
+	// EditStart is the moment an edit begins.
+	EditStart EditPoint = iota
+
+	// EditEnd is the moment an edit completes.
+	EditEnd
+)
+
+// String returns the string representation of EditPoint.
+func (p EditPoint) String() string {
+	switch p {
+	case EditStart:
+		return "start"
+	case EditEnd:
+		return "end"
+	default:
+		return fmt.Sprintf("EditPoint(%d)", p)
+	}
+}
+
+// TemporalBound is one endpoint of a node's edit interval.
+type TemporalBound struct {
+	// NodeID is the edited node.
+	NodeID string
+
+	// Point is the start or end of the edit.
+	Point EditPoint
+}
+
+// String returns "<node>@start" or "<node>@end".
+func (b TemporalBound) String() string {
+	return b.NodeID + "@" + b.Point.String()
+}
+
+// TemporalEdge states that one edit endpoint strictly precedes another.
+//
+// Description:
+//
+//	Temporal constraints are normalized into edges between interval
+//	endpoints so propagation algorithms see a single relation:
+//
+//	  Before(a, b): a@end   < b@start
+//	  After(a, b):  b@end   < a@start
+//	  During(a, b): b@start < a@start, a@end < b@end
+//
+//	The implicit start < end of each edit is not included.
+type TemporalEdge struct {
+	// ConstraintID is the constraint the edge was derived from.
+	ConstraintID string
+
+	// From is the endpoint that happens first.
+	From TemporalBound
+
+	// To is the endpoint that happens later.
+	To TemporalBound
+}
+
+// TemporalEdges returns the precedence edges implied by a temporal
+// constraint, or nil for non-temporal or malformed constraints.
+func (c Constraint) TemporalEdges() []TemporalEdge {
+	if !c.Type.IsTemporal() || len(c.Nodes) != 2 {
+		return nil
+	}
+	a, b := c.Nodes[0], c.Nodes[1]
+	edge := func(from string, fp EditPoint, to string, tp EditPoint) TemporalEdge {
+		return TemporalEdge{
+			ConstraintID: c.ID,
+			From:         TemporalBound{NodeID: from, Point: fp},
+			To:           TemporalBound{NodeID: to, Point: tp},
+		}
+	}
+	switch c.Type {
+	case ConstraintTypeBefore:
+		return []TemporalEdge{edge(a, EditEnd, b, EditStart)}
+	case ConstraintTypeAfter:
+		return []TemporalEdge{edge(b, EditEnd, a, EditStart)}
+	case ConstraintTypeDuring:
+		return []TemporalEdge{
+			edge(b, EditStart, a, EditStart),
+			edge(a, EditEnd, b, EditEnd),
+		}
+	}
+	return nil
+}
+
 // SimilarityMatch represents a similarity search result.
 type SimilarityMatch struct {
 	// NodeID is the matching node.
@@ -1304,6 +1518,10 @@ type ApplyMetrics struct {
 
 	// NewGeneration is the generation after apply.
 	NewGeneration int64
+
+	// Compression reports how a CompositeDelta was compressed before
+	// apply. Nil for other delta types.
+	Compression *CompressionStats
 }
 
 // -----------------------------------------------------------------------------
@@ -1346,15 +1564,22 @@ type Config struct {
 	// EnableTracing enables OpenTelemetry tracing.
 	// Default: true.
 	EnableTracing bool
+
+	// SnapshotMemoryBudget caps the estimated bytes retained by live
+	// snapshots. When exceeded, snapshots are compacted and a warning is
+	// logged if live snapshots alone still exceed it.
+	// Default: DefaultSnapshotMemoryBudget (512MB). 0 means unlimited.
+	SnapshotMemoryBudget int64
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		MaxGeneration:      0,
-		SnapshotEpochLimit: 1000,
-		EnableMetrics:      true,
-		EnableTracing:      true,
+		MaxGeneration:        0,
+		SnapshotEpochLimit:   1000,
+		EnableMetrics:        true,
+		EnableTracing:        true,
+		SnapshotMemoryBudget: DefaultSnapshotMemoryBudget,
 	}
 }
 
@@ -1363,6 +1588,9 @@ func (c *Config) Validate() error {
 	if c.SnapshotEpochLimit < 0 {
 		return errors.New("snapshot epoch limit must be non-negative")
 	}
+	if c.SnapshotMemoryBudget < 0 {
+		return errors.New("snapshot memory budget must be non-negative")
+	}
 	return nil
 }
 
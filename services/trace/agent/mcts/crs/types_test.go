@@ -106,6 +106,9 @@ func TestConstraintType_String(t *testing.T) {
 		{ConstraintTypeImplication, "implication"},
 		{ConstraintTypeOrdering, "ordering"},
 		{ConstraintTypeResource, "resource"},
+		{ConstraintTypeBefore, "before"},
+		{ConstraintTypeAfter, "after"},
+		{ConstraintTypeDuring, "during"},
 		{ConstraintType(99), "ConstraintType(99)"},
 	}
 
@@ -168,6 +171,15 @@ func TestConfig_Validate(t *testing.T) {
 			t.Errorf("zero SnapshotEpochLimit should be valid: %v", err)
 		}
 	})
+
+	t.Run("negative snapshot memory budget", func(t *testing.T) {
+		config := &Config{
+			SnapshotMemoryBudget: -1,
+		}
+		if err := config.Validate(); err == nil {
+			t.Error("negative SnapshotMemoryBudget should fail")
+		}
+	})
 }
 
 func TestDefaultConfig(t *testing.T) {
@@ -185,6 +197,9 @@ func TestDefaultConfig(t *testing.T) {
 	if !config.EnableTracing {
 		t.Error("EnableTracing should be true")
 	}
+	if config.SnapshotMemoryBudget != DefaultSnapshotMemoryBudget {
+		t.Errorf("SnapshotMemoryBudget = %d, want %d", config.SnapshotMemoryBudget, DefaultSnapshotMemoryBudget)
+	}
 }
 
 // -----------------------------------------------------------------------------
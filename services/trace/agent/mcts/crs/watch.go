@@ -0,0 +1,311 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// -----------------------------------------------------------------------------
+// Watch API
+// -----------------------------------------------------------------------------
+
+// DefaultWatchBuffer is the default channel capacity for Watch.
+const DefaultWatchBuffer = 64
+
+// allIndexes covers all six indexes.
+const allIndexes = IndexProof | IndexConstraint | IndexSimilarity |
+	IndexDependency | IndexHistory | IndexStreaming
+
+// WatchOptions filters the events delivered by Watch.
+type WatchOptions struct {
+	// Indexes limits events to changes touching these indexes.
+	// Default: 0 (all indexes).
+	Indexes IndexMask
+
+	// NodePrefix limits events to changes touching a node whose ID starts
+	// with this prefix, and filters NodeIDs and Proofs to matching nodes.
+	// Default: "" (all nodes).
+	NodePrefix string
+
+	// Buffer is the channel capacity. Events that do not fit are dropped
+	// and counted in the next delivered event's Missed field.
+	// Default: DefaultWatchBuffer.
+	Buffer int
+}
+
+// WatchEvent describes one generation change.
+type WatchEvent struct {
+	// Generation is the generation the change produced.
+	Generation int64
+
+	// Indexes are the indexes the change modified.
+	Indexes IndexMask
+
+	// NodeIDs are the nodes the change touched, sorted. For a Restored
+	// event, NodeIDs is nil because any node may have changed.
+	NodeIDs []string
+
+	// Proofs are the proof numbers that changed, sorted by node ID. Use
+	// them to react to status flips such as PROVEN -> DISPROVEN.
+	Proofs []ProofChange
+
+	// Restored is true when the whole state was replaced by Restore or
+	// RestoreFrom rather than by a delta.
+	Restored bool
+
+	// Missed is the number of events dropped since the previous delivered
+	// event because the channel was full. Take a fresh Snapshot to resync.
+	Missed int
+}
+
+// watcher is one Watch subscription.
+type watcher struct {
+	opts   WatchOptions
+	ch     chan WatchEvent
+	missed int
+}
+
+// watchRegistry holds active subscriptions. The zero value is ready to use.
+type watchRegistry struct {
+	count    atomic.Int32
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+}
+
+// active reports whether any watcher is registered, so Apply can skip
+// building events when nobody is listening.
+func (r *watchRegistry) active() bool {
+	return r.count.Load() > 0
+}
+
+func (r *watchRegistry) add(w *watcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.watchers == nil {
+		r.watchers = make(map[*watcher]struct{})
+	}
+	r.watchers[w] = struct{}{}
+	r.count.Add(1)
+}
+
+func (r *watchRegistry) remove(w *watcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.watchers[w]; ok {
+		delete(r.watchers, w)
+		r.count.Add(-1)
+		close(w.ch)
+	}
+}
+
+// publish delivers ev to every matching watcher without blocking.
+func (r *watchRegistry) publish(ev WatchEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for w := range r.watchers {
+		filtered, ok := w.filter(ev)
+		if !ok {
+			continue
+		}
+		filtered.Missed = w.missed
+		select {
+		case w.ch <- filtered:
+			w.missed = 0
+		default:
+			w.missed++
+		}
+	}
+}
+
+// filter applies the watcher's options to ev.
+func (w *watcher) filter(ev WatchEvent) (WatchEvent, bool) {
+	if w.opts.Indexes != 0 && !ev.Indexes.Has(w.opts.Indexes) {
+		return ev, false
+	}
+	prefix := w.opts.NodePrefix
+	if prefix == "" || ev.Restored {
+		return ev, true
+	}
+
+	ev.NodeIDs = slices.DeleteFunc(slices.Clone(ev.NodeIDs), func(id string) bool {
+		return !strings.HasPrefix(id, prefix)
+	})
+	if len(ev.NodeIDs) == 0 {
+		return ev, false
+	}
+	ev.Proofs = slices.DeleteFunc(slices.Clone(ev.Proofs), func(c ProofChange) bool {
+		return !strings.HasPrefix(c.NodeID, prefix)
+	})
+	return ev, true
+}
+
+// Watch subscribes to generation changes.
+//
+// Description:
+//
+//	Returns a channel receiving a WatchEvent for each successful Apply,
+//	Restore, and RestoreFrom that matches opts, in generation order. The
+//	channel is closed when ctx is done. Delivery never blocks Apply: if the
+//	channel is full the event is dropped and counted in the next event's
+//	Missed field.
+//
+// Inputs:
+//   - ctx: Controls the subscription lifetime. Must not be nil.
+//   - opts: Index and node filters.
+//
+// Outputs:
+//   - <-chan WatchEvent: The event stream.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *crsImpl) Watch(ctx context.Context, opts WatchOptions) <-chan WatchEvent {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultWatchBuffer
+	}
+	w := &watcher{opts: opts, ch: make(chan WatchEvent, opts.Buffer)}
+	c.watchers.add(w)
+	context.AfterFunc(ctx, func() { c.watchers.remove(w) })
+	return w.ch
+}
+
+// watchCapture records the pre-apply state a watch event needs.
+type watchCapture struct {
+	nodes  map[string]struct{}
+	proofs map[string]ProofNumber // before values; absent = did not exist
+	keys   []string               // proof keys to compare after apply
+}
+
+// captureForWatch collects the nodes a delta touches and their current
+// proof numbers. Caller must hold c.mu.
+func (c *crsImpl) captureForWatch(delta Delta) *watchCapture {
+	wc := &watchCapture{
+		nodes:  make(map[string]struct{}),
+		proofs: make(map[string]ProofNumber),
+	}
+	c.collectWatchNodes(delta, wc)
+	for _, key := range wc.keys {
+		if p, ok := c.proofData[key]; ok {
+			wc.proofs[key] = p
+		}
+	}
+	return wc
+}
+
+// collectWatchNodes adds the nodes and proof keys touched by delta.
+func (c *crsImpl) collectWatchNodes(delta Delta, wc *watchCapture) {
+	node := func(id string) {
+		if id != "" {
+			wc.nodes[id] = struct{}{}
+		}
+	}
+	proof := func(id string) {
+		node(id)
+		wc.keys = append(wc.keys, id)
+	}
+
+	switch d := delta.(type) {
+	case *ProofDelta:
+		for id := range d.Updates {
+			proof(id)
+		}
+	case *ConstraintDelta:
+		for _, id := range d.Remove {
+			for _, n := range c.constraintData[id].Nodes {
+				node(n)
+			}
+		}
+		for _, con := range d.Update {
+			for _, n := range con.Nodes {
+				node(n)
+			}
+		}
+		for _, con := range d.Add {
+			for _, n := range con.Nodes {
+				node(n)
+			}
+		}
+	case *SimilarityDelta:
+		for pair := range d.Updates {
+			node(pair[0])
+			node(pair[1])
+		}
+	case *DependencyDelta:
+		for _, e := range d.AddEdges {
+			node(e[0])
+			node(e[1])
+		}
+		for _, e := range d.RemoveEdges {
+			node(e[0])
+			node(e[1])
+		}
+	case *HistoryDelta:
+		for _, e := range d.Entries {
+			node(e.NodeID)
+		}
+	case *StreamingDelta:
+		for item := range d.Increments {
+			node(item)
+		}
+		for _, item := range d.CardinalityItems {
+			node(item)
+		}
+	case *AnalyticsDelta:
+		if d.Record != nil {
+			proof(d.Record.GetProofDoneKey())
+			proof(d.Record.GetProofFoundKey())
+		}
+	case *CompositeDelta:
+		for _, inner := range d.Deltas {
+			c.collectWatchNodes(inner, wc)
+		}
+	}
+}
+
+// event builds the watch event after a successful apply. Caller must hold c.mu.
+func (wc *watchCapture) event(c *crsImpl, metrics ApplyMetrics) WatchEvent {
+	ev := WatchEvent{
+		Generation: metrics.NewGeneration,
+		Indexes:    metrics.IndexesUpdated,
+		NodeIDs:    slices.Sorted(maps.Keys(wc.nodes)),
+	}
+
+	slices.Sort(wc.keys)
+	for _, key := range slices.Compact(wc.keys) {
+		before, existed := wc.proofs[key]
+		after, exists := c.proofData[key]
+		switch {
+		case !existed && exists:
+			ev.Proofs = append(ev.Proofs, ProofChange{NodeID: key, Kind: ChangeAdded, After: after})
+		case existed && !exists:
+			ev.Proofs = append(ev.Proofs, ProofChange{NodeID: key, Kind: ChangeRemoved, Before: before})
+		case existed && before != after:
+			ev.Proofs = append(ev.Proofs, ProofChange{NodeID: key, Kind: ChangeModified, Before: before, After: after})
+		}
+	}
+	return ev
+}
+
+// publishRestored notifies watchers that the whole state was replaced.
+func (c *crsImpl) publishRestored(generation int64) {
+	if !c.watchers.active() {
+		return
+	}
+	c.watchers.publish(WatchEvent{
+		Generation: generation,
+		Indexes:    allIndexes,
+		Restored:   true,
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package crs
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recvEvent waits briefly for an event.
+func recvEvent(t *testing.T, ch <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		if !ok {
+			t.Fatal("watch channel closed")
+		}
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for watch event")
+		return WatchEvent{}
+	}
+}
+
+// expectNoEvent fails if an event is pending.
+func expectNoEvent(t *testing.T, ch <-chan WatchEvent) {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New(nil)
+
+	all := c.Watch(ctx, WatchOptions{})
+	proofs := c.Watch(ctx, WatchOptions{Indexes: IndexProof, NodePrefix: "pkg/a."})
+
+	delta := NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+		"pkg/a.Foo": {Proof: 1, Disproof: 5, Status: ProofStatusExpanded},
+		"pkg/b.Bar": {Proof: 2, Disproof: 2, Status: ProofStatusUnknown},
+	})
+	if _, err := c.Apply(ctx, delta); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	ev := recvEvent(t, all)
+	if ev.Generation != 1 || !ev.Indexes.Has(IndexProof) {
+		t.Errorf("event = %+v, want generation 1 on proof index", ev)
+	}
+	if want := []string{"pkg/a.Foo", "pkg/b.Bar"}; !slices.Equal(ev.NodeIDs, want) {
+		t.Errorf("NodeIDs = %v, want %v", ev.NodeIDs, want)
+	}
+	if len(ev.Proofs) != 2 || ev.Proofs[0].Kind != ChangeAdded {
+		t.Errorf("Proofs = %+v, want two additions", ev.Proofs)
+	}
+
+	ev = recvEvent(t, proofs)
+	if !slices.Equal(ev.NodeIDs, []string{"pkg/a.Foo"}) || len(ev.Proofs) != 1 {
+		t.Errorf("prefix-filtered event = %+v", ev)
+	}
+
+	t.Run("status flip", func(t *testing.T) {
+		delta := NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"pkg/a.Foo": {Proof: ProofNumberInfinite, Disproof: 0, Status: ProofStatusDisproven},
+		})
+		if _, err := c.Apply(ctx, delta); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		recvEvent(t, all)
+
+		ev := recvEvent(t, proofs)
+		if len(ev.Proofs) != 1 {
+			t.Fatalf("Proofs = %+v, want one change", ev.Proofs)
+		}
+		pc := ev.Proofs[0]
+		if pc.Kind != ChangeModified || pc.Before.Status != ProofStatusExpanded || pc.After.Status != ProofStatusDisproven {
+			t.Errorf("change = %+v, want EXPANDED -> DISPROVEN", pc)
+		}
+	})
+
+	t.Run("filters other indexes and nodes", func(t *testing.T) {
+		hist := NewHistoryDelta(SignalSourceHard, []HistoryEntry{{ID: "h1", NodeID: "pkg/a.Foo", Action: "visit"}})
+		if _, err := c.Apply(ctx, hist); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		other := NewProofDelta(SignalSourceHard, map[string]ProofNumber{
+			"pkg/b.Bar": {Proof: 3, Disproof: 1, Status: ProofStatusExpanded},
+		})
+		if _, err := c.Apply(ctx, other); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		if ev := recvEvent(t, all); !ev.Indexes.Has(IndexHistory) {
+			t.Errorf("event = %+v, want history", ev)
+		}
+		recvEvent(t, all)
+		expectNoEvent(t, proofs)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "crs.snap")
+		if err := c.Persist(ctx, path); err != nil {
+			t.Fatalf("Persist: %v", err)
+		}
+		if err := c.RestoreFrom(ctx, path); err != nil {
+			t.Fatalf("RestoreFrom: %v", err)
+		}
+		for _, ch := range []<-chan WatchEvent{all, proofs} {
+			if ev := recvEvent(t, ch); !ev.Restored || ev.Generation != c.Generation() {
+				t.Errorf("event = %+v, want restored at generation %d", ev, c.Generation())
+			}
+		}
+	})
+
+	t.Run("closes on cancel", func(t *testing.T) {
+		cancel()
+		for range all {
+		}
+		for range proofs {
+		}
+		if c.(*crsImpl).watchers.active() {
+			t.Error("watchers still registered after cancel")
+		}
+	})
+}
+
+func TestWatch_Missed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New(nil)
+	ch := c.Watch(ctx, WatchOptions{Buffer: 1})
+
+	step := func(i int) *HistoryDelta {
+		return NewHistoryDelta(SignalSourceHard, []HistoryEntry{{ID: fmt.Sprintf("h%d", i), NodeID: "n", Action: "step"}})
+	}
+	for i := range 3 {
+		delta := step(i)
+		if _, err := c.Apply(ctx, delta); err != nil {
+			t.Fatalf("Apply %d: %v", i, err)
+		}
+	}
+	if ev := recvEvent(t, ch); ev.Generation != 1 || ev.Missed != 0 {
+		t.Errorf("first event = %+v", ev)
+	}
+
+	if _, err := c.Apply(ctx, step(3)); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if ev := recvEvent(t, ch); ev.Generation != 4 || ev.Missed != 2 {
+		t.Errorf("event = %+v, want generation 4 with 2 missed", ev)
+	}
+}
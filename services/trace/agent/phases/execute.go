@@ -1670,6 +1670,9 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 		deps.Session.RecordTraceStep(completionStep)
 	}
 
+	// Remember what solved the query for future sessions in this repository
+	recordSolutions(ctx, deps, responseContent)
+
 	// Transition to complete
 	p.emitStateTransition(deps, agent.StateExecute, agent.StateComplete, "task completed")
 
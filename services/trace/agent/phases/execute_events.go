@@ -481,7 +481,7 @@ func (p *ExecutePhase) buildGraphContextForTool(
 			switch inv.Tool {
 			case "Read":
 				builder.WithFilesRead(filePath)
-			case "Write", "Edit":
+			case "Write", "Edit", "Patch":
 				builder.WithFilesModified(filePath)
 			}
 		}
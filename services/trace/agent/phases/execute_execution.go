@@ -526,6 +526,9 @@ func (p *ExecutePhase) recordTraceStep(deps *Dependencies, inv *agent.ToolInvoca
 	// Extract symbols found from result if available
 	if result != nil && result.Success {
 		step.SymbolsFound = extractSymbolsFromResult(result)
+		if len(result.ModifiedFiles) > 0 {
+			step.Metadata[traceModifiedFilesKey] = strings.Join(result.ModifiedFiles, ",")
+		}
 	}
 
 	deps.Session.RecordTraceStep(step)
@@ -1284,7 +1287,7 @@ func (p *ExecutePhase) extractToolParameters(
 			"limit":    20,
 		}, nil
 
-	case "find_path":
+	case "find_path", "explain_call_path":
 		// Extract "from" and "to" symbols - both required
 		from, to, ok := extractPathSymbolsFromQuery(query)
 		if !ok {
@@ -1299,15 +1302,15 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 		}
 		if from == "" || to == "" {
-			slog.Debug("GR-Phase1: find_path extraction failed",
+			slog.Debug("GR-Phase1: path extraction failed",
 				slog.String("tool", toolName),
 				slog.String("query_preview", truncateForLog(query, 100)),
 				slog.String("from", from),
 				slog.String("to", to),
 			)
-			return nil, fmt.Errorf("could not extract 'from' and 'to' symbols from query for find_path (need both source and target)")
+			return nil, fmt.Errorf("could not extract 'from' and 'to' symbols from query for %s (need both source and target)", toolName)
 		}
-		slog.Debug("GR-Phase1: extracted find_path params",
+		slog.Debug("GR-Phase1: extracted path params",
 			slog.String("tool", toolName),
 			slog.String("from", from),
 			slog.String("to", to),
@@ -1604,6 +1607,25 @@ func (p *ExecutePhase) extractToolParameters(
 			"module": moduleName,
 		}, nil
 
+	case "find_doc_coverage":
+		// Optional package, otherwise reports every package
+		// Patterns: "doc coverage of X", "how documented is package X"
+		params := map[string]interface{}{
+			"limit": extractTopNFromQuery(query, 20),
+		}
+		// "which packages ..." asks about all of them; the package heuristic
+		// would otherwise read the plural suffix as a package name.
+		if !strings.Contains(strings.ToLower(query), "packages") {
+			if pkgName := extractPackageNameFromQuery(query); pkgName != "" {
+				params["package"] = pkgName
+			}
+		}
+		slog.Debug("extracted find_doc_coverage params",
+			slog.String("tool", toolName),
+			slog.Int("limit", params["limit"].(int)),
+		)
+		return params, nil
+
 	default:
 		// For other tools, fallback to Main LLM
 		return nil, fmt.Errorf("parameter extraction not implemented for tool: %s", toolName)
@@ -18,6 +18,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
 )
 
 // PlanPhase handles context assembly and execution preparation.
@@ -25,12 +26,19 @@ import (
 // This phase is responsible for:
 //   - Assembling initial context for the user's query
 //   - Detecting ambiguous queries that need clarification
+//   - Adding similar past solutions of the repository to the prompt
 //   - Preparing the context for the execution phase
 //
 // Thread Safety: PlanPhase is safe for concurrent use.
 type PlanPhase struct {
 	// initialBudget is the token budget for initial context assembly.
 	initialBudget int
+
+	// solutionLimit is the number of prior solutions injected (0 disables).
+	solutionLimit int
+
+	// solutionMinScore is the minimum similarity of injected solutions.
+	solutionMinScore float64
 }
 
 // PlanPhaseOption configures a PlanPhase.
@@ -51,6 +59,23 @@ func WithInitialBudget(budget int) PlanPhaseOption {
 	}
 }
 
+// WithPriorSolutions configures prior solution retrieval.
+//
+// Inputs:
+//
+//	limit - The number of prior solutions injected. Zero disables retrieval.
+//	minScore - The minimum similarity of an injected solution.
+//
+// Outputs:
+//
+//	PlanPhaseOption - The configuration function.
+func WithPriorSolutions(limit int, minScore float64) PlanPhaseOption {
+	return func(p *PlanPhase) {
+		p.solutionLimit = limit
+		p.solutionMinScore = minScore
+	}
+}
+
 // NewPlanPhase creates a new planning phase.
 //
 // Inputs:
@@ -62,7 +87,9 @@ func WithInitialBudget(budget int) PlanPhaseOption {
 //	*PlanPhase - The configured phase.
 func NewPlanPhase(opts ...PlanPhaseOption) *PlanPhase {
 	p := &PlanPhase{
-		initialBudget: 8000, // Default budget
+		initialBudget:    8000, // Default budget
+		solutionLimit:    solutions.DefaultRetrieveLimit,
+		solutionMinScore: solutions.DefaultMinScore,
 	}
 
 	for _, opt := range opts {
@@ -87,7 +114,9 @@ func (p *PlanPhase) Name() string {
 //
 //	Assembles initial context for the user's query. If the query
 //	is ambiguous or context assembly fails, may transition to
-//	CLARIFY state to request user input.
+//	CLARIFY state to request user input. When a solution memory is
+//	configured, similar past solutions of the repository are added
+//	to the system prompt.
 //
 // Inputs:
 //
@@ -136,6 +165,7 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 			)
 			return p.handleAssemblyError(deps, err)
 		}
+		p.injectPriorSolutions(ctx, deps, assembledContext)
 
 		// Store context in dependencies for execute phase
 		deps.Context = assembledContext
@@ -172,6 +202,7 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 				},
 			},
 		}
+		p.injectPriorSolutions(ctx, deps, assembledContext)
 		deps.Context = assembledContext
 
 		// Persist context to session for cross-phase access
@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+// solutions.go connects the phases to the repository-scoped solution
+// memory: PLAN retrieves prior solutions, EXECUTE records new ones.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
+)
+
+// traceModifiedFilesKey is the trace step metadata key listing the files a
+// tool call modified (comma-separated).
+const traceModifiedFilesKey = "modified_files"
+
+// maxSolutionAnswerBytes bounds the final answer stored with a solution.
+const maxSolutionAnswerBytes = 2000
+
+// injectPriorSolutions adds similar past solutions to the system prompt.
+//
+// Description:
+//
+//	Retrieves the solutions of the session's repository most similar to
+//	the query and appends them to the system prompt. Retrieval failures
+//	are logged and leave the context unchanged; the memory only helps.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies. Solutions must be non-nil.
+//	assembled - The context to extend.
+//
+// Outputs:
+//
+//	int - The number of solutions injected.
+func (p *PlanPhase) injectPriorSolutions(ctx context.Context, deps *Dependencies, assembled *agent.AssembledContext) int {
+	repo := deps.Session.GetProjectRoot()
+	if deps.Solutions == nil || p.solutionLimit <= 0 || repo == "" {
+		return 0
+	}
+
+	matches, err := deps.Solutions.Retrieve(ctx, repo, deps.Query, solutions.RetrieveOptions{
+		Limit:    p.solutionLimit,
+		MinScore: p.solutionMinScore,
+	})
+	if err != nil {
+		slog.Warn("Prior solution retrieval failed",
+			slog.String("session_id", deps.Session.ID),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+	if len(matches) == 0 {
+		return 0
+	}
+
+	section := solutions.FormatForPrompt(matches)
+	assembled.SystemPrompt = strings.TrimRight(assembled.SystemPrompt, "\n") + "\n\n" + section
+	assembled.TotalTokens += len(section) / 4
+
+	slog.Info("Prior solutions injected",
+		slog.String("session_id", deps.Session.ID),
+		slog.Int("count", len(matches)),
+		slog.Float64("best_score", matches[0].Score),
+	)
+	return len(matches)
+}
+
+// recordSolutions stores what solved the session's query.
+//
+// Description:
+//
+//	Records one fix (if tool calls modified files) or plan (otherwise)
+//	with the successful tool sequence and the final answer, plus every
+//	clause the CRS learned during this session. Failures are logged; the
+//	session has already succeeded.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	answer - The final response.
+func recordSolutions(ctx context.Context, deps *Dependencies, answer string) {
+	if deps.Solutions == nil || deps.Session == nil {
+		return
+	}
+	repo := deps.Session.GetProjectRoot()
+	if repo == "" || deps.Query == "" {
+		return
+	}
+
+	var records []*solutions.Solution
+	if sol := sessionSolution(deps, answer); sol != nil {
+		records = append(records, sol)
+	}
+	records = append(records, learnedClauseSolutions(deps)...)
+
+	for _, sol := range records {
+		sol.Repository = repo
+		sol.Problem = deps.Query
+		sol.SessionID = deps.Session.ID
+		if err := deps.Solutions.Record(ctx, sol); err != nil {
+			slog.Warn("Recording solution failed",
+				slog.String("session_id", deps.Session.ID),
+				slog.String("kind", string(sol.Kind)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	if len(records) > 0 {
+		slog.Debug("Solutions recorded",
+			slog.String("session_id", deps.Session.ID),
+			slog.Int("count", len(records)),
+		)
+	}
+}
+
+// sessionSolution builds the fix or plan solution from the trace steps.
+// Returns nil if there is neither a tool sequence nor an answer.
+func sessionSolution(deps *Dependencies, answer string) *solutions.Solution {
+	var toolSeq []string
+	modified := make(map[string]bool)
+	for _, step := range deps.Session.GetTraceSteps() {
+		if step.Action != "tool_call" || step.Error != "" || step.Tool == "" {
+			continue
+		}
+		if n := len(toolSeq); n == 0 || toolSeq[n-1] != step.Tool {
+			toolSeq = append(toolSeq, step.Tool)
+		}
+		if files := step.Metadata[traceModifiedFilesKey]; files != "" {
+			for _, f := range strings.Split(files, ",") {
+				modified[f] = true
+			}
+		}
+	}
+
+	answer = strings.TrimSpace(answer)
+	if len(toolSeq) == 0 && answer == "" {
+		return nil
+	}
+
+	sol := &solutions.Solution{Kind: solutions.KindPlan}
+	var sb strings.Builder
+	if len(modified) > 0 {
+		sol.Kind = solutions.KindFix
+		files := make([]string, 0, len(modified))
+		for f := range modified {
+			files = append(files, f)
+		}
+		sort.Strings(files)
+		fmt.Fprintf(&sb, "Files changed: %s\n", strings.Join(files, ", "))
+	}
+	if len(toolSeq) > 0 {
+		fmt.Fprintf(&sb, "Steps: %s\n", strings.Join(toolSeq, " → "))
+	}
+	if answer != "" {
+		if len(answer) > maxSolutionAnswerBytes {
+			answer = strings.ToValidUTF8(answer[:maxSolutionAnswerBytes], "") + "..."
+		}
+		fmt.Fprintf(&sb, "Answer: %s\n", answer)
+	}
+	sol.Content = sb.String()
+	return sol
+}
+
+// learnedClauseSolutions returns the clauses the CRS learned in this
+// session as solutions.
+func learnedClauseSolutions(deps *Dependencies) []*solutions.Solution {
+	crsInstance := deps.Session.GetCRS()
+	if crsInstance == nil {
+		return nil
+	}
+	ci := crsInstance.Snapshot().ConstraintIndex()
+	if ci == nil {
+		return nil
+	}
+
+	clauses := ci.AllClauses()
+	ids := make([]string, 0, len(clauses))
+	for id, clause := range clauses {
+		if clause.SessionID == deps.Session.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	sols := make([]*solutions.Solution, 0, len(ids))
+	for _, id := range ids {
+		clause := clauses[id]
+		content := "Avoid: " + clause.String()
+		if clause.FailureType != "" {
+			content += fmt.Sprintf(" (learned from %s)", clause.FailureType)
+		}
+		sols = append(sols, &solutions.Solution{Kind: solutions.KindClause, Content: content})
+	}
+	return sols
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
+)
+
+func TestPlanPhase_Execute_InjectsPriorSolutions(t *testing.T) {
+	ctx := context.Background()
+	store := solutions.NewStore(nil)
+	if err := store.Record(ctx, &solutions.Solution{
+		Repository: "/test/project",
+		Kind:       solutions.KindFix,
+		Problem:    "Why does ParseConfig panic on an empty config file?",
+		Content:    "Files changed: config.go\nAnswer: ParseConfig dereferenced a nil section",
+	}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	deps := createTestDependencies()
+	deps.Solutions = store
+	deps.Query = "ParseConfig panics when the config file is empty"
+
+	nextState, err := NewPlanPhase().Execute(ctx, deps)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if nextState != agent.StateExecute {
+		t.Fatalf("nextState = %s, want EXECUTE", nextState)
+	}
+	if !strings.Contains(deps.Context.SystemPrompt, "## Prior Solutions in This Repository") ||
+		!strings.Contains(deps.Context.SystemPrompt, "Files changed: config.go") {
+		t.Errorf("system prompt has no prior solution:\n%s", deps.Context.SystemPrompt)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		deps := createTestDependencies()
+		deps.Solutions = store
+		deps.Query = "ParseConfig panics when the config file is empty"
+
+		if _, err := NewPlanPhase(WithPriorSolutions(0, 0)).Execute(ctx, deps); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if strings.Contains(deps.Context.SystemPrompt, "Prior Solutions") {
+			t.Error("prior solutions injected with retrieval disabled")
+		}
+	})
+}
+
+func TestRecordSolutions(t *testing.T) {
+	ctx := context.Background()
+	deps := createTestDependencies()
+	deps.Query = "Fix the nil pointer in ParseConfig"
+	deps.Solutions = solutions.NewStore(nil)
+
+	deps.Session.SetTraceRecorder(crs.NewTraceRecorder(crs.DefaultTraceConfig()))
+	for _, step := range []crs.TraceStep{
+		{Action: "tool_call", Tool: "find_symbol"},
+		{Action: "tool_call", Tool: "find_symbol"},
+		{Action: "tool_call", Tool: "grep", Error: "timeout"},
+		{Action: "tool_call", Tool: "edit_file", Metadata: map[string]string{traceModifiedFilesKey: "config.go,config_test.go"}},
+	} {
+		deps.Session.RecordTraceStep(step)
+	}
+
+	sessionCRS := crs.New(nil)
+	deps.Session.SetCRS(sessionCRS)
+	for _, clause := range []*crs.Clause{
+		{ID: "c1", Literals: []crs.Literal{{Variable: "tool:grep", Negated: true}}, Source: crs.SignalSourceHard, FailureType: crs.FailureTypeToolError, SessionID: deps.Session.ID},
+		{ID: "c2", Literals: []crs.Literal{{Variable: "tool:list_packages", Negated: true}}, Source: crs.SignalSourceHard, SessionID: "other-session"},
+	} {
+		if err := sessionCRS.AddClause(ctx, clause); err != nil {
+			t.Fatalf("AddClause() error = %v", err)
+		}
+	}
+
+	recordSolutions(ctx, deps, "ParseConfig now returns an error for empty files.")
+
+	if n, _ := deps.Solutions.Count("/test/project"); n != 2 {
+		t.Fatalf("Count() = %d, want the fix and one clause", n)
+	}
+
+	matches, err := deps.Solutions.Retrieve(ctx, "/test/project", "nil pointer in ParseConfig", solutions.RetrieveOptions{Kinds: []solutions.Kind{solutions.KindFix}})
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Retrieve() = %v, %v, want the fix", matches, err)
+	}
+	wantFix := "Files changed: config.go, config_test.go\n" +
+		"Steps: find_symbol → edit_file\n" +
+		"Answer: ParseConfig now returns an error for empty files.\n"
+	if got := matches[0].Solution.Content; got != wantFix {
+		t.Errorf("fix content = %q, want %q", got, wantFix)
+	}
+
+	matches, err = deps.Solutions.Retrieve(ctx, "/test/project", "nil pointer in ParseConfig grep", solutions.RetrieveOptions{Kinds: []solutions.Kind{solutions.KindClause}})
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Retrieve() = %v, %v, want the session's clause", matches, err)
+	}
+	if got := matches[0].Solution.Content; got != "Avoid: (¬tool:grep) (learned from tool_error)" {
+		t.Errorf("clause content = %q", got)
+	}
+}
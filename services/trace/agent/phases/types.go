@@ -33,8 +33,10 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/integration"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/workspace"
 )
 
 // Phase defines the interface for agent phases.
@@ -166,6 +168,18 @@ type Dependencies struct {
 	// Optional - if nil, delta journaling is disabled.
 	// GR-33/GR-36: Required for session restore to replay deltas.
 	BadgerJournal *crs.BadgerJournal
+
+	// Workspaces provides the session's scratch git worktree for applying
+	// candidate patches and running tests without touching the user's tree.
+	// Optional - if nil, patch verification runs are disabled.
+	// Acquire with Session.ID; the worktree is released when the session ends.
+	Workspaces *workspace.Manager
+
+	// Solutions is the repository-scoped memory of past solutions.
+	// Optional - if nil, prior solutions are neither retrieved nor recorded.
+	// PLAN injects similar solutions into the prompt; EXECUTE records the
+	// session's solution and learned clauses on completion.
+	Solutions *solutions.Store
 }
 
 // GraphProvider initializes and provides access to the code graph.
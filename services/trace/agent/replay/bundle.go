@@ -0,0 +1,277 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// BundleVersion is the bundle format written by this package.
+const BundleVersion = 1
+
+// MaxToolOutputBytes is the longest tool output kept in a bundle. Longer
+// outputs are truncated and marked.
+const MaxToolOutputBytes = 64 * 1024
+
+// Errors returned by the replay package.
+var (
+	// ErrUnsupportedVersion indicates a bundle written in an unknown format.
+	ErrUnsupportedVersion = errors.New("unsupported replay bundle version")
+
+	// ErrExhausted indicates the agent asked for more LLM responses than
+	// the bundle recorded.
+	ErrExhausted = errors.New("no recorded LLM response left")
+)
+
+// Bundle is a sanitized capture of one agent run.
+type Bundle struct {
+	// Version is the bundle format (BundleVersion).
+	Version int `json:"version"`
+
+	// SessionID is the captured session.
+	SessionID string `json:"session_id"`
+
+	// CapturedAt is when the run finished.
+	CapturedAt time.Time `json:"captured_at"`
+
+	// Request is the run request.
+	Request Request `json:"request"`
+
+	// Model is the LLM model that served the run.
+	Model string `json:"model,omitempty"`
+
+	// Exchanges are the LLM calls, in order.
+	Exchanges []Exchange `json:"exchanges"`
+
+	// ToolCalls are the tool executions, in order.
+	ToolCalls []ToolCall `json:"tool_calls"`
+
+	// Outcome is how the run ended.
+	Outcome Outcome `json:"outcome"`
+}
+
+// Request is the agent run request.
+type Request struct {
+	// ProjectRoot is the project the agent ran against.
+	ProjectRoot string `json:"project_root"`
+
+	// Query is the user's query.
+	Query string `json:"query"`
+
+	// Config is the effective session configuration.
+	Config *agent.SessionConfig `json:"config,omitempty"`
+}
+
+// Exchange is one LLM call.
+type Exchange struct {
+	// Seq is the position of the call in the run, from 0.
+	Seq int `json:"seq"`
+
+	// Request is the request sent. Tool definitions are replaced by
+	// their names in Tools.
+	Request *llm.Request `json:"request"`
+
+	// Tools names the tools offered to the LLM.
+	Tools []string `json:"tools,omitempty"`
+
+	// Response is the response received. Nil if the call failed.
+	Response *llm.Response `json:"response,omitempty"`
+
+	// Error is the call's error, if any.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the call took.
+	Duration time.Duration `json:"duration"`
+}
+
+// ToolCall is one tool execution.
+type ToolCall struct {
+	// AfterExchange is the number of LLM calls made before this one.
+	AfterExchange int `json:"after_exchange"`
+
+	// Tool is the tool name.
+	Tool string `json:"tool"`
+
+	// Parameters are the tool arguments.
+	Parameters map[string]any `json:"parameters,omitempty"`
+
+	// Success is true if the tool ran and reported success.
+	Success bool `json:"success"`
+
+	// Output is the tool's text output, at most MaxToolOutputBytes.
+	Output string `json:"output,omitempty"`
+
+	// Truncated is true if Output was cut.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Error is the tool or executor error, if any.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the tool ran.
+	Duration time.Duration `json:"duration"`
+}
+
+// Outcome is how a run ended.
+type Outcome struct {
+	// State is the final agent state.
+	State string `json:"state"`
+
+	// Error is the agent error, if any.
+	Error string `json:"error,omitempty"`
+
+	// StepsTaken is the number of agent steps.
+	StepsTaken int `json:"steps_taken"`
+}
+
+// -----------------------------------------------------------------------------
+// Files
+// -----------------------------------------------------------------------------
+
+// WriteBundle writes a bundle to dir as <session_id>.json.
+//
+// Inputs:
+//
+//	dir - Output directory. Created with mode 0700 if missing.
+//	bundle - The bundle. Must not be nil.
+//
+// Outputs:
+//
+//	string - The path written.
+//	error - Non-nil if the file could not be written.
+func WriteBundle(dir string, bundle *Bundle) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("creating replay directory: %w", err)
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding replay bundle: %w", err)
+	}
+
+	path := filepath.Join(dir, filepath.Base(bundle.SessionID)+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("writing replay bundle: %w", err)
+	}
+	return path, nil
+}
+
+// ReadBundle reads a bundle written by WriteBundle.
+//
+// Outputs:
+//
+//	*Bundle - The bundle.
+//	error - Non-nil if the file cannot be read or parsed, or
+//	        ErrUnsupportedVersion.
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("parsing replay bundle: %w", err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, bundle.Version)
+	}
+	return &bundle, nil
+}
+
+// -----------------------------------------------------------------------------
+// Sanitizing
+// -----------------------------------------------------------------------------
+
+// sanitizer redacts secrets from everything recorded.
+var sanitizer = crs.NewSecretSanitizer()
+
+// sanitizeRequest returns a redacted copy of an LLM request without tool
+// definitions, and the names of the tools offered.
+func sanitizeRequest(req *llm.Request) (*llm.Request, []string) {
+	if req == nil {
+		return nil, nil
+	}
+	out := *req
+	out.SystemPrompt = sanitizer.Sanitize(req.SystemPrompt)
+	out.Tools = nil
+	out.Messages = make([]llm.Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = sanitizer.Sanitize(msg.Content)
+		msg.ToolCalls = sanitizeToolCalls(msg.ToolCalls)
+		if msg.ToolResults != nil {
+			results := make([]llm.ToolCallResult, len(msg.ToolResults))
+			for j, r := range msg.ToolResults {
+				r.Content = sanitizer.Sanitize(r.Content)
+				results[j] = r
+			}
+			msg.ToolResults = results
+		}
+		out.Messages[i] = msg
+	}
+
+	var names []string
+	for _, def := range req.Tools {
+		names = append(names, def.Name)
+	}
+	return &out, names
+}
+
+// sanitizeResponse returns a redacted copy of an LLM response.
+func sanitizeResponse(resp *llm.Response) *llm.Response {
+	if resp == nil {
+		return nil
+	}
+	out := *resp
+	out.Content = sanitizer.Sanitize(resp.Content)
+	out.ToolCalls = sanitizeToolCalls(resp.ToolCalls)
+	return &out
+}
+
+func sanitizeToolCalls(calls []llm.ToolCall) []llm.ToolCall {
+	if calls == nil {
+		return nil
+	}
+	out := make([]llm.ToolCall, len(calls))
+	for i, call := range calls {
+		call.Arguments = sanitizer.Sanitize(call.Arguments)
+		out[i] = call
+	}
+	return out
+}
+
+// sanitizeValue redacts the strings in a decoded JSON value.
+func sanitizeValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return sanitizer.Sanitize(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = sanitizeValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = sanitizeValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package replay captures agent runs so failures can be reproduced locally.
+//
+// A Recording is started for a session and collects the run request, every
+// LLM exchange and every tool output. Secrets are redacted as they are
+// recorded, and long tool outputs are truncated. When the run fails, the
+// recording is written as a Bundle, a single JSON file that can be attached
+// to a bug report.
+//
+// A StubClient replays a bundle: it implements llm.Client by returning the
+// recorded LLM responses in order, so the agent takes the same decisions
+// while the tools run for real against the local build. Each request the
+// agent sends is compared with the recorded one, and differences, such as
+// a tool that now returns different output, are reported as Divergences.
+//
+// Recordings are found by session ID (see Start and Lookup), because agent
+// phases do not always pass the request context down to the LLM client.
+//
+// Thread Safety:
+//
+//	Recording and StubClient are safe for concurrent use.
+package replay
@@ -0,0 +1,215 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// active holds the recordings of running sessions by session ID.
+var active = struct {
+	sync.RWMutex
+	recordings map[string]*Recording
+}{recordings: make(map[string]*Recording)}
+
+// Recording collects the LLM exchanges and tool calls of one session.
+//
+// Thread Safety: Recording is safe for concurrent use.
+type Recording struct {
+	mu     sync.Mutex
+	bundle Bundle
+}
+
+// Start begins recording a session.
+//
+// Description:
+//
+//	The recording is registered under sessionID so the dependencies
+//	factory can find it with Lookup and route the session's LLM client
+//	and tool executor through it. A previous recording for the same
+//	session is replaced.
+//
+// Inputs:
+//
+//	sessionID - The session to record.
+//	req - The run request. Its query is sanitized.
+//
+// Outputs:
+//
+//	*Recording - The recording. Call Stop when the run ends.
+func Start(sessionID string, req Request) *Recording {
+	req.Query = sanitizer.Sanitize(req.Query)
+	r := &Recording{bundle: Bundle{
+		Version:   BundleVersion,
+		SessionID: sessionID,
+		Request:   req,
+		Exchanges: make([]Exchange, 0),
+		ToolCalls: make([]ToolCall, 0),
+	}}
+
+	active.Lock()
+	active.recordings[sessionID] = r
+	active.Unlock()
+	return r
+}
+
+// Lookup returns the recording of a session, or nil if it is not being
+// recorded.
+//
+// Thread Safety: Safe for concurrent use.
+func Lookup(sessionID string) *Recording {
+	active.RLock()
+	defer active.RUnlock()
+	return active.recordings[sessionID]
+}
+
+// Stop unregisters the recording. Calls made after Stop through clients
+// already wrapped are still recorded.
+func (r *Recording) Stop() {
+	active.Lock()
+	defer active.Unlock()
+	if active.recordings[r.bundle.SessionID] == r {
+		delete(active.recordings, r.bundle.SessionID)
+	}
+}
+
+// Finish sets the outcome and returns a copy of the bundle.
+//
+// Inputs:
+//
+//	outcome - How the run ended. The error is sanitized.
+//
+// Outputs:
+//
+//	*Bundle - The captured run, ready for WriteBundle.
+func (r *Recording) Finish(outcome Outcome) *Bundle {
+	outcome.Error = sanitizer.Sanitize(outcome.Error)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.Outcome = outcome
+	r.bundle.CapturedAt = time.Now().UTC()
+
+	bundle := r.bundle
+	bundle.Exchanges = slices.Clone(r.bundle.Exchanges)
+	bundle.ToolCalls = slices.Clone(r.bundle.ToolCalls)
+	return &bundle
+}
+
+// Client wraps an LLM client so its calls are recorded.
+//
+// Outputs:
+//
+//	llm.Client - The wrapped client, or nil if inner is nil.
+func (r *Recording) Client(inner llm.Client) llm.Client {
+	if inner == nil {
+		return nil
+	}
+	return &recordingClient{inner: inner, recording: r}
+}
+
+// ObserveExecution implements tools.ExecutionObserver.
+func (r *Recording) ObserveExecution(inv *tools.Invocation, result *tools.Result, err error) {
+	call := ToolCall{
+		Tool:       inv.ToolName,
+		Parameters: sanitizeParams(inv.Parameters),
+	}
+	if inv.CompletedAt > 0 {
+		call.Duration = time.Duration(inv.CompletedAt-inv.StartedAt) * time.Millisecond
+	}
+	switch {
+	case err != nil:
+		call.Error = sanitizer.Sanitize(err.Error())
+	case result != nil:
+		call.Success = result.Success
+		call.Error = sanitizer.Sanitize(result.Error)
+		call.Output, call.Truncated = truncate(sanitizer.Sanitize(result.OutputText), MaxToolOutputBytes)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call.AfterExchange = len(r.bundle.Exchanges)
+	r.bundle.ToolCalls = append(r.bundle.ToolCalls, call)
+}
+
+// recordExchange appends a sanitized LLM exchange.
+func (r *Recording) recordExchange(model string, req *llm.Request, resp *llm.Response, err error, d time.Duration) {
+	ex := Exchange{
+		Response: sanitizeResponse(resp),
+		Duration: d,
+	}
+	ex.Request, ex.Tools = sanitizeRequest(req)
+	if err != nil {
+		ex.Error = sanitizer.Sanitize(err.Error())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.bundle.Model == "" {
+		r.bundle.Model = model
+	}
+	ex.Seq = len(r.bundle.Exchanges)
+	r.bundle.Exchanges = append(r.bundle.Exchanges, ex)
+}
+
+// recordingClient records every call to an inner client.
+type recordingClient struct {
+	inner     llm.Client
+	recording *Recording
+}
+
+// Complete dispatches to the inner client and records the exchange.
+func (c *recordingClient) Complete(ctx context.Context, request *llm.Request) (*llm.Response, error) {
+	start := time.Now()
+	resp, err := c.inner.Complete(ctx, request)
+	model := c.inner.Model()
+	if resp != nil && resp.Model != "" {
+		model = resp.Model
+	}
+	c.recording.recordExchange(model, request, resp, err, time.Since(start))
+	return resp, err
+}
+
+// Name returns the inner client's provider name.
+func (c *recordingClient) Name() string {
+	return c.inner.Name()
+}
+
+// Model returns the inner client's model.
+func (c *recordingClient) Model() string {
+	return c.inner.Model()
+}
+
+// sanitizeParams returns a redacted copy of tool parameters.
+func sanitizeParams(params map[string]any) map[string]any {
+	if params == nil {
+		return nil
+	}
+	return sanitizeValue(params).(map[string]any)
+}
+
+// truncate cuts s to at most n bytes on a rune boundary.
+func truncate(s string, n int) (string, bool) {
+	if len(s) <= n {
+		return s, false
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// Divergence is a difference between a recorded LLM request and the one
+// the replayed run sent, typically because a tool returned different
+// output on the local build.
+type Divergence struct {
+	// Exchange is the LLM call at which the difference appeared.
+	Exchange int `json:"exchange"`
+
+	// Field locates the difference, e.g. "messages[3].tool_results[0]".
+	Field string `json:"field"`
+
+	// Recorded is the value in the bundle.
+	Recorded string `json:"recorded"`
+
+	// Replayed is the value sent by the replayed run, sanitized.
+	Replayed string `json:"replayed"`
+}
+
+// StubClient serves the LLM responses recorded in a bundle.
+//
+// Description:
+//
+//	Responses are returned in recorded order, whatever the request, so
+//	the replayed run follows the recorded decisions. A recorded error is
+//	returned as an error. Once every response is served, Complete returns
+//	ErrExhausted. Each request is compared with the recorded one and
+//	differences are kept as Divergences; a difference carried forward in
+//	the conversation history is reported only once.
+//
+// Thread Safety: StubClient is safe for concurrent use.
+type StubClient struct {
+	mu          sync.Mutex
+	bundle      *Bundle
+	next        int
+	divergences []Divergence
+	reported    map[string]bool
+}
+
+// NewStubClient creates a client that replays a bundle.
+//
+// Inputs:
+//
+//	bundle - The recorded run. Must not be nil.
+//
+// Outputs:
+//
+//	*StubClient - The client.
+func NewStubClient(bundle *Bundle) *StubClient {
+	return &StubClient{bundle: bundle, reported: make(map[string]bool)}
+}
+
+// Complete implements llm.Client.
+func (c *StubClient) Complete(ctx context.Context, request *llm.Request) (*llm.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next >= len(c.bundle.Exchanges) {
+		return nil, fmt.Errorf("%w: the run made %d calls", ErrExhausted, c.next+1)
+	}
+	ex := c.bundle.Exchanges[c.next]
+	c.next++
+
+	replayed, _ := sanitizeRequest(request)
+	c.compare(ex.Seq, ex.Request, replayed)
+
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	if ex.Response == nil {
+		return nil, fmt.Errorf("exchange %d has no recorded response", ex.Seq)
+	}
+	resp := *ex.Response
+	resp.ToolCalls = slices.Clone(ex.Response.ToolCalls)
+	return &resp, nil
+}
+
+// Name implements llm.Client.
+func (c *StubClient) Name() string {
+	return "replay"
+}
+
+// Model implements llm.Client. It returns the recorded model.
+func (c *StubClient) Model() string {
+	return c.bundle.Model
+}
+
+// Served returns the number of recorded responses served.
+func (c *StubClient) Served() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next
+}
+
+// Divergences returns the differences found so far, in order.
+func (c *StubClient) Divergences() []Divergence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.divergences)
+}
+
+// compare records how a replayed request differs from the recorded one.
+// Caller must hold c.mu.
+func (c *StubClient) compare(seq int, recorded, replayed *llm.Request) {
+	if recorded == nil || replayed == nil {
+		return
+	}
+	c.diverge(seq, "system_prompt", recorded.SystemPrompt, replayed.SystemPrompt)
+
+	n := min(len(recorded.Messages), len(replayed.Messages))
+	for i := 0; i < n; i++ {
+		rec, rep := recorded.Messages[i], replayed.Messages[i]
+		field := fmt.Sprintf("messages[%d]", i)
+		c.diverge(seq, field+".role", rec.Role, rep.Role)
+		c.diverge(seq, field+".content", rec.Content, rep.Content)
+
+		m := min(len(rec.ToolResults), len(rep.ToolResults))
+		for j := 0; j < m; j++ {
+			c.diverge(seq, fmt.Sprintf("%s.tool_results[%d]", field, j),
+				rec.ToolResults[j].Content, rep.ToolResults[j].Content)
+		}
+		if len(rec.ToolResults) != len(rep.ToolResults) {
+			c.diverge(seq, field+".tool_results",
+				fmt.Sprintf("%d results", len(rec.ToolResults)),
+				fmt.Sprintf("%d results", len(rep.ToolResults)))
+		}
+	}
+	if len(recorded.Messages) != len(replayed.Messages) {
+		c.diverge(seq, "messages",
+			fmt.Sprintf("%d messages", len(recorded.Messages)),
+			fmt.Sprintf("%d messages", len(replayed.Messages)))
+	}
+}
+
+// diverge records a difference unless the values match or the field
+// already diverged. Caller must hold c.mu.
+func (c *StubClient) diverge(seq int, field, recorded, replayed string) {
+	if recorded == replayed || c.reported[field] {
+		return
+	}
+	c.reported[field] = true
+	c.divergences = append(c.divergences, Divergence{
+		Exchange: seq,
+		Field:    field,
+		Recorded: recorded,
+		Replayed: replayed,
+	})
+}
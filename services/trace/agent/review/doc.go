@@ -0,0 +1,28 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package review implements the agent's patch review mode.
+//
+// Instead of a free-text query, review mode takes a unified diff (from a PR
+// or `git diff`) and produces structured findings. The review is grounded in
+// the code graph in two ways:
+//
+//   - Deterministic: changed hunks are mapped to the symbols they touch and
+//     each symbol's blast radius is computed. Wide blast radius, untested
+//     changes, and deleted code that still has callers become findings
+//     without involving the LLM.
+//   - Agentic: the diff and the graph facts are turned into a review prompt
+//     for the agent loop, which can explore further with its tools. The
+//     agent's JSON findings are parsed and merged with the graph findings.
+//
+// Thread Safety:
+//
+//	Reviewer is safe for concurrent use once the graph is frozen.
+package review
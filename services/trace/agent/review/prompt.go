@@ -0,0 +1,157 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxPromptDiffBytes bounds the diff text embedded in the review prompt.
+const MaxPromptDiffBytes = 32 * 1024
+
+// BuildPrompt builds the agent query for review mode.
+//
+// Description:
+//
+//	The prompt contains the diff (truncated to MaxPromptDiffBytes), the
+//	changed symbols with their blast radius, and the graph findings already
+//	known, and asks the agent to verify callers with its tools and reply
+//	with a JSON array of findings in a ```json block.
+//
+// Inputs:
+//
+//	diffText - The unified diff.
+//	result - The graph analysis from Reviewer.Analyze.
+//
+// Outputs:
+//
+//	string - The query for the agent loop.
+func BuildPrompt(diffText string, result *Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("Review the following patch for bugs, breaking changes, missing error handling, ")
+	sb.WriteString("and missing tests. Use your tools to inspect callers of the changed symbols ")
+	sb.WriteString("before concluding a change is safe.\n\n")
+
+	if len(result.ChangedSymbols) > 0 {
+		sb.WriteString("## Changed symbols (from the code graph)\n")
+		for _, cs := range result.ChangedSymbols {
+			fmt.Fprintf(&sb, "- %s (%s) at %s:%d — risk %s, %d direct / %d indirect callers, %d test file(s)\n",
+				cs.Name, cs.Kind, cs.FilePath, cs.Line, cs.RiskLevel, cs.DirectCallers, cs.IndirectCallers, len(cs.TestFiles))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.Findings) > 0 {
+		sb.WriteString("## Already reported (do not repeat)\n")
+		for _, f := range result.Findings {
+			fmt.Fprintf(&sb, "- [%s] %s: %s\n", f.Severity, f.Location(), f.Message)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Patch\n```diff\n")
+	if len(diffText) > MaxPromptDiffBytes {
+		sb.WriteString(diffText[:MaxPromptDiffBytes])
+		sb.WriteString("\n... (diff truncated)\n")
+	} else {
+		sb.WriteString(diffText)
+	}
+	if !strings.HasSuffix(diffText, "\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("## Output\n")
+	sb.WriteString("Finish with a ```json block containing an array of findings. Each finding is ")
+	sb.WriteString(`{"severity": "info|warning|error|critical", "file": "<path>", "line": <new-file line>, `)
+	sb.WriteString(`"symbol": "<name>", "message": "<problem>", "suggestion": "<fix>"}. `)
+	sb.WriteString("Only report problems you can point to in the patch. Use [] if there are none.\n")
+
+	return sb.String()
+}
+
+// ParseFindings extracts agent findings from a response.
+//
+// Description:
+//
+//	Tries the ```json fenced blocks from last to first, so the findings
+//	win over examples the model echoes earlier, then falls back to the
+//	outermost [...] in the response. Findings without a message are
+//	dropped and severities are normalized with ParseSeverity.
+//
+// Outputs:
+//
+//	[]Finding - Findings tagged SourceAgent (possibly empty)
+//	error - ErrNoFindings if no JSON array could be parsed
+func ParseFindings(response string) ([]Finding, error) {
+	for _, candidate := range findingCandidates(response) {
+		var raw []struct {
+			Severity   string `json:"severity"`
+			File       string `json:"file"`
+			Line       int    `json:"line"`
+			Symbol     string `json:"symbol"`
+			Message    string `json:"message"`
+			Suggestion string `json:"suggestion"`
+		}
+		if err := json.Unmarshal([]byte(candidate), &raw); err != nil {
+			continue
+		}
+
+		findings := make([]Finding, 0, len(raw))
+		for _, r := range raw {
+			if strings.TrimSpace(r.Message) == "" {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity:   ParseSeverity(r.Severity),
+				FilePath:   strings.TrimPrefix(strings.TrimPrefix(r.File, "b/"), "./"),
+				Line:       r.Line,
+				Symbol:     r.Symbol,
+				Message:    strings.TrimSpace(r.Message),
+				Suggestion: strings.TrimSpace(r.Suggestion),
+				Source:     SourceAgent,
+			})
+		}
+		return findings, nil
+	}
+	return nil, ErrNoFindings
+}
+
+// findingCandidates returns possible JSON array texts in priority order:
+// fenced blocks last-first, then the outermost bracketed text.
+func findingCandidates(response string) []string {
+	var candidates []string
+
+	rest := response
+	for {
+		start := strings.Index(rest, "```json")
+		if start < 0 {
+			break
+		}
+		body := rest[start+len("```json"):]
+		end := strings.Index(body, "```")
+		if end < 0 {
+			break
+		}
+		candidates = append(candidates, strings.TrimSpace(body[:end]))
+		rest = body[end+3:]
+	}
+	slices.Reverse(candidates)
+
+	if start, end := strings.Index(response, "["), strings.LastIndex(response, "]"); start >= 0 && end > start {
+		candidates = append(candidates, response[start:end+1])
+	}
+	return candidates
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package review
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// riskRank orders blast radius risk levels.
+var riskRank = map[analysis.RiskLevel]int{
+	analysis.RiskLow:      0,
+	analysis.RiskMedium:   1,
+	analysis.RiskHigh:     2,
+	analysis.RiskCritical: 3,
+}
+
+// ParseDiff parses a unified diff (single or multi-file).
+//
+// Outputs:
+//
+//	[]*diff.ProposedChange - One entry per file
+//	error - ErrEmptyDiff if no file changes are present, or a parse error
+func ParseDiff(text string) ([]*diff.ProposedChange, error) {
+	changes, err := diff.ParseMultiFileDiff(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, ErrEmptyDiff
+	}
+	return changes, nil
+}
+
+// Reviewer grounds a diff in the code graph.
+//
+// Thread Safety: Safe for concurrent use once the graph is frozen.
+type Reviewer struct {
+	index *index.SymbolIndex
+	blast *analysis.BlastRadiusAnalyzer
+
+	// tests maps symbols to the tests that exercise them. Nil if the
+	// mapping could not be built.
+	tests *analysis.TestMapping
+}
+
+// NewReviewer creates a reviewer for the given graph and index.
+//
+// Description:
+//
+//	Builds the test-to-code mapping of g, so findings about untested
+//	changes are based on tests that actually reach a symbol rather than
+//	on test file names.
+//
+// Inputs:
+//
+//	g - The code graph of the changed tree. Must be frozen.
+//	idx - The symbol index for g.
+//
+// Outputs:
+//
+//	*Reviewer - The reviewer
+func NewReviewer(g *graph.Graph, idx *index.SymbolIndex) *Reviewer {
+	r := &Reviewer{
+		index: idx,
+		blast: analysis.NewBlastRadiusAnalyzer(g, idx, nil),
+	}
+	tests, err := analysis.BuildTestMapping(context.Background(), g, nil)
+	if err != nil {
+		slog.Debug("review: test mapping unavailable", "error", err)
+		return r
+	}
+	r.tests = tests
+	r.blast.SetTestMapping(tests)
+	return r
+}
+
+// Analyze maps the diff to changed symbols and derives graph findings.
+//
+// Description:
+//
+//	A symbol is changed if any added line, or the position of any removed
+//	line, falls within its line range in the new file. Each changed symbol
+//	gets a blast radius, and findings are emitted for:
+//
+//	  - HIGH/CRITICAL blast radius (warning/error)
+//	  - exported symbols with callers but no covering tests (warning)
+//	  - deleted files whose symbols still have callers (error)
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	changes - Parsed diff from ParseDiff
+//
+// Outputs:
+//
+//	*Result - Changed symbols and graph findings (no agent findings yet)
+//	error - Non-nil only if ctx is cancelled
+func (r *Reviewer) Analyze(ctx context.Context, changes []*diff.ProposedChange) (*Result, error) {
+	result := &Result{
+		RiskLevel:    string(analysis.RiskLow),
+		FilesChanged: len(changes),
+	}
+	overall := analysis.RiskLow
+
+	for _, change := range changes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		lines := changedLines(change)
+		for _, sym := range r.index.GetByFile(change.FilePath) {
+			if !sym.Kind.IsDocumentable() {
+				continue
+			}
+			line, touched := firstLineInRange(lines, sym.StartLine, sym.EndLine)
+			if !change.IsDelete && !touched {
+				continue
+			}
+			if change.IsDelete {
+				line = 0
+			}
+
+			cs := ChangedSymbol{
+				ID:        sym.ID,
+				Name:      sym.Name,
+				Kind:      sym.Kind.String(),
+				FilePath:  sym.FilePath,
+				Line:      line,
+				Exported:  sym.Exported,
+				RiskLevel: string(analysis.RiskLow),
+				Deleted:   change.IsDelete,
+			}
+
+			br, err := r.blast.Analyze(ctx, sym.ID, nil)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				slog.Debug("review: blast radius failed", "symbol", sym.ID, "error", err)
+			} else {
+				cs.RiskLevel = string(br.RiskLevel)
+				cs.DirectCallers = len(br.DirectCallers)
+				cs.IndirectCallers = len(br.IndirectCallers)
+				cs.TestFiles = br.TestFiles
+				if riskRank[br.RiskLevel] > riskRank[overall] {
+					overall = br.RiskLevel
+				}
+			}
+
+			if r.tests != nil {
+				cs.TestFiles = r.tests.TestFilesFor([]string{sym.ID}, 0)
+			}
+
+			result.ChangedSymbols = append(result.ChangedSymbols, cs)
+			result.Findings = append(result.Findings, graphFindings(cs)...)
+		}
+	}
+
+	result.RiskLevel = string(overall)
+	result.Summary = fmt.Sprintf("%d file(s), %d changed symbol(s), %s risk",
+		result.FilesChanged, len(result.ChangedSymbols), result.RiskLevel)
+	SortFindings(result.Findings)
+	return result, nil
+}
+
+// graphFindings derives deterministic findings for one changed symbol.
+func graphFindings(cs ChangedSymbol) []Finding {
+	var findings []Finding
+	callers := cs.DirectCallers + cs.IndirectCallers
+
+	if cs.Deleted {
+		if cs.DirectCallers > 0 {
+			findings = append(findings, Finding{
+				Severity:   SeverityError,
+				FilePath:   cs.FilePath,
+				Symbol:     cs.Name,
+				Message:    fmt.Sprintf("%s is deleted but still has %d direct caller(s)", cs.Name, cs.DirectCallers),
+				Suggestion: "Update or remove the callers in the same change",
+				Source:     SourceGraph,
+			})
+		}
+		return findings
+	}
+
+	switch analysis.RiskLevel(cs.RiskLevel) {
+	case analysis.RiskCritical:
+		findings = append(findings, Finding{
+			Severity:   SeverityError,
+			FilePath:   cs.FilePath,
+			Line:       cs.Line,
+			Symbol:     cs.Name,
+			Message:    fmt.Sprintf("Change to %s has a critical blast radius (%d direct, %d indirect callers)", cs.Name, cs.DirectCallers, cs.IndirectCallers),
+			Suggestion: "Keep the signature and behavior backward compatible, or split the change",
+			Source:     SourceGraph,
+		})
+	case analysis.RiskHigh:
+		findings = append(findings, Finding{
+			Severity:   SeverityWarning,
+			FilePath:   cs.FilePath,
+			Line:       cs.Line,
+			Symbol:     cs.Name,
+			Message:    fmt.Sprintf("Change to %s affects %d direct and %d indirect callers", cs.Name, cs.DirectCallers, cs.IndirectCallers),
+			Suggestion: "Check that callers still hold their assumptions about this symbol",
+			Source:     SourceGraph,
+		})
+	}
+
+	if cs.Exported && callers > 0 && len(cs.TestFiles) == 0 {
+		findings = append(findings, Finding{
+			Severity:   SeverityWarning,
+			FilePath:   cs.FilePath,
+			Line:       cs.Line,
+			Symbol:     cs.Name,
+			Message:    fmt.Sprintf("No tests exercise %s, which has %d caller(s)", cs.Name, callers),
+			Suggestion: fmt.Sprintf("Add a test covering the changed behavior of %s", cs.Name),
+			Source:     SourceGraph,
+		})
+	}
+
+	return findings
+}
+
+// changedLines returns the sorted new-file line numbers touched by the
+// change. Removed lines are attributed to the new-file line where the
+// removal happened.
+func changedLines(change *diff.ProposedChange) []int {
+	seen := make(map[int]bool)
+	for _, h := range change.Hunks {
+		newLine := h.NewStart
+		for _, l := range h.Lines {
+			switch {
+			case l.IsAddition():
+				seen[newLine] = true
+				newLine++
+			case l.IsDeletion():
+				if newLine > 0 {
+					seen[newLine] = true
+				} else {
+					seen[1] = true
+				}
+			default:
+				newLine++
+			}
+		}
+	}
+
+	lines := make([]int, 0, len(seen))
+	for l := range seen {
+		lines = append(lines, l)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// firstLineInRange returns the first line in sorted lines within [start, end].
+func firstLineInRange(lines []int, start, end int) (int, bool) {
+	i := sort.SearchInts(lines, start)
+	if i < len(lines) && lines[i] <= end {
+		return lines[i], true
+	}
+	return 0, false
+}
+
+// Merge adds agent findings to a result, dropping duplicates of graph
+// findings at the same location, and re-sorts.
+func Merge(result *Result, agentFindings []Finding) {
+	seen := make(map[string]bool, len(result.Findings))
+	for _, f := range result.Findings {
+		seen[f.Location()+"|"+f.Message] = true
+	}
+	for _, f := range agentFindings {
+		key := f.Location() + "|" + f.Message
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result.Findings = append(result.Findings, f)
+	}
+	SortFindings(result.Findings)
+}
+
+// SortFindings orders findings by descending severity, then file and line.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+}
@@ -0,0 +1,228 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const testDiff = `diff --git a/pkg/service.go b/pkg/service.go
+--- a/pkg/service.go
++++ b/pkg/service.go
+@@ -11,3 +11,4 @@ func ProcessData(ctx context.Context, data []byte) error {
+ 	if len(data) == 0 {
+-		return nil
++		return ErrEmpty
++	}
+ 	return nil
+`
+
+// createTestGraph builds ProcessData (lines 10-25) with 12 callers, plus an
+// unrelated Helper (lines 30-40). With tested set, TestProcessData in
+// pkg/service_test.go calls ProcessData.
+func createTestGraph(t *testing.T, tested bool) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+
+	g := graph.NewGraph("/test/project")
+	idx := index.NewSymbolIndex()
+
+	add := func(sym *ast.Symbol) {
+		if err := idx.Add(sym); err != nil {
+			t.Fatalf("index add: %v", err)
+		}
+		g.AddNode(sym)
+	}
+
+	target := &ast.Symbol{
+		ID: "pkg/service.go:10:ProcessData", Name: "ProcessData", Kind: ast.SymbolKindFunction,
+		FilePath: "pkg/service.go", StartLine: 10, EndLine: 25, Exported: true, Language: "go",
+	}
+	helper := &ast.Symbol{
+		ID: "pkg/service.go:30:Helper", Name: "Helper", Kind: ast.SymbolKindFunction,
+		FilePath: "pkg/service.go", StartLine: 30, EndLine: 40, Exported: true, Language: "go",
+	}
+	add(target)
+	add(helper)
+
+	for i := 0; i < 12; i++ {
+		caller := &ast.Symbol{
+			ID:   fmt.Sprintf("pkg/caller%d.go:5:Caller%d", i, i),
+			Name: fmt.Sprintf("Caller%d", i), Kind: ast.SymbolKindFunction,
+			FilePath: fmt.Sprintf("pkg/caller%d.go", i), StartLine: 5, EndLine: 10, Language: "go",
+		}
+		add(caller)
+		g.AddEdge(caller.ID, target.ID, graph.EdgeTypeCalls, ast.Location{FilePath: caller.FilePath, StartLine: 7})
+	}
+
+	if tested {
+		test := &ast.Symbol{
+			ID: "pkg/service_test.go:8:TestProcessData", Name: "TestProcessData", Kind: ast.SymbolKindFunction,
+			FilePath: "pkg/service_test.go", StartLine: 8, EndLine: 20, Exported: true, Language: "go",
+		}
+		add(test)
+		g.AddEdge(test.ID, target.ID, graph.EdgeTypeCalls, ast.Location{FilePath: test.FilePath, StartLine: 10})
+	}
+
+	g.Freeze()
+	return g, idx
+}
+
+func TestReviewer_Analyze(t *testing.T) {
+	g, idx := createTestGraph(t, false)
+	changes, err := ParseDiff(testDiff)
+	if err != nil {
+		t.Fatalf("ParseDiff failed: %v", err)
+	}
+
+	result, err := NewReviewer(g, idx).Analyze(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(result.ChangedSymbols) != 1 || result.ChangedSymbols[0].Name != "ProcessData" {
+		t.Fatalf("expected only ProcessData changed, got %+v", result.ChangedSymbols)
+	}
+	cs := result.ChangedSymbols[0]
+	if cs.Line != 12 {
+		t.Errorf("expected first changed line 12, got %d", cs.Line)
+	}
+	if cs.DirectCallers != 12 {
+		t.Errorf("expected 12 direct callers, got %d", cs.DirectCallers)
+	}
+	if result.RiskLevel == "LOW" {
+		t.Errorf("expected elevated risk for 12 callers, got %s", result.RiskLevel)
+	}
+
+	var untested bool
+	for _, f := range result.Findings {
+		if f.Source != SourceGraph {
+			t.Errorf("unexpected source %q", f.Source)
+		}
+		if strings.Contains(f.Message, "No tests exercise ProcessData") {
+			untested = true
+		}
+	}
+	if !untested {
+		t.Errorf("expected untested-change finding, got %+v", result.Findings)
+	}
+}
+
+func TestReviewer_Analyze_TestedSymbol(t *testing.T) {
+	g, idx := createTestGraph(t, true)
+	changes, err := ParseDiff(testDiff)
+	if err != nil {
+		t.Fatalf("ParseDiff failed: %v", err)
+	}
+
+	result, err := NewReviewer(g, idx).Analyze(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var cs *ChangedSymbol
+	for i := range result.ChangedSymbols {
+		if result.ChangedSymbols[i].Name == "ProcessData" {
+			cs = &result.ChangedSymbols[i]
+		}
+	}
+	if cs == nil || len(cs.TestFiles) != 1 || cs.TestFiles[0] != "pkg/service_test.go" {
+		t.Fatalf("expected ProcessData covered by pkg/service_test.go, got %+v", result.ChangedSymbols)
+	}
+	for _, f := range result.Findings {
+		if strings.Contains(f.Message, "No tests exercise") {
+			t.Errorf("unexpected untested-change finding %+v", f)
+		}
+	}
+}
+
+func TestParseDiff_Empty(t *testing.T) {
+	if _, err := ParseDiff(""); !errors.Is(err, ErrEmptyDiff) {
+		t.Errorf("expected ErrEmptyDiff, got %v", err)
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	t.Run("last fenced block wins", func(t *testing.T) {
+		resp := "Example:\n```json\n[{\"message\":\"example\"}]\n```\nFindings:\n```json\n" +
+			`[{"severity":"HIGH","file":"b/pkg/service.go","line":12,"message":"returns error for empty input","suggestion":"document it"},` +
+			`{"severity":"info","file":"x.go","message":""}]` + "\n```"
+		findings, err := ParseFindings(resp)
+		if err != nil {
+			t.Fatalf("ParseFindings failed: %v", err)
+		}
+		if len(findings) != 1 {
+			t.Fatalf("expected 1 finding, got %+v", findings)
+		}
+		f := findings[0]
+		if f.Severity != SeverityError || f.FilePath != "pkg/service.go" || f.Line != 12 || f.Source != SourceAgent {
+			t.Errorf("unexpected finding %+v", f)
+		}
+		if f.Location() != "pkg/service.go:12" {
+			t.Errorf("unexpected location %q", f.Location())
+		}
+	})
+
+	t.Run("bare array", func(t *testing.T) {
+		findings, err := ParseFindings(`Here you go: [{"severity":"warning","file":"a.go","line":3,"message":"m"}]`)
+		if err != nil || len(findings) != 1 || findings[0].Severity != SeverityWarning {
+			t.Errorf("unexpected result %+v, %v", findings, err)
+		}
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		if _, err := ParseFindings("Looks good to me."); !errors.Is(err, ErrNoFindings) {
+			t.Errorf("expected ErrNoFindings, got %v", err)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	result := &Result{Findings: []Finding{
+		{Severity: SeverityWarning, FilePath: "a.go", Line: 3, Message: "m", Source: SourceGraph},
+	}}
+	Merge(result, []Finding{
+		{Severity: SeverityWarning, FilePath: "a.go", Line: 3, Message: "m", Source: SourceAgent},
+		{Severity: SeverityCritical, FilePath: "b.go", Line: 1, Message: "nil deref", Source: SourceAgent},
+	})
+
+	if len(result.Findings) != 2 {
+		t.Fatalf("expected duplicate dropped, got %+v", result.Findings)
+	}
+	if result.Findings[0].Severity != SeverityCritical {
+		t.Errorf("expected critical first, got %+v", result.Findings[0])
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	result := &Result{
+		ChangedSymbols: []ChangedSymbol{{Name: "ProcessData", Kind: "function", FilePath: "pkg/service.go", Line: 12, RiskLevel: "HIGH", DirectCallers: 12}},
+		Findings:       []Finding{{Severity: SeverityWarning, FilePath: "pkg/service.go", Line: 12, Message: "No tests"}},
+	}
+	prompt := BuildPrompt(testDiff, result)
+	for _, want := range []string{"ProcessData (function) at pkg/service.go:12", "[warning] pkg/service.go:12: No tests", "```diff", "```json"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	long := strings.Repeat("+x\n", MaxPromptDiffBytes)
+	if !strings.Contains(BuildPrompt(long, &Result{}), "(diff truncated)") {
+		t.Error("expected long diff to be truncated")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package review
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the review package.
+var (
+	// ErrEmptyDiff indicates the diff contains no file changes.
+	ErrEmptyDiff = errors.New("diff contains no changes")
+
+	// ErrNoFindings indicates the agent response contained no parsable
+	// findings block.
+	ErrNoFindings = errors.New("no findings in agent response")
+)
+
+// Severity is the importance of a finding.
+type Severity string
+
+const (
+	// SeverityInfo is an observation that needs no action.
+	SeverityInfo Severity = "info"
+
+	// SeverityWarning should be looked at before merging.
+	SeverityWarning Severity = "warning"
+
+	// SeverityError is likely a bug or breaking change.
+	SeverityError Severity = "error"
+
+	// SeverityCritical must be fixed before merging.
+	SeverityCritical Severity = "critical"
+)
+
+// severityRank orders severities for sorting.
+var severityRank = map[Severity]int{
+	SeverityInfo:     0,
+	SeverityWarning:  1,
+	SeverityError:    2,
+	SeverityCritical: 3,
+}
+
+// ParseSeverity normalizes a severity string. Unknown values map to
+// SeverityInfo.
+func ParseSeverity(s string) Severity {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	switch sev {
+	case SeverityWarning, SeverityError, SeverityCritical:
+		return sev
+	case "high":
+		return SeverityError
+	case "medium", "warn":
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// Source identifies which review stage produced a finding.
+type Source string
+
+const (
+	// SourceGraph findings come from deterministic graph analysis.
+	SourceGraph Source = "graph"
+
+	// SourceAgent findings come from the agent loop.
+	SourceAgent Source = "agent"
+)
+
+// Finding is one structured review comment.
+type Finding struct {
+	// Severity is how important the finding is.
+	Severity Severity `json:"severity"`
+
+	// FilePath is the repository-relative file the finding refers to.
+	FilePath string `json:"file"`
+
+	// Line is the 1-indexed line in the new version of the file (0 if the
+	// finding applies to the whole file).
+	Line int `json:"line"`
+
+	// Symbol is the affected symbol name, if any.
+	Symbol string `json:"symbol,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+
+	// Suggestion is the recommended fix.
+	Suggestion string `json:"suggestion,omitempty"`
+
+	// Source is the stage that produced the finding.
+	Source Source `json:"source"`
+}
+
+// Location returns "file:line", or just the file if Line is 0.
+func (f Finding) Location() string {
+	if f.Line <= 0 {
+		return f.FilePath
+	}
+	return f.FilePath + ":" + strconv.Itoa(f.Line)
+}
+
+// ChangedSymbol is a symbol touched by the diff, with its blast radius.
+type ChangedSymbol struct {
+	// ID is the graph symbol ID.
+	ID string `json:"id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind.
+	Kind string `json:"kind"`
+
+	// FilePath is the file containing the symbol.
+	FilePath string `json:"file_path"`
+
+	// Line is the first changed line inside the symbol.
+	Line int `json:"line"`
+
+	// Exported reports whether the symbol is part of the public API.
+	Exported bool `json:"exported"`
+
+	// RiskLevel is the blast radius risk (LOW, MEDIUM, HIGH, CRITICAL).
+	RiskLevel string `json:"risk_level"`
+
+	// DirectCallers is the number of direct callers.
+	DirectCallers int `json:"direct_callers"`
+
+	// IndirectCallers is the number of transitive callers.
+	IndirectCallers int `json:"indirect_callers"`
+
+	// TestFiles are tests that exercise the symbol.
+	TestFiles []string `json:"test_files,omitempty"`
+
+	// Deleted reports whether the symbol's file is deleted by the diff.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// Result is the outcome of a review.
+type Result struct {
+	// RiskLevel is the highest risk across changed symbols.
+	RiskLevel string `json:"risk_level"`
+
+	// FilesChanged is the number of files in the diff.
+	FilesChanged int `json:"files_changed"`
+
+	// ChangedSymbols are the symbols touched by the diff.
+	ChangedSymbols []ChangedSymbol `json:"changed_symbols"`
+
+	// Findings are sorted by severity, then location.
+	Findings []Finding `json:"findings"`
+
+	// Summary is a short overall assessment.
+	Summary string `json:"summary,omitempty"`
+}
@@ -284,6 +284,13 @@ func DefaultToolSpecs() []ToolSpec {
 			Params:      []string{"name", "kind"},
 			Category:    "search",
 		},
+		{
+			Name:        "search_code",
+			Description: "Search symbols by partial name, words, or misspelling; results ranked by relevance and importance.",
+			BestFor:     []string{"unknown exact name", "fuzzy symbol lookup", "finding symbols related to a concept"},
+			Params:      []string{"query", "kind", "path"},
+			Category:    "search",
+		},
 		{
 			Name:        "find_symbol_usages",
 			Description: "Find all places where a symbol is used/called.",
@@ -333,6 +340,13 @@ func DefaultToolSpecs() []ToolSpec {
 			Params:      []string{"symbol_id", "depth"},
 			Category:    "analyze",
 		},
+		{
+			Name:        "generate_commit_message",
+			Description: "Write a conventional-commit message and changelog fragment for a diff, ranked by blast radius.",
+			BestFor:     []string{"commit message", "changelog entry", "describing a diff"},
+			Params:      []string{"diff", "style"},
+			Category:    "reasoning",
+		},
 		{
 			Name:        "answer",
 			Description: "Provide a direct answer when no tool is needed.",
@@ -13,11 +13,14 @@ package agent
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -362,6 +365,14 @@ type Session struct {
 	// require tool usage and should accept text responses as final answers.
 	// GR-44: Fixes death spiral where CB fires but execute phase still demands tools.
 	circuitBreakerActive bool
+
+	// editHistory records file writes by the session's tools for undo.
+	// Lazily initialized on first access.
+	editHistory *undo.Stack
+
+	// editStore keeps editHistory's content and operations when set, so
+	// the history outlives the process. Set by the agent loop.
+	editStore *cas.Store
 }
 
 // SafetyViolation represents a safety-blocked operation for CDCL learning.
@@ -935,6 +946,51 @@ func (s *Session) GetTraceRecorder() *crs.TraceRecorder {
 	return s.traceRecorder
 }
 
+// EditHistory returns the undo/redo history of files written by this
+// session's tools.
+//
+// Description:
+//
+//	The history is created on first access. With an edit store (see
+//	WithEditStore) it resumes the history saved there under the session
+//	ID, so edits stay undoable after a restart; otherwise it keeps file
+//	content in memory for the lifetime of the session. A saved history
+//	that cannot be read is logged and replaced.
+//
+// Outputs:
+//
+//	*undo.Stack - The edit history. Never nil.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) EditHistory() *undo.Stack {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.editHistory == nil {
+		config := undo.Config{Store: s.editStore, Namespace: s.ID}
+		stack, err := undo.Open(config)
+		if err != nil {
+			slog.Warn("Discarding unreadable edit history",
+				slog.String("session_id", s.ID),
+				slog.String("error", err.Error()))
+			stack = undo.NewStack(config)
+		}
+		s.editHistory = stack
+	}
+	return s.editHistory
+}
+
+// setEditStore sets the store EditHistory keeps edits in. It has no effect
+// once the history exists.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) setEditStore(store *cas.Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.editHistory == nil {
+		s.editStore = store
+	}
+}
+
 // GetReasoningSummary returns the reasoning summary for this session.
 //
 // Description:
@@ -0,0 +1,431 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/sessionstore"
+)
+
+// SessionLocker is implemented by session stores shared between replicas.
+//
+// Description:
+//
+//	The agent loop holds the lock for the whole of Run or Continue so no
+//	other replica runs the same session concurrently, and saves the session
+//	with Put before releasing it.
+type SessionLocker interface {
+	// Lock acquires the session for this replica.
+	//
+	// Outputs:
+	//   func() - Releases the lock.
+	//   error - ErrSessionInProgress if another replica holds the session.
+	Lock(ctx context.Context, sessionID string) (func(), error)
+}
+
+// sessionRecordVersion is the current sessionRecord format.
+const sessionRecordVersion = 1
+
+// sessionRecord is the stored form of a Session.
+//
+// Only serializable state is kept. Process-local components (CRS, tool
+// router, model manager, trace recorder) are rebuilt by the replica that
+// loads the session.
+type sessionRecord struct {
+	Version              int               `json:"version"`
+	ID                   string            `json:"id"`
+	ProjectRoot          string            `json:"project_root"`
+	GraphID              string            `json:"graph_id,omitempty"`
+	State                AgentState        `json:"state"`
+	Config               *SessionConfig    `json:"config"`
+	History              []HistoryEntry    `json:"history"`
+	Metrics              *SessionMetrics   `json:"metrics"`
+	CurrentContext       *AssembledContext `json:"current_context,omitempty"`
+	LastQuery            string            `json:"last_query,omitempty"`
+	LastIntent           *QueryIntent      `json:"last_intent,omitempty"`
+	CreatedAt            int64             `json:"created_at"`
+	LastActiveAt         int64             `json:"last_active_at"`
+	RecentToolErrors     []ToolRouterError `json:"recent_tool_errors,omitempty"`
+	SafetyViolations     []SafetyViolation `json:"safety_violations,omitempty"`
+	CircuitBreakerActive bool              `json:"circuit_breaker_active,omitempty"`
+}
+
+// encodeSession serializes a session's shareable state.
+//
+// Thread Safety: This function is safe for concurrent use.
+func encodeSession(s *Session) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(sessionRecord{
+		Version:              sessionRecordVersion,
+		ID:                   s.ID,
+		ProjectRoot:          s.ProjectRoot,
+		GraphID:              s.GraphID,
+		State:                s.State,
+		Config:               s.Config,
+		History:              s.History,
+		Metrics:              s.Metrics,
+		CurrentContext:       s.CurrentContext,
+		LastQuery:            s.LastQuery,
+		LastIntent:           s.LastIntent,
+		CreatedAt:            s.CreatedAt,
+		LastActiveAt:         s.LastActiveAt,
+		RecentToolErrors:     s.recentToolErrors,
+		SafetyViolations:     s.safetyViolations,
+		CircuitBreakerActive: s.circuitBreakerActive,
+	})
+}
+
+// decodeSessionRecord parses a stored session.
+func decodeSessionRecord(data []byte) (*sessionRecord, error) {
+	var rec sessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("decoding session: %w", err)
+	}
+	if rec.Version != sessionRecordVersion {
+		return nil, fmt.Errorf("decoding session: unsupported record version %d", rec.Version)
+	}
+	if rec.Metrics == nil {
+		rec.Metrics = &SessionMetrics{}
+	}
+	if rec.History == nil {
+		rec.History = make([]HistoryEntry, 0)
+	}
+	return &rec, nil
+}
+
+// applyRecord replaces the session's shareable state, keeping its
+// process-local components.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) applyRecord(rec *sessionRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ID = rec.ID
+	s.ProjectRoot = rec.ProjectRoot
+	s.GraphID = rec.GraphID
+	s.State = rec.State
+	s.Config = rec.Config
+	s.History = rec.History
+	s.Metrics = rec.Metrics
+	s.CurrentContext = rec.CurrentContext
+	s.LastQuery = rec.LastQuery
+	s.LastIntent = rec.LastIntent
+	s.CreatedAt = rec.CreatedAt
+	s.LastActiveAt = rec.LastActiveAt
+	s.recentToolErrors = rec.RecentToolErrors
+	s.safetyViolations = rec.SafetyViolations
+	s.circuitBreakerActive = rec.CircuitBreakerActive
+	s.cachedSummary = nil
+}
+
+// DefaultSessionCacheSize is the number of idle sessions a
+// SharedSessionStore keeps in memory.
+const DefaultSessionCacheSize = 1024
+
+// SharedSessionStore is a SessionStore backed by a sessionstore.Backend.
+//
+// Description:
+//
+//	Stores sessions outside the process so any replica can serve any
+//	session. Sessions are encoded as JSON on Put and decoded on Get.
+//
+//	Each replica keeps the Session objects it has handed out and refreshes
+//	them in place on Get, so process-local components attached by this
+//	replica (tool router, CRS, trace recorder) survive between requests.
+//	A session first loaded on a different replica starts without them.
+//	At most DefaultSessionCacheSize idle sessions are kept, least recently
+//	used first out; an evicted session is decoded afresh on its next Get.
+//	While this replica holds a session's lock, Get returns the live object
+//	without reading the backend, and the lease is renewed in the
+//	background until the lock is released.
+//
+//	SessionStore methods cannot return errors, so backend failures are
+//	logged and reported as "not found" (Get) or an empty list (List).
+//
+// Thread Safety: SharedSessionStore is safe for concurrent use.
+type SharedSessionStore struct {
+	backend   sessionstore.Backend
+	leaseTTL  time.Duration
+	timeout   time.Duration
+	cacheSize int
+	logger    *slog.Logger
+
+	mu     sync.Mutex
+	cache  map[string]*list.Element // idle sessions, values are *cachedSession
+	order  *list.List               // front = most recently used
+	leased map[string]*Session      // locked here; nil until loaded or put
+}
+
+// cachedSession is one entry of the idle-session LRU.
+type cachedSession struct {
+	id      string
+	session *Session
+}
+
+// NewSharedSessionStore creates a store over a shared backend.
+//
+// Inputs:
+//
+//	backend - The shared backend. Must not be nil.
+//
+// Outputs:
+//
+//	*SharedSessionStore - The new store.
+func NewSharedSessionStore(backend sessionstore.Backend) *SharedSessionStore {
+	return &SharedSessionStore{
+		backend:   backend,
+		leaseTTL:  sessionstore.DefaultLeaseTTL,
+		timeout:   5 * time.Second,
+		cacheSize: DefaultSessionCacheSize,
+		logger:    slog.Default(),
+		cache:     make(map[string]*list.Element),
+		order:     list.New(),
+		leased:    make(map[string]*Session),
+	}
+}
+
+// Get implements SessionStore.
+func (s *SharedSessionStore) Get(id string) (*Session, bool) {
+	s.mu.Lock()
+	live := s.leased[id]
+	s.mu.Unlock()
+	if live != nil {
+		return live, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	data, err := s.backend.Load(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sessionstore.ErrNotFound) {
+			s.logger.Error("Session store load failed",
+				slog.String("session_id", id),
+				slog.String("error", err.Error()),
+			)
+		}
+		s.forget(id)
+		return nil, false
+	}
+	rec, err := decodeSessionRecord(data)
+	if err != nil {
+		s.logger.Error("Session store decode failed",
+			slog.String("session_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session := s.lookup(id)
+	if session == nil {
+		session = &Session{traceRecorder: crs.NewTraceRecorder(crs.DefaultTraceConfig())}
+	}
+	session.applyRecord(rec)
+	s.remember(id, session)
+	return session, true
+}
+
+// Put implements SessionStore.
+func (s *SharedSessionStore) Put(session *Session) {
+	data, err := encodeSession(session)
+	if err != nil {
+		s.logger.Error("Session store encode failed",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.backend.Save(ctx, session.ID, data); err != nil {
+		s.logger.Error("Session store save failed",
+			slog.String("session_id", session.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remember(session.ID, session)
+}
+
+// Delete implements SessionStore.
+func (s *SharedSessionStore) Delete(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.backend.Delete(ctx, id); err != nil {
+		s.logger.Error("Session store delete failed",
+			slog.String("session_id", id),
+			slog.String("error", err.Error()),
+		)
+	}
+	s.forget(id)
+}
+
+// List implements SessionStore.
+//
+// Outputs:
+//
+//	[]string - All stored session IDs across replicas, sorted alphabetically.
+func (s *SharedSessionStore) List() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	ids, err := s.backend.List(ctx)
+	if err != nil {
+		s.logger.Error("Session store list failed", slog.String("error", err.Error()))
+		return []string{}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Lock implements SessionLocker.
+//
+// Description:
+//
+//	Takes the session's lease in the backend and renews it every third of
+//	sessionstore.DefaultLeaseTTL until the returned function is called.
+//	The lease expires if this replica dies without releasing it.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *SharedSessionStore) Lock(ctx context.Context, sessionID string) (func(), error) {
+	lease, err := s.backend.Lock(ctx, sessionID, s.leaseTTL)
+	if errors.Is(err, sessionstore.ErrLocked) {
+		return nil, ErrSessionInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking session %s: %w", sessionID, err)
+	}
+
+	s.mu.Lock()
+	s.leased[sessionID] = s.lookup(sessionID)
+	s.unlink(sessionID)
+	s.mu.Unlock()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go s.renewLease(sessionID, lease, stop, done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+
+			s.mu.Lock()
+			live, ok := s.leased[sessionID]
+			delete(s.leased, sessionID)
+			if ok && live != nil {
+				s.remember(sessionID, live)
+			}
+			s.mu.Unlock()
+			lease.Release()
+		})
+	}, nil
+}
+
+// renewLease renews a held lease until stop is closed or the lease is
+// lost. Closes done on return.
+func (s *SharedSessionStore) renewLease(id string, lease sessionstore.Lease, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err := lease.Renew(ctx, s.leaseTTL)
+		cancel()
+		if errors.Is(err, sessionstore.ErrLocked) {
+			s.logger.Error("Session lease lost",
+				slog.String("session_id", id),
+			)
+			return
+		}
+		if err != nil {
+			s.logger.Warn("Session lease renewal failed",
+				slog.String("session_id", id),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// lookup returns the local session object, locked or idle, or nil.
+//
+// Caller must hold s.mu.
+func (s *SharedSessionStore) lookup(id string) *Session {
+	if live := s.leased[id]; live != nil {
+		return live
+	}
+	if el, ok := s.cache[id]; ok {
+		return el.Value.(*cachedSession).session
+	}
+	return nil
+}
+
+// remember records the local session object: as the live object if the
+// session is locked here, otherwise at the front of the idle LRU, evicting
+// the least recently used sessions beyond cacheSize.
+//
+// Caller must hold s.mu.
+func (s *SharedSessionStore) remember(id string, session *Session) {
+	if _, ok := s.leased[id]; ok {
+		s.leased[id] = session
+		return
+	}
+	if el, ok := s.cache[id]; ok {
+		el.Value.(*cachedSession).session = session
+		s.order.MoveToFront(el)
+		return
+	}
+	s.cache[id] = s.order.PushFront(&cachedSession{id: id, session: session})
+	for s.order.Len() > s.cacheSize {
+		s.unlink(s.order.Back().Value.(*cachedSession).id)
+	}
+}
+
+// unlink removes a session from the idle LRU.
+//
+// Caller must hold s.mu.
+func (s *SharedSessionStore) unlink(id string) {
+	if el, ok := s.cache[id]; ok {
+		s.order.Remove(el)
+		delete(s.cache, id)
+	}
+}
+
+// forget drops a session from the local cache.
+func (s *SharedSessionStore) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unlink(id)
+	delete(s.leased, id)
+}
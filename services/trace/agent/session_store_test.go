@@ -0,0 +1,204 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/sessionstore"
+)
+
+func TestSharedSessionStore_AcrossReplicas(t *testing.T) {
+	backend := sessionstore.NewMemory()
+	replicaA := NewSharedSessionStore(backend)
+	replicaB := NewSharedSessionStore(backend)
+
+	session, err := NewSession("/test/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	session.SetState(StateComplete)
+	session.AddHistoryEntry(HistoryEntry{Type: "query", Query: "what calls main?"})
+	session.SetCurrentContext(&AssembledContext{
+		ConversationHistory: []Message{{Role: "user", Content: "what calls main?"}},
+	})
+	session.SetCircuitBreakerActive(true)
+	replicaA.Put(session)
+
+	got, ok := replicaB.Get(session.ID)
+	if !ok {
+		t.Fatal("session not found on replica B")
+	}
+	if got.GetState() != StateComplete || len(got.GetHistory()) != 1 || !got.IsCircuitBreakerActive() {
+		t.Errorf("state not shared: state=%s history=%d cb=%v",
+			got.GetState(), len(got.GetHistory()), got.IsCircuitBreakerActive())
+	}
+	if ctx := got.GetCurrentContext(); ctx == nil || len(ctx.ConversationHistory) != 1 {
+		t.Errorf("conversation history not shared: %+v", ctx)
+	}
+	if ids := replicaB.List(); len(ids) != 1 || ids[0] != session.ID {
+		t.Errorf("List = %v", ids)
+	}
+
+	t.Run("refreshes cached session in place", func(t *testing.T) {
+		session.SetState(StateError)
+		replicaA.Put(session)
+
+		again, ok := replicaB.Get(session.ID)
+		if !ok || again != got {
+			t.Fatal("Get should return the cached session object")
+		}
+		if again.GetState() != StateError {
+			t.Errorf("State = %s, want ERROR", again.GetState())
+		}
+	})
+
+	t.Run("lock excludes other replicas", func(t *testing.T) {
+		unlock, err := replicaA.Lock(context.Background(), session.ID)
+		if err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		if _, err := replicaB.Lock(context.Background(), session.ID); !errors.Is(err, ErrSessionInProgress) {
+			t.Errorf("Lock on B = %v, want ErrSessionInProgress", err)
+		}
+		unlock()
+		unlockB, err := replicaB.Lock(context.Background(), session.ID)
+		if err != nil {
+			t.Fatalf("Lock on B after release: %v", err)
+		}
+		unlockB()
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		replicaB.Delete(session.ID)
+		if _, ok := replicaA.Get(session.ID); ok {
+			t.Error("session still found after Delete")
+		}
+	})
+}
+
+func TestDefaultAgentLoop_SharedStore_ContinueOnOtherReplica(t *testing.T) {
+	backend := sessionstore.NewMemory()
+	loopA := NewDefaultAgentLoop(WithSessionStore(NewSharedSessionStore(backend)))
+	loopB := NewDefaultAgentLoop(WithSessionStore(NewSharedSessionStore(backend)))
+
+	session, _ := NewSession("/test/project", nil)
+	if _, err := loopA.Run(context.Background(), session, "What does this function do?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	state, err := loopB.GetState(session.ID)
+	if err != nil {
+		t.Fatalf("GetState on replica B: %v", err)
+	}
+	if state.State != StateComplete {
+		t.Fatalf("State on replica B = %s, want COMPLETE", state.State)
+	}
+
+	result, err := loopB.Continue(context.Background(), session.ID, "And what calls it?")
+	if err != nil {
+		t.Fatalf("Continue on replica B failed: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Errorf("State = %s, want COMPLETE", result.State)
+	}
+
+	// Replica A sees the follow-up turn recorded by replica B.
+	resumed, err := loopA.GetSession(session.ID)
+	if err != nil {
+		t.Fatalf("GetSession on replica A: %v", err)
+	}
+	var followUps int
+	for _, h := range resumed.GetHistory() {
+		if h.Type == "follow_up" {
+			followUps++
+		}
+	}
+	if followUps != 1 {
+		t.Errorf("follow_up entries on replica A = %d, want 1", followUps)
+	}
+}
+
+func TestSharedSessionStore_CacheBounded(t *testing.T) {
+	store := NewSharedSessionStore(sessionstore.NewMemory())
+	store.cacheSize = 2
+
+	sessions := make([]*Session, 3)
+	for i := range sessions {
+		session, err := NewSession("/test/project", nil)
+		if err != nil {
+			t.Fatalf("NewSession failed: %v", err)
+		}
+		sessions[i] = session
+		store.Put(session)
+	}
+	if n := store.order.Len(); n != 2 || len(store.cache) != 2 {
+		t.Fatalf("cache holds %d sessions (%d indexed), want 2", n, len(store.cache))
+	}
+
+	// The evicted session is still stored and decodes into a new object.
+	got, ok := store.Get(sessions[0].ID)
+	if !ok {
+		t.Fatal("evicted session not found")
+	}
+	if got == sessions[0] {
+		t.Error("Get returned the evicted object")
+	}
+	if again, _ := store.Get(sessions[2].ID); again != sessions[2] {
+		t.Error("Get should return the cached object of a recent session")
+	}
+
+	t.Run("locked sessions are not evicted", func(t *testing.T) {
+		unlock, err := store.Lock(context.Background(), sessions[1].ID)
+		if err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		live, _ := store.Get(sessions[1].ID)
+		for i := 0; i < 3; i++ {
+			session, _ := NewSession("/test/project", nil)
+			store.Put(session)
+		}
+		if got, _ := store.Get(sessions[1].ID); got != live {
+			t.Error("locked session was evicted")
+		}
+		unlock()
+		if n := store.order.Len(); n != 2 {
+			t.Errorf("cache holds %d sessions after unlock, want 2", n)
+		}
+	})
+}
+
+func TestSharedSessionStore_LockRenewsLease(t *testing.T) {
+	backend := sessionstore.NewMemory()
+	replicaA := NewSharedSessionStore(backend)
+	replicaA.leaseTTL = 60 * time.Millisecond
+	replicaB := NewSharedSessionStore(backend)
+
+	unlock, err := replicaA.Lock(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	time.Sleep(5 * replicaA.leaseTTL)
+	if _, err := replicaB.Lock(context.Background(), "s1"); !errors.Is(err, ErrSessionInProgress) {
+		t.Errorf("Lock on B after the lease ttl = %v, want ErrSessionInProgress", err)
+	}
+
+	unlock()
+	unlock() // idempotent
+	unlockB, err := replicaB.Lock(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("Lock on B after release: %v", err)
+	}
+	unlockB()
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package solutions implements the agent's long-term memory of past
+// solutions, scoped per repository.
+//
+// When a session completes, the agent records what solved the problem:
+//
+//   - Fixes: the files the session changed and the final answer.
+//   - Plans: the tool sequence that answered the query and the final answer.
+//   - Clauses: constraints learned by the CRS during the session (tool
+//     sequences that failed and should not be repeated).
+//
+// Each solution is embedded together with the problem it solved. At plan
+// time, the PLAN phase retrieves the solutions most similar to the new
+// query in the same repository and adds them to the system prompt, so
+// recurring problems reuse prior work instead of being re-derived.
+//
+// Solutions are stored as one JSON file per repository when a persistence
+// directory is configured, and in memory otherwise.
+//
+// Thread Safety:
+//
+//	Store is safe for concurrent use.
+package solutions
@@ -0,0 +1,63 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package solutions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatForPrompt renders retrieved solutions as a system prompt section.
+//
+// Description:
+//
+//	Each solution is listed with its kind, similarity, the problem it
+//	solved, and its content. The section tells the model to verify a prior
+//	solution against the current code before reusing it, since the code
+//	may have changed since it was recorded.
+//
+// Inputs:
+//
+//	matches - Retrieved solutions, best first.
+//
+// Outputs:
+//
+//	string - The prompt section, or "" if there are no matches.
+func FormatForPrompt(matches []Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Prior Solutions in This Repository\n\n")
+	sb.WriteString("Similar problems were solved in this repository before. Reuse these ")
+	sb.WriteString("solutions where they apply, but verify them against the current code ")
+	sb.WriteString("first: files may have changed since.\n")
+
+	for i, m := range matches {
+		fmt.Fprintf(&sb, "\n%d. [%s] (similarity %.2f) Problem: %s\n",
+			i+1, m.Solution.Kind, m.Score, firstLine(m.Solution.Problem))
+		for _, line := range strings.Split(strings.TrimRight(m.Solution.Content, "\n"), "\n") {
+			sb.WriteString("   ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,444 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package solutions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
+)
+
+// Store is a repository-scoped memory of past solutions.
+//
+// # Description
+//
+// Solutions are grouped by repository (the cleaned project root) and
+// retrieved by cosine similarity between the embedded query and the
+// embedded problem and content of each solution. Only solutions of the
+// queried repository are considered.
+//
+// With a persistence directory, each repository's solutions are kept in
+// one JSON file, loaded on first access and rewritten on every change.
+//
+// # Thread Safety
+//
+// Store is safe for concurrent use.
+type Store struct {
+	embedder         Embedder
+	dir              string
+	maxPerRepository int
+
+	mu    sync.Mutex
+	repos map[string][]*Solution
+}
+
+// StoreOption configures a Store.
+type StoreOption func(*Store)
+
+// WithPersistDir keeps solutions in dir, one JSON file per repository.
+// An empty dir keeps solutions in memory only.
+//
+// Inputs:
+//
+//	dir - The persistence directory. Created on first write.
+//
+// Outputs:
+//
+//	StoreOption - The configuration function.
+func WithPersistDir(dir string) StoreOption {
+	return func(s *Store) {
+		s.dir = dir
+	}
+}
+
+// WithMaxPerRepository sets how many solutions are kept per repository.
+//
+// Inputs:
+//
+//	n - The maximum. Non-positive values use DefaultMaxPerRepository.
+//
+// Outputs:
+//
+//	StoreOption - The configuration function.
+func WithMaxPerRepository(n int) StoreOption {
+	return func(s *Store) {
+		s.maxPerRepository = n
+	}
+}
+
+// NewStore creates a solution store.
+//
+// Inputs:
+//
+//	embedder - Embedding backend. If nil, an explore.LocalEmbedder is used.
+//	opts - Configuration options.
+//
+// Outputs:
+//
+//	*Store - The configured store.
+func NewStore(embedder Embedder, opts ...StoreOption) *Store {
+	if embedder == nil {
+		embedder = explore.NewLocalEmbedder(0)
+	}
+	s := &Store{
+		embedder: embedder,
+		repos:    make(map[string][]*Solution),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxPerRepository <= 0 {
+		s.maxPerRepository = DefaultMaxPerRepository
+	}
+	return s
+}
+
+// Record stores a solution.
+//
+// Description:
+//
+//	Validates and embeds the solution, truncating Content to
+//	MaxContentBytes. ID and CreatedAt are assigned if empty. Recording the
+//	same kind and content again for a repository refreshes the existing
+//	solution instead of adding a duplicate. When the repository is over
+//	its limit, the least recently used solutions are evicted.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	sol - The solution to record. Modified in place.
+//
+// Outputs:
+//
+//	error - Non-nil if validation, embedding, or persistence fails.
+func (s *Store) Record(ctx context.Context, sol *Solution) error {
+	if sol == nil {
+		return ErrEmptyContent
+	}
+	sol.Repository = repositoryKey(sol.Repository)
+	sol.Content = truncateUTF8(sol.Content, MaxContentBytes)
+	if err := sol.Validate(); err != nil {
+		return err
+	}
+
+	vectors, err := s.embedder.BatchEmbed(ctx, []string{sol.embeddingText()})
+	if err != nil {
+		return fmt.Errorf("embed solution: %w", err)
+	}
+	if len(vectors) != 1 {
+		return fmt.Errorf("embed solution: got %d vectors, want 1", len(vectors))
+	}
+	sol.Embedding = vectors[0]
+
+	now := time.Now().UnixMilli()
+	if sol.ID == "" {
+		sol.ID = uuid.NewString()
+	}
+	if sol.CreatedAt == 0 {
+		sol.CreatedAt = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.loadLocked(sol.Repository)
+	if err != nil {
+		return err
+	}
+
+	for i, old := range existing {
+		if old.Kind == sol.Kind && old.Content == sol.Content {
+			sol.ID = old.ID
+			sol.UseCount = old.UseCount
+			sol.LastUsed = old.LastUsed
+			existing[i] = sol
+			return s.saveLocked(sol.Repository)
+		}
+	}
+
+	existing = append(existing, sol)
+	if len(existing) > s.maxPerRepository {
+		sort.SliceStable(existing, func(i, j int) bool {
+			return recency(existing[i]) > recency(existing[j])
+		})
+		existing = existing[:s.maxPerRepository]
+	}
+	s.repos[sol.Repository] = existing
+	return s.saveLocked(sol.Repository)
+}
+
+// Retrieve returns the solutions of a repository most similar to query.
+//
+// Description:
+//
+//	Embeds the query and ranks the repository's solutions by cosine
+//	similarity. Only positive scores at or above opts.MinScore are
+//	returned. Solutions embedded with a different vector size (the
+//	embedder changed) are re-embedded first. Retrieved solutions have
+//	their UseCount and LastUsed updated.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	repository - The project root.
+//	query - The new problem.
+//	opts - Retrieval options.
+//
+// Outputs:
+//
+//	[]Match - Matches, best first. Empty if nothing is similar enough.
+//	error - Non-nil if embedding or persistence fails.
+func (s *Store) Retrieve(ctx context.Context, repository, query string, opts RetrieveOptions) ([]Match, error) {
+	repository = repositoryKey(repository)
+	if repository == "" {
+		return nil, ErrEmptyRepository
+	}
+	if query == "" {
+		return nil, ErrEmptyProblem
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultRetrieveLimit
+	}
+
+	vectors, err := s.embedder.BatchEmbed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embed query: got %d vectors, want 1", len(vectors))
+	}
+	queryVec := vectors[0]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.loadLocked(repository)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	changed, err := s.reembedLocked(ctx, existing, len(queryVec))
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[Kind]bool, len(opts.Kinds))
+	for _, k := range opts.Kinds {
+		kinds[k] = true
+	}
+
+	var matches []Match
+	for _, sol := range existing {
+		if len(kinds) > 0 && !kinds[sol.Kind] {
+			continue
+		}
+		score := cosineSimilarity(queryVec, sol.Embedding)
+		if score <= 0 || score < opts.MinScore {
+			continue
+		}
+		matches = append(matches, Match{Solution: sol, Score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	now := time.Now().UnixMilli()
+	for i, m := range matches {
+		m.Solution.UseCount++
+		m.Solution.LastUsed = now
+		// Return copies so callers never race with later updates.
+		copied := *m.Solution
+		matches[i].Solution = &copied
+	}
+
+	if len(matches) > 0 || changed {
+		if err := s.saveLocked(repository); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// Count returns the number of solutions stored for a repository.
+//
+// Inputs:
+//
+//	repository - The project root.
+//
+// Outputs:
+//
+//	int - The number of solutions.
+//	error - Non-nil if the repository's file cannot be loaded.
+func (s *Store) Count(repository string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.loadLocked(repositoryKey(repository))
+	if err != nil {
+		return 0, err
+	}
+	return len(existing), nil
+}
+
+// reembedLocked re-embeds solutions whose vector size differs from dim.
+// Reports whether any solution changed. Caller must hold s.mu.
+func (s *Store) reembedLocked(ctx context.Context, sols []*Solution, dim int) (bool, error) {
+	var stale []*Solution
+	var texts []string
+	for _, sol := range sols {
+		if len(sol.Embedding) != dim {
+			stale = append(stale, sol)
+			texts = append(texts, sol.embeddingText())
+		}
+	}
+	if len(stale) == 0 {
+		return false, nil
+	}
+
+	vectors, err := s.embedder.BatchEmbed(ctx, texts)
+	if err != nil {
+		return false, fmt.Errorf("re-embed solutions: %w", err)
+	}
+	if len(vectors) != len(stale) {
+		return false, fmt.Errorf("re-embed solutions: got %d vectors, want %d", len(vectors), len(stale))
+	}
+	for i, sol := range stale {
+		sol.Embedding = vectors[i]
+	}
+	return true, nil
+}
+
+// loadLocked returns the solutions of a repository, reading its file on
+// first access. Caller must hold s.mu.
+func (s *Store) loadLocked(repository string) ([]*Solution, error) {
+	if sols, ok := s.repos[repository]; ok {
+		return sols, nil
+	}
+	if s.dir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(s.path(repository))
+	if errors.Is(err, os.ErrNotExist) {
+		s.repos[repository] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read solutions: %w", err)
+	}
+
+	var sols []*Solution
+	if err := json.Unmarshal(data, &sols); err != nil {
+		return nil, fmt.Errorf("decode solutions: %w", err)
+	}
+	s.repos[repository] = sols
+	return sols, nil
+}
+
+// saveLocked writes the solutions of a repository to its file, if the
+// store is persistent. Caller must hold s.mu.
+func (s *Store) saveLocked(repository string) error {
+	if s.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create solutions dir: %w", err)
+	}
+
+	data, err := json.Marshal(s.repos[repository])
+	if err != nil {
+		return fmt.Errorf("encode solutions: %w", err)
+	}
+
+	// Write to a temp file and rename so a crash never leaves a torn file.
+	path := s.path(repository)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write solutions: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write solutions: %w", err)
+	}
+	return nil
+}
+
+// path returns the file holding a repository's solutions.
+func (s *Store) path(repository string) string {
+	sum := sha256.Sum256([]byte(repository))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// repositoryKey normalizes a project root.
+func repositoryKey(repository string) string {
+	if repository == "" {
+		return ""
+	}
+	return filepath.Clean(repository)
+}
+
+// recency returns when a solution was last recorded or used.
+func recency(sol *Solution) int64 {
+	if sol.LastUsed > sol.CreatedAt {
+		return sol.LastUsed
+	}
+	return sol.CreatedAt
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 if
+// their sizes differ or either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// truncateUTF8 truncates s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
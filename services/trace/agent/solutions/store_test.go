@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package solutions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStore_RecordAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(nil)
+
+	for _, sol := range []*Solution{
+		{Repository: "/repo/a", Kind: KindFix, Problem: "nil pointer panic in ParseConfig when file is empty", Content: "Files changed: config.go\nAnswer: guard empty input in ParseConfig"},
+		{Repository: "/repo/a", Kind: KindPlan, Problem: "who calls the HTTP router setup", Content: "Steps: find_callers → read_file"},
+		{Repository: "/repo/b", Kind: KindFix, Problem: "nil pointer panic in ParseConfig when file is empty", Content: "Files changed: other.go"},
+	} {
+		if err := store.Record(ctx, sol); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if sol.ID == "" || sol.CreatedAt == 0 || len(sol.Embedding) == 0 {
+			t.Fatalf("Record() did not fill ID, CreatedAt and Embedding: %+v", sol)
+		}
+	}
+
+	matches, err := store.Retrieve(ctx, "/repo/a/", "ParseConfig panics with a nil pointer on empty file", RetrieveOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Retrieve() returned %d matches, want 1", len(matches))
+	}
+	got := matches[0].Solution
+	if got.Repository != "/repo/a" || got.Kind != KindFix {
+		t.Errorf("best match = %+v, want the fix of /repo/a", got)
+	}
+	if got.UseCount != 1 || got.LastUsed == 0 {
+		t.Errorf("usage not updated: use_count=%d last_used=%d", got.UseCount, got.LastUsed)
+	}
+
+	t.Run("kinds filter", func(t *testing.T) {
+		matches, err := store.Retrieve(ctx, "/repo/a", "ParseConfig nil pointer", RetrieveOptions{Kinds: []Kind{KindPlan}})
+		if err != nil {
+			t.Fatalf("Retrieve() error = %v", err)
+		}
+		for _, m := range matches {
+			if m.Solution.Kind != KindPlan {
+				t.Errorf("got kind %s, want only plans", m.Solution.Kind)
+			}
+		}
+	})
+
+	t.Run("min score", func(t *testing.T) {
+		matches, err := store.Retrieve(ctx, "/repo/a", "ParseConfig nil pointer", RetrieveOptions{MinScore: 0.99})
+		if err != nil {
+			t.Fatalf("Retrieve() error = %v", err)
+		}
+		if len(matches) != 0 {
+			t.Errorf("got %d matches above 0.99, want 0", len(matches))
+		}
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		matches, err := store.Retrieve(ctx, "/repo/c", "ParseConfig nil pointer", RetrieveOptions{})
+		if err != nil || len(matches) != 0 {
+			t.Errorf("Retrieve() = %v, %v, want no matches", matches, err)
+		}
+	})
+}
+
+func TestStore_Record_Validation(t *testing.T) {
+	store := NewStore(nil)
+	tests := []struct {
+		name string
+		sol  *Solution
+		want error
+	}{
+		{"no repository", &Solution{Kind: KindFix, Problem: "p", Content: "c"}, ErrEmptyRepository},
+		{"no problem", &Solution{Repository: "/r", Kind: KindFix, Content: "c"}, ErrEmptyProblem},
+		{"no content", &Solution{Repository: "/r", Kind: KindFix, Problem: "p"}, ErrEmptyContent},
+		{"bad kind", &Solution{Repository: "/r", Kind: "guess", Problem: "p", Content: "c"}, ErrInvalidKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Record(context.Background(), tt.sol); !errors.Is(err, tt.want) {
+				t.Errorf("Record() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Record_DeduplicatesAndEvicts(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(nil, WithMaxPerRepository(2))
+
+	record := func(problem, content string) *Solution {
+		sol := &Solution{Repository: "/r", Kind: KindClause, Problem: problem, Content: content}
+		if err := store.Record(ctx, sol); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		return sol
+	}
+
+	first := record("query one", "Avoid: (¬tool:grep)")
+	again := record("query two", "Avoid: (¬tool:grep)")
+	if again.ID != first.ID {
+		t.Errorf("same content got a new ID %s, want %s", again.ID, first.ID)
+	}
+	if n, _ := store.Count("/r"); n != 1 {
+		t.Fatalf("Count() = %d, want 1 after duplicate", n)
+	}
+
+	record("query three", "Avoid: (¬tool:find_callers)")
+	record("query four", "Avoid: (¬tool:read_file)")
+	if n, _ := store.Count("/r"); n != 2 {
+		t.Errorf("Count() = %d, want 2 after eviction", n)
+	}
+}
+
+func TestStore_Record_TruncatesContent(t *testing.T) {
+	store := NewStore(nil)
+	sol := &Solution{Repository: "/r", Kind: KindPlan, Problem: "p", Content: strings.Repeat("é", MaxContentBytes)}
+	if err := store.Record(context.Background(), sol); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(sol.Content) > MaxContentBytes || !strings.HasPrefix(sol.Content, "é") || strings.ContainsRune(sol.Content, '�') {
+		t.Errorf("content not truncated on a rune boundary: %d bytes", len(sol.Content))
+	}
+}
+
+func TestStore_Persistence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := NewStore(nil, WithPersistDir(dir))
+	sol := &Solution{Repository: "/repo", Kind: KindFix, Problem: "flaky retry loop in the uploader", Content: "Files changed: upload.go"}
+	if err := store.Record(ctx, sol); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	reopened := NewStore(nil, WithPersistDir(dir))
+	matches, err := reopened.Retrieve(ctx, "/repo", "uploader retry loop is flaky", RetrieveOptions{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Solution.ID != sol.ID {
+		t.Fatalf("Retrieve() after reopen = %+v, want the recorded solution", matches)
+	}
+
+	t.Run("embedder change", func(t *testing.T) {
+		small := NewStore(&fixedDimEmbedder{dim: 8}, WithPersistDir(dir))
+		matches, err := small.Retrieve(ctx, "/repo", "uploader retry", RetrieveOptions{})
+		if err != nil {
+			t.Fatalf("Retrieve() error = %v", err)
+		}
+		if len(matches) != 1 || len(matches[0].Solution.Embedding) != 8 {
+			t.Errorf("solutions were not re-embedded for the new embedder: %+v", matches)
+		}
+	})
+}
+
+func TestFormatForPrompt(t *testing.T) {
+	if got := FormatForPrompt(nil); got != "" {
+		t.Errorf("FormatForPrompt(nil) = %q, want empty", got)
+	}
+
+	got := FormatForPrompt([]Match{{
+		Solution: &Solution{Kind: KindFix, Problem: "panic in ParseConfig\nstack trace...", Content: "Files changed: config.go\nAnswer: guard empty input"},
+		Score:    0.82,
+	}})
+	for _, want := range []string{
+		"## Prior Solutions in This Repository",
+		"1. [fix] (similarity 0.82) Problem: panic in ParseConfig\n",
+		"   Files changed: config.go\n",
+		"   Answer: guard empty input\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatForPrompt() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+// fixedDimEmbedder embeds every text as the same vector of size dim.
+type fixedDimEmbedder struct {
+	dim int
+}
+
+func (e *fixedDimEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vec := make([]float32, e.dim)
+		vec[0] = 1
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package solutions
+
+import (
+	"context"
+	"errors"
+)
+
+// Errors returned by the solutions package.
+var (
+	// ErrEmptyRepository indicates a solution or query has no repository.
+	ErrEmptyRepository = errors.New("repository cannot be empty")
+
+	// ErrEmptyProblem indicates a solution has no problem description.
+	ErrEmptyProblem = errors.New("problem cannot be empty")
+
+	// ErrEmptyContent indicates a solution has no content.
+	ErrEmptyContent = errors.New("solution content cannot be empty")
+
+	// ErrInvalidKind indicates an unknown solution kind.
+	ErrInvalidKind = errors.New("invalid solution kind")
+)
+
+// Kind is the kind of a recorded solution.
+type Kind string
+
+const (
+	// KindFix is a session that changed files to solve the problem.
+	KindFix Kind = "fix"
+
+	// KindPlan is a session that answered the query without changing files.
+	KindPlan Kind = "plan"
+
+	// KindClause is a constraint learned by the CRS during a session.
+	KindClause Kind = "clause"
+)
+
+// validKinds is the set of valid solution kinds.
+var validKinds = map[Kind]bool{
+	KindFix:    true,
+	KindPlan:   true,
+	KindClause: true,
+}
+
+const (
+	// DefaultRetrieveLimit is the default number of solutions retrieved.
+	DefaultRetrieveLimit = 3
+
+	// DefaultMinScore is the default minimum similarity for retrieval.
+	DefaultMinScore = 0.3
+
+	// DefaultMaxPerRepository is the default number of solutions kept per
+	// repository. The least recently used are evicted first.
+	DefaultMaxPerRepository = 500
+
+	// MaxContentBytes bounds the stored content of a solution.
+	MaxContentBytes = 4096
+)
+
+// Embedder converts text into dense vectors.
+//
+// explore.EmbeddingClient and explore.LocalEmbedder implement it.
+type Embedder interface {
+	// BatchEmbed returns one vector per input text, in order.
+	BatchEmbed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Solution is one recorded solution.
+type Solution struct {
+	// ID is the unique solution identifier.
+	ID string `json:"id"`
+
+	// Repository is the project root the solution belongs to.
+	Repository string `json:"repository"`
+
+	// Kind is the kind of solution.
+	Kind Kind `json:"kind"`
+
+	// Problem is the query or bug description that was solved.
+	Problem string `json:"problem"`
+
+	// Content is the solution itself: changed files, tool sequence, or
+	// learned clause, followed by the final answer where there is one.
+	// Truncated to MaxContentBytes.
+	Content string `json:"content"`
+
+	// SessionID is the session that produced the solution.
+	SessionID string `json:"session_id,omitempty"`
+
+	// CreatedAt is when the solution was recorded (Unix milliseconds UTC).
+	CreatedAt int64 `json:"created_at"`
+
+	// LastUsed is when the solution was last retrieved (Unix milliseconds UTC).
+	LastUsed int64 `json:"last_used,omitempty"`
+
+	// UseCount is how many times the solution was retrieved.
+	UseCount int `json:"use_count"`
+
+	// Embedding is the vector of Problem and Content.
+	Embedding []float32 `json:"embedding"`
+}
+
+// Validate checks that the solution has the required fields.
+func (s *Solution) Validate() error {
+	switch {
+	case s.Repository == "":
+		return ErrEmptyRepository
+	case s.Problem == "":
+		return ErrEmptyProblem
+	case s.Content == "":
+		return ErrEmptyContent
+	case !validKinds[s.Kind]:
+		return ErrInvalidKind
+	}
+	return nil
+}
+
+// embeddingText is the text embedded for the solution.
+func (s *Solution) embeddingText() string {
+	return s.Problem + "\n" + s.Content
+}
+
+// Match is a retrieved solution and its similarity to the query.
+type Match struct {
+	// Solution is the retrieved solution.
+	Solution *Solution `json:"solution"`
+
+	// Score is the cosine similarity to the query (-1.0 to 1.0).
+	Score float64 `json:"score"`
+}
+
+// RetrieveOptions configures Store.Retrieve.
+type RetrieveOptions struct {
+	// Limit is the maximum number of matches.
+	// Default: DefaultRetrieveLimit
+	Limit int
+
+	// MinScore drops matches scoring below this value.
+	// Default: 0 (no filtering beyond positive similarity)
+	MinScore float64
+
+	// Kinds restricts matches to these kinds. Empty means all kinds.
+	Kinds []Kind
+}
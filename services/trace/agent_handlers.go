@@ -14,16 +14,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/orchestrator/datatypes"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/review"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/budget"
+	evalreplay "github.com/AleutianAI/AleutianFOSS/services/trace/eval/replay"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
 	"github.com/gin-gonic/gin"
 )
 
@@ -34,6 +42,24 @@ type AgentHandlers struct {
 	loop         agent.AgentLoop
 	svc          *Service
 	modelManager *llm.MultiModelManager
+
+	// replayDir receives replay bundles of failed runs. Empty disables
+	// capture.
+	replayDir string
+
+	// corpusRecorder records algorithm inputs into the eval golden
+	// corpus. Nil disables recording.
+	corpusRecorder *evalreplay.Recorder
+
+	// savedHistoryMu serializes undo and redo of sessions that are no
+	// longer loaded, whose saved edit history is opened per request.
+	savedHistoryMu sync.Mutex
+}
+
+// editHistoryOpener is implemented by agent loops that keep session edit
+// history in a blob store (see agent.WithEditStore).
+type editHistoryOpener interface {
+	OpenEditHistory(sessionID string) (*undo.Stack, error)
 }
 
 // NewAgentHandlers creates handlers for the Code Buddy agent.
@@ -44,6 +70,10 @@ type AgentHandlers struct {
 //	The handlers provide REST endpoints for starting, continuing,
 //	and aborting agent sessions.
 //	Also initializes a shared MultiModelManager for tool routing.
+//	If AGENT_REPLAY_DIR is set, every run is recorded and failed runs are
+//	written there as replay bundles (see package replay). If
+//	EVAL_CORPUS_DIR is set, redacted algorithm inputs are recorded there
+//	as an eval golden corpus (see package eval/replay).
 //
 // Inputs:
 //
@@ -66,11 +96,32 @@ func NewAgentHandlers(loop agent.AgentLoop, svc *Service) *AgentHandlers {
 		ollamaURL = "http://localhost:11434"
 	}
 
+	var corpusRecorder *evalreplay.Recorder
+	if dir := os.Getenv("EVAL_CORPUS_DIR"); dir != "" {
+		rec, err := evalreplay.NewRecorder(evalreplay.DefaultRecorderConfig(dir))
+		if err != nil {
+			slog.Warn("Eval corpus recording disabled", "dir", dir, "error", err)
+		} else {
+			corpusRecorder = rec
+		}
+	}
+
 	return &AgentHandlers{
-		loop:         loop,
-		svc:          svc,
-		modelManager: llm.NewMultiModelManager(ollamaURL),
+		loop:           loop,
+		svc:            svc,
+		modelManager:   llm.NewMultiModelManager(ollamaURL),
+		replayDir:      os.Getenv("AGENT_REPLAY_DIR"),
+		corpusRecorder: corpusRecorder,
+	}
+}
+
+// withCorpusRecorder attaches the eval corpus recorder for a session to
+// ctx, if recording is enabled.
+func (h *AgentHandlers) withCorpusRecorder(ctx context.Context, sessionID string) context.Context {
+	if h.corpusRecorder == nil {
+		return ctx
 	}
+	return algorithms.ContextWithInputRecorder(ctx, h.corpusRecorder.ForSession(sessionID))
 }
 
 // HandleAgentRun handles POST /v1/codebuddy/agent/run.
@@ -90,6 +141,7 @@ func NewAgentHandlers(loop agent.AgentLoop, svc *Service) *AgentHandlers {
 //	200 OK: AgentRunResponse (session completed or needs clarification)
 //	400 Bad Request: Validation error
 //	409 Conflict: Session already in progress
+//	429 Too Many Requests: API key budget exhausted
 //	500 Internal Server Error: Processing error
 //
 // Thread Safety: This method is safe for concurrent use.
@@ -107,33 +159,66 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 		return
 	}
 
-	if req.Query == "" {
-		logger.Warn("Empty query")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Query is required",
-			Code:  "EMPTY_QUERY",
+	resp, err := h.runAgent(c.Request.Context(), &req, logger)
+	if err != nil {
+		if budgetResp, ok := budget.NewBudgetExceededResponse(err); ok {
+			logger.Warn("Agent run over budget", "error", err)
+			c.JSON(http.StatusTooManyRequests, budgetResp)
+			return
+		}
+
+		statusCode, errCode := statusForError(agentRunErrorCodes, err, "AGENT_ERROR")
+		logger.Error("Agent run failed", "error", err)
+		c.JSON(statusCode, ErrorResponse{
+			Error: err.Error(),
+			Code:  errCode,
 		})
 		return
 	}
 
+	c.JSON(http.StatusOK, resp)
+}
+
+// RunAgent starts a new agent session with the given query.
+//
+// Description:
+//
+//	The transport-agnostic core of HandleAgentRun: creates the session,
+//	initializes the tool router if enabled, and runs the agent loop until
+//	completion or clarification. In-process callers use it through
+//	LocalEngine.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	req - The run request. Must not be nil.
+//
+// Outputs:
+//
+//	*AgentRunResponse - The session result.
+//	error - ErrInvalidAgentConfig, agent.ErrEmptyQuery, agent.ErrInvalidSession,
+//	        agent.ErrSessionInProgress, budget.ErrBudgetExceeded, or another
+//	        agent error.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) RunAgent(ctx context.Context, req *AgentRunRequest) (*AgentRunResponse, error) {
+	return h.runAgent(ctx, req, slog.With("component", "agent"))
+}
+
+// runAgent implements RunAgent with the caller's logger.
+func (h *AgentHandlers) runAgent(ctx context.Context, req *AgentRunRequest, logger *slog.Logger) (*AgentRunResponse, error) {
+	if req.Query == "" {
+		return nil, agent.ErrEmptyQuery
+	}
+
 	logger.Info("Starting agent session",
 		"project_root", req.ProjectRoot,
 		"query_len", len(req.Query))
 
-	// Create session with optional config
-	var sessionConfig *agent.SessionConfig
-	if req.Config != nil {
-		sessionConfig = req.Config
-	}
-
-	session, err := agent.NewSession(req.ProjectRoot, sessionConfig)
+	session, err := agent.NewSession(req.ProjectRoot, req.Config)
 	if err != nil {
 		logger.Error("Failed to create session", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INVALID_CONFIG",
-		})
-		return
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAgentConfig, err)
 	}
 
 	logger.Info("Session created",
@@ -151,7 +236,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 			"ollama_endpoint", h.getOllamaEndpoint(),
 			"has_model_manager", h.modelManager != nil)
 
-		if preflightErr := h.verifyRouterModelAvailable(c.Request.Context(), session.Config.ToolRouterModel, logger); preflightErr != nil {
+		if preflightErr := h.verifyRouterModelAvailable(ctx, session.Config.ToolRouterModel, logger); preflightErr != nil {
 			logger.Error("⚠️  PRE-FLIGHT CHECK FAILED",
 				"session_id", session.ID,
 				"router_model", session.Config.ToolRouterModel,
@@ -171,7 +256,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 			"has_model_manager", h.modelManager != nil,
 			"ollama_endpoint", h.getOllamaEndpoint())
 
-		if err := h.initializeToolRouter(c.Request.Context(), session, logger); err != nil {
+		if err := h.initializeToolRouter(ctx, session, logger); err != nil {
 			// Log warning but don't fail - tool routing is optional
 			logger.Warn("Failed to initialize tool router, continuing without it",
 				"session_id", session.ID,
@@ -200,29 +285,24 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 			"session_id", session.ID)
 	}
 
+	// Record the run for replay; only failed runs are kept.
+	var recording *replay.Recording
+	if h.replayDir != "" {
+		recording = replay.Start(session.ID, replay.Request{
+			ProjectRoot: req.ProjectRoot,
+			Query:       req.Query,
+			Config:      session.Config,
+		})
+		defer recording.Stop()
+	}
+
 	// Run the agent loop
-	result, err := h.loop.Run(c.Request.Context(), session, req.Query)
+	result, err := h.loop.Run(h.withCorpusRecorder(ctx, session.ID), session, req.Query)
+	if recording != nil && (err != nil || result.State == agent.StateError) {
+		h.saveReplay(recording, result, err, logger)
+	}
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		errCode := "AGENT_ERROR"
-
-		if errors.Is(err, agent.ErrInvalidSession) {
-			statusCode = http.StatusBadRequest
-			errCode = "INVALID_SESSION"
-		} else if errors.Is(err, agent.ErrEmptyQuery) {
-			statusCode = http.StatusBadRequest
-			errCode = "EMPTY_QUERY"
-		} else if errors.Is(err, agent.ErrSessionInProgress) {
-			statusCode = http.StatusConflict
-			errCode = "SESSION_IN_PROGRESS"
-		}
-
-		logger.Error("Agent run failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
-		return
+		return nil, err
 	}
 
 	logger.Info("Agent session completed",
@@ -230,7 +310,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 		"state", result.State,
 		"steps_taken", result.StepsTaken)
 
-	c.JSON(http.StatusOK, AgentRunResponse{
+	return &AgentRunResponse{
 		SessionID:    session.ID,
 		State:        string(result.State),
 		StepsTaken:   result.StepsTaken,
@@ -239,7 +319,187 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 		NeedsClarify: result.NeedsClarify,
 		Error:        agentErrorToString(result.Error),
 		DegradedMode: session.GetMetrics().DegradedMode,
-	})
+	}, nil
+}
+
+// saveReplay writes the replay bundle of a failed run. Failures to write
+// are logged; they never fail the run.
+func (h *AgentHandlers) saveReplay(recording *replay.Recording, result *agent.RunResult, runErr error, logger *slog.Logger) {
+	outcome := replay.Outcome{State: string(agent.StateError)}
+	if result != nil {
+		outcome.State = string(result.State)
+		outcome.StepsTaken = result.StepsTaken
+		outcome.Error = agentErrorToString(result.Error)
+	}
+	if runErr != nil {
+		outcome.Error = runErr.Error()
+	}
+
+	bundle := recording.Finish(outcome)
+	path, err := replay.WriteBundle(h.replayDir, bundle)
+	if err != nil {
+		logger.Warn("Failed to save replay bundle", "session_id", bundle.SessionID, "error", err)
+		return
+	}
+	logger.Info("Saved replay bundle of failed run",
+		"session_id", bundle.SessionID,
+		"path", path,
+		"llm_exchanges", len(bundle.Exchanges),
+		"tool_calls", len(bundle.ToolCalls))
+}
+
+// HandleAgentReview handles POST /v1/trace/agent/review.
+//
+// Description:
+//
+//	Runs the agent in patch review mode. The diff is mapped to the symbols
+//	it touches and their blast radius is computed from the code graph,
+//	which yields deterministic findings. Unless GraphOnly is set, the diff
+//	and graph facts are then given to the agent loop, whose JSON findings
+//	are merged in. If the agent fails, graph findings are still returned
+//	with AgentError set.
+//
+// Request Body:
+//
+//	AgentReviewRequest
+//
+// Response:
+//
+//	200 OK: AgentReviewResponse
+//	400 Bad Request: Invalid request, unparsable diff, or bad project root
+//	500 Internal Server Error: Graph initialization failed
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleAgentReview(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleAgentReview")
+	ctx := c.Request.Context()
+
+	var req AgentReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	changes, err := review.ParseDiff(req.Diff)
+	if err != nil {
+		logger.Warn("Invalid diff", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_DIFF",
+		})
+		return
+	}
+
+	// Keep test files in the graph so untested changes can be detected.
+	initResp, err := h.svc.Init(ctx, req.ProjectRoot, req.Languages, []string{"vendor/*"})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrRelativePath) || errors.Is(err, ErrPathTraversal) {
+			statusCode = http.StatusBadRequest
+		}
+		logger.Error("Graph initialization failed", "error", err)
+		c.JSON(statusCode, ErrorResponse{
+			Error: err.Error(),
+			Code:  "INIT_FAILED",
+		})
+		return
+	}
+	cached, err := h.svc.GetGraph(initResp.GraphID)
+	if err != nil {
+		logger.Error("Graph not available after init", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: err.Error(),
+			Code:  "GRAPH_NOT_FOUND",
+		})
+		return
+	}
+
+	result, err := review.NewReviewer(cached.Graph, cached.Index).Analyze(ctx, changes)
+	if err != nil {
+		logger.Error("Graph review failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: err.Error(),
+			Code:  "REVIEW_FAILED",
+		})
+		return
+	}
+
+	resp := AgentReviewResponse{
+		RiskLevel:    result.RiskLevel,
+		FilesChanged: result.FilesChanged,
+	}
+
+	if !req.GraphOnly {
+		h.runReviewAgent(ctx, &req, result, &resp, logger)
+	}
+
+	resp.ChangedSymbols = result.ChangedSymbols
+	resp.Findings = result.Findings
+	resp.Summary = result.Summary
+	if resp.ChangedSymbols == nil {
+		resp.ChangedSymbols = []review.ChangedSymbol{}
+	}
+	if resp.Findings == nil {
+		resp.Findings = []review.Finding{}
+	}
+
+	logger.Info("Patch review completed",
+		"files", resp.FilesChanged,
+		"changed_symbols", len(resp.ChangedSymbols),
+		"findings", len(resp.Findings),
+		"risk", resp.RiskLevel,
+		"agent_error", resp.AgentError)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// runReviewAgent runs the agent loop on the review prompt and merges its
+// findings into result. Failures are recorded in resp.AgentError.
+func (h *AgentHandlers) runReviewAgent(ctx context.Context, req *AgentReviewRequest, result *review.Result, resp *AgentReviewResponse, logger *slog.Logger) {
+	session, err := agent.NewSession(req.ProjectRoot, req.Config)
+	if err != nil {
+		resp.AgentError = err.Error()
+		return
+	}
+	resp.SessionID = session.ID
+
+	if session.Config.ToolRouterEnabled {
+		if err := h.initializeToolRouter(ctx, session, logger); err != nil {
+			logger.Warn("Failed to initialize tool router, continuing without it",
+				"session_id", session.ID, "error", err)
+		}
+	}
+
+	runResult, err := h.loop.Run(h.withCorpusRecorder(ctx, session.ID), session, review.BuildPrompt(req.Diff, result))
+	if err != nil {
+		logger.Warn("Review agent failed", "session_id", session.ID, "error", err)
+		resp.AgentError = err.Error()
+		return
+	}
+	resp.State = string(runResult.State)
+	resp.StepsTaken = runResult.StepsTaken
+	resp.TokensUsed = runResult.TokensUsed
+
+	if runResult.Response == "" {
+		resp.AgentError = agentErrorToString(runResult.Error)
+		if resp.AgentError == "" {
+			resp.AgentError = "agent returned no response (state " + resp.State + ")"
+		}
+		return
+	}
+
+	findings, err := review.ParseFindings(runResult.Response)
+	if err != nil {
+		logger.Warn("Review agent response had no findings block", "session_id", session.ID)
+		resp.AgentError = err.Error()
+		return
+	}
+	review.Merge(result, findings)
 }
 
 // HandleAgentContinue handles POST /v1/codebuddy/agent/continue.
@@ -258,6 +518,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 //	200 OK: AgentRunResponse
 //	400 Bad Request: Session not in CLARIFY state
 //	404 Not Found: Session not found
+//	429 Too Many Requests: API key budget exhausted
 //	500 Internal Server Error: Processing error
 //
 // Thread Safety: This method is safe for concurrent use.
@@ -288,8 +549,15 @@ func (h *AgentHandlers) HandleAgentContinue(c *gin.Context) {
 		"session_id", req.SessionID,
 		"clarification_len", len(req.Clarification))
 
-	result, err := h.loop.Continue(c.Request.Context(), req.SessionID, req.Clarification)
+	ctx := h.withCorpusRecorder(c.Request.Context(), req.SessionID)
+	result, err := h.loop.Continue(ctx, req.SessionID, req.Clarification)
 	if err != nil {
+		if resp, ok := budget.NewBudgetExceededResponse(err); ok {
+			logger.Warn("Agent continue over budget", "error", err)
+			c.JSON(http.StatusTooManyRequests, resp)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		errCode := "AGENT_ERROR"
 
@@ -613,6 +881,146 @@ func (h *AgentHandlers) HandleGetCRSExport(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// HandleAgentUndo handles POST /v1/trace/agent/:id/undo.
+//
+// Description:
+//
+//	Reverts the session's last Count file edits, newest first. An edit is
+//	only reverted if its file still holds what the session wrote, so
+//	changes made since by anyone else are never overwritten. Processing
+//	stops at the first such file.
+//
+//	With an edit store (agent.WithEditStore), sessions no longer loaded,
+//	e.g. after a restart, are served from their saved edit history.
+//
+// Path Parameters:
+//
+//	id: Session ID (required)
+//
+// Request Body:
+//
+//	AgentUndoRequest (optional)
+//
+// Response:
+//
+//	200 OK: AgentUndoResponse
+//	400 Bad Request: Invalid request or count
+//	404 Not Found: Session not found and no saved edit history
+//	409 Conflict: Nothing to undo, session running, or a file changed
+//	  since the edit (AgentUndoResponse with the edits reverted before it)
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleAgentUndo(c *gin.Context) {
+	h.handleEditHistory(c, "HandleAgentUndo", (*undo.Stack).Undo)
+}
+
+// HandleAgentRedo handles POST /v1/trace/agent/:id/redo.
+//
+// Description:
+//
+//	Re-applies the session's last Count undone edits. Same rules and
+//	responses as HandleAgentUndo.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleAgentRedo(c *gin.Context) {
+	h.handleEditHistory(c, "HandleAgentRedo", (*undo.Stack).Redo)
+}
+
+// handleEditHistory runs apply on the session's edit history.
+func (h *AgentHandlers) handleEditHistory(c *gin.Context, handler string, apply func(*undo.Stack, int) ([]undo.Operation, error)) {
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", handler)
+
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		logger.Warn("Missing session id")
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "session id is required",
+			Code:  "MISSING_PARAMETER",
+		})
+		return
+	}
+
+	req := AgentUndoRequest{Count: 1}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	var history *undo.Stack
+	session, err := h.loop.GetSession(sessionID)
+	if opener, ok := h.loop.(editHistoryOpener); ok && errors.Is(err, agent.ErrSessionNotFound) {
+		// The session is gone, e.g. after a restart, but its edits may
+		// still be saved. Opened per request, so serialize the requests.
+		h.savedHistoryMu.Lock()
+		defer h.savedHistoryMu.Unlock()
+		history, err = opener.OpenEditHistory(sessionID)
+	}
+	if err != nil {
+		if errors.Is(err, agent.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: err.Error(),
+				Code:  "SESSION_NOT_FOUND",
+			})
+			return
+		}
+
+		logger.Error("Get session failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: err.Error(),
+			Code:  "GET_SESSION_FAILED",
+		})
+		return
+	}
+
+	if session != nil {
+		// Do not race the agent's own writes.
+		if !session.TryAcquire() {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error: agent.ErrSessionInProgress.Error(),
+				Code:  "SESSION_IN_PROGRESS",
+			})
+			return
+		}
+		defer session.Release()
+		history = session.EditHistory()
+	}
+
+	ops, err := apply(history, req.Count)
+	resp := AgentUndoResponse{SessionID: sessionID, Operations: ops}
+	resp.UndoAvailable, resp.RedoAvailable = history.Len()
+	if resp.Operations == nil {
+		resp.Operations = []undo.Operation{}
+	}
+
+	if err != nil {
+		statusCode, code := statusForError(agentUndoErrorCodes, err, "UNDO_FAILED")
+		logger.Warn("Edit history operation failed",
+			"session_id", sessionID,
+			"processed", len(ops),
+			"error", err)
+		if len(ops) == 0 {
+			c.JSON(statusCode, ErrorResponse{Error: err.Error(), Code: code})
+			return
+		}
+		resp.Error = err.Error()
+		c.JSON(statusCode, resp)
+		return
+	}
+
+	logger.Info("Edit history operation completed",
+		"session_id", sessionID,
+		"processed", len(ops),
+		"undo_available", resp.UndoAvailable,
+		"redo_available", resp.RedoAvailable)
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // HandleDebugCRS handles GET /v1/codebuddy/agent/debug/crs.
 //
 // Description:
@@ -16,10 +16,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
 	"github.com/gin-gonic/gin"
 )
 
@@ -617,3 +622,261 @@ func TestAgentHandlers_HandleGetCRSExport_MissingSessionID(t *testing.T) {
 		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
 	}
 }
+
+func TestAgentHandlers_HandleAgentUndo(t *testing.T) {
+	session, _ := agent.NewSession("/test/project", nil)
+	path := filepath.Join(t.TempDir(), "main.go")
+	for _, content := range []string{"v1", "v2"} {
+		before, readErr := os.ReadFile(path)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := session.EditHistory().RecordEdit(path, before, readErr == nil, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockLoop := &MockAgentLoop{
+		getSessionFunc: func(sessionID string) (*agent.Session, error) {
+			if sessionID != session.ID {
+				return nil, agent.ErrSessionNotFound
+			}
+			return session, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	post := func(url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Default count is 1.
+	w := post("/v1/trace/agent/"+session.ID+"/undo", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp AgentUndoResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(resp.Operations) != 1 || resp.UndoAvailable != 1 || resp.RedoAvailable != 1 {
+		t.Errorf("response = %+v, want 1 operation, 1 undo and 1 redo left", resp)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "v1" {
+		t.Errorf("content after undo = %q, want v1", data)
+	}
+
+	w = post("/v1/trace/agent/"+session.ID+"/redo", `{"count": 5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("redo Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "v2" {
+		t.Errorf("content after redo = %q, want v2", data)
+	}
+
+	// The file changes outside the session.
+	if err := os.WriteFile(path, []byte("external"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w := post("/v1/trace/agent/"+session.ID+"/undo", ""); w.Code != http.StatusConflict ||
+		!strings.Contains(w.Body.String(), "EDIT_CONFLICT") {
+		t.Errorf("Status = %d (%s), want %d EDIT_CONFLICT", w.Code, w.Body.String(), http.StatusConflict)
+	}
+
+	if w := post("/v1/trace/agent/"+session.ID+"/redo", ""); w.Code != http.StatusConflict {
+		t.Errorf("redo with empty history Status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := post("/v1/trace/agent/"+session.ID+"/undo", `{"count": -1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("negative count Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := post("/v1/trace/agent/unknown/undo", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown session Status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAgentHandlers_HandleAgentUndo_IdempotencyKey(t *testing.T) {
+	sessions := make(map[string]*agent.Session)
+	paths := make(map[string]string)
+	for range 2 {
+		session, _ := agent.NewSession("/test/project", nil)
+		path := filepath.Join(t.TempDir(), "main.go")
+		for _, content := range []string{"v1", "v2", "v3"} {
+			before, readErr := os.ReadFile(path)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := session.EditHistory().RecordEdit(path, before, readErr == nil, []byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sessions[session.ID] = session
+		paths[session.ID] = path
+	}
+
+	mockLoop := &MockAgentLoop{
+		getSessionFunc: func(sessionID string) (*agent.Session, error) {
+			if session, ok := sessions[sessionID]; ok {
+				return session, nil
+			}
+			return nil, agent.ErrSessionNotFound
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	key := "undo-" + t.Name()
+	post := func(sessionID, action string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/trace/agent/"+sessionID+"/"+action, nil)
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	content := func(sessionID string) string {
+		data, _ := os.ReadFile(paths[sessionID])
+		return string(data)
+	}
+
+	for id := range sessions {
+		first := post(id, "undo")
+		if first.Code != http.StatusOK {
+			t.Fatalf("Status = %d, want %d: %s", first.Code, http.StatusOK, first.Body.String())
+		}
+		retry := post(id, "undo")
+		if retry.Code != http.StatusOK || retry.Header().Get("Idempotent-Replayed") != "true" {
+			t.Errorf("retry = %d (replayed %q), want the replayed 200",
+				retry.Code, retry.Header().Get("Idempotent-Replayed"))
+		}
+		if retry.Body.String() != first.Body.String() {
+			t.Errorf("retry body = %s, want %s", retry.Body, first.Body)
+		}
+		// The same key on another session is its own request.
+		if got := content(id); got != "v2" {
+			t.Errorf("session %s content after retried undo = %q, want v2 (undone once)", id, got)
+		}
+
+		// Redo is a different endpoint, so the key runs it once too.
+		post(id, "redo")
+		post(id, "redo")
+		if got := content(id); got != "v3" {
+			t.Errorf("session %s content after retried redo = %q, want v3", id, got)
+		}
+	}
+}
+
+func TestAgentHandlers_HandleAgentUndo_SavedHistory(t *testing.T) {
+	store, err := cas.Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier process recorded the edit; the session itself is gone.
+	history, err := undo.Open(undo.Config{Store: store, Namespace: "session-1"})
+	if err != nil {
+		t.Fatalf("undo.Open: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.RecordEdit(path, []byte("v1"), true, []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+
+	loop := agent.NewDefaultAgentLoop(agent.WithEditStore(store))
+	r := setupAgentTestRouter(NewAgentHandlers(loop, nil))
+	post := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("/v1/trace/agent/session-1/undo"); w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "v1" {
+		t.Errorf("content after undo = %q, want v1", data)
+	}
+	if w := post("/v1/trace/agent/session-1/redo"); w.Code != http.StatusOK {
+		t.Errorf("redo Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w := post("/v1/trace/agent/unknown/undo"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown session Status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAgentHandlers_HandleAgentReview_InvalidDiff(t *testing.T) {
+	handlers := NewAgentHandlers(&MockAgentLoop{}, nil)
+	r := setupAgentTestRouter(handlers)
+
+	jsonBody, _ := json.Marshal(AgentReviewRequest{ProjectRoot: "/test/project", Diff: "not a diff"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/review", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAgentHandlers_HandleAgentReview_MergesAgentFindings(t *testing.T) {
+	projectRoot := t.TempDir()
+	src := "package calc\n\n// Add adds.\nfunc Add(a, b int) int {\n\treturn a + b\n}\n"
+	if err := os.WriteFile(filepath.Join(projectRoot, "calc.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotQuery string
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			gotQuery = query
+			return &agent.RunResult{
+				State:      agent.StateComplete,
+				StepsTaken: 2,
+				Response: "Reviewed.\n```json\n" +
+					`[{"severity":"error","file":"calc.go","line":5,"message":"Add ignores overflow","suggestion":"check bounds"}]` +
+					"\n```",
+			}, nil
+		},
+	}
+	handlers := NewAgentHandlers(mockLoop, NewService(DefaultServiceConfig()))
+	r := setupAgentTestRouter(handlers)
+
+	diffText := "--- a/calc.go\n+++ b/calc.go\n@@ -4,3 +4,3 @@\n func Add(a, b int) int {\n-\treturn a - b\n+\treturn a + b\n }\n"
+	jsonBody, _ := json.Marshal(AgentReviewRequest{ProjectRoot: projectRoot, Diff: diffText})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/review", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp AgentReviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(resp.ChangedSymbols) != 1 || resp.ChangedSymbols[0].Name != "Add" {
+		t.Errorf("ChangedSymbols = %+v, want [Add]", resp.ChangedSymbols)
+	}
+	if len(resp.Findings) == 0 || resp.Findings[0].Message != "Add ignores overflow" {
+		t.Errorf("expected agent finding first, got %+v", resp.Findings)
+	}
+	if resp.AgentError != "" {
+		t.Errorf("unexpected agent error %q", resp.AgentError)
+	}
+	if !strings.Contains(gotQuery, "Add (function) at calc.go:5") {
+		t.Errorf("review prompt not grounded in graph:\n%s", gotQuery)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/audit"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/budget"
+)
+
+// AgentLoopConfig configures NewAgentLoop.
+type AgentLoopConfig struct {
+	// LLMClient drives the agent phases. If nil, the loop runs without
+	// registered phases (default state transitions only).
+	LLMClient llm.Client
+
+	// EventEmitter receives agent events. If nil, a new emitter is created.
+	EventEmitter *events.Emitter
+
+	// ContextEnabled enables context assembly by the ContextManager.
+	ContextEnabled bool
+
+	// ToolsEnabled enables the exploration tool registry.
+	ToolsEnabled bool
+
+	// AuditLog records tool executions (optional).
+	AuditLog *audit.Log
+
+	// Budgets charges LLM usage against API key budgets (optional).
+	Budgets *budget.Tracker
+
+	// ContextWindow is the model's context size in tokens. When positive,
+	// every request is fitted to it before dispatch (see
+	// llm.ContextWindowClient). Zero disables fitting.
+	ContextWindow int
+
+	// SessionStore shares sessions between replicas (optional).
+	SessionStore *agent.SharedSessionStore
+
+	// Solutions remembers past solutions per repository (optional). PLAN
+	// reuses similar ones; completed sessions add to it.
+	Solutions *solutions.Store
+
+	// EditStore keeps the undo history of session file edits (optional).
+	// Pass the store the CLI BackupManager uses so agent edits can be
+	// undone after a restart. Without it, edit history is kept in memory.
+	EditStore *cas.Store
+}
+
+// NewAgentLoop builds the agent loop over svc.
+//
+// Description:
+//
+//	Registers the agent phases, the service graph provider, the safety
+//	gate and the dependencies factory. The trace server and embedded
+//	(in-process) callers both build their loop here, so sessions behave
+//	the same regardless of transport.
+//
+// Inputs:
+//
+//	svc - The Code Buddy service. Must not be nil.
+//	cfg - Loop configuration.
+//
+// Outputs:
+//
+//	agent.AgentLoop - The configured loop.
+func NewAgentLoop(svc *Service, cfg AgentLoopConfig) agent.AgentLoop {
+	var loopOpts []agent.DefaultLoopOption
+	if cfg.SessionStore != nil {
+		loopOpts = append(loopOpts, agent.WithSessionStore(cfg.SessionStore))
+	}
+	if cfg.EditStore != nil {
+		loopOpts = append(loopOpts, agent.WithEditStore(cfg.EditStore))
+	}
+	if cfg.LLMClient == nil {
+		return agent.NewDefaultAgentLoop(loopOpts...)
+	}
+
+	registry := agent.NewPhaseRegistry()
+	registry.Register(agent.StateInit, NewPhaseAdapter(phases.NewInitPhase()))
+	registry.Register(agent.StatePlan, NewPhaseAdapter(phases.NewPlanPhase()))
+	registry.Register(agent.StateExecute, NewPhaseAdapter(phases.NewExecutePhase()))
+	registry.Register(agent.StateReflect, NewPhaseAdapter(phases.NewReflectPhase()))
+	registry.Register(agent.StateClarify, NewPhaseAdapter(phases.NewClarifyPhase()))
+
+	emitter := cfg.EventEmitter
+	if emitter == nil {
+		emitter = events.NewEmitter()
+	}
+
+	// GR-39: Enable Coordinator and Session Restore for CRS persistence
+	depsFactory := NewDependenciesFactory(
+		WithLLMClient(llm.NewContextWindowClient(
+			llm.NewBudgetedClient(cfg.LLMClient, cfg.Budgets),
+			llm.ContextWindowConfig{Window: cfg.ContextWindow},
+		)),
+		WithGraphProvider(agent.NewServiceGraphProvider(NewServiceAdapter(svc))),
+		WithEventEmitter(emitter),
+		WithSafetyGate(safety.NewDefaultGate(nil)),
+		WithService(svc),
+		WithContextEnabled(cfg.ContextEnabled),
+		WithToolsEnabled(cfg.ToolsEnabled),
+		WithCoordinatorEnabled(true),
+		WithSessionRestoreEnabled(true),
+		WithWorkspacesEnabled(true),
+		WithAuditLog(cfg.AuditLog),
+		WithSolutionMemory(cfg.Solutions),
+	)
+
+	return agent.NewDefaultAgentLoop(append(loopOpts,
+		agent.WithPhaseRegistry(registry),
+		agent.WithDependenciesFactory(depsFactory),
+	)...)
+}
@@ -11,12 +11,12 @@
 package code_buddy
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/patterns"
@@ -35,7 +35,11 @@ func (h *Handlers) HandleGetTools(c *gin.Context) {
 	logger.Info("Fetching tool definitions")
 
 	registry := NewToolRegistry()
-	c.JSON(http.StatusOK, ToolsResponse{Tools: registry.GetTools()})
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	writePage(c, ToolsResponse{Tools: registry.GetTools()}, "tools", page, true)
 }
 
 // =============================================================================
@@ -57,6 +61,10 @@ func (h *Handlers) HandleFindEntryPoints(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -95,10 +103,10 @@ func (h *Handlers) HandleFindEntryPoints(c *gin.Context) {
 	}
 
 	logger.Info("Found entry points", "count", len(result.EntryPoints))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result.entry_points", page, true)
 }
 
 // HandleTraceDataFlow traces data flow from a source through the codebase.
@@ -222,6 +230,10 @@ func (h *Handlers) HandleFindConfigUsage(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -246,10 +258,10 @@ func (h *Handlers) HandleFindConfigUsage(c *gin.Context) {
 	}
 
 	logger.Info("Found config usage", "key", req.ConfigKey, "uses", len(result.UsedIn))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result.used_in", page, true)
 }
 
 // HandleFindSimilarCode finds structurally similar code.
@@ -267,6 +279,10 @@ func (h *Handlers) HandleFindSimilarCode(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -308,10 +324,80 @@ func (h *Handlers) HandleFindSimilarCode(c *gin.Context) {
 	}
 
 	logger.Info("Found similar code", "matches", len(result.Results))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
+	}, "result.results", page, true)
+}
+
+// HandleSemanticSearch finds symbols matching a natural-language query.
+func (h *Handlers) HandleSemanticSearch(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleSemanticSearch")
+
+	var req SemanticSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	ssi, backend, err := h.svc.GetSemanticIndex(c.Request.Context(), req.GraphID)
+	if err != nil {
+		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
+			logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   err.Error(),
+				Code:    "GRAPH_NOT_FOUND",
+				Details: "Ensure /init was called first",
+			})
+			return
+		}
+		logger.Error("Failed to build semantic index", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to build semantic index",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	results, err := ssi.Search(c.Request.Context(), req.Query, &explore.SemanticSearchOptions{
+		Limit:    req.Limit,
+		MinScore: req.MinScore,
+		Kinds:    req.Kinds,
 	})
+	if err != nil {
+		if errors.Is(err, explore.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+		logger.Error("Semantic search failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Semantic search failed",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	logger.Info("Semantic search complete", "results", len(results), "backend", backend)
+	writePage(c, SemanticSearchResponse{
+		Query:          req.Query,
+		Results:        results,
+		Backend:        backend,
+		IndexedSymbols: ssi.Size(),
+		LatencyMs:      time.Since(start).Milliseconds(),
+	}, "results", page, true)
 }
 
 // HandleBuildMinimalContext builds token-efficient context for a symbol.
@@ -481,7 +567,7 @@ func (h *Handlers) HandleAnalyzeChangeImpact(c *gin.Context) {
 		return
 	}
 
-	analyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
+	analyzer := cached.blastRadiusAnalyzer()
 	result, err := analyzer.Analyze(c.Request.Context(), req.SymbolID, nil)
 	if err != nil {
 		logger.Error("Failed to analyze change impact", "error", err)
@@ -499,6 +585,66 @@ func (h *Handlers) HandleAnalyzeChangeImpact(c *gin.Context) {
 	})
 }
 
+// HandleExplainCallPath explains how one symbol reaches another, with the
+// dominating call path, its guarding conditions and file:line evidence.
+func (h *Handlers) HandleExplainCallPath(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleExplainCallPath")
+
+	var req ExplainCallPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   err.Error(),
+			Code:    "GRAPH_NOT_FOUND",
+			Details: "Ensure /init was called first",
+		})
+		return
+	}
+
+	opts := explore.DefaultCallPathOptions()
+	if req.ContextLines != nil {
+		opts.ContextLines = min(max(*req.ContextLines, 0), 10)
+	}
+
+	explainer := explore.NewCallPathExplainer(cached.Graph, cached.Index, nil)
+	result, err := explainer.ExplainCallPath(c.Request.Context(), req.FromID, req.ToID, opts)
+	if err != nil {
+		if errors.Is(err, explore.ErrSymbolNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: err.Error(),
+				Code:  "SYMBOL_NOT_FOUND",
+			})
+			return
+		}
+		logger.Error("Failed to explain call path", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to explain call path",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	logger.Info("Explained call path", "from", req.FromID, "to", req.ToID,
+		"reachable", result.Reachable, "guards", len(result.Guards))
+	c.JSON(http.StatusOK, AgenticResponse{
+		Result:      result,
+		LatencyMs:   time.Since(start).Milliseconds(),
+		Limitations: result.Limitations,
+	})
+}
+
 // =============================================================================
 // REASONING HANDLERS
 // =============================================================================
@@ -720,6 +866,10 @@ func (h *Handlers) HandleDetectSideEffects(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -744,10 +894,10 @@ func (h *Handlers) HandleDetectSideEffects(c *gin.Context) {
 	}
 
 	logger.Info("Detected side effects", "count", len(result.SideEffects))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result.side_effects", page, true)
 }
 
 // HandleSuggestRefactor suggests refactoring improvements.
@@ -827,7 +977,7 @@ func (h *Handlers) HandlePlanMultiFileChange(c *gin.Context) {
 	}
 
 	breakingAnalyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
-	blastAnalyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
+	blastAnalyzer := cached.blastRadiusAnalyzer()
 	validator := reason.NewChangeValidator(cached.Index)
 
 	coordinator := coordinate.NewMultiFileChangeCoordinator(
@@ -921,7 +1071,7 @@ func (h *Handlers) HandleValidatePlan(c *gin.Context) {
 	}
 
 	breakingAnalyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
-	blastAnalyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
+	blastAnalyzer := cached.blastRadiusAnalyzer()
 	validator := reason.NewChangeValidator(cached.Index)
 
 	coordinator := coordinate.NewMultiFileChangeCoordinator(
@@ -995,7 +1145,7 @@ func (h *Handlers) HandlePreviewChanges(c *gin.Context) {
 	}
 
 	breakingAnalyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
-	blastAnalyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
+	blastAnalyzer := cached.blastRadiusAnalyzer()
 	validator := reason.NewChangeValidator(cached.Index)
 
 	coordinator := coordinate.NewMultiFileChangeCoordinator(
@@ -1039,6 +1189,10 @@ func (h *Handlers) HandleDetectPatterns(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1081,10 +1235,10 @@ func (h *Handlers) HandleDetectPatterns(c *gin.Context) {
 	}
 
 	logger.Info("Detected patterns", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
 
 // HandleFindCodeSmells finds code quality issues.
@@ -1102,6 +1256,10 @@ func (h *Handlers) HandleFindCodeSmells(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1137,10 +1295,10 @@ func (h *Handlers) HandleFindCodeSmells(c *gin.Context) {
 	}
 
 	logger.Info("Found code smells", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
 
 // HandleFindDuplication finds duplicate code.
@@ -1158,6 +1316,10 @@ func (h *Handlers) HandleFindDuplication(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1202,10 +1364,10 @@ func (h *Handlers) HandleFindDuplication(c *gin.Context) {
 	}
 
 	logger.Info("Found duplication", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
 
 // HandleFindCircularDeps finds circular dependencies.
@@ -1223,6 +1385,10 @@ func (h *Handlers) HandleFindCircularDeps(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1253,10 +1419,10 @@ func (h *Handlers) HandleFindCircularDeps(c *gin.Context) {
 	}
 
 	logger.Info("Found circular dependencies", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
 
 // HandleExtractConventions extracts coding conventions.
@@ -1274,6 +1440,10 @@ func (h *Handlers) HandleExtractConventions(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1303,10 +1473,10 @@ func (h *Handlers) HandleExtractConventions(c *gin.Context) {
 	}
 
 	logger.Info("Extracted conventions", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
 
 // HandleFindDeadCode finds unreferenced code.
@@ -1324,6 +1494,10 @@ func (h *Handlers) HandleFindDeadCode(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
@@ -1352,8 +1526,8 @@ func (h *Handlers) HandleFindDeadCode(c *gin.Context) {
 	}
 
 	logger.Info("Found dead code", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	writePage(c, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
-	})
+	}, "result", page, true)
 }
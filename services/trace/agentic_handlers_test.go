@@ -41,9 +41,9 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
 
-	// Should have 24 tools
-	if len(resp.Tools) != 24 {
-		t.Errorf("expected 24 tools, got %d", len(resp.Tools))
+	// Should have 25 tools
+	if len(resp.Tools) != 25 {
+		t.Errorf("expected 25 tools, got %d", len(resp.Tools))
 	}
 
 	// Verify tool categories are present
@@ -53,7 +53,7 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 	}
 
 	expectedCategories := map[string]int{
-		"explore":    9,
+		"explore":    10,
 		"reason":     6,
 		"coordinate": 3,
 		"patterns":   6,
@@ -66,6 +66,59 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleGetTools_Pagination(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	type page struct {
+		Tools []map[string]any `json:"tools"`
+		Page  PageInfo         `json:"page"`
+	}
+	get := func(query string) (int, page) {
+		req, _ := http.NewRequest("GET", "/v1/codebuddy/tools?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var p page
+		_ = json.Unmarshal(w.Body.Bytes(), &p)
+		return w.Code, p
+	}
+
+	seen := make(map[string]bool)
+	query := "limit=10&fields=name,category"
+	for pages := 0; ; pages++ {
+		code, p := get(query)
+		if code != http.StatusOK {
+			t.Fatalf("page %d: status %d", pages, code)
+		}
+		if p.Page.Total == nil || *p.Page.Total != 25 {
+			t.Errorf("page %d: total = %v, want 25", pages, p.Page.Total)
+		}
+		for _, tool := range p.Tools {
+			if len(tool) != 2 || tool["name"] == nil || tool["category"] == nil {
+				t.Errorf("fields not selected: %v", tool)
+			}
+			seen[tool["name"].(string)] = true
+		}
+		if p.Page.NextCursor == "" {
+			if pages != 2 {
+				t.Errorf("got %d pages, want 3", pages+1)
+			}
+			break
+		}
+		query = "limit=10&fields=name,category&cursor=" + p.Page.NextCursor
+	}
+	if len(seen) != 25 {
+		t.Errorf("paged through %d distinct tools, want 25", len(seen))
+	}
+
+	if code, _ := get("limit=0"); code != http.StatusBadRequest {
+		t.Errorf("limit=0: status %d, want 400", code)
+	}
+	if code, _ := get("cursor=" + encodeCursor("/v1/codebuddy/callers", 10)); code != http.StatusBadRequest {
+		t.Errorf("foreign cursor: status %d, want 400", code)
+	}
+}
+
 // =============================================================================
 // EXPLORATION HANDLER TESTS
 // =============================================================================
@@ -384,6 +437,7 @@ func TestAgenticRoutes_AllEndpointsExist(t *testing.T) {
 		{"POST", "/v1/codebuddy/explore/summarize_file"},
 		{"POST", "/v1/codebuddy/explore/summarize_package"},
 		{"POST", "/v1/codebuddy/explore/change_impact"},
+		{"POST", "/v1/codebuddy/explore/call_path"},
 		// Reasoning
 		{"POST", "/v1/codebuddy/reason/breaking_changes"},
 		{"POST", "/v1/codebuddy/reason/simulate_change"},
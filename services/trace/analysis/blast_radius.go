@@ -13,12 +13,14 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"math"
 	"path/filepath"
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // BlastRadiusAnalyzer calculates the impact of changing code.
@@ -33,9 +35,11 @@ import (
 //
 // All methods are safe for concurrent use.
 type BlastRadiusAnalyzer struct {
-	graph      *graph.Graph
-	index      *index.SymbolIndex
-	riskConfig RiskConfig
+	graph       *graph.Graph
+	index       *index.SymbolIndex
+	riskConfig  RiskConfig
+	testMapping *TestMapping
+	origin      origin.Filter
 }
 
 // NewBlastRadiusAnalyzer creates an analyzer with the given graph and index.
@@ -64,9 +68,37 @@ func NewBlastRadiusAnalyzer(g *graph.Graph, idx *index.SymbolIndex, riskConfig *
 		graph:      g,
 		index:      idx,
 		riskConfig: config,
+		origin:     origin.DefaultFilter(),
 	}
 }
 
+// SetOriginFilter overrides how generated and vendored callers are counted.
+//
+// # Description
+//
+// Excluded callers are dropped from the result. Down-weighted callers are
+// reported but contribute only their filter weight to the risk level, so
+// a function called from hundreds of generated mocks is not CRITICAL.
+// Default: exclude vendored, down-weight generated.
+//
+// Must be called before the analyzer is shared across goroutines.
+func (a *BlastRadiusAnalyzer) SetOriginFilter(f origin.Filter) {
+	a.origin = f
+}
+
+// SetTestMapping enables call-graph-based test selection.
+//
+// # Description
+//
+// When a mapping is set, TestFiles in the result contains the test files
+// whose tests exercise the target or its direct callers, in addition to
+// the *_test naming-convention matches. Pass nil to disable.
+//
+// Must be called before the analyzer is shared across goroutines.
+func (a *BlastRadiusAnalyzer) SetTestMapping(m *TestMapping) {
+	a.testMapping = m
+}
+
 // Analyze calculates the blast radius for a target symbol.
 //
 // # Description
@@ -176,12 +208,17 @@ func (a *BlastRadiusAnalyzer) findDirectCallers(ctx context.Context, targetID st
 			continue
 		}
 
+		if !a.origin.Include(symbol.Origin()) {
+			continue
+		}
+
 		callers = append(callers, Caller{
 			ID:       symbol.ID,
 			Name:     symbol.Name,
 			FilePath: symbol.FilePath,
 			Line:     symbol.StartLine,
 			Hops:     1,
+			Origin:   symbol.Origin(),
 		})
 	}
 
@@ -231,12 +268,17 @@ func (a *BlastRadiusAnalyzer) findIndirectCallers(ctx context.Context, directCal
 					break
 				}
 
+				if !a.origin.Include(symbol.Origin()) {
+					continue
+				}
+
 				newCaller := Caller{
 					ID:       symbol.ID,
 					Name:     symbol.Name,
 					FilePath: symbol.FilePath,
 					Line:     symbol.StartLine,
 					Hops:     hop,
+					Origin:   symbol.Origin(),
 				}
 				indirectCallers = append(indirectCallers, newCaller)
 				nextLevel = append(nextLevel, newCaller)
@@ -389,6 +431,9 @@ func (a *BlastRadiusAnalyzer) finalizeResult(result *BlastRadius, opts AnalyzeOp
 
 	// Find test files
 	result.TestFiles = a.findTestFiles(result.FilesAffected, opts.TestPatterns)
+	if a.testMapping != nil {
+		result.TestFiles = a.mergeMappedTestFiles(result)
+	}
 
 	// Generate summary
 	result.Summary = a.generateSummary(result)
@@ -404,7 +449,16 @@ func (a *BlastRadiusAnalyzer) calculateRiskLevel(result *BlastRadius) RiskLevel
 		return RiskCritical
 	}
 
-	directCount := len(result.DirectCallers)
+	// Generated callers count only by their filter weight
+	weighted := 0.0
+	for _, c := range result.DirectCallers {
+		if c.Origin == origin.KindSource {
+			weighted++
+			continue
+		}
+		weighted += a.origin.Weight(c.Origin)
+	}
+	directCount := int(math.Round(weighted))
 
 	switch {
 	case directCount >= a.riskConfig.CriticalThreshold:
@@ -444,6 +498,29 @@ func (a *BlastRadiusAnalyzer) findTestFiles(affectedFiles []string, patterns []s
 	return testFiles
 }
 
+// mergeMappedTestFiles adds test files from the test mapping that exercise
+// the target or any of its direct callers.
+func (a *BlastRadiusAnalyzer) mergeMappedTestFiles(result *BlastRadius) []string {
+	ids := make([]string, 0, len(result.DirectCallers)+1)
+	ids = append(ids, result.Target)
+	for _, c := range result.DirectCallers {
+		ids = append(ids, c.ID)
+	}
+
+	files := result.TestFiles
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		seen[f] = true
+	}
+	for _, f := range a.testMapping.TestFilesFor(ids, 0) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
 // generateSummary creates a human-readable summary.
 func (a *BlastRadiusAnalyzer) generateSummary(result *BlastRadius) string {
 	parts := []string{
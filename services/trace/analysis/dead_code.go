@@ -18,6 +18,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // DeadCodeDetector finds unused code in the codebase.
@@ -32,8 +33,9 @@ import (
 //
 // Safe for concurrent use after construction.
 type DeadCodeDetector struct {
-	graph *graph.Graph
-	index *index.SymbolIndex
+	graph  *graph.Graph
+	index  *index.SymbolIndex
+	origin origin.Filter
 }
 
 // DeadCodeResult contains the results of dead code detection.
@@ -96,11 +98,22 @@ const (
 //   - *DeadCodeDetector: Ready-to-use detector.
 func NewDeadCodeDetector(g *graph.Graph, idx *index.SymbolIndex) *DeadCodeDetector {
 	return &DeadCodeDetector{
-		graph: g,
-		index: idx,
+		graph:  g,
+		index:  idx,
+		origin: origin.ExcludeAllFilter(),
 	}
 }
 
+// SetOriginFilter overrides how generated and vendored code is treated.
+//
+// By default both are excluded. With PolicyDownWeight, the symbol's
+// confidence is scaled by the filter weight.
+//
+// Must be called before Detect is used concurrently.
+func (d *DeadCodeDetector) SetOriginFilter(f origin.Filter) {
+	d.origin = f
+}
+
 // Detect finds dead code in the codebase.
 //
 // # Description
@@ -155,6 +168,11 @@ func (d *DeadCodeDetector) Detect(ctx context.Context) (*DeadCodeResult, error)
 			continue
 		}
 
+		// Skip generated/vendored code unless the filter keeps it
+		if !d.origin.Include(sym.Origin()) {
+			continue
+		}
+
 		// Check if symbol has any callers
 		callers := callerIndex[sym.ID]
 		if len(callers) > 0 {
@@ -162,7 +180,7 @@ func (d *DeadCodeDetector) Detect(ctx context.Context) (*DeadCodeResult, error)
 		}
 
 		// Calculate confidence
-		confidence := d.calculateConfidence(sym)
+		confidence := int(float64(d.calculateConfidence(sym)) * d.origin.Weight(sym.Origin()))
 
 		// Skip if confidence too low
 		if confidence < 20 {
@@ -0,0 +1,397 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// DefaultTestMappingDepth is the default number of call hops followed from a
+// test function when building the test-to-code mapping.
+const DefaultTestMappingDepth = 3
+
+// TestLinkSource describes how a test was linked to a production symbol.
+type TestLinkSource string
+
+const (
+	// TestLinkDirectCall means the test calls the symbol directly.
+	TestLinkDirectCall TestLinkSource = "direct_call"
+
+	// TestLinkTransitiveCall means the test reaches the symbol through
+	// one or more intermediate calls.
+	TestLinkTransitiveCall TestLinkSource = "transitive_call"
+
+	// TestLinkNaming means the link was inferred from naming conventions
+	// (e.g., TestParseConfig -> ParseConfig, test_parse_config -> parse_config).
+	TestLinkNaming TestLinkSource = "naming"
+)
+
+// TestLink associates a test function with a production symbol it exercises.
+type TestLink struct {
+	// TestID is the symbol ID of the test function.
+	TestID string `json:"test_id"`
+
+	// TestName is the name of the test function.
+	TestName string `json:"test_name"`
+
+	// TestFile is the file containing the test function.
+	TestFile string `json:"test_file"`
+
+	// SymbolID is the production symbol exercised by the test.
+	SymbolID string `json:"symbol_id"`
+
+	// Source describes how the link was discovered.
+	Source TestLinkSource `json:"source"`
+
+	// Hops is the call distance from the test to the symbol (0 for naming links).
+	Hops int `json:"hops"`
+
+	// Confidence is a 0.0-1.0 score; direct calls and naming matches rank
+	// highest, and confidence decays with call depth.
+	Confidence float64 `json:"confidence"`
+}
+
+// TestMappingOptions configures how a TestMapping is built.
+type TestMappingOptions struct {
+	// MaxDepth is the maximum number of call hops followed from each test.
+	// Default: 3
+	MaxDepth int
+
+	// UseNaming enables naming-heuristic links in addition to call analysis.
+	// Default: true
+	UseNaming bool
+}
+
+// DefaultTestMappingOptions returns sensible defaults.
+func DefaultTestMappingOptions() TestMappingOptions {
+	return TestMappingOptions{
+		MaxDepth:  DefaultTestMappingDepth,
+		UseNaming: true,
+	}
+}
+
+// TestMapping is an index linking test functions to the production symbols
+// they exercise.
+//
+// # Description
+//
+// Built from a frozen graph by walking the call edges out of every test
+// function (static call analysis) and by matching test names against symbol
+// names in the same directory (naming heuristics). Answers "what tests cover
+// this function" for impact-based test selection and TDG.
+//
+// # Thread Safety
+//
+// Immutable after construction; safe for concurrent use.
+type TestMapping struct {
+	bySymbol  map[string][]TestLink // production symbol ID -> links
+	byTest    map[string][]TestLink // test symbol ID -> links
+	testCount int
+}
+
+// BuildTestMapping constructs a TestMapping from a frozen graph.
+//
+// # Description
+//
+// Identifies test functions by language convention, follows outgoing call
+// edges up to opts.MaxDepth hops, and optionally adds naming-heuristic links.
+// When a symbol is reachable by several routes from the same test, the
+// highest-confidence link is kept.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation. Must not be nil.
+//   - g: Frozen code graph. Must not be nil.
+//   - opts: Build options (nil uses defaults).
+//
+// # Outputs
+//
+//   - *TestMapping: Ready-to-query mapping.
+//   - error: Non-nil if inputs are invalid or the context was cancelled.
+func BuildTestMapping(ctx context.Context, g *graph.Graph, opts *TestMappingOptions) (*TestMapping, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if g == nil {
+		return nil, errors.New("graph must not be nil")
+	}
+	if !g.IsFrozen() {
+		return nil, ErrGraphNotReady
+	}
+
+	options := DefaultTestMappingOptions()
+	if opts != nil {
+		options = *opts
+	}
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = DefaultTestMappingDepth
+	}
+
+	m := &TestMapping{
+		bySymbol: make(map[string][]TestLink),
+		byTest:   make(map[string][]TestLink),
+	}
+
+	// Collect test functions and index production symbols by directory for
+	// the naming heuristic.
+	tests := make([]*graph.Node, 0)
+	prodByDir := make(map[string][]*ast.Symbol)
+	for _, node := range g.Nodes() {
+		sym := node.Symbol
+		if sym == nil {
+			continue
+		}
+		if IsTestFunction(sym) {
+			tests = append(tests, node)
+			continue
+		}
+		if !IsTestFile(sym.FilePath) && isCallableKind(sym.Kind) {
+			dir := filepath.Dir(sym.FilePath)
+			prodByDir[dir] = append(prodByDir[dir], sym)
+		}
+	}
+
+	for _, test := range tests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		best := make(map[string]TestLink)
+		walkTestCalls(g, test, options.MaxDepth, best)
+		if options.UseNaming {
+			for _, sym := range prodByDir[filepath.Dir(test.Symbol.FilePath)] {
+				if matchesTestName(test.Symbol.Name, sym) {
+					addBestLink(best, newTestLink(test.Symbol, sym.ID, TestLinkNaming, 0))
+				}
+			}
+		}
+
+		for _, link := range best {
+			m.bySymbol[link.SymbolID] = append(m.bySymbol[link.SymbolID], link)
+			m.byTest[link.TestID] = append(m.byTest[link.TestID], link)
+		}
+		m.testCount++
+	}
+
+	for id := range m.bySymbol {
+		sortTestLinks(m.bySymbol[id])
+	}
+	for id := range m.byTest {
+		sortTestLinks(m.byTest[id])
+	}
+
+	return m, nil
+}
+
+// walkTestCalls performs a bounded BFS over call edges starting at a test.
+func walkTestCalls(g *graph.Graph, test *graph.Node, maxDepth int, best map[string]TestLink) {
+	visited := map[string]bool{test.ID: true}
+	frontier := []*graph.Node{test}
+
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		next := make([]*graph.Node, 0)
+		for _, node := range frontier {
+			for _, edge := range node.Outgoing {
+				if edge.Type != graph.EdgeTypeCalls || visited[edge.ToID] {
+					continue
+				}
+				visited[edge.ToID] = true
+
+				callee, ok := g.GetNode(edge.ToID)
+				if !ok || callee.Symbol == nil {
+					continue
+				}
+				next = append(next, callee)
+				if IsTestFile(callee.Symbol.FilePath) {
+					// Test helpers are traversed but not reported.
+					continue
+				}
+
+				source := TestLinkDirectCall
+				if depth > 1 {
+					source = TestLinkTransitiveCall
+				}
+				addBestLink(best, newTestLink(test.Symbol, callee.ID, source, depth))
+			}
+		}
+		frontier = next
+	}
+}
+
+// TestsFor returns the tests that exercise the given symbol, best first.
+//
+// # Inputs
+//
+//   - symbolID: Production symbol ID.
+//
+// # Outputs
+//
+//   - []TestLink: Copy of the links; empty if no test covers the symbol.
+func (m *TestMapping) TestsFor(symbolID string) []TestLink {
+	return append([]TestLink(nil), m.bySymbol[symbolID]...)
+}
+
+// SymbolsCoveredBy returns the production symbols exercised by a test.
+func (m *TestMapping) SymbolsCoveredBy(testID string) []TestLink {
+	return append([]TestLink(nil), m.byTest[testID]...)
+}
+
+// TestFilesFor returns the sorted, de-duplicated test files whose tests
+// exercise any of the given symbols with at least minConfidence.
+func (m *TestMapping) TestFilesFor(symbolIDs []string, minConfidence float64) []string {
+
+	seen := make(map[string]bool)
+	files := make([]string, 0)
+	for _, id := range symbolIDs {
+		for _, link := range m.bySymbol[id] {
+			if link.Confidence < minConfidence || seen[link.TestFile] {
+				continue
+			}
+			seen[link.TestFile] = true
+			files = append(files, link.TestFile)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// TestCount returns the number of test functions in the mapping.
+func (m *TestMapping) TestCount() int {
+	return m.testCount
+}
+
+// CoveredSymbolCount returns the number of production symbols with at least
+// one linked test.
+func (m *TestMapping) CoveredSymbolCount() int {
+	return len(m.bySymbol)
+}
+
+// IsTestFile reports whether a path is a test file by language convention.
+func IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(base, "_test.go"):
+		return true
+	case strings.HasSuffix(base, ".py"):
+		return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py")
+	case strings.Contains(base, ".test.") || strings.Contains(base, ".spec."):
+		return true
+	}
+	dir := filepath.ToSlash(filepath.Dir(path))
+	return strings.Contains("/"+dir+"/", "/__tests__/")
+}
+
+// IsTestFunction reports whether a symbol is a test function.
+//
+// Go: Test*/Benchmark*/Fuzz*/Example* in *_test.go.
+// Python: test_* functions or methods in test files.
+// JS/TS: any function in a *.test.* / *.spec.* / __tests__ file.
+func IsTestFunction(sym *ast.Symbol) bool {
+	if sym == nil || !isCallableKind(sym.Kind) || !IsTestFile(sym.FilePath) {
+		return false
+	}
+	switch sym.Language {
+	case "go":
+		for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+			if strings.HasPrefix(sym.Name, prefix) {
+				return true
+			}
+		}
+		return false
+	case "python":
+		return strings.HasPrefix(sym.Name, "test")
+	default:
+		return true
+	}
+}
+
+// matchesTestName applies naming heuristics: TestFoo, TestFoo_bar,
+// TestType_Method, test_foo and test_foo_bar all match Foo / foo.
+func matchesTestName(testName string, sym *ast.Symbol) bool {
+	stem := testName
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "test_", "test"} {
+		if strings.HasPrefix(stem, prefix) {
+			stem = strings.TrimPrefix(stem, prefix)
+			break
+		}
+	}
+	if stem == "" {
+		return false
+	}
+
+	parts := strings.Split(stem, "_")
+	candidates := []string{parts[0], stem}
+	if len(parts) > 1 && sym.Receiver != "" {
+		// TestType_Method -> Method on Type.
+		if strings.EqualFold(parts[0], strings.TrimPrefix(sym.Receiver, "*")) {
+			candidates = append(candidates, parts[1])
+		}
+	}
+	for _, c := range candidates {
+		if c != "" && strings.EqualFold(c, sym.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTestLink creates a link with a confidence derived from its source.
+func newTestLink(test *ast.Symbol, symbolID string, source TestLinkSource, hops int) TestLink {
+	confidence := 0.8
+	switch source {
+	case TestLinkDirectCall:
+		confidence = 1.0
+	case TestLinkTransitiveCall:
+		confidence = 1.0 / float64(hops)
+	}
+	return TestLink{
+		TestID:     test.ID,
+		TestName:   test.Name,
+		TestFile:   test.FilePath,
+		SymbolID:   symbolID,
+		Source:     source,
+		Hops:       hops,
+		Confidence: confidence,
+	}
+}
+
+// addBestLink keeps the highest-confidence link per symbol.
+func addBestLink(best map[string]TestLink, link TestLink) {
+	if existing, ok := best[link.SymbolID]; ok && existing.Confidence >= link.Confidence {
+		return
+	}
+	best[link.SymbolID] = link
+}
+
+// sortTestLinks orders links by confidence (desc), then test ID for stability.
+func sortTestLinks(links []TestLink) {
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Confidence != links[j].Confidence {
+			return links[i].Confidence > links[j].Confidence
+		}
+		if links[i].TestID != links[j].TestID {
+			return links[i].TestID < links[j].TestID
+		}
+		return links[i].SymbolID < links[j].SymbolID
+	})
+}
+
+// isCallableKind reports whether a symbol kind can be called.
+func isCallableKind(kind ast.SymbolKind) bool {
+	return kind == ast.SymbolKindFunction || kind == ast.SymbolKindMethod
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// setupTestMappingGraph builds:
+//
+//	TestHandler (pkg/handler_test.go) -> Handler -> validate -> normalize
+//	TestValidate (pkg/validate_test.go) -> newFixture (test helper) -> validate
+//	TestNormalize (pkg/normalize_test.go) has no calls (naming only)
+func setupTestMappingGraph(t *testing.T) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+
+	symbols := []*ast.Symbol{
+		createTestSymbol("pkg/handler.go:10:Handler", "Handler", "pkg/handler.go", 10, ast.SymbolKindFunction),
+		createTestSymbol("pkg/validate.go:10:validate", "validate", "pkg/validate.go", 10, ast.SymbolKindFunction),
+		createTestSymbol("pkg/normalize.go:10:normalize", "normalize", "pkg/normalize.go", 10, ast.SymbolKindFunction),
+		createTestSymbol("pkg/handler_test.go:10:TestHandler", "TestHandler", "pkg/handler_test.go", 10, ast.SymbolKindFunction),
+		createTestSymbol("pkg/validate_test.go:10:TestValidate", "TestValidate", "pkg/validate_test.go", 10, ast.SymbolKindFunction),
+		createTestSymbol("pkg/validate_test.go:30:newFixture", "newFixture", "pkg/validate_test.go", 30, ast.SymbolKindFunction),
+		createTestSymbol("pkg/normalize_test.go:10:TestNormalize", "TestNormalize", "pkg/normalize_test.go", 10, ast.SymbolKindFunction),
+	}
+	edges := [][3]string{
+		{"pkg/handler_test.go:10:TestHandler", "pkg/handler.go:10:Handler", "calls"},
+		{"pkg/handler.go:10:Handler", "pkg/validate.go:10:validate", "calls"},
+		{"pkg/validate.go:10:validate", "pkg/normalize.go:10:normalize", "calls"},
+		{"pkg/validate_test.go:10:TestValidate", "pkg/validate_test.go:30:newFixture", "calls"},
+		{"pkg/validate_test.go:30:newFixture", "pkg/validate.go:10:validate", "calls"},
+	}
+
+	return setupTestGraph(symbols, edges)
+}
+
+func TestBuildTestMapping(t *testing.T) {
+	g, _ := setupTestMappingGraph(t)
+
+	m, err := BuildTestMapping(context.Background(), g, nil)
+	if err != nil {
+		t.Fatalf("BuildTestMapping failed: %v", err)
+	}
+
+	if m.TestCount() != 3 {
+		t.Errorf("expected 3 tests, got %d", m.TestCount())
+	}
+
+	t.Run("direct call", func(t *testing.T) {
+		links := m.TestsFor("pkg/handler.go:10:Handler")
+		if len(links) != 1 {
+			t.Fatalf("expected 1 link, got %d", len(links))
+		}
+		if links[0].Source != TestLinkDirectCall || links[0].Hops != 1 {
+			t.Errorf("expected direct call at 1 hop, got %s at %d", links[0].Source, links[0].Hops)
+		}
+	})
+
+	t.Run("transitive through helper is ranked by depth", func(t *testing.T) {
+		links := m.TestsFor("pkg/validate.go:10:validate")
+		if len(links) != 2 {
+			t.Fatalf("expected 2 links, got %d", len(links))
+		}
+		// TestValidate: naming match (0.8) beats transitive 2-hop (0.5).
+		if links[0].TestName != "TestValidate" {
+			t.Errorf("expected TestValidate first, got %s", links[0].TestName)
+		}
+		if links[0].Source != TestLinkNaming {
+			t.Errorf("expected naming link to win, got %s", links[0].Source)
+		}
+		if links[1].TestName != "TestHandler" || links[1].Hops != 2 {
+			t.Errorf("expected TestHandler at 2 hops, got %s at %d", links[1].TestName, links[1].Hops)
+		}
+	})
+
+	t.Run("test helpers are not reported", func(t *testing.T) {
+		if links := m.TestsFor("pkg/validate_test.go:30:newFixture"); len(links) != 0 {
+			t.Errorf("expected no links for test helper, got %d", len(links))
+		}
+	})
+
+	t.Run("naming only", func(t *testing.T) {
+		links := m.SymbolsCoveredBy("pkg/normalize_test.go:10:TestNormalize")
+		if len(links) != 1 || links[0].SymbolID != "pkg/normalize.go:10:normalize" {
+			t.Fatalf("expected naming link to normalize, got %+v", links)
+		}
+	})
+
+	t.Run("test files for symbols", func(t *testing.T) {
+		files := m.TestFilesFor([]string{"pkg/normalize.go:10:normalize"}, 0)
+		want := []string{"pkg/handler_test.go", "pkg/normalize_test.go", "pkg/validate_test.go"}
+		if len(files) != len(want) {
+			t.Fatalf("expected %v, got %v", want, files)
+		}
+		for i := range want {
+			if files[i] != want[i] {
+				t.Errorf("files[%d] = %s, want %s", i, files[i], want[i])
+			}
+		}
+	})
+}
+
+func TestBuildTestMapping_DepthLimit(t *testing.T) {
+	g, _ := setupTestMappingGraph(t)
+
+	m, err := BuildTestMapping(context.Background(), g, &TestMappingOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("BuildTestMapping failed: %v", err)
+	}
+
+	for _, link := range m.TestsFor("pkg/normalize.go:10:normalize") {
+		if link.Source != TestLinkNaming {
+			t.Errorf("expected only naming links at depth 1, got %s from %s", link.Source, link.TestName)
+		}
+	}
+}
+
+func TestBuildTestMapping_Errors(t *testing.T) {
+	if _, err := BuildTestMapping(context.Background(), nil, nil); err == nil {
+		t.Error("expected error for nil graph")
+	}
+
+	g := graph.NewGraph("/test/project")
+	if _, err := BuildTestMapping(context.Background(), g, nil); err != ErrGraphNotReady {
+		t.Errorf("expected ErrGraphNotReady, got %v", err)
+	}
+}
+
+func TestIsTestFunction(t *testing.T) {
+	tests := []struct {
+		name string
+		sym  *ast.Symbol
+		want bool
+	}{
+		{"go test", &ast.Symbol{Name: "TestFoo", FilePath: "a_test.go", Kind: ast.SymbolKindFunction, Language: "go"}, true},
+		{"go helper", &ast.Symbol{Name: "helper", FilePath: "a_test.go", Kind: ast.SymbolKindFunction, Language: "go"}, false},
+		{"go prod", &ast.Symbol{Name: "TestFoo", FilePath: "a.go", Kind: ast.SymbolKindFunction, Language: "go"}, false},
+		{"python test", &ast.Symbol{Name: "test_foo", FilePath: "tests/test_a.py", Kind: ast.SymbolKindFunction, Language: "python"}, true},
+		{"ts spec", &ast.Symbol{Name: "anything", FilePath: "src/a.spec.ts", Kind: ast.SymbolKindFunction, Language: "typescript"}, true},
+		{"struct", &ast.Symbol{Name: "TestFoo", FilePath: "a_test.go", Kind: ast.SymbolKindStruct, Language: "go"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTestFunction(tt.sym); got != tt.want {
+				t.Errorf("IsTestFunction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlastRadiusAnalyzer_TestMapping(t *testing.T) {
+	g, idx := setupTestMappingGraph(t)
+	m, err := BuildTestMapping(context.Background(), g, nil)
+	if err != nil {
+		t.Fatalf("BuildTestMapping failed: %v", err)
+	}
+
+	analyzer := NewBlastRadiusAnalyzer(g, idx, nil)
+	analyzer.SetTestMapping(m)
+
+	result, err := analyzer.Analyze(context.Background(), "pkg/normalize.go:10:normalize", nil)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, f := range result.TestFiles {
+		found[f] = true
+	}
+	for _, want := range []string{"pkg/normalize_test.go", "pkg/handler_test.go", "pkg/validate_test.go"} {
+		if !found[want] {
+			t.Errorf("expected %s in TestFiles, got %v", want, result.TestFiles)
+		}
+	}
+}
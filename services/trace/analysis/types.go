@@ -21,7 +21,11 @@
 // All analyzer types are safe for concurrent use.
 package analysis
 
-import "time"
+import (
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
+)
 
 // RiskLevel indicates the risk associated with a change.
 type RiskLevel string
@@ -88,12 +92,14 @@ type BlastRadius struct {
 //   - FilePath: File containing the caller.
 //   - Line: Line number of the call.
 //   - Hops: Distance from target (1 = direct, 2+ = indirect).
+//   - Origin: Whether the caller is in source, generated, or vendored code.
 type Caller struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	FilePath string `json:"file_path"`
-	Line     int    `json:"line"`
-	Hops     int    `json:"hops"`
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	FilePath string      `json:"file_path"`
+	Line     int         `json:"line"`
+	Hops     int         `json:"hops"`
+	Origin   origin.Kind `json:"origin,omitempty"`
 }
 
 // Implementer represents a type that implements an interface.
@@ -0,0 +1,204 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"sync"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const (
+	// arenaChunkSize is the number of values in each arena chunk.
+	arenaChunkSize = 256
+
+	// maxPooledScratch is the largest scratch buffer kept when an arena is
+	// returned to the pool. Larger buffers from unusual files are dropped.
+	maxPooledScratch = 4096
+)
+
+// arena is a bump allocator for values of type T.
+//
+// Description:
+//
+//	Values are handed out from fixed-size chunks. reset rewinds the
+//	arena and zeroes the values it handed out, so the chunks are reused
+//	by the next file instead of becoming garbage. Pointers returned by
+//	alloc are only valid until reset.
+//
+// Thread Safety: Not safe for concurrent use.
+type arena[T any] struct {
+	chunks [][]T
+	chunk  int // index of the chunk being filled
+	used   int // values handed out from chunks[chunk]
+}
+
+// alloc returns a pointer to a zero T.
+func (a *arena[T]) alloc() *T {
+	if len(a.chunks) == 0 || a.used == arenaChunkSize {
+		if len(a.chunks) > 0 {
+			a.chunk++
+		}
+		if a.chunk == len(a.chunks) {
+			a.chunks = append(a.chunks, make([]T, arenaChunkSize))
+		}
+		a.used = 0
+	}
+	v := &a.chunks[a.chunk][a.used]
+	a.used++
+	return v
+}
+
+// reset zeroes the handed-out values and rewinds the arena.
+func (a *arena[T]) reset() {
+	for i := 0; i < a.chunk && i < len(a.chunks); i++ {
+		clear(a.chunks[i])
+	}
+	if a.chunk < len(a.chunks) {
+		clear(a.chunks[a.chunk][:a.used])
+	}
+	a.chunk, a.used = 0, 0
+}
+
+// walkEntry is a node waiting to be visited by an iterative tree walk.
+type walkEntry struct {
+	node  *sitter.Node
+	depth int
+}
+
+// parseArena holds the short-lived structures of one file's parse.
+//
+// Description:
+//
+//	A parse allocates many values that die with the file: call sites
+//	that are copied into their symbol, traversal stacks, and node type
+//	strings. parseArena bump-allocates or reuses them, and is returned to
+//	a pool once the file is done, which takes that garbage off the GC.
+//
+//	A nil *parseArena is valid and allocates on the heap, so extraction
+//	code is the same with and without an arena.
+//
+// Thread Safety: Not safe for concurrent use; each parse acquires its own.
+type parseArena struct {
+	calls arena[CallSite]
+
+	// walk is the scratch stack for iterative traversals.
+	walk []walkEntry
+
+	// callBuf collects a body's call sites before they are copied out.
+	callBuf []CallSite
+
+	// types caches node type names by grammar symbol. Symbols are fixed
+	// by the grammar, so the cache survives reset and is only cleared
+	// when the arena is acquired for another language.
+	types    map[sitter.Symbol]string
+	language string
+}
+
+var parseArenaPool = sync.Pool{
+	New: func() any {
+		return &parseArena{types: make(map[sitter.Symbol]string)}
+	},
+}
+
+// acquireParseArena returns an empty arena from the pool for parsing
+// files of the given language.
+func acquireParseArena(language string) *parseArena {
+	a := parseArenaPool.Get().(*parseArena)
+	if a.language != language {
+		clear(a.types)
+		a.language = language
+	}
+	return a
+}
+
+// release resets the arena and returns it to the pool. The arena and
+// everything allocated from it must not be used afterwards.
+func (a *parseArena) release() {
+	if a == nil {
+		return
+	}
+	a.calls.reset()
+	if cap(a.walk) > maxPooledScratch {
+		a.walk = nil
+	}
+	clear(a.walk[:cap(a.walk)])
+	a.walk = a.walk[:0]
+	if cap(a.callBuf) > maxPooledScratch {
+		a.callBuf = nil
+	}
+	clear(a.callBuf[:cap(a.callBuf)])
+	a.callBuf = a.callBuf[:0]
+	parseArenaPool.Put(a)
+}
+
+// newCallSite returns a zero CallSite valid until the arena is released.
+func (a *parseArena) newCallSite() *CallSite {
+	if a == nil {
+		return &CallSite{}
+	}
+	return a.calls.alloc()
+}
+
+// walkStack returns an empty traversal stack. Pass the grown stack back
+// to putWalkStack when done.
+func (a *parseArena) walkStack() []walkEntry {
+	if a == nil || a.walk == nil {
+		return make([]walkEntry, 0, 64)
+	}
+	s := a.walk[:0]
+	a.walk = nil // not reentrant: a nested walk gets a fresh stack
+	return s
+}
+
+// putWalkStack hands a stack from walkStack back to the arena.
+func (a *parseArena) putWalkStack(s []walkEntry) {
+	if a != nil {
+		clear(s[:cap(s)])
+		a.walk = s[:0]
+	}
+}
+
+// callScratch returns an empty buffer for collecting call sites.
+func (a *parseArena) callScratch() []CallSite {
+	if a == nil {
+		return make([]CallSite, 0, 16)
+	}
+	return a.callBuf[:0]
+}
+
+// keepCalls returns the call sites in buf as a slice that outlives the
+// arena, and takes buf back as scratch. Without an arena, buf is returned
+// as is.
+func (a *parseArena) keepCalls(buf []CallSite) []CallSite {
+	if a == nil {
+		return buf
+	}
+	out := make([]CallSite, len(buf))
+	copy(out, buf)
+	clear(buf)
+	a.callBuf = buf[:0]
+	return out
+}
+
+// nodeType returns n.Type(), cached by grammar symbol.
+func (a *parseArena) nodeType(n *sitter.Node) string {
+	if a == nil {
+		return n.Type()
+	}
+	sym := n.Symbol()
+	t, ok := a.types[sym]
+	if !ok {
+		t = n.Type()
+		a.types[sym] = t
+	}
+	return t
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// callHeavyGoSource generates a Go file with n functions that each make
+// several calls, approximating the call-site load of real code.
+func callHeavyGoSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("package bench\n\nimport \"fmt\"\n\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `// Func%[1]d does work.
+func Func%[1]d(s *Server, x int) int {
+	if err := s.Validate(x); err != nil {
+		fmt.Println(err)
+	}
+	y := helper(x, compute(x+%[1]d))
+	for _, v := range s.Items() {
+		y += s.store.Get(v).Len()
+	}
+	return y
+}
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func TestArena_AllocReset(t *testing.T) {
+	var a arena[CallSite]
+	first := a.alloc()
+	first.Target = "a"
+	for i := 1; i < arenaChunkSize+10; i++ {
+		a.alloc().Target = "x"
+	}
+	if len(a.chunks) != 2 {
+		t.Fatalf("chunks = %d, want 2", len(a.chunks))
+	}
+
+	a.reset()
+	if first.Target != "" {
+		t.Errorf("reset did not zero handed-out values: %q", first.Target)
+	}
+	if again := a.alloc(); again != first {
+		t.Error("alloc after reset did not reuse the first slot")
+	}
+	if len(a.chunks) != 2 {
+		t.Errorf("reset dropped chunks: %d", len(a.chunks))
+	}
+}
+
+// TestGoParser_Arena checks that arena allocation gives the same call
+// sites as heap allocation, with fewer allocations per parse.
+func TestGoParser_Arena(t *testing.T) {
+	ctx := context.Background()
+	src := callHeavyGoSource(50)
+	heap := NewGoParser(WithArena(false))
+	withArena := NewGoParser()
+
+	want, err := heap.Parse(ctx, src, "bench.go")
+	if err != nil {
+		t.Fatalf("Parse(heap): %v", err)
+	}
+	// Parse twice so the second run reuses a pooled arena.
+	for i := 0; i < 2; i++ {
+		got, err := withArena.Parse(ctx, src, "bench.go")
+		if err != nil {
+			t.Fatalf("Parse(arena): %v", err)
+		}
+		if len(got.Symbols) != len(want.Symbols) {
+			t.Fatalf("symbols = %d, want %d", len(got.Symbols), len(want.Symbols))
+		}
+		for j, sym := range got.Symbols {
+			if !reflect.DeepEqual(sym.Calls, want.Symbols[j].Calls) {
+				t.Errorf("%s calls = %+v, want %+v", sym.Name, sym.Calls, want.Symbols[j].Calls)
+			}
+		}
+	}
+
+	if testing.Short() {
+		return
+	}
+	heapAllocs := testing.AllocsPerRun(5, func() { _, _ = heap.Parse(ctx, src, "bench.go") })
+	arenaAllocs := testing.AllocsPerRun(5, func() { _, _ = withArena.Parse(ctx, src, "bench.go") })
+	t.Logf("allocs/parse: heap %.0f, arena %.0f", heapAllocs, arenaAllocs)
+	if arenaAllocs >= heapAllocs {
+		t.Errorf("arena allocs/parse = %.0f, want fewer than heap's %.0f", arenaAllocs, heapAllocs)
+	}
+}
+
+// BenchmarkGoParser_Arena compares parsing with and without the per-file
+// arena. Run with -benchmem to see the allocation difference.
+func BenchmarkGoParser_Arena(b *testing.B) {
+	src := callHeavyGoSource(200)
+	for _, tc := range []struct {
+		name  string
+		arena bool
+	}{
+		{"heap", false},
+		{"arena", true},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			parser := NewGoParser(WithArena(tc.arena))
+			b.ReportAllocs()
+			b.SetBytes(int64(len(src)))
+			for i := 0; i < b.N; i++ {
+				if _, err := parser.Parse(context.Background(), src, "bench.go"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
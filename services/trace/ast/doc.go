@@ -0,0 +1,139 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import "strings"
+
+// MaxDocSummaryLength is the maximum length of a summary returned by DocSummary.
+const MaxDocSummaryLength = 200
+
+// CleanDocComment strips comment delimiters from a raw doc comment.
+//
+// Description:
+//
+//	Parsers store doc comments as they appear in the source ("// ...",
+//	"# ...", "/** ... */", or a Python docstring body). CleanDocComment
+//	removes the delimiters and leading "*" gutters, trims each line, and
+//	drops leading and trailing blank lines so the text can be shown to a
+//	user or an LLM without language-specific noise.
+//
+// Inputs:
+//
+//	raw - The doc comment as stored in Symbol.DocComment.
+//
+// Outputs:
+//
+//	string - The comment text with lines joined by "\n". Empty if raw is blank.
+//
+// Thread Safety: This function is safe for concurrent use.
+func CleanDocComment(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	for _, q := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(raw, q) {
+			raw = strings.TrimSuffix(strings.TrimPrefix(raw, q), q)
+		}
+	}
+	if strings.HasPrefix(raw, "/*") {
+		raw = strings.TrimSuffix(raw, "*/")
+		raw = strings.TrimLeft(raw[2:], "*!")
+	}
+
+	lines := strings.Split(raw, "\n")
+	cleaned := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "///"):
+			line = line[3:]
+		case strings.HasPrefix(line, "//"):
+			line = line[2:]
+		case strings.HasPrefix(line, "#"):
+			line = line[1:]
+		case line == "*":
+			line = ""
+		case strings.HasPrefix(line, "* "):
+			line = line[2:]
+		}
+		cleaned = append(cleaned, strings.TrimSpace(line))
+	}
+
+	// Drop leading and trailing blank lines.
+	start, end := 0, len(cleaned)
+	for start < end && cleaned[start] == "" {
+		start++
+	}
+	for end > start && cleaned[end-1] == "" {
+		end--
+	}
+	return strings.Join(cleaned[start:end], "\n")
+}
+
+// DocSummary returns the first sentence of a doc comment.
+//
+// Description:
+//
+//	Cleans the comment, takes the first paragraph, and cuts it at the first
+//	sentence terminator. The result is capped at MaxDocSummaryLength so
+//	that context assembly can include summaries for many symbols cheaply.
+//
+// Inputs:
+//
+//	raw - The doc comment as stored in Symbol.DocComment.
+//
+// Outputs:
+//
+//	string - The summary sentence, or empty if there is no documentation.
+//
+// Thread Safety: This function is safe for concurrent use.
+func DocSummary(raw string) string {
+	text := CleanDocComment(raw)
+	if text == "" {
+		return ""
+	}
+
+	if i := strings.Index(text, "\n\n"); i >= 0 {
+		text = text[:i]
+	}
+	text = strings.Join(strings.Fields(text), " ")
+
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+
+	if len(text) > MaxDocSummaryLength {
+		text = strings.TrimSpace(text[:MaxDocSummaryLength-3]) + "..."
+	}
+	return text
+}
+
+// IsDocumentable reports whether symbols of this kind are expected to carry
+// documentation and count toward documentation coverage.
+//
+// Declarations such as imports, fields, parameters, and markup nodes are
+// not counted.
+func (k SymbolKind) IsDocumentable() bool {
+	switch k {
+	case SymbolKindFunction, SymbolKindMethod, SymbolKindInterface,
+		SymbolKindStruct, SymbolKindClass, SymbolKindType, SymbolKindEnum:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasDoc reports whether the symbol has a non-empty doc comment.
+func (s *Symbol) HasDoc() bool {
+	return s != nil && CleanDocComment(s.DocComment) != ""
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanDocComment(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"go line comments", "// Foo does a thing.\n// It is useful.", "Foo does a thing.\nIt is useful."},
+		{"go blank comment line", "// Foo.\n//\n// Details.", "Foo.\n\nDetails."},
+		{"jsdoc", "/**\n * Adds two numbers.\n * @param a first\n */", "Adds two numbers.\n@param a first"},
+		{"block comment", "/* Simple block */", "Simple block"},
+		{"python docstring", "\"\"\"\n    Compute totals.\n    \"\"\"", "Compute totals."},
+		{"hash comment", "# Deploys the app\n# to production", "Deploys the app\nto production"},
+		{"whitespace only", "//\n//  \n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanDocComment(tt.raw); got != tt.want {
+				t.Errorf("CleanDocComment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"first sentence", "// Foo does a thing. It also does another.", "Foo does a thing."},
+		{"first paragraph across lines", "// Foo does\n// a thing\n//\n// More detail.", "Foo does a thing"},
+		{"no docs", "", ""},
+		{"version number kept", "// Requires Go 1.21 or later", "Requires Go 1.21 or later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DocSummary(tt.raw); got != tt.want {
+				t.Errorf("DocSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("truncates long summaries", func(t *testing.T) {
+		got := DocSummary("// " + strings.Repeat("word ", 100))
+		if len(got) > MaxDocSummaryLength {
+			t.Errorf("summary length %d exceeds %d", len(got), MaxDocSummaryLength)
+		}
+		if !strings.HasSuffix(got, "...") {
+			t.Errorf("expected truncated summary to end with ..., got %q", got)
+		}
+	})
+}
+
+func TestSymbol_HasDoc(t *testing.T) {
+	if (&Symbol{DocComment: "// Foo does things."}).HasDoc() != true {
+		t.Error("expected symbol with doc comment to have doc")
+	}
+	if (&Symbol{DocComment: "//"}).HasDoc() {
+		t.Error("expected empty comment marker not to count as doc")
+	}
+	var nilSym *Symbol
+	if nilSym.HasDoc() {
+		t.Error("expected nil symbol to have no doc")
+	}
+}
+
+func TestSymbolKind_IsDocumentable(t *testing.T) {
+	for _, k := range []SymbolKind{SymbolKindFunction, SymbolKindMethod, SymbolKindStruct, SymbolKindInterface} {
+		if !k.IsDocumentable() {
+			t.Errorf("expected %s to be documentable", k)
+		}
+	}
+	for _, k := range []SymbolKind{SymbolKindImport, SymbolKindField, SymbolKindHeading} {
+		if k.IsDocumentable() {
+			t.Errorf("expected %s not to be documentable", k)
+		}
+	}
+}
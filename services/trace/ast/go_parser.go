@@ -71,6 +71,23 @@ func WithParseOptions(opts ParseOptions) GoParserOption {
 	}
 }
 
+// WithArena enables or disables per-file arena allocation of parse-time
+// structures. Enabled by default; disabling it is mainly useful for
+// comparing allocation behaviour.
+//
+// Parameters:
+//   - enabled: Whether to allocate call sites and walk stacks from a
+//     pooled per-file arena.
+//
+// Example:
+//
+//	parser := NewGoParser(WithArena(false)) // plain heap allocation
+func WithArena(enabled bool) GoParserOption {
+	return func(p *GoParser) {
+		p.useArena = enabled
+	}
+}
+
 // GoParser implements the Parser interface for Go source code.
 //
 // Description:
@@ -97,6 +114,7 @@ func WithParseOptions(opts ParseOptions) GoParserOption {
 type GoParser struct {
 	maxFileSize  int64
 	parseOptions ParseOptions
+	useArena     bool
 }
 
 // NewGoParser creates a new GoParser with the given options.
@@ -107,7 +125,7 @@ type GoParser struct {
 //	provided to customize behavior such as maximum file size.
 //
 // Inputs:
-//   - opts: Optional configuration functions (WithMaxFileSize, WithParseOptions)
+//   - opts: Optional configuration functions (WithMaxFileSize, WithParseOptions, WithArena)
 //
 // Outputs:
 //   - *GoParser: Configured parser instance, never nil
@@ -127,6 +145,7 @@ func NewGoParser(opts ...GoParserOption) *GoParser {
 	p := &GoParser{
 		maxFileSize:  DefaultMaxFileSize,
 		parseOptions: DefaultParseOptions(),
+		useArena:     true,
 	}
 
 	for _, opt := range opts {
@@ -246,6 +265,13 @@ func (p *GoParser) Parse(ctx context.Context, content []byte, filePath string) (
 		Errors:        make([]string, 0),
 	}
 
+	// Parse-time scratch (call sites, walk stacks) is freed with the file
+	var arena *parseArena
+	if p.useArena {
+		arena = acquireParseArena("go")
+		defer arena.release()
+	}
+
 	// Extract symbols from the tree
 	rootNode := tree.RootNode()
 	if rootNode == nil {
@@ -265,10 +291,10 @@ func (p *GoParser) Parse(ctx context.Context, content []byte, filePath string) (
 	p.extractImports(rootNode, content, filePath, result)
 
 	// Extract functions (GR-41: now extracts call sites too)
-	p.extractFunctions(ctx, rootNode, content, filePath, result)
+	p.extractFunctions(ctx, rootNode, content, filePath, result, arena)
 
 	// Extract methods (GR-41: now extracts call sites too)
-	p.extractMethods(ctx, rootNode, content, filePath, result)
+	p.extractMethods(ctx, rootNode, content, filePath, result, arena)
 
 	// Extract types (structs, interfaces, type aliases)
 	p.extractTypes(rootNode, content, filePath, result)
@@ -438,18 +464,18 @@ func (p *GoParser) processImportSpec(node *sitter.Node, content []byte, filePath
 
 // extractFunctions extracts function declarations from the AST.
 // GR-41: Now accepts context for call site extraction.
-func (p *GoParser) extractFunctions(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult) {
+func (p *GoParser) extractFunctions(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult, arena *parseArena) {
 	for i := 0; i < int(root.ChildCount()); i++ {
 		child := root.Child(i)
 		if child.Type() == "function_declaration" {
-			p.processFunctionDecl(ctx, child, content, filePath, result, root)
+			p.processFunctionDecl(ctx, child, content, filePath, result, root, arena)
 		}
 	}
 }
 
 // processFunctionDecl extracts a single function declaration.
 // GR-41: Now accepts context and extracts call sites from function body.
-func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node) {
+func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, arena *parseArena) {
 	var name string
 	var signature string
 	var params string
@@ -513,7 +539,7 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 
 	// GR-41: Extract call sites from function body
 	if bodyNode != nil {
-		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath)
+		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath, arena)
 	}
 
 	result.Symbols = append(result.Symbols, sym)
@@ -521,18 +547,18 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 
 // extractMethods extracts method declarations from the AST.
 // GR-41: Now accepts context for call site extraction.
-func (p *GoParser) extractMethods(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult) {
+func (p *GoParser) extractMethods(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult, arena *parseArena) {
 	for i := 0; i < int(root.ChildCount()); i++ {
 		child := root.Child(i)
 		if child.Type() == "method_declaration" {
-			p.processMethodDecl(ctx, child, content, filePath, result, root)
+			p.processMethodDecl(ctx, child, content, filePath, result, root, arena)
 		}
 	}
 }
 
 // processMethodDecl extracts a single method declaration.
 // GR-41: Now accepts context and extracts call sites from method body.
-func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node) {
+func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, arena *parseArena) {
 	var name string
 	var receiverStr string
 	var params string
@@ -603,7 +629,7 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 
 	// GR-41: Extract call sites from method body
 	if bodyNode != nil {
-		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath)
+		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath, arena)
 	}
 
 	result.Symbols = append(result.Symbols, sym)
@@ -1377,7 +1403,7 @@ func (p *GoParser) processVarSpec(node *sitter.Node, content []byte, filePath st
 	}
 }
 
-// getPrecedingComment extracts the doc comment immediately before a node.
+// getPrecedingComment extracts the doc comment block immediately before a node.
 func (p *GoParser) getPrecedingComment(root *sitter.Node, node *sitter.Node, content []byte) string {
 	if node == nil {
 		return ""
@@ -1388,13 +1414,33 @@ func (p *GoParser) getPrecedingComment(root *sitter.Node, node *sitter.Node, con
 	// Search siblings for comment immediately preceding this node
 	for i := 0; i < int(root.ChildCount()); i++ {
 		sibling := root.Child(i)
-		if sibling.Type() == "comment" {
-			commentEndLine := int(sibling.EndPoint().Row)
-			// Comment must be on line immediately before node
-			if commentEndLine == nodeStartLine-1 || commentEndLine == nodeStartLine {
-				return strings.TrimSpace(string(content[sibling.StartByte():sibling.EndByte()]))
+		if sibling.Type() != "comment" {
+			continue
+		}
+		commentEndLine := int(sibling.EndPoint().Row)
+		// Comment must be on line immediately before node
+		if commentEndLine != nodeStartLine-1 && commentEndLine != nodeStartLine {
+			continue
+		}
+
+		// Each "//" line is its own comment node; walk back to the start
+		// of the contiguous block so multi-line doc comments are kept whole.
+		first := i
+		for first > 0 {
+			prev := root.Child(first - 1)
+			if prev.Type() != "comment" ||
+				int(prev.EndPoint().Row) != int(root.Child(first).StartPoint().Row)-1 {
+				break
 			}
+			first--
+		}
+
+		lines := make([]string, 0, i-first+1)
+		for j := first; j <= i; j++ {
+			c := root.Child(j)
+			lines = append(lines, strings.TrimSpace(string(content[c.StartByte():c.EndByte()])))
 		}
+		return strings.Join(lines, "\n")
 	}
 
 	return ""
@@ -1414,6 +1460,8 @@ func (p *GoParser) getPrecedingComment(root *sitter.Node, node *sitter.Node, con
 //   - bodyNode: The block node representing the function body. May be nil.
 //   - content: The source file content bytes.
 //   - filePath: Path to the source file for location data.
+//   - arena: The parse's arena for scratch allocations, or nil for the heap.
+//     The returned slice never points into the arena.
 //
 // Outputs:
 //   - []CallSite: Extracted call sites. Empty slice if bodyNode is nil or no calls found.
@@ -1431,11 +1479,11 @@ func (p *GoParser) getPrecedingComment(root *sitter.Node, node *sitter.Node, con
 //
 // Example:
 //
-//	calls := p.extractCallSites(ctx, bodyNode, content, "main.go")
+//	calls := p.extractCallSites(ctx, bodyNode, content, "main.go", arena)
 //	for _, call := range calls {
 //	    fmt.Printf("Call to %s at line %d\n", call.Target, call.Location.StartLine)
 //	}
-func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, content []byte, filePath string) []CallSite {
+func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, content []byte, filePath string, arena *parseArena) []CallSite {
 	if bodyNode == nil {
 		return nil
 	}
@@ -1449,16 +1497,12 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 	ctx, span := tracer.Start(ctx, "GoParser.extractCallSites")
 	defer span.End()
 
-	calls := make([]CallSite, 0, 16) // Pre-allocate for typical function
+	calls := arena.callScratch()
 
 	// Iterative traversal with depth limiting
-	type stackEntry struct {
-		node  *sitter.Node
-		depth int
-	}
-
-	stack := make([]stackEntry, 0, 64)
-	stack = append(stack, stackEntry{node: bodyNode, depth: 0})
+	stack := arena.walkStack()
+	defer func() { arena.putWalkStack(stack) }()
+	stack = append(stack, walkEntry{node: bodyNode, depth: 0})
 
 	nodeCount := 0
 	for len(stack) > 0 {
@@ -1488,7 +1532,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 					slog.String("file", filePath),
 					slog.Int("calls_found", len(calls)),
 				)
-				return calls
+				return arena.keepCalls(calls)
 			}
 		}
 
@@ -1498,12 +1542,12 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 				slog.String("file", filePath),
 				slog.Int("limit", MaxCallSitesPerSymbol),
 			)
-			return calls
+			return arena.keepCalls(calls)
 		}
 
 		// Process call expressions
-		if node.Type() == "call_expression" {
-			call := p.extractSingleCallSite(node, content, filePath)
+		if arena.nodeType(node) == "call_expression" {
+			call := p.extractSingleCallSite(node, content, filePath, arena)
 			if call != nil && call.Target != "" {
 				calls = append(calls, *call)
 			}
@@ -1514,7 +1558,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 		for i := childCount - 1; i >= 0; i-- {
 			child := node.Child(i)
 			if child != nil {
-				stack = append(stack, stackEntry{
+				stack = append(stack, walkEntry{
 					node:  child,
 					depth: entry.depth + 1,
 				})
@@ -1529,7 +1573,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 		attribute.Int("nodes_traversed", nodeCount),
 	)
 
-	return calls
+	return arena.keepCalls(calls)
 }
 
 // extractSingleCallSite extracts call information from a call_expression node.
@@ -1544,13 +1588,15 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 //   - node: A call_expression node from tree-sitter. Must not be nil.
 //   - content: The source file content bytes.
 //   - filePath: Path to the source file for location data.
+//   - arena: The parse's arena, or nil to allocate on the heap.
 //
 // Outputs:
-//   - *CallSite: The extracted call site, or nil if extraction fails.
+//   - *CallSite: The extracted call site, or nil if extraction fails. When
+//     arena is non-nil it is only valid until the arena is released.
 //
 // Thread Safety: This function is safe for concurrent use.
-func (p *GoParser) extractSingleCallSite(node *sitter.Node, content []byte, filePath string) *CallSite {
-	if node == nil || node.Type() != "call_expression" {
+func (p *GoParser) extractSingleCallSite(node *sitter.Node, content []byte, filePath string, arena *parseArena) *CallSite {
+	if node == nil || arena.nodeType(node) != "call_expression" {
 		return nil
 	}
 
@@ -1566,17 +1612,16 @@ func (p *GoParser) extractSingleCallSite(node *sitter.Node, content []byte, file
 		return nil
 	}
 
-	call := &CallSite{
-		Location: Location{
-			FilePath:  filePath,
-			StartLine: int(node.StartPoint().Row) + 1,
-			EndLine:   int(node.EndPoint().Row) + 1,
-			StartCol:  int(node.StartPoint().Column),
-			EndCol:    int(node.EndPoint().Column),
-		},
+	call := arena.newCallSite()
+	call.Location = Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
 	}
 
-	switch funcNode.Type() {
+	switch arena.nodeType(funcNode) {
 	case "identifier":
 		// Simple function call: FunctionName(args)
 		call.Target = string(content[funcNode.StartByte():funcNode.EndByte()])
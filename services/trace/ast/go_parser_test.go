@@ -220,6 +220,40 @@ func TestGoParser_Parse_Function(t *testing.T) {
 	}
 }
 
+func TestGoParser_Parse_MultiLineDocComment(t *testing.T) {
+	parser := NewGoParser()
+	ctx := context.Background()
+
+	src := `package math
+
+// Unrelated comment.
+
+// Add returns the sum of a and b.
+//
+// It never overflows silently.
+func Add(a, b int) int {
+	return a + b
+}
+`
+	result, err := parser.Parse(ctx, []byte(src), "add.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	funcs := filterByKind(result.Symbols, SymbolKindFunction)
+	if len(funcs) != 1 {
+		t.Fatalf("expected 1 function, got %d", len(funcs))
+	}
+
+	want := "// Add returns the sum of a and b.\n//\n// It never overflows silently."
+	if funcs[0].DocComment != want {
+		t.Errorf("DocComment = %q, want %q", funcs[0].DocComment, want)
+	}
+	if got := DocSummary(funcs[0].DocComment); got != "Add returns the sum of a and b." {
+		t.Errorf("DocSummary = %q", got)
+	}
+}
+
 func TestGoParser_Parse_Method(t *testing.T) {
 	parser := NewGoParser()
 	ctx := context.Background()
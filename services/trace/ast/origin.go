@@ -0,0 +1,73 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import "github.com/AleutianAI/AleutianFOSS/services/trace/origin"
+
+// AnnotateOrigin classifies the parsed file and propagates the result to
+// every symbol.
+//
+// Description:
+//
+//	Detects generated and vendored files from the file path and the content
+//	header (see origin.Detect), stores the classification in r.Origin, and
+//	copies it into Metadata.Origin of each symbol and its children so that
+//	analyzers can filter individual symbols without re-reading files.
+//	Source files are left untouched to avoid allocating Metadata.
+//
+// Inputs:
+//
+//	content - The file content that was parsed. May be nil to classify by path only.
+//
+// Outputs:
+//
+//	origin.Kind - The detected classification.
+//
+// Thread Safety: Not safe for concurrent use on the same ParseResult.
+func (r *ParseResult) AnnotateOrigin(content []byte) origin.Kind {
+	kind := origin.Detect(r.FilePath, content)
+	r.Origin = kind
+	if kind == origin.KindSource {
+		return kind
+	}
+
+	stack := append([]*Symbol(nil), r.Symbols...)
+	depth := map[*Symbol]int{}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s == nil {
+			continue
+		}
+		if s.Metadata == nil {
+			s.Metadata = &SymbolMetadata{}
+		}
+		s.Metadata.Origin = kind
+
+		if d := depth[s]; d < MaxSymbolDepth {
+			for _, c := range s.Children {
+				depth[c] = d + 1
+				stack = append(stack, c)
+			}
+		}
+	}
+	return kind
+}
+
+// Origin returns the symbol's file classification.
+//
+// Symbols without metadata are hand-written source.
+func (s *Symbol) Origin() origin.Kind {
+	if s == nil || s.Metadata == nil {
+		return origin.KindSource
+	}
+	return s.Metadata.Origin
+}
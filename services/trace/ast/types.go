@@ -27,6 +27,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // SymbolKind represents the type of code symbol extracted from source code.
@@ -634,6 +636,10 @@ type SymbolMetadata struct {
 
 	// LinkTitle is the optional title for link reference definitions.
 	LinkTitle string `json:"link_title,omitempty"`
+
+	// Origin classifies the containing file as source, generated, or vendored.
+	// Propagated from ParseResult.Origin by ParseResult.AnnotateOrigin.
+	Origin origin.Kind `json:"origin,omitempty"`
 }
 
 // GenerateID creates a unique identifier for a symbol based on its location and name.
@@ -794,6 +800,10 @@ type ParseResult struct {
 	// Hash is the SHA256 hash of the file content at parse time.
 	// Used for cache invalidation and staleness detection.
 	Hash string `json:"hash"`
+
+	// Origin classifies the file as source, generated, or vendored.
+	// Set by AnnotateOrigin; zero value means hand-written source.
+	Origin origin.Kind `json:"origin,omitempty"`
 }
 
 // Import represents an import statement in source code.
@@ -17,6 +17,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 func TestSymbolKind_String(t *testing.T) {
@@ -1093,3 +1095,31 @@ func (m *mockParser) Language() string {
 func (m *mockParser) Extensions() []string {
 	return m.extensions
 }
+
+func TestParseResult_AnnotateOrigin(t *testing.T) {
+	child := &Symbol{ID: "a.pb.go:5:GetName", Name: "GetName", Kind: SymbolKindMethod}
+	parent := &Symbol{ID: "a.pb.go:3:User", Name: "User", Kind: SymbolKindStruct, Children: []*Symbol{child}}
+	r := &ParseResult{FilePath: "api/a.pb.go", Symbols: []*Symbol{parent}}
+
+	if got := r.AnnotateOrigin(nil); got != origin.KindGenerated {
+		t.Fatalf("AnnotateOrigin = %v, want generated", got)
+	}
+	if r.Origin != origin.KindGenerated {
+		t.Errorf("ParseResult.Origin = %v, want generated", r.Origin)
+	}
+	if parent.Origin() != origin.KindGenerated || child.Origin() != origin.KindGenerated {
+		t.Errorf("origin not propagated: parent=%v child=%v", parent.Origin(), child.Origin())
+	}
+}
+
+func TestParseResult_AnnotateOrigin_Source(t *testing.T) {
+	sym := &Symbol{ID: "main.go:1:main", Name: "main", Kind: SymbolKindFunction}
+	r := &ParseResult{FilePath: "main.go", Symbols: []*Symbol{sym}}
+
+	if got := r.AnnotateOrigin([]byte("package main\n")); got != origin.KindSource {
+		t.Fatalf("AnnotateOrigin = %v, want source", got)
+	}
+	if sym.Metadata != nil {
+		t.Error("source symbols should not get metadata allocated")
+	}
+}
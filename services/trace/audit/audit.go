@@ -0,0 +1,460 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package audit records agent actions in an append-only, hash-chained log.
+//
+// Every action that touches files or executes commands is written as one
+// JSON line holding the actor, session, tool, a digest of the arguments, and
+// the outcome. Each entry's hash covers its content and the previous entry's
+// hash, so editing, deleting, or reordering any entry breaks the chain from
+// that point on. Verify walks the chain and reports the first broken entry.
+//
+// Arguments are stored only as a SHA-256 digest: the log proves what was
+// done without retaining file contents or secrets passed to tools.
+//
+// An unkeyed chain detects edits by anyone who cannot also rewrite every
+// later entry. Configure a key (HMAC-SHA256) or record the head hash
+// elsewhere to also detect a rewrite of the whole file.
+//
+// Thread Safety: Log is safe for concurrent use within one process. Only one
+// process should append to a given file.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTampered is returned when the hash chain does not verify.
+	ErrTampered = errors.New("audit log tampered")
+
+	// ErrClosed is returned when recording to a closed log.
+	ErrClosed = errors.New("audit log closed")
+)
+
+// GenesisHash is the PrevHash of the first entry in a log.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// maxLineBytes bounds a single entry when reading a log.
+const maxLineBytes = 1 << 20
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+const (
+	// OutcomeSuccess means the action completed and reported success.
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeFailure means the action completed but reported failure.
+	OutcomeFailure Outcome = "failure"
+
+	// OutcomeError means the action could not be completed.
+	OutcomeError Outcome = "error"
+
+	// OutcomeTimeout means the action ran out of time.
+	OutcomeTimeout Outcome = "timeout"
+
+	// OutcomeDenied means the action was refused before it ran.
+	OutcomeDenied Outcome = "denied"
+)
+
+// Entry is one record in the audit log.
+//
+// Field order is part of the hash format; do not reorder.
+type Entry struct {
+	// Seq is the 1-based position of the entry in the log.
+	Seq uint64 `json:"seq"`
+
+	// Timestamp is when the action finished (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+
+	// Actor is who performed the action, e.g. "agent".
+	Actor string `json:"actor"`
+
+	// SessionID is the agent session the action belongs to.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Tool is the tool that performed the action.
+	Tool string `json:"tool"`
+
+	// Action classifies the action, e.g. "file_read", "file_write", "command".
+	Action string `json:"action,omitempty"`
+
+	// ArgsDigest is DigestArgs of the tool arguments.
+	ArgsDigest string `json:"args_digest"`
+
+	// Outcome is the result of the action.
+	Outcome Outcome `json:"outcome"`
+
+	// Error describes a failure, if any.
+	Error string `json:"error,omitempty"`
+
+	// DurationMs is how long the action took.
+	DurationMs int64 `json:"duration_ms"`
+
+	// PrevHash is the Hash of the previous entry, or GenesisHash.
+	PrevHash string `json:"prev_hash"`
+
+	// Hash covers every other field. See Log for how it is computed.
+	Hash string `json:"hash"`
+}
+
+// Record describes an action to append to the log.
+type Record struct {
+	Actor     string
+	SessionID string
+	Tool      string
+	Action    string
+	Args      map[string]any
+	Outcome   Outcome
+	Error     string
+	Duration  time.Duration
+}
+
+// Options configures a Log.
+type Options struct {
+	// Key, if set, keys the chain with HMAC-SHA256 so it cannot be rebuilt
+	// without the key. Verify must be given the same key.
+	Key []byte
+
+	// Sync fsyncs the file after every entry (default: false).
+	Sync bool
+}
+
+// Log is an append-only, hash-chained audit log backed by a JSONL file.
+//
+// Description:
+//
+//	Entry.Hash is the hex SHA-256 (or HMAC-SHA256 when keyed) of the
+//	previous hash followed by the entry's JSON encoding with Hash empty.
+//	Opening an existing file verifies it and continues its chain.
+//
+// Thread Safety: Safe for concurrent use.
+type Log struct {
+	mu     sync.Mutex
+	file   *os.File
+	path   string
+	opts   Options
+	seq    uint64
+	head   string
+	closed bool
+	err    error // sticky write error
+}
+
+// Open opens or creates the audit log at path.
+//
+// Inputs:
+//
+//	path - The log file. Parent directories are created.
+//	opts - Log options.
+//
+// Outputs:
+//
+//	*Log - The log, positioned after the last verified entry.
+//	error - Wraps ErrTampered if the existing file does not verify.
+func Open(path string, opts Options) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	report, err := Verify(f, opts.Key)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("audit log %s: %w", path, err)
+	}
+
+	return &Log{
+		file: f,
+		path: path,
+		opts: opts,
+		seq:  uint64(report.Entries),
+		head: report.HeadHash,
+	}, nil
+}
+
+// Path returns the log file path.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Head returns the sequence number and hash of the last entry.
+func (l *Log) Head() (uint64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq, l.head
+}
+
+// Err returns the error that stopped the log from recording, if any.
+// Once a write fails the log refuses further records, since the file may
+// hold a partial entry.
+func (l *Log) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	return l.err
+}
+
+// Record appends an entry for an action.
+//
+// Outputs:
+//
+//	*Entry - The entry as written.
+//	error - Non-nil if the entry could not be written.
+func (l *Log) Record(r Record) (*Entry, error) {
+	digest, err := DigestArgs(r.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil, ErrClosed
+	}
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	e := &Entry{
+		Seq:        l.seq + 1,
+		Timestamp:  time.Now().UnixMilli(),
+		Actor:      r.Actor,
+		SessionID:  r.SessionID,
+		Tool:       r.Tool,
+		Action:     r.Action,
+		ArgsDigest: digest,
+		Outcome:    r.Outcome,
+		Error:      r.Error,
+		DurationMs: r.Duration.Milliseconds(),
+		PrevHash:   l.head,
+	}
+	if e.Hash, err = computeHash(e, l.opts.Key); err != nil {
+		return nil, err
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := l.file.Write(line); err != nil {
+		l.err = fmt.Errorf("writing audit log: %w", err)
+		return nil, l.err
+	}
+	if l.opts.Sync {
+		if err := l.file.Sync(); err != nil {
+			l.err = fmt.Errorf("syncing audit log: %w", err)
+			return nil, l.err
+		}
+	}
+
+	l.seq = e.Seq
+	l.head = e.Hash
+	return e, nil
+}
+
+// Close closes the log file. Safe to call on a nil log and more than once.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.file.Close()
+}
+
+// DigestArgs returns the hex SHA-256 of the JSON encoding of args. Map keys
+// are encoded in sorted order, so equal arguments give equal digests.
+func DigestArgs(args map[string]any) (string, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("encoding audit arguments: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// -----------------------------------------------------------------------------
+// Verification
+// -----------------------------------------------------------------------------
+
+// VerifyReport summarizes a verified log.
+type VerifyReport struct {
+	// Entries is the number of entries that verified.
+	Entries int `json:"entries"`
+
+	// HeadHash is the hash of the last verified entry (GenesisHash if none).
+	HeadHash string `json:"head_hash"`
+
+	// FirstTimestamp and LastTimestamp bound the verified entries
+	// (Unix milliseconds UTC, zero if none).
+	FirstTimestamp int64 `json:"first_timestamp,omitempty"`
+	LastTimestamp  int64 `json:"last_timestamp,omitempty"`
+
+	// BrokenLine is the 1-based line at which verification failed (0 if
+	// the log verified).
+	BrokenLine int `json:"broken_line,omitempty"`
+
+	// AnchorSeq is the sequence number of the entry matching the anchor
+	// hash given to VerifyFile (0 if none was given).
+	AnchorSeq uint64 `json:"anchor_seq,omitempty"`
+}
+
+// Verify checks the hash chain of a log.
+//
+// Description:
+//
+//	Each line must parse as an Entry, re-encode to exactly the same bytes,
+//	follow the previous entry's sequence number and hash, and carry the
+//	hash of its own content. The report covers the entries before the
+//	first failure.
+//
+// Inputs:
+//
+//	r - The log contents.
+//	key - The HMAC key the log was written with, or nil.
+//
+// Outputs:
+//
+//	*VerifyReport - Always non-nil.
+//	error - Wraps ErrTampered on the first broken entry, or reports a
+//	        read error.
+func Verify(r io.Reader, key []byte) (*VerifyReport, error) {
+	return verify(r, key, "")
+}
+
+// verify implements Verify, also locating the entry whose hash is anchor.
+func verify(r io.Reader, key []byte, anchor string) (*VerifyReport, error) {
+	report := &VerifyReport{HeadHash: GenesisHash}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLineBytes)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := sc.Bytes()
+
+		fail := func(format string, args ...any) (*VerifyReport, error) {
+			report.BrokenLine = line
+			return report, fmt.Errorf("%w: line %d: %s", ErrTampered, line, fmt.Sprintf(format, args...))
+		}
+
+		var e Entry
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&e); err != nil {
+			return fail("malformed entry: %v", err)
+		}
+		canonical, err := json.Marshal(&e)
+		if err != nil || !bytes.Equal(canonical, raw) {
+			return fail("entry is not in canonical form")
+		}
+		if e.Seq != uint64(report.Entries)+1 {
+			return fail("sequence %d, want %d", e.Seq, report.Entries+1)
+		}
+		if e.PrevHash != report.HeadHash {
+			return fail("previous hash does not match entry %d", report.Entries)
+		}
+		want, err := computeHash(&e, key)
+		if err != nil {
+			return fail("%v", err)
+		}
+		if !hmac.Equal([]byte(want), []byte(e.Hash)) {
+			return fail("hash mismatch for entry %d", e.Seq)
+		}
+
+		report.Entries++
+		report.HeadHash = e.Hash
+		if anchor != "" && e.Hash == anchor {
+			report.AnchorSeq = e.Seq
+		}
+		if report.FirstTimestamp == 0 {
+			report.FirstTimestamp = e.Timestamp
+		}
+		report.LastTimestamp = e.Timestamp
+	}
+	if err := sc.Err(); err != nil {
+		report.BrokenLine = line + 1
+		return report, fmt.Errorf("reading audit log: %w", err)
+	}
+	if anchor != "" && report.AnchorSeq == 0 {
+		return report, fmt.Errorf("%w: anchor hash %s not found in chain", ErrTampered, anchor)
+	}
+	return report, nil
+}
+
+// VerifyFile verifies the log at path. See Verify.
+//
+// Inputs:
+//
+//	path - The log file.
+//	key - The HMAC key the log was written with, or nil.
+//	anchor - A head hash recorded earlier, or "". When set, the chain must
+//	         still contain it, which detects truncation and a rebuilt chain
+//	         while allowing entries appended since.
+func VerifyFile(path string, key []byte, anchor string) (*VerifyReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return &VerifyReport{HeadHash: GenesisHash}, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	return verify(f, key, anchor)
+}
+
+// computeHash returns the chain hash of e, ignoring e.Hash.
+func computeHash(e *Entry, key []byte) (string, error) {
+	unsigned := *e
+	unsigned.Hash = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("encoding audit entry: %w", err)
+	}
+
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write([]byte(e.PrevHash))
+	h.Write([]byte{'\n'})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeEntries opens a log at path and records n entries.
+func writeEntries(t *testing.T, path string, opts Options, n int) *Log {
+	t.Helper()
+	l, err := Open(path, opts)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for i := range n {
+		_, err := l.Record(Record{
+			Actor:     "agent",
+			SessionID: "sess-1",
+			Tool:      "write_file",
+			Action:    "file_write",
+			Args:      map[string]any{"path": "main.go", "i": i},
+			Outcome:   OutcomeSuccess,
+			Duration:  5 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	return l
+}
+
+func TestLog_RecordAndVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "agent.jsonl")
+
+	l := writeEntries(t, path, Options{}, 3)
+	seq, head := l.Head()
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	report, err := VerifyFile(path, nil, "")
+	if err != nil {
+		t.Fatalf("VerifyFile: %v", err)
+	}
+	if report.Entries != 3 || seq != 3 {
+		t.Errorf("Entries = %d, seq = %d, want 3", report.Entries, seq)
+	}
+	if report.HeadHash != head {
+		t.Errorf("HeadHash = %s, want %s", report.HeadHash, head)
+	}
+
+	t.Run("reopen continues the chain", func(t *testing.T) {
+		l := writeEntries(t, path, Options{}, 2)
+		defer l.Close()
+		seq, _ := l.Head()
+		if seq != 5 {
+			t.Errorf("seq = %d, want 5", seq)
+		}
+		if report, err := VerifyFile(path, nil, ""); err != nil || report.Entries != 5 {
+			t.Errorf("VerifyFile = %+v, %v; want 5 entries", report, err)
+		}
+	})
+
+	t.Run("anchor must remain in the chain", func(t *testing.T) {
+		if report, err := VerifyFile(path, nil, head); err != nil || report.AnchorSeq != 3 {
+			t.Errorf("VerifyFile = %+v, %v; want anchor at 3", report, err)
+		}
+		if _, err := VerifyFile(path, nil, GenesisHash[1:]+"1"); !errors.Is(err, ErrTampered) {
+			t.Errorf("VerifyFile with unknown anchor error = %v, want ErrTampered", err)
+		}
+	})
+
+	t.Run("arguments are stored as a digest", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte("main.go")) {
+			t.Error("log contains raw arguments")
+		}
+	})
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.jsonl")
+	writeEntries(t, path, Options{}, 3).Close()
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(string(original), "\n"), "\n")
+
+	tests := []struct {
+		name     string
+		mutate   func() string
+		wantLine int
+	}{
+		{
+			name: "edited outcome",
+			mutate: func() string {
+				return strings.Replace(string(original), `"outcome":"success"`, `"outcome":"failure"`, 1)
+			},
+			wantLine: 1,
+		},
+		{
+			name: "deleted entry",
+			mutate: func() string {
+				return lines[0] + lines[2]
+			},
+			wantLine: 2,
+		},
+		{
+			name: "reordered entries",
+			mutate: func() string {
+				return lines[1] + lines[0] + lines[2]
+			},
+			wantLine: 1,
+		},
+		{
+			name: "added field",
+			mutate: func() string {
+				return strings.Replace(lines[0], `{"seq"`, `{"note":"x","seq"`, 1) + lines[1] + lines[2]
+			},
+			wantLine: 1,
+		},
+		{
+			name: "truncated entry",
+			mutate: func() string {
+				return lines[0] + lines[1] + lines[2][:20]
+			},
+			wantLine: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Verify(strings.NewReader(tt.mutate()), nil)
+			if !errors.Is(err, ErrTampered) {
+				t.Fatalf("Verify error = %v, want ErrTampered", err)
+			}
+			if report.BrokenLine != tt.wantLine {
+				t.Errorf("BrokenLine = %d, want %d", report.BrokenLine, tt.wantLine)
+			}
+		})
+	}
+
+	t.Run("open refuses a tampered log", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.jsonl")
+		if err := os.WriteFile(bad, []byte(lines[1]), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(bad, Options{}); !errors.Is(err, ErrTampered) {
+			t.Errorf("Open error = %v, want ErrTampered", err)
+		}
+	})
+}
+
+func TestVerify_Key(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	key := []byte("secret")
+	writeEntries(t, path, Options{Key: key}, 2).Close()
+
+	if _, err := VerifyFile(path, key, ""); err != nil {
+		t.Errorf("VerifyFile with key: %v", err)
+	}
+	if _, err := VerifyFile(path, nil, ""); !errors.Is(err, ErrTampered) {
+		t.Errorf("VerifyFile without key error = %v, want ErrTampered", err)
+	}
+	if _, err := VerifyFile(path, []byte("wrong"), ""); !errors.Is(err, ErrTampered) {
+		t.Errorf("VerifyFile with wrong key error = %v, want ErrTampered", err)
+	}
+}
+
+func TestLog_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	l, err := Open(path, Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.Record(Record{Actor: "agent", Tool: "edit_file", Args: map[string]any{"n": i}, Outcome: OutcomeSuccess}); err != nil {
+				t.Errorf("Record: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	l.Close()
+
+	if _, err := l.Record(Record{Tool: "x"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Record after Close error = %v, want ErrClosed", err)
+	}
+	if report, err := VerifyFile(path, nil, ""); err != nil || report.Entries != 20 {
+		t.Errorf("VerifyFile = %+v, %v; want 20 entries", report, err)
+	}
+}
+
+func TestDigestArgs(t *testing.T) {
+	a, _ := DigestArgs(map[string]any{"path": "a.go", "line": 3})
+	b, _ := DigestArgs(map[string]any{"line": 3, "path": "a.go"})
+	c, _ := DigestArgs(map[string]any{"path": "b.go", "line": 3})
+	if a != b {
+		t.Error("digest depends on map order")
+	}
+	if a == c {
+		t.Error("different arguments share a digest")
+	}
+	if _, err := DigestArgs(map[string]any{"bad": func() {}}); err == nil {
+		t.Error("expected error for unencodable arguments")
+	}
+}
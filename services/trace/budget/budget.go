@@ -0,0 +1,339 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package budget enforces per-API-key rate limits and daily LLM budgets.
+//
+// # Description
+//
+// A Tracker holds, for each key, a request rate limiter and the tokens and
+// estimated cost consumed since the start of the current UTC day. Callers
+// check the budget before dispatching to the LLM (Check) and record the
+// response's token counts afterwards (Record). Budgets reset at midnight UTC.
+//
+// Limits live in the access policy file next to the API keys (see package
+// rbac), keyed by key name:
+//
+//	budgets:
+//	  default:
+//	    daily_tokens: 200000
+//	    requests_per_minute: 30
+//	  keys:
+//	    ci:
+//	      daily_tokens: 2000000
+//	      daily_cost_usd: 5
+//	pricing:
+//	  input_per_mtok: 0.50
+//	  output_per_mtok: 1.50
+//
+// A zero limit is unlimited. Cost is estimated from Pricing, so cost limits
+// have no effect unless pricing is set.
+//
+// # Thread Safety
+//
+// Tracker is safe for concurrent use.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrBudgetExceeded indicates a key has exhausted a budget or rate limit.
+// Errors returned by Tracker wrap it in an *ExceededError.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Resource names the limit that was exceeded.
+type Resource string
+
+const (
+	// ResourceTokens is the daily token budget.
+	ResourceTokens Resource = "tokens"
+
+	// ResourceCost is the daily cost budget in USD.
+	ResourceCost Resource = "cost"
+
+	// ResourceRequests is the per-minute request rate.
+	ResourceRequests Resource = "requests"
+)
+
+// ExceededError describes which budget a key exceeded and when it resets.
+type ExceededError struct {
+	Key      string    `json:"key"`
+	Resource Resource  `json:"resource"`
+	Limit    float64   `json:"limit"`
+	Used     float64   `json:"used"`
+	ResetAt  time.Time `json:"reset_at"`
+}
+
+// Error implements error.
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s %s for key %q (%g of %g), resets at %s",
+		e.Resource, ErrBudgetExceeded, e.Key, e.Used, e.Limit, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Unwrap returns ErrBudgetExceeded.
+func (e *ExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// Limits bounds one key's usage. Zero values are unlimited.
+type Limits struct {
+	DailyTokens       int64   `yaml:"daily_tokens" json:"daily_tokens,omitempty"`
+	DailyCostUSD      float64 `yaml:"daily_cost_usd" json:"daily_cost_usd,omitempty"`
+	RequestsPerMinute int     `yaml:"requests_per_minute" json:"requests_per_minute,omitempty"`
+}
+
+// Pricing estimates LLM cost in USD per million tokens.
+type Pricing struct {
+	InputPerMTok  float64 `yaml:"input_per_mtok"`
+	OutputPerMTok float64 `yaml:"output_per_mtok"`
+}
+
+// Cost returns the estimated cost of a call.
+func (p Pricing) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.InputPerMTok + float64(outputTokens)*p.OutputPerMTok) / 1e6
+}
+
+// Config configures a Tracker.
+type Config struct {
+	// Default applies to keys without an entry in Keys.
+	Default Limits `yaml:"default"`
+
+	// Keys maps key names to their limits.
+	Keys map[string]Limits `yaml:"keys"`
+
+	// Pricing estimates cost from token counts.
+	Pricing Pricing `yaml:"-"`
+}
+
+// limitsFor returns the limits for a key.
+func (c Config) limitsFor(key string) Limits {
+	if l, ok := c.Keys[key]; ok {
+		return l
+	}
+	return c.Default
+}
+
+// Usage is what a key consumed in the current window.
+type Usage struct {
+	Tokens  int64   `json:"tokens"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// Status is a key's budget position.
+type Status struct {
+	Key     string    `json:"key"`
+	Limits  Limits    `json:"limits"`
+	Used    Usage     `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// RemainingTokens returns the tokens left today, or -1 if unlimited.
+func (s Status) RemainingTokens() int64 {
+	if s.Limits.DailyTokens <= 0 {
+		return -1
+	}
+	return max(s.Limits.DailyTokens-s.Used.Tokens, 0)
+}
+
+// RemainingCostUSD returns the cost left today, or -1 if unlimited.
+func (s Status) RemainingCostUSD() float64 {
+	if s.Limits.DailyCostUSD <= 0 {
+		return -1
+	}
+	return math.Max(s.Limits.DailyCostUSD-s.Used.CostUSD, 0)
+}
+
+// exceeded returns the first exhausted daily budget, or nil.
+func (s Status) exceeded() *ExceededError {
+	if s.Limits.DailyTokens > 0 && s.Used.Tokens >= s.Limits.DailyTokens {
+		return &ExceededError{
+			Key: s.Key, Resource: ResourceTokens, ResetAt: s.ResetAt,
+			Limit: float64(s.Limits.DailyTokens), Used: float64(s.Used.Tokens),
+		}
+	}
+	if s.Limits.DailyCostUSD > 0 && s.Used.CostUSD >= s.Limits.DailyCostUSD {
+		return &ExceededError{
+			Key: s.Key, Resource: ResourceCost, ResetAt: s.ResetAt,
+			Limit: s.Limits.DailyCostUSD, Used: s.Used.CostUSD,
+		}
+	}
+	return nil
+}
+
+// keyState is one key's usage window and rate limiter.
+type keyState struct {
+	day     time.Time
+	usage   Usage
+	limiter *rate.Limiter
+}
+
+// Tracker enforces budgets per key.
+type Tracker struct {
+	cfg Config
+	now func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*keyState
+}
+
+// NewTracker creates a tracker.
+func NewTracker(cfg Config) *Tracker {
+	return &Tracker{
+		cfg:  cfg,
+		now:  time.Now,
+		keys: make(map[string]*keyState),
+	}
+}
+
+// policyFile is the budget section of the access policy file.
+type policyFile struct {
+	Budgets Config  `yaml:"budgets"`
+	Pricing Pricing `yaml:"pricing"`
+}
+
+// LoadPolicyFile reads budgets from an access policy file.
+//
+// # Outputs
+//
+//   - *Tracker: The tracker. Never nil on success; a file without a
+//     budgets section yields a tracker with no limits.
+//   - error: Non-nil if the file cannot be read or parsed.
+func LoadPolicyFile(path string) (*Tracker, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading budget policy: %w", err)
+	}
+
+	var f policyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing budget policy: %w", err)
+	}
+	f.Budgets.Pricing = f.Pricing
+	return NewTracker(f.Budgets), nil
+}
+
+// state returns the key's state, rolling the window over at midnight UTC.
+// Caller must hold t.mu.
+func (t *Tracker) state(key string, now time.Time) *keyState {
+	day := now.UTC().Truncate(24 * time.Hour)
+	s, ok := t.keys[key]
+	if !ok {
+		s = &keyState{day: day}
+		if rpm := t.cfg.limitsFor(key).RequestsPerMinute; rpm > 0 {
+			s.limiter = rate.NewLimiter(rate.Limit(float64(rpm)/60), rpm)
+		}
+		t.keys[key] = s
+	}
+	if !s.day.Equal(day) {
+		s.day = day
+		s.usage = Usage{}
+	}
+	return s
+}
+
+// status builds the key's status. Caller must hold t.mu.
+func (t *Tracker) status(key string, s *keyState) Status {
+	return Status{
+		Key:     key,
+		Limits:  t.cfg.limitsFor(key),
+		Used:    s.usage,
+		ResetAt: s.day.Add(24 * time.Hour),
+	}
+}
+
+// Status returns the key's current budget position.
+func (t *Tracker) Status(key string) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status(key, t.state(key, t.now()))
+}
+
+// Check reports whether the key has daily budget left.
+//
+// # Outputs
+//
+//   - Status: The key's budget position.
+//   - error: An *ExceededError (matching ErrBudgetExceeded) if a daily
+//     budget is exhausted.
+func (t *Tracker) Check(key string) (Status, error) {
+	st := t.Status(key)
+	if e := st.exceeded(); e != nil {
+		return st, e
+	}
+	return st, nil
+}
+
+// Allow consumes one request from the key's rate limit.
+//
+// # Outputs
+//
+//   - error: An *ExceededError for ResourceRequests if the key is over its
+//     per-minute rate; ResetAt is when the next request will be allowed.
+func (t *Tracker) Allow(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	s := t.state(key, now)
+	if s.limiter == nil {
+		return nil
+	}
+
+	r := s.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return &ExceededError{
+			Key:      key,
+			Resource: ResourceRequests,
+			Limit:    float64(s.limiter.Burst()),
+			Used:     float64(s.limiter.Burst()),
+			ResetAt:  now.Add(delay),
+		}
+	}
+	return nil
+}
+
+// Record adds a completed LLM call to the key's usage.
+//
+// # Outputs
+//
+//   - Status: The key's budget position after the call.
+func (t *Tracker) Record(key string, inputTokens, outputTokens int) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.state(key, t.now())
+	s.usage.Tokens += int64(inputTokens + outputTokens)
+	s.usage.CostUSD += t.cfg.Pricing.Cost(inputTokens, outputTokens)
+	return t.status(key, s)
+}
+
+// contextKey is the context key for the budget key.
+type contextKey struct{}
+
+// WithKey returns a context charging LLM usage to key.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFrom returns the budget key carried by ctx.
+func KeyFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(contextKey{}).(string)
+	return key, ok && key != ""
+}
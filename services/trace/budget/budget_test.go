@@ -0,0 +1,221 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeClock is an adjustable clock for Tracker.now.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestTracker(cfg Config) (*Tracker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC)}
+	tr := NewTracker(cfg)
+	tr.now = clock.now
+	return tr, clock
+}
+
+func TestTracker_DailyBudget(t *testing.T) {
+	tr, clock := newTestTracker(Config{
+		Default: Limits{DailyTokens: 1000},
+		Keys:    map[string]Limits{"big": {DailyTokens: 50000, DailyCostUSD: 0.01}},
+		Pricing: Pricing{InputPerMTok: 1, OutputPerMTok: 4},
+	})
+
+	st := tr.Record("small", 600, 300)
+	if st.RemainingTokens() != 100 {
+		t.Errorf("RemainingTokens = %d, want 100", st.RemainingTokens())
+	}
+	if _, err := tr.Check("small"); err != nil {
+		t.Errorf("Check under budget: %v", err)
+	}
+
+	tr.Record("small", 50, 50)
+	_, err := tr.Check("small")
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Check error = %v, want *ExceededError", err)
+	}
+	wantReset := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	if exceeded.Resource != ResourceTokens || !exceeded.ResetAt.Equal(wantReset) {
+		t.Errorf("exceeded = %+v, want tokens resetting at %s", exceeded, wantReset)
+	}
+
+	t.Run("cost budget", func(t *testing.T) {
+		// 1000 input * $1/M + 2000 output * $4/M = $0.009
+		tr.Record("big", 1000, 2000)
+		if _, err := tr.Check("big"); err != nil {
+			t.Errorf("Check at $0.009: %v", err)
+		}
+		tr.Record("big", 2000, 0)
+		_, err := tr.Check("big")
+		if !errors.As(err, &exceeded) || exceeded.Resource != ResourceCost {
+			t.Errorf("Check error = %v, want cost exceeded", err)
+		}
+	})
+
+	t.Run("resets at midnight UTC", func(t *testing.T) {
+		clock.t = wantReset.Add(time.Second)
+		if _, err := tr.Check("small"); err != nil {
+			t.Errorf("Check after reset: %v", err)
+		}
+		if st := tr.Status("small"); st.Used.Tokens != 0 {
+			t.Errorf("Used after reset = %+v, want zero", st.Used)
+		}
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		tr := NewTracker(Config{})
+		tr.Record("k", 1e6, 1e6)
+		if _, err := tr.Check("k"); err != nil {
+			t.Errorf("Check with no limits: %v", err)
+		}
+		if st := tr.Status("k"); st.RemainingTokens() != -1 || st.RemainingCostUSD() != -1 {
+			t.Errorf("Remaining = %d, %g; want -1, -1", st.RemainingTokens(), st.RemainingCostUSD())
+		}
+	})
+}
+
+func TestTracker_Allow(t *testing.T) {
+	tr, clock := newTestTracker(Config{Default: Limits{RequestsPerMinute: 2}})
+
+	for i := range 2 {
+		if err := tr.Allow("k"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	err := tr.Allow("k")
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Resource != ResourceRequests {
+		t.Fatalf("third request error = %v, want requests exceeded", err)
+	}
+	if want := clock.t.Add(30 * time.Second); !exceeded.ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %s, want %s", exceeded.ResetAt, want)
+	}
+
+	clock.t = clock.t.Add(30 * time.Second)
+	if err := tr.Allow("k"); err != nil {
+		t.Errorf("request after refill: %v", err)
+	}
+}
+
+func TestLoadPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := `keys:
+  - name: ci
+    sha256: 0000000000000000000000000000000000000000000000000000000000000000
+    role: operator
+budgets:
+  default:
+    daily_tokens: 100
+  keys:
+    ci:
+      daily_tokens: 5000
+pricing:
+  input_per_mtok: 2
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := LoadPolicyFile(path)
+	if err != nil {
+		t.Fatalf("LoadPolicyFile: %v", err)
+	}
+	if got := tr.Status("ci").Limits.DailyTokens; got != 5000 {
+		t.Errorf("ci DailyTokens = %d, want 5000", got)
+	}
+	if got := tr.Status("other").Limits.DailyTokens; got != 100 {
+		t.Errorf("default DailyTokens = %d, want 100", got)
+	}
+	if got := tr.Record("ci", 1e6, 0).Used.CostUSD; got != 2 {
+		t.Errorf("cost = %g, want 2", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy, err := rbac.NewPolicy([]rbac.Key{
+		{Name: "ci", SHA256: rbac.HashKey("ci-key"), Role: rbac.RoleOperator},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, _ := newTestTracker(Config{Default: Limits{DailyTokens: 100}})
+
+	router := gin.New()
+	v1 := router.Group("/v1", rbac.Middleware(policy), Middleware(tr))
+	v1.POST("/codebuddy/agent/run", func(c *gin.Context) {
+		// Stand-in for the budgeted LLM client.
+		key, _ := KeyFrom(c.Request.Context())
+		tr.Record(key, 40, 20)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	v1.GET("/codebuddy/callers", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer ci-key")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/v1/codebuddy/agent/run")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first run status = %d", rec.Code)
+	}
+	if got := rec.Header().Get(HeaderTokensRemaining); got != "40" {
+		t.Errorf("%s = %q, want 40 (after this request's usage)", HeaderTokensRemaining, got)
+	}
+	if got := rec.Header().Get(HeaderReset); got != "2025-06-02T00:00:00Z" {
+		t.Errorf("%s = %q", HeaderReset, got)
+	}
+
+	if rec := do(http.MethodGet, "/v1/codebuddy/callers"); rec.Header().Get(HeaderTokensRemaining) != "" {
+		t.Error("non-LLM route should not report budget")
+	}
+
+	do(http.MethodPost, "/v1/codebuddy/agent/run") // overdraws to 120 tokens
+	rec = do(http.MethodPost, "/v1/codebuddy/agent/run")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	var body BudgetExceededResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != "BUDGET_EXCEEDED" || body.Resource != ResourceTokens || body.ResetAt.IsZero() {
+		t.Errorf("body = %+v", body)
+	}
+	if rec.Header().Get("Retry-After") != "7200" {
+		t.Errorf("Retry-After = %q, want 7200", rec.Header().Get("Retry-After"))
+	}
+	if rec.Header().Get(HeaderTokensRemaining) != "0" {
+		t.Errorf("%s = %q, want 0", HeaderTokensRemaining, rec.Header().Get(HeaderTokensRemaining))
+	}
+}
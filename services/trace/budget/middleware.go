@@ -0,0 +1,163 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package budget
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers exposing the caller's remaining budget.
+const (
+	HeaderTokensRemaining = "X-Budget-Tokens-Remaining"
+	HeaderCostRemaining   = "X-Budget-Cost-Remaining"
+	HeaderReset           = "X-Budget-Reset"
+)
+
+// LLMRoutes lists the route patterns that dispatch to the LLM.
+var LLMRoutes = map[string]bool{
+	"POST /v1/codebuddy/agent/run":      true,
+	"POST /v1/codebuddy/agent/continue": true,
+	"POST /v1/trace/agent/review":       true,
+}
+
+// BudgetExceededResponse is the 429 body for an exhausted budget.
+type BudgetExceededResponse struct {
+	Error    string    `json:"error"`
+	Code     string    `json:"code"`
+	Resource Resource  `json:"resource"`
+	Limit    float64   `json:"limit"`
+	Used     float64   `json:"used"`
+	ResetAt  time.Time `json:"reset_at"`
+}
+
+// NewBudgetExceededResponse converts an error matching ErrBudgetExceeded
+// into the 429 body. ok is false for other errors.
+func NewBudgetExceededResponse(err error) (BudgetExceededResponse, bool) {
+	var e *ExceededError
+	if !errors.As(err, &e) {
+		return BudgetExceededResponse{}, false
+	}
+	return BudgetExceededResponse{
+		Error:    e.Error(),
+		Code:     "BUDGET_EXCEEDED",
+		Resource: e.Resource,
+		Limit:    e.Limit,
+		Used:     e.Used,
+		ResetAt:  e.ResetAt.UTC(),
+	}, true
+}
+
+// Middleware returns gin middleware that enforces budgets on LLMRoutes.
+//
+// # Description
+//
+// Runs after rbac.Middleware. For requests with an authenticated key, the
+// key is attached to the request context (see WithKey) so the budgeted LLM
+// client can charge it. On LLM routes the rate limit and daily budget are
+// checked before the handler runs, and the remaining budget is reported in
+// the X-Budget-* headers of the response.
+//
+// # Responses
+//
+//   - 429 Too Many Requests: BudgetExceededResponse with a Retry-After header.
+func Middleware(t *Tracker) gin.HandlerFunc {
+	logger := slog.Default().With("component", "budget")
+
+	return func(c *gin.Context) {
+		principal, ok := rbac.PrincipalFrom(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		key := principal.Name
+		c.Request = c.Request.WithContext(WithKey(c.Request.Context(), key))
+
+		if !LLMRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		err := t.Allow(key)
+		if err == nil {
+			_, err = t.Check(key)
+		}
+		if err != nil {
+			resp, _ := NewBudgetExceededResponse(err)
+			logger.Warn("request over budget",
+				slog.String("key", key),
+				slog.String("resource", string(resp.Resource)),
+				slog.Time("reset_at", resp.ResetAt))
+			writeHeaders(c.Writer.Header(), t.Status(key))
+			retry := math.Ceil(resp.ResetAt.Sub(t.now()).Seconds())
+			c.Header("Retry-After", strconv.Itoa(int(max(retry, 1))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, resp)
+			return
+		}
+
+		c.Writer = &budgetWriter{ResponseWriter: c.Writer, tracker: t, key: key}
+		c.Next()
+	}
+}
+
+// writeHeaders sets the X-Budget-* headers. Unlimited budgets are omitted.
+func writeHeaders(h http.Header, st Status) {
+	if n := st.RemainingTokens(); n >= 0 {
+		h.Set(HeaderTokensRemaining, strconv.FormatInt(n, 10))
+	}
+	if c := st.RemainingCostUSD(); c >= 0 {
+		h.Set(HeaderCostRemaining, strconv.FormatFloat(c, 'f', 4, 64))
+	}
+	h.Set(HeaderReset, st.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// budgetWriter adds the budget headers when the response is first written,
+// so they reflect usage recorded while the handler ran.
+type budgetWriter struct {
+	gin.ResponseWriter
+	tracker *Tracker
+	key     string
+	done    bool
+}
+
+func (w *budgetWriter) setHeaders() {
+	if !w.done && !w.Written() {
+		w.done = true
+		writeHeaders(w.Header(), w.tracker.Status(w.key))
+	}
+}
+
+func (w *budgetWriter) WriteHeader(code int) {
+	w.setHeaders()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *budgetWriter) WriteHeaderNow() {
+	w.setHeaders()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *budgetWriter) Write(data []byte) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *budgetWriter) WriteString(s string) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/httpcache"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/idempotency"
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyStore holds the responses of keyed mutating requests. It is
+// shared by the graph, job and agent routes; keys are scoped per route.
+var idempotencyStore = idempotency.NewStore(idempotency.DefaultConfig())
+
+// idempotent is the middleware for mutating endpoints that honour the
+// Idempotency-Key header.
+func idempotent() gin.HandlerFunc {
+	return idempotency.Middleware(idempotencyStore)
+}
+
+// graphReadMiddleware returns the middleware for read-only graph endpoints:
+// response compression and ETags keyed by graph generation.
+func (h *Handlers) graphReadMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		httpcache.Compress(httpcache.CompressOptions{}),
+		httpcache.ETag(h.graphVersion),
+	}
+}
+
+// graphVersion is the httpcache.VersionFunc for graph queries.
+//
+// Description:
+//
+//	The graph is named by the graph_id query parameter or, for POST
+//	queries, the graph_id field of the JSON body. The version is the graph
+//	ID with its generation and build time, so it changes whenever the graph
+//	is refreshed, evicted and rebuilt, or the server restarts. Requests for
+//	unknown or expired graphs are not cached; the handler reports the error.
+func (h *Handlers) graphVersion(c *gin.Context, body []byte) (string, bool) {
+	graphID := c.Query("graph_id")
+	if graphID == "" && len(body) > 0 {
+		var req struct {
+			GraphID string `json:"graph_id"`
+		}
+		if json.Unmarshal(body, &req) == nil {
+			graphID = req.GraphID
+		}
+	}
+	if graphID == "" {
+		return "", false
+	}
+
+	cached, err := h.svc.GetGraph(graphID)
+	if err != nil {
+		return "", false
+	}
+	return graphID + ":" + strconv.FormatInt(cached.Generation, 10) + ":" +
+		strconv.FormatInt(cached.BuiltAtMilli, 10), true
+}
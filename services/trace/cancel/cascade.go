@@ -0,0 +1,151 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Failure Cascade
+// -----------------------------------------------------------------------------
+
+// SetCascade sets how this activity reacts to algorithm failures,
+// overriding the session's SessionConfig.Cascade.
+//
+// Description:
+//
+//	The policy applies to failures reported after the call; failures
+//	already counted still count towards EscalateAfter.
+//
+// Inputs:
+//   - config: The cascade configuration.
+//
+// Outputs:
+//   - error: Wraps ErrInvalidConfig if config is invalid.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *ActivityContext) SetCascade(config CascadeConfig) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	a.cascadeMu.Lock()
+	defer a.cascadeMu.Unlock()
+	a.cascade = config
+	return nil
+}
+
+// Cascade returns the activity's cascade configuration.
+func (a *ActivityContext) Cascade() CascadeConfig {
+	a.cascadeMu.Lock()
+	defer a.cascadeMu.Unlock()
+	return a.cascade
+}
+
+// Failures returns how many of the activity's algorithms have failed.
+func (a *ActivityContext) Failures() int {
+	a.cascadeMu.Lock()
+	defer a.cascadeMu.Unlock()
+	return a.failures
+}
+
+// Fail cancels the algorithm because it hit an error it cannot recover
+// from, and reports the failure to the activity's cascade policy.
+//
+// Description:
+//
+//	Algorithms call Fail instead of Cancel so that a misbehaving algorithm
+//	can take down its siblings or its activity if the activity is
+//	configured to. Does nothing if the algorithm is no longer running.
+//
+// Inputs:
+//   - err: The error that caused the failure. Must not be nil.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *AlgorithmContext) Fail(err error) {
+	a.Cancel(CancelReason{
+		Type:      CancelFailure,
+		Message:   err.Error(),
+		Component: a.id,
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+// reportFailure passes the algorithm's failure to its activity, at most
+// once per algorithm.
+func (a *AlgorithmContext) reportFailure(reason CancelReason) {
+	if a.activity == nil || !a.failed.CompareAndSwap(false, true) {
+		return
+	}
+	a.activity.handleFailure(a, reason)
+}
+
+// handleFailure applies the activity's cascade policy to a failed algorithm.
+func (a *ActivityContext) handleFailure(failed *AlgorithmContext, reason CancelReason) {
+	a.cascadeMu.Lock()
+	a.failures++
+	failures := a.failures
+	config := a.cascade
+	a.cascadeMu.Unlock()
+
+	if a.State() != StateRunning {
+		return
+	}
+
+	switch config.Policy {
+	case CancelSiblingsOnFailure:
+		siblingReason := CancelReason{
+			Type:      CancelCascade,
+			Message:   fmt.Sprintf("Sibling algorithm %s failed: %s", failed.name, reason.Message),
+			Component: failed.id,
+			Timestamp: time.Now().UnixMilli(),
+		}
+		cancelled := 0
+		for _, alg := range a.Algorithms() {
+			if alg != failed && alg.State() == StateRunning {
+				alg.Cancel(siblingReason)
+				cancelled++
+			}
+		}
+		a.recordCascade(config.Policy, failed, cancelled)
+
+	case EscalateToParentAfterN:
+		if failures < config.EscalateAfter {
+			return
+		}
+		a.recordCascade(config.Policy, failed, 1)
+		a.Cancel(CancelReason{
+			Type:      CancelCascade,
+			Message:   fmt.Sprintf("%d algorithm failures, last %s: %s", failures, failed.name, reason.Message),
+			Threshold: fmt.Sprintf("failures >= %d", config.EscalateAfter),
+			Component: failed.id,
+			Timestamp: time.Now().UnixMilli(),
+		})
+	}
+}
+
+// recordCascade logs and counts a cascade triggered by failed.
+func (a *ActivityContext) recordCascade(policy CascadePolicy, failed *AlgorithmContext, cancelled int) {
+	if a.controller == nil {
+		return
+	}
+	a.controller.logger.Warn("algorithm failure cascaded",
+		slog.String("activity_id", a.id),
+		slog.String("algorithm_id", failed.id),
+		slog.String("policy", policy.String()),
+		slog.Int("cancelled", cancelled),
+	)
+	if a.controller.metrics != nil {
+		a.controller.metrics.CascadeTotal.WithLabelValues(policy.String()).Inc()
+	}
+}
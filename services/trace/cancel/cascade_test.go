@@ -0,0 +1,163 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCascade_Policies(t *testing.T) {
+	_, session := newCheckpointTestSession(t, "cascade")
+
+	t.Run("isolate by default", func(t *testing.T) {
+		activity := session.NewActivity("isolate")
+		a := activity.NewAlgorithm("a", time.Minute)
+		b := activity.NewAlgorithm("b", time.Minute)
+
+		a.Fail(errors.New("boom"))
+
+		if reason := a.getCancelReason(); reason == nil || reason.Type != CancelFailure || reason.Message != "boom" {
+			t.Errorf("failed algorithm reason = %+v, want failure boom", reason)
+		}
+		if b.State() != StateRunning || activity.State() != StateRunning {
+			t.Error("an isolated failure should not affect the sibling or activity")
+		}
+		if activity.Failures() != 1 {
+			t.Errorf("Failures = %d, want 1", activity.Failures())
+		}
+	})
+
+	t.Run("cancel siblings", func(t *testing.T) {
+		activity := session.NewActivity("siblings")
+		if err := activity.SetCascade(CascadeConfig{Policy: CancelSiblingsOnFailure}); err != nil {
+			t.Fatal(err)
+		}
+		a := activity.NewAlgorithm("a", time.Minute)
+		b := activity.NewAlgorithm("b", time.Minute)
+		c := activity.NewAlgorithm("c", time.Minute)
+		c.MarkDone()
+
+		a.Cancel(CancelReason{Type: CancelDeadlock, Message: "no progress"})
+
+		reason := b.getCancelReason()
+		if reason == nil || reason.Type != CancelCascade || reason.Component != a.ID() {
+			t.Errorf("sibling reason = %+v, want cascade from %s", reason, a.ID())
+		}
+		if c.State() != StateDone {
+			t.Error("a finished sibling should not be cancelled")
+		}
+		if activity.State() != StateRunning {
+			t.Error("CancelSiblingsOnFailure should keep the activity running")
+		}
+		if activity.Failures() != 1 {
+			t.Errorf("cascaded cancellations counted as failures: Failures = %d", activity.Failures())
+		}
+	})
+
+	t.Run("escalate after N", func(t *testing.T) {
+		activity := session.NewActivity("escalate")
+		if err := activity.SetCascade(CascadeConfig{Policy: EscalateToParentAfterN, EscalateAfter: 2}); err != nil {
+			t.Fatal(err)
+		}
+		a := activity.NewAlgorithm("a", time.Minute)
+		b := activity.NewAlgorithm("b", time.Minute)
+		c := activity.NewAlgorithm("c", time.Minute)
+
+		a.Fail(errors.New("first"))
+		a.Fail(errors.New("repeat"))
+		if activity.State() != StateRunning || activity.Failures() != 1 {
+			t.Fatalf("after one failure: state %v, failures %d", activity.State(), activity.Failures())
+		}
+
+		b.Fail(errors.New("second"))
+		reason := activity.getCancelReason()
+		if activity.State() == StateRunning || reason == nil || reason.Type != CancelCascade || reason.Threshold != "failures >= 2" {
+			t.Errorf("activity state %v reason %+v, want cascade at 2 failures", activity.State(), reason)
+		}
+		if r := c.getCancelReason(); r == nil || r.Type != CancelParent {
+			t.Errorf("remaining algorithm reason = %+v, want parent", r)
+		}
+		if session.State() != StateRunning {
+			t.Error("escalation should stop at the activity")
+		}
+	})
+
+	t.Run("external cancellation is not a failure", func(t *testing.T) {
+		activity := session.NewActivity("external")
+		activity.SetCascade(CascadeConfig{Policy: CancelSiblingsOnFailure})
+		a := activity.NewAlgorithm("a", time.Minute)
+		b := activity.NewAlgorithm("b", time.Minute)
+
+		a.Cancel(CancelReason{Type: CancelUser})
+
+		if b.State() != StateRunning || activity.Failures() != 0 {
+			t.Error("a user cancellation should not cascade")
+		}
+	})
+}
+
+func TestCascade_DeadlineExpiry(t *testing.T) {
+	ctrl, err := NewController(ControllerConfig{}, nil)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	defer ctrl.Close()
+
+	session, err := ctrl.NewSession(context.Background(), SessionConfig{
+		ID:      "deadline",
+		Cascade: CascadeConfig{Policy: CancelSiblingsOnFailure},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	activity := session.NewActivity("search")
+	if activity.Cascade().Policy != CancelSiblingsOnFailure {
+		t.Fatalf("activity cascade = %+v, want the session default", activity.Cascade())
+	}
+	activity.NewAlgorithm("short", 10*time.Millisecond)
+	long := activity.NewAlgorithm("long", time.Minute)
+
+	waitFor(t, "sibling cancellation", func() bool { return long.State() != StateRunning })
+	if reason := long.getCancelReason(); reason == nil || reason.Type != CancelCascade {
+		t.Errorf("sibling reason = %+v, want cascade", reason)
+	}
+}
+
+func TestCascadeConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CascadeConfig
+		wantErr bool
+	}{
+		{"zero", CascadeConfig{}, false},
+		{"siblings", CascadeConfig{Policy: CancelSiblingsOnFailure}, false},
+		{"escalate", CascadeConfig{Policy: EscalateToParentAfterN, EscalateAfter: 3}, false},
+		{"escalate without N", CascadeConfig{Policy: EscalateToParentAfterN}, true},
+		{"negative N", CascadeConfig{EscalateAfter: -1}, true},
+		{"unknown policy", CascadeConfig{Policy: CascadePolicy(9)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, session := newCheckpointTestSession(t, "validate")
+	if err := session.NewActivity("x").SetCascade(CascadeConfig{Policy: EscalateToParentAfterN}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("SetCascade error = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+var (
+	// ErrNoCheckpoint is returned by Resume when an activity has no checkpoints.
+	ErrNoCheckpoint = errors.New("no checkpoint for activity")
+
+	// ErrActivityRunning is returned by Resume when the activity is still running.
+	ErrActivityRunning = errors.New("activity is still running")
+)
+
+// -----------------------------------------------------------------------------
+// Types
+// -----------------------------------------------------------------------------
+
+// Checkpointer captures an algorithm's state so it can resume after
+// cancellation instead of restarting.
+//
+// Description:
+//
+//	Checkpoint is called when the algorithm is cancelled by a timeout or a
+//	resource limit. It may run concurrently with the algorithm, including
+//	after the algorithm's context is done, so it must return a consistent
+//	snapshot of the latest state. The returned bytes are handed back to
+//	the resumed algorithm unchanged via ResumeState.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type Checkpointer interface {
+	Checkpoint() ([]byte, error)
+}
+
+// CheckpointerFunc adapts a function to the Checkpointer interface.
+type CheckpointerFunc func() ([]byte, error)
+
+// Checkpoint calls f.
+func (f CheckpointerFunc) Checkpoint() ([]byte, error) {
+	return f()
+}
+
+// Checkpoint is the persisted state of one algorithm.
+type Checkpoint struct {
+	// ActivityID is the full ID of the activity ("session/activity").
+	ActivityID string
+
+	// Algorithm is the algorithm name within the activity.
+	Algorithm string
+
+	// Data is the state returned by the algorithm's Checkpointer.
+	Data []byte
+
+	// Reason is the cancellation that triggered the checkpoint.
+	Reason CancelReason
+
+	// CreatedAt is when the checkpoint was taken (Unix milliseconds UTC).
+	CreatedAt int64
+}
+
+// CheckpointStore persists checkpoints between cancellation and resume.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Save stores a checkpoint, replacing any earlier checkpoint for the
+	// same activity and algorithm.
+	Save(cp Checkpoint) error
+
+	// Load returns the checkpoints of an activity, empty if there are none.
+	Load(activityID string) ([]Checkpoint, error)
+
+	// Delete removes the checkpoints of an activity.
+	Delete(activityID string) error
+}
+
+// -----------------------------------------------------------------------------
+// Memory Store
+// -----------------------------------------------------------------------------
+
+// MemoryCheckpointStore is an in-process CheckpointStore. It is the
+// controller's default; checkpoints do not survive a restart.
+//
+// Thread Safety: Safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]map[string]Checkpoint // activity ID -> algorithm -> checkpoint
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]map[string]Checkpoint)}
+}
+
+// Save stores a checkpoint.
+func (m *MemoryCheckpointStore) Save(cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.checkpoints[cp.ActivityID] == nil {
+		m.checkpoints[cp.ActivityID] = make(map[string]Checkpoint)
+	}
+	cp.Data = append([]byte(nil), cp.Data...)
+	m.checkpoints[cp.ActivityID][cp.Algorithm] = cp
+	return nil
+}
+
+// Load returns the checkpoints of an activity.
+func (m *MemoryCheckpointStore) Load(activityID string) ([]Checkpoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]Checkpoint, 0, len(m.checkpoints[activityID]))
+	for _, cp := range m.checkpoints[activityID] {
+		result = append(result, cp)
+	}
+	return result, nil
+}
+
+// Delete removes the checkpoints of an activity.
+func (m *MemoryCheckpointStore) Delete(activityID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.checkpoints, activityID)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Checkpointing
+// -----------------------------------------------------------------------------
+
+// isResumable reports whether a cancellation of this type checkpoints.
+// User, shutdown and deadlock cancellations do not: the work was either
+// unwanted or stuck.
+func isResumable(t CancelType) bool {
+	return t == CancelTimeout || t == CancelResourceLimit || t == CancelResourceCPU
+}
+
+// SetCheckpointer registers the algorithm's checkpointer.
+//
+// Description:
+//
+//	Once set, a timeout or resource-limit cancellation of the algorithm,
+//	its activity or its session persists a checkpoint to the controller's
+//	CheckpointStore. Pass nil to unregister.
+//
+// Inputs:
+//   - cp: The checkpointer.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *AlgorithmContext) SetCheckpointer(cp Checkpointer) {
+	a.checkpointMu.Lock()
+	defer a.checkpointMu.Unlock()
+	a.checkpointer = cp
+}
+
+// ResumeState returns the checkpoint data this algorithm resumes from.
+//
+// Outputs:
+//   - []byte: The data saved by the previous run's Checkpointer.
+//   - bool: False if the algorithm was not resumed from a checkpoint.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *AlgorithmContext) ResumeState() ([]byte, bool) {
+	if a.resumeFrom == nil {
+		return nil, false
+	}
+	return a.resumeFrom.Data, true
+}
+
+// Cancel cancels this algorithm, checkpointing it first if the reason is
+// a timeout or resource limit. Failures are then reported to the
+// activity's cascade policy.
+func (a *AlgorithmContext) Cancel(reason CancelReason) {
+	if isResumable(reason.Type) && a.State() == StateRunning {
+		a.checkpoint(reason)
+	}
+	if a.baseContext.tryCancel(reason) && reason.Type.IsFailure() {
+		a.reportFailure(reason)
+	}
+}
+
+// checkpoint saves the algorithm's checkpoint, at most once per run.
+func (a *AlgorithmContext) checkpoint(reason CancelReason) {
+	a.checkpointMu.Lock()
+	cp := a.checkpointer
+	a.checkpointMu.Unlock()
+	if cp == nil || a.controller == nil || !a.checkpointed.CompareAndSwap(false, true) {
+		return
+	}
+
+	data, err := cp.Checkpoint()
+	if err != nil {
+		a.controller.logger.Warn("checkpoint failed",
+			slog.String("id", a.id),
+			slog.String("error", err.Error()),
+		)
+		a.controller.recordCheckpoint("error")
+		return
+	}
+	if reason.Timestamp == 0 {
+		reason.Timestamp = time.Now().UnixMilli()
+	}
+	a.controller.saveCheckpoint(Checkpoint{
+		ActivityID: a.activity.id,
+		Algorithm:  a.name,
+		Data:       data,
+		Reason:     reason,
+		CreatedAt:  time.Now().UnixMilli(),
+	})
+}
+
+// watchDeadline checkpoints the algorithm and reports the failure when its
+// own timeout expires without an explicit Cancel call.
+func (a *AlgorithmContext) watchDeadline(deadlineCtx context.Context, timeout time.Duration) {
+	context.AfterFunc(deadlineCtx, func() {
+		if !errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) || a.State() != StateRunning {
+			return
+		}
+		reason := CancelReason{
+			Type:      CancelTimeout,
+			Message:   "Algorithm timeout exceeded",
+			Threshold: timeout.String(),
+			Component: a.id,
+			Timestamp: time.Now().UnixMilli(),
+		}
+		a.checkpoint(reason)
+		a.reportFailure(reason)
+	})
+}
+
+// checkpointAlgorithms checkpoints every running algorithm of the activity.
+func (a *ActivityContext) checkpointAlgorithms(reason CancelReason) {
+	for _, alg := range a.Algorithms() {
+		if alg.State() == StateRunning {
+			alg.checkpoint(reason)
+		}
+	}
+}
+
+// Resume restarts an activity from its checkpoints.
+//
+// Description:
+//
+//	Creates a new activity context in this session in place of the
+//	cancelled one. Algorithms created in it with the names of checkpointed
+//	algorithms receive their saved state through ResumeState. The
+//	checkpoints are consumed; a resumed run that is cancelled again saves
+//	new ones. The session may be a new session with the same ID as the one
+//	that was cancelled, provided both use the same controller or store.
+//
+// Inputs:
+//   - activityID: The activity's full ID ("session/activity") or its name.
+//
+// Outputs:
+//   - *ActivityContext: The resumed activity. Never nil on success.
+//   - error: ErrNoCheckpoint if the activity has no checkpoints,
+//     ErrActivityRunning if it is still running, ErrAlreadyCancelled if
+//     the session is no longer running, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+//
+// Example:
+//
+//	activity, err := session.Resume("search")
+//	if errors.Is(err, cancel.ErrNoCheckpoint) {
+//	    activity = session.NewActivity("search")
+//	}
+//	alg := activity.NewAlgorithm("pnmcts", 5*time.Second)
+//	if state, ok := alg.ResumeState(); ok {
+//	    tree.Restore(state)
+//	}
+func (s *SessionContext) Resume(activityID string) (*ActivityContext, error) {
+	name := strings.TrimPrefix(activityID, s.id+"/")
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("%w: %s", ErrActivityNotFound, activityID)
+	}
+	fullID := s.id + "/" + name
+
+	if s.State() != StateRunning {
+		return nil, fmt.Errorf("session %s: %w", s.id, ErrAlreadyCancelled)
+	}
+	if existing := s.Activity(name); existing != nil && existing.State() == StateRunning {
+		return nil, fmt.Errorf("%w: %s", ErrActivityRunning, fullID)
+	}
+	if s.controller == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoCheckpoint, fullID)
+	}
+
+	checkpoints, err := s.controller.checkpoints.Load(fullID)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoints for %s: %w", fullID, err)
+	}
+	if len(checkpoints) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoCheckpoint, fullID)
+	}
+
+	resume := make(map[string]*Checkpoint, len(checkpoints))
+	for i := range checkpoints {
+		resume[checkpoints[i].Algorithm] = &checkpoints[i]
+	}
+	activity := s.newActivity(name, resume)
+
+	if err := s.controller.checkpoints.Delete(fullID); err != nil {
+		s.controller.logger.Warn("failed to delete consumed checkpoints",
+			slog.String("activity_id", fullID),
+			slog.String("error", err.Error()),
+		)
+	}
+	s.controller.logger.Info("activity resumed from checkpoint",
+		slog.String("activity_id", fullID),
+		slog.Int("algorithms", len(checkpoints)),
+	)
+	if s.controller.metrics != nil {
+		s.controller.metrics.ResumesTotal.Inc()
+	}
+	return activity, nil
+}
+
+// saveCheckpoint persists a checkpoint and records the outcome.
+func (c *CancellationController) saveCheckpoint(cp Checkpoint) {
+	if err := c.checkpoints.Save(cp); err != nil {
+		c.logger.Warn("failed to save checkpoint",
+			slog.String("activity_id", cp.ActivityID),
+			slog.String("algorithm", cp.Algorithm),
+			slog.String("error", err.Error()),
+		)
+		c.recordCheckpoint("error")
+		return
+	}
+	c.logger.Info("checkpoint saved",
+		slog.String("activity_id", cp.ActivityID),
+		slog.String("algorithm", cp.Algorithm),
+		slog.String("reason", cp.Reason.Type.String()),
+		slog.Int("bytes", len(cp.Data)),
+	)
+	c.recordCheckpoint("saved")
+}
+
+// recordCheckpoint counts a checkpoint attempt.
+func (c *CancellationController) recordCheckpoint(result string) {
+	if c.metrics != nil {
+		c.metrics.CheckpointsTotal.WithLabelValues(result).Inc()
+	}
+}
+
+// Checkpoints returns the stored checkpoints of an activity.
+//
+// Inputs:
+//   - activityID: The activity's full ID ("session/activity").
+//
+// Outputs:
+//   - []Checkpoint: The checkpoints, empty if there are none.
+//   - error: Non-nil if the store fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *CancellationController) Checkpoints(activityID string) ([]Checkpoint, error) {
+	return c.checkpoints.Load(activityID)
+}
+
+// -----------------------------------------------------------------------------
+// Helper Functions
+// -----------------------------------------------------------------------------
+
+// SetCheckpointer registers a checkpointer from within an algorithm.
+//
+// Description:
+//
+//	Looks up the algorithm context carried by ctx and registers cp on it.
+//	Algorithms that only receive a context.Context use this instead of
+//	AlgorithmContext.SetCheckpointer.
+//
+// Inputs:
+//   - ctx: The context passed to the algorithm.
+//   - cp: The checkpointer.
+//
+// Outputs:
+//   - bool: False if ctx does not belong to an algorithm context.
+func SetCheckpointer(ctx context.Context, cp Checkpointer) bool {
+	alg, ok := ctx.Value(algorithmKey).(*AlgorithmContext)
+	if !ok {
+		return false
+	}
+	alg.SetCheckpointer(cp)
+	return true
+}
+
+// ResumeState returns the checkpoint data the algorithm in ctx resumes from.
+//
+// Outputs:
+//   - []byte: The saved state.
+//   - bool: False if ctx does not belong to a resumed algorithm.
+func ResumeState(ctx context.Context) ([]byte, bool) {
+	alg, ok := ctx.Value(algorithmKey).(*AlgorithmContext)
+	if !ok {
+		return nil, false
+	}
+	return alg.ResumeState()
+}
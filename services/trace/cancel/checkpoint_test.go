@@ -0,0 +1,180 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counterCheckpointer checkpoints a counter and counts its calls.
+type counterCheckpointer struct {
+	value atomic.Int64
+	calls atomic.Int32
+}
+
+func (c *counterCheckpointer) Checkpoint() ([]byte, error) {
+	c.calls.Add(1)
+	return []byte{byte(c.value.Load())}, nil
+}
+
+func newCheckpointTestSession(t *testing.T, id string) (*CancellationController, *SessionContext) {
+	t.Helper()
+	ctrl, err := NewController(ControllerConfig{}, nil)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	t.Cleanup(func() { ctrl.Close() })
+
+	session, err := ctrl.NewSession(context.Background(), SessionConfig{ID: id})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	return ctrl, session
+}
+
+func TestCheckpoint_ResourceLimitThenResume(t *testing.T) {
+	ctrl, session := newCheckpointTestSession(t, "s1")
+
+	activity := session.NewActivity("search")
+	alg := activity.NewAlgorithm("pnmcts", time.Minute)
+	cp := &counterCheckpointer{}
+	cp.value.Store(42)
+	if !SetCheckpointer(alg.Context(), cp) {
+		t.Fatal("SetCheckpointer should find the algorithm in its context")
+	}
+
+	session.Cancel(CancelReason{Type: CancelResourceLimit, Message: "Memory limit exceeded"})
+
+	checkpoints, _ := ctrl.Checkpoints("s1/search")
+	if len(checkpoints) != 1 || checkpoints[0].Algorithm != "pnmcts" || checkpoints[0].Data[0] != 42 {
+		t.Fatalf("checkpoints = %+v, want one for pnmcts with state 42", checkpoints)
+	}
+	if checkpoints[0].Reason.Type != CancelResourceLimit {
+		t.Errorf("checkpoint reason = %s, want resource_limit", checkpoints[0].Reason.Type)
+	}
+	if cp.calls.Load() != 1 {
+		t.Errorf("checkpointer called %d times, want 1", cp.calls.Load())
+	}
+
+	// The cancelled session cannot host the activity again; a new session
+	// with the same ID resumes it.
+	if _, err := session.Resume("search"); !errors.Is(err, ErrAlreadyCancelled) {
+		t.Errorf("Resume on cancelled session: err = %v", err)
+	}
+	next, err := ctrl.NewSession(context.Background(), SessionConfig{ID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := next.Resume("s1/search")
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	resumedAlg := resumed.NewAlgorithm("pnmcts", time.Minute)
+	state, ok := ResumeState(resumedAlg.Context())
+	if !ok || state[0] != 42 {
+		t.Errorf("ResumeState = %v, %v; want [42], true", state, ok)
+	}
+	if _, ok := resumed.NewAlgorithm("zobrist", time.Minute).ResumeState(); ok {
+		t.Error("an algorithm without a checkpoint should start fresh")
+	}
+
+	t.Run("checkpoints are consumed", func(t *testing.T) {
+		resumed.Cancel(CancelReason{Type: CancelUser})
+		if _, err := next.Resume("search"); !errors.Is(err, ErrNoCheckpoint) {
+			t.Errorf("second Resume: err = %v, want ErrNoCheckpoint", err)
+		}
+	})
+}
+
+func TestCheckpoint_TimeoutDeadline(t *testing.T) {
+	ctrl, session := newCheckpointTestSession(t, "s2")
+
+	alg := session.NewActivity("search").NewAlgorithm("tms", 20*time.Millisecond)
+	cp := &counterCheckpointer{}
+	cp.value.Store(7)
+	alg.SetCheckpointer(cp)
+
+	<-alg.Done()
+	deadline := time.Now().Add(time.Second)
+	for cp.calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	checkpoints, _ := ctrl.Checkpoints("s2/search")
+	if len(checkpoints) != 1 || checkpoints[0].Reason.Type != CancelTimeout {
+		t.Fatalf("checkpoints = %+v, want one timeout checkpoint", checkpoints)
+	}
+
+	// An explicit timeout cancel afterwards does not checkpoint again.
+	alg.Cancel(CancelReason{Type: CancelTimeout})
+	if cp.calls.Load() != 1 {
+		t.Errorf("checkpointer called %d times, want 1", cp.calls.Load())
+	}
+}
+
+func TestCheckpoint_NonResumableReasons(t *testing.T) {
+	for _, reasonType := range []CancelType{CancelUser, CancelDeadlock, CancelShutdown} {
+		t.Run(reasonType.String(), func(t *testing.T) {
+			ctrl, session := newCheckpointTestSession(t, "s3")
+			activity := session.NewActivity("search")
+			cp := &counterCheckpointer{}
+			activity.NewAlgorithm("a", time.Minute).SetCheckpointer(cp)
+
+			activity.Cancel(CancelReason{Type: reasonType})
+
+			if cp.calls.Load() != 0 {
+				t.Error("checkpointer should not run")
+			}
+			if checkpoints, _ := ctrl.Checkpoints("s3/search"); len(checkpoints) != 0 {
+				t.Errorf("checkpoints = %+v, want none", checkpoints)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_ResumeErrors(t *testing.T) {
+	ctrl, session := newCheckpointTestSession(t, "s4")
+	session.NewActivity("running")
+
+	if _, err := session.Resume("running"); !errors.Is(err, ErrActivityRunning) {
+		t.Errorf("running activity: err = %v", err)
+	}
+	if _, err := session.Resume("missing"); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("no checkpoint: err = %v", err)
+	}
+	if _, err := session.Resume("other/activity"); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("other session: err = %v", err)
+	}
+
+	t.Run("failing checkpointer saves nothing", func(t *testing.T) {
+		activity := session.NewActivity("failing")
+		activity.NewAlgorithm("a", time.Minute).SetCheckpointer(CheckpointerFunc(func() ([]byte, error) {
+			return nil, errors.New("boom")
+		}))
+		activity.Cancel(CancelReason{Type: CancelTimeout})
+		if checkpoints, _ := ctrl.Checkpoints("s4/failing"); len(checkpoints) != 0 {
+			t.Errorf("checkpoints = %+v, want none", checkpoints)
+		}
+	})
+}
+
+func TestCheckpoint_HelpersWithoutAlgorithm(t *testing.T) {
+	if SetCheckpointer(context.Background(), &counterCheckpointer{}) {
+		t.Error("SetCheckpointer should report false outside an algorithm")
+	}
+	if _, ok := ResumeState(context.Background()); ok {
+		t.Error("ResumeState should report false outside an algorithm")
+	}
+}
@@ -85,9 +85,15 @@ func (b *baseContext) Err() error {
 
 // Cancel initiates cancellation with the given reason.
 func (b *baseContext) Cancel(reason CancelReason) {
+	b.tryCancel(reason)
+}
+
+// tryCancel initiates cancellation and reports whether this call moved the
+// context out of StateRunning.
+func (b *baseContext) tryCancel(reason CancelReason) bool {
 	// Only transition from Running to Cancelling
 	if !b.state.CompareAndSwap(int32(StateRunning), int32(StateCancelling)) {
-		return // Already cancelling or terminal
+		return false // Already cancelling or terminal
 	}
 
 	// Store the reason
@@ -100,6 +106,7 @@ func (b *baseContext) Cancel(reason CancelReason) {
 
 	// Cancel the context
 	b.cancel()
+	return true
 }
 
 // markDone marks the context as done (normal completion).
@@ -240,6 +247,12 @@ func newSessionContext(parent context.Context, config SessionConfig, ctrl *Cance
 //
 // Thread Safety: Safe for concurrent use.
 func (s *SessionContext) NewActivity(name string) *ActivityContext {
+	return s.newActivity(name, nil)
+}
+
+// newActivity creates an activity, resuming its algorithms from resume
+// (keyed by algorithm name) if it is non-nil.
+func (s *SessionContext) newActivity(name string, resume map[string]*Checkpoint) *ActivityContext {
 	s.activitiesMu.Lock()
 	defer s.activitiesMu.Unlock()
 
@@ -259,6 +272,8 @@ func (s *SessionContext) NewActivity(name string) *ActivityContext {
 		name:       name,
 		session:    s,
 		algorithms: make(map[string]*AlgorithmContext),
+		resume:     resume,
+		cascade:    s.config.Cascade,
 	}
 
 	a.state.Store(int32(StateRunning))
@@ -297,6 +312,8 @@ func (s *SessionContext) Activities() []*ActivityContext {
 }
 
 // Cancel cancels this session and all its activities and algorithms.
+// Timeout and resource-limit cancellations checkpoint running algorithms
+// first.
 func (s *SessionContext) Cancel(reason CancelReason) {
 	// Cancel all children first
 	s.activitiesMu.RLock()
@@ -306,6 +323,12 @@ func (s *SessionContext) Cancel(reason CancelReason) {
 	}
 	s.activitiesMu.RUnlock()
 
+	if isResumable(reason.Type) && s.State() == StateRunning {
+		for _, a := range activities {
+			a.checkpointAlgorithms(reason)
+		}
+	}
+
 	childReason := CancelReason{
 		Type:      CancelParent,
 		Message:   fmt.Sprintf("Parent session cancelled: %s", reason.Message),
@@ -361,6 +384,14 @@ type ActivityContext struct {
 	// Child algorithms
 	algorithms   map[string]*AlgorithmContext
 	algorithmsMu sync.RWMutex
+
+	// Checkpoints to resume algorithms from, keyed by name (nil if not resumed)
+	resume map[string]*Checkpoint
+
+	// Failure cascade policy and the number of failed algorithms so far
+	cascade   CascadeConfig
+	failures  int
+	cascadeMu sync.Mutex
 }
 
 // Name returns the activity name.
@@ -415,17 +446,27 @@ func (a *ActivityContext) NewAlgorithm(name string, timeout time.Duration) *Algo
 			parent:     a,
 			controller: a.controller,
 		},
-		name:     name,
-		activity: a,
-		timeout:  timeout,
+		name:       name,
+		activity:   a,
+		timeout:    timeout,
+		resumeFrom: a.resume[name],
 	}
 
 	alg.state.Store(int32(StateRunning))
 	alg.lastProgress.Store(time.Now().UnixNano())
+	if a.session != nil {
+		alg.cpuBudget.Store(int64(a.session.resourceLimits.MaxAlgorithmCPUTime))
+	}
 
-	// Update context with new ID and progress reporter
+	// Checkpoint if the deadline expires without an explicit cancel
+	if deadline, ok := ctx.Deadline(); ok {
+		alg.watchDeadline(ctx, time.Until(deadline).Round(time.Millisecond))
+	}
+
+	// Update context with new ID, progress reporter and algorithm
 	alg.ctx = context.WithValue(alg.ctx, contextIDKey, alg.id)
 	alg.ctx = context.WithValue(alg.ctx, progressReporterKey, ProgressReporter(alg.ReportProgress))
+	alg.ctx = context.WithValue(alg.ctx, algorithmKey, alg)
 
 	a.algorithms[name] = alg
 
@@ -457,7 +498,13 @@ func (a *ActivityContext) Algorithms() []*AlgorithmContext {
 }
 
 // Cancel cancels this activity and all its algorithms.
+// Timeout and resource-limit cancellations checkpoint running algorithms
+// first.
 func (a *ActivityContext) Cancel(reason CancelReason) {
+	if isResumable(reason.Type) && a.State() == StateRunning {
+		a.checkpointAlgorithms(reason)
+	}
+
 	// Cancel all children first
 	a.algorithmsMu.RLock()
 	algorithms := make([]*AlgorithmContext, 0, len(a.algorithms))
@@ -508,6 +555,20 @@ type AlgorithmContext struct {
 	name     string
 	activity *ActivityContext
 	timeout  time.Duration
+
+	// Checkpointing
+	checkpointer Checkpointer
+	checkpointMu sync.Mutex
+	checkpointed atomic.Bool
+	resumeFrom   *Checkpoint // nil if not resumed
+
+	// CPU accounting, in nanoseconds
+	cpuBudget atomic.Int64
+	cpuUsed   atomic.Int64
+
+	// failed is set once the algorithm's failure has been reported to the
+	// activity's cascade policy
+	failed atomic.Bool
 }
 
 // Name returns the algorithm name.
@@ -525,6 +586,33 @@ func (a *AlgorithmContext) Timeout() time.Duration {
 	return a.timeout
 }
 
+// SetCPUBudget sets the CPU time the algorithm may consume before it is
+// cancelled with CancelResourceCPU, overriding the session's
+// MaxAlgorithmCPUTime. Zero removes the budget.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *AlgorithmContext) SetCPUBudget(budget time.Duration) {
+	a.cpuBudget.Store(int64(max(budget, 0)))
+}
+
+// CPUBudget returns the algorithm's CPU time budget, zero if unlimited.
+func (a *AlgorithmContext) CPUBudget() time.Duration {
+	return time.Duration(a.cpuBudget.Load())
+}
+
+// CPUTime returns the CPU time charged to the algorithm so far.
+//
+// CPU time is sampled for the whole process and split evenly among the
+// algorithms running at each sample, so this is an estimate.
+func (a *AlgorithmContext) CPUTime() time.Duration {
+	return time.Duration(a.cpuUsed.Load())
+}
+
+// chargeCPU adds d to the algorithm's CPU time and returns the new total.
+func (a *AlgorithmContext) chargeCPU(d time.Duration) time.Duration {
+	return time.Duration(a.cpuUsed.Add(int64(d)))
+}
+
 // Status returns the current status.
 func (a *AlgorithmContext) Status() Status {
 	return a.baseStatus()
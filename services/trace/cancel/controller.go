@@ -39,12 +39,18 @@ type CancellationController struct {
 	// Resource monitor
 	resourceMonitor *ResourceMonitor
 
+	// CPU time source for budgets and MaxCPUPercent (nil if unavailable)
+	cpuSampler CPUSampler
+
 	// Shutdown coordination
 	closed     bool
 	closedMu   sync.RWMutex
 	shutdownCh chan struct{}
 	shutdownWg sync.WaitGroup
 
+	// Checkpoints taken on resumable cancellations
+	checkpoints CheckpointStore
+
 	// Metrics
 	metrics *Metrics
 }
@@ -97,6 +103,11 @@ func NewController(config ControllerConfig, logger *slog.Logger) (*CancellationC
 		shutdownCh: make(chan struct{}),
 	}
 
+	c.checkpoints = config.CheckpointStore
+	if c.checkpoints == nil {
+		c.checkpoints = NewMemoryCheckpointStore()
+	}
+
 	// Initialize metrics
 	if config.EnableMetrics {
 		c.metrics = NewMetrics()
@@ -108,10 +119,25 @@ func NewController(config ControllerConfig, logger *slog.Logger) (*CancellationC
 	// Initialize resource monitor (but don't start - started per session with limits)
 	c.resourceMonitor = NewResourceMonitor(c)
 
+	c.cpuSampler = config.CPUSampler
+	if c.cpuSampler == nil {
+		sampler, err := NewCPUSampler()
+		if err != nil {
+			c.logger.Debug("cpu sampling unavailable, CPU limits disabled", slog.String("error", err.Error()))
+		} else {
+			c.cpuSampler = sampler
+		}
+	}
+
 	// Start background workers
 	c.shutdownWg.Add(1)
 	go c.deadlockDetector.Run(c.shutdownCh, &c.shutdownWg)
 
+	if c.cpuSampler != nil {
+		c.shutdownWg.Add(1)
+		go newCPUAccountant(c, c.cpuSampler, config.ProgressCheckInterval).Run(c.shutdownCh, &c.shutdownWg)
+	}
+
 	return c, nil
 }
 
@@ -0,0 +1,318 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// CPU Samplers
+// -----------------------------------------------------------------------------
+
+// ErrCPUSamplingUnsupported is returned when no CPU time source is available.
+var ErrCPUSamplingUnsupported = errors.New("cpu time sampling is not supported on this platform")
+
+// CPUSampler reports cumulative CPU time consumed.
+//
+// Go cannot attribute CPU time to individual goroutines, so samplers measure
+// the whole process (or its container) and the controller apportions each
+// sample's delta among the algorithms running at that moment.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type CPUSampler interface {
+	// CPUTime returns the cumulative user+system CPU time.
+	CPUTime() (time.Duration, error)
+
+	// CPUs returns the number of CPUs the measured workload may use, for
+	// converting CPU time into a utilization percentage.
+	CPUs() float64
+}
+
+// ProcessCPUSampler samples the CPU time of the current process via
+// getrusage(2).
+type ProcessCPUSampler struct{}
+
+// CPUs returns the number of logical CPUs usable by the process.
+func (ProcessCPUSampler) CPUs() float64 {
+	return float64(runtime.GOMAXPROCS(0))
+}
+
+// CgroupCPUSampler samples a cgroup v2 cpu.stat file.
+//
+// Inside a container the cgroup covers the container's processes, and
+// cpu.max reflects the CPU quota the orchestrator granted, which is a
+// better denominator for utilization than the host's CPU count.
+type CgroupCPUSampler struct {
+	dir string
+}
+
+// NewCgroupCPUSampler creates a sampler for the cgroup v2 directory dir.
+//
+// Description:
+//
+//	Verifies that dir contains a readable cpu.stat with a usage_usec field.
+//
+// Inputs:
+//   - dir: The cgroup directory, e.g. "/sys/fs/cgroup".
+//
+// Outputs:
+//   - *CgroupCPUSampler: The sampler. Nil on error.
+//   - error: Non-nil if cpu.stat is missing or malformed.
+func NewCgroupCPUSampler(dir string) (*CgroupCPUSampler, error) {
+	s := &CgroupCPUSampler{dir: dir}
+	if _, err := s.CPUTime(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CPUTime returns the cgroup's cumulative CPU usage.
+func (s *CgroupCPUSampler) CPUTime() (time.Duration, error) {
+	f, err := os.Open(filepath.Join(s.dir, "cpu.stat"))
+	if err != nil {
+		return 0, fmt.Errorf("reading cgroup cpu.stat: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || key != "usage_usec" {
+			continue
+		}
+		usec, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parsing cgroup usage_usec %q: %w", value, err)
+		}
+		return time.Duration(usec) * time.Microsecond, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading cgroup cpu.stat: %w", err)
+	}
+	return 0, errors.New("cgroup cpu.stat has no usage_usec field")
+}
+
+// CPUs returns the cgroup's CPU quota from cpu.max, falling back to
+// GOMAXPROCS when the cgroup is unlimited.
+func (s *CgroupCPUSampler) CPUs() float64 {
+	data, err := os.ReadFile(filepath.Join(s.dir, "cpu.max"))
+	if err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, qErr := strconv.ParseFloat(fields[0], 64)
+			period, pErr := strconv.ParseFloat(fields[1], 64)
+			if qErr == nil && pErr == nil && quota > 0 && period > 0 {
+				return quota / period
+			}
+		}
+	}
+	return float64(runtime.GOMAXPROCS(0))
+}
+
+// NewCPUSampler picks the best available CPU time source.
+//
+// Description:
+//
+//	Uses the cgroup v2 sampler when the process runs in its own cgroup
+//	namespace (a container, where /proc/self/cgroup reports "0::/"), and
+//	the process sampler otherwise. On a host, the process's cgroup is
+//	usually shared with other processes and would overcount.
+//
+// Outputs:
+//   - CPUSampler: The sampler. Nil on error.
+//   - error: ErrCPUSamplingUnsupported if neither source is available.
+func NewCPUSampler() (CPUSampler, error) {
+	if data, err := os.ReadFile("/proc/self/cgroup"); err == nil && strings.TrimSpace(string(data)) == "0::/" {
+		if s, err := NewCgroupCPUSampler("/sys/fs/cgroup"); err == nil {
+			return s, nil
+		}
+	}
+	var p ProcessCPUSampler
+	if _, err := p.CPUTime(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// -----------------------------------------------------------------------------
+// CPU Utilization
+// -----------------------------------------------------------------------------
+
+// cpuWindowMin is the shortest window MaxCPUPercent is evaluated over, so
+// brief bursts between two monitor ticks do not cancel a session.
+const cpuWindowMin = time.Second
+
+// cpuWindow measures CPU utilization between successive evaluations.
+//
+// Thread Safety: Not safe for concurrent use; owned by one monitor loop.
+type cpuWindow struct {
+	sampler   CPUSampler
+	startCPU  time.Duration
+	startWall time.Time
+	now       func() time.Time
+}
+
+// newCPUWindow starts a window at the sampler's current CPU time.
+func newCPUWindow(sampler CPUSampler) *cpuWindow {
+	w := &cpuWindow{sampler: sampler, now: time.Now}
+	w.startCPU, _ = sampler.CPUTime()
+	w.startWall = w.now()
+	return w
+}
+
+// percent returns the utilization since the window started, as a
+// percentage of the sampler's CPUs, and starts a new window. ok is false
+// until cpuWindowMin has elapsed or if sampling fails.
+func (w *cpuWindow) percent() (percent float64, ok bool) {
+	wall := w.now()
+	elapsed := wall.Sub(w.startWall)
+	if elapsed < cpuWindowMin {
+		return 0, false
+	}
+	cpu, err := w.sampler.CPUTime()
+	if err != nil {
+		return 0, false
+	}
+	used := cpu - w.startCPU
+	w.startCPU, w.startWall = cpu, wall
+
+	cpus := w.sampler.CPUs()
+	if cpus <= 0 {
+		cpus = 1
+	}
+	return 100 * used.Seconds() / (elapsed.Seconds() * cpus), true
+}
+
+// -----------------------------------------------------------------------------
+// CPU Accountant
+// -----------------------------------------------------------------------------
+
+// cpuAccountant charges sampled CPU time to running algorithms and cancels
+// those that exceed their CPU budget.
+//
+// Each sample's delta is split evenly among the algorithms running at that
+// moment. This is an approximation: an algorithm blocked on I/O is charged
+// the same share as a busy one.
+//
+// Thread Safety: Run must only be called once.
+type cpuAccountant struct {
+	controller *CancellationController
+	sampler    CPUSampler
+	interval   time.Duration
+	logger     *slog.Logger
+	last       time.Duration
+}
+
+// newCPUAccountant creates an accountant sampling at interval, starting
+// from the sampler's current CPU time.
+func newCPUAccountant(controller *CancellationController, sampler CPUSampler, interval time.Duration) *cpuAccountant {
+	c := &cpuAccountant{
+		controller: controller,
+		sampler:    sampler,
+		interval:   interval,
+		logger:     controller.logger.With(slog.String("subsystem", "cpu_accountant")),
+	}
+	c.last, _ = sampler.CPUTime()
+	return c
+}
+
+// Run samples CPU time until stopCh is closed.
+func (c *cpuAccountant) Run(stopCh <-chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			c.sample()
+		}
+	}
+}
+
+// sample charges the CPU time used since the previous sample.
+func (c *cpuAccountant) sample() {
+	now, err := c.sampler.CPUTime()
+	if err != nil {
+		c.logger.Debug("cpu sample failed", slog.String("error", err.Error()))
+		return
+	}
+	delta := now - c.last
+	c.last = now
+	if delta <= 0 {
+		return
+	}
+
+	algorithms := c.controller.runningAlgorithms()
+	if len(algorithms) == 0 {
+		return
+	}
+	share := delta / time.Duration(len(algorithms))
+
+	for _, alg := range algorithms {
+		used := alg.chargeCPU(share)
+		if c.controller.metrics != nil {
+			c.controller.metrics.CPUSecondsTotal.WithLabelValues(alg.Name()).Add(share.Seconds())
+		}
+
+		budget := alg.CPUBudget()
+		if budget <= 0 || used <= budget {
+			continue
+		}
+
+		c.logger.Warn("cpu time budget exceeded",
+			slog.String("algorithm_id", alg.ID()),
+			slog.Duration("used", used),
+			slog.Duration("budget", budget),
+		)
+
+		alg.Cancel(CancelReason{
+			Type:      CancelResourceCPU,
+			Message:   "CPU time budget exceeded",
+			Threshold: budget.String(),
+			Component: alg.ID(),
+			Timestamp: time.Now().UnixMilli(),
+		})
+
+		if c.controller.metrics != nil {
+			c.controller.metrics.CPUBudgetExceededTotal.WithLabelValues(alg.Name()).Inc()
+			c.controller.metrics.ResourceLimitExceededTotal.WithLabelValues("cpu_time", alg.ID()).Inc()
+		}
+	}
+}
+
+// runningAlgorithms returns the registered algorithms that are still running.
+func (c *CancellationController) runningAlgorithms() []*AlgorithmContext {
+	c.contextsMu.RLock()
+	defer c.contextsMu.RUnlock()
+
+	var result []*AlgorithmContext
+	for _, ctx := range c.contexts {
+		if alg, ok := ctx.(*AlgorithmContext); ok && alg.State() == StateRunning {
+			result = append(result, alg)
+		}
+	}
+	return result
+}
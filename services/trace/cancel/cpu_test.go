@@ -0,0 +1,196 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCPUSampler reports CPU time advanced explicitly by the test.
+type fakeCPUSampler struct {
+	used atomic.Int64
+	cpus float64
+}
+
+func (f *fakeCPUSampler) CPUTime() (time.Duration, error) {
+	return time.Duration(f.used.Load()), nil
+}
+
+func (f *fakeCPUSampler) CPUs() float64 { return f.cpus }
+
+func (f *fakeCPUSampler) advance(d time.Duration) { f.used.Add(int64(d)) }
+
+// waitFor polls cond until it holds or a second passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCPUBudget_CancelsAndCheckpoints(t *testing.T) {
+	sampler := &fakeCPUSampler{cpus: 1}
+	ctrl, err := NewController(ControllerConfig{
+		CPUSampler:            sampler,
+		ProgressCheckInterval: 5 * time.Millisecond,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	defer ctrl.Close()
+
+	session, err := ctrl.NewSession(context.Background(), SessionConfig{
+		ID:             "cpu",
+		ResourceLimits: ResourceLimits{MaxAlgorithmCPUTime: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	activity := session.NewActivity("search")
+	budgeted := activity.NewAlgorithm("budgeted", time.Minute)
+	unlimited := activity.NewAlgorithm("unlimited", time.Minute)
+	unlimited.SetCPUBudget(0)
+	cp := &counterCheckpointer{}
+	budgeted.SetCheckpointer(cp)
+
+	if budgeted.CPUBudget() != 50*time.Millisecond {
+		t.Errorf("CPUBudget = %v, want the session's 50ms", budgeted.CPUBudget())
+	}
+
+	// 60ms split between two algorithms stays within the 50ms budget.
+	sampler.advance(60 * time.Millisecond)
+	waitFor(t, "first charge", func() bool { return budgeted.CPUTime() >= 30*time.Millisecond })
+	if budgeted.State() != StateRunning {
+		t.Fatalf("algorithm cancelled at %v of a 50ms budget", budgeted.CPUTime())
+	}
+
+	sampler.advance(60 * time.Millisecond)
+	waitFor(t, "budget cancellation", func() bool { return budgeted.State() != StateRunning })
+
+	reason := budgeted.getCancelReason()
+	if reason == nil || reason.Type != CancelResourceCPU || reason.Threshold != "50ms" {
+		t.Errorf("cancel reason = %+v, want resource_cpu at 50ms", reason)
+	}
+	if unlimited.State() != StateRunning {
+		t.Error("an algorithm without a budget should keep running")
+	}
+	if checkpoints, _ := ctrl.Checkpoints("cpu/search"); len(checkpoints) != 1 || checkpoints[0].Reason.Type != CancelResourceCPU {
+		t.Errorf("checkpoints = %+v, want one resource_cpu checkpoint", checkpoints)
+	}
+
+	t.Run("only running algorithms are charged", func(t *testing.T) {
+		before := budgeted.CPUTime()
+		sampler.advance(40 * time.Millisecond)
+		waitFor(t, "charge", func() bool { return unlimited.CPUTime() >= 100*time.Millisecond })
+		if budgeted.CPUTime() != before {
+			t.Errorf("cancelled algorithm charged %v more", budgeted.CPUTime()-before)
+		}
+	})
+}
+
+func TestCgroupCPUSampler(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := NewCgroupCPUSampler(dir); err == nil {
+		t.Error("missing cpu.stat should fail")
+	}
+	write("cpu.stat", "nr_periods 0\n")
+	if _, err := NewCgroupCPUSampler(dir); err == nil {
+		t.Error("cpu.stat without usage_usec should fail")
+	}
+
+	write("cpu.stat", "usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\n")
+	s, err := NewCgroupCPUSampler(dir)
+	if err != nil {
+		t.Fatalf("NewCgroupCPUSampler: %v", err)
+	}
+	if got, _ := s.CPUTime(); got != 1500*time.Millisecond {
+		t.Errorf("CPUTime = %v, want 1.5s", got)
+	}
+
+	write("cpu.max", "200000 100000\n")
+	if got := s.CPUs(); got != 2 {
+		t.Errorf("CPUs with quota = %v, want 2", got)
+	}
+	write("cpu.max", "max 100000\n")
+	if got := s.CPUs(); got != float64(runtime.GOMAXPROCS(0)) {
+		t.Errorf("CPUs unlimited = %v, want GOMAXPROCS", got)
+	}
+}
+
+func TestCPUWindow_MaxCPUPercent(t *testing.T) {
+	sampler := &fakeCPUSampler{cpus: 2}
+	clock := time.Unix(0, 0)
+	w := newCPUWindow(sampler)
+	w.now = func() time.Time { return clock }
+	w.startWall = clock
+
+	monitor := &ResourceMonitor{}
+	limits := ResourceLimits{MaxCPUPercent: 75}
+
+	// Too short a window is not evaluated.
+	clock = clock.Add(100 * time.Millisecond)
+	sampler.advance(200 * time.Millisecond)
+	if v := monitor.checkLimits(nil, limits, w); v != nil {
+		t.Fatalf("violation before the window elapsed: %+v", v)
+	}
+
+	// 1.2s of CPU over 1s on 2 CPUs is 60%.
+	clock = clock.Add(900 * time.Millisecond)
+	sampler.advance(time.Second)
+	if v := monitor.checkLimits(nil, limits, w); v != nil {
+		t.Fatalf("violation at 60%%: %+v", v)
+	}
+
+	// 1.8s over the next second is 90%.
+	clock = clock.Add(time.Second)
+	sampler.advance(1800 * time.Millisecond)
+	v := monitor.checkLimits(nil, limits, w)
+	if v == nil || v.cancelType != CancelResourceCPU || v.current != "90.00%" {
+		t.Errorf("violation = %+v, want resource_cpu at 90.00%%", v)
+	}
+}
+
+func TestProcessCPUSampler(t *testing.T) {
+	var s ProcessCPUSampler
+	first, err := s.CPUTime()
+	if err != nil {
+		t.Skipf("process CPU sampling unsupported: %v", err)
+	}
+	// Burn a little CPU so the counter advances.
+	deadline := time.Now().Add(20 * time.Millisecond)
+	for x := 0; time.Now().Before(deadline); x++ {
+		_ = x * x
+	}
+	second, _ := s.CPUTime()
+	if second < first {
+		t.Errorf("CPU time went backwards: %v then %v", first, second)
+	}
+	if s.CPUs() < 1 {
+		t.Errorf("CPUs = %v, want >= 1", s.CPUs())
+	}
+}
@@ -31,16 +31,42 @@
 //	└── ...
 //
 // Cancelling a parent context automatically cancels all children, but children
-// can be cancelled independently without affecting siblings or parents.
+// can be cancelled independently without affecting siblings or parents
+// unless the activity's cascade policy says otherwise.
 //
 // # Cancellation Triggers
 //
-// Four types of cancellation are supported:
+// Six types of cancellation are supported:
 //
 //   - User-initiated: Explicit cancel via API, Ctrl+C, or stop button
 //   - Timeout: Algorithm exceeds its configured Timeout() duration
 //   - Deadlock: No progress reported for 3x the ProgressInterval
-//   - Resource limit: Memory or CPU threshold exceeded
+//   - Resource limit: Memory or goroutine threshold exceeded
+//   - Resource CPU: Algorithm CPU-time budget or session CPU utilization exceeded
+//   - Failure: Algorithm reports an unrecoverable error with Fail
+//
+// # Cascade Policies
+//
+// Timeouts, deadlocks, resource limits and Fail are algorithm failures.
+// Each activity decides what a failure does to the rest of it
+// (SessionConfig.Cascade, overridden per activity with SetCascade):
+//
+//   - IsolateFailure (default): only the failed algorithm stops
+//   - CancelSiblingsOnFailure: the other running algorithms are cancelled
+//   - EscalateToParentAfterN: the activity is cancelled after N failures
+//
+// Contexts cancelled by a cascade get CancelCascade, which is not itself a
+// failure, so cascades never chain.
+//
+// # CPU Limits
+//
+// CPU time is sampled from the process via getrusage, or from the cgroup v2
+// cpu.stat when running in a container, every ProgressCheckInterval. Go
+// cannot attribute CPU to goroutines, so each sample is split evenly among
+// the running algorithms. An algorithm whose charged time exceeds its
+// budget (ResourceLimits.MaxAlgorithmCPUTime, or SetCPUBudget) is cancelled
+// with CancelResourceCPU; MaxCPUPercent cancels the whole session when
+// utilization averaged over at least a second exceeds the limit.
 //
 // # Graceful Shutdown Protocol
 //
@@ -80,6 +106,38 @@
 //	    return result, delta, nil
 //	}
 //
+// # Deadlock Diagnostics
+//
+// Algorithms run inside AlgorithmContext.Do (or the package-level Do) get
+// goroutine labels naming their session, activity and algorithm. When the
+// deadlock detector trips, it captures the stacks of the labelled
+// goroutines belonging to the stalled context and attaches them to
+// CancelReason.Stacks, so a hung algorithm can be diagnosed after it has
+// been cancelled:
+//
+//	cancel.Do(ctx, func(ctx context.Context) {
+//	    out, delta, err = a.Process(ctx, snapshot, input)
+//	})
+//
+// # Checkpoint and Resume
+//
+// Algorithms that can continue from saved state register a Checkpointer.
+// When a timeout, resource limit or CPU limit cancels the algorithm, its activity or
+// its session, the controller persists a checkpoint to its CheckpointStore
+// (in-memory by default), and the activity can later be resumed instead of
+// restarted:
+//
+//	cancel.SetCheckpointer(ctx, cancel.CheckpointerFunc(tree.Snapshot))
+//
+//	// Later, possibly in a new session with the same ID
+//	activity, err := session.Resume("search")
+//	algoCtx := activity.NewAlgorithm("pnmcts", 5*time.Second)
+//	if state, ok := algoCtx.ResumeState(); ok {
+//	    tree.Restore(state)
+//	}
+//
+// User, deadlock and shutdown cancellations do not checkpoint.
+//
 // # Thread Safety
 //
 // All exported types in this package are safe for concurrent use.
@@ -94,6 +152,9 @@
 //   - deadlock_detected_total: Counter of deadlock detections by component
 //   - resource_limit_exceeded_total: Counter of resource violations
 //   - partial_results_collected: Counter of partial results saved
+//   - cpu_seconds_total: Counter of CPU seconds charged by algorithm name
+//   - cpu_budget_exceeded_total: Counter of CPU budget cancellations by algorithm name
+//   - cascade_total: Counter of failures that cascaded, by cascade policy
 //
 // # Usage
 //
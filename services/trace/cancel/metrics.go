@@ -54,6 +54,23 @@ type Metrics struct {
 
 	// ProgressReportsTotal counts progress reports by component.
 	ProgressReportsTotal *prometheus.CounterVec
+
+	// CheckpointsTotal counts checkpoints taken on cancellation by result.
+	CheckpointsTotal *prometheus.CounterVec
+
+	// ResumesTotal counts activities resumed from checkpoints.
+	ResumesTotal prometheus.Counter
+
+	// CPUSecondsTotal counts CPU seconds charged to algorithms by name.
+	CPUSecondsTotal *prometheus.CounterVec
+
+	// CPUBudgetExceededTotal counts algorithms cancelled for exceeding
+	// their CPU time budget, by name.
+	CPUBudgetExceededTotal *prometheus.CounterVec
+
+	// CascadeTotal counts algorithm failures that cancelled siblings or an
+	// activity, by cascade policy.
+	CascadeTotal *prometheus.CounterVec
 }
 
 // NewMetrics creates and registers all cancellation metrics.
@@ -185,6 +202,55 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"component"},
 		),
+
+		CheckpointsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "code_buddy",
+				Subsystem: "cancel",
+				Name:      "checkpoints_total",
+				Help:      "Total checkpoints taken on cancellation by result (saved, error)",
+			},
+			[]string{"result"},
+		),
+
+		ResumesTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "code_buddy",
+				Subsystem: "cancel",
+				Name:      "resumes_total",
+				Help:      "Total activities resumed from checkpoints",
+			},
+		),
+
+		CPUSecondsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "code_buddy",
+				Subsystem: "cancel",
+				Name:      "cpu_seconds_total",
+				Help:      "Estimated CPU seconds charged to algorithms by algorithm name",
+			},
+			[]string{"algorithm"},
+		),
+
+		CPUBudgetExceededTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "code_buddy",
+				Subsystem: "cancel",
+				Name:      "cpu_budget_exceeded_total",
+				Help:      "Total algorithms cancelled for exceeding their CPU time budget by algorithm name",
+			},
+			[]string{"algorithm"},
+		),
+
+		CascadeTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "code_buddy",
+				Subsystem: "cancel",
+				Name:      "cascade_total",
+				Help:      "Total algorithm failures that cancelled siblings or their activity by cascade policy",
+			},
+			[]string{"policy"},
+		),
 	}
 }
 
@@ -24,7 +24,9 @@ import (
 // DeadlockDetector monitors contexts for progress and detects deadlocks.
 //
 // A deadlock is detected when a context has not reported progress for
-// DeadlockMultiplier * ProgressInterval.
+// DeadlockMultiplier * ProgressInterval. The stacks of the context's
+// labelled goroutines are attached to the CancelReason (see
+// AlgorithmContext.Do).
 //
 // Thread Safety: Safe for concurrent use.
 type DeadlockDetector struct {
@@ -127,11 +129,20 @@ func (d *DeadlockDetector) checkAllContexts() {
 
 		elapsed := time.Duration(now-lastProgress) * time.Millisecond
 		if elapsed > threshold {
+			// Capture stacks before cancelling, while the goroutines are
+			// still where they hung
+			var stacks string
+			var goroutines int
+			if label, ok := stackLabel(ctx); ok {
+				stacks, goroutines = captureStacks(label, ctx.ID())
+			}
+
 			d.logger.Warn("deadlock detected",
 				slog.String("id", ctx.ID()),
 				slog.String("level", ctx.Level().String()),
 				slog.Duration("elapsed", elapsed),
 				slog.Duration("threshold", threshold),
+				slog.Int("goroutines", goroutines),
 			)
 
 			reason := CancelReason{
@@ -140,6 +151,7 @@ func (d *DeadlockDetector) checkAllContexts() {
 				Threshold: threshold.String(),
 				Component: ctx.ID(),
 				Timestamp: now,
+				Stacks:    stacks,
 			}
 
 			ctx.Cancel(reason)
@@ -215,6 +227,17 @@ func (m *ResourceMonitor) MonitorSession(session *SessionContext, stopCh <-chan
 		slog.Int("max_goroutines", limits.MaxGoroutines),
 	)
 
+	var cpu *cpuWindow
+	if limits.MaxCPUPercent > 0 {
+		if m.controller.cpuSampler != nil {
+			cpu = newCPUWindow(m.controller.cpuSampler)
+		} else {
+			m.logger.Warn("cpu sampling unavailable, MaxCPUPercent not enforced",
+				slog.String("session_id", session.ID()),
+			)
+		}
+	}
+
 	for {
 		select {
 		case <-stopCh:
@@ -228,7 +251,7 @@ func (m *ResourceMonitor) MonitorSession(session *SessionContext, stopCh <-chan
 			)
 			return
 		case <-ticker.C:
-			if violation := m.checkLimits(session, limits); violation != nil {
+			if violation := m.checkLimits(session, limits, cpu); violation != nil {
 				m.logger.Warn("resource limit exceeded",
 					slog.String("session_id", session.ID()),
 					slog.String("resource", violation.resource),
@@ -237,7 +260,7 @@ func (m *ResourceMonitor) MonitorSession(session *SessionContext, stopCh <-chan
 				)
 
 				reason := CancelReason{
-					Type:      CancelResourceLimit,
+					Type:      violation.cancelType,
 					Message:   violation.message,
 					Threshold: violation.limit,
 					Component: session.ID(),
@@ -261,14 +284,16 @@ func (m *ResourceMonitor) MonitorSession(session *SessionContext, stopCh <-chan
 
 // resourceViolation describes a resource limit violation.
 type resourceViolation struct {
-	resource string
-	current  string
-	limit    string
-	message  string
+	resource   string
+	current    string
+	limit      string
+	message    string
+	cancelType CancelType
 }
 
 // checkLimits checks if any resource limits are exceeded.
-func (m *ResourceMonitor) checkLimits(session *SessionContext, limits ResourceLimits) *resourceViolation {
+// cpu is nil when MaxCPUPercent is not enforced.
+func (m *ResourceMonitor) checkLimits(session *SessionContext, limits ResourceLimits, cpu *cpuWindow) *resourceViolation {
 	// Check memory
 	if limits.MaxMemoryBytes > 0 {
 		var memStats runtime.MemStats
@@ -276,10 +301,11 @@ func (m *ResourceMonitor) checkLimits(session *SessionContext, limits ResourceLi
 
 		if int64(memStats.Alloc) > limits.MaxMemoryBytes {
 			return &resourceViolation{
-				resource: "memory",
-				current:  formatBytes(int64(memStats.Alloc)),
-				limit:    formatBytes(limits.MaxMemoryBytes),
-				message:  "Memory limit exceeded",
+				resource:   "memory",
+				current:    formatBytes(int64(memStats.Alloc)),
+				limit:      formatBytes(limits.MaxMemoryBytes),
+				message:    "Memory limit exceeded",
+				cancelType: CancelResourceLimit,
 			}
 		}
 	}
@@ -289,17 +315,27 @@ func (m *ResourceMonitor) checkLimits(session *SessionContext, limits ResourceLi
 		numGoroutines := runtime.NumGoroutine()
 		if numGoroutines > limits.MaxGoroutines {
 			return &resourceViolation{
-				resource: "goroutines",
-				current:  formatInt(numGoroutines),
-				limit:    formatInt(limits.MaxGoroutines),
-				message:  "Goroutine limit exceeded",
+				resource:   "goroutines",
+				current:    formatInt(numGoroutines),
+				limit:      formatInt(limits.MaxGoroutines),
+				message:    "Goroutine limit exceeded",
+				cancelType: CancelResourceLimit,
 			}
 		}
 	}
 
-	// Note: CPU percentage monitoring is more complex and requires sampling
-	// over time. For now, we focus on memory and goroutines which are
-	// instantaneously measurable.
+	// Check CPU utilization, averaged over the sampling window
+	if cpu != nil {
+		if percent, ok := cpu.percent(); ok && percent > limits.MaxCPUPercent {
+			return &resourceViolation{
+				resource:   "cpu",
+				current:    formatFloat64(percent) + "%",
+				limit:      formatFloat64(limits.MaxCPUPercent) + "%",
+				message:    "CPU limit exceeded",
+				cancelType: CancelResourceCPU,
+			}
+		}
+	}
 
 	return nil
 }
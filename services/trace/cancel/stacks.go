@@ -0,0 +1,152 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Goroutine Labels
+// -----------------------------------------------------------------------------
+
+// Goroutine label keys set by AlgorithmContext.Do. The deadlock detector
+// uses them to find the goroutines of a context that stopped progressing.
+const (
+	LabelSession   = "cancel_session"
+	LabelActivity  = "cancel_activity"
+	LabelAlgorithm = "cancel_algorithm"
+)
+
+// maxStackBytes caps the stacks attached to a CancelReason.
+const maxStackBytes = 64 * 1024
+
+// labels returns the goroutine labels identifying the algorithm.
+func (a *AlgorithmContext) labels() pprof.LabelSet {
+	args := []string{LabelAlgorithm, a.id}
+	if a.activity != nil {
+		args = append(args, LabelActivity, a.activity.id)
+		if a.activity.session != nil {
+			args = append(args, LabelSession, a.activity.session.id)
+		}
+	}
+	return pprof.Labels(args...)
+}
+
+// Do runs fn on the calling goroutine labelled with the algorithm's
+// session, activity and algorithm IDs.
+//
+// Description:
+//
+//	Goroutines started by fn inherit the labels. If the algorithm or one
+//	of its parents is cancelled for lack of progress, the stacks of all
+//	labelled goroutines are attached to the CancelReason, so algorithms
+//	should run their work inside Do to be diagnosable when they hang.
+//
+// Inputs:
+//   - fn: The algorithm body. Receives the algorithm's context.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *AlgorithmContext) Do(fn func(ctx context.Context)) {
+	pprof.Do(a.Context(), a.labels(), fn)
+}
+
+// Do runs fn labelled as the algorithm carried by ctx.
+//
+// Description:
+//
+//	Algorithms that only receive a context.Context use this instead of
+//	AlgorithmContext.Do. If ctx does not belong to an algorithm context,
+//	fn runs unlabelled.
+//
+// Inputs:
+//   - ctx: The context passed to the algorithm.
+//   - fn: The algorithm body.
+func Do(ctx context.Context, fn func(ctx context.Context)) {
+	alg, ok := ctx.Value(algorithmKey).(*AlgorithmContext)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	pprof.Do(ctx, alg.labels(), fn)
+}
+
+// stackLabel returns the goroutine label that identifies c's goroutines.
+func stackLabel(c Cancellable) (string, bool) {
+	switch c.(type) {
+	case *SessionContext:
+		return LabelSession, true
+	case *ActivityContext:
+		return LabelActivity, true
+	case *AlgorithmContext:
+		return LabelAlgorithm, true
+	}
+	return "", false
+}
+
+// captureStacks returns the stacks of the goroutines labelled key=value
+// and how many goroutines they cover.
+//
+// Description:
+//
+//	Reads the goroutine profile in its debug=1 text form, which groups
+//	goroutines with identical stacks and prints their labels, and keeps
+//	the groups with a matching label. The result is truncated to
+//	maxStackBytes.
+func captureStacks(key, value string) (string, int) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return "", 0
+	}
+	return filterStacks(buf.String(), key, value)
+}
+
+// filterStacks keeps the records of a debug=1 goroutine profile that carry
+// the label key=value.
+func filterStacks(profile, key, value string) (string, int) {
+	want := fmt.Sprintf("%q:%q", key, value)
+
+	var (
+		out   strings.Builder
+		count int
+	)
+	for _, record := range strings.Split(profile, "\n\n") {
+		record = strings.TrimSpace(record)
+		if !hasLabel(record, want) {
+			continue
+		}
+		var n int
+		fmt.Sscanf(record, "%d @", &n)
+		count += n
+
+		if out.Len()+len(record) > maxStackBytes {
+			out.WriteString("... (truncated)\n")
+			break
+		}
+		out.WriteString(record)
+		out.WriteString("\n\n")
+	}
+	return out.String(), count
+}
+
+// hasLabel reports whether a profile record's labels line contains want.
+func hasLabel(record, want string) bool {
+	for _, line := range strings.Split(record, "\n") {
+		if labels, ok := strings.CutPrefix(line, "# labels: "); ok {
+			return strings.Contains(labels, want)
+		}
+	}
+	return false
+}
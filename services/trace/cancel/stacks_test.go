@@ -0,0 +1,126 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package cancel
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadlockDetector_CapturesStacks(t *testing.T) {
+	ctrl, err := NewController(ControllerConfig{
+		ProgressCheckInterval: 10 * time.Millisecond,
+		DeadlockMultiplier:    2,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	defer ctrl.Close()
+
+	session, _ := ctrl.NewSession(context.Background(), SessionConfig{
+		ID:               "stacks",
+		ProgressInterval: 20 * time.Millisecond,
+	})
+	activity := session.NewActivity("activity")
+	algo := activity.NewAlgorithm("algo", 5*time.Second)
+
+	// A hung algorithm: blocked on something other than ctx.Done()
+	release := make(chan struct{})
+	defer close(release)
+	go algo.Do(func(ctx context.Context) {
+		<-release
+	})
+
+	select {
+	case <-algo.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Deadlock should have been detected")
+	}
+
+	// Whichever level tripped first carries the stacks
+	var reason *CancelReason
+	for _, status := range []Status{session.Status(), activity.Status(), algo.Status()} {
+		if status.CancelReason != nil && status.CancelReason.Type == CancelDeadlock {
+			reason = status.CancelReason
+			break
+		}
+	}
+	if reason == nil {
+		t.Fatal("no context was cancelled for deadlock")
+	}
+	if !strings.Contains(reason.Stacks, "TestDeadlockDetector_CapturesStacks") {
+		t.Errorf("Stacks do not contain the hung goroutine:\n%s", reason.Stacks)
+	}
+	if !strings.Contains(reason.Stacks, `"cancel_algorithm":"stacks/activity/algo"`) {
+		t.Errorf("Stacks do not carry the algorithm label:\n%s", reason.Stacks)
+	}
+}
+
+func TestDo_Labels(t *testing.T) {
+	_, session := newCheckpointTestSession(t, "labels")
+	algo := session.NewActivity("a").NewAlgorithm("b", time.Minute)
+
+	var got map[string]string
+	Do(algo.Context(), func(ctx context.Context) {
+		got = map[string]string{}
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			got[key] = value
+			return true
+		})
+	})
+	want := map[string]string{
+		LabelSession:   session.ID(),
+		LabelActivity:  algo.Activity().ID(),
+		LabelAlgorithm: algo.ID(),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("label %s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	ran := false
+	Do(context.Background(), func(ctx context.Context) { ran = true })
+	if !ran {
+		t.Error("Do without an algorithm context did not run fn")
+	}
+}
+
+func TestFilterStacks(t *testing.T) {
+	profile := `goroutine profile: total 4
+1 @ 0x1 0x2
+#	0x1	main.main+0x1	/src/main.go:10
+
+2 @ 0x3 0x4
+# labels: {"cancel_algorithm":"s/a/alg", "cancel_activity":"s/a"}
+#	0x3	main.hung+0x1	/src/main.go:20
+
+1 @ 0x5 0x6
+# labels: {"cancel_algorithm":"s/a/alg2", "cancel_activity":"s/a"}
+#	0x5	main.other+0x1	/src/main.go:30
+`
+	stacks, n := filterStacks(profile, LabelAlgorithm, "s/a/alg")
+	if n != 2 || !strings.Contains(stacks, "main.hung") || strings.Contains(stacks, "main.other") {
+		t.Errorf("algorithm filter: %d goroutines\n%s", n, stacks)
+	}
+
+	stacks, n = filterStacks(profile, LabelActivity, "s/a")
+	if n != 3 || strings.Contains(stacks, "main.main") {
+		t.Errorf("activity filter: %d goroutines\n%s", n, stacks)
+	}
+
+	if stacks, n := filterStacks(profile, LabelAlgorithm, "missing"); n != 0 || stacks != "" {
+		t.Errorf("no match: %d goroutines %q", n, stacks)
+	}
+}
@@ -13,6 +13,7 @@ package cancel
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -60,7 +61,7 @@ const (
 	// CancelDeadlock indicates no progress was reported within the deadlock threshold.
 	CancelDeadlock
 
-	// CancelResourceLimit indicates memory or CPU limits were exceeded.
+	// CancelResourceLimit indicates memory or goroutine limits were exceeded.
 	CancelResourceLimit
 
 	// CancelParent indicates the parent context was cancelled.
@@ -68,6 +69,17 @@ const (
 
 	// CancelShutdown indicates system shutdown is in progress.
 	CancelShutdown
+
+	// CancelResourceCPU indicates a CPU-time budget or CPU utilization
+	// limit was exceeded.
+	CancelResourceCPU
+
+	// CancelFailure indicates the algorithm reported an error via Fail.
+	CancelFailure
+
+	// CancelCascade indicates the activity's cascade policy cancelled the
+	// context after an algorithm in the activity failed.
+	CancelCascade
 )
 
 // String returns the string representation of the cancel type.
@@ -85,6 +97,57 @@ func (t CancelType) String() string {
 		return "parent"
 	case CancelShutdown:
 		return "shutdown"
+	case CancelResourceCPU:
+		return "resource_cpu"
+	case CancelFailure:
+		return "failure"
+	case CancelCascade:
+		return "cascade"
+	default:
+		return "unknown"
+	}
+}
+
+// IsFailure reports whether a cancellation of this type means the
+// algorithm itself misbehaved, as opposed to being stopped from outside.
+// Failures are what activity cascade policies react to.
+func (t CancelType) IsFailure() bool {
+	switch t {
+	case CancelTimeout, CancelDeadlock, CancelResourceLimit, CancelResourceCPU, CancelFailure:
+		return true
+	default:
+		return false
+	}
+}
+
+// CascadePolicy decides what an activity does when one of its algorithms
+// fails.
+type CascadePolicy int
+
+const (
+	// IsolateFailure contains the failure: the other algorithms keep
+	// running. This is the default.
+	IsolateFailure CascadePolicy = iota
+
+	// CancelSiblingsOnFailure cancels every other running algorithm in the
+	// activity as soon as one fails. The activity itself keeps running, so
+	// the caller can start replacement algorithms.
+	CancelSiblingsOnFailure
+
+	// EscalateToParentAfterN cancels the whole activity once
+	// CascadeConfig.EscalateAfter of its algorithms have failed.
+	EscalateToParentAfterN
+)
+
+// String returns the string representation of the cascade policy.
+func (p CascadePolicy) String() string {
+	switch p {
+	case IsolateFailure:
+		return "isolate"
+	case CancelSiblingsOnFailure:
+		return "cancel_siblings"
+	case EscalateToParentAfterN:
+		return "escalate"
 	default:
 		return "unknown"
 	}
@@ -186,6 +249,16 @@ type ControllerConfig struct {
 	// EnableMetrics enables Prometheus metrics collection.
 	// Default: true.
 	EnableMetrics bool
+
+	// CheckpointStore persists algorithm checkpoints taken on timeout or
+	// resource-limit cancellation, for SessionContext.Resume.
+	// Default: an in-memory store.
+	CheckpointStore CheckpointStore
+
+	// CPUSampler measures CPU time for per-algorithm CPU budgets and
+	// MaxCPUPercent session limits.
+	// Default: NewCPUSampler (cgroup v2 in containers, getrusage otherwise).
+	CPUSampler CPUSampler
 }
 
 // Validate checks if the configuration is valid.
@@ -254,6 +327,11 @@ type SessionConfig struct {
 	// ProgressInterval is how often algorithms should report progress.
 	// Zero means use the default (1 second).
 	ProgressInterval time.Duration
+
+	// Cascade is the failure cascade policy given to each activity in the
+	// session. Activities can override it with SetCascade.
+	// Default: IsolateFailure.
+	Cascade CascadeConfig
 }
 
 // Validate checks if the session configuration is valid.
@@ -267,9 +345,40 @@ func (c *SessionConfig) Validate() error {
 	if c.ProgressInterval < 0 {
 		return errors.New("ProgressInterval must be >= 0")
 	}
+	if err := c.Cascade.Validate(); err != nil {
+		return err
+	}
 	return c.ResourceLimits.Validate()
 }
 
+// CascadeConfig configures how an activity reacts to algorithm failures.
+type CascadeConfig struct {
+	// Policy is the cascade policy. Default: IsolateFailure.
+	Policy CascadePolicy
+
+	// EscalateAfter is the number of failed algorithms after which an
+	// EscalateToParentAfterN activity is cancelled. Must be >= 1 for that
+	// policy and is ignored by the others.
+	EscalateAfter int
+}
+
+// Validate checks if the cascade configuration is valid.
+func (c *CascadeConfig) Validate() error {
+	switch c.Policy {
+	case IsolateFailure, CancelSiblingsOnFailure:
+	case EscalateToParentAfterN:
+		if c.EscalateAfter < 1 {
+			return errors.New("EscalateAfter must be >= 1 for EscalateToParentAfterN")
+		}
+	default:
+		return fmt.Errorf("unknown cascade policy %d", c.Policy)
+	}
+	if c.EscalateAfter < 0 {
+		return errors.New("EscalateAfter must be >= 0")
+	}
+	return nil
+}
+
 // ApplyDefaults fills in zero values with sensible defaults.
 func (c *SessionConfig) ApplyDefaults() {
 	if c.ProgressInterval == 0 {
@@ -290,6 +399,11 @@ type ResourceLimits struct {
 	// MaxGoroutines is the maximum number of goroutines before triggering cancellation.
 	// Zero means no limit.
 	MaxGoroutines int
+
+	// MaxAlgorithmCPUTime is the CPU-time budget given to each algorithm in
+	// the session. Algorithms can override it with SetCPUBudget.
+	// Zero means no limit.
+	MaxAlgorithmCPUTime time.Duration
 }
 
 // Validate checks if resource limits are valid.
@@ -303,10 +417,14 @@ func (r *ResourceLimits) Validate() error {
 	if r.MaxGoroutines < 0 {
 		return errors.New("MaxGoroutines must be >= 0")
 	}
+	if r.MaxAlgorithmCPUTime < 0 {
+		return errors.New("MaxAlgorithmCPUTime must be >= 0")
+	}
 	return nil
 }
 
-// HasLimits returns true if any limits are configured.
+// HasLimits returns true if any session-wide limits are configured.
+// MaxAlgorithmCPUTime is enforced per algorithm and is not included.
 func (r *ResourceLimits) HasLimits() bool {
 	return r.MaxMemoryBytes > 0 || r.MaxCPUPercent > 0 || r.MaxGoroutines > 0
 }
@@ -332,6 +450,12 @@ type CancelReason struct {
 
 	// Timestamp is when the cancellation was triggered (Unix milliseconds UTC).
 	Timestamp int64
+
+	// Stacks holds the goroutine stacks of the context's algorithms at the
+	// time a deadlock was detected, in the debug=1 goroutine profile format.
+	// Only goroutines running under AlgorithmContext.Do are captured.
+	// Empty for other cancellation types.
+	Stacks string
 }
 
 // Status provides the current status of a cancellable context.
@@ -424,6 +548,9 @@ const (
 
 	// progressReporterKey stores the ProgressReporter in context.
 	progressReporterKey
+
+	// algorithmKey stores the *AlgorithmContext in context.
+	algorithmKey
 )
 
 // -----------------------------------------------------------------------------
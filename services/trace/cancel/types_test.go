@@ -27,6 +27,9 @@ func TestCancelType_String(t *testing.T) {
 		{"resource_limit", CancelResourceLimit, "resource_limit"},
 		{"parent", CancelParent, "parent"},
 		{"shutdown", CancelShutdown, "shutdown"},
+		{"resource_cpu", CancelResourceCPU, "resource_cpu"},
+		{"failure", CancelFailure, "failure"},
+		{"cascade", CancelCascade, "cascade"},
 		{"unknown", CancelType(99), "unknown"},
 	}
 
@@ -65,6 +65,7 @@ func RegisterExploreTools(registry *Registry, g *graph.Graph, idx *index.SymbolI
 	registry.Register(NewFindCalleesTool(g, idx))
 	registry.Register(NewFindImplementationsTool(g, idx))
 	registry.Register(NewFindSymbolTool(g, idx))
+	registry.Register(NewSearchCodeTool(g, idx))
 	registry.Register(NewGetCallChainTool(g, idx))
 	registry.Register(NewFindReferencesTool(g, idx))
 
@@ -91,11 +92,16 @@ func RegisterExploreTools(registry *Registry, g *graph.Graph, idx *index.SymbolI
 			registry.Register(NewFindCriticalPathTool(analytics, idx))        // GR-18a: Critical path
 			registry.Register(NewFindModuleAPITool(analytics, g, idx))        // GR-18b: Module API surface
 			registry.Register(NewFindWeightedCriticalityTool(analytics, idx)) // GR-18c: Weighted criticality
+			registry.Register(NewExplainCallPathTool(analytics, g, idx))      // Call path with guards and evidence
+			registry.Register(NewFindDocCoverageTool(analytics))              // Doc coverage per package
 		}
 	}
 
 	// find_path uses Graph directly (doesn't need HierarchicalGraph)
 	registry.Register(NewFindPathTool(g, idx))
+
+	// Change description
+	registry.Register(NewGenerateCommitMessageTool(g, idx))
 }
 
 // ============================================================================
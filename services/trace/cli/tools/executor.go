@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/audit"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/transaction"
 	"github.com/google/uuid"
 )
@@ -41,6 +42,10 @@ var (
 
 	// ErrRequirementNotMet indicates a tool requirement is not satisfied.
 	ErrRequirementNotMet = errors.New("tool requirement not met")
+
+	// ErrAuditUnavailable indicates the audit log cannot record, so audited
+	// tools are refused.
+	ErrAuditUnavailable = errors.New("audit log unavailable")
 )
 
 // Executor handles tool invocations with validation and observability.
@@ -64,6 +69,23 @@ type Executor struct {
 
 	// sessionID correlates transactions with agent sessions for tracing.
 	sessionID string
+
+	// auditLog records tools that touch files or run commands.
+	// Optional - if nil, nothing is audited.
+	auditLog *audit.Log
+
+	// observer is told about every invocation and its outcome.
+	// Optional - if nil, nothing is observed.
+	observer ExecutionObserver
+}
+
+// ExecutionObserver is told about every tool invocation an Executor runs.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type ExecutionObserver interface {
+	// ObserveExecution is called after an invocation completes. Exactly one
+	// of result and err is non-nil.
+	ObserveExecution(invocation *Invocation, result *Result, err error)
 }
 
 // ExecutorOption configures an Executor.
@@ -79,6 +101,25 @@ func WithTransactionManager(tm *transaction.Manager) ExecutorOption {
 	}
 }
 
+// WithAuditLog records every tool that touches files or runs commands in
+// an append-only audit log.
+//
+// Audited tools are refused with ErrAuditUnavailable once the log can no
+// longer record, so no audited action goes unrecorded.
+func WithAuditLog(log *audit.Log) ExecutorOption {
+	return func(e *Executor) {
+		e.auditLog = log
+	}
+}
+
+// WithObserver reports every invocation and its outcome to obs, for
+// example to capture a session for replay.
+func WithObserver(obs ExecutionObserver) ExecutorOption {
+	return func(e *Executor) {
+		e.observer = obs
+	}
+}
+
 // WithSessionID sets the session ID for trace correlation.
 func WithSessionID(sessionID string) ExecutorOption {
 	return func(e *Executor) {
@@ -172,6 +213,15 @@ func (e *Executor) SetSessionID(sessionID string) {
 //
 // Thread Safety: This method is safe for concurrent use.
 func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	result, err := e.execute(ctx, invocation)
+	if e.observer != nil && invocation != nil {
+		e.observer.ObserveExecution(invocation, result, err)
+	}
+	return result, err
+}
+
+// execute implements Execute.
+func (e *Executor) execute(ctx context.Context, invocation *Invocation) (*Result, error) {
 	// TO-001: Check context cancellation early to avoid wasted work
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("context cancelled before execution: %w", err)
@@ -231,6 +281,14 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	audited := e.auditLog != nil && isAuditedTool(tool.Definition())
+	if audited {
+		if err := e.auditLog.Err(); err != nil {
+			logger.Error("Refusing audited tool", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrAuditUnavailable, err)
+		}
+	}
+
 	// Check if this tool has side effects and we have a transaction manager
 	hasSideEffects := tool.Definition().SideEffects
 	var txActive bool
@@ -289,12 +347,19 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 			_, commitErr := e.transactionManager.Commit(ctx, "agent tool execution")
 			if commitErr != nil {
 				logger.Error("Transaction commit failed", "error", commitErr)
+				if audited {
+					e.recordAudit(tool.Definition(), invocation, nil, commitErr, ctx.Err())
+				}
 				return nil, fmt.Errorf("committing transaction: %w", commitErr)
 			}
 			logger.Debug("Transaction committed")
 		}
 	}
 
+	if audited {
+		e.recordAudit(tool.Definition(), invocation, result, err, ctx.Err())
+	}
+
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			logger.Error("Tool execution timed out", "timeout", timeout)
@@ -329,6 +394,69 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 	return result, nil
 }
 
+// commandTools are tools that execute shell commands.
+var commandTools = map[string]bool{
+	"run_command": true,
+	"shell":       true,
+	"bash":        true,
+}
+
+// isAuditedTool reports whether a tool touches files or executes commands.
+func isAuditedTool(def ToolDefinition) bool {
+	return def.SideEffects || def.Category == CategoryFile || commandTools[def.Name]
+}
+
+// auditAction classifies an audited tool for the audit log.
+func auditAction(def ToolDefinition) string {
+	switch {
+	case commandTools[def.Name]:
+		return "command"
+	case def.Category == CategoryFile && def.SideEffects:
+		return "file_write"
+	case def.Category == CategoryFile:
+		return "file_read"
+	default:
+		return "side_effect"
+	}
+}
+
+// recordAudit appends an audit entry for an executed tool. A failure to
+// record is logged; the next audited tool is then refused.
+func (e *Executor) recordAudit(def ToolDefinition, inv *Invocation, result *Result, execErr, ctxErr error) {
+	e.mu.RLock()
+	sessionID := e.sessionID
+	e.mu.RUnlock()
+
+	rec := audit.Record{
+		Actor:     "agent",
+		SessionID: sessionID,
+		Tool:      inv.ToolName,
+		Action:    auditAction(def),
+		Args:      inv.Parameters,
+		Duration:  time.Duration(inv.CompletedAt-inv.StartedAt) * time.Millisecond,
+	}
+	switch {
+	case execErr != nil && (errors.Is(execErr, context.DeadlineExceeded) || errors.Is(ctxErr, context.DeadlineExceeded)):
+		rec.Outcome = audit.OutcomeTimeout
+	case execErr != nil:
+		rec.Outcome = audit.OutcomeError
+		rec.Error = execErr.Error()
+	case result != nil && !result.Success:
+		rec.Outcome = audit.OutcomeFailure
+		rec.Error = result.Error
+	default:
+		rec.Outcome = audit.OutcomeSuccess
+	}
+
+	if _, err := e.auditLog.Record(rec); err != nil {
+		slog.Error("Failed to record audit entry",
+			"tool", inv.ToolName,
+			"invocation_id", inv.ID,
+			"error", err,
+		)
+	}
+}
+
 // coerceParams attempts to convert parameter values to their expected types.
 //
 // Description:
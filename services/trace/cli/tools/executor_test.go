@@ -12,8 +12,12 @@ package tools
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/audit"
 )
 
 // mockTool is a minimal tool implementation for testing.
@@ -433,3 +437,50 @@ func TestExecutor_Execute_WithCoercion(t *testing.T) {
 		}
 	})
 }
+
+func TestExecutor_Execute_AuditLog(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&mockTool{
+		name:       "write_file",
+		definition: ToolDefinition{Name: "write_file", Category: CategoryFile, SideEffects: true},
+	})
+	registry.Register(&mockTool{
+		name:       "find_callers",
+		definition: ToolDefinition{Name: "find_callers", Category: CategoryExploration},
+	})
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path, audit.Options{})
+	if err != nil {
+		t.Fatalf("audit.Open: %v", err)
+	}
+	executor := NewExecutorWithOptions(registry, nil, WithAuditLog(log), WithSessionID("sess-1"))
+	ctx := context.Background()
+
+	t.Run("records file tools only", func(t *testing.T) {
+		for _, name := range []string{"write_file", "find_callers"} {
+			if _, err := executor.Execute(ctx, &Invocation{ToolName: name, Parameters: map[string]any{"path": "a.go"}}); err != nil {
+				t.Fatalf("Execute(%s): %v", name, err)
+			}
+		}
+
+		report, err := audit.VerifyFile(path, nil, "")
+		if err != nil {
+			t.Fatalf("VerifyFile: %v", err)
+		}
+		if report.Entries != 1 {
+			t.Errorf("Entries = %d, want 1", report.Entries)
+		}
+	})
+
+	t.Run("refuses audited tools when the log is closed", func(t *testing.T) {
+		log.Close()
+		_, err := executor.Execute(ctx, &Invocation{ToolName: "write_file", Parameters: map[string]any{}})
+		if !errors.Is(err, ErrAuditUnavailable) {
+			t.Errorf("Execute error = %v, want ErrAuditUnavailable", err)
+		}
+		if _, err := executor.Execute(ctx, &Invocation{ToolName: "find_callers", Parameters: map[string]any{}}); err != nil {
+			t.Errorf("unaudited tool should still run: %v", err)
+		}
+	})
+}
@@ -221,6 +221,7 @@ func (t *EditTool) Execute(ctx context.Context, params map[string]any) (*tools.R
 			Duration: time.Since(start),
 		}, nil
 	}
+	t.config.recordEdit(p.FilePath, []byte(oldContent), true, []byte(newContent))
 
 	// Synchronous graph refresh BEFORE returning to prevent event storm.
 	// This ensures the graph is updated immediately, so subsequent queries
@@ -485,6 +485,197 @@ func TestEditTool_Execute_FileNotRead(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Patch Tool Tests
+// ============================================================================
+
+func TestPatchTool_Execute_UnifiedDiff(t *testing.T) {
+	dir, config, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	path := createTestFile(t, dir, "code.go", "package code\n\nfunc a() {\n\tone()\n}\n")
+	config.MarkFileRead(path)
+
+	tool := NewPatchTool(config)
+	result, err := tool.Execute(context.Background(), map[string]any{
+		// Line numbers are stale; the hunk is located by context.
+		"patch": "--- a/code.go\n+++ b/code.go\n@@ -10,3 +10,4 @@\n func a() {\n \tone()\n+\ttwo()\n }\n" +
+			"--- /dev/null\n+++ b/sub/new.go\n@@ -0,0 +1 @@\n+package sub\n",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "package code\n\nfunc a() {\n\tone()\n\ttwo()\n}\n" {
+		t.Errorf("patched content = %q", data)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "sub", "new.go")); err != nil || string(data) != "package sub\n" {
+		t.Errorf("new file = %q, %v", data, err)
+	}
+	if len(result.ModifiedFiles) != 2 {
+		t.Errorf("expected 2 modified files, got %v", result.ModifiedFiles)
+	}
+	patchResult := result.Output.(*PatchResult)
+	if len(patchResult.Files[0].Notes) != 1 || !strings.Contains(patchResult.Files[0].Notes[0], "offset") {
+		t.Errorf("expected offset note, got %v", patchResult.Files[0].Notes)
+	}
+}
+
+func TestPatchTool_Execute_RejectedHunk(t *testing.T) {
+	dir, config, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	original := "a\nb\nc\n"
+	path := createTestFile(t, dir, "f.txt", original)
+	config.MarkFileRead(path)
+
+	badHunk := "@@ -2,1 +2,1 @@\n-missing\n+x\n"
+	params := map[string]any{
+		"file_path": path,
+		"patch":     "@@ -1,1 +1,1 @@\n-a\n+A\n" + badHunk,
+	}
+
+	tool := NewPatchTool(config)
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("expected failure with a rejected hunk")
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("file changed despite rejection: %q", data)
+	}
+	rejected := result.Output.(*PatchResult).Files[0].Rejected
+	if len(rejected) != 1 || rejected[0].Hunk != badHunk {
+		t.Errorf("rejected = %+v, want exact hunk %q", rejected, badHunk)
+	}
+
+	params["partial"] = true
+	result, err = tool.Execute(context.Background(), params)
+	if err != nil || !result.Success {
+		t.Fatalf("partial apply failed: %v %s", err, result.Error)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "A\nb\nc\n" {
+		t.Errorf("partial content = %q", data)
+	}
+}
+
+func TestPatchTool_Execute_EditScript(t *testing.T) {
+	dir, config, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	path := createTestFile(t, dir, "code.go", "func main() {\n\trun()\n}\n")
+	config.MarkFileRead(path)
+
+	tool := NewPatchTool(config)
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"file_path": "code.go",
+		"edits": []any{
+			map[string]any{"op": "insert_after", "anchor": "func main() {", "text": "\tinit()"},
+			map[string]any{"op": "replace", "anchor": "run()", "text": "serve()"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "func main() {\n\tinit()\n\tserve()\n}\n" {
+		t.Errorf("edited content = %q", data)
+	}
+}
+
+func TestPatchTool_Execute_FileNotRead(t *testing.T) {
+	dir, config, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	path := createTestFile(t, dir, "f.txt", "a\n")
+
+	tool := NewPatchTool(config)
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"file_path": path,
+		"edits":     `[{"op": "replace", "anchor": "a", "text": "b"}]`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Error, ErrFileNotRead.Error()) {
+		t.Errorf("expected file-not-read error, got success=%v error=%q", result.Success, result.Error)
+	}
+}
+
+// ============================================================================
+// Edit Recording Tests
+// ============================================================================
+
+type recordedEdit struct {
+	path    string
+	before  string
+	existed bool
+	after   string
+}
+
+type fakeEditRecorder struct {
+	edits []recordedEdit
+}
+
+func (r *fakeEditRecorder) RecordEdit(path string, before []byte, existed bool, after []byte) error {
+	r.edits = append(r.edits, recordedEdit{path, string(before), existed, string(after)})
+	return nil
+}
+
+func TestFileTools_RecordEdits(t *testing.T) {
+	dir, config, cleanup := setupTestDir(t)
+	defer cleanup()
+	recorder := &fakeEditRecorder{}
+	config.EditRecorder = recorder
+	ctx := context.Background()
+
+	path := filepath.Join(dir, "code.go")
+	if result, err := NewWriteTool(config).Execute(ctx, map[string]any{
+		"file_path": path,
+		"content":   "a\n",
+	}); err != nil || !result.Success {
+		t.Fatalf("write failed: %v %+v", err, result)
+	}
+
+	config.MarkFileRead(path)
+	if result, err := NewEditTool(config).Execute(ctx, map[string]any{
+		"file_path":  path,
+		"old_string": "a",
+		"new_string": "b",
+	}); err != nil || !result.Success {
+		t.Fatalf("edit failed: %v %+v", err, result)
+	}
+
+	if result, err := NewPatchTool(config).Execute(ctx, map[string]any{
+		"file_path": path,
+		"edits":     `[{"op": "replace", "anchor": "b", "text": "c"}]`,
+	}); err != nil || !result.Success {
+		t.Fatalf("patch failed: %v %+v", err, result)
+	}
+
+	want := []recordedEdit{
+		{path, "", false, "a\n"},
+		{path, "a\n", true, "b\n"},
+		{path, "b\n", true, "c\n"},
+	}
+	if len(recorder.edits) != len(want) {
+		t.Fatalf("recorded %d edits, want %d: %+v", len(recorder.edits), len(want), recorder.edits)
+	}
+	for i, w := range want {
+		if recorder.edits[i] != w {
+			t.Errorf("edit %d = %+v, want %+v", i, recorder.edits[i], w)
+		}
+	}
+}
+
 // ============================================================================
 // Glob Tool Tests
 // ============================================================================
@@ -0,0 +1,439 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+)
+
+// PatchTool implements the Patch file operation.
+//
+// Thread Safety: PatchTool is safe for concurrent use.
+type PatchTool struct {
+	config *Config
+}
+
+// NewPatchTool creates a new Patch tool with the given configuration.
+func NewPatchTool(config *Config) *PatchTool {
+	return &PatchTool{config: config}
+}
+
+// Name returns the tool name.
+func (t *PatchTool) Name() string {
+	return "Patch"
+}
+
+// Category returns the tool category.
+func (t *PatchTool) Category() tools.ToolCategory {
+	return tools.CategoryFile
+}
+
+// Definition returns the tool's parameter schema.
+func (t *PatchTool) Definition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: "Patch",
+		Description: "Apply a unified diff (one or more files) or a JSON edit script (insert_before/insert_after/replace/delete by anchor text) to a file. " +
+			"Hunks are located by context, so line numbers may be approximate. Rejected hunks and edits are reported exactly; " +
+			"nothing is written if any are rejected unless partial is true.",
+		Parameters: map[string]tools.ParamDef{
+			"file_path": {
+				Type:        tools.ParamTypeString,
+				Description: "File to patch. Required with edits; with patch it overrides the diff's file paths. Can be absolute or relative to the project root.",
+				Required:    false,
+			},
+			"patch": {
+				Type:        tools.ParamTypeString,
+				Description: "Unified diff to apply (--- / +++ headers optional when file_path is set)",
+				Required:    false,
+			},
+			"edits": {
+				Type:        tools.ParamTypeArray,
+				Description: "Edit operations applied in order: {op, anchor, text, occurrence}",
+				Required:    false,
+				Items: &tools.ParamDef{
+					Type: tools.ParamTypeObject,
+					Properties: map[string]tools.ParamDef{
+						"op": {
+							Type:     tools.ParamTypeString,
+							Required: true,
+							Enum:     []any{"insert_before", "insert_after", "replace", "delete"},
+						},
+						"anchor": {
+							Type:        tools.ParamTypeString,
+							Description: "Existing text that locates the edit (unique unless occurrence is set)",
+							Required:    true,
+						},
+						"text": {
+							Type:        tools.ParamTypeString,
+							Description: "Text to insert, or the replacement for the anchor",
+						},
+						"occurrence": {
+							Type:        tools.ParamTypeInt,
+							Description: "Which match of the anchor to use (1-based)",
+						},
+					},
+				},
+			},
+			"partial": {
+				Type:        tools.ParamTypeBool,
+				Description: "Write the hunks and edits that applied even if others were rejected",
+				Required:    false,
+				Default:     false,
+			},
+		},
+		Category:    tools.CategoryFile,
+		Priority:    97,
+		SideEffects: true,
+		Timeout:     30 * time.Second,
+		Examples: []tools.ToolExample{
+			{
+				Description: "Apply a unified diff",
+				Parameters: map[string]any{
+					"patch": "--- a/main.go\n+++ b/main.go\n@@ -3,3 +3,4 @@\n func main() {\n+\tinit()\n \trun()\n }\n",
+				},
+			},
+			{
+				Description: "Insert a line after an anchor",
+				Parameters: map[string]any{
+					"file_path": "/path/to/main.go",
+					"edits": []map[string]any{
+						{"op": "insert_after", "anchor": "func main() {", "text": "\tinit()"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// patchTarget is one file to be patched.
+type patchTarget struct {
+	path   string
+	change *diff.ProposedChange // nil for edit scripts
+}
+
+// patchedFile is a file with its computed new content.
+type patchedFile struct {
+	result     PatchFileResult
+	oldContent string
+	newContent string
+	hash       string
+	perm       os.FileMode
+}
+
+// Execute applies a unified diff or edit script.
+func (t *PatchTool) Execute(ctx context.Context, params map[string]any) (*tools.Result, error) {
+	start := time.Now()
+	fail := func(msg string) (*tools.Result, error) {
+		return &tools.Result{
+			Success:  false,
+			Error:    msg,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	// Parse parameters
+	p := &PatchParams{}
+	if filePath, ok := params["file_path"].(string); ok {
+		p.FilePath = filePath
+	}
+	if patch, ok := params["patch"].(string); ok {
+		p.Patch = patch
+	}
+	if partial, ok := params["partial"].(bool); ok {
+		p.Partial = partial
+	}
+	if raw, ok := params["edits"]; ok && raw != nil {
+		edits, err := parseEditsParam(raw)
+		if err != nil {
+			return fail(err.Error())
+		}
+		p.Edits = edits
+	}
+
+	// Resolve relative paths to absolute using working directory
+	if p.FilePath != "" && !filepath.IsAbs(p.FilePath) {
+		p.FilePath = filepath.Join(t.config.WorkingDir, p.FilePath)
+	}
+
+	// Validate
+	if err := p.Validate(); err != nil {
+		return fail(err.Error())
+	}
+
+	targets, err := t.targets(p)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	// Compute every file's new content before writing anything.
+	files := make([]*patchedFile, 0, len(targets))
+	rejected := 0
+	for _, target := range targets {
+		pf, err := t.prepare(target, p)
+		if err != nil {
+			return fail(err.Error())
+		}
+		rejected += len(pf.result.Rejected)
+		files = append(files, pf)
+	}
+
+	result := &PatchResult{Success: rejected == 0}
+	if rejected > 0 && !p.Partial {
+		for _, pf := range files {
+			result.Files = append(result.Files, pf.result)
+		}
+		return &tools.Result{
+			Success:    false,
+			Error:      fmt.Sprintf("%d hunk(s)/edit(s) rejected; no files were changed (set partial=true to apply the rest)", rejected),
+			Output:     result,
+			OutputText: formatPatchResult(result),
+			Duration:   time.Since(start),
+		}, nil
+	}
+
+	var modified []string
+	for _, pf := range files {
+		if pf.newContent != pf.oldContent {
+			if err := t.write(pf); err != nil {
+				if err == ErrConflict {
+					return fail(fmt.Sprintf("%s: %s", pf.result.Path, ErrConflict.Error()))
+				}
+				return fail(fmt.Sprintf("failed to write %s: %v", pf.result.Path, err))
+			}
+			pf.result.Written = true
+			t.config.recordEdit(pf.result.Path, []byte(pf.oldContent), !pf.result.Created, []byte(pf.newContent))
+			pf.result.Diff = generateUnifiedDiff(pf.result.Path, pf.oldContent, pf.newContent)
+			modified = append(modified, pf.result.Path)
+		}
+		result.Files = append(result.Files, pf.result)
+	}
+
+	// Synchronous graph refresh, as in Edit.
+	if t.config.GraphRefresher != nil && len(modified) > 0 {
+		if err := t.config.GraphRefresher.RefreshFiles(ctx, modified); err != nil {
+			slog.Warn("failed to refresh graph after patch",
+				slog.Any("files", modified),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return &tools.Result{
+		Success:       true,
+		Output:        result,
+		OutputText:    formatPatchResult(result),
+		Duration:      time.Since(start),
+		ModifiedFiles: modified,
+	}, nil
+}
+
+// targets resolves the files a patch or edit script applies to.
+func (t *PatchTool) targets(p *PatchParams) ([]patchTarget, error) {
+	if len(p.Edits) > 0 {
+		return []patchTarget{{path: p.FilePath}}, nil
+	}
+
+	text := p.Patch
+	if !strings.HasPrefix(text, "--- ") && !strings.Contains(text, "\n--- ") {
+		if p.FilePath == "" {
+			return nil, fmt.Errorf("patch has no --- / +++ file headers; set file_path")
+		}
+		text = "--- a/file\n+++ b/file\n" + text
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	changes, err := diff.ParseMultiFileDiff(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("patch contains no file changes")
+	}
+	if p.FilePath != "" && len(changes) > 1 {
+		return nil, fmt.Errorf("file_path is set but the patch changes %d files", len(changes))
+	}
+
+	targets := make([]patchTarget, 0, len(changes))
+	for _, change := range changes {
+		if change.IsDelete {
+			return nil, fmt.Errorf("patch deletes a file; file deletion is not supported")
+		}
+		path := p.FilePath
+		if path == "" {
+			path = change.FilePath
+			if strings.Contains(path, "..") {
+				return nil, fmt.Errorf("patch path must not contain '..': %s", path)
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(t.config.WorkingDir, path)
+			}
+		}
+		targets = append(targets, patchTarget{path: path, change: change})
+	}
+	return targets, nil
+}
+
+// prepare checks a target and computes its patched content.
+func (t *PatchTool) prepare(target patchTarget, p *PatchParams) (*patchedFile, error) {
+	path := target.path
+	if IsSensitivePath(path) {
+		return nil, fmt.Errorf("cannot patch sensitive file: %s", path)
+	}
+	if !t.config.IsPathAllowed(path) {
+		return nil, fmt.Errorf("path is outside allowed directories: %s", path)
+	}
+
+	pf := &patchedFile{result: PatchFileResult{Path: path}, perm: 0644}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if target.change == nil || !target.change.IsNew {
+			return nil, fmt.Errorf("file does not exist: %s", path)
+		}
+		pf.result.Created = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to stat %s: %v", path, err)
+	default:
+		if !t.config.WasFileRead(path) {
+			return nil, fmt.Errorf("%s: %s", path, ErrFileNotRead.Error())
+		}
+		if info.Size() > MaxEditFileSize {
+			return nil, fmt.Errorf("file too large for patch (%d bytes, max %d): %s", info.Size(), MaxEditFileSize, path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %v", err)
+		}
+		pf.oldContent = string(content)
+		pf.hash = computeContentHash(content)
+		pf.perm = info.Mode().Perm()
+	}
+
+	if target.change != nil {
+		res := diff.ApplyHunksFuzzy(pf.oldContent, target.change.Hunks, diff.DefaultPatchOptions())
+		pf.newContent = res.Content
+		for _, h := range res.Hunks {
+			if !h.Applied {
+				pf.result.Rejected = append(pf.result.Rejected, PatchRejection{Index: h.Index, Reason: h.Reason, Hunk: h.Text})
+				continue
+			}
+			pf.result.Applied++
+			if note := hunkNote(h); note != "" {
+				pf.result.Notes = append(pf.result.Notes, note)
+			}
+		}
+	} else {
+		res := diff.ApplyEditScript(pf.oldContent, p.Edits)
+		pf.newContent = res.Content
+		for _, e := range res.Edits {
+			if !e.Applied {
+				pf.result.Rejected = append(pf.result.Rejected, PatchRejection{Index: e.Index, Reason: e.Reason, Anchor: p.Edits[e.Index].Anchor})
+				continue
+			}
+			pf.result.Applied++
+			if e.WhitespaceInsensitive {
+				pf.result.Notes = append(pf.result.Notes, fmt.Sprintf("edit %d: anchor matched at line %d ignoring whitespace", e.Index, e.Line))
+			}
+		}
+	}
+	return pf, nil
+}
+
+// write stores a patched file, with optimistic locking for existing files.
+func (t *PatchTool) write(pf *patchedFile) error {
+	if pf.result.Created {
+		if err := os.MkdirAll(filepath.Dir(pf.result.Path), 0755); err != nil {
+			return err
+		}
+		return atomicWriteFile(pf.result.Path, []byte(pf.newContent), pf.perm)
+	}
+	return verifyAndWrite(pf.result.Path, pf.hash, []byte(pf.newContent), pf.perm)
+}
+
+// hunkNote describes how far a hunk had to move or relax to apply.
+func hunkNote(h diff.HunkOutcome) string {
+	var parts []string
+	if h.Offset != 0 {
+		parts = append(parts, fmt.Sprintf("offset %+d", h.Offset))
+	}
+	if h.Fuzz > 0 {
+		parts = append(parts, fmt.Sprintf("fuzz %d", h.Fuzz))
+	}
+	if h.WhitespaceInsensitive {
+		parts = append(parts, "ignoring whitespace")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("hunk %d applied at line %d (%s)", h.Index, h.Line, strings.Join(parts, ", "))
+}
+
+// parseEditsParam accepts edits as decoded JSON (an array of objects) or
+// as a JSON string.
+func parseEditsParam(raw any) ([]diff.EditOperation, error) {
+	var data []byte
+	if s, ok := raw.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("invalid edits: %v", err)
+		}
+	}
+	return diff.ParseEditScript(data)
+}
+
+// formatPatchResult renders a patch result for the LLM.
+func formatPatchResult(r *PatchResult) string {
+	var b strings.Builder
+	for _, f := range r.Files {
+		status := "not written"
+		switch {
+		case f.Written && f.Created:
+			status = "created"
+		case f.Written:
+			status = "patched"
+		case len(f.Rejected) == 0:
+			status = "unchanged"
+		}
+		fmt.Fprintf(&b, "%s: %s, %d applied, %d rejected\n", f.Path, status, f.Applied, len(f.Rejected))
+		for _, note := range f.Notes {
+			fmt.Fprintf(&b, "  note: %s\n", note)
+		}
+		for _, rej := range f.Rejected {
+			fmt.Fprintf(&b, "  rejected #%d: %s\n", rej.Index, rej.Reason)
+			if rej.Hunk != "" {
+				b.WriteString(rej.Hunk)
+			}
+			if rej.Anchor != "" {
+				fmt.Fprintf(&b, "  anchor: %q\n", rej.Anchor)
+			}
+		}
+		if f.Diff != "" {
+			b.WriteString("\n")
+			b.WriteString(f.Diff)
+		}
+	}
+	return b.String()
+}
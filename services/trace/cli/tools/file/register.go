@@ -18,7 +18,7 @@ import (
 //
 // Description:
 //
-//	Registers all file tools (Read, Write, Edit, Glob, Grep, Diff, Tree, JSON)
+//	Registers all file tools (Read, Write, Edit, Patch, Glob, Grep, Diff, Tree, JSON)
 //	with the provided tool registry. These tools require a Config that
 //	specifies the working directory and allowed paths.
 //
@@ -39,6 +39,7 @@ func RegisterFileTools(registry *tools.Registry, config *Config) {
 	registry.Register(NewReadTool(config))
 	registry.Register(NewWriteTool(config))
 	registry.Register(NewEditTool(config))
+	registry.Register(NewPatchTool(config))
 	registry.Register(NewGlobTool(config))
 	registry.Register(NewGrepTool(config))
 
@@ -75,6 +76,7 @@ func StaticFileToolDefinitions() []tools.ToolDefinition {
 		NewReadTool(dummyConfig).Definition(),
 		NewWriteTool(dummyConfig).Definition(),
 		NewEditTool(dummyConfig).Definition(),
+		NewPatchTool(dummyConfig).Definition(),
 		NewGlobTool(dummyConfig).Definition(),
 		NewGrepTool(dummyConfig).Definition(),
 
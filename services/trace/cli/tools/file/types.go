@@ -14,6 +14,7 @@
 //   - Read: Read file contents with line numbers and pagination
 //   - Write: Create new files with atomic writes
 //   - Edit: Make surgical edits via old_string → new_string replacement
+//   - Patch: Apply unified diffs or anchor-based edit scripts with fuzzy matching
 //   - Glob: Find files by glob pattern
 //   - Grep: Search file contents with regex
 //
@@ -27,9 +28,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
 )
 
 // ============================================================================
@@ -265,6 +269,100 @@ func (e *EditError) Unwrap() error {
 	return e.Err
 }
 
+// ============================================================================
+// Patch Types
+// ============================================================================
+
+// PatchParams defines parameters for the Patch tool.
+type PatchParams struct {
+	// FilePath is the file to patch. Required for Edits; for Patch it
+	// overrides the paths in the diff headers.
+	FilePath string `json:"file_path,omitempty"`
+
+	// Patch is a unified diff. May cover several files.
+	Patch string `json:"patch,omitempty"`
+
+	// Edits is a structured edit script for FilePath.
+	Edits []diff.EditOperation `json:"edits,omitempty"`
+
+	// Partial writes the hunks and edits that applied even if others were
+	// rejected. By default nothing is written if anything is rejected.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Validate checks that PatchParams are valid.
+// Note: Relative paths are allowed and should be resolved by the tool against the working directory.
+func (p *PatchParams) Validate() error {
+	if strings.Contains(p.FilePath, "..") {
+		return errors.New("file_path must not contain '..'")
+	}
+	switch {
+	case p.Patch == "" && len(p.Edits) == 0:
+		return errors.New("one of patch or edits is required")
+	case p.Patch != "" && len(p.Edits) > 0:
+		return errors.New("patch and edits are mutually exclusive")
+	case len(p.Edits) > 0 && p.FilePath == "":
+		return errors.New("file_path is required with edits")
+	case len(p.Patch) > MaxWriteContentSize:
+		return fmt.Errorf("patch exceeds max size of %d bytes", MaxWriteContentSize)
+	}
+	for i, op := range p.Edits {
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("edit %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// PatchRejection describes a hunk or edit that could not be applied.
+type PatchRejection struct {
+	// Index is the hunk's or edit's position within its file (0-based).
+	Index int `json:"index"`
+
+	// Reason explains why it was rejected.
+	Reason string `json:"reason"`
+
+	// Hunk is the rejected hunk exactly as given (unified diffs only).
+	Hunk string `json:"hunk,omitempty"`
+
+	// Anchor is the anchor that was not matched (edit scripts only).
+	Anchor string `json:"anchor,omitempty"`
+}
+
+// PatchFileResult contains the outcome of patching one file.
+type PatchFileResult struct {
+	// Path is the absolute path of the file.
+	Path string `json:"path"`
+
+	// Created indicates the patch created the file.
+	Created bool `json:"created,omitempty"`
+
+	// Applied is the number of hunks or edits applied.
+	Applied int `json:"applied"`
+
+	// Notes describe hunks that needed an offset, fuzz, or whitespace-
+	// insensitive matching to apply.
+	Notes []string `json:"notes,omitempty"`
+
+	// Rejected lists hunks or edits that could not be applied.
+	Rejected []PatchRejection `json:"rejected,omitempty"`
+
+	// Written indicates the file was modified on disk.
+	Written bool `json:"written"`
+
+	// Diff is the unified diff of the changes written.
+	Diff string `json:"diff,omitempty"`
+}
+
+// PatchResult contains the outcome of a Patch operation.
+type PatchResult struct {
+	// Success indicates every hunk and edit applied and was written.
+	Success bool `json:"success"`
+
+	// Files has one result per file, in patch order.
+	Files []PatchFileResult `json:"files"`
+}
+
 // ============================================================================
 // Glob Types
 // ============================================================================
@@ -489,6 +587,23 @@ type GraphRefresher interface {
 	RefreshFiles(ctx context.Context, paths []string) error
 }
 
+// EditRecorder records file writes so they can be undone later.
+//
+// Description:
+//
+//	Edit, Write and Patch call RecordEdit after every successful write
+//	with the content before and after it. The undo package's Stack
+//	implements this interface.
+//
+// Thread Safety:
+//
+//	Implementations must be safe for concurrent use.
+type EditRecorder interface {
+	// RecordEdit records that path was written. before is ignored when
+	// existed is false, i.e. when the write created the file.
+	RecordEdit(path string, before []byte, existed bool, after []byte) error
+}
+
 // Config holds configuration for file tools.
 type Config struct {
 	// AllowedPaths is a list of paths that file operations are allowed in.
@@ -516,6 +631,10 @@ type Config struct {
 	// Optional. If nil, no synchronous refresh is performed.
 	// Recommended to prevent event storms and stale graph queries.
 	GraphRefresher GraphRefresher
+
+	// EditRecorder records writes for undo.
+	// Optional. If nil, writes are not recorded.
+	EditRecorder EditRecorder
 }
 
 // NewConfig creates a new Config with the given working directory.
@@ -643,6 +762,24 @@ func (c *Config) ClearContentHash(path string) {
 	delete(c.ContentHashes, absPath)
 }
 
+// recordEdit passes a successful write to the EditRecorder, if any.
+// Recording failures are logged; they never fail the write.
+func (c *Config) recordEdit(path string, before []byte, existed bool, after []byte) {
+	if c.EditRecorder == nil {
+		return
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	if err := c.EditRecorder.RecordEdit(absPath, before, existed, after); err != nil {
+		slog.Warn("failed to record edit for undo",
+			slog.String("file", absPath),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // SensitivePaths contains paths that should never be written to.
 var SensitivePaths = []string{
 	"/etc/passwd",
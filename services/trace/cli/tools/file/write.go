@@ -126,6 +126,19 @@ func (t *WriteTool) Execute(ctx context.Context, params map[string]any) (*tools.
 	_, err := os.Stat(p.FilePath)
 	isNew := os.IsNotExist(err)
 
+	// Keep the old content for undo
+	var oldContent []byte
+	recordable := t.config.EditRecorder != nil
+	if recordable && !isNew {
+		if oldContent, err = os.ReadFile(p.FilePath); err != nil {
+			slog.Warn("failed to read file before write, not recording for undo",
+				slog.String("file", p.FilePath),
+				slog.String("error", err.Error()),
+			)
+			recordable = false
+		}
+	}
+
 	// Create parent directories if needed
 	dir := filepath.Dir(p.FilePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -171,6 +184,9 @@ func (t *WriteTool) Execute(ctx context.Context, params map[string]any) (*tools.
 			Duration: time.Since(start),
 		}, nil
 	}
+	if recordable {
+		t.config.recordEdit(p.FilePath, oldContent, !isNew, []byte(p.Content))
+	}
 
 	// Synchronous graph refresh BEFORE returning to prevent event storm.
 	// This ensures the graph is updated immediately, so subsequent queries
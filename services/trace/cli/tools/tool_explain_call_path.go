@@ -0,0 +1,355 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// =============================================================================
+// explain_call_path Tool - Typed Implementation
+// =============================================================================
+
+var explainCallPathTracer = otel.Tracer("tools.explain_call_path")
+
+// maxCallPathContextLines bounds the evidence excerpt size.
+const maxCallPathContextLines = 10
+
+// ExplainCallPathParams contains the validated input parameters.
+type ExplainCallPathParams struct {
+	// From is the calling function.
+	// Required.
+	From string
+
+	// To is the function to explain the path to.
+	// Required.
+	To string
+
+	// ContextLines is the number of source lines around each evidence line.
+	// Default: 2, Max: 10
+	ContextLines int
+}
+
+// explainCallPathTool explains how one function reaches another.
+type explainCallPathTool struct {
+	explainer *explore.CallPathExplainer
+	index     *index.SymbolIndex
+	logger    *slog.Logger
+}
+
+// NewExplainCallPathTool creates a new explain_call_path tool.
+//
+// Description:
+//
+//	Creates a tool that explains how one function reaches another: the
+//	dominating call path, the branches guarding it (control dependence),
+//	and file:line evidence for every step, formatted for citation.
+//
+// Inputs:
+//   - analytics: GraphAnalytics instance for dominator and control dependence
+//     computation. If nil, analytics are created from g on first use.
+//   - g: The code graph, used for call sites and the project root. Must not be nil.
+//   - idx: SymbolIndex for resolving function names (can be nil; then only
+//     full IDs are accepted).
+//
+// Outputs:
+//   - Tool: The configured explain_call_path tool.
+//
+// Limitations:
+//   - Guarding conditions are found textually around the call site
+//   - Evidence excerpts require the source files on disk
+//
+// Thread Safety: Safe for concurrent use after construction.
+func NewExplainCallPathTool(analytics *graph.GraphAnalytics, g *graph.Graph, idx *index.SymbolIndex) Tool {
+	return &explainCallPathTool{
+		explainer: explore.NewCallPathExplainer(g, idx, analytics),
+		index:     idx,
+		logger:    slog.Default(),
+	}
+}
+
+func (t *explainCallPathTool) Name() string {
+	return "explain_call_path"
+}
+
+func (t *explainCallPathTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *explainCallPathTool) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name: "explain_call_path",
+		Description: "Explain HOW one function reaches another, with evidence. " +
+			"Returns the mandatory (dominating) call path, the conditions guarding it, " +
+			"and [file:line] source snippets for every call, ready to cite in the answer.",
+		Parameters: map[string]ParamDef{
+			"from": {
+				Type:        ParamTypeString,
+				Description: "Calling function name or ID (required)",
+				Required:    true,
+			},
+			"to": {
+				Type:        ParamTypeString,
+				Description: "Called function name or ID (required)",
+				Required:    true,
+			},
+			"context_lines": {
+				Type:        ParamTypeInt,
+				Description: "Source lines shown around each evidence line (default: 2, max: 10)",
+				Required:    false,
+				Default:     2,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    87,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     30 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"how does", "how is", "reach", "explain path",
+				"why is called", "under what conditions", "when is called",
+				"call path evidence", "path between",
+			},
+			UseWhen: "User asks how or under which conditions one function ends up " +
+				"calling another, and the answer needs citable evidence.",
+			AvoidWhen: "User only wants any path (use find_path) or only the mandatory " +
+				"sequence without evidence (use find_critical_path).",
+		},
+	}
+}
+
+// Execute runs the explain_call_path tool.
+func (t *explainCallPathTool) Execute(ctx context.Context, params map[string]any) (*Result, error) {
+	start := time.Now()
+
+	// Parse and validate parameters
+	p, err := t.parseParams(params)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	// Start span with context
+	ctx, span := explainCallPathTracer.Start(ctx, "explainCallPathTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "explain_call_path"),
+			attribute.String("from", p.From),
+			attribute.String("to", p.To),
+		),
+	)
+	defer span.End()
+
+	// Check context cancellation before expensive operation
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	fromID, err := t.resolveSymbol(p.From)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+	toID, err := t.resolveSymbol(p.To)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+	span.SetAttributes(
+		attribute.String("from_resolved", fromID),
+		attribute.String("to_resolved", toID),
+	)
+
+	opts := explore.DefaultCallPathOptions()
+	opts.ContextLines = p.ContextLines
+	exp, err := t.explainer.ExplainCallPath(ctx, fromID, toID, opts)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			span.RecordError(ctxErr)
+			return nil, ctxErr
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &Result{
+			Success: false,
+			Error:   fmt.Sprintf("failed to explain call path: %v", err),
+		}, nil
+	}
+
+	outputText := t.formatText(exp)
+
+	span.SetAttributes(
+		attribute.Bool("reachable", exp.Reachable),
+		attribute.Int("path_length", len(exp.DominatingPath)),
+		attribute.Int("call_chain_length", len(exp.CallChain)),
+		attribute.Int("guards", len(exp.Guards)),
+	)
+
+	t.logger.Debug("explain_call_path completed",
+		slog.String("tool", "explain_call_path"),
+		slog.String("from", fromID),
+		slog.String("to", toID),
+		slog.Bool("reachable", exp.Reachable),
+		slog.Int("guards", len(exp.Guards)),
+	)
+
+	return &Result{
+		Success:    true,
+		Output:     exp,
+		OutputText: outputText,
+		TokensUsed: estimateTokens(outputText),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// parseParams validates and extracts typed parameters from the raw map.
+func (t *explainCallPathTool) parseParams(params map[string]any) (ExplainCallPathParams, error) {
+	p := ExplainCallPathParams{ContextLines: 2}
+
+	for _, name := range []string{"from", "to"} {
+		raw, ok := params[name]
+		if !ok {
+			return p, fmt.Errorf("%s parameter is required", name)
+		}
+		value, ok := parseStringParam(raw)
+		if !ok {
+			return p, fmt.Errorf("%s parameter must be a string", name)
+		}
+		if value == "" {
+			return p, fmt.Errorf("%s parameter cannot be empty", name)
+		}
+		if name == "from" {
+			p.From = value
+		} else {
+			p.To = value
+		}
+	}
+
+	if raw, ok := params["context_lines"]; ok {
+		if lines, ok := parseIntParam(raw); ok {
+			p.ContextLines = clampInt(lines, 0, maxCallPathContextLines)
+		}
+	}
+
+	return p, nil
+}
+
+// resolveSymbol resolves a symbol name or ID to a full ID.
+//
+// Full IDs are passed through and validated by the explainer. For ambiguous
+// names the first match is used, preferring functions and methods.
+func (t *explainCallPathTool) resolveSymbol(nameOrID string) (string, error) {
+	if t.index == nil {
+		return nameOrID, nil
+	}
+	if _, exists := t.index.GetByID(nameOrID); exists {
+		return nameOrID, nil
+	}
+	symbols := t.index.GetByName(nameOrID)
+	if len(symbols) == 0 {
+		return "", fmt.Errorf("symbol %q not found in graph", nameOrID)
+	}
+	for _, sym := range symbols {
+		if sym.Kind == ast.SymbolKindFunction || sym.Kind == ast.SymbolKindMethod {
+			return sym.ID, nil
+		}
+	}
+	return symbols[0].ID, nil
+}
+
+// formatText creates the text output, with citations inline so the answer
+// can quote them.
+func (t *explainCallPathTool) formatText(exp *explore.CallPathExplanation) string {
+	var sb strings.Builder
+	sb.Grow(200 + len(exp.CallChain)*200 + len(exp.Guards)*200)
+
+	if !exp.Reachable {
+		sb.WriteString(exp.Summary)
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	names := make([]string, len(exp.DominatingPath))
+	for i, s := range exp.DominatingPath {
+		names[i] = s.Name
+	}
+	sb.WriteString(fmt.Sprintf("Call Path: %s\n\n", strings.Join(names, " → ")))
+
+	sb.WriteString("Call Chain:\n")
+	for i, hop := range exp.CallChain {
+		marker := ""
+		if hop.Mandatory {
+			marker = " (mandatory)"
+		}
+		sb.WriteString(fmt.Sprintf("  %d. %s → %s%s", i+1,
+			extractNameFromNodeID(hop.CallerID), extractNameFromNodeID(hop.CalleeID), marker))
+		writeEvidence(&sb, hop.Evidence)
+	}
+
+	if len(exp.Guards) > 0 {
+		sb.WriteString("\nGuarding Conditions:\n")
+		for i, guard := range exp.Guards {
+			sb.WriteString(fmt.Sprintf("  %d. %s runs only if %s branches to it",
+				i+1, extractNameFromNodeID(guard.GuardedID), extractNameFromNodeID(guard.ControllerID)))
+			if guard.Condition != "" {
+				sb.WriteString(fmt.Sprintf(": `%s`", guard.Condition))
+			}
+			writeEvidence(&sb, guard.Evidence)
+		}
+	}
+
+	for _, limitation := range exp.Limitations {
+		sb.WriteString(fmt.Sprintf("\nNote: %s\n", limitation))
+	}
+
+	sb.WriteString("\nSummary: ")
+	sb.WriteString(exp.Summary)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// writeEvidence appends the citation and excerpt of an evidence snippet.
+func writeEvidence(sb *strings.Builder, ev *explore.EvidenceSnippet) {
+	if ev == nil {
+		sb.WriteString("\n")
+		return
+	}
+	sb.WriteString(" ")
+	sb.WriteString(ev.Citation)
+	sb.WriteString("\n")
+	for _, line := range strings.Split(strings.TrimRight(ev.Code, "\n"), "\n") {
+		if line != "" {
+			sb.WriteString("       ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+}
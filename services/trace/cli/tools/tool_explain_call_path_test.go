@@ -0,0 +1,90 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// explain_call_path Tool Tests
+// =============================================================================
+
+func TestExplainCallPathTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	g, idx := createTestGraphForCriticalPath(t)
+	hg, err := graph.WrapGraph(g)
+	if err != nil {
+		t.Fatalf("WrapGraph() error = %v", err)
+	}
+	tool := NewExplainCallPathTool(graph.NewGraphAnalytics(hg), g, idx)
+
+	t.Run("explains path by name", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{"from": "main", "to": "D"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+
+		exp, ok := result.Output.(*explore.CallPathExplanation)
+		if !ok {
+			t.Fatalf("Output type = %T, want *explore.CallPathExplanation", result.Output)
+		}
+		if !exp.Reachable || len(exp.DominatingPath) != 5 || len(exp.CallChain) != 4 {
+			t.Errorf("explanation = %+v, want 5-node path main → D", exp)
+		}
+		for _, want := range []string{"main → init → A → B → D", "[pkg/a.go:15]", "Summary:"} {
+			if !strings.Contains(result.OutputText, want) {
+				t.Errorf("OutputText missing %q:\n%s", want, result.OutputText)
+			}
+		}
+	})
+
+	t.Run("unreachable target", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{"from": "C", "to": "D"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success || !strings.Contains(result.OutputText, "not reachable") {
+			t.Errorf("result = %+v", result)
+		}
+	})
+
+	t.Run("parameter errors", func(t *testing.T) {
+		for name, params := range map[string]map[string]any{
+			"missing to":     {"from": "main"},
+			"empty from":     {"from": "", "to": "D"},
+			"unknown symbol": {"from": "main", "to": "Nope"},
+		} {
+			result, err := tool.Execute(ctx, params)
+			if err != nil {
+				t.Fatalf("%s: Execute() error = %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected failure", name)
+			}
+		}
+	})
+
+	t.Run("clamps context lines", func(t *testing.T) {
+		p, err := tool.(*explainCallPathTool).parseParams(map[string]any{"from": "a", "to": "b", "context_lines": 50})
+		if err != nil || p.ContextLines != maxCallPathContextLines {
+			t.Errorf("parseParams() = %+v, %v", p, err)
+		}
+	})
+}
@@ -0,0 +1,374 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// find_doc_coverage Tool - Typed Implementation
+// =============================================================================
+
+var findDocCoverageTracer = otel.Tracer("tools.find_doc_coverage")
+
+// FindDocCoverageParams contains the validated input parameters.
+type FindDocCoverageParams struct {
+	// Package restricts the report to a single package.
+	// Default: "" (all packages)
+	Package string
+
+	// Limit is the maximum number of packages to return.
+	// Default: 20, Max: 100
+	Limit int
+}
+
+// FindDocCoverageOutput contains the structured result.
+type FindDocCoverageOutput struct {
+	// Coverage is the project-wide fraction of documented symbols (0-1).
+	Coverage float64 `json:"coverage"`
+
+	// Documentable is the project-wide count of documentable symbols.
+	Documentable int `json:"documentable"`
+
+	// Documented is the project-wide count of documented symbols.
+	Documented int `json:"documented"`
+
+	// PackageCount is the number of packages returned.
+	PackageCount int `json:"package_count"`
+
+	// Packages lists per-package coverage, least documented first.
+	Packages []DocCoverageInfo `json:"packages"`
+}
+
+// DocCoverageInfo holds documentation coverage for one package.
+type DocCoverageInfo struct {
+	// Package is the package path.
+	Package string `json:"package"`
+
+	// Documentable is the number of documentable symbols.
+	Documentable int `json:"documentable"`
+
+	// Documented is the number of documentable symbols with a doc comment.
+	Documented int `json:"documented"`
+
+	// Coverage is Documented / Documentable (0-1).
+	Coverage float64 `json:"coverage"`
+
+	// Exported is the number of exported documentable symbols.
+	Exported int `json:"exported"`
+
+	// ExportedDocumented is the number of exported symbols with a doc comment.
+	ExportedDocumented int `json:"exported_documented"`
+
+	// ExportedCoverage is ExportedDocumented / Exported (0-1).
+	ExportedCoverage float64 `json:"exported_coverage"`
+}
+
+// findDocCoverageTool reports documentation coverage per package.
+type findDocCoverageTool struct {
+	analytics *graph.GraphAnalytics
+	logger    *slog.Logger
+}
+
+// NewFindDocCoverageTool creates the find_doc_coverage tool.
+//
+// Description:
+//
+//	Creates a tool that reports how many functions, methods, and types in
+//	each package carry a doc comment, with exported symbols counted
+//	separately. Packages are listed least documented first so gaps in the
+//	public API surface stand out.
+//
+// Inputs:
+//
+//   - analytics: The GraphAnalytics instance for coverage metrics. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The find_doc_coverage tool implementation.
+//
+// Limitations:
+//
+//   - Only counts doc comments the parsers attach to symbols
+//   - Packages without documentable symbols are omitted
+//   - Maximum 100 packages per query to prevent excessive output
+//
+// Assumptions:
+//
+//   - Graph is frozen before tool creation
+func NewFindDocCoverageTool(analytics *graph.GraphAnalytics) Tool {
+	return &findDocCoverageTool{
+		analytics: analytics,
+		logger:    slog.Default(),
+	}
+}
+
+func (t *findDocCoverageTool) Name() string {
+	return "find_doc_coverage"
+}
+
+func (t *findDocCoverageTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *findDocCoverageTool) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name: "find_doc_coverage",
+		Description: "Report documentation coverage per package. " +
+			"Counts functions, methods, and types with doc comments, with exported symbols counted separately. " +
+			"Packages are listed least documented first.",
+		Parameters: map[string]ParamDef{
+			"package": {
+				Type:        ParamTypeString,
+				Description: "Package to report on (default: all packages)",
+				Required:    false,
+			},
+			"limit": {
+				Type:        ParamTypeInt,
+				Description: "Maximum number of packages to return",
+				Required:    false,
+				Default:     20,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    70,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     15 * time.Second,
+	}
+}
+
+// Execute runs the find_doc_coverage tool.
+func (t *findDocCoverageTool) Execute(ctx context.Context, params map[string]any) (*Result, error) {
+	start := time.Now()
+
+	// Parse and validate parameters
+	p, err := t.parseParams(params)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	// Validate analytics is available
+	if t.analytics == nil {
+		return &Result{
+			Success: false,
+			Error:   "graph analytics not initialized",
+		}, nil
+	}
+
+	// Start span with context
+	ctx, span := findDocCoverageTracer.Start(ctx, "findDocCoverageTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "find_doc_coverage"),
+			attribute.String("package", p.Package),
+			attribute.Int("limit", p.Limit),
+		),
+	)
+	defer span.End()
+
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var metrics []graph.DocCoverageMetrics
+	var traceStep crs.TraceStep
+
+	if p.Package != "" {
+		m := t.analytics.GetDocCoverageForPackage(p.Package)
+		if m == nil {
+			errMsg := fmt.Sprintf("package %q not found in graph", p.Package)
+			traceStep = crs.NewTraceStepBuilder().
+				WithAction("analytics_doc_coverage").
+				WithTarget(p.Package).
+				WithTool("DocCoverage").
+				WithDuration(time.Since(start)).
+				WithError(errMsg).
+				Build()
+			return &Result{
+				Success:   false,
+				Error:     errMsg,
+				TraceStep: &traceStep,
+				Duration:  time.Since(start),
+			}, nil
+		}
+		metrics = append(metrics, *m)
+		traceStep = crs.NewTraceStepBuilder().
+			WithAction("analytics_doc_coverage").
+			WithTarget(p.Package).
+			WithTool("DocCoverage").
+			WithDuration(time.Since(start)).
+			WithMetadata("packages_analyzed", "1").
+			WithMetadata("coverage_pct", fmt.Sprintf("%d", int(m.Coverage*100))).
+			Build()
+	} else {
+		var all map[string]graph.DocCoverageMetrics
+		all, traceStep = t.analytics.DocCoverageWithCRS(ctx)
+		for _, m := range all {
+			if m.Documentable > 0 {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("packages_count", len(metrics)),
+		attribute.String("trace_action", traceStep.Action),
+	)
+
+	// Project totals are computed before the limit is applied
+	documentable, documented := 0, 0
+	for _, m := range metrics {
+		documentable += m.Documentable
+		documented += m.Documented
+	}
+
+	// Least documented first; ties broken by package path for stable output
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Coverage != metrics[j].Coverage {
+			return metrics[i].Coverage < metrics[j].Coverage
+		}
+		return metrics[i].Package < metrics[j].Package
+	})
+
+	if len(metrics) > p.Limit {
+		t.logger.Debug("doc coverage results limited",
+			slog.String("tool", "find_doc_coverage"),
+			slog.Int("raw_count", len(metrics)),
+			slog.Int("limit", p.Limit),
+		)
+		metrics = metrics[:p.Limit]
+	}
+
+	// Build typed output
+	output := t.buildOutput(metrics, documentable, documented)
+
+	// Format text output
+	outputText := t.formatText(output)
+
+	return &Result{
+		Success:    true,
+		Output:     output,
+		OutputText: outputText,
+		TokensUsed: estimateTokens(outputText),
+		TraceStep:  &traceStep,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// parseParams validates and extracts typed parameters from the raw map.
+func (t *findDocCoverageTool) parseParams(params map[string]any) (FindDocCoverageParams, error) {
+	p := FindDocCoverageParams{
+		Limit: 20,
+	}
+
+	// Extract package (optional)
+	if pkgRaw, ok := params["package"]; ok {
+		pkg, ok := pkgRaw.(string)
+		if !ok {
+			return p, fmt.Errorf("package must be a string")
+		}
+		p.Package = strings.TrimSpace(pkg)
+	}
+
+	// Extract limit (optional)
+	if limitRaw, ok := params["limit"]; ok {
+		if limit, ok := parseIntParam(limitRaw); ok {
+			if limit < 1 {
+				t.logger.Warn("limit below minimum, clamping to 1",
+					slog.String("tool", "find_doc_coverage"),
+					slog.Int("requested", limit),
+				)
+				limit = 1
+			} else if limit > 100 {
+				t.logger.Warn("limit above maximum, clamping to 100",
+					slog.String("tool", "find_doc_coverage"),
+					slog.Int("requested", limit),
+				)
+				limit = 100
+			}
+			p.Limit = limit
+		}
+	}
+
+	return p, nil
+}
+
+// buildOutput creates the typed output struct.
+func (t *findDocCoverageTool) buildOutput(metrics []graph.DocCoverageMetrics, documentable, documented int) FindDocCoverageOutput {
+	packages := make([]DocCoverageInfo, 0, len(metrics))
+	for _, m := range metrics {
+		packages = append(packages, DocCoverageInfo{
+			Package:            m.Package,
+			Documentable:       m.Documentable,
+			Documented:         m.Documented,
+			Coverage:           m.Coverage,
+			Exported:           m.Exported,
+			ExportedDocumented: m.ExportedDocumented,
+			ExportedCoverage:   m.ExportedCoverage,
+		})
+	}
+
+	coverage := 1.0
+	if documentable > 0 {
+		coverage = float64(documented) / float64(documentable)
+	}
+
+	return FindDocCoverageOutput{
+		Coverage:     coverage,
+		Documentable: documentable,
+		Documented:   documented,
+		PackageCount: len(packages),
+		Packages:     packages,
+	}
+}
+
+// formatText creates a human-readable text summary.
+func (t *findDocCoverageTool) formatText(output FindDocCoverageOutput) string {
+	var sb strings.Builder
+
+	if output.PackageCount == 0 {
+		sb.WriteString("No documentable symbols found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Documentation coverage: %.0f%% (%d of %d symbols documented)\n\n",
+		output.Coverage*100, output.Documented, output.Documentable))
+
+	sb.WriteString(fmt.Sprintf("Packages (least documented first, %d shown):\n", output.PackageCount))
+	for _, pkg := range output.Packages {
+		sb.WriteString(fmt.Sprintf("  %s: %.0f%% (%d/%d)", pkg.Package, pkg.Coverage*100, pkg.Documented, pkg.Documentable))
+		if pkg.Exported > 0 {
+			sb.WriteString(fmt.Sprintf(", exported %.0f%% (%d/%d)",
+				pkg.ExportedCoverage*100, pkg.ExportedDocumented, pkg.Exported))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// find_doc_coverage Tool Tests
+// =============================================================================
+
+// createTestAnalyticsForDocCoverage creates analytics over two packages:
+//
+//	api:      Serve (exported, documented), Route (exported, undocumented),
+//	          handle (unexported, documented)              -> 2/3 documented
+//	internal: load (documented), parse (undocumented),
+//	          Config struct (exported, undocumented)       -> 1/3 documented
+func createTestAnalyticsForDocCoverage(t *testing.T) *graph.GraphAnalytics {
+	t.Helper()
+
+	g := graph.NewGraph("/test")
+	symbols := []*ast.Symbol{
+		{ID: "api/serve.go:10:Serve", Name: "Serve", Kind: ast.SymbolKindFunction, FilePath: "api/serve.go", StartLine: 10, Package: "api", Exported: true, DocComment: "// Serve starts the server.", Language: "go"},
+		{ID: "api/serve.go:20:Route", Name: "Route", Kind: ast.SymbolKindFunction, FilePath: "api/serve.go", StartLine: 20, Package: "api", Exported: true, Language: "go"},
+		{ID: "api/serve.go:30:handle", Name: "handle", Kind: ast.SymbolKindFunction, FilePath: "api/serve.go", StartLine: 30, Package: "api", DocComment: "// handle dispatches a request.", Language: "go"},
+		{ID: "internal/load.go:10:load", Name: "load", Kind: ast.SymbolKindFunction, FilePath: "internal/load.go", StartLine: 10, Package: "internal", DocComment: "// load reads the file.", Language: "go"},
+		{ID: "internal/load.go:20:parse", Name: "parse", Kind: ast.SymbolKindFunction, FilePath: "internal/load.go", StartLine: 20, Package: "internal", Language: "go"},
+		{ID: "internal/load.go:30:Config", Name: "Config", Kind: ast.SymbolKindStruct, FilePath: "internal/load.go", StartLine: 30, Package: "internal", Exported: true, Language: "go"},
+	}
+	for _, sym := range symbols {
+		g.AddNode(sym)
+	}
+	g.AddEdge("api/serve.go:10:Serve", "internal/load.go:10:load", graph.EdgeTypeCalls, ast.Location{FilePath: "api/serve.go", StartLine: 12})
+	g.Freeze()
+
+	hg, err := graph.WrapGraph(g)
+	if err != nil {
+		t.Fatalf("WrapGraph failed: %v", err)
+	}
+	return graph.NewGraphAnalytics(hg)
+}
+
+func TestFindDocCoverageTool_Metadata(t *testing.T) {
+	tool := NewFindDocCoverageTool(createTestAnalyticsForDocCoverage(t))
+
+	if tool.Name() != "find_doc_coverage" {
+		t.Errorf("Name() = %s, want find_doc_coverage", tool.Name())
+	}
+	if tool.Category() != CategoryExploration {
+		t.Errorf("Category() = %s, want %s", tool.Category(), CategoryExploration)
+	}
+	def := tool.Definition()
+	if def.Name != "find_doc_coverage" {
+		t.Errorf("Definition.Name = %s, want find_doc_coverage", def.Name)
+	}
+	if _, ok := def.Parameters["package"]; !ok {
+		t.Error("Definition.Parameters missing package")
+	}
+}
+
+func TestFindDocCoverageTool_Execute_AllPackages(t *testing.T) {
+	tool := NewFindDocCoverageTool(createTestAnalyticsForDocCoverage(t))
+
+	result, err := tool.Execute(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute not successful: %s", result.Error)
+	}
+	if result.TraceStep == nil || result.TraceStep.Action != "analytics_doc_coverage" {
+		t.Errorf("TraceStep = %+v, want action analytics_doc_coverage", result.TraceStep)
+	}
+
+	output, ok := result.Output.(FindDocCoverageOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want FindDocCoverageOutput", result.Output)
+	}
+	if output.Documentable != 6 || output.Documented != 3 {
+		t.Errorf("totals = %d/%d, want 3/6", output.Documented, output.Documentable)
+	}
+	if output.PackageCount != 2 {
+		t.Fatalf("PackageCount = %d, want 2", output.PackageCount)
+	}
+	if got := output.Packages[0].Package; got != "internal" {
+		t.Errorf("first package = %s, want internal (least documented)", got)
+	}
+	if api := output.Packages[1]; api.Exported != 2 || api.ExportedDocumented != 1 {
+		t.Errorf("api exported = %d/%d, want 1/2", api.ExportedDocumented, api.Exported)
+	}
+	if !strings.Contains(result.OutputText, "50%") {
+		t.Errorf("OutputText missing overall coverage:\n%s", result.OutputText)
+	}
+}
+
+func TestFindDocCoverageTool_Execute_SinglePackage(t *testing.T) {
+	tool := NewFindDocCoverageTool(createTestAnalyticsForDocCoverage(t))
+	ctx := context.Background()
+
+	result, err := tool.Execute(ctx, map[string]any{"package": "api"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute not successful: %s", result.Error)
+	}
+	output := result.Output.(FindDocCoverageOutput)
+	if output.PackageCount != 1 || output.Packages[0].Package != "api" {
+		t.Fatalf("Packages = %+v, want only api", output.Packages)
+	}
+	if output.Documented != 2 || output.Documentable != 3 {
+		t.Errorf("totals = %d/%d, want 2/3", output.Documented, output.Documentable)
+	}
+
+	t.Run("unknown package fails", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{"package": "missing"})
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Error, "not found") {
+			t.Errorf("result = %+v, want not found failure", result)
+		}
+		if result.TraceStep == nil || result.TraceStep.Error == "" {
+			t.Error("expected a TraceStep recording the error")
+		}
+	})
+}
+
+func TestFindDocCoverageTool_Execute_Limit(t *testing.T) {
+	tool := NewFindDocCoverageTool(createTestAnalyticsForDocCoverage(t))
+
+	result, err := tool.Execute(context.Background(), map[string]any{"limit": 1})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	output := result.Output.(FindDocCoverageOutput)
+	if output.PackageCount != 1 || output.Packages[0].Package != "internal" {
+		t.Errorf("Packages = %+v, want only internal", output.Packages)
+	}
+	if output.Documentable != 6 {
+		t.Errorf("Documentable = %d, want project total 6 regardless of limit", output.Documentable)
+	}
+}
+
+func TestFindDocCoverageTool_Execute_NilAnalytics(t *testing.T) {
+	tool := NewFindDocCoverageTool(nil)
+
+	result, err := tool.Execute(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("Execute should not return error, got: %v", err)
+	}
+	if result.Success {
+		t.Error("Expected success=false with nil analytics")
+	}
+}
+
+func TestFindDocCoverageTool_Execute_ContextCancellation(t *testing.T) {
+	tool := NewFindDocCoverageTool(createTestAnalyticsForDocCoverage(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := tool.Execute(ctx, map[string]any{}); err == nil {
+		t.Error("Expected context cancellation error, got nil")
+	}
+}
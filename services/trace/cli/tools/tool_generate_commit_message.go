@@ -0,0 +1,259 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/review"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/commitmsg"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// =============================================================================
+// generate_commit_message Tool - Typed Implementation
+// =============================================================================
+
+var generateCommitMessageTracer = otel.Tracer("tools.generate_commit_message")
+
+// GenerateCommitMessageParams contains the validated input parameters.
+type GenerateCommitMessageParams struct {
+	// Diff is the unified diff to describe.
+	Diff string
+
+	// Style is the built-in message style (conventional, simple, detailed).
+	Style string
+
+	// Type overrides the inferred commit type (optional).
+	Type string
+
+	// Scope overrides the inferred scope (optional).
+	Scope string
+}
+
+// generateCommitMessageTool writes a commit message and changelog fragment
+// for a diff, using the graph's blast radius to rank changed symbols.
+//
+// Thread Safety: Safe for concurrent use.
+type generateCommitMessageTool struct {
+	graph  *graph.Graph
+	index  *index.SymbolIndex
+	logger *slog.Logger
+}
+
+// NewGenerateCommitMessageTool creates the generate_commit_message tool.
+//
+// Inputs:
+//
+//   - g: The code graph of the changed tree. Must be frozen.
+//   - idx: The symbol index.
+//
+// Outputs:
+//
+//   - Tool: The generate_commit_message tool implementation.
+func NewGenerateCommitMessageTool(g *graph.Graph, idx *index.SymbolIndex) Tool {
+	return &generateCommitMessageTool{
+		graph:  g,
+		index:  idx,
+		logger: slog.Default(),
+	}
+}
+
+func (t *generateCommitMessageTool) Name() string {
+	return "generate_commit_message"
+}
+
+func (t *generateCommitMessageTool) Category() ToolCategory {
+	return CategoryReasoning
+}
+
+func (t *generateCommitMessageTool) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name: "generate_commit_message",
+		Description: "Generate a conventional-commit message and changelog fragment for a unified diff. " +
+			"Changed symbols are ranked by blast radius, and removing exported symbols that still " +
+			"have callers is flagged as a breaking change.",
+		Parameters: map[string]ParamDef{
+			"diff": {
+				Type:        ParamTypeString,
+				Description: "Unified diff to describe (e.g. output of 'git diff --cached')",
+				Required:    true,
+			},
+			"style": {
+				Type:        ParamTypeString,
+				Description: "Message style: conventional, simple, or detailed",
+				Required:    false,
+				Default:     commitmsg.StyleConventional,
+				Enum:        []any{commitmsg.StyleConventional, commitmsg.StyleSimple, commitmsg.StyleDetailed},
+			},
+			"type": {
+				Type:        ParamTypeString,
+				Description: "Override the inferred commit type (feat, fix, refactor, ...)",
+				Required:    false,
+			},
+			"scope": {
+				Type:        ParamTypeString,
+				Description: "Override the inferred scope ('-' for none)",
+				Required:    false,
+			},
+		},
+		Category:    CategoryReasoning,
+		Priority:    60,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Execute runs the generate_commit_message tool.
+func (t *generateCommitMessageTool) Execute(ctx context.Context, params map[string]any) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params)
+	if err != nil {
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+
+	ctx, span := generateCommitMessageTracer.Start(ctx, "generateCommitMessageTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "generate_commit_message"),
+			attribute.String("style", p.Style),
+			attribute.Int("diff_bytes", len(p.Diff)),
+		),
+	)
+	defer span.End()
+
+	changes, err := review.ParseDiff(p.Diff)
+	if err != nil {
+		return &Result{Success: false, Error: fmt.Sprintf("invalid diff: %v", err)}, nil
+	}
+	analysis, err := review.NewReviewer(t.graph, t.index).Analyze(ctx, changes)
+	if err != nil {
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+
+	gen, err := commitmsg.NewGenerator(commitmsg.Options{Style: p.Style, Type: p.Type, Scope: p.Scope})
+	if err != nil {
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+	output, err := gen.Generate(CommitInputFromReview(changes, analysis))
+	if err != nil {
+		return &Result{Success: false, Error: err.Error()}, nil
+	}
+
+	outputText := output.CommitText + "\nChangelog fragment:\n" + output.Changelog
+	span.SetAttributes(
+		attribute.String("commit_type", output.Message.Type),
+		attribute.Bool("breaking", output.Message.Breaking),
+	)
+
+	return &Result{
+		Success:    true,
+		Output:     output,
+		OutputText: outputText,
+		TokensUsed: estimateTokens(outputText),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// CommitInputFromReview converts a parsed diff and its graph review into
+// commit message input.
+//
+// Symbols in new files are reported as added and symbols in deleted files
+// as removed; all others as modified.
+func CommitInputFromReview(changes []*diff.ProposedChange, analysis *review.Result) commitmsg.Input {
+	in := commitmsg.Input{RiskLevel: analysis.RiskLevel}
+
+	newFiles := make(map[string]bool)
+	for _, c := range changes {
+		status := commitmsg.StatusModified
+		switch {
+		case c.IsNew:
+			status = commitmsg.StatusAdded
+			newFiles[c.FilePath] = true
+		case c.IsDelete:
+			status = commitmsg.StatusDeleted
+		}
+		in.Files = append(in.Files, commitmsg.FileChange{Path: c.FilePath, Status: status})
+	}
+
+	tests := make(map[string]bool)
+	for _, cs := range analysis.ChangedSymbols {
+		status := commitmsg.StatusModified
+		switch {
+		case cs.Deleted:
+			status = commitmsg.StatusDeleted
+		case newFiles[cs.FilePath]:
+			status = commitmsg.StatusAdded
+		}
+		in.Symbols = append(in.Symbols, commitmsg.SymbolChange{
+			Name:      cs.Name,
+			Kind:      cs.Kind,
+			FilePath:  cs.FilePath,
+			Exported:  cs.Exported,
+			Status:    status,
+			Callers:   cs.DirectCallers + cs.IndirectCallers,
+			RiskLevel: cs.RiskLevel,
+		})
+		for _, tf := range cs.TestFiles {
+			tests[tf] = true
+		}
+	}
+	for tf := range tests {
+		in.AffectedTests = append(in.AffectedTests, tf)
+	}
+	sort.Strings(in.AffectedTests)
+
+	return in
+}
+
+// parseParams validates and extracts typed parameters from the raw map.
+func (t *generateCommitMessageTool) parseParams(params map[string]any) (GenerateCommitMessageParams, error) {
+	p := GenerateCommitMessageParams{Style: commitmsg.StyleConventional}
+
+	if diffRaw, ok := params["diff"]; ok {
+		if d, ok := parseStringParam(diffRaw); ok {
+			p.Diff = d
+		}
+	}
+	if strings.TrimSpace(p.Diff) == "" {
+		return p, fmt.Errorf("diff is required")
+	}
+
+	if styleRaw, ok := params["style"]; ok {
+		if style, ok := parseStringParam(styleRaw); ok && style != "" {
+			p.Style = strings.ToLower(style)
+		}
+	}
+	if typeRaw, ok := params["type"]; ok {
+		if typ, ok := parseStringParam(typeRaw); ok {
+			p.Type = strings.ToLower(strings.TrimSpace(typ))
+		}
+	}
+	if scopeRaw, ok := params["scope"]; ok {
+		if scope, ok := parseStringParam(scopeRaw); ok {
+			p.Scope = strings.TrimSpace(scope)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,304 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/search"
+)
+
+// =============================================================================
+// search_code Tool - Typed Implementation
+// =============================================================================
+
+var searchCodeTracer = otel.Tracer("tools.search_code")
+
+// SearchCodeParams contains the validated input parameters.
+type SearchCodeParams struct {
+	// Query is the partial name, misspelled name, or words to search for.
+	Query string
+
+	// Kind filters by symbol kind (function, method, struct, interface, ...), or all.
+	Kind string
+
+	// Path restricts results to files under this path prefix (optional).
+	Path string
+
+	// Limit is the maximum number of results.
+	Limit int
+}
+
+// SearchCodeOutput contains the structured result.
+type SearchCodeOutput struct {
+	// Query is the query that was searched for.
+	Query string `json:"query"`
+
+	// MatchCount is the number of results returned.
+	MatchCount int `json:"match_count"`
+
+	// Results are ranked best match first.
+	Results []search.Result `json:"results"`
+}
+
+// searchCodeTool performs hybrid lexical + structural symbol search.
+//
+// Description:
+//
+//	Unlike find_symbol, which requires an exact name, search_code accepts
+//	partial names, identifier words ("parse request"), and typos, and ranks
+//	matches by name similarity boosted by call-graph in-degree.
+//
+// Thread Safety: Safe for concurrent use. The search index is built once on
+// first use and is read-only afterwards.
+type searchCodeTool struct {
+	graph  *graph.Graph
+	index  *index.SymbolIndex
+	logger *slog.Logger
+
+	once   sync.Once
+	search *search.Index
+}
+
+// NewSearchCodeTool creates the search_code tool.
+//
+// Description:
+//
+//	Creates a tool that searches symbol names with trigram/lexical matching,
+//	kind and path filters, and centrality boosting.
+//
+// Inputs:
+//
+//   - g: The code graph. Must not be nil.
+//   - idx: The symbol index. May be nil.
+//
+// Outputs:
+//
+//   - Tool: The search_code tool implementation.
+//
+// Assumptions:
+//
+//   - Graph is frozen before the first Execute call
+func NewSearchCodeTool(g *graph.Graph, idx *index.SymbolIndex) Tool {
+	return &searchCodeTool{
+		graph:  g,
+		index:  idx,
+		logger: slog.Default(),
+	}
+}
+
+func (t *searchCodeTool) Name() string {
+	return "search_code"
+}
+
+func (t *searchCodeTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *searchCodeTool) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name: "search_code",
+		Description: "Search for symbols by partial name, identifier words, or misspelled name. " +
+			"Results are ranked by name similarity and boosted by how often the symbol is used. " +
+			"Use this when you don't know the exact name; use find_symbol when you do.",
+		Parameters: map[string]ParamDef{
+			"query": {
+				Type:        ParamTypeString,
+				Description: "Name fragment or words to search for (e.g. 'validate token', 'HandleReq')",
+				Required:    true,
+			},
+			"kind": {
+				Type:        ParamTypeString,
+				Description: "Filter by symbol kind: function, method, struct, interface, type, variable, constant, or all",
+				Required:    false,
+				Default:     "all",
+				Enum:        []any{"function", "method", "struct", "interface", "type", "variable", "constant", "all"},
+			},
+			"path": {
+				Type:        ParamTypeString,
+				Description: "Restrict results to files under this path prefix (optional)",
+				Required:    false,
+			},
+			"limit": {
+				Type:        ParamTypeInt,
+				Description: "Maximum number of results (default 20)",
+				Required:    false,
+				Default:     search.DefaultLimit,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    91,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     5 * time.Second,
+	}
+}
+
+// Execute runs the search_code tool.
+func (t *searchCodeTool) Execute(ctx context.Context, params map[string]any) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	ctx, span := searchCodeTracer.Start(ctx, "searchCodeTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "search_code"),
+			attribute.String("query", p.Query),
+			attribute.String("kind", p.Kind),
+			attribute.String("path", p.Path),
+		),
+	)
+	defer span.End()
+
+	t.once.Do(t.buildIndex)
+
+	q := search.Query{
+		Text:       p.Query,
+		PathPrefix: p.Path,
+		Limit:      p.Limit,
+	}
+	if p.Kind != "all" {
+		q.Kinds = []string{p.Kind}
+	}
+
+	results, err := t.search.Search(ctx, q)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	output := SearchCodeOutput{
+		Query:      p.Query,
+		MatchCount: len(results),
+		Results:    results,
+	}
+	outputText := t.formatText(p.Query, results)
+
+	span.SetAttributes(attribute.Int("match_count", len(results)))
+
+	return &Result{
+		Success:    true,
+		Output:     output,
+		OutputText: outputText,
+		TokensUsed: estimateTokens(outputText),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// buildIndex converts graph nodes to search documents. Centrality is the
+// node's in-degree, so widely used symbols rank higher.
+func (t *searchCodeTool) buildIndex() {
+	var docs []search.Document
+	if t.graph != nil {
+		for _, node := range t.graph.Nodes() {
+			if node == nil || node.Symbol == nil {
+				continue
+			}
+			sym := node.Symbol
+			if sym.Kind == ast.SymbolKindImport || sym.Kind == ast.SymbolKindParameter {
+				continue
+			}
+			docs = append(docs, search.Document{
+				ID:         sym.ID,
+				Name:       sym.Name,
+				Kind:       sym.Kind.String(),
+				FilePath:   sym.FilePath,
+				Line:       sym.StartLine,
+				Signature:  sym.Signature,
+				Centrality: float64(len(node.Incoming)),
+			})
+		}
+	}
+	t.search = search.NewIndex(docs, search.DefaultOptions())
+	t.logger.Debug("search_code index built", slog.Int("documents", len(docs)))
+}
+
+// parseParams validates and extracts typed parameters from the raw map.
+func (t *searchCodeTool) parseParams(params map[string]any) (SearchCodeParams, error) {
+	p := SearchCodeParams{
+		Kind:  "all",
+		Limit: search.DefaultLimit,
+	}
+
+	if queryRaw, ok := params["query"]; ok {
+		if query, ok := parseStringParam(queryRaw); ok {
+			p.Query = strings.TrimSpace(query)
+		}
+	}
+	if p.Query == "" {
+		return p, fmt.Errorf("query is required")
+	}
+
+	if kindRaw, ok := params["kind"]; ok {
+		if kind, ok := parseStringParam(kindRaw); ok && kind != "" {
+			p.Kind = strings.ToLower(kind)
+		}
+	}
+
+	if pathRaw, ok := params["path"]; ok {
+		if path, ok := parseStringParam(pathRaw); ok {
+			p.Path = path
+		}
+	}
+
+	if limitRaw, ok := params["limit"]; ok {
+		if limit, ok := parseIntParam(limitRaw); ok && limit > 0 {
+			if limit > 100 {
+				limit = 100
+			}
+			p.Limit = limit
+		}
+	}
+
+	return p, nil
+}
+
+// formatText creates a human-readable text summary.
+func (t *searchCodeTool) formatText(query string, results []search.Result) string {
+	var sb strings.Builder
+
+	if len(results) == 0 {
+		sb.WriteString(fmt.Sprintf("No symbols found matching '%s'.\n", query))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d symbols matching '%s' (best first):\n\n", len(results), query))
+	for _, r := range results {
+		sb.WriteString(fmt.Sprintf("• %s (%s) — %s match, score %.2f\n", r.Name, r.Kind, r.Match, r.Score))
+		sb.WriteString(fmt.Sprintf("  Location: %s:%d\n", r.FilePath, r.Line))
+		if r.Signature != "" {
+			sb.WriteString(fmt.Sprintf("  Signature: %s\n", r.Signature))
+		}
+		sb.WriteString(fmt.Sprintf("  ID: %s\n", r.ID))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/search"
+)
+
+func TestSearchCodeTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	g, idx := createTestGraphWithCallers(t)
+
+	tool := NewSearchCodeTool(g, idx)
+
+	t.Run("ranks partial name matches", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{
+			"query": "config",
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+
+		output, ok := result.Output.(SearchCodeOutput)
+		if !ok {
+			t.Fatalf("Output is not SearchCodeOutput, got %T", result.Output)
+		}
+		if output.MatchCount < 2 {
+			t.Fatalf("got %d matches, want at least 2", output.MatchCount)
+		}
+		for _, r := range output.Results {
+			if r.Match == search.MatchFuzzy {
+				continue
+			}
+			if r.Name != "parseConfig" && r.Name != "LoadConfig" {
+				t.Errorf("unexpected non-fuzzy match %q", r.Name)
+			}
+		}
+	})
+
+	t.Run("tolerates typos", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{
+			"query": "parseConfg",
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		output := result.Output.(SearchCodeOutput)
+		if output.MatchCount == 0 || output.Results[0].Name != "parseConfig" {
+			t.Errorf("expected parseConfig first, got %+v", output.Results)
+		}
+	})
+
+	t.Run("filters by kind", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{
+			"query": "handler",
+			"kind":  "interface",
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		output := result.Output.(SearchCodeOutput)
+		for _, r := range output.Results {
+			if r.Kind != "interface" {
+				t.Errorf("got kind %q, want interface", r.Kind)
+			}
+		}
+	})
+
+	t.Run("requires query", func(t *testing.T) {
+		result, err := tool.Execute(ctx, map[string]any{})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure without query")
+		}
+	})
+}
@@ -0,0 +1,512 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package commitmsg generates commit messages and changelog fragments from
+// a change set and its impact analysis.
+//
+// # Description
+//
+// The generator classifies the change (feat, fix, refactor, test, docs,
+// ci, build, chore), picks a scope from the most-touched package, writes an
+// imperative subject naming the most important changed symbols, and adds a
+// body summarizing the blast radius. Removing exported symbols that still
+// have callers marks the change as breaking.
+//
+// Output is rendered through text/template, so teams can supply their own
+// commit and changelog templates; three built-in styles are provided
+// (conventional, simple, detailed).
+//
+// The package depends only on the standard library so both the CLI
+// (`aleutian commit-msg`, fed by the on-disk index) and the agent tool
+// (fed by the code graph) share the same wording.
+//
+// # Thread Safety
+//
+// Generator is immutable after NewGenerator and safe for concurrent use.
+package commitmsg
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// MaxSubjectLength is the maximum length of the commit header line.
+const MaxSubjectLength = 72
+
+// maxSubjectSymbols is how many symbols are named in the subject.
+const maxSubjectSymbols = 2
+
+// maxBodySymbols is how many symbols are listed in the body.
+const maxBodySymbols = 5
+
+// Built-in style names.
+const (
+	StyleConventional = "conventional"
+	StyleSimple       = "simple"
+	StyleDetailed     = "detailed"
+)
+
+// Styles returns the built-in style names.
+func Styles() []string {
+	return []string{StyleConventional, StyleSimple, StyleDetailed}
+}
+
+// commitTemplates are the built-in commit message templates.
+var commitTemplates = map[string]string{
+	StyleConventional: `{{.Message.Header}}
+{{- if .Message.Body}}
+
+{{range .Message.Body}}- {{.}}
+{{end}}{{end}}
+{{- if .Message.Breaking}}
+BREAKING CHANGE: {{.Message.BreakingNote}}
+{{end}}`,
+
+	StyleSimple: `{{capitalize .Message.Subject}}
+{{- if .Message.Breaking}}
+
+BREAKING: {{.Message.BreakingNote}}
+{{end}}`,
+
+	StyleDetailed: `{{.Message.Header}}
+{{- if .Message.Body}}
+
+{{range .Message.Body}}- {{.}}
+{{end}}{{end}}
+Impact: {{if .Input.RiskLevel}}{{.Input.RiskLevel}}{{else}}LOW{{end}} risk, {{len .Input.Files}} file(s), {{len .Input.Symbols}} symbol(s){{if .Input.AffectedTests}}, {{len .Input.AffectedTests}} affected test file(s){{end}}
+{{- if .Input.AffectedTests}}
+
+Tests to run:
+{{range .Input.AffectedTests}}  {{.}}
+{{end}}{{end}}
+{{- if .Message.Breaking}}
+BREAKING CHANGE: {{.Message.BreakingNote}}
+{{end}}`,
+}
+
+// DefaultChangelogTemplate renders a Keep a Changelog fragment.
+const DefaultChangelogTemplate = `### {{.Message.ChangelogSection}}
+
+- {{if .Message.Scope}}**{{.Message.Scope}}:** {{end}}{{capitalize .Message.Subject}}{{if .Message.Breaking}} (**breaking:** {{.Message.BreakingNote}}){{end}}
+`
+
+// Options configures a Generator.
+type Options struct {
+	// Style is a built-in style name. Ignored if CommitTemplate is set.
+	// Default: StyleConventional
+	Style string
+
+	// CommitTemplate is custom text/template source for the commit message.
+	// It is executed with templateData{Message, Input}.
+	CommitTemplate string
+
+	// ChangelogTemplate is custom text/template source for the changelog
+	// fragment. Default: DefaultChangelogTemplate
+	ChangelogTemplate string
+
+	// Type overrides the inferred commit type.
+	Type string
+
+	// Scope overrides the inferred scope. Use "-" for no scope.
+	Scope string
+}
+
+// Generator renders commit messages and changelog fragments.
+type Generator struct {
+	commit    *template.Template
+	changelog *template.Template
+	opts      Options
+}
+
+// templateData is passed to templates.
+type templateData struct {
+	Message *Message
+	Input   *Input
+}
+
+var templateFuncs = template.FuncMap{
+	"capitalize": capitalize,
+	"join":       strings.Join,
+}
+
+// NewGenerator creates a generator.
+//
+// # Outputs
+//
+//   - *Generator: The generator.
+//   - error: ErrUnknownStyle, or a template parse error.
+func NewGenerator(opts Options) (*Generator, error) {
+	commitSrc := opts.CommitTemplate
+	if strings.TrimSpace(commitSrc) == "" {
+		style := opts.Style
+		if style == "" {
+			style = StyleConventional
+		}
+		src, ok := commitTemplates[style]
+		if !ok {
+			return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownStyle, style, strings.Join(Styles(), ", "))
+		}
+		commitSrc = src
+	}
+	changelogSrc := opts.ChangelogTemplate
+	if strings.TrimSpace(changelogSrc) == "" {
+		changelogSrc = DefaultChangelogTemplate
+	}
+
+	commit, err := template.New("commit").Funcs(templateFuncs).Parse(commitSrc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing commit template: %w", err)
+	}
+	changelog, err := template.New("changelog").Funcs(templateFuncs).Parse(changelogSrc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing changelog template: %w", err)
+	}
+	return &Generator{commit: commit, changelog: changelog, opts: opts}, nil
+}
+
+// Generate builds and renders the commit message for in.
+//
+// # Outputs
+//
+//   - *Output: Structured message plus rendered commit and changelog text.
+//   - error: ErrNoChanges if in has no files, or a template error.
+func (g *Generator) Generate(in Input) (*Output, error) {
+	if len(in.Files) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	msg := Describe(in)
+	if g.opts.Type != "" {
+		msg.Type = g.opts.Type
+		msg.ChangelogSection = changelogSection(msg.Type, msg.Breaking)
+	}
+	switch g.opts.Scope {
+	case "":
+	case "-":
+		msg.Scope = ""
+	default:
+		msg.Scope = g.opts.Scope
+	}
+	msg.Subject = fitSubject(msg)
+
+	data := templateData{Message: msg, Input: &in}
+	var commit, changelog bytes.Buffer
+	if err := g.commit.Execute(&commit, data); err != nil {
+		return nil, fmt.Errorf("rendering commit template: %w", err)
+	}
+	if err := g.changelog.Execute(&changelog, data); err != nil {
+		return nil, fmt.Errorf("rendering changelog template: %w", err)
+	}
+
+	return &Output{
+		Message:    *msg,
+		CommitText: strings.TrimRight(commit.String(), "\n") + "\n",
+		Changelog:  changelog.String(),
+	}, nil
+}
+
+// Describe infers the commit type, scope, subject, body, and breaking note.
+func Describe(in Input) *Message {
+	msg := &Message{
+		Type:  classify(in),
+		Scope: inferScope(in.Files),
+	}
+
+	var removed []SymbolChange
+	for _, s := range in.Symbols {
+		if s.Status == StatusDeleted && s.Exported && s.Callers > 0 {
+			removed = append(removed, s)
+		}
+	}
+	if len(removed) > 0 {
+		msg.Breaking = true
+		names := symbolNames(removed, maxSubjectSymbols)
+		msg.BreakingNote = fmt.Sprintf("removes %s, still used by %d caller(s)", names, totalCallers(removed))
+	}
+
+	msg.Subject = subject(msg.Type, in)
+	msg.Body = body(in)
+	msg.ChangelogSection = changelogSection(msg.Type, msg.Breaking)
+	return msg
+}
+
+// classify infers the conventional-commit type.
+func classify(in Input) string {
+	counts := make(map[string]int)
+	for _, f := range in.Files {
+		counts[fileCategory(f.Path)]++
+	}
+	if counts["code"] == 0 {
+		for _, cat := range []string{"test", "docs", "ci", "build"} {
+			if counts[cat] == len(in.Files) {
+				return cat
+			}
+		}
+		return "chore"
+	}
+
+	var added, deleted, modified int
+	for _, s := range in.Symbols {
+		switch s.Status {
+		case StatusAdded:
+			if s.Exported {
+				added++
+			}
+		case StatusDeleted:
+			deleted++
+		default:
+			modified++
+		}
+	}
+	for _, f := range in.Files {
+		if fileCategory(f.Path) == "code" && f.Status == StatusAdded && len(in.Symbols) == 0 {
+			added++
+		}
+	}
+
+	switch {
+	case added > 0:
+		return "feat"
+	case modified == 0 && deleted > 0:
+		return "refactor"
+	case allCodeRenamed(in.Files):
+		return "refactor"
+	default:
+		return "fix"
+	}
+}
+
+// allCodeRenamed reports whether every code file was only renamed.
+func allCodeRenamed(files []FileChange) bool {
+	for _, f := range files {
+		if fileCategory(f.Path) == "code" && f.Status != StatusRenamed {
+			return false
+		}
+	}
+	return true
+}
+
+// fileCategory buckets a path as code, test, docs, ci, or build.
+func fileCategory(p string) string {
+	base := path.Base(p)
+	lower := strings.ToLower(p)
+	ext := strings.ToLower(path.Ext(p))
+
+	switch {
+	case strings.HasPrefix(lower, ".github/") || base == ".gitlab-ci.yml" || base == "Jenkinsfile" ||
+		strings.HasPrefix(lower, ".circleci/"):
+		return "ci"
+	case base == "go.mod" || base == "go.sum" || base == "Makefile" || strings.HasPrefix(base, "Dockerfile") ||
+		base == "package.json" || strings.HasSuffix(base, ".lock") || base == "package-lock.json" ||
+		base == "pyproject.toml" || base == "requirements.txt" || base == "Cargo.toml":
+		return "build"
+	case strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(lower, "tests/") || strings.Contains(lower, "/tests/") || strings.Contains(lower, "/testdata/"):
+		return "test"
+	case ext == ".md" || ext == ".rst" || ext == ".adoc" || base == "LICENSE" ||
+		strings.HasPrefix(lower, "docs/") || strings.Contains(lower, "/docs/"):
+		return "docs"
+	default:
+		return "code"
+	}
+}
+
+// inferScope returns the base name of the most-changed directory among code
+// files (or all files if there is no code).
+func inferScope(files []FileChange) string {
+	dirs := make(map[string]int)
+	for _, f := range files {
+		if fileCategory(f.Path) == "code" {
+			dirs[path.Dir(f.Path)]++
+		}
+	}
+	if len(dirs) == 0 {
+		for _, f := range files {
+			dirs[path.Dir(f.Path)]++
+		}
+	}
+
+	best, bestCount := "", 0
+	for dir, n := range dirs {
+		if n > bestCount || (n == bestCount && dir < best) {
+			best, bestCount = dir, n
+		}
+	}
+	if best == "." || best == "" {
+		return ""
+	}
+	return path.Base(best)
+}
+
+// subject writes the imperative description.
+func subject(typ string, in Input) string {
+	ranked := rankSymbols(in.Symbols)
+
+	var verb string
+	var subjects []SymbolChange
+	switch typ {
+	case "feat":
+		verb = "add"
+		subjects = filterStatus(ranked, StatusAdded)
+	case "refactor":
+		verb = "remove"
+		subjects = filterStatus(ranked, StatusDeleted)
+		if len(subjects) == 0 {
+			verb = "move"
+		}
+	case "test":
+		return "update tests for " + fileList(in.Files)
+	case "docs":
+		return "update " + fileList(in.Files)
+	default:
+		verb = "update"
+		subjects = ranked
+	}
+
+	if len(subjects) > 0 {
+		return verb + " " + symbolNames(subjects, maxSubjectSymbols)
+	}
+	return verb + " " + fileList(in.Files)
+}
+
+// body lists the most impactful changed symbols.
+func body(in Input) []string {
+	ranked := rankSymbols(in.Symbols)
+	var lines []string
+	for i, s := range ranked {
+		if i == maxBodySymbols {
+			lines = append(lines, fmt.Sprintf("and %d more symbol(s)", len(ranked)-maxBodySymbols))
+			break
+		}
+		line := fmt.Sprintf("%s %s (%s", capitalize(statusVerb(s.Status)), s.Name, s.Kind)
+		if s.Callers > 0 {
+			line += fmt.Sprintf(", %d caller(s)", s.Callers)
+		}
+		if s.RiskLevel != "" && s.RiskLevel != "LOW" {
+			line += ", " + s.RiskLevel + " risk"
+		}
+		lines = append(lines, line+")")
+	}
+	return lines
+}
+
+// rankSymbols orders symbols by exported first, then callers, then name.
+func rankSymbols(symbols []SymbolChange) []SymbolChange {
+	ranked := append([]SymbolChange(nil), symbols...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Exported != b.Exported {
+			return a.Exported
+		}
+		if a.Callers != b.Callers {
+			return a.Callers > b.Callers
+		}
+		return a.Name < b.Name
+	})
+	return ranked
+}
+
+func filterStatus(symbols []SymbolChange, status Status) []SymbolChange {
+	var out []SymbolChange
+	for _, s := range symbols {
+		if s.Status == status {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// symbolNames joins up to n names: "A", "A and B", "A, B and 3 more".
+func symbolNames(symbols []SymbolChange, n int) string {
+	names := make([]string, 0, n)
+	for i, s := range symbols {
+		if i == n {
+			break
+		}
+		names = append(names, s.Name)
+	}
+	switch extra := len(symbols) - len(names); {
+	case extra > 0:
+		return strings.Join(names, ", ") + fmt.Sprintf(" and %d more", extra)
+	case len(names) == 2:
+		return names[0] + " and " + names[1]
+	default:
+		return strings.Join(names, ", ")
+	}
+}
+
+// fileList names a single file or counts several.
+func fileList(files []FileChange) string {
+	if len(files) == 1 {
+		return path.Base(files[0].Path)
+	}
+	return fmt.Sprintf("%d files", len(files))
+}
+
+func totalCallers(symbols []SymbolChange) int {
+	n := 0
+	for _, s := range symbols {
+		n += s.Callers
+	}
+	return n
+}
+
+func statusVerb(s Status) string {
+	switch s {
+	case StatusAdded:
+		return "add"
+	case StatusDeleted:
+		return "remove"
+	default:
+		return "change"
+	}
+}
+
+// changelogSection maps a commit type to a Keep a Changelog heading.
+func changelogSection(typ string, breaking bool) string {
+	switch {
+	case breaking:
+		return "Removed"
+	case typ == "feat":
+		return "Added"
+	case typ == "fix":
+		return "Fixed"
+	default:
+		return "Changed"
+	}
+}
+
+// fitSubject truncates the subject so the header fits MaxSubjectLength.
+func fitSubject(m *Message) string {
+	over := len(m.Header()) - MaxSubjectLength
+	if over <= 0 {
+		return m.Subject
+	}
+	keep := len(m.Subject) - over - 3
+	if keep < 10 {
+		keep = 10
+	}
+	if keep >= len(m.Subject) {
+		return m.Subject
+	}
+	return strings.TrimSpace(m.Subject[:keep]) + "..."
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package commitmsg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDescribe_Classification(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        Input
+		wantType  string
+		wantScope string
+	}{
+		{
+			name: "new exported symbol is a feature",
+			in: Input{
+				Files:   []FileChange{{Path: "services/trace/search/search.go", Status: StatusModified}},
+				Symbols: []SymbolChange{{Name: "Search", Kind: "function", Exported: true, Status: StatusAdded}},
+			},
+			wantType:  "feat",
+			wantScope: "search",
+		},
+		{
+			name: "modified symbols are a fix",
+			in: Input{
+				Files:   []FileChange{{Path: "pkg/auth/token.go", Status: StatusModified}},
+				Symbols: []SymbolChange{{Name: "Validate", Kind: "function", Exported: true, Status: StatusModified}},
+			},
+			wantType:  "fix",
+			wantScope: "auth",
+		},
+		{
+			name:     "tests only",
+			in:       Input{Files: []FileChange{{Path: "pkg/auth/token_test.go"}}},
+			wantType: "test",
+		},
+		{
+			name:     "docs only",
+			in:       Input{Files: []FileChange{{Path: "README.md"}, {Path: "docs/guide.md"}}},
+			wantType: "docs",
+		},
+		{
+			name:     "ci only",
+			in:       Input{Files: []FileChange{{Path: ".github/workflows/ci.yml"}}},
+			wantType: "ci",
+		},
+		{
+			name:     "deps only",
+			in:       Input{Files: []FileChange{{Path: "go.mod"}, {Path: "go.sum"}}},
+			wantType: "build",
+		},
+		{
+			name: "code plus tests classifies by code",
+			in: Input{
+				Files: []FileChange{
+					{Path: "pkg/a/a.go", Status: StatusModified},
+					{Path: "pkg/a/a_test.go", Status: StatusModified},
+				},
+				Symbols: []SymbolChange{{Name: "A", Status: StatusModified}},
+			},
+			wantType:  "fix",
+			wantScope: "a",
+		},
+		{
+			name: "removal only",
+			in: Input{
+				Files:   []FileChange{{Path: "pkg/old/old.go", Status: StatusDeleted}},
+				Symbols: []SymbolChange{{Name: "Legacy", Status: StatusDeleted}},
+			},
+			wantType:  "refactor",
+			wantScope: "old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := Describe(tt.in)
+			if msg.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", msg.Type, tt.wantType)
+			}
+			if tt.wantScope != "" && msg.Scope != tt.wantScope {
+				t.Errorf("Scope = %q, want %q", msg.Scope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestDescribe_SubjectAndBreaking(t *testing.T) {
+	in := Input{
+		Files: []FileChange{{Path: "pkg/api/api.go", Status: StatusModified}},
+		Symbols: []SymbolChange{
+			{Name: "helper", Kind: "function", Status: StatusAdded},
+			{Name: "NewClient", Kind: "function", Exported: true, Status: StatusAdded, Callers: 1},
+			{Name: "Dial", Kind: "function", Exported: true, Status: StatusAdded, Callers: 4},
+			{Name: "OldDial", Kind: "function", Exported: true, Status: StatusDeleted, Callers: 7, RiskLevel: "HIGH"},
+		},
+	}
+	msg := Describe(in)
+
+	if msg.Subject != "add Dial, NewClient and 1 more" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !msg.Breaking || !strings.Contains(msg.BreakingNote, "OldDial") {
+		t.Errorf("expected breaking change for removed OldDial, got %+v", msg)
+	}
+	if msg.Header() != "feat(api)!: add Dial, NewClient and 1 more" {
+		t.Errorf("Header = %q", msg.Header())
+	}
+	if msg.ChangelogSection != "Removed" {
+		t.Errorf("ChangelogSection = %q", msg.ChangelogSection)
+	}
+	if len(msg.Body) != 4 || msg.Body[0] != "Remove OldDial (function, 7 caller(s), HIGH risk)" {
+		t.Errorf("Body = %q", msg.Body)
+	}
+}
+
+func TestGenerator_Styles(t *testing.T) {
+	in := Input{
+		Files:         []FileChange{{Path: "pkg/auth/token.go", Status: StatusModified}},
+		Symbols:       []SymbolChange{{Name: "Validate", Kind: "function", Exported: true, Status: StatusModified, Callers: 3}},
+		RiskLevel:     "MEDIUM",
+		AffectedTests: []string{"pkg/auth/token_test.go"},
+	}
+
+	t.Run("conventional", func(t *testing.T) {
+		g, err := NewGenerator(Options{})
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %v", err)
+		}
+		out, err := g.Generate(in)
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		want := "fix(auth): update Validate\n\n- Change Validate (function, 3 caller(s))\n"
+		if out.CommitText != want {
+			t.Errorf("CommitText = %q, want %q", out.CommitText, want)
+		}
+		if !strings.Contains(out.Changelog, "### Fixed") || !strings.Contains(out.Changelog, "**auth:** Update Validate") {
+			t.Errorf("Changelog = %q", out.Changelog)
+		}
+	})
+
+	t.Run("simple", func(t *testing.T) {
+		g, _ := NewGenerator(Options{Style: StyleSimple})
+		out, _ := g.Generate(in)
+		if out.CommitText != "Update Validate\n" {
+			t.Errorf("CommitText = %q", out.CommitText)
+		}
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		g, _ := NewGenerator(Options{Style: StyleDetailed})
+		out, _ := g.Generate(in)
+		for _, want := range []string{"Impact: MEDIUM risk, 1 file(s), 1 symbol(s)", "Tests to run:", "pkg/auth/token_test.go"} {
+			if !strings.Contains(out.CommitText, want) {
+				t.Errorf("CommitText missing %q:\n%s", want, out.CommitText)
+			}
+		}
+	})
+
+	t.Run("custom template and overrides", func(t *testing.T) {
+		g, err := NewGenerator(Options{CommitTemplate: "[{{.Message.Type}}] {{.Message.Subject}}", Type: "perf", Scope: "-"})
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %v", err)
+		}
+		out, _ := g.Generate(in)
+		if out.CommitText != "[perf] update Validate\n" || out.Message.Scope != "" {
+			t.Errorf("unexpected output %+v", out)
+		}
+	})
+
+	t.Run("unknown style", func(t *testing.T) {
+		if _, err := NewGenerator(Options{Style: "haiku"}); !errors.Is(err, ErrUnknownStyle) {
+			t.Errorf("expected ErrUnknownStyle, got %v", err)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		g, _ := NewGenerator(Options{})
+		if _, err := g.Generate(Input{}); !errors.Is(err, ErrNoChanges) {
+			t.Errorf("expected ErrNoChanges, got %v", err)
+		}
+	})
+}
+
+func TestFitSubject(t *testing.T) {
+	m := &Message{Type: "feat", Scope: "search", Subject: "add " + strings.Repeat("VeryLongSymbolName", 5)}
+	m.Subject = fitSubject(m)
+	if len(m.Header()) > MaxSubjectLength {
+		t.Errorf("header too long (%d): %q", len(m.Header()), m.Header())
+	}
+	if !strings.HasSuffix(m.Subject, "...") {
+		t.Errorf("expected truncation marker, got %q", m.Subject)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package commitmsg
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned by the commitmsg package.
+var (
+	// ErrNoChanges indicates the input has no changed files.
+	ErrNoChanges = errors.New("no changes to describe")
+
+	// ErrUnknownStyle indicates an unsupported built-in style name.
+	ErrUnknownStyle = errors.New("unknown style")
+)
+
+// Status is how a file or symbol changed.
+type Status string
+
+const (
+	// StatusAdded is a new file or symbol.
+	StatusAdded Status = "added"
+
+	// StatusModified is a changed file or symbol.
+	StatusModified Status = "modified"
+
+	// StatusDeleted is a removed file or symbol.
+	StatusDeleted Status = "deleted"
+
+	// StatusRenamed is a moved file.
+	StatusRenamed Status = "renamed"
+)
+
+// ParseStatus converts a git status letter (A, M, D, R, C) or word to a
+// Status. Unknown values map to StatusModified.
+func ParseStatus(s string) Status {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "A", "C", "ADDED":
+		return StatusAdded
+	case "D", "DELETED":
+		return StatusDeleted
+	case "R", "RENAMED":
+		return StatusRenamed
+	default:
+		return StatusModified
+	}
+}
+
+// FileChange is one changed file.
+type FileChange struct {
+	// Path is the repository-relative path.
+	Path string `json:"path"`
+
+	// OldPath is the previous path for renames.
+	OldPath string `json:"old_path,omitempty"`
+
+	// Status is how the file changed.
+	Status Status `json:"status"`
+}
+
+// SymbolChange is one changed symbol with its blast radius.
+type SymbolChange struct {
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind (function, method, struct, ...).
+	Kind string `json:"kind"`
+
+	// FilePath is the file containing the symbol.
+	FilePath string `json:"file_path"`
+
+	// Exported reports whether the symbol is public API.
+	Exported bool `json:"exported"`
+
+	// Status is how the symbol changed.
+	Status Status `json:"status"`
+
+	// Callers is the number of direct and transitive callers.
+	Callers int `json:"callers"`
+
+	// RiskLevel is the blast radius risk (LOW, MEDIUM, HIGH, CRITICAL).
+	RiskLevel string `json:"risk_level,omitempty"`
+}
+
+// Input describes a change set.
+type Input struct {
+	// Files are the changed files. Required.
+	Files []FileChange `json:"files"`
+
+	// Symbols are the changed symbols, if an index was available.
+	Symbols []SymbolChange `json:"symbols,omitempty"`
+
+	// RiskLevel is the overall risk of the change.
+	RiskLevel string `json:"risk_level,omitempty"`
+
+	// AffectedTests are test files exercising the change.
+	AffectedTests []string `json:"affected_tests,omitempty"`
+}
+
+// Message is a generated commit message.
+type Message struct {
+	// Type is the conventional-commit type (feat, fix, refactor, ...).
+	Type string `json:"type"`
+
+	// Scope is the conventional-commit scope (may be empty).
+	Scope string `json:"scope,omitempty"`
+
+	// Subject is the short imperative description.
+	Subject string `json:"subject"`
+
+	// Body is a list of explanatory lines.
+	Body []string `json:"body,omitempty"`
+
+	// Breaking reports whether the change removes public API in use.
+	Breaking bool `json:"breaking"`
+
+	// BreakingNote explains the breaking change.
+	BreakingNote string `json:"breaking_note,omitempty"`
+
+	// ChangelogSection is the Keep a Changelog heading (Added, Fixed, ...).
+	ChangelogSection string `json:"changelog_section"`
+}
+
+// Header returns "type(scope)!: subject".
+func (m *Message) Header() string {
+	var sb strings.Builder
+	sb.WriteString(m.Type)
+	if m.Scope != "" {
+		sb.WriteString("(" + m.Scope + ")")
+	}
+	if m.Breaking {
+		sb.WriteString("!")
+	}
+	sb.WriteString(": " + m.Subject)
+	return sb.String()
+}
+
+// Output is the rendered generator result.
+type Output struct {
+	// Message is the structured message.
+	Message Message `json:"message"`
+
+	// CommitText is the rendered commit message.
+	CommitText string `json:"commit_text"`
+
+	// Changelog is the rendered changelog fragment.
+	Changelog string `json:"changelog"`
+}
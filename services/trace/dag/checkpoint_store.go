@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+)
+
+// storedCheckpoint is the blob store record for a checkpoint. The state is
+// stored as its own blob so checkpoints with identical state share it.
+type storedCheckpoint struct {
+	StateDigest cas.Digest `json:"state_digest"`
+	Timestamp   int64      `json:"timestamp"` // Unix milliseconds UTC
+	Version     string     `json:"version"`
+	Checksum    string     `json:"checksum"`
+	DAGName     string     `json:"dag_name"`
+}
+
+// checkpointRefs returns the blob store references for a named checkpoint.
+func checkpointRefs(dagName, name string) (record, state string) {
+	base := "checkpoints/" + dagName + "/" + name
+	return base + "/record", base + "/state"
+}
+
+// SaveCheckpointToStore saves a checkpoint in a content-addressable store.
+//
+// Description:
+//
+//	Stores the execution state and a small record pointing at it under
+//	"checkpoints/<dagName>/<name>". Saving the same state again, under
+//	this or any other name, stores no new state data. Saving under an
+//	existing name replaces that checkpoint and releases state no other
+//	checkpoint uses.
+//
+// Inputs:
+//
+//	store - The blob store. Must not be nil.
+//	state - The current execution state. Must not be nil.
+//	dagName - Name of the DAG being executed.
+//	name - Checkpoint name, e.g. a session ID. Same pattern as dagName.
+//
+// Outputs:
+//
+//	cas.Digest - Digest of the stored state.
+//	error - Non-nil if inputs are invalid or the store write fails.
+//
+// Thread Safety:
+//
+//	Safe to call concurrently with DAG execution.
+func SaveCheckpointToStore(store *cas.Store, state *State, dagName, name string) (cas.Digest, error) {
+	if store == nil {
+		return "", fmt.Errorf("%w: store must not be nil", ErrInvalidInput)
+	}
+	if state == nil {
+		return "", fmt.Errorf("%w: state must not be nil", ErrInvalidInput)
+	}
+	if !validDAGNamePattern.MatchString(dagName) {
+		return "", fmt.Errorf("%w: dagName must match pattern [a-zA-Z0-9_-]+, got %q", ErrInvalidInput, dagName)
+	}
+	if !validDAGNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: checkpoint name must match pattern [a-zA-Z0-9_-]+, got %q", ErrInvalidInput, name)
+	}
+
+	cs := state.toCheckpointState()
+	timestamp := time.Now().UnixMilli()
+
+	checksum, err := computeChecksum(cs, dagName, timestamp)
+	if err != nil {
+		return "", fmt.Errorf("compute checksum: %w", err)
+	}
+
+	// Compact JSON with sorted map keys, so equal states hash equally
+	stateData, err := json.Marshal(cs)
+	if err != nil {
+		return "", fmt.Errorf("marshal state: %w", err)
+	}
+
+	recordRef, stateRef := checkpointRefs(dagName, name)
+	stateDigest, err := store.Save(stateRef, bytes.NewReader(stateData))
+	if err != nil {
+		return "", fmt.Errorf("store state: %w", err)
+	}
+
+	record, err := json.Marshal(&storedCheckpoint{
+		StateDigest: stateDigest,
+		Timestamp:   timestamp,
+		Version:     CheckpointVersion,
+		Checksum:    checksum,
+		DAGName:     dagName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	if _, err := store.Save(recordRef, bytes.NewReader(record)); err != nil {
+		return "", fmt.Errorf("store checkpoint: %w", err)
+	}
+
+	return stateDigest, nil
+}
+
+// LoadCheckpointFromStore reads and verifies a checkpoint from a store.
+//
+// Description:
+//
+//	Loads a checkpoint saved with SaveCheckpointToStore, verifying its
+//	version and checksum as LoadCheckpoint does.
+//
+// Inputs:
+//
+//	store - The blob store. Must not be nil.
+//	dagName - Name of the DAG.
+//	name - Checkpoint name.
+//
+// Outputs:
+//
+//	*Checkpoint - The loaded checkpoint. Never nil on success.
+//	error - Non-nil if the checkpoint is missing, corrupt, or version mismatched.
+func LoadCheckpointFromStore(store *cas.Store, dagName, name string) (*Checkpoint, error) {
+	if store == nil {
+		return nil, fmt.Errorf("%w: store must not be nil", ErrInvalidInput)
+	}
+	if !validDAGNamePattern.MatchString(dagName) || !validDAGNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: invalid checkpoint %q/%q", ErrInvalidInput, dagName, name)
+	}
+
+	recordRef, _ := checkpointRefs(dagName, name)
+	data, err := store.GetRef(recordRef)
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	var record storedCheckpoint
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	if record.Version != CheckpointVersion {
+		return nil, fmt.Errorf("%w: got %s, want %s", ErrCheckpointVersionMismatch, record.Version, CheckpointVersion)
+	}
+
+	stateData, err := store.Get(record.StateDigest)
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint state: %w", err)
+	}
+	var cs checkpointState
+	if err := json.Unmarshal(stateData, &cs); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint state: %w", err)
+	}
+
+	expectedChecksum, err := computeChecksum(&cs, record.DAGName, record.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("compute checksum for verification: %w", err)
+	}
+	if record.Checksum != expectedChecksum || record.DAGName != dagName {
+		return nil, ErrCheckpointCorrupt
+	}
+
+	return &Checkpoint{
+		State:     cs.toState(),
+		Timestamp: record.Timestamp,
+		Version:   record.Version,
+		Checksum:  record.Checksum,
+		DAGName:   record.DAGName,
+	}, nil
+}
+
+// DeleteCheckpointFromStore removes a stored checkpoint, releasing state no
+// other checkpoint uses.
+//
+// Inputs:
+//
+//	store - The blob store. Must not be nil.
+//	dagName - Name of the DAG.
+//	name - Checkpoint name.
+//
+// Outputs:
+//
+//	error - Non-nil if the checkpoint does not exist or removal fails.
+func DeleteCheckpointFromStore(store *cas.Store, dagName, name string) error {
+	if store == nil {
+		return fmt.Errorf("%w: store must not be nil", ErrInvalidInput)
+	}
+	if !validDAGNamePattern.MatchString(dagName) || !validDAGNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid checkpoint %q/%q", ErrInvalidInput, dagName, name)
+	}
+
+	recordRef, stateRef := checkpointRefs(dagName, name)
+	if err := store.Unlink(recordRef); err != nil {
+		return fmt.Errorf("delete checkpoint: %w", err)
+	}
+	if err := store.Unlink(stateRef); err != nil {
+		return fmt.Errorf("delete checkpoint state: %w", err)
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 func TestSaveCheckpoint_Basic(t *testing.T) {
@@ -657,3 +659,53 @@ func TestCheckpointState_DeepCopy_NestedStructures(t *testing.T) {
 		t.Error("deep copy failed: map modification affected checkpoint")
 	}
 }
+
+func TestCheckpointStore_RoundtripAndDedup(t *testing.T) {
+	store, err := cas.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+
+	state := NewState("store-session")
+	state.SetCompleted("node-a", "output")
+
+	d1, err := SaveCheckpointToStore(store, state, "store-dag", "first")
+	if err != nil {
+		t.Fatalf("SaveCheckpointToStore: %v", err)
+	}
+	d2, err := SaveCheckpointToStore(store, state, "store-dag", "second")
+	if err != nil {
+		t.Fatalf("SaveCheckpointToStore: %v", err)
+	}
+	if d1 != d2 {
+		t.Errorf("identical states stored as %s and %s", d1, d2)
+	}
+	if n, _ := store.RefCount(d1); n != 2 {
+		t.Errorf("state RefCount = %d, want 2", n)
+	}
+
+	loaded, err := LoadCheckpointFromStore(store, "store-dag", "second")
+	if err != nil {
+		t.Fatalf("LoadCheckpointFromStore: %v", err)
+	}
+	if loaded.State.SessionID != "store-session" || !loaded.State.IsCompleted("node-a") {
+		t.Errorf("loaded state = %+v", loaded.State)
+	}
+	if !loaded.Verify() {
+		t.Error("loaded checkpoint should verify")
+	}
+
+	if err := DeleteCheckpointFromStore(store, "store-dag", "first"); err != nil {
+		t.Fatalf("DeleteCheckpointFromStore: %v", err)
+	}
+	if !store.Has(d1) {
+		t.Error("shared state released while still referenced")
+	}
+	if _, err := LoadCheckpointFromStore(store, "store-dag", "first"); !errors.Is(err, cas.ErrRefNotFound) {
+		t.Errorf("load deleted checkpoint = %v, want ErrRefNotFound", err)
+	}
+
+	if _, err := SaveCheckpointToStore(store, state, "store-dag", "../escape"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("invalid name = %v, want ErrInvalidInput", err)
+	}
+}
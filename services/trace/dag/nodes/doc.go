@@ -25,7 +25,7 @@
 //
 // Verification Nodes (parallel):
 //   - LSPSpawnNode: Spawns language servers via lsp.Manager
-//   - LSPTypeCheckNode: Performs type checking via lsp.Operations
+//   - LSPTypeCheckNode: Performs type checking and collects diagnostics via lsp.Operations
 //   - LintAnalyzeNode: Runs linters via lint.LintRunner
 //   - LintCheckNode: Validates lint results against policies
 //   - PatternScanNode: Detects design patterns via patterns.PatternDetector
@@ -238,24 +238,29 @@ func (n *LSPSpawnNode) OnResume(ctx context.Context, output any) error {
 	return nil
 }
 
-// LSPTypeCheckNode performs type checking using LSP hover.
+// LSPTypeCheckNode performs type checking using LSP hover and diagnostics.
 //
 // Description:
 //
 //	Uses the LSP hover operation to retrieve type information for
-//	specified symbols. This provides accurate type resolution across
-//	the project.
+//	specified symbols, and the LSP diagnostics operation to collect
+//	compiler errors for the files being checked. This provides accurate
+//	type resolution across the project.
 //
 // Inputs (from map[string]any):
 //
 //	"operations" (*lsp.Operations): LSP operations from LSP_SPAWN. Required.
-//	"symbols" ([]SymbolLocation): Symbols to type check. Required.
+//	"symbols" ([]SymbolLocation): Symbols to type check. Optional.
+//	"files" ([]string): Files to collect diagnostics for. Optional;
+//	    defaults to the files of the symbols.
 //
 // Outputs:
 //
 //	*LSPTypeCheckOutput containing:
 //	  - Results: Type information for each symbol
-//	  - Errors: Symbols that failed type checking
+//	  - Diagnostics: Compiler diagnostics for the checked files
+//	  - ErrorCount: Number of error diagnostics
+//	  - Errors: Symbols and files that failed type checking
 //	  - Duration: Check time
 //
 // Thread Safety:
@@ -277,7 +282,14 @@ type LSPTypeCheckOutput struct {
 	// Results contains type information for checked symbols.
 	Results []TypeCheckResult
 
-	// Errors contains symbols that failed type checking.
+	// Diagnostics contains compiler diagnostics for the checked files.
+	Diagnostics []TypeCheckDiagnostic
+
+	// ErrorCount is the number of error-severity diagnostics.
+	ErrorCount int
+
+	// Errors contains symbols and files that failed type checking. A
+	// file failure has only Symbol.FilePath set.
 	Errors []TypeCheckError
 
 	// Duration is the check time.
@@ -291,6 +303,16 @@ type TypeCheckResult struct {
 	Kind     string // "plaintext" or "markdown"
 }
 
+// TypeCheckDiagnostic is a compiler diagnostic reported by the language server.
+type TypeCheckDiagnostic struct {
+	FilePath string
+	Line     int // 1-indexed
+	Column   int // 0-indexed
+	Severity string
+	Source   string
+	Message  string
+}
+
 // TypeCheckError represents a type check failure.
 type TypeCheckError struct {
 	Symbol SymbolLocation
@@ -321,12 +343,13 @@ func NewLSPTypeCheckNode(deps []string) *LSPTypeCheckNode {
 //
 // Description:
 //
-//	Uses LSP hover to retrieve type information for each symbol.
+//	Uses LSP hover to retrieve type information for each symbol, then
+//	collects diagnostics for each file.
 //
 // Inputs:
 //
 //	ctx - Context for cancellation.
-//	inputs - Map containing "operations" and "symbols".
+//	inputs - Map containing "operations", "symbols" and "files".
 //
 // Outputs:
 //
@@ -342,6 +365,10 @@ func (n *LSPTypeCheckNode) Execute(ctx context.Context, inputs map[string]any) (
 	if err != nil {
 		return nil, err
 	}
+	files, err := n.extractFiles(inputs, symbols)
+	if err != nil {
+		return nil, err
+	}
 
 	start := time.Now()
 
@@ -367,10 +394,39 @@ func (n *LSPTypeCheckNode) Execute(ctx context.Context, inputs map[string]any) (
 		}
 	}
 
+	diagnostics := make([]TypeCheckDiagnostic, 0)
+	errorCount := 0
+	for _, file := range files {
+		diags, err := ops.Diagnostics(ctx, file)
+		if err != nil {
+			errors = append(errors, TypeCheckError{
+				Symbol: SymbolLocation{FilePath: file},
+				Error:  err.Error(),
+			})
+			continue
+		}
+
+		for _, d := range diags {
+			if d.IsError() {
+				errorCount++
+			}
+			diagnostics = append(diagnostics, TypeCheckDiagnostic{
+				FilePath: file,
+				Line:     d.Range.Start.Line + 1,
+				Column:   d.Range.Start.Character,
+				Severity: d.Severity.String(),
+				Source:   d.Source,
+				Message:  d.Message,
+			})
+		}
+	}
+
 	return &LSPTypeCheckOutput{
-		Results:  results,
-		Errors:   errors,
-		Duration: time.Since(start),
+		Results:     results,
+		Diagnostics: diagnostics,
+		ErrorCount:  errorCount,
+		Errors:      errors,
+		Duration:    time.Since(start),
 	}, nil
 }
 
@@ -407,3 +463,25 @@ func (n *LSPTypeCheckNode) extractInputs(inputs map[string]any) (*lsp.Operations
 
 	return nil, nil, fmt.Errorf("%w: symbols must be []SymbolLocation", ErrInvalidInputType)
 }
+
+// extractFiles returns the files to collect diagnostics for.
+func (n *LSPTypeCheckNode) extractFiles(inputs map[string]any, symbols []SymbolLocation) ([]string, error) {
+	if filesRaw, ok := inputs["files"]; ok {
+		files, ok := filesRaw.([]string)
+		if !ok {
+			return nil, fmt.Errorf("%w: files must be []string", ErrInvalidInputType)
+		}
+		return files, nil
+	}
+
+	// Default to the distinct files of the symbols, in order
+	seen := make(map[string]bool, len(symbols))
+	files := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		if !seen[sym.FilePath] {
+			seen[sym.FilePath] = true
+			files = append(files, sym.FilePath)
+		}
+	}
+	return files, nil
+}
@@ -196,3 +196,48 @@ func TestLSPSpawnNode_Execute_InvalidLanguagesType(t *testing.T) {
 		t.Errorf("expected ErrInvalidInputType, got: %v", err)
 	}
 }
+
+func TestLSPTypeCheckNode_ExtractFiles(t *testing.T) {
+	node := NewLSPTypeCheckNode(nil)
+	symbols := []SymbolLocation{
+		{FilePath: "/p/a.go", Line: 1},
+		{FilePath: "/p/b.go", Line: 2},
+		{FilePath: "/p/a.go", Line: 3},
+	}
+
+	files, err := node.extractFiles(map[string]any{}, symbols)
+	if err != nil || len(files) != 2 || files[0] != "/p/a.go" || files[1] != "/p/b.go" {
+		t.Errorf("default files = %v, %v, want the distinct symbol files", files, err)
+	}
+
+	files, err = node.extractFiles(map[string]any{"files": []string{"/p/c.go"}}, symbols)
+	if err != nil || len(files) != 1 || files[0] != "/p/c.go" {
+		t.Errorf("explicit files = %v, %v, want [/p/c.go]", files, err)
+	}
+
+	if _, err := node.extractFiles(map[string]any{"files": "c.go"}, nil); !errors.Is(err, ErrInvalidInputType) {
+		t.Errorf("expected ErrInvalidInputType, got: %v", err)
+	}
+}
+
+func TestLSPTypeCheckNode_Execute_DiagnosticsError(t *testing.T) {
+	mgr := lsp.NewManager("/tmp/test", lsp.DefaultManagerConfig())
+	defer mgr.ShutdownAll(context.Background())
+	node := NewLSPTypeCheckNode(nil)
+
+	out, err := node.Execute(context.Background(), map[string]any{
+		"operations": lsp.NewOperations(mgr),
+		"files":      []string{"/tmp/test/notes.unknown"},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	result := out.(*LSPTypeCheckOutput)
+	if len(result.Errors) != 1 || result.Errors[0].Symbol.FilePath != "/tmp/test/notes.unknown" {
+		t.Errorf("Errors = %+v, want one error for the file", result.Errors)
+	}
+	if len(result.Diagnostics) != 0 || result.ErrorCount != 0 {
+		t.Errorf("Diagnostics = %+v, ErrorCount = %d, want none", result.Diagnostics, result.ErrorCount)
+	}
+}
@@ -304,6 +304,7 @@ func (n *ParseFilesNode) parseFile(
 			Error:    fmt.Sprintf("parse: %v", err),
 		}
 	}
+	result.AnnotateOrigin(content)
 
 	return result, nil
 }
@@ -26,9 +26,13 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/integration"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/solutions"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/audit"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/file"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/workspace"
 )
 
 // coordinatorRegistry tracks coordinators by session ID for cleanup.
@@ -63,6 +67,36 @@ var journalsByProject = struct {
 	sessions: make(map[string]string),
 }
 
+// workspaceRegistry tracks workspace managers by project root and the
+// sessions that use them, so a session's worktree is removed when it ends.
+var workspaceRegistry = struct {
+	mu       sync.Mutex
+	managers map[string]*workspace.Manager // key is project root
+	sessions map[string]*workspace.Manager // key is session ID
+}{
+	managers: make(map[string]*workspace.Manager),
+	sessions: make(map[string]*workspace.Manager),
+}
+
+// workspaceManagerFor returns the shared workspace manager for a project,
+// creating it on first use, and records that the session uses it.
+func workspaceManagerFor(sessionID, projectRoot string) (*workspace.Manager, error) {
+	workspaceRegistry.mu.Lock()
+	defer workspaceRegistry.mu.Unlock()
+
+	mgr, ok := workspaceRegistry.managers[projectRoot]
+	if !ok {
+		var err error
+		mgr, err = workspace.NewManager(context.Background(), projectRoot, workspace.Config{})
+		if err != nil {
+			return nil, err
+		}
+		workspaceRegistry.managers[projectRoot] = mgr
+	}
+	workspaceRegistry.sessions[sessionID] = mgr
+	return mgr, nil
+}
+
 // registerCoordinator stores a coordinator for later cleanup.
 func registerCoordinator(sessionID string, coord *integration.Coordinator) {
 	coordinatorRegistry.mu.Lock()
@@ -177,10 +211,29 @@ func cleanupPersistence(sessionID string) {
 	}
 }
 
+// cleanupWorkspace removes the session's scratch worktree, if it has one.
+func cleanupWorkspace(sessionID string) {
+	workspaceRegistry.mu.Lock()
+	mgr, ok := workspaceRegistry.sessions[sessionID]
+	delete(workspaceRegistry.sessions, sessionID)
+	workspaceRegistry.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if err := mgr.Release(context.Background(), sessionID); err != nil {
+		slog.Warn("Failed to release session workspace",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // init registers cleanup hooks.
 func init() {
 	agent.RegisterSessionCleanupHook("coordinator", cleanupCoordinator)
 	agent.RegisterSessionCleanupHook("persistence", cleanupPersistence)
+	agent.RegisterSessionCleanupHook("workspace", cleanupWorkspace)
 }
 
 // DefaultDependenciesFactory creates phase Dependencies for agent sessions.
@@ -221,6 +274,15 @@ type DefaultDependenciesFactory struct {
 	// persistenceBaseDir is the base directory for CRS persistence
 	// GR-36: Defaults to ~/.aleutian/crs if not set
 	persistenceBaseDir string
+
+	// enableWorkspaces provides a scratch git worktree per session
+	enableWorkspaces bool
+
+	// auditLog records file and command tools run by session executors
+	auditLog *audit.Log
+
+	// solutionMemory remembers past solutions per repository
+	solutionMemory *solutions.Store
 }
 
 // DependenciesFactoryOption configures a DefaultDependenciesFactory.
@@ -386,6 +448,66 @@ func WithPersistenceBaseDir(dir string) DependenciesFactoryOption {
 	}
 }
 
+// WithWorkspacesEnabled enables session-scoped scratch workspaces.
+//
+// Description:
+//
+//	When enabled, sessions whose project root is a git repository get a
+//	workspace.Manager in Dependencies. The session's worktree is created on
+//	first Acquire, shared by TDG and MCTS simulation runs in that session,
+//	and removed when the session ends.
+//
+// Inputs:
+//
+//	enabled - Whether to enable workspaces.
+//
+// Outputs:
+//
+//	DependenciesFactoryOption - The configuration function.
+func WithWorkspacesEnabled(enabled bool) DependenciesFactoryOption {
+	return func(f *DefaultDependenciesFactory) {
+		f.enableWorkspaces = enabled
+	}
+}
+
+// WithAuditLog records agent actions in a hash-chained audit log.
+//
+// Description:
+//
+//	Tool executors created for sessions record every tool that touches
+//	files or executes commands, with the session ID, to the log. The log
+//	is shared by all sessions; the caller owns it and closes it on
+//	shutdown.
+//
+// Inputs:
+//
+//	log - The audit log. Nil disables auditing.
+//
+// Outputs:
+//
+//	DependenciesFactoryOption - The configuration function.
+func WithAuditLog(log *audit.Log) DependenciesFactoryOption {
+	return func(f *DefaultDependenciesFactory) {
+		f.auditLog = log
+	}
+}
+
+// WithSolutionMemory sets the repository-scoped solution memory.
+//
+// Inputs:
+//
+//	store - The solution store. Nil disables prior solution retrieval
+//	        and recording.
+//
+// Outputs:
+//
+//	DependenciesFactoryOption - The configuration function.
+func WithSolutionMemory(store *solutions.Store) DependenciesFactoryOption {
+	return func(f *DefaultDependenciesFactory) {
+		f.solutionMemory = store
+	}
+}
+
 // Create implements agent.DependenciesFactory.
 //
 // Description:
@@ -418,10 +540,17 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 		SafetyGate:       f.safetyGate,
 		EventEmitter:     f.eventEmitter,
 		ResponseGrounder: f.responseGrounder,
+		Solutions:        f.solutionMemory,
 		// Retrieve existing context from session (persisted by PlanPhase)
 		Context: session.GetCurrentContext(),
 	}
 
+	// Route the session through its replay recording, if it is captured.
+	recording := replay.Lookup(session.ID)
+	if recording != nil {
+		deps.LLMClient = recording.Client(deps.LLMClient)
+	}
+
 	// Try to get the cached graph if we need context or tools
 	if (f.enableContext || f.enableTools) && f.service != nil {
 		graphID := session.GetGraphID()
@@ -462,15 +591,34 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 					projectRoot := session.GetProjectRoot()
 					if projectRoot != "" {
 						fileConfig := file.NewConfig(projectRoot)
+						fileConfig.EditRecorder = session.EditHistory()
 						file.RegisterFileTools(registry, fileConfig)
 						slog.Info("File tools registered",
 							slog.String("session_id", session.ID),
 							slog.String("project_root", projectRoot),
 						)
+
+						// Apply the project's tool policy from .aleutian/config.yaml
+						policy := f.service.ProjectConfig(projectRoot).ToolPolicy
+						for _, name := range registry.Names() {
+							if !policy.Allows(name) {
+								registry.Unregister(name)
+							}
+						}
 					}
 
 					deps.ToolRegistry = registry
-					deps.ToolExecutor = tools.NewExecutor(registry, nil)
+					var execOpts []tools.ExecutorOption
+					if f.auditLog != nil {
+						execOpts = append(execOpts,
+							tools.WithAuditLog(f.auditLog),
+							tools.WithSessionID(session.ID),
+						)
+					}
+					if recording != nil {
+						execOpts = append(execOpts, tools.WithObserver(recording))
+					}
+					deps.ToolExecutor = tools.NewExecutorWithOptions(registry, nil, execOpts...)
 
 					// Mark graph_initialized requirement as satisfied since we have a valid graph
 					deps.ToolExecutor.SatisfyRequirement("graph_initialized")
@@ -542,6 +690,21 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 		)
 	}
 
+	if f.enableWorkspaces {
+		if projectRoot := session.GetProjectRoot(); projectRoot != "" {
+			mgr, err := workspaceManagerFor(session.ID, projectRoot)
+			if err != nil {
+				slog.Warn("Session workspaces unavailable",
+					slog.String("session_id", session.ID),
+					slog.String("project_root", projectRoot),
+					slog.String("error", err.Error()),
+				)
+			} else {
+				deps.Workspaces = mgr
+			}
+		}
+	}
+
 	return deps, nil
 }
 
@@ -0,0 +1,316 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// Edit Operations
+// =============================================================================
+
+// EditOpKind is the kind of a structured edit operation.
+type EditOpKind string
+
+const (
+	// EditInsertBefore inserts Text as whole lines before the line where
+	// Anchor starts.
+	EditInsertBefore EditOpKind = "insert_before"
+
+	// EditInsertAfter inserts Text as whole lines after the line where
+	// Anchor ends.
+	EditInsertAfter EditOpKind = "insert_after"
+
+	// EditReplace replaces Anchor with Text.
+	EditReplace EditOpKind = "replace"
+
+	// EditDelete removes Anchor.
+	EditDelete EditOpKind = "delete"
+)
+
+// EditOperation is one structured edit, located by anchor text rather
+// than line numbers.
+type EditOperation struct {
+	// Op is the kind of edit.
+	Op EditOpKind `json:"op"`
+
+	// Anchor is the text that locates the edit. It may span lines.
+	Anchor string `json:"anchor"`
+
+	// Text is the text to insert or the replacement. Ignored for delete.
+	Text string `json:"text,omitempty"`
+
+	// Occurrence selects which match of Anchor to use (1-based). Zero
+	// requires the anchor to be unique.
+	Occurrence int `json:"occurrence,omitempty"`
+}
+
+// Validate checks that the operation is well formed.
+func (op EditOperation) Validate() error {
+	switch op.Op {
+	case EditInsertBefore, EditInsertAfter, EditReplace, EditDelete:
+	default:
+		return fmt.Errorf("unknown op %q (want insert_before, insert_after, replace, or delete)", op.Op)
+	}
+	if op.Anchor == "" {
+		return errors.New("anchor is required")
+	}
+	if op.Occurrence < 0 {
+		return errors.New("occurrence must be positive")
+	}
+	return nil
+}
+
+// ParseEditScript parses a JSON edit script.
+//
+// # Description
+//
+// Accepts either an array of operations or an object with an "edits"
+// array. Every operation is validated.
+//
+// # Inputs
+//
+//   - data: The JSON edit script.
+//
+// # Outputs
+//
+//   - []EditOperation: The parsed operations.
+//   - error: Non-nil if the JSON is malformed or an operation is invalid.
+func ParseEditScript(data []byte) ([]EditOperation, error) {
+	data = bytes.TrimSpace(data)
+	var ops []EditOperation
+	if len(data) > 0 && data[0] == '{' {
+		var wrapper struct {
+			Edits []EditOperation `json:"edits"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("parsing edit script: %w", err)
+		}
+		ops = wrapper.Edits
+	} else if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("parsing edit script: %w", err)
+	}
+
+	if len(ops) == 0 {
+		return nil, errors.New("edit script has no operations")
+	}
+	for i, op := range ops {
+		if err := op.Validate(); err != nil {
+			return nil, fmt.Errorf("edit %d: %w", i, err)
+		}
+	}
+	return ops, nil
+}
+
+// =============================================================================
+// Edit Script Result
+// =============================================================================
+
+// EditOutcome reports how one edit operation was applied.
+type EditOutcome struct {
+	// Index is the operation's position in the script (0-based).
+	Index int
+
+	// Op is the operation kind.
+	Op EditOpKind
+
+	// Applied indicates the edit was applied.
+	Applied bool
+
+	// Line is the 1-based line where the anchor was found (0 if rejected).
+	Line int
+
+	// WhitespaceInsensitive indicates the anchor only matched ignoring
+	// whitespace, in which case the edit covers the whole matched lines.
+	WhitespaceInsensitive bool
+
+	// Reason explains why the edit was rejected.
+	Reason string
+}
+
+// EditScriptResult contains the outcome of applying an edit script.
+type EditScriptResult struct {
+	// Content is the content with all applied edits.
+	Content string
+
+	// Edits has one outcome per operation, in script order.
+	Edits []EditOutcome
+}
+
+// Rejected returns the outcomes of edits that did not apply.
+func (r *EditScriptResult) Rejected() []EditOutcome {
+	var rejected []EditOutcome
+	for _, e := range r.Edits {
+		if !e.Applied {
+			rejected = append(rejected, e)
+		}
+	}
+	return rejected
+}
+
+// =============================================================================
+// Edit Script Application
+// =============================================================================
+
+// ApplyEditScript applies structured edits to content.
+//
+// # Description
+//
+// Operations are applied in order, each to the result of the previous
+// ones. An anchor is matched exactly first; if it is not found, its lines
+// are matched ignoring whitespace. An operation whose anchor is missing or
+// ambiguous is rejected and the remaining operations are still tried.
+//
+// # Inputs
+//
+//   - content: The original content.
+//   - ops: The edit operations.
+//
+// # Outputs
+//
+//   - *EditScriptResult: The edited content and per-edit outcomes. Never nil.
+func ApplyEditScript(content string, ops []EditOperation) *EditScriptResult {
+	result := &EditScriptResult{Edits: make([]EditOutcome, 0, len(ops))}
+
+	for i, op := range ops {
+		outcome := EditOutcome{Index: i, Op: op.Op}
+		if err := op.Validate(); err != nil {
+			outcome.Reason = err.Error()
+			result.Edits = append(result.Edits, outcome)
+			continue
+		}
+
+		span, err := findAnchor(content, op.Anchor, op.Occurrence)
+		if err != nil {
+			outcome.Reason = err.Error()
+			result.Edits = append(result.Edits, outcome)
+			continue
+		}
+
+		outcome.Applied = true
+		outcome.Line = strings.Count(content[:span.start], "\n") + 1
+		content = applyEdit(content, op, span)
+		outcome.WhitespaceInsensitive = span.loose
+		result.Edits = append(result.Edits, outcome)
+	}
+
+	result.Content = content
+	return result
+}
+
+// anchorSpan is the byte range of a matched anchor.
+type anchorSpan struct {
+	start, end int
+	loose      bool
+}
+
+// findAnchor locates the requested occurrence of anchor in content.
+func findAnchor(content, anchor string, occurrence int) (anchorSpan, error) {
+	spans := exactSpans(content, anchor)
+	if len(spans) == 0 {
+		spans = looseSpans(content, anchor)
+	}
+
+	switch {
+	case len(spans) == 0:
+		return anchorSpan{}, errors.New("anchor not found")
+	case occurrence == 0 && len(spans) > 1:
+		return anchorSpan{}, fmt.Errorf("anchor matches %d times; set occurrence or extend the anchor", len(spans))
+	case occurrence > len(spans):
+		return anchorSpan{}, fmt.Errorf("occurrence %d requested but anchor matches %d times", occurrence, len(spans))
+	case occurrence == 0:
+		return spans[0], nil
+	default:
+		return spans[occurrence-1], nil
+	}
+}
+
+// exactSpans returns all non-overlapping exact matches of anchor.
+func exactSpans(content, anchor string) []anchorSpan {
+	var spans []anchorSpan
+	for offset := 0; ; {
+		idx := strings.Index(content[offset:], anchor)
+		if idx < 0 {
+			return spans
+		}
+		start := offset + idx
+		spans = append(spans, anchorSpan{start: start, end: start + len(anchor)})
+		offset = start + len(anchor)
+	}
+}
+
+// looseSpans matches the anchor's lines against whole content lines,
+// ignoring whitespace. Spans cover the matched lines without the final
+// newline.
+func looseSpans(content, anchor string) []anchorSpan {
+	want := strings.Split(strings.Trim(anchor, "\n"), "\n")
+	for i := range want {
+		want[i] = normalizeWhitespace(want[i])
+	}
+
+	lines := strings.Split(content, "\n")
+	offsets := make([]int, len(lines)+1)
+	for i, l := range lines {
+		offsets[i+1] = offsets[i] + len(l) + 1
+	}
+
+	var spans []anchorSpan
+	for i := 0; i+len(want) <= len(lines); i++ {
+		if blockMatches(lines[i:], want, true) {
+			end := i + len(want)
+			spans = append(spans, anchorSpan{start: offsets[i], end: offsets[end] - 1, loose: true})
+			i = end - 1
+		}
+	}
+	return spans
+}
+
+// applyEdit performs one located edit.
+func applyEdit(content string, op EditOperation, span anchorSpan) string {
+	switch op.Op {
+	case EditInsertBefore:
+		at := strings.LastIndexByte(content[:span.start], '\n') + 1
+		return content[:at] + asLines(op.Text) + content[at:]
+	case EditInsertAfter:
+		at := len(content)
+		if nl := strings.IndexByte(content[span.end:], '\n'); nl >= 0 {
+			at = span.end + nl + 1
+		} else {
+			// Anchor is on the last line, which has no newline.
+			return content + "\n" + strings.TrimSuffix(asLines(op.Text), "\n")
+		}
+		return content[:at] + asLines(op.Text) + content[at:]
+	case EditReplace:
+		return content[:span.start] + op.Text + content[span.end:]
+	case EditDelete:
+		end := span.end
+		// Deleting whole lines also removes the line break.
+		lineStart := span.start == 0 || content[span.start-1] == '\n'
+		if lineStart && end < len(content) && content[end] == '\n' {
+			end++
+		}
+		return content[:span.start] + content[end:]
+	}
+	return content
+}
+
+// asLines ensures inserted text ends with a newline.
+func asLines(text string) string {
+	if text == "" || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
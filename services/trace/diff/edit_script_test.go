@@ -0,0 +1,101 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const scriptBase = "package f\n\nfunc a() {\n\tone()\n}\n\nfunc b() {\n\tone()\n}\n"
+
+func TestApplyEditScript(t *testing.T) {
+	tests := []struct {
+		name string
+		op   EditOperation
+		want string
+	}{
+		{
+			name: "insert before",
+			op:   EditOperation{Op: EditInsertBefore, Anchor: "func b() {", Text: "// b does things."},
+			want: "}\n\n// b does things.\nfunc b() {\n",
+		},
+		{
+			name: "insert after multi-line anchor",
+			op:   EditOperation{Op: EditInsertAfter, Anchor: "func a() {\n\tone()", Text: "\ttwo()\n"},
+			want: "func a() {\n\tone()\n\ttwo()\n}\n",
+		},
+		{
+			name: "replace occurrence",
+			op:   EditOperation{Op: EditReplace, Anchor: "one()", Text: "two()", Occurrence: 2},
+			want: "func b() {\n\ttwo()\n}\n",
+		},
+		{
+			name: "delete whole line",
+			op:   EditOperation{Op: EditDelete, Anchor: "\tone()\n}\n\nfunc b"},
+			want: "func a() {\n() {\n",
+		},
+		{
+			name: "whitespace-insensitive anchor",
+			op:   EditOperation{Op: EditReplace, Anchor: "func  b()  {", Text: "func b(x int) {"},
+			want: "func b(x int) {\n\tone()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ApplyEditScript(scriptBase, []EditOperation{tt.op})
+			if len(res.Rejected()) != 0 {
+				t.Fatalf("rejected: %+v", res.Rejected())
+			}
+			if !strings.Contains(res.Content, tt.want) {
+				t.Errorf("content = %q, want it to contain %q", res.Content, tt.want)
+			}
+		})
+	}
+
+	t.Run("reports rejected edits and continues", func(t *testing.T) {
+		res := ApplyEditScript(scriptBase, []EditOperation{
+			{Op: EditReplace, Anchor: "one()", Text: "x()"},
+			{Op: EditReplace, Anchor: "nothing", Text: "x"},
+			{Op: EditReplace, Anchor: "one()", Text: "x()", Occurrence: 3},
+			{Op: EditInsertAfter, Anchor: "package f", Text: "// ok"},
+		})
+		rejected := res.Rejected()
+		if len(rejected) != 3 {
+			t.Fatalf("rejected = %+v, want 3", rejected)
+		}
+		for i, want := range []string{"matches 2 times", "not found", "matches 2 times"} {
+			if !strings.Contains(rejected[i].Reason, want) {
+				t.Errorf("rejected[%d].Reason = %q, want %q", i, rejected[i].Reason, want)
+			}
+		}
+		if !strings.HasPrefix(res.Content, "package f\n// ok\n") || res.Edits[3].Line != 1 {
+			t.Errorf("last edit not applied: %q %+v", res.Content, res.Edits[3])
+		}
+	})
+}
+
+func TestParseEditScript(t *testing.T) {
+	ops, err := ParseEditScript([]byte(`{"edits": [{"op": "delete", "anchor": "x"}]}`))
+	if err != nil || len(ops) != 1 || ops[0].Op != EditDelete {
+		t.Errorf("wrapped form: ops = %+v, err = %v", ops, err)
+	}
+	ops, err = ParseEditScript([]byte(` [{"op": "replace", "anchor": "x", "text": "y"}]`))
+	if err != nil || len(ops) != 1 || ops[0].Text != "y" {
+		t.Errorf("array form: ops = %+v, err = %v", ops, err)
+	}
+	for _, bad := range []string{`[]`, `[{"op": "move", "anchor": "x"}]`, `[{"op": "delete"}]`, `{`} {
+		if _, err := ParseEditScript([]byte(bad)); err == nil {
+			t.Errorf("ParseEditScript(%s) = nil error", bad)
+		}
+	}
+}
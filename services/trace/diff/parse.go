@@ -73,6 +73,23 @@ func GenerateDiff(filePath, oldContent, newContent, rationale string) (*Proposed
 	return change, nil
 }
 
+// UnifiedDiff returns a unified diff from oldContent to newContent, with
+// a/ and b/ prefixed filePath headers and 3 lines of context. It returns
+// "" if the contents are equal.
+func UnifiedDiff(filePath, oldContent, newContent string) string {
+	if oldContent == newContent {
+		return ""
+	}
+	// splitLines keeps the empty line after a final newline; drop it when
+	// both sides have one so the hunks only count real lines.
+	if strings.HasSuffix(oldContent, "\n") && strings.HasSuffix(newContent, "\n") {
+		oldContent = oldContent[:len(oldContent)-1]
+		newContent = newContent[:len(newContent)-1]
+	}
+	diff, _ := generateUnifiedDiff(filePath, oldContent, newContent)
+	return diff
+}
+
 // generateUnifiedDiff creates a unified diff string.
 func generateUnifiedDiff(filePath, oldContent, newContent string) (string, error) {
 	// Use go-diff library for unified diff generation
@@ -235,6 +235,18 @@ func TestIsSecuritySensitive(t *testing.T) {
 	}
 }
 
+func TestUnifiedDiff(t *testing.T) {
+	if got := UnifiedDiff("a.go", "x\n", "x\n"); got != "" {
+		t.Errorf("UnifiedDiff(equal) = %q, want empty", got)
+	}
+
+	got := UnifiedDiff("a.go", "a\nb\nc\n", "a\nB\nc\n")
+	want := "--- a/a.go\n+++ b/a.go\n@@ -1,3 +1,3 @@\n a\n+B\n-b\n c\n"
+	if got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
 func TestSplitLines(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,378 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// Fuzzy Patch Options
+// =============================================================================
+
+// PatchOptions configures fuzzy hunk application.
+type PatchOptions struct {
+	// Fuzz is the maximum number of leading and trailing context lines that
+	// may be ignored when a hunk does not match with full context, like
+	// patch(1) -F (default: 2).
+	Fuzz int
+
+	// MaxOffset limits how far from its stated line a hunk may be applied.
+	// Zero searches the whole file.
+	MaxOffset int
+
+	// IgnoreWhitespace allows a hunk to match when its lines differ from the
+	// file only in whitespace (default: true).
+	IgnoreWhitespace bool
+}
+
+// DefaultPatchOptions returns sensible defaults.
+func DefaultPatchOptions() PatchOptions {
+	return PatchOptions{
+		Fuzz:             2,
+		IgnoreWhitespace: true,
+	}
+}
+
+// =============================================================================
+// Fuzzy Patch Result
+// =============================================================================
+
+// HunkOutcome reports how one hunk was applied.
+type HunkOutcome struct {
+	// Index is the hunk's position in the patch (0-based).
+	Index int
+
+	// Header is the hunk's @@ header.
+	Header string
+
+	// Applied indicates the hunk was applied.
+	Applied bool
+
+	// Line is the 1-based line in the original content where the hunk was
+	// applied (0 if rejected).
+	Line int
+
+	// Offset is how many lines the hunk moved from its stated position.
+	Offset int
+
+	// Fuzz is the number of context lines ignored at each end to apply it.
+	Fuzz int
+
+	// WhitespaceInsensitive indicates the hunk only matched ignoring whitespace.
+	WhitespaceInsensitive bool
+
+	// Reason explains why the hunk was rejected.
+	Reason string
+
+	// Text is the hunk exactly as it appeared in the patch.
+	Text string
+}
+
+// PatchResult contains the outcome of applying hunks to content.
+type PatchResult struct {
+	// Content is the content with all applied hunks.
+	Content string
+
+	// Hunks has one outcome per hunk, in patch order.
+	Hunks []HunkOutcome
+}
+
+// AppliedCount returns the number of applied hunks.
+func (r *PatchResult) AppliedCount() int {
+	count := 0
+	for _, h := range r.Hunks {
+		if h.Applied {
+			count++
+		}
+	}
+	return count
+}
+
+// Rejected returns the outcomes of hunks that did not apply.
+func (r *PatchResult) Rejected() []HunkOutcome {
+	var rejected []HunkOutcome
+	for _, h := range r.Hunks {
+		if !h.Applied {
+			rejected = append(rejected, h)
+		}
+	}
+	return rejected
+}
+
+// =============================================================================
+// Fuzzy Hunk Application
+// =============================================================================
+
+// ApplyHunksFuzzy applies hunks to content by matching their context.
+//
+// # Description
+//
+// Unlike Applier, which trusts hunk line numbers, each hunk is located by
+// searching for its context and removed lines, nearest its stated line
+// first. This tolerates patches generated against a slightly different
+// version of the file and hand-written patches with wrong line numbers.
+// A hunk that does not match exactly is retried with up to opts.Fuzz
+// context lines dropped from each end, then ignoring whitespace.
+//
+// Hunks are applied in order and may not overlap. A hunk that cannot be
+// located is rejected and the remaining hunks are still tried.
+//
+// # Inputs
+//
+//   - content: The original content.
+//   - hunks: Hunks to apply, in file order. Status is ignored.
+//   - opts: Matching options.
+//
+// # Outputs
+//
+//   - *PatchResult: The patched content and per-hunk outcomes. Never nil.
+func ApplyHunksFuzzy(content string, hunks []*Hunk, opts PatchOptions) *PatchResult {
+	lines, trailingNewline := splitContentLines(content)
+	result := &PatchResult{Hunks: make([]HunkOutcome, 0, len(hunks))}
+
+	// delta tracks how far applied hunks have shifted later line numbers.
+	delta := 0
+	// floor is the first line the next hunk may touch.
+	floor := 0
+	// origShift is the net number of lines added by applied hunks, used to
+	// report positions in the original content.
+	origShift := 0
+
+	for i, h := range hunks {
+		outcome := HunkOutcome{Index: i, Header: h.Header(), Text: formatHunk(h)}
+
+		oldBlock, newBlock, lead, trail := hunkBlocks(h.EffectiveLines())
+		if len(oldBlock) == 0 && len(newBlock) == 0 {
+			outcome.Reason = "empty hunk"
+			result.Hunks = append(result.Hunks, outcome)
+			continue
+		}
+
+		expected := max(h.OldStart-1, 0) + delta
+		m, ok := locateHunk(lines, oldBlock, lead, trail, expected, floor, opts)
+		if !ok {
+			outcome.Reason = "context not found"
+			if opts.MaxOffset > 0 {
+				outcome.Reason = fmt.Sprintf("context not found within %d lines of line %d", opts.MaxOffset, h.OldStart)
+			}
+			result.Hunks = append(result.Hunks, outcome)
+			continue
+		}
+
+		// Replace the matched old lines with the corresponding new lines,
+		// keeping the file's own text for any context lines that matched.
+		matchedOld := oldBlock[m.lead : len(oldBlock)-m.trail]
+		replacement := rebuildBlock(lines[m.pos:m.pos+len(matchedOld)], h.EffectiveLines(), m.lead, m.trail)
+
+		start := m.pos - m.lead
+		outcome.Applied = true
+		outcome.Line = start - origShift + 1
+		outcome.Offset = start - expected
+		outcome.Fuzz = max(m.lead, m.trail)
+		outcome.WhitespaceInsensitive = m.loose
+
+		updated := make([]string, 0, len(lines)-len(matchedOld)+len(replacement))
+		updated = append(updated, lines[:m.pos]...)
+		updated = append(updated, replacement...)
+		updated = append(updated, lines[m.pos+len(matchedOld):]...)
+		lines = updated
+
+		growth := len(replacement) - len(matchedOld)
+		delta = start - max(h.OldStart-1, 0) + growth
+		origShift += growth
+		floor = m.pos + len(replacement)
+
+		result.Hunks = append(result.Hunks, outcome)
+	}
+
+	result.Content = joinContentLines(lines, trailingNewline || content == "")
+	return result
+}
+
+// hunkMatch is where a hunk's old block was found.
+type hunkMatch struct {
+	// pos is the index of the first matched line.
+	pos int
+
+	// lead and trail are the context lines dropped at each end.
+	lead, trail int
+
+	// loose indicates whitespace-insensitive matching was needed.
+	loose bool
+}
+
+// locateHunk finds the position of oldBlock in lines, trying exact matches
+// with full context first and relaxing one step at a time.
+func locateHunk(lines, oldBlock []string, lead, trail, expected, floor int, opts PatchOptions) (hunkMatch, bool) {
+	modes := []bool{false}
+	if opts.IgnoreWhitespace {
+		modes = append(modes, true)
+	}
+
+	for _, loose := range modes {
+		for fuzz := 0; fuzz <= max(opts.Fuzz, 0); fuzz++ {
+			dropLead, dropTrail := min(fuzz, lead), min(fuzz, trail)
+			if fuzz > 0 && dropLead < fuzz && dropTrail < fuzz {
+				// Nothing more to drop at this level.
+				break
+			}
+			block := oldBlock[dropLead : len(oldBlock)-dropTrail]
+			if len(block) == 0 && len(oldBlock) > 0 {
+				break
+			}
+			if pos, ok := searchBlock(lines, block, expected+dropLead, floor, opts.MaxOffset, loose); ok {
+				return hunkMatch{pos: pos, lead: dropLead, trail: dropTrail, loose: loose}, true
+			}
+		}
+	}
+	return hunkMatch{}, false
+}
+
+// searchBlock returns the position of block in lines closest to expected,
+// not before floor.
+func searchBlock(lines, block []string, expected, floor, maxOffset int, loose bool) (int, bool) {
+	last := len(lines) - len(block)
+	if last < floor {
+		return 0, false
+	}
+	expected = min(max(expected, floor), last)
+
+	if len(block) == 0 {
+		// Pure insertion without context: trust the stated line.
+		return expected, true
+	}
+
+	limit := max(expected-floor, last-expected)
+	if maxOffset > 0 {
+		limit = min(limit, maxOffset)
+	}
+	for d := 0; d <= limit; d++ {
+		if p := expected - d; p >= floor && blockMatches(lines[p:], block, loose) {
+			return p, true
+		}
+		if p := expected + d; d > 0 && p <= last && blockMatches(lines[p:], block, loose) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// blockMatches reports whether lines starts with block.
+func blockMatches(lines, block []string, loose bool) bool {
+	for i, want := range block {
+		got := lines[i]
+		if loose {
+			got, want = normalizeWhitespace(got), normalizeWhitespace(want)
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeWhitespace collapses runs of whitespace and trims the ends.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// hunkBlocks splits hunk lines into the lines it expects (context and
+// removed) and the lines it produces (context and added), and counts the
+// context lines at each end.
+func hunkBlocks(hunkLines []DiffLine) (oldBlock, newBlock []string, lead, trail int) {
+	for _, l := range hunkLines {
+		switch l.Type {
+		case LineContext:
+			oldBlock = append(oldBlock, l.Content)
+			newBlock = append(newBlock, l.Content)
+		case LineRemoved:
+			oldBlock = append(oldBlock, l.Content)
+		case LineAdded:
+			newBlock = append(newBlock, l.Content)
+		}
+	}
+	for lead < len(hunkLines) && hunkLines[lead].Type == LineContext {
+		lead++
+	}
+	for trail < len(hunkLines)-lead && hunkLines[len(hunkLines)-1-trail].Type == LineContext {
+		trail++
+	}
+	return oldBlock, newBlock, lead, trail
+}
+
+// rebuildBlock produces the replacement for matched file lines. Context
+// lines keep the file's text, so a whitespace-insensitive match does not
+// reformat unchanged lines.
+func rebuildBlock(matched []string, hunkLines []DiffLine, dropLead, dropTrail int) []string {
+	// Skip the dropped context lines at each end.
+	start, end := 0, len(hunkLines)
+	for skipped := 0; skipped < dropLead; start++ {
+		if hunkLines[start].Type == LineContext {
+			skipped++
+		}
+	}
+	for skipped := 0; skipped < dropTrail; end-- {
+		if hunkLines[end-1].Type == LineContext {
+			skipped++
+		}
+	}
+
+	var out []string
+	fileIdx := 0
+	for _, l := range hunkLines[start:end] {
+		switch l.Type {
+		case LineContext:
+			out = append(out, matched[fileIdx])
+			fileIdx++
+		case LineRemoved:
+			fileIdx++
+		case LineAdded:
+			out = append(out, l.Content)
+		}
+	}
+	return out
+}
+
+// formatHunk renders a hunk in unified diff form.
+func formatHunk(h *Hunk) string {
+	var b strings.Builder
+	b.WriteString(h.Header())
+	b.WriteByte('\n')
+	for _, l := range h.EffectiveLines() {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// splitContentLines splits content into lines, reporting whether it ended
+// with a newline.
+func splitContentLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, false
+	}
+	trailing := strings.HasSuffix(content, "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), trailing
+}
+
+// joinContentLines is the inverse of splitContentLines.
+func joinContentLines(lines []string, trailingNewline bool) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	s := strings.Join(lines, "\n")
+	if trailingNewline {
+		s += "\n"
+	}
+	return s
+}
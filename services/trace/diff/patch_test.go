@@ -0,0 +1,144 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseTestHunks parses a single-file diff body into hunks.
+func parseTestHunks(t *testing.T, body string) []*Hunk {
+	t.Helper()
+	changes, err := ParseMultiFileDiff("--- a/f.go\n+++ b/f.go\n" + body)
+	if err != nil {
+		t.Fatalf("ParseMultiFileDiff() error = %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("got %d files, want 1", len(changes))
+	}
+	return changes[0].Hunks
+}
+
+const fuzzyBase = `package f
+
+func a() {
+	one()
+	two()
+}
+
+func b() {
+	three()
+}
+`
+
+func TestApplyHunksFuzzy(t *testing.T) {
+	t.Run("exact", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -3,4 +3,5 @@\n func a() {\n \tone()\n+\tonePointFive()\n \ttwo()\n }\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		if len(res.Rejected()) != 0 {
+			t.Fatalf("rejected: %+v", res.Rejected())
+		}
+		if !strings.Contains(res.Content, "\tone()\n\tonePointFive()\n\ttwo()\n") {
+			t.Errorf("content = %q", res.Content)
+		}
+		if h := res.Hunks[0]; h.Line != 3 || h.Offset != 0 || h.Fuzz != 0 {
+			t.Errorf("outcome = %+v, want line 3, no offset or fuzz", h)
+		}
+	})
+
+	t.Run("wrong line numbers are offset", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -1,3 +1,3 @@\n func b() {\n-\tthree()\n+\tfour()\n }\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		if res.AppliedCount() != 1 || !strings.Contains(res.Content, "\tfour()") {
+			t.Fatalf("content = %q, outcomes = %+v", res.Content, res.Hunks)
+		}
+		if h := res.Hunks[0]; h.Line != 8 || h.Offset != 7 {
+			t.Errorf("outcome = %+v, want line 8 offset 7", h)
+		}
+	})
+
+	t.Run("stale context uses fuzz", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -7,3 +7,3 @@\n func bee() {\n-\tthree()\n+\tfour()\n }\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		if res.AppliedCount() != 1 || res.Hunks[0].Fuzz != 1 {
+			t.Fatalf("outcomes = %+v", res.Hunks)
+		}
+		if !strings.Contains(res.Content, "func b() {\n\tfour()\n}") {
+			t.Errorf("dropped context was not preserved: %q", res.Content)
+		}
+	})
+
+	t.Run("whitespace differences", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -4,2 +4,2 @@\n-    one()\n+    uno()\n     two()\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		if res.AppliedCount() != 1 || !res.Hunks[0].WhitespaceInsensitive {
+			t.Fatalf("outcomes = %+v", res.Hunks)
+		}
+		// Context keeps the file's indentation; added lines are taken as given.
+		if !strings.Contains(res.Content, "    uno()\n\ttwo()\n") {
+			t.Errorf("content = %q", res.Content)
+		}
+
+		strict := DefaultPatchOptions()
+		strict.IgnoreWhitespace = false
+		if res := ApplyHunksFuzzy(fuzzyBase, hunks, strict); res.AppliedCount() != 0 {
+			t.Errorf("strict whitespace applied: %+v", res.Hunks)
+		}
+	})
+
+	t.Run("rejects and continues", func(t *testing.T) {
+		hunks := parseTestHunks(t,
+			"@@ -4,1 +4,1 @@\n-\tmissing()\n+\tgone()\n"+
+				"@@ -9,1 +9,1 @@\n-\tthree()\n+\tthrice()\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		rejected := res.Rejected()
+		if len(rejected) != 1 || rejected[0].Index != 0 {
+			t.Fatalf("rejected = %+v, want hunk 0", rejected)
+		}
+		if rejected[0].Reason == "" || rejected[0].Text != "@@ -4,1 +4,1 @@\n-\tmissing()\n+\tgone()\n" {
+			t.Errorf("rejected hunk not reported exactly: %+v", rejected[0])
+		}
+		if !strings.Contains(res.Content, "\tthrice()") {
+			t.Errorf("second hunk not applied: %q", res.Content)
+		}
+	})
+
+	t.Run("max offset", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -1,1 +1,1 @@\n-\tthree()\n+\tfour()\n")
+		opts := DefaultPatchOptions()
+		opts.MaxOffset = 3
+		if res := ApplyHunksFuzzy(fuzzyBase, hunks, opts); res.AppliedCount() != 0 {
+			t.Errorf("applied beyond max offset: %+v", res.Hunks)
+		}
+	})
+
+	t.Run("later hunks track earlier growth", func(t *testing.T) {
+		hunks := parseTestHunks(t,
+			"@@ -1,1 +1,3 @@\n package f\n+\n+import \"fmt\"\n"+
+				"@@ -9,1 +11,1 @@\n-\tthree()\n+\tfmt.Println()\n")
+		res := ApplyHunksFuzzy(fuzzyBase, hunks, DefaultPatchOptions())
+		if res.AppliedCount() != 2 {
+			t.Fatalf("outcomes = %+v", res.Hunks)
+		}
+		if h := res.Hunks[1]; h.Line != 9 || h.Offset != 0 {
+			t.Errorf("second outcome = %+v, want line 9 offset 0", h)
+		}
+	})
+
+	t.Run("new file", func(t *testing.T) {
+		hunks := parseTestHunks(t, "@@ -0,0 +1,2 @@\n+package g\n+// new\n")
+		res := ApplyHunksFuzzy("", hunks, DefaultPatchOptions())
+		if res.Content != "package g\n// new\n" {
+			t.Errorf("content = %q", res.Content)
+		}
+	})
+}
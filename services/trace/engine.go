@@ -0,0 +1,197 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+// Engine is the transport-agnostic interface to the trace engine.
+//
+// Description:
+//
+//	LocalEngine runs the engine in-process (embedded mode: no HTTP server,
+//	no containers); HTTPEngine talks to a trace server. Both return the
+//	same sentinel errors (ErrRelativePath, agent.ErrEmptyQuery, ...), so
+//	callers handle errors the same way whichever engine they use.
+//
+// Thread Safety: Implementations are safe for concurrent use.
+type Engine interface {
+	// Init builds (or refreshes) the code graph for a project.
+	Init(ctx context.Context, req *InitRequest) (*InitResponse, error)
+
+	// RunAgent runs an agent session until completion or clarification.
+	RunAgent(ctx context.Context, req *AgentRunRequest) (*AgentRunResponse, error)
+
+	// Close releases the engine's resources.
+	Close(ctx context.Context) error
+}
+
+// LocalEngine runs the trace engine in the calling process.
+//
+// Thread Safety: LocalEngine is safe for concurrent use.
+type LocalEngine struct {
+	svc   *Service
+	agent *AgentHandlers
+}
+
+// NewLocalEngine creates an in-process engine.
+//
+// Description:
+//
+//	Requests go through the same Service and agent code paths as the
+//	trace server's handlers, without HTTP in between.
+//
+// Inputs:
+//
+//	svc - The Code Buddy service. Must not be nil.
+//	loop - The agent loop, typically from NewAgentLoop. Must not be nil.
+//
+// Outputs:
+//
+//	*LocalEngine - The engine. Close it to release the service.
+//
+// Example:
+//
+//	svc := code_buddy.NewService(code_buddy.DefaultServiceConfig())
+//	loop := code_buddy.NewAgentLoop(svc, code_buddy.AgentLoopConfig{LLMClient: client})
+//	engine := code_buddy.NewLocalEngine(svc, loop)
+//	defer engine.Close(ctx)
+func NewLocalEngine(svc *Service, loop agent.AgentLoop) *LocalEngine {
+	return &LocalEngine{
+		svc:   svc,
+		agent: NewAgentHandlers(loop, svc),
+	}
+}
+
+// Init builds the code graph for req.ProjectRoot.
+func (e *LocalEngine) Init(ctx context.Context, req *InitRequest) (*InitResponse, error) {
+	return e.svc.Init(ctx, req.ProjectRoot, req.Languages, req.ExcludePatterns)
+}
+
+// RunAgent runs an agent session. See AgentHandlers.RunAgent.
+func (e *LocalEngine) RunAgent(ctx context.Context, req *AgentRunRequest) (*AgentRunResponse, error) {
+	return e.agent.RunAgent(ctx, req)
+}
+
+// Close closes the service.
+func (e *LocalEngine) Close(ctx context.Context) error {
+	return e.svc.Close(ctx)
+}
+
+// DefaultHTTPEngineTimeout bounds requests of an HTTPEngine created
+// without a client. Agent runs can take minutes.
+const DefaultHTTPEngineTimeout = 10 * time.Minute
+
+// HTTPEngine talks to a trace server.
+//
+// Thread Safety: HTTPEngine is safe for concurrent use.
+type HTTPEngine struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPEngine creates an engine for the trace server at baseURL
+// (e.g. "http://localhost:8080").
+//
+// Inputs:
+//
+//	baseURL - Server URL without the /v1 prefix.
+//	client - HTTP client. If nil, a client with DefaultHTTPEngineTimeout is used.
+//
+// Outputs:
+//
+//	*HTTPEngine - The engine.
+func NewHTTPEngine(baseURL string, client *http.Client) *HTTPEngine {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPEngineTimeout}
+	}
+	return &HTTPEngine{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Init calls POST /v1/codebuddy/init.
+func (e *HTTPEngine) Init(ctx context.Context, req *InitRequest) (*InitResponse, error) {
+	var resp InitResponse
+	if err := e.post(ctx, "/v1/codebuddy/init", req, &resp, initErrorCodes); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RunAgent calls POST /v1/codebuddy/agent/run.
+func (e *HTTPEngine) RunAgent(ctx context.Context, req *AgentRunRequest) (*AgentRunResponse, error) {
+	var resp AgentRunResponse
+	if err := e.post(ctx, "/v1/codebuddy/agent/run", req, &resp, agentRunErrorCodes); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Close is a no-op; the server owns its resources.
+func (e *HTTPEngine) Close(context.Context) error {
+	return nil
+}
+
+// post sends body as JSON and decodes a 200 response into out. Error
+// responses are mapped back to the sentinels in codes, or else to an
+// *apperr.Error carrying the response's code and category.
+func (e *HTTPEngine) post(ctx context.Context, path string, body, out any, codes []errorCode) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var errResp ErrorResponse
+		if json.Unmarshal(raw, &errResp) != nil || errResp.Error == "" {
+			return fmt.Errorf("POST %s: %s", path, resp.Status)
+		}
+		if sentinel := errorForCode(codes, errResp.Code); sentinel != nil {
+			return fmt.Errorf("%w: %s", sentinel, errResp.Error)
+		}
+		return fmt.Errorf("POST %s: %s: %w", path, resp.Status, errResp.Err())
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	return nil
+}
+
+// Compile-time interface checks.
+var (
+	_ Engine = (*LocalEngine)(nil)
+	_ Engine = (*HTTPEngine)(nil)
+)
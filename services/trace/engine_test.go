@@ -0,0 +1,70 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/gin-gonic/gin"
+)
+
+// TestEngines checks that the local and HTTP engines return the same
+// results and sentinel errors.
+func TestEngines(t *testing.T) {
+	ctx := context.Background()
+	loop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			if query == "busy" {
+				return nil, fmt.Errorf("run: %w", agent.ErrSessionInProgress)
+			}
+			return &agent.RunResult{State: agent.StateComplete, StepsTaken: 2, Response: "answer: " + query}, nil
+		},
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	router := gin.New()
+	v1 := router.Group("/v1")
+	RegisterRoutes(v1, NewHandlers(svc))
+	RegisterAgentRoutes(v1, NewAgentHandlers(loop, svc))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	engines := map[string]Engine{
+		"local": NewLocalEngine(svc, loop),
+		"http":  NewHTTPEngine(srv.URL, nil),
+	}
+	for name, engine := range engines {
+		t.Run(name, func(t *testing.T) {
+			resp, err := engine.RunAgent(ctx, &AgentRunRequest{ProjectRoot: "/test/project", Query: "q"})
+			if err != nil {
+				t.Fatalf("RunAgent: %v", err)
+			}
+			if resp.Response != "answer: q" || resp.StepsTaken != 2 || resp.State != string(agent.StateComplete) {
+				t.Errorf("RunAgent = %+v", resp)
+			}
+
+			_, err = engine.RunAgent(ctx, &AgentRunRequest{ProjectRoot: "/test/project", Query: "busy"})
+			if !errors.Is(err, agent.ErrSessionInProgress) {
+				t.Errorf("RunAgent(busy) = %v, want ErrSessionInProgress", err)
+			}
+
+			_, err = engine.Init(ctx, &InitRequest{ProjectRoot: "relative/path"})
+			if !errors.Is(err, ErrRelativePath) {
+				t.Errorf("Init(relative) = %v, want ErrRelativePath", err)
+			}
+		})
+	}
+}
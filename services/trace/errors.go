@@ -10,28 +10,165 @@
 
 package code_buddy
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/budget"
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
+)
 
 // Sentinel errors for the Code Buddy service.
 var (
 	// ErrGraphNotInitialized indicates no graph has been built for the project.
-	ErrGraphNotInitialized = errors.New("graph not initialized")
+	ErrGraphNotInitialized = apperr.New("GRAPH_NOT_INITIALIZED", apperr.CategoryNotFound,
+		"graph not initialized").WithRemediation(remediationInit)
 
 	// ErrGraphExpired indicates the cached graph has been evicted.
-	ErrGraphExpired = errors.New("graph expired")
+	ErrGraphExpired = apperr.New("GRAPH_EXPIRED", apperr.CategoryNotFound,
+		"graph expired").WithRemediation(remediationInit)
 
 	// ErrRelativePath indicates the project root was a relative path.
-	ErrRelativePath = errors.New("project root must be absolute path")
+	ErrRelativePath = apperr.New("INVALID_PATH", apperr.CategoryInvalidInput,
+		"project root must be absolute path").WithRemediation("Pass an absolute project_root")
 
 	// ErrPathTraversal indicates path contains .. traversal sequences.
-	ErrPathTraversal = errors.New("path contains traversal sequences")
+	ErrPathTraversal = apperr.New("PATH_TRAVERSAL", apperr.CategoryInvalidInput,
+		"path contains traversal sequences")
 
 	// ErrProjectTooLarge indicates the project exceeds size limits.
-	ErrProjectTooLarge = errors.New("project exceeds size limits")
+	ErrProjectTooLarge = apperr.New("PROJECT_TOO_LARGE", apperr.CategoryInvalidInput,
+		"project exceeds size limits").
+		WithRemediation("Exclude vendored or generated directories with exclude_patterns")
 
 	// ErrInitInProgress indicates another init is already running for this project.
-	ErrInitInProgress = errors.New("initialization in progress")
+	ErrInitInProgress = apperr.New("INIT_IN_PROGRESS", apperr.CategoryConflict,
+		"initialization in progress").WithRemediation("Wait for the running initialization to finish")
 
 	// ErrInitTimeout indicates the init operation timed out.
-	ErrInitTimeout = errors.New("initialization timed out")
+	ErrInitTimeout = apperr.New("INIT_TIMEOUT", apperr.CategoryTimeout,
+		"initialization timed out")
+
+	// ErrInvalidAgentConfig indicates the agent session config was rejected.
+	ErrInvalidAgentConfig = apperr.New("INVALID_CONFIG", apperr.CategoryInvalidInput,
+		"invalid agent session config")
 )
+
+// remediationInit tells clients how to (re)build a graph.
+const remediationInit = "Initialize the project with POST /v1/codebuddy/init"
+
+// apiErrors registers the remaining error codes the handlers return, so
+// their responses carry a category, retryability and remediation.
+var apiErrors = []*apperr.Error{
+	apperr.ErrInvalidRequest,
+	apperr.ErrMissingParameter,
+	apperr.ErrInternal,
+	apperr.New("GRAPH_NOT_FOUND", apperr.CategoryNotFound, "graph not found").
+		WithRemediation(remediationInit),
+	apperr.New("NO_GRAPHS", apperr.CategoryNotFound, "no graphs loaded").
+		WithRemediation(remediationInit),
+	apperr.New("SYMBOL_NOT_FOUND", apperr.CategoryNotFound, "symbol not found"),
+	apperr.New("FILE_NOT_INDEXED", apperr.CategoryNotFound, "file not indexed"),
+	apperr.New("INVALID_POSITION", apperr.CategoryInvalidInput, "invalid source position"),
+	apperr.New("SESSION_NOT_FOUND", apperr.CategoryNotFound, "session not found"),
+	apperr.New("PLAN_NOT_FOUND", apperr.CategoryNotFound, "plan not found"),
+	apperr.New("MEMORY_NOT_FOUND", apperr.CategoryNotFound, "memory not found"),
+	apperr.New("JOB_NOT_FOUND", apperr.CategoryNotFound, "job not found"),
+	apperr.New("EMPTY_QUERY", apperr.CategoryInvalidInput, "query is empty"),
+	apperr.New("QUERY_TOO_LONG", apperr.CategoryInvalidInput, "query is too long"),
+	apperr.New("INVALID_SESSION", apperr.CategoryInvalidInput, "invalid session configuration"),
+	apperr.New("INVALID_DIFF", apperr.CategoryInvalidInput, "invalid diff"),
+	apperr.New("INVALID_CURSOR", apperr.CategoryInvalidInput, "invalid pagination cursor").
+		WithRemediation("Restart pagination without a cursor"),
+	apperr.New("VALIDATION_FAILED", apperr.CategoryInvalidInput, "validation failed"),
+	apperr.New("UNKNOWN_JOB_KIND", apperr.CategoryInvalidInput, "unknown job kind"),
+	apperr.New("SESSION_IN_PROGRESS", apperr.CategoryConflict, "session operation in progress"),
+	apperr.New("JOB_NOT_FINISHED", apperr.CategoryConflict, "job has not finished"),
+	apperr.New("JOB_FINISHED", apperr.CategoryConflict, "job has already finished").WithRetryable(false),
+	apperr.New("JOB_FAILED", apperr.CategoryInternal, "job failed"),
+	apperr.New("JOB_CANCELED", apperr.CategoryConflict, "job was canceled").WithRetryable(false),
+	apperr.New("BUDGET_EXCEEDED", apperr.CategoryRateLimited, "budget exceeded").
+		WithRemediation("Wait for the budget window to reset or raise the API key's budget"),
+	apperr.New("CACHE_NOT_AVAILABLE", apperr.CategoryUnavailable, "cache not available"),
+	apperr.New("JOB_QUEUE_UNAVAILABLE", apperr.CategoryUnavailable, "job queue unavailable"),
+	apperr.New("JOBS_DISABLED", apperr.CategoryUnavailable, "background jobs are disabled").WithRetryable(false),
+	apperr.New("MEMORY_NOT_CONFIGURED", apperr.CategoryUnavailable, "memory store not configured").
+		WithRetryable(false).WithRemediation("Configure Weaviate for the trace service"),
+	apperr.New("WEAVIATE_NOT_CONFIGURED", apperr.CategoryUnavailable, "Weaviate not configured").
+		WithRetryable(false).WithRemediation("Configure Weaviate for the trace service"),
+	apperr.New("WEBHOOK_DISABLED", apperr.CategoryUnavailable, "webhook disabled").WithRetryable(false),
+}
+
+// errorCode maps a sentinel error to its HTTP status and API error code.
+//
+// The handlers use it to answer requests and the HTTP engine uses it to
+// turn error responses back into sentinels, so callers can match errors
+// with errors.Is whichever transport they use.
+type errorCode struct {
+	err    error
+	status int
+	code   string
+}
+
+// initErrorCodes are the errors of Init.
+var initErrorCodes = []errorCode{
+	{ErrRelativePath, http.StatusBadRequest, "INVALID_PATH"},
+	{ErrPathTraversal, http.StatusBadRequest, "PATH_TRAVERSAL"},
+	{ErrProjectTooLarge, http.StatusBadRequest, "PROJECT_TOO_LARGE"},
+	{ErrInitInProgress, http.StatusConflict, "INIT_IN_PROGRESS"},
+	{ErrInitTimeout, http.StatusGatewayTimeout, "INIT_TIMEOUT"},
+}
+
+// contextErrorCodes are the errors of GetContext.
+var contextErrorCodes = []errorCode{
+	{ErrGraphNotInitialized, http.StatusBadRequest, "GRAPH_NOT_INITIALIZED"},
+	{ErrGraphExpired, http.StatusBadRequest, "GRAPH_EXPIRED"},
+	{cbcontext.ErrEmptyQuery, http.StatusBadRequest, "EMPTY_QUERY"},
+	{cbcontext.ErrQueryTooLong, http.StatusBadRequest, "QUERY_TOO_LONG"},
+}
+
+// agentRunErrorCodes are the errors of RunAgent.
+var agentRunErrorCodes = []errorCode{
+	{ErrInvalidAgentConfig, http.StatusBadRequest, "INVALID_CONFIG"},
+	{agent.ErrInvalidSession, http.StatusBadRequest, "INVALID_SESSION"},
+	{agent.ErrEmptyQuery, http.StatusBadRequest, "EMPTY_QUERY"},
+	{agent.ErrSessionInProgress, http.StatusConflict, "SESSION_IN_PROGRESS"},
+	{budget.ErrBudgetExceeded, http.StatusTooManyRequests, "BUDGET_EXCEEDED"},
+}
+
+// agentUndoErrorCodes are the errors of undoing and redoing agent edits.
+var agentUndoErrorCodes = []errorCode{
+	{undo.ErrInvalidCount, http.StatusBadRequest, "INVALID_COUNT"},
+	{undo.ErrNothingToUndo, http.StatusConflict, "NOTHING_TO_UNDO"},
+	{undo.ErrNothingToRedo, http.StatusConflict, "NOTHING_TO_REDO"},
+	{undo.ErrConflict, http.StatusConflict, "EDIT_CONFLICT"},
+	{agent.ErrSessionInProgress, http.StatusConflict, "SESSION_IN_PROGRESS"},
+}
+
+// statusForError returns the HTTP status and error code for err, from
+// codes or else from err's apperr classification, or fallbackCode with
+// 500 Internal Server Error if err is neither.
+func statusForError(codes []errorCode, err error, fallbackCode string) (int, string) {
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.status, c.code
+		}
+	}
+	if e, ok := apperr.As(err); ok {
+		return e.Category.HTTPStatus(), e.Code
+	}
+	return http.StatusInternalServerError, fallbackCode
+}
+
+// errorForCode returns the sentinel error for an API error code, or nil.
+func errorForCode(codes []errorCode, code string) error {
+	for _, c := range codes {
+		if c.code == code {
+			return c.err
+		}
+	}
+	return nil
+}
@@ -90,6 +90,29 @@ type DecisionConfig struct {
 	// RequireCorrectness requires correctness match rate >= threshold.
 	// Default: 0.99 (99% correctness match)
 	RequireCorrectness float64
+
+	// Sequential enables mixture sequential probability ratio testing
+	// (mSPRT). The engine may then decide as soon as the evidence crosses
+	// the 1/MaxPValue boundary instead of waiting for MinSamples and
+	// MinPower; MinSamples instead caps the experiment (see
+	// SequentialHorizon).
+	// Default: false
+	Sequential bool
+
+	// SequentialBurnIn is the minimum samples per variant before the first
+	// sequential look, so variance estimates are stable.
+	// Default: 20 (when 0)
+	SequentialBurnIn int
+
+	// SequentialHorizon is the samples per variant after which a sequential
+	// experiment that has not reached significance ends with NoDifference.
+	// Default: MinSamples (when 0)
+	SequentialHorizon int
+
+	// MixtureEffectSize is the standard deviation of the mSPRT mixing
+	// distribution, in Cohen's d units.
+	// Default: MinEffectSize (when 0)
+	MixtureEffectSize float64
 }
 
 // DefaultDecisionConfig returns sensible defaults.
@@ -198,6 +221,13 @@ type Evidence struct {
 
 	// ExperimentDuration is how long the experiment has been running.
 	ExperimentDuration time.Duration
+
+	// Sequential is the mSPRT result. Nil unless sequential testing is enabled.
+	Sequential *SequentialResult
+
+	// EarlyStop is true if a sequential test reached significance before
+	// MinSamples per variant.
+	EarlyStop bool
 }
 
 // DecisionInput contains the data needed to make a decision.
@@ -245,12 +275,16 @@ func (e *DecisionEngine) Evaluate(input *DecisionInput) *Decision {
 	evidence.ExperimentDuration = time.Since(input.ExperimentStartTime)
 
 	// Check minimum samples
-	if evidence.ControlSamples < e.config.MinSamples ||
-		evidence.ExperimentSamples < e.config.MinSamples {
+	minSamples := e.config.MinSamples
+	if e.config.Sequential {
+		minSamples = e.sequentialBurnIn()
+	}
+	if evidence.ControlSamples < minSamples ||
+		evidence.ExperimentSamples < minSamples {
 		decision.Recommendation = NeedMoreData
 		decision.Reason = fmt.Sprintf(
 			"Insufficient samples: control=%d, experiment=%d (need %d each)",
-			evidence.ControlSamples, evidence.ExperimentSamples, e.config.MinSamples,
+			evidence.ControlSamples, evidence.ExperimentSamples, minSamples,
 		)
 		return decision
 	}
@@ -304,6 +338,12 @@ func (e *DecisionEngine) Evaluate(input *DecisionInput) *Decision {
 		evidence.EffectSize, e.config.MaxPValue,
 	)
 
+	// Sequential tests are valid at every look, so they bypass the
+	// fixed-horizon power and timeout checks
+	if e.config.Sequential {
+		return e.makeSequentialDecision(decision, evidence, input)
+	}
+
 	// Check if we've exceeded max duration
 	if evidence.ExperimentDuration > e.config.MaxDuration {
 		return e.makeTimeoutDecision(decision, evidence)
@@ -68,6 +68,43 @@
 //   - Cohen's d for effect size measurement
 //   - Bootstrap confidence intervals for robustness
 //   - Power analysis to determine required sample sizes
+//   - Mixture sequential probability ratio test (mSPRT) for early stopping
+//
+// # Sequential Testing
+//
+// By default the decision engine waits for MinSamples per variant and
+// sufficient power before deciding. For expensive variants (e.g. ones that
+// make LLM calls) WithSequentialTesting switches to an mSPRT, whose
+// always-valid p-value may be checked after every sample without inflating
+// the false positive rate:
+//
+//	harness, _ := ab.NewHarness(controlAlgo, experimentAlgo,
+//	    ab.WithSequentialTesting(true),
+//	    ab.WithMinSamples(1000), // Upper bound when sequential
+//	)
+//
+//	if harness.Stopped() {
+//	    // A decision was reached; Compare no longer runs the experiment
+//	}
+//
+// The harness looks every 10 experiment calls once SequentialBurnIn samples
+// are available, and ends with NoDifference at SequentialHorizon.
+//
+// # Adaptive Allocation
+//
+// With SamplerTypeBandit the split is not fixed: a Thompson-sampling
+// BanditSampler keeps a Beta posterior of each variant's win rate (the
+// faster variant of a paired Compare wins; errors lose) and routes each
+// request by a draw from both posteriors. Traffic drifts toward the
+// better variant as evidence accumulates, but each variant always keeps
+// at least the exploration floor:
+//
+//	harness, _ := ab.NewHarness(controlAlgo, experimentAlgo,
+//	    ab.WithSamplerType(ab.SamplerTypeBandit),
+//	    ab.WithExplorationFloor(0.1), // each variant keeps >= 10% of traffic
+//	)
+//
+// Results.SampleRate reports the current experiment share.
 //
 // # Thread Safety
 //
@@ -55,6 +55,11 @@ type HarnessConfig struct {
 	// Default: SamplerTypeHash
 	SamplerType SamplerType
 
+	// ExplorationFloor is the minimum traffic share each variant keeps
+	// under SamplerTypeBandit (0.0 to 0.5).
+	// Default: 0.05 (5%)
+	ExplorationFloor float64
+
 	// RunBothAlways runs both algorithms for every request (for comparison).
 	// Default: false
 	RunBothAlways bool
@@ -95,14 +100,15 @@ const (
 //   - *HarnessConfig: Default configuration. Never nil.
 func DefaultHarnessConfig() *HarnessConfig {
 	return &HarnessConfig{
-		SampleRate:     0.1,
-		MaxSamples:     10000,
-		SamplerType:    SamplerTypeHash,
-		RunBothAlways:  false,
-		CompareOutputs: true,
-		DecisionConfig: DefaultDecisionConfig(),
-		Logger:         slog.Default(),
-		MetricsPrefix:  "ab_harness",
+		SampleRate:       0.1,
+		MaxSamples:       10000,
+		SamplerType:      SamplerTypeHash,
+		ExplorationFloor: 0.05,
+		RunBothAlways:    false,
+		CompareOutputs:   true,
+		DecisionConfig:   DefaultDecisionConfig(),
+		Logger:           slog.Default(),
+		MetricsPrefix:    "ab_harness",
 	}
 }
 
@@ -138,6 +144,16 @@ func WithSamplerType(t SamplerType) HarnessOption {
 	}
 }
 
+// WithExplorationFloor sets the minimum traffic share of each variant
+// under SamplerTypeBandit.
+func WithExplorationFloor(floor float64) HarnessOption {
+	return func(c *HarnessConfig) {
+		if floor >= 0 && floor <= 0.5 {
+			c.ExplorationFloor = floor
+		}
+	}
+}
+
 // WithRunBothAlways enables running both algorithms for every request.
 func WithRunBothAlways(enabled bool) HarnessOption {
 	return func(c *HarnessConfig) {
@@ -188,10 +204,31 @@ func WithConfidenceLevel(level float64) HarnessOption {
 	}
 }
 
+// WithSequentialTesting enables mSPRT early stopping.
+//
+// Description:
+//
+//	The decision engine evaluates a mixture sequential probability ratio
+//	test after every look and the harness stops running the experiment
+//	once a decision is reached, instead of waiting for MinSamples.
+//	MinSamples becomes the upper bound on samples per variant.
+func WithSequentialTesting(enabled bool) HarnessOption {
+	return func(c *HarnessConfig) {
+		if c.DecisionConfig != nil {
+			c.DecisionConfig.Sequential = enabled
+		}
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Harness
 // -----------------------------------------------------------------------------
 
+// sequentialLookInterval is how many experiment calls pass between
+// sequential looks. The mSPRT is valid at any look; spacing them only
+// bounds the cost of re-evaluating the statistics.
+const sequentialLookInterval = 10
+
 // Harness runs A/B tests comparing two algorithm implementations.
 //
 // Description:
@@ -223,6 +260,9 @@ type Harness struct {
 	experimentCalls  atomic.Int64
 	controlErrors    atomic.Int64
 	experimentErrors atomic.Int64
+
+	// stopped is set once a sequential test reaches a decision.
+	stopped atomic.Bool
 }
 
 // NewHarness creates a new A/B test harness.
@@ -258,7 +298,7 @@ func NewHarness(control, experiment eval.Evaluable, opts ...HarnessOption) (*Har
 	case SamplerTypeRandom:
 		sampler = NewRandomSampler(config.SampleRate)
 	case SamplerTypeBandit:
-		sampler = NewBanditSampler(0.05)
+		sampler = NewBanditSampler(config.ExplorationFloor)
 		sampler.SetRate(config.SampleRate)
 	case SamplerTypeRampUp:
 		sampler = NewRampUpSampler(0.01, config.SampleRate, 24*time.Hour)
@@ -328,7 +368,7 @@ func (h *Harness) Compare(
 
 	// Decide whether to run experiment
 	runExperiment := h.config.RunBothAlways || h.sampler.Sample(key)
-	if !runExperiment {
+	if !runExperiment || h.stopped.Load() {
 		return controlOutput, controlErr
 	}
 
@@ -339,6 +379,7 @@ func (h *Harness) Compare(
 		h.experimentErrors.Add(1)
 	} else {
 		h.expSamples.Add(expDuration)
+		h.checkSequentialStop()
 	}
 
 	// Compare outputs if enabled
@@ -357,15 +398,17 @@ func (h *Harness) Compare(
 
 	// Update bandit if using adaptive sampling
 	if bandit, ok := h.sampler.(*BanditSampler); ok {
-		if controlErr == nil && expErr == nil {
+		switch {
+		case controlErr == nil && expErr == nil:
 			// Record based on relative performance
-			if expDuration < controlDuration {
-				bandit.RecordSuccess(true)
-				bandit.RecordFailure(false)
-			} else {
-				bandit.RecordSuccess(false)
-				bandit.RecordFailure(true)
-			}
+			bandit.RecordSuccess(expDuration < controlDuration)
+			bandit.RecordFailure(expDuration >= controlDuration)
+		case expErr != nil && controlErr == nil:
+			bandit.RecordSuccess(false)
+			bandit.RecordFailure(true)
+		case controlErr != nil && expErr == nil:
+			bandit.RecordSuccess(true)
+			bandit.RecordFailure(false)
 		}
 	}
 
@@ -392,19 +435,87 @@ func (h *Harness) SelectVariant(key string) bool {
 //
 // Thread Safety: Safe for concurrent use.
 func (h *Harness) RecordLatency(experiment bool, duration time.Duration) {
+	h.rewardBandit(experiment, duration)
 	if experiment {
 		h.expSamples.Add(duration)
 		h.experimentCalls.Add(1)
+		h.checkSequentialStop()
 	} else {
 		h.controlSamples.Add(duration)
 		h.controlCalls.Add(1)
 	}
 }
 
+// Stopped returns true once a sequential test has reached a decision.
+//
+// Description:
+//
+//	After Stopped returns true, Compare no longer runs the experiment.
+//	Callers that use SelectVariant and RecordLatency should stop running
+//	the experiment themselves. Always false without WithSequentialTesting.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Harness) Stopped() bool {
+	return h.stopped.Load()
+}
+
+// checkSequentialStop evaluates the sequential test every
+// sequentialLookInterval experiment calls and marks the harness stopped
+// once it reaches a decision.
+func (h *Harness) checkSequentialStop() {
+	if !h.config.DecisionConfig.Sequential || h.stopped.Load() {
+		return
+	}
+	if h.experimentCalls.Load()%sequentialLookInterval != 0 {
+		return
+	}
+
+	decision := h.GetDecision()
+	if decision.Recommendation == NeedMoreData {
+		return
+	}
+	if h.stopped.CompareAndSwap(false, true) {
+		h.logger.Info("sequential test reached a decision",
+			slog.String("experiment", h.experiment.Name()),
+			slog.String("recommendation", decision.Recommendation.String()),
+			slog.Int("control_samples", decision.Evidence.ControlSamples),
+			slog.Int("experiment_samples", decision.Evidence.ExperimentSamples),
+		)
+	}
+}
+
+// rewardBandit updates a bandit sampler with an unpaired latency. The
+// variant wins if it was at least as fast as the other variant's mean;
+// nothing is recorded until the other variant has samples.
+func (h *Harness) rewardBandit(experiment bool, duration time.Duration) {
+	bandit, ok := h.sampler.(*BanditSampler)
+	if !ok {
+		return
+	}
+	other := h.controlSamples
+	if !experiment {
+		other = h.expSamples
+	}
+	samples := other.Samples()
+	if len(samples) == 0 {
+		return
+	}
+	if float64(duration) <= mean(samples) {
+		bandit.RecordSuccess(experiment)
+	} else {
+		bandit.RecordFailure(experiment)
+	}
+}
+
 // RecordError records an error for the specified variant.
 //
+// Errors count as losses for a bandit sampler.
+//
 // Thread Safety: Safe for concurrent use.
 func (h *Harness) RecordError(experiment bool) {
+	if bandit, ok := h.sampler.(*BanditSampler); ok {
+		bandit.RecordFailure(experiment)
+	}
 	if experiment {
 		h.experimentErrors.Add(1)
 	} else {
@@ -440,6 +551,7 @@ func (h *Harness) GetResults() *Results {
 		ExperimentCalls:   h.experimentCalls.Load(),
 		ControlErrors:     h.controlErrors.Load(),
 		ExperimentErrors:  h.experimentErrors.Load(),
+		Stopped:           h.stopped.Load(),
 	}
 
 	// Calculate correctness
@@ -522,6 +634,7 @@ func (h *Harness) Reset() {
 	h.experimentCalls.Store(0)
 	h.controlErrors.Store(0)
 	h.experimentErrors.Store(0)
+	h.stopped.Store(false)
 }
 
 // SetSampleRate updates the experiment sample rate.
@@ -551,6 +664,9 @@ type Results struct {
 	ControlErrors    int64
 	ExperimentErrors int64
 
+	// Stopped is true if a sequential test ended the experiment.
+	Stopped bool
+
 	// Timing
 	Duration   time.Duration
 	SampleRate float64
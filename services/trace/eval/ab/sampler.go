@@ -12,6 +12,8 @@ package ab
 
 import (
 	"hash/fnv"
+	"math"
+	"math/rand/v2"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -176,14 +178,19 @@ func (s *HashSampler) SetRate(rate float64) {
 // Description:
 //
 //	BanditSampler implements Thompson Sampling, a Bayesian approach
-//	to the multi-armed bandit problem. It balances exploration (trying
-//	both variants) with exploitation (preferring the better variant).
-//	As evidence accumulates, traffic is automatically shifted toward
-//	the better-performing variant.
+//	to the multi-armed bandit problem. Each variant's win rate has a
+//	Beta posterior; every request draws once from both posteriors and
+//	routes to the variant with the higher draw. As evidence accumulates,
+//	traffic shifts toward the better-performing variant in proportion to
+//	the probability that it is better.
+//
+//	The exploration floor guarantees each variant at least that share of
+//	traffic, so the losing variant keeps producing samples for the
+//	statistical analysis and a variant that was unlucky early can recover.
 //
 // Thread Safety: Safe for concurrent use.
 type BanditSampler struct {
-	mu sync.RWMutex
+	mu sync.Mutex
 
 	// Beta distribution parameters for control
 	controlAlpha float64
@@ -193,29 +200,42 @@ type BanditSampler struct {
 	experimentAlpha float64
 	experimentBeta  float64
 
-	// Minimum exploration rate
-	minExplorationRate float64
+	// Minimum traffic share for each variant, in [0, 0.5]
+	explorationFloor float64
 
-	// RNG seed for Thompson sampling
-	seed uint64
+	rng *rand.Rand
 }
 
 // NewBanditSampler creates a new Thompson Sampling sampler.
 //
 // Inputs:
-//   - minExplorationRate: Minimum rate for each variant (e.g., 0.05 for 5%).
+//   - explorationFloor: Minimum traffic share for each variant (e.g., 0.05
+//     for 5%). Clamped to [0, 0.5].
 //
 // Outputs:
 //   - *BanditSampler: The new sampler. Never nil.
-func NewBanditSampler(minExplorationRate float64) *BanditSampler {
+func NewBanditSampler(explorationFloor float64) *BanditSampler {
+	return NewSeededBanditSampler(explorationFloor, uint64(time.Now().UnixNano()))
+}
+
+// NewSeededBanditSampler creates a Thompson Sampling sampler with a fixed
+// random seed, so the same outcomes produce the same allocation.
+//
+// Inputs:
+//   - explorationFloor: Minimum traffic share for each variant. Clamped to [0, 0.5].
+//   - seed: Seed for the random source.
+//
+// Outputs:
+//   - *BanditSampler: The new sampler. Never nil.
+func NewSeededBanditSampler(explorationFloor float64, seed uint64) *BanditSampler {
 	return &BanditSampler{
 		// Start with uniform prior (alpha=1, beta=1)
-		controlAlpha:       1,
-		controlBeta:        1,
-		experimentAlpha:    1,
-		experimentBeta:     1,
-		minExplorationRate: minExplorationRate,
-		seed:               uint64(time.Now().UnixNano()),
+		controlAlpha:     1,
+		controlBeta:      1,
+		experimentAlpha:  1,
+		experimentBeta:   1,
+		explorationFloor: math.Min(math.Max(explorationFloor, 0), 0.5),
+		rng:              rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
 	}
 }
 
@@ -223,24 +243,25 @@ func NewBanditSampler(minExplorationRate float64) *BanditSampler {
 //
 // Description:
 //
-//	Uses Thompson Sampling to select variant. Samples from Beta
-//	distributions for each variant and selects the one with higher sample.
+//	With probability explorationFloor each, the request is sent to the
+//	experiment or the control outright. Otherwise one draw is taken from
+//	each variant's Beta posterior and the higher draw wins.
 //
 // Thread Safety: Safe for concurrent use.
 func (s *BanditSampler) Sample(_ string) bool {
-	s.mu.RLock()
-	controlSample := s.sampleBeta(s.controlAlpha, s.controlBeta)
-	experimentSample := s.sampleBeta(s.experimentAlpha, s.experimentBeta)
-	minRate := s.minExplorationRate
-	s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Ensure minimum exploration
-	randomValue := s.nextRandom()
-	if randomValue < minRate {
-		// Force random selection at minimum rate
-		return s.nextRandom() < 0.5
+	u := s.rng.Float64()
+	switch {
+	case u < s.explorationFloor:
+		return true
+	case u < 2*s.explorationFloor:
+		return false
 	}
 
+	experimentSample := s.sampleBeta(s.experimentAlpha, s.experimentBeta)
+	controlSample := s.sampleBeta(s.controlAlpha, s.controlBeta)
 	return experimentSample > controlSample
 }
 
@@ -282,22 +303,16 @@ func (s *BanditSampler) RecordFailure(experiment bool) {
 //
 // Description:
 //
-//	Returns the probability that experiment will be selected based
-//	on current Beta distribution parameters. This is an approximation
-//	as actual sampling uses Thompson Sampling.
+//	Returns the probability that Sample selects the experiment: the
+//	exploration floor plus the remaining traffic times the posterior
+//	probability that the experiment is better. That probability uses a
+//	normal approximation of the difference of the two Beta posteriors.
 func (s *BanditSampler) Rate() float64 {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	// Expected value of Beta distribution is alpha / (alpha + beta)
-	controlMean := s.controlAlpha / (s.controlAlpha + s.controlBeta)
-	experimentMean := s.experimentAlpha / (s.experimentAlpha + s.experimentBeta)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Probability experiment is better (rough approximation)
-	if experimentMean > controlMean {
-		return 0.5 + (experimentMean-controlMean)/2
-	}
-	return 0.5 - (controlMean-experimentMean)/2
+	pBetter := betaProbGreater(s.experimentAlpha, s.experimentBeta, s.controlAlpha, s.controlBeta)
+	return s.explorationFloor + (1-2*s.explorationFloor)*pBetter
 }
 
 // SetRate adjusts the Beta distribution to achieve target rate.
@@ -327,38 +342,74 @@ func (s *BanditSampler) SetRate(rate float64) {
 	s.controlBeta = 1
 }
 
-// sampleBeta samples from a Beta distribution using the inverse transform.
+// ExplorationFloor returns the minimum traffic share of each variant.
+func (s *BanditSampler) ExplorationFloor() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.explorationFloor
+}
+
+// Stats returns the current Beta distribution parameters.
+func (s *BanditSampler) Stats() (controlAlpha, controlBeta, expAlpha, expBeta float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.controlAlpha, s.controlBeta, s.experimentAlpha, s.experimentBeta
+}
+
+// sampleBeta draws from Beta(alpha, beta) as X/(X+Y) with X ~ Gamma(alpha)
+// and Y ~ Gamma(beta). Caller must hold s.mu.
 func (s *BanditSampler) sampleBeta(alpha, beta float64) float64 {
-	// Use Kumaraswamy approximation for Beta distribution
-	// For alpha=beta=1, this is uniform
-	u := s.nextRandom()
-
-	// Kumaraswamy CDF inverse: x = (1 - (1-u)^(1/b))^(1/a)
-	// Use a simple approximation that works reasonably well
-	x := u
-	if alpha != 1 || beta != 1 {
-		// Shape the distribution based on alpha and beta
-		// This is a rough approximation for demonstration
-		x = (alpha * u) / (alpha*u + beta*(1-u))
+	x := s.sampleGamma(alpha)
+	y := s.sampleGamma(beta)
+	if x+y == 0 {
+		return 0.5
 	}
-	return x
+	return x / (x + y)
 }
 
-// nextRandom returns a random value in [0, 1).
-func (s *BanditSampler) nextRandom() float64 {
-	s.mu.Lock()
-	s.seed = s.seed*6364136223846793005 + 1442695040888963407
-	result := s.seed
-	s.mu.Unlock()
+// sampleGamma draws from Gamma(shape, 1) with the Marsaglia-Tsang method.
+// Caller must hold s.mu.
+func (s *BanditSampler) sampleGamma(shape float64) float64 {
+	if shape < 1 {
+		// Boost to shape+1 and scale back: Gamma(a) = Gamma(a+1) * U^(1/a)
+		return s.sampleGamma(shape+1) * math.Pow(s.rng.Float64(), 1/shape)
+	}
 
-	return float64(result%1000000000) / 1000000000.0
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := s.rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := s.rng.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
 }
 
-// Stats returns the current Beta distribution parameters.
-func (s *BanditSampler) Stats() (controlAlpha, controlBeta, expAlpha, expBeta float64) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.controlAlpha, s.controlBeta, s.experimentAlpha, s.experimentBeta
+// betaProbGreater approximates P(X > Y) for X ~ Beta(a1, b1) and
+// Y ~ Beta(a2, b2) by treating the difference as normal.
+func betaProbGreater(a1, b1, a2, b2 float64) float64 {
+	mean1, var1 := betaMoments(a1, b1)
+	mean2, var2 := betaMoments(a2, b2)
+	sd := math.Sqrt(var1 + var2)
+	if sd == 0 {
+		if mean1 > mean2 {
+			return 1
+		}
+		return 0
+	}
+	return normalCDF((mean1 - mean2) / sd)
+}
+
+// betaMoments returns the mean and variance of Beta(a, b).
+func betaMoments(a, b float64) (float64, float64) {
+	n := a + b
+	return a / n, a * b / (n * n * (n + 1))
 }
 
 // -----------------------------------------------------------------------------
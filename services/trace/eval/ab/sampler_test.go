@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ab
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Bandit Sampler Tests
+// -----------------------------------------------------------------------------
+
+// experimentShare returns the fraction of n samples routed to experiment.
+func experimentShare(s Sampler, n int) float64 {
+	hits := 0
+	for range n {
+		if s.Sample("") {
+			hits++
+		}
+	}
+	return float64(hits) / float64(n)
+}
+
+func TestBanditSampler_UniformPrior(t *testing.T) {
+	s := NewSeededBanditSampler(0.05, 1)
+
+	if got := s.Rate(); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Rate = %f, want 0.5 with no evidence", got)
+	}
+	if got := experimentShare(s, 10000); math.Abs(got-0.5) > 0.03 {
+		t.Errorf("share = %f, want about 0.5 with no evidence", got)
+	}
+}
+
+func TestBanditSampler_ShiftsTowardWinner(t *testing.T) {
+	s := NewSeededBanditSampler(0.05, 2)
+	for range 200 {
+		s.RecordSuccess(true)
+		s.RecordFailure(false)
+	}
+
+	share := experimentShare(s, 10000)
+	if share < 0.9 {
+		t.Errorf("share = %f, want most traffic on the winning experiment", share)
+	}
+	if math.Abs(share-s.Rate()) > 0.02 {
+		t.Errorf("share = %f, Rate = %f; Rate should predict the allocation", share, s.Rate())
+	}
+}
+
+func TestBanditSampler_ExplorationFloor(t *testing.T) {
+	for _, floor := range []float64{0, 0.05, 0.2} {
+		s := NewSeededBanditSampler(floor, 3)
+		for range 500 {
+			s.RecordSuccess(false)
+			s.RecordFailure(true)
+		}
+
+		share := experimentShare(s, 20000)
+		if share < floor*0.9 || share > floor+0.02 {
+			t.Errorf("floor %.2f: losing experiment share = %f", floor, share)
+		}
+		if got := s.Rate(); math.Abs(got-floor) > 1e-3 {
+			t.Errorf("floor %.2f: Rate = %f", floor, got)
+		}
+	}
+
+	if got := NewBanditSampler(0.9).ExplorationFloor(); got != 0.5 {
+		t.Errorf("floor clamped to %f, want 0.5", got)
+	}
+}
+
+func TestBanditSampler_Deterministic(t *testing.T) {
+	a := NewSeededBanditSampler(0.05, 42)
+	b := NewSeededBanditSampler(0.05, 42)
+	for i := range 1000 {
+		if i%3 == 0 {
+			a.RecordSuccess(true)
+			b.RecordSuccess(true)
+		}
+		if a.Sample("") != b.Sample("") {
+			t.Fatalf("samplers with the same seed diverged at %d", i)
+		}
+	}
+}
+
+func TestBanditSampler_BetaDraws(t *testing.T) {
+	s := NewSeededBanditSampler(0, 4)
+	for _, tc := range []struct{ alpha, beta float64 }{{1, 1}, {0.5, 0.5}, {2, 8}, {30, 10}} {
+		const n = 20000
+		sum := 0.0
+		for range n {
+			x := s.sampleBeta(tc.alpha, tc.beta)
+			if x < 0 || x > 1 {
+				t.Fatalf("Beta(%g,%g) draw %f out of range", tc.alpha, tc.beta, x)
+			}
+			sum += x
+		}
+		want, _ := betaMoments(tc.alpha, tc.beta)
+		if got := sum / n; math.Abs(got-want) > 0.01 {
+			t.Errorf("Beta(%g,%g) mean = %f, want %f", tc.alpha, tc.beta, got, want)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Harness Integration Tests
+// -----------------------------------------------------------------------------
+
+func TestHarness_BanditAllocation(t *testing.T) {
+	harness, err := NewHarness(newMockEvaluable("control"), newMockEvaluable("experiment"),
+		WithSamplerType(SamplerTypeBandit),
+		WithSampleRate(0.5),
+		WithExplorationFloor(0.1),
+	)
+	if err != nil {
+		t.Fatalf("NewHarness: %v", err)
+	}
+
+	slow := func(ctx context.Context, input any) (any, time.Duration, error) {
+		return input, 10 * time.Millisecond, nil
+	}
+	fast := func(ctx context.Context, input any) (any, time.Duration, error) {
+		return input, 5 * time.Millisecond, nil
+	}
+	for range 300 {
+		if _, err := harness.Compare(context.Background(), "k", slow, fast, 1); err != nil {
+			t.Fatalf("Compare: %v", err)
+		}
+	}
+
+	results := harness.GetResults()
+	if results.SampleRate < 0.85 || results.SampleRate > 0.9+1e-9 {
+		t.Errorf("SampleRate = %f, want close to the 0.9 cap for a faster experiment", results.SampleRate)
+	}
+	if results.ExperimentCalls < 150 {
+		t.Errorf("ExperimentCalls = %d, want traffic shifted to the experiment", results.ExperimentCalls)
+	}
+
+	t.Run("errors count as losses", func(t *testing.T) {
+		_, _, _, before := harness.sampler.(*BanditSampler).Stats()
+		harness.RecordError(true)
+		if _, _, _, after := harness.sampler.(*BanditSampler).Stats(); after != before+1 {
+			t.Errorf("experiment beta = %f, want %f", after, before+1)
+		}
+	})
+
+	t.Run("invalid floor ignored", func(t *testing.T) {
+		h, _ := NewHarness(newMockEvaluable("c"), newMockEvaluable("e"), WithExplorationFloor(0.7))
+		if h.config.ExplorationFloor != 0.05 {
+			t.Errorf("ExplorationFloor = %f, want default 0.05", h.config.ExplorationFloor)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ab
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Mixture Sequential Probability Ratio Test
+// -----------------------------------------------------------------------------
+
+// SequentialResult holds the result of a mixture sequential probability
+// ratio test at one look.
+type SequentialResult struct {
+	// LikelihoodRatio is the mixture likelihood ratio Λ of "the means
+	// differ" against "the means are equal".
+	LikelihoodRatio float64
+
+	// Threshold is the rejection boundary 1/alpha.
+	Threshold float64
+
+	// PValue is the always-valid p-value min(1, 1/Λ). Unlike a t-test
+	// p-value it may be checked after every sample without inflating the
+	// false positive rate.
+	PValue float64
+
+	// Significant is true if LikelihoodRatio >= Threshold.
+	Significant bool
+
+	// MixtureSD is the standard deviation of the mixing distribution over
+	// the mean difference (nanoseconds).
+	MixtureSD float64
+}
+
+// MSPRT performs a mixture sequential probability ratio test on the
+// difference of two means.
+//
+// Description:
+//
+//	Computes the mSPRT statistic of Johari et al. for H0: mean2 == mean1
+//	under a normal approximation, with the sampling variance of the
+//	difference estimated as in Welch's t-test and a N(0, mixtureSD²)
+//	mixing distribution over the true difference:
+//
+//	    Λ = sqrt(V/(V+τ²)) · exp(τ²Δ² / (2V(V+τ²)))
+//
+//	The probability that Λ ever crosses 1/alpha under H0 is at most
+//	alpha, so the test may be evaluated after every sample and the
+//	experiment stopped as soon as it is significant.
+//
+//	mixtureSD should be on the scale of the smallest difference worth
+//	detecting: smaller values detect small effects sooner and large
+//	effects later.
+//
+// Inputs:
+//   - samples1: First sample set. Must have at least 2 samples.
+//   - samples2: Second sample set. Must have at least 2 samples.
+//   - mixtureSD: Mixing distribution standard deviation (nanoseconds). Must be positive.
+//   - alpha: Significance level (e.g., 0.05).
+//
+// Outputs:
+//   - *SequentialResult: Likelihood ratio, always-valid p-value, and significance.
+//   - error: Non-nil if samples are insufficient or mixtureSD is not positive.
+//
+// Thread Safety: This function is stateless and safe for concurrent use.
+func MSPRT(samples1, samples2 []time.Duration, mixtureSD, alpha float64) (*SequentialResult, error) {
+	if len(samples1) < 2 || len(samples2) < 2 {
+		return nil, ErrInsufficientSamples
+	}
+	if mixtureSD <= 0 || math.IsNaN(mixtureSD) || math.IsInf(mixtureSD, 0) {
+		return nil, fmt.Errorf("mixture standard deviation must be positive, got %v", mixtureSD)
+	}
+
+	mean1 := mean(samples1)
+	mean2 := mean(samples2)
+	diff := mean2 - mean1
+
+	v := variance(samples1, mean1)/float64(len(samples1)) +
+		variance(samples2, mean2)/float64(len(samples2))
+	tau2 := mixtureSD * mixtureSD
+
+	// Work in log space: the exponent grows linearly with n.
+	var logLR float64
+	switch {
+	case v == 0 && diff == 0:
+		logLR = 0
+	case v == 0:
+		logLR = math.Inf(1)
+	default:
+		logLR = 0.5*math.Log(v/(v+tau2)) + tau2*diff*diff/(2*v*(v+tau2))
+	}
+
+	lr := math.Exp(logLR)
+	threshold := 1 / alpha
+	pValue := 1.0
+	if logLR > 0 {
+		pValue = math.Exp(-logLR)
+	}
+
+	return &SequentialResult{
+		LikelihoodRatio: lr,
+		Threshold:       threshold,
+		PValue:          pValue,
+		Significant:     logLR >= math.Log(threshold),
+		MixtureSD:       mixtureSD,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Sequential Decisions
+// -----------------------------------------------------------------------------
+
+// sequentialBurnIn returns the samples per variant required before the
+// first sequential look.
+func (e *DecisionEngine) sequentialBurnIn() int {
+	if e.config.SequentialBurnIn > 0 {
+		return e.config.SequentialBurnIn
+	}
+	return 20
+}
+
+// sequentialHorizon returns the samples per variant after which a
+// sequential experiment that has not crossed the boundary is stopped.
+func (e *DecisionEngine) sequentialHorizon() int {
+	if e.config.SequentialHorizon > 0 {
+		return e.config.SequentialHorizon
+	}
+	return e.config.MinSamples
+}
+
+// makeSequentialDecision decides from the mSPRT instead of the fixed-horizon
+// t-test and power checks.
+func (e *DecisionEngine) makeSequentialDecision(decision *Decision, evidence *Evidence, input *DecisionInput) *Decision {
+	mixtureEffect := e.config.MixtureEffectSize
+	if mixtureEffect <= 0 {
+		mixtureEffect = e.config.MinEffectSize
+	}
+	if mixtureEffect <= 0 {
+		mixtureEffect = 0.2
+	}
+
+	// Scale the standardized effect to nanoseconds with the pooled SD used by
+	// Cohen's d.
+	controlVar := variance(input.ControlSamples, evidence.ControlMean)
+	expVar := variance(input.ExperimentSamples, evidence.ExperimentMean)
+	pooledSD := math.Sqrt((controlVar + expVar) / 2)
+	if pooledSD == 0 {
+		pooledSD = 1
+	}
+
+	seq, err := MSPRT(input.ControlSamples, input.ExperimentSamples, mixtureEffect*pooledSD, e.config.MaxPValue)
+	if err != nil {
+		decision.Recommendation = NeedMoreData
+		decision.Reason = fmt.Sprintf("Sequential test failed: %v", err)
+		return decision
+	}
+	evidence.Sequential = seq
+
+	if !seq.Significant {
+		horizon := e.sequentialHorizon()
+		reachedHorizon := evidence.ControlSamples >= horizon && evidence.ExperimentSamples >= horizon
+		if reachedHorizon || evidence.ExperimentDuration > e.config.MaxDuration {
+			decision.Recommendation = NoDifference
+			decision.Confidence = 1 - seq.PValue
+			decision.Reason = fmt.Sprintf(
+				"No significant difference by end of sequential test (Λ=%.2f < %.2f). Effect size: %.3f (%s)",
+				seq.LikelihoodRatio, seq.Threshold, evidence.EffectSize, evidence.EffectCategory,
+			)
+			return decision
+		}
+
+		decision.Recommendation = NeedMoreData
+		decision.Confidence = 1 - seq.PValue
+		decision.Reason = fmt.Sprintf(
+			"Sequential test not yet significant (Λ=%.2f < %.2f, p=%.4f). Continuing up to %d samples per group",
+			seq.LikelihoodRatio, seq.Threshold, seq.PValue, horizon,
+		)
+		return decision
+	}
+
+	evidence.EarlyStop = evidence.ControlSamples < e.config.MinSamples ||
+		evidence.ExperimentSamples < e.config.MinSamples
+
+	// Significant: apply the same practical-significance rules as the
+	// fixed-horizon decision, judged on the always-valid p-value.
+	absEffect := math.Abs(evidence.EffectSize)
+	confidence := 1 - seq.PValue
+
+	if absEffect < e.config.MinEffectSize {
+		decision.Recommendation = NoDifference
+		decision.Confidence = confidence
+		decision.Reason = fmt.Sprintf(
+			"Effect size too small: %.3f < %.3f (sequentially significant but not practically)",
+			absEffect, e.config.MinEffectSize,
+		)
+		return decision
+	}
+
+	if evidence.EffectSize > 0 {
+		improvement := -evidence.RelativeImprovement
+		if improvement < e.config.ImprovementThreshold {
+			decision.Recommendation = NoDifference
+			decision.Confidence = confidence
+			decision.Reason = fmt.Sprintf(
+				"Improvement %.2f%% below threshold %.2f%%",
+				improvement*100, e.config.ImprovementThreshold*100,
+			)
+			return decision
+		}
+
+		decision.Recommendation = SwitchToExperiment
+		decision.Confidence = confidence
+		decision.Reason = fmt.Sprintf(
+			"Experiment is %.2f%% faster (sequential p=%.4f, d=%.3f %s) after %d/%d samples",
+			improvement*100, seq.PValue, evidence.EffectSize, evidence.EffectCategory,
+			evidence.ControlSamples, evidence.ExperimentSamples,
+		)
+		return decision
+	}
+
+	decision.Recommendation = KeepControl
+	decision.Confidence = confidence
+	decision.Reason = fmt.Sprintf(
+		"Experiment is %.2f%% slower (sequential p=%.4f, d=%.3f %s) after %d/%d samples. Keeping control.",
+		evidence.RelativeImprovement*100, seq.PValue, evidence.EffectSize, evidence.EffectCategory,
+		evidence.ControlSamples, evidence.ExperimentSamples,
+	)
+	return decision
+}
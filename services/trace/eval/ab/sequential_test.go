@@ -0,0 +1,176 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ab
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// latencies returns n samples around base with a small repeating spread.
+func latencies(n int, base time.Duration) []time.Duration {
+	samples := make([]time.Duration, n)
+	for i := range samples {
+		samples[i] = base + time.Duration(i%5)*time.Millisecond
+	}
+	return samples
+}
+
+func TestMSPRT(t *testing.T) {
+	t.Run("clear difference is significant", func(t *testing.T) {
+		result, err := MSPRT(latencies(30, 100*time.Millisecond), latencies(30, 80*time.Millisecond),
+			float64(time.Millisecond), 0.05)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Significant {
+			t.Errorf("expected significant, got Λ=%.2f", result.LikelihoodRatio)
+		}
+		if result.PValue >= 0.05 {
+			t.Errorf("expected p < 0.05, got %.4f", result.PValue)
+		}
+	})
+
+	t.Run("equal samples are not significant", func(t *testing.T) {
+		samples := latencies(30, 100*time.Millisecond)
+		result, err := MSPRT(samples, samples, float64(time.Millisecond), 0.05)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Significant {
+			t.Errorf("expected not significant, got Λ=%.2f", result.LikelihoodRatio)
+		}
+		if result.PValue != 1 {
+			t.Errorf("expected p = 1, got %.4f", result.PValue)
+		}
+		if result.Threshold != 20 {
+			t.Errorf("expected threshold 20, got %.2f", result.Threshold)
+		}
+	})
+
+	t.Run("insufficient samples", func(t *testing.T) {
+		_, err := MSPRT([]time.Duration{1}, []time.Duration{1, 2}, 1, 0.05)
+		if !errors.Is(err, ErrInsufficientSamples) {
+			t.Errorf("expected ErrInsufficientSamples, got %v", err)
+		}
+	})
+
+	t.Run("non-positive mixture", func(t *testing.T) {
+		if _, err := MSPRT(latencies(5, 1), latencies(5, 2), 0, 0.05); err == nil {
+			t.Error("expected error for zero mixture standard deviation")
+		}
+	})
+}
+
+func TestDecisionEngine_Sequential(t *testing.T) {
+	config := DefaultDecisionConfig()
+	config.Sequential = true
+	engine := NewDecisionEngine(config)
+
+	input := func(control, experiment []time.Duration) *DecisionInput {
+		return &DecisionInput{
+			ControlSamples:      control,
+			ExperimentSamples:   experiment,
+			CorrectnessMatches:  len(experiment),
+			TotalComparisons:    len(experiment),
+			ExperimentStartTime: time.Now(),
+		}
+	}
+
+	t.Run("stops early on a clear winner", func(t *testing.T) {
+		decision := engine.Evaluate(input(latencies(30, 100*time.Millisecond), latencies(30, 80*time.Millisecond)))
+		if decision.Recommendation != SwitchToExperiment {
+			t.Fatalf("expected switch_to_experiment, got %s: %s", decision.Recommendation, decision.Reason)
+		}
+		if !decision.Evidence.EarlyStop {
+			t.Error("expected EarlyStop before MinSamples")
+		}
+		if decision.Evidence.Sequential == nil || !decision.Evidence.Sequential.Significant {
+			t.Errorf("expected significant sequential evidence, got %+v", decision.Evidence.Sequential)
+		}
+	})
+
+	t.Run("keeps control on a clear loser", func(t *testing.T) {
+		decision := engine.Evaluate(input(latencies(30, 80*time.Millisecond), latencies(30, 100*time.Millisecond)))
+		if decision.Recommendation != KeepControl {
+			t.Errorf("expected keep_control, got %s: %s", decision.Recommendation, decision.Reason)
+		}
+	})
+
+	t.Run("waits for burn-in", func(t *testing.T) {
+		decision := engine.Evaluate(input(latencies(10, 100*time.Millisecond), latencies(10, 80*time.Millisecond)))
+		if decision.Recommendation != NeedMoreData {
+			t.Errorf("expected need_more_data, got %s", decision.Recommendation)
+		}
+	})
+
+	t.Run("continues while not significant", func(t *testing.T) {
+		samples := latencies(30, 100*time.Millisecond)
+		decision := engine.Evaluate(input(samples, samples))
+		if decision.Recommendation != NeedMoreData {
+			t.Errorf("expected need_more_data, got %s: %s", decision.Recommendation, decision.Reason)
+		}
+	})
+
+	t.Run("no difference at horizon", func(t *testing.T) {
+		samples := latencies(config.MinSamples, 100*time.Millisecond)
+		decision := engine.Evaluate(input(samples, samples))
+		if decision.Recommendation != NoDifference {
+			t.Errorf("expected no_difference, got %s: %s", decision.Recommendation, decision.Reason)
+		}
+	})
+}
+
+func TestHarness_SequentialStop(t *testing.T) {
+	harness, err := NewHarness(newMockEvaluable("control"), newMockEvaluable("experiment"),
+		WithRunBothAlways(true),
+		WithSequentialTesting(true),
+		WithMinSamples(1000),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	i := 0
+	controlProc := func(ctx context.Context, input any) (any, time.Duration, error) {
+		return "output", 100*time.Millisecond + time.Duration(i%5)*time.Millisecond, nil
+	}
+	expProc := func(ctx context.Context, input any) (any, time.Duration, error) {
+		return "output", 80*time.Millisecond + time.Duration(i%5)*time.Millisecond, nil
+	}
+
+	for ; i < 200; i++ {
+		if _, err := harness.Compare(context.Background(), "key", controlProc, expProc, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if !harness.Stopped() {
+		t.Fatal("expected sequential test to stop the experiment")
+	}
+	results := harness.GetResults()
+	if results.ExperimentCalls >= 200 {
+		t.Errorf("expected experiment to stop early, got %d calls", results.ExperimentCalls)
+	}
+	if results.ControlCalls != 200 {
+		t.Errorf("expected control to keep running, got %d calls", results.ControlCalls)
+	}
+	if !results.Stopped || results.Recommendation != SwitchToExperiment {
+		t.Errorf("expected stopped with switch_to_experiment, got stopped=%v %s", results.Stopped, results.Recommendation)
+	}
+
+	harness.Reset()
+	if harness.Stopped() {
+		t.Error("expected Reset to clear Stopped")
+	}
+}
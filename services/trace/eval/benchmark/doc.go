@@ -78,6 +78,42 @@
 //	    fmt.Printf("%s is %.2fx faster\n", comparison.Winner, comparison.Speedup)
 //	}
 //
+// # Process Isolation
+//
+// In-process benchmarks share one heap, so garbage left by one component
+// can trigger GC pauses that land in another's samples. WithIsolation runs
+// each target in a helper process instead: the runner re-executes the
+// binary (or HelperCommand) with HelperEnv set, sends the configuration
+// as a length-delimited protobuf message on stdin, and reads the raw
+// samples and memory counters back from stdout. Statistics are computed
+// in the parent as usual. The binary must hand control to ServeIsolated
+// when IsHelperProcess reports true:
+//
+//	if benchmark.IsHelperProcess() {
+//	    if err := benchmark.ServeIsolated(ctx, registry, os.Stdin, os.Stdout); err != nil {
+//	        os.Exit(1)
+//	    }
+//	    os.Exit(0)
+//	}
+//	result, err := runner.Run(ctx, "cdcl", benchmark.WithIsolation())
+//
+// # Profiling
+//
+// WithCPUProfile explains a regression as well as detecting it. Each run
+// profiles its measured iterations (in the helper, for isolated runs) and
+// writes <name>.cpu.pprof. Compare then treats the first component as the
+// baseline and writes, for every other one, a differential flame graph
+// (<baseline>_vs_<candidate>.svg) with frames sized by the candidate's CPU
+// time and colored red where it got slower and blue where it got faster,
+// the folded stacks behind it for flamegraph.pl, and comparison.json:
+//
+//	comparison, err := runner.Compare(ctx, []string{"cdcl_v1", "cdcl_v2"},
+//	    benchmark.WithCPUProfile("out/bench"),
+//	)
+//	for _, diff := range comparison.ProfileDiffs {
+//	    fmt.Println(diff.FlameGraph)
+//	}
+//
 // # Statistical Rigor
 //
 // The benchmark package provides:
@@ -0,0 +1,341 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package benchmark
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Flame graph layout, in pixels.
+const (
+	flameWidth       = 1200.0
+	flameMargin      = 10.0
+	flameFrameHeight = 16.0
+	flameTitleHeight = 40.0
+	flameMinWidth    = 0.1
+	flameCharWidth   = 7.0
+)
+
+// -----------------------------------------------------------------------------
+// Differential Flame Graphs
+// -----------------------------------------------------------------------------
+
+// WriteDiffFolded writes the stacks of two profiles side by side.
+//
+// Description:
+//
+//	Each line is "stack baseline candidate", the input format of
+//	flamegraph.pl for differential flame graphs. Stacks are sorted and
+//	missing stacks count as zero.
+//
+// Inputs:
+//   - w: The destination.
+//   - baseline: The stacks of the baseline profile.
+//   - candidate: The stacks of the candidate profile.
+//
+// Outputs:
+//   - error: Non-nil if writing failed.
+func WriteDiffFolded(w io.Writer, baseline, candidate FoldedStacks) error {
+	keys := make(map[string]struct{}, len(baseline)+len(candidate))
+	for k := range baseline {
+		keys[k] = struct{}{}
+	}
+	for k := range candidate {
+		keys[k] = struct{}{}
+	}
+	stacks := make([]string, 0, len(keys))
+	for k := range keys {
+		stacks = append(stacks, k)
+	}
+	sort.Strings(stacks)
+
+	bw := bufio.NewWriter(w)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(bw, "%s %d %d\n", stack, baseline[stack], candidate[stack]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// flameNode is one frame of the merged call tree.
+type flameNode struct {
+	name      string
+	baseline  int64
+	candidate int64
+	children  map[string]*flameNode
+}
+
+// child returns the named child, creating it if needed.
+func (n *flameNode) child(name string) *flameNode {
+	c, ok := n.children[name]
+	if !ok {
+		c = &flameNode{name: name, children: make(map[string]*flameNode)}
+		n.children[name] = c
+	}
+	return c
+}
+
+// sortedChildren returns the children in name order, as flame graphs
+// order frames alphabetically rather than by time.
+func (n *flameNode) sortedChildren() []*flameNode {
+	out := make([]*flameNode, 0, len(n.children))
+	for _, c := range n.children {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// mergeStacks builds the call tree of both profiles.
+func mergeStacks(baseline, candidate FoldedStacks) *flameNode {
+	root := &flameNode{name: "all", children: make(map[string]*flameNode)}
+	add := func(stacks FoldedStacks, candidateSide bool) {
+		for stack, v := range stacks {
+			node := root
+			node.add(v, candidateSide)
+			for _, frame := range strings.Split(stack, ";") {
+				node = node.child(frame)
+				node.add(v, candidateSide)
+			}
+		}
+	}
+	add(baseline, false)
+	add(candidate, true)
+	return root
+}
+
+// add accumulates a weight on one side of the comparison.
+func (n *flameNode) add(v int64, candidateSide bool) {
+	if candidateSide {
+		n.candidate += v
+	} else {
+		n.baseline += v
+	}
+}
+
+// WriteDiffFlameGraph renders a differential flame graph as SVG.
+//
+// Description:
+//
+//	Frames are sized by the candidate profile and colored by the change
+//	from the baseline: red frames got slower, blue frames got faster, and
+//	the more saturated the color, the larger the change relative to the
+//	largest change in the graph. Code that only runs in the baseline has
+//	no width in the candidate and is not drawn; the folded file written by
+//	WriteDiffFolded still lists it. Hovering a frame shows both times.
+//
+// Inputs:
+//   - w: The destination.
+//   - title: The heading of the graph.
+//   - baseline: The stacks of the baseline profile, in nanoseconds.
+//   - candidate: The stacks of the candidate profile, in nanoseconds.
+//
+// Outputs:
+//   - error: Non-nil if writing failed.
+func WriteDiffFlameGraph(w io.Writer, title string, baseline, candidate FoldedStacks) error {
+	root := mergeStacks(baseline, candidate)
+
+	depth := treeDepth(root)
+	maxDelta := maxAbsDelta(root)
+	height := flameTitleHeight + float64(depth)*flameFrameHeight + 2*flameMargin
+	scale := 0.0
+	if root.candidate > 0 {
+		scale = (flameWidth - 2*flameMargin) / float64(root.candidate)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<?xml version="1.0" standalone="no"?>
+<svg version="1.1" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" xmlns="http://www.w3.org/2000/svg">
+<rect x="0" y="0" width="100%%" height="100%%" fill="#f8f8f8"/>
+<text x="%.0f" y="24" text-anchor="middle" font-family="Verdana" font-size="17">%s</text>
+<text x="%.0f" y="%.0f" font-family="Verdana" font-size="12" fill="#555">red: slower than baseline, blue: faster; width: candidate CPU time</text>
+`, flameWidth, height, flameWidth, height, flameWidth/2, html.EscapeString(title), flameMargin, flameTitleHeight-6)
+
+	var draw func(n *flameNode, x float64, level int)
+	draw = func(n *flameNode, x float64, level int) {
+		width := float64(n.candidate) * scale
+		if width < flameMinWidth {
+			return
+		}
+		y := height - flameMargin - float64(level+1)*flameFrameHeight
+		fmt.Fprintf(bw, `<g><title>%s</title><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" rx="2" ry="2"/>`,
+			html.EscapeString(frameSummary(n, root)), x, y, width, flameFrameHeight-1, deltaColor(n.candidate-n.baseline, maxDelta))
+		if label := fitLabel(n.name, width); label != "" {
+			fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" font-family="Verdana" font-size="12">%s</text>`,
+				x+3, y+flameFrameHeight-4, html.EscapeString(label))
+		}
+		bw.WriteString("</g>\n")
+
+		for _, c := range n.sortedChildren() {
+			draw(c, x, level+1)
+			x += float64(c.candidate) * scale
+		}
+	}
+	draw(root, flameMargin, 0)
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// treeDepth returns the number of levels in the tree.
+func treeDepth(n *flameNode) int {
+	deepest := 0
+	for _, c := range n.children {
+		deepest = max(deepest, treeDepth(c))
+	}
+	return deepest + 1
+}
+
+// maxAbsDelta returns the largest change of any frame below the root.
+func maxAbsDelta(n *flameNode) int64 {
+	var largest int64
+	for _, c := range n.children {
+		d := c.candidate - c.baseline
+		if d < 0 {
+			d = -d
+		}
+		largest = max(largest, d, maxAbsDelta(c))
+	}
+	return largest
+}
+
+// deltaColor maps a change to a fill: red for slower, blue for faster.
+func deltaColor(delta, maxDelta int64) string {
+	if delta == 0 || maxDelta == 0 {
+		return "rgb(235,235,235)"
+	}
+	v := int(210 * (1 - math.Abs(float64(delta))/float64(maxDelta)))
+	if delta > 0 {
+		return fmt.Sprintf("rgb(255,%d,%d)", v, v)
+	}
+	return fmt.Sprintf("rgb(%d,%d,255)", v, v)
+}
+
+// frameSummary describes a frame for its tooltip.
+func frameSummary(n, root *flameNode) string {
+	change := "new"
+	if n.baseline > 0 {
+		change = fmt.Sprintf("%+.1f%%", 100*float64(n.candidate-n.baseline)/float64(n.baseline))
+	}
+	return fmt.Sprintf("%s\nbaseline: %v (%.2f%%)\ncandidate: %v (%.2f%%)\nchange: %s",
+		n.name,
+		time.Duration(n.baseline), percentOf(n.baseline, root.baseline),
+		time.Duration(n.candidate), percentOf(n.candidate, root.candidate),
+		change)
+}
+
+// percentOf returns v as a percentage of total, or 0 if total is 0.
+func percentOf(v, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(v) / float64(total)
+}
+
+// fitLabel truncates a frame name to the frame's width.
+func fitLabel(name string, width float64) string {
+	chars := int((width - 6) / flameCharWidth)
+	if chars < 3 {
+		return ""
+	}
+	runes := []rune(name)
+	if len(runes) <= chars {
+		return name
+	}
+	return string(runes[:chars-2]) + ".."
+}
+
+// -----------------------------------------------------------------------------
+// Comparison Artifacts
+// -----------------------------------------------------------------------------
+
+// writeProfileDiff writes the differential artifacts of two results.
+//
+// Description:
+//
+//	Reads both CPU profiles and writes <baseline>_vs_<candidate>.folded
+//	and .svg to dir. When the runs completed different numbers of
+//	iterations, the candidate is scaled to the baseline's count so the
+//	graph compares cost per iteration.
+//
+// Outputs:
+//   - ProfileDiff: The paths of the written artifacts.
+//   - error: Non-nil if a profile could not be read or a file written.
+func writeProfileDiff(dir string, baseline, candidate *Result) (ProfileDiff, error) {
+	base, err := readCPUProfileFile(baseline.CPUProfile)
+	if err != nil {
+		return ProfileDiff{}, err
+	}
+	cand, err := readCPUProfileFile(candidate.CPUProfile)
+	if err != nil {
+		return ProfileDiff{}, err
+	}
+	if baseline.Iterations > 0 && candidate.Iterations > 0 && baseline.Iterations != candidate.Iterations {
+		cand = cand.Scale(float64(baseline.Iterations) / float64(candidate.Iterations))
+	}
+
+	stem := filepath.Join(dir, profileFileName(baseline.Name)+"_vs_"+profileFileName(candidate.Name))
+	diff := ProfileDiff{
+		Baseline:   baseline.Name,
+		Candidate:  candidate.Name,
+		Folded:     stem + ".folded",
+		FlameGraph: stem + ".svg",
+	}
+
+	title := fmt.Sprintf("CPU: %s vs %s", candidate.Name, baseline.Name)
+	if err := writeFile(diff.Folded, func(w io.Writer) error { return WriteDiffFolded(w, base, cand) }); err != nil {
+		return ProfileDiff{}, err
+	}
+	if err := writeFile(diff.FlameGraph, func(w io.Writer) error { return WriteDiffFlameGraph(w, title, base, cand) }); err != nil {
+		return ProfileDiff{}, err
+	}
+	return diff, nil
+}
+
+// readCPUProfileFile reads a CPU profile from disk.
+func readCPUProfileFile(path string) (FoldedStacks, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening profile: %w", err)
+	}
+	defer f.Close()
+	stacks, err := ReadCPUProfile(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return stacks, nil
+}
+
+// writeFile creates path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// spin burns CPU for d so the profiler has samples to take.
+func spin(d time.Duration) {
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
+
+func TestRunner_Compare_CPUProfile(t *testing.T) {
+	registry := eval.NewRegistry()
+	registry.MustRegister(eval.NewSimpleEvaluable("v1").
+		SetHealthCheck(func(ctx context.Context) error {
+			spin(200 * time.Microsecond)
+			return nil
+		}))
+	registry.MustRegister(eval.NewSimpleEvaluable("v2").
+		SetHealthCheck(func(ctx context.Context) error {
+			spin(time.Millisecond)
+			return nil
+		}))
+
+	dir := t.TempDir()
+	comparison, err := NewRunner(registry).Compare(context.Background(), []string{"v1", "v2"},
+		WithIterations(200),
+		WithWarmup(0),
+		WithCooldown(0),
+		WithCPUProfile(dir),
+	)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+
+	for name, result := range comparison.Results {
+		if result.CPUProfile != filepath.Join(dir, name+".cpu.pprof") {
+			t.Errorf("%s profile = %q", name, result.CPUProfile)
+		}
+		if _, err := readCPUProfileFile(result.CPUProfile); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	want := []ProfileDiff{{
+		Baseline:   "v1",
+		Candidate:  "v2",
+		Folded:     filepath.Join(dir, "v1_vs_v2.folded"),
+		FlameGraph: filepath.Join(dir, "v1_vs_v2.svg"),
+	}}
+	if !reflect.DeepEqual(comparison.ProfileDiffs, want) {
+		t.Fatalf("ProfileDiffs = %+v, want %+v", comparison.ProfileDiffs, want)
+	}
+	svg, err := os.ReadFile(want[0].FlameGraph)
+	if err != nil || !bytes.Contains(svg, []byte("<svg")) {
+		t.Errorf("flame graph not written: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "comparison.json"))
+	if err != nil {
+		t.Fatalf("reading comparison.json: %v", err)
+	}
+	var saved struct {
+		ProfileDiffs []struct {
+			FlameGraph string `json:"flame_graph"`
+		} `json:"profile_diffs"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved.ProfileDiffs) != 1 || saved.ProfileDiffs[0].FlameGraph != want[0].FlameGraph {
+		t.Errorf("comparison.json does not reference the flame graph: %s (%v)", data, err)
+	}
+}
+
+func TestReadCPUProfile(t *testing.T) {
+	message := func(fields ...func([]byte) []byte) []byte {
+		var b []byte
+		for _, f := range fields {
+			b = f(b)
+		}
+		return b
+	}
+	varint := func(num protowire.Number, v uint64) func([]byte) []byte {
+		return func(b []byte) []byte {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			return protowire.AppendVarint(b, v)
+		}
+	}
+	bytesField := func(num protowire.Number, v []byte) func([]byte) []byte {
+		return func(b []byte) []byte {
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			return protowire.AppendBytes(b, v)
+		}
+	}
+	packed := func(num protowire.Number, vs ...uint64) func([]byte) []byte {
+		var p []byte
+		for _, v := range vs {
+			p = protowire.AppendVarint(p, v)
+		}
+		return bytesField(num, p)
+	}
+
+	// Strings: 0 "", 1 "samples", 2 "cpu", 3 "main", 4 "work", 5 "helper".
+	var fields []func([]byte) []byte
+	for _, s := range []string{"", "samples", "cpu", "main", "work", "helper"} {
+		fields = append(fields, bytesField(6, []byte(s)))
+	}
+	fields = append(fields,
+		bytesField(1, message(varint(1, 1))),
+		bytesField(1, message(varint(1, 2))),
+		bytesField(5, message(varint(1, 1), varint(2, 3))),
+		bytesField(5, message(varint(1, 2), varint(2, 4))),
+		bytesField(5, message(varint(1, 3), varint(2, 5))),
+		// helper is inlined into work, so location 1 lists it first.
+		bytesField(4, message(varint(1, 1), bytesField(4, message(varint(1, 3))), bytesField(4, message(varint(1, 2))))),
+		bytesField(4, message(varint(1, 2), bytesField(4, message(varint(1, 1))))),
+		bytesField(2, message(packed(1, 1, 2), packed(2, 3, 300))),
+		bytesField(2, message(varint(1, 2), packed(2, 1, 100))),
+		bytesField(2, message(varint(1, 2), packed(2, 1, 50))),
+	)
+
+	got, err := ReadCPUProfile(bytes.NewReader(message(fields...)))
+	if err != nil {
+		t.Fatalf("ReadCPUProfile: %v", err)
+	}
+	want := FoldedStacks{"main;work;helper": 300, "main": 150}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stacks = %v, want %v", got, want)
+	}
+}
+
+func TestWriteDiffFlameGraph(t *testing.T) {
+	baseline := FoldedStacks{"main;parse": 100, "main;solve": 400, "main;legacy": 50}
+	candidate := FoldedStacks{"main;parse": 100, "main;solve": 900, "main;cache": 20}
+
+	var folded bytes.Buffer
+	if err := WriteDiffFolded(&folded, baseline, candidate); err != nil {
+		t.Fatalf("WriteDiffFolded: %v", err)
+	}
+	wantFolded := "main;cache 0 20\nmain;legacy 50 0\nmain;parse 100 100\nmain;solve 400 900\n"
+	if folded.String() != wantFolded {
+		t.Errorf("folded =\n%s\nwant\n%s", folded.String(), wantFolded)
+	}
+
+	var svg bytes.Buffer
+	if err := WriteDiffFlameGraph(&svg, "solve <regression>", baseline, candidate); err != nil {
+		t.Fatalf("WriteDiffFlameGraph: %v", err)
+	}
+	out := svg.String()
+	for _, want := range []string{
+		"solve &lt;regression&gt;",
+		"<title>solve\nbaseline: 400ns",
+		`fill="rgb(255,0,0)"`, // solve grew the most
+		"change: +125.0%",
+		"<title>parse\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("flame graph missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<title>legacy") {
+		t.Error("frames absent from the candidate should not be drawn")
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// HelperEnv is set in the environment of isolated helper processes.
+const HelperEnv = "CODE_BUDDY_BENCHMARK_HELPER"
+
+// maxIsolatedMessage bounds a message read from the helper protocol.
+const maxIsolatedMessage = 64 << 20
+
+// -----------------------------------------------------------------------------
+// Helper Process
+// -----------------------------------------------------------------------------
+
+// IsHelperProcess reports whether this process was started as an
+// isolated benchmark helper.
+//
+// Example:
+//
+//	func main() {
+//	    registry := buildRegistry()
+//	    if benchmark.IsHelperProcess() {
+//	        if err := benchmark.ServeIsolated(ctx, registry, os.Stdin, os.Stdout); err != nil {
+//	            os.Exit(1)
+//	        }
+//	        os.Exit(0)
+//	    }
+//	    ...
+//	}
+func IsHelperProcess() bool {
+	return os.Getenv(HelperEnv) == "1"
+}
+
+// ServeIsolated runs one benchmark requested by a parent Runner.
+//
+// Description:
+//
+//	Reads an isolatedRequest from in, runs warmup, cooldown, and the
+//	measured iterations in this process, and writes the raw samples and
+//	memory counters to out as an isolatedResponse. Statistics are
+//	computed by the parent, so isolated and in-process results go through
+//	the same pipeline. Failures running the benchmark are reported to the
+//	parent in the response.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - registry: Registry containing the requested component.
+//   - in: Source of the request, normally os.Stdin.
+//   - out: Destination of the response, normally os.Stdout. Nothing else
+//     may write to it.
+//
+// Outputs:
+//   - error: Non-nil if the request could not be read or the response
+//     could not be written.
+func ServeIsolated(ctx context.Context, registry *eval.Registry, in io.Reader, out io.Writer) error {
+	payload, err := readDelimited(bufio.NewReader(in))
+	if err != nil {
+		return fmt.Errorf("reading isolated request: %w", err)
+	}
+	req, err := decodeIsolatedRequest(payload)
+	if err != nil {
+		return fmt.Errorf("decoding isolated request: %w", err)
+	}
+
+	resp := &isolatedResponse{}
+	component, ok := registry.Get(req.name)
+	if !ok {
+		resp.err = fmt.Sprintf("getting component %s: %v", req.name, eval.ErrNotFound)
+	} else {
+		config := req.config()
+		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+
+		m, err := NewRunner(registry).measure(ctx, component, config)
+		if err != nil {
+			resp.err = err.Error()
+		} else {
+			resp.fromMeasurement(m)
+		}
+	}
+
+	if err := writeDelimited(out, resp.marshal()); err != nil {
+		return fmt.Errorf("writing isolated response: %w", err)
+	}
+	return nil
+}
+
+// measureIsolated runs the benchmark in a helper process.
+func (r *Runner) measureIsolated(ctx context.Context, name string, config *Config) (*measurement, error) {
+	command := config.HelperCommand
+	if len(command) == 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("locating helper executable: %w", err)
+		}
+		command = []string{exe}
+	}
+
+	var request bytes.Buffer
+	if err := writeDelimited(&request, newIsolatedRequest(name, config).marshal()); err != nil {
+		return nil, fmt.Errorf("encoding isolated request: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), HelperEnv+"=1")
+	cmd.Stdin = &request
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	r.logger.Debug("starting isolated benchmark helper",
+		"component", name,
+		"command", command[0],
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running helper for %s: %w (stderr: %s)",
+			name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	payload, err := readDelimited(bufio.NewReader(&stdout))
+	if err != nil {
+		return nil, fmt.Errorf("reading helper response for %s: %w", name, err)
+	}
+	resp, err := decodeIsolatedResponse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding helper response for %s: %w", name, err)
+	}
+	if resp.err != "" {
+		return nil, fmt.Errorf("helper for %s: %s: %w", name, resp.err, ErrBenchmarkFailed)
+	}
+	return resp.toMeasurement(), nil
+}
+
+// -----------------------------------------------------------------------------
+// Wire Format
+// -----------------------------------------------------------------------------
+//
+// Messages are protobuf-encoded and length-delimited (a varint byte count
+// before each message, as in protodelim). The schema is:
+//
+//	message IsolatedRequest {
+//	  string name = 1;
+//	  int64 iterations = 2;
+//	  int64 warmup = 3;
+//	  int64 cooldown_ns = 4;
+//	  int64 timeout_ns = 5;
+//	  int64 iteration_timeout_ns = 6;
+//	  int64 parallelism = 7;
+//	  bool collect_memory = 8;
+//	  string cpu_profile_dir = 9;
+//	}
+//
+//	message IsolatedResponse {
+//	  repeated int64 samples_ns = 1 [packed = true];
+//	  int64 errors = 2;
+//	  uint64 heap_alloc_before = 3;
+//	  uint32 num_gc_before = 4;
+//	  uint64 pause_total_ns_before = 5;
+//	  uint64 heap_alloc_after = 6;
+//	  uint32 num_gc_after = 7;
+//	  uint64 pause_total_ns_after = 8;
+//	  string error = 9;
+//	  bytes cpu_profile = 10;
+//	}
+
+// isolatedRequest asks a helper to run one benchmark.
+type isolatedRequest struct {
+	name             string
+	iterations       int64
+	warmup           int64
+	cooldown         time.Duration
+	timeout          time.Duration
+	iterationTimeout time.Duration
+	parallelism      int64
+	collectMemory    bool
+	cpuProfileDir    string
+}
+
+// newIsolatedRequest builds the request for a benchmark run.
+func newIsolatedRequest(name string, c *Config) *isolatedRequest {
+	return &isolatedRequest{
+		name:             name,
+		iterations:       int64(c.Iterations),
+		warmup:           int64(c.Warmup),
+		cooldown:         c.Cooldown,
+		timeout:          c.Timeout,
+		iterationTimeout: c.IterationTimeout,
+		parallelism:      int64(c.Parallelism),
+		collectMemory:    c.CollectMemory,
+		cpuProfileDir:    c.CPUProfileDir,
+	}
+}
+
+// config returns the helper-side configuration for the request.
+func (q *isolatedRequest) config() *Config {
+	c := DefaultConfig()
+	c.Iterations = int(q.iterations)
+	c.Warmup = int(q.warmup)
+	c.Cooldown = q.cooldown
+	c.CollectMemory = q.collectMemory
+	c.CPUProfileDir = q.cpuProfileDir
+	if q.timeout > 0 {
+		c.Timeout = q.timeout
+	}
+	if q.iterationTimeout > 0 {
+		c.IterationTimeout = q.iterationTimeout
+	}
+	if q.parallelism > 0 {
+		c.Parallelism = int(q.parallelism)
+	}
+	return c
+}
+
+// marshal encodes the request as an IsolatedRequest message.
+func (q *isolatedRequest) marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, q.name)
+	for _, f := range []struct {
+		num protowire.Number
+		v   int64
+	}{
+		{2, q.iterations},
+		{3, q.warmup},
+		{4, int64(q.cooldown)},
+		{5, int64(q.timeout)},
+		{6, int64(q.iterationTimeout)},
+		{7, q.parallelism},
+	} {
+		b = protowire.AppendTag(b, f.num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(f.v))
+	}
+	b = protowire.AppendTag(b, 8, protowire.VarintType)
+	b = protowire.AppendVarint(b, protowire.EncodeBool(q.collectMemory))
+	if q.cpuProfileDir != "" {
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendString(b, q.cpuProfileDir)
+	}
+	return b
+}
+
+// decodeIsolatedRequest parses an IsolatedRequest message.
+func decodeIsolatedRequest(b []byte) (*isolatedRequest, error) {
+	q := &isolatedRequest{}
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if (num == 1 || num == 9) && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			if num == 1 {
+				q.name = v
+			} else {
+				q.cpuProfileDir = v
+			}
+			return n, nil
+		}
+		if typ != protowire.VarintType {
+			return -1, nil
+		}
+		v, n := protowire.ConsumeVarint(b)
+		switch num {
+		case 2:
+			q.iterations = int64(v)
+		case 3:
+			q.warmup = int64(v)
+		case 4:
+			q.cooldown = time.Duration(v)
+		case 5:
+			q.timeout = time.Duration(v)
+		case 6:
+			q.iterationTimeout = time.Duration(v)
+		case 7:
+			q.parallelism = int64(v)
+		case 8:
+			q.collectMemory = protowire.DecodeBool(v)
+		}
+		return n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// isolatedResponse carries a helper's raw measurements.
+type isolatedResponse struct {
+	samples    []time.Duration
+	errorCount int64
+
+	heapAllocBefore, heapAllocAfter   uint64
+	numGCBefore, numGCAfter           uint32
+	pauseTotalBefore, pauseTotalAfter uint64
+
+	err        string
+	cpuProfile []byte
+}
+
+// fromMeasurement copies a helper's measurement into the response.
+func (p *isolatedResponse) fromMeasurement(m *measurement) {
+	p.samples = m.samples
+	p.errorCount = int64(m.errorCount)
+	p.heapAllocBefore = m.memBefore.HeapAlloc
+	p.numGCBefore = m.memBefore.NumGC
+	p.pauseTotalBefore = m.memBefore.PauseTotalNs
+	p.heapAllocAfter = m.memAfter.HeapAlloc
+	p.numGCAfter = m.memAfter.NumGC
+	p.pauseTotalAfter = m.memAfter.PauseTotalNs
+	p.cpuProfile = m.cpuProfile
+}
+
+// toMeasurement rebuilds the measurement on the parent side.
+func (p *isolatedResponse) toMeasurement() *measurement {
+	m := &measurement{
+		samples:    p.samples,
+		errorCount: int(p.errorCount),
+		cpuProfile: p.cpuProfile,
+	}
+	m.memBefore.HeapAlloc = p.heapAllocBefore
+	m.memBefore.NumGC = p.numGCBefore
+	m.memBefore.PauseTotalNs = p.pauseTotalBefore
+	m.memAfter.HeapAlloc = p.heapAllocAfter
+	m.memAfter.NumGC = p.numGCAfter
+	m.memAfter.PauseTotalNs = p.pauseTotalAfter
+	return m
+}
+
+// marshal encodes the response as an IsolatedResponse message.
+func (p *isolatedResponse) marshal() []byte {
+	var b []byte
+	if len(p.samples) > 0 {
+		var packed []byte
+		for _, s := range p.samples {
+			packed = protowire.AppendVarint(packed, uint64(s))
+		}
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, packed)
+	}
+	for _, f := range []struct {
+		num protowire.Number
+		v   uint64
+	}{
+		{2, uint64(p.errorCount)},
+		{3, p.heapAllocBefore},
+		{4, uint64(p.numGCBefore)},
+		{5, p.pauseTotalBefore},
+		{6, p.heapAllocAfter},
+		{7, uint64(p.numGCAfter)},
+		{8, p.pauseTotalAfter},
+	} {
+		b = protowire.AppendTag(b, f.num, protowire.VarintType)
+		b = protowire.AppendVarint(b, f.v)
+	}
+	if p.err != "" {
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendString(b, p.err)
+	}
+	if len(p.cpuProfile) > 0 {
+		b = protowire.AppendTag(b, 10, protowire.BytesType)
+		b = protowire.AppendBytes(b, p.cpuProfile)
+	}
+	return b
+}
+
+// decodeIsolatedResponse parses an IsolatedResponse message.
+func decodeIsolatedResponse(b []byte) (*isolatedResponse, error) {
+	p := &isolatedResponse{}
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			packed, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, nil
+			}
+			for len(packed) > 0 {
+				v, m := protowire.ConsumeVarint(packed)
+				if m < 0 {
+					return -1, protowire.ParseError(m)
+				}
+				p.samples = append(p.samples, time.Duration(v))
+				packed = packed[m:]
+			}
+			return n, nil
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			p.samples = append(p.samples, time.Duration(v))
+			return n, nil
+		case num == 9 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			p.err = v
+			return n, nil
+		case num == 10 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			p.cpuProfile = append([]byte(nil), v...)
+			return n, nil
+		case typ != protowire.VarintType:
+			return -1, nil
+		}
+		v, n := protowire.ConsumeVarint(b)
+		switch num {
+		case 2:
+			p.errorCount = int64(v)
+		case 3:
+			p.heapAllocBefore = v
+		case 4:
+			p.numGCBefore = uint32(v)
+		case 5:
+			p.pauseTotalBefore = v
+		case 6:
+			p.heapAllocAfter = v
+		case 7:
+			p.numGCAfter = uint32(v)
+		case 8:
+			p.pauseTotalAfter = v
+		}
+		return n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// consumeFields walks the fields of a protobuf message. field decodes one
+// field value and returns the bytes consumed, or -1 to skip the field.
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n == -1 {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// writeDelimited writes a varint length prefix followed by msg.
+func writeDelimited(w io.Writer, msg []byte) error {
+	if _, err := w.Write(protowire.AppendVarint(nil, uint64(len(msg)))); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// readDelimited reads one length-prefixed message.
+func readDelimited(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if size > maxIsolatedMessage {
+		return nil, fmt.Errorf("message of %d bytes exceeds limit of %d", size, maxIsolatedMessage)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+// isolationRegistry returns the components shared by the test binary and
+// its helper processes.
+func isolationRegistry() *eval.Registry {
+	registry := eval.NewRegistry()
+	registry.MustRegister(eval.NewSimpleEvaluable("sleepy").
+		SetHealthCheck(func(ctx context.Context) error {
+			time.Sleep(50 * time.Microsecond)
+			return nil
+		}))
+	registry.MustRegister(eval.NewSimpleEvaluable("failing").
+		SetHealthCheck(func(ctx context.Context) error {
+			return errors.New("down")
+		}))
+	return registry
+}
+
+// TestMain lets the test binary act as its own isolated helper.
+func TestMain(m *testing.M) {
+	if IsHelperProcess() {
+		if err := ServeIsolated(context.Background(), isolationRegistry(), os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRunner_Isolated(t *testing.T) {
+	t.Run("runs in a helper process", func(t *testing.T) {
+		runner := NewRunner(isolationRegistry())
+
+		result, err := runner.Run(context.Background(), "sleepy",
+			WithIterations(50),
+			WithWarmup(5),
+			WithCooldown(0),
+			WithIsolation(),
+		)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if !result.Isolated {
+			t.Error("Isolated = false, want true")
+		}
+		if result.Iterations != 50 || len(result.RawSamples) != 50 {
+			t.Errorf("Iterations = %d, samples = %d; want 50", result.Iterations, len(result.RawSamples))
+		}
+		if result.Latency.Min < 50*time.Microsecond {
+			t.Errorf("Min latency = %v, want >= 50µs", result.Latency.Min)
+		}
+		if result.Memory == nil || result.Memory.HeapAllocBefore == 0 {
+			t.Errorf("Memory = %+v, want helper heap stats", result.Memory)
+		}
+	})
+
+	t.Run("helper errors are reported", func(t *testing.T) {
+		runner := NewRunner(isolationRegistry())
+
+		_, err := runner.Run(context.Background(), "failing",
+			WithIterations(5),
+			WithWarmup(0),
+			WithCooldown(0),
+			WithIsolation(),
+		)
+		if !errors.Is(err, ErrBenchmarkFailed) {
+			t.Errorf("err = %v, want ErrBenchmarkFailed", err)
+		}
+	})
+
+	t.Run("input generator is rejected", func(t *testing.T) {
+		runner := NewRunner(isolationRegistry())
+
+		_, err := runner.Run(context.Background(), "sleepy",
+			WithIsolation(),
+			WithInputGenerator(func() any { return 1 }),
+		)
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("err = %v, want ErrInvalidConfig", err)
+		}
+	})
+
+	t.Run("missing helper", func(t *testing.T) {
+		runner := NewRunner(isolationRegistry())
+
+		_, err := runner.Run(context.Background(), "sleepy",
+			WithWarmup(0),
+			WithIsolation("/nonexistent/benchmark-helper"),
+		)
+		if err == nil {
+			t.Error("expected error starting a missing helper")
+		}
+	})
+}
+
+func TestIsolatedWireFormat(t *testing.T) {
+	req := newIsolatedRequest("cdcl", &Config{
+		Iterations:       100,
+		Warmup:           10,
+		Cooldown:         time.Millisecond,
+		Timeout:          time.Minute,
+		IterationTimeout: time.Second,
+		Parallelism:      4,
+		CollectMemory:    true,
+		CPUProfileDir:    "/tmp/profiles",
+	})
+	var buf bytes.Buffer
+	if err := writeDelimited(&buf, req.marshal()); err != nil {
+		t.Fatalf("writeDelimited: %v", err)
+	}
+	payload, err := readDelimited(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("readDelimited: %v", err)
+	}
+	gotReq, err := decodeIsolatedRequest(payload)
+	if err != nil {
+		t.Fatalf("decodeIsolatedRequest: %v", err)
+	}
+	if !reflect.DeepEqual(gotReq, req) {
+		t.Errorf("request round trip = %+v, want %+v", gotReq, req)
+	}
+
+	resp := &isolatedResponse{
+		samples:         []time.Duration{1, 300, 70000},
+		errorCount:      2,
+		heapAllocBefore: 1 << 20,
+		numGCAfter:      3,
+		err:             "boom",
+		cpuProfile:      []byte{0x1f, 0x8b, 0},
+	}
+	gotResp, err := decodeIsolatedResponse(resp.marshal())
+	if err != nil {
+		t.Fatalf("decodeIsolatedResponse: %v", err)
+	}
+	if !reflect.DeepEqual(gotResp, resp) {
+		t.Errorf("response round trip = %+v, want %+v", gotResp, resp)
+	}
+
+	if _, err := readDelimited(bufio.NewReader(bytes.NewReader([]byte{5, 1}))); err == nil {
+		t.Error("expected error reading a truncated message")
+	}
+}
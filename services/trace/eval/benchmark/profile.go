@@ -0,0 +1,340 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package benchmark
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// -----------------------------------------------------------------------------
+// CPU Profile Capture
+// -----------------------------------------------------------------------------
+
+// cpuProfiler captures a CPU profile into memory.
+type cpuProfiler struct {
+	buf bytes.Buffer
+}
+
+// startCPUProfile starts profiling the process.
+//
+// Description:
+//
+//	Go allows one CPU profile per process, so in-process runs that
+//	profile must not overlap. Compare runs components one at a time when
+//	profiling unless they are isolated in helper processes.
+//
+// Outputs:
+//   - *cpuProfiler: The running profiler.
+//   - error: Non-nil if a CPU profile is already running.
+func startCPUProfile() (*cpuProfiler, error) {
+	p := &cpuProfiler{}
+	if err := pprof.StartCPUProfile(&p.buf); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// stop ends profiling and returns the gzipped profile.
+func (p *cpuProfiler) stop() []byte {
+	pprof.StopCPUProfile()
+	return p.buf.Bytes()
+}
+
+// writeCPUProfile writes a component's profile to dir.
+//
+// Outputs:
+//   - string: The path of the written profile.
+//   - error: Non-nil if the directory or file could not be written.
+func writeCPUProfile(dir, name string, profile []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating profile directory: %w", err)
+	}
+	path := filepath.Join(dir, profileFileName(name)+".cpu.pprof")
+	if err := os.WriteFile(path, profile, 0o644); err != nil {
+		return "", fmt.Errorf("writing profile: %w", err)
+	}
+	return path, nil
+}
+
+// profileFileName makes a component name safe to use in a file name.
+func profileFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// -----------------------------------------------------------------------------
+// Profile Parsing
+// -----------------------------------------------------------------------------
+
+// FoldedStacks maps call stacks to their weight.
+//
+// Description:
+//
+//	Each key is a stack of function names from the root to the leaf,
+//	separated by semicolons, as in Brendan Gregg's folded format. For
+//	stacks read from CPU profiles the weight is nanoseconds of CPU time.
+type FoldedStacks map[string]int64
+
+// Total returns the summed weight of all stacks.
+func (s FoldedStacks) Total() int64 {
+	var total int64
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+// Scale returns a copy of the stacks with every weight multiplied by f.
+func (s FoldedStacks) Scale(f float64) FoldedStacks {
+	out := make(FoldedStacks, len(s))
+	for stack, v := range s {
+		out[stack] = int64(float64(v) * f)
+	}
+	return out
+}
+
+// ReadCPUProfile reads a pprof CPU profile into folded stacks.
+//
+// Description:
+//
+//	Decodes the gzipped profile.proto written by runtime/pprof. Inlined
+//	frames are expanded, so a stack lists every function on it. Samples
+//	are weighted by their "cpu" value, or by the last value if the
+//	profile has no such sample type.
+//
+// Inputs:
+//   - r: The profile, gzipped or not.
+//
+// Outputs:
+//   - FoldedStacks: The weighted stacks. Empty for a profile without samples.
+//   - error: Non-nil if the profile could not be decoded.
+func ReadCPUProfile(r io.Reader) (FoldedStacks, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading profile: %w", err)
+	}
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("opening gzipped profile: %w", err)
+		}
+		if data, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("decompressing profile: %w", err)
+		}
+	}
+
+	p, err := decodeProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding profile: %w", err)
+	}
+	return p.fold(), nil
+}
+
+// rawProfile holds the parts of profile.proto needed to fold stacks.
+//
+// The schema subset is:
+//
+//	message Profile {
+//	  repeated ValueType sample_type = 1;  // type = 1 (string index)
+//	  repeated Sample sample = 2;          // location_id = 1, value = 2
+//	  repeated Location location = 4;      // id = 1, line = 4
+//	  repeated Function function = 5;      // id = 1, name = 2 (string index)
+//	  repeated string string_table = 6;
+//	}
+//
+//	message Line { uint64 function_id = 1; }
+type rawProfile struct {
+	sampleTypes []int64
+	samples     []rawSample
+	locations   map[uint64][]uint64 // location ID -> function IDs, innermost first
+	functions   map[uint64]int64    // function ID -> name string index
+	strings     []string
+}
+
+type rawSample struct {
+	locations []uint64
+	values    []int64
+}
+
+// decodeProfile parses an uncompressed Profile message.
+func decodeProfile(b []byte) (*rawProfile, error) {
+	p := &rawProfile{
+		locations: make(map[uint64][]uint64),
+		functions: make(map[uint64]int64),
+	}
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return -1, nil
+		}
+		msg, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, nil
+		}
+		var err error
+		switch num {
+		case 1:
+			err = consumeFields(msg, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				if num != 1 || typ != protowire.VarintType {
+					return -1, nil
+				}
+				v, n := protowire.ConsumeVarint(b)
+				p.sampleTypes = append(p.sampleTypes, int64(v))
+				return n, nil
+			})
+		case 2:
+			var s rawSample
+			err = consumeFields(msg, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				switch num {
+				case 1:
+					return consumeVarints(typ, b, func(v uint64) { s.locations = append(s.locations, v) })
+				case 2:
+					return consumeVarints(typ, b, func(v uint64) { s.values = append(s.values, int64(v)) })
+				}
+				return -1, nil
+			})
+			p.samples = append(p.samples, s)
+		case 4:
+			var id uint64
+			var funcs []uint64
+			err = consumeFields(msg, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				switch {
+				case num == 1 && typ == protowire.VarintType:
+					v, n := protowire.ConsumeVarint(b)
+					id = v
+					return n, nil
+				case num == 4 && typ == protowire.BytesType:
+					line, n := protowire.ConsumeBytes(b)
+					if n < 0 {
+						return n, nil
+					}
+					return n, consumeFields(line, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+						if num != 1 || typ != protowire.VarintType {
+							return -1, nil
+						}
+						v, n := protowire.ConsumeVarint(b)
+						funcs = append(funcs, v)
+						return n, nil
+					})
+				}
+				return -1, nil
+			})
+			p.locations[id] = funcs
+		case 5:
+			var id uint64
+			var name int64
+			err = consumeFields(msg, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				if typ != protowire.VarintType || (num != 1 && num != 2) {
+					return -1, nil
+				}
+				v, n := protowire.ConsumeVarint(b)
+				if num == 1 {
+					id = v
+				} else {
+					name = int64(v)
+				}
+				return n, nil
+			})
+			p.functions[id] = name
+		case 6:
+			p.strings = append(p.strings, string(msg))
+		}
+		return n, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// consumeVarints decodes a repeated varint field, packed or not.
+func consumeVarints(typ protowire.Type, b []byte, add func(uint64)) (int, error) {
+	switch typ {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(b)
+		if n >= 0 {
+			add(v)
+		}
+		return n, nil
+	case protowire.BytesType:
+		packed, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, nil
+		}
+		for len(packed) > 0 {
+			v, m := protowire.ConsumeVarint(packed)
+			if m < 0 {
+				return -1, protowire.ParseError(m)
+			}
+			add(v)
+			packed = packed[m:]
+		}
+		return n, nil
+	}
+	return -1, nil
+}
+
+// fold converts the samples to folded stacks.
+func (p *rawProfile) fold() FoldedStacks {
+	valueIndex := len(p.sampleTypes) - 1
+	for i, t := range p.sampleTypes {
+		if p.str(t) == "cpu" {
+			valueIndex = i
+		}
+	}
+
+	stacks := make(FoldedStacks)
+	var frames []string
+	for _, s := range p.samples {
+		if valueIndex < 0 || valueIndex >= len(s.values) || s.values[valueIndex] == 0 {
+			continue
+		}
+		// Locations run from leaf to root, and the functions of a
+		// location from the innermost inlined call outward.
+		frames = frames[:0]
+		for i := len(s.locations) - 1; i >= 0; i-- {
+			funcs := p.locations[s.locations[i]]
+			for j := len(funcs) - 1; j >= 0; j-- {
+				name := p.str(p.functions[funcs[j]])
+				if name == "" {
+					name = "[unknown]"
+				}
+				frames = append(frames, strings.ReplaceAll(name, ";", ":"))
+			}
+		}
+		if len(frames) == 0 {
+			continue
+		}
+		stacks[strings.Join(frames, ";")] += s.values[valueIndex]
+	}
+	return stacks
+}
+
+// str returns an entry of the string table, or "" if out of range.
+func (p *rawProfile) str(i int64) string {
+	if i < 0 || i >= int64(len(p.strings)) {
+		return ""
+	}
+	return p.strings[i]
+}
@@ -213,6 +213,13 @@ func (r *ConsoleReporter) ReportComparison(comparison *ComparisonResult) error {
 		sb.WriteString("\n  No statistically significant winner.\n")
 	}
 
+	if len(comparison.ProfileDiffs) > 0 {
+		sb.WriteString("\nFlame Graphs:\n")
+		for _, diff := range comparison.ProfileDiffs {
+			sb.WriteString(fmt.Sprintf("  %s vs %s: %s\n", diff.Candidate, diff.Baseline, diff.FlameGraph))
+		}
+	}
+
 	sb.WriteString("\n")
 	_, err := io.WriteString(r.out, sb.String())
 	return err
@@ -296,6 +303,7 @@ type jsonResult struct {
 	Throughput    jsonThroughput   `json:"throughput"`
 	Memory        *jsonMemoryStats `json:"memory,omitempty"`
 	Timestamp     time.Time        `json:"timestamp"`
+	CPUProfile    string           `json:"cpu_profile,omitempty"`
 }
 
 type jsonLatencyStats struct {
@@ -325,6 +333,13 @@ type jsonMemoryStats struct {
 	GCPauseTotal    string `json:"gc_pause_total"`
 }
 
+type jsonProfileDiff struct {
+	Baseline   string `json:"baseline"`
+	Candidate  string `json:"candidate"`
+	Folded     string `json:"folded"`
+	FlameGraph string `json:"flame_graph"`
+}
+
 // Report writes a benchmark result as JSON.
 //
 // Description:
@@ -367,6 +382,7 @@ func (r *JSONReporter) ReportComparison(comparison *ComparisonResult) error {
 		EffectSize         float64               `json:"effect_size"`
 		EffectSizeCategory string                `json:"effect_size_category"`
 		Ranking            []string              `json:"ranking"`
+		ProfileDiffs       []jsonProfileDiff     `json:"profile_diffs,omitempty"`
 	}
 
 	jc := jsonComparison{
@@ -384,6 +400,9 @@ func (r *JSONReporter) ReportComparison(comparison *ComparisonResult) error {
 	for name, result := range comparison.Results {
 		jc.Results[name] = r.convertResult(result)
 	}
+	for _, diff := range comparison.ProfileDiffs {
+		jc.ProfileDiffs = append(jc.ProfileDiffs, jsonProfileDiff(diff))
+	}
 
 	return r.writeJSON(jc)
 }
@@ -434,7 +453,8 @@ func (r *JSONReporter) convertResult(result *Result) jsonResult {
 			BytesPerSecond: result.Throughput.BytesPerSecond,
 			ItemsPerSecond: result.Throughput.ItemsPerSecond,
 		},
-		Timestamp: time.UnixMilli(result.Timestamp),
+		Timestamp:  time.UnixMilli(result.Timestamp),
+		CPUProfile: result.CPUProfile,
 	}
 
 	if result.Memory != nil {
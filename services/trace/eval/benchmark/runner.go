@@ -14,7 +14,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
@@ -248,6 +250,53 @@ func WithInputGenerator(gen func() any) RunOption {
 	}
 }
 
+// WithIsolation runs each benchmark target in its own helper process.
+//
+// Description:
+//
+//	Isolated runs keep one component's garbage and GC pauses out of
+//	another's latency samples. The helper process must call
+//	ServeIsolated with a registry containing the benchmarked components.
+//	Custom input generators cannot be sent to the helper, so the
+//	component's property generator is used.
+//
+// Inputs:
+//   - command: The helper program and its arguments. If empty, the current
+//     executable is started with no arguments.
+//
+// Example:
+//
+//	runner.Compare(ctx, []string{"cdcl_v1", "cdcl_v2"}, benchmark.WithIsolation())
+func WithIsolation(command ...string) RunOption {
+	return func(c *Config) {
+		c.Isolated = true
+		c.HelperCommand = command
+	}
+}
+
+// WithCPUProfile captures a CPU profile of each benchmark.
+//
+// Description:
+//
+//	Profiles the measured iterations and writes <name>.cpu.pprof to dir.
+//	Compare additionally writes a differential flame graph of each
+//	candidate against the first component, the baseline, together with
+//	comparison.json, so a regression can be traced to the code that
+//	caused it. In-process comparisons run one component at a time while
+//	profiling, because Go allows only one CPU profile per process.
+//
+// Inputs:
+//   - dir: Directory for the profiles and flame graphs. Created if needed.
+//
+// Example:
+//
+//	runner.Compare(ctx, []string{"cdcl_v1", "cdcl_v2"}, benchmark.WithCPUProfile("out/bench"))
+func WithCPUProfile(dir string) RunOption {
+	return func(c *Config) {
+		c.CPUProfileDir = dir
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Runner
 // -----------------------------------------------------------------------------
@@ -382,12 +431,67 @@ func (r *Runner) Run(ctx context.Context, name string, opts ...RunOption) (*Resu
 		attribute.Int("benchmark.iterations", config.Iterations),
 		attribute.Int("benchmark.warmup", config.Warmup),
 		attribute.Int("benchmark.parallelism", config.Parallelism),
+		attribute.Bool("benchmark.isolated", config.Isolated),
 	)
 
 	// Set up timeout context
 	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
 	defer cancel()
 
+	var m *measurement
+	var err error
+	if config.Isolated {
+		m, err = r.measureIsolated(ctx, name, config)
+	} else {
+		m, err = r.measure(ctx, component, config)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "benchmark failed")
+		return nil, err
+	}
+
+	if len(m.samples) == 0 {
+		return nil, fmt.Errorf("no successful iterations: %w", ErrBenchmarkFailed)
+	}
+
+	// Build result
+	result := r.buildResult(name, m.samples, m.errorCount, config, &m.memBefore, &m.memAfter)
+	result.Isolated = config.Isolated
+
+	if len(m.cpuProfile) > 0 {
+		path, err := writeCPUProfile(config.CPUProfileDir, name, m.cpuProfile)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "writing profile failed")
+			return nil, fmt.Errorf("saving CPU profile of %s: %w", name, err)
+		}
+		result.CPUProfile = path
+	}
+
+	// Record result in span
+	span.SetAttributes(
+		attribute.Int("benchmark.result.iterations", result.Iterations),
+		attribute.Int("benchmark.result.errors", result.Errors),
+		attribute.Float64("benchmark.result.ops_per_second", result.Throughput.OpsPerSecond),
+		attribute.Int64("benchmark.result.p99_ns", int64(result.Latency.P99)),
+	)
+	span.SetStatus(codes.Ok, "benchmark completed")
+
+	return result, nil
+}
+
+// measurement is the raw output of a benchmark run, before statistics.
+type measurement struct {
+	samples    []time.Duration
+	errorCount int
+	memBefore  runtime.MemStats
+	memAfter   runtime.MemStats
+	cpuProfile []byte
+}
+
+// measure runs warmup, cooldown, and measured iterations in this process.
+func (r *Runner) measure(ctx context.Context, component eval.Evaluable, config *Config) (*measurement, error) {
 	// Get input generator
 	generator := config.InputGenerator
 	if generator == nil {
@@ -400,11 +504,12 @@ func (r *Runner) Run(ctx context.Context, name string, opts ...RunOption) (*Resu
 		}
 	}
 
+	m := &measurement{}
+
 	// Collect memory stats before
-	var memBefore runtime.MemStats
 	if config.CollectMemory {
 		runtime.GC()
-		runtime.ReadMemStats(&memBefore)
+		runtime.ReadMemStats(&m.memBefore)
 	}
 
 	// Run warmup
@@ -421,35 +526,35 @@ func (r *Runner) Run(ctx context.Context, name string, opts ...RunOption) (*Resu
 		}
 	}
 
+	// Profile only the measured iterations
+	var profiler *cpuProfiler
+	if config.CPUProfileDir != "" {
+		var err error
+		if profiler, err = startCPUProfile(); err != nil {
+			r.logger.Warn("CPU profiling unavailable",
+				slog.String("component", component.Name()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
 	// Run measurement iterations
 	samples, errorCount, err := r.runMeasurement(ctx, component, generator, config)
+	if profiler != nil {
+		m.cpuProfile = profiler.stop()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("running measurement: %w", err)
 	}
-
-	if len(samples) == 0 {
-		return nil, fmt.Errorf("no successful iterations: %w", ErrBenchmarkFailed)
-	}
+	m.samples = samples
+	m.errorCount = errorCount
 
 	// Collect memory stats after
-	var memAfter runtime.MemStats
 	if config.CollectMemory {
-		runtime.ReadMemStats(&memAfter)
+		runtime.ReadMemStats(&m.memAfter)
 	}
 
-	// Build result
-	result := r.buildResult(name, samples, errorCount, config, &memBefore, &memAfter)
-
-	// Record result in span
-	span.SetAttributes(
-		attribute.Int("benchmark.result.iterations", result.Iterations),
-		attribute.Int("benchmark.result.errors", result.Errors),
-		attribute.Float64("benchmark.result.ops_per_second", result.Throughput.OpsPerSecond),
-		attribute.Int64("benchmark.result.p99_ns", int64(result.Latency.P99)),
-	)
-	span.SetStatus(codes.Ok, "benchmark completed")
-
-	return result, nil
+	return m, nil
 }
 
 // runWarmup executes warmup iterations.
@@ -611,7 +716,9 @@ func (r *Runner) buildResult(name string, samples []time.Duration, errorCount in
 //
 //	Runs benchmarks for each component with the same configuration,
 //	then performs statistical comparison to determine if there's a
-//	significant difference. Uses Welch's t-test and Cohen's d.
+//	significant difference. Uses Welch's t-test and Cohen's d. With
+//	WithCPUProfile, the first name is the baseline and a differential
+//	flame graph is written for each of the others.
 //
 // Inputs:
 //   - ctx: Context for cancellation and timeout. Must not be nil.
@@ -648,6 +755,14 @@ func (r *Runner) Compare(ctx context.Context, names []string, opts ...RunOption)
 		return nil, errors.New("comparison requires at least 2 components")
 	}
 
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	// Go allows one CPU profile per process, so in-process runs that
+	// profile take turns.
+	sequential := config.CPUProfileDir != "" && !config.Isolated
+
 	// Start trace span
 	tracer := otel.Tracer(tracerName)
 	ctx, span := tracer.Start(ctx, "benchmark.Runner.Compare",
@@ -662,21 +777,28 @@ func (r *Runner) Compare(ctx context.Context, names []string, opts ...RunOption)
 	var wg sync.WaitGroup
 	errCh := make(chan error, len(names))
 
-	// Run benchmarks in parallel
+	run := func(componentName string) {
+		result, err := r.Run(ctx, componentName, opts...)
+		if err != nil {
+			errCh <- fmt.Errorf("benchmarking %s: %w", componentName, err)
+			return
+		}
+
+		mu.Lock()
+		results[componentName] = result
+		mu.Unlock()
+	}
+
+	// Run benchmarks in parallel unless profiling in-process
 	for _, name := range names {
+		if sequential {
+			run(name)
+			continue
+		}
 		wg.Add(1)
 		go func(componentName string) {
 			defer wg.Done()
-
-			result, err := r.Run(ctx, componentName, opts...)
-			if err != nil {
-				errCh <- fmt.Errorf("benchmarking %s: %w", componentName, err)
-				return
-			}
-
-			mu.Lock()
-			results[componentName] = result
-			mu.Unlock()
+			run(componentName)
 		}(name)
 	}
 
@@ -694,6 +816,14 @@ func (r *Runner) Compare(ctx context.Context, names []string, opts ...RunOption)
 	// Build comparison result
 	comparison := r.buildComparison(results)
 
+	if config.CPUProfileDir != "" {
+		if err := r.writeProfileArtifacts(config.CPUProfileDir, names, comparison); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "writing profile artifacts failed")
+			return nil, err
+		}
+	}
+
 	span.SetAttributes(
 		attribute.String("benchmark.winner", comparison.Winner),
 		attribute.Float64("benchmark.speedup", comparison.Speedup),
@@ -760,6 +890,35 @@ func (r *Runner) buildComparison(results map[string]*Result) *ComparisonResult {
 	return comparison
 }
 
+// writeProfileArtifacts writes the differential flame graphs of each
+// candidate against the baseline, names[0], then the comparison JSON.
+func (r *Runner) writeProfileArtifacts(dir string, names []string, comparison *ComparisonResult) error {
+	baseline := comparison.Results[names[0]]
+	for _, name := range names[1:] {
+		candidate := comparison.Results[name]
+		if baseline.CPUProfile == "" || candidate.CPUProfile == "" {
+			r.logger.Warn("skipping differential flame graph without profiles",
+				slog.String("baseline", baseline.Name),
+				slog.String("candidate", name),
+			)
+			continue
+		}
+		diff, err := writeProfileDiff(dir, baseline, candidate)
+		if err != nil {
+			return fmt.Errorf("building flame graph of %s vs %s: %w", name, baseline.Name, err)
+		}
+		comparison.ProfileDiffs = append(comparison.ProfileDiffs, diff)
+	}
+
+	path := filepath.Join(dir, "comparison.json")
+	if err := writeFile(path, func(w io.Writer) error {
+		return NewJSONReporter(w, true).ReportComparison(comparison)
+	}); err != nil {
+		return fmt.Errorf("saving comparison: %w", err)
+	}
+	return nil
+}
+
 // RunAll runs benchmarks for all registered components.
 //
 // Description:
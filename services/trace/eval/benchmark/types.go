@@ -85,6 +85,24 @@ type Config struct {
 	// InputGenerator generates input for each iteration.
 	// If nil, uses the component's default generator.
 	InputGenerator func() any
+
+	// Isolated runs each benchmark target in its own helper process, so
+	// GC pauses and heap growth from one target do not skew another's
+	// latency statistics. See ServeIsolated.
+	// Default: false
+	Isolated bool
+
+	// HelperCommand is the command started for isolated runs. The first
+	// element is the program, the rest its arguments.
+	// Default: the current executable with no arguments.
+	HelperCommand []string
+
+	// CPUProfileDir, if set, captures a CPU profile of the measured
+	// iterations and writes it to this directory as <name>.cpu.pprof.
+	// Compare also writes a differential flame graph for each candidate
+	// against the baseline, and the comparison JSON, to the directory.
+	// Default: "" (no profiling)
+	CPUProfileDir string
 }
 
 // DefaultConfig returns a configuration with default values.
@@ -153,6 +171,9 @@ func (c *Config) Validate() error {
 	if c.Parallelism <= 0 {
 		return errors.New("parallelism must be positive")
 	}
+	if c.Isolated && c.InputGenerator != nil {
+		return errors.New("input generator cannot cross into an isolated helper process")
+	}
 	return nil
 }
 
@@ -205,6 +226,13 @@ type Result struct {
 
 	// Samples holds the latency samples used for statistics.
 	Samples []time.Duration
+
+	// Isolated is true if the benchmark ran in a helper process.
+	Isolated bool
+
+	// CPUProfile is the path of the CPU profile captured during the
+	// measured iterations. Empty if profiling was not enabled.
+	CPUProfile string
 }
 
 // LatencyStats holds latency percentile statistics.
@@ -344,6 +372,34 @@ type ComparisonResult struct {
 
 	// Ranking is the components ranked from fastest to slowest.
 	Ranking []string
+
+	// ProfileDiffs holds the differential flame graphs of each candidate
+	// against the baseline. Empty if profiling was not enabled.
+	ProfileDiffs []ProfileDiff
+}
+
+// ProfileDiff locates the profiling artifacts comparing two components.
+//
+// Description:
+//
+//	The first component passed to Compare is the baseline; every other
+//	component is a candidate. The folded file uses the two-column format
+//	of difffolded.pl ("stack baseline candidate"), so it can also be fed
+//	to external flame graph tools.
+//
+// Thread Safety: Safe for concurrent read access after creation.
+type ProfileDiff struct {
+	// Baseline is the name of the baseline component.
+	Baseline string
+
+	// Candidate is the name of the candidate component.
+	Candidate string
+
+	// Folded is the path of the differential folded stacks.
+	Folded string
+
+	// FlameGraph is the path of the differential flame graph SVG.
+	FlameGraph string
 }
 
 // EffectSizeCategory categorizes effect sizes using Cohen's conventions.
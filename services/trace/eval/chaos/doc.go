@@ -30,7 +30,9 @@
 //	│          ├──► ErrorFault: Return errors                                 │
 //	│          ├──► PanicFault: Trigger panics                                │
 //	│          ├──► ResourceFault: Limit CPU/memory                           │
-//	│          └──► TimeoutFault: Force timeouts                              │
+//	│          ├──► TimeoutFault: Force timeouts                              │
+//	│          ├──► NetworkPartitionFault: Refuse/drop HTTP connections       │
+//	│          └──► DNSFault: Fail name resolution                            │
 //	│                                                                          │
 //	│   Scheduling Strategies:                                                 │
 //	│                                                                          │
@@ -63,6 +65,27 @@
 //	// Run chaos test
 //	result, err := injector.Run(ctx, target, 10*time.Minute)
 //
+// # Network Faults
+//
+// NetworkPartitionFault and DNSFault intercept HTTP clients through a
+// Transport. Clients with a nil Transport (the Ollama client, the data
+// fetcher) can be intercepted globally; clients held by test code can be
+// wrapped individually:
+//
+//	partition := chaos.NewNetworkPartitionFault(chaos.PartitionDrop, 0.5, "ollama:11434")
+//	partition.SetRecoveryProbe(chaos.HTTPProbe(nil, ollamaURL+"/api/tags"))
+//	restore := chaos.InstallDefaultTransport(partition)
+//	defer restore()
+//
+//	server.HTTPClient = chaos.WrapClient(server.HTTPClient, chaos.NewDNSFault(chaos.DNSNotFound, 1.0))
+//
+// Language servers talk to the process over stdio rather than HTTP and are
+// not affected by network faults.
+//
+// After a fault is reverted the injector waits until the target's health
+// check, the fault's RecoveryVerifier, and any WithRecoveryProbes probes
+// all pass before recording the recovery as verified.
+//
 // # Safety
 //
 // Chaos testing can cause system instability. The framework includes:
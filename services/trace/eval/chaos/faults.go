@@ -587,6 +587,19 @@ func (f *CompositeFault) Apply(ctx context.Context, originalErr error) error {
 	return err
 }
 
+// VerifyRecovery implements RecoveryVerifier by verifying every combined
+// fault that implements it.
+func (f *CompositeFault) VerifyRecovery(ctx context.Context) error {
+	for _, fault := range f.faults {
+		if verifier, ok := fault.(RecoveryVerifier); ok {
+			if err := verifier.VerifyRecovery(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // Helpers
 // -----------------------------------------------------------------------------
@@ -68,6 +68,11 @@ type InjectorConfig struct {
 	// Default: 30s
 	RecoveryTimeout time.Duration
 
+	// RecoveryProbes run after every revert, alongside the target's
+	// health check and the fault's own RecoveryVerifier. Recovery is
+	// verified once all of them pass.
+	RecoveryProbes []RecoveryProbe
+
 	// Logger for debug output.
 	Logger *slog.Logger
 }
@@ -134,6 +139,18 @@ func WithRecoveryTimeout(d time.Duration) InjectorOption {
 	}
 }
 
+// WithRecoveryProbes adds probes that must pass before recovery is
+// verified, e.g. HTTPProbe against the Ollama or data fetcher endpoints.
+func WithRecoveryProbes(probes ...RecoveryProbe) InjectorOption {
+	return func(c *InjectorConfig) {
+		for _, p := range probes {
+			if p != nil {
+				c.RecoveryProbes = append(c.RecoveryProbes, p)
+			}
+		}
+	}
+}
+
 // WithInjectorLogger sets the logger.
 func WithInjectorLogger(logger *slog.Logger) InjectorOption {
 	return func(c *InjectorConfig) {
@@ -408,7 +425,7 @@ func (i *Injector) revertFault(ctx context.Context, fault Fault, target eval.Eva
 		// Verify recovery
 		recoveryStart := time.Now()
 		recoveryCtx, cancel := context.WithTimeout(ctx, i.config.RecoveryTimeout)
-		recoveryErr := i.verifyRecovery(recoveryCtx, target)
+		recoveryErr := i.verifyRecovery(recoveryCtx, target, fault)
 		cancel()
 
 		result.RecoveryDuration = time.Since(recoveryStart)
@@ -426,22 +443,48 @@ func (i *Injector) revertFault(ctx context.Context, fault Fault, target eval.Eva
 }
 
 // verifyRecovery checks that the target has recovered.
-func (i *Injector) verifyRecovery(ctx context.Context, target eval.Evaluable) error {
+//
+// The target's health check, the fault's RecoveryVerifier (if any) and
+// every configured RecoveryProbe must all pass in the same round.
+func (i *Injector) verifyRecovery(ctx context.Context, target eval.Evaluable, fault Fault) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
+	var lastErr error
 	for {
 		select {
 		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w: %w", ErrRecoveryFailed, lastErr)
+			}
 			return ErrRecoveryFailed
 		case <-ticker.C:
-			if err := target.HealthCheck(ctx); err == nil {
+			lastErr = i.checkRecovery(ctx, target, fault)
+			if lastErr == nil {
 				return nil
 			}
 		}
 	}
 }
 
+// checkRecovery runs one round of recovery checks.
+func (i *Injector) checkRecovery(ctx context.Context, target eval.Evaluable, fault Fault) error {
+	if err := target.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+	if verifier, ok := fault.(RecoveryVerifier); ok {
+		if err := verifier.VerifyRecovery(ctx); err != nil {
+			return fmt.Errorf("fault %s: %w", fault.Name(), err)
+		}
+	}
+	for _, probe := range i.config.RecoveryProbes {
+		if err := probe(ctx); err != nil {
+			return fmt.Errorf("recovery probe: %w", err)
+		}
+	}
+	return nil
+}
+
 // revertAllFaults reverts all active faults.
 func (i *Injector) revertAllFaults(ctx context.Context, target eval.Evaluable) {
 	i.mu.Lock()
@@ -0,0 +1,626 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Network Fault Interface
+// -----------------------------------------------------------------------------
+
+// NetworkFault is a fault that can intercept outgoing HTTP requests.
+//
+// Description:
+//
+//	NetworkFaults are installed into HTTP clients with a Transport. The
+//	Transport calls Intercept before every request; a non-nil error fails
+//	the request without it reaching the network, the way a real network
+//	failure would.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type NetworkFault interface {
+	Fault
+
+	// Intercept decides whether req fails.
+	// Returns nil to let the request through.
+	Intercept(req *http.Request) error
+}
+
+// RecoveryProbe checks that a dependency is reachable again.
+// Returns nil when the dependency has recovered.
+type RecoveryProbe func(ctx context.Context) error
+
+// RecoveryVerifier is implemented by faults that can check recovery from
+// their own effect, in addition to the target's health check.
+type RecoveryVerifier interface {
+	// VerifyRecovery returns nil once the fault's effect is gone.
+	VerifyRecovery(ctx context.Context) error
+}
+
+// HTTPProbe returns a RecoveryProbe that GETs url with client.
+//
+// Description:
+//
+//	The probe succeeds when the server answers with a status below 500.
+//	Typical URLs are Ollama's "/api/tags" or the data fetcher's "/health".
+//
+// Inputs:
+//   - client: Client to probe with. If nil, uses http.DefaultClient.
+//   - url: URL to GET.
+//
+// Outputs:
+//   - RecoveryProbe: The probe. Never nil.
+func HTTPProbe(client *http.Client, url string) RecoveryProbe {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("probe %s: status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Transport
+// -----------------------------------------------------------------------------
+
+// Transport is an http.RoundTripper that applies network faults.
+//
+// Description:
+//
+//	Transport asks each fault in order whether a request fails. The first
+//	error is returned as the request's error; otherwise the request goes
+//	to the base transport unchanged.
+//
+// Thread Safety: Safe for concurrent use.
+type Transport struct {
+	base   http.RoundTripper
+	faults []NetworkFault
+}
+
+// NewTransport wraps base with faults.
+//
+// Inputs:
+//   - base: Transport to forward to. If nil, uses http.DefaultTransport.
+//   - faults: Faults to apply, in order.
+//
+// Outputs:
+//   - *Transport: The wrapping transport. Never nil.
+func NewTransport(base http.RoundTripper, faults ...NetworkFault) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, faults: faults}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, fault := range t.faults {
+		if err := fault.Intercept(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// WrapClient returns a copy of client whose requests pass through faults.
+//
+// Description:
+//
+//	Use this for clients that are reachable from test code, such as the
+//	data fetcher's Server.HTTPClient. The original client is not modified.
+//
+// Inputs:
+//   - client: Client to wrap. If nil, wraps a zero http.Client.
+//   - faults: Faults to apply.
+//
+// Outputs:
+//   - *http.Client: The wrapped copy. Never nil.
+func WrapClient(client *http.Client, faults ...NetworkFault) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = NewTransport(wrapped.Transport, faults...)
+	return wrapped
+}
+
+// InstallDefaultTransport routes http.DefaultTransport through faults.
+//
+// Description:
+//
+//	Clients built with a nil Transport, such as the Ollama client in
+//	services/llm and the data fetcher's Yahoo client, use
+//	http.DefaultTransport, so this intercepts them without code changes.
+//	Call the returned function to restore the previous transport.
+//
+// Inputs:
+//   - faults: Faults to apply.
+//
+// Outputs:
+//   - func(): Restores the previous http.DefaultTransport.
+//
+// Thread Safety: Not safe for concurrent use with HTTP requests. Install
+// and restore during test setup and teardown only.
+func InstallDefaultTransport(faults ...NetworkFault) func() {
+	previous := http.DefaultTransport
+	http.DefaultTransport = NewTransport(previous, faults...)
+	return func() {
+		http.DefaultTransport = previous
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Network Partition Fault
+// -----------------------------------------------------------------------------
+
+// PartitionMode selects how a partitioned connection fails.
+type PartitionMode int
+
+const (
+	// PartitionRefuse fails connections immediately with ECONNREFUSED,
+	// as when the remote host is up but unreachable on the port.
+	PartitionRefuse PartitionMode = iota
+
+	// PartitionDrop silently drops connections: requests hang until their
+	// context ends or the drop timeout passes, then fail with a timeout.
+	PartitionDrop
+)
+
+// String returns the mode name.
+func (m PartitionMode) String() string {
+	switch m {
+	case PartitionRefuse:
+		return "refuse"
+	case PartitionDrop:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+// NetworkPartitionFault cuts off some or all remote hosts.
+//
+// Description:
+//
+//	NetworkPartitionFault fails requests to matching hosts at a configured
+//	rate. A rate below 1 simulates a flaky link; a host list simulates a
+//	partial outage where some services stay reachable. Host patterns are
+//	"host", "host:port", or "*.domain"; no patterns matches every host.
+//
+// Thread Safety: Safe for concurrent use.
+type NetworkPartitionFault struct {
+	name        string
+	mode        PartitionMode
+	rate        float64
+	hosts       []string
+	dropTimeout time.Duration
+	probe       RecoveryProbe
+	active      atomic.Bool
+	mu          sync.RWMutex
+	seed        uint64
+	injected    atomic.Int64
+	total       atomic.Int64
+}
+
+// NewNetworkPartitionFault creates a network partition fault.
+//
+// Inputs:
+//   - mode: How partitioned connections fail.
+//   - rate: Probability a matching request fails (0.0 to 1.0).
+//   - hosts: Host patterns to partition. Empty partitions every host.
+//
+// Outputs:
+//   - *NetworkPartitionFault: The new fault. Never nil.
+func NewNetworkPartitionFault(mode PartitionMode, rate float64, hosts ...string) *NetworkPartitionFault {
+	if rate < 0 {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	return &NetworkPartitionFault{
+		name:        "network_partition",
+		mode:        mode,
+		rate:        rate,
+		hosts:       hosts,
+		dropTimeout: 30 * time.Second,
+		seed:        uint64(time.Now().UnixNano()),
+	}
+}
+
+// SetDropTimeout sets how long PartitionDrop requests hang when their
+// context has no deadline. Default: 30s.
+func (f *NetworkPartitionFault) SetDropTimeout(d time.Duration) {
+	if d > 0 {
+		f.mu.Lock()
+		f.dropTimeout = d
+		f.mu.Unlock()
+	}
+}
+
+// SetRecoveryProbe sets the probe VerifyRecovery runs after the fault is
+// reverted, typically an HTTPProbe against the partitioned service.
+func (f *NetworkPartitionFault) SetRecoveryProbe(probe RecoveryProbe) {
+	f.mu.Lock()
+	f.probe = probe
+	f.mu.Unlock()
+}
+
+// Name implements Fault.
+func (f *NetworkPartitionFault) Name() string { return f.name }
+
+// Description implements Fault.
+func (f *NetworkPartitionFault) Description() string {
+	return "Partitions " + describeHosts(f.hosts) + " (" + f.mode.String() + ") at " +
+		formatPercent(f.rate) + " rate"
+}
+
+// Inject implements Fault.
+func (f *NetworkPartitionFault) Inject(_ context.Context) error {
+	if !f.active.CompareAndSwap(false, true) {
+		return ErrFaultActive
+	}
+	return nil
+}
+
+// Revert implements Fault.
+func (f *NetworkPartitionFault) Revert(_ context.Context) error {
+	if !f.active.CompareAndSwap(true, false) {
+		return ErrFaultInactive
+	}
+	return nil
+}
+
+// IsActive implements Fault.
+func (f *NetworkPartitionFault) IsActive() bool {
+	return f.active.Load()
+}
+
+// Apply implements Fault.
+//
+// Applies the partition to a non-HTTP operation, ignoring host patterns.
+func (f *NetworkPartitionFault) Apply(ctx context.Context, originalErr error) error {
+	if !f.IsActive() {
+		return originalErr
+	}
+	if err := f.fail(ctx); err != nil {
+		return err
+	}
+	return originalErr
+}
+
+// Intercept implements NetworkFault.
+func (f *NetworkPartitionFault) Intercept(req *http.Request) error {
+	if !f.IsActive() || !matchHost(f.hosts, req.URL.Host) {
+		return nil
+	}
+	return f.fail(req.Context())
+}
+
+// VerifyRecovery implements RecoveryVerifier.
+func (f *NetworkPartitionFault) VerifyRecovery(ctx context.Context) error {
+	if f.IsActive() {
+		return ErrFaultActive
+	}
+	f.mu.RLock()
+	probe := f.probe
+	f.mu.RUnlock()
+	if probe == nil {
+		return nil
+	}
+	return probe(ctx)
+}
+
+// Stats returns injection statistics.
+func (f *NetworkPartitionFault) Stats() (injected, total int64) {
+	return f.injected.Load(), f.total.Load()
+}
+
+// fail returns the partition error for one operation, or nil if the
+// operation is let through.
+func (f *NetworkPartitionFault) fail(ctx context.Context) error {
+	f.total.Add(1)
+	if !f.shouldInject() {
+		return nil
+	}
+	f.injected.Add(1)
+
+	if f.mode == PartitionRefuse {
+		return &net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED},
+		}
+	}
+
+	f.mu.RLock()
+	timeout := f.dropTimeout
+	f.mu.RUnlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(timeout):
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	}
+}
+
+// shouldInject returns true if the operation should fail.
+func (f *NetworkPartitionFault) shouldInject() bool {
+	f.mu.Lock()
+	f.seed = f.seed*6364136223846793005 + 1442695040888963407
+	seed := f.seed
+	f.mu.Unlock()
+
+	return float64(seed%1000000)/1000000 < f.rate
+}
+
+// -----------------------------------------------------------------------------
+// DNS Fault
+// -----------------------------------------------------------------------------
+
+// DNSMode selects how a failed lookup is reported.
+type DNSMode int
+
+const (
+	// DNSNotFound reports the host as unknown (NXDOMAIN).
+	DNSNotFound DNSMode = iota
+
+	// DNSTimeout reports that the resolver did not answer.
+	DNSTimeout
+
+	// DNSServerFailure reports a temporary resolver failure (SERVFAIL).
+	DNSServerFailure
+)
+
+// String returns the mode name.
+func (m DNSMode) String() string {
+	switch m {
+	case DNSNotFound:
+		return "not_found"
+	case DNSTimeout:
+		return "timeout"
+	case DNSServerFailure:
+		return "server_failure"
+	default:
+		return "unknown"
+	}
+}
+
+// DNSFault makes name resolution fail for some or all hosts.
+//
+// Description:
+//
+//	DNSFault fails requests to matching hosts with a *net.DNSError, the
+//	error a real resolver failure produces. Requests to IP literals are
+//	never affected since they need no lookup. Host patterns work as for
+//	NetworkPartitionFault.
+//
+// Thread Safety: Safe for concurrent use.
+type DNSFault struct {
+	name     string
+	mode     DNSMode
+	rate     float64
+	hosts    []string
+	probe    RecoveryProbe
+	active   atomic.Bool
+	mu       sync.RWMutex
+	seed     uint64
+	injected atomic.Int64
+	total    atomic.Int64
+}
+
+// NewDNSFault creates a DNS failure fault.
+//
+// Inputs:
+//   - mode: Kind of resolver failure to report.
+//   - rate: Probability a matching lookup fails (0.0 to 1.0).
+//   - hosts: Host patterns whose lookups fail. Empty fails every lookup.
+//
+// Outputs:
+//   - *DNSFault: The new fault. Never nil.
+func NewDNSFault(mode DNSMode, rate float64, hosts ...string) *DNSFault {
+	if rate < 0 {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	return &DNSFault{
+		name:  "dns",
+		mode:  mode,
+		rate:  rate,
+		hosts: hosts,
+		seed:  uint64(time.Now().UnixNano()),
+	}
+}
+
+// SetRecoveryProbe sets the probe VerifyRecovery runs after the fault is
+// reverted.
+func (f *DNSFault) SetRecoveryProbe(probe RecoveryProbe) {
+	f.mu.Lock()
+	f.probe = probe
+	f.mu.Unlock()
+}
+
+// Name implements Fault.
+func (f *DNSFault) Name() string { return f.name }
+
+// Description implements Fault.
+func (f *DNSFault) Description() string {
+	return "Fails DNS lookups (" + f.mode.String() + ") for " + describeHosts(f.hosts) + " at " +
+		formatPercent(f.rate) + " rate"
+}
+
+// Inject implements Fault.
+func (f *DNSFault) Inject(_ context.Context) error {
+	if !f.active.CompareAndSwap(false, true) {
+		return ErrFaultActive
+	}
+	return nil
+}
+
+// Revert implements Fault.
+func (f *DNSFault) Revert(_ context.Context) error {
+	if !f.active.CompareAndSwap(true, false) {
+		return ErrFaultInactive
+	}
+	return nil
+}
+
+// IsActive implements Fault.
+func (f *DNSFault) IsActive() bool {
+	return f.active.Load()
+}
+
+// Apply implements Fault.
+//
+// Applies the lookup failure to a non-HTTP operation, ignoring host
+// patterns.
+func (f *DNSFault) Apply(_ context.Context, originalErr error) error {
+	if !f.IsActive() {
+		return originalErr
+	}
+	if err := f.fail(""); err != nil {
+		return err
+	}
+	return originalErr
+}
+
+// Intercept implements NetworkFault.
+func (f *DNSFault) Intercept(req *http.Request) error {
+	if !f.IsActive() || !matchHost(f.hosts, req.URL.Host) {
+		return nil
+	}
+	if net.ParseIP(req.URL.Hostname()) != nil {
+		return nil
+	}
+	return f.fail(req.URL.Hostname())
+}
+
+// VerifyRecovery implements RecoveryVerifier.
+func (f *DNSFault) VerifyRecovery(ctx context.Context) error {
+	if f.IsActive() {
+		return ErrFaultActive
+	}
+	f.mu.RLock()
+	probe := f.probe
+	f.mu.RUnlock()
+	if probe == nil {
+		return nil
+	}
+	return probe(ctx)
+}
+
+// Stats returns injection statistics.
+func (f *DNSFault) Stats() (injected, total int64) {
+	return f.injected.Load(), f.total.Load()
+}
+
+// fail returns the lookup error for host, or nil if the lookup succeeds.
+func (f *DNSFault) fail(host string) error {
+	f.total.Add(1)
+	if !f.shouldInject() {
+		return nil
+	}
+	f.injected.Add(1)
+
+	dnsErr := &net.DNSError{Name: host}
+	switch f.mode {
+	case DNSTimeout:
+		dnsErr.Err = "i/o timeout"
+		dnsErr.IsTimeout = true
+	case DNSServerFailure:
+		dnsErr.Err = "server misbehaving"
+		dnsErr.IsTemporary = true
+	default:
+		dnsErr.Err = "no such host"
+		dnsErr.IsNotFound = true
+	}
+	return &net.OpError{Op: "dial", Net: "tcp", Err: dnsErr}
+}
+
+// shouldInject returns true if the lookup should fail.
+func (f *DNSFault) shouldInject() bool {
+	f.mu.Lock()
+	f.seed = f.seed*6364136223846793005 + 1442695040888963407
+	seed := f.seed
+	f.mu.Unlock()
+
+	return float64(seed%1000000)/1000000 < f.rate
+}
+
+// -----------------------------------------------------------------------------
+// Host Matching
+// -----------------------------------------------------------------------------
+
+// matchHost reports whether hostport matches any pattern. Patterns are
+// "host", "host:port" or "*.domain"; no patterns matches everything.
+func matchHost(patterns []string, hostport string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	hostport = strings.ToLower(hostport)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		switch {
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		case pattern == host || pattern == hostport:
+			return true
+		}
+	}
+	return false
+}
+
+// describeHosts formats host patterns for Description.
+func describeHosts(hosts []string) string {
+	if len(hosts) == 0 {
+		return "all hosts"
+	}
+	return strings.Join(hosts, ", ")
+}
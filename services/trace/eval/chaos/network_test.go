@@ -0,0 +1,227 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func get(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestNetworkPartitionFault(t *testing.T) {
+	server, hits := newTestServer(t)
+
+	t.Run("refuse", func(t *testing.T) {
+		fault := NewNetworkPartitionFault(PartitionRefuse, 1.0)
+		client := WrapClient(server.Client(), fault)
+
+		if err := get(context.Background(), client, server.URL); err != nil {
+			t.Fatalf("inactive fault should pass requests: %v", err)
+		}
+
+		fault.Inject(context.Background())
+		before := hits.Load()
+		err := get(context.Background(), client, server.URL)
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			t.Errorf("expected ECONNREFUSED, got %v", err)
+		}
+		if hits.Load() != before {
+			t.Error("partitioned request reached the server")
+		}
+
+		fault.Revert(context.Background())
+		if err := get(context.Background(), client, server.URL); err != nil {
+			t.Errorf("reverted fault should pass requests: %v", err)
+		}
+	})
+
+	t.Run("drop honours context", func(t *testing.T) {
+		fault := NewNetworkPartitionFault(PartitionDrop, 1.0)
+		fault.SetDropTimeout(time.Minute)
+		fault.Inject(context.Background())
+		client := WrapClient(server.Client(), fault)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := get(ctx, client, server.URL)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		if time.Since(start) > time.Second {
+			t.Error("dropped request ignored its context")
+		}
+	})
+
+	t.Run("drop timeout", func(t *testing.T) {
+		fault := NewNetworkPartitionFault(PartitionDrop, 1.0)
+		fault.SetDropTimeout(10 * time.Millisecond)
+		fault.Inject(context.Background())
+
+		err := get(context.Background(), WrapClient(nil, fault), server.URL)
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Errorf("expected a timeout net.Error, got %v", err)
+		}
+	})
+
+	t.Run("partial outage by host", func(t *testing.T) {
+		fault := NewNetworkPartitionFault(PartitionRefuse, 1.0, "ollama.internal:11434", "*.example.com")
+		fault.Inject(context.Background())
+
+		cases := map[string]bool{
+			"ollama.internal:11434": true,
+			"ollama.internal:8080":  false,
+			"api.example.com":       true,
+			"example.org:443":       false,
+		}
+		for host, want := range cases {
+			req, _ := http.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+			if got := fault.Intercept(req) != nil; got != want {
+				t.Errorf("Intercept(%s) failed=%v, want %v", host, got, want)
+			}
+		}
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		fault := NewNetworkPartitionFault(PartitionRefuse, 0.5)
+		fault.Inject(context.Background())
+		for i := 0; i < 1000; i++ {
+			fault.Apply(context.Background(), nil)
+		}
+		injected, total := fault.Stats()
+		if total != 1000 || injected < 350 || injected > 650 {
+			t.Errorf("injected %d of %d, want about half", injected, total)
+		}
+	})
+}
+
+func TestDNSFault(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	fault := NewDNSFault(DNSNotFound, 1.0, "ollama.internal")
+	fault.Inject(context.Background())
+
+	req, _ := http.NewRequest(http.MethodGet, "http://ollama.internal:11434/api/tags", nil)
+	err := fault.Intercept(req)
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound || dnsErr.Name != "ollama.internal" {
+		t.Errorf("expected not-found DNSError for ollama.internal, got %v", err)
+	}
+
+	// IP literals need no lookup and are unaffected.
+	all := NewDNSFault(DNSTimeout, 1.0)
+	all.Inject(context.Background())
+	if err := get(context.Background(), WrapClient(server.Client(), all), server.URL); err != nil {
+		t.Errorf("request to IP literal failed: %v", err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	if !errors.As(all.Intercept(req), &dnsErr) || !dnsErr.Timeout() {
+		t.Errorf("expected timeout DNSError, got %v", dnsErr)
+	}
+}
+
+func TestInstallDefaultTransport(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	fault := NewNetworkPartitionFault(PartitionRefuse, 1.0)
+	fault.Inject(context.Background())
+	restore := InstallDefaultTransport(fault)
+
+	client := &http.Client{Timeout: time.Second}
+	if err := get(context.Background(), client, server.URL); !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("default transport not intercepted: %v", err)
+	}
+
+	restore()
+	if err := get(context.Background(), client, server.URL); err != nil {
+		t.Errorf("request failed after restore: %v", err)
+	}
+}
+
+func TestInjector_RecoveryHooks(t *testing.T) {
+	t.Run("fault recovery probe", func(t *testing.T) {
+		server, _ := newTestServer(t)
+		fault := NewNetworkPartitionFault(PartitionRefuse, 1.0)
+		fault.SetRecoveryProbe(HTTPProbe(WrapClient(server.Client(), fault), server.URL))
+
+		injector := NewInjector(
+			WithFaults(fault),
+			WithScheduler(NewPeriodicScheduler(50*time.Millisecond, 30*time.Millisecond)),
+			WithHealthCheckInterval(10*time.Millisecond),
+			WithRecoveryTimeout(time.Second),
+		)
+		result, err := injector.Run(context.Background(), newMockTarget("target"), 300*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.FaultsInjected == 0 {
+			t.Fatal("expected faults to be injected")
+		}
+		if result.RecoveriesFailure != 0 {
+			t.Errorf("expected all recoveries verified, got %d failures", result.RecoveriesFailure)
+		}
+	})
+
+	t.Run("failing injector probe", func(t *testing.T) {
+		probeErr := errors.New("ollama unreachable")
+		injector := NewInjector(
+			WithFaults(NewDNSFault(DNSNotFound, 1.0)),
+			WithScheduler(NewPeriodicScheduler(50*time.Millisecond, 30*time.Millisecond)),
+			WithHealthCheckInterval(10*time.Millisecond),
+			WithRecoveryTimeout(150*time.Millisecond),
+			WithRecoveryProbes(func(context.Context) error { return probeErr }),
+		)
+		result, err := injector.Run(context.Background(), newMockTarget("target"), 200*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.RecoveriesFailure == 0 {
+			t.Fatal("expected recovery to fail")
+		}
+		for _, fr := range result.FaultResults {
+			if fr.Error != nil && (!errors.Is(fr.Error, ErrRecoveryFailed) || !errors.Is(fr.Error, probeErr)) {
+				t.Errorf("expected ErrRecoveryFailed wrapping the probe error, got %v", fr.Error)
+			}
+		}
+	})
+}
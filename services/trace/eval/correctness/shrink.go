@@ -0,0 +1,330 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package correctness
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// -----------------------------------------------------------------------------
+// Automatic Shrinking
+// -----------------------------------------------------------------------------
+
+const (
+	// maxShrinkCandidates bounds the candidates AutoShrink returns per call.
+	maxShrinkCandidates = 1024
+
+	// maxShrinkDepth bounds how deep AutoShrink descends into nested values.
+	maxShrinkDepth = 8
+)
+
+// AutoShrink proposes smaller variants of an input by reflection.
+//
+// Description:
+//
+//	The Verifier uses AutoShrink for properties without a Shrink function.
+//	Candidates are ordered from the largest reduction to the smallest, so
+//	taking the first candidate that still fails and repeating performs
+//	delta debugging:
+//
+//	  - Slices, strings and maps first lose all their elements, then each
+//	    half, quarter and so on down to single elements.
+//	  - Elements, map values, exported struct fields and pointees are then
+//	    shrunk in place.
+//	  - Numbers move toward zero, and booleans toward false.
+//
+//	Unexported struct fields are kept as they are, so values whose
+//	invariants live in unexported state are never put into a state their
+//	constructor could not produce. The containers a candidate changes are
+//	copied; nested values it leaves alone are shared with the input.
+//
+// Inputs:
+//   - input: The failing input.
+//
+// Outputs:
+//   - []any: Candidates of the input's dynamic type, at most 1024. Nil if
+//     the input cannot be shrunk.
+//
+// Thread Safety: Safe for concurrent use.
+func AutoShrink(input any) []any {
+	if input == nil {
+		return nil
+	}
+	values := shrinkValue(reflect.ValueOf(input), maxShrinkDepth)
+	if len(values) == 0 {
+		return nil
+	}
+	candidates := make([]any, len(values))
+	for i, v := range values {
+		candidates[i] = v.Interface()
+	}
+	return candidates
+}
+
+// shrinkValue returns smaller variants of v, largest reduction first.
+func shrinkValue(v reflect.Value, depth int) []reflect.Value {
+	if depth <= 0 || !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return []reflect.Value{reflect.Zero(v.Type())}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return shrinkInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return shrinkUint(v)
+	case reflect.Float32, reflect.Float64:
+		return shrinkFloat(v)
+	case reflect.String:
+		return shrinkString(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		return shrinkSlice(v, depth)
+	case reflect.Array:
+		return shrinkElements(v, depth, nil)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		return shrinkMap(v, depth)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		var out []reflect.Value
+		for _, c := range shrinkValue(v.Elem(), depth-1) {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(c)
+			out = append(out, p)
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return shrinkValue(v.Elem(), depth)
+	case reflect.Struct:
+		return shrinkStruct(v, depth)
+	}
+	return nil
+}
+
+// shrinkInt moves an integer toward zero.
+func shrinkInt(v reflect.Value) []reflect.Value {
+	n := v.Int()
+	if n == 0 {
+		return nil
+	}
+	step := int64(1)
+	if n < 0 {
+		step = -1
+	}
+	return numbers(n, []int64{0, n / 2, n - step}, func(x int64) reflect.Value {
+		return reflect.ValueOf(x).Convert(v.Type())
+	})
+}
+
+// shrinkUint moves an unsigned integer toward zero.
+func shrinkUint(v reflect.Value) []reflect.Value {
+	n := v.Uint()
+	if n == 0 {
+		return nil
+	}
+	return numbers(n, []uint64{0, n / 2, n - 1}, func(x uint64) reflect.Value {
+		return reflect.ValueOf(x).Convert(v.Type())
+	})
+}
+
+// shrinkFloat moves a float toward zero, trying integral values first.
+func shrinkFloat(v reflect.Value) []reflect.Value {
+	f := v.Float()
+	if f == 0 || math.IsNaN(f) {
+		return nil
+	}
+	if math.IsInf(f, 0) {
+		return []reflect.Value{reflect.Zero(v.Type())}
+	}
+	step := 1.0
+	if f < 0 {
+		step = -1
+	}
+	candidates := []float64{0, math.Trunc(f), math.Trunc(f / 2)}
+	if math.Abs(f) >= 1 {
+		candidates = append(candidates, math.Trunc(f)-step)
+	}
+	return numbers(f, candidates, func(x float64) reflect.Value {
+		return reflect.ValueOf(x).Convert(v.Type())
+	})
+}
+
+// numbers converts distinct candidates other than the current value.
+func numbers[T comparable](current T, candidates []T, convert func(T) reflect.Value) []reflect.Value {
+	var out []reflect.Value
+	seen := map[T]bool{current: true}
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		out = append(out, convert(c))
+	}
+	return out
+}
+
+// shrinkString removes chunks of runes.
+func shrinkString(v reflect.Value) []reflect.Value {
+	runes := []rune(v.String())
+	var out []reflect.Value
+	for _, keep := range removals(len(runes)) {
+		var b []rune
+		for _, r := range keep {
+			b = append(b, runes[r[0]:r[1]]...)
+		}
+		out = append(out, reflect.ValueOf(string(b)).Convert(v.Type()))
+	}
+	return out
+}
+
+// shrinkSlice removes chunks of elements, then shrinks each element.
+func shrinkSlice(v reflect.Value, depth int) []reflect.Value {
+	var out []reflect.Value
+	for _, keep := range removals(v.Len()) {
+		s := reflect.MakeSlice(v.Type(), 0, v.Len())
+		for _, r := range keep {
+			s = reflect.AppendSlice(s, v.Slice(r[0], r[1]))
+		}
+		out = append(out, s)
+		if len(out) >= maxShrinkCandidates {
+			return out
+		}
+	}
+	return shrinkElements(v, depth, out)
+}
+
+// shrinkElements shrinks each element of a slice or array in place,
+// appending the variants to out.
+func shrinkElements(v reflect.Value, depth int, out []reflect.Value) []reflect.Value {
+	for i := 0; i < v.Len(); i++ {
+		for _, c := range shrinkValue(v.Index(i), depth-1) {
+			variant := copyContainer(v)
+			variant.Index(i).Set(c)
+			out = append(out, variant)
+			if len(out) >= maxShrinkCandidates {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// copyContainer returns a shallow copy of a slice or an addressable copy
+// of an array.
+func copyContainer(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Array {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return c
+	}
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+	return c
+}
+
+// shrinkMap removes keys, then shrinks each value. Keys are visited in a
+// stable order so shrinking is deterministic.
+func shrinkMap(v reflect.Value, depth int) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	var out []reflect.Value
+	for _, keep := range removals(len(keys)) {
+		m := reflect.MakeMapWithSize(v.Type(), len(keys))
+		for _, r := range keep {
+			for _, k := range keys[r[0]:r[1]] {
+				m.SetMapIndex(k, v.MapIndex(k))
+			}
+		}
+		out = append(out, m)
+		if len(out) >= maxShrinkCandidates {
+			return out
+		}
+	}
+
+	for _, k := range keys {
+		for _, c := range shrinkValue(v.MapIndex(k), depth-1) {
+			m := reflect.MakeMapWithSize(v.Type(), len(keys))
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), iter.Value())
+			}
+			m.SetMapIndex(k, c)
+			out = append(out, m)
+			if len(out) >= maxShrinkCandidates {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// shrinkStruct shrinks each exported field.
+func shrinkStruct(v reflect.Value, depth int) []reflect.Value {
+	var out []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		for _, c := range shrinkValue(v.Field(i), depth-1) {
+			variant := reflect.New(v.Type()).Elem()
+			variant.Set(v)
+			variant.Field(i).Set(c)
+			out = append(out, variant)
+			if len(out) >= maxShrinkCandidates {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// removals lists, for a sequence of length n, the index ranges kept when
+// removing everything, then each half, each quarter, and so on down to
+// each single element.
+func removals(n int) [][][2]int {
+	if n == 0 {
+		return nil
+	}
+	out := [][][2]int{nil}
+	for chunk := n / 2; chunk >= 1; chunk /= 2 {
+		for lo := 0; lo < n; lo += chunk {
+			hi := min(lo+chunk, n)
+			var keep [][2]int
+			if lo > 0 {
+				keep = append(keep, [2]int{0, lo})
+			}
+			if hi < n {
+				keep = append(keep, [2]int{hi, n})
+			}
+			out = append(out, keep)
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package correctness
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+// testDelta mimics a CRS delta: a batch of keyed updates plus metadata.
+type testDelta struct {
+	Source  string
+	Updates []testUpdate
+	Labels  map[string]int
+	Applied *bool
+	seq     int
+}
+
+type testUpdate struct {
+	Key   string
+	Value int
+}
+
+// randomDelta returns a large delta with one negative update buried in it.
+func randomDelta(rng *rand.Rand) *testDelta {
+	applied := true
+	d := &testDelta{
+		Source:  "hard:compiler",
+		Labels:  map[string]int{"a": 1, "b": 2, "c": 3},
+		Applied: &applied,
+		seq:     7,
+	}
+	for i := 0; i < 200; i++ {
+		d.Updates = append(d.Updates, testUpdate{Key: "node-" + strings.Repeat("x", rng.Intn(10)), Value: rng.Intn(1000)})
+	}
+	d.Updates[rng.Intn(len(d.Updates))].Value = -rng.Intn(1000) - 1
+	return d
+}
+
+// noNegativeUpdates fails for any delta with a negative update.
+func noNegativeUpdates(input, _ any) error {
+	for _, u := range input.(*testDelta).Updates {
+		if u.Value < 0 {
+			return errors.New("negative update")
+		}
+	}
+	return nil
+}
+
+func TestAutoShrink_Delta(t *testing.T) {
+	input := randomDelta(rand.New(rand.NewSource(1)))
+	before := len(input.Updates)
+
+	shrunk, steps, err := shrinkInput(context.Background(), noNegativeUpdates, AutoShrink, input, 1000)
+	if err == nil || steps == 0 {
+		t.Fatalf("expected a failing shrunk input, got err=%v after %d steps", err, steps)
+	}
+
+	want := &testDelta{Updates: []testUpdate{{Value: -1}}, Labels: map[string]int{}, Applied: new(bool), seq: 7}
+	if got := shrunk.(*testDelta); !reflect.DeepEqual(got, want) {
+		t.Errorf("shrunk to %+v, want %+v", got, want)
+	}
+	if len(input.Updates) != before || !*input.Applied {
+		t.Error("shrinking modified the original input")
+	}
+}
+
+func TestAutoShrink_Values(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+		fails func(any) bool
+		want  any
+	}{
+		{"int to boundary", 937, func(v any) bool { return v.(int) >= 100 }, 100},
+		{"negative int", int64(-40), func(v any) bool { return v.(int64) < 0 }, int64(-1)},
+		{"uint", uint8(200), func(v any) bool { return v.(uint8) > 3 }, uint8(4)},
+		{"float", 12.75, func(v any) bool { return v.(float64) > 1 }, 2.0},
+		{"string keeps culprit", "hello, wörld", func(v any) bool { return strings.Contains(v.(string), "ö") }, "ö"},
+		{"slice pair", []int{5, 1, 9, 3, 7, 2}, func(v any) bool {
+			s := v.([]int)
+			for i := 1; i < len(s); i++ {
+				if s[i] < s[i-1] {
+					return true
+				}
+			}
+			return false
+		}, []int{1, 0}},
+		{"map key", map[string]bool{"x": false, "y": true, "z": false}, func(v any) bool { return len(v.(map[string]bool)) > 0 }, map[string]bool{"z": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(input, _ any) error {
+				if tt.fails(input) {
+					return errors.New("fails")
+				}
+				return nil
+			}
+			got, _, err := shrinkInput(context.Background(), check, AutoShrink, tt.input, 1000)
+			if err == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shrunk to %#v (err %v), want %#v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoShrink_Unshrinkable(t *testing.T) {
+	for _, input := range []any{nil, 0, "", false, []int{}, (*testDelta)(nil), struct{ n int }{5}} {
+		if c := AutoShrink(input); c != nil {
+			t.Errorf("AutoShrink(%#v) = %v, want nil", input, c)
+		}
+	}
+}
+
+func TestShrinkInput_PanicCountsAsPass(t *testing.T) {
+	// Dereferencing the first element panics once the slice is empty.
+	check := func(input, _ any) error {
+		if input.([]int)[0] > 0 {
+			return errors.New("positive")
+		}
+		return nil
+	}
+	got, steps, err := shrinkInput(context.Background(), check, AutoShrink, []int{8, 3}, 100)
+	if err == nil || steps == 0 || !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("shrunk to %v after %d steps (err %v), want [1]", got, steps, err)
+	}
+}
+
+func TestVerifier_Verify_AutoShrink(t *testing.T) {
+	registry := eval.NewRegistry()
+	rng := rand.New(rand.NewSource(2))
+	registry.MustRegister(eval.NewSimpleEvaluable("crs_delta").
+		AddProperty(eval.Property{
+			Name:        "no_negative_updates",
+			Description: "Delta updates are never negative.",
+			Check:       noNegativeUpdates,
+			Generator:   func() any { return randomDelta(rng) },
+		}))
+
+	v := NewVerifier(registry)
+	result, err := v.Verify(context.Background(), "crs_delta", WithIterations(5), WithShrinkIterations(1000))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	failed := result.Properties[0]
+	if failed.Passed || failed.ShrinkSteps == 0 {
+		t.Fatalf("expected a shrunk failure, got %+v", failed)
+	}
+	if got := failed.FailingInput.(*testDelta); len(got.Updates) != 1 || got.Updates[0].Value != -1 {
+		t.Errorf("FailingInput not minimal: %+v", got)
+	}
+	if original := failed.OriginalInput.(*testDelta); len(original.Updates) != 200 {
+		t.Errorf("OriginalInput should be the generated input, has %d updates", len(original.Updates))
+	}
+
+	result, _ = v.Verify(context.Background(), "crs_delta", WithIterations(5), WithAutoShrink(false))
+	if failed := result.Properties[0]; failed.ShrinkSteps != 0 || failed.OriginalInput != nil {
+		t.Errorf("expected no shrinking with WithAutoShrink(false), got %d steps", failed.ShrinkSteps)
+	}
+}
@@ -52,6 +52,7 @@ type verifyConfig struct {
 	tags             []string
 	logger           *slog.Logger
 	shrinkIterations int
+	autoShrink       bool
 }
 
 func defaultConfig() *verifyConfig {
@@ -62,6 +63,7 @@ func defaultConfig() *verifyConfig {
 		parallelism:      1,
 		stopOnFailure:    false,
 		shrinkIterations: 100,
+		autoShrink:       true,
 	}
 }
 
@@ -138,6 +140,15 @@ func WithShrinkIterations(n int) VerifyOption {
 	}
 }
 
+// WithAutoShrink controls shrinking of failing inputs for properties
+// without a Shrink function, using AutoShrink.
+// Default is true.
+func WithAutoShrink(enabled bool) VerifyOption {
+	return func(c *verifyConfig) {
+		c.autoShrink = enabled
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Verifier
 // -----------------------------------------------------------------------------
@@ -193,9 +204,9 @@ func (v *Verifier) SetLogger(logger *slog.Logger) {
 // Description:
 //
 //	For each property, generates random inputs using the property's Generator,
-//	runs the Check function, and reports any failures. If a failure is found
-//	and the property has a Shrink function, attempts to find a minimal
-//	counterexample.
+//	runs the Check function, and reports any failures. If a failure is found,
+//	it is shrunk to a minimal counterexample with the property's Shrink
+//	function, or with AutoShrink if the property has none.
 //
 // Inputs:
 //   - ctx: Context for cancellation. Must not be nil.
@@ -433,11 +444,18 @@ func (v *Verifier) verifyProperty(ctx context.Context, prop eval.Property, confi
 			result.Iterations = i + 1
 
 			// Try to shrink
-			if prop.Shrink != nil && config.shrinkIterations > 0 {
-				shrunkInput, shrinkSteps := v.shrinkInput(ctx, prop, input, output, config.shrinkIterations)
-				if shrunkInput != nil {
-					result.FailingInput = shrunkInput
-					result.ShrinkSteps = shrinkSteps
+			shrink := prop.Shrink
+			if shrink == nil && config.autoShrink {
+				shrink = AutoShrink
+			}
+			if shrink != nil && config.shrinkIterations > 0 {
+				shrunk, steps, shrunkErr := shrinkInput(ctx, prop.Check, shrink, input, config.shrinkIterations)
+				if steps > 0 {
+					result.OriginalInput = input
+					result.FailingInput = shrunk
+					result.FailingOutput = shrunk
+					result.Error = shrunkErr
+					result.ShrinkSteps = steps
 				}
 			}
 
@@ -452,32 +470,32 @@ func (v *Verifier) verifyProperty(ctx context.Context, prop eval.Property, confi
 	return result
 }
 
-// shrinkInput attempts to find a minimal failing input.
-func (v *Verifier) shrinkInput(ctx context.Context, prop eval.Property, input, output any, maxIterations int) (any, int) {
-	if prop.Shrink == nil {
-		return nil, 0
-	}
-
+// shrinkInput finds a minimal failing input.
+//
+// Each round takes the first candidate that still fails and shrinks it
+// again, until no candidate fails, maxIterations rounds have run, or the
+// context ends. A candidate whose check panics counts as passing, so
+// shrinking does not wander from the original failure into a crash
+// caused by the shrinking itself.
+//
+// Outputs:
+//   - any: The smallest failing input found.
+//   - int: The number of successful shrink steps.
+//   - error: The check error of that input. Nil if no candidate failed.
+func shrinkInput(ctx context.Context, check func(input, output any) error, shrink func(any) []any, input any, maxIterations int) (any, int, error) {
 	current := input
+	var currentErr error
 	steps := 0
 
 	for i := 0; i < maxIterations; i++ {
-		select {
-		case <-ctx.Done():
-			return current, steps
-		default:
-		}
-
-		candidates := prop.Shrink(current)
-		if len(candidates) == 0 {
-			break
-		}
-
 		foundSmaller := false
-		for _, candidate := range candidates {
-			// Check if this smaller input still fails
-			if err := prop.Check(candidate, candidate); err != nil {
-				current = candidate
+		for _, candidate := range shrink(current) {
+			if ctx.Err() != nil {
+				return current, steps, currentErr
+			}
+			// Output is the input itself, as in verifyProperty.
+			if err := checkRecovered(check, candidate); err != nil {
+				current, currentErr = candidate, err
 				steps++
 				foundSmaller = true
 				break
@@ -489,7 +507,17 @@ func (v *Verifier) shrinkInput(ctx context.Context, prop eval.Property, input, o
 		}
 	}
 
-	return current, steps
+	return current, steps, currentErr
+}
+
+// checkRecovered runs a check, treating a panic as passing.
+func checkRecovered(check func(input, output any) error, input any) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = nil
+		}
+	}()
+	return check(input, input)
 }
 
 // verifyPropertiesParallel verifies properties in parallel.
@@ -96,6 +96,19 @@ The Verifier runs properties against generated inputs:
 	    log.Fatalf("Property %s failed: %v", result.FailedProperty, result.FailingInput)
 	}
 
+# Health and Dependencies
+
+Components declare what they depend on by implementing Dependent or with
+Registry.SetDependencies. HealthCheckAll checks each component on its own;
+HealthReport orders the results so dependencies come first and marks the
+dependents of a failing component degraded rather than unhealthy, so the
+report points at the root cause:
+
+	registry.SetDependencies("query_engine", "proof_index")
+	report, err := registry.HealthReport(ctx, 5)
+	// proof_index down: proof_index unhealthy, query_engine degraded,
+	// report.RootCauses == []string{"proof_index"}
+
 # Thread Safety
 
 All types in this package are safe for concurrent use unless otherwise documented.
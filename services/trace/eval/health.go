@@ -0,0 +1,330 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package eval
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Dependencies
+// -----------------------------------------------------------------------------
+
+// SetDependencies declares the components a registered component depends on.
+//
+// Description:
+//
+//	Declared dependencies are added to those the component reports through
+//	Dependent. Use this for components that do not implement Dependent.
+//	Dependencies may name components that are not registered yet.
+//
+// Inputs:
+//   - name: The dependent component. Must be registered.
+//   - dependsOn: Names of the components it depends on.
+//
+// Outputs:
+//   - error: ErrNotFound if name is not registered, ErrDependencyCycle if
+//     the declaration would create a cycle.
+//
+// Thread Safety: Safe for concurrent use.
+//
+// Example:
+//
+//	registry.SetDependencies("query_engine", "proof_index")
+func (r *Registry) SetDependencies(name string, dependsOn ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.components[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	previous, had := r.deps[name]
+	r.deps[name] = slices.Clone(dependsOn)
+	if _, err := r.dependencyOrderLocked(); err != nil {
+		if had {
+			r.deps[name] = previous
+		} else {
+			delete(r.deps, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Dependencies returns the direct dependencies of a component.
+//
+// Outputs:
+//   - []string: Sorted dependency names, declared and reported through
+//     Dependent. Nil if the component is not registered.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *Registry) Dependencies(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.components[name]; !exists {
+		return nil
+	}
+	return r.dependenciesLocked(name)
+}
+
+// dependenciesLocked returns the sorted, de-duplicated direct
+// dependencies of a registered component. Self-references are dropped.
+func (r *Registry) dependenciesLocked(name string) []string {
+	deps := slices.Clone(r.deps[name])
+	if d, ok := r.components[name].(Dependent); ok {
+		deps = append(deps, d.Dependencies()...)
+	}
+	sort.Strings(deps)
+	deps = slices.Compact(deps)
+	return slices.DeleteFunc(deps, func(d string) bool { return d == name })
+}
+
+// DependencyOrder returns the registered components sorted so that every
+// component comes after its dependencies.
+//
+// Outputs:
+//   - []string: Component names. Ties are broken alphabetically.
+//   - error: ErrDependencyCycle naming the components on or behind a cycle.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *Registry) DependencyOrder() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.dependencyOrderLocked()
+}
+
+// dependencyOrderLocked sorts the components topologically with Kahn's
+// algorithm. Dependencies that are not registered are ignored.
+func (r *Registry) dependencyOrderLocked() ([]string, error) {
+	inDegree := make(map[string]int, len(r.components))
+	dependents := make(map[string][]string, len(r.components))
+	for name := range r.components {
+		degree := 0
+		for _, dep := range r.dependenciesLocked(name) {
+			if _, registered := r.components[dep]; !registered {
+				continue
+			}
+			degree++
+			dependents[dep] = append(dependents[dep], name)
+		}
+		inDegree[name] = degree
+	}
+
+	var ready []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, name)
+		}
+	}
+	sort.Strings(ready)
+
+	order := make([]string, 0, len(r.components))
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+
+		var next []string
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				next = append(next, dependent)
+			}
+		}
+		if len(next) > 0 {
+			ready = append(ready, next...)
+			sort.Strings(ready)
+		}
+	}
+
+	if len(order) < len(r.components) {
+		var cycle []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("%w among %s", ErrDependencyCycle, strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
+
+// -----------------------------------------------------------------------------
+// Health Report
+// -----------------------------------------------------------------------------
+
+// HealthReport is the dependency-aware health of all registered components.
+type HealthReport struct {
+	// Status is the overall status: the worst status of any component.
+	Status HealthStatus
+
+	// Results holds one result per component, dependencies before
+	// dependents.
+	Results []HealthResult
+
+	// RootCauses names the unhealthy components, and missing dependencies,
+	// that are not explained by a failing dependency.
+	RootCauses []string
+}
+
+// Result returns the result for a component.
+//
+// Outputs:
+//   - HealthResult: The result, or the zero value if not found.
+//   - bool: true if found.
+func (h *HealthReport) Result(name string) (HealthResult, bool) {
+	for _, result := range h.Results {
+		if result.Component == name {
+			return result, true
+		}
+	}
+	return HealthResult{}, false
+}
+
+// HealthReport runs health checks and cascades failures to dependents.
+//
+// Description:
+//
+//	Every component is checked as by HealthCheckAll. Then, in dependency
+//	order, a component with an unhealthy or degraded dependency is marked
+//	HealthDegraded rather than HealthUnhealthy, and DegradedBy names the
+//	root causes, so one failing component (e.g. the proof index) is not
+//	reported as a dozen failures. A dependency that is not registered is
+//	treated as a failing root cause. The component's own check error, if
+//	any, is kept in Details["check_error"].
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - concurrency: Maximum number of concurrent health checks. If <= 0, defaults to 10.
+//
+// Outputs:
+//   - *HealthReport: The report. Nil on error.
+//   - error: ErrDependencyCycle if dependencies form a cycle.
+//
+// Thread Safety: Safe for concurrent use.
+//
+// Example:
+//
+//	report, err := registry.HealthReport(ctx, 5)
+//	for _, result := range report.Results {
+//	    fmt.Printf("%-20s %s %v\n", result.Component, result.Status, result.DegradedBy)
+//	}
+func (r *Registry) HealthReport(ctx context.Context, concurrency int) (*HealthReport, error) {
+	r.mu.RLock()
+	order, err := r.dependencyOrderLocked()
+	deps := make(map[string][]string, len(order))
+	for _, name := range order {
+		deps[name] = r.dependenciesLocked(name)
+	}
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make(map[string]HealthResult, len(order))
+	for _, result := range r.HealthCheckAll(ctx, concurrency) {
+		checked[result.Component] = result
+	}
+
+	report := &HealthReport{Status: HealthHealthy, Results: make([]HealthResult, 0, len(order))}
+	causes := make(map[string][]string, len(order))
+	rootCauses := make(map[string]bool)
+
+	for _, name := range order {
+		result, ok := checked[name]
+		if !ok {
+			// Registered after the order was computed.
+			continue
+		}
+		result.DependsOn = deps[name]
+
+		var degradedBy []string
+		var missing []string
+		for _, dep := range deps[name] {
+			depResult, registered := checked[dep]
+			switch {
+			case !registered:
+				missing = append(missing, dep)
+				degradedBy = append(degradedBy, dep)
+				rootCauses[dep] = true
+			case len(causes[dep]) > 0:
+				degradedBy = append(degradedBy, causes[dep]...)
+			case depResult.Status == HealthUnhealthy:
+				degradedBy = append(degradedBy, dep)
+			}
+		}
+
+		if len(degradedBy) > 0 {
+			sort.Strings(degradedBy)
+			degradedBy = slices.Compact(degradedBy)
+			causes[name] = degradedBy
+			result = cascade(result, degradedBy, missing)
+		} else if result.Status == HealthUnhealthy {
+			rootCauses[name] = true
+		}
+
+		checked[name] = result
+		report.Results = append(report.Results, result)
+		if severity(result.Status) > severity(report.Status) {
+			report.Status = result.Status
+		}
+	}
+
+	for name := range rootCauses {
+		report.RootCauses = append(report.RootCauses, name)
+	}
+	sort.Strings(report.RootCauses)
+	return report, nil
+}
+
+// cascade marks a result degraded by failing dependencies.
+func cascade(result HealthResult, degradedBy, missing []string) HealthResult {
+	details := make(map[string]any, len(result.Details)+1)
+	for k, v := range result.Details {
+		details[k] = v
+	}
+	if result.Status == HealthUnhealthy {
+		details["check_error"] = result.Message
+	}
+	if len(missing) > 0 {
+		details["missing_dependencies"] = missing
+	}
+
+	result.Details = details
+	result.Status = HealthDegraded
+	result.DegradedBy = degradedBy
+	result.Message = "degraded by failing dependency: " + strings.Join(degradedBy, ", ")
+	return result
+}
+
+// severity ranks statuses for the overall report status. Unknown ranks
+// between healthy and degraded.
+func severity(s HealthStatus) int {
+	switch s {
+	case HealthHealthy:
+		return 0
+	case HealthUnknown:
+		return 1
+	case HealthDegraded:
+		return 2
+	case HealthUnhealthy:
+		return 3
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package eval
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func failing(name string) *SimpleEvaluable {
+	return NewSimpleEvaluable(name).SetHealthCheck(func(context.Context) error {
+		return errors.New(name + " down")
+	})
+}
+
+func TestRegistry_DependencyOrder(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NewSimpleEvaluable("query").DependsOn("proof_index", "graph"))
+	r.MustRegister(NewSimpleEvaluable("proof_index").DependsOn("graph"))
+	r.MustRegister(NewSimpleEvaluable("graph"))
+	r.MustRegister(NewSimpleEvaluable("agent"))
+
+	order, err := r.DependencyOrder()
+	if err != nil {
+		t.Fatalf("DependencyOrder failed: %v", err)
+	}
+	want := []string{"agent", "graph", "proof_index", "query"}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	if err := r.SetDependencies("graph", "query"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("expected ErrDependencyCycle, got %v", err)
+	}
+	if deps := r.Dependencies("graph"); len(deps) != 0 {
+		t.Errorf("rejected declaration was kept: %v", deps)
+	}
+	if err := r.SetDependencies("missing", "graph"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := r.SetDependencies("agent", "query", "query"); err != nil {
+		t.Fatalf("SetDependencies failed: %v", err)
+	}
+	if deps := r.Dependencies("agent"); !slices.Equal(deps, []string{"query"}) {
+		t.Errorf("Dependencies = %v", deps)
+	}
+	order, _ = r.DependencyOrder()
+	if order[len(order)-1] != "agent" {
+		t.Errorf("agent should now come last: %v", order)
+	}
+}
+
+func TestRegistry_HealthReport(t *testing.T) {
+	t.Run("failing dependency degrades dependents", func(t *testing.T) {
+		r := NewRegistry()
+		r.MustRegister(failing("proof_index"))
+		r.MustRegister(failing("query").DependsOn("proof_index"))
+		r.MustRegister(NewSimpleEvaluable("planner").DependsOn("query"))
+		r.MustRegister(NewSimpleEvaluable("graph"))
+
+		report, err := r.HealthReport(context.Background(), 2)
+		if err != nil {
+			t.Fatalf("HealthReport failed: %v", err)
+		}
+
+		if report.Status != HealthUnhealthy {
+			t.Errorf("overall status = %s, want unhealthy", report.Status)
+		}
+		if !slices.Equal(report.RootCauses, []string{"proof_index"}) {
+			t.Errorf("root causes = %v", report.RootCauses)
+		}
+
+		var names []string
+		for _, result := range report.Results {
+			names = append(names, result.Component)
+		}
+		if !slices.Equal(names, []string{"graph", "proof_index", "query", "planner"}) {
+			t.Errorf("results not in dependency order: %v", names)
+		}
+
+		index, _ := report.Result("proof_index")
+		query, _ := report.Result("query")
+		planner, _ := report.Result("planner")
+		graph, _ := report.Result("graph")
+		if index.Status != HealthUnhealthy || graph.Status != HealthHealthy {
+			t.Errorf("proof_index = %s, graph = %s", index.Status, graph.Status)
+		}
+		for _, dependent := range []HealthResult{query, planner} {
+			if dependent.Status != HealthDegraded || !slices.Equal(dependent.DegradedBy, []string{"proof_index"}) {
+				t.Errorf("%s = %s degraded by %v", dependent.Component, dependent.Status, dependent.DegradedBy)
+			}
+		}
+		if query.Details["check_error"] != "query down" {
+			t.Errorf("own check error not kept: %v", query.Details)
+		}
+		if !slices.Equal(query.DependsOn, []string{"proof_index"}) {
+			t.Errorf("DependsOn = %v", query.DependsOn)
+		}
+	})
+
+	t.Run("independent failure is a root cause", func(t *testing.T) {
+		r := NewRegistry()
+		r.MustRegister(NewSimpleEvaluable("proof_index"))
+		r.MustRegister(failing("query").DependsOn("proof_index"))
+
+		report, _ := r.HealthReport(context.Background(), 0)
+		query, _ := report.Result("query")
+		if query.Status != HealthUnhealthy || len(query.DegradedBy) != 0 {
+			t.Errorf("query = %s degraded by %v", query.Status, query.DegradedBy)
+		}
+		if !slices.Equal(report.RootCauses, []string{"query"}) {
+			t.Errorf("root causes = %v", report.RootCauses)
+		}
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		r := NewRegistry()
+		r.MustRegister(NewSimpleEvaluable("query").DependsOn("proof_index"))
+
+		report, _ := r.HealthReport(context.Background(), 0)
+		query, _ := report.Result("query")
+		if query.Status != HealthDegraded || report.Status != HealthDegraded {
+			t.Errorf("query = %s, overall = %s", query.Status, report.Status)
+		}
+		if !slices.Equal(report.RootCauses, []string{"proof_index"}) {
+			t.Errorf("root causes = %v", report.RootCauses)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		r := NewRegistry()
+		r.MustRegister(NewSimpleEvaluable("a").DependsOn("b"))
+		r.MustRegister(NewSimpleEvaluable("b").DependsOn("a"))
+
+		if _, err := r.HealthReport(context.Background(), 0); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("expected ErrDependencyCycle, got %v", err)
+		}
+	})
+}
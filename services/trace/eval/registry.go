@@ -30,6 +30,7 @@ import (
 type Registry struct {
 	mu         sync.RWMutex
 	components map[string]Evaluable
+	deps       map[string][]string
 	hooks      []RegistrationHook
 }
 
@@ -48,6 +49,7 @@ type RegistrationHook func(name string, component Evaluable, registered bool)
 func NewRegistry() *Registry {
 	return &Registry{
 		components: make(map[string]Evaluable),
+		deps:       make(map[string][]string),
 		hooks:      make([]RegistrationHook, 0),
 	}
 }
@@ -145,6 +147,7 @@ func (r *Registry) Unregister(name string) error {
 	}
 
 	delete(r.components, name)
+	delete(r.deps, name)
 
 	// Notify hooks
 	for _, hook := range r.hooks {
@@ -257,6 +260,7 @@ func (r *Registry) Clear() {
 	}
 
 	r.components = make(map[string]Evaluable)
+	r.deps = make(map[string][]string)
 }
 
 // AddHook adds a registration hook.
@@ -54,6 +54,28 @@ type Baseline interface {
 	Delete(ctx context.Context, component string) error
 }
 
+// History is a Baseline that keeps the runs stored before the latest one.
+//
+// Description:
+//
+//	Each Set records a run; Get returns the most recent. Older runs are
+//	kept up to a retention limit so that slow drift can be found with
+//	AnalyzeTrend.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type History interface {
+	Baseline
+
+	// Runs returns up to limit of the most recent runs for a component,
+	// oldest first. limit <= 0 returns every retained run.
+	// Returns ErrBaselineNotFound if the component has no runs.
+	Runs(ctx context.Context, component string, limit int) ([]*BaselineData, error)
+}
+
+// DefaultHistoryRetention is the number of runs kept per component by
+// stores that implement History.
+const DefaultHistoryRetention = 100
+
 // BaselineData holds the performance metrics for a baseline.
 type BaselineData struct {
 	// Component is the name of the component.
@@ -144,12 +166,13 @@ type ErrorBaseline struct {
 // Description:
 //
 //	MemoryBaselineStore is useful for testing and short-lived processes.
-//	Data is lost when the process exits.
+//	Data is lost when the process exits. It implements History, keeping
+//	the last DefaultHistoryRetention runs per component.
 //
 // Thread Safety: Safe for concurrent use.
 type MemoryBaselineStore struct {
 	mu   sync.RWMutex
-	data map[string]*BaselineData
+	data map[string][]*BaselineData
 }
 
 // NewMemoryBaseline creates a new memory-backed baseline store.
@@ -158,7 +181,7 @@ type MemoryBaselineStore struct {
 //   - *MemoryBaselineStore: The new store. Never nil.
 func NewMemoryBaseline() *MemoryBaselineStore {
 	return &MemoryBaselineStore{
-		data: make(map[string]*BaselineData),
+		data: make(map[string][]*BaselineData),
 	}
 }
 
@@ -167,13 +190,13 @@ func (m *MemoryBaselineStore) Get(_ context.Context, component string) (*Baselin
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	data, ok := m.data[component]
+	runs, ok := m.data[component]
 	if !ok {
 		return nil, ErrBaselineNotFound
 	}
 
 	// Return a copy to prevent mutation
-	dataCopy := *data
+	dataCopy := *runs[len(runs)-1]
 	return &dataCopy, nil
 }
 
@@ -192,10 +215,35 @@ func (m *MemoryBaselineStore) Set(_ context.Context, component string, data *Bas
 	if dataCopy.CreatedAt.IsZero() {
 		dataCopy.CreatedAt = dataCopy.UpdatedAt
 	}
-	m.data[component] = &dataCopy
+	runs := append(m.data[component], &dataCopy)
+	if len(runs) > DefaultHistoryRetention {
+		runs = append([]*BaselineData(nil), runs[len(runs)-DefaultHistoryRetention:]...)
+	}
+	m.data[component] = runs
 	return nil
 }
 
+// Runs implements History.
+func (m *MemoryBaselineStore) Runs(_ context.Context, component string, limit int) ([]*BaselineData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runs, ok := m.data[component]
+	if !ok {
+		return nil, ErrBaselineNotFound
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	out := make([]*BaselineData, len(runs))
+	for i, run := range runs {
+		runCopy := *run
+		out[i] = &runCopy
+	}
+	return out, nil
+}
+
 // List implements Baseline.
 func (m *MemoryBaselineStore) List(_ context.Context) ([]string, error) {
 	m.mu.RLock()
@@ -68,6 +68,11 @@ func (r RegressionType) String() string {
 	}
 }
 
+// MarshalText encodes the type by name.
+func (r RegressionType) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
 // Severity indicates how severe a regression is.
 type Severity int
 
@@ -101,6 +106,11 @@ func (s Severity) String() string {
 	}
 }
 
+// MarshalText encodes the severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // -----------------------------------------------------------------------------
 // Detection Result
 // -----------------------------------------------------------------------------
@@ -236,19 +246,19 @@ func NewDetector(config *DetectorConfig) *Detector {
 // CurrentMetrics holds current performance measurements.
 type CurrentMetrics struct {
 	// Latency holds current latency metrics.
-	Latency LatencyBaseline
+	Latency LatencyBaseline `json:"latency"`
 
 	// Throughput holds current throughput metrics.
-	Throughput ThroughputBaseline
+	Throughput ThroughputBaseline `json:"throughput"`
 
 	// Memory holds current memory metrics.
-	Memory MemoryBaseline
+	Memory MemoryBaseline `json:"memory"`
 
 	// ErrorRate is the current error rate.
-	ErrorRate float64
+	ErrorRate float64 `json:"error_rate"`
 
 	// SampleCount is the number of samples.
-	SampleCount int
+	SampleCount int `json:"sample_count"`
 }
 
 // Detect compares current metrics against a baseline.
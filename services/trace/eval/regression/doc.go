@@ -60,6 +60,25 @@
 //	    log.Fatalf("Regression detected: %s", decision.Report)
 //	}
 //
+// # Trend Detection
+//
+// FileBaselineStore keeps only the latest baseline, so a metric that gets 1%
+// worse on every build passes every check. SQLiteBaselineStore (and
+// MemoryBaselineStore) implement History and keep a rolling window of runs;
+// AnalyzeTrend fits a line through the recent runs and flags metrics whose
+// drift across the window exceeds the single-run threshold:
+//
+//	store, err := regression.OpenSQLiteBaseline("./baselines.db", 0)
+//	gate := regression.NewGate(store,
+//	    regression.WithUpdateBaseline(true),
+//	    regression.WithTrendDetection(nil), // drift reported as warnings
+//	)
+//
+// SQLiteBaselineStore needs a database/sql driver registered as "sqlite",
+// such as modernc.org/sqlite. The CLI runs the gate over a benchmark
+// results file with "aleutian regression check", which records every
+// passing run, and reports trends with "aleutian regression trend".
+//
 // # CI/CD Integration
 //
 // The gate is designed for CI/CD pipelines:
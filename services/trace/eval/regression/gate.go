@@ -59,6 +59,11 @@ type GateConfig struct {
 	// Default: false
 	FailOnWarnings bool
 
+	// Trend enables trend detection when the baseline store implements
+	// History. Drifting metrics are reported as warnings.
+	// Default: nil (disabled)
+	Trend *TrendConfig
+
 	// Logger for output.
 	Logger *slog.Logger
 }
@@ -142,6 +147,21 @@ func WithFailOnWarnings(fail bool) GateOption {
 	}
 }
 
+// WithTrendDetection enables trend detection across stored runs.
+//
+// Inputs:
+//   - config: Trend configuration. Nil uses DefaultTrendConfig with the
+//     gate's detector thresholds.
+func WithTrendDetection(config *TrendConfig) GateOption {
+	return func(c *GateConfig) {
+		if config == nil {
+			config = DefaultTrendConfig()
+			config.Thresholds = nil
+		}
+		c.Trend = config
+	}
+}
+
 // WithGateLogger sets the logger.
 func WithGateLogger(logger *slog.Logger) GateOption {
 	return func(c *GateConfig) {
@@ -209,6 +229,10 @@ type GateDecision struct {
 	// BaselineUpdated is true if baseline was updated.
 	BaselineUpdated bool
 
+	// Trend is the trend analysis including the current run. Nil unless
+	// trend detection is enabled and the baseline store keeps history.
+	Trend *TrendReport
+
 	// Report is a human-readable summary.
 	Report string
 
@@ -295,10 +319,15 @@ func (g *Gate) Check(ctx context.Context, component string, current *CurrentMetr
 	decision.Regressions = result.Regressions
 	decision.Warnings = result.Warnings
 
+	if trend := g.analyzeTrend(ctx, component, current); trend != nil {
+		decision.Trend = trend
+		decision.Warnings = append(decision.Warnings, trend.Regressions...)
+	}
+
 	// Determine pass/fail
-	if len(result.Regressions) > g.config.AllowedRegressions {
+	if len(decision.Regressions) > g.config.AllowedRegressions {
 		decision.Pass = false
-	} else if g.config.FailOnWarnings && len(result.Warnings) > 0 {
+	} else if g.config.FailOnWarnings && len(decision.Warnings) > 0 {
 		decision.Pass = false
 	} else {
 		decision.Pass = true
@@ -372,6 +401,34 @@ func (g *Gate) CheckAll(ctx context.Context, components map[string]*CurrentMetri
 	return decisions, nil
 }
 
+// analyzeTrend runs trend detection over the stored runs and the current
+// one. Returns nil if trend detection is disabled, the store keeps no
+// history, or the history cannot be read.
+func (g *Gate) analyzeTrend(ctx context.Context, component string, current *CurrentMetrics) *TrendReport {
+	if g.config.Trend == nil {
+		return nil
+	}
+	history, ok := g.baseline.(History)
+	if !ok {
+		return nil
+	}
+
+	config := *g.config.Trend
+	if config.Thresholds == nil {
+		config.Thresholds = g.config.DetectorConfig
+	}
+	// AnalyzeTrend trims to the window, so reading extra runs is harmless.
+	runs, err := history.Runs(ctx, component, max(config.Window-1, 0))
+	if err != nil {
+		g.logger.Warn("failed to read baseline history",
+			slog.String("component", component),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	return AnalyzeTrend(component, append(runs, g.createBaseline(component, current)), &config)
+}
+
 // createBaseline creates baseline data from current metrics.
 func (g *Gate) createBaseline(component string, current *CurrentMetrics) *BaselineData {
 	return &BaselineData{
@@ -0,0 +1,254 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package regression
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// SQLite Baseline
+// -----------------------------------------------------------------------------
+
+// SQLiteDriverName is the database/sql driver OpenSQLiteBaseline uses.
+//
+// The store does not link a driver itself; programs import one that
+// registers this name, such as modernc.org/sqlite.
+const SQLiteDriverName = "sqlite"
+
+// sqliteSchema creates the run table. Headline metrics are stored as
+// columns for ad-hoc queries; the data column holds the full BaselineData.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS baseline_runs (
+	id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+	component          TEXT    NOT NULL,
+	version            TEXT    NOT NULL,
+	recorded_at        INTEGER NOT NULL,
+	p50_ns             INTEGER NOT NULL,
+	p99_ns             INTEGER NOT NULL,
+	ops_per_second     REAL    NOT NULL,
+	alloc_bytes_per_op INTEGER NOT NULL,
+	error_rate         REAL    NOT NULL,
+	data               TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS baseline_runs_component ON baseline_runs (component, id);
+`
+
+// SQLiteBaselineStore stores baseline runs in a SQLite database.
+//
+// Description:
+//
+//	Unlike FileBaselineStore, which keeps only the latest baseline, every
+//	Set appends a run. Get returns the latest run and Runs returns recent
+//	runs for trend detection. The oldest runs of a component are deleted
+//	once it has more than the retention limit.
+//
+// Thread Safety: Safe for concurrent use.
+type SQLiteBaselineStore struct {
+	db     *sql.DB
+	retain int
+	owned  bool
+}
+
+// OpenSQLiteBaseline opens or creates a SQLite baseline store.
+//
+// Inputs:
+//   - path: Database file. Its directory is created if missing.
+//   - retain: Runs kept per component. <= 0 uses DefaultHistoryRetention.
+//
+// Outputs:
+//   - *SQLiteBaselineStore: The store. Close it when done.
+//   - error: Non-nil if the database cannot be opened, for example
+//     because no driver is registered as SQLiteDriverName.
+func OpenSQLiteBaseline(path string, retain int) (*SQLiteBaselineStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(SQLiteDriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("opening baseline database: %w", err)
+	}
+	// SQLite allows one writer; a single connection avoids SQLITE_BUSY
+	// between the store's own goroutines.
+	db.SetMaxOpenConns(1)
+
+	store, err := NewSQLiteBaseline(db, retain)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	store.owned = true
+	return store, nil
+}
+
+// NewSQLiteBaseline creates a baseline store on an open database,
+// creating its table if needed.
+//
+// Inputs:
+//   - db: Open SQLite database. Must not be nil. Not closed by Close.
+//   - retain: Runs kept per component. <= 0 uses DefaultHistoryRetention.
+//
+// Outputs:
+//   - *SQLiteBaselineStore: The store.
+//   - error: Non-nil if the schema cannot be created.
+func NewSQLiteBaseline(db *sql.DB, retain int) (*SQLiteBaselineStore, error) {
+	if db == nil {
+		return nil, errors.New("database must not be nil")
+	}
+	if retain <= 0 {
+		retain = DefaultHistoryRetention
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("creating baseline schema: %w", err)
+	}
+	return &SQLiteBaselineStore{db: db, retain: retain}, nil
+}
+
+// Close closes the database if the store opened it.
+func (s *SQLiteBaselineStore) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Get implements Baseline.
+func (s *SQLiteBaselineStore) Get(ctx context.Context, component string) (*BaselineData, error) {
+	runs, err := s.Runs(ctx, component, 1)
+	if err != nil {
+		return nil, err
+	}
+	return runs[0], nil
+}
+
+// Set implements Baseline. It records a new run and prunes runs beyond
+// the retention limit.
+func (s *SQLiteBaselineStore) Set(ctx context.Context, component string, data *BaselineData) error {
+	if data == nil {
+		return errors.New("baseline data must not be nil")
+	}
+
+	data.UpdatedAt = time.Now()
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = data.UpdatedAt
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO baseline_runs (component, version, recorded_at, p50_ns, p99_ns,
+			ops_per_second, alloc_bytes_per_op, error_rate, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		component, data.Version, data.UpdatedAt.UnixNano(),
+		int64(data.Latency.P50), int64(data.Latency.P99),
+		data.Throughput.OpsPerSecond, int64(data.Memory.AllocBytesPerOp),
+		data.Error.Rate, string(jsonData))
+	if err != nil {
+		return fmt.Errorf("recording baseline run: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM baseline_runs
+		WHERE component = ? AND id NOT IN (
+			SELECT id FROM baseline_runs WHERE component = ? ORDER BY id DESC LIMIT ?
+		)`,
+		component, component, s.retain)
+	if err != nil {
+		return fmt.Errorf("pruning baseline runs: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// List implements Baseline.
+func (s *SQLiteBaselineStore) List(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT component FROM baseline_runs ORDER BY component`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Delete implements Baseline. It removes every run of the component.
+func (s *SQLiteBaselineStore) Delete(ctx context.Context, component string) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM baseline_runs WHERE component = ?`, component)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrBaselineNotFound
+	}
+	return nil
+}
+
+// Runs implements History.
+func (s *SQLiteBaselineStore) Runs(ctx context.Context, component string, limit int) ([]*BaselineData, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT data FROM baseline_runs WHERE component = ? ORDER BY id DESC LIMIT ?`,
+		component, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*BaselineData
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var run BaselineData
+		if err := json.Unmarshal([]byte(raw), &run); err != nil {
+			return nil, ErrInvalidBaseline
+		}
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrBaselineNotFound
+	}
+
+	slices.Reverse(runs)
+	return runs, nil
+}
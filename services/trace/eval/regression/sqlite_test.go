@@ -0,0 +1,106 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package regression
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLiteBaseline(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "baselines.db")
+
+	store, err := OpenSQLiteBaseline(path, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := store.Get(ctx, "search"); err != ErrBaselineNotFound {
+		t.Fatalf("expected ErrBaselineNotFound, got %v", err)
+	}
+	for i := 1; i <= 8; i++ {
+		err := store.Set(ctx, "search", &BaselineData{
+			Component:   "search",
+			Latency:     LatencyBaseline{P50: time.Duration(i) * time.Millisecond},
+			SampleCount: i,
+		})
+		if err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	store.Set(ctx, "index", &BaselineData{Component: "index"})
+
+	latest, err := store.Get(ctx, "search")
+	if err != nil || latest.SampleCount != 8 || latest.Latency.P50 != 8*time.Millisecond {
+		t.Errorf("Get = %+v, %v", latest, err)
+	}
+
+	runs, err := store.Runs(ctx, "search", 0)
+	if err != nil || len(runs) != 5 || runs[0].SampleCount != 4 || runs[4].SampleCount != 8 {
+		t.Errorf("expected runs 4..8 after pruning, got %d runs: %v", len(runs), err)
+	}
+	if runs, _ := store.Runs(ctx, "search", 2); len(runs) != 2 || runs[1].SampleCount != 8 {
+		t.Errorf("Runs with limit returned %d runs", len(runs))
+	}
+
+	names, _ := store.List(ctx)
+	if len(names) != 2 || names[0] != "index" || names[1] != "search" {
+		t.Errorf("List = %v", names)
+	}
+
+	// History survives reopening.
+	store.Close()
+	store, err = OpenSQLiteBaseline(path, 5)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Delete(ctx, "search"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "search"); err != ErrBaselineNotFound {
+		t.Errorf("second Delete = %v, want ErrBaselineNotFound", err)
+	}
+	if _, err := store.Get(ctx, "index"); err != nil {
+		t.Errorf("other component lost: %v", err)
+	}
+}
+
+func TestSQLiteBaseline_GateTrend(t *testing.T) {
+	store, err := OpenSQLiteBaseline(filepath.Join(t.TempDir(), "baselines.db"), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	gate := NewGate(store, WithUpdateBaseline(true), WithTrendDetection(nil))
+	var decision *GateDecision
+	for _, run := range creepingRuns(10, 0.01, 0) {
+		decision, err = gate.Check(context.Background(), "search", &CurrentMetrics{
+			Latency:     run.Latency,
+			Throughput:  run.Throughput,
+			Memory:      run.Memory,
+			SampleCount: 100,
+		})
+		if err != nil || !decision.Pass {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if decision.Trend == nil || !decision.Trend.HasRegressions() {
+		t.Error("expected the trend to be detected from SQLite history")
+	}
+}
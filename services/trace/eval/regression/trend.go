@@ -0,0 +1,313 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package regression
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Trend Configuration
+// -----------------------------------------------------------------------------
+
+// TrendConfig configures trend detection across stored runs.
+type TrendConfig struct {
+	// Window is the number of most recent runs analyzed.
+	Window int
+
+	// MinRuns is the fewest runs for which a trend is reported.
+	MinRuns int
+
+	// MinR2 is the least coefficient of determination of the linear fit
+	// for a change to count as a trend rather than noise (0 to 1).
+	MinR2 float64
+
+	// Thresholds gives the allowed change per metric. A trend is a
+	// regression when the fitted change across the window exceeds the
+	// threshold a single run is held to.
+	Thresholds *DetectorConfig
+}
+
+// DefaultTrendConfig returns sensible defaults.
+func DefaultTrendConfig() *TrendConfig {
+	return &TrendConfig{
+		Window:     10,
+		MinRuns:    5,
+		MinR2:      0.6,
+		Thresholds: DefaultDetectorConfig(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Trend Report
+// -----------------------------------------------------------------------------
+
+// MetricTrend is the linear trend of one metric across runs.
+type MetricTrend struct {
+	// Type identifies the metric.
+	Type RegressionType `json:"type"`
+
+	// Values are the metric values, oldest first. Latencies are in
+	// nanoseconds.
+	Values []float64 `json:"values"`
+
+	// Start and End are the fitted values at the first and last run.
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	// SlopePerRun is the fitted change per run.
+	SlopePerRun float64 `json:"slope_per_run"`
+
+	// Change is the fitted change across the window in the regressing
+	// direction (positive = worse): relative for latency, throughput and
+	// memory, absolute for error rate.
+	Change float64 `json:"change"`
+
+	// R2 is the coefficient of determination of the fit.
+	R2 float64 `json:"r2"`
+
+	// Threshold is the allowed change for the metric.
+	Threshold float64 `json:"threshold"`
+}
+
+// regressing reports whether the trend exceeds its threshold with a good
+// enough fit.
+func (m MetricTrend) regressing(minR2 float64) bool {
+	return m.Change > m.Threshold && m.R2 >= minR2
+}
+
+// TrendReport holds the result of trend analysis for a component.
+type TrendReport struct {
+	// Component is the component analyzed.
+	Component string `json:"component"`
+
+	// Runs is the number of runs analyzed.
+	Runs int `json:"runs"`
+
+	// From and To are the times of the first and last run analyzed.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// Metrics holds the trend of every metric. Empty if there were fewer
+	// than MinRuns runs.
+	Metrics []MetricTrend `json:"metrics"`
+
+	// Regressions holds the metrics drifting past their threshold.
+	Regressions []Regression `json:"regressions"`
+}
+
+// HasRegressions returns true if any metric is drifting past its threshold.
+func (r *TrendReport) HasRegressions() bool {
+	return len(r.Regressions) > 0
+}
+
+// -----------------------------------------------------------------------------
+// Trend Analysis
+// -----------------------------------------------------------------------------
+
+// trendMetric describes how to read and judge one metric.
+type trendMetric struct {
+	typ       RegressionType
+	value     func(*BaselineData) float64
+	threshold func(*DetectorConfig) float64
+	// higherIsBetter is true for throughput.
+	higherIsBetter bool
+	// absolute is true for error rate, whose threshold is absolute.
+	absolute bool
+}
+
+var trendMetrics = []trendMetric{
+	{
+		typ:       RegressionLatencyP50,
+		value:     func(b *BaselineData) float64 { return float64(b.Latency.P50) },
+		threshold: func(c *DetectorConfig) float64 { return c.LatencyP50Threshold },
+	},
+	{
+		typ:       RegressionLatencyP95,
+		value:     func(b *BaselineData) float64 { return float64(b.Latency.P95) },
+		threshold: func(c *DetectorConfig) float64 { return c.LatencyP95Threshold },
+	},
+	{
+		typ:       RegressionLatencyP99,
+		value:     func(b *BaselineData) float64 { return float64(b.Latency.P99) },
+		threshold: func(c *DetectorConfig) float64 { return c.LatencyP99Threshold },
+	},
+	{
+		typ:            RegressionThroughput,
+		value:          func(b *BaselineData) float64 { return b.Throughput.OpsPerSecond },
+		threshold:      func(c *DetectorConfig) float64 { return c.ThroughputThreshold },
+		higherIsBetter: true,
+	},
+	{
+		typ:       RegressionMemory,
+		value:     func(b *BaselineData) float64 { return float64(b.Memory.AllocBytesPerOp) },
+		threshold: func(c *DetectorConfig) float64 { return c.MemoryThreshold },
+	},
+	{
+		typ:       RegressionErrorRate,
+		value:     func(b *BaselineData) float64 { return b.Error.Rate },
+		threshold: func(c *DetectorConfig) float64 { return c.ErrorRateThreshold },
+		absolute:  true,
+	},
+}
+
+// AnalyzeTrend finds metrics that drift across a series of runs.
+//
+// Description:
+//
+//	A least-squares line is fitted to each metric over the last
+//	config.Window runs. A metric regresses when the fitted change from
+//	the first run to the last exceeds the threshold a single run is held
+//	to, and the fit explains at least config.MinR2 of the variance. This
+//	catches slow creep, such as 1% per build over 10 builds, that never
+//	trips a single-run comparison. Metrics other than error rate whose
+//	fitted first value is zero (i.e. not measured) are reported but not
+//	judged.
+//
+// Inputs:
+//   - component: Component name.
+//   - runs: Runs, oldest first (see History.Runs).
+//   - config: Trend configuration. Nil uses DefaultTrendConfig.
+//
+// Outputs:
+//   - *TrendReport: The analysis. Never nil.
+//
+// Thread Safety: Safe for concurrent use.
+func AnalyzeTrend(component string, runs []*BaselineData, config *TrendConfig) *TrendReport {
+	if config == nil {
+		config = DefaultTrendConfig()
+	}
+	thresholds := config.Thresholds
+	if thresholds == nil {
+		thresholds = DefaultDetectorConfig()
+	}
+	if config.Window > 0 && len(runs) > config.Window {
+		runs = runs[len(runs)-config.Window:]
+	}
+
+	report := &TrendReport{
+		Component:   component,
+		Runs:        len(runs),
+		Regressions: make([]Regression, 0),
+	}
+	if len(runs) > 0 {
+		report.From = runs[0].UpdatedAt
+		report.To = runs[len(runs)-1].UpdatedAt
+	}
+	if len(runs) < max(config.MinRuns, 2) {
+		return report
+	}
+
+	for _, metric := range trendMetrics {
+		values := make([]float64, len(runs))
+		for i, run := range runs {
+			values[i] = metric.value(run)
+		}
+
+		slope, intercept, r2 := linearFit(values)
+		trend := MetricTrend{
+			Type:        metric.typ,
+			Values:      values,
+			Start:       intercept,
+			End:         intercept + slope*float64(len(values)-1),
+			SlopePerRun: slope,
+			R2:          r2,
+			Threshold:   metric.threshold(thresholds),
+		}
+
+		switch {
+		case metric.absolute:
+			trend.Change = trend.End - trend.Start
+		case trend.Start <= 0:
+			report.Metrics = append(report.Metrics, trend)
+			continue
+		case metric.higherIsBetter:
+			trend.Change = (trend.Start - trend.End) / trend.Start
+		default:
+			trend.Change = (trend.End - trend.Start) / trend.Start
+		}
+		report.Metrics = append(report.Metrics, trend)
+
+		if trend.regressing(config.MinR2) {
+			report.Regressions = append(report.Regressions, trendRegression(component, metric, trend, len(runs)))
+		}
+	}
+
+	return report
+}
+
+// trendRegression describes a drifting metric as a Regression.
+func trendRegression(component string, metric trendMetric, trend MetricTrend, runs int) Regression {
+	var change string
+	if metric.absolute {
+		change = fmt.Sprintf("%+.2f%%", trend.Change*100)
+	} else {
+		change = fmt.Sprintf("%.1f%%", trend.Change*100)
+	}
+	verb := "rose"
+	if metric.higherIsBetter {
+		verb = "fell"
+	}
+
+	severity := SeverityWarning
+	if trend.Change > trend.Threshold*2 {
+		severity = SeverityError
+	}
+
+	return Regression{
+		Type:          metric.typ,
+		Severity:      severity,
+		Component:     component,
+		BaselineValue: trend.Start,
+		CurrentValue:  trend.End,
+		Change:        trend.Change,
+		Threshold:     trend.Threshold,
+		Message: fmt.Sprintf("%s %s %s over %d runs (threshold %.1f%%, r²=%.2f)",
+			metric.typ, verb, change, runs, trend.Threshold*100, trend.R2),
+	}
+}
+
+// linearFit fits y = intercept + slope*x by least squares, with x the
+// index of each value, and returns the coefficient of determination.
+// r2 is 0 when the values are constant.
+func linearFit(values []float64) (slope, intercept, r2 float64) {
+	n := float64(len(values))
+	if n == 0 {
+		return 0, 0, 0
+	}
+	meanX := (n - 1) / 2
+	var meanY float64
+	for _, y := range values {
+		meanY += y
+	}
+	meanY /= n
+
+	var sxy, sxx, syy float64
+	for i, y := range values {
+		dx := float64(i) - meanX
+		dy := y - meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, meanY, 0
+	}
+	slope = sxy / sxx
+	intercept = meanY - slope*meanX
+	if syy == 0 {
+		return slope, intercept, 0
+	}
+	r2 = math.Min(1, (sxy*sxy)/(sxx*syy))
+	return slope, intercept, r2
+}
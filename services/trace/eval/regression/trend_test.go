@@ -0,0 +1,171 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package regression
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// creepingRuns returns n runs whose P50 latency grows by step (a ratio)
+// per run, with alternating jitter. Other metrics are constant.
+func creepingRuns(n int, step, jitter float64) []*BaselineData {
+	runs := make([]*BaselineData, n)
+	p50 := float64(10 * time.Millisecond)
+	for i := range runs {
+		j := jitter
+		if i%2 == 1 {
+			j = -jitter
+		}
+		runs[i] = &BaselineData{
+			Component:  "search",
+			Latency:    LatencyBaseline{P50: time.Duration(p50 * (1 + j))},
+			Throughput: ThroughputBaseline{OpsPerSecond: 1000},
+			Memory:     MemoryBaseline{AllocBytesPerOp: 4096},
+			UpdatedAt:  time.Unix(int64(i), 0),
+		}
+		p50 *= 1 + step
+	}
+	return runs
+}
+
+func TestAnalyzeTrend(t *testing.T) {
+	t.Run("slow creep below single-run threshold", func(t *testing.T) {
+		runs := creepingRuns(10, 0.01, 0.002)
+
+		// No consecutive pair trips the 5% P50 gate.
+		detector := NewDetector(nil)
+		for i := 1; i < len(runs); i++ {
+			current := &CurrentMetrics{
+				Latency:     runs[i].Latency,
+				Throughput:  runs[i].Throughput,
+				Memory:      runs[i].Memory,
+				SampleCount: 100,
+			}
+			if detector.Detect(runs[i-1], current).HasRegressions() {
+				t.Fatalf("run %d tripped the single-run gate", i)
+			}
+		}
+
+		report := AnalyzeTrend("search", runs, nil)
+		if !report.HasRegressions() {
+			t.Fatalf("expected creep to be detected: %+v", report.Metrics)
+		}
+		if len(report.Regressions) != 1 || report.Regressions[0].Type != RegressionLatencyP50 {
+			t.Errorf("expected only a P50 regression, got %+v", report.Regressions)
+		}
+		if report.Runs != 10 || !report.To.Equal(time.Unix(9, 0)) {
+			t.Errorf("report covers %d runs to %v", report.Runs, report.To)
+		}
+	})
+
+	t.Run("noise is not a trend", func(t *testing.T) {
+		runs := creepingRuns(10, 0, 0.04)
+		if report := AnalyzeTrend("search", runs, nil); report.HasRegressions() {
+			t.Errorf("unexpected regressions: %+v", report.Regressions)
+		}
+	})
+
+	t.Run("improvement is not a regression", func(t *testing.T) {
+		runs := creepingRuns(10, -0.02, 0)
+		if report := AnalyzeTrend("search", runs, nil); report.HasRegressions() {
+			t.Errorf("unexpected regressions: %+v", report.Regressions)
+		}
+	})
+
+	t.Run("window and minimum runs", func(t *testing.T) {
+		// Old creep followed by a flat window.
+		runs := append(creepingRuns(10, 0.02, 0), creepingRuns(10, 0, 0)...)
+		if report := AnalyzeTrend("search", runs, nil); report.HasRegressions() || report.Runs != 10 {
+			t.Errorf("expected flat 10-run window, got %d runs, %+v", report.Runs, report.Regressions)
+		}
+
+		report := AnalyzeTrend("search", creepingRuns(4, 0.05, 0), nil)
+		if len(report.Metrics) != 0 || report.HasRegressions() {
+			t.Error("expected no analysis below MinRuns")
+		}
+	})
+}
+
+func TestMemoryBaseline_Runs(t *testing.T) {
+	store := NewMemoryBaseline()
+	ctx := context.Background()
+
+	if _, err := store.Runs(ctx, "search", 0); err != ErrBaselineNotFound {
+		t.Fatalf("expected ErrBaselineNotFound, got %v", err)
+	}
+	for i := 0; i < DefaultHistoryRetention+5; i++ {
+		store.Set(ctx, "search", &BaselineData{Component: "search", SampleCount: i})
+	}
+
+	all, _ := store.Runs(ctx, "search", 0)
+	if len(all) != DefaultHistoryRetention || all[0].SampleCount != 5 {
+		t.Errorf("expected %d retained runs starting at 5, got %d starting at %d",
+			DefaultHistoryRetention, len(all), all[0].SampleCount)
+	}
+	last, _ := store.Runs(ctx, "search", 3)
+	if len(last) != 3 || last[2].SampleCount != DefaultHistoryRetention+4 {
+		t.Errorf("unexpected last runs: %d", len(last))
+	}
+	latest, _ := store.Get(ctx, "search")
+	if latest.SampleCount != DefaultHistoryRetention+4 {
+		t.Errorf("Get returned run %d, want the latest", latest.SampleCount)
+	}
+}
+
+func TestGate_TrendDetection(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryBaseline()
+	gate := NewGate(store, WithUpdateBaseline(true), WithTrendDetection(nil))
+
+	var decision *GateDecision
+	for _, run := range creepingRuns(10, 0.01, 0) {
+		var err error
+		decision, err = gate.Check(ctx, "search", &CurrentMetrics{
+			Latency:     run.Latency,
+			Throughput:  run.Throughput,
+			Memory:      run.Memory,
+			SampleCount: 100,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decision.Pass {
+			t.Fatalf("single run failed the gate:\n%s", decision.Report)
+		}
+	}
+
+	if decision.Trend == nil || !decision.Trend.HasRegressions() {
+		t.Fatal("expected the gate to report the trend")
+	}
+	found := false
+	for _, w := range decision.Warnings {
+		if w.Type == RegressionLatencyP50 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a P50 trend warning, got %+v", decision.Warnings)
+	}
+
+	strict := NewGate(store, WithTrendDetection(nil), WithFailOnWarnings(true))
+	latest, _ := store.Get(ctx, "search")
+	decision, _ = strict.Check(ctx, "search", &CurrentMetrics{
+		Latency:     latest.Latency,
+		Throughput:  latest.Throughput,
+		Memory:      latest.Memory,
+		SampleCount: 100,
+	})
+	if decision.Pass {
+		t.Error("FailOnWarnings should fail on a trend")
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+var (
+	// ErrEmptyCorpus indicates that a corpus directory holds no samples.
+	ErrEmptyCorpus = errors.New("corpus has no samples")
+
+	// ErrUnsupportedVersion indicates a sample written by a newer format.
+	ErrUnsupportedVersion = errors.New("unsupported sample version")
+)
+
+// -----------------------------------------------------------------------------
+// Sample
+// -----------------------------------------------------------------------------
+
+// SampleVersion is the current sample file format version.
+const SampleVersion = 1
+
+// Sample is one recorded algorithm execution.
+//
+// Samples are stored as corpusDir/<algorithm>/<hash>.json, where hash is
+// derived from the redacted input and snapshot, so identical executions are
+// stored once.
+type Sample struct {
+	// Version is the sample format version.
+	Version int `json:"version"`
+
+	// Algorithm is the name of the algorithm that received the input.
+	Algorithm string `json:"algorithm"`
+
+	// InputType is the Go type of the input, for reference.
+	InputType string `json:"input_type"`
+
+	// SessionID is the session the input was recorded in.
+	SessionID string `json:"session_id,omitempty"`
+
+	// RecordedAt is when the input was recorded.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Input is the redacted JSON encoding of the input.
+	Input json.RawMessage `json:"input"`
+
+	// Snapshot is the redacted crs.CRSExport of the snapshot, if recorded.
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+
+	// Path is the file the sample was loaded from. Not serialized.
+	Path string `json:"-"`
+}
+
+// DecodeInput decodes the sample input into a value of type t.
+//
+// Inputs:
+//
+//	t - The algorithm's InputType. Pointer types yield a pointer to a
+//	    freshly decoded value.
+//
+// Outputs:
+//
+//	any - The decoded input.
+//	error - Non-nil if the input does not decode into t.
+func (s *Sample) DecodeInput(t reflect.Type) (any, error) {
+	if t == nil {
+		return nil, fmt.Errorf("decode %s input: nil input type", s.Algorithm)
+	}
+	if t.Kind() == reflect.Pointer {
+		v := reflect.New(t.Elem())
+		if err := json.Unmarshal(s.Input, v.Interface()); err != nil {
+			return nil, fmt.Errorf("decode %s input as %s: %w", s.Algorithm, t, err)
+		}
+		return v.Interface(), nil
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(s.Input, v.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s input as %s: %w", s.Algorithm, t, err)
+	}
+	return v.Elem().Interface(), nil
+}
+
+// RestoreSnapshot rebuilds the recorded snapshot in a fresh CRS.
+//
+// Description:
+//
+//	Applies the recorded proof entries and constraints as hard-signal
+//	deltas. Other indexes are not restored and start empty; dependencies
+//	come from the code graph, which samples do not include. A sample
+//	without a snapshot yields the snapshot of an empty CRS.
+//
+// Outputs:
+//
+//	crs.Snapshot - The restored snapshot.
+//	error - Non-nil if the snapshot does not decode or apply.
+func (s *Sample) RestoreSnapshot(ctx context.Context) (crs.Snapshot, error) {
+	c := crs.New(nil)
+	if len(s.Snapshot) == 0 {
+		return c.Snapshot(), nil
+	}
+
+	var export crs.CRSExport
+	if err := json.Unmarshal(s.Snapshot, &export); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	if entries := export.Indexes.Proof.Entries; len(entries) > 0 {
+		updates := make(map[string]crs.ProofNumber, len(entries))
+		for _, e := range entries {
+			updates[e.NodeID] = crs.ProofNumber{
+				Proof:     e.Proof,
+				Disproof:  e.Disproof,
+				Status:    proofStatuses[e.Status],
+				Source:    signalSources[e.Source],
+				UpdatedAt: e.UpdatedAt.UnixMilli(),
+			}
+		}
+		if _, err := c.Apply(ctx, crs.NewProofDelta(crs.SignalSourceHard, updates)); err != nil {
+			return nil, fmt.Errorf("restore proof index: %w", err)
+		}
+	}
+
+	if entries := export.Indexes.Constraint.Constraints; len(entries) > 0 {
+		delta := crs.NewConstraintDelta(crs.SignalSourceHard)
+		for _, e := range entries {
+			delta.Add = append(delta.Add, crs.Constraint{
+				ID:         e.ID,
+				Type:       constraintTypes[e.Type],
+				Nodes:      e.Nodes,
+				Expression: e.Expression,
+				Active:     e.Active,
+				Source:     signalSources[e.Source],
+				CreatedAt:  e.CreatedAt.UnixMilli(),
+			})
+		}
+		if _, err := c.Apply(ctx, delta); err != nil {
+			return nil, fmt.Errorf("restore constraint index: %w", err)
+		}
+	}
+
+	return c.Snapshot(), nil
+}
+
+// proofStatuses maps exported proof statuses back to their values.
+var proofStatuses = map[string]crs.ProofStatus{
+	crs.ProofStatusUnknown.String():   crs.ProofStatusUnknown,
+	crs.ProofStatusProven.String():    crs.ProofStatusProven,
+	crs.ProofStatusDisproven.String(): crs.ProofStatusDisproven,
+	crs.ProofStatusExpanded.String():  crs.ProofStatusExpanded,
+}
+
+// signalSources maps exported signal sources back to their values.
+var signalSources = map[string]crs.SignalSource{
+	crs.SignalSourceUnknown.String(): crs.SignalSourceUnknown,
+	crs.SignalSourceHard.String():    crs.SignalSourceHard,
+	crs.SignalSourceSoft.String():    crs.SignalSourceSoft,
+	crs.SignalSourceSafety.String():  crs.SignalSourceSafety,
+}
+
+// constraintTypes maps exported constraint types back to their values.
+var constraintTypes = func() map[string]crs.ConstraintType {
+	types := make(map[string]crs.ConstraintType)
+	for t := crs.ConstraintTypeUnknown; t <= crs.ConstraintTypeDuring; t++ {
+		types[t.String()] = t
+	}
+	return types
+}()
+
+// -----------------------------------------------------------------------------
+// Corpus
+// -----------------------------------------------------------------------------
+
+// Corpus is a set of samples loaded from a corpus directory.
+type Corpus struct {
+	// Dir is the directory the corpus was loaded from.
+	Dir string
+
+	samples map[string][]*Sample
+}
+
+// LoadCorpus reads every sample below dir.
+//
+// Description:
+//
+//	Walks dir for *.json files and decodes each as a Sample. Samples of
+//	each algorithm are ordered by path, so replays are deterministic.
+//
+// Outputs:
+//
+//	*Corpus - The loaded corpus.
+//	error - Non-nil if dir cannot be read, a sample is malformed or has
+//	        an unsupported version, or the corpus is empty.
+func LoadCorpus(dir string) (*Corpus, error) {
+	corpus := &Corpus{Dir: dir, samples: make(map[string][]*Sample)}
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		sample, err := readSample(path)
+		if err != nil {
+			return err
+		}
+		corpus.samples[sample.Algorithm] = append(corpus.samples[sample.Algorithm], sample)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load corpus %s: %w", dir, err)
+	}
+	if len(corpus.samples) == 0 {
+		return nil, fmt.Errorf("load corpus %s: %w", dir, ErrEmptyCorpus)
+	}
+
+	for _, samples := range corpus.samples {
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Path < samples[j].Path })
+	}
+	return corpus, nil
+}
+
+// readSample decodes one sample file.
+func readSample(path string) (*Sample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sample Sample
+	if err := json.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if sample.Version > SampleVersion {
+		return nil, fmt.Errorf("%s: %w %d", path, ErrUnsupportedVersion, sample.Version)
+	}
+	if sample.Algorithm == "" {
+		return nil, fmt.Errorf("%s: sample has no algorithm", path)
+	}
+	sample.Path = path
+	return &sample, nil
+}
+
+// Algorithms returns the names of the algorithms with samples, sorted.
+func (c *Corpus) Algorithms() []string {
+	names := make([]string, 0, len(c.samples))
+	for name := range c.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Samples returns the samples recorded for an algorithm.
+func (c *Corpus) Samples(algorithm string) []*Sample {
+	return c.samples[algorithm]
+}
+
+// Len returns the total number of samples.
+func (c *Corpus) Len() int {
+	n := 0
+	for _, samples := range c.samples {
+		n += len(samples)
+	}
+	return n
+}
+
+// InputGenerator returns a generator that cycles through the inputs
+// recorded for algo.
+//
+// Description:
+//
+//	The generator fits eval.Property.Generator and
+//	benchmark.WithInputGenerator, so existing harnesses can run on real
+//	inputs. Each call decodes a fresh value, so algorithms that modify
+//	their input do not affect later calls. Samples that do not decode into
+//	algo's input type are left out.
+//
+// Outputs:
+//
+//	func() any - The generator. Safe for concurrent use.
+//	error - Non-nil if no sample of algo decodes.
+func (c *Corpus) InputGenerator(algo algorithms.Algorithm) (func() any, error) {
+	inputType := algo.InputType()
+	var usable []*Sample
+	for _, sample := range c.samples[algo.Name()] {
+		if _, err := sample.DecodeInput(inputType); err == nil {
+			usable = append(usable, sample)
+		}
+	}
+	if len(usable) == 0 {
+		return nil, fmt.Errorf("%s: %w", algo.Name(), ErrEmptyCorpus)
+	}
+
+	var (
+		mu   sync.Mutex
+		next int
+	)
+	return func() any {
+		mu.Lock()
+		sample := usable[next]
+		next = (next + 1) % len(usable)
+		mu.Unlock()
+
+		// Decoded successfully above, so this cannot fail.
+		input, _ := sample.DecodeInput(inputType)
+		return input
+	}, nil
+}
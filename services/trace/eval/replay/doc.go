@@ -0,0 +1,81 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package replay records real algorithm inputs and replays them as a golden
+// test corpus.
+//
+// # Overview
+//
+// Property checks and benchmarks normally run on synthetic generators,
+// which rarely look like what the agent produces on real code. This
+// package captures the inputs algorithms receive during production
+// sessions, together with the CRS snapshot they ran against, and replays
+// them later so correctness and benchmark runs exercise realistic inputs.
+//
+//	┌──────────────────────────────────────────────────────────────────┐
+//	│                     GOLDEN CORPUS REPLAY                         │
+//	├──────────────────────────────────────────────────────────────────┤
+//	│                                                                  │
+//	│  Session ──► algorithms.Runner ──► Recorder ──► corpusDir/       │
+//	│                                    • sample      <algo>/<hash>   │
+//	│                                    • redact          .json       │
+//	│                                    • dedupe            │         │
+//	│                                                        ▼         │
+//	│  Report ◄── Property checks ◄── Process ◄──────── Run(corpusDir) │
+//	│                                                                  │
+//	└──────────────────────────────────────────────────────────────────┘
+//
+// # Recording
+//
+// A Recorder is attached to a context with algorithms.ContextWithInputRecorder.
+// Every algorithm the runner executes under that context is offered to the
+// recorder, which samples, redacts and writes one JSON file per distinct
+// input:
+//
+//	rec, err := replay.NewRecorder(replay.DefaultRecorderConfig(dir))
+//	ctx = algorithms.ContextWithInputRecorder(ctx, rec.ForSession(sessionID))
+//
+// String values are passed through a crs.Sanitizer and configured field
+// names are blanked before anything is written. Inputs that cannot be
+// encoded as JSON, or exceed the size limit, are not recorded.
+//
+// # Replaying
+//
+// Run loads a corpus, restores each sample's snapshot, runs the algorithm
+// on the decoded input and checks every eval.Property of the algorithm
+// against the output:
+//
+//	report, err := replay.Run(ctx, "testdata/corpus")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !report.OK() {
+//	    for _, r := range report.Failures() {
+//	        log.Printf("%s %s: %s", r.Algorithm, r.Path, r.Error)
+//	    }
+//	}
+//
+// A loaded Corpus also supplies input generators for the existing harnesses:
+//
+//	gen, err := corpus.InputGenerator(algo)
+//	...
+//	result, err := runner.Run(ctx, algo.Name(), benchmark.WithInputGenerator(gen))
+//
+// # Snapshots
+//
+// Snapshots are stored as crs.CRSExport. Replay restores the proof and
+// constraint indexes; the other indexes start empty. Dependencies are read
+// from the code graph, which is not recorded, so algorithms that query
+// them see none during replay.
+//
+// # Thread Safety
+//
+// Recorder is safe for concurrent use. Corpus is read-only after loading.
+package replay
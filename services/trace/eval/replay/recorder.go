@@ -0,0 +1,349 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// redacted replaces the values of redacted fields.
+const redacted = "[REDACTED]"
+
+// -----------------------------------------------------------------------------
+// Recorder Configuration
+// -----------------------------------------------------------------------------
+
+// RecorderConfig configures a Recorder.
+type RecorderConfig struct {
+	// Dir is the corpus directory samples are written to. Required.
+	Dir string
+
+	// MaxSamplesPerAlgorithm caps the samples kept per algorithm, counting
+	// samples already in Dir. Zero means the default of 200.
+	MaxSamplesPerAlgorithm int
+
+	// SampleRate is the fraction of executions recorded, in (0, 1].
+	// Zero means every execution is recorded.
+	SampleRate float64
+
+	// MaxSampleBytes skips samples whose encoded form exceeds this size.
+	// Zero means the default of 1 MiB.
+	MaxSampleBytes int
+
+	// RecordSnapshots stores the CRS snapshot with each input.
+	RecordSnapshots bool
+
+	// Sanitizer redacts secrets from every string value. Nil means
+	// crs.NewSecretSanitizer().
+	Sanitizer crs.Sanitizer
+
+	// RedactFields lists JSON field names whose values are blanked
+	// wherever they appear, compared case-insensitively. Strings become
+	// "[REDACTED]", other values their zero value.
+	RedactFields []string
+
+	// Logger receives recording failures. Nil means slog.Default().
+	Logger *slog.Logger
+}
+
+// DefaultRecorderConfig returns the default configuration for recording
+// into dir.
+func DefaultRecorderConfig(dir string) RecorderConfig {
+	return RecorderConfig{
+		Dir:                    dir,
+		MaxSamplesPerAlgorithm: 200,
+		SampleRate:             1,
+		MaxSampleBytes:         1 << 20,
+		RecordSnapshots:        true,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Recorder
+// -----------------------------------------------------------------------------
+
+// Recorder writes redacted algorithm inputs to a corpus directory.
+//
+// Thread Safety: Safe for concurrent use.
+type Recorder struct {
+	config     RecorderConfig
+	redact     map[string]bool
+	serializer *crs.Serializer
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewRecorder creates a recorder, creating the corpus directory if needed.
+//
+// Outputs:
+//
+//	*Recorder - The recorder.
+//	error - Non-nil if Dir is empty or cannot be created or read.
+func NewRecorder(config RecorderConfig) (*Recorder, error) {
+	if config.Dir == "" {
+		return nil, errors.New("recorder: corpus directory must not be empty")
+	}
+	defaults := DefaultRecorderConfig(config.Dir)
+	if config.MaxSamplesPerAlgorithm <= 0 {
+		config.MaxSamplesPerAlgorithm = defaults.MaxSamplesPerAlgorithm
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.MaxSampleBytes <= 0 {
+		config.MaxSampleBytes = defaults.MaxSampleBytes
+	}
+	if config.Sanitizer == nil {
+		config.Sanitizer = crs.NewSecretSanitizer()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+
+	r := &Recorder{
+		config:     config,
+		redact:     make(map[string]bool, len(config.RedactFields)),
+		serializer: crs.NewSerializer(config.Logger),
+		counts:     make(map[string]int),
+	}
+	for _, field := range config.RedactFields {
+		r.redact[strings.ToLower(field)] = true
+	}
+
+	// Count existing samples so the cap holds across restarts.
+	entries, err := os.ReadDir(config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		files, _ := filepath.Glob(filepath.Join(config.Dir, entry.Name(), "*.json"))
+		r.counts[entry.Name()] = len(files)
+	}
+	return r, nil
+}
+
+// Record writes one execution to the corpus.
+//
+// Description:
+//
+//	Skips the execution if it is not sampled, the algorithm already has
+//	MaxSamplesPerAlgorithm samples, or the encoded sample is too large.
+//	The input and snapshot are encoded as JSON, redacted and written to
+//	Dir/<algorithm>/<hash>.json; an identical sample is written once.
+//
+// Inputs:
+//
+//	algorithm - The algorithm name.
+//	snapshot - The snapshot the algorithm ran against. May be nil.
+//	input - The algorithm input. Not modified.
+//	sessionID - The session the execution belongs to.
+//
+// Outputs:
+//
+//	bool - True if a new sample was written.
+//	error - Non-nil if the input cannot be encoded or the file cannot be
+//	        written.
+func (r *Recorder) Record(algorithm string, snapshot crs.Snapshot, input any, sessionID string) (bool, error) {
+	if algorithm == "" || strings.ContainsAny(algorithm, `/\`) || strings.HasPrefix(algorithm, ".") {
+		return false, fmt.Errorf("record: invalid algorithm name %q", algorithm)
+	}
+	if r.config.SampleRate < 1 && rand.Float64() >= r.config.SampleRate {
+		return false, nil
+	}
+	if r.full(algorithm) {
+		return false, nil
+	}
+
+	encodedInput, err := r.encode(input)
+	if err != nil {
+		return false, fmt.Errorf("record %s input: %w", algorithm, err)
+	}
+	var encodedSnapshot json.RawMessage
+	if r.config.RecordSnapshots && snapshot != nil {
+		export := r.serializer.Export(snapshot, "")
+		// Timestamps would defeat deduplication of identical states.
+		export.Timestamp = 0
+		if encodedSnapshot, err = r.encode(export); err != nil {
+			return false, fmt.Errorf("record %s snapshot: %w", algorithm, err)
+		}
+	}
+	if len(encodedInput)+len(encodedSnapshot) > r.config.MaxSampleBytes {
+		return false, nil
+	}
+
+	hash := sha256.New()
+	hash.Write(encodedInput)
+	hash.Write([]byte{0})
+	hash.Write(encodedSnapshot)
+	name := hex.EncodeToString(hash.Sum(nil))[:16] + ".json"
+
+	data, err := json.MarshalIndent(&Sample{
+		Version:    SampleVersion,
+		Algorithm:  algorithm,
+		InputType:  fmt.Sprint(reflect.TypeOf(input)),
+		SessionID:  sessionID,
+		RecordedAt: time.Now().UTC(),
+		Input:      encodedInput,
+		Snapshot:   encodedSnapshot,
+	}, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("record %s: %w", algorithm, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts[algorithm] >= r.config.MaxSamplesPerAlgorithm {
+		return false, nil
+	}
+	dir := filepath.Join(r.config.Dir, algorithm)
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("record %s: %w", algorithm, err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return false, fmt.Errorf("record %s: %w", algorithm, err)
+	}
+	r.counts[algorithm]++
+	return true, nil
+}
+
+// ForSession returns an algorithms.InputRecorder that records into r on
+// behalf of a session. Failures are logged, never returned to the runner.
+func (r *Recorder) ForSession(sessionID string) algorithms.InputRecorder {
+	return &sessionRecorder{recorder: r, sessionID: sessionID}
+}
+
+// full reports whether algorithm has reached its sample cap.
+func (r *Recorder) full(algorithm string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[algorithm] >= r.config.MaxSamplesPerAlgorithm
+}
+
+// encode marshals v to JSON and redacts the result.
+func (r *Recorder) encode(v any) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.redactValue(decoded))
+}
+
+// redactValue sanitizes every string in a decoded JSON value and blanks
+// the values of redacted fields.
+func (r *Recorder) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.config.Sanitizer.Sanitize(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if r.redact[strings.ToLower(k)] {
+				out[k] = blank(item)
+				continue
+			}
+			out[k] = r.redactValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// blank returns the replacement for a redacted value of v's JSON kind, so
+// the sample still decodes into the input type.
+func blank(v any) any {
+	switch v.(type) {
+	case string:
+		return redacted
+	case float64:
+		return 0
+	case bool:
+		return false
+	case []any:
+		return []any{}
+	case map[string]any:
+		return map[string]any{}
+	default:
+		return nil
+	}
+}
+
+// writeFileAtomic writes data to path through a temporary file in the
+// same directory.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sample-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sessionRecorder adapts a Recorder to algorithms.InputRecorder.
+type sessionRecorder struct {
+	recorder  *Recorder
+	sessionID string
+}
+
+// RecordInput implements algorithms.InputRecorder.
+func (s *sessionRecorder) RecordInput(ctx context.Context, algo algorithms.Algorithm, snapshot crs.Snapshot, input any) {
+	if _, err := s.recorder.Record(algo.Name(), snapshot, input, s.sessionID); err != nil {
+		s.recorder.config.Logger.WarnContext(ctx, "eval replay: failed to record algorithm input",
+			slog.String("algorithm", algo.Name()),
+			slog.String("session_id", s.sessionID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
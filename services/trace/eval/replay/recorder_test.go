@@ -0,0 +1,205 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// wordSanitizer redacts one fixed word, so tests do not depend on the
+// secret patterns of crs.NewSecretSanitizer.
+type wordSanitizer string
+
+func (w wordSanitizer) Sanitize(s string) string {
+	return strings.ReplaceAll(s, string(w), redacted)
+}
+
+type requestInput struct {
+	Query   string
+	Token   string
+	Retries int
+	Headers map[string]string
+}
+
+func testRecorder(t *testing.T, mutate func(*RecorderConfig)) (*Recorder, string) {
+	t.Helper()
+	dir := t.TempDir()
+	config := DefaultRecorderConfig(dir)
+	config.Sanitizer = wordSanitizer("hunter2")
+	if mutate != nil {
+		mutate(&config)
+	}
+	r, err := NewRecorder(config)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	return r, dir
+}
+
+func TestRecorder_RedactsAndDeduplicates(t *testing.T) {
+	r, dir := testRecorder(t, func(c *RecorderConfig) {
+		c.RedactFields = []string{"token", "retries", "headers"}
+	})
+	input := &requestInput{
+		Query:   "login with hunter2",
+		Token:   "abc123",
+		Retries: 3,
+		Headers: map[string]string{"Authorization": "Bearer x"},
+	}
+
+	written, err := r.Record("lookup", nil, input, "session-1")
+	if err != nil || !written {
+		t.Fatalf("Record = %v, %v; want a new sample", written, err)
+	}
+	if written, err := r.Record("lookup", nil, input, "session-2"); err != nil || written {
+		t.Errorf("recording an identical input = %v, %v; want deduplicated", written, err)
+	}
+	if input.Token != "abc123" {
+		t.Error("Record modified its input")
+	}
+
+	corpus, err := LoadCorpus(dir)
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	samples := corpus.Samples("lookup")
+	if len(samples) != 1 || corpus.Len() != 1 {
+		t.Fatalf("corpus has %d samples, want 1", corpus.Len())
+	}
+	sample := samples[0]
+	if sample.SessionID != "session-1" || sample.InputType != "*replay.requestInput" {
+		t.Errorf("sample = %+v", sample)
+	}
+	for _, secret := range []string{"hunter2", "abc123", "Bearer"} {
+		if strings.Contains(string(sample.Input), secret) {
+			t.Errorf("input %s still contains %q", sample.Input, secret)
+		}
+	}
+
+	decoded, err := sample.DecodeInput(algoInputType)
+	if err != nil {
+		t.Fatalf("DecodeInput: %v", err)
+	}
+	got := decoded.(*requestInput)
+	if got.Query != "login with "+redacted || got.Token != redacted || got.Retries != 0 || len(got.Headers) != 0 {
+		t.Errorf("decoded input = %+v", got)
+	}
+}
+
+func TestRecorder_Limits(t *testing.T) {
+	r, dir := testRecorder(t, func(c *RecorderConfig) {
+		c.MaxSamplesPerAlgorithm = 2
+		c.MaxSampleBytes = 200
+	})
+
+	for _, query := range []string{"a", "b", "c"} {
+		if _, err := r.Record("lookup", nil, &requestInput{Query: query}, ""); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if written, _ := r.Record("other", nil, &requestInput{Query: strings.Repeat("x", 300)}, ""); written {
+		t.Error("oversized sample was recorded")
+	}
+	if _, err := r.Record("lookup", nil, make(chan int), ""); err != nil {
+		t.Errorf("a full algorithm should skip before encoding, got %v", err)
+	}
+	if _, err := r.Record("other", nil, make(chan int), ""); err == nil {
+		t.Error("expected an error for an input that cannot be encoded")
+	}
+	if _, err := r.Record("../escape", nil, &requestInput{}, ""); err == nil {
+		t.Error("expected an error for an algorithm name with a path separator")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "lookup", "*.json"))
+	if len(files) != 2 {
+		t.Errorf("%d samples written, want 2", len(files))
+	}
+
+	// The cap counts samples left by earlier recorders.
+	again, err := NewRecorder(RecorderConfig{Dir: dir, MaxSamplesPerAlgorithm: 2})
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if written, _ := again.Record("lookup", nil, &requestInput{Query: "d"}, ""); written {
+		t.Error("sample recorded beyond the cap after restart")
+	}
+}
+
+func TestRecorder_SnapshotRoundTrip(t *testing.T) {
+	r, dir := testRecorder(t, nil)
+	ctx := context.Background()
+
+	c := crs.New(nil)
+	proof := crs.NewProofDelta(crs.SignalSourceHard, map[string]crs.ProofNumber{
+		"main.go:Run": {Proof: 3, Disproof: 5, Status: crs.ProofStatusExpanded, Source: crs.SignalSourceHard},
+		"util.go:Fix": {Proof: 0, Disproof: 1, Status: crs.ProofStatusProven, Source: crs.SignalSourceSoft},
+	})
+	if _, err := c.Apply(ctx, proof); err != nil {
+		t.Fatalf("apply proof: %v", err)
+	}
+	constraints := crs.NewConstraintDelta(crs.SignalSourceHard)
+	constraints.Add = []crs.Constraint{{
+		ID:     "order-1",
+		Type:   crs.ConstraintTypeBefore,
+		Nodes:  []string{"util.go:Fix", "main.go:Run"},
+		Active: true,
+		Source: crs.SignalSourceHard,
+	}}
+	if _, err := c.Apply(ctx, constraints); err != nil {
+		t.Fatalf("apply constraints: %v", err)
+	}
+
+	if _, err := r.Record("lookup", c.Snapshot(), &requestInput{Query: "q"}, "s"); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	corpus, err := LoadCorpus(dir)
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	snapshot, err := corpus.Samples("lookup")[0].RestoreSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+
+	if got, want := snapshot.ProofIndex().All(), c.Snapshot().ProofIndex().All(); len(got) != len(want) {
+		t.Fatalf("restored %d proof entries, want %d", len(got), len(want))
+	}
+	pn, ok := snapshot.ProofIndex().Get("main.go:Run")
+	if !ok || pn.Proof != 3 || pn.Disproof != 5 || pn.Status != crs.ProofStatusExpanded {
+		t.Errorf("restored proof = %+v, %v", pn, ok)
+	}
+	if pn, _ := snapshot.ProofIndex().Get("util.go:Fix"); pn.Source != crs.SignalSourceSoft {
+		t.Errorf("restored source = %v, want soft", pn.Source)
+	}
+	constraint, ok := snapshot.ConstraintIndex().Get("order-1")
+	if !ok || constraint.Type != crs.ConstraintTypeBefore || len(constraint.Nodes) != 2 || !constraint.Active {
+		t.Errorf("restored constraint = %+v, %v", constraint, ok)
+	}
+}
+
+func TestNewRecorder_RequiresDir(t *testing.T) {
+	if _, err := NewRecorder(RecorderConfig{}); err == nil {
+		t.Error("expected an error for an empty directory")
+	}
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRecorder(RecorderConfig{Dir: file}); err == nil {
+		t.Error("expected an error for a directory that is a file")
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/constraints"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/planning"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/search"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/streaming"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// -----------------------------------------------------------------------------
+// Run Options
+// -----------------------------------------------------------------------------
+
+// Option configures a replay run.
+type Option func(*runConfig)
+
+type runConfig struct {
+	algorithms []algorithms.Algorithm
+	filter     func(*Sample) bool
+	logger     *slog.Logger
+}
+
+// WithAlgorithms sets the algorithm instances samples are replayed on,
+// matched by name. Default is DefaultAlgorithms().
+func WithAlgorithms(algos ...algorithms.Algorithm) Option {
+	return func(c *runConfig) {
+		c.algorithms = algos
+	}
+}
+
+// WithFilter replays only the samples for which keep returns true.
+func WithFilter(keep func(*Sample) bool) Option {
+	return func(c *runConfig) {
+		c.filter = keep
+	}
+}
+
+// WithLogger sets the logger for replay progress.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *runConfig) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// DefaultAlgorithms returns a default-configured instance of every
+// algorithm the agent runs.
+func DefaultAlgorithms() []algorithms.Algorithm {
+	return []algorithms.Algorithm{
+		search.NewPNMCTS(nil),
+		search.NewCDCL(nil),
+		search.NewDPLL(nil),
+		search.NewBeamSearch(nil),
+		search.NewTransposition(nil),
+		search.NewUnitPropagation(nil),
+		search.NewWatchedLiterals(nil),
+		graph.NewVF2(nil),
+		graph.NewDominators(nil),
+		graph.NewTarjanSCC(nil),
+		planning.NewCBS(nil),
+		planning.NewBlackboard(nil),
+		planning.NewHTN(nil),
+		streaming.NewAGMSketch(nil),
+		streaming.NewWeisfeilerLeman(nil),
+		streaming.NewCountMin(nil),
+		streaming.NewHyperLogLog(nil),
+		streaming.NewLSH(nil),
+		streaming.NewL0Sampling(nil),
+		streaming.NewMinHash(nil),
+		constraints.NewAC3(nil),
+		constraints.NewTMS(nil),
+		constraints.NewSemanticBackprop(nil),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Report
+// -----------------------------------------------------------------------------
+
+// Status is the outcome of replaying one sample.
+type Status string
+
+const (
+	// StatusPassed means the algorithm ran and every property held.
+	StatusPassed Status = "passed"
+
+	// StatusFailed means the sample did not decode, the algorithm failed,
+	// or a property was violated.
+	StatusFailed Status = "failed"
+
+	// StatusSkipped means no algorithm with the sample's name was given.
+	StatusSkipped Status = "skipped"
+)
+
+// Violation is a property that did not hold for a sample.
+type Violation struct {
+	// Property is the property name.
+	Property string `json:"property"`
+
+	// Error describes the violation.
+	Error string `json:"error"`
+}
+
+// SampleResult is the outcome of replaying one sample.
+type SampleResult struct {
+	// Algorithm is the algorithm name.
+	Algorithm string `json:"algorithm"`
+
+	// Path is the sample file.
+	Path string `json:"path"`
+
+	// Status is the outcome.
+	Status Status `json:"status"`
+
+	// Duration is how long Process took.
+	Duration time.Duration `json:"duration"`
+
+	// Error is set if the sample could not be replayed or the algorithm
+	// returned an error.
+	Error string `json:"error,omitempty"`
+
+	// Violations lists the properties that did not hold.
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// AlgorithmStats summarizes the replays of one algorithm.
+type AlgorithmStats struct {
+	// Runs is the number of samples the algorithm processed.
+	Runs int `json:"runs"`
+
+	// Failed is the number of failed samples.
+	Failed int `json:"failed"`
+
+	// Mean, P50, P95 and Max describe Process durations.
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	Max  time.Duration `json:"max"`
+}
+
+// Report is the outcome of a replay run.
+type Report struct {
+	// CorpusDir is the replayed corpus.
+	CorpusDir string `json:"corpus_dir"`
+
+	// Samples, Passed, Failed and Skipped count the samples by outcome.
+	Samples int `json:"samples"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+
+	// Duration is the wall time of the run.
+	Duration time.Duration `json:"duration"`
+
+	// Results holds one result per sample, ordered by algorithm and path.
+	Results []SampleResult `json:"results"`
+
+	// Algorithms holds per-algorithm statistics, keyed by name.
+	Algorithms map[string]*AlgorithmStats `json:"algorithms"`
+}
+
+// OK reports whether no sample failed.
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+// Failures returns the failed results.
+func (r *Report) Failures() []SampleResult {
+	var failed []SampleResult
+	for _, result := range r.Results {
+		if result.Status == StatusFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// -----------------------------------------------------------------------------
+// Run
+// -----------------------------------------------------------------------------
+
+// Run replays every sample in corpusDir.
+//
+// Description:
+//
+//	Loads the corpus and replays it with RunCorpus.
+//
+// Outputs:
+//
+//	*Report - The replay report.
+//	error - Non-nil if the corpus cannot be loaded or ctx is cancelled.
+//	        Failing samples are reported in the Report, not as an error.
+func Run(ctx context.Context, corpusDir string, opts ...Option) (*Report, error) {
+	corpus, err := LoadCorpus(corpusDir)
+	if err != nil {
+		return nil, err
+	}
+	return RunCorpus(ctx, corpus, opts...)
+}
+
+// RunCorpus replays every sample of a loaded corpus.
+//
+// Description:
+//
+//	For each sample, restores its snapshot, decodes its input into the
+//	algorithm's input type, runs Process under the algorithm's timeout
+//	and checks every property of the algorithm against input and output.
+//	Samples run one at a time so durations are comparable.
+//
+// Outputs:
+//
+//	*Report - The replay report.
+//	error - Non-nil if ctx is cancelled.
+func RunCorpus(ctx context.Context, corpus *Corpus, opts ...Option) (*Report, error) {
+	if ctx == nil {
+		return nil, errors.New("ctx must not be nil")
+	}
+	config := &runConfig{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.algorithms == nil {
+		config.algorithms = DefaultAlgorithms()
+	}
+	byName := make(map[string]algorithms.Algorithm, len(config.algorithms))
+	for _, algo := range config.algorithms {
+		byName[algo.Name()] = algo
+	}
+
+	start := time.Now()
+	report := &Report{CorpusDir: corpus.Dir, Algorithms: make(map[string]*AlgorithmStats)}
+	durations := make(map[string][]time.Duration)
+
+	for _, name := range corpus.Algorithms() {
+		algo := byName[name]
+		for _, sample := range corpus.Samples(name) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if config.filter != nil && !config.filter(sample) {
+				continue
+			}
+
+			report.Samples++
+			if algo == nil {
+				report.Skipped++
+				report.Results = append(report.Results, SampleResult{
+					Algorithm: name,
+					Path:      sample.Path,
+					Status:    StatusSkipped,
+					Error:     "no algorithm named " + name,
+				})
+				continue
+			}
+
+			result, ran := replaySample(ctx, algo, sample)
+			report.Results = append(report.Results, result)
+			if result.Status == StatusFailed {
+				report.Failed++
+				config.logger.Warn("eval replay: sample failed",
+					slog.String("algorithm", name),
+					slog.String("path", sample.Path),
+					slog.String("error", result.Error),
+					slog.Int("violations", len(result.Violations)),
+				)
+			} else {
+				report.Passed++
+			}
+
+			stats := report.Algorithms[name]
+			if stats == nil {
+				stats = &AlgorithmStats{}
+				report.Algorithms[name] = stats
+			}
+			if result.Status == StatusFailed {
+				stats.Failed++
+			}
+			if ran {
+				stats.Runs++
+				durations[name] = append(durations[name], result.Duration)
+			}
+		}
+	}
+
+	for name, ds := range durations {
+		summarize(report.Algorithms[name], ds)
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// replaySample runs one sample and checks the algorithm's properties. It
+// reports whether Process ran, so only real runs enter the statistics.
+func replaySample(ctx context.Context, algo algorithms.Algorithm, sample *Sample) (SampleResult, bool) {
+	result := SampleResult{Algorithm: sample.Algorithm, Path: sample.Path, Status: StatusFailed}
+
+	snapshot, err := sample.RestoreSnapshot(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result, false
+	}
+	input, err := sample.DecodeInput(algo.InputType())
+	if err != nil {
+		result.Error = err.Error()
+		return result, false
+	}
+
+	runCtx := ctx
+	if timeout := algo.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	output, err := process(runCtx, algo, snapshot, input)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result, true
+	}
+
+	for _, prop := range algo.Properties() {
+		if prop.Check == nil {
+			continue
+		}
+		if err := check(prop.Check, input, output); err != nil {
+			result.Violations = append(result.Violations, Violation{Property: prop.Name, Error: err.Error()})
+		}
+	}
+	if len(result.Violations) > 0 {
+		result.Error = fmt.Sprintf("%d of %d properties violated", len(result.Violations), len(algo.Properties()))
+		return result, true
+	}
+	result.Status = StatusPassed
+	return result, true
+}
+
+// process runs algo, converting a panic into an error.
+func process(ctx context.Context, algo algorithms.Algorithm, snapshot crs.Snapshot, input any) (output any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in %s: %v", algo.Name(), r)
+		}
+	}()
+	output, _, err = algo.Process(ctx, snapshot, input)
+	return output, err
+}
+
+// check runs a property check, converting a panic into an error.
+func check(fn func(input, output any) error, input, output any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(input, output)
+}
+
+// summarize fills the duration statistics of stats.
+func summarize(stats *AlgorithmStats, durations []time.Duration) {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	stats.Mean = total / time.Duration(len(durations))
+	stats.P50 = percentile(durations, 0.50)
+	stats.P95 = percentile(durations, 0.95)
+	stats.Max = durations[len(durations)-1]
+}
+
+// percentile returns the p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
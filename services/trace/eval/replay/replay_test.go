@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+)
+
+var algoInputType = reflect.TypeOf(&requestInput{})
+
+// lookupAlgo upper-cases the query. Its property requires the output to
+// be the upper-cased query, which a broken instance violates.
+type lookupAlgo struct {
+	broken bool
+	panics bool
+}
+
+func (a *lookupAlgo) Name() string                     { return "lookup" }
+func (a *lookupAlgo) Timeout() time.Duration           { return time.Second }
+func (a *lookupAlgo) InputType() reflect.Type          { return algoInputType }
+func (a *lookupAlgo) OutputType() reflect.Type         { return reflect.TypeOf("") }
+func (a *lookupAlgo) ProgressInterval() time.Duration  { return time.Second }
+func (a *lookupAlgo) SupportsPartialResults() bool     { return false }
+func (a *lookupAlgo) Metrics() []eval.MetricDefinition { return nil }
+func (a *lookupAlgo) HealthCheck(context.Context) error {
+	return nil
+}
+
+func (a *lookupAlgo) Process(_ context.Context, _ crs.Snapshot, input any) (any, crs.Delta, error) {
+	in := input.(*requestInput)
+	switch {
+	case a.panics:
+		panic("boom")
+	case in.Query == "fail":
+		return nil, nil, errors.New("cannot look up")
+	case a.broken:
+		return in.Query, nil, nil
+	}
+	return strings.ToUpper(in.Query), nil, nil
+}
+
+func (a *lookupAlgo) Properties() []eval.Property {
+	return []eval.Property{{
+		Name: "upper_cased",
+		Check: func(input, output any) error {
+			if output != strings.ToUpper(input.(*requestInput).Query) {
+				return errors.New("output is not the upper-cased query")
+			}
+			return nil
+		},
+	}}
+}
+
+func recordQueries(t *testing.T, queries ...string) string {
+	t.Helper()
+	r, dir := testRecorder(t, nil)
+	for _, query := range queries {
+		if _, err := r.Record("lookup", nil, &requestInput{Query: query}, "s"); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestRun_Passes(t *testing.T) {
+	dir := recordQueries(t, "a", "b", "c")
+
+	report, err := Run(context.Background(), dir, WithAlgorithms(&lookupAlgo{}))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.OK() || report.Samples != 3 || report.Passed != 3 {
+		t.Fatalf("report = %+v, want 3 passed", report)
+	}
+	stats := report.Algorithms["lookup"]
+	if stats == nil || stats.Runs != 3 || stats.Max < stats.P50 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	dir := recordQueries(t, "a", "fail")
+	// A sample for an algorithm that is not replayed.
+	other, err := NewRecorder(RecorderConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Record("retired", nil, "x", ""); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	report, err := Run(ctx, dir, WithAlgorithms(&lookupAlgo{broken: true}))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.OK() || report.Failed != 2 || report.Skipped != 1 || report.Passed != 0 {
+		t.Fatalf("report = %+v, want 2 failed and 1 skipped", report)
+	}
+	var violated, errored bool
+	for _, r := range report.Failures() {
+		violated = violated || (len(r.Violations) == 1 && r.Violations[0].Property == "upper_cased")
+		errored = errored || r.Error == "cannot look up"
+	}
+	if !violated || !errored {
+		t.Errorf("failures = %+v, want a property violation and a process error", report.Failures())
+	}
+
+	report, err = Run(ctx, dir,
+		WithAlgorithms(&lookupAlgo{panics: true}),
+		WithFilter(func(s *Sample) bool { return s.Algorithm == "lookup" }),
+	)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Samples != 2 || report.Failed != 2 || !strings.Contains(report.Results[0].Error, "panic") {
+		t.Errorf("report = %+v, want panics reported as failures", report)
+	}
+}
+
+func TestRun_UndecodableInput(t *testing.T) {
+	dir := recordQueries(t, "a")
+	path, _ := filepath.Glob(filepath.Join(dir, "lookup", "*.json"))
+	data, err := os.ReadFile(path[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"Retries": 0`, `"Retries": "many"`, 1))
+	if err := os.WriteFile(path[0], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), dir, WithAlgorithms(&lookupAlgo{}))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Failed != 1 || report.Algorithms["lookup"].Runs != 0 {
+		t.Errorf("report = %+v, want one failure that never ran", report)
+	}
+}
+
+func TestRun_EmptyCorpus(t *testing.T) {
+	if _, err := Run(context.Background(), t.TempDir()); !errors.Is(err, ErrEmptyCorpus) {
+		t.Errorf("err = %v, want ErrEmptyCorpus", err)
+	}
+}
+
+func TestRun_RecordedThroughRunner(t *testing.T) {
+	r, dir := testRecorder(t, nil)
+	ctx := algorithms.ContextWithInputRecorder(context.Background(), r.ForSession("session-1"))
+
+	input := &graph.TarjanInput{
+		Nodes: []string{"a", "b", "c"},
+		Edges: map[string][]string{"a": {"b"}, "b": {"a", "c"}},
+	}
+	if _, _, err := algorithms.RunParallel(ctx, crs.New(nil).Snapshot(),
+		algorithms.NewExecution(graph.NewTarjanSCC(nil), input)); err != nil {
+		t.Fatalf("RunParallel: %v", err)
+	}
+
+	report, err := Run(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.OK() || report.Passed != 1 || report.Results[0].Algorithm != "tarjan_scc" {
+		t.Errorf("report = %+v, want the recorded tarjan_scc sample to pass", report)
+	}
+}
+
+func TestCorpus_InputGenerator(t *testing.T) {
+	corpus, err := LoadCorpus(recordQueries(t, "a", "b"))
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	gen, err := corpus.InputGenerator(&lookupAlgo{})
+	if err != nil {
+		t.Fatalf("InputGenerator: %v", err)
+	}
+
+	seen := make(map[string]int)
+	for range 4 {
+		in := gen().(*requestInput)
+		seen[in.Query]++
+		in.Query = "modified"
+	}
+	if seen["a"] != 2 || seen["b"] != 2 {
+		t.Errorf("generated %v, want each input twice", seen)
+	}
+
+	if _, err := corpus.InputGenerator(graph.NewTarjanSCC(nil)); !errors.Is(err, ErrEmptyCorpus) {
+		t.Errorf("err = %v, want ErrEmptyCorpus for an algorithm without samples", err)
+	}
+}
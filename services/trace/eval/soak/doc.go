@@ -0,0 +1,78 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package soak drives long-running synthetic agent workloads and checks
+// that the process stays healthy over time.
+//
+// # Architecture
+//
+// Agent sessions run for hours. Leaks that are invisible in a unit test (a
+// goroutine per cancelled query, a cache entry per edit) add up over such
+// a session. The harness runs a weighted mix of operations on several
+// workers for a fixed duration and samples the process while it runs:
+//
+//	┌──────────────┐     ┌──────────────┐     ┌──────────────┐
+//	│   Workers    │────►│  Operations  │────►│   OpStats    │
+//	│ (weighted,   │     │ query, edit, │     │ count, errs, │
+//	│  seeded)     │     │ cancel, ...  │     │ latency      │
+//	└──────────────┘     └──────────────┘     └──────────────┘
+//	┌──────────────┐     ┌──────────────┐     ┌──────────────┐
+//	│   Sampler    │────►│   Samples    │────►│  Thresholds  │──► pass/fail
+//	│ (GC, heap,   │     │ heap, gorout │     │  heap slope, │
+//	│  goroutines) │     │ generation   │     │  leaks, rate │
+//	└──────────────┘     └──────────────┘     └──────────────┘
+//
+// A fraction of operations run under a context that is cancelled after a
+// random delay, exercising the cancellation paths; the harness measures
+// how long an operation takes to return once cancelled.
+//
+// # Checks
+//
+//   - Heap growth: least-squares slope of the live heap (sampled after a
+//     forced GC) over the samples after warmup, in bytes per hour
+//   - Goroutine leaks: goroutines left once the workers have stopped,
+//     compared with before the run
+//   - Error rate: failed operations over all operations, not counting
+//     operations that returned because they were cancelled
+//   - CRS generation rate: generations per second between samples, from
+//     an optional probe; a window below the minimum means the agent stalled
+//   - Cancellation latency: the slowest return after cancellation
+//
+// # Usage
+//
+//	harness := soak.NewHarness(
+//	    soak.WithDuration(2*time.Hour),
+//	    soak.WithGenerationProbe(crs.Generation),
+//	)
+//	result, err := harness.Run(ctx, []soak.Op{
+//	    {Name: "query", Weight: 6, Run: runQuery},
+//	    {Name: "edit", Weight: 3, Run: editFile},
+//	    {Name: "plan", Weight: 1, Run: runPlanner},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	result.WriteJSON(reportFile)
+//	if !result.Passed() {
+//	    // result.Violations lists the failed checks
+//	}
+//
+// # Nightly CI
+//
+// TestSoak_Nightly runs a synthetic agent workload against a CRS and real
+// files. It is skipped unless SOAK_DURATION is set:
+//
+//	SOAK_DURATION=2h SOAK_REPORT=soak.json go test -run TestSoak_Nightly -timeout 3h ./services/trace/eval/soak/
+//
+// # Thread Safety
+//
+// A Harness runs one soak at a time. Op.Run is called concurrently from
+// all workers.
+package soak
@@ -0,0 +1,534 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package soak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+var (
+	// ErrNoOps indicates Run was called without operations.
+	ErrNoOps = errors.New("no operations configured")
+
+	// ErrInvalidOp indicates an operation without a name, function or
+	// positive weight.
+	ErrInvalidOp = errors.New("invalid operation")
+
+	// ErrHarnessRunning indicates the harness is already running.
+	ErrHarnessRunning = errors.New("harness is already running")
+)
+
+// -----------------------------------------------------------------------------
+// Operations
+// -----------------------------------------------------------------------------
+
+// Op is one kind of synthetic agent work, e.g. a scripted query or a file
+// edit.
+type Op struct {
+	// Name identifies the operation in the result. Must be unique.
+	Name string
+
+	// Weight is the relative frequency of the operation. Must be > 0.
+	Weight int
+
+	// Run performs the operation once. iteration is unique across all
+	// workers and increases over the run, so scripts can cycle through
+	// inputs. Run must return promptly once ctx is cancelled.
+	Run func(ctx context.Context, iteration int64) error
+}
+
+// -----------------------------------------------------------------------------
+// Harness Configuration
+// -----------------------------------------------------------------------------
+
+// Thresholds are the pass/fail limits of a soak run. Zero disables a check.
+type Thresholds struct {
+	// MaxHeapGrowthPerHour is the largest allowed slope of the live heap
+	// after warmup, in bytes per hour.
+	MaxHeapGrowthPerHour float64
+
+	// MaxGoroutineLeak is the most goroutines allowed to outlive the run.
+	MaxGoroutineLeak int
+
+	// MaxErrorRate is the largest allowed fraction of failed operations.
+	MaxErrorRate float64
+
+	// MinGenerationRate is the lowest allowed CRS generation rate, in
+	// generations per second, in any sample window after warmup. Requires
+	// a generation probe.
+	MinGenerationRate float64
+
+	// MaxCancelLatency is the longest an operation may take to return
+	// after its context is cancelled.
+	MaxCancelLatency time.Duration
+}
+
+// DefaultThresholds returns limits suitable for nightly CI.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MaxHeapGrowthPerHour: 64 << 20, // 64 MiB/hour
+		MaxGoroutineLeak:     10,
+		MaxErrorRate:         0.01,
+		MaxCancelLatency:     5 * time.Second,
+	}
+}
+
+// Config configures the soak harness.
+type Config struct {
+	// Duration is how long operations run.
+	// Default: 1m
+	Duration time.Duration
+
+	// Concurrency is the number of workers running operations.
+	// Default: 4
+	Concurrency int
+
+	// SampleInterval is how often the process is sampled.
+	// Default: 10s
+	SampleInterval time.Duration
+
+	// Warmup is excluded from the heap growth and generation rate checks,
+	// so caches can fill first.
+	// Default: Duration / 10
+	Warmup time.Duration
+
+	// CancelProbability is the fraction of operations whose context is
+	// cancelled while they run.
+	// Default: 0.1
+	CancelProbability float64
+
+	// MaxCancelDelay bounds the random delay before such a cancellation.
+	// Default: 50ms
+	MaxCancelDelay time.Duration
+
+	// SettleTimeout is how long to wait after the workers stop for
+	// goroutines to exit before counting leaks.
+	// Default: 5s
+	SettleTimeout time.Duration
+
+	// Generation returns the current CRS generation. Optional.
+	Generation func() int64
+
+	// Thresholds are the pass/fail limits.
+	// Default: DefaultThresholds()
+	Thresholds Thresholds
+
+	// Seed makes operation choice and cancellations reproducible.
+	// Default: the current time
+	Seed int64
+
+	// Logger for progress output.
+	Logger *slog.Logger
+}
+
+// DefaultConfig returns sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Duration:          time.Minute,
+		Concurrency:       4,
+		SampleInterval:    10 * time.Second,
+		CancelProbability: 0.1,
+		MaxCancelDelay:    50 * time.Millisecond,
+		SettleTimeout:     5 * time.Second,
+		Thresholds:        DefaultThresholds(),
+		Seed:              time.Now().UnixNano(),
+		Logger:            slog.Default(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Harness Options
+// -----------------------------------------------------------------------------
+
+// Option configures the harness.
+type Option func(*Config)
+
+// WithDuration sets how long operations run.
+func WithDuration(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.Duration = d
+		}
+	}
+}
+
+// WithConcurrency sets the number of workers.
+func WithConcurrency(n int) Option {
+	return func(c *Config) {
+		if n > 0 {
+			c.Concurrency = n
+		}
+	}
+}
+
+// WithSampleInterval sets how often the process is sampled.
+func WithSampleInterval(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.SampleInterval = d
+		}
+	}
+}
+
+// WithWarmup sets the period excluded from the growth and rate checks.
+func WithWarmup(d time.Duration) Option {
+	return func(c *Config) {
+		if d >= 0 {
+			c.Warmup = d
+		}
+	}
+}
+
+// WithCancellation sets the fraction of operations cancelled while they
+// run and the longest delay before cancelling them.
+func WithCancellation(probability float64, maxDelay time.Duration) Option {
+	return func(c *Config) {
+		c.CancelProbability = min(max(probability, 0), 1)
+		if maxDelay > 0 {
+			c.MaxCancelDelay = maxDelay
+		}
+	}
+}
+
+// WithSettleTimeout sets how long to wait for goroutines to exit.
+func WithSettleTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.SettleTimeout = d
+		}
+	}
+}
+
+// WithGenerationProbe sets the function reporting the CRS generation.
+func WithGenerationProbe(fn func() int64) Option {
+	return func(c *Config) {
+		c.Generation = fn
+	}
+}
+
+// WithThresholds sets the pass/fail limits.
+func WithThresholds(t Thresholds) Option {
+	return func(c *Config) {
+		c.Thresholds = t
+	}
+}
+
+// WithSeed makes the run reproducible.
+func WithSeed(seed int64) Option {
+	return func(c *Config) {
+		c.Seed = seed
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		if logger != nil {
+			c.Logger = logger
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Harness
+// -----------------------------------------------------------------------------
+
+// Harness runs soak tests.
+//
+// Description:
+//
+//	Harness runs a weighted mix of operations on Concurrency workers for
+//	Duration, samples heap, goroutines and CRS generation every
+//	SampleInterval, and checks the result against Thresholds.
+//
+// Thread Safety: Safe for concurrent use, but only one Run at a time.
+type Harness struct {
+	config  *Config
+	logger  *slog.Logger
+	running atomic.Bool
+}
+
+// NewHarness creates a soak harness.
+//
+// Inputs:
+//   - opts: Configuration options.
+//
+// Outputs:
+//   - *Harness: The new harness. Never nil.
+func NewHarness(opts ...Option) *Harness {
+	config := DefaultConfig()
+	warmupSet := false
+	for _, opt := range opts {
+		before := config.Warmup
+		opt(config)
+		if config.Warmup != before {
+			warmupSet = true
+		}
+	}
+	if !warmupSet {
+		config.Warmup = config.Duration / 10
+	}
+	return &Harness{config: config, logger: config.Logger}
+}
+
+// run holds the state of one soak run.
+type run struct {
+	config *Config
+	ops    []Op
+	total  int
+
+	iteration atomic.Int64
+	completed atomic.Int64
+
+	mu            sync.Mutex
+	stats         map[string]*OpStats
+	maxCancelWait time.Duration
+}
+
+// Run executes the soak test.
+//
+// Description:
+//
+//	Run blocks for Duration plus the settle time. Operations that fail are
+//	counted, not fatal: the run continues so that a rare error does not
+//	hide a leak.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil. Cancelling it ends
+//     the run early; the result covers the time until then.
+//   - ops: The operations to run. Must not be empty.
+//
+// Outputs:
+//   - *Result: Measurements and threshold violations.
+//   - error: Non-nil if the test could not run.
+//
+// Thread Safety: Safe for concurrent use, but only one Run at a time.
+func (h *Harness) Run(ctx context.Context, ops []Op) (*Result, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("soak harness run: context must not be nil")
+	}
+	if len(ops) == 0 {
+		return nil, ErrNoOps
+	}
+	seen := make(map[string]bool, len(ops))
+	total := 0
+	for _, op := range ops {
+		if op.Name == "" || op.Run == nil || op.Weight <= 0 || seen[op.Name] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidOp, op.Name)
+		}
+		seen[op.Name] = true
+		total += op.Weight
+	}
+	if !h.running.CompareAndSwap(false, true) {
+		return nil, ErrHarnessRunning
+	}
+	defer h.running.Store(false)
+
+	cfg := h.config
+	ctx, span := otel.Tracer("soak").Start(ctx, "soak.Harness.Run",
+		trace.WithAttributes(
+			attribute.String("duration", cfg.Duration.String()),
+			attribute.Int("concurrency", cfg.Concurrency),
+			attribute.Int("ops", len(ops)),
+		),
+	)
+	defer span.End()
+
+	r := &run{
+		config: cfg,
+		ops:    ops,
+		total:  total,
+		stats:  make(map[string]*OpStats, len(ops)),
+	}
+	for _, op := range ops {
+		r.stats[op.Name] = &OpStats{}
+	}
+
+	runtime.GC()
+	goroutinesBefore := runtime.NumGoroutine()
+	start := time.Now()
+
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	// Sample until the workers stop
+	samples := make([]Sample, 0, int(cfg.Duration/cfg.SampleInterval)+2)
+	var samplesMu sync.Mutex
+	samplerDone := make(chan struct{})
+	go func() {
+		defer close(samplerDone)
+		ticker := time.NewTicker(cfg.SampleInterval)
+		defer ticker.Stop()
+		for {
+			s := r.sample(start)
+			samplesMu.Lock()
+			samples = append(samples, s)
+			samplesMu.Unlock()
+			h.logger.Debug("soak sample",
+				slog.Duration("elapsed", s.Elapsed),
+				slog.Uint64("heap_bytes", s.HeapBytes),
+				slog.Int("goroutines", s.Goroutines),
+				slog.Int64("ops", s.Ops),
+			)
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			r.work(runCtx, rand.New(rand.NewSource(cfg.Seed+int64(worker))))
+		}(w)
+	}
+	wg.Wait()
+	cancel()
+	<-samplerDone
+
+	// Final sample once the workers are gone, then wait for stragglers
+	elapsed := time.Since(start)
+	samples = append(samples, r.sample(start))
+	goroutinesAfter := settle(goroutinesBefore+cfg.Thresholds.MaxGoroutineLeak, cfg.SettleTimeout)
+
+	result := r.result(elapsed, samples, goroutinesBefore, goroutinesAfter)
+	span.SetAttributes(
+		attribute.Int64("total_ops", result.TotalOps),
+		attribute.Int("violations", len(result.Violations)),
+	)
+	if !result.Passed() {
+		span.SetStatus(codes.Error, "soak thresholds violated")
+	}
+	h.logger.Info("soak run finished",
+		slog.Duration("duration", elapsed),
+		slog.Int64("ops", result.TotalOps),
+		slog.Float64("heap_growth_per_hour", result.HeapGrowthPerHour),
+		slog.Int("goroutine_leak", result.GoroutineLeak),
+		slog.Bool("passed", result.Passed()),
+	)
+	return result, nil
+}
+
+// work runs operations until ctx is done.
+func (r *run) work(ctx context.Context, rng *rand.Rand) {
+	for ctx.Err() == nil {
+		op := r.pick(rng)
+		r.runOp(ctx, op, rng)
+	}
+}
+
+// pick chooses an operation by weight.
+func (r *run) pick(rng *rand.Rand) *Op {
+	n := rng.Intn(r.total)
+	for i := range r.ops {
+		n -= r.ops[i].Weight
+		if n < 0 {
+			return &r.ops[i]
+		}
+	}
+	return &r.ops[len(r.ops)-1]
+}
+
+// runOp runs one operation, possibly cancelling it part way, and records
+// the outcome.
+func (r *run) runOp(ctx context.Context, op *Op, rng *rand.Rand) {
+	opCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cancelledAt atomic.Int64
+	if rng.Float64() < r.config.CancelProbability {
+		delay := time.Duration(rng.Int63n(int64(r.config.MaxCancelDelay) + 1))
+		timer := time.AfterFunc(delay, func() {
+			cancelledAt.Store(time.Now().UnixNano())
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	iteration := r.iteration.Add(1)
+	start := time.Now()
+	err := op.Run(opCtx, iteration)
+	end := time.Now()
+	r.completed.Add(1)
+
+	cancelled := cancelledAt.Load()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.stats[op.Name]
+	s.Count++
+	latency := end.Sub(start)
+	s.TotalLatency += latency
+	s.MaxLatency = max(s.MaxLatency, latency)
+	switch {
+	case cancelled != 0:
+		s.Cancelled++
+		r.maxCancelWait = max(r.maxCancelWait, end.Sub(time.Unix(0, cancelled)))
+	case err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		// The run ended under the operation
+	case err != nil:
+		s.Errors++
+		s.LastError = err.Error()
+	}
+}
+
+// sample measures the process. It forces a GC so HeapBytes is the live heap.
+func (r *run) sample(start time.Time) Sample {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s := Sample{
+		Elapsed:     time.Since(start),
+		HeapBytes:   m.HeapAlloc,
+		HeapObjects: m.HeapObjects,
+		Goroutines:  runtime.NumGoroutine(),
+		Ops:         r.completed.Load(),
+	}
+	if r.config.Generation != nil {
+		s.Generation = r.config.Generation()
+	}
+	return s
+}
+
+// settle waits up to timeout for the goroutine count to drop to target and
+// returns the final count.
+func settle(target int, timeout time.Duration) int {
+	deadline := time.Now().Add(timeout)
+	for {
+		runtime.GC()
+		n := runtime.NumGoroutine()
+		if n <= target || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package soak
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// shortHarness returns a harness for a sub-second run.
+func shortHarness(opts ...Option) *Harness {
+	base := []Option{
+		WithDuration(300 * time.Millisecond),
+		WithSampleInterval(50 * time.Millisecond),
+		WithSettleTimeout(500 * time.Millisecond),
+		WithSeed(1),
+	}
+	return NewHarness(append(base, opts...)...)
+}
+
+// sleepOp returns an operation that sleeps for d or until cancelled.
+func sleepOp(name string, weight int, d time.Duration) Op {
+	return Op{Name: name, Weight: weight, Run: func(ctx context.Context, _ int64) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+			return nil
+		}
+	}}
+}
+
+// -----------------------------------------------------------------------------
+// Harness Tests
+// -----------------------------------------------------------------------------
+
+func TestHarness_Run_Validation(t *testing.T) {
+	h := shortHarness()
+	tests := []struct {
+		name string
+		ops  []Op
+		want error
+	}{
+		{"no ops", nil, ErrNoOps},
+		{"zero weight", []Op{sleepOp("a", 0, time.Millisecond)}, ErrInvalidOp},
+		{"no name", []Op{sleepOp("", 1, time.Millisecond)}, ErrInvalidOp},
+		{"duplicate", []Op{sleepOp("a", 1, time.Millisecond), sleepOp("a", 1, time.Millisecond)}, ErrInvalidOp},
+		{"no func", []Op{{Name: "a", Weight: 1}}, ErrInvalidOp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Run(context.Background(), tt.ops); !errors.Is(err, tt.want) {
+				t.Errorf("Run() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHarness_Run_Healthy(t *testing.T) {
+	var gen atomic.Int64
+	h := shortHarness(
+		WithConcurrency(3),
+		WithCancellation(0.2, 2*time.Millisecond),
+		WithGenerationProbe(gen.Load),
+	)
+	result, err := h.Run(context.Background(), []Op{
+		{Name: "query", Weight: 3, Run: func(ctx context.Context, _ int64) error {
+			gen.Add(1)
+			return nil
+		}},
+		sleepOp("edit", 1, 5*time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Passed() {
+		t.Errorf("Passed() = false, violations: %v", result.Violations)
+	}
+	if result.Ops["query"].Count == 0 || result.Ops["edit"].Count == 0 {
+		t.Errorf("ops not all run: query=%d edit=%d", result.Ops["query"].Count, result.Ops["edit"].Count)
+	}
+	if result.Cancellations == 0 {
+		t.Error("Cancellations = 0, want some with probability 0.2")
+	}
+	if result.TotalErrors != 0 {
+		t.Errorf("TotalErrors = %d, cancelled ops should not count", result.TotalErrors)
+	}
+	if len(result.Samples) < 3 {
+		t.Errorf("len(Samples) = %d, want at least 3", len(result.Samples))
+	}
+	if result.GenerationRate <= 0 {
+		t.Errorf("GenerationRate = %v, want > 0", result.GenerationRate)
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if _, ok := decoded["heap_growth_per_hour_bytes"]; !ok {
+		t.Error("JSON report is missing heap_growth_per_hour_bytes")
+	}
+}
+
+func TestHarness_Run_DetectsGoroutineLeak(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	h := shortHarness(WithThresholds(Thresholds{MaxGoroutineLeak: 5}))
+	result, err := h.Run(context.Background(), []Op{
+		{Name: "leak", Weight: 1, Run: func(ctx context.Context, _ int64) error {
+			go func() { <-block }()
+			time.Sleep(time.Millisecond)
+			return nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Passed() || result.GoroutineLeak <= 5 {
+		t.Errorf("leak not detected: leak=%d violations=%v", result.GoroutineLeak, result.Violations)
+	}
+}
+
+func TestHarness_Run_DetectsErrorRate(t *testing.T) {
+	h := shortHarness(WithCancellation(0, 0))
+	result, err := h.Run(context.Background(), []Op{
+		{Name: "flaky", Weight: 1, Run: func(ctx context.Context, i int64) error {
+			time.Sleep(time.Millisecond)
+			if i%2 == 0 {
+				return fmt.Errorf("failure %d", i)
+			}
+			return nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if math.Abs(result.ErrorRate-0.5) > 0.1 {
+		t.Errorf("ErrorRate = %v, want about 0.5", result.ErrorRate)
+	}
+	if result.Passed() || !strings.Contains(strings.Join(result.Violations, "\n"), "error rate") {
+		t.Errorf("Violations = %v, want an error rate violation", result.Violations)
+	}
+	if !strings.HasPrefix(result.Ops["flaky"].LastError, "failure") {
+		t.Errorf("LastError = %q", result.Ops["flaky"].LastError)
+	}
+}
+
+func TestHarness_Run_SlowCancellation(t *testing.T) {
+	h := shortHarness(
+		WithCancellation(1, time.Millisecond),
+		WithThresholds(Thresholds{MaxCancelLatency: 10 * time.Millisecond}),
+	)
+	result, err := h.Run(context.Background(), []Op{
+		{Name: "stubborn", Weight: 1, Run: func(ctx context.Context, _ int64) error {
+			time.Sleep(30 * time.Millisecond) // ignores ctx
+			return nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.MaxCancelLatency < 10*time.Millisecond || result.Passed() {
+		t.Errorf("slow cancellation not detected: latency=%s violations=%v",
+			result.MaxCancelLatency, result.Violations)
+	}
+}
+
+func TestHarness_Run_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	h := NewHarness(WithDuration(time.Hour), WithSampleInterval(10*time.Millisecond))
+	start := time.Now()
+	result, err := h.Run(ctx, []Op{sleepOp("op", 1, time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() took %s after context cancellation", elapsed)
+	}
+	if result.TotalErrors != 0 {
+		t.Errorf("TotalErrors = %d, ops ended by the run should not count", result.TotalErrors)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Analysis Tests
+// -----------------------------------------------------------------------------
+
+func TestHeapSlope(t *testing.T) {
+	samples := make([]Sample, 10)
+	for i := range samples {
+		samples[i] = Sample{
+			Elapsed:   time.Duration(i) * time.Second,
+			HeapBytes: uint64(1000 + 100*i),
+		}
+	}
+	if got := heapSlope(samples); math.Abs(got-100) > 1e-9 {
+		t.Errorf("heapSlope() = %v, want 100", got)
+	}
+	if got := heapSlope(samples[:1]); got != 0 {
+		t.Errorf("heapSlope(one sample) = %v, want 0", got)
+	}
+}
+
+func TestGenerationRates(t *testing.T) {
+	samples := []Sample{
+		{Elapsed: 0, Generation: 0},
+		{Elapsed: time.Second, Generation: 10},
+		{Elapsed: 2 * time.Second, Generation: 12},
+		{Elapsed: 3 * time.Second, Generation: 30},
+	}
+	mean, lowest := generationRates(samples)
+	if mean != 10 || lowest != 2 {
+		t.Errorf("generationRates() = %v, %v, want 10, 2", mean, lowest)
+	}
+}
+
+func TestAfterWarmup(t *testing.T) {
+	samples := []Sample{{Elapsed: 0}, {Elapsed: time.Second}, {Elapsed: 2 * time.Second}}
+	if got := afterWarmup(samples, time.Second); len(got) != 2 {
+		t.Errorf("afterWarmup(1s) kept %d samples, want 2", len(got))
+	}
+	if got := afterWarmup(samples, time.Hour); len(got) != 2 {
+		t.Errorf("afterWarmup(1h) kept %d samples, want the last 2", len(got))
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Nightly Soak
+// -----------------------------------------------------------------------------
+
+// TestSoak_Nightly runs a synthetic agent session: scripted queries that
+// record proofs in a CRS, snapshot reads, file edits, and planner runs
+// that honour cancellation. Set SOAK_DURATION to run it and SOAK_REPORT
+// to write the JSON report.
+func TestSoak_Nightly(t *testing.T) {
+	durationEnv := os.Getenv("SOAK_DURATION")
+	if durationEnv == "" {
+		t.Skip("SOAK_DURATION not set")
+	}
+	duration, err := time.ParseDuration(durationEnv)
+	if err != nil {
+		t.Fatalf("invalid SOAK_DURATION %q: %v", durationEnv, err)
+	}
+
+	c := crs.New(nil)
+	dir := t.TempDir()
+	const nodes = 512
+	const files = 64
+
+	ops := []Op{
+		{Name: "query", Weight: 6, Run: func(ctx context.Context, i int64) error {
+			node := fmt.Sprintf("pkg/soak.Func%d", i%nodes)
+			_, err := c.Apply(ctx, crs.NewProofDelta(crs.SignalSourceHard, map[string]crs.ProofNumber{
+				node: {Proof: uint64(i % 100), Disproof: 1, Status: crs.ProofStatusExpanded, Source: crs.SignalSourceHard},
+			}))
+			return err
+		}},
+		{Name: "snapshot", Weight: 3, Run: func(ctx context.Context, _ int64) error {
+			if c.Snapshot().ProofIndex().Size() > nodes {
+				return fmt.Errorf("proof index grew past %d nodes", nodes)
+			}
+			return nil
+		}},
+		{Name: "edit", Weight: 2, Run: func(ctx context.Context, i int64) error {
+			path := filepath.Join(dir, fmt.Sprintf("file_%d.go", i%files))
+			body := fmt.Sprintf("package soak\n\nconst iteration = %d\n", i)
+			return os.WriteFile(path, []byte(body), 0o644)
+		}},
+		{Name: "plan", Weight: 1, Run: func(ctx context.Context, _ int64) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return nil
+			}
+		}},
+	}
+
+	sample := max(duration/120, time.Second)
+	h := NewHarness(
+		WithDuration(duration),
+		WithSampleInterval(sample),
+		WithGenerationProbe(c.Generation),
+	)
+	result, err := h.Run(context.Background(), ops)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report := os.Getenv("SOAK_REPORT"); report != "" {
+		f, err := os.Create(report)
+		if err != nil {
+			t.Fatalf("create report: %v", err)
+		}
+		defer f.Close()
+		if err := result.WriteJSON(f); err != nil {
+			t.Fatalf("write report: %v", err)
+		}
+	}
+
+	t.Logf("ops=%d errors=%d cancelled=%d heap=%.1f MiB/h leak=%d gen=%.1f/s",
+		result.TotalOps, result.TotalErrors, result.Cancellations,
+		result.HeapGrowthPerHour/(1<<20), result.GoroutineLeak, result.GenerationRate)
+	for _, v := range result.Violations {
+		t.Error(v)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package soak
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Result Types
+// -----------------------------------------------------------------------------
+
+// OpStats are the outcomes of one operation.
+type OpStats struct {
+	// Count is how many times the operation ran.
+	Count int64 `json:"count"`
+
+	// Errors is how many runs failed, not counting cancelled runs.
+	Errors int64 `json:"errors"`
+
+	// Cancelled is how many runs were cancelled by the harness.
+	Cancelled int64 `json:"cancelled"`
+
+	// TotalLatency is the summed duration of all runs.
+	TotalLatency time.Duration `json:"total_latency_ns"`
+
+	// MaxLatency is the slowest run.
+	MaxLatency time.Duration `json:"max_latency_ns"`
+
+	// LastError is the message of the most recent failure.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Sample is one measurement of the process.
+type Sample struct {
+	// Elapsed is the time since the run started.
+	Elapsed time.Duration `json:"elapsed_ns"`
+
+	// HeapBytes is the live heap after a forced GC.
+	HeapBytes uint64 `json:"heap_bytes"`
+
+	// HeapObjects is the number of live heap objects.
+	HeapObjects uint64 `json:"heap_objects"`
+
+	// Goroutines is the number of goroutines.
+	Goroutines int `json:"goroutines"`
+
+	// Generation is the CRS generation, or 0 without a probe.
+	Generation int64 `json:"generation"`
+
+	// Ops is the number of completed operations so far.
+	Ops int64 `json:"ops"`
+}
+
+// Result contains the measurements of a soak run.
+type Result struct {
+	// Duration is how long operations ran.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Seed reproduces the operation mix and cancellations.
+	Seed int64 `json:"seed"`
+
+	// Ops are the outcomes per operation name.
+	Ops map[string]*OpStats `json:"ops"`
+
+	// TotalOps, TotalErrors and Cancellations sum Ops.
+	TotalOps      int64 `json:"total_ops"`
+	TotalErrors   int64 `json:"total_errors"`
+	Cancellations int64 `json:"cancellations"`
+
+	// ErrorRate is TotalErrors over the operations that were not cancelled.
+	ErrorRate float64 `json:"error_rate"`
+
+	// HeapStart and HeapEnd are the live heap at the first sample after
+	// warmup and at the last sample.
+	HeapStart uint64 `json:"heap_start_bytes"`
+	HeapEnd   uint64 `json:"heap_end_bytes"`
+
+	// HeapGrowthPerHour is the slope of the live heap after warmup, in
+	// bytes per hour.
+	HeapGrowthPerHour float64 `json:"heap_growth_per_hour_bytes"`
+
+	// GoroutinesBefore and GoroutinesAfter are counted before the run and
+	// after the workers stopped and goroutines settled.
+	GoroutinesBefore int `json:"goroutines_before"`
+	GoroutinesAfter  int `json:"goroutines_after"`
+
+	// GoroutineLeak is GoroutinesAfter minus GoroutinesBefore.
+	GoroutineLeak int `json:"goroutine_leak"`
+
+	// GenerationRate is the mean CRS generation rate after warmup, and
+	// MinGenerationRate the lowest rate of any sample window, both in
+	// generations per second. Zero without a generation probe.
+	GenerationRate    float64 `json:"generation_rate"`
+	MinGenerationRate float64 `json:"min_generation_rate"`
+
+	// MaxCancelLatency is the slowest return after cancellation.
+	MaxCancelLatency time.Duration `json:"max_cancel_latency_ns"`
+
+	// Samples are the periodic measurements.
+	Samples []Sample `json:"samples"`
+
+	// Violations describes each failed threshold.
+	Violations []string `json:"violations"`
+}
+
+// Passed returns true if no threshold was violated.
+func (r *Result) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// WriteJSON writes the result as indented JSON, for CI artifacts.
+//
+// Inputs:
+//   - w: Destination. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if encoding or writing failed.
+func (r *Result) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// -----------------------------------------------------------------------------
+// Analysis
+// -----------------------------------------------------------------------------
+
+// result computes the measurements and checks the thresholds.
+func (r *run) result(elapsed time.Duration, samples []Sample, before, after int) *Result {
+	cfg := r.config
+	res := &Result{
+		Duration:         elapsed,
+		Seed:             cfg.Seed,
+		Ops:              r.stats,
+		GoroutinesBefore: before,
+		GoroutinesAfter:  after,
+		GoroutineLeak:    after - before,
+		MaxCancelLatency: r.maxCancelWait,
+		Samples:          samples,
+		Violations:       []string{},
+	}
+	for _, s := range r.stats {
+		res.TotalOps += s.Count
+		res.TotalErrors += s.Errors
+		res.Cancellations += s.Cancelled
+	}
+	if n := res.TotalOps - res.Cancellations; n > 0 {
+		res.ErrorRate = float64(res.TotalErrors) / float64(n)
+	}
+
+	steady := afterWarmup(samples, cfg.Warmup)
+	if len(steady) > 0 {
+		res.HeapStart = steady[0].HeapBytes
+		res.HeapEnd = steady[len(steady)-1].HeapBytes
+	}
+	res.HeapGrowthPerHour = heapSlope(steady) * float64(time.Hour/time.Second)
+	if cfg.Generation != nil {
+		res.GenerationRate, res.MinGenerationRate = generationRates(steady)
+	}
+
+	t := cfg.Thresholds
+	if t.MaxHeapGrowthPerHour > 0 && len(steady) >= 3 && res.HeapGrowthPerHour > t.MaxHeapGrowthPerHour {
+		res.violate("heap grows %.1f MiB/hour (limit %.1f MiB/hour)",
+			res.HeapGrowthPerHour/(1<<20), t.MaxHeapGrowthPerHour/(1<<20))
+	}
+	if t.MaxGoroutineLeak > 0 && res.GoroutineLeak > t.MaxGoroutineLeak {
+		res.violate("%d goroutines leaked (limit %d)", res.GoroutineLeak, t.MaxGoroutineLeak)
+	}
+	if t.MaxErrorRate > 0 && res.ErrorRate > t.MaxErrorRate {
+		res.violate("error rate %.4f (limit %.4f)", res.ErrorRate, t.MaxErrorRate)
+	}
+	if t.MinGenerationRate > 0 && cfg.Generation != nil && len(steady) >= 2 && res.MinGenerationRate < t.MinGenerationRate {
+		res.violate("CRS generation rate fell to %.2f/s (limit %.2f/s)", res.MinGenerationRate, t.MinGenerationRate)
+	}
+	if t.MaxCancelLatency > 0 && res.MaxCancelLatency > t.MaxCancelLatency {
+		res.violate("operation took %s to return after cancellation (limit %s)", res.MaxCancelLatency, t.MaxCancelLatency)
+	}
+	return res
+}
+
+// violate records a failed threshold.
+func (r *Result) violate(format string, args ...any) {
+	r.Violations = append(r.Violations, fmt.Sprintf(format, args...))
+}
+
+// afterWarmup returns the samples taken after warmup. If warmup covers
+// every sample, the last two are returned so short runs are still checked.
+func afterWarmup(samples []Sample, warmup time.Duration) []Sample {
+	for i, s := range samples {
+		if s.Elapsed >= warmup {
+			return samples[i:]
+		}
+	}
+	return samples[max(len(samples)-2, 0):]
+}
+
+// heapSlope returns the least-squares slope of the live heap over time, in
+// bytes per second.
+func heapSlope(samples []Sample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.Elapsed.Seconds()
+		y := float64(s.HeapBytes)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+// generationRates returns the mean generation rate over the samples and
+// the lowest rate between consecutive samples, in generations per second.
+func generationRates(samples []Sample) (mean, lowest float64) {
+	if len(samples) < 2 {
+		return 0, 0
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	if span := (last.Elapsed - first.Elapsed).Seconds(); span > 0 {
+		mean = float64(last.Generation-first.Generation) / span
+	}
+	lowest = math.Inf(1)
+	for i := 1; i < len(samples); i++ {
+		window := (samples[i].Elapsed - samples[i-1].Elapsed).Seconds()
+		if window <= 0 {
+			continue
+		}
+		lowest = min(lowest, float64(samples[i].Generation-samples[i-1].Generation)/window)
+	}
+	if math.IsInf(lowest, 1) {
+		lowest = mean
+	}
+	return mean, lowest
+}
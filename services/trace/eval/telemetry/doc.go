@@ -80,6 +80,27 @@
 //	})
 //	defer shutdown(ctx)
 //
+// # File Sink
+//
+// For air-gapped deployments without Prometheus or an OTLP collector, the
+// JSONL sink writes one JSON record per line to local files, rotating by
+// size and gzipping old files:
+//
+//	config := telemetry.DefaultJSONLConfig()
+//	config.Dir = "/var/lib/aleutian/telemetry"
+//	jsonlSink, err := telemetry.NewJSONLSink(config)
+//
+// # Exemplars
+//
+// The Prometheus sink tags duration and latency observations with the
+// trace and span ID of the recording context as OpenMetrics exemplars, so
+// a spike in code_buddy_eval_benchmark_duration_seconds links to the Jaeger
+// trace behind it. Exemplars are only exposed in the OpenMetrics format:
+//
+//	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+//	    EnableOpenMetrics: true,
+//	}))
+//
 // # Thread Safety
 //
 // All Sink implementations are safe for concurrent use from multiple goroutines.
@@ -0,0 +1,477 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Configuration
+// -----------------------------------------------------------------------------
+
+// JSONLConfig configures the JSONL file sink.
+//
+// Description:
+//
+//	JSONLConfig specifies where records are written and when the active
+//	file is rotated.
+//
+// Thread Safety: Immutable after creation; safe for concurrent read access.
+type JSONLConfig struct {
+	// Dir is the directory for the telemetry files. Created if missing.
+	// Required.
+	Dir string
+
+	// FilePrefix names the files: <prefix>.jsonl is the active file and
+	// <prefix>-<timestamp>.jsonl[.gz] are rotated ones.
+	// Default: "telemetry"
+	FilePrefix string
+
+	// MaxFileBytes is the size at which the active file is rotated.
+	// Default: 64 MiB
+	MaxFileBytes int64
+
+	// MaxBackups is the number of rotated files to keep. Older files are
+	// deleted on rotation. Zero keeps all of them.
+	// Default: 10
+	MaxBackups int
+
+	// Compress gzips rotated files.
+	// Default: true
+	Compress bool
+}
+
+// DefaultJSONLConfig returns a configuration with sensible defaults.
+//
+// Description:
+//
+//	Returns a JSONLConfig with default prefix, rotation size, retention
+//	and compression. Dir must still be set.
+//
+// Outputs:
+//   - *JSONLConfig: Configuration with defaults applied.
+//
+// Thread Safety: Stateless function; safe for concurrent use.
+//
+// Example:
+//
+//	config := telemetry.DefaultJSONLConfig()
+//	config.Dir = "/var/lib/aleutian/telemetry"
+//	sink, err := telemetry.NewJSONLSink(config)
+func DefaultJSONLConfig() *JSONLConfig {
+	return &JSONLConfig{
+		FilePrefix:   "telemetry",
+		MaxFileBytes: 64 << 20,
+		MaxBackups:   10,
+		Compress:     true,
+	}
+}
+
+// Validate checks that the configuration is valid.
+//
+// Description:
+//
+//	Validates that the directory is set and limits are not negative.
+//
+// Outputs:
+//   - error: Non-nil if configuration is invalid.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *JSONLConfig) Validate() error {
+	if c.Dir == "" {
+		return errors.New("dir is required")
+	}
+	if strings.ContainsAny(c.FilePrefix, `/\`) {
+		return errors.New("file prefix must not contain path separators")
+	}
+	if c.MaxFileBytes < 0 {
+		return errors.New("max file bytes must not be negative")
+	}
+	if c.MaxBackups < 0 {
+		return errors.New("max backups must not be negative")
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// JSONL Sink
+// -----------------------------------------------------------------------------
+
+// Record types written by JSONLSink.
+const (
+	JSONLRecordBenchmark  = "benchmark"
+	JSONLRecordComparison = "comparison"
+	JSONLRecordError      = "error"
+)
+
+// JSONLRecord is one line of a JSONL telemetry file.
+//
+// Description:
+//
+//	Type is one of the JSONLRecord* constants and Data is the BenchmarkData,
+//	ComparisonData or ErrorData that was recorded, with durations in
+//	nanoseconds.
+type JSONLRecord struct {
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// JSONLSink writes telemetry as JSON lines to rotating local files.
+//
+// Description:
+//
+//	JSONLSink is for deployments without Prometheus or an OTLP collector,
+//	such as air-gapped installs. Each record is one JSON object per line in
+//	<Dir>/<FilePrefix>.jsonl. When the file would grow past MaxFileBytes it
+//	is renamed with a timestamp, optionally gzipped, and a new file is
+//	started; rotated files beyond MaxBackups are deleted, oldest first.
+//
+// Thread Safety: Safe for concurrent use.
+//
+// Example:
+//
+//	config := telemetry.DefaultJSONLConfig()
+//	config.Dir = "/var/lib/aleutian/telemetry"
+//	sink, err := telemetry.NewJSONLSink(config)
+//	if err != nil {
+//	    return fmt.Errorf("create jsonl sink: %w", err)
+//	}
+//	defer sink.Close()
+type JSONLSink struct {
+	config *JSONLConfig
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+	closed bool
+
+	// now is the clock used for default timestamps and rotated names.
+	now func() time.Time
+}
+
+// NewJSONLSink creates a new JSONL file sink.
+//
+// Description:
+//
+//	Creates the directory if needed and opens the active file for
+//	appending, continuing an existing file from a previous run.
+//
+// Inputs:
+//   - config: JSONL configuration. Must not be nil.
+//
+// Outputs:
+//   - *JSONLSink: The created sink. Never nil on success.
+//   - error: Non-nil if configuration is invalid or the file cannot be opened.
+//
+// Thread Safety: The returned sink is safe for concurrent use.
+//
+// Limitations:
+//   - Rotation and compression run inline in the recording call that
+//     triggers them.
+//   - A single record larger than MaxFileBytes is still written whole.
+//
+// Assumptions:
+//   - No other process writes to the same directory and prefix.
+func NewJSONLSink(config *JSONLConfig) (*JSONLSink, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, errors.Join(ErrInvalidConfig, err)
+	}
+
+	cfg := *config // Copy to avoid mutating input
+	defaults := DefaultJSONLConfig()
+	if cfg.FilePrefix == "" {
+		cfg.FilePrefix = defaults.FilePrefix
+	}
+	if cfg.MaxFileBytes == 0 {
+		cfg.MaxFileBytes = defaults.MaxFileBytes
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create telemetry dir: %w", err)
+	}
+
+	sink := &JSONLSink{config: &cfg, now: time.Now}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Path returns the path of the active file.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *JSONLSink) Path() string {
+	return filepath.Join(s.config.Dir, s.config.FilePrefix+".jsonl")
+}
+
+// RecordBenchmark appends a benchmark record.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - data: Benchmark data to record. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if the sink is closed, inputs are invalid, or the
+//     write fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *JSONLSink) RecordBenchmark(ctx context.Context, data *BenchmarkData) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if data == nil {
+		return ErrNilData
+	}
+	return s.write(JSONLRecordBenchmark, data.Timestamp, data)
+}
+
+// RecordComparison appends a comparison record.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - data: Comparison data to record. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if the sink is closed, inputs are invalid, or the
+//     write fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *JSONLSink) RecordComparison(ctx context.Context, data *ComparisonData) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if data == nil {
+		return ErrNilData
+	}
+	return s.write(JSONLRecordComparison, data.Timestamp, data)
+}
+
+// RecordError appends an error record.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//   - data: Error data to record. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if the sink is closed, inputs are invalid, or the
+//     write fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *JSONLSink) RecordError(ctx context.Context, data *ErrorData) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if data == nil {
+		return ErrNilData
+	}
+	return s.write(JSONLRecordError, data.Timestamp, data)
+}
+
+// Flush writes buffered records to disk.
+//
+// Description:
+//
+//	Flushes the write buffer and syncs the active file.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Must not be nil.
+//
+// Outputs:
+//   - error: Non-nil if the sink is closed or the flush fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *JSONLSink) Flush(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	if err := s.writer.Flush(); err != nil {
+		return fmt.Errorf("flush telemetry file: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// Close flushes and closes the active file.
+//
+// Outputs:
+//   - error: Non-nil if the final flush or close fails.
+//
+// Thread Safety: Safe for concurrent use. Idempotent.
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.closeFile()
+}
+
+// write appends one record, rotating first if it would not fit.
+func (s *JSONLSink) write(recordType string, timestamp time.Time, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal %s record: %w", recordType, err)
+	}
+	if timestamp.IsZero() {
+		timestamp = s.now()
+	}
+	line, err := json.Marshal(JSONLRecord{Type: recordType, Timestamp: timestamp.UTC(), Data: payload})
+	if err != nil {
+		return fmt.Errorf("marshal %s record: %w", recordType, err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+
+	if s.size > 0 && s.size+int64(len(line)) > s.config.MaxFileBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.writer.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write telemetry record: %w", err)
+	}
+	return nil
+}
+
+// open opens the active file for appending. Caller holds mu or owns s.
+func (s *JSONLSink) open() error {
+	file, err := os.OpenFile(s.Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open telemetry file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat telemetry file: %w", err)
+	}
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	s.size = info.Size()
+	return nil
+}
+
+// closeFile flushes and closes the active file. Caller holds mu.
+func (s *JSONLSink) closeFile() error {
+	flushErr := s.writer.Flush()
+	closeErr := s.file.Close()
+	return errors.Join(flushErr, closeErr)
+}
+
+// rotate moves the active file aside, compresses it if configured,
+// prunes old backups and opens a new active file. Caller holds mu.
+func (s *JSONLSink) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return fmt.Errorf("close telemetry file for rotation: %w", err)
+	}
+
+	stamp := s.now().UTC().Format("20060102T150405.000000000")
+	rotated := filepath.Join(s.config.Dir, s.config.FilePrefix+"-"+stamp+".jsonl")
+	if err := os.Rename(s.Path(), rotated); err != nil {
+		// Keep writing to the old file rather than losing records.
+		return errors.Join(fmt.Errorf("rotate telemetry file: %w", err), s.open())
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	var errs []error
+	if s.config.Compress {
+		if err := gzipFile(rotated); err != nil {
+			errs = append(errs, fmt.Errorf("compress rotated telemetry file: %w", err))
+		}
+	}
+	if err := s.pruneBackups(); err != nil {
+		errs = append(errs, err)
+	}
+	// The new file is open, so the record can still be written.
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// pruneBackups deletes the oldest rotated files beyond MaxBackups.
+func (s *JSONLSink) pruneBackups() error {
+	if s.config.MaxBackups == 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(s.config.Dir, s.config.FilePrefix+"-*.jsonl*"))
+	if err != nil {
+		return fmt.Errorf("list rotated telemetry files: %w", err)
+	}
+	if len(matches) <= s.config.MaxBackups {
+		return nil
+	}
+	// Timestamps sort lexically, so name order is age order.
+	sort.Strings(matches)
+	var errs []error
+	for _, path := range matches[:len(matches)-s.config.MaxBackups] {
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// gzipFile compresses path to path.gz and removes the original.
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, copyErr := io.Copy(zw, src)
+	if err := errors.Join(copyErr, zw.Close(), dst.Close(), src.Close()); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Verify interface compliance at compile time.
+var _ Sink = (*JSONLSink)(nil)
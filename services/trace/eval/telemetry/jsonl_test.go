@@ -0,0 +1,277 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readJSONL reads the records of a JSONL file, gzipped or not.
+func readJSONL(t *testing.T, path string) []JSONLRecord {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var scanner *bufio.Scanner
+	if filepath.Ext(path) == ".gz" {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip %s: %v", path, err)
+		}
+		scanner = bufio.NewScanner(zr)
+	} else {
+		scanner = bufio.NewScanner(f)
+	}
+
+	var records []JSONLRecord
+	for scanner.Scan() {
+		var r JSONLRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// -----------------------------------------------------------------------------
+// Configuration Tests
+// -----------------------------------------------------------------------------
+
+func TestJSONLConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*JSONLConfig)
+		wantErr bool
+	}{
+		{"valid", func(c *JSONLConfig) {}, false},
+		{"missing dir", func(c *JSONLConfig) { c.Dir = "" }, true},
+		{"prefix with separator", func(c *JSONLConfig) { c.FilePrefix = "a/b" }, true},
+		{"negative size", func(c *JSONLConfig) { c.MaxFileBytes = -1 }, true},
+		{"negative backups", func(c *JSONLConfig) { c.MaxBackups = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultJSONLConfig()
+			config.Dir = t.TempDir()
+			tt.modify(config)
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewJSONLSink(nil); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewJSONLSink(nil) error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Recording Tests
+// -----------------------------------------------------------------------------
+
+func TestJSONLSink_Records(t *testing.T) {
+	config := DefaultJSONLConfig()
+	config.Dir = filepath.Join(t.TempDir(), "nested")
+	sink, err := NewJSONLSink(config)
+	if err != nil {
+		t.Fatalf("NewJSONLSink: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := sink.RecordBenchmark(ctx, createTestBenchmarkData()); err != nil {
+		t.Fatalf("RecordBenchmark: %v", err)
+	}
+	if err := sink.RecordComparison(ctx, createTestComparisonData()); err != nil {
+		t.Fatalf("RecordComparison: %v", err)
+	}
+	if err := sink.RecordError(ctx, &ErrorData{Component: "graph", Message: "boom"}); err != nil {
+		t.Fatalf("RecordError: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	records := readJSONL(t, sink.Path())
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	for i, want := range []string{JSONLRecordBenchmark, JSONLRecordComparison, JSONLRecordError} {
+		if records[i].Type != want {
+			t.Errorf("record %d type = %s, want %s", i, records[i].Type, want)
+		}
+	}
+
+	var bench BenchmarkData
+	if err := json.Unmarshal(records[0].Data, &bench); err != nil {
+		t.Fatal(err)
+	}
+	if want := createTestBenchmarkData(); bench.Name != want.Name || bench.Latency.P99 != want.Latency.P99 {
+		t.Errorf("benchmark round trip = %+v", bench)
+	}
+	if records[2].Timestamp.IsZero() {
+		t.Error("missing timestamp should default to now")
+	}
+
+	t.Run("appends across restarts", func(t *testing.T) {
+		sink, err := NewJSONLSink(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sink.RecordError(ctx, &ErrorData{Message: "again"})
+		sink.Close()
+		if got := len(readJSONL(t, sink.Path())); got != 4 {
+			t.Errorf("got %d records, want 4", got)
+		}
+	})
+
+	t.Run("validation and close", func(t *testing.T) {
+		if err := sink.RecordBenchmark(nil, createTestBenchmarkData()); !errors.Is(err, ErrNilContext) {
+			t.Errorf("nil context: %v", err)
+		}
+		if err := sink.RecordError(ctx, nil); !errors.Is(err, ErrNilData) {
+			t.Errorf("nil data: %v", err)
+		}
+		if err := sink.RecordError(ctx, &ErrorData{}); !errors.Is(err, ErrSinkClosed) {
+			t.Errorf("after close: %v", err)
+		}
+		if err := sink.Flush(ctx); !errors.Is(err, ErrSinkClosed) {
+			t.Errorf("flush after close: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Errorf("second Close: %v", err)
+		}
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Rotation Tests
+// -----------------------------------------------------------------------------
+
+func TestJSONLSink_Rotation(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		name := "plain"
+		if compress {
+			name = "gzip"
+		}
+		t.Run(name, func(t *testing.T) {
+			config := DefaultJSONLConfig()
+			config.Dir = t.TempDir()
+			config.MaxFileBytes = 400
+			config.MaxBackups = 2
+			config.Compress = compress
+			sink, err := NewJSONLSink(config)
+			if err != nil {
+				t.Fatalf("NewJSONLSink: %v", err)
+			}
+			clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			sink.now = func() time.Time {
+				clock = clock.Add(time.Second)
+				return clock
+			}
+
+			const n = 20
+			for i := range n {
+				if err := sink.RecordError(context.Background(), &ErrorData{Component: "c", Message: string(rune('a' + i))}); err != nil {
+					t.Fatalf("record %d: %v", i, err)
+				}
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			pattern := "telemetry-*.jsonl"
+			if compress {
+				pattern += ".gz"
+			}
+			rotated, _ := filepath.Glob(filepath.Join(config.Dir, pattern))
+			if len(rotated) != 2 {
+				t.Fatalf("got %d rotated files %v, want 2 (MaxBackups)", len(rotated), rotated)
+			}
+			if all, _ := filepath.Glob(filepath.Join(config.Dir, "*")); len(all) != 3 {
+				t.Errorf("directory holds %v, want 2 backups and the active file", all)
+			}
+
+			var messages []string
+			for _, path := range append(rotated, sink.Path()) {
+				info, _ := os.Stat(path)
+				if !compress && info.Size() > config.MaxFileBytes {
+					t.Errorf("%s is %d bytes, over the %d limit", path, info.Size(), config.MaxFileBytes)
+				}
+				for _, r := range readJSONL(t, path) {
+					var e ErrorData
+					json.Unmarshal(r.Data, &e)
+					messages = append(messages, e.Message)
+				}
+			}
+			// The kept records are the newest ones, in order.
+			last := messages[len(messages)-1]
+			if last != string(rune('a'+n-1)) {
+				t.Errorf("newest record = %q, want %q", last, string(rune('a'+n-1)))
+			}
+			for i := 1; i < len(messages); i++ {
+				if messages[i] <= messages[i-1] {
+					t.Errorf("records out of order: %v", messages)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestJSONLSink_Concurrent(t *testing.T) {
+	config := DefaultJSONLConfig()
+	config.Dir = t.TempDir()
+	config.MaxFileBytes = 2048
+	config.MaxBackups = 0
+	sink, err := NewJSONLSink(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				sink.RecordBenchmark(context.Background(), createTestBenchmarkData())
+			}
+		}()
+	}
+	wg.Wait()
+	sink.Close()
+
+	files, _ := filepath.Glob(filepath.Join(config.Dir, "telemetry*"))
+	total := 0
+	for _, path := range files {
+		total += len(readJSONL(t, path))
+	}
+	if total != 200 {
+		t.Errorf("got %d records across %d files, want 200", total, len(files))
+	}
+}
+
+func TestJSONLSink_InterfaceCompliance(t *testing.T) {
+	var _ Sink = (*JSONLSink)(nil)
+}
@@ -16,6 +16,7 @@ import (
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // -----------------------------------------------------------------------------
@@ -71,6 +72,14 @@ type PrometheusConfig struct {
 	// When exceeded, new label values are mapped to "_other".
 	// Default: 1000
 	MaxLabelCardinality int
+
+	// EnableExemplars attaches the trace and span ID of the recording
+	// context as an OpenMetrics exemplar to duration and latency
+	// observations, so a histogram bucket links to the trace behind it.
+	// Exemplars are only exposed in the OpenMetrics format; serve the
+	// registry with promhttp.HandlerOpts{EnableOpenMetrics: true}.
+	// Default: true
+	EnableExemplars bool
 }
 
 // DefaultPrometheusConfig returns a configuration with sensible defaults.
@@ -103,6 +112,7 @@ func DefaultPrometheusConfig() *PrometheusConfig {
 			1024, 10240, 102400, 1048576, 10485760, 104857600, 1073741824,
 		},
 		MaxLabelCardinality: 1000,
+		EnableExemplars:     true,
 	}
 }
 
@@ -444,21 +454,23 @@ func (s *PrometheusSink) RecordBenchmark(ctx context.Context, data *BenchmarkDat
 	}
 	name = s.sanitizeLabel("name", name)
 
+	exemplar := s.exemplarFor(ctx)
+
 	// Record duration
-	s.benchmarkDuration.WithLabelValues(name).Observe(data.Duration.Seconds())
+	observe(s.benchmarkDuration.WithLabelValues(name), data.Duration.Seconds(), exemplar)
 
 	// Record iterations
 	s.benchmarkIterations.WithLabelValues(name).Add(float64(data.Iterations))
 
 	// Record latency percentiles
-	s.benchmarkLatency.WithLabelValues(name, "min").Observe(data.Latency.Min.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "max").Observe(data.Latency.Max.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "mean").Observe(data.Latency.Mean.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "p50").Observe(data.Latency.P50.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "p90").Observe(data.Latency.P90.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "p95").Observe(data.Latency.P95.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "p99").Observe(data.Latency.P99.Seconds())
-	s.benchmarkLatency.WithLabelValues(name, "p999").Observe(data.Latency.P999.Seconds())
+	observe(s.benchmarkLatency.WithLabelValues(name, "min"), data.Latency.Min.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "max"), data.Latency.Max.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "mean"), data.Latency.Mean.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "p50"), data.Latency.P50.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "p90"), data.Latency.P90.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "p95"), data.Latency.P95.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "p99"), data.Latency.P99.Seconds(), exemplar)
+	observe(s.benchmarkLatency.WithLabelValues(name, "p999"), data.Latency.P999.Seconds(), exemplar)
 
 	// Record throughput
 	s.benchmarkThroughput.WithLabelValues(name).Observe(data.Throughput.OpsPerSecond)
@@ -696,5 +708,40 @@ func (s *PrometheusSink) sanitizeLabel(labelName, labelValue string) string {
 	return labelValue
 }
 
+// exemplarFor returns the exemplar labels for ctx's span.
+//
+// Description:
+//
+//	Returns trace_id and span_id labels from the span context in ctx, or
+//	nil if exemplars are disabled or ctx carries no valid span context.
+//	The label names match what Grafana uses to link exemplars to Jaeger.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *PrometheusSink) exemplarFor(ctx context.Context) prometheus.Labels {
+	if !s.config.EnableExemplars {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return prometheus.Labels{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
+
+// observe records value on observer, with exemplar if it is non-nil and
+// the observer supports exemplars.
+func observe(observer prometheus.Observer, value float64, exemplar prometheus.Labels) {
+	if exemplar != nil {
+		if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(value, exemplar)
+			return
+		}
+	}
+	observer.Observe(value)
+}
+
 // Verify interface compliance at compile time.
 var _ Sink = (*PrometheusSink)(nil)
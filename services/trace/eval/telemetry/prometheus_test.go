@@ -20,6 +20,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // -----------------------------------------------------------------------------
@@ -283,6 +284,96 @@ func TestPrometheusSink_RecordBenchmark(t *testing.T) {
 	})
 }
 
+// -----------------------------------------------------------------------------
+// Exemplar Tests
+// -----------------------------------------------------------------------------
+
+// durationExemplars returns the exemplar labels on the duration histogram
+// buckets, keyed by label name.
+func durationExemplars(t *testing.T, reg *prometheus.Registry) []map[string]string {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var exemplars []map[string]string
+	for _, mf := range mfs {
+		if mf.GetName() != "code_buddy_eval_benchmark_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, b := range m.GetHistogram().GetBucket() {
+				if b.GetExemplar() == nil {
+					continue
+				}
+				labels := make(map[string]string)
+				for _, lp := range b.GetExemplar().GetLabel() {
+					labels[lp.GetName()] = lp.GetValue()
+				}
+				exemplars = append(exemplars, labels)
+			}
+		}
+	}
+	return exemplars
+}
+
+func TestPrometheusSink_Exemplars(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	t.Run("attaches trace ID from context", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		config := DefaultPrometheusConfig()
+		config.Registry = reg
+		sink, _ := NewPrometheusSink(config)
+		defer sink.Close()
+
+		if err := sink.RecordBenchmark(ctx, createTestBenchmarkData()); err != nil {
+			t.Fatalf("RecordBenchmark failed: %v", err)
+		}
+
+		exemplars := durationExemplars(t, reg)
+		if len(exemplars) != 1 {
+			t.Fatalf("got %d exemplars, want 1", len(exemplars))
+		}
+		if exemplars[0]["trace_id"] != traceID.String() || exemplars[0]["span_id"] != spanID.String() {
+			t.Errorf("exemplar = %v", exemplars[0])
+		}
+	})
+
+	t.Run("no exemplar without span context", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		config := DefaultPrometheusConfig()
+		config.Registry = reg
+		sink, _ := NewPrometheusSink(config)
+		defer sink.Close()
+
+		sink.RecordBenchmark(context.Background(), createTestBenchmarkData())
+		if exemplars := durationExemplars(t, reg); len(exemplars) != 0 {
+			t.Errorf("got exemplars %v, want none", exemplars)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		config := DefaultPrometheusConfig()
+		config.Registry = reg
+		config.EnableExemplars = false
+		sink, _ := NewPrometheusSink(config)
+		defer sink.Close()
+
+		sink.RecordBenchmark(ctx, createTestBenchmarkData())
+		if exemplars := durationExemplars(t, reg); len(exemplars) != 0 {
+			t.Errorf("got exemplars %v, want none", exemplars)
+		}
+	})
+}
+
 // -----------------------------------------------------------------------------
 // RecordComparison Tests
 // -----------------------------------------------------------------------------
@@ -40,6 +40,9 @@ var (
 	// ErrHealthCheckFailed is returned when a health check fails.
 	ErrHealthCheckFailed = errors.New("health check failed")
 
+	// ErrDependencyCycle is returned when component dependencies form a cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+
 	// ErrSoftSignalViolation is returned when soft signals are used for hard decisions.
 	ErrSoftSignalViolation = errors.New("soft signal used for state mutation")
 )
@@ -160,6 +163,18 @@ type Evaluable interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// Dependent is implemented by evaluables that depend on other components.
+//
+// Registry.HealthReport uses the dependencies to tell a component that
+// fails on its own from one degraded by a failing dependency.
+type Dependent interface {
+	// Dependencies returns the names of the components this one needs.
+	//
+	// Example: a query engine backed by the proof index returns
+	// []string{"proof_index"}.
+	Dependencies() []string
+}
+
 // -----------------------------------------------------------------------------
 // Property Definition
 // -----------------------------------------------------------------------------
@@ -201,7 +216,8 @@ type Property struct {
 	Generator func() any
 
 	// Shrink attempts to reduce a failing input to a minimal case.
-	// If nil, no shrinking is performed.
+	// If nil, the Verifier shrinks by reflection (see
+	// correctness.AutoShrink).
 	// This helps debugging by finding the smallest failing input.
 	Shrink func(input any) []any
 
@@ -390,6 +406,10 @@ type PropertyResult struct {
 	// This is the minimal input after shrinking.
 	FailingInput any
 
+	// OriginalInput is the generated input before shrinking. Nil if the
+	// input was not shrunk.
+	OriginalInput any
+
 	// FailingOutput is the output that caused failure (if any).
 	FailingOutput any
 
@@ -476,6 +496,15 @@ type HealthResult struct {
 
 	// Details contains component-specific health information.
 	Details map[string]any
+
+	// DependsOn lists the component's declared dependencies.
+	// Set only by Registry.HealthReport.
+	DependsOn []string
+
+	// DegradedBy lists the failing components, direct or transitive
+	// dependencies, that degraded this component. Set only by
+	// Registry.HealthReport.
+	DegradedBy []string
 }
 
 // -----------------------------------------------------------------------------
@@ -484,10 +513,11 @@ type HealthResult struct {
 
 // SimpleEvaluable is a simple implementation of Evaluable for testing.
 type SimpleEvaluable struct {
-	name        string
-	properties  []Property
-	metrics     []MetricDefinition
-	healthCheck func(ctx context.Context) error
+	name         string
+	properties   []Property
+	metrics      []MetricDefinition
+	dependencies []string
+	healthCheck  func(ctx context.Context) error
 }
 
 // NewSimpleEvaluable creates a new SimpleEvaluable.
@@ -540,6 +570,17 @@ func (s *SimpleEvaluable) AddMetric(m MetricDefinition) *SimpleEvaluable {
 	return s
 }
 
+// DependsOn declares components this evaluable depends on.
+func (s *SimpleEvaluable) DependsOn(names ...string) *SimpleEvaluable {
+	s.dependencies = append(s.dependencies, names...)
+	return s
+}
+
+// Dependencies implements Dependent.
+func (s *SimpleEvaluable) Dependencies() []string {
+	return s.dependencies
+}
+
 // SetHealthCheck sets the health check function.
 func (s *SimpleEvaluable) SetHealthCheck(fn func(ctx context.Context) error) *SimpleEvaluable {
 	s.healthCheck = fn
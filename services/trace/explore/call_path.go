@@ -0,0 +1,597 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// CallPathOptions configures call path explanations.
+type CallPathOptions struct {
+	// ContextLines is the number of source lines shown before and after
+	// each evidence line.
+	ContextLines int
+
+	// MaxGuards is the maximum number of guarding conditions returned.
+	MaxGuards int
+}
+
+// DefaultCallPathOptions returns sensible defaults.
+func DefaultCallPathOptions() CallPathOptions {
+	return CallPathOptions{
+		ContextLines: 2,
+		MaxGuards:    10,
+	}
+}
+
+// CallPathExplanation explains how one symbol reaches another.
+type CallPathExplanation struct {
+	// From is the starting symbol ID.
+	From string `json:"from"`
+
+	// To is the target symbol ID.
+	To string `json:"to"`
+
+	// Reachable indicates whether To can be reached from From.
+	Reachable bool `json:"reachable"`
+
+	// DominatingPath lists the symbols every call path from From to To
+	// passes through, in call order, including From and To.
+	DominatingPath []CallPathStep `json:"dominating_path"`
+
+	// CallChain is one concrete call chain through the dominating path.
+	CallChain []CallPathHop `json:"call_chain"`
+
+	// Guards are the conditions that decide whether the chain is taken.
+	Guards []CallPathGuard `json:"guards"`
+
+	// Citations lists every [file:line] citation in path order.
+	Citations []string `json:"citations"`
+
+	// Summary is a prose explanation with inline citations, ready to be
+	// quoted in a grounded response.
+	Summary string `json:"summary"`
+
+	// Limitations describes what the explanation could not establish.
+	Limitations []string `json:"limitations,omitempty"`
+}
+
+// CallPathStep is a symbol on the dominating path.
+type CallPathStep struct {
+	// ID is the symbol ID.
+	ID string `json:"id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// FilePath is the file defining the symbol.
+	FilePath string `json:"file_path,omitempty"`
+
+	// Line is the line of the definition.
+	Line int `json:"line,omitempty"`
+
+	// Citation is the [file:line] citation of the definition.
+	Citation string `json:"citation,omitempty"`
+}
+
+// CallPathHop is one call in the call chain.
+type CallPathHop struct {
+	// CallerID is the calling symbol.
+	CallerID string `json:"caller_id"`
+
+	// CalleeID is the called symbol.
+	CalleeID string `json:"callee_id"`
+
+	// Mandatory indicates the callee is on the dominating path.
+	Mandatory bool `json:"mandatory"`
+
+	// Evidence is the call site, or the callee definition if the call
+	// site is unknown.
+	Evidence *EvidenceSnippet `json:"evidence,omitempty"`
+}
+
+// CallPathGuard is a branch that controls whether a symbol on the chain runs.
+type CallPathGuard struct {
+	// ControllerID is the symbol whose branch decides execution.
+	ControllerID string `json:"controller_id"`
+
+	// GuardedID is the symbol on the chain that is control-dependent on
+	// the controller.
+	GuardedID string `json:"guarded_id"`
+
+	// Condition is the enclosing branch statement, if found in the source.
+	Condition string `json:"condition,omitempty"`
+
+	// Evidence is the guarded call site in the controller.
+	Evidence *EvidenceSnippet `json:"evidence,omitempty"`
+}
+
+// EvidenceSnippet is a source excerpt supporting a claim.
+type EvidenceSnippet struct {
+	// FilePath is the file, relative to the project root.
+	FilePath string `json:"file_path"`
+
+	// Line is the line the evidence points at.
+	Line int `json:"line"`
+
+	// StartLine and EndLine bound Code.
+	StartLine int `json:"start_line,omitempty"`
+	EndLine   int `json:"end_line,omitempty"`
+
+	// Citation is the [file:line] citation of Line.
+	Citation string `json:"citation"`
+
+	// Code is the excerpt, empty if the source file could not be read.
+	Code string `json:"code,omitempty"`
+}
+
+// guardConditionPattern matches lines that open a branch.
+var guardConditionPattern = regexp.MustCompile(
+	`^\s*(\}\s*)?(if|else|elif|switch|case|select|for|while|match|when|except|catch|default)\b`,
+)
+
+// CallPathExplainer explains call paths using dominators, control
+// dependence and source evidence.
+//
+// Thread Safety:
+//
+//	CallPathExplainer is safe for concurrent use. It performs read-only
+//	operations on the graph, index and source files.
+type CallPathExplainer struct {
+	graph     *graph.Graph
+	index     *index.SymbolIndex
+	analytics *graph.GraphAnalytics
+
+	analyticsOnce sync.Once
+	analyticsErr  error
+}
+
+// NewCallPathExplainer creates a new CallPathExplainer.
+//
+// Description:
+//
+//	Creates an explainer that answers "how does From reach To": the
+//	dominating call path, the branches guarding it, and the source lines
+//	proving each step.
+//
+// Inputs:
+//
+//	g - The code graph. Must be frozen.
+//	idx - The symbol index. May be nil.
+//	analytics - Analytics over g, shared for its result cache. If nil, it
+//	  is created from g on first use.
+//
+// Outputs:
+//
+//	*CallPathExplainer - The configured explainer.
+//
+// Example:
+//
+//	explainer := NewCallPathExplainer(graph, index, nil)
+//	exp, err := explainer.ExplainCallPath(ctx, "main.main", "db.Exec", DefaultCallPathOptions())
+func NewCallPathExplainer(g *graph.Graph, idx *index.SymbolIndex, analytics *graph.GraphAnalytics) *CallPathExplainer {
+	return &CallPathExplainer{
+		graph:     g,
+		index:     idx,
+		analytics: analytics,
+	}
+}
+
+// ExplainCallPath explains how fromID reaches toID.
+//
+// Description:
+//
+//	Computes the dominator tree rooted at fromID; the dominators of toID
+//	are the calls every path must make. A shortest concrete call chain
+//	through them supplies call sites. The control dependence graph then
+//	names the branches deciding whether each symbol on the chain runs.
+//	Every step carries a [file:line] citation and a source excerpt.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	fromID - The calling symbol ID.
+//	toID - The called symbol ID.
+//	opts - Evidence and guard limits.
+//
+// Outputs:
+//
+//	*CallPathExplanation - The explanation. Reachable is false if toID
+//	  cannot be reached from fromID.
+//	error - Non-nil if a symbol is not found or the operation was canceled.
+//
+// Errors:
+//
+//	ErrInvalidInput - A symbol ID is empty.
+//	ErrSymbolNotFound - A symbol is not in the graph.
+//	ErrGraphNotReady - Graph is not frozen.
+//	ErrContextCanceled - Context was canceled.
+//
+// Limitations:
+//
+//   - Guards are function-level: the condition is the nearest enclosing
+//     branch of the call site, found textually
+//   - Guards are omitted if the post-dominator tree cannot be computed
+//   - Evidence excerpts need the source files under the graph's project root
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (e *CallPathExplainer) ExplainCallPath(ctx context.Context, fromID, toID string, opts CallPathOptions) (*CallPathExplanation, error) {
+	if ctx == nil || fromID == "" || toID == "" {
+		return nil, ErrInvalidInput
+	}
+	if e.graph == nil || !e.graph.IsFrozen() {
+		return nil, ErrGraphNotReady
+	}
+	if _, ok := e.graph.GetNode(fromID); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, fromID)
+	}
+	if _, ok := e.graph.GetNode(toID); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, toID)
+	}
+	if opts.ContextLines < 0 {
+		opts.ContextLines = 0
+	}
+	if opts.MaxGuards <= 0 {
+		opts.MaxGuards = DefaultCallPathOptions().MaxGuards
+	}
+
+	analytics, err := e.getAnalytics()
+	if err != nil {
+		return nil, err
+	}
+
+	domTree, err := analytics.Dominators(ctx, fromID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrContextCanceled
+		}
+		return nil, fmt.Errorf("computing dominators from %s: %w", fromID, err)
+	}
+
+	exp := &CallPathExplanation{
+		From:           fromID,
+		To:             toID,
+		DominatingPath: []CallPathStep{},
+		CallChain:      []CallPathHop{},
+		Guards:         []CallPathGuard{},
+		Citations:      []string{},
+	}
+
+	// DominatorsOf returns target → entry
+	doms := domTree.DominatorsOf(toID)
+	if len(doms) == 0 {
+		exp.Summary = fmt.Sprintf("%s is not reachable from %s.", e.name(toID), e.name(fromID))
+		return exp, nil
+	}
+	exp.Reachable = true
+
+	mandatory := make(map[string]bool, len(doms))
+	for i := len(doms) - 1; i >= 0; i-- {
+		mandatory[doms[i]] = true
+		exp.DominatingPath = append(exp.DominatingPath, e.step(doms[i]))
+	}
+
+	files := newSourceCache(e.graph.ProjectRoot)
+
+	// Concrete chain through consecutive dominators
+	chain := []string{fromID}
+	for i := 1; i < len(exp.DominatingPath); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, ErrContextCanceled
+		}
+		prev, next := exp.DominatingPath[i-1].ID, exp.DominatingPath[i].ID
+		segment, err := e.graph.ShortestPath(ctx, prev, next)
+		if err != nil || len(segment.Path) < 2 {
+			chain = append(chain, next)
+			continue
+		}
+		chain = append(chain, segment.Path[1:]...)
+	}
+	for i := 1; i < len(chain); i++ {
+		caller, callee := chain[i-1], chain[i]
+		hop := CallPathHop{
+			CallerID:  caller,
+			CalleeID:  callee,
+			Mandatory: mandatory[callee],
+		}
+		if loc, ok := e.callSite(caller, callee); ok {
+			hop.Evidence = files.snippet(loc.FilePath, loc.StartLine, opts.ContextLines)
+		} else if sym := e.symbol(callee); sym != nil && sym.FilePath != "" {
+			hop.Evidence = files.snippet(sym.FilePath, sym.StartLine, opts.ContextLines)
+		}
+		exp.CallChain = append(exp.CallChain, hop)
+	}
+
+	exp.Guards, exp.Limitations = e.guards(ctx, analytics, domTree, chain, files, opts)
+
+	e.collectCitations(exp)
+	exp.Summary = e.summarize(exp)
+	return exp, nil
+}
+
+// getAnalytics returns the analytics, creating them from the graph once.
+func (e *CallPathExplainer) getAnalytics() (*graph.GraphAnalytics, error) {
+	if e.analytics != nil {
+		return e.analytics, nil
+	}
+	e.analyticsOnce.Do(func() {
+		hg, err := graph.WrapGraph(e.graph)
+		if err != nil {
+			e.analyticsErr = fmt.Errorf("wrapping graph for analytics: %w", err)
+			return
+		}
+		e.analytics = graph.NewGraphAnalytics(hg)
+	})
+	return e.analytics, e.analyticsErr
+}
+
+// guards returns the branches controlling the symbols on the chain.
+func (e *CallPathExplainer) guards(
+	ctx context.Context,
+	analytics *graph.GraphAnalytics,
+	domTree *graph.DominatorTree,
+	chain []string,
+	files *sourceCache,
+	opts CallPathOptions,
+) ([]CallPathGuard, []string) {
+	guards := []CallPathGuard{}
+
+	postDomTree, err := analytics.PostDominators(ctx, "")
+	if err != nil {
+		return guards, []string{fmt.Sprintf("guarding conditions unavailable: %v", err)}
+	}
+	cd, err := analytics.ComputeControlDependence(ctx, postDomTree)
+	if err != nil {
+		return guards, []string{fmt.Sprintf("guarding conditions unavailable: %v", err)}
+	}
+
+	var limitations []string
+	seen := make(map[string]bool)
+	for i := 1; i < len(chain); i++ {
+		guarded := chain[i]
+		for _, controller := range cd.GetDependencies(guarded) {
+			// Only branches taken after From count
+			if !domTree.Dominates(chain[0], controller) || seen[controller+"\x00"+guarded] {
+				continue
+			}
+			seen[controller+"\x00"+guarded] = true
+			if len(guards) == opts.MaxGuards {
+				limitations = append(limitations, fmt.Sprintf("guards truncated to %d", opts.MaxGuards))
+				return guards, limitations
+			}
+
+			guard := CallPathGuard{ControllerID: controller, GuardedID: guarded}
+			if loc, ok := e.callSite(controller, guarded); ok {
+				guard.Evidence = files.snippet(loc.FilePath, loc.StartLine, opts.ContextLines)
+				start := 1
+				if sym := e.symbol(controller); sym != nil {
+					start = sym.StartLine
+				}
+				guard.Condition = files.enclosingBranch(loc.FilePath, loc.StartLine, start)
+			} else if sym := e.symbol(controller); sym != nil && sym.FilePath != "" {
+				guard.Evidence = files.snippet(sym.FilePath, sym.StartLine, opts.ContextLines)
+			}
+			guards = append(guards, guard)
+		}
+	}
+	return guards, limitations
+}
+
+// callSite returns the location of the call from caller to callee.
+func (e *CallPathExplainer) callSite(caller, callee string) (ast.Location, bool) {
+	node, ok := e.graph.GetNode(caller)
+	if !ok {
+		return ast.Location{}, false
+	}
+	var found *graph.Edge
+	for _, edge := range node.Outgoing {
+		if edge.ToID != callee || edge.Location.FilePath == "" || edge.Location.StartLine <= 0 {
+			continue
+		}
+		if found == nil || (edge.Type == graph.EdgeTypeCalls && found.Type != graph.EdgeTypeCalls) {
+			found = edge
+		}
+	}
+	if found == nil {
+		return ast.Location{}, false
+	}
+	return found.Location, true
+}
+
+// symbol returns the symbol of a node, or nil.
+func (e *CallPathExplainer) symbol(id string) *ast.Symbol {
+	if node, ok := e.graph.GetNode(id); ok && node.Symbol != nil {
+		return node.Symbol
+	}
+	if e.index != nil {
+		if sym, ok := e.index.GetByID(id); ok {
+			return sym
+		}
+	}
+	return nil
+}
+
+// name returns the display name of a symbol.
+func (e *CallPathExplainer) name(id string) string {
+	if sym := e.symbol(id); sym != nil && sym.Name != "" {
+		return sym.Name
+	}
+	return id
+}
+
+// step builds the dominating path entry for a symbol.
+func (e *CallPathExplainer) step(id string) CallPathStep {
+	s := CallPathStep{ID: id, Name: e.name(id)}
+	if sym := e.symbol(id); sym != nil && sym.FilePath != "" {
+		s.FilePath = sym.FilePath
+		s.Line = sym.StartLine
+		s.Citation = formatCitation(sym.FilePath, sym.StartLine)
+	}
+	return s
+}
+
+// collectCitations gathers the citations of the explanation in path order.
+func (e *CallPathExplainer) collectCitations(exp *CallPathExplanation) {
+	seen := make(map[string]bool)
+	add := func(c string) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			exp.Citations = append(exp.Citations, c)
+		}
+	}
+	for _, hop := range exp.CallChain {
+		if hop.Evidence != nil {
+			add(hop.Evidence.Citation)
+		}
+	}
+	for _, guard := range exp.Guards {
+		if guard.Evidence != nil {
+			add(guard.Evidence.Citation)
+		}
+	}
+}
+
+// summarize writes the explanation as prose with inline citations.
+func (e *CallPathExplainer) summarize(exp *CallPathExplanation) string {
+	var sb strings.Builder
+
+	names := make([]string, len(exp.DominatingPath))
+	for i, s := range exp.DominatingPath {
+		names[i] = s.Name
+	}
+	fmt.Fprintf(&sb, "Every call path from %s to %s passes through %s.",
+		e.name(exp.From), e.name(exp.To), strings.Join(names, " → "))
+
+	for _, hop := range exp.CallChain {
+		sb.WriteString(" ")
+		fmt.Fprintf(&sb, "%s calls %s", e.name(hop.CallerID), e.name(hop.CalleeID))
+		if hop.Evidence != nil {
+			sb.WriteString(" " + hop.Evidence.Citation)
+		}
+		sb.WriteString(".")
+	}
+
+	for _, guard := range exp.Guards {
+		sb.WriteString(" ")
+		fmt.Fprintf(&sb, "%s only runs when %s takes the branch", e.name(guard.GuardedID), e.name(guard.ControllerID))
+		if guard.Condition != "" {
+			fmt.Fprintf(&sb, " `%s`", guard.Condition)
+		}
+		if guard.Evidence != nil {
+			sb.WriteString(" " + guard.Evidence.Citation)
+		}
+		sb.WriteString(".")
+	}
+	return sb.String()
+}
+
+// formatCitation formats a [file:line] citation.
+func formatCitation(filePath string, line int) string {
+	return fmt.Sprintf("[%s:%d]", filePath, line)
+}
+
+// =============================================================================
+// SOURCE EVIDENCE
+// =============================================================================
+
+// sourceCache reads source files once per explanation.
+type sourceCache struct {
+	root  string
+	files map[string][]string
+}
+
+// newSourceCache creates a cache reading files under root.
+func newSourceCache(root string) *sourceCache {
+	return &sourceCache{root: root, files: make(map[string][]string)}
+}
+
+// lines returns the lines of a file, or nil if it cannot be read.
+func (c *sourceCache) lines(filePath string) []string {
+	if lines, ok := c.files[filePath]; ok {
+		return lines
+	}
+	path := filePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.root, filePath)
+	}
+	var lines []string
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.Close()
+	}
+	c.files[filePath] = lines
+	return lines
+}
+
+// snippet returns the evidence for a line with surrounding context.
+func (c *sourceCache) snippet(filePath string, line, contextLines int) *EvidenceSnippet {
+	ev := &EvidenceSnippet{
+		FilePath: filePath,
+		Line:     line,
+		Citation: formatCitation(filePath, line),
+	}
+	lines := c.lines(filePath)
+	if line < 1 || line > len(lines) {
+		return ev
+	}
+	ev.StartLine = max(1, line-contextLines)
+	ev.EndLine = min(len(lines), line+contextLines)
+
+	var sb strings.Builder
+	for n := ev.StartLine; n <= ev.EndLine; n++ {
+		fmt.Fprintf(&sb, "%d: %s\n", n, lines[n-1])
+	}
+	ev.Code = sb.String()
+	return ev
+}
+
+// enclosingBranch returns the nearest branch statement enclosing a line,
+// searching up to the first line of the enclosing function.
+func (c *sourceCache) enclosingBranch(filePath string, line, funcStart int) string {
+	lines := c.lines(filePath)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	indent := indentation(lines[line-1])
+	for n := line - 1; n >= max(1, funcStart); n-- {
+		text := lines[n-1]
+		if strings.TrimSpace(text) == "" || indentation(text) >= indent {
+			continue
+		}
+		if guardConditionPattern.MatchString(text) {
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "{"))
+		}
+		indent = indentation(text)
+	}
+	return ""
+}
+
+// indentation returns the width of a line's leading whitespace.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
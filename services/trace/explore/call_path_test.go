@@ -0,0 +1,174 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const callPathTestSource = `package app
+
+func main() {
+	cfg := load()
+	if cfg.Enabled {
+		run(cfg)
+	}
+}
+
+func run(cfg Config) {
+	store(cfg)
+}
+
+func load() Config { return Config{} }
+
+func store(cfg Config) {}
+
+func unused() {}
+`
+
+func setupCallPathTestGraph(t *testing.T) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.go"), []byte(callPathTestSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.NewGraph(root)
+	idx := index.NewSymbolIndex()
+	for _, s := range []struct {
+		name       string
+		start, end int
+	}{
+		{"main", 3, 8}, {"run", 10, 12}, {"load", 14, 14}, {"store", 16, 16}, {"unused", 18, 18},
+	} {
+		sym := &ast.Symbol{
+			ID:        "app.go:" + s.name,
+			Name:      s.name,
+			Kind:      ast.SymbolKindFunction,
+			FilePath:  "app.go",
+			StartLine: s.start,
+			EndLine:   s.end,
+			Language:  "go",
+		}
+		g.AddNode(sym)
+		if err := idx.Add(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	call := func(from, to string, line int) {
+		g.AddEdge("app.go:"+from, "app.go:"+to, graph.EdgeTypeCalls, ast.Location{FilePath: "app.go", StartLine: line})
+	}
+	call("main", "load", 4)
+	call("main", "run", 6)
+	call("run", "store", 11)
+	g.Freeze()
+	return g, idx
+}
+
+func TestCallPathExplainer_ExplainCallPath(t *testing.T) {
+	g, idx := setupCallPathTestGraph(t)
+	explainer := NewCallPathExplainer(g, idx, nil)
+	ctx := context.Background()
+
+	t.Run("dominating path with guard and evidence", func(t *testing.T) {
+		exp, err := explainer.ExplainCallPath(ctx, "app.go:main", "app.go:store", DefaultCallPathOptions())
+		if err != nil {
+			t.Fatalf("ExplainCallPath() error = %v", err)
+		}
+		if !exp.Reachable {
+			t.Fatal("expected store to be reachable from main")
+		}
+
+		var names []string
+		for _, s := range exp.DominatingPath {
+			names = append(names, s.Name)
+		}
+		if got := strings.Join(names, ","); got != "main,run,store" {
+			t.Errorf("DominatingPath = %s, want main,run,store", got)
+		}
+
+		if len(exp.CallChain) != 2 {
+			t.Fatalf("CallChain has %d hops, want 2", len(exp.CallChain))
+		}
+		hop := exp.CallChain[0]
+		if hop.Evidence == nil || hop.Evidence.Citation != "[app.go:6]" {
+			t.Fatalf("first hop evidence = %+v, want [app.go:6]", hop.Evidence)
+		}
+		if !strings.Contains(hop.Evidence.Code, "6: \t\trun(cfg)") || hop.Evidence.StartLine != 4 || hop.Evidence.EndLine != 8 {
+			t.Errorf("first hop snippet = %d-%d %q", hop.Evidence.StartLine, hop.Evidence.EndLine, hop.Evidence.Code)
+		}
+
+		var guard *CallPathGuard
+		for i := range exp.Guards {
+			if exp.Guards[i].GuardedID == "app.go:run" {
+				guard = &exp.Guards[i]
+			}
+		}
+		if guard == nil {
+			t.Fatalf("no guard for run in %+v", exp.Guards)
+		}
+		if guard.ControllerID != "app.go:main" || guard.Condition != "if cfg.Enabled" {
+			t.Errorf("guard = %+v, want main's `if cfg.Enabled`", guard)
+		}
+
+		if len(exp.Citations) == 0 || exp.Citations[0] != "[app.go:6]" {
+			t.Errorf("Citations = %v", exp.Citations)
+		}
+		if !strings.Contains(exp.Summary, "main calls run [app.go:6]") || !strings.Contains(exp.Summary, "`if cfg.Enabled`") {
+			t.Errorf("Summary = %q", exp.Summary)
+		}
+	})
+
+	t.Run("unreachable target", func(t *testing.T) {
+		exp, err := explainer.ExplainCallPath(ctx, "app.go:run", "app.go:unused", DefaultCallPathOptions())
+		if err != nil {
+			t.Fatalf("ExplainCallPath() error = %v", err)
+		}
+		if exp.Reachable || len(exp.CallChain) != 0 {
+			t.Errorf("expected unreachable, got %+v", exp)
+		}
+		if !strings.Contains(exp.Summary, "not reachable") {
+			t.Errorf("Summary = %q", exp.Summary)
+		}
+	})
+
+	t.Run("missing source keeps citations", func(t *testing.T) {
+		moved := NewCallPathExplainer(g, idx, nil)
+		if err := os.Remove(filepath.Join(g.ProjectRoot, "app.go")); err != nil {
+			t.Fatal(err)
+		}
+		exp, err := moved.ExplainCallPath(ctx, "app.go:main", "app.go:run", CallPathOptions{})
+		if err != nil {
+			t.Fatalf("ExplainCallPath() error = %v", err)
+		}
+		if ev := exp.CallChain[0].Evidence; ev == nil || ev.Citation != "[app.go:6]" || ev.Code != "" {
+			t.Errorf("evidence = %+v, want citation without code", ev)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := explainer.ExplainCallPath(ctx, "", "app.go:run", DefaultCallPathOptions()); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("error = %v, want ErrInvalidInput", err)
+		}
+		if _, err := explainer.ExplainCallPath(ctx, "app.go:main", "app.go:missing", DefaultCallPathOptions()); !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("error = %v, want ErrSymbolNotFound", err)
+		}
+	})
+}
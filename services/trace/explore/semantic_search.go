@@ -0,0 +1,453 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// Embedder converts text into dense vectors.
+//
+// # Description
+//
+// EmbeddingClient implements Embedder against the embeddings service used by
+// the orchestrator. LocalEmbedder is an offline fallback.
+//
+// # Thread Safety
+//
+// Implementations must be safe for concurrent use.
+type Embedder interface {
+	// BatchEmbed returns one vector per input text, in order.
+	BatchEmbed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// DefaultLocalEmbeddingDim is the vector size produced by LocalEmbedder.
+const DefaultLocalEmbeddingDim = 256
+
+// LocalEmbedder is a dependency-free embedder based on feature hashing.
+//
+// # Description
+//
+// Splits text into lowercase identifier parts (camelCase and snake_case are
+// split), hashes each token and adjacent token pair into a fixed-size
+// vector, and L2-normalizes the result. It captures lexical overlap only,
+// but requires no model and no network, so semantic search keeps working
+// when the embeddings service is unavailable.
+//
+// # Thread Safety
+//
+// LocalEmbedder is safe for concurrent use.
+type LocalEmbedder struct {
+	dim int
+}
+
+// NewLocalEmbedder creates a local embedder with the given dimension.
+// A non-positive dim uses DefaultLocalEmbeddingDim.
+func NewLocalEmbedder(dim int) *LocalEmbedder {
+	if dim <= 0 {
+		dim = DefaultLocalEmbeddingDim
+	}
+	return &LocalEmbedder{dim: dim}
+}
+
+// BatchEmbed implements Embedder.
+func (e *LocalEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, ErrContextCanceled
+		}
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed hashes the tokens of text into a normalized vector.
+func (e *LocalEmbedder) embed(text string) []float32 {
+	vec := make([]float32, e.dim)
+	tokens := tokenizeForEmbedding(text)
+
+	add := func(feature string, weight float32) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum32()
+		sign := float32(1)
+		if sum&1 == 1 {
+			sign = -1
+		}
+		vec[int(sum>>1)%e.dim] += sign * weight
+	}
+
+	for i, tok := range tokens {
+		add(tok, 1)
+		if i > 0 {
+			add(tokens[i-1]+" "+tok, 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vec {
+			vec[i] *= scale
+		}
+	}
+	return vec
+}
+
+// embeddingStopWords are dropped by tokenizeForEmbedding.
+var embeddingStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "to": true, "and": true,
+	"or": true, "in": true, "is": true, "it": true, "for": true, "on": true,
+	"func": true, "def": true, "function": true, "return": true, "returns": true,
+}
+
+// tokenizeForEmbedding splits text into lowercase words and identifier parts.
+func tokenizeForEmbedding(text string) []string {
+	var tokens []string
+	var cur []rune
+
+	flush := func() {
+		if len(cur) > 0 {
+			tok := strings.ToLower(string(cur))
+			if !embeddingStopWords[tok] && len(tok) > 1 {
+				tokens = append(tokens, tok)
+			}
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Split camelCase: "parseHTTPRequest" -> parse, http, request
+			if unicode.IsUpper(r) && len(cur) > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					flush()
+				}
+			}
+			cur = append(cur, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// SemanticSearchResult is a symbol matched by a natural-language query.
+type SemanticSearchResult struct {
+	// SymbolID is the matched symbol's ID.
+	SymbolID string `json:"symbol_id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind.
+	Kind string `json:"kind"`
+
+	// FilePath is the relative path to the file.
+	FilePath string `json:"file_path"`
+
+	// Line is the 1-indexed start line.
+	Line int `json:"line"`
+
+	// Signature is the symbol signature.
+	Signature string `json:"signature,omitempty"`
+
+	// DocSummary is the first sentence of the symbol's doc comment.
+	DocSummary string `json:"doc_summary,omitempty"`
+
+	// Score is the cosine similarity to the query (-1.0 to 1.0).
+	Score float64 `json:"score"`
+}
+
+// SemanticSearchOptions configures a semantic search query.
+type SemanticSearchOptions struct {
+	// Limit is the maximum number of results.
+	// Default: 10
+	Limit int
+
+	// MinScore drops results scoring below this value.
+	// Default: 0 (no filtering beyond positive similarity)
+	MinScore float64
+
+	// Kinds restricts results to these symbol kinds (e.g. "function").
+	// Empty means all indexed kinds.
+	Kinds []string
+}
+
+// DefaultSemanticSearchLimit is the default result limit.
+const DefaultSemanticSearchLimit = 10
+
+// semanticEntry is one indexed symbol with its embedding.
+type semanticEntry struct {
+	symbol *ast.Symbol
+	vector []float32
+}
+
+// SemanticSearchIndex answers natural-language queries over symbols.
+//
+// # Description
+//
+// Embeds a corpus built from each documentable symbol's name, signature,
+// and doc comment, then ranks symbols by cosine similarity to the embedded
+// query. This complements exact graph queries (find_callers, find_symbol)
+// when the user describes behavior rather than naming a symbol.
+//
+// # Thread Safety
+//
+// Safe for concurrent Search calls after Build returns. Build must not be
+// called concurrently with itself.
+type SemanticSearchIndex struct {
+	idx      *index.SymbolIndex
+	embedder Embedder
+
+	mu      sync.RWMutex
+	entries []semanticEntry
+	built   bool
+}
+
+// NewSemanticSearchIndex creates a semantic search index.
+//
+// # Inputs
+//
+//   - idx: Symbol index to draw the corpus from.
+//   - embedder: Embedding backend. If nil, a LocalEmbedder is used.
+//
+// # Example
+//
+//	client := explore.NewEmbeddingClient(os.Getenv("EMBEDDING_SERVICE_URL"))
+//	ssi := explore.NewSemanticSearchIndex(idx, client)
+//	if err := ssi.Build(ctx); err != nil { ... }
+//	results, err := ssi.Search(ctx, "parse config from yaml", nil)
+func NewSemanticSearchIndex(idx *index.SymbolIndex, embedder Embedder) *SemanticSearchIndex {
+	if embedder == nil {
+		embedder = NewLocalEmbedder(DefaultLocalEmbeddingDim)
+	}
+	return &SemanticSearchIndex{
+		idx:      idx,
+		embedder: embedder,
+	}
+}
+
+// semanticSearchKinds are the symbol kinds included in the corpus.
+var semanticSearchKinds = []ast.SymbolKind{
+	ast.SymbolKindFunction,
+	ast.SymbolKindMethod,
+	ast.SymbolKindInterface,
+	ast.SymbolKindStruct,
+	ast.SymbolKindClass,
+	ast.SymbolKindType,
+	ast.SymbolKindEnum,
+}
+
+// semanticBatchSize is the number of texts sent per embedding request.
+const semanticBatchSize = 50
+
+// Build embeds the symbol corpus.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - error: ErrGraphNotReady if idx is nil, or the embedding error.
+func (s *SemanticSearchIndex) Build(ctx context.Context) error {
+	if ctx == nil {
+		return ErrInvalidInput
+	}
+	if s.idx == nil {
+		return ErrGraphNotReady
+	}
+
+	var symbols []*ast.Symbol
+	for _, kind := range semanticSearchKinds {
+		symbols = append(symbols, s.idx.GetByKind(kind)...)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].ID < symbols[j].ID })
+
+	entries := make([]semanticEntry, 0, len(symbols))
+	for i := 0; i < len(symbols); i += semanticBatchSize {
+		if err := ctx.Err(); err != nil {
+			return ErrContextCanceled
+		}
+
+		end := i + semanticBatchSize
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		batch := symbols[i:end]
+
+		texts := make([]string, len(batch))
+		for j, sym := range batch {
+			texts[j] = SemanticSearchText(sym)
+		}
+
+		vectors, err := s.embedder.BatchEmbed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("batch embed: %w", err)
+		}
+		for j, sym := range batch {
+			if j < len(vectors) && len(vectors[j]) > 0 {
+				entries = append(entries, semanticEntry{symbol: sym, vector: vectors[j]})
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.entries = entries
+	s.built = true
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsBuilt returns true once Build has completed successfully.
+func (s *SemanticSearchIndex) IsBuilt() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.built
+}
+
+// Size returns the number of embedded symbols.
+func (s *SemanticSearchIndex) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Search returns the symbols nearest to a natural-language query.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - query: Natural-language description of the code to find.
+//   - opts: Search options. May be nil for defaults.
+//
+// # Outputs
+//
+//   - []SemanticSearchResult: Results sorted by descending score.
+//   - error: ErrGraphNotReady if Build has not run, ErrInvalidInput for an
+//     empty query, or the embedding error.
+func (s *SemanticSearchIndex) Search(ctx context.Context, query string, opts *SemanticSearchOptions) ([]SemanticSearchResult, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is empty", ErrInvalidInput)
+	}
+	if !s.IsBuilt() {
+		return nil, ErrGraphNotReady
+	}
+
+	limit := DefaultSemanticSearchLimit
+	var minScore float64
+	kinds := make(map[string]bool)
+	if opts != nil {
+		if opts.Limit > 0 {
+			limit = opts.Limit
+		}
+		minScore = opts.MinScore
+		for _, k := range opts.Kinds {
+			kinds[strings.ToLower(k)] = true
+		}
+	}
+
+	vectors, err := s.embedder.BatchEmbed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("embedding backend returned no vectors")
+	}
+	queryVec := vectors[0]
+
+	s.mu.RLock()
+	results := make([]SemanticSearchResult, 0, limit)
+	for _, e := range s.entries {
+		if len(kinds) > 0 && !kinds[e.symbol.Kind.String()] {
+			continue
+		}
+		score := CosineSimilarity(queryVec, e.vector)
+		if score <= 0 || score < minScore {
+			continue
+		}
+		results = append(results, SemanticSearchResult{
+			SymbolID:   e.symbol.ID,
+			Name:       e.symbol.Name,
+			Kind:       e.symbol.Kind.String(),
+			FilePath:   e.symbol.FilePath,
+			Line:       e.symbol.StartLine,
+			Signature:  e.symbol.Signature,
+			DocSummary: ast.DocSummary(e.symbol.DocComment),
+			Score:      score,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].SymbolID < results[j].SymbolID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// SemanticSearchText builds the text embedded for a symbol.
+//
+// Combines the name, receiver, signature, and cleaned doc comment so that
+// both identifiers and prose contribute to the match.
+func SemanticSearchText(sym *ast.Symbol) string {
+	var b strings.Builder
+	b.WriteString(sym.Name)
+	if sym.Receiver != "" {
+		b.WriteString(" ")
+		b.WriteString(sym.Receiver)
+	}
+	if sym.Signature != "" {
+		b.WriteString(" ")
+		b.WriteString(sym.Signature)
+	}
+	if doc := ast.CleanDocComment(sym.DocComment); doc != "" {
+		b.WriteString(" ")
+		b.WriteString(doc)
+	}
+	return b.String()
+}
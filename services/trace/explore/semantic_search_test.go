@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+func createTestSearchIndex(t *testing.T) *index.SymbolIndex {
+	t.Helper()
+
+	symbols := []*ast.Symbol{
+		{
+			ID: "config/load.go:10:LoadConfig", Name: "LoadConfig", Kind: ast.SymbolKindFunction,
+			FilePath: "config/load.go", StartLine: 10, EndLine: 30, Language: "go",
+			Signature:  "func LoadConfig(path string) (*Config, error)",
+			DocComment: "// LoadConfig reads the YAML configuration file from disk.",
+		},
+		{
+			ID: "http/server.go:10:StartServer", Name: "StartServer", Kind: ast.SymbolKindFunction,
+			FilePath: "http/server.go", StartLine: 10, EndLine: 40, Language: "go",
+			Signature:  "func StartServer(addr string) error",
+			DocComment: "// StartServer listens for HTTP requests on addr.",
+		},
+		{
+			ID: "auth/token.go:10:ValidateToken", Name: "ValidateToken", Kind: ast.SymbolKindFunction,
+			FilePath: "auth/token.go", StartLine: 10, EndLine: 25, Language: "go",
+			Signature:  "func ValidateToken(token string) (*Claims, error)",
+			DocComment: "// ValidateToken verifies the JWT signature and expiry.",
+		},
+		{
+			ID: "config/types.go:5:Config", Name: "Config", Kind: ast.SymbolKindStruct,
+			FilePath: "config/types.go", StartLine: 5, EndLine: 12, Language: "go",
+			DocComment: "// Config holds application configuration.",
+		},
+		{
+			ID: "config/types.go:3:config", Name: "config", Kind: ast.SymbolKindImport,
+			FilePath: "config/types.go", StartLine: 3, EndLine: 3, Language: "go",
+		},
+	}
+
+	idx := index.NewSymbolIndex()
+	for _, sym := range symbols {
+		if err := idx.Add(sym); err != nil {
+			t.Fatalf("Add(%s): %v", sym.ID, err)
+		}
+	}
+	return idx
+}
+
+func TestSemanticSearchIndex_Search(t *testing.T) {
+	ctx := context.Background()
+	ssi := NewSemanticSearchIndex(createTestSearchIndex(t), nil)
+
+	if err := ssi.Build(ctx); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	// Imports are not part of the corpus.
+	if ssi.Size() != 4 {
+		t.Errorf("expected 4 indexed symbols, got %d", ssi.Size())
+	}
+
+	t.Run("best match first", func(t *testing.T) {
+		results, err := ssi.Search(ctx, "read yaml configuration file", nil)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) == 0 {
+			t.Fatal("expected results")
+		}
+		if results[0].SymbolID != "config/load.go:10:LoadConfig" {
+			t.Errorf("expected LoadConfig first, got %s", results[0].SymbolID)
+		}
+		if results[0].DocSummary != "LoadConfig reads the YAML configuration file from disk." {
+			t.Errorf("unexpected DocSummary %q", results[0].DocSummary)
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Score > results[i-1].Score {
+				t.Errorf("results not sorted by score at %d", i)
+			}
+		}
+	})
+
+	t.Run("kind filter and limit", func(t *testing.T) {
+		results, err := ssi.Search(ctx, "configuration", &SemanticSearchOptions{Kinds: []string{"struct"}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Name != "Config" {
+			t.Errorf("expected only Config, got %+v", results)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		if _, err := ssi.Search(ctx, "  ", nil); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestSemanticSearchIndex_NotBuilt(t *testing.T) {
+	ssi := NewSemanticSearchIndex(createTestSearchIndex(t), nil)
+	if _, err := ssi.Search(context.Background(), "server", nil); !errors.Is(err, ErrGraphNotReady) {
+		t.Errorf("expected ErrGraphNotReady, got %v", err)
+	}
+}
+
+func TestTokenizeForEmbedding(t *testing.T) {
+	got := tokenizeForEmbedding("func parseHTTPRequest(req_body []byte) error")
+	want := []string{"parse", "http", "request", "req", "body", "byte", "error"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenizeForEmbedding() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalEmbedder_Deterministic(t *testing.T) {
+	e := NewLocalEmbedder(0)
+	vecs, err := e.BatchEmbed(context.Background(), []string{"load config", "load config", ""})
+	if err != nil {
+		t.Fatalf("BatchEmbed failed: %v", err)
+	}
+	if len(vecs[0]) != DefaultLocalEmbeddingDim {
+		t.Errorf("expected dim %d, got %d", DefaultLocalEmbeddingDim, len(vecs[0]))
+	}
+	if sim := CosineSimilarity(vecs[0], vecs[1]); sim < 0.999 {
+		t.Errorf("expected identical texts to have similarity 1, got %f", sim)
+	}
+	if sim := CosineSimilarity(vecs[0], vecs[2]); sim != 0 {
+		t.Errorf("expected empty text to have similarity 0, got %f", sim)
+	}
+}
@@ -38,7 +38,7 @@ import (
 //	entry point is requested multiple times. The cache is automatically
 //	invalidated when:
 //	- A different entry point is requested
-//	- The underlying graph changes (detected via BuiltAtMilli)
+//	- The underlying graph changes (detected via Generation)
 //
 // Thread Safety:
 //
@@ -47,7 +47,7 @@ type DominatorTreeCache struct {
 	mu           sync.RWMutex
 	entry        string         // Entry point used for computation
 	tree         *DominatorTree // Cached dominator tree
-	graphVersion int64          // Graph BuiltAtMilli when computed
+	graphVersion int64          // Graph Generation when computed
 	computedAt   int64          // Unix milliseconds when cache was populated
 }
 
@@ -82,6 +82,12 @@ type GraphAnalyticsOptions struct {
 	// Required if EnableBadgerQueryCache is true, otherwise ignored.
 	// Must be opened and managed by caller.
 	BadgerDB *badger.DB
+
+	// AnalyticsCache caches dominator trees, SCCs and articulation points
+	// per graph generation. Pass the same cache to every analytics instance
+	// of a graph to share results between them.
+	// Default: nil (a private cache of DefaultAnalyticsCacheSize results)
+	AnalyticsCache *AnalyticsCache
 }
 
 // =============================================================================
@@ -141,6 +147,9 @@ type GraphAnalytics struct {
 	// Avoids recomputation when multiple tools request the same post-dominator tree.
 	postDomTreeCache *DominatorTreeCache
 
+	// analyticsCache caches analytics results per graph generation.
+	analyticsCache *AnalyticsCache
+
 	// CRS integration (optional) - for HLD query recording
 	crs              crs.CRS
 	hld              *HLDecomposition
@@ -184,6 +193,7 @@ func NewGraphAnalytics(graph *HierarchicalGraph) *GraphAnalytics {
 		graph:            graph,
 		domTreeCache:     &DominatorTreeCache{},
 		postDomTreeCache: &DominatorTreeCache{},
+		analyticsCache:   NewAnalyticsCache(DefaultAnalyticsCacheSize),
 		logger:           slog.Default().With(slog.String("component", "graph_analytics")),
 	}
 }
@@ -214,6 +224,10 @@ func NewGraphAnalyticsWithCRS(graph *HierarchicalGraph, hld *HLDecomposition, cr
 	if opts == nil {
 		opts = &GraphAnalyticsOptions{AutoSession: false}
 	}
+	analyticsCache := opts.AnalyticsCache
+	if analyticsCache == nil {
+		analyticsCache = NewAnalyticsCache(DefaultAnalyticsCacheSize)
+	}
 
 	return &GraphAnalytics{
 		graph:             graph,
@@ -223,6 +237,7 @@ func NewGraphAnalyticsWithCRS(graph *HierarchicalGraph, hld *HLDecomposition, cr
 		sessionTimeout:    opts.SessionTimeout,
 		domTreeCache:      &DominatorTreeCache{},
 		postDomTreeCache:  &DominatorTreeCache{},
+		analyticsCache:    analyticsCache,
 		pathQueryEngines:  make(map[AggregateFunc]*PathQueryEngine),
 		db:                opts.BadgerDB, // Can be nil
 		enableBadgerCache: opts.EnableBadgerQueryCache,
@@ -524,12 +539,23 @@ type CyclicDependency struct {
 //	Implementation uses an explicit call stack to avoid stack overflow on
 //	deep graphs (CR-2 fix).
 //
+//	Results are cached per graph generation (see AnalyticsCache).
+//
 // Outputs:
 //
 //	[]CyclicDependency - All cycles found, sorted by length descending.
+//	                     Shared with other callers; do not modify.
 //
 // Thread Safety: Safe for concurrent use.
 func (a *GraphAnalytics) CyclicDependencies() []CyclicDependency {
+	cycles, _ := cachedAnalytics(context.Background(), a, analyticsCyclicDependencies, "", func() ([]CyclicDependency, error) {
+		return a.computeCyclicDependencies(), nil
+	})
+	return cycles
+}
+
+// computeCyclicDependencies runs Tarjan's SCC algorithm without caching.
+func (a *GraphAnalytics) computeCyclicDependencies() []CyclicDependency {
 	// Tarjan's SCC state
 	index := 0
 	nodeIndex := make(map[string]int)
@@ -885,6 +911,136 @@ func (a *GraphAnalytics) GetCouplingForPackage(pkg string) *CouplingMetrics {
 	}
 }
 
+// DocCoverage computes documentation coverage for every package.
+//
+// Description:
+//
+//	Counts documentable symbols (functions, methods, and type declarations)
+//	and how many of them carry a doc comment. Exported symbols are reported
+//	separately because missing docs on the public API matter most.
+//	Reported per package alongside PackageCoupling so that poorly
+//	documented, highly depended-upon packages can be spotted together.
+//
+// Outputs:
+//
+//	map[string]DocCoverageMetrics - Metrics keyed by package path.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) DocCoverage() map[string]DocCoverageMetrics {
+	result := make(map[string]DocCoverageMetrics)
+
+	for _, pkgInfo := range a.graph.GetPackages() {
+		result[pkgInfo.Name] = docCoverageForNodes(pkgInfo.Name, a.graph.GetNodesInPackage(pkgInfo.Name))
+	}
+
+	return result
+}
+
+// DocCoverageWithCRS returns documentation coverage with a TraceStep.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) DocCoverageWithCRS(ctx context.Context) (map[string]DocCoverageMetrics, crs.TraceStep) {
+	start := time.Now()
+
+	if ctx != nil && ctx.Err() != nil {
+		step := crs.NewTraceStepBuilder().
+			WithAction("analytics_doc_coverage").
+			WithTarget("project").
+			WithTool("DocCoverage").
+			WithDuration(time.Since(start)).
+			WithError(ctx.Err().Error()).
+			Build()
+		return map[string]DocCoverageMetrics{}, step
+	}
+
+	metrics := a.DocCoverage()
+
+	// Find the least documented package and the project-wide coverage
+	leastDocumented := ""
+	lowest := 2.0 // Start above max
+	documentable, documented := 0, 0
+
+	for pkg, m := range metrics {
+		documentable += m.Documentable
+		documented += m.Documented
+		if m.Documentable > 0 && m.Coverage < lowest {
+			lowest = m.Coverage
+			leastDocumented = pkg
+		}
+	}
+
+	overall := 1.0
+	if documentable > 0 {
+		overall = float64(documented) / float64(documentable)
+	}
+
+	step := crs.NewTraceStepBuilder().
+		WithAction("analytics_doc_coverage").
+		WithTarget("project").
+		WithTool("DocCoverage").
+		WithDuration(time.Since(start)).
+		WithMetadata("packages_analyzed", itoa(len(metrics))).
+		WithMetadata("least_documented_pkg", leastDocumented).
+		WithMetadata("coverage_pct", itoa(int(overall*100))).
+		Build()
+
+	return metrics, step
+}
+
+// GetDocCoverageForPackage returns documentation coverage for a specific package.
+//
+// Inputs:
+//
+//	pkg - The package path to analyze.
+//
+// Outputs:
+//
+//	*DocCoverageMetrics - Metrics for the package, or nil if it has no nodes.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) GetDocCoverageForPackage(pkg string) *DocCoverageMetrics {
+	nodes := a.graph.GetNodesInPackage(pkg)
+	if len(nodes) == 0 {
+		return nil
+	}
+	m := docCoverageForNodes(pkg, nodes)
+	return &m
+}
+
+// docCoverageForNodes counts documented symbols among the given nodes.
+func docCoverageForNodes(pkg string, nodes []*Node) DocCoverageMetrics {
+	m := DocCoverageMetrics{Package: pkg}
+
+	for _, node := range nodes {
+		if node.Symbol == nil || !node.Symbol.Kind.IsDocumentable() {
+			continue
+		}
+		hasDoc := node.Symbol.HasDoc()
+
+		m.Documentable++
+		if hasDoc {
+			m.Documented++
+		}
+		if node.Symbol.Exported {
+			m.Exported++
+			if hasDoc {
+				m.ExportedDocumented++
+			}
+		}
+	}
+
+	m.Coverage = 1
+	if m.Documentable > 0 {
+		m.Coverage = float64(m.Documented) / float64(m.Documentable)
+	}
+	m.ExportedCoverage = 1
+	if m.Exported > 0 {
+		m.ExportedCoverage = float64(m.ExportedDocumented) / float64(m.Exported)
+	}
+
+	return m
+}
+
 // =============================================================================
 // CRS Session Management (GR-19c)
 // =============================================================================
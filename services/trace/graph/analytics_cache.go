@@ -0,0 +1,253 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultAnalyticsCacheSize is the number of analytics results kept per
+// cache when GraphAnalyticsOptions does not provide a cache.
+const DefaultAnalyticsCacheSize = 64
+
+// Analytics algorithm names used as cache keys and metric attributes.
+const (
+	analyticsDominators         = "dominators"
+	analyticsPostDominators     = "post_dominators"
+	analyticsCyclicDependencies = "cyclic_dependencies"
+	analyticsArticulationPoints = "articulation_points"
+	analyticsBetweenness        = "betweenness"
+)
+
+// =============================================================================
+// Analytics Cache
+// =============================================================================
+
+// analyticsCacheKey identifies an analytics result.
+type analyticsCacheKey struct {
+	algorithm  string
+	params     string
+	generation int64
+}
+
+// String returns the key as a singleflight group key.
+func (k analyticsCacheKey) String() string {
+	return k.algorithm + "\x00" + k.params + "\x00" + strconv.FormatInt(k.generation, 10)
+}
+
+// AnalyticsCache caches analytics results per graph generation.
+//
+// Description:
+//
+//	Dominator trees, strongly connected components, articulation points
+//	and betweenness centrality only depend on the frozen graph and the
+//	query parameters, so they are cached under (algorithm, params, graph
+//	generation). A rebuilt graph has
+//	a new generation, so results of the old graph are never returned; they
+//	age out of the LRU. Concurrent misses for the same key compute once.
+//
+//	Cached results are shared between callers and must be treated as
+//	read-only.
+//
+// Thread Safety: Safe for concurrent use.
+type AnalyticsCache struct {
+	lru    *LRUCache[analyticsCacheKey, any]
+	group  singleflight.Group
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// AnalyticsCacheStats reports the effectiveness of an AnalyticsCache.
+type AnalyticsCacheStats struct {
+	// Hits is the number of lookups served from the cache.
+	Hits int64 `json:"hits"`
+
+	// Misses is the number of lookups that had to compute.
+	Misses int64 `json:"misses"`
+
+	// Evictions is the number of results dropped to stay within Capacity.
+	Evictions int64 `json:"evictions"`
+
+	// Size is the number of cached results.
+	Size int `json:"size"`
+
+	// Capacity is the maximum number of cached results.
+	Capacity int `json:"capacity"`
+
+	// HitRate is Hits / (Hits + Misses), or 0 before the first lookup.
+	HitRate float64 `json:"hit_rate"`
+}
+
+// NewAnalyticsCache creates an analytics cache.
+//
+// Description:
+//
+//	Share one cache between the GraphAnalytics instances of a graph (and
+//	its rebuilds) through GraphAnalyticsOptions.AnalyticsCache.
+//
+// Inputs:
+//   - capacity: Maximum number of cached results. Values <= 0 use
+//     DefaultAnalyticsCacheSize.
+//
+// Outputs:
+//   - *AnalyticsCache: The cache. Never nil.
+func NewAnalyticsCache(capacity int) *AnalyticsCache {
+	if capacity <= 0 {
+		capacity = DefaultAnalyticsCacheSize
+	}
+	return &AnalyticsCache{lru: NewLRUCache[analyticsCacheKey, any](capacity)}
+}
+
+// Stats returns hit, miss and eviction counts.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *AnalyticsCache) Stats() AnalyticsCacheStats {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	stats := AnalyticsCacheStats{
+		Hits:      hits,
+		Misses:    misses,
+		Evictions: c.lru.Evictions(),
+		Size:      c.lru.Len(),
+		Capacity:  c.lru.capacity,
+	}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// Purge removes all cached results and resets the statistics.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *AnalyticsCache) Purge() {
+	c.lru.Purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+// AnalyticsCacheStats returns the statistics of the analytics cache.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) AnalyticsCacheStats() AnalyticsCacheStats {
+	if a == nil || a.analyticsCache == nil {
+		return AnalyticsCacheStats{}
+	}
+	return a.analyticsCache.Stats()
+}
+
+// cachedAnalytics returns the cached result of an analytics computation,
+// computing and caching it on a miss.
+//
+// Description:
+//
+//	Only successful results are cached: a computation that fails or is
+//	cancelled may have returned a partial result. If a concurrent caller's
+//	computation is shared and was cancelled, the result is computed again
+//	under the caller's own context.
+//
+// Inputs:
+//   - ctx: Context of the caller. Used for metrics and to decide whether a
+//     shared failure applies to this caller.
+//   - a: The analytics instance. If nil or without a cache, compute runs
+//     directly.
+//   - algorithm: The algorithm name.
+//   - params: The query parameters, e.g. the entry node.
+//   - compute: Computes the result.
+//
+// Outputs:
+//   - T: The cached or computed result.
+//   - error: The computation error, if any.
+//
+// Thread Safety: Safe for concurrent use.
+func cachedAnalytics[T any](ctx context.Context, a *GraphAnalytics, algorithm, params string, compute func() (T, error)) (T, error) {
+	if a == nil || a.analyticsCache == nil || a.graph == nil || a.graph.Graph == nil {
+		return compute()
+	}
+	c := a.analyticsCache
+	key := analyticsCacheKey{algorithm: algorithm, params: params, generation: a.graph.Generation}
+
+	if v, ok := c.lru.Get(key); ok {
+		c.hits.Add(1)
+		recordAnalyticsCacheLookup(ctx, algorithm, true)
+		return v.(T), nil
+	}
+	c.misses.Add(1)
+	recordAnalyticsCacheLookup(ctx, algorithm, false)
+
+	v, err, shared := c.group.Do(key.String(), func() (any, error) {
+		// A computation may have finished between the lookup and Do
+		if v, ok := c.lru.Get(key); ok {
+			return v, nil
+		}
+		result, err := compute()
+		if err == nil {
+			c.lru.Set(key, result)
+		}
+		return result, err
+	})
+	if err != nil && shared && isContextError(err) && (ctx == nil || ctx.Err() == nil) {
+		return compute()
+	}
+	return v.(T), err
+}
+
+// isContextError returns true if err is a context cancellation or deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// =============================================================================
+// Analytics Cache Metrics
+// =============================================================================
+
+var (
+	analyticsCacheLookups     metric.Int64Counter
+	analyticsCacheMetricsOnce sync.Once
+	analyticsCacheMetricsErr  error
+)
+
+// initAnalyticsCacheMetrics initializes the analytics cache metrics. Safe to
+// call multiple times.
+func initAnalyticsCacheMetrics() error {
+	analyticsCacheMetricsOnce.Do(func() {
+		analyticsCacheLookups, analyticsCacheMetricsErr = meter.Int64Counter(
+			"graph_analytics_cache_lookups_total",
+			metric.WithDescription("Analytics cache lookups by algorithm and result (hit or miss)"),
+		)
+	})
+	return analyticsCacheMetricsErr
+}
+
+// recordAnalyticsCacheLookup counts a cache hit or miss.
+func recordAnalyticsCacheLookup(ctx context.Context, algorithm string, hit bool) {
+	if err := initAnalyticsCacheMetrics(); err != nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	analyticsCacheLookups.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("algorithm", algorithm),
+		attribute.String("result", result),
+	))
+}
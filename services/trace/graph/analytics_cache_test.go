@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGraph_Freeze_AssignsGeneration(t *testing.T) {
+	g1 := NewGraph("/test/project")
+	g2 := NewGraph("/test/project")
+	if g1.Generation != 0 {
+		t.Errorf("Generation before Freeze = %d, want 0", g1.Generation)
+	}
+	g1.Freeze()
+	g2.Freeze()
+	if g1.Generation == 0 || g2.Generation <= g1.Generation {
+		t.Errorf("generations = %d, %d, want increasing and non-zero", g1.Generation, g2.Generation)
+	}
+
+	clone := g1.Clone()
+	clone.Freeze()
+	if clone.Generation == g1.Generation {
+		t.Error("refrozen clone shares the original's generation")
+	}
+}
+
+func TestGraphAnalytics_AnalyticsCache(t *testing.T) {
+	nodes := []string{"main", "a", "b", "c"}
+	edges := [][2]string{{"main", "a"}, {"a", "b"}, {"b", "a"}, {"a", "c"}}
+	ctx := context.Background()
+
+	t.Run("repeated queries hit", func(t *testing.T) {
+		analytics := NewGraphAnalytics(buildDominatorTestGraph(t, nodes, edges))
+
+		dt1, err := analytics.Dominators(ctx, "main")
+		if err != nil {
+			t.Fatalf("Dominators() error = %v", err)
+		}
+		dt2, _ := analytics.Dominators(ctx, "main")
+		if dt1 != dt2 {
+			t.Error("second Dominators() call was recomputed")
+		}
+		if dt3, _ := analytics.Dominators(ctx, "a"); dt3 == dt1 {
+			t.Error("different entry returned the cached tree")
+		}
+
+		ap1, _ := analytics.ArticulationPoints(ctx)
+		ap2, _ := analytics.ArticulationPoints(ctx)
+		if ap1 != ap2 {
+			t.Error("second ArticulationPoints() call was recomputed")
+		}
+		if c1, c2 := analytics.CyclicDependencies(), analytics.CyclicDependencies(); len(c1) != 1 || &c1[0] != &c2[0] {
+			t.Errorf("CyclicDependencies() not cached: %v", c1)
+		}
+
+		stats := analytics.AnalyticsCacheStats()
+		if stats.Hits != 3 || stats.Misses != 4 || stats.Size != 4 {
+			t.Errorf("stats = %+v, want 3 hits, 4 misses, 4 entries", stats)
+		}
+		if stats.HitRate != 3.0/7.0 {
+			t.Errorf("HitRate = %v, want 3/7", stats.HitRate)
+		}
+	})
+
+	t.Run("shared cache is keyed by generation", func(t *testing.T) {
+		cache := NewAnalyticsCache(8)
+		hg1 := buildDominatorTestGraph(t, nodes, edges)
+		hg2 := buildDominatorTestGraph(t, nodes, edges)
+		a1 := NewGraphAnalyticsWithCRS(hg1, &HLDecomposition{}, nil, &GraphAnalyticsOptions{AnalyticsCache: cache})
+		a1b := NewGraphAnalyticsWithCRS(hg1, &HLDecomposition{}, nil, &GraphAnalyticsOptions{AnalyticsCache: cache})
+		a2 := NewGraphAnalyticsWithCRS(hg2, &HLDecomposition{}, nil, &GraphAnalyticsOptions{AnalyticsCache: cache})
+
+		dt1, _ := a1.Dominators(ctx, "main")
+		if dt, _ := a1b.Dominators(ctx, "main"); dt != dt1 {
+			t.Error("analytics of the same graph did not share the cached tree")
+		}
+		if dt, _ := a2.Dominators(ctx, "main"); dt == dt1 {
+			t.Error("a rebuilt graph returned the previous generation's tree")
+		}
+	})
+
+	t.Run("size bound evicts", func(t *testing.T) {
+		cache := NewAnalyticsCache(2)
+		analytics := NewGraphAnalyticsWithCRS(buildDominatorTestGraph(t, nodes, edges),
+			&HLDecomposition{}, nil, &GraphAnalyticsOptions{AnalyticsCache: cache})
+		for _, entry := range nodes {
+			if _, err := analytics.Dominators(ctx, entry); err != nil {
+				t.Fatalf("Dominators(%s) error = %v", entry, err)
+			}
+		}
+		stats := cache.Stats()
+		if stats.Size != 2 || stats.Evictions != 2 || stats.Capacity != 2 {
+			t.Errorf("stats = %+v, want 2 entries and 2 evictions", stats)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		analytics := NewGraphAnalytics(buildDominatorTestGraph(t, nodes, edges))
+		if _, err := analytics.Dominators(ctx, "missing"); err == nil {
+			t.Fatal("Dominators(missing) should fail")
+		}
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := analytics.ArticulationPoints(cancelled); err == nil {
+			t.Fatal("ArticulationPoints(cancelled) should fail")
+		}
+		if size := analytics.AnalyticsCacheStats().Size; size != 0 {
+			t.Errorf("Size = %d after failed computations, want 0", size)
+		}
+		if result, err := analytics.ArticulationPoints(ctx); err != nil || result.NodeCount != len(nodes) {
+			t.Errorf("ArticulationPoints() after cancellation = %+v, %v", result, err)
+		}
+	})
+}
+
+func TestCachedAnalytics_ConcurrentMissesComputeOnce(t *testing.T) {
+	analytics := NewGraphAnalytics(buildDominatorTestGraph(t, []string{"a"}, nil))
+
+	var computed atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cachedAnalytics(context.Background(), analytics, "test", "", func() (int, error) {
+				computed.Add(1)
+				<-release
+				return 42, nil
+			})
+		}()
+	}
+	// Let the goroutines pile up behind the first computation
+	for analytics.AnalyticsCacheStats().Misses < 8 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if n := computed.Load(); n != 1 {
+		t.Errorf("computed %d times, want 1", n)
+	}
+}
+
+func TestCachedAnalytics_SharedCancellationRecomputes(t *testing.T) {
+	analytics := NewGraphAnalytics(buildDominatorTestGraph(t, []string{"a"}, nil))
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, _ = cachedAnalytics(leaderCtx, analytics, "test", "", func() (int, error) {
+			close(started)
+			<-leaderCtx.Done()
+			return 0, leaderCtx.Err()
+		})
+	}()
+	<-started
+
+	followerDone := make(chan int)
+	go func() {
+		v, _ := cachedAnalytics(context.Background(), analytics, "test", "", func() (int, error) {
+			return 7, nil
+		})
+		followerDone <- v
+	}()
+	for analytics.AnalyticsCacheStats().Misses < 2 {
+		runtime.Gosched()
+	}
+	cancelLeader()
+	<-leaderDone
+
+	if v := <-followerDone; v != 7 {
+		t.Errorf("follower got %d, want its own result 7", v)
+	}
+}
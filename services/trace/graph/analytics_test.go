@@ -660,17 +660,22 @@ func (m *mockCRS) Snapshot() crs.Snapshot { return nil }
 func (m *mockCRS) Apply(context.Context, crs.Delta) (crs.ApplyMetrics, error) {
 	return crs.ApplyMetrics{}, nil
 }
-func (m *mockCRS) Generation() int64                                         { return 0 }
-func (m *mockCRS) Checkpoint(context.Context) (crs.Checkpoint, error)        { return crs.Checkpoint{}, nil }
-func (m *mockCRS) Restore(context.Context, crs.Checkpoint) error             { return nil }
-func (m *mockCRS) RecordStep(ctx context.Context, step crs.StepRecord) error { return step.Validate() }
-func (m *mockCRS) GetLastStep(string) *crs.StepRecord                        { return nil }
-func (m *mockCRS) CountToolExecutions(string, string) int                    { return 0 }
-func (m *mockCRS) GetStepsByActor(string, crs.Actor) []crs.StepRecord        { return nil }
-func (m *mockCRS) GetStepsByOutcome(string, crs.Outcome) []crs.StepRecord    { return nil }
-func (m *mockCRS) ClearStepHistory(string)                                   {}
-func (m *mockCRS) UpdateProofNumber(context.Context, crs.ProofUpdate) error  { return nil }
-func (m *mockCRS) GetProofStatus(string) (crs.ProofNumber, bool)             { return crs.ProofNumber{}, false }
+func (m *mockCRS) Generation() int64                                             { return 0 }
+func (m *mockCRS) Watch(context.Context, crs.WatchOptions) <-chan crs.WatchEvent { return nil }
+func (m *mockCRS) Checkpoint(context.Context) (crs.Checkpoint, error)            { return crs.Checkpoint{}, nil }
+func (m *mockCRS) Restore(context.Context, crs.Checkpoint) error                 { return nil }
+func (m *mockCRS) Persist(context.Context, string) error                         { return nil }
+func (m *mockCRS) RestoreFrom(context.Context, string) error                     { return nil }
+func (m *mockCRS) SetDeltaJournal(*crs.DeltaJournal)                             {}
+func (m *mockCRS) ReplayJournal(context.Context, int64, int64) (crs.CRS, error)  { return nil, nil }
+func (m *mockCRS) RecordStep(ctx context.Context, step crs.StepRecord) error     { return step.Validate() }
+func (m *mockCRS) GetLastStep(string) *crs.StepRecord                            { return nil }
+func (m *mockCRS) CountToolExecutions(string, string) int                        { return 0 }
+func (m *mockCRS) GetStepsByActor(string, crs.Actor) []crs.StepRecord            { return nil }
+func (m *mockCRS) GetStepsByOutcome(string, crs.Outcome) []crs.StepRecord        { return nil }
+func (m *mockCRS) ClearStepHistory(string)                                       {}
+func (m *mockCRS) UpdateProofNumber(context.Context, crs.ProofUpdate) error      { return nil }
+func (m *mockCRS) GetProofStatus(string) (crs.ProofNumber, bool)                 { return crs.ProofNumber{}, false }
 func (m *mockCRS) CheckCircuitBreaker(string, string) crs.CircuitBreakerResult {
 	return crs.CircuitBreakerResult{}
 }
@@ -1931,3 +1936,98 @@ func buildTestHLDTree(t *testing.T) (*HierarchicalGraph, *HLDecomposition, map[s
 
 	return hg, hld, nodeIDs
 }
+
+// =============================================================================
+// DocCoverage Tests
+// =============================================================================
+
+func buildDocCoverageGraph(t *testing.T) *HierarchicalGraph {
+	t.Helper()
+
+	g := NewGraph("/test/project")
+
+	symbols := []*ast.Symbol{
+		{ID: "pkg/a/a.go:10:Documented", Name: "Documented", Kind: ast.SymbolKindFunction, Package: "pkg/a",
+			FilePath: "pkg/a/a.go", Exported: true, DocComment: "// Documented does things."},
+		{ID: "pkg/a/a.go:20:Undocumented", Name: "Undocumented", Kind: ast.SymbolKindFunction, Package: "pkg/a",
+			FilePath: "pkg/a/a.go", Exported: true},
+		{ID: "pkg/a/a.go:30:helper", Name: "helper", Kind: ast.SymbolKindFunction, Package: "pkg/a",
+			FilePath: "pkg/a/a.go", DocComment: "// helper assists."},
+		{ID: "pkg/a/a.go:40:limit", Name: "limit", Kind: ast.SymbolKindConstant, Package: "pkg/a",
+			FilePath: "pkg/a/a.go"},
+		{ID: "pkg/b/b.go:10:Service", Name: "Service", Kind: ast.SymbolKindStruct, Package: "pkg/b",
+			FilePath: "pkg/b/b.go", Exported: true, DocComment: "// Service serves."},
+	}
+	for _, sym := range symbols {
+		mustAddNode(t, g, sym)
+	}
+	mustAddEdge(t, g, symbols[0].ID, symbols[4].ID, EdgeTypeCalls)
+
+	g.Freeze()
+
+	hg, err := WrapGraph(g)
+	if err != nil {
+		t.Fatalf("WrapGraph failed: %v", err)
+	}
+
+	return hg
+}
+
+func TestDocCoverage(t *testing.T) {
+	hg := buildDocCoverageGraph(t)
+	analytics := NewGraphAnalytics(hg)
+
+	metrics := analytics.DocCoverage()
+
+	a, ok := metrics["pkg/a"]
+	if !ok {
+		t.Fatal("expected metrics for pkg/a")
+	}
+	// Constants are not documentable.
+	if a.Documentable != 3 || a.Documented != 2 {
+		t.Errorf("expected 2/3 documented, got %d/%d", a.Documented, a.Documentable)
+	}
+	if a.Exported != 2 || a.ExportedDocumented != 1 {
+		t.Errorf("expected 1/2 exported documented, got %d/%d", a.ExportedDocumented, a.Exported)
+	}
+	if a.ExportedCoverage != 0.5 {
+		t.Errorf("expected ExportedCoverage=0.5, got %f", a.ExportedCoverage)
+	}
+
+	b := metrics["pkg/b"]
+	if b.Coverage != 1.0 {
+		t.Errorf("expected pkg/b Coverage=1.0, got %f", b.Coverage)
+	}
+}
+
+func TestDocCoverageWithCRS(t *testing.T) {
+	hg := buildDocCoverageGraph(t)
+	analytics := NewGraphAnalytics(hg)
+
+	metrics, traceStep := analytics.DocCoverageWithCRS(context.Background())
+
+	if len(metrics) != 2 {
+		t.Errorf("expected 2 packages, got %d", len(metrics))
+	}
+	if traceStep.Action != "analytics_doc_coverage" {
+		t.Errorf("expected Action='analytics_doc_coverage', got '%s'", traceStep.Action)
+	}
+	if traceStep.Metadata["least_documented_pkg"] != "pkg/a" {
+		t.Errorf("expected least_documented_pkg='pkg/a', got '%s'", traceStep.Metadata["least_documented_pkg"])
+	}
+	if traceStep.Metadata["coverage_pct"] != "75" {
+		t.Errorf("expected coverage_pct='75', got '%s'", traceStep.Metadata["coverage_pct"])
+	}
+}
+
+func TestGetDocCoverageForPackage(t *testing.T) {
+	hg := buildDocCoverageGraph(t)
+	analytics := NewGraphAnalytics(hg)
+
+	if m := analytics.GetDocCoverageForPackage("pkg/b"); m == nil || m.Documented != 1 {
+		t.Errorf("expected 1 documented symbol in pkg/b, got %+v", m)
+	}
+	if m := analytics.GetDocCoverageForPackage("pkg/missing"); m != nil {
+		t.Errorf("expected nil for unknown package, got %+v", m)
+	}
+}
@@ -0,0 +1,490 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// =============================================================================
+// Betweenness Centrality
+// =============================================================================
+
+var betweennessTracer = otel.Tracer("graph.betweenness")
+
+// Betweenness configuration constants.
+const (
+	// DefaultBetweennessSampleThreshold is the node count above which
+	// shortest paths are computed from a sample of sources only.
+	DefaultBetweennessSampleThreshold = 2000
+
+	// DefaultBetweennessSamples is the number of sampled sources.
+	DefaultBetweennessSamples = 256
+
+	// DefaultBetweennessSeed seeds source sampling, so repeated queries
+	// on the same graph return the same scores.
+	DefaultBetweennessSeed = 1
+)
+
+// BetweennessOptions configures the betweenness centrality computation.
+type BetweennessOptions struct {
+	// Normalized divides scores by (n-1)(n-2), the number of ordered pairs
+	// of other nodes, so scores are in [0, 1]. Default: true
+	Normalized bool
+
+	// SampleThreshold is the node count above which sources are sampled.
+	// Negative means never sample. Default: 2000
+	SampleThreshold int
+
+	// Samples is the number of sampled sources. Must be > 0. Default: 256
+	Samples int
+
+	// Seed seeds source sampling. Default: 1
+	Seed int64
+}
+
+// Validate checks options and applies defaults for invalid values.
+func (o *BetweennessOptions) Validate() {
+	if o.SampleThreshold == 0 {
+		o.SampleThreshold = DefaultBetweennessSampleThreshold
+	}
+	if o.Samples <= 0 {
+		o.Samples = DefaultBetweennessSamples
+	}
+}
+
+// DefaultBetweennessOptions returns sensible defaults.
+func DefaultBetweennessOptions() *BetweennessOptions {
+	return &BetweennessOptions{
+		Normalized:      true,
+		SampleThreshold: DefaultBetweennessSampleThreshold,
+		Samples:         DefaultBetweennessSamples,
+		Seed:            DefaultBetweennessSeed,
+	}
+}
+
+// BetweennessResult contains the output of betweenness centrality.
+type BetweennessResult struct {
+	// Scores maps nodeID to betweenness score.
+	Scores map[string]float64
+
+	// Sampled indicates scores were estimated from a sample of sources.
+	Sampled bool
+
+	// Sources is the number of sources shortest paths were computed from.
+	Sources int
+
+	// NodeCount is the total nodes analyzed.
+	NodeCount int
+
+	// EdgeCount is the total edges analyzed.
+	EdgeCount int
+}
+
+// BetweennessNode represents a node with its betweenness score and rank.
+type BetweennessNode struct {
+	// Node is the graph node.
+	Node *Node
+
+	// Score is the betweenness score.
+	Score float64
+
+	// Rank is the position in the ranking (1-indexed).
+	Rank int
+}
+
+// BetweennessCentrality computes betweenness centrality for all nodes.
+//
+// Description:
+//
+//	Uses Brandes' algorithm to compute, for each node, the fraction of
+//	shortest call paths between other nodes that pass through it. A
+//	function with high betweenness sits between otherwise separate parts
+//	of the code base, so a bug there affects many flows: it answers
+//	"what is the most critical function?" better than caller counts.
+//
+//	Edges are followed in their direction, and parallel edges and self
+//	loops are ignored. Graphs with more than opts.SampleThreshold nodes
+//	use opts.Samples randomly chosen sources and scale the result by
+//	n / samples, an unbiased estimate of the exact scores.
+//
+// Inputs:
+//
+//   - ctx: Context for cancellation. Must not be nil. Checked once per source.
+//   - opts: Configuration options. If nil, defaults are used.
+//
+// Outputs:
+//
+//   - *BetweennessResult: Scores for all nodes. Never nil.
+//   - error: Non-nil only on context cancellation. Partial scores, from the
+//     sources processed so far, are still returned.
+//
+// Example:
+//
+//	result, err := analytics.BetweennessCentrality(ctx, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	if result.Sampled {
+//	    log.Printf("estimated from %d sources", result.Sources)
+//	}
+//
+// Limitations:
+//
+//   - Unweighted: every call counts as one hop
+//   - Sampled scores are estimates; nodes with close scores may swap ranks
+//
+// Caching: Results are cached per graph generation and options (see
+// AnalyticsCache). The returned result is shared with other callers; do
+// not modify it.
+//
+// Thread Safety: Safe for concurrent use (read-only on graph).
+//
+// Complexity: O(S × (V + E)) time, O(V + E) space, where S is V or the
+// sample size.
+func (a *GraphAnalytics) BetweennessCentrality(ctx context.Context, opts *BetweennessOptions) (*BetweennessResult, error) {
+	if opts == nil {
+		opts = DefaultBetweennessOptions()
+	} else {
+		opts.Validate()
+	}
+	params := fmt.Sprintf("normalized=%t,threshold=%d,samples=%d,seed=%d",
+		opts.Normalized, opts.SampleThreshold, opts.Samples, opts.Seed)
+	return cachedAnalytics(ctx, a, analyticsBetweenness, params, func() (*BetweennessResult, error) {
+		return a.computeBetweenness(ctx, opts)
+	})
+}
+
+// computeBetweenness computes betweenness centrality without caching.
+func (a *GraphAnalytics) computeBetweenness(ctx context.Context, opts *BetweennessOptions) (*BetweennessResult, error) {
+	result := &BetweennessResult{Scores: make(map[string]float64)}
+	if a.graph == nil || a.graph.Graph == nil {
+		return result, nil
+	}
+
+	ctx, span := betweennessTracer.Start(ctx, "GraphAnalytics.BetweennessCentrality",
+		trace.WithAttributes(
+			attribute.Int("node_count", a.graph.NodeCount()),
+			attribute.Int("edge_count", a.graph.EdgeCount()),
+		),
+	)
+	defer span.End()
+
+	result.NodeCount = a.graph.NodeCount()
+	result.EdgeCount = a.graph.EdgeCount()
+	n := result.NodeCount
+	if n == 0 {
+		span.AddEvent("empty_graph")
+		return result, nil
+	}
+
+	// Index nodes in ID order so sampling is reproducible
+	nodes := make(map[string]*Node, n)
+	ids := make([]string, 0, n)
+	for id, node := range a.graph.Nodes() {
+		nodes[id] = node
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	indexOf := make(map[string]int, n)
+	for i, id := range ids {
+		indexOf[id] = i
+	}
+
+	adjacency := make([][]int, n)
+	for i, id := range ids {
+		seen := make(map[int]bool)
+		for _, edge := range nodes[id].Outgoing {
+			j, ok := indexOf[edge.ToID]
+			if !ok || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			adjacency[i] = append(adjacency[i], j)
+		}
+	}
+
+	sources := make([]int, n)
+	for i := range sources {
+		sources[i] = i
+	}
+	scale := 1.0
+	if opts.SampleThreshold > 0 && n > opts.SampleThreshold && opts.Samples < n {
+		rng := rand.New(rand.NewSource(opts.Seed))
+		rng.Shuffle(n, func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+		sources = sources[:opts.Samples]
+		scale = float64(n) / float64(opts.Samples)
+		result.Sampled = true
+	}
+
+	span.SetAttributes(
+		attribute.Bool("sampled", result.Sampled),
+		attribute.Int("sources", len(sources)),
+	)
+
+	scores := make([]float64, n)
+	var cancelErr error
+	state := newBrandesState(n)
+	for processed, s := range sources {
+		if err := ctx.Err(); err != nil {
+			span.AddEvent("cancelled", trace.WithAttributes(
+				attribute.Int("sources_completed", processed),
+			))
+			cancelErr = err
+			break
+		}
+		state.accumulate(s, adjacency, scores)
+		result.Sources++
+	}
+
+	if opts.Normalized && n > 2 {
+		scale /= float64((n - 1) * (n - 2))
+	}
+	for i, id := range ids {
+		result.Scores[id] = scores[i] * scale
+	}
+
+	slog.Debug("Betweenness centrality completed",
+		slog.Int("node_count", n),
+		slog.Int("sources", result.Sources),
+		slog.Bool("sampled", result.Sampled),
+	)
+
+	return result, cancelErr
+}
+
+// brandesState holds the per-source buffers of Brandes' algorithm, reused
+// across sources.
+type brandesState struct {
+	sigma []float64 // number of shortest paths from the source
+	dist  []int     // distance from the source, -1 if unreached
+	delta []float64 // dependency of the source on each node
+	preds [][]int   // predecessors on shortest paths
+	order []int     // nodes in non-decreasing distance
+	queue []int
+}
+
+func newBrandesState(n int) *brandesState {
+	return &brandesState{
+		sigma: make([]float64, n),
+		dist:  make([]int, n),
+		delta: make([]float64, n),
+		preds: make([][]int, n),
+		order: make([]int, 0, n),
+		queue: make([]int, 0, n),
+	}
+}
+
+// accumulate adds the dependencies of source s to scores.
+func (b *brandesState) accumulate(s int, adjacency [][]int, scores []float64) {
+	for i := range b.dist {
+		b.sigma[i] = 0
+		b.dist[i] = -1
+		b.delta[i] = 0
+		b.preds[i] = b.preds[i][:0]
+	}
+	b.order = b.order[:0]
+	b.queue = append(b.queue[:0], s)
+	b.sigma[s] = 1
+	b.dist[s] = 0
+
+	// Breadth-first search counting shortest paths
+	for head := 0; head < len(b.queue); head++ {
+		v := b.queue[head]
+		b.order = append(b.order, v)
+		for _, w := range adjacency[v] {
+			if b.dist[w] < 0 {
+				b.dist[w] = b.dist[v] + 1
+				b.queue = append(b.queue, w)
+			}
+			if b.dist[w] == b.dist[v]+1 {
+				b.sigma[w] += b.sigma[v]
+				b.preds[w] = append(b.preds[w], v)
+			}
+		}
+	}
+
+	// Back-propagate dependencies, farthest nodes first
+	for i := len(b.order) - 1; i >= 0; i-- {
+		w := b.order[i]
+		for _, v := range b.preds[w] {
+			b.delta[v] += b.sigma[v] / b.sigma[w] * (1 + b.delta[w])
+		}
+		if w != s {
+			scores[w] += b.delta[w]
+		}
+	}
+}
+
+// BetweennessCentralityWithCRS wraps BetweennessCentrality with CRS tracing.
+//
+// Description:
+//
+//	Provides the same functionality as BetweennessCentrality but also
+//	returns a TraceStep for recording in the Code Reasoning State (CRS).
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) BetweennessCentralityWithCRS(ctx context.Context, opts *BetweennessOptions) (*BetweennessResult, crs.TraceStep) {
+	start := time.Now()
+
+	// Early cancellation check
+	if ctx != nil && ctx.Err() != nil {
+		step := crs.NewTraceStepBuilder().
+			WithAction("analytics_betweenness").
+			WithTarget("graph").
+			WithTool("BetweennessCentrality").
+			WithDuration(time.Since(start)).
+			WithError(ctx.Err().Error()).
+			Build()
+		return &BetweennessResult{Scores: make(map[string]float64)}, step
+	}
+
+	result, err := a.BetweennessCentrality(ctx, opts)
+	if err != nil {
+		step := crs.NewTraceStepBuilder().
+			WithAction("analytics_betweenness").
+			WithTarget("graph").
+			WithTool("BetweennessCentrality").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return result, step
+	}
+
+	step := crs.NewTraceStepBuilder().
+		WithAction("analytics_betweenness").
+		WithTarget("graph").
+		WithTool("BetweennessCentrality").
+		WithDuration(time.Since(start)).
+		WithMetadata("sampled", btoa(result.Sampled)).
+		WithMetadata("sources", itoa(result.Sources)).
+		WithMetadata("node_count", itoa(result.NodeCount)).
+		WithMetadata("edge_count", itoa(result.EdgeCount)).
+		Build()
+
+	return result, step
+}
+
+// BetweennessTop returns the top-k nodes by betweenness centrality.
+//
+// Description:
+//
+//	Computes betweenness centrality and returns the k highest scoring
+//	nodes, ties broken by node ID. Nodes with a score of zero lie on no
+//	shortest path between other nodes and are not returned.
+//
+// Inputs:
+//
+//   - ctx: Context for cancellation. Must not be nil.
+//   - k: Number of top nodes to return. Must be > 0.
+//   - opts: Configuration options. If nil, defaults are used.
+//
+// Outputs:
+//
+//   - []BetweennessNode: Top-k nodes sorted by score descending.
+//   - error: Non-nil on context cancellation; the ranking is then of
+//     partial scores.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) BetweennessTop(ctx context.Context, k int, opts *BetweennessOptions) ([]BetweennessNode, error) {
+	if k <= 0 {
+		return []BetweennessNode{}, nil
+	}
+
+	result, err := a.BetweennessCentrality(ctx, opts)
+
+	ids := make([]string, 0, len(result.Scores))
+	for id, score := range result.Scores {
+		if score > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		si, sj := result.Scores[ids[i]], result.Scores[ids[j]]
+		if si != sj {
+			return si > sj
+		}
+		return ids[i] < ids[j]
+	})
+	if k > len(ids) {
+		k = len(ids)
+	}
+
+	top := make([]BetweennessNode, k)
+	for i := 0; i < k; i++ {
+		node, _ := a.graph.GetNode(ids[i])
+		top[i] = BetweennessNode{
+			Node:  node,
+			Score: result.Scores[ids[i]],
+			Rank:  i + 1,
+		}
+	}
+	return top, err
+}
+
+// BetweennessTopWithCRS returns top-k nodes with a TraceStep for CRS recording.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *GraphAnalytics) BetweennessTopWithCRS(ctx context.Context, k int, opts *BetweennessOptions) ([]BetweennessNode, crs.TraceStep) {
+	start := time.Now()
+
+	// Early cancellation check
+	if ctx != nil && ctx.Err() != nil {
+		step := crs.NewTraceStepBuilder().
+			WithAction("analytics_betweenness_top").
+			WithTarget("graph").
+			WithTool("BetweennessTop").
+			WithDuration(time.Since(start)).
+			WithError(ctx.Err().Error()).
+			Build()
+		return []BetweennessNode{}, step
+	}
+
+	top, err := a.BetweennessTop(ctx, k, opts)
+	if err != nil {
+		step := crs.NewTraceStepBuilder().
+			WithAction("analytics_betweenness_top").
+			WithTarget("graph").
+			WithTool("BetweennessTop").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return top, step
+	}
+
+	topScore := 0.0
+	if len(top) > 0 {
+		topScore = top[0].Score
+	}
+
+	step := crs.NewTraceStepBuilder().
+		WithAction("analytics_betweenness_top").
+		WithTarget("graph").
+		WithTool("BetweennessTop").
+		WithDuration(time.Since(start)).
+		WithMetadata("requested", itoa(k)).
+		WithMetadata("returned", itoa(len(top))).
+		WithMetadata("top_score", ftoa(topScore)).
+		WithMetadata("total_nodes", itoa(a.graph.NodeCount())).
+		Build()
+
+	return top, step
+}
@@ -0,0 +1,256 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Betweenness Centrality Tests
+// =============================================================================
+
+// exactBetweenness returns options computing exact, unnormalized scores.
+func exactBetweenness() *BetweennessOptions {
+	return &BetweennessOptions{SampleThreshold: -1}
+}
+
+func assertScores(t *testing.T, got map[string]float64, want map[string]float64) {
+	t.Helper()
+	for id, w := range want {
+		if math.Abs(got[id]-w) > 1e-9 {
+			t.Errorf("score[%s] = %v, want %v", id, got[id], w)
+		}
+	}
+}
+
+func TestBetweennessOptions_Validate(t *testing.T) {
+	opts := BetweennessOptions{}
+	opts.Validate()
+	if opts.SampleThreshold != DefaultBetweennessSampleThreshold || opts.Samples != DefaultBetweennessSamples {
+		t.Errorf("Validate() = %+v, want default threshold and samples", opts)
+	}
+
+	opts = BetweennessOptions{SampleThreshold: -1, Samples: 10}
+	opts.Validate()
+	if opts.SampleThreshold != -1 || opts.Samples != 10 {
+		t.Errorf("Validate() changed valid options: %+v", opts)
+	}
+}
+
+func TestBetweenness_EmptyGraph(t *testing.T) {
+	analytics := NewGraphAnalytics(createEmptyGraph())
+	result, err := analytics.BetweennessCentrality(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("BetweennessCentrality: %v", err)
+	}
+	if len(result.Scores) != 0 || result.Sources != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestBetweenness_Chain(t *testing.T) {
+	// A -> B -> C -> D
+	g := newTestGraph("test").
+		addNode("A", "a.go").addNode("B", "b.go").addNode("C", "c.go").addNode("D", "d.go").
+		addEdge("A", "B", EdgeTypeCalls).
+		addEdge("B", "C", EdgeTypeCalls).
+		addEdge("C", "D", EdgeTypeCalls).
+		build()
+	analytics := NewGraphAnalytics(g)
+
+	result, err := analytics.BetweennessCentrality(context.Background(), exactBetweenness())
+	if err != nil {
+		t.Fatalf("BetweennessCentrality: %v", err)
+	}
+	// B is on A->C and A->D; C is on A->D and B->D.
+	assertScores(t, result.Scores, map[string]float64{"A": 0, "B": 2, "C": 2, "D": 0})
+	if result.Sampled || result.Sources != 4 {
+		t.Errorf("expected 4 exact sources, got sampled=%v sources=%d", result.Sampled, result.Sources)
+	}
+
+	normalized, err := analytics.BetweennessCentrality(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("BetweennessCentrality normalized: %v", err)
+	}
+	assertScores(t, normalized.Scores, map[string]float64{"B": 2.0 / 6, "C": 2.0 / 6})
+}
+
+func TestBetweenness_SplitsEqualPaths(t *testing.T) {
+	// A -> B -> D and A -> C -> D are both shortest paths from A to D.
+	g := newTestGraph("test").
+		addNode("A", "a.go").addNode("B", "b.go").addNode("C", "c.go").addNode("D", "d.go").
+		addEdge("A", "B", EdgeTypeCalls).
+		addEdge("A", "C", EdgeTypeCalls).
+		addEdge("B", "D", EdgeTypeCalls).
+		addEdge("C", "D", EdgeTypeCalls).
+		build()
+
+	result, err := NewGraphAnalytics(g).BetweennessCentrality(context.Background(), exactBetweenness())
+	if err != nil {
+		t.Fatalf("BetweennessCentrality: %v", err)
+	}
+	assertScores(t, result.Scores, map[string]float64{"A": 0, "B": 0.5, "C": 0.5, "D": 0})
+}
+
+func TestBetweenness_IgnoresParallelEdgesAndSelfLoops(t *testing.T) {
+	g := newTestGraph("test").
+		addNode("A", "a.go").addNode("B", "b.go").addNode("C", "c.go").
+		addEdge("A", "B", EdgeTypeCalls).
+		addEdge("A", "B", EdgeTypeReferences).
+		addEdge("B", "B", EdgeTypeCalls).
+		addEdge("B", "C", EdgeTypeCalls).
+		build()
+
+	result, err := NewGraphAnalytics(g).BetweennessCentrality(context.Background(), exactBetweenness())
+	if err != nil {
+		t.Fatalf("BetweennessCentrality: %v", err)
+	}
+	assertScores(t, result.Scores, map[string]float64{"A": 0, "B": 1, "C": 0})
+}
+
+// hubGraph returns a graph where n callers call a hub that calls n callees.
+func hubGraph(n int) *HierarchicalGraph {
+	b := newTestGraph("hub").addNode("hub", "hub.go")
+	for i := 0; i < n; i++ {
+		b.addNode("in"+itoa(i), "in.go").addNode("out"+itoa(i), "out.go")
+		b.addEdge("in"+itoa(i), "hub", EdgeTypeCalls)
+		b.addEdge("hub", "out"+itoa(i), EdgeTypeCalls)
+	}
+	return b.build()
+}
+
+func TestBetweenness_Sampling(t *testing.T) {
+	g := hubGraph(30)
+	opts := &BetweennessOptions{SampleThreshold: 10, Samples: 20, Seed: 7}
+
+	result, err := NewGraphAnalytics(g).BetweennessCentrality(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("BetweennessCentrality: %v", err)
+	}
+	if !result.Sampled || result.Sources != 20 {
+		t.Fatalf("expected 20 sampled sources, got sampled=%v sources=%d", result.Sampled, result.Sources)
+	}
+
+	// Exact hub score is 30*30; the estimate is scaled from the sample.
+	exact := 900.0
+	if hub := result.Scores["hub"]; hub < exact/3 || hub > exact*3 {
+		t.Errorf("sampled hub score = %v, want roughly %v", hub, exact)
+	}
+
+	// Same seed, same sample.
+	again, err := NewGraphAnalytics(g).BetweennessCentrality(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("BetweennessCentrality again: %v", err)
+	}
+	if again.Scores["hub"] != result.Scores["hub"] {
+		t.Errorf("sampling not reproducible: %v != %v", again.Scores["hub"], result.Scores["hub"])
+	}
+
+	// No sampling when the sample would cover the graph.
+	full, err := NewGraphAnalytics(g).BetweennessCentrality(context.Background(),
+		&BetweennessOptions{SampleThreshold: 10, Samples: 1000})
+	if err != nil {
+		t.Fatalf("BetweennessCentrality full: %v", err)
+	}
+	if full.Sampled || full.Scores["hub"] != exact {
+		t.Errorf("expected exact hub score %v, got %v (sampled=%v)", exact, full.Scores["hub"], full.Sampled)
+	}
+}
+
+func TestBetweenness_ContextCancellation(t *testing.T) {
+	analytics := NewGraphAnalytics(hubGraph(10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := analytics.BetweennessCentrality(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if result == nil || result.Sources != 0 {
+		t.Fatalf("expected empty partial result, got %+v", result)
+	}
+
+	// A cancelled computation is not cached.
+	result, err = analytics.BetweennessCentrality(context.Background(), nil)
+	if err != nil || result.Sources != 21 {
+		t.Errorf("expected complete result after cancellation, got %+v, %v", result, err)
+	}
+}
+
+func TestBetweennessTop(t *testing.T) {
+	g := newTestGraph("test").
+		addNode("A", "a.go").addNode("B", "b.go").addNode("C", "c.go").addNode("D", "d.go").
+		addEdge("A", "B", EdgeTypeCalls).
+		addEdge("B", "C", EdgeTypeCalls).
+		addEdge("C", "D", EdgeTypeCalls).
+		build()
+	analytics := NewGraphAnalytics(g)
+
+	top, err := analytics.BetweennessTop(context.Background(), 10, nil)
+	if err != nil {
+		t.Fatalf("BetweennessTop: %v", err)
+	}
+	// A and D lie on no path between other nodes; B and C tie, by ID.
+	if len(top) != 2 || top[0].Node.ID != "B" || top[1].Node.ID != "C" || top[1].Rank != 2 {
+		t.Errorf("top = %+v, want B then C", top)
+	}
+
+	if top, _ := analytics.BetweennessTop(context.Background(), 0, nil); len(top) != 0 {
+		t.Errorf("BetweennessTop(0) = %v, want empty", top)
+	}
+}
+
+func TestBetweennessWithCRS(t *testing.T) {
+	analytics := NewGraphAnalytics(hubGraph(3))
+
+	result, step := analytics.BetweennessCentralityWithCRS(context.Background(), nil)
+	if result == nil || result.Scores["hub"] == 0 {
+		t.Fatalf("expected hub score, got %+v", result)
+	}
+	if step.Action != "analytics_betweenness" || step.Tool != "BetweennessCentrality" {
+		t.Errorf("unexpected step action %q tool %q", step.Action, step.Tool)
+	}
+	if step.Metadata["sources"] != "7" || step.Metadata["sampled"] != "false" {
+		t.Errorf("unexpected metadata %v", step.Metadata)
+	}
+
+	top, step := analytics.BetweennessTopWithCRS(context.Background(), 1, nil)
+	if len(top) != 1 || top[0].Node.ID != "hub" {
+		t.Errorf("top = %+v, want hub", top)
+	}
+	if step.Action != "analytics_betweenness_top" || step.Metadata["returned"] != "1" {
+		t.Errorf("unexpected step %+v", step)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, step := analytics.BetweennessCentralityWithCRS(ctx, nil); step.Error == "" {
+		t.Error("expected error in step for cancelled context")
+	}
+	if _, step := analytics.BetweennessTopWithCRS(ctx, 1, nil); step.Error == "" {
+		t.Error("expected error in step for cancelled context")
+	}
+}
+
+func BenchmarkBetweenness_1000Nodes(b *testing.B) {
+	analytics := NewGraphAnalytics(createBenchmarkGraph(1000))
+	ctx := context.Background()
+	opts := &BetweennessOptions{SampleThreshold: -1}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = analytics.computeBetweenness(ctx, opts)
+	}
+}
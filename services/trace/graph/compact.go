@@ -0,0 +1,150 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// stringPool interns strings so that equal strings share one allocation.
+//
+// Symbols and edges repeat the same file paths, package names and
+// languages many times. Parsers usually share these strings already, but
+// graphs decoded from storage or the network (e.g. graphstore, JSON)
+// allocate every copy separately.
+//
+// Thread Safety: Not safe for concurrent use; owned by a building Graph.
+type stringPool map[string]string
+
+// intern returns the pooled string equal to s.
+func (p stringPool) intern(s string) string {
+	if s == "" {
+		return ""
+	}
+	if pooled, ok := p[s]; ok {
+		return pooled
+	}
+	p[s] = s
+	return s
+}
+
+// internInto replaces *s with the pooled string if it is a different
+// copy. Strings that are already shared are not written, so symbols that
+// other goroutines may be reading are left untouched.
+func (p stringPool) internInto(s *string) {
+	pooled := p.intern(*s)
+	if unsafe.StringData(pooled) != unsafe.StringData(*s) {
+		*s = pooled
+	}
+}
+
+// internSymbol replaces the repeated string fields of sym with pooled
+// equal strings. Values are unchanged, so this is not a visible mutation.
+func (g *Graph) internSymbol(sym *ast.Symbol) {
+	if !g.options.Compact {
+		return
+	}
+	if g.strings == nil {
+		g.strings = make(stringPool)
+	}
+	p := g.strings
+	p.internInto(&sym.FilePath)
+	p.internInto(&sym.Package)
+	p.internInto(&sym.Language)
+	p.internInto(&sym.Receiver)
+	for i := range sym.Calls {
+		p.internInto(&sym.Calls[i].Location.FilePath)
+	}
+}
+
+// internPath returns the pooled copy of a file path.
+func (g *Graph) internPath(path string) string {
+	if !g.options.Compact {
+		return path
+	}
+	if g.strings == nil {
+		g.strings = make(stringPool)
+	}
+	return g.strings.intern(path)
+}
+
+// edgeSlabSize is the number of edges allocated per block.
+const edgeSlabSize = 1024
+
+// newEdge returns a pointer to a copy of e. With compaction enabled,
+// edges are carved from blocks of edgeSlabSize instead of being allocated
+// one by one, which saves the per-object allocator overhead.
+func (g *Graph) newEdge(e Edge) *Edge {
+	if !g.options.Compact {
+		return &e
+	}
+	if len(g.edgeSlab) == cap(g.edgeSlab) {
+		g.edgeSlab = make([]Edge, 0, edgeSlabSize)
+	}
+	g.edgeSlab = append(g.edgeSlab, e)
+	return &g.edgeSlab[len(g.edgeSlab)-1]
+}
+
+// compact reduces the memory of a graph that is about to be frozen.
+//
+// Description:
+//
+//	Slices grown by append during the build keep up to half their
+//	capacity unused, and every node owns two small allocations for its
+//	edge lists. compact copies each node's Outgoing and Incoming edges
+//	into windows of two shared arrays of exactly E entries (capacity
+//	equal to length, so an append on a clone reallocates rather than
+//	overwriting a neighbor), trims the secondary indexes to their length
+//	and drops the string pool. The public API is unchanged.
+//
+//	O(V + E). Disable with WithCompaction(false).
+func (g *Graph) compact() {
+	g.strings = nil
+
+	outgoing := make([]*Edge, 0, len(g.edges))
+	incoming := make([]*Edge, 0, len(g.edges))
+	for _, node := range g.nodes {
+		start := len(outgoing)
+		outgoing = append(outgoing, node.Outgoing...)
+		node.Outgoing = outgoing[start:len(outgoing):len(outgoing)]
+
+		start = len(incoming)
+		incoming = append(incoming, node.Incoming...)
+		node.Incoming = incoming[start:len(incoming):len(incoming)]
+	}
+
+	g.edges = exact(g.edges)
+	for t := range g.edgesByType {
+		g.edgesByType[t] = exact(g.edgesByType[t])
+	}
+	for path, edges := range g.edgesByFile {
+		g.edgesByFile[path] = exact(edges)
+	}
+	for name, nodes := range g.nodesByName {
+		g.nodesByName[name] = exact(nodes)
+	}
+	for kind, nodes := range g.nodesByKind {
+		g.nodesByKind[kind] = exact(nodes)
+	}
+}
+
+// exact returns s with capacity equal to its length, copying only if
+// there is spare capacity.
+func exact[T any](s []T) []T {
+	if cap(s) == len(s) {
+		return s
+	}
+	out := make([]T, len(s))
+	copy(out, s)
+	return out
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildDecodedGraph builds a graph whose strings are all separate copies,
+// like a graph decoded from storage: nodes symbols spread over nodes/100
+// files, each calling edgesPerNode others.
+func buildDecodedGraph(tb testing.TB, nodes, edgesPerNode int, opts ...GraphOption) *Graph {
+	tb.Helper()
+	g := NewGraph("/project", opts...)
+	id := func(i int) string {
+		return fmt.Sprintf("pkg%d/file%d.go:%d:Func%d", i/1000, i/100, i%100, i)
+	}
+	for i := 0; i < nodes; i++ {
+		file := fmt.Sprintf("pkg%d/file%d.go", i/1000, i/100)
+		if _, err := g.AddNode(&ast.Symbol{
+			ID:        id(i),
+			Name:      fmt.Sprintf("Func%d", i),
+			Kind:      ast.SymbolKindFunction,
+			FilePath:  file,
+			StartLine: i%100 + 1,
+			Package:   fmt.Sprintf("pkg%d", i/1000),
+			Language:  strings.Clone("go"),
+			Exported:  true,
+		}); err != nil {
+			tb.Fatalf("AddNode: %v", err)
+		}
+	}
+	for i := 0; i < nodes; i++ {
+		for k := 1; k <= edgesPerNode; k++ {
+			j := (i*7 + k*13) % nodes
+			loc := ast.Location{FilePath: fmt.Sprintf("pkg%d/file%d.go", i/1000, i/100), StartLine: i%100 + 1}
+			if err := g.AddEdge(id(i), id(j), EdgeTypeCalls, loc); err != nil {
+				tb.Fatalf("AddEdge: %v", err)
+			}
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraph_Compaction(t *testing.T) {
+	compact := buildDecodedGraph(t, 500, 3)
+	plain := buildDecodedGraph(t, 500, 3, WithCompaction(false))
+
+	if compact.NodeCount() != plain.NodeCount() || compact.EdgeCount() != plain.EdgeCount() {
+		t.Fatalf("compact graph has %d nodes, %d edges; plain has %d, %d",
+			compact.NodeCount(), compact.EdgeCount(), plain.NodeCount(), plain.EdgeCount())
+	}
+	for id, node := range plain.Nodes() {
+		c, ok := compact.GetNode(id)
+		if !ok {
+			t.Fatalf("node %s missing from compact graph", id)
+		}
+		if len(c.Outgoing) != len(node.Outgoing) || len(c.Incoming) != len(node.Incoming) {
+			t.Fatalf("node %s: %d/%d edges, want %d/%d", id, len(c.Outgoing), len(c.Incoming), len(node.Outgoing), len(node.Incoming))
+		}
+		if cap(c.Outgoing) != len(c.Outgoing) || cap(c.Incoming) != len(c.Incoming) {
+			t.Errorf("node %s: edge lists not exactly sized", id)
+		}
+		for i, e := range c.Outgoing {
+			if e.ToID != node.Outgoing[i].ToID || e.FromID != id {
+				t.Fatalf("node %s: outgoing[%d] = %s -> %s", id, i, e.FromID, e.ToID)
+			}
+		}
+	}
+	if got, want := len(compact.GetEdgesByFile("pkg0/file0.go")), len(plain.GetEdgesByFile("pkg0/file0.go")); got != want {
+		t.Errorf("GetEdgesByFile = %d edges, want %d", got, want)
+	}
+
+	// Equal strings share one allocation; edges share their nodes' IDs.
+	a, _ := compact.GetNode("pkg0/file0.go:0:Func0")
+	b, _ := compact.GetNode("pkg0/file0.go:1:Func1")
+	if unsafe.StringData(a.Symbol.FilePath) != unsafe.StringData(b.Symbol.FilePath) {
+		t.Error("file paths not interned")
+	}
+	if unsafe.StringData(a.Outgoing[0].FromID) != unsafe.StringData(a.ID) {
+		t.Error("edge FromID does not share the node ID")
+	}
+
+	// Appending to a clone's edge list must not overwrite a neighbor's window.
+	clone := compact.Clone()
+	before := fmt.Sprint(len(a.Outgoing), a.Outgoing[0].ToID)
+	if err := clone.AddEdge(a.ID, b.ID, EdgeTypeReferences, ast.Location{}); err != nil {
+		t.Fatalf("AddEdge on clone: %v", err)
+	}
+	if after := fmt.Sprint(len(a.Outgoing), a.Outgoing[0].ToID); after != before {
+		t.Errorf("original edges changed by clone: %s, want %s", after, before)
+	}
+}
+
+// BenchmarkGraphMemory reports the heap retained by a frozen 100k-node
+// graph with and without compaction.
+func BenchmarkGraphMemory(b *testing.B) {
+	const nodes, edgesPerNode = 100_000, 4
+	for _, bc := range []struct {
+		name    string
+		compact bool
+	}{{"compact", true}, {"plain", false}} {
+		b.Run(bc.name, func(b *testing.B) {
+			var retained uint64
+			for i := 0; i < b.N; i++ {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				g := buildDecodedGraph(b, nodes, edgesPerNode, WithCompaction(bc.compact))
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				retained += after.HeapAlloc - before.HeapAlloc
+				runtime.KeepAlive(g)
+			}
+			b.ReportMetric(float64(retained)/float64(b.N)/nodes, "heap-B/node")
+		})
+	}
+}
@@ -118,10 +118,20 @@ type articulationFrame struct {
 //   - Node IDs are valid and non-empty strings
 //   - Self-loops are skipped (do not affect connectivity)
 //
+// Caching: Results are cached per graph generation (see AnalyticsCache).
+// The returned articulation result is shared with other callers; do not modify it.
+//
 // Thread Safety: Safe for concurrent use (read-only on graph).
 //
 // Complexity: O(V + E) time, O(V) space.
 func (a *GraphAnalytics) ArticulationPoints(ctx context.Context) (*ArticulationResult, error) {
+	return cachedAnalytics(ctx, a, analyticsArticulationPoints, "", func() (*ArticulationResult, error) {
+		return a.computeArticulationPoints(ctx)
+	})
+}
+
+// computeArticulationPoints computes the articulation result without caching.
+func (a *GraphAnalytics) computeArticulationPoints(ctx context.Context) (*ArticulationResult, error) {
 	// Initialize result with empty slices (never return nil slices)
 	result := &ArticulationResult{
 		Points:  make([]string, 0),
@@ -148,10 +148,20 @@ func (e *DominatorError) Error() string {
 //   - Entry node exists in the graph
 //   - Directed edges represent call relationships
 //
+// Caching: Results are cached per graph generation (see AnalyticsCache).
+// The returned dominator tree is shared with other callers; do not modify it.
+//
 // Thread Safety: Safe for concurrent use (read-only on graph).
 //
 // Complexity: O(E) typical, O(V²) worst case.
 func (a *GraphAnalytics) Dominators(ctx context.Context, entry string) (*DominatorTree, error) {
+	return cachedAnalytics(ctx, a, analyticsDominators, entry, func() (*DominatorTree, error) {
+		return a.computeDominators(ctx, entry)
+	})
+}
+
+// computeDominators computes the dominator tree without caching.
+func (a *GraphAnalytics) computeDominators(ctx context.Context, entry string) (*DominatorTree, error) {
 	// Initialize result with empty maps
 	result := &DominatorTree{
 		Entry:          entry,
@@ -1207,7 +1217,7 @@ func (a *GraphAnalytics) DominatorsWithCRS(ctx context.Context, entry string) (*
 //	recomputation when multiple tools request the dominator tree for the same
 //	entry point. The cache is automatically invalidated when:
 //	- A different entry point is requested
-//	- The underlying graph changes (detected via BuiltAtMilli)
+//	- The underlying graph changes (detected via Generation)
 //
 // Inputs:
 //
@@ -1233,7 +1243,7 @@ func (a *GraphAnalytics) DominatorsWithCache(ctx context.Context, entry string)
 	// Get current graph version for cache validation
 	graphVersion := int64(0)
 	if a.graph != nil && a.graph.Graph != nil {
-		graphVersion = a.graph.BuiltAtMilli
+		graphVersion = a.graph.Generation
 	}
 
 	// Check cache with read lock
@@ -1355,10 +1365,20 @@ var postDominatorTracer = otel.Tracer("graph.post_dominators")
 //   - Exit node exists in the graph (if specified)
 //   - Directed edges represent call relationships
 //
+// Caching: Results are cached per graph generation (see AnalyticsCache).
+// The returned post-dominator tree is shared with other callers; do not modify it.
+//
 // Thread Safety: Safe for concurrent use (read-only on graph).
 //
 // Complexity: O(E) typical, O(V²) worst case.
 func (a *GraphAnalytics) PostDominators(ctx context.Context, exit string) (*DominatorTree, error) {
+	return cachedAnalytics(ctx, a, analyticsPostDominators, exit, func() (*DominatorTree, error) {
+		return a.computePostDominators(ctx, exit)
+	})
+}
+
+// computePostDominators computes the post-dominator tree without caching.
+func (a *GraphAnalytics) computePostDominators(ctx context.Context, exit string) (*DominatorTree, error) {
 	// Initialize result with empty maps
 	result := &DominatorTree{
 		ImmediateDom:   make(map[string]string),
@@ -1920,7 +1940,7 @@ func (a *GraphAnalytics) PostDominatorsWithCRS(ctx context.Context, exit string)
 //	avoid recomputation when multiple tools request the post-dominator tree for
 //	the same exit point. The cache is automatically invalidated when:
 //	- A different exit point is requested
-//	- The underlying graph changes (detected via BuiltAtMilli)
+//	- The underlying graph changes (detected via Generation)
 //
 // Inputs:
 //
@@ -1946,7 +1966,7 @@ func (a *GraphAnalytics) PostDominatorsWithCache(ctx context.Context, exit strin
 	// Get current graph version for cache validation
 	graphVersion := int64(0)
 	if a.graph != nil && a.graph.Graph != nil {
-		graphVersion = a.graph.BuiltAtMilli
+		graphVersion = a.graph.Generation
 	}
 
 	// Check cache with read lock
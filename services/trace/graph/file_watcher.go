@@ -12,14 +12,10 @@ package graph
 
 import (
 	"context"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
+	"github.com/AleutianAI/AleutianFOSS/pkg/watcher"
 )
 
 // FileChange represents a file system change event.
@@ -27,6 +23,9 @@ type FileChange struct {
 	// Path is the absolute path to the changed file.
 	Path string
 
+	// OldPath is the previous path of a renamed file, otherwise empty.
+	OldPath string
+
 	// Op is the type of change.
 	Op FileOp
 
@@ -80,23 +79,19 @@ type FileChangeHandler func(changes []FileChange)
 //
 // # Debouncing
 //
-// Changes are collected into a buffer. When the debounce period expires
-// without new changes, all collected changes are sent to the handler.
-// This is implemented using channels for efficient, non-blocking operation.
+// Watching, debouncing, and coalescing are done by pkg/watcher: when the
+// debounce period expires without new changes, the collected changes are
+// merged per file and sent to the handler. A rename within the project
+// arrives as a FileOpRename with OldPath set, one per file for a renamed
+// directory.
 //
 // # Thread Safety
 //
 // Safe for concurrent use. The handler is called from a single goroutine.
 type FileWatcher struct {
-	root          string
-	watcher       *fsnotify.Watcher
-	handler       FileChangeHandler
-	debounce      time.Duration
-	ignorePattern []string
-
-	// Channels for communication
-	changes  chan FileChange
-	done     chan struct{}
+	watcher *watcher.Watcher
+	handler FileChangeHandler
+
 	stopOnce sync.Once
 
 	mu       sync.RWMutex
@@ -109,8 +104,9 @@ type FileWatcherOptions struct {
 	// Default: 100ms
 	DebounceWindow time.Duration
 
-	// IgnorePatterns are glob patterns for files/directories to ignore.
-	// Default: [".git", "node_modules", ".idea", "*.swp", "*.tmp"]
+	// IgnorePatterns are names or glob patterns for files/directories to
+	// ignore, matched against every path component.
+	// Default: [".git", "node_modules", ".idea", "*.swp", "*.tmp", "__pycache__"]
 	IgnorePatterns []string
 
 	// BufferSize is the size of the change buffer channel.
@@ -138,7 +134,7 @@ func DefaultFileWatcherOptions() FileWatcherOptions {
 // # Outputs
 //
 //   - *FileWatcher: Ready-to-use watcher (call Start to begin watching).
-//   - error: Non-nil if the watcher could not be created.
+//   - error: Non-nil if the directory tree could not be watched.
 //
 // # Example
 //
@@ -159,19 +155,23 @@ func NewFileWatcher(root string, handler FileChangeHandler, opts *FileWatcherOpt
 		opts = &defaults
 	}
 
-	watcher, err := fsnotify.NewWatcher()
+	ignore := opts.IgnorePatterns
+	if ignore == nil {
+		ignore = []string{}
+	}
+	w, err := watcher.New(watcher.Config{
+		Root:       root,
+		Debounce:   opts.DebounceWindow,
+		Ignore:     ignore,
+		BufferSize: opts.BufferSize,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &FileWatcher{
-		root:          root,
-		watcher:       watcher,
-		handler:       handler,
-		debounce:      opts.DebounceWindow,
-		ignorePattern: opts.IgnorePatterns,
-		changes:       make(chan FileChange, opts.BufferSize),
-		done:          make(chan struct{}),
+		watcher: w,
+		handler: handler,
 	}, nil
 }
 
@@ -179,8 +179,9 @@ func NewFileWatcher(root string, handler FileChangeHandler, opts *FileWatcherOpt
 //
 // # Description
 //
-// Recursively watches the root directory and all subdirectories.
-// Changes are debounced and sent to the handler in batches.
+// Changes under the root directory and all subdirectories are debounced
+// and sent to the handler in batches from a background goroutine, which
+// exits when Stop() is called or ctx is canceled.
 //
 // # Inputs
 //
@@ -188,15 +189,7 @@ func NewFileWatcher(root string, handler FileChangeHandler, opts *FileWatcherOpt
 //
 // # Outputs
 //
-//   - error: Non-nil if watching could not be started.
-//
-// # Behavior
-//
-// Spawns two goroutines:
-//   - Event processor: Converts fsnotify events to FileChange
-//   - Debouncer: Batches changes and calls handler
-//
-// Both goroutines exit when Stop() is called or context is canceled.
+//   - error: Always nil; the tree is already watched by NewFileWatcher.
 func (w *FileWatcher) Start(ctx context.Context) error {
 	w.mu.Lock()
 	if w.watching {
@@ -206,24 +199,18 @@ func (w *FileWatcher) Start(ctx context.Context) error {
 	w.watching = true
 	w.mu.Unlock()
 
-	// Add root directory recursively
-	if err := w.addRecursive(w.root); err != nil {
-		return err
-	}
-
-	// Start event processor
-	go w.processEvents(ctx)
-
-	// Start debouncer
-	go w.debounceLoop(ctx)
-
+	go func() {
+		_ = w.watcher.Run(ctx, w.dispatch)
+		w.mu.Lock()
+		w.watching = false
+		w.mu.Unlock()
+	}()
 	return nil
 }
 
 // Stop stops the file watcher.
 func (w *FileWatcher) Stop() {
 	w.stopOnce.Do(func() {
-		close(w.done)
 		w.watcher.Close()
 
 		w.mu.Lock()
@@ -239,200 +226,48 @@ func (w *FileWatcher) IsWatching() bool {
 	return w.watching
 }
 
-// addRecursive adds a directory and all subdirectories to the watch list.
-func (w *FileWatcher) addRecursive(root string) error {
-	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // Ignore errors, continue walking
-		}
-
-		// Only watch directories
-		if !d.IsDir() {
-			return nil
-		}
-
-		// Check ignore patterns
-		if w.shouldIgnore(path) {
-			return filepath.SkipDir
-		}
-
-		// Add to watcher
-		return w.watcher.Add(path)
-	})
-}
-
-// shouldIgnore checks if a path matches any ignore pattern.
-func (w *FileWatcher) shouldIgnore(path string) bool {
-	base := filepath.Base(path)
-
-	for _, pattern := range w.ignorePattern {
-		// Check direct name match
-		if base == pattern {
-			return true
-		}
-
-		// Check glob pattern
-		matched, _ := filepath.Match(pattern, base)
-		if matched {
-			return true
-		}
-
-		// Check if path contains the pattern (for directories)
-		if strings.Contains(path, pattern) {
-			return true
-		}
+// dispatch converts a watcher batch to FileChanges and calls the handler.
+func (w *FileWatcher) dispatch(events []watcher.Event) {
+	w.mu.RLock()
+	handler := w.handler
+	w.mu.RUnlock()
+	if handler == nil {
+		return
 	}
 
-	return false
-}
-
-// processEvents converts fsnotify events to FileChange and sends to channel.
-func (w *FileWatcher) processEvents(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-w.done:
-			return
-		case event, ok := <-w.watcher.Events:
-			if !ok {
-				return
-			}
-
-			// Skip ignored paths
-			if w.shouldIgnore(event.Name) {
-				continue
-			}
-
-			// Convert to FileChange
-			change := FileChange{
-				Path: event.Name,
-				Time: time.Now(),
-				Op:   w.convertOp(event.Op),
-			}
-
-			// Send to debounce channel (non-blocking)
-			select {
-			case w.changes <- change:
-			default:
-				// Buffer full, drop oldest or skip
-				// In practice, the debouncer should keep up
-			}
-
-			// If new directory created, add it to watcher
-			if event.Has(fsnotify.Create) {
-				if info, err := getFileInfo(event.Name); err == nil && info {
-					w.watcher.Add(event.Name)
-				}
-			}
-
-		case err, ok := <-w.watcher.Errors:
-			if !ok {
-				return
-			}
-			// Log error but continue watching
-			_ = err // In production, log this
+	changes := make([]FileChange, 0, len(events))
+	for _, batched := range events {
+		for _, ev := range w.watcher.Expand(batched) {
+			changes = append(changes, FileChange{
+				Path:    ev.Path,
+				OldPath: ev.OldPath,
+				Op:      convertOp(ev.Op),
+				Time:    ev.Time,
+			})
 		}
 	}
-}
-
-// getFileInfo returns true if path is a directory.
-func getFileInfo(path string) (bool, error) {
-	info, err := os.Stat(path)
-	if err != nil {
-		return false, err
+	if len(changes) > 0 {
+		handler(changes)
 	}
-	return info.IsDir(), nil
 }
 
-// convertOp converts fsnotify.Op to FileOp.
-func (w *FileWatcher) convertOp(op fsnotify.Op) FileOp {
-	switch {
-	case op.Has(fsnotify.Create):
+// convertOp converts a watcher.Op to FileOp.
+func convertOp(op watcher.Op) FileOp {
+	switch op {
+	case watcher.Create:
 		return FileOpCreate
-	case op.Has(fsnotify.Write):
-		return FileOpWrite
-	case op.Has(fsnotify.Remove):
+	case watcher.Remove:
 		return FileOpRemove
-	case op.Has(fsnotify.Rename):
+	case watcher.Rename:
 		return FileOpRename
 	default:
-		return FileOpWrite // Default to write
-	}
-}
-
-// debounceLoop batches changes and calls handler after debounce window.
-func (w *FileWatcher) debounceLoop(ctx context.Context) {
-	var batch []FileChange
-	var timer *time.Timer
-	var timerC <-chan time.Time
-
-	flush := func() {
-		if len(batch) > 0 {
-			// Deduplicate changes
-			deduped := w.deduplicateChanges(batch)
-			if len(deduped) > 0 && w.handler != nil {
-				w.handler(deduped)
-			}
-			batch = batch[:0]
-		}
-		if timer != nil {
-			timer.Stop()
-			timer = nil
-			timerC = nil
-		}
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			flush()
-			return
-		case <-w.done:
-			flush()
-			return
-		case change := <-w.changes:
-			batch = append(batch, change)
-
-			// Reset or start debounce timer
-			if timer == nil {
-				timer = time.NewTimer(w.debounce)
-				timerC = timer.C
-			} else {
-				timer.Reset(w.debounce)
-			}
-
-		case <-timerC:
-			// Debounce window expired, flush batch
-			flush()
-		}
-	}
-}
-
-// deduplicateChanges removes duplicate changes for the same file.
-// Keeps the most recent change per path.
-func (w *FileWatcher) deduplicateChanges(changes []FileChange) []FileChange {
-	seen := make(map[string]int) // path -> index in result
-	result := make([]FileChange, 0, len(changes))
-
-	for _, change := range changes {
-		if idx, exists := seen[change.Path]; exists {
-			// Replace with newer change
-			result[idx] = change
-		} else {
-			seen[change.Path] = len(result)
-			result = append(result, change)
-		}
+		return FileOpWrite
 	}
-
-	return result
 }
 
 // AddPattern adds an ignore pattern.
 func (w *FileWatcher) AddPattern(pattern string) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-	w.ignorePattern = append(w.ignorePattern, pattern)
+	w.watcher.Ignore().Add(pattern)
 }
 
 // SetHandler changes the change handler.
@@ -0,0 +1,62 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher_DirectoryRename(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg", "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pkg", "a", "a.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	batches := make(chan []FileChange, 4)
+	opts := DefaultFileWatcherOptions()
+	opts.DebounceWindow = 20 * time.Millisecond
+	w, err := NewFileWatcher(root, func(changes []FileChange) { batches <- changes }, &opts)
+	if err != nil {
+		t.Fatalf("NewFileWatcher: %v", err)
+	}
+	defer w.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := os.Rename(filepath.Join(root, "pkg"), filepath.Join(root, "lib")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changes := <-batches:
+		want := FileChange{
+			Path:    filepath.Join(root, "lib", "a", "a.go"),
+			OldPath: filepath.Join(root, "pkg", "a", "a.go"),
+			Op:      FileOpRename,
+		}
+		if len(changes) != 1 || changes[0].Path != want.Path || changes[0].OldPath != want.OldPath || changes[0].Op != want.Op {
+			t.Errorf("changes = %+v, want one %+v", changes, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes delivered")
+	}
+}
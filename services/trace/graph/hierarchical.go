@@ -119,6 +119,35 @@ type CouplingMetrics struct {
 	Abstractness float64
 }
 
+// DocCoverageMetrics contains documentation coverage for a package.
+//
+// Only documentable symbols (functions, methods, and type declarations; see
+// ast.SymbolKind.IsDocumentable) are counted.
+type DocCoverageMetrics struct {
+	// Package is the package being measured.
+	Package string
+
+	// Documentable is the number of symbols expected to carry documentation.
+	Documentable int
+
+	// Documented is the number of documentable symbols with a doc comment.
+	Documented int
+
+	// Exported is the number of exported documentable symbols.
+	Exported int
+
+	// ExportedDocumented is the number of exported symbols with a doc comment.
+	ExportedDocumented int
+
+	// Coverage is Documented / Documentable, range [0, 1].
+	// 1 when the package has no documentable symbols.
+	Coverage float64
+
+	// ExportedCoverage is ExportedDocumented / Exported, range [0, 1].
+	// 1 when the package has no exported symbols.
+	ExportedCoverage float64
+}
+
 // =============================================================================
 // HierarchicalGraph
 // =============================================================================
@@ -138,7 +167,8 @@ type CouplingMetrics struct {
 // Thread Safety:
 //
 //	After construction, HierarchicalGraph is safe for concurrent reads.
-//	Indexes are built during construction and never mutated.
+//	Indexes are built during construction and only replaced as a whole by
+//	ApplyFileChange, which must not run concurrently with readers.
 //
 // Performance:
 //
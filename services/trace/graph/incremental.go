@@ -0,0 +1,447 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// =============================================================================
+// Incremental File Updates
+// =============================================================================
+
+var incrementalTracer = otel.Tracer("graph.incremental")
+
+// FileChangeResult describes an update applied by ApplyFileChange.
+type FileChangeResult struct {
+	// FilePath is the changed file, relative to the project root.
+	FilePath string
+
+	// NodesRemoved is the number of nodes of the old file version.
+	NodesRemoved int
+
+	// NodesAdded is the number of nodes of the new file version.
+	NodesAdded int
+
+	// EdgesAdded is the number of edges extracted from the new file version.
+	EdgesAdded int
+
+	// EdgesRelinked is the number of edges from other files that were
+	// resolved again against the new file version.
+	EdgesRelinked int
+
+	// PlaceholdersRemoved is the number of external placeholder nodes
+	// dropped because nothing references them anymore.
+	PlaceholdersRemoved int
+
+	// Generation is the generation of the patched graph.
+	Generation int64
+
+	// Duration is how long the update took.
+	Duration time.Duration
+}
+
+// staleEdge is an edge from another file that pointed at a node replaced by
+// a file change and must be resolved again.
+type staleEdge struct {
+	from       *ast.Symbol
+	targetName string
+	edgeType   EdgeType
+	location   ast.Location
+}
+
+// ApplyFileChange updates the graph for a single changed file.
+//
+// Description:
+//
+//	Reparses only the changed file and patches the graph instead of
+//	rebuilding it from every source file:
+//
+//	  1. The nodes of the old file version and their edges are removed.
+//	  2. The symbols of the new version are added and their edges are
+//	     extracted as Build would.
+//	  3. Edges from other files that pointed at removed nodes, or at
+//	     external placeholders named like a new symbol, are resolved
+//	     again, so callers in other files follow renames and moves.
+//	  4. Placeholders nothing references anymore are dropped.
+//
+//	The patched graph is frozen with a new Generation and the hierarchical
+//	indexes are rebuilt, so analytics cached for the old graph (dominator
+//	trees, SCCs, articulation points, betweenness) are recomputed on their
+//	next use by every GraphAnalytics of hg.
+//
+//	A nil newContent removes the file, e.g. when it was deleted.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//	parsers - Parser registry used to reparse the file. May be nil if
+//	          newContent is nil.
+//	path - The changed file, absolute or relative to the project root.
+//	newContent - The new file content, or nil if the file was removed.
+//
+// Outputs:
+//
+//	*FileChangeResult - Statistics about the update.
+//	error - Non-nil if the file could not be parsed or the update was
+//	        cancelled. hg is unchanged in that case.
+//
+// Example:
+//
+//	result, err := hg.ApplyFileChange(ctx, registry, "pkg/server.go", content)
+//	if err != nil {
+//	    return fmt.Errorf("update graph: %w", err)
+//	}
+//	log.Printf("replaced %d nodes with %d", result.NodesRemoved, result.NodesAdded)
+//
+// Limitations:
+//
+//	Implicit interface implementations and cross-file method sets are
+//	computed over the whole project by Build and are not recomputed:
+//	implements edges from the changed file's types to interfaces
+//	declared elsewhere only come from explicit Implements metadata until
+//	the next full build. An HLD built for the old graph is not rebuilt.
+//
+// Thread Safety:
+//
+//	NOT safe for concurrent use with any other method of hg. The update
+//	is prepared on a clone, so readers that need to keep running during
+//	an update should apply it to a separate HierarchicalGraph and swap.
+//
+// Complexity:
+//
+//	O(V + E) to clone the graph and rebuild the indexes, plus parsing and
+//	edge extraction for the changed file only.
+func (hg *HierarchicalGraph) ApplyFileChange(
+	ctx context.Context,
+	parsers *ast.ParserRegistry,
+	path string,
+	newContent []byte,
+) (*FileChangeResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+	if hg == nil || hg.Graph == nil {
+		return nil, ErrNilGraph
+	}
+
+	relPath, err := hg.relativeFilePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := incrementalTracer.Start(ctx, "HierarchicalGraph.ApplyFileChange",
+		trace.WithAttributes(
+			attribute.String("file", relPath),
+			attribute.Bool("removed", newContent == nil),
+		),
+	)
+	defer span.End()
+	start := time.Now()
+
+	pr, err := parseChangedFile(ctx, parsers, relPath, newContent)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	b := NewBuilder(WithProjectRoot(hg.ProjectRoot))
+	if err := b.validateParseResult(pr); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("invalid parse result for %s: %w", relPath, err)
+	}
+
+	g := hg.Graph.Clone()
+	result, err := b.patchFile(ctx, g, hg.fileIndex[relPath], pr)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	g.Freeze()
+	patched, err := WrapGraph(g)
+	if err != nil {
+		return nil, err
+	}
+	*hg = *patched
+
+	result.Generation = g.Generation
+	result.Duration = time.Since(start)
+
+	span.SetAttributes(
+		attribute.Int("nodes_removed", result.NodesRemoved),
+		attribute.Int("nodes_added", result.NodesAdded),
+		attribute.Int("edges_added", result.EdgesAdded),
+		attribute.Int("edges_relinked", result.EdgesRelinked),
+		attribute.Int64("generation", result.Generation),
+	)
+	return result, nil
+}
+
+// relativeFilePath converts path to the project-relative, slash-separated
+// form used by Symbol.FilePath.
+func (hg *HierarchicalGraph) relativeFilePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path must not be empty")
+	}
+	if filepath.IsAbs(path) {
+		if hg.ProjectRoot == "" {
+			return "", fmt.Errorf("absolute path %s requires a project root", path)
+		}
+		rel, err := filepath.Rel(hg.ProjectRoot, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("file %s is outside project root %s", path, hg.ProjectRoot)
+		}
+		path = rel
+	}
+	return filepath.ToSlash(filepath.Clean(path)), nil
+}
+
+// parseChangedFile parses the new content of a file. A nil content yields
+// an empty result, which removes the file from the graph.
+func parseChangedFile(ctx context.Context, parsers *ast.ParserRegistry, relPath string, content []byte) (*ast.ParseResult, error) {
+	if content == nil {
+		return &ast.ParseResult{FilePath: relPath}, nil
+	}
+	if parsers == nil {
+		return nil, fmt.Errorf("parser registry must not be nil")
+	}
+
+	ext := getFileExtension(relPath)
+	parser, ok := parsers.GetByExtension(ext)
+	if !ok {
+		return nil, fmt.Errorf("no parser for extension %q (file: %s)", ext, relPath)
+	}
+
+	pr, err := parser.Parse(ctx, content, relPath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file: %w", err)
+	}
+	pr.AnnotateOrigin(content)
+	return pr, nil
+}
+
+// patchFile replaces the nodes of one file in a building graph.
+//
+// Description:
+//
+//	Implements steps 1-4 of ApplyFileChange on g, which must be a clone
+//	in the building state. oldNodes are the nodes of the old file version
+//	in the graph g was cloned from; they are looked up in g by ID.
+//
+// Outputs:
+//
+//	*FileChangeResult - Node and edge counts. Generation and Duration
+//	                    are left to the caller.
+//	error - Non-nil if ctx was cancelled.
+func (b *Builder) patchFile(ctx context.Context, g *Graph, oldNodes []*Node, pr *ast.ParseResult) (*FileChangeResult, error) {
+	relPath := pr.FilePath
+	result := &FileChangeResult{FilePath: relPath}
+
+	// Step 1: remember edges into the old version from other files, and
+	// the placeholders the old version referenced, then remove it.
+	var stale []staleEdge
+	touched := make(map[string]bool)
+	for _, old := range oldNodes {
+		node, ok := g.GetNode(old.ID)
+		if !ok {
+			continue
+		}
+		for _, e := range node.Incoming {
+			if from, ok := g.GetNode(e.FromID); ok && from.Symbol != nil && from.Symbol.FilePath != relPath {
+				stale = append(stale, staleEdge{from.Symbol, node.Symbol.Name, e.Type, e.Location})
+			}
+		}
+		for _, e := range node.Outgoing {
+			if to, ok := g.GetNode(e.ToID); ok && isPlaceholder(to) {
+				touched[to.ID] = true
+			}
+		}
+	}
+
+	removed, err := g.RemoveFile(relPath)
+	if err != nil {
+		return nil, err
+	}
+	result.NodesRemoved = removed
+
+	// Step 2: add the new version and extract its edges against the
+	// symbols of the whole graph.
+	state := newPatchState(g)
+	if err := b.collectPhase(ctx, state, []*ast.ParseResult{pr}); err != nil {
+		return nil, err
+	}
+	result.NodesAdded = state.result.Stats.NodesCreated
+
+	// Step 3a: edges from other files to placeholders named like a new
+	// symbol may now resolve to it.
+	newNames := make(map[string]bool)
+	for _, sym := range state.symbolsByID {
+		if sym.FilePath == relPath && sym.Name != "" {
+			newNames[sym.Name] = true
+		}
+	}
+	unresolved := make(map[*Edge]bool)
+	for id, placeholder := range state.placeholders {
+		name := placeholder.Symbol.Name
+		if i := strings.LastIndex(name, "."); i >= 0 && placeholder.Symbol.Package == "" {
+			name = name[i+1:]
+		}
+		if !newNames[name] {
+			continue
+		}
+		for _, e := range placeholder.Incoming {
+			if from, ok := g.GetNode(e.FromID); ok && from.Symbol != nil {
+				stale = append(stale, staleEdge{from.Symbol, name, e.Type, e.Location})
+				unresolved[e] = true
+				touched[id] = true
+			}
+		}
+	}
+	g.removeEdges(unresolved)
+
+	b.extractFileEdges(ctx, state, pr)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.EdgesAdded = state.result.Stats.EdgesCreated
+
+	// Step 3b: resolve the stale edges again.
+	for _, se := range stale {
+		result.EdgesRelinked += b.relinkEdge(state, se)
+	}
+
+	// Step 4: drop placeholders nothing references anymore.
+	orphans := make(map[string]bool)
+	for id := range touched {
+		if node, ok := g.GetNode(id); ok && len(node.Incoming) == 0 && len(node.Outgoing) == 0 {
+			orphans[id] = true
+		}
+	}
+	g.removeNodes(orphans)
+	result.PlaceholdersRemoved = len(orphans)
+
+	return result, nil
+}
+
+// newPatchState creates a build state over an existing graph, indexing its
+// symbols and placeholders the way collectPhase and getOrCreatePlaceholder
+// would have.
+func newPatchState(g *Graph) *buildState {
+	state := &buildState{
+		graph: g,
+		result: &BuildResult{
+			Graph:      g,
+			FileErrors: make([]FileError, 0),
+			EdgeErrors: make([]EdgeError, 0),
+		},
+		symbolsByID:   make(map[string]*ast.Symbol, g.NodeCount()),
+		symbolsByName: make(map[string][]*ast.Symbol),
+		fileImports:   make(map[string][]ast.Import),
+		placeholders:  make(map[string]*Node),
+		startTime:     time.Now(),
+	}
+	for id, node := range g.Nodes() {
+		if node.Symbol == nil {
+			continue
+		}
+		if isPlaceholder(node) {
+			state.placeholders[id] = node
+			continue
+		}
+		state.symbolsByID[id] = node.Symbol
+		state.symbolsByName[node.Symbol.Name] = append(state.symbolsByName[node.Symbol.Name], node.Symbol)
+	}
+	return state
+}
+
+// relinkEdge resolves a stale edge again and adds the edges a full build
+// would create for it. Returns the number of edges added.
+func (b *Builder) relinkEdge(state *buildState, se staleEdge) int {
+	var targets []string
+	if call, ok := findCallSite(se.from, se.location); ok && se.edgeType == EdgeTypeCalls {
+		targetID := b.resolveCallTarget(state, call, se.from)
+		if targetID == "" {
+			targetID = b.getOrCreatePlaceholder(state, "", call.Target)
+		}
+		targets = []string{targetID}
+	} else {
+		targets = b.resolveSymbolByName(state, se.targetName, se.from.FilePath)
+		if len(targets) == 0 {
+			pkg := ""
+			if se.edgeType == EdgeTypeReceives {
+				pkg = se.from.Package
+			}
+			targets = []string{b.getOrCreatePlaceholder(state, pkg, se.targetName)}
+		}
+	}
+
+	added := 0
+	for _, targetID := range targets {
+		if targetID == se.from.ID || !b.validateEdgeType(state, se.from.ID, targetID, se.edgeType) {
+			continue
+		}
+		if hasEdge(state.graph, se.from.ID, targetID, se.edgeType) {
+			continue
+		}
+		if err := state.graph.AddEdge(se.from.ID, targetID, se.edgeType, se.location); err != nil {
+			state.result.EdgeErrors = append(state.result.EdgeErrors, EdgeError{
+				FromID:   se.from.ID,
+				ToID:     targetID,
+				EdgeType: se.edgeType,
+				Err:      err,
+			})
+			continue
+		}
+		added++
+	}
+	return added
+}
+
+// findCallSite returns the call site of sym at loc.
+func findCallSite(sym *ast.Symbol, loc ast.Location) (ast.CallSite, bool) {
+	for _, call := range sym.Calls {
+		if call.Location.StartLine == loc.StartLine && call.Location.StartCol == loc.StartCol &&
+			call.Location.FilePath == loc.FilePath {
+			return call, true
+		}
+	}
+	return ast.CallSite{}, false
+}
+
+// hasEdge reports whether g has an edge of edgeType from fromID to toID.
+func hasEdge(g *Graph, fromID, toID string, edgeType EdgeType) bool {
+	from, ok := g.GetNode(fromID)
+	if !ok {
+		return false
+	}
+	for _, e := range from.Outgoing {
+		if e.ToID == toID && e.Type == edgeType {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlaceholder reports whether node is an external placeholder created
+// for an unresolved reference.
+func isPlaceholder(node *Node) bool {
+	return node.Symbol != nil && node.Symbol.Kind == ast.SymbolKindExternal && node.Symbol.FilePath == ""
+}
@@ -0,0 +1,257 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// =============================================================================
+// Incremental File Update Tests
+// =============================================================================
+
+const incrementalUtil = `package app
+
+func Helper() {}
+
+func Run() {
+	Helper()
+}
+`
+
+const incrementalMain = `package app
+
+func Main() {
+	Run()
+	Helper()
+	Setup()
+}
+`
+
+func goParsers() *ast.ParserRegistry {
+	registry := ast.NewParserRegistry()
+	registry.Register(ast.NewGoParser())
+	return registry
+}
+
+// buildFromSources parses and builds a graph from path -> content.
+func buildFromSources(t *testing.T, sources map[string]string) *HierarchicalGraph {
+	t.Helper()
+	parser := ast.NewGoParser()
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var results []*ast.ParseResult
+	for _, path := range paths {
+		pr, err := parser.Parse(context.Background(), []byte(sources[path]), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, pr)
+	}
+	built, err := NewBuilder(WithProjectRoot("/project")).Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	hg, err := WrapGraph(built.Graph)
+	if err != nil {
+		t.Fatalf("WrapGraph: %v", err)
+	}
+	return hg
+}
+
+// edgeSet describes the edges of a graph independently of symbol IDs,
+// which contain line numbers.
+func edgeSet(hg *HierarchicalGraph) string {
+	describe := func(id string) string {
+		node, ok := hg.GetNode(id)
+		if !ok || node.Symbol == nil || node.Symbol.FilePath == "" {
+			return id
+		}
+		return node.Symbol.FilePath + ":" + node.Symbol.Name
+	}
+	var edges []string
+	for _, e := range hg.Edges() {
+		edges = append(edges, describe(e.FromID)+" -"+e.Type.String()+"-> "+describe(e.ToID))
+	}
+	sort.Strings(edges)
+	return strings.Join(edges, "\n")
+}
+
+func nodeSet(hg *HierarchicalGraph) string {
+	var nodes []string
+	for _, node := range hg.Nodes() {
+		nodes = append(nodes, node.Symbol.FilePath+":"+node.Symbol.Name)
+	}
+	sort.Strings(nodes)
+	return strings.Join(nodes, "\n")
+}
+
+func TestApplyFileChange_MatchesFullBuild(t *testing.T) {
+	// Setup moves into util.go, Run is renamed to Start and every symbol
+	// shifts down, so all old IDs of util.go change.
+	changed := `package app
+
+// Setup prepares the app.
+func Setup() {}
+
+func Helper() {}
+
+func Start() {
+	Helper()
+	Setup()
+}
+`
+	hg := buildFromSources(t, map[string]string{"util.go": incrementalUtil, "main.go": incrementalMain})
+	oldGeneration := hg.Generation
+
+	result, err := hg.ApplyFileChange(context.Background(), goParsers(), "util.go", []byte(changed))
+	if err != nil {
+		t.Fatalf("ApplyFileChange: %v", err)
+	}
+
+	want := buildFromSources(t, map[string]string{"util.go": changed, "main.go": incrementalMain})
+	if got, want := edgeSet(hg), edgeSet(want); got != want {
+		t.Errorf("edges after change:\n%s\nwant (full build):\n%s", got, want)
+	}
+	if got, want := nodeSet(hg), nodeSet(want); got != want {
+		t.Errorf("nodes after change:\n%s\nwant (full build):\n%s", got, want)
+	}
+
+	if result.FilePath != "util.go" || result.NodesRemoved == 0 || result.NodesAdded == 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	// Main's calls to Helper, the formerly unresolved Setup and the
+	// now unresolved Run; the Setup placeholder is gone.
+	if result.EdgesRelinked != 3 || result.PlaceholdersRemoved != 1 {
+		t.Errorf("relinked %d edges and removed %d placeholders, want 3 and 1",
+			result.EdgesRelinked, result.PlaceholdersRemoved)
+	}
+	if !hg.IsFrozen() || hg.Generation == oldGeneration || result.Generation != hg.Generation {
+		t.Errorf("generation %d (result %d), want a new frozen generation after %d",
+			hg.Generation, result.Generation, oldGeneration)
+	}
+	if got := len(hg.GetNodesInFile("util.go")); got != result.NodesAdded {
+		t.Errorf("file index has %d nodes, want %d", got, result.NodesAdded)
+	}
+}
+
+func TestApplyFileChange_RemovedFile(t *testing.T) {
+	hg := buildFromSources(t, map[string]string{"util.go": incrementalUtil, "main.go": incrementalMain})
+
+	result, err := hg.ApplyFileChange(context.Background(), nil, "/project/util.go", nil)
+	if err != nil {
+		t.Fatalf("ApplyFileChange: %v", err)
+	}
+
+	want := buildFromSources(t, map[string]string{"main.go": incrementalMain})
+	if got, want := edgeSet(hg), edgeSet(want); got != want {
+		t.Errorf("edges after removal:\n%s\nwant (full build):\n%s", got, want)
+	}
+	if result.NodesAdded != 0 || len(hg.GetNodesInFile("util.go")) != 0 {
+		t.Errorf("util.go still has nodes: %+v", result)
+	}
+}
+
+func TestApplyFileChange_InvalidatesAnalytics(t *testing.T) {
+	main := "package app\n\nfunc Main() {\n\tRun()\n}\n"
+	hg := buildFromSources(t, map[string]string{"util.go": incrementalUtil, "main.go": main})
+	analytics := NewGraphAnalytics(hg)
+	ctx := context.Background()
+
+	// Main -> Run -> Helper: Run lies on the only path through the graph.
+	top, err := analytics.BetweennessTop(ctx, 1, nil)
+	if err != nil || len(top) != 1 || top[0].Node.Symbol.Name != "Run" {
+		t.Fatalf("BetweennessTop before change = %+v, %v; want Run", top, err)
+	}
+
+	// Run no longer calls Helper.
+	changed := strings.Replace(incrementalUtil, "\tHelper()\n", "", 1)
+	if _, err := hg.ApplyFileChange(ctx, goParsers(), "util.go", []byte(changed)); err != nil {
+		t.Fatalf("ApplyFileChange: %v", err)
+	}
+
+	top, err = analytics.BetweennessTop(ctx, 1, nil)
+	if err != nil || len(top) != 0 {
+		t.Errorf("BetweennessTop after change = %+v, %v; want no node on a path", top, err)
+	}
+}
+
+func TestApplyFileChange_Errors(t *testing.T) {
+	hg := buildFromSources(t, map[string]string{"util.go": incrementalUtil})
+	generation := hg.Generation
+	ctx := context.Background()
+
+	//nolint:staticcheck // testing nil context handling
+	if _, err := hg.ApplyFileChange(nil, goParsers(), "util.go", nil); err == nil {
+		t.Error("expected error for nil context")
+	}
+	if _, err := hg.ApplyFileChange(ctx, goParsers(), "/elsewhere/util.go", nil); err == nil {
+		t.Error("expected error for file outside the project root")
+	}
+	if _, err := hg.ApplyFileChange(ctx, goParsers(), "notes.txt", []byte("text")); err == nil {
+		t.Error("expected error for file without parser")
+	}
+	if _, err := hg.ApplyFileChange(ctx, nil, "util.go", []byte(incrementalUtil)); err == nil {
+		t.Error("expected error for nil parser registry")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := hg.ApplyFileChange(cancelled, goParsers(), "util.go", nil); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+
+	if hg.Generation != generation || len(hg.GetNodesInFile("util.go")) == 0 {
+		t.Error("failed updates must leave the graph unchanged")
+	}
+
+	var nilGraph *HierarchicalGraph
+	if _, err := nilGraph.ApplyFileChange(ctx, goParsers(), "util.go", nil); err != ErrNilGraph {
+		t.Errorf("err = %v, want ErrNilGraph", err)
+	}
+}
+
+func TestGraph_RemoveEdges(t *testing.T) {
+	g := NewGraph("/project")
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := g.AddNode(&ast.Symbol{ID: id, Name: id, Kind: ast.SymbolKindFunction, FilePath: "x.go",
+			StartLine: 1, EndLine: 1, Language: "go"}); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	loc := ast.Location{FilePath: "x.go", StartLine: 1}
+	_ = g.AddEdge("a", "b", EdgeTypeCalls, loc)
+	_ = g.AddEdge("b", "c", EdgeTypeCalls, loc)
+
+	a, _ := g.GetNode("a")
+	g.removeEdges(map[*Edge]bool{a.Outgoing[0]: true})
+
+	b, _ := g.GetNode("b")
+	if g.EdgeCount() != 1 || len(a.Outgoing) != 0 || len(b.Incoming) != 0 || len(b.Outgoing) != 1 {
+		t.Errorf("edges = %d, a.out = %d, b.in = %d, b.out = %d; want 1, 0, 0, 1",
+			g.EdgeCount(), len(a.Outgoing), len(b.Incoming), len(b.Outgoing))
+	}
+	if got := len(g.GetEdgesByType(EdgeTypeCalls)); got != 1 {
+		t.Errorf("calls index has %d edges, want 1", got)
+	}
+	if got := len(g.edgesByFile["x.go"]); got != 1 {
+		t.Errorf("file index has %d edges, want 1", got)
+	}
+}
@@ -416,6 +416,7 @@ func (r *Refresher) parseFile(ctx context.Context, path string) (*ast.ParseResul
 	if err != nil {
 		return nil, fmt.Errorf("parsing file: %w", err)
 	}
+	result.AnnotateOrigin(content)
 
 	return result, nil
 }
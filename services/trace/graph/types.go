@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
@@ -164,6 +165,12 @@ type GraphOptions struct {
 	// MaxEdges is the maximum number of edges the graph can hold.
 	// Default: 10,000,000
 	MaxEdges int
+
+	// Compact interns repeated strings (file paths, packages, ...) while
+	// building and packs edge lists and indexes into exactly sized arrays
+	// on Freeze, trading O(V + E) freeze time for lower memory.
+	// Default: true
+	Compact bool
 }
 
 // DefaultGraphOptions returns sensible defaults for graph configuration.
@@ -171,6 +178,7 @@ func DefaultGraphOptions() GraphOptions {
 	return GraphOptions{
 		MaxNodes: DefaultMaxNodes,
 		MaxEdges: DefaultMaxEdges,
+		Compact:  true,
 	}
 }
 
@@ -191,6 +199,14 @@ func WithMaxEdges(n int) GraphOption {
 	}
 }
 
+// WithCompaction enables or disables string interning and compaction on
+// Freeze.
+func WithCompaction(enabled bool) GraphOption {
+	return func(o *GraphOptions) {
+		o.Compact = enabled
+	}
+}
+
 // Graph represents the code relationship graph for a project.
 //
 // Thread Safety:
@@ -246,11 +262,27 @@ type Graph struct {
 	// options contains configuration.
 	options GraphOptions
 
+	// strings interns repeated strings during the build; nil once frozen.
+	strings stringPool
+
+	// edgeSlab is the current block edges are allocated from when
+	// compaction is enabled.
+	edgeSlab []Edge
+
 	// BuiltAtMilli is the Unix timestamp in milliseconds when Freeze() was called.
 	// Zero if the graph has not been frozen.
 	BuiltAtMilli int64
+
+	// Generation uniquely identifies this frozen graph within the process.
+	// Assigned by Freeze() from an increasing counter, so unlike BuiltAtMilli
+	// two graphs frozen in the same millisecond never share it. Zero if the
+	// graph has not been frozen.
+	Generation int64
 }
 
+// graphGeneration is the last generation assigned by Freeze().
+var graphGeneration atomic.Int64
+
 // NewGraph creates a new empty graph for the given project root.
 //
 // Description:
@@ -308,7 +340,9 @@ func (g *Graph) IsFrozen() bool {
 //
 //	After calling Freeze(), AddNode and AddEdge will return ErrGraphFrozen.
 //	This operation is irreversible. The BuiltAtMilli timestamp is set to
-//	the current time. Validates secondary index integrity before freezing.
+//	the current time and a new Generation is assigned. Validates secondary
+//	index integrity before freezing, then compacts the graph's memory unless
+//	WithCompaction(false).
 //
 // Thread Safety:
 //
@@ -323,8 +357,14 @@ func (g *Graph) Freeze() {
 	// GR-06/07/08: Validate secondary indexes before freezing
 	g.validateIndexes()
 
+	if g.options.Compact {
+		g.compact()
+	}
+	g.strings = nil
+	g.edgeSlab = nil
 	g.state = GraphStateReadOnly
 	g.BuiltAtMilli = time.Now().UnixMilli()
+	g.Generation = graphGeneration.Add(1)
 }
 
 // validateIndexes verifies secondary index integrity.
@@ -436,7 +476,9 @@ func (g *Graph) EdgeCount() int {
 // Ownership:
 //
 //	The graph stores a pointer to the symbol but does NOT own it.
-//	The symbol MUST NOT be mutated after this call.
+//	The symbol MUST NOT be mutated after this call. Its file path,
+//	package, language and receiver strings are replaced by equal
+//	interned strings.
 func (g *Graph) AddNode(symbol *ast.Symbol) (*Node, error) {
 	if g.state == GraphStateReadOnly {
 		return nil, ErrGraphFrozen
@@ -454,6 +496,7 @@ func (g *Graph) AddNode(symbol *ast.Symbol) (*Node, error) {
 		return nil, fmt.Errorf("%w: %s", ErrDuplicateNode, symbol.ID)
 	}
 
+	g.internSymbol(symbol)
 	node := &Node{
 		ID:       symbol.ID,
 		Symbol:   symbol,
@@ -537,12 +580,15 @@ func (g *Graph) AddEdge(fromID, toID string, edgeType EdgeType, loc ast.Location
 		return fmt.Errorf("%w: target %s", ErrNodeNotFound, toID)
 	}
 
-	edge := &Edge{
-		FromID:   fromID,
-		ToID:     toID,
+	// Share the nodes' ID strings and pooled paths instead of the
+	// caller's copies.
+	loc.FilePath = g.internPath(loc.FilePath)
+	edge := g.newEdge(Edge{
+		FromID:   fromNode.ID,
+		ToID:     toNode.ID,
 		Type:     edgeType,
 		Location: loc,
-	}
+	})
 
 	g.edges = append(g.edges, edge)
 	fromNode.Outgoing = append(fromNode.Outgoing, edge)
@@ -842,27 +888,39 @@ func (g *Graph) RemoveFile(filePath string) (int, error) {
 		return 0, ErrGraphFrozen
 	}
 
-	// Find nodes to remove and track their names/kinds for index cleanup
 	toRemove := make(map[string]bool)
-	removedNames := make(map[string]bool)
-	removedKinds := make(map[ast.SymbolKind]bool)
-
 	for id, node := range g.nodes {
 		if node.Symbol != nil && node.Symbol.FilePath == filePath {
 			toRemove[id] = true
-			if node.Symbol.Name != "" {
-				removedNames[node.Symbol.Name] = true
-			}
-			removedKinds[node.Symbol.Kind] = true
 		}
 	}
 
+	g.removeNodes(toRemove)
+	return len(toRemove), nil
+}
+
+// removeNodes removes the given nodes and every edge that references them,
+// keeping all secondary indexes consistent. The graph must be building.
+func (g *Graph) removeNodes(toRemove map[string]bool) {
 	if len(toRemove) == 0 {
-		return 0, nil
+		return
 	}
 
-	// Remove nodes from primary index
+	// Track names/kinds of removed nodes for index cleanup
+	removedNames := make(map[string]bool)
+	removedKinds := make(map[ast.SymbolKind]bool)
 	for id := range toRemove {
+		node, ok := g.nodes[id]
+		if !ok {
+			continue
+		}
+		if node.Symbol != nil {
+			if node.Symbol.Name != "" {
+				removedNames[node.Symbol.Name] = true
+			}
+			removedKinds[node.Symbol.Kind] = true
+		}
+		// Remove nodes from primary index
 		delete(g.nodes, id)
 	}
 
@@ -898,18 +956,38 @@ func (g *Graph) RemoveFile(filePath string) (int, error) {
 		}
 	}
 
+	removed := make(map[*Edge]bool)
+	for _, edge := range g.edges {
+		if toRemove[edge.FromID] || toRemove[edge.ToID] {
+			removed[edge] = true
+		}
+	}
+	g.removeEdges(removed)
+}
+
+// removeEdges removes the given edges from the edge list, the secondary
+// indexes and the Incoming/Outgoing slices of the remaining nodes. The graph
+// must be building.
+func (g *Graph) removeEdges(removed map[*Edge]bool) {
+	if len(removed) == 0 {
+		return
+	}
+
 	// Filter edges and track which types/files need index update
 	newEdges := make([]*Edge, 0, len(g.edges))
 	removedEdgeTypes := make(map[EdgeType]bool)
 	removedEdgeFiles := make(map[string]bool)
+	touchedNodes := make(map[string]bool)
 
 	for _, edge := range g.edges {
-		if toRemove[edge.FromID] || toRemove[edge.ToID] {
+		if removed[edge] {
 			removedEdgeTypes[edge.Type] = true
 			if edge.Location.FilePath != "" {
 				removedEdgeFiles[edge.Location.FilePath] = true
 			}
-			continue // Skip edges that reference removed nodes
+			touchedNodes[edge.FromID] = true
+			touchedNodes[edge.ToID] = true
+			continue
 		}
 		newEdges = append(newEdges, edge)
 	}
@@ -918,26 +996,13 @@ func (g *Graph) RemoveFile(filePath string) (int, error) {
 	// GR-08: Update edgesByType index - rebuild affected types
 	for edgeType := range removedEdgeTypes {
 		if edgeType >= 0 && edgeType < NumEdgeTypes {
-			edges := g.edgesByType[edgeType]
-			filtered := make([]*Edge, 0, len(edges))
-			for _, e := range edges {
-				if !toRemove[e.FromID] && !toRemove[e.ToID] {
-					filtered = append(filtered, e)
-				}
-			}
-			g.edgesByType[edgeType] = filtered
+			g.edgesByType[edgeType] = filterEdges(g.edgesByType[edgeType], removed)
 		}
 	}
 
 	// GR-09: Update edgesByFile index - rebuild affected files
 	for filePath := range removedEdgeFiles {
-		edges := g.edgesByFile[filePath]
-		filtered := make([]*Edge, 0, len(edges))
-		for _, e := range edges {
-			if !toRemove[e.FromID] && !toRemove[e.ToID] {
-				filtered = append(filtered, e)
-			}
-		}
+		filtered := filterEdges(g.edgesByFile[filePath], removed)
 		if len(filtered) == 0 {
 			delete(g.edgesByFile, filePath)
 		} else {
@@ -946,19 +1011,19 @@ func (g *Graph) RemoveFile(filePath string) (int, error) {
 	}
 
 	// Rebuild edge references for remaining nodes
-	for _, node := range g.nodes {
-		node.Outgoing = filterEdges(node.Outgoing, toRemove)
-		node.Incoming = filterEdges(node.Incoming, toRemove)
+	for id := range touchedNodes {
+		if node, ok := g.nodes[id]; ok {
+			node.Outgoing = filterEdges(node.Outgoing, removed)
+			node.Incoming = filterEdges(node.Incoming, removed)
+		}
 	}
-
-	return len(toRemove), nil
 }
 
-// filterEdges removes edges that reference removed nodes.
-func filterEdges(edges []*Edge, removed map[string]bool) []*Edge {
+// filterEdges returns edges without the removed ones.
+func filterEdges(edges []*Edge, removed map[*Edge]bool) []*Edge {
 	result := make([]*Edge, 0, len(edges))
 	for _, e := range edges {
-		if !removed[e.FromID] && !removed[e.ToID] {
+		if !removed[e] {
 			result = append(result, e)
 		}
 	}
@@ -0,0 +1,189 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graphstore
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+)
+
+// bundleMagic identifies a graph bundle, inside its gzip stream.
+var bundleMagic = [8]byte{'A', 'L', 'B', 'U', 'N', 'D', 'L', 0}
+
+// headerSize is the size of bundleMagic plus the format version.
+const headerSize = len(bundleMagic) + 4
+
+// BundleInfo describes a graph bundle.
+type BundleInfo struct {
+	// SourceRoot is the project root on the machine that exported the
+	// bundle. Imports rebase the graph onto their own root.
+	SourceRoot string `json:"source_root"`
+
+	// ManifestHash is a SHA256 over the relative path and content hash of
+	// every file the graph was built from.
+	ManifestHash string `json:"manifest_hash"`
+
+	// Params are the languages and excludes the graph was built with.
+	Params BuildParams `json:"params"`
+
+	// Files, Nodes and Edges are the size of the bundled graph.
+	Files int `json:"files"`
+	Nodes int `json:"nodes"`
+	Edges int `json:"edges"`
+
+	// BuiltAtMilli is when the graph was built.
+	BuiltAtMilli int64 `json:"built_at_milli"`
+
+	// ExportedAtMilli is when the bundle was written.
+	ExportedAtMilli int64 `json:"exported_at_milli"`
+}
+
+// bundle is the gob-encoded body of a graph bundle.
+type bundle struct {
+	Info     BundleInfo
+	Snapshot snapshot
+}
+
+// Export writes the stored graph as a portable bundle.
+//
+// Description:
+//
+//	A bundle is a gzip stream of an 8-byte magic ("ALBUNDL\x00"), the
+//	big-endian uint32 FormatVersion, and a gob-encoded BundleInfo and
+//	snapshot. Paths inside the graph are relative to the project root,
+//	so a bundle can be imported into a checkout of the same repository
+//	at any path, such as a CI runner's workspace.
+//
+// Inputs:
+//   - w: Destination of the bundle.
+//
+// Outputs:
+//   - *BundleInfo: Description of the written bundle.
+//   - error: ErrNotFound if no graph is stored, ErrIncompatible or
+//     ErrCorrupted for an unreadable graph.db, or a write error.
+func (s *Store) Export(w io.Writer) (*BundleInfo, error) {
+	snap, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if snap.Manifest == nil {
+		return nil, fmt.Errorf("%w: no manifest", ErrCorrupted)
+	}
+
+	b := bundle{
+		Info: BundleInfo{
+			SourceRoot:      snap.ProjectRoot,
+			ManifestHash:    manifestHash(snap.Manifest),
+			Params:          snap.Params,
+			Files:           len(snap.Manifest.Files),
+			Nodes:           len(snap.Nodes),
+			Edges:           len(snap.Edges),
+			BuiltAtMilli:    snap.BuiltAtMilli,
+			ExportedAtMilli: time.Now().UnixMilli(),
+		},
+		Snapshot: *snap,
+	}
+
+	zw := gzip.NewWriter(w)
+	zw.Write(bundleMagic[:])
+	_ = binary.Write(zw, binary.BigEndian, FormatVersion)
+	if err := gob.NewEncoder(zw).Encode(&b); err != nil {
+		return nil, fmt.Errorf("graphstore: writing bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("graphstore: writing bundle: %w", err)
+	}
+	return &b.Info, nil
+}
+
+// Import installs a bundle written by Export as this project's graph.
+//
+// Description:
+//
+//	The bundle's manifest is checked against its ManifestHash, then the
+//	graph is rebased onto this store's project root and written to
+//	graph.db, replacing any stored graph. Files that differ between the
+//	bundle and the checkout are found on the next load; see
+//	LoadWithChanges.
+//
+// Inputs:
+//   - r: Source of the bundle.
+//
+// Outputs:
+//   - *BundleInfo: Description of the imported bundle.
+//   - error: ErrIncompatible for another magic or format version,
+//     ErrCorrupted if the bundle cannot be decoded or fails the manifest
+//     hash check, or an I/O error.
+func (s *Store) Import(r io.Reader) (*BundleInfo, error) {
+	zr, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIncompatible, err)
+	}
+	defer zr.Close()
+
+	var header [headerSize]byte
+	if _, err := io.ReadFull(zr, header[:]); err != nil || !bytes.Equal(header[:len(bundleMagic)], bundleMagic[:]) {
+		return nil, fmt.Errorf("%w: bad magic", ErrIncompatible)
+	}
+	if v := binary.BigEndian.Uint32(header[len(bundleMagic):]); v != FormatVersion {
+		return nil, fmt.Errorf("%w: version %d, want %d", ErrIncompatible, v, FormatVersion)
+	}
+
+	var b bundle
+	if err := gob.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	snap := &b.Snapshot
+	if snap.Manifest == nil {
+		return nil, fmt.Errorf("%w: no manifest", ErrCorrupted)
+	}
+	if got := manifestHash(snap.Manifest); got != b.Info.ManifestHash {
+		return nil, fmt.Errorf("%w: manifest hash %s, want %s", ErrCorrupted, got, b.Info.ManifestHash)
+	}
+
+	snap.ProjectRoot = s.projectRoot
+	snap.Manifest.ProjectRoot = s.projectRoot
+	if err := s.write(snap); err != nil {
+		return nil, err
+	}
+	return &b.Info, nil
+}
+
+// manifestHash hashes the relative path and content hash of every file
+// in m, in path order. It does not depend on the project root or on
+// file modification times.
+func manifestHash(m *manifest.Manifest) string {
+	paths := make([]string, 0, len(m.Files))
+	for p := range m.Files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Files[p].Hash))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graphstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	src := t.TempDir()
+	params := BuildParams{Languages: []string{"go"}, Excludes: []string{"vendor/*"}}
+
+	var buf bytes.Buffer
+	if _, err := New(src).Export(&buf); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Export before Save = %v, want ErrNotFound", err)
+	}
+	if err := New(src).Save(testGraph(t, src), testManifest(src, "aaaa"), params); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	info, err := New(src).Export(&buf)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if info.SourceRoot != src || info.Files != 1 || info.Nodes != 3 || info.Edges != 1 || info.ManifestHash == "" {
+		t.Errorf("Export info = %+v", info)
+	}
+	data := buf.Bytes()
+
+	t.Run("import into another checkout", func(t *testing.T) {
+		dst := t.TempDir()
+		store := New(dst)
+		imported, err := store.Import(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+		if imported.ManifestHash != info.ManifestHash {
+			t.Errorf("imported hash = %s, want %s", imported.ManifestHash, info.ManifestHash)
+		}
+
+		g, err := store.Load(testManifest(dst, "aaaa"), params)
+		if err != nil {
+			t.Fatalf("Load after Import: %v", err)
+		}
+		if g.ProjectRoot != dst || g.NodeCount() != 3 || g.EdgeCount() != 1 {
+			t.Errorf("root=%s nodes=%d edges=%d, want %s, 3 and 1", g.ProjectRoot, g.NodeCount(), g.EdgeCount(), dst)
+		}
+	})
+
+	t.Run("load with changes", func(t *testing.T) {
+		dst := t.TempDir()
+		store := New(dst)
+		if _, err := store.Import(bytes.NewReader(data)); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+
+		current := testManifest(dst, "bbbb")
+		current.Files["util.go"] = current.Files["main.go"]
+		if _, err := store.Load(current, params); !errors.Is(err, ErrStale) {
+			t.Errorf("Load = %v, want ErrStale", err)
+		}
+		g, changes, err := store.LoadWithChanges(current, params)
+		if err != nil {
+			t.Fatalf("LoadWithChanges: %v", err)
+		}
+		if g.NodeCount() != 3 {
+			t.Errorf("nodes = %d, want the stored 3", g.NodeCount())
+		}
+		if len(changes.Modified) != 1 || changes.Modified[0] != "main.go" || len(changes.Added) != 1 || changes.Added[0] != "util.go" {
+			t.Errorf("changes = %+v, want main.go modified and util.go added", changes)
+		}
+		if _, _, err := store.LoadWithChanges(current, BuildParams{Languages: []string{"python"}}); !errors.Is(err, ErrStale) {
+			t.Errorf("LoadWithChanges with other params = %v, want ErrStale", err)
+		}
+	})
+
+	t.Run("tampered manifest", func(t *testing.T) {
+		tampered := rewriteBundle(t, data, func(b *bundle) {
+			b.Snapshot.Manifest.Files["main.go"] = testManifest("", "cccc").Files["main.go"]
+		})
+		if _, err := New(t.TempDir()).Import(bytes.NewReader(tampered)); !errors.Is(err, ErrCorrupted) {
+			t.Errorf("Import = %v, want ErrCorrupted", err)
+		}
+	})
+
+	t.Run("not a bundle", func(t *testing.T) {
+		if _, err := New(t.TempDir()).Import(bytes.NewReader([]byte("ALGRAPH\x00"))); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("Import = %v, want ErrIncompatible", err)
+		}
+	})
+}
+
+// rewriteBundle decodes data, applies edit and encodes the result.
+func rewriteBundle(t *testing.T, data []byte, edit func(*bundle)) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var header [headerSize]byte
+	if _, err := io.ReadFull(zr, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	var b bundle
+	if err := gob.NewDecoder(zr).Decode(&b); err != nil {
+		t.Fatal(err)
+	}
+	edit(&b)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(bundleMagic[:])
+	_ = binary.Write(zw, binary.BigEndian, FormatVersion)
+	if err := gob.NewEncoder(zw).Encode(&b); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,651 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package graphstore persists frozen code graphs between server starts.
+//
+// A graph is saved to <project>/.aleutian/graph.db together with the file
+// manifest it was built from. On startup the stored manifest is compared
+// with a fresh scan of the project, and the graph is rebuilt from the
+// database only if no tracked file changed. Otherwise ErrStale is
+// returned and the caller re-parses the project.
+//
+// # File Format
+//
+// graph.db is a SQLite database whose user_version is FormatVersion. The
+// meta table holds the project root, build time, build parameters and
+// manifest header; files holds the manifest entries; nodes holds one
+// JSON-encoded symbol per row next to its ID, name, kind, file and line
+// range; symbol_children links parent symbols to their children; and
+// edges holds the graph edges with their locations. Databases with
+// another user_version, and files that are not SQLite databases, are
+// rejected with ErrIncompatible and are simply rebuilt. The database is
+// written to a temporary file that is renamed into place, so readers
+// never see a partial graph.
+//
+// # Bundles
+//
+// Export writes the stored graph as a gzip-compressed, root-independent
+// bundle that Import installs into another checkout of the project, so
+// ephemeral machines such as CI runners can start from a graph built
+// elsewhere. Imports are validated against the bundle's manifest hash.
+// LoadWithChanges then restores the graph together with the files that
+// differ from the checkout, so callers re-parse only those.
+//
+// # Thread Safety
+//
+// Store is safe for concurrent use. Concurrent Saves for the same project
+// are last-writer-wins.
+package graphstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+
+	// Registers the pure-Go "sqlite" driver that reads and writes graph.db.
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// DirName is the per-project directory holding Aleutian state.
+	DirName = ".aleutian"
+
+	// FileName is the graph store file inside DirName.
+	FileName = "graph.db"
+
+	// FormatVersion is the current file format version, stored as the
+	// SQLite user_version of graph.db. Bump it whenever the schema or the
+	// graph builder's output changes meaning.
+	FormatVersion uint32 = 1
+)
+
+// driverName is the database/sql driver registered by modernc.org/sqlite.
+const driverName = "sqlite"
+
+// schema creates the graph.db tables. The symbol column holds the full
+// symbol without its Children, which are rows in symbol_children; the
+// other node and edge columns exist for queries against the file.
+const schema = `
+CREATE TABLE meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE files (
+	path  TEXT PRIMARY KEY,
+	hash  TEXT    NOT NULL,
+	size  INTEGER NOT NULL,
+	mtime INTEGER NOT NULL
+);
+CREATE TABLE nodes (
+	id         TEXT PRIMARY KEY,
+	name       TEXT    NOT NULL,
+	kind       TEXT    NOT NULL,
+	file_path  TEXT    NOT NULL,
+	start_line INTEGER NOT NULL,
+	end_line   INTEGER NOT NULL,
+	symbol     TEXT    NOT NULL
+);
+CREATE INDEX nodes_file ON nodes (file_path);
+CREATE INDEX nodes_name ON nodes (name);
+CREATE TABLE symbol_children (
+	parent_id TEXT    NOT NULL,
+	position  INTEGER NOT NULL,
+	child_id  TEXT    NOT NULL,
+	PRIMARY KEY (parent_id, position)
+);
+CREATE TABLE edges (
+	from_id    TEXT    NOT NULL,
+	to_id      TEXT    NOT NULL,
+	type       INTEGER NOT NULL,
+	file_path  TEXT    NOT NULL,
+	start_line INTEGER NOT NULL,
+	end_line   INTEGER NOT NULL,
+	start_col  INTEGER NOT NULL,
+	end_col    INTEGER NOT NULL
+);
+CREATE INDEX edges_from ON edges (from_id);
+CREATE INDEX edges_to ON edges (to_id);
+`
+
+// Keys of the meta table.
+const (
+	metaProjectRoot = "project_root"
+	metaBuiltAt     = "built_at_milli"
+	metaParams      = "params"
+	metaManifest    = "manifest"
+)
+
+// Sentinel errors for store operations.
+var (
+	// ErrNotFound is returned when no stored graph exists.
+	ErrNotFound = errors.New("no stored graph")
+
+	// ErrStale is returned when the project changed since the graph was
+	// stored, or it was built with different languages or excludes.
+	ErrStale = errors.New("stored graph is stale")
+
+	// ErrIncompatible is returned for files with an unknown magic or
+	// format version.
+	ErrIncompatible = errors.New("incompatible graph store format")
+
+	// ErrCorrupted is returned when a stored graph cannot be decoded.
+	ErrCorrupted = errors.New("stored graph is corrupted")
+)
+
+// BuildParams are the Init parameters that shaped a graph. A stored
+// graph is only reused for the same parameters.
+type BuildParams struct {
+	Languages []string `json:"languages"`
+	Excludes  []string `json:"excludes"`
+}
+
+// equal reports whether p and o describe the same build, ignoring order.
+func (p BuildParams) equal(o BuildParams) bool {
+	return sameSet(p.Languages, o.Languages) && sameSet(p.Excludes, o.Excludes)
+}
+
+// snapshot is a stored graph in memory: the contents of graph.db, and
+// the gob-encoded body of a bundle.
+type snapshot struct {
+	ProjectRoot  string
+	BuiltAtMilli int64
+	Params       BuildParams
+	Manifest     *manifest.Manifest
+	Nodes        []storedNode
+	Edges        []storedEdge
+}
+
+// storedNode is a node's symbol without its Children pointers, which
+// would otherwise duplicate child symbols in the encoding. Children are
+// relinked by ID on load.
+type storedNode struct {
+	Symbol   ast.Symbol
+	ChildIDs []string
+}
+
+// storedEdge is a graph edge by node ID.
+type storedEdge struct {
+	FromID   string
+	ToID     string
+	Type     graph.EdgeType
+	Location ast.Location
+}
+
+// Store reads and writes the graph.db of one project.
+type Store struct {
+	projectRoot string
+	path        string
+}
+
+// New returns the store for projectRoot. No file is touched until Save
+// or Load.
+//
+// Inputs:
+//   - projectRoot: Absolute path to the project root.
+//
+// Outputs:
+//   - *Store: The store for <projectRoot>/.aleutian/graph.db.
+func New(projectRoot string) *Store {
+	return &Store{
+		projectRoot: projectRoot,
+		path:        filepath.Join(projectRoot, DirName, FileName),
+	}
+}
+
+// Path returns the graph.db path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save writes a frozen graph and the manifest it was built from.
+//
+// Inputs:
+//   - g: The graph. Must be frozen.
+//   - m: Manifest of the project files at build time. Must not be nil.
+//   - params: The languages and excludes the graph was built with.
+//
+// Outputs:
+//   - error: Non-nil if the graph is not frozen or the file cannot be
+//     written.
+func (s *Store) Save(g *graph.Graph, m *manifest.Manifest, params BuildParams) error {
+	if g == nil || !g.IsFrozen() {
+		return errors.New("graphstore: graph must be frozen")
+	}
+	if m == nil {
+		return errors.New("graphstore: manifest is required")
+	}
+
+	snap := snapshot{
+		ProjectRoot:  g.ProjectRoot,
+		BuiltAtMilli: g.BuiltAtMilli,
+		Params:       params,
+		Manifest:     m,
+		Nodes:        make([]storedNode, 0, g.NodeCount()),
+		Edges:        make([]storedEdge, 0, g.EdgeCount()),
+	}
+	for _, node := range g.Nodes() {
+		sym := *node.Symbol
+		var childIDs []string
+		for _, c := range sym.Children {
+			if c != nil {
+				childIDs = append(childIDs, c.ID)
+			}
+		}
+		sym.Children = nil
+		snap.Nodes = append(snap.Nodes, storedNode{Symbol: sym, ChildIDs: childIDs})
+	}
+	for _, e := range g.Edges() {
+		snap.Edges = append(snap.Edges, storedEdge{
+			FromID:   e.FromID,
+			ToID:     e.ToID,
+			Type:     e.Type,
+			Location: e.Location,
+		})
+	}
+
+	return s.write(&snap)
+}
+
+// write atomically replaces graph.db with a database holding snap.
+func (s *Store) write(snap *snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("graphstore: creating %s: %w", DirName, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), FileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("graphstore: creating temp file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if err := writeDatabase(tmp.Name(), snap); err != nil {
+		return fmt.Errorf("graphstore: writing graph: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("graphstore: replacing %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// writeDatabase creates the graph.db schema in the empty file at path
+// and inserts snap in one transaction.
+func writeDatabase(path string, snap *snapshot) (err error) {
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	// Pragmas are per connection. The file is private until it is renamed
+	// into place, so it needs no rollback journal.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA journal_mode = OFF; PRAGMA synchronous = OFF; PRAGMA user_version = %d;", FormatVersion)); err != nil {
+		return err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("creating schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	params, err := json.Marshal(snap.Params)
+	if err != nil {
+		return err
+	}
+	header := *snap.Manifest
+	header.Files = nil
+	manifestHeader, err := json.Marshal(&header)
+	if err != nil {
+		return err
+	}
+	meta := [][2]string{
+		{metaProjectRoot, snap.ProjectRoot},
+		{metaBuiltAt, strconv.FormatInt(snap.BuiltAtMilli, 10)},
+		{metaParams, string(params)},
+		{metaManifest, string(manifestHeader)},
+	}
+	for _, kv := range meta {
+		if _, err := tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)`, kv[0], kv[1]); err != nil {
+			return fmt.Errorf("inserting meta: %w", err)
+		}
+	}
+
+	insertFile, err := tx.Prepare(`INSERT INTO files (path, hash, size, mtime) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	for path, f := range snap.Manifest.Files {
+		if _, err := insertFile.Exec(path, f.Hash, f.Size, f.Mtime); err != nil {
+			return fmt.Errorf("inserting file %s: %w", path, err)
+		}
+	}
+
+	insertNode, err := tx.Prepare(`INSERT INTO nodes (id, name, kind, file_path, start_line, end_line, symbol) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertChild, err := tx.Prepare(`INSERT INTO symbol_children (parent_id, position, child_id) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	for _, n := range snap.Nodes {
+		sym := n.Symbol
+		data, err := json.Marshal(&sym)
+		if err != nil {
+			return fmt.Errorf("encoding node %s: %w", sym.ID, err)
+		}
+		if _, err := insertNode.Exec(sym.ID, sym.Name, sym.Kind.String(), sym.FilePath, sym.StartLine, sym.EndLine, string(data)); err != nil {
+			return fmt.Errorf("inserting node %s: %w", sym.ID, err)
+		}
+		for i, childID := range n.ChildIDs {
+			if _, err := insertChild.Exec(sym.ID, i, childID); err != nil {
+				return fmt.Errorf("inserting child of %s: %w", sym.ID, err)
+			}
+		}
+	}
+
+	insertEdge, err := tx.Prepare(`INSERT INTO edges (from_id, to_id, type, file_path, start_line, end_line, start_col, end_col) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	for _, e := range snap.Edges {
+		loc := e.Location
+		if _, err := insertEdge.Exec(e.FromID, e.ToID, int(e.Type), loc.FilePath, loc.StartLine, loc.EndLine, loc.StartCol, loc.EndCol); err != nil {
+			return fmt.Errorf("inserting edge %s -> %s: %w", e.FromID, e.ToID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Load reads graph.db and rebuilds the stored graph if it is still
+// current.
+//
+// Description:
+//
+//	The stored manifest is diffed against current by content hash. Any
+//	added, modified or deleted file, a different project root, or
+//	different build parameters make the graph stale. The returned graph
+//	is frozen and keeps its original BuiltAtMilli.
+//
+// Inputs:
+//   - current: A fresh manifest scan of the project. Must not be nil.
+//   - params: The languages and excludes of the requested build.
+//
+// Outputs:
+//   - *graph.Graph: The restored, frozen graph.
+//   - error: ErrNotFound, ErrStale, ErrIncompatible or ErrCorrupted
+//     (possibly wrapped), or an I/O error.
+func (s *Store) Load(current *manifest.Manifest, params BuildParams) (*graph.Graph, error) {
+	snap, changes, err := s.compare(current, params)
+	if err != nil {
+		return nil, err
+	}
+	if changes.HasChanges() {
+		return nil, fmt.Errorf("%w: %d added, %d modified, %d deleted files",
+			ErrStale, len(changes.Added), len(changes.Modified), len(changes.Deleted))
+	}
+	return snap.restore()
+}
+
+// LoadWithChanges restores the stored graph even if project files
+// changed since it was stored, and reports which.
+//
+// Description:
+//
+//	Lets callers top up a stored graph by re-parsing only the changed
+//	files, for example after importing a bundle built on another
+//	machine. A different project root or different build parameters
+//	still make the graph stale.
+//
+// Inputs:
+//   - current: A fresh manifest scan of the project. Must not be nil.
+//   - params: The languages and excludes of the requested build.
+//
+// Outputs:
+//   - *graph.Graph: The restored, frozen graph as it was stored.
+//   - *manifest.Changes: Files added, modified or deleted since. Paths
+//     are relative to the project root.
+//   - error: ErrNotFound, ErrStale, ErrIncompatible or ErrCorrupted
+//     (possibly wrapped), or an I/O error.
+func (s *Store) LoadWithChanges(current *manifest.Manifest, params BuildParams) (*graph.Graph, *manifest.Changes, error) {
+	snap, changes, err := s.compare(current, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := snap.restore()
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, changes, nil
+}
+
+// compare reads graph.db and diffs its manifest against current. It
+// fails with ErrStale if the root or build parameters differ.
+func (s *Store) compare(current *manifest.Manifest, params BuildParams) (*snapshot, *manifest.Changes, error) {
+	if current == nil {
+		return nil, nil, errors.New("graphstore: manifest is required")
+	}
+
+	snap, err := s.read()
+	if err != nil {
+		return nil, nil, err
+	}
+	if snap.ProjectRoot != s.projectRoot || snap.Manifest == nil {
+		return nil, nil, fmt.Errorf("%w: stored for %q", ErrStale, snap.ProjectRoot)
+	}
+	if !snap.Params.equal(params) {
+		return nil, nil, fmt.Errorf("%w: built with different languages or excludes", ErrStale)
+	}
+	return snap, manifest.NewManifestManager().Diff(snap.Manifest, current), nil
+}
+
+// read opens graph.db and reads its snapshot.
+func (s *Store) read() (*snapshot, error) {
+	if _, err := os.Stat(s.path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("graphstore: reading %s: %w", s.path, err)
+	}
+	db, err := sql.Open(driverName, s.path)
+	if err != nil {
+		return nil, fmt.Errorf("graphstore: opening %s: %w", s.path, err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// Reading the header fails for files that are not SQLite databases.
+	var version uint32
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIncompatible, err)
+	}
+	if version != FormatVersion {
+		return nil, fmt.Errorf("%w: version %d, want %d", ErrIncompatible, version, FormatVersion)
+	}
+
+	snap, err := readDatabase(db)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return snap, nil
+}
+
+// readDatabase reads the snapshot stored in an open graph.db.
+func readDatabase(db *sql.DB) (*snapshot, error) {
+	meta := make(map[string]string)
+	err := queryRows(db, `SELECT key, value FROM meta`, func(rows *sql.Rows) error {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		meta[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{metaProjectRoot, metaBuiltAt, metaParams, metaManifest} {
+		if _, ok := meta[key]; !ok {
+			return nil, fmt.Errorf("missing meta %q", key)
+		}
+	}
+
+	snap := &snapshot{ProjectRoot: meta[metaProjectRoot]}
+	if snap.BuiltAtMilli, err = strconv.ParseInt(meta[metaBuiltAt], 10, 64); err != nil {
+		return nil, fmt.Errorf("meta %q: %w", metaBuiltAt, err)
+	}
+	if err := json.Unmarshal([]byte(meta[metaParams]), &snap.Params); err != nil {
+		return nil, fmt.Errorf("meta %q: %w", metaParams, err)
+	}
+	snap.Manifest = &manifest.Manifest{}
+	if err := json.Unmarshal([]byte(meta[metaManifest]), snap.Manifest); err != nil {
+		return nil, fmt.Errorf("meta %q: %w", metaManifest, err)
+	}
+
+	snap.Manifest.Files = make(map[string]manifest.FileEntry)
+	err = queryRows(db, `SELECT path, hash, size, mtime FROM files`, func(rows *sql.Rows) error {
+		var f manifest.FileEntry
+		if err := rows.Scan(&f.Path, &f.Hash, &f.Size, &f.Mtime); err != nil {
+			return err
+		}
+		snap.Manifest.Files[f.Path] = f
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	err = queryRows(db, `SELECT id, symbol FROM nodes ORDER BY rowid`, func(rows *sql.Rows) error {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return err
+		}
+		var n storedNode
+		if err := json.Unmarshal(data, &n.Symbol); err != nil {
+			return fmt.Errorf("node %s: %w", id, err)
+		}
+		index[id] = len(snap.Nodes)
+		snap.Nodes = append(snap.Nodes, n)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(db, `SELECT parent_id, child_id FROM symbol_children ORDER BY parent_id, position`, func(rows *sql.Rows) error {
+		var parentID, childID string
+		if err := rows.Scan(&parentID, &childID); err != nil {
+			return err
+		}
+		i, ok := index[parentID]
+		if !ok {
+			return fmt.Errorf("child %s of unknown node %s", childID, parentID)
+		}
+		snap.Nodes[i].ChildIDs = append(snap.Nodes[i].ChildIDs, childID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(db, `SELECT from_id, to_id, type, file_path, start_line, end_line, start_col, end_col FROM edges ORDER BY rowid`, func(rows *sql.Rows) error {
+		var e storedEdge
+		var edgeType int
+		loc := &e.Location
+		if err := rows.Scan(&e.FromID, &e.ToID, &edgeType, &loc.FilePath, &loc.StartLine, &loc.EndLine, &loc.StartCol, &loc.EndCol); err != nil {
+			return err
+		}
+		e.Type = graph.EdgeType(edgeType)
+		snap.Edges = append(snap.Edges, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// queryRows runs query and calls scan for each result row.
+func queryRows(db *sql.DB, query string, scan func(*sql.Rows) error) error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// restore rebuilds the graph from a decoded snapshot.
+func (snap *snapshot) restore() (*graph.Graph, error) {
+	g := graph.NewGraph(snap.ProjectRoot,
+		graph.WithMaxNodes(max(len(snap.Nodes), graph.DefaultMaxNodes)),
+		graph.WithMaxEdges(max(len(snap.Edges), graph.DefaultMaxEdges)),
+	)
+
+	symbols := make(map[string]*ast.Symbol, len(snap.Nodes))
+	for i := range snap.Nodes {
+		sym := &snap.Nodes[i].Symbol
+		if _, err := g.AddNode(sym); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", ErrCorrupted, sym.ID, err)
+		}
+		symbols[sym.ID] = sym
+	}
+	for i := range snap.Nodes {
+		for _, id := range snap.Nodes[i].ChildIDs {
+			if child, ok := symbols[id]; ok {
+				snap.Nodes[i].Symbol.Children = append(snap.Nodes[i].Symbol.Children, child)
+			}
+		}
+	}
+	for _, e := range snap.Edges {
+		if err := g.AddEdge(e.FromID, e.ToID, e.Type, e.Location); err != nil {
+			return nil, fmt.Errorf("%w: edge %s -> %s: %v", ErrCorrupted, e.FromID, e.ToID, err)
+		}
+	}
+
+	g.Freeze()
+	g.BuiltAtMilli = snap.BuiltAtMilli
+	return g, nil
+}
+
+// sameSet reports whether a and b contain the same strings.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
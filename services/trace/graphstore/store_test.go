@@ -0,0 +1,163 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graphstore
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+)
+
+func testGraph(t *testing.T, root string) *graph.Graph {
+	t.Helper()
+	method := &ast.Symbol{ID: "main.go:5:Run", Name: "Run", Kind: ast.SymbolKindMethod, FilePath: "main.go", StartLine: 5, Language: "go"}
+	server := &ast.Symbol{ID: "main.go:3:Server", Name: "Server", Kind: ast.SymbolKindStruct, FilePath: "main.go", StartLine: 3, Language: "go", Children: []*ast.Symbol{method}}
+	mainFn := &ast.Symbol{ID: "main.go:10:main", Name: "main", Kind: ast.SymbolKindFunction, FilePath: "main.go", StartLine: 10, Language: "go"}
+
+	g := graph.NewGraph(root)
+	for _, sym := range []*ast.Symbol{server, method, mainFn} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if err := g.AddEdge(mainFn.ID, method.ID, graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 11}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	g.Freeze()
+	return g
+}
+
+func testManifest(root, hash string) *manifest.Manifest {
+	return &manifest.Manifest{
+		ProjectRoot: root,
+		Files: map[string]manifest.FileEntry{
+			"main.go": {Path: "main.go", Hash: hash, Size: 42},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	root := t.TempDir()
+	store := New(root)
+	params := BuildParams{Languages: []string{"go"}, Excludes: []string{"vendor/*", "*_test.go"}}
+	current := testManifest(root, "aaaa")
+
+	if _, err := store.Load(current, params); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load before Save = %v, want ErrNotFound", err)
+	}
+
+	g := testGraph(t, root)
+	if err := store.Save(g, current, params); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		// Parameter order does not matter.
+		loaded, err := store.Load(current, BuildParams{Languages: []string{"go"}, Excludes: []string{"*_test.go", "vendor/*"}})
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if !loaded.IsFrozen() || loaded.BuiltAtMilli != g.BuiltAtMilli {
+			t.Errorf("frozen=%v built=%d, want frozen graph built at %d", loaded.IsFrozen(), loaded.BuiltAtMilli, g.BuiltAtMilli)
+		}
+		if loaded.NodeCount() != 3 || loaded.EdgeCount() != 1 {
+			t.Errorf("nodes=%d edges=%d, want 3 and 1", loaded.NodeCount(), loaded.EdgeCount())
+		}
+		server, ok := loaded.GetNode("main.go:3:Server")
+		if !ok || len(server.Symbol.Children) != 1 {
+			t.Fatalf("Server node = %+v, want one child", server)
+		}
+		method, _ := loaded.GetNode("main.go:5:Run")
+		if server.Symbol.Children[0] != method.Symbol {
+			t.Error("child symbol not relinked to its node")
+		}
+		if len(method.Incoming) != 1 || method.Incoming[0].FromID != "main.go:10:main" {
+			t.Errorf("Run incoming = %+v", method.Incoming)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		cases := map[string]struct {
+			m      *manifest.Manifest
+			params BuildParams
+		}{
+			"modified file": {testManifest(root, "bbbb"), params},
+			"languages":     {current, BuildParams{Languages: []string{"go", "python"}, Excludes: params.Excludes}},
+		}
+		for name, tc := range cases {
+			if _, err := store.Load(tc.m, tc.params); !errors.Is(err, ErrStale) {
+				t.Errorf("%s: Load = %v, want ErrStale", name, err)
+			}
+		}
+		if _, err := New(t.TempDir()).Load(current, params); !errors.Is(err, ErrNotFound) {
+			t.Errorf("other project: Load = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("sqlite database", func(t *testing.T) {
+		db, err := sql.Open(driverName, store.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		var callers []string
+		err = queryRows(db, `SELECT n.name FROM edges e JOIN nodes n ON n.id = e.from_id WHERE e.to_id = 'main.go:5:Run'`, func(rows *sql.Rows) error {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			callers = append(callers, name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		if len(callers) != 1 || callers[0] != "main" {
+			t.Errorf("callers of Run = %v, want [main]", callers)
+		}
+		var hash string
+		if err := db.QueryRow(`SELECT hash FROM files WHERE path = 'main.go'`).Scan(&hash); err != nil || hash != "aaaa" {
+			t.Errorf("main.go hash = %q (%v), want aaaa", hash, err)
+		}
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		path := filepath.Join(root, DirName, FileName)
+
+		// Another schema version.
+		db, err := sql.Open(driverName, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`PRAGMA user_version = 99`); err != nil {
+			t.Fatal(err)
+		}
+		db.Close()
+		if _, err := store.Load(current, params); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("other version: Load = %v, want ErrIncompatible", err)
+		}
+
+		// Not a SQLite database.
+		if err := os.WriteFile(path, []byte("ALGRAPH\x00\x00\x00\x00\x01 not a database"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Load(current, params); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("other format: Load = %v, want ErrIncompatible", err)
+		}
+	})
+}
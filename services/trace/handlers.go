@@ -12,12 +12,15 @@ package code_buddy
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/jobs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/memory"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/seeder"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
@@ -35,6 +38,8 @@ type Handlers struct {
 	memoryRetriever  *memory.MemoryRetriever
 	lifecycleManager *memory.LifecycleManager
 	dataSpace        string
+	webhook          *webhook.Receiver
+	jobs             *jobs.Queue
 }
 
 // NewHandlers creates handlers for the given service.
@@ -97,6 +102,52 @@ func (h *Handlers) WithMemory(dataSpace string) *Handlers {
 	return h
 }
 
+// WithWebhook sets the receiver for PR webhooks.
+//
+// Description:
+//
+//	Enables POST /v1/trace/webhook. Without a receiver the endpoint
+//	responds 503 so misconfigured hooks are visible in the provider UI.
+//
+// Inputs:
+//
+//	r - The webhook receiver. May be nil.
+//
+// Outputs:
+//
+//	*Handlers - The handlers for method chaining
+func (h *Handlers) WithWebhook(r *webhook.Receiver) *Handlers {
+	h.webhook = r
+	return h
+}
+
+// HandleWebhook handles POST /v1/trace/webhook.
+//
+// Description:
+//
+//	Receives GitHub pull_request and GitLab Merge Request Hook deliveries.
+//	Authentication, parsing, and scheduling are done by webhook.Receiver;
+//	analysis runs in the background and is posted as a PR comment.
+//
+// Response:
+//
+//	202 Accepted - Analysis scheduled
+//	204 No Content - Event needs no analysis
+//	400 Bad Request - Unknown provider or malformed payload
+//	401 Unauthorized - Signature or token mismatch
+//	503 Service Unavailable - Webhooks not configured
+func (h *Handlers) HandleWebhook(c *gin.Context) {
+	if h.webhook == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Webhooks are not configured",
+			Code:    "WEBHOOK_DISABLED",
+			Details: "Set WEBHOOK_GITHUB_SECRET or WEBHOOK_GITLAB_TOKEN",
+		})
+		return
+	}
+	h.webhook.ServeHTTP(c.Writer, c.Request)
+}
+
 // HandleInit handles POST /v1/codebuddy/init.
 //
 // Description:
@@ -131,26 +182,7 @@ func (h *Handlers) HandleInit(c *gin.Context) {
 
 	resp, err := h.svc.Init(c.Request.Context(), req.ProjectRoot, req.Languages, req.ExcludePatterns)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		errCode := "INIT_FAILED"
-
-		if errors.Is(err, ErrRelativePath) {
-			statusCode = http.StatusBadRequest
-			errCode = "INVALID_PATH"
-		} else if errors.Is(err, ErrPathTraversal) {
-			statusCode = http.StatusBadRequest
-			errCode = "PATH_TRAVERSAL"
-		} else if errors.Is(err, ErrProjectTooLarge) {
-			statusCode = http.StatusBadRequest
-			errCode = "PROJECT_TOO_LARGE"
-		} else if errors.Is(err, ErrInitInProgress) {
-			statusCode = http.StatusConflict
-			errCode = "INIT_IN_PROGRESS"
-		} else if errors.Is(err, ErrInitTimeout) {
-			statusCode = http.StatusGatewayTimeout
-			errCode = "INIT_TIMEOUT"
-		}
-
+		statusCode, errCode := statusForError(initErrorCodes, err, "INIT_FAILED")
 		logger.Error("Init failed", "error", err)
 		c.JSON(statusCode, ErrorResponse{
 			Error: err.Error(),
@@ -210,23 +242,7 @@ func (h *Handlers) HandleContext(c *gin.Context) {
 
 	resp, err := h.svc.GetContext(c.Request.Context(), req.GraphID, req.Query, budget)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		errCode := "CONTEXT_FAILED"
-
-		if errors.Is(err, ErrGraphNotInitialized) {
-			statusCode = http.StatusBadRequest
-			errCode = "GRAPH_NOT_INITIALIZED"
-		} else if errors.Is(err, ErrGraphExpired) {
-			statusCode = http.StatusBadRequest
-			errCode = "GRAPH_EXPIRED"
-		} else if errors.Is(err, cbcontext.ErrEmptyQuery) {
-			statusCode = http.StatusBadRequest
-			errCode = "EMPTY_QUERY"
-		} else if errors.Is(err, cbcontext.ErrQueryTooLong) {
-			statusCode = http.StatusBadRequest
-			errCode = "QUERY_TOO_LONG"
-		}
-
+		statusCode, errCode := statusForError(contextErrorCodes, err, "CONTEXT_FAILED")
 		logger.Error("Context assembly failed", "error", err)
 		c.JSON(statusCode, ErrorResponse{
 			Error: err.Error(),
@@ -308,6 +324,78 @@ func (h *Handlers) HandleSymbol(c *gin.Context) {
 	c.JSON(http.StatusOK, SymbolResponse{Symbol: sym})
 }
 
+// HandleResolveSymbols handles POST /v1/codebuddy/symbols/resolve.
+//
+// Description:
+//
+//	Resolves many (file, line, column) positions to the symbols containing
+//	them in one call, so editor integrations need not issue one symbol
+//	lookup per position. Positions that cannot be resolved are reported
+//	per result; they do not fail the request.
+//
+// Request Body:
+//
+//	ResolveSymbolsRequest
+//
+// Response:
+//
+//	200 OK: ResolveSymbolsResponse (results in request order)
+//	400 Bad Request: Invalid body, too many positions, or graph not initialized
+func (h *Handlers) HandleResolveSymbols(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleResolveSymbols")
+
+	var req ResolveSymbolsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body: graph_id and positions are required",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+	if len(req.Positions) > MaxResolvePositions {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: fmt.Sprintf("at most %d positions per request, got %d", MaxResolvePositions, len(req.Positions)),
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	logger.Info("Resolving positions", "graph_id", req.GraphID, "positions", len(req.Positions))
+
+	results, err := h.svc.ResolvePositions(c.Request.Context(), req.GraphID, req.Positions)
+	if err != nil {
+		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+				Code:  "GRAPH_NOT_INITIALIZED",
+			})
+			return
+		}
+
+		logger.Error("Resolve positions failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: err.Error(),
+			Code:  "QUERY_FAILED",
+		})
+		return
+	}
+
+	resp := ResolveSymbolsResponse{Results: results}
+	for _, r := range results {
+		if r.Symbol != nil {
+			resp.Resolved++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	logger.Info("Resolved positions", "resolved", resp.Resolved, "failed", resp.Failed)
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // HandleCallers handles GET /v1/codebuddy/callers.
 //
 // Description:
@@ -318,11 +406,11 @@ func (h *Handlers) HandleSymbol(c *gin.Context) {
 //
 //	graph_id: ID of the graph to query (required)
 //	function: Name of the function to find callers for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit, cursor, fields: Pagination (see parsePage)
 //
 // Response:
 //
-//	200 OK: CallersResponse (may be empty array)
+//	200 OK: CallersResponse with page (may be empty array)
 //	400 Bad Request: Missing parameters or graph not initialized
 func (h *Handlers) HandleCallers(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
@@ -337,10 +425,11 @@ func (h *Handlers) HandleCallers(c *gin.Context) {
 		})
 		return
 	}
-
-	if req.Limit <= 0 {
-		req.Limit = 50
+	page, ok := parsePage(c)
+	if !ok {
+		return
 	}
+	req.Limit = page.fetchLimit()
 
 	logger.Info("Finding callers", "graph_id", req.GraphID, "function", req.Function)
 
@@ -364,10 +453,10 @@ func (h *Handlers) HandleCallers(c *gin.Context) {
 
 	logger.Info("Found callers", "count", len(callers))
 
-	c.JSON(http.StatusOK, CallersResponse{
+	writePage(c, CallersResponse{
 		Function: req.Function,
 		Callers:  callers,
-	})
+	}, "callers", page, len(callers) < req.Limit)
 }
 
 // HandleImplementations handles GET /v1/codebuddy/implementations.
@@ -380,11 +469,11 @@ func (h *Handlers) HandleCallers(c *gin.Context) {
 //
 //	graph_id: ID of the graph to query (required)
 //	interface: Name of the interface to find implementations for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit, cursor, fields: Pagination (see parsePage)
 //
 // Response:
 //
-//	200 OK: ImplementationsResponse (may be empty array)
+//	200 OK: ImplementationsResponse with page (may be empty array)
 //	400 Bad Request: Missing parameters or graph not initialized
 func (h *Handlers) HandleImplementations(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
@@ -399,10 +488,11 @@ func (h *Handlers) HandleImplementations(c *gin.Context) {
 		})
 		return
 	}
-
-	if req.Limit <= 0 {
-		req.Limit = 50
+	page, ok := parsePage(c)
+	if !ok {
+		return
 	}
+	req.Limit = page.fetchLimit()
 
 	logger.Info("Finding implementations", "graph_id", req.GraphID, "interface", req.Interface)
 
@@ -426,10 +516,73 @@ func (h *Handlers) HandleImplementations(c *gin.Context) {
 
 	logger.Info("Found implementations", "count", len(implementations))
 
-	c.JSON(http.StatusOK, ImplementationsResponse{
+	writePage(c, ImplementationsResponse{
 		Interface:       req.Interface,
 		Implementations: implementations,
-	})
+	}, "implementations", page, len(implementations) < req.Limit)
+}
+
+// HandleTests handles GET /v1/codebuddy/tests.
+//
+// Description:
+//
+//	Finds the tests that exercise the given function, by static call
+//	analysis and naming conventions. Only tests in the graph are found,
+//	so the graph must be initialized without excluding test files.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (required)
+//	function: Name of the function to find tests for (required)
+//	limit, cursor, fields: Pagination (see parsePage)
+//
+// Response:
+//
+//	200 OK: TestsResponse with page (may be empty array)
+//	400 Bad Request: Missing parameters or graph not initialized
+func (h *Handlers) HandleTests(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleTests")
+
+	var req TestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid query parameters: graph_id and function are required",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	req.Limit = page.fetchLimit()
+
+	tests, err := h.svc.FindTests(c.Request.Context(), req.GraphID, req.Function, req.Limit)
+	if err != nil {
+		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+				Code:  "GRAPH_NOT_INITIALIZED",
+			})
+			return
+		}
+
+		logger.Error("Find tests failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: err.Error(),
+			Code:  "QUERY_FAILED",
+		})
+		return
+	}
+
+	logger.Info("Found tests", "function", req.Function, "count", len(tests))
+
+	writePage(c, TestsResponse{
+		Function: req.Function,
+		Tests:    tests,
+	}, "tests", page, len(tests) < req.Limit)
 }
 
 // HandleHealth handles GET /v1/codebuddy/health.
@@ -727,15 +880,15 @@ func getOrCreateRequestID(c *gin.Context) string {
 //
 // Query Parameters:
 //
-//	limit: Maximum number of results (optional, default 10)
-//	offset: Number of results to skip for pagination (optional)
+//	limit, cursor, fields: Pagination (see parsePage)
+//	offset: Number of results to skip when no cursor is given (optional)
 //	memory_type: Filter by memory type (optional)
 //	include_archived: Include archived memories (optional, default false)
 //	min_confidence: Minimum confidence threshold (optional)
 //
 // Response:
 //
-//	200 OK: MemoriesResponse
+//	200 OK: MemoriesResponse with page
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleListMemories(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
@@ -759,11 +912,18 @@ func (h *Handlers) HandleListMemories(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	if c.Query("cursor") == "" && req.Offset > 0 {
+		page.offset = req.Offset
+	}
 
 	memories, err := h.memoryStore.List(
 		c.Request.Context(),
-		req.Limit,
-		req.Offset,
+		page.limit+1,
+		page.offset,
 		req.MemoryType,
 		req.IncludeArchived,
 		req.MinConfidence,
@@ -779,10 +939,10 @@ func (h *Handlers) HandleListMemories(c *gin.Context) {
 
 	logger.Info("Listed memories", "count", len(memories))
 
-	c.JSON(http.StatusOK, memory.MemoriesResponse{
+	writePage(c, memory.MemoriesResponse{
 		Memories: memories,
-		Total:    len(memories),
-	})
+		Total:    min(len(memories), page.limit),
+	}, "memories", page.fetchedFromOffset(), len(memories) <= page.limit)
 }
 
 // HandleStoreMemory handles POST /v1/codebuddy/memories.
@@ -887,7 +1047,7 @@ func (h *Handlers) HandleStoreMemory(c *gin.Context) {
 //
 // Response:
 //
-//	200 OK: RetrieveResponse
+//	200 OK: RetrieveResponse with page (limit, cursor, fields: see parsePage)
 //	400 Bad Request: Validation error
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
@@ -912,6 +1072,10 @@ func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
 		})
 		return
 	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
 	opts := memory.RetrieveOptions{
 		Query:           req.Query,
@@ -935,9 +1099,9 @@ func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
 		"query", req.Query,
 		"count", len(results))
 
-	c.JSON(http.StatusOK, memory.RetrieveResponse{
+	writePage(c, memory.RetrieveResponse{
 		Results: results,
-	})
+	}, "results", page, true)
 }
 
 // HandleDeleteMemory handles DELETE /v1/codebuddy/memories/:id.
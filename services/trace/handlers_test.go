@@ -12,11 +12,16 @@ package code_buddy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/gin-gonic/gin"
 )
 
@@ -303,6 +308,89 @@ func TestHandlers_HandleSymbol_GraphNotInitialized(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleResolveSymbols(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	idx := index.NewSymbolIndex()
+	for _, sym := range []*ast.Symbol{
+		{ID: "server.go:3:Server", Name: "Server", Kind: ast.SymbolKindStruct, FilePath: "server.go",
+			StartLine: 3, EndLine: 20, Language: "go"},
+		{ID: "server.go:8:Start", Name: "Start", Kind: ast.SymbolKindMethod, FilePath: "server.go",
+			StartLine: 8, EndLine: 12, StartCol: 0, EndCol: 1, Language: "go"},
+	} {
+		if err := idx.Add(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc.mu.Lock()
+	svc.graphs["g1"] = &CachedGraph{
+		Graph:       graph.NewGraph("/tmp/project"),
+		Index:       idx,
+		ProjectRoot: "/tmp/project",
+	}
+	svc.mu.Unlock()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/v1/codebuddy/symbols/resolve", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("partial failure", func(t *testing.T) {
+		w := post(`{"graph_id":"g1","positions":[
+			{"file_path":"server.go","line":10,"column":4},
+			{"file_path":"/tmp/project/server.go","line":15},
+			{"file_path":"server.go","line":1},
+			{"file_path":"other.go","line":1},
+			{"file_path":"server.go","line":0}]}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp ResolveSymbolsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.Resolved != 2 || resp.Failed != 3 || len(resp.Results) != 5 {
+			t.Fatalf("expected 2 resolved and 3 failed, got %+v", resp)
+		}
+
+		var got []string
+		for _, r := range resp.Results {
+			if r.Symbol != nil {
+				got = append(got, r.Symbol.Name)
+			} else {
+				got = append(got, r.Code)
+			}
+		}
+		want := []string{"Start", "Server", "SYMBOL_NOT_FOUND", "FILE_NOT_INDEXED", "INVALID_POSITION"}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("results = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+
+	t.Run("too many positions", func(t *testing.T) {
+		positions := make([]SymbolPosition, MaxResolvePositions+1)
+		body, _ := json.Marshal(ResolveSymbolsRequest{GraphID: "g1", Positions: positions})
+		if w := post(string(body)); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("graph not initialized", func(t *testing.T) {
+		w := post(`{"graph_id":"missing","positions":[{"file_path":"server.go","line":1}]}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
 func TestSymbolInfoFromAST(t *testing.T) {
 	t.Run("nil symbol returns nil", func(t *testing.T) {
 		result := SymbolInfoFromAST(nil)
@@ -359,3 +447,103 @@ func TestHandlers_HandleGetGraphStats_GraphNotFound(t *testing.T) {
 		t.Errorf("expected code 'GRAPH_NOT_FOUND', got %q", errResp.Code)
 	}
 }
+
+func TestHandlers_GraphRead_ETag(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	svc.mu.Lock()
+	svc.graphs["g1"] = &CachedGraph{
+		Graph:        graph.NewGraph("/tmp/project"),
+		ProjectRoot:  "/tmp/project",
+		BuiltAtMilli: 1000,
+		Generation:   1,
+	}
+	svc.mu.Unlock()
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/v1/codebuddy/debug/graph/stats?graph_id=g1", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d and %q", first.Code, etag)
+	}
+
+	if w := get(etag); w.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	// A refresh produces a new generation, invalidating the ETag.
+	svc.mu.Lock()
+	svc.graphs["g1"].Generation = 2
+	svc.mu.Unlock()
+
+	w := get(etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("expected 200 with a new ETag, got %d and %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestHandlers_HandleTests(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, map[string]string{
+		"go.mod":        "module example.com/calc\n\ngo 1.22\n",
+		"calc.go":       "package calc\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n",
+		"calc_test.go":  "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal()\n\t}\n}\n",
+		"vendor/x/x.go": "package x\n",
+	})
+
+	svc := NewService(DefaultServiceConfig())
+	initResp, err := svc.Init(context.Background(), dir, []string{"go"}, []string{"vendor/*"})
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	get := func(function string) TestsResponse {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/v1/codebuddy/tests?graph_id="+initResp.GraphID+"&function="+function, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var resp TestsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp
+	}
+
+	resp := get("Add")
+	if len(resp.Tests) != 1 || resp.Tests[0].TestName != "TestAdd" {
+		t.Fatalf("tests for Add = %+v, want TestAdd", resp.Tests)
+	}
+	if resp.Tests[0].Source != analysis.TestLinkDirectCall {
+		t.Errorf("source = %q, want %q", resp.Tests[0].Source, analysis.TestLinkDirectCall)
+	}
+	if resp := get("Sub"); len(resp.Tests) != 0 {
+		t.Errorf("tests for Sub = %+v, want none", resp.Tests)
+	}
+}
+
+func TestHandlers_HandleTests_GraphNotInitialized(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	req, _ := http.NewRequest("GET", "/v1/codebuddy/tests?graph_id=nonexistent&function=test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package httpcache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content codings, in order of preference.
+const (
+	EncodingZstd = "zstd"
+	EncodingGzip = "gzip"
+)
+
+// DefaultMinSize is the smallest response body Compress compresses.
+const DefaultMinSize = 1024
+
+// CompressOptions configures Compress.
+type CompressOptions struct {
+	// MinSize is the smallest body to compress. Smaller bodies are sent as
+	// is, since compression would barely shrink them. Zero means
+	// DefaultMinSize.
+	MinSize int
+
+	// GzipLevel is the gzip compression level. Zero means
+	// gzip.DefaultCompression.
+	GzipLevel int
+}
+
+// Compress returns gin middleware that compresses responses.
+//
+// # Description
+//
+// The coding is negotiated from the Accept-Encoding header: zstd is
+// preferred, then gzip, honoring q-values. The handler's response is
+// buffered, and a 200 response of at least MinSize bytes is sent
+// compressed with Content-Encoding set. Responses the handler already
+// encoded, other statuses and requests that accept neither coding are sent
+// unchanged. Every response says "Vary: Accept-Encoding" so shared caches
+// keep the encodings apart.
+//
+// Compress buffers whole responses, so it must not wrap streaming
+// endpoints.
+func Compress(opts CompressOptions) gin.HandlerFunc {
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultMinSize
+	}
+	if opts.GzipLevel == 0 {
+		opts.GzipLevel = gzip.DefaultCompression
+	}
+
+	gzipPool := sync.Pool{New: func() any {
+		w, err := gzip.NewWriterLevel(nil, opts.GzipLevel)
+		if err != nil {
+			w = gzip.NewWriter(nil)
+		}
+		return w
+	}}
+	// A single encoder is safe for concurrent EncodeAll calls.
+	zstdEncoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		out := c.Writer
+		buf := &bufferedWriter{ResponseWriter: out, status: http.StatusOK}
+		c.Writer = buf
+		defer func() { c.Writer = out }()
+		c.Next()
+
+		body := buf.body.Bytes()
+		if buf.status != http.StatusOK || len(body) < opts.MinSize || out.Header().Get("Content-Encoding") != "" {
+			buf.flush()
+			return
+		}
+
+		var compressed bytes.Buffer
+		switch encoding {
+		case EncodingZstd:
+			compressed.Write(zstdEncoder.EncodeAll(body, make([]byte, 0, len(body)/2)))
+		case EncodingGzip:
+			gz := gzipPool.Get().(*gzip.Writer)
+			gz.Reset(&compressed)
+			_, err := gz.Write(body)
+			if err == nil {
+				err = gz.Close()
+			}
+			gzipPool.Put(gz)
+			if err != nil {
+				buf.flush()
+				return
+			}
+		}
+
+		out.Header().Set("Content-Encoding", encoding)
+		out.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+		out.WriteHeader(buf.status)
+		out.Write(compressed.Bytes())
+	}
+}
+
+// negotiateEncoding picks the coding for an Accept-Encoding header, or ""
+// if the client accepts neither zstd nor gzip.
+func negotiateEncoding(header string) string {
+	q := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					weight = f
+				}
+			}
+		}
+		q[name] = weight
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range []string{EncodingZstd, EncodingGzip} {
+		w, ok := q[enc]
+		if !ok {
+			w, ok = q["*"]
+		}
+		if ok && w > bestQ {
+			best, bestQ = enc, w
+		}
+	}
+	return best
+}
+
+// bufferedWriter holds a handler's response until Compress decides how to
+// send it.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() { w.written = true }
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int { return w.status }
+
+func (w *bufferedWriter) Written() bool { return w.written }
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Flush is a no-op: the response is sent when the handler returns.
+func (w *bufferedWriter) Flush() {}
+
+// flush sends the buffered response unchanged.
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	switch {
+	case w.body.Len() > 0:
+		w.ResponseWriter.Write(w.body.Bytes())
+	case w.written:
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package httpcache provides gin middleware that cuts the bandwidth of
+// read-only endpoints: conditional requests with ETag/If-None-Match, and
+// gzip/zstd response compression.
+//
+// Clients that poll the same queries (e.g. IDE plugins) send back the ETag
+// of their last response and get an empty 304 until the data the query
+// reads changes. Responses that must be sent are compressed when the client
+// accepts it.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxHashedBody is the largest request body ETag hashes. Requests with
+// larger bodies are served without an ETag.
+const MaxHashedBody = 1 << 20
+
+// VersionFunc returns the version of the data a request reads.
+//
+// body is the request body, already read; the request body remains
+// readable by the handler. ok is false when the request cannot be cached,
+// for example because it names no known graph.
+type VersionFunc func(c *gin.Context, body []byte) (version string, ok bool)
+
+// ETag returns gin middleware that answers conditional requests.
+//
+// # Description
+//
+// The ETag of a response is derived from the version returned by version,
+// the method, the URL with its query string and the request body, so POST
+// queries are cached per body. If the request's If-None-Match header holds
+// the current ETag, the request is answered with 304 Not Modified and the
+// handler does not run. Otherwise the handler runs and a 200 response
+// carries the ETag and "Cache-Control: no-cache", telling clients to
+// revalidate before reusing it. Other statuses are sent without an ETag.
+//
+// The ETag is weak because the same content may be sent with different
+// encodings (see Compress).
+//
+// # Responses
+//
+//   - 304 Not Modified: The client's copy is current.
+func ETag(version VersionFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, ok := readBody(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		v, ok := version(c, body)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		tag := computeETag(v, c.Request, body)
+		if matchesETag(c.GetHeader("If-None-Match"), tag) {
+			c.Header("ETag", tag)
+			c.Header("Cache-Control", "no-cache")
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		c.Writer = &etagWriter{ResponseWriter: c.Writer, tag: tag}
+		c.Next()
+	}
+}
+
+// readBody reads the request body and puts it back for the handler. ok is
+// false if the body is too large to hash or cannot be read.
+func readBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, true
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, MaxHashedBody+1))
+	rest := c.Request.Body
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rest), rest}
+	if err != nil || len(body) > MaxHashedBody {
+		return nil, false
+	}
+	return body, true
+}
+
+// computeETag returns the weak ETag for a request at data version v.
+func computeETag(v string, r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, v)
+	io.WriteString(h, "\n"+r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// matchesETag reports whether an If-None-Match header value matches tag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+func matchesETag(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter adds the ETag to 200 responses.
+type etagWriter struct {
+	gin.ResponseWriter
+	tag string
+}
+
+// WriteHeader sets the caching headers when code is 200.
+func (w *etagWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.Header().Set("ETag", w.tag)
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Del("ETag")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow sets the caching headers for an implicit 200.
+func (w *etagWriter) WriteHeaderNow() {
+	if !w.Written() {
+		w.WriteHeader(w.Status())
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write sets the caching headers for an implicit 200 before the body.
+func (w *etagWriter) Write(data []byte) (int, error) {
+	if !w.Written() {
+		w.WriteHeader(w.Status())
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString sets the caching headers for an implicit 200 before the body.
+func (w *etagWriter) WriteString(s string) (int, error) {
+	if !w.Written() {
+		w.WriteHeader(w.Status())
+	}
+	return w.ResponseWriter.WriteString(s)
+}
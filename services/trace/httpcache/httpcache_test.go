@@ -0,0 +1,197 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package httpcache
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newRouter(version *string, big string) *gin.Engine {
+	r := gin.New()
+	mw := []gin.HandlerFunc{
+		Compress(CompressOptions{}),
+		ETag(func(c *gin.Context, body []byte) (string, bool) {
+			return *version, *version != ""
+		}),
+	}
+	r.GET("/big", append(mw, func(c *gin.Context) { c.String(http.StatusOK, big) })...)
+	r.GET("/small", append(mw, func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })...)
+	r.GET("/missing", append(mw, func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "x"}) })...)
+	r.POST("/query", append(mw, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})...)
+	return r
+}
+
+func do(r http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"identity":                "",
+		"gzip":                    EncodingGzip,
+		"gzip, deflate, br, zstd": EncodingZstd,
+		"zstd;q=0.5, gzip":        EncodingGzip,
+		"zstd;q=0, gzip;q=0":      "",
+		"*":                       EncodingZstd,
+		"*;q=0.1, gzip;q=0.9":     EncodingGzip,
+		"GZIP ; q=0.8":            EncodingGzip,
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	version := "g1"
+	big := strings.Repeat("callers of handleRequest ", 200)
+	r := newRouter(&version, big)
+
+	t.Run("gzip", func(t *testing.T) {
+		w := do(r, http.MethodGet, "/big", "", "Accept-Encoding", "gzip")
+		if w.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Fatalf("Content-Encoding = %q", w.Header().Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(zr)
+		if string(got) != big {
+			t.Error("gzip body does not round-trip")
+		}
+	})
+
+	t.Run("zstd", func(t *testing.T) {
+		w := do(r, http.MethodGet, "/big", "", "Accept-Encoding", "gzip, zstd")
+		if w.Header().Get("Content-Encoding") != EncodingZstd {
+			t.Fatalf("Content-Encoding = %q", w.Header().Get("Content-Encoding"))
+		}
+		if w.Body.Len() >= len(big) {
+			t.Errorf("compressed size %d >= %d", w.Body.Len(), len(big))
+		}
+		dec, _ := zstd.NewReader(nil)
+		defer dec.Close()
+		got, err := dec.DecodeAll(w.Body.Bytes(), nil)
+		if err != nil || string(got) != big {
+			t.Errorf("zstd body does not round-trip: %v", err)
+		}
+	})
+
+	t.Run("uncompressed", func(t *testing.T) {
+		for _, tc := range []struct{ path, accept string }{
+			{"/big", ""},
+			{"/small", "gzip"},
+			{"/missing", "gzip"},
+		} {
+			w := do(r, http.MethodGet, tc.path, "", "Accept-Encoding", tc.accept)
+			if enc := w.Header().Get("Content-Encoding"); enc != "" {
+				t.Errorf("%s with %q: Content-Encoding = %q", tc.path, tc.accept, enc)
+			}
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("%s: missing Vary header", tc.path)
+			}
+		}
+		if w := do(r, http.MethodGet, "/missing", "", "Accept-Encoding", "gzip"); w.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", w.Code)
+		}
+	})
+}
+
+func TestETag(t *testing.T) {
+	version := "g1"
+	r := newRouter(&version, strings.Repeat("x", 2000))
+
+	first := do(r, http.MethodGet, "/big?limit=10", "", "Accept-Encoding", "gzip")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(tag, `W/"`) {
+		t.Fatalf("status = %d, ETag = %q", first.Code, tag)
+	}
+
+	t.Run("not modified", func(t *testing.T) {
+		w := do(r, http.MethodGet, "/big?limit=10", "", "If-None-Match", tag, "Accept-Encoding", "gzip")
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != tag {
+			t.Errorf("status = %d, body %d bytes, ETag %q", w.Code, w.Body.Len(), w.Header().Get("ETag"))
+		}
+		if w := do(r, http.MethodGet, "/big?limit=10", "", "If-None-Match", `"other", `+tag); w.Code != http.StatusNotModified {
+			t.Errorf("list match: status = %d", w.Code)
+		}
+	})
+
+	t.Run("query changes tag", func(t *testing.T) {
+		w := do(r, http.MethodGet, "/big?limit=20", "", "If-None-Match", tag)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == tag {
+			t.Errorf("status = %d, ETag = %q", w.Code, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("version changes tag", func(t *testing.T) {
+		version = "g2"
+		defer func() { version = "g1" }()
+		w := do(r, http.MethodGet, "/big?limit=10", "", "If-None-Match", tag)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == tag {
+			t.Errorf("status = %d, ETag = %q", w.Code, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("post body", func(t *testing.T) {
+		a := do(r, http.MethodPost, "/query", `{"graph_id":"x","q":1}`)
+		if a.Body.String() != `{"graph_id":"x","q":1}` {
+			t.Fatalf("handler saw body %q", a.Body.String())
+		}
+		b := do(r, http.MethodPost, "/query", `{"graph_id":"x","q":2}`)
+		if a.Header().Get("ETag") == b.Header().Get("ETag") {
+			t.Error("different bodies share an ETag")
+		}
+		w := do(r, http.MethodPost, "/query", `{"graph_id":"x","q":1}`, "If-None-Match", a.Header().Get("ETag"))
+		if w.Code != http.StatusNotModified {
+			t.Errorf("status = %d, want 304", w.Code)
+		}
+	})
+
+	t.Run("errors carry no tag", func(t *testing.T) {
+		if w := do(r, http.MethodGet, "/missing", ""); w.Header().Get("ETag") != "" {
+			t.Errorf("404 has ETag %q", w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("unversioned requests are not cached", func(t *testing.T) {
+		version = ""
+		defer func() { version = "g1" }()
+		w := do(r, http.MethodGet, "/big?limit=10", "", "If-None-Match", tag)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") != "" {
+			t.Errorf("status = %d, ETag = %q", w.Code, w.Header().Get("ETag"))
+		}
+	})
+}
@@ -0,0 +1,425 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package idempotency provides gin middleware that makes retries of
+// mutating requests safe.
+//
+// A client that sends an Idempotency-Key header with a POST can repeat the
+// request after a network error without starting the operation twice: the
+// first request runs the handler and its response is kept for a short
+// time; repeats with the same key get that response back instead of
+// running the handler again. A repeat that arrives while the first request
+// is still running waits for it to finish.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// Request and response headers.
+const (
+	// HeaderKey is the request header holding the client's key.
+	HeaderKey = "Idempotency-Key"
+
+	// HeaderReplayed is set to "true" on responses served from the store.
+	HeaderReplayed = "Idempotent-Replayed"
+)
+
+// Limits on keys and the requests and responses they cover.
+const (
+	// MaxKeyLength is the longest key accepted.
+	MaxKeyLength = 255
+
+	// MaxRequestBody is the largest request body a key can cover.
+	MaxRequestBody = 1 << 20
+
+	// MaxResponseBody is the largest response kept for replay. Larger
+	// responses are sent but not kept, so a repeat runs the handler again.
+	MaxResponseBody = 4 << 20
+)
+
+var (
+	// ErrInvalidKey is returned for an empty, over-long or non-ASCII key.
+	ErrInvalidKey = apperr.New("IDEMPOTENCY_KEY_INVALID", apperr.CategoryInvalidInput,
+		"invalid Idempotency-Key").
+		WithRemediation("Send a printable ASCII key of at most 255 characters, such as a UUID")
+
+	// ErrKeyReused is returned when a key is sent again with a different
+	// request body.
+	ErrKeyReused = apperr.New("IDEMPOTENCY_KEY_REUSED", apperr.CategoryInvalidInput,
+		"Idempotency-Key was already used for a different request").
+		WithRemediation("Use a new key for each distinct request")
+
+	// ErrInProgress is returned when the request holding a key is still
+	// running after the wait limit.
+	ErrInProgress = apperr.New("IDEMPOTENCY_KEY_IN_PROGRESS", apperr.CategoryConflict,
+		"a request with this Idempotency-Key is still in progress").
+		WithRemediation("Retry with the same key once the original request completes")
+
+	// ErrRequestTooLarge is returned for keyed requests whose body exceeds
+	// MaxRequestBody.
+	ErrRequestTooLarge = apperr.New("IDEMPOTENCY_REQUEST_TOO_LARGE", apperr.CategoryInvalidInput,
+		"request body too large for an Idempotency-Key").
+		WithRemediation("Send the request without an Idempotency-Key")
+
+	// ErrStoreFull is returned when every slot holds a request still in
+	// progress.
+	ErrStoreFull = apperr.New("IDEMPOTENCY_STORE_FULL", apperr.CategoryUnavailable,
+		"too many requests with an Idempotency-Key in progress")
+)
+
+// Config configures a Store.
+type Config struct {
+	// TTL is how long a completed response is kept. Default: 10 minutes.
+	TTL time.Duration
+
+	// MaxEntries bounds the number of keys held. When full, the completed
+	// response closest to expiry is dropped. Default: 10000.
+	MaxEntries int
+
+	// MaxWait bounds how long a repeat waits for the request holding its
+	// key before failing with ErrInProgress. Default: 30 seconds.
+	MaxWait time.Duration
+}
+
+// DefaultConfig returns the default store configuration.
+func DefaultConfig() Config {
+	return Config{
+		TTL:        10 * time.Minute,
+		MaxEntries: 10000,
+		MaxWait:    30 * time.Second,
+	}
+}
+
+// Response is a response kept for replay.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// entry is the state of one key. done is closed when the request holding
+// the key completes or releases it; resp is nil until it completes.
+type entry struct {
+	fingerprint string
+	done        chan struct{}
+	resp        *Response
+	expires     time.Time
+}
+
+// Store holds the keys of in-flight and recently completed requests.
+//
+// Thread Safety: Store is safe for concurrent use.
+type Store struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// NewStore creates an in-memory store. Zero fields of cfg take their
+// defaults.
+func NewStore(cfg Config) *Store {
+	def := DefaultConfig()
+	if cfg.TTL <= 0 {
+		cfg.TTL = def.TTL
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = def.MaxEntries
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = def.MaxWait
+	}
+	return &Store{cfg: cfg, now: time.Now, entries: make(map[string]*entry)}
+}
+
+// Len returns the number of keys held, including expired keys not yet
+// removed.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// begin claims key for a request with the given fingerprint.
+//
+// If the key holds a completed response for the same fingerprint, that
+// response is returned. If another request holds the key, begin waits for
+// it to finish. Otherwise the caller becomes the owner (owner is true) and
+// must call complete or release.
+func (s *Store) begin(ctx context.Context, key, fingerprint string) (resp *Response, owner bool, err error) {
+	timer := time.NewTimer(s.cfg.MaxWait)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		e, ok := s.entries[key]
+		if ok && e.resp != nil && !s.now().Before(e.expires) {
+			delete(s.entries, key)
+			ok = false
+		}
+		if !ok {
+			if err := s.makeRoomLocked(); err != nil {
+				s.mu.Unlock()
+				return nil, false, err
+			}
+			s.entries[key] = &entry{fingerprint: fingerprint, done: make(chan struct{})}
+			s.mu.Unlock()
+			return nil, true, nil
+		}
+		if e.fingerprint != fingerprint {
+			s.mu.Unlock()
+			return nil, false, ErrKeyReused
+		}
+		if e.resp != nil {
+			s.mu.Unlock()
+			return e.resp, false, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-e.done:
+			// Completed or released; look again.
+		case <-timer.C:
+			return nil, false, ErrInProgress
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+// makeRoomLocked frees a slot when the store is full: expired responses
+// are removed first, then the completed response closest to expiry.
+func (s *Store) makeRoomLocked() error {
+	if len(s.entries) < s.cfg.MaxEntries {
+		return nil
+	}
+	now := s.now()
+	var oldestKey string
+	var oldest *entry
+	for k, e := range s.entries {
+		if e.resp == nil {
+			continue
+		}
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+			continue
+		}
+		if oldest == nil || e.expires.Before(oldest.expires) {
+			oldestKey, oldest = k, e
+		}
+	}
+	if len(s.entries) < s.cfg.MaxEntries {
+		return nil
+	}
+	if oldest == nil {
+		return ErrStoreFull
+	}
+	delete(s.entries, oldestKey)
+	return nil
+}
+
+// complete stores the response for key and wakes waiting repeats.
+func (s *Store) complete(key string, resp *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		e.resp = resp
+		e.expires = s.now().Add(s.cfg.TTL)
+		close(e.done)
+	}
+}
+
+// release forgets key without a response, so the next request with it
+// runs the handler. Waiting repeats are woken and one of them takes over.
+func (s *Store) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+		close(e.done)
+	}
+}
+
+// Middleware returns gin middleware that deduplicates keyed requests.
+//
+// # Description
+//
+// Requests without an Idempotency-Key header pass through unchanged. For
+// keyed requests, the key is scoped to the authenticated principal (see
+// rbac.PrincipalFrom) and the request path, and bound to a hash of the request
+// body. The first request runs the handler. A 2xx or 4xx response is kept
+// for Config.TTL and replayed, with "Idempotent-Replayed: true", to
+// repeats with the same key and body. 5xx responses, panics and responses
+// larger than MaxResponseBody are not kept, so a retry runs the handler
+// again.
+//
+// # Responses
+//
+//   - 400 Bad Request: ErrInvalidKey, ErrKeyReused or ErrRequestTooLarge.
+//   - 409 Conflict: ErrInProgress.
+//   - 503 Service Unavailable: ErrStoreFull.
+func Middleware(s *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validKey(key) {
+			abort(c, ErrInvalidKey)
+			return
+		}
+		body, err := readBody(c)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		scoped := scopeKey(c, key)
+		resp, owner, err := s.begin(c.Request.Context(), scoped, fingerprint(body))
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if !owner {
+			replay(c, resp)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		completed := false
+		defer func() {
+			if !completed {
+				s.release(scoped)
+			}
+		}()
+
+		c.Next()
+
+		status := w.Status()
+		if status >= http.StatusInternalServerError || w.overflow {
+			return
+		}
+		s.complete(scoped, &Response{Status: status, Header: w.Header().Clone(), Body: w.body.Bytes()})
+		completed = true
+	}
+}
+
+// validKey reports whether key is printable ASCII of acceptable length.
+func validKey(key string) bool {
+	if len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeKey qualifies key with the principal and request path so that
+// different callers, endpoints, and path parameters (e.g. the session of
+// /agent/:id/undo) cannot collide.
+func scopeKey(c *gin.Context, key string) string {
+	var principal string
+	if p, ok := rbac.PrincipalFrom(c); ok {
+		principal = p.Name
+	}
+	return principal + "\x00" + c.Request.Method + " " + c.Request.URL.Path + "\x00" + key
+}
+
+// fingerprint hashes a request body.
+func fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// readBody reads the request body and puts it back for the handler.
+func readBody(c *gin.Context) ([]byte, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, MaxRequestBody+1))
+	if err != nil {
+		return nil, apperr.ErrInvalidRequest.Withf("reading request body: %w", err)
+	}
+	if len(body) > MaxRequestBody {
+		return nil, ErrRequestTooLarge
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// abort ends the request with the error's status and JSON body.
+func abort(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(apperr.HTTPStatus(err), apperr.ToResponse(err))
+}
+
+// replay writes a stored response. Headers already set for this request,
+// such as a request ID, are kept.
+func replay(c *gin.Context, resp *Response) {
+	h := c.Writer.Header()
+	for k, v := range resp.Header {
+		if _, ok := h[k]; !ok {
+			h[k] = append([]string(nil), v...)
+		}
+	}
+	h.Set(HeaderReplayed, "true")
+	c.Writer.WriteHeader(resp.Status)
+	c.Writer.Write(resp.Body)
+	c.Abort()
+}
+
+// recorder copies the response body as it is written, up to
+// MaxResponseBody.
+type recorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+// Write copies data before passing it on.
+func (w *recorder) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString copies s before passing it on.
+func (w *recorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recorder) record(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > MaxResponseBody {
+		w.overflow = true
+		w.body = bytes.Buffer{}
+		return
+	}
+	w.body.Write(data)
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package idempotency
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newRouter serves POST /run, which counts its calls and answers with the
+// status in the "status" query parameter (default 200). Calls block until
+// gate is closed, if gate is non-nil.
+func newRouter(s *Store, calls *atomic.Int64, gate chan struct{}) *gin.Engine {
+	r := gin.New()
+	r.POST("/run", Middleware(s), func(c *gin.Context) {
+		n := calls.Add(1)
+		if gate != nil {
+			<-gate
+		}
+		status := http.StatusOK
+		if c.Query("status") == "500" {
+			status = http.StatusInternalServerError
+		}
+		var body map[string]any
+		c.ShouldBindJSON(&body)
+		c.JSON(status, gin.H{"call": n, "echo": body})
+	})
+	return r
+}
+
+func do(r http.Handler, key, body, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/run"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body.String(), err)
+	}
+	return resp.Code
+}
+
+func TestMiddleware_Replay(t *testing.T) {
+	var calls atomic.Int64
+	r := newRouter(NewStore(Config{}), &calls, nil)
+
+	first := do(r, "k1", `{"project":"a"}`, "")
+	second := do(r, "k1", `{"project":"a"}`, "")
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(HeaderReplayed) != "true" || first.Header().Get(HeaderReplayed) != "" {
+		t.Error("only the replay should carry Idempotent-Replayed")
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Error("replay lost the Content-Type header")
+	}
+
+	// No key, or a different key, runs the handler.
+	do(r, "", `{"project":"a"}`, "")
+	do(r, "k2", `{"project":"a"}`, "")
+	if calls.Load() != 3 {
+		t.Errorf("handler ran %d times, want 3", calls.Load())
+	}
+}
+
+func TestMiddleware_Errors(t *testing.T) {
+	var calls atomic.Int64
+	r := newRouter(NewStore(Config{}), &calls, nil)
+
+	do(r, "k1", `{"project":"a"}`, "")
+	w := do(r, "k1", `{"project":"b"}`, "")
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("reused key = %d %s", w.Code, w.Body)
+	}
+
+	w = do(r, strings.Repeat("x", MaxKeyLength+1), `{}`, "")
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != "IDEMPOTENCY_KEY_INVALID" {
+		t.Errorf("long key = %d %s", w.Code, w.Body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	var calls atomic.Int64
+	r := newRouter(NewStore(Config{}), &calls, nil)
+
+	if w := do(r, "k1", `{}`, "?status=500"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d", w.Code)
+	}
+	w := do(r, "k1", `{}`, "?status=500")
+	if calls.Load() != 2 || w.Header().Get(HeaderReplayed) != "" {
+		t.Error("a 5xx response should not be replayed")
+	}
+}
+
+func TestMiddleware_ConcurrentRepeatWaits(t *testing.T) {
+	var calls atomic.Int64
+	gate := make(chan struct{})
+	r := newRouter(NewStore(Config{}), &calls, gate)
+
+	results := make([]*httptest.ResponseRecorder, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = do(r, "k1", `{}`, "")
+		}(i)
+	}
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(gate)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	for _, w := range results {
+		if w.Code != http.StatusOK || w.Body.String() != results[0].Body.String() {
+			t.Errorf("response = %d %s", w.Code, w.Body)
+		}
+	}
+}
+
+func TestMiddleware_InProgressTimeout(t *testing.T) {
+	var calls atomic.Int64
+	gate := make(chan struct{})
+	defer close(gate)
+	r := newRouter(NewStore(Config{MaxWait: 20 * time.Millisecond}), &calls, gate)
+
+	go do(r, "k1", `{}`, "")
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	w := do(r, "k1", `{}`, "")
+	if w.Code != http.StatusConflict || errorCode(t, w) != "IDEMPOTENCY_KEY_IN_PROGRESS" {
+		t.Errorf("in-progress repeat = %d %s", w.Code, w.Body)
+	}
+}
+
+func TestStore_ExpiryAndEviction(t *testing.T) {
+	now := time.Now()
+	s := NewStore(Config{TTL: time.Minute, MaxEntries: 2})
+	s.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	for _, key := range []string{"a", "b"} {
+		if _, owner, err := s.begin(ctx, key, "fp"); !owner || err != nil {
+			t.Fatalf("begin(%s) = %v, %v", key, owner, err)
+		}
+	}
+	// Both in flight: no room.
+	if _, _, err := s.begin(ctx, "c", "fp"); err != ErrStoreFull {
+		t.Fatalf("begin on full store = %v, want ErrStoreFull", err)
+	}
+
+	s.complete("a", &Response{Status: http.StatusOK})
+	now = now.Add(time.Second)
+	s.complete("b", &Response{Status: http.StatusOK})
+
+	// The completed response closest to expiry ("a") makes room.
+	if _, owner, _ := s.begin(ctx, "c", "fp"); !owner {
+		t.Fatal("expected room after eviction")
+	}
+	if resp, _, _ := s.begin(ctx, "b", "fp"); resp == nil {
+		t.Error("b should still be stored")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if resp, owner, _ := s.begin(ctx, "b", "fp"); resp != nil || !owner {
+		t.Error("expired response should not be replayed")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxErrorBody bounds how much of a failed response is kept as the job error.
+const maxErrorBody = 1024
+
+// FromGin adapts a JSON POST endpoint into a job Handler.
+//
+// # Description
+//
+// The job params are sent as the request body and the job context becomes
+// the request context, so the endpoint runs exactly as it would for a
+// synchronous call. A 2xx JSON response becomes the job result; any other
+// status fails the job with the response body as the error.
+//
+// # Outputs
+//
+//   - Handler: Runs h once per job.
+func FromGin(h gin.HandlerFunc) Handler {
+	return func(ctx context.Context, params json.RawMessage) (any, error) {
+		if len(params) == 0 {
+			params = json.RawMessage("{}")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", bytes.NewReader(params))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = req
+		h(c)
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		body := rec.Body.Bytes()
+		if rec.Code < 200 || rec.Code >= 300 {
+			if len(body) > maxErrorBody {
+				body = body[:maxErrorBody]
+			}
+			return nil, fmt.Errorf("status %d: %s", rec.Code, bytes.TrimSpace(body))
+		}
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("status %d: response is not JSON", rec.Code)
+		}
+		return json.RawMessage(body), nil
+	}
+}
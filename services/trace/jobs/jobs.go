@@ -0,0 +1,154 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package jobs runs expensive operations asynchronously.
+//
+// # Description
+//
+// HTTP clients submit a job of a registered kind and poll its status instead
+// of holding a connection open for minutes while a huge repository is
+// analyzed. A Queue runs jobs on a fixed pool of workers, tracks progress
+// reported by the running Handler, and stores each job (including its
+// result) in a Store. With a FileStore the queue survives restarts: jobs
+// that were queued or running when the process stopped run again on Open.
+//
+// Finished jobs are kept for Options.Retention so their results can be
+// fetched, then removed.
+//
+// # Thread Safety
+//
+// Queue and the Store implementations are safe for concurrent use.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound indicates no job has the requested ID.
+	ErrNotFound = errors.New("job not found")
+
+	// ErrUnknownKind indicates a submission for a kind with no handler.
+	ErrUnknownKind = errors.New("unknown job kind")
+
+	// ErrNotFinished indicates a result was requested before the job ended.
+	ErrNotFinished = errors.New("job not finished")
+
+	// ErrFinished indicates a cancel request for a job that already ended.
+	ErrFinished = errors.New("job already finished")
+
+	// ErrQueueFull indicates the queue is at Options.MaxQueued.
+	ErrQueueFull = errors.New("job queue full")
+
+	// ErrClosed indicates the queue has been closed.
+	ErrClosed = errors.New("job queue closed")
+)
+
+// Status is a job's lifecycle state.
+type Status string
+
+const (
+	// StatusQueued means the job is waiting for a worker.
+	StatusQueued Status = "queued"
+
+	// StatusRunning means a worker is executing the job.
+	StatusRunning Status = "running"
+
+	// StatusSucceeded means the job finished and has a result.
+	StatusSucceeded Status = "succeeded"
+
+	// StatusFailed means the job finished with an error.
+	StatusFailed Status = "failed"
+
+	// StatusCanceled means the job was canceled before it finished.
+	StatusCanceled Status = "canceled"
+)
+
+// Finished reports whether the status is terminal.
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Progress is the latest progress a handler reported.
+type Progress struct {
+	// Done and Total count units of work; Total is 0 when unknown.
+	Done  int64 `json:"done"`
+	Total int64 `json:"total,omitempty"`
+
+	// Message describes the current step.
+	Message string `json:"message,omitempty"`
+}
+
+// Fraction returns Done/Total, or 0 when Total is unknown.
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// Job is one submitted operation.
+type Job struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Status   Status          `json:"status"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Progress Progress        `json:"progress"`
+
+	// Result is the handler's output, set when Status is succeeded.
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Error is set when Status is failed or canceled.
+	Error string `json:"error,omitempty"`
+
+	// Attempts counts starts, including restarts after a crash.
+	Attempts int `json:"attempts"`
+
+	// Submitter identifies who submitted the job (e.g. an API key name).
+	Submitter string `json:"submitter,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// clone returns a copy safe to hand to callers.
+func (j *Job) clone() *Job {
+	c := *j
+	return &c
+}
+
+// Handler executes one kind of job.
+//
+// # Inputs
+//
+//   - ctx: Canceled when the job is canceled or the queue closes. Use
+//     ReportProgress(ctx, ...) to publish progress.
+//   - params: The submitted parameters.
+//
+// # Outputs
+//
+//   - any: The result, encoded as JSON. A json.RawMessage is stored as is.
+//   - error: Non-nil marks the job failed.
+type Handler func(ctx context.Context, params json.RawMessage) (any, error)
+
+// progressKey is the context key for the running job's progress reporter.
+type progressKey struct{}
+
+// ReportProgress publishes progress for the job running under ctx. It is a
+// no-op outside a job.
+func ReportProgress(ctx context.Context, done, total int64, message string) {
+	if report, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		report(Progress{Done: done, Total: total, Message: message})
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// waitStatus polls until the job reaches want or the test times out.
+func waitStatus(t *testing.T, q *Queue, id string, want Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		j, err := q.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", id, err)
+		}
+		if j.Status == want {
+			return j
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s status = %s, want %s", id, j.Status, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueue_Lifecycle(t *testing.T) {
+	q, err := Open(NewMemoryStore(), Options{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	release := make(chan struct{})
+	q.Register("sum", func(ctx context.Context, params json.RawMessage) (any, error) {
+		var in struct{ A, B int }
+		if err := json.Unmarshal(params, &in); err != nil {
+			return nil, err
+		}
+		ReportProgress(ctx, 1, 2, "adding")
+		<-release
+		return map[string]int{"sum": in.A + in.B}, nil
+	})
+	q.Register("fail", func(context.Context, json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+	q.Start()
+
+	if _, err := q.Submit("missing", nil, ""); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("Submit unknown kind: err = %v, want ErrUnknownKind", err)
+	}
+
+	job, err := q.Submit("sum", json.RawMessage(`{"A":2,"B":3}`), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := waitStatus(t, q, job.ID, StatusRunning)
+	for running.Progress.Message == "" {
+		time.Sleep(5 * time.Millisecond)
+		running, _ = q.Get(job.ID)
+	}
+	if running.Progress.Fraction() != 0.5 || running.Attempts != 1 {
+		t.Errorf("running job = %+v, want half done on attempt 1", running)
+	}
+	close(release)
+
+	done := waitStatus(t, q, job.ID, StatusSucceeded)
+	if string(done.Result) != `{"sum":5}` || done.FinishedAt == nil || done.Submitter != "alice" {
+		t.Errorf("finished job = %+v", done)
+	}
+	if _, err := q.Cancel(job.ID); !errors.Is(err, ErrFinished) {
+		t.Errorf("Cancel finished job: err = %v, want ErrFinished", err)
+	}
+
+	failed, _ := q.Submit("fail", nil, "")
+	if j := waitStatus(t, q, failed.ID, StatusFailed); j.Error != "boom" {
+		t.Errorf("failed job error = %q, want boom", j.Error)
+	}
+
+	if got := q.List(); len(got) != 2 || got[0].ID != failed.ID {
+		t.Errorf("List = %d jobs, want newest first", len(got))
+	}
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	q, err := Open(NewMemoryStore(), Options{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	stopped := make(chan error, 1)
+	q.Register("block", func(ctx context.Context, _ json.RawMessage) (any, error) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	q.Start()
+
+	first, _ := q.Submit("block", nil, "")
+	second, _ := q.Submit("block", nil, "")
+	waitStatus(t, q, first.ID, StatusRunning)
+
+	// Canceling a queued job means it never runs.
+	if j, err := q.Cancel(second.ID); err != nil || j.Status != StatusCanceled {
+		t.Fatalf("Cancel queued = %+v, %v", j, err)
+	}
+	if _, err := q.Cancel(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-stopped; !errors.Is(err, context.Canceled) {
+		t.Errorf("handler ctx err = %v, want context.Canceled", err)
+	}
+
+	j := waitStatus(t, q, first.ID, StatusCanceled)
+	if j.Error != "canceled" {
+		t.Errorf("canceled job error = %q", j.Error)
+	}
+	if j, _ := q.Get(second.ID); j.Attempts != 0 {
+		t.Errorf("canceled queued job ran %d times", j.Attempts)
+	}
+	if _, err := q.Cancel("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Cancel unknown: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestQueue_ResumesAfterRestart(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	q1, _ := Open(store, Options{Workers: 1})
+	q1.Register("analyze", func(ctx context.Context, _ json.RawMessage) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	q1.Start()
+	interrupted, _ := q1.Submit("analyze", json.RawMessage(`{"repo":"big"}`), "")
+	queued, _ := q1.Submit("analyze", nil, "")
+	<-started
+	q1.Close()
+
+	q2, err := Open(store, Options{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q2.Close()
+	q2.Register("analyze", func(_ context.Context, params json.RawMessage) (any, error) {
+		return params, nil
+	})
+	q2.Start()
+
+	j := waitStatus(t, q2, interrupted.ID, StatusSucceeded)
+	if j.Attempts != 2 || string(j.Result) != `{"repo":"big"}` {
+		t.Errorf("resumed job = %+v, want attempt 2 with params echoed", j)
+	}
+	waitStatus(t, q2, queued.ID, StatusSucceeded)
+}
+
+func TestQueue_Retention(t *testing.T) {
+	store := NewMemoryStore()
+	q, _ := Open(store, Options{Workers: 1, Retention: time.Hour})
+	defer q.Close()
+	q.Register("noop", func(context.Context, json.RawMessage) (any, error) { return nil, nil })
+	q.Start()
+
+	job, _ := q.Submit("noop", nil, "")
+	waitStatus(t, q, job.ID, StatusSucceeded)
+
+	q.mu.Lock()
+	q.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	q.pruneLocked()
+	q.mu.Unlock()
+
+	if _, err := q.Get(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get expired job: err = %v, want ErrNotFound", err)
+	}
+	if left, _ := store.LoadAll(); len(left) != 0 {
+		t.Errorf("store still holds %d jobs", len(left))
+	}
+}
+
+func TestFromGin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := FromGin(func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil || req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"hello": req.Name})
+	})
+
+	out, err := h(context.Background(), json.RawMessage(`{"name":"graph"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out.(json.RawMessage)); got != `{"hello":"graph"}` {
+		t.Errorf("result = %s", got)
+	}
+
+	if _, err := h(context.Background(), nil); err == nil {
+		t.Error("expected 400 response to fail the job")
+	}
+}
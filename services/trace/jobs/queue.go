@@ -0,0 +1,446 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWorkers is the number of jobs run concurrently.
+	DefaultWorkers = 2
+
+	// DefaultRetention is how long finished jobs are kept.
+	DefaultRetention = 24 * time.Hour
+
+	// DefaultMaxQueued bounds the number of jobs waiting for a worker.
+	DefaultMaxQueued = 1000
+)
+
+// Options configures a Queue. Zero fields use the defaults.
+type Options struct {
+	// Workers is the number of jobs run concurrently.
+	Workers int
+
+	// Retention is how long finished jobs are kept before being removed.
+	Retention time.Duration
+
+	// MaxQueued bounds the number of queued jobs; Submit returns
+	// ErrQueueFull beyond it.
+	MaxQueued int
+
+	// Logger receives job lifecycle logs. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = DefaultWorkers
+	}
+	if o.Retention <= 0 {
+		o.Retention = DefaultRetention
+	}
+	if o.MaxQueued <= 0 {
+		o.MaxQueued = DefaultMaxQueued
+	}
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+	return o
+}
+
+// Queue runs submitted jobs on a pool of workers.
+//
+// Register handlers, then call Start. Progress reported by handlers is kept
+// in memory; every status change is written to the Store.
+type Queue struct {
+	store Store
+	opts  Options
+	now   func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	handlers map[string]Handler
+	jobs     map[string]*Job
+	pending  []string
+	running  map[string]context.CancelFunc
+	started  bool
+	closed   bool
+}
+
+// Open creates a queue backed by store and loads its jobs.
+//
+// # Description
+//
+// Jobs that were queued or running when the previous process stopped are
+// queued again in submission order. Expired finished jobs are removed.
+//
+// # Outputs
+//
+//   - *Queue: The queue. Workers do not run until Start is called.
+//   - error: Non-nil if the store could not be read.
+func Open(store Store, opts Options) (*Queue, error) {
+	loaded, err := store.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		store:    store,
+		opts:     opts.withDefaults(),
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+		handlers: make(map[string]Handler),
+		jobs:     make(map[string]*Job, len(loaded)),
+		running:  make(map[string]context.CancelFunc),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	slices.SortFunc(loaded, func(a, b *Job) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	for _, j := range loaded {
+		q.jobs[j.ID] = j
+		if j.Status.Finished() {
+			continue
+		}
+		if j.Status == StatusRunning {
+			q.opts.Logger.Info("Requeuing interrupted job", "job_id", j.ID, "kind", j.Kind)
+		}
+		j.Status = StatusQueued
+		j.StartedAt = nil
+		j.Progress = Progress{}
+		q.save(j)
+		q.pending = append(q.pending, j.ID)
+	}
+	q.pruneLocked()
+
+	return q, nil
+}
+
+// Register sets the handler for a job kind. Call before Start.
+func (q *Queue) Register(kind string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = h
+}
+
+// Kinds returns the registered job kinds, sorted.
+func (q *Queue) Kinds() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for k := range q.handlers {
+		kinds = append(kinds, k)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// Start launches the workers and the retention janitor. Calling it more
+// than once has no effect.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+
+	for range q.opts.Workers {
+		q.wg.Add(1)
+		go q.worker()
+	}
+
+	q.wg.Add(1)
+	go q.janitor()
+}
+
+// Close stops the workers and waits for them to exit.
+//
+// Running jobs are canceled and stored as queued, so a queue opened on the
+// same store runs them again.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.cancel()
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	return nil
+}
+
+// Submit queues a job.
+//
+// # Outputs
+//
+//   - *Job: A copy of the queued job.
+//   - error: ErrUnknownKind, ErrQueueFull, ErrClosed, or a store error.
+func (q *Queue) Submit(kind string, params json.RawMessage, submitter string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, ErrClosed
+	}
+	if _, ok := q.handlers[kind]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if len(q.pending) >= q.opts.MaxQueued {
+		return nil, ErrQueueFull
+	}
+
+	job := &Job{
+		ID:        newID(),
+		Kind:      kind,
+		Status:    StatusQueued,
+		Params:    params,
+		Submitter: submitter,
+		CreatedAt: q.now().UTC(),
+	}
+	if err := q.store.Save(job); err != nil {
+		return nil, err
+	}
+	q.jobs[job.ID] = job
+	q.pending = append(q.pending, job.ID)
+	q.cond.Signal()
+
+	return job.clone(), nil
+}
+
+// Get returns a copy of the job with the given ID.
+func (q *Queue) Get(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return j.clone(), nil
+}
+
+// List returns copies of all known jobs, newest first.
+func (q *Queue) List() []*Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]*Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		out = append(out, j.clone())
+	}
+	slices.SortFunc(out, func(a, b *Job) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return out
+}
+
+// Cancel cancels a queued or running job.
+//
+// # Outputs
+//
+//   - *Job: A copy of the canceled job.
+//   - error: ErrNotFound, or ErrFinished if the job already ended.
+func (q *Queue) Cancel(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if j.Status.Finished() {
+		return nil, ErrFinished
+	}
+
+	if cancel, ok := q.running[id]; ok {
+		cancel()
+	}
+	q.pending = slices.DeleteFunc(q.pending, func(p string) bool { return p == id })
+	q.finishLocked(j, StatusCanceled, nil, "canceled")
+
+	return j.clone(), nil
+}
+
+// worker runs jobs until the queue closes.
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		id := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		q.run(id)
+	}
+}
+
+// run executes one job.
+func (q *Queue) run(id string) {
+	q.mu.Lock()
+	j, ok := q.jobs[id]
+	if !ok || j.Status != StatusQueued {
+		q.mu.Unlock()
+		return
+	}
+	h, ok := q.handlers[j.Kind]
+	if !ok {
+		q.finishLocked(j, StatusFailed, nil, fmt.Sprintf("%v: %q", ErrUnknownKind, j.Kind))
+		q.mu.Unlock()
+		return
+	}
+
+	started := q.now().UTC()
+	j.Status = StatusRunning
+	j.StartedAt = &started
+	j.Attempts++
+	ctx, cancel := context.WithCancel(q.ctx)
+	q.running[id] = cancel
+	q.save(j)
+	params, kind, attempt := j.Params, j.Kind, j.Attempts
+	q.mu.Unlock()
+
+	q.opts.Logger.Info("Job started", "job_id", id, "kind", kind, "attempt", attempt)
+	ctx = context.WithValue(ctx, progressKey{}, func(p Progress) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if j.Status == StatusRunning {
+			j.Progress = p
+		}
+	})
+	result, err := runHandler(ctx, h, params)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cancel()
+	delete(q.running, id)
+
+	switch {
+	case j.Status != StatusRunning:
+		// Canceled while running; Cancel already recorded the outcome.
+	case q.closed && errors.Is(err, context.Canceled):
+		// Shutting down: leave the job to run again on the next Open.
+		j.Status = StatusQueued
+		j.StartedAt = nil
+		j.Progress = Progress{}
+		q.save(j)
+	case err != nil:
+		q.finishLocked(j, StatusFailed, nil, err.Error())
+	default:
+		q.finishLocked(j, StatusSucceeded, result, "")
+	}
+}
+
+// runHandler calls h and encodes its result, converting panics to errors.
+func runHandler(ctx context.Context, h Handler, params json.RawMessage) (result json.RawMessage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	out, err := h(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := out.(json.RawMessage); ok {
+		return raw, nil
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("encoding job result: %w", err)
+	}
+	return data, nil
+}
+
+// finishLocked records a terminal status. Caller must hold q.mu.
+func (q *Queue) finishLocked(j *Job, status Status, result json.RawMessage, errMsg string) {
+	finished := q.now().UTC()
+	j.Status = status
+	j.Result = result
+	j.Error = errMsg
+	j.FinishedAt = &finished
+	if status == StatusSucceeded && j.Progress.Total > 0 {
+		j.Progress.Done = j.Progress.Total
+	}
+	q.save(j)
+	q.opts.Logger.Info("Job finished", "job_id", j.ID, "kind", j.Kind, "status", status, "error", errMsg)
+}
+
+// save writes j to the store, logging failures. The in-memory state stays
+// authoritative for this process.
+func (q *Queue) save(j *Job) {
+	if err := q.store.Save(j); err != nil {
+		q.opts.Logger.Error("Failed to save job", "job_id", j.ID, "error", err)
+	}
+}
+
+// janitor periodically removes expired finished jobs.
+func (q *Queue) janitor() {
+	defer q.wg.Done()
+	interval := min(max(q.opts.Retention/10, time.Second), time.Hour)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			q.pruneLocked()
+			q.mu.Unlock()
+		}
+	}
+}
+
+// pruneLocked removes finished jobs older than the retention period.
+// Caller must hold q.mu.
+func (q *Queue) pruneLocked() {
+	cutoff := q.now().Add(-q.opts.Retention)
+	for id, j := range q.jobs {
+		if j.FinishedAt == nil || !j.Status.Finished() || j.FinishedAt.After(cutoff) {
+			continue
+		}
+		if err := q.store.Delete(id); err != nil {
+			q.opts.Logger.Error("Failed to delete expired job", "job_id", id, "error", err)
+			continue
+		}
+		delete(q.jobs, id)
+	}
+}
+
+// newID returns a random 128-bit hex job ID.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,262 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/jobs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// SubmitJobRequest is the request body for POST /v1/trace/jobs.
+type SubmitJobRequest struct {
+	// Kind is the operation to run, e.g. "init" or "patterns/dead_code".
+	Kind string `json:"kind" binding:"required"`
+
+	// Params is the request body the synchronous endpoint would take.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// JobListResponse is the response for GET /v1/trace/jobs.
+type JobListResponse struct {
+	Jobs  []*jobs.Job `json:"jobs"`
+	Kinds []string    `json:"kinds"`
+}
+
+// WithJobs sets the queue for asynchronous jobs.
+//
+// Description:
+//
+//	Registers a job kind for each long-running endpoint (graph init,
+//	library seeding, and the full-graph pattern analyses) and enables the
+//	/v1/trace/jobs endpoints. A kind is the endpoint path below
+//	/v1/codebuddy, and its params are that endpoint's request body.
+//	Without a queue the job endpoints respond 503. The caller starts and
+//	closes the queue.
+//
+// Inputs:
+//
+//	q - The job queue. May be nil.
+//
+// Outputs:
+//
+//	*Handlers - The handlers for method chaining
+func (h *Handlers) WithJobs(q *jobs.Queue) *Handlers {
+	h.jobs = q
+	if q == nil {
+		return h
+	}
+	for kind, handler := range map[string]gin.HandlerFunc{
+		"init":                   h.HandleInit,
+		"seed":                   h.HandleSeed,
+		"patterns/detect":        h.HandleDetectPatterns,
+		"patterns/code_smells":   h.HandleFindCodeSmells,
+		"patterns/duplication":   h.HandleFindDuplication,
+		"patterns/circular_deps": h.HandleFindCircularDeps,
+		"patterns/conventions":   h.HandleExtractConventions,
+		"patterns/dead_code":     h.HandleFindDeadCode,
+	} {
+		q.Register(kind, jobs.FromGin(handler))
+	}
+	return h
+}
+
+// HandleSubmitJob handles POST /v1/trace/jobs.
+//
+// Description:
+//
+//	Queues a long-running operation and returns immediately. Poll
+//	GET /v1/trace/jobs/:id for progress and fetch the output from
+//	GET /v1/trace/jobs/:id/result.
+//
+// Request Body:
+//
+//	SubmitJobRequest
+//
+// Response:
+//
+//	202 Accepted: jobs.Job
+//	400 Bad Request: Malformed body or unknown kind
+//	503 Service Unavailable: Jobs not configured or queue full
+func (h *Handlers) HandleSubmitJob(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	requestID := getOrCreateRequestID(c)
+	logger := slog.With("request_id", requestID, "handler", "HandleSubmitJob")
+
+	var req SubmitJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	var submitter string
+	if p, ok := rbac.PrincipalFrom(c); ok {
+		submitter = p.Name
+	}
+
+	job, err := h.jobs.Submit(req.Kind, req.Params, submitter)
+	switch {
+	case errors.Is(err, jobs.ErrUnknownKind):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   err.Error(),
+			Code:    "UNKNOWN_JOB_KIND",
+			Details: "GET /v1/trace/jobs lists the available kinds",
+		})
+		return
+	case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, jobs.ErrClosed):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: err.Error(),
+			Code:  "JOB_QUEUE_UNAVAILABLE",
+		})
+		return
+	case err != nil:
+		logger.Error("Failed to submit job", "kind", req.Kind, "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to submit job",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	logger.Info("Job submitted", "job_id", job.ID, "kind", job.Kind)
+	c.Header("Location", "/v1/trace/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// HandleListJobs handles GET /v1/trace/jobs.
+//
+// Response:
+//
+//	200 OK: JobListResponse with page (newest first, without results)
+//	503 Service Unavailable: Jobs not configured
+func (h *Handlers) HandleListJobs(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	list := h.jobs.List()
+	for _, j := range list {
+		j.Result = nil
+	}
+	writePage(c, JobListResponse{Jobs: list, Kinds: h.jobs.Kinds()}, "jobs", page, true)
+}
+
+// HandleGetJob handles GET /v1/trace/jobs/:id.
+//
+// Response:
+//
+//	200 OK: jobs.Job with status and progress, without the result
+//	404 Not Found: Unknown or expired job
+//	503 Service Unavailable: Jobs not configured
+func (h *Handlers) HandleGetJob(c *gin.Context) {
+	job, ok := h.lookupJob(c)
+	if !ok {
+		return
+	}
+	job.Result = nil
+	c.JSON(http.StatusOK, job)
+}
+
+// HandleGetJobResult handles GET /v1/trace/jobs/:id/result.
+//
+// Response:
+//
+//	200 OK: The endpoint response the job produced
+//	404 Not Found: Unknown or expired job
+//	409 Conflict: Job still queued or running
+//	422 Unprocessable Entity: Job failed or was canceled
+//	503 Service Unavailable: Jobs not configured
+func (h *Handlers) HandleGetJobResult(c *gin.Context) {
+	job, ok := h.lookupJob(c)
+	if !ok {
+		return
+	}
+	switch job.Status {
+	case jobs.StatusSucceeded:
+		c.Data(http.StatusOK, "application/json; charset=utf-8", job.Result)
+	case jobs.StatusFailed, jobs.StatusCanceled:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: job.Error,
+			Code:  "JOB_" + strings.ToUpper(string(job.Status)),
+		})
+	default:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   jobs.ErrNotFinished.Error(),
+			Code:    "JOB_NOT_FINISHED",
+			Details: "Job is " + string(job.Status),
+		})
+	}
+}
+
+// HandleCancelJob handles DELETE /v1/trace/jobs/:id.
+//
+// Response:
+//
+//	200 OK: jobs.Job (canceled)
+//	404 Not Found: Unknown or expired job
+//	409 Conflict: Job already finished
+//	503 Service Unavailable: Jobs not configured
+func (h *Handlers) HandleCancelJob(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	job, err := h.jobs.Cancel(c.Param("id"))
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "JOB_NOT_FOUND"})
+	case errors.Is(err, jobs.ErrFinished):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error(), Code: "JOB_FINISHED"})
+	default:
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// requireJobs writes 503 and returns false when no queue is configured.
+func (h *Handlers) requireJobs(c *gin.Context) bool {
+	if h.jobs != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "Jobs are not configured",
+		Code:    "JOBS_DISABLED",
+		Details: "Set TRACE_JOBS_DIR to a writable directory",
+	})
+	return false
+}
+
+// lookupJob fetches the job named by the :id parameter, writing the error
+// response if it cannot.
+func (h *Handlers) lookupJob(c *gin.Context) (*jobs.Job, bool) {
+	if !h.requireJobs(c) {
+		return nil, false
+	}
+	job, err := h.jobs.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "JOB_NOT_FOUND"})
+		return nil, false
+	}
+	return job, true
+}
@@ -19,6 +19,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
 )
 
 // =============================================================================
@@ -99,7 +101,7 @@ func (r *LintRunner) AutoFixWithLanguage(ctx context.Context, filePath, language
 
 	// Check if fix mode is supported
 	if len(config.FixArgs) == 0 {
-		return nil, fmt.Errorf("linter %s does not support auto-fix", config.Command)
+		return nil, fmt.Errorf("%w: %s", ErrAutoFixUnsupported, config.Command)
 	}
 
 	// Check availability
@@ -121,8 +123,26 @@ func (r *LintRunner) AutoFixWithLanguage(ctx context.Context, filePath, language
 		}
 	}
 
+	// Project linters fix the whole project; confine them to this file
+	if config.ProjectFile != "" {
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading file: %w", err)
+		}
+		fix, err := r.FixContentAt(ctx, content, language, absPath)
+		if err != nil {
+			return nil, err
+		}
+		if fix.Changed() {
+			if err := os.WriteFile(absPath, fix.Fixed, 0o644); err != nil {
+				return nil, fmt.Errorf("writing fixed file: %w", err)
+			}
+		}
+		return fix.Remaining, nil
+	}
+
 	// Execute linter with fix args
-	if _, err := r.executeLinterFix(ctx, config, absPath); err != nil {
+	if _, err := r.executeLinterFix(ctx, config, absPath, "", ""); err != nil {
 		return nil, err
 	}
 
@@ -173,7 +193,7 @@ func (r *LintRunner) AutoFixContent(ctx context.Context, content []byte, languag
 
 	// Check if fix mode is supported
 	if len(config.FixArgs) == 0 {
-		return nil, nil, fmt.Errorf("linter %s does not support auto-fix", config.Command)
+		return nil, nil, fmt.Errorf("%w: %s", ErrAutoFixUnsupported, config.Command)
 	}
 
 	// Check availability
@@ -187,6 +207,11 @@ func (r *LintRunner) AutoFixContent(ctx context.Context, content []byte, languag
 		}, nil
 	}
 
+	// Project linters need to know where the content lives
+	if config.ProjectFile != "" {
+		return nil, nil, fmt.Errorf("%w: %s needs a file path, use FixContentAt", ErrNoProject, config.Command)
+	}
+
 	// Get extension for temp file
 	ext := ExtensionForLanguage(language)
 	if ext == "" {
@@ -209,7 +234,7 @@ func (r *LintRunner) AutoFixContent(ctx context.Context, content []byte, languag
 	tmpFile.Close()
 
 	// Run linter with fix
-	if _, err := r.executeLinterFix(ctx, config, tmpPath); err != nil {
+	if _, err := r.executeLinterFix(ctx, config, tmpPath, "", ""); err != nil {
 		return nil, nil, err
 	}
 
@@ -228,12 +253,167 @@ func (r *LintRunner) AutoFixContent(ctx context.Context, content []byte, languag
 	return fixedContent, result, nil
 }
 
-// executeLinterFix runs the linter in fix mode.
-func (r *LintRunner) executeLinterFix(ctx context.Context, config *LinterConfig, filePath string) ([]byte, error) {
+// Fix applies the linter's auto-fixes to a copy of a file.
+//
+// Description:
+//
+//	Unlike AutoFix, the file is not modified: the linter runs in fix
+//	mode on a sandbox copy, and the result carries the fixed content
+//	and a unified diff against the file. An agent whose patch was
+//	blocked by fixable lint issues can fold the fixes into its patch
+//	and retry validation (see validate.PatchValidator.FixLint).
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Path to the file to fix
+//
+// Outputs:
+//
+//	*FixResult - Fixed content, diff, and remaining issues
+//	error - ErrAutoFixUnsupported if the linter has no fix mode,
+//	        or non-nil if the linter failed
+//
+// Example:
+//
+//	fix, err := runner.Fix(ctx, "path/to/file.py")
+//	if err == nil && fix.Changed() {
+//	    fmt.Print(fix.Diff)
+//	}
+//
+// Thread Safety: Safe for concurrent use.
+func (r *LintRunner) Fix(ctx context.Context, filePath string) (*FixResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("%w: ctx must not be nil", ErrInvalidInput)
+	}
+	if filePath == "" {
+		return nil, fmt.Errorf("%w: filePath must not be empty", ErrInvalidInput)
+	}
+
+	language := LanguageFromPath(filePath)
+	if language == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
+	}
+
+	absPath := filePath
+	if !filepath.IsAbs(filePath) && r.workingDir != "" {
+		absPath = filepath.Join(r.workingDir, filePath)
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	return r.FixContentAt(ctx, content, language, filePath)
+}
+
+// FixContentAt applies the linter's auto-fixes to content as if it were
+// at filePath.
+//
+// Description:
+//
+//	Writes content to a sandbox (a copy of the project for project
+//	linters, see LintContentAt), runs the linter in fix mode there and
+//	re-lints the result. Nothing outside the sandbox is modified. The
+//	diff and remaining issues are reported at filePath.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	content - The source code to fix
+//	language - The language identifier
+//	filePath - Path the content belongs at, used for the diff header
+//	           and, for project linters, to find the project
+//
+// Outputs:
+//
+//	*FixResult - Fixed content, diff, and remaining issues
+//	error - ErrAutoFixUnsupported if the linter has no fix mode,
+//	        or non-nil if the linter failed
+//
+// Thread Safety: Safe for concurrent use.
+func (r *LintRunner) FixContentAt(ctx context.Context, content []byte, language, filePath string) (*FixResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("%w: ctx must not be nil", ErrInvalidInput)
+	}
+
+	config := r.configs.Get(language)
+	if config == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
+	}
+	if len(config.FixArgs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAutoFixUnsupported, config.Command)
+	}
+
+	fix := &FixResult{
+		FilePath: filePath,
+		Language: language,
+		Fixed:    content,
+	}
+	if len(content) == 0 || !r.IsAvailable(language) {
+		fix.Remaining = &LintResult{
+			Valid:           true,
+			Errors:          make([]LintIssue, 0),
+			Warnings:        make([]LintIssue, 0),
+			Linter:          config.Command,
+			Language:        language,
+			FilePath:        filePath,
+			LinterAvailable: r.IsAvailable(language),
+		}
+		return fix, nil
+	}
+
+	absPath := filePath
+	if !filepath.IsAbs(filePath) {
+		if r.workingDir != "" {
+			absPath = filepath.Join(r.workingDir, filePath)
+		} else {
+			var err error
+			absPath, err = filepath.Abs(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("resolving path: %w", err)
+			}
+		}
+	}
+
+	sb, err := newSandbox(config, content, absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer sb.Close()
+
+	configFile := r.resolver.Resolve(config, absPath)
+	if _, err := r.executeLinterFix(ctx, config, sb.path, sb.projectDir, configFile); err != nil {
+		return nil, err
+	}
+	fixed, err := os.ReadFile(sb.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixed file: %w", err)
+	}
+
+	remaining, err := r.lintAs(ctx, sb.path, language, absPath)
+	if err != nil {
+		return nil, err
+	}
+	remapIssuePaths(remaining, sb.path, filePath)
+
+	fix.Fixed = fixed
+	fix.Diff = diff.UnifiedDiff(filePath, string(content), string(fixed))
+	fix.Remaining = remaining
+	return fix, nil
+}
+
+// executeLinterFix runs the linter in fix mode. If projectDir is set,
+// the linter runs there on the whole project instead of on filePath. A
+// non-empty configFile is passed with the linter's ConfigFlag.
+func (r *LintRunner) executeLinterFix(ctx context.Context, config *LinterConfig, filePath, projectDir, configFile string) ([]byte, error) {
 	// Build command with fix args
 	args := make([]string, len(config.FixArgs))
 	copy(args, config.FixArgs)
-	args = append(args, filePath)
+	args = append(args, configArgs(config, configFile)...)
+	if projectDir == "" {
+		args = append(args, filePath)
+	}
 
 	// Create command with timeout
 	timeout := config.Timeout
@@ -247,9 +427,12 @@ func (r *LintRunner) executeLinterFix(ctx context.Context, config *LinterConfig,
 	cmd := exec.CommandContext(cmdCtx, config.Command, args...)
 
 	// Set working directory
-	if r.workingDir != "" {
+	switch {
+	case projectDir != "":
+		cmd.Dir = projectDir
+	case r.workingDir != "":
 		cmd.Dir = r.workingDir
-	} else {
+	default:
 		cmd.Dir = filepath.Dir(filePath)
 	}
 
@@ -12,6 +12,9 @@ package lint
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -146,6 +149,77 @@ func TestAutoFixContent_LinterUnavailable(t *testing.T) {
 // FEEDBACK TESTS
 // =============================================================================
 
+// newTrimRunner returns a runner whose "python" linter reports nothing
+// and whose fix mode strips trailing spaces.
+func newTrimRunner(t *testing.T) *LintRunner {
+	t.Helper()
+	runner := NewLintRunner()
+	runner.Configs().Register(&LinterConfig{
+		Language:   "python",
+		Command:    "sh",
+		Args:       []string{"-c", "echo '[]'"},
+		Extensions: []string{".py"},
+		FixArgs:    []string{"-c", `sed -i 's/ *$//' "$0"`},
+	})
+	runner.DetectAvailableLinters()
+	return runner
+}
+
+func TestLintRunner_Fix(t *testing.T) {
+	runner := newTrimRunner(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.py")
+	original := "x = 1  \ny = 2\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fix, err := runner.Fix(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if !fix.Changed() || string(fix.Fixed) != "x = 1\ny = 2\n" {
+		t.Errorf("Fixed = %q, changed = %v", fix.Fixed, fix.Changed())
+	}
+	if !strings.Contains(fix.Diff, "@@ -1,2 +1,2 @@\n+x = 1\n-x = 1  \n y = 2\n") {
+		t.Errorf("Diff = %q", fix.Diff)
+	}
+	if fix.Remaining == nil || !fix.Remaining.Valid || fix.Remaining.FilePath != path {
+		t.Errorf("Remaining = %+v", fix.Remaining)
+	}
+
+	// The file itself is untouched
+	if got, _ := os.ReadFile(path); string(got) != original {
+		t.Errorf("file changed to %q", got)
+	}
+}
+
+func TestLintRunner_Fix_NothingToFix(t *testing.T) {
+	runner := newTrimRunner(t)
+
+	fix, err := runner.FixContentAt(context.Background(), []byte("x = 1\n"), "python", "app.py")
+	if err != nil {
+		t.Fatalf("FixContentAt: %v", err)
+	}
+	if fix.Changed() || fix.Diff != "" {
+		t.Errorf("Diff = %q, want none", fix.Diff)
+	}
+}
+
+func TestLintRunner_Fix_Unsupported(t *testing.T) {
+	runner := NewLintRunner()
+	runner.Configs().Register(&LinterConfig{
+		Language:   "nofixlang",
+		Command:    "somecommand",
+		Extensions: []string{".nofix"},
+	})
+
+	_, err := runner.FixContentAt(context.Background(), []byte("x"), "nofixlang", "a.nofix")
+	if !errors.Is(err, ErrAutoFixUnsupported) {
+		t.Errorf("FixContentAt error = %v, want ErrAutoFixUnsupported", err)
+	}
+}
+
 func TestFormatFeedback_NilResult(t *testing.T) {
 	feedback := FormatFeedback(nil)
 
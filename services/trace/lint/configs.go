@@ -45,6 +45,8 @@ var DefaultGoConfig = LinterConfig{
 		"--issues-exit-code=0",
 		"--timeout=30s",
 	},
+	ConfigFiles: []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"},
+	ConfigFlag:  "--config",
 }
 
 // DefaultPythonConfig is the configuration for Ruff.
@@ -70,6 +72,9 @@ var DefaultPythonConfig = LinterConfig{
 		"--output-format=json",
 		"--exit-zero",
 	},
+	// pyproject.toml counts only with a [tool.ruff] table
+	ConfigFiles: []string{".ruff.toml", "ruff.toml", "pyproject.toml"},
+	ConfigFlag:  "--config",
 }
 
 // DefaultTSConfig is the configuration for ESLint (TypeScript/JavaScript).
@@ -92,6 +97,8 @@ var DefaultTSConfig = LinterConfig{
 		"--format=json",
 		"--no-error-on-unmatched-pattern",
 	},
+	ConfigFiles: eslintConfigFiles,
+	ConfigFlag:  "--config",
 }
 
 // DefaultJSConfig is an alias for TypeScript config (same linter).
@@ -109,6 +116,55 @@ var DefaultJSConfig = LinterConfig{
 		"--format=json",
 		"--no-error-on-unmatched-pattern",
 	},
+	ConfigFiles: eslintConfigFiles,
+	ConfigFlag:  "--config",
+}
+
+// eslintConfigFiles are ESLint's flat and legacy config file names.
+var eslintConfigFiles = []string{
+	"eslint.config.js",
+	"eslint.config.mjs",
+	"eslint.config.cjs",
+	"eslint.config.ts",
+	".eslintrc.js",
+	".eslintrc.cjs",
+	".eslintrc.yaml",
+	".eslintrc.yml",
+	".eslintrc.json",
+	".eslintrc",
+}
+
+// DefaultRustConfig is the configuration for Clippy.
+//
+// Description:
+//
+//	Clippy is the standard Rust linter, run through cargo. It checks the
+//	whole crate, so it runs at the Cargo project root and its JSON
+//	messages are filtered to the linted file. Fix mode rewrites files
+//	without a clean VCS tree, so only run it in a sandbox (FixContentAt).
+var DefaultRustConfig = LinterConfig{
+	Language: "rust",
+	Command:  "cargo",
+	Args: []string{
+		"clippy",
+		"--message-format=json",
+		"--quiet",
+		"--all-targets",
+	},
+	Extensions: []string{".rs"},
+	Timeout:    120 * time.Second,
+	FixArgs: []string{
+		"clippy",
+		"--fix",
+		"--allow-dirty",
+		"--allow-staged",
+		"--allow-no-vcs",
+		"--quiet",
+		"--all-targets",
+	},
+	ProjectFile:  "Cargo.toml",
+	ProbeCommand: "cargo-clippy",
+	ConfigFiles:  []string{"clippy.toml", ".clippy.toml"},
 }
 
 // =============================================================================
@@ -142,6 +198,7 @@ func (r *ConfigRegistry) registerDefaults() {
 	r.Register(&DefaultPythonConfig)
 	r.Register(&DefaultTSConfig)
 	r.Register(&DefaultJSConfig)
+	r.Register(&DefaultRustConfig)
 }
 
 // Register adds or updates a linter configuration.
@@ -292,6 +349,8 @@ func LanguageFromPath(filePath string) string {
 		return "typescript"
 	case ".js", ".jsx", ".mjs", ".cjs":
 		return "javascript"
+	case ".rs":
+		return "rust"
 	default:
 		return ""
 	}
@@ -321,6 +380,8 @@ func ExtensionForLanguage(language string) string {
 		return ".ts"
 	case "javascript":
 		return ".js"
+	case "rust":
+		return ".rs"
 	default:
 		return ""
 	}
@@ -35,6 +35,21 @@
 //	| Python     | Ruff           | ruff check          |
 //	| TypeScript | ESLint         | eslint              |
 //	| JavaScript | ESLint         | eslint              |
+//	| Rust       | Clippy         | cargo clippy        |
+//
+// Clippy is a project linter: it checks the whole crate containing the
+// file (found via Cargo.toml) and only issues in that file are kept.
+// LintContentAt lints unwritten content inside a temp copy of the project.
+// Clippy levels (deny/warn) carry over; see DefaultRustPolicy.
+//
+// # Project Config
+//
+// LintConfigResolver finds the config that applies to a file
+// (.golangci.yml, ruff.toml or pyproject.toml with [tool.ruff], ESLint
+// configs, clippy.toml) by walking up from it, caching the answer per
+// directory. LintResult.ConfigFile reports it, so a user can see why a
+// rule fired. Content linted from a temp file (LintContentAt, Fix) gets
+// the config passed explicitly, as the linter cannot find it from there.
 //
 // # Severity Mapping
 //
@@ -62,6 +77,19 @@
 //	// Lint content directly
 //	result, err := runner.LintContent(ctx, []byte("package main..."), "go")
 //
+//	// Lint content as if it were at a path in its project
+//	result, err := runner.LintContentAt(ctx, content, "rust", "/repo/src/lib.rs")
+//
+//	// Lint the whole file but report only issues on changed lines
+//	result, err := runner.LintChanges(ctx, old, new, "go", path,
+//	    []lint.LineRange{{Start: 40, End: 42}}, lint.ScopeOptions{ContextLines: 3})
+//
+//	// Apply auto-fixes to a sandbox copy and get the diff
+//	fix, err := runner.Fix(ctx, "path/to/file.py")
+//	if err == nil && fix.Changed() {
+//	    fmt.Print(fix.Diff)
+//	}
+//
 // # Thread Safety
 //
 // All exported types are safe for concurrent use.
@@ -34,6 +34,13 @@ var (
 
 	// ErrInvalidInput indicates invalid input to a lint function.
 	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrAutoFixUnsupported indicates the linter has no fix mode.
+	ErrAutoFixUnsupported = errors.New("linter does not support auto-fix")
+
+	// ErrNoProject indicates a project linter (e.g., cargo clippy) was
+	// asked to lint a file outside any project it can build.
+	ErrNoProject = errors.New("file is not in a lintable project")
 )
 
 // LinterError wraps errors from a specific linter with context.
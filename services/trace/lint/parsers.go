@@ -11,6 +11,7 @@
 package lint
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -360,6 +361,171 @@ func mapESLintSeverity(severity int) Severity {
 	}
 }
 
+// =============================================================================
+// CLIPPY PARSER
+// =============================================================================
+
+// cargoMessage is one line of cargo --message-format=json output.
+type cargoMessage struct {
+	Reason  string         `json:"reason"`
+	Message *clippyMessage `json:"message"`
+}
+
+type clippyMessage struct {
+	Message  string          `json:"message"`
+	Code     *clippyCode     `json:"code"`
+	Level    string          `json:"level"`
+	Spans    []clippySpan    `json:"spans"`
+	Children []clippyMessage `json:"children"`
+}
+
+type clippyCode struct {
+	Code string `json:"code"`
+}
+
+type clippySpan struct {
+	FileName                string  `json:"file_name"`
+	LineStart               int     `json:"line_start"`
+	LineEnd                 int     `json:"line_end"`
+	ColumnStart             int     `json:"column_start"`
+	ColumnEnd               int     `json:"column_end"`
+	IsPrimary               bool    `json:"is_primary"`
+	SuggestedReplacement    *string `json:"suggested_replacement"`
+	SuggestionApplicability *string `json:"suggestion_applicability"`
+}
+
+// parseClippyOutput parses JSON output from cargo clippy.
+//
+// Description:
+//
+//	cargo clippy --message-format=json writes one JSON object per line.
+//	Only "compiler-message" lines with a primary span are issues; build
+//	progress and summaries ("aborting due to ...") are skipped. Rustc
+//	errors (E codes) and clippy lints (clippy:: codes) are both kept.
+//	A diagnostic reported for several targets of the crate is kept once.
+//
+// Inputs:
+//
+//	data - Raw output from cargo clippy --message-format=json
+//
+// Outputs:
+//
+//	[]LintIssue - Parsed issues. File paths are as cargo reports them,
+//	relative to the directory cargo ran in.
+//	error - Non-nil if a line is not valid JSON
+func parseClippyOutput(data []byte) ([]LintIssue, error) {
+	var issues []LintIssue
+	seen := make(map[string]bool)
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var cm cargoMessage
+		if err := json.Unmarshal(line, &cm); err != nil {
+			return nil, fmt.Errorf("parsing clippy output: %w", err)
+		}
+		if cm.Reason != "compiler-message" || cm.Message == nil {
+			continue
+		}
+		msg := cm.Message
+		span := primarySpan(msg.Spans)
+		if span == nil {
+			continue
+		}
+
+		issue := LintIssue{
+			File:      span.FileName,
+			Line:      span.LineStart,
+			Column:    span.ColumnStart,
+			EndLine:   span.LineEnd,
+			EndColumn: span.ColumnEnd,
+			Rule:      "rustc",
+			Severity:  mapClippySeverity(msg.Level),
+			Message:   msg.Message,
+			Linter:    "clippy",
+		}
+		if msg.Code != nil && msg.Code.Code != "" {
+			issue.Rule = msg.Code.Code
+			issue.RuleURL = clippyRuleURL(msg.Code.Code)
+		}
+
+		// Suggestions are children whose spans carry a replacement
+		for _, child := range msg.Children {
+			var replacements []string
+			applicable := true
+			for _, cs := range child.Spans {
+				if cs.SuggestedReplacement == nil {
+					continue
+				}
+				replacements = append(replacements, *cs.SuggestedReplacement)
+				if cs.SuggestionApplicability == nil || *cs.SuggestionApplicability != "MachineApplicable" {
+					applicable = false
+				}
+			}
+			if len(replacements) == 0 {
+				continue
+			}
+			issue.Suggestion = child.Message
+			issue.CanAutoFix = applicable
+			if len(replacements) == 1 {
+				issue.Replacement = replacements[0]
+			}
+			break
+		}
+
+		key := fmt.Sprintf("%s:%d:%d:%s:%s", issue.File, issue.Line, issue.Column, issue.Rule, issue.Message)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// primarySpan returns the primary span of a diagnostic, or nil.
+func primarySpan(spans []clippySpan) *clippySpan {
+	for i := range spans {
+		if spans[i].IsPrimary {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+// mapClippySeverity maps a rustc diagnostic level to our Severity.
+//
+// Clippy reports a lint at the level it is configured at: deny and
+// forbid lints, like compile errors, are "error"; warn lints are
+// "warning". Allowed lints are not reported at all.
+func mapClippySeverity(level string) Severity {
+	switch level {
+	case "error", "error: internal compiler error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	case "note", "help", "failure-note":
+		return SeverityInfo
+	default:
+		return SeverityWarning
+	}
+}
+
+// clippyRuleURL returns the documentation link for a clippy lint or a
+// rustc error code, or "" for other codes.
+func clippyRuleURL(code string) string {
+	if name, ok := strings.CutPrefix(code, "clippy::"); ok {
+		return "https://rust-lang.github.io/rust-clippy/master/index.html#" + name
+	}
+	if len(code) == 5 && code[0] == 'E' {
+		return "https://doc.rust-lang.org/error_codes/" + code + ".html"
+	}
+	return ""
+}
+
 // =============================================================================
 // PARSER REGISTRY
 // =============================================================================
@@ -373,6 +539,7 @@ var parserRegistry = map[string]ParserFunc{
 	"python":     parseRuffOutput,
 	"typescript": parseESLintOutput,
 	"javascript": parseESLintOutput,
+	"rust":       parseClippyOutput,
 }
 
 // GetParser returns the parser function for a language.
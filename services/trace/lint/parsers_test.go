@@ -342,6 +342,103 @@ func TestParseESLintOutput(t *testing.T) {
 	})
 }
 
+func TestParseClippyOutput(t *testing.T) {
+	t.Run("valid output with issues", func(t *testing.T) {
+		// cargo clippy --message-format=json output, one message per line
+		output := []byte(`{"reason":"compiler-artifact","package_id":"demo 0.1.0"}
+{"reason":"compiler-message","message":{"level":"warning","message":"unneeded ` + "`return`" + ` statement","code":{"code":"clippy::needless_return"},"spans":[{"file_name":"src/lib.rs","line_start":2,"line_end":2,"column_start":5,"column_end":18,"is_primary":true}],"children":[{"level":"help","message":"remove ` + "`return`" + `","spans":[{"file_name":"src/lib.rs","line_start":2,"line_end":2,"column_start":5,"column_end":18,"is_primary":true,"suggested_replacement":"x + 1","suggestion_applicability":"MachineApplicable"}],"children":[]}]}}
+{"reason":"compiler-message","message":{"level":"error","message":"equal expressions as operands to ` + "`==`" + `","code":{"code":"clippy::eq_op"},"spans":[{"file_name":"src/lib.rs","line_start":7,"line_end":7,"column_start":5,"column_end":11,"is_primary":true}],"children":[]}}
+{"reason":"compiler-message","message":{"level":"error","message":"mismatched types","code":{"code":"E0308"},"spans":[{"file_name":"src/main.rs","line_start":3,"line_end":3,"column_start":18,"column_end":20,"is_primary":false},{"file_name":"src/main.rs","line_start":3,"line_end":3,"column_start":23,"column_end":26,"is_primary":true}],"children":[{"level":"help","message":"try using a conversion method","spans":[{"file_name":"src/main.rs","line_start":3,"line_end":3,"column_start":23,"column_end":26,"is_primary":true,"suggested_replacement":"\"a\".to_string()","suggestion_applicability":"MaybeIncorrect"}],"children":[]}]}}
+{"reason":"compiler-message","message":{"level":"warning","message":"2 warnings emitted","code":null,"spans":[],"children":[]}}
+{"reason":"build-finished","success":false}
+`)
+
+		issues, err := parseClippyOutput(output)
+		if err != nil {
+			t.Fatalf("parseClippyOutput: %v", err)
+		}
+
+		if len(issues) != 3 {
+			t.Fatalf("Expected 3 issues, got %d", len(issues))
+		}
+
+		// Clippy lint with a machine-applicable fix
+		if issues[0].Rule != "clippy::needless_return" || issues[0].Severity != SeverityWarning {
+			t.Errorf("Issue 0 = %s/%v, want clippy::needless_return/warning", issues[0].Rule, issues[0].Severity)
+		}
+		if issues[0].File != "src/lib.rs" || issues[0].Line != 2 || issues[0].Column != 5 {
+			t.Errorf("Issue 0 location = %s", issues[0].Location())
+		}
+		if !issues[0].CanAutoFix || issues[0].Replacement != "x + 1" {
+			t.Errorf("Issue 0 CanAutoFix = %v, Replacement = %q", issues[0].CanAutoFix, issues[0].Replacement)
+		}
+		if issues[0].RuleURL == "" {
+			t.Error("Issue 0 should have a RuleURL")
+		}
+
+		// Denied clippy lint
+		if issues[1].Severity != SeverityError {
+			t.Errorf("Issue 1 Severity = %v, want error", issues[1].Severity)
+		}
+
+		// Compiler error reported at its primary span
+		if issues[2].Rule != "E0308" || issues[2].Column != 23 {
+			t.Errorf("Issue 2 = %s at column %d, want E0308 at 23", issues[2].Rule, issues[2].Column)
+		}
+		if issues[2].CanAutoFix || issues[2].Suggestion != "try using a conversion method" {
+			t.Errorf("Issue 2 CanAutoFix = %v, Suggestion = %q", issues[2].CanAutoFix, issues[2].Suggestion)
+		}
+	})
+
+	t.Run("duplicate messages", func(t *testing.T) {
+		// --all-targets reports lib diagnostics once per target
+		line := `{"reason":"compiler-message","message":{"level":"warning","message":"unused variable: ` + "`y`" + `","code":{"code":"unused_variables"},"spans":[{"file_name":"src/lib.rs","line_start":4,"line_end":4,"column_start":9,"column_end":10,"is_primary":true}],"children":[]}}`
+		issues, err := parseClippyOutput([]byte(line + "\n" + line + "\n"))
+		if err != nil {
+			t.Fatalf("parseClippyOutput: %v", err)
+		}
+		if len(issues) != 1 {
+			t.Errorf("Expected 1 issue, got %d", len(issues))
+		}
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		issues, err := parseClippyOutput([]byte(`{"reason":"build-finished","success":true}`))
+		if err != nil {
+			t.Fatalf("parseClippyOutput: %v", err)
+		}
+		if len(issues) != 0 {
+			t.Errorf("Expected 0 issues, got %d", len(issues))
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := parseClippyOutput([]byte("error: could not find Cargo.toml")); err == nil {
+			t.Error("Expected error for invalid JSON")
+		}
+	})
+}
+
+func TestMapClippySeverity(t *testing.T) {
+	tests := []struct {
+		level string
+		want  Severity
+	}{
+		{"error", SeverityError},
+		{"error: internal compiler error", SeverityError},
+		{"warning", SeverityWarning},
+		{"note", SeverityInfo},
+		{"help", SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		got := mapClippySeverity(tt.level)
+		if got != tt.want {
+			t.Errorf("mapClippySeverity(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
 func TestMapRuffSeverity(t *testing.T) {
 	tests := []struct {
 		code string
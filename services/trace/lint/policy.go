@@ -36,6 +36,12 @@ type RulePolicy struct {
 
 	// Ignore are rules to completely ignore.
 	Ignore []string
+
+	// KeepLinterSeverity keeps the severity the linter reported for
+	// rules in none of the lists, instead of defaulting to a warning.
+	// Used for linters whose levels are configured per project, such as
+	// clippy's deny and warn lint levels.
+	KeepLinterSeverity bool
 }
 
 // ShouldBlock returns true if the rule should block patches.
@@ -145,12 +151,13 @@ func (p *RulePolicy) GetSeverity(rule string) Severity {
 //   - "errcheck" matches "errcheck"
 //   - "SA1000" matches "SA" (prefix)
 //   - "errcheck/assert" matches "errcheck" (hierarchy)
+//   - "clippy::eq_op" matches "clippy" (Rust lint tool)
 func matchesRule(rule, pattern string) bool {
 	if rule == pattern {
 		return true
 	}
 	// Check hierarchy match (e.g., "errcheck/assert" matches "errcheck")
-	if strings.HasPrefix(rule, pattern+"/") {
+	if strings.HasPrefix(rule, pattern+"/") || strings.HasPrefix(rule, pattern+"::") {
 		return true
 	}
 	// Check prefix match for codes like SA1000 matching SA
@@ -304,6 +311,22 @@ var DefaultTSPolicy = RulePolicy{
 	},
 }
 
+// DefaultRustPolicy is the default policy for Rust linting (Clippy).
+//
+// Description:
+//
+//	Rustc errors (E0308, etc.) always block. Clippy lints keep the level
+//	the project configures: clippy::correctness is deny by default and
+//	blocks, the other enabled groups warn, so #![deny(...)] and
+//	#![warn(...)] attributes and clippy.toml are honored.
+var DefaultRustPolicy = RulePolicy{
+	BlockOn: []string{
+		// Compile errors
+		"E",
+	},
+	KeepLinterSeverity: true,
+}
+
 // =============================================================================
 // POLICY REGISTRY
 // =============================================================================
@@ -331,6 +354,7 @@ func (r *PolicyRegistry) registerDefaults() {
 	r.policies["python"] = &DefaultPythonPolicy
 	r.policies["typescript"] = &DefaultTSPolicy
 	r.policies["javascript"] = &DefaultTSPolicy // Same as TS
+	r.policies["rust"] = &DefaultRustPolicy
 }
 
 // Get returns the policy for a language.
@@ -420,6 +444,9 @@ func ApplyPolicy(issues []LintIssue, policy *RulePolicy) (errors, warnings, info
 
 		// Apply policy severity
 		severity := policy.GetSeverity(issue.Rule)
+		if policy.KeepLinterSeverity && !policy.ShouldBlock(issue.Rule) && !policy.ShouldWarn(issue.Rule) {
+			severity = issue.Severity
+		}
 		issue.Severity = severity
 
 		switch severity {
@@ -434,3 +461,58 @@ func ApplyPolicy(issues []LintIssue, policy *RulePolicy) (errors, warnings, info
 
 	return errors, warnings, infos
 }
+
+// =============================================================================
+// PROFILES
+// =============================================================================
+
+// Profile adjusts how strictly a project treats lint findings, on top of
+// the per-language rule policies.
+type Profile string
+
+const (
+	// ProfileDefault leaves results as the rule policy classified them.
+	ProfileDefault Profile = "default"
+
+	// ProfileStrict promotes warnings to blocking errors.
+	ProfileStrict Profile = "strict"
+
+	// ProfileRelaxed demotes blocking errors to warnings.
+	ProfileRelaxed Profile = "relaxed"
+)
+
+// ApplyProfile reclassifies a lint result for a project's profile.
+//
+// Description:
+//
+//	Under ProfileStrict every warning becomes an error; under
+//	ProfileRelaxed every error becomes a warning, so the result is always
+//	valid. Ignored rules (infos) are unaffected. Unknown profiles and
+//	ProfileDefault leave the result unchanged.
+//
+// Inputs:
+//
+//	result - The result to modify in place. Nil is a no-op.
+//	profile - The project's lint profile
+func ApplyProfile(result *LintResult, profile Profile) {
+	if result == nil {
+		return
+	}
+	switch profile {
+	case ProfileStrict:
+		for i := range result.Warnings {
+			result.Warnings[i].Severity = SeverityError
+		}
+		result.Errors = append(result.Errors, result.Warnings...)
+		result.Warnings = make([]LintIssue, 0)
+	case ProfileRelaxed:
+		for i := range result.Errors {
+			result.Errors[i].Severity = SeverityWarning
+		}
+		result.Warnings = append(result.Errors, result.Warnings...)
+		result.Errors = make([]LintIssue, 0)
+	default:
+		return
+	}
+	result.Valid = len(result.Errors) == 0
+}
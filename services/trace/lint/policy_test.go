@@ -273,3 +273,73 @@ func TestDefaultPythonPolicy(t *testing.T) {
 		t.Error("E501 should be ignored")
 	}
 }
+
+func TestDefaultRustPolicy(t *testing.T) {
+	// Compiler errors block
+	if !DefaultRustPolicy.ShouldBlock("E0308") {
+		t.Error("E0308 should block")
+	}
+
+	// Clippy lints keep the level clippy reported
+	issues := []LintIssue{
+		{Rule: "clippy::eq_op", Severity: SeverityError},
+		{Rule: "clippy::needless_return", Severity: SeverityWarning},
+		{Rule: "dead_code", Severity: SeverityWarning},
+		{Rule: "E0308", Severity: SeverityError},
+	}
+	errors, warnings, infos := ApplyPolicy(issues, &DefaultRustPolicy)
+	if len(errors) != 2 || len(warnings) != 2 || len(infos) != 0 {
+		t.Errorf("got %d errors, %d warnings, %d infos, want 2, 2 and 0", len(errors), len(warnings), len(infos))
+	}
+}
+
+func TestRulePolicy_RustPaths(t *testing.T) {
+	policy := &RulePolicy{WarnOn: []string{"clippy"}, BlockOn: []string{"clippy::correctness"}}
+
+	if !policy.ShouldWarn("clippy::needless_return") {
+		t.Error("clippy should match clippy::needless_return")
+	}
+	if policy.ShouldWarn("clippyish") {
+		t.Error("clippy should not match clippyish")
+	}
+	if policy.ShouldBlock("clippy::needless_return") {
+		t.Error("clippy::correctness should not match clippy::needless_return")
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	newResult := func() *LintResult {
+		return &LintResult{
+			Valid:    false,
+			Errors:   []LintIssue{{Rule: "errcheck", Severity: SeverityError}},
+			Warnings: []LintIssue{{Rule: "unused", Severity: SeverityWarning}},
+			Infos:    []LintIssue{{Rule: "lll", Severity: SeverityInfo}},
+		}
+	}
+
+	strict := newResult()
+	ApplyProfile(strict, ProfileStrict)
+	if len(strict.Errors) != 2 || len(strict.Warnings) != 0 || strict.Valid {
+		t.Errorf("strict: errors=%d warnings=%d valid=%v, want 2, 0, false", len(strict.Errors), len(strict.Warnings), strict.Valid)
+	}
+	if strict.Errors[1].Severity != SeverityError {
+		t.Errorf("strict: promoted severity = %v, want error", strict.Errors[1].Severity)
+	}
+
+	relaxed := newResult()
+	ApplyProfile(relaxed, ProfileRelaxed)
+	if len(relaxed.Errors) != 0 || len(relaxed.Warnings) != 2 || !relaxed.Valid {
+		t.Errorf("relaxed: errors=%d warnings=%d valid=%v, want 0, 2, true", len(relaxed.Errors), len(relaxed.Warnings), relaxed.Valid)
+	}
+	if len(relaxed.Infos) != 1 {
+		t.Error("relaxed: infos should be unchanged")
+	}
+
+	unchanged := newResult()
+	ApplyProfile(unchanged, ProfileDefault)
+	if len(unchanged.Errors) != 1 || len(unchanged.Warnings) != 1 || unchanged.Valid {
+		t.Error("default profile should leave the result unchanged")
+	}
+
+	ApplyProfile(nil, ProfileStrict)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lint
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// =============================================================================
+// CONFIG RESOLUTION
+// =============================================================================
+
+// configMarkers qualify shared config file names: the file is a linter
+// config only if it contains the marker.
+var configMarkers = map[string][]byte{
+	"pyproject.toml": []byte("[tool.ruff"),
+}
+
+// LintConfigResolver finds the project lint config that applies to a file.
+//
+// Description:
+//
+//	Walks up from the file's directory to the first directory holding
+//	one of the linter's ConfigFiles, as the linters themselves do. The
+//	answer for every directory on the way is cached per language, so
+//	files in the same tree resolve with one map lookup. Config files
+//	created or removed later are not seen until Clear is called.
+//
+// Thread Safety: Safe for concurrent use.
+type LintConfigResolver struct {
+	mu    sync.RWMutex
+	cache map[resolverKey]string
+}
+
+// resolverKey identifies a cached resolution.
+type resolverKey struct {
+	language string
+	dir      string
+}
+
+// NewLintConfigResolver creates an empty resolver.
+func NewLintConfigResolver() *LintConfigResolver {
+	return &LintConfigResolver{cache: make(map[resolverKey]string)}
+}
+
+// Resolve returns the config file in effect for filePath.
+//
+// Description:
+//
+//	Returns the first of config.ConfigFiles found in the file's
+//	directory or its nearest ancestor. Within a directory, earlier names
+//	win.
+//
+// Inputs:
+//
+//	config - The linter config, for ConfigFiles and Language
+//	filePath - Absolute path of the linted file
+//
+// Outputs:
+//
+//	string - Absolute path of the config file, or "" if there is none
+//
+// Thread Safety: Safe for concurrent use.
+func (c *LintConfigResolver) Resolve(config *LinterConfig, filePath string) string {
+	if config == nil || len(config.ConfigFiles) == 0 {
+		return ""
+	}
+
+	var visited []string
+	found := ""
+	for dir := filepath.Dir(filePath); ; dir = filepath.Dir(dir) {
+		c.mu.RLock()
+		cached, ok := c.cache[resolverKey{config.Language, dir}]
+		c.mu.RUnlock()
+		if ok {
+			found = cached
+			break
+		}
+
+		visited = append(visited, dir)
+		if path := findConfigIn(dir, config.ConfigFiles); path != "" {
+			found = path
+			break
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	for _, dir := range visited {
+		c.cache[resolverKey{config.Language, dir}] = found
+	}
+	c.mu.Unlock()
+	return found
+}
+
+// Clear drops all cached resolutions.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *LintConfigResolver) Clear() {
+	c.mu.Lock()
+	c.cache = make(map[resolverKey]string)
+	c.mu.Unlock()
+}
+
+// findConfigIn returns the first of names that is a config file in dir.
+func findConfigIn(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if marker, ok := configMarkers[name]; ok {
+			data, err := os.ReadFile(path)
+			if err != nil || !bytes.Contains(data, marker) {
+				continue
+			}
+		}
+		return path
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles writes files (relative path to content) under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLintConfigResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".golangci.yml":        "linters: {}\n",
+		"svc/.golangci.json":   "{}\n",
+		"svc/.golangci.yaml":   "linters: {}\n",
+		"pyproject.toml":       "[tool.ruff]\nline-length = 100\n",
+		"tools/pyproject.toml": "[project]\nname = \"tools\"\n",
+	})
+	resolver := NewLintConfigResolver()
+
+	tests := []struct {
+		name   string
+		config *LinterConfig
+		file   string
+		want   string
+	}{
+		{"nearest ancestor", &DefaultGoConfig, "pkg/deep/a.go", ".golangci.yml"},
+		{"closer dir wins", &DefaultGoConfig, "svc/a.go", "svc/.golangci.yaml"},
+		{"ruff in pyproject", &DefaultPythonConfig, "app.py", "pyproject.toml"},
+		{"pyproject without ruff", &DefaultPythonConfig, "tools/run.py", "pyproject.toml"},
+		{"no config files", &LinterConfig{Language: "none"}, "a.txt", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(dir, tt.want)
+			}
+			if got := resolver.Resolve(tt.config, filepath.Join(dir, tt.file)); got != want {
+				t.Errorf("Resolve(%s) = %q, want %q", tt.file, got, want)
+			}
+		})
+	}
+}
+
+func TestLintConfigResolver_Cache(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"ruff.toml": "line-length = 100\n"})
+	resolver := NewLintConfigResolver()
+	file := filepath.Join(dir, "a", "b", "app.py")
+
+	if got := resolver.Resolve(&DefaultPythonConfig, file); got != filepath.Join(dir, "ruff.toml") {
+		t.Fatalf("Resolve = %q", got)
+	}
+
+	// Cached until cleared
+	if err := os.Remove(filepath.Join(dir, "ruff.toml")); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolver.Resolve(&DefaultPythonConfig, filepath.Join(dir, "a", "other.py")); got == "" {
+		t.Error("Resolve from a cached parent dir missed the cache")
+	}
+	resolver.Clear()
+	if got := resolver.Resolve(&DefaultPythonConfig, file); got != "" {
+		t.Errorf("Resolve after Clear = %q, want none", got)
+	}
+}
+
+func TestLintRunner_ConfigFile(t *testing.T) {
+	// A python "linter" that reports an error only when given a config
+	script := `if [ "$0" = "--config" ]; then
+  echo '[{"code":"F821","message":"configured","location":{"row":1,"column":1}}]'
+else
+  echo '[]'
+fi`
+	runner := NewLintRunner()
+	runner.Configs().Register(&LinterConfig{
+		Language:    "python",
+		Command:     "sh",
+		Args:        []string{"-c", script},
+		Extensions:  []string{".py"},
+		ConfigFiles: []string{"ruff.toml"},
+		ConfigFlag:  "--config",
+	})
+	runner.DetectAvailableLinters()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"ruff.toml": "", "app.py": "x = 1\n"})
+	path := filepath.Join(dir, "app.py")
+
+	// Content linted away from the project gets the project's config
+	result, err := runner.LintContentAt(ctx, []byte("x = 1\n"), "python", path)
+	if err != nil {
+		t.Fatalf("LintContentAt: %v", err)
+	}
+	if result.ConfigFile != filepath.Join(dir, "ruff.toml") || len(result.Errors) != 1 {
+		t.Errorf("ConfigFile = %q, errors = %d, want ruff.toml and 1", result.ConfigFile, len(result.Errors))
+	}
+
+	// Files linted in place are left to the linter's own discovery
+	result, err = runner.Lint(ctx, path)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	if result.ConfigFile != filepath.Join(dir, "ruff.toml") || len(result.Errors) != 0 {
+		t.Errorf("ConfigFile = %q, errors = %d, want ruff.toml and 0", result.ConfigFile, len(result.Errors))
+	}
+}
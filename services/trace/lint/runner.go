@@ -21,6 +21,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // =============================================================================
@@ -42,6 +44,8 @@ type LintRunner struct {
 	available  map[string]bool
 	availMu    sync.RWMutex
 	workingDir string
+	origin     origin.Filter
+	resolver   *LintConfigResolver
 }
 
 // Option configures the LintRunner.
@@ -61,6 +65,17 @@ func WithConfigs(configs *ConfigRegistry) Option {
 	}
 }
 
+// WithOriginFilter sets how generated and vendored files are linted.
+//
+// PolicyExclude skips the file (the result is valid and marked Skipped).
+// PolicyDownWeight lints the file but demotes blocking errors to warnings.
+// Default: exclude both.
+func WithOriginFilter(f origin.Filter) Option {
+	return func(r *LintRunner) {
+		r.origin = f
+	}
+}
+
 // WithPolicies sets a custom policy registry.
 func WithPolicies(policies *PolicyRegistry) Option {
 	return func(r *LintRunner) {
@@ -87,6 +102,8 @@ func NewLintRunner(opts ...Option) *LintRunner {
 		configs:   NewConfigRegistry(),
 		policies:  NewPolicyRegistry(),
 		available: make(map[string]bool),
+		origin:    origin.ExcludeAllFilter(),
+		resolver:  NewLintConfigResolver(),
 	}
 
 	for _, opt := range opts {
@@ -121,7 +138,11 @@ func (r *LintRunner) DetectAvailableLinters() map[string]bool {
 			continue
 		}
 
-		_, err := exec.LookPath(config.Command)
+		probe := config.Command
+		if config.ProbeCommand != "" {
+			probe = config.ProbeCommand
+		}
+		_, err := exec.LookPath(probe)
 		available := err == nil
 
 		r.available[lang] = available
@@ -226,6 +247,13 @@ func (r *LintRunner) Lint(ctx context.Context, filePath string) (*LintResult, er
 //
 // Thread Safety: Safe for concurrent use.
 func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language string) (*LintResult, error) {
+	return r.lintAs(ctx, filePath, language, "")
+}
+
+// lintAs lints filePath. If configFrom is set, filePath holds content
+// that belongs at configFrom: the project lint config is resolved from
+// there and passed to the linter explicitly.
+func (r *LintRunner) lintAs(ctx context.Context, filePath, language, configFrom string) (*LintResult, error) {
 	// Start tracing span
 	ctx, span := startLintSpan(ctx, language, filePath)
 	defer span.End()
@@ -238,6 +266,39 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
 	}
 
+	// Resolve file path
+	absPath := filePath
+	if !filepath.IsAbs(filePath) {
+		if r.workingDir != "" {
+			absPath = filepath.Join(r.workingDir, filePath)
+		} else {
+			var err error
+			absPath, err = filepath.Abs(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("resolving path: %w", err)
+			}
+		}
+	}
+
+	// Skip or demote generated/vendored files
+	kind := origin.Detect(filePath, readFileHeader(absPath))
+	if !r.origin.Include(kind) {
+		setLintSpanResult(span, 0, 0, true)
+		recordLintMetrics(ctx, language, time.Since(start), 0, 0, true)
+		return &LintResult{
+			Valid:           true,
+			Errors:          make([]LintIssue, 0),
+			Warnings:        make([]LintIssue, 0),
+			Duration:        time.Since(start),
+			Linter:          config.Command,
+			Language:        language,
+			FilePath:        filePath,
+			LinterAvailable: r.IsAvailable(language),
+			Origin:          kind,
+			Skipped:         true,
+		}, nil
+	}
+
 	// Check availability
 	if !r.IsAvailable(language) {
 		// Return empty result with flag indicating linter unavailable
@@ -255,22 +316,27 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 		}, nil
 	}
 
-	// Resolve file path
-	absPath := filePath
-	if !filepath.IsAbs(filePath) {
-		if r.workingDir != "" {
-			absPath = filepath.Join(r.workingDir, filePath)
-		} else {
-			var err error
-			absPath, err = filepath.Abs(filePath)
-			if err != nil {
-				return nil, fmt.Errorf("resolving path: %w", err)
-			}
+	// Project linters check the whole project the file belongs to
+	var projectDir string
+	if config.ProjectFile != "" {
+		projectDir = findProjectDir(absPath, config.ProjectFile)
+		if projectDir == "" {
+			recordLintMetrics(ctx, language, time.Since(start), 0, 0, false)
+			return nil, fmt.Errorf("%w: no %s above %s", ErrNoProject, config.ProjectFile, filePath)
 		}
 	}
 
+	// Resolve the project lint config
+	var configFile, explicitConfig string
+	if configFrom != "" {
+		configFile = r.resolver.Resolve(config, configFrom)
+		explicitConfig = configFile
+	} else {
+		configFile = r.resolver.Resolve(config, absPath)
+	}
+
 	// Execute linter
-	output, err := r.executeLinter(ctx, config, absPath)
+	output, err := r.executeLinter(ctx, config, absPath, projectDir, explicitConfig)
 	if err != nil {
 		recordLintMetrics(ctx, language, time.Since(start), 0, 0, false)
 		return nil, err
@@ -282,11 +348,23 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 		recordLintMetrics(ctx, language, time.Since(start), 0, 0, false)
 		return nil, fmt.Errorf("%w: %v", ErrParseOutput, err)
 	}
+	if projectDir != "" {
+		issues = issuesInFile(issues, projectDir, absPath)
+	}
 
 	// Apply policy
 	policy := r.policies.Get(language)
 	errors, warnings, infos := ApplyPolicy(issues, policy)
 
+	// Down-weighted code never blocks: demote errors to warnings
+	if kind != origin.KindSource && r.origin.Weight(kind) < 1 {
+		for i := range errors {
+			errors[i].Severity = SeverityWarning
+		}
+		warnings = append(errors, warnings...)
+		errors = make([]LintIssue, 0)
+	}
+
 	result := &LintResult{
 		Valid:           len(errors) == 0,
 		Errors:          errors,
@@ -297,6 +375,8 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 		Language:        language,
 		FilePath:        filePath,
 		LinterAvailable: true,
+		Origin:          kind,
+		ConfigFile:      configFile,
 	}
 
 	// Record successful lint metrics
@@ -334,6 +414,12 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 //
 // Thread Safety: Safe for concurrent use.
 func (r *LintRunner) LintContent(ctx context.Context, content []byte, language string) (*LintResult, error) {
+	return r.lintContent(ctx, content, language, "")
+}
+
+// lintContent is LintContent for content that belongs at configFrom, if
+// set (see lintAs).
+func (r *LintRunner) lintContent(ctx context.Context, content []byte, language, configFrom string) (*LintResult, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("%w: ctx must not be nil", ErrInvalidInput)
 	}
@@ -369,7 +455,7 @@ func (r *LintRunner) LintContent(ctx context.Context, content []byte, language s
 	tmpFile.Close()
 
 	// Run linter
-	result, err := r.LintWithLanguage(ctx, tmpPath, language)
+	result, err := r.lintAs(ctx, tmpPath, language, configFrom)
 	if err != nil {
 		return nil, err
 	}
@@ -395,12 +481,187 @@ func (r *LintRunner) LintContent(ctx context.Context, content []byte, language s
 	return result, nil
 }
 
-// executeLinter runs the linter subprocess.
-func (r *LintRunner) executeLinter(ctx context.Context, config *LinterConfig, filePath string) ([]byte, error) {
+// LintContentAt runs the linter on content as if it were at filePath.
+//
+// Description:
+//
+//	Used to lint a patched file before it is written. For most linters
+//	this is LintContent with paths reported as filePath. Project linters
+//	(LinterConfig.ProjectFile, e.g. cargo clippy) need the rest of the
+//	project to check a file, so the project is copied to a temp dir with
+//	content at filePath's place and linted there. Build output dirs are
+//	linked, not copied, so the linter reuses the project's build cache.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	content - The source code to lint
+//	language - The language identifier (e.g., "go", "rust")
+//	filePath - Absolute path the content will be written to
+//
+// Outputs:
+//
+//	*LintResult - The lint result with issues reported at filePath
+//	error - Non-nil if the linter failed, or ErrNoProject if a project
+//	        linter's file is outside any project
+//
+// Thread Safety: Safe for concurrent use.
+func (r *LintRunner) LintContentAt(ctx context.Context, content []byte, language, filePath string) (*LintResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("%w: ctx must not be nil", ErrInvalidInput)
+	}
+	config := r.configs.Get(language)
+	if config == nil || config.ProjectFile == "" || len(content) == 0 || !r.IsAvailable(language) {
+		result, err := r.lintContent(ctx, content, language, filePath)
+		if err != nil {
+			return nil, err
+		}
+		remapIssuePaths(result, result.FilePath, filePath)
+		return result, nil
+	}
+
+	sb, err := newSandbox(config, content, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer sb.Close()
+
+	result, err := r.lintAs(ctx, sb.path, language, filePath)
+	if err != nil {
+		return nil, err
+	}
+	remapIssuePaths(result, sb.path, filePath)
+	return result, nil
+}
+
+// sandbox is a temp dir holding content in the place of a real file, so
+// it can be linted or fixed without touching the file.
+type sandbox struct {
+	// dir is the temp dir, removed by Close.
+	dir string
+
+	// path is where the content was written.
+	path string
+
+	// projectDir is the copied project root for project linters, or "".
+	projectDir string
+}
+
+// newSandbox writes content to a temp dir under filePath's base name.
+// For project linters (LinterConfig.ProjectFile) the project around
+// filePath is copied too, and content replaces filePath in the copy.
+func newSandbox(config *LinterConfig, content []byte, filePath string) (*sandbox, error) {
+	dir, err := os.MkdirTemp("", "lint-sandbox-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	sb := &sandbox{dir: dir, path: filepath.Join(dir, filepath.Base(filePath))}
+
+	if config.ProjectFile != "" {
+		projectDir := findProjectDir(filePath, config.ProjectFile)
+		if projectDir == "" {
+			sb.Close()
+			return nil, fmt.Errorf("%w: no %s above %s", ErrNoProject, config.ProjectFile, filePath)
+		}
+		rel, err := filepath.Rel(projectDir, filePath)
+		if err != nil {
+			sb.Close()
+			return nil, fmt.Errorf("resolving path: %w", err)
+		}
+		if err := copyProject(projectDir, dir); err != nil {
+			sb.Close()
+			return nil, fmt.Errorf("copying project: %w", err)
+		}
+		sb.path = filepath.Join(dir, rel)
+		sb.projectDir = dir
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sb.path), 0o755); err != nil {
+		sb.Close()
+		return nil, fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := os.WriteFile(sb.path, content, 0o644); err != nil {
+		sb.Close()
+		return nil, fmt.Errorf("writing temp file: %w", err)
+	}
+	return sb, nil
+}
+
+// Close removes the sandbox.
+func (s *sandbox) Close() {
+	os.RemoveAll(s.dir)
+}
+
+// projectBuildDirs are top-level project dirs holding build output.
+// copyProject links them instead of copying them.
+var projectBuildDirs = map[string]bool{
+	"target": true,
+}
+
+// copyProject copies the project at src into dst for LintContentAt.
+// Hidden top-level dirs (.git and the like) are skipped, and build
+// output dirs are symlinked so the copy shares the build cache. Hidden
+// files such as .clippy.toml are copied.
+func copyProject(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if filepath.Dir(rel) == "." {
+			if strings.HasPrefix(rel, ".") && d.IsDir() {
+				return filepath.SkipDir
+			}
+			if d.IsDir() && projectBuildDirs[rel] {
+				if err := os.Symlink(path, target); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case d.Type().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, data, 0o644)
+		default:
+			return nil
+		}
+	})
+}
+
+// remapIssuePaths reports the result and its issues at to instead of from.
+func remapIssuePaths(result *LintResult, from, to string) {
+	result.FilePath = to
+	for _, issues := range [][]LintIssue{result.Errors, result.Warnings, result.Infos} {
+		for i := range issues {
+			if issues[i].File == from {
+				issues[i].File = to
+			}
+		}
+	}
+}
+
+// executeLinter runs the linter subprocess. If projectDir is set, the
+// linter runs there on the whole project instead of on filePath. A
+// non-empty configFile is passed with the linter's ConfigFlag.
+func (r *LintRunner) executeLinter(ctx context.Context, config *LinterConfig, filePath, projectDir, configFile string) ([]byte, error) {
 	// Build command
 	args := make([]string, len(config.Args))
 	copy(args, config.Args)
-	args = append(args, filePath)
+	args = append(args, configArgs(config, configFile)...)
+	if projectDir == "" {
+		args = append(args, filePath)
+	}
 
 	// Create command with timeout
 	timeout := config.Timeout
@@ -414,9 +675,12 @@ func (r *LintRunner) executeLinter(ctx context.Context, config *LinterConfig, fi
 	cmd := exec.CommandContext(cmdCtx, config.Command, args...)
 
 	// Set working directory
-	if r.workingDir != "" {
+	switch {
+	case projectDir != "":
+		cmd.Dir = projectDir
+	case r.workingDir != "":
 		cmd.Dir = r.workingDir
-	} else {
+	default:
 		cmd.Dir = filepath.Dir(filePath)
 	}
 
@@ -449,6 +713,41 @@ func (r *LintRunner) executeLinter(ctx context.Context, config *LinterConfig, fi
 	return stdout.Bytes(), nil
 }
 
+// findProjectDir returns the outermost ancestor directory of filePath
+// that contains marker, or "" if there is none. The outermost one is the
+// workspace root for tools like cargo, where member crates have their
+// own manifest.
+func findProjectDir(filePath, marker string) string {
+	var found string
+	for dir := filepath.Dir(filePath); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			found = dir
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return found
+		}
+	}
+}
+
+// issuesInFile keeps the issues of a project linter run that are in
+// filePath. Issue paths may be absolute or relative to projectDir; kept
+// issues get filePath as their path.
+func issuesInFile(issues []LintIssue, projectDir, filePath string) []LintIssue {
+	kept := make([]LintIssue, 0, len(issues))
+	for _, issue := range issues {
+		path := issue.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		if filepath.Clean(path) != filepath.Clean(filePath) {
+			continue
+		}
+		issue.File = filePath
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
 // parseOutput parses linter JSON output based on language.
 func (r *LintRunner) parseOutput(language string, output []byte) ([]LintIssue, error) {
 	// Skip empty output
@@ -464,6 +763,20 @@ func (r *LintRunner) parseOutput(language string, output []byte) ([]LintIssue, e
 	return parser(output)
 }
 
+// configArgs returns the arguments that pass configFile to the linter.
+func configArgs(config *LinterConfig, configFile string) []string {
+	if configFile == "" || config.ConfigFlag == "" {
+		return nil
+	}
+	return []string{config.ConfigFlag, configFile}
+}
+
+// ConfigResolver returns the resolver of project lint configs, e.g. to
+// Clear it after config files change.
+func (r *LintRunner) ConfigResolver() *LintConfigResolver {
+	return r.resolver
+}
+
 // Configs returns the config registry for customization.
 func (r *LintRunner) Configs() *ConfigRegistry {
 	return r.configs
@@ -565,6 +878,11 @@ func (r *LintRunner) LintDirectory(ctx context.Context, dirPath string) ([]*Lint
 			return nil
 		}
 
+		// Skip generated/vendored files excluded by the origin filter
+		if !r.origin.IncludeFile(path, readFileHeader(path)) {
+			return nil
+		}
+
 		// Check if we have a linter for this file type
 		language := LanguageFromPath(path)
 		if language != "" && r.IsAvailable(language) {
@@ -583,3 +901,17 @@ func (r *LintRunner) LintDirectory(ctx context.Context, dirPath string) ([]*Lint
 
 	return r.LintFiles(ctx, files)
 }
+
+// readFileHeader returns up to origin.MaxHeaderBytes from the start of a
+// file, or nil if it cannot be read.
+func readFileHeader(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	buf := make([]byte, origin.MaxHeaderBytes)
+	n, _ := f.Read(buf)
+	return buf[:n]
+}
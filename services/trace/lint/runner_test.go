@@ -12,11 +12,14 @@ package lint
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 func TestNewLintRunner(t *testing.T) {
@@ -279,6 +282,109 @@ def main():
 	})
 }
 
+func TestLintRunner_Integration_Rust(t *testing.T) {
+	if _, err := exec.LookPath("cargo-clippy"); err != nil {
+		t.Skip("cargo clippy not installed")
+	}
+
+	runner := NewLintRunner()
+	runner.DetectAvailableLinters()
+
+	// Create a crate; clippy lints the whole crate, so main.rs has an
+	// issue that must not be reported for lib.rs.
+	dir := t.TempDir()
+	files := map[string]string{
+		"Cargo.toml":  "[package]\nname = \"demo\"\nversion = \"0.1.0\"\nedition = \"2021\"\n",
+		"src/main.rs": "fn main() {\n    let unused = 1;\n}\n",
+		"src/lib.rs":  "pub fn add_one(x: i32) -> i32 {\n    x + 1\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	libPath := filepath.Join(dir, "src", "lib.rs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	t.Run("clean file", func(t *testing.T) {
+		result, err := runner.Lint(ctx, libPath)
+		if err != nil {
+			t.Fatalf("Lint: %v", err)
+		}
+		if !result.LinterAvailable {
+			t.Skip("Linter not available")
+		}
+		if result.IssueCount() != 0 {
+			t.Errorf("Expected no issues in lib.rs, got %v", result.AllIssues())
+		}
+	})
+
+	t.Run("patched content", func(t *testing.T) {
+		content := []byte(`pub fn add_one(x: i32) -> i32 {
+    return x + 1;
+}
+
+#[deny(clippy::eq_op)]
+pub fn same(x: i32) -> bool {
+    x == x
+}
+`)
+		result, err := runner.LintContentAt(ctx, content, "rust", libPath)
+		if err != nil {
+			t.Fatalf("LintContentAt: %v", err)
+		}
+		if !result.LinterAvailable {
+			t.Skip("Linter not available")
+		}
+
+		// The denied lint blocks, needless_return stays a warning
+		if len(result.Errors) != 1 || result.Errors[0].Rule != "clippy::eq_op" {
+			t.Errorf("Errors = %v, want clippy::eq_op", result.Errors)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Rule != "clippy::needless_return" {
+			t.Errorf("Warnings = %v, want clippy::needless_return", result.Warnings)
+		}
+		for _, issue := range result.AllIssues() {
+			if issue.File != libPath {
+				t.Errorf("Issue File = %q, want %q", issue.File, libPath)
+			}
+		}
+
+		// The project itself is untouched
+		got, err := os.ReadFile(libPath)
+		if err != nil || string(got) != files["src/lib.rs"] {
+			t.Errorf("lib.rs changed: %q, %v", got, err)
+		}
+	})
+
+	t.Run("fix in sandbox", func(t *testing.T) {
+		content := []byte("pub fn add_one(x: i32) -> i32 {\n    return x + 1;\n}\n")
+		fix, err := runner.FixContentAt(ctx, content, "rust", libPath)
+		if err != nil {
+			t.Fatalf("FixContentAt: %v", err)
+		}
+		if string(fix.Fixed) != files["src/lib.rs"] {
+			t.Errorf("Fixed = %q, want %q", fix.Fixed, files["src/lib.rs"])
+		}
+		if fix.Remaining.IssueCount() != 0 {
+			t.Errorf("Remaining = %v", fix.Remaining.AllIssues())
+		}
+	})
+
+	t.Run("file outside a crate", func(t *testing.T) {
+		_, err := runner.LintContentAt(ctx, []byte("fn main() {}\n"), "rust", filepath.Join(t.TempDir(), "main.rs"))
+		if !errors.Is(err, ErrNoProject) {
+			t.Errorf("LintContentAt error = %v, want ErrNoProject", err)
+		}
+	})
+}
+
 func TestLintRunner_LinterUnavailable(t *testing.T) {
 	runner := NewLintRunner()
 	// Don't call DetectAvailableLinters - linters should be unavailable
@@ -312,6 +418,7 @@ func TestLanguageFromPath(t *testing.T) {
 		{"app.js", "javascript"},
 		{"app.jsx", "javascript"},
 		{"app.mjs", "javascript"},
+		{"src/lib.rs", "rust"},
 		{"file.txt", ""},
 		{"file.unknown", ""},
 		{"/path/to/main.go", "go"},
@@ -334,6 +441,7 @@ func TestExtensionForLanguage(t *testing.T) {
 		{"python", ".py"},
 		{"typescript", ".ts"},
 		{"javascript", ".js"},
+		{"rust", ".rs"},
 		{"unknown", ""},
 	}
 
@@ -344,3 +452,52 @@ func TestExtensionForLanguage(t *testing.T) {
 		}
 	}
 }
+
+func TestLintRunner_Lint_SkipsGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "types.go")
+	content := "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pb\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	runner := NewLintRunner()
+	result, err := runner.Lint(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+
+	if !result.Skipped {
+		t.Error("Expected generated file to be skipped")
+	}
+	if !result.Valid {
+		t.Error("Skipped file should be valid")
+	}
+	if result.Origin != origin.KindGenerated {
+		t.Errorf("Origin = %v, want generated", result.Origin)
+	}
+}
+
+func TestLintRunner_LintDirectory_SkipsVendor(t *testing.T) {
+	dir := t.TempDir()
+	vendored := filepath.Join(dir, "third_party", "lib")
+	if err := os.MkdirAll(vendored, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(vendored, "lib.go"), []byte("package lib\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	runner := NewLintRunner()
+	runner.availMu.Lock()
+	runner.available["go"] = true
+	runner.availMu.Unlock()
+
+	results, err := runner.LintDirectory(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("LintDirectory: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected vendored files to be skipped, got %d results", len(results))
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lint
+
+import (
+	"context"
+	"log/slog"
+)
+
+// =============================================================================
+// CHANGE-SCOPED LINTING
+// =============================================================================
+
+// LintChanges lints content and keeps the issues on its changed lines.
+//
+// Description:
+//
+//	Linters need the whole file for context (imports, types), so the
+//	full content is linted as with LintContentAt. Issues are then kept
+//	only if they fall within a changed range widened by
+//	opts.ContextLines, or have no line (file-level issues). A 3-line
+//	patch to a 5K-line file is not blocked by the file's existing
+//	debt.
+//
+//	A change can cause errors elsewhere in the file, such as an unused
+//	import after removing its last use. With opts.BlockOutside, errors
+//	outside the ranges are kept when the original content does not
+//	have them, compared by rule and message since lines shift.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	original - The content before the change (empty for a new file)
+//	content - The content after the change
+//	language - The language identifier
+//	filePath - Path the content belongs at (see LintContentAt)
+//	changed - Changed line ranges in content
+//	opts - Context lines and outside-error handling
+//
+// Outputs:
+//
+//	*LintResult - The scoped result; OutOfScope counts dropped issues
+//	error - Non-nil if the linter failed
+//
+// Thread Safety: Safe for concurrent use.
+func (r *LintRunner) LintChanges(ctx context.Context, original, content []byte, language, filePath string, changed []LineRange, opts ScopeOptions) (*LintResult, error) {
+	result, err := r.LintContentAt(ctx, content, language, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !result.LinterAvailable || result.Skipped {
+		return result, nil
+	}
+
+	inScope := func(issue LintIssue) bool {
+		if issue.Line <= 0 {
+			return true
+		}
+		for _, lr := range changed {
+			if lr.Contains(issue.Line, opts.ContextLines) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var outside []LintIssue
+	result.Errors, outside = splitByScope(result.Errors, inScope)
+	if opts.BlockOutside && len(outside) > 0 {
+		fresh := r.newIssues(ctx, outside, original, language, filePath)
+		result.Errors = append(result.Errors, fresh...)
+		result.OutOfScope += len(outside) - len(fresh)
+	} else {
+		result.OutOfScope += len(outside)
+	}
+
+	var dropped int
+	result.Warnings, dropped = dropOutOfScope(result.Warnings, inScope)
+	result.OutOfScope += dropped
+	result.Infos, dropped = dropOutOfScope(result.Infos, inScope)
+	result.OutOfScope += dropped
+
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// newIssues returns the issues the original content does not have.
+// Issues are matched by rule and message, as many times as the original
+// has them. If the original cannot be linted, all issues count as new.
+func (r *LintRunner) newIssues(ctx context.Context, issues []LintIssue, original []byte, language, filePath string) []LintIssue {
+	if len(original) == 0 {
+		return issues
+	}
+	baseline, err := r.LintContentAt(ctx, original, language, filePath)
+	if err != nil {
+		slog.Warn("Linting original content failed",
+			slog.String("file", filePath),
+			slog.String("error", err.Error()),
+		)
+		return issues
+	}
+
+	existing := make(map[string]int)
+	for _, issue := range baseline.AllIssues() {
+		existing[issue.Rule+"\x00"+issue.Message]++
+	}
+	fresh := make([]LintIssue, 0, len(issues))
+	for _, issue := range issues {
+		key := issue.Rule + "\x00" + issue.Message
+		if existing[key] > 0 {
+			existing[key]--
+			continue
+		}
+		fresh = append(fresh, issue)
+	}
+	return fresh
+}
+
+// splitByScope splits issues into those in scope and those outside.
+func splitByScope(issues []LintIssue, inScope func(LintIssue) bool) (in, out []LintIssue) {
+	in = make([]LintIssue, 0, len(issues))
+	for _, issue := range issues {
+		if inScope(issue) {
+			in = append(in, issue)
+		} else {
+			out = append(out, issue)
+		}
+	}
+	return in, out
+}
+
+// dropOutOfScope keeps the issues in scope and counts the others.
+func dropOutOfScope(issues []LintIssue, inScope func(LintIssue) bool) ([]LintIssue, int) {
+	if issues == nil {
+		return nil, 0
+	}
+	in, out := splitByScope(issues, inScope)
+	return in, len(out)
+}
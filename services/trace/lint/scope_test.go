@@ -0,0 +1,124 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lint
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// newGrepRunner returns a runner whose "python" linter reports a
+// blocking F821 on every line containing "bad" and an E711 warning on
+// every line containing "meh".
+func newGrepRunner(t *testing.T) *LintRunner {
+	t.Helper()
+	script := `awk 'BEGIN { printf "["; sep = "" }
+/bad/ { printf "%s{\"code\":\"F821\",\"message\":\"undefined name\",\"filename\":\"%s\",\"location\":{\"row\":%d,\"column\":1}}", sep, FILENAME, NR; sep = "," }
+/meh/ { printf "%s{\"code\":\"E711\",\"message\":\"comparison to None\",\"filename\":\"%s\",\"location\":{\"row\":%d,\"column\":1}}", sep, FILENAME, NR; sep = "," }
+END { print "]" }' "$0"`
+	runner := NewLintRunner()
+	runner.Configs().Register(&LinterConfig{
+		Language:   "python",
+		Command:    "sh",
+		Args:       []string{"-c", script},
+		Extensions: []string{".py"},
+	})
+	runner.DetectAvailableLinters()
+	return runner
+}
+
+func TestLintRunner_LintChanges(t *testing.T) {
+	runner := newGrepRunner(t)
+	ctx := context.Background()
+
+	// Line 1 has old debt; the change is line 5, which adds a warning.
+	lines := []string{"bad = 1", "a = 1", "b = 2", "c = 3", "meh = 4", "d = 5", "e = 6", "f = 7"}
+	original := []byte(strings.Join(append(lines[:4:4], lines[5:]...), "\n") + "\n")
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	changed := []LineRange{{Start: 5, End: 5}}
+
+	t.Run("whole file", func(t *testing.T) {
+		result, err := runner.LintContentAt(ctx, content, "python", "app.py")
+		if err != nil {
+			t.Fatalf("LintContentAt: %v", err)
+		}
+		if len(result.Errors) != 1 || len(result.Warnings) != 1 {
+			t.Errorf("got %d errors and %d warnings, want 1 and 1", len(result.Errors), len(result.Warnings))
+		}
+	})
+
+	t.Run("changed lines only", func(t *testing.T) {
+		result, err := runner.LintChanges(ctx, original, content, "python", "app.py", changed, ScopeOptions{})
+		if err != nil {
+			t.Fatalf("LintChanges: %v", err)
+		}
+		if !result.Valid || len(result.Errors) != 0 {
+			t.Errorf("Errors = %v, want the old error on line 1 dropped", result.Errors)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Line != 5 {
+			t.Errorf("Warnings = %v, want the one on line 5", result.Warnings)
+		}
+		if result.OutOfScope != 1 {
+			t.Errorf("OutOfScope = %d, want 1", result.OutOfScope)
+		}
+	})
+
+	t.Run("context lines", func(t *testing.T) {
+		result, err := runner.LintChanges(ctx, original, content, "python", "app.py", changed, ScopeOptions{ContextLines: 4})
+		if err != nil {
+			t.Fatalf("LintChanges: %v", err)
+		}
+		if len(result.Errors) != 1 || result.OutOfScope != 0 {
+			t.Errorf("got %d errors and %d out of scope, want line 1 in context", len(result.Errors), result.OutOfScope)
+		}
+	})
+
+	t.Run("block outside keeps only new errors", func(t *testing.T) {
+		result, err := runner.LintChanges(ctx, original, content, "python", "app.py", changed, ScopeOptions{BlockOutside: true})
+		if err != nil {
+			t.Fatalf("LintChanges: %v", err)
+		}
+		if len(result.Errors) != 0 || result.OutOfScope != 1 {
+			t.Errorf("got %d errors and %d out of scope, want the old error dropped", len(result.Errors), result.OutOfScope)
+		}
+
+		// A second "bad" line the change did not touch but the original lacks
+		withNew := []byte(strings.Replace(string(content), "f = 7", "bad = 7", 1))
+		result, err = runner.LintChanges(ctx, original, withNew, "python", "app.py", changed, ScopeOptions{BlockOutside: true})
+		if err != nil {
+			t.Fatalf("LintChanges: %v", err)
+		}
+		if result.Valid || len(result.Errors) != 1 || result.Errors[0].Line != 8 {
+			t.Errorf("Errors = %v, want the new error on line 8", result.Errors)
+		}
+	})
+}
+
+func TestLineRange_Contains(t *testing.T) {
+	lr := LineRange{Start: 10, End: 12}
+	tests := []struct {
+		line, margin int
+		want         bool
+	}{
+		{10, 0, true},
+		{12, 0, true},
+		{9, 0, false},
+		{13, 0, false},
+		{7, 3, true},
+		{16, 3, false},
+	}
+	for _, tt := range tests {
+		if got := lr.Contains(tt.line, tt.margin); got != tt.want {
+			t.Errorf("Contains(%d, %d) = %v, want %v", tt.line, tt.margin, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,8 @@ package lint
 
 import (
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // =============================================================================
@@ -107,6 +109,28 @@ type LinterConfig struct {
 	// FixArgs are arguments for running the linter in fix mode.
 	// Empty if the linter doesn't support auto-fix.
 	FixArgs []string
+
+	// ProjectFile marks linters that check a whole project rather than
+	// one file (e.g., "Cargo.toml" for cargo clippy). The linter runs in
+	// the outermost ancestor directory of the file holding ProjectFile,
+	// without the file path as an argument, and only issues in the file
+	// are kept. Empty for per-file linters.
+	ProjectFile string
+
+	// ProbeCommand is the binary DetectAvailableLinters looks for, if it
+	// differs from Command (e.g., "cargo-clippy" for "cargo clippy").
+	ProbeCommand string
+
+	// ConfigFiles are the names of the linter's project config files, in
+	// the order the linter prefers them (e.g., ".golangci.yml"). See
+	// LintConfigResolver.
+	ConfigFiles []string
+
+	// ConfigFlag passes a config file to the linter (e.g., "--config").
+	// Used when content is linted away from its real location, where the
+	// linter cannot find the project config itself. Empty if the linter
+	// takes no such flag.
+	ConfigFlag string
 }
 
 // Clone returns a deep copy of the config.
@@ -120,10 +144,15 @@ func (c *LinterConfig) Clone() *LinterConfig {
 		Available:     c.Available,
 		SupportsStdin: c.SupportsStdin,
 		FixArgs:       make([]string, len(c.FixArgs)),
+		ProjectFile:   c.ProjectFile,
+		ProbeCommand:  c.ProbeCommand,
+		ConfigFiles:   make([]string, len(c.ConfigFiles)),
+		ConfigFlag:    c.ConfigFlag,
 	}
 	copy(clone.Args, c.Args)
 	copy(clone.Extensions, c.Extensions)
 	copy(clone.FixArgs, c.FixArgs)
+	copy(clone.ConfigFiles, c.ConfigFiles)
 	return clone
 }
 
@@ -162,6 +191,20 @@ type LintResult struct {
 	// LinterAvailable indicates whether the linter was found.
 	// When false, the result may be empty due to unavailable linter.
 	LinterAvailable bool `json:"linter_available"`
+
+	// Origin classifies the file as source, generated, or vendored.
+	Origin origin.Kind `json:"origin,omitempty"`
+
+	// Skipped is true when the origin filter excluded the file.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// OutOfScope counts issues LintChanges dropped because they are
+	// outside the changed lines.
+	OutOfScope int `json:"out_of_scope,omitempty"`
+
+	// ConfigFile is the project lint config in effect for the file, or
+	// empty if the linter ran with its defaults.
+	ConfigFile string `json:"config_file,omitempty"`
 }
 
 // HasErrors returns true if there are any blocking errors.
@@ -305,6 +348,67 @@ type TextEdit struct {
 	NewText string `json:"new_text"`
 }
 
+// =============================================================================
+// FIX RESULT
+// =============================================================================
+
+// FixResult is the outcome of applying a linter's auto-fixes in a sandbox.
+//
+// Thread Safety: Immutable after creation by the runner.
+type FixResult struct {
+	// FilePath is the fixed file, as given to Fix or FixContentAt.
+	FilePath string `json:"file_path"`
+
+	// Language is the language that was fixed.
+	Language string `json:"language"`
+
+	// Fixed is the content with auto-fixes applied.
+	Fixed []byte `json:"-"`
+
+	// Diff is a unified diff from the original content to Fixed.
+	// Empty if the linter changed nothing.
+	Diff string `json:"diff,omitempty"`
+
+	// Remaining holds the issues left after fixing.
+	Remaining *LintResult `json:"remaining"`
+}
+
+// Changed returns true if any auto-fix was applied.
+func (r *FixResult) Changed() bool {
+	return r.Diff != ""
+}
+
+// =============================================================================
+// CHANGE SCOPE
+// =============================================================================
+
+// LineRange is an inclusive range of 1-based line numbers.
+type LineRange struct {
+	// Start is the first line of the range.
+	Start int `json:"start"`
+
+	// End is the last line of the range.
+	End int `json:"end"`
+}
+
+// Contains returns true if line is within the range widened by margin
+// lines on both sides.
+func (lr LineRange) Contains(line, margin int) bool {
+	return line >= lr.Start-margin && line <= lr.End+margin
+}
+
+// ScopeOptions configures LintChanges.
+type ScopeOptions struct {
+	// ContextLines widens each changed range on both sides. Issues
+	// within the widened ranges are kept.
+	ContextLines int
+
+	// BlockOutside keeps blocking errors outside the changed ranges if
+	// they are new, i.e. not reported for the original content. This
+	// costs a second lint run, of the original content.
+	BlockOutside bool
+}
+
 // =============================================================================
 // LINT OPTIONS
 // =============================================================================
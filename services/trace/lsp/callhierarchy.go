@@ -0,0 +1,237 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// CALL HIERARCHY OPERATIONS
+// =============================================================================
+
+// PrepareCallHierarchy returns the call hierarchy items at a position.
+//
+// Description:
+//
+//	Sends a textDocument/prepareCallHierarchy request. The items identify
+//	the function or method at the position for later incoming and
+//	outgoing calls requests. Usually there is at most one item.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//	line - 1-indexed line number
+//	col - 0-indexed column number
+//
+// Outputs:
+//
+//	[]CallHierarchyItem - Items at the position, may be empty
+//	error - Non-nil on failure
+//
+// Errors:
+//
+//	ErrNotSupported - The server does not provide call hierarchy
+func (o *Operations) PrepareCallHierarchy(ctx context.Context, filePath string, line, col int) ([]CallHierarchyItem, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+
+	language := o.languageFromPath(filePath)
+	if language == "" {
+		return nil, fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
+	}
+
+	// Start tracing span
+	ctx, span := startOperationSpan(ctx, "PrepareCallHierarchy", language, filePath)
+	defer span.End()
+	start := time.Now()
+
+	items, err := o.prepareCallHierarchy(ctx, language, filePath, line, col)
+	if err != nil {
+		setOperationSpanResult(span, 0, false)
+		recordOperationMetrics(ctx, "prepare_call_hierarchy", language, time.Since(start), 0, false)
+		return nil, err
+	}
+
+	setOperationSpanResult(span, len(items), true)
+	recordOperationMetrics(ctx, "prepare_call_hierarchy", language, time.Since(start), len(items), true)
+	return items, nil
+}
+
+// IncomingCalls returns the callers of the function at a position.
+//
+// Description:
+//
+//	Prepares the call hierarchy at the position, then sends a
+//	callHierarchy/incomingCalls request for each item. Unlike References,
+//	the result only contains calls, each grouped under the function or
+//	method that makes it, and resolves calls through interfaces and
+//	function values as far as the server does.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//	line - 1-indexed line number
+//	col - 0-indexed column number, on the function's name
+//
+// Outputs:
+//
+//	[]CallHierarchyIncomingCall - One entry per caller, may be empty
+//	error - Non-nil on failure
+//
+// Errors:
+//
+//	ErrNotSupported - The server does not provide call hierarchy
+//
+// Example:
+//
+//	calls, err := ops.IncomingCalls(ctx, "/project/config.go", 42, 5)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, call := range calls {
+//	    fmt.Printf("%s calls it %d times\n", call.From.Name, len(call.FromRanges))
+//	}
+func (o *Operations) IncomingCalls(ctx context.Context, filePath string, line, col int) ([]CallHierarchyIncomingCall, error) {
+	return callHierarchyCalls[CallHierarchyIncomingCall](ctx, o, "IncomingCalls", "incoming_calls",
+		"callHierarchy/incomingCalls", filePath, line, col)
+}
+
+// OutgoingCalls returns the functions called by the function at a position.
+//
+// Description:
+//
+//	Prepares the call hierarchy at the position, then sends a
+//	callHierarchy/outgoingCalls request for each item.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//	line - 1-indexed line number
+//	col - 0-indexed column number, on the function's name
+//
+// Outputs:
+//
+//	[]CallHierarchyOutgoingCall - One entry per callee, may be empty
+//	error - Non-nil on failure
+//
+// Errors:
+//
+//	ErrNotSupported - The server does not provide call hierarchy
+func (o *Operations) OutgoingCalls(ctx context.Context, filePath string, line, col int) ([]CallHierarchyOutgoingCall, error) {
+	return callHierarchyCalls[CallHierarchyOutgoingCall](ctx, o, "OutgoingCalls", "outgoing_calls",
+		"callHierarchy/outgoingCalls", filePath, line, col)
+}
+
+// callHierarchyCalls prepares the items at a position and sends method
+// for each, collecting the calls.
+func callHierarchyCalls[T any](
+	ctx context.Context,
+	o *Operations,
+	operation, metricName, method, filePath string,
+	line, col int,
+) ([]T, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+
+	language := o.languageFromPath(filePath)
+	if language == "" {
+		return nil, fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
+	}
+
+	// Start tracing span
+	ctx, span := startOperationSpan(ctx, operation, language, filePath)
+	defer span.End()
+	start := time.Now()
+
+	fail := func(err error) ([]T, error) {
+		setOperationSpanResult(span, 0, false)
+		recordOperationMetrics(ctx, metricName, language, time.Since(start), 0, false)
+		return nil, err
+	}
+
+	items, err := o.prepareCallHierarchy(ctx, language, filePath, line, col)
+	if err != nil {
+		return fail(err)
+	}
+
+	var calls []T
+	for _, item := range items {
+		// Both calls requests take the item as their only param.
+		params := CallHierarchyIncomingCallsParams{Item: item}
+
+		// Use retry for this idempotent operation
+		resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+			return server.Request(ctx, method, params)
+		})
+		if err != nil {
+			return fail(fmt.Errorf("%s request: %w", method, err))
+		}
+
+		var result []T
+		if err := unmarshalOptional(resp.Result, &result); err != nil {
+			return fail(fmt.Errorf("parse %s: %w", method, err))
+		}
+		calls = append(calls, result...)
+	}
+
+	setOperationSpanResult(span, len(calls), true)
+	recordOperationMetrics(ctx, metricName, language, time.Since(start), len(calls), true)
+	return calls, nil
+}
+
+// prepareCallHierarchy sends textDocument/prepareCallHierarchy.
+func (o *Operations) prepareCallHierarchy(ctx context.Context, language, filePath string, line, col int) ([]CallHierarchyItem, error) {
+	server, err := o.manager.GetOrSpawn(ctx, language)
+	if err != nil {
+		return nil, fmt.Errorf("get server: %w", err)
+	}
+	caps := server.Capabilities()
+	if !caps.HasCallHierarchyProvider() {
+		return nil, fmt.Errorf("%w: call hierarchy for %s", ErrNotSupported, language)
+	}
+
+	params := TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: pathToURI(filePath)},
+		Position:     Position{Line: line - 1, Character: col},
+	}
+
+	// Use retry for this idempotent operation
+	resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+		return server.Request(ctx, "textDocument/prepareCallHierarchy", params)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prepare call hierarchy request: %w", err)
+	}
+
+	var items []CallHierarchyItem
+	if err := unmarshalOptional(resp.Result, &items); err != nil {
+		return nil, fmt.Errorf("parse call hierarchy items: %w", err)
+	}
+	return items, nil
+}
+
+// unmarshalOptional decodes a result that may be empty or null into v.
+func unmarshalOptional(result json.RawMessage, v interface{}) error {
+	if len(result) == 0 || string(result) == "null" {
+		return nil
+	}
+	return json.Unmarshal(result, v)
+}
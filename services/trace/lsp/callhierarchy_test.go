@@ -0,0 +1,105 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerCapabilities_HasCallHierarchyProvider(t *testing.T) {
+	for _, tc := range []struct {
+		provider interface{}
+		want     bool
+	}{
+		{nil, false},
+		{false, false},
+		{true, true},
+		{map[string]interface{}{"workDoneProgress": true}, true},
+	} {
+		caps := ServerCapabilities{CallHierarchyProvider: tc.provider}
+		if got := caps.HasCallHierarchyProvider(); got != tc.want {
+			t.Errorf("HasCallHierarchyProvider() with %v = %v, want %v", tc.provider, got, tc.want)
+		}
+	}
+}
+
+func TestOperations_CallHierarchy_RequiresContext(t *testing.T) {
+	ops := NewOperations(NewManager(t.TempDir(), DefaultManagerConfig()))
+	//nolint:staticcheck // testing nil context handling
+	if _, err := ops.IncomingCalls(nil, "/project/main.go", 1, 0); err == nil {
+		t.Error("IncomingCalls(nil) should fail")
+	}
+	//nolint:staticcheck // testing nil context handling
+	if _, err := ops.OutgoingCalls(nil, "/project/main.go", 1, 0); err == nil {
+		t.Error("OutgoingCalls(nil) should fail")
+	}
+}
+
+func TestOperations_CallHierarchy(t *testing.T) {
+	ops := newFakeOperations(t, fakeModePull)
+	path := writeFakeFile(t, ops, "a.fake", "func main\n\trun()\n\thelper()\nfunc run\n\thelper()\n\thelper()\nfunc helper\n")
+	writeFakeFile(t, ops, "b.fake", "func other\n\thelper()\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("prepare", func(t *testing.T) {
+		items, err := ops.PrepareCallHierarchy(ctx, path, 7, 5)
+		if err != nil {
+			t.Fatalf("PrepareCallHierarchy: %v", err)
+		}
+		if len(items) != 1 || items[0].Name != "helper" || items[0].Path() != path {
+			t.Errorf("items = %+v, want helper in %s", items, path)
+		}
+	})
+
+	t.Run("incoming", func(t *testing.T) {
+		calls, err := ops.IncomingCalls(ctx, path, 7, 5)
+		if err != nil {
+			t.Fatalf("IncomingCalls: %v", err)
+		}
+		var got []string
+		for _, call := range calls {
+			got = append(got, fmt.Sprintf("%s/%s:%d", call.From.Name, filepath.Base(call.From.Path()), len(call.FromRanges)))
+		}
+		sort.Strings(got)
+		if want := "main/a.fake:1 other/b.fake:1 run/a.fake:2"; strings.Join(got, " ") != want {
+			t.Errorf("incoming calls = %s, want %s", strings.Join(got, " "), want)
+		}
+	})
+
+	t.Run("outgoing", func(t *testing.T) {
+		calls, err := ops.OutgoingCalls(ctx, path, 1, 5)
+		if err != nil {
+			t.Fatalf("OutgoingCalls: %v", err)
+		}
+		var got []string
+		for _, call := range calls {
+			got = append(got, call.To.Name)
+		}
+		if want := "run helper"; strings.Join(got, " ") != want {
+			t.Errorf("outgoing calls = %s, want %s", strings.Join(got, " "), want)
+		}
+	})
+
+	t.Run("not a function", func(t *testing.T) {
+		calls, err := ops.IncomingCalls(ctx, path, 2, 1)
+		if err != nil || len(calls) != 0 {
+			t.Errorf("IncomingCalls on a call site = %v, %v; want no calls", calls, err)
+		}
+	})
+}
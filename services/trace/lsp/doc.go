@@ -33,6 +33,43 @@
 //   - Protocol: Handles JSON-RPC communication
 //   - Operations: Provides high-level LSP operations (definition, references, etc.)
 //
+// # Diagnostics
+//
+// Operations.Diagnostics pulls diagnostics with textDocument/diagnostic when
+// the server supports it. Otherwise it returns the diagnostics the server last
+// pushed with textDocument/publishDiagnostics, which each Server records.
+//
+// # Crash Recovery
+//
+// A server whose process exits or breaks the protocol is marked crashed and its
+// in-flight requests fail with ErrServerCrashed. The Manager restarts it with
+// exponential backoff (ManagerConfig.RestartBackoff, doubling up to
+// MaxRestartBackoff), reopens the documents opened through Operations, and gives
+// up after MaxRestarts crashes in a row. Idempotent operations replay once on
+// the restarted server. Restarts are counted by lsp_server_restarts_total.
+//
+// # Symbol Search
+//
+// Operations.WorkspaceSymbols queries the servers of several languages at once
+// and returns ranked, paginated matches: exact names first, then prefix, word
+// and substring matches, then the server's fuzzy matches.
+//
+// # Semantic Tokens
+//
+// Operations.SemanticTokens, SemanticTokensRange and SemanticTokenAt decode
+// the server's token data into SemanticToken values with named types and
+// modifiers. SemanticTokenType.Category groups types into types, functions
+// and variables, so a claim such as "Foo is a function" can be checked
+// against the identifier's token at its declaration.
+//
+// # Call Hierarchy
+//
+// Operations.IncomingCalls and OutgoingCalls prepare the call hierarchy at a
+// function's name and return its callers or callees, grouped by function with
+// the call sites. The service answers caller queries with IncomingCalls when
+// a server is installed for the function's language, and from the graph
+// otherwise.
+//
 // # Thread Safety
 //
 // All exported types are safe for concurrent use.
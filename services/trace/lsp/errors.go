@@ -40,6 +40,9 @@ var (
 
 	// ErrServerAlreadyStarted indicates Start was called on an already running server.
 	ErrServerAlreadyStarted = errors.New("server already started")
+
+	// ErrNotSupported indicates the LSP server does not provide the requested feature.
+	ErrNotSupported = errors.New("lsp feature not supported by server")
 )
 
 // LSPError represents an error returned by the language server via JSON-RPC.
@@ -0,0 +1,420 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeServerEnv makes the test binary act as a language server. Its value
+// selects the server's behavior; see runFakeServer.
+const fakeServerEnv = "ALEUTIAN_FAKE_LSP_SERVER"
+
+// Fake server modes.
+const (
+	// fakeModePull advertises pull diagnostics and never publishes.
+	fakeModePull = "pull"
+
+	// fakeModePush publishes diagnostics on didOpen and has no pull support.
+	fakeModePush = "push"
+)
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakeServerEnv); mode != "" {
+		runFakeServer(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeCrashMarker is a file in the workspace root that makes the fake
+// server delete it and exit on the next hover request.
+const fakeCrashMarker = "crash-once"
+
+// newFakeOperations returns Operations backed by the fake server in mode.
+// The languages "fake" and "fake2" handle the .fake and .fake2 extensions,
+// each with its own fake server process.
+func newFakeOperations(t *testing.T, mode string) *Operations {
+	t.Helper()
+	return newFakeOperationsWithConfig(t, mode, DefaultManagerConfig())
+}
+
+// newFakeOperationsWithConfig is newFakeOperations with a manager config.
+func newFakeOperationsWithConfig(t *testing.T, mode string, config ManagerConfig) *Operations {
+	t.Helper()
+	t.Setenv(fakeServerEnv, mode)
+
+	mgr := NewManager(t.TempDir(), config)
+	for _, language := range []string{"fake", "fake2"} {
+		mgr.Configs().Register(LanguageConfig{
+			Language:   language,
+			Command:    os.Args[0],
+			Extensions: []string{"." + language},
+		})
+	}
+	t.Cleanup(func() { _ = mgr.ShutdownAll(context.Background()) })
+	return NewOperations(mgr)
+}
+
+// writeFakeFile writes content to name in the workspace root and opens it.
+func writeFakeFile(t *testing.T, ops *Operations, name, content string) string {
+	t.Helper()
+	path := filepath.Join(ops.Manager().RootPath(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ops.OpenDocument(context.Background(), path, content); err != nil {
+		t.Fatalf("OpenDocument: %v", err)
+	}
+	return path
+}
+
+// runFakeServer serves LSP over stdin and stdout until exit.
+//
+// Every line of an open document containing "undefined" gets an error
+// diagnostic. Lines starting with "func " or "type " declare a function
+// or struct named by the next word, and "var " a variable; each name is
+// also a semantic token. Only pull mode supports range semantic tokens.
+// A function's body is the lines up to the next declaration, and "name("
+// in a body calls the function name. Hover returns the hovered line of an
+// open document, and "fake/hang" never gets a response.
+func runFakeServer(mode string) {
+	p := NewProtocol(os.Stdin, os.Stdout)
+	docs := make(map[string]string)
+
+	reply := func(id int64, result interface{}) {
+		data, _ := json.Marshal(result)
+		_ = p.writeMessage(Response{JSONRPC: JSONRPCVersion, ID: id, Result: data})
+	}
+	fail := func(id int64, code int, message string) {
+		_ = p.writeMessage(Response{JSONRPC: JSONRPCVersion, ID: id, Error: &ResponseError{Code: code, Message: message}})
+	}
+
+	for {
+		raw, err := p.readMessage()
+		if err != nil {
+			return
+		}
+		var msg struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return
+		}
+
+		switch msg.Method {
+		case "initialize":
+			caps := map[string]interface{}{
+				"textDocumentSync":      1,
+				"callHierarchyProvider": true,
+				"semanticTokensProvider": map[string]interface{}{
+					"legend": fakeSemanticLegend,
+					"full":   true,
+					"range":  mode == fakeModePull,
+				},
+			}
+			if mode == fakeModePull {
+				caps["diagnosticProvider"] = map[string]interface{}{"interFileDependencies": false}
+			}
+			reply(msg.ID, map[string]interface{}{"capabilities": caps})
+
+		case "textDocument/didOpen":
+			var params DidOpenTextDocumentParams
+			_ = json.Unmarshal(msg.Params, &params)
+			docs[params.TextDocument.URI] = params.TextDocument.Text
+			if mode == fakeModePush {
+				_ = p.SendNotification("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+					URI:         params.TextDocument.URI,
+					Diagnostics: fakeDiagnostics(params.TextDocument.Text),
+				})
+			}
+
+		case "textDocument/didClose":
+			var params DidCloseTextDocumentParams
+			_ = json.Unmarshal(msg.Params, &params)
+			delete(docs, params.TextDocument.URI)
+
+		case "textDocument/diagnostic":
+			if mode != fakeModePull {
+				fail(msg.ID, -32601, "method not found")
+				continue
+			}
+			var params DocumentDiagnosticParams
+			_ = json.Unmarshal(msg.Params, &params)
+			reply(msg.ID, DocumentDiagnosticReport{
+				Kind:  DocumentDiagnosticReportFull,
+				Items: fakeDiagnostics(docs[params.TextDocument.URI]),
+			})
+
+		case "workspace/symbol":
+			var params WorkspaceSymbolParams
+			_ = json.Unmarshal(msg.Params, &params)
+			symbols := []SymbolInformation{}
+			for uri, text := range docs {
+				for _, sym := range fakeSymbols(uri, text) {
+					if fakeFuzzyMatch(sym.Name, params.Query) {
+						symbols = append(symbols, sym)
+					}
+				}
+			}
+			reply(msg.ID, symbols)
+
+		case "textDocument/hover":
+			if os.Remove(fakeCrashMarker) == nil {
+				os.Exit(1)
+			}
+			var params TextDocumentPositionParams
+			_ = json.Unmarshal(msg.Params, &params)
+			text, ok := docs[params.TextDocument.URI]
+			lines := strings.Split(text, "\n")
+			if !ok || params.Position.Line >= len(lines) {
+				reply(msg.ID, nil)
+				continue
+			}
+			reply(msg.ID, HoverResult{Contents: MarkupContent{Kind: "plaintext", Value: lines[params.Position.Line]}})
+
+		case "textDocument/semanticTokens/full":
+			var params SemanticTokensParams
+			_ = json.Unmarshal(msg.Params, &params)
+			reply(msg.ID, SemanticTokens{Data: fakeSemanticTokens(docs[params.TextDocument.URI], 0, -1)})
+
+		case "textDocument/semanticTokens/range":
+			var params SemanticTokensRangeParams
+			_ = json.Unmarshal(msg.Params, &params)
+			reply(msg.ID, SemanticTokens{Data: fakeSemanticTokens(docs[params.TextDocument.URI],
+				params.Range.Start.Line, params.Range.End.Line)})
+
+		case "textDocument/prepareCallHierarchy":
+			var params TextDocumentPositionParams
+			_ = json.Unmarshal(msg.Params, &params)
+			var items []CallHierarchyItem
+			for _, fn := range fakeFunctions(params.TextDocument.URI, docs[params.TextDocument.URI]) {
+				sel := fn.item.SelectionRange
+				if sel.Start.Line == params.Position.Line &&
+					params.Position.Character >= sel.Start.Character && params.Position.Character < sel.End.Character {
+					items = append(items, fn.item)
+				}
+			}
+			reply(msg.ID, items)
+
+		case "callHierarchy/incomingCalls":
+			var params CallHierarchyIncomingCallsParams
+			_ = json.Unmarshal(msg.Params, &params)
+			calls := []CallHierarchyIncomingCall{}
+			for uri, text := range docs {
+				for _, fn := range fakeFunctions(uri, text) {
+					if ranges := fn.calls[params.Item.Name]; len(ranges) > 0 {
+						calls = append(calls, CallHierarchyIncomingCall{From: fn.item, FromRanges: ranges})
+					}
+				}
+			}
+			reply(msg.ID, calls)
+
+		case "callHierarchy/outgoingCalls":
+			var params CallHierarchyOutgoingCallsParams
+			_ = json.Unmarshal(msg.Params, &params)
+			calls := []CallHierarchyOutgoingCall{}
+			for _, caller := range fakeFunctions(params.Item.URI, docs[params.Item.URI]) {
+				if caller.item.Name != params.Item.Name {
+					continue
+				}
+				for uri, text := range docs {
+					for _, callee := range fakeFunctions(uri, text) {
+						if ranges := caller.calls[callee.item.Name]; len(ranges) > 0 {
+							calls = append(calls, CallHierarchyOutgoingCall{To: callee.item, FromRanges: ranges})
+						}
+					}
+				}
+			}
+			reply(msg.ID, calls)
+
+		case "fake/hang":
+
+		case "shutdown":
+			reply(msg.ID, nil)
+
+		case "exit":
+			return
+
+		default:
+			if msg.ID != 0 {
+				fail(msg.ID, -32601, "method not found: "+msg.Method)
+			}
+		}
+	}
+}
+
+// fakeDiagnostics reports an error on every line containing "undefined".
+func fakeDiagnostics(text string) []Diagnostic {
+	diags := []Diagnostic{}
+	for i, line := range strings.Split(text, "\n") {
+		col := strings.Index(line, "undefined")
+		if col < 0 {
+			continue
+		}
+		diags = append(diags, Diagnostic{
+			Range: Range{
+				Start: Position{Line: i, Character: col},
+				End:   Position{Line: i, Character: len(line)},
+			},
+			Severity: DiagnosticSeverityError,
+			Source:   "fake",
+			Message:  strings.TrimSpace(line[col:]),
+		})
+	}
+	return diags
+}
+
+// fakeSymbols returns the functions and types declared in text.
+func fakeSymbols(uri, text string) []SymbolInformation {
+	var symbols []SymbolInformation
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kind := SymbolKindFunction
+		switch fields[0] {
+		case "func":
+		case "type":
+			kind = SymbolKindStruct
+		case "var":
+			kind = SymbolKindVariable
+		default:
+			continue
+		}
+		col := strings.Index(line, fields[1])
+		symbols = append(symbols, SymbolInformation{
+			Name: fields[1],
+			Kind: kind,
+			Location: Location{URI: uri, Range: Range{
+				Start: Position{Line: i, Character: col},
+				End:   Position{Line: i, Character: col + len(fields[1])},
+			}},
+		})
+	}
+	return symbols
+}
+
+// fakeFuzzyMatch reports whether query is a case-insensitive subsequence
+// of name, as many servers match workspace symbols.
+func fakeFuzzyMatch(name, query string) bool {
+	name, query = strings.ToLower(name), strings.ToLower(query)
+	for _, r := range query {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+1:]
+	}
+	return true
+}
+
+// fakeSemanticLegend is the fake server's semantic tokens legend.
+var fakeSemanticLegend = SemanticTokensLegend{
+	TokenTypes:     []string{"namespace", "function", "struct", "variable"},
+	TokenModifiers: []string{"declaration", "readonly"},
+}
+
+// fakeSemanticTokens encodes the names declared on lines start up to, but
+// not including, end as semantic tokens. A negative end means all lines.
+func fakeSemanticTokens(text string, start, end int) []uint32 {
+	data := []uint32{}
+	prevLine, prevChar := 0, 0
+	for _, sym := range fakeSymbols("", text) {
+		pos := sym.Location.Range.Start
+		if pos.Line < start || (end >= 0 && pos.Line >= end) {
+			continue
+		}
+		tokenType, modifiers := uint32(1), uint32(1)
+		switch sym.Kind {
+		case SymbolKindStruct:
+			tokenType = 2
+		case SymbolKindVariable:
+			tokenType, modifiers = 3, 3
+		}
+		deltaChar := pos.Character
+		if pos.Line == prevLine {
+			deltaChar -= prevChar
+		}
+		data = append(data, uint32(pos.Line-prevLine), uint32(deltaChar), uint32(len(sym.Name)), tokenType, modifiers)
+		prevLine, prevChar = pos.Line, pos.Character
+	}
+	return data
+}
+
+// fakeFunction is a function declared in a fake document.
+type fakeFunction struct {
+	item CallHierarchyItem
+
+	// calls maps each name called in the body to its call sites.
+	calls map[string][]Range
+}
+
+// fakeFunctions returns the functions declared in text and their calls.
+func fakeFunctions(uri, text string) []fakeFunction {
+	lines := strings.Split(text, "\n")
+	var functions []fakeFunction
+	symbols := fakeSymbols(uri, text)
+	for i, sym := range symbols {
+		if sym.Kind != SymbolKindFunction {
+			continue
+		}
+		end := len(lines)
+		if i+1 < len(symbols) {
+			end = symbols[i+1].Location.Range.Start.Line
+		}
+		start := sym.Location.Range.Start.Line
+		fn := fakeFunction{
+			item: CallHierarchyItem{
+				Name: sym.Name,
+				Kind: SymbolKindFunction,
+				URI:  uri,
+				Range: Range{
+					Start: Position{Line: start},
+					End:   Position{Line: end},
+				},
+				SelectionRange: sym.Location.Range,
+			},
+			calls: make(map[string][]Range),
+		}
+		for line := start + 1; line < end; line++ {
+			text := lines[line]
+			for col := 0; col < len(text); {
+				open := strings.IndexByte(text[col:], '(')
+				if open < 0 {
+					break
+				}
+				open += col
+				nameStart := open
+				for nameStart > 0 && text[nameStart-1] != ' ' && text[nameStart-1] != '\t' && text[nameStart-1] != '(' {
+					nameStart--
+				}
+				if name := text[nameStart:open]; name != "" {
+					fn.calls[name] = append(fn.calls[name], Range{
+						Start: Position{Line: line, Character: nameStart},
+						End:   Position{Line: line, Character: open},
+					})
+				}
+				col = open + 1
+			}
+		}
+		functions = append(functions, fn)
+	}
+	return functions
+}
@@ -12,6 +12,7 @@ package lsp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os/exec"
@@ -34,6 +35,18 @@ type ManagerConfig struct {
 
 	// RequestTimeout is the default timeout for LSP requests.
 	RequestTimeout time.Duration
+
+	// MaxRestarts is how many crashes in a row a language's server is
+	// restarted after before the manager gives up on it. Set to 0 to
+	// disable automatic restart.
+	MaxRestarts int
+
+	// RestartBackoff is the delay before restarting after a first crash.
+	// It doubles with each further crash in a row.
+	RestartBackoff time.Duration
+
+	// MaxRestartBackoff caps the delay between restarts.
+	MaxRestartBackoff time.Duration
 }
 
 // DefaultManagerConfig returns sensible defaults for the manager.
@@ -44,14 +57,24 @@ type ManagerConfig struct {
 //	  - IdleTimeout: 10 minutes
 //	  - StartupTimeout: 30 seconds
 //	  - RequestTimeout: 10 seconds
+//	  - MaxRestarts: 5
+//	  - RestartBackoff: 500 milliseconds
+//	  - MaxRestartBackoff: 30 seconds
 func DefaultManagerConfig() ManagerConfig {
 	return ManagerConfig{
-		IdleTimeout:    10 * time.Minute,
-		StartupTimeout: 30 * time.Second,
-		RequestTimeout: 10 * time.Second,
+		IdleTimeout:       10 * time.Minute,
+		StartupTimeout:    30 * time.Second,
+		RequestTimeout:    10 * time.Second,
+		MaxRestarts:       5,
+		RestartBackoff:    500 * time.Millisecond,
+		MaxRestartBackoff: 30 * time.Second,
 	}
 }
 
+// restartStableAfter is how long a server must run before a crash no
+// longer counts toward MaxRestarts.
+const restartStableAfter = time.Minute
+
 // =============================================================================
 // MANAGER
 // =============================================================================
@@ -62,7 +85,9 @@ func DefaultManagerConfig() ManagerConfig {
 //
 //	Provides lazy startup of language servers as needed, with idle
 //	timeout and graceful shutdown. Each language has at most one
-//	server instance per workspace.
+//	server instance per workspace. Crashed servers are restarted with
+//	exponential backoff, and documents opened through Operations are
+//	reopened in the new server.
 //
 // Thread Safety:
 //
@@ -78,6 +103,27 @@ type Manager struct {
 
 	stopped  chan struct{}
 	stopOnce sync.Once
+
+	restarts   map[string]*restartState // language → crash history
+	restartsMu sync.Mutex
+
+	documents   map[string]map[string]TextDocumentItem // language → URI → open document
+	documentsMu sync.Mutex
+}
+
+// restartState tracks the crashes of one language's server.
+type restartState struct {
+	// crashes is the number of crashes in a row.
+	crashes int
+
+	// notBefore is when the server may be started again.
+	notBefore time.Time
+
+	// pending is true until a server starts after the last crash.
+	pending bool
+
+	// gaveUp is true once crashes exceeded MaxRestarts.
+	gaveUp bool
 }
 
 // NewManager creates a new LSP manager.
@@ -103,6 +149,9 @@ func NewManager(rootPath string, config ManagerConfig) *Manager {
 		configs:  NewConfigRegistry(),
 		servers:  make(map[string]*Server),
 		stopped:  make(chan struct{}),
+
+		restarts:  make(map[string]*restartState),
+		documents: make(map[string]map[string]TextDocumentItem),
 	}
 }
 
@@ -113,6 +162,7 @@ func NewManager(rootPath string, config ManagerConfig) *Manager {
 //	Returns an existing server if one is running and ready, otherwise
 //	starts a new server. Uses double-check locking to ensure only one
 //	server is started per language even under concurrent requests.
+//	After a crash, waits out the restart backoff before starting.
 //
 // Inputs:
 //
@@ -129,6 +179,7 @@ func NewManager(rootPath string, config ManagerConfig) *Manager {
 //	ErrUnsupportedLanguage - No configuration for the language
 //	ErrServerNotInstalled - Server binary not found
 //	ErrInitializeFailed - Server initialization failed
+//	ErrServerCrashed - The server crashed more than MaxRestarts times in a row
 //
 // Thread Safety:
 //
@@ -182,6 +233,11 @@ func (m *Manager) GetOrSpawn(ctx context.Context, language string) (*Server, err
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
 	}
 
+	restarting, err := m.waitForRestart(ctx, language)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create and start new server
 	server = NewServer(config, m.rootPath)
 
@@ -194,14 +250,29 @@ func (m *Manager) GetOrSpawn(ctx context.Context, language string) (*Server, err
 	}
 
 	if err := server.Start(startCtx); err != nil {
+		recordServerSpawn(ctx, language, false)
+		if restarting {
+			recordServerRestart(ctx, language, false)
+		}
 		return nil, err
 	}
+	recordServerSpawn(ctx, language, true)
 
 	// Store the server
 	m.serversMu.Lock()
 	m.servers[language] = server
 	m.serversMu.Unlock()
 
+	if restarting {
+		recordServerRestart(ctx, language, true)
+		m.restartDone(language)
+		m.reopenDocuments(server)
+		slog.Info("Restarted LSP server after crash",
+			slog.String("language", language),
+		)
+	}
+	go m.watch(language, server, time.Now())
+
 	return server, nil
 }
 
@@ -238,8 +309,9 @@ func (m *Manager) Get(language string) *Server {
 //
 // Description:
 //
-//	Gracefully shuts down the server for the given language.
-//	No-op if no server is running for the language.
+//	Gracefully shuts down the server for the given language and resets
+//	its crash history, so a server the manager gave up on can be started
+//	again. No-op if no server is running for the language.
 //
 // Inputs:
 //
@@ -261,6 +333,11 @@ func (m *Manager) Shutdown(ctx context.Context, language string) error {
 	}
 	m.serversMu.Unlock()
 
+	// An explicit shutdown forgets earlier crashes
+	m.restartsMu.Lock()
+	delete(m.restarts, language)
+	m.restartsMu.Unlock()
+
 	if !ok {
 		return nil
 	}
@@ -530,6 +607,203 @@ func (m *Manager) ReopenFile(ctx context.Context, filePath string, content strin
 	return lastErr
 }
 
+// =============================================================================
+// CRASH RECOVERY
+// =============================================================================
+
+// watch waits for server to stop and schedules a restart if it crashed.
+func (m *Manager) watch(language string, server *Server, started time.Time) {
+	select {
+	case <-m.stopped:
+		return
+	case <-server.Done():
+	}
+	if server.CrashErr() == nil {
+		return
+	}
+
+	m.serversMu.Lock()
+	if m.servers[language] == server {
+		delete(m.servers, language)
+	}
+	m.serversMu.Unlock()
+
+	if m.recordCrash(language, time.Since(started)) {
+		m.restart(language)
+	}
+}
+
+// restart starts language's server once its backoff has passed.
+//
+// Description:
+//
+//	Retries with growing backoff while the start fails, until it
+//	succeeds, the manager gives up on the language, or someone else
+//	started the server first.
+func (m *Manager) restart(language string) {
+	for {
+		m.restartsMu.Lock()
+		st, ok := m.restarts[language]
+		var wait time.Duration
+		if ok {
+			wait = time.Until(st.notBefore)
+		}
+		m.restartsMu.Unlock()
+		if !ok {
+			return // Shut down explicitly meanwhile
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-m.stopped:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		_, err := m.GetOrSpawn(context.Background(), language)
+		if err == nil || errors.Is(err, ErrServerCrashed) {
+			return
+		}
+		slog.Warn("Failed to restart LSP server",
+			slog.String("language", language),
+			slog.String("error", err.Error()),
+		)
+		if !m.recordCrash(language, 0) {
+			return
+		}
+	}
+}
+
+// recordCrash records a crash of language's server after it ran for
+// uptime, and sets the backoff before the next start. Returns false if
+// the server should not be restarted automatically.
+func (m *Manager) recordCrash(language string, uptime time.Duration) bool {
+	if m.config.MaxRestarts <= 0 {
+		return false
+	}
+
+	m.restartsMu.Lock()
+	defer m.restartsMu.Unlock()
+
+	st, ok := m.restarts[language]
+	if !ok || uptime >= restartStableAfter {
+		st = &restartState{}
+		m.restarts[language] = st
+	}
+	st.crashes++
+	st.pending = true
+	if st.crashes > m.config.MaxRestarts {
+		st.gaveUp = true
+		slog.Error("LSP server keeps crashing, not restarting",
+			slog.String("language", language),
+			slog.Int("crashes", st.crashes),
+		)
+		return false
+	}
+
+	backoff := m.config.RestartBackoff << (st.crashes - 1)
+	if backoff > m.config.MaxRestartBackoff || backoff < 0 {
+		backoff = m.config.MaxRestartBackoff
+	}
+	st.notBefore = time.Now().Add(backoff)
+	slog.Info("Scheduling LSP server restart",
+		slog.String("language", language),
+		slog.Int("crashes", st.crashes),
+		slog.Duration("backoff", backoff),
+	)
+	return true
+}
+
+// waitForRestart blocks until language's server may be started.
+//
+// Description:
+//
+//	Returns immediately if the server has not crashed. Otherwise waits
+//	out the restart backoff. Returns true if the start is a restart
+//	after a crash.
+func (m *Manager) waitForRestart(ctx context.Context, language string) (bool, error) {
+	m.restartsMu.Lock()
+	st, ok := m.restarts[language]
+	if !ok || !st.pending {
+		m.restartsMu.Unlock()
+		return false, nil
+	}
+	gaveUp, crashes, wait := st.gaveUp, st.crashes, time.Until(st.notBefore)
+	m.restartsMu.Unlock()
+
+	if gaveUp {
+		return false, fmt.Errorf("%w: %s crashed %d times in a row, not restarting", ErrServerCrashed, language, crashes)
+	}
+	if wait <= 0 {
+		return true, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, fmt.Errorf("%w: waiting to restart %s: %v", ErrServerCrashed, language, ctx.Err())
+	case <-m.stopped:
+		return false, fmt.Errorf("manager is stopped")
+	case <-timer.C:
+		return true, nil
+	}
+}
+
+// restartDone marks language's server as restarted.
+func (m *Manager) restartDone(language string) {
+	m.restartsMu.Lock()
+	if st, ok := m.restarts[language]; ok {
+		st.pending = false
+	}
+	m.restartsMu.Unlock()
+}
+
+// trackDocument remembers a document opened in language's server so it
+// can be reopened after a restart.
+func (m *Manager) trackDocument(language string, item TextDocumentItem) {
+	m.documentsMu.Lock()
+	defer m.documentsMu.Unlock()
+	docs, ok := m.documents[language]
+	if !ok {
+		docs = make(map[string]TextDocumentItem)
+		m.documents[language] = docs
+	}
+	docs[item.URI] = item
+}
+
+// untrackDocument forgets a document closed in language's server.
+func (m *Manager) untrackDocument(language, uri string) {
+	m.documentsMu.Lock()
+	defer m.documentsMu.Unlock()
+	delete(m.documents[language], uri)
+}
+
+// reopenDocuments sends didOpen for every tracked document of the
+// server's language.
+func (m *Manager) reopenDocuments(server *Server) {
+	m.documentsMu.Lock()
+	docs := make([]TextDocumentItem, 0, len(m.documents[server.Language()]))
+	for _, item := range m.documents[server.Language()] {
+		docs = append(docs, item)
+	}
+	m.documentsMu.Unlock()
+
+	for _, item := range docs {
+		if err := server.Notify("textDocument/didOpen", DidOpenTextDocumentParams{TextDocument: item}); err != nil {
+			slog.Warn("Failed to reopen document after LSP server restart",
+				slog.String("language", server.Language()),
+				slog.String("uri", item.URI),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+	}
+}
+
 // =============================================================================
 // IDLE MONITOR
 // =============================================================================
@@ -12,6 +12,7 @@ package lsp
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -463,3 +464,84 @@ func TestManager_ReleaseFile_Integration(t *testing.T) {
 		t.Errorf("ReopenFile: %v", err)
 	}
 }
+
+// waitForServer polls until the manager runs a server for language other
+// than old, and returns it.
+func waitForServer(t *testing.T, mgr *Manager, language string, old *Server) *Server {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if srv := mgr.Get(language); srv != nil && srv != old {
+			return srv
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no new %s server", language)
+	return nil
+}
+
+func TestManager_RestartsCrashedServer(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxRestarts = 2
+	config.RestartBackoff = 20 * time.Millisecond
+	config.MaxRestartBackoff = 100 * time.Millisecond
+	mgr := newFakeOperationsWithConfig(t, fakeModePush, config).Manager()
+	ctx := context.Background()
+
+	srv, err := mgr.GetOrSpawn(ctx, "fake")
+	if err != nil {
+		t.Fatalf("GetOrSpawn: %v", err)
+	}
+
+	// Two crashes in a row are restarted.
+	for i := 0; i < 2; i++ {
+		_ = srv.cmd.Process.Kill()
+		<-srv.Done()
+		if !errors.Is(srv.CrashErr(), ErrServerCrashed) {
+			t.Fatalf("CrashErr() = %v, want ErrServerCrashed", srv.CrashErr())
+		}
+		srv = waitForServer(t, mgr, "fake", srv)
+	}
+
+	// The third gives up.
+	_ = srv.cmd.Process.Kill()
+	<-srv.Done()
+	time.Sleep(2 * config.MaxRestartBackoff)
+	if mgr.Get("fake") != nil {
+		t.Fatal("server restarted after MaxRestarts crashes")
+	}
+	if _, err := mgr.GetOrSpawn(ctx, "fake"); !errors.Is(err, ErrServerCrashed) {
+		t.Fatalf("GetOrSpawn after giving up = %v, want ErrServerCrashed", err)
+	}
+
+	// An explicit shutdown resets the crash history.
+	if err := mgr.Shutdown(ctx, "fake"); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, err := mgr.GetOrSpawn(ctx, "fake"); err != nil {
+		t.Fatalf("GetOrSpawn after Shutdown: %v", err)
+	}
+}
+
+func TestManager_RestartDisabled(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxRestarts = 0
+	mgr := newFakeOperationsWithConfig(t, fakeModePush, config).Manager()
+	ctx := context.Background()
+
+	srv, err := mgr.GetOrSpawn(ctx, "fake")
+	if err != nil {
+		t.Fatalf("GetOrSpawn: %v", err)
+	}
+	_ = srv.cmd.Process.Kill()
+	<-srv.Done()
+	time.Sleep(100 * time.Millisecond)
+	if mgr.Get("fake") != nil {
+		t.Fatal("server restarted with MaxRestarts = 0")
+	}
+
+	// Servers are still started on demand.
+	if _, err := mgr.GetOrSpawn(ctx, "fake"); err != nil {
+		t.Fatalf("GetOrSpawn after crash: %v", err)
+	}
+}
@@ -32,6 +32,7 @@ var (
 	operationLatency metric.Float64Histogram
 	operationTotal   metric.Int64Counter
 	serverSpawns     metric.Int64Counter
+	serverRestarts   metric.Int64Counter
 	resultCount      metric.Int64Histogram
 
 	metricsOnce sync.Once
@@ -71,6 +72,15 @@ func initMetrics() error {
 			return
 		}
 
+		serverRestarts, err = meter.Int64Counter(
+			"lsp_server_restarts_total",
+			metric.WithDescription("Total number of LSP server restarts after a crash"),
+		)
+		if err != nil {
+			metricsErr = err
+			return
+		}
+
 		resultCount, err = meter.Int64Histogram(
 			"lsp_result_count",
 			metric.WithDescription("Number of results returned by LSP operations"),
@@ -134,3 +144,14 @@ func recordServerSpawn(ctx context.Context, language string, success bool) {
 		attribute.Bool("success", success),
 	))
 }
+
+// recordServerRestart records a restart attempt after a server crash.
+func recordServerRestart(ctx context.Context, language string, success bool) {
+	if err := initMetrics(); err != nil {
+		return
+	}
+	serverRestarts.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("language", language),
+		attribute.Bool("success", success),
+	))
+}
@@ -188,7 +188,10 @@ func parseLocationResponse(data json.RawMessage) ([]Location, error) {
 // Description:
 //
 //	Executes the request function and retries once if a transient error occurs.
-//	Only idempotent operations (definition, references, hover) should use this.
+//	If the server crashed, the retry replays the request on the restarted
+//	server, waiting out the manager's restart backoff. Only idempotent
+//	operations (definition, references, hover, diagnostics, symbols) should
+//	use this.
 func (o *Operations) requestWithRetry(
 	ctx context.Context,
 	language string,
@@ -606,11 +609,6 @@ func (o *Operations) PrepareRename(ctx context.Context, filePath string, line, c
 		return nil, fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
 	}
 
-	server, err := o.manager.GetOrSpawn(ctx, language)
-	if err != nil {
-		return nil, fmt.Errorf("get server: %w", err)
-	}
-
 	params := PrepareRenameParams{
 		TextDocumentPositionParams: TextDocumentPositionParams{
 			TextDocument: TextDocumentIdentifier{URI: pathToURI(filePath)},
@@ -618,7 +616,10 @@ func (o *Operations) PrepareRename(ctx context.Context, filePath string, line, c
 		},
 	}
 
-	resp, err := server.Request(ctx, "textDocument/prepareRename", params)
+	// Use retry for this idempotent operation
+	resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+		return server.Request(ctx, "textDocument/prepareRename", params)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("prepareRename request: %w", err)
 	}
@@ -683,16 +684,12 @@ func (o *Operations) WorkspaceSymbol(ctx context.Context, language, query string
 	defer span.End()
 	start := time.Now()
 
-	server, err := o.manager.GetOrSpawn(ctx, language)
-	if err != nil {
-		setOperationSpanResult(span, 0, false)
-		recordOperationMetrics(ctx, "workspace_symbol", language, time.Since(start), 0, false)
-		return nil, fmt.Errorf("get server: %w", err)
-	}
-
 	params := WorkspaceSymbolParams{Query: query}
 
-	resp, err := server.Request(ctx, "workspace/symbol", params)
+	// Use retry for this idempotent operation
+	resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+		return server.Request(ctx, "workspace/symbol", params)
+	})
 	if err != nil {
 		setOperationSpanResult(span, 0, false)
 		recordOperationMetrics(ctx, "workspace_symbol", language, time.Since(start), 0, false)
@@ -717,6 +714,137 @@ func (o *Operations) WorkspaceSymbol(ctx context.Context, language, query string
 	return symbols, nil
 }
 
+// =============================================================================
+// DIAGNOSTICS OPERATION
+// =============================================================================
+
+// publishDiagnosticsWait bounds how long Diagnostics waits for a server
+// without pull support to publish diagnostics for a document.
+const publishDiagnosticsWait = 3 * time.Second
+
+// Diagnostics returns the compiler diagnostics for a file.
+//
+// Description:
+//
+//	Sends a textDocument/diagnostic request if the server supports pull
+//	diagnostics. Otherwise, or if the server rejects the request, falls
+//	back to the diagnostics the server last pushed with
+//	textDocument/publishDiagnostics, waiting up to a few seconds for
+//	the first set to arrive. Most servers only report diagnostics for
+//	open documents, so call OpenDocument first.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//
+// Outputs:
+//
+//	[]Diagnostic - Diagnostics for the file, empty if there are none or
+//	               the server published none in time
+//	error - Non-nil on failure
+//
+// Example:
+//
+//	diags, err := ops.Diagnostics(ctx, "/project/main.go")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, d := range diags {
+//	    fmt.Printf("%d: %s: %s\n", d.Range.Start.Line+1, d.Severity, d.Message)
+//	}
+func (o *Operations) Diagnostics(ctx context.Context, filePath string) ([]Diagnostic, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+
+	language := o.languageFromPath(filePath)
+	if language == "" {
+		return nil, fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
+	}
+
+	// Start tracing span
+	ctx, span := startOperationSpan(ctx, "Diagnostics", language, filePath)
+	defer span.End()
+	start := time.Now()
+
+	server, err := o.manager.GetOrSpawn(ctx, language)
+	if err != nil {
+		setOperationSpanResult(span, 0, false)
+		recordOperationMetrics(ctx, "diagnostics", language, time.Since(start), 0, false)
+		return nil, fmt.Errorf("get server: %w", err)
+	}
+
+	uri := pathToURI(filePath)
+	var diags []Diagnostic
+	pulled := false
+	if caps := server.Capabilities(); caps.HasDiagnosticProvider() {
+		diags, err = o.pullDiagnostics(ctx, language, uri)
+		var lspErr *LSPError
+		switch {
+		case err == nil:
+			pulled = true
+		case errors.As(err, &lspErr) && lspErr.IsMethodNotFound():
+			slog.Debug("Pull diagnostics not supported, using published diagnostics",
+				slog.String("language", language),
+			)
+		default:
+			setOperationSpanResult(span, 0, false)
+			recordOperationMetrics(ctx, "diagnostics", language, time.Since(start), 0, false)
+			return nil, fmt.Errorf("diagnostic request: %w", err)
+		}
+	}
+
+	if !pulled {
+		waitCtx, cancel := context.WithTimeout(ctx, publishDiagnosticsWait)
+		diags, err = server.WaitForDiagnostics(waitCtx, uri)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				setOperationSpanResult(span, 0, false)
+				recordOperationMetrics(ctx, "diagnostics", language, time.Since(start), 0, false)
+				return nil, fmt.Errorf("%w: %v", ErrRequestTimeout, ctx.Err())
+			}
+			// Nothing published in time: no diagnostics known.
+			diags = nil
+		}
+	}
+
+	setOperationSpanResult(span, len(diags), true)
+	recordOperationMetrics(ctx, "diagnostics", language, time.Since(start), len(diags), true)
+	return diags, nil
+}
+
+// pullDiagnostics sends a textDocument/diagnostic request for uri.
+func (o *Operations) pullDiagnostics(ctx context.Context, language, uri string) ([]Diagnostic, error) {
+	params := DocumentDiagnosticParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}
+
+	// Use retry for this idempotent operation
+	resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+		return server.Request(ctx, "textDocument/diagnostic", params)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Result) == 0 || string(resp.Result) == "null" {
+		return nil, nil
+	}
+
+	var report DocumentDiagnosticReport
+	if err := json.Unmarshal(resp.Result, &report); err != nil {
+		return nil, fmt.Errorf("parse diagnostic report: %w", err)
+	}
+	if report.Kind != DocumentDiagnosticReportFull && report.Kind != DocumentDiagnosticReportUnchanged {
+		return nil, fmt.Errorf("%w: diagnostic report kind %q", ErrInvalidResponse, report.Kind)
+	}
+	// An unchanged report only follows a request with a previous result
+	// ID, which we never send, so it carries no items.
+	return report.Items, nil
+}
+
 // =============================================================================
 // DOCUMENT NOTIFICATION OPERATIONS
 // =============================================================================
@@ -726,7 +854,8 @@ func (o *Operations) WorkspaceSymbol(ctx context.Context, language, query string
 // Description:
 //
 //	Sends a textDocument/didOpen notification. This is required before
-//	most LSP operations will work on a file.
+//	most LSP operations will work on a file. The document stays open
+//	across server restarts until CloseDocument is called.
 //
 // Inputs:
 //
@@ -760,7 +889,11 @@ func (o *Operations) OpenDocument(ctx context.Context, filePath, content string)
 		},
 	}
 
-	return server.Notify("textDocument/didOpen", params)
+	if err := server.Notify("textDocument/didOpen", params); err != nil {
+		return err
+	}
+	o.manager.trackDocument(language, params.TextDocument)
+	return nil
 }
 
 // CloseDocument notifies the server that a document was closed.
@@ -786,6 +919,9 @@ func (o *Operations) CloseDocument(ctx context.Context, filePath string) error {
 		return fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
 	}
 
+	uri := pathToURI(filePath)
+	o.manager.untrackDocument(language, uri)
+
 	server := o.manager.Get(language)
 	if server == nil {
 		// Server not running, nothing to close
@@ -793,7 +929,7 @@ func (o *Operations) CloseDocument(ctx context.Context, filePath string) error {
 	}
 
 	params := DidCloseTextDocumentParams{
-		TextDocument: TextDocumentIdentifier{URI: pathToURI(filePath)},
+		TextDocument: TextDocumentIdentifier{URI: uri},
 	}
 
 	return server.Notify("textDocument/didClose", params)
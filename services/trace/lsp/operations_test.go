@@ -629,3 +629,79 @@ func TestOperations_IsRetryableError(t *testing.T) {
 		})
 	}
 }
+
+func TestOperations_Diagnostics_RequiresContext(t *testing.T) {
+	mgr := NewManager("/tmp", DefaultManagerConfig())
+	ops := NewOperations(mgr)
+
+	_, err := ops.Diagnostics(nil, "/tmp/test.go") //nolint:staticcheck
+	if err == nil {
+		t.Error("expected error for nil context")
+	}
+}
+
+func TestOperations_Diagnostics(t *testing.T) {
+	const content = "ok\nx := undefined: foo\nok\n"
+
+	for _, mode := range []string{fakeModePull, fakeModePush} {
+		t.Run(mode, func(t *testing.T) {
+			ops := newFakeOperations(t, mode)
+			path := writeFakeFile(t, ops, "main.fake", content)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			diags, err := ops.Diagnostics(ctx, path)
+			if err != nil {
+				t.Fatalf("Diagnostics: %v", err)
+			}
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics, want 1: %+v", len(diags), diags)
+			}
+			d := diags[0]
+			if d.Range.Start.Line != 1 || d.Range.Start.Character != 5 || !d.IsError() || d.Message != "undefined: foo" {
+				t.Errorf("diagnostic = %+v", d)
+			}
+		})
+	}
+
+	t.Run("nothing published", func(t *testing.T) {
+		ops := newFakeOperations(t, fakeModePush)
+		path := filepath.Join(ops.Manager().RootPath(), "closed.fake")
+
+		start := time.Now()
+		diags, err := ops.Diagnostics(context.Background(), path)
+		if err != nil || len(diags) != 0 {
+			t.Errorf("Diagnostics = %v, %v, want none", diags, err)
+		}
+		if time.Since(start) > 2*publishDiagnosticsWait {
+			t.Error("Diagnostics did not stop waiting for published diagnostics")
+		}
+	})
+}
+
+func TestOperations_Hover_ReplaysAfterCrash(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.RestartBackoff = 20 * time.Millisecond
+	ops := newFakeOperationsWithConfig(t, fakeModePush, config)
+	path := writeFakeFile(t, ops, "main.fake", "first\nsecond\n")
+
+	marker := filepath.Join(ops.Manager().RootPath(), fakeCrashMarker)
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	crashed := ops.Manager().Get("fake")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	info, err := ops.Hover(ctx, path, 2, 0)
+	if err != nil {
+		t.Fatalf("Hover: %v", err)
+	}
+	// The restarted server only knows the document if it was reopened.
+	if info == nil || info.Content != "second" {
+		t.Errorf("Hover = %+v, want the second line", info)
+	}
+	if crashed.CrashErr() == nil || ops.Manager().Get("fake") == crashed {
+		t.Error("hover did not run on a restarted server")
+	}
+}
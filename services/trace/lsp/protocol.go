@@ -106,8 +106,18 @@ type Protocol struct {
 	pending   map[int64]chan Response
 	pendingMu sync.Mutex
 	closed    int32 // atomic: 1 if closed
+
+	onNotification NotificationHandler
 }
 
+// NotificationHandler receives notifications sent by the server.
+//
+// Description:
+//
+//	Called from the read loop goroutine for every server notification,
+//	such as textDocument/publishDiagnostics. Handlers must not block.
+type NotificationHandler func(method string, params json.RawMessage)
+
 // NewProtocol creates a new protocol handler.
 //
 // Description:
@@ -135,6 +145,20 @@ func NewProtocol(r io.Reader, w io.Writer) *Protocol {
 	}
 }
 
+// OnNotification sets the handler for server notifications.
+//
+// Description:
+//
+//	Replaces any previous handler. Notifications received while no
+//	handler is set are dropped.
+//
+// Thread Safety:
+//
+//	Must be called before ReadLoop starts.
+func (p *Protocol) OnNotification(handler NotificationHandler) {
+	p.onNotification = handler
+}
+
 // SendRequest sends a request and waits for the response.
 //
 // Description:
@@ -263,8 +287,9 @@ func (p *Protocol) writeMessage(v interface{}) error {
 // Description:
 //
 //	Continuously reads messages from the server. Responses are matched
-//	to pending requests. Notifications are ignored. Call this in a
-//	goroutine after starting the server.
+//	to pending requests. Notifications are passed to the handler set
+//	with OnNotification. Call this in a goroutine after starting the
+//	server.
 //
 // Inputs:
 //
@@ -353,6 +378,24 @@ func (p *Protocol) readMessage() (json.RawMessage, error) {
 
 // handleMessage dispatches a received message.
 func (p *Protocol) handleMessage(msg json.RawMessage) {
+	var envelope struct {
+		ID     int64           `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(msg, &envelope); err != nil {
+		return
+	}
+
+	if envelope.Method != "" {
+		// Server-to-client requests (with an ID) are not supported and
+		// are ignored; notifications go to the handler.
+		if envelope.ID == 0 && p.onNotification != nil {
+			p.onNotification(envelope.Method, envelope.Params)
+		}
+		return
+	}
+
 	// Try to parse as response (has ID)
 	var resp Response
 	if err := json.Unmarshal(msg, &resp); err == nil && resp.ID != 0 {
@@ -367,11 +410,7 @@ func (p *Protocol) handleMessage(msg json.RawMessage) {
 			default:
 			}
 		}
-		return
 	}
-
-	// Could be a notification from server - we ignore these for now
-	// Future: handle window/logMessage, window/showMessage, etc.
 }
 
 // Close marks the protocol as closed.
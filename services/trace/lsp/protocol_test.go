@@ -13,6 +13,7 @@ package lsp
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -191,6 +192,37 @@ func TestProtocol_HandleMessage(t *testing.T) {
 		msg := []byte(`{"jsonrpc":"2.0","method":"window/logMessage","params":{}}`)
 		p.handleMessage(msg) // Should not panic
 	})
+
+	t.Run("dispatches notifications to handler", func(t *testing.T) {
+		p := NewProtocol(nil, nil)
+		var gotMethod, gotParams string
+		p.OnNotification(func(method string, params json.RawMessage) {
+			gotMethod, gotParams = method, string(params)
+		})
+
+		p.handleMessage([]byte(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.go"}}`))
+		if gotMethod != "textDocument/publishDiagnostics" || gotParams != `{"uri":"file:///a.go"}` {
+			t.Errorf("handler got %q %s", gotMethod, gotParams)
+		}
+	})
+
+	t.Run("does not treat server requests as responses", func(t *testing.T) {
+		p := NewProtocol(nil, nil)
+		respCh := make(chan Response, 1)
+		p.pendingMu.Lock()
+		p.pending[1] = respCh
+		p.pendingMu.Unlock()
+		p.OnNotification(func(method string, params json.RawMessage) {
+			t.Errorf("server request passed to notification handler: %s", method)
+		})
+
+		p.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"workspace/configuration","params":{}}`))
+		select {
+		case resp := <-respCh:
+			t.Errorf("server request dispatched as response %+v", resp)
+		default:
+		}
+	})
 }
 
 func TestProtocol_SendRequest(t *testing.T) {
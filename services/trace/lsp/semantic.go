@@ -0,0 +1,399 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// SEMANTIC TOKEN TYPES
+// =============================================================================
+
+// SemanticTokenType is the classification of a semantic token.
+type SemanticTokenType string
+
+// Semantic token types as defined by the LSP specification. Servers may
+// report other types as well.
+const (
+	TokenTypeNamespace     SemanticTokenType = "namespace"
+	TokenTypeType          SemanticTokenType = "type"
+	TokenTypeClass         SemanticTokenType = "class"
+	TokenTypeEnum          SemanticTokenType = "enum"
+	TokenTypeInterface     SemanticTokenType = "interface"
+	TokenTypeStruct        SemanticTokenType = "struct"
+	TokenTypeTypeParameter SemanticTokenType = "typeParameter"
+	TokenTypeParameter     SemanticTokenType = "parameter"
+	TokenTypeVariable      SemanticTokenType = "variable"
+	TokenTypeProperty      SemanticTokenType = "property"
+	TokenTypeEnumMember    SemanticTokenType = "enumMember"
+	TokenTypeEvent         SemanticTokenType = "event"
+	TokenTypeFunction      SemanticTokenType = "function"
+	TokenTypeMethod        SemanticTokenType = "method"
+	TokenTypeMacro         SemanticTokenType = "macro"
+	TokenTypeKeyword       SemanticTokenType = "keyword"
+	TokenTypeModifier      SemanticTokenType = "modifier"
+	TokenTypeComment       SemanticTokenType = "comment"
+	TokenTypeString        SemanticTokenType = "string"
+	TokenTypeNumber        SemanticTokenType = "number"
+	TokenTypeRegexp        SemanticTokenType = "regexp"
+	TokenTypeOperator      SemanticTokenType = "operator"
+	TokenTypeDecorator     SemanticTokenType = "decorator"
+)
+
+// Semantic token modifiers as defined by the LSP specification.
+const (
+	TokenModifierDeclaration    = "declaration"
+	TokenModifierDefinition     = "definition"
+	TokenModifierReadonly       = "readonly"
+	TokenModifierStatic         = "static"
+	TokenModifierDeprecated     = "deprecated"
+	TokenModifierAbstract       = "abstract"
+	TokenModifierAsync          = "async"
+	TokenModifierModification   = "modification"
+	TokenModifierDocumentation  = "documentation"
+	TokenModifierDefaultLibrary = "defaultLibrary"
+)
+
+// standardSemanticTokenTypes lists the token types the client declares.
+var standardSemanticTokenTypes = []string{
+	"namespace", "type", "class", "enum", "interface", "struct",
+	"typeParameter", "parameter", "variable", "property", "enumMember",
+	"event", "function", "method", "macro", "keyword", "modifier",
+	"comment", "string", "number", "regexp", "operator", "decorator",
+}
+
+// standardSemanticTokenModifiers lists the token modifiers the client declares.
+var standardSemanticTokenModifiers = []string{
+	"declaration", "definition", "readonly", "static", "deprecated",
+	"abstract", "async", "modification", "documentation", "defaultLibrary",
+}
+
+// TokenCategory groups semantic token types by what the identifier names.
+type TokenCategory int
+
+const (
+	// TokenCategoryOther is anything not covered below, such as keywords,
+	// literals and comments.
+	TokenCategoryOther TokenCategory = iota
+
+	// TokenCategoryType names a type: class, struct, interface, enum,
+	// type parameter or plain type.
+	TokenCategoryType
+
+	// TokenCategoryFunction names a function, method or macro.
+	TokenCategoryFunction
+
+	// TokenCategoryVariable names a value: variable, parameter, property
+	// or enum member.
+	TokenCategoryVariable
+
+	// TokenCategoryNamespace names a namespace, package or module.
+	TokenCategoryNamespace
+)
+
+// String returns a human-readable category name.
+func (c TokenCategory) String() string {
+	names := []string{"other", "type", "function", "variable", "namespace"}
+	if int(c) < len(names) {
+		return names[c]
+	}
+	return "unknown"
+}
+
+// Category returns what an identifier of this token type names.
+func (t SemanticTokenType) Category() TokenCategory {
+	switch t {
+	case TokenTypeType, TokenTypeClass, TokenTypeEnum, TokenTypeInterface,
+		TokenTypeStruct, TokenTypeTypeParameter:
+		return TokenCategoryType
+	case TokenTypeFunction, TokenTypeMethod, TokenTypeMacro:
+		return TokenCategoryFunction
+	case TokenTypeVariable, TokenTypeParameter, TokenTypeProperty, TokenTypeEnumMember:
+		return TokenCategoryVariable
+	case TokenTypeNamespace:
+		return TokenCategoryNamespace
+	default:
+		return TokenCategoryOther
+	}
+}
+
+// SemanticToken is a decoded semantic token.
+type SemanticToken struct {
+	// Line is the 0-indexed line of the token.
+	Line int `json:"line"`
+
+	// StartChar is the 0-indexed character offset of the token.
+	StartChar int `json:"start_char"`
+
+	// Length is the token's length in characters.
+	Length int `json:"length"`
+
+	// Type is the token's type, named by the server's legend.
+	Type SemanticTokenType `json:"type"`
+
+	// Modifiers are the token's modifiers, named by the server's legend.
+	Modifiers []string `json:"modifiers,omitempty"`
+}
+
+// HasModifier returns true if the token has the named modifier.
+func (t SemanticToken) HasModifier(modifier string) bool {
+	for _, m := range t.Modifiers {
+		if m == modifier {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns true if pos lies within the token.
+func (t SemanticToken) Contains(pos Position) bool {
+	return pos.Line == t.Line && pos.Character >= t.StartChar && pos.Character < t.StartChar+t.Length
+}
+
+// decodeSemanticTokens decodes relative token data using legend.
+//
+// Description:
+//
+//	Each token is five integers: line delta from the previous token,
+//	start character (relative to the previous token if on the same
+//	line), length, type index and modifier bit set.
+func decodeSemanticTokens(data []uint32, legend SemanticTokensLegend) ([]SemanticToken, error) {
+	if len(data)%5 != 0 {
+		return nil, fmt.Errorf("%w: semantic token data length %d is not a multiple of 5", ErrInvalidResponse, len(data))
+	}
+
+	tokens := make([]SemanticToken, 0, len(data)/5)
+	line, char := 0, 0
+	for i := 0; i < len(data); i += 5 {
+		deltaLine, deltaChar := int(data[i]), int(data[i+1])
+		if deltaLine > 0 {
+			line += deltaLine
+			char = deltaChar
+		} else {
+			char += deltaChar
+		}
+
+		typeIndex := int(data[i+3])
+		if typeIndex >= len(legend.TokenTypes) {
+			return nil, fmt.Errorf("%w: semantic token type %d not in legend", ErrInvalidResponse, typeIndex)
+		}
+
+		var modifiers []string
+		for bit, bits := 0, data[i+4]; bits != 0; bit, bits = bit+1, bits>>1 {
+			if bits&1 != 0 && bit < len(legend.TokenModifiers) {
+				modifiers = append(modifiers, legend.TokenModifiers[bit])
+			}
+		}
+
+		tokens = append(tokens, SemanticToken{
+			Line:      line,
+			StartChar: char,
+			Length:    int(data[i+2]),
+			Type:      SemanticTokenType(legend.TokenTypes[typeIndex]),
+			Modifiers: modifiers,
+		})
+	}
+	return tokens, nil
+}
+
+// =============================================================================
+// SEMANTIC TOKENS OPERATIONS
+// =============================================================================
+
+// SemanticTokens returns the semantic tokens of a whole file.
+//
+// Description:
+//
+//	Sends a textDocument/semanticTokens/full request and decodes the
+//	result with the server's legend. The file should be open.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//
+// Outputs:
+//
+//	[]SemanticToken - Tokens in document order
+//	error - Non-nil on failure
+//
+// Errors:
+//
+//	ErrNotSupported - The server does not provide semantic tokens
+//
+// Example:
+//
+//	tokens, err := ops.SemanticTokens(ctx, "/project/main.go")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, tok := range tokens {
+//	    fmt.Printf("%d:%d %s\n", tok.Line+1, tok.StartChar, tok.Type)
+//	}
+func (o *Operations) SemanticTokens(ctx context.Context, filePath string) ([]SemanticToken, error) {
+	return o.semanticTokens(ctx, "SemanticTokens", filePath, nil)
+}
+
+// SemanticTokensRange returns the semantic tokens of a range of lines.
+//
+// Description:
+//
+//	Sends a textDocument/semanticTokens/range request for the lines
+//	startLine through endLine. Servers without range support are sent
+//	a full request, and the result is filtered to the lines.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//	startLine - 1-indexed first line
+//	endLine - 1-indexed last line, inclusive
+//
+// Outputs:
+//
+//	[]SemanticToken - Tokens on the lines, in document order
+//	error - Non-nil on failure
+func (o *Operations) SemanticTokensRange(ctx context.Context, filePath string, startLine, endLine int) ([]SemanticToken, error) {
+	if startLine < 1 || endLine < startLine {
+		return nil, fmt.Errorf("invalid line range %d-%d", startLine, endLine)
+	}
+	rng := Range{
+		Start: Position{Line: startLine - 1},
+		End:   Position{Line: endLine},
+	}
+	return o.semanticTokens(ctx, "SemanticTokensRange", filePath, &rng)
+}
+
+// SemanticTokenAt returns the semantic token at a position.
+//
+// Description:
+//
+//	Classifies the identifier at the position, for example to check
+//	whether a name is a type, a function or a variable.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	filePath - Absolute path to the file
+//	line - 1-indexed line number
+//	col - 0-indexed column number
+//
+// Outputs:
+//
+//	*SemanticToken - The token, nil if the position is not in a token
+//	error - Non-nil on failure
+//
+// Example:
+//
+//	tok, err := ops.SemanticTokenAt(ctx, "/project/main.go", 10, 5)
+//	if err == nil && tok != nil && tok.Type.Category() == lsp.TokenCategoryFunction {
+//	    // the identifier is a function
+//	}
+func (o *Operations) SemanticTokenAt(ctx context.Context, filePath string, line, col int) (*SemanticToken, error) {
+	tokens, err := o.SemanticTokensRange(ctx, filePath, line, line)
+	if err != nil {
+		return nil, err
+	}
+	pos := Position{Line: line - 1, Character: col}
+	for i := range tokens {
+		if tokens[i].Contains(pos) {
+			return &tokens[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// semanticTokens requests the tokens of rng, or the whole file if rng is nil.
+func (o *Operations) semanticTokens(ctx context.Context, operation, filePath string, rng *Range) ([]SemanticToken, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+
+	language := o.languageFromPath(filePath)
+	if language == "" {
+		return nil, fmt.Errorf("%w: no language for %s", ErrUnsupportedLanguage, filepath.Ext(filePath))
+	}
+
+	// Start tracing span
+	ctx, span := startOperationSpan(ctx, operation, language, filePath)
+	defer span.End()
+	start := time.Now()
+	metricName := "semantic_tokens"
+
+	fail := func(err error) ([]SemanticToken, error) {
+		setOperationSpanResult(span, 0, false)
+		recordOperationMetrics(ctx, metricName, language, time.Since(start), 0, false)
+		return nil, err
+	}
+
+	server, err := o.manager.GetOrSpawn(ctx, language)
+	if err != nil {
+		return fail(fmt.Errorf("get server: %w", err))
+	}
+	caps := server.Capabilities()
+	opts, ok := caps.SemanticTokensOptions()
+	if !ok {
+		return fail(fmt.Errorf("%w: semantic tokens for %s", ErrNotSupported, language))
+	}
+
+	uri := pathToURI(filePath)
+	method, params := "textDocument/semanticTokens/full", interface{}(SemanticTokensParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	})
+	if rng != nil && opts.SupportsRange() {
+		method, params = "textDocument/semanticTokens/range", SemanticTokensRangeParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+			Range:        *rng,
+		}
+	} else if !opts.SupportsFull() {
+		return fail(fmt.Errorf("%w: full semantic tokens for %s", ErrNotSupported, language))
+	}
+
+	// Use retry for this idempotent operation
+	resp, err := o.requestWithRetry(ctx, language, func(server *Server) (*Response, error) {
+		return server.Request(ctx, method, params)
+	})
+	if err != nil {
+		return fail(fmt.Errorf("semantic tokens request: %w", err))
+	}
+
+	var tokens []SemanticToken
+	if len(resp.Result) > 0 && string(resp.Result) != "null" {
+		var result SemanticTokens
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return fail(fmt.Errorf("parse semantic tokens: %w", err))
+		}
+		tokens, err = decodeSemanticTokens(result.Data, opts.Legend)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	// A full response to a range query is filtered to the range
+	if rng != nil && !opts.SupportsRange() {
+		filtered := tokens[:0]
+		for _, tok := range tokens {
+			if tok.Line >= rng.Start.Line && tok.Line < rng.End.Line {
+				filtered = append(filtered, tok)
+			}
+		}
+		tokens = filtered
+	}
+
+	setOperationSpanResult(span, len(tokens), true)
+	recordOperationMetrics(ctx, metricName, language, time.Since(start), len(tokens), true)
+	return tokens, nil
+}
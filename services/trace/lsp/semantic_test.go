@@ -0,0 +1,168 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeSemanticTokens(t *testing.T) {
+	legend := SemanticTokensLegend{
+		TokenTypes:     []string{"type", "function", "variable"},
+		TokenModifiers: []string{"declaration", "readonly", "static"},
+	}
+
+	t.Run("relative positions and modifiers", func(t *testing.T) {
+		data := []uint32{
+			2, 5, 3, 0, 0, // line 2, char 5, type
+			0, 4, 6, 1, 1, // same line, char 9, function declaration
+			1, 2, 1, 2, 6, // line 3, char 2, readonly static variable
+		}
+		tokens, err := decodeSemanticTokens(data, legend)
+		if err != nil {
+			t.Fatalf("decodeSemanticTokens: %v", err)
+		}
+		want := []SemanticToken{
+			{Line: 2, StartChar: 5, Length: 3, Type: TokenTypeType},
+			{Line: 2, StartChar: 9, Length: 6, Type: TokenTypeFunction, Modifiers: []string{"declaration"}},
+			{Line: 3, StartChar: 2, Length: 1, Type: TokenTypeVariable, Modifiers: []string{"readonly", "static"}},
+		}
+		if fmt.Sprint(tokens) != fmt.Sprint(want) {
+			t.Errorf("tokens = %v, want %v", tokens, want)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		tokens, err := decodeSemanticTokens(nil, legend)
+		if err != nil || len(tokens) != 0 {
+			t.Errorf("decodeSemanticTokens(nil) = %v, %v; want no tokens", tokens, err)
+		}
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		if _, err := decodeSemanticTokens([]uint32{0, 0, 1, 0}, legend); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("err = %v, want ErrInvalidResponse", err)
+		}
+	})
+
+	t.Run("type outside legend", func(t *testing.T) {
+		if _, err := decodeSemanticTokens([]uint32{0, 0, 1, 3, 0}, legend); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("err = %v, want ErrInvalidResponse", err)
+		}
+	})
+}
+
+func TestSemanticTokenType_Category(t *testing.T) {
+	tests := []struct {
+		tokenType SemanticTokenType
+		want      TokenCategory
+	}{
+		{TokenTypeStruct, TokenCategoryType},
+		{TokenTypeInterface, TokenCategoryType},
+		{TokenTypeMethod, TokenCategoryFunction},
+		{TokenTypeParameter, TokenCategoryVariable},
+		{TokenTypeNamespace, TokenCategoryNamespace},
+		{TokenTypeKeyword, TokenCategoryOther},
+		{"customType", TokenCategoryOther},
+	}
+	for _, tc := range tests {
+		if got := tc.tokenType.Category(); got != tc.want {
+			t.Errorf("%s.Category() = %s, want %s", tc.tokenType, got, tc.want)
+		}
+	}
+}
+
+func TestServerCapabilities_SemanticTokensOptions(t *testing.T) {
+	caps := ServerCapabilities{SemanticTokensProvider: map[string]interface{}{
+		"legend": map[string]interface{}{"tokenTypes": []string{"type"}, "tokenModifiers": []string{}},
+		"full":   map[string]interface{}{"delta": true},
+	}}
+	opts, ok := caps.SemanticTokensOptions()
+	if !ok || !opts.SupportsFull() || opts.SupportsRange() {
+		t.Errorf("SemanticTokensOptions() = %+v, %v; want full only", opts, ok)
+	}
+
+	for _, provider := range []interface{}{nil, false, map[string]interface{}{"full": true}} {
+		caps := ServerCapabilities{SemanticTokensProvider: provider}
+		if _, ok := caps.SemanticTokensOptions(); ok {
+			t.Errorf("SemanticTokensOptions() with provider %v = true, want false", provider)
+		}
+	}
+}
+
+func TestOperations_SemanticTokens_RequiresContext(t *testing.T) {
+	ops := NewOperations(NewManager(t.TempDir(), DefaultManagerConfig()))
+	//nolint:staticcheck // testing nil context handling
+	if _, err := ops.SemanticTokens(nil, "/project/main.go"); err == nil {
+		t.Error("SemanticTokens(nil) should fail")
+	}
+}
+
+func TestOperations_SemanticTokens(t *testing.T) {
+	const text = "type Server\nfunc NewServer\nvar count\nfunc main\n"
+
+	for _, mode := range []string{fakeModePull, fakeModePush} {
+		t.Run(mode, func(t *testing.T) {
+			ops := newFakeOperations(t, mode)
+			path := writeFakeFile(t, ops, "main.fake", text)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			tokens, err := ops.SemanticTokens(ctx, path)
+			if err != nil {
+				t.Fatalf("SemanticTokens: %v", err)
+			}
+			var got []string
+			for _, tok := range tokens {
+				got = append(got, fmt.Sprintf("%d:%d:%s", tok.Line, tok.StartChar, tok.Type))
+			}
+			want := "0:5:struct 1:5:function 2:4:variable 3:5:function"
+			if strings.Join(got, " ") != want {
+				t.Errorf("tokens = %s, want %s", strings.Join(got, " "), want)
+			}
+
+			// Push mode has no range support, so the full result is filtered.
+			tokens, err = ops.SemanticTokensRange(ctx, path, 2, 3)
+			if err != nil {
+				t.Fatalf("SemanticTokensRange: %v", err)
+			}
+			if len(tokens) != 2 || tokens[0].Type != TokenTypeFunction || tokens[1].Type != TokenTypeVariable {
+				t.Errorf("range tokens = %v, want NewServer and count", tokens)
+			}
+
+			tok, err := ops.SemanticTokenAt(ctx, path, 3, 6)
+			if err != nil {
+				t.Fatalf("SemanticTokenAt: %v", err)
+			}
+			if tok == nil || tok.Type.Category() != TokenCategoryVariable || !tok.HasModifier(TokenModifierReadonly) {
+				t.Errorf("SemanticTokenAt(3, 6) = %+v, want readonly variable", tok)
+			}
+
+			tok, err = ops.SemanticTokenAt(ctx, path, 3, 0)
+			if err != nil || tok != nil {
+				t.Errorf("SemanticTokenAt(3, 0) = %+v, %v; want no token", tok, err)
+			}
+		})
+	}
+}
+
+func TestOperations_SemanticTokensRange_InvalidRange(t *testing.T) {
+	ops := newFakeOperations(t, fakeModePull)
+	if _, err := ops.SemanticTokensRange(context.Background(), "/project/main.fake", 3, 2); err == nil {
+		t.Error("SemanticTokensRange(3, 2) should fail")
+	}
+}
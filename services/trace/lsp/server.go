@@ -13,6 +13,7 @@ package lsp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -81,8 +82,9 @@ type Server struct {
 	protocol     *Protocol
 	capabilities ServerCapabilities
 
-	state   ServerState
-	stateMu sync.RWMutex
+	state    ServerState
+	crashErr error // set if the connection ended while running
+	stateMu  sync.RWMutex
 
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -90,6 +92,12 @@ type Server struct {
 
 	lastUsed   time.Time
 	lastUsedMu sync.Mutex
+
+	// diagnostics holds the latest publishDiagnostics per document URI.
+	// diagnosticsCh is closed and replaced whenever a new set arrives.
+	diagnostics   map[string][]Diagnostic
+	diagnosticsCh chan struct{}
+	diagnosticsMu sync.Mutex
 }
 
 // NewServer creates a new server instance (not started).
@@ -114,6 +122,9 @@ func NewServer(config LanguageConfig, rootPath string) *Server {
 		state:    ServerStateUninitialized,
 		readDone: make(chan struct{}),
 		lastUsed: time.Now(),
+
+		diagnostics:   make(map[string][]Diagnostic),
+		diagnosticsCh: make(chan struct{}),
 	}
 }
 
@@ -200,11 +211,12 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Setup protocol
 	s.protocol = NewProtocol(s.stdout, s.stdin)
+	s.protocol.OnNotification(s.handleNotification)
 
 	// Start read loop in background
 	go func() {
 		defer close(s.readDone)
-		_ = s.protocol.ReadLoop(s.ctx)
+		s.handleExit(s.protocol.ReadLoop(s.ctx))
 	}()
 
 	// Perform initialize handshake
@@ -246,6 +258,17 @@ func (s *Server) initialize(ctx context.Context) error {
 				Rename: &RenameCapabilities{
 					PrepareSupport: true,
 				},
+				PublishDiagnostics: &PublishDiagnosticsClientCapabilities{
+					VersionSupport: true,
+				},
+				Diagnostic: &DiagnosticClientCapabilities{},
+				SemanticTokens: &SemanticTokensClientCapabilities{
+					Requests:       SemanticTokensClientRequests{Range: true, Full: true},
+					TokenTypes:     standardSemanticTokenTypes,
+					TokenModifiers: standardSemanticTokenModifiers,
+					Formats:        []string{"relative"},
+				},
+				CallHierarchy: &CallHierarchyClientCapabilities{},
 			},
 			Workspace: WorkspaceClientCapabilities{
 				ApplyEdit: true,
@@ -368,6 +391,43 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// handleExit handles the end of the read loop.
+//
+// Description:
+//
+//	If the connection ended while the server was starting or ready, the
+//	process died or broke the protocol: the server is marked crashed,
+//	pending requests fail with ErrServerCrashed, and the process is
+//	killed and reaped. Does nothing if Shutdown is in progress.
+func (s *Server) handleExit(err error) {
+	s.stateMu.Lock()
+	if s.state != ServerStateStarting && s.state != ServerStateReady {
+		s.stateMu.Unlock()
+		return
+	}
+	if err == nil || !errors.Is(err, ErrServerCrashed) {
+		err = fmt.Errorf("%w: %v", ErrServerCrashed, err)
+	}
+	s.crashErr = err
+	// Stopping keeps a concurrent Shutdown from touching the process.
+	s.state = ServerStateStopping
+	s.stateMu.Unlock()
+
+	slog.Warn("LSP server crashed",
+		slog.String("language", s.config.Language),
+		slog.String("error", err.Error()),
+	)
+
+	// Fail in-flight requests now rather than at their timeouts
+	s.protocol.Close()
+
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = s.cmd.Process.Kill()
+		_ = s.cmd.Wait()
+	}
+	s.cleanup()
+}
+
 // cleanup releases resources and sets state to stopped.
 func (s *Server) cleanup() {
 	if s.cancel != nil {
@@ -417,6 +477,33 @@ func (s *Server) Capabilities() ServerCapabilities {
 	return s.capabilities
 }
 
+// CrashErr returns why the server crashed, or nil if it did not.
+//
+// Description:
+//
+//	Non-nil once the server's process exited or its connection broke
+//	without a Shutdown call. The error wraps ErrServerCrashed.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use.
+func (s *Server) CrashErr() error {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.crashErr
+}
+
+// Done returns a channel that is closed when the server's connection ends.
+//
+// Description:
+//
+//	The channel is closed after a Shutdown or a crash; use CrashErr to
+//	tell them apart. It is never closed for a server that was not
+//	started successfully.
+func (s *Server) Done() <-chan struct{} {
+	return s.readDone
+}
+
 // LastUsed returns when the server was last used.
 //
 // Thread Safety:
@@ -450,6 +537,11 @@ func (s *Server) LastUsed() time.Time {
 //	*Response - The server's response
 //	error - Non-nil if server not ready, send failed, or timeout
 //
+// Errors:
+//
+//	ErrServerCrashed - The server crashed before or while handling the request
+//	ErrServerNotRunning - The server is not ready
+//
 // Thread Safety:
 //
 //	Safe for concurrent use.
@@ -458,10 +550,20 @@ func (s *Server) Request(ctx context.Context, method string, params interface{})
 		return nil, fmt.Errorf("ctx must not be nil")
 	}
 	if s.State() != ServerStateReady {
+		if crashErr := s.CrashErr(); crashErr != nil {
+			return nil, crashErr
+		}
 		return nil, ErrServerNotRunning
 	}
 	s.touchLastUsed()
-	return s.protocol.SendRequest(ctx, method, params)
+	resp, err := s.protocol.SendRequest(ctx, method, params)
+	if err != nil {
+		if crashErr := s.CrashErr(); crashErr != nil {
+			return nil, fmt.Errorf("%s: %w", method, crashErr)
+		}
+		return nil, err
+	}
+	return resp, nil
 }
 
 // Notify sends an LSP notification.
@@ -491,6 +593,98 @@ func (s *Server) Notify(method string, params interface{}) error {
 	return s.protocol.SendNotification(method, params)
 }
 
+// =============================================================================
+// NOTIFICATIONS
+// =============================================================================
+
+// handleNotification records notifications the server pushes to us.
+func (s *Server) handleNotification(method string, params json.RawMessage) {
+	switch method {
+	case "textDocument/publishDiagnostics":
+		var p PublishDiagnosticsParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			slog.Debug("Ignoring malformed publishDiagnostics",
+				slog.String("language", s.config.Language),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		s.diagnosticsMu.Lock()
+		s.diagnostics[p.URI] = p.Diagnostics
+		close(s.diagnosticsCh)
+		s.diagnosticsCh = make(chan struct{})
+		s.diagnosticsMu.Unlock()
+	}
+}
+
+// PublishedDiagnostics returns the diagnostics last published for a document.
+//
+// Description:
+//
+//	Returns the diagnostics from the most recent
+//	textDocument/publishDiagnostics notification for uri. The boolean
+//	is false if the server has not published diagnostics for uri yet.
+//
+// Inputs:
+//
+//	uri - The document URI
+//
+// Outputs:
+//
+//	[]Diagnostic - The published diagnostics
+//	bool - True if the server has published diagnostics for uri
+//
+// Thread Safety:
+//
+//	Safe for concurrent use.
+func (s *Server) PublishedDiagnostics(uri string) ([]Diagnostic, bool) {
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+	diags, ok := s.diagnostics[uri]
+	return diags, ok
+}
+
+// WaitForDiagnostics waits until the server has published diagnostics for a document.
+//
+// Description:
+//
+//	Returns immediately if diagnostics for uri were already published,
+//	otherwise blocks until they are or the context is done.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	uri - The document URI
+//
+// Outputs:
+//
+//	[]Diagnostic - The published diagnostics
+//	error - Non-nil if the context is done first
+//
+// Thread Safety:
+//
+//	Safe for concurrent use.
+func (s *Server) WaitForDiagnostics(ctx context.Context, uri string) ([]Diagnostic, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+	for {
+		s.diagnosticsMu.Lock()
+		diags, ok := s.diagnostics[uri]
+		published := s.diagnosticsCh
+		s.diagnosticsMu.Unlock()
+		if ok {
+			return diags, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-published:
+		}
+	}
+}
+
 // =============================================================================
 // INTERNAL HELPERS
 // =============================================================================
@@ -12,6 +12,7 @@ package lsp
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -298,3 +299,43 @@ func TestServer_Request_Integration(t *testing.T) {
 		t.Error("expected non-nil response")
 	}
 }
+
+func TestServer_CrashFailsInFlightRequests(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxRestarts = 0
+	mgr := newFakeOperationsWithConfig(t, fakeModePush, config).Manager()
+
+	srv, err := mgr.GetOrSpawn(context.Background(), "fake")
+	if err != nil {
+		t.Fatalf("GetOrSpawn: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := srv.Request(ctx, "fake/hang", nil)
+		errCh <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	_ = srv.cmd.Process.Kill()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrServerCrashed) {
+			t.Errorf("Request() error = %v, want ErrServerCrashed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request did not fail after the crash")
+	}
+
+	// In-flight requests fail before the process is reaped.
+	<-srv.Done()
+	if srv.State() != ServerStateStopped {
+		t.Errorf("State() = %v, want stopped", srv.State())
+	}
+	if _, err := srv.Request(ctx, "fake/hang", nil); !errors.Is(err, ErrServerCrashed) {
+		t.Errorf("Request() after crash error = %v, want ErrServerCrashed", err)
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// =============================================================================
+// WORKSPACE SYMBOL SEARCH
+// =============================================================================
+
+// DefaultSymbolPageSize is the page size WorkspaceSymbols uses when
+// SymbolSearchOptions.Limit is zero.
+const DefaultSymbolPageSize = 50
+
+// SymbolSearchOptions configures a workspace symbol search.
+type SymbolSearchOptions struct {
+	// Languages are the languages to search. Empty means every running
+	// server, or if none is running, every installed server whose root
+	// files (e.g., go.mod) are at the workspace root.
+	Languages []string
+
+	// Offset is the number of ranked results to skip.
+	Offset int
+
+	// Limit is the maximum number of results to return. Zero means
+	// DefaultSymbolPageSize; negative means no limit.
+	Limit int
+}
+
+// SymbolMatch is a ranked workspace symbol.
+type SymbolMatch struct {
+	SymbolInformation
+
+	// Language is the language whose server reported the symbol.
+	Language string `json:"language"`
+
+	// Rank is how well the name matches the query; lower is better.
+	Rank SymbolRank `json:"rank"`
+}
+
+// SymbolRank orders symbol matches, best first.
+type SymbolRank int
+
+// Symbol ranks, best first.
+const (
+	// SymbolRankExact means the name equals the query.
+	SymbolRankExact SymbolRank = iota
+
+	// SymbolRankExactFold means the name equals the query ignoring case.
+	SymbolRankExactFold
+
+	// SymbolRankPrefix means the name starts with the query, ignoring case.
+	SymbolRankPrefix
+
+	// SymbolRankWord means a word of the name starts with the query,
+	// such as "Handler" in "NewHandler" or "handler" in "new_handler".
+	SymbolRankWord
+
+	// SymbolRankSubstring means the name contains the query, ignoring case.
+	SymbolRankSubstring
+
+	// SymbolRankFuzzy means the server matched the symbol some other way,
+	// such as by subsequence.
+	SymbolRankFuzzy
+)
+
+// SymbolSearchResult is one page of a workspace symbol search.
+type SymbolSearchResult struct {
+	// Symbols is the requested page of ranked symbols.
+	Symbols []SymbolMatch `json:"symbols"`
+
+	// Total is the number of matches across all pages.
+	Total int `json:"total"`
+
+	// Offset is the offset of the first symbol in this page.
+	Offset int `json:"offset"`
+
+	// NextOffset is the offset of the next page, or 0 if this is the last.
+	NextOffset int `json:"next_offset,omitempty"`
+
+	// Errors maps languages whose servers failed to their error. The
+	// search still succeeds if at least one server answered.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// HasMore returns true if there are results after this page.
+func (r *SymbolSearchResult) HasMore() bool {
+	return r.NextOffset > 0
+}
+
+// WorkspaceSymbols finds symbols matching a query across all project languages.
+//
+// Description:
+//
+//	Sends a workspace/symbol request to the server of each language in
+//	opts.Languages, concurrently, then merges, de-duplicates and ranks
+//	the results. Exact name matches rank first, then prefix, word and
+//	substring matches, then whatever else the server matched. Within a
+//	rank, types and functions come before other kinds, then shorter
+//	names, then by file and position, so pages are stable across calls.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	query - The symbol query; empty lists all symbols the servers report
+//	opts - Languages and paging
+//
+// Outputs:
+//
+//	*SymbolSearchResult - One page of ranked symbols
+//	error - Non-nil if no language could be searched
+//
+// Example:
+//
+//	res, err := ops.WorkspaceSymbols(ctx, "Handler", lsp.SymbolSearchOptions{Limit: 20})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, sym := range res.Symbols {
+//	    fmt.Printf("%s (%s)\n", sym.Name, uriToPath(sym.Location.URI))
+//	}
+//	if res.HasMore() {
+//	    // fetch the next page with Offset: res.NextOffset
+//	}
+func (o *Operations) WorkspaceSymbols(ctx context.Context, query string, opts SymbolSearchOptions) (*SymbolSearchResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	languages := opts.Languages
+	if len(languages) == 0 {
+		languages = o.searchLanguages()
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("%w: no language server for workspace", ErrUnsupportedLanguage)
+	}
+
+	// Start tracing span
+	ctx, span := startOperationSpan(ctx, "WorkspaceSymbols", strings.Join(languages, ","), "")
+	defer span.End()
+	start := time.Now()
+
+	type languageResult struct {
+		symbols []SymbolInformation
+		err     error
+	}
+	results := make([]languageResult, len(languages))
+	var wg sync.WaitGroup
+	for i, language := range languages {
+		wg.Add(1)
+		go func(i int, language string) {
+			defer wg.Done()
+			symbols, err := o.WorkspaceSymbol(ctx, language, query)
+			results[i] = languageResult{symbols: symbols, err: err}
+		}(i, language)
+	}
+	wg.Wait()
+
+	var matches []SymbolMatch
+	errs := make(map[string]string)
+	seen := make(map[symbolKey]bool)
+	for i, res := range results {
+		if res.err != nil {
+			errs[languages[i]] = res.err.Error()
+			continue
+		}
+		for _, sym := range res.symbols {
+			key := symbolKey{sym.Name, sym.Location.URI, sym.Location.Range.Start}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			matches = append(matches, SymbolMatch{
+				SymbolInformation: sym,
+				Language:          languages[i],
+				Rank:              rankSymbol(sym.Name, query),
+			})
+		}
+	}
+	if len(errs) == len(languages) {
+		setOperationSpanResult(span, 0, false)
+		recordOperationMetrics(ctx, "workspace_symbols", "all", time.Since(start), 0, false)
+		return nil, fmt.Errorf("symbol search failed for all languages: %s", errs[languages[0]])
+	}
+
+	sortSymbolMatches(matches)
+	result := paginateSymbols(matches, opts)
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+
+	setOperationSpanResult(span, len(result.Symbols), true)
+	recordOperationMetrics(ctx, "workspace_symbols", "all", time.Since(start), len(result.Symbols), true)
+	return result, nil
+}
+
+// symbolKey identifies a symbol reported by more than one server.
+type symbolKey struct {
+	name  string
+	uri   string
+	start Position
+}
+
+// searchLanguages returns the languages to search when none are given.
+func (o *Operations) searchLanguages() []string {
+	running := o.manager.RunningServers()
+	if len(running) > 0 {
+		sort.Strings(running)
+		return running
+	}
+
+	var languages []string
+	for _, language := range o.manager.Configs().Languages() {
+		config, ok := o.manager.Configs().Get(language)
+		if !ok || !o.manager.IsAvailable(language) {
+			continue
+		}
+		for _, rootFile := range config.RootFiles {
+			if _, err := os.Stat(filepath.Join(o.manager.RootPath(), rootFile)); err == nil {
+				languages = append(languages, language)
+				break
+			}
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// rankSymbol ranks how well name matches query.
+func rankSymbol(name, query string) SymbolRank {
+	if query == "" || name == query {
+		return SymbolRankExact
+	}
+	lowerName, lowerQuery := strings.ToLower(name), strings.ToLower(query)
+	switch {
+	case lowerName == lowerQuery:
+		return SymbolRankExactFold
+	case strings.HasPrefix(lowerName, lowerQuery):
+		return SymbolRankPrefix
+	case hasWordPrefix(name, lowerQuery):
+		return SymbolRankWord
+	case strings.Contains(lowerName, lowerQuery):
+		return SymbolRankSubstring
+	default:
+		return SymbolRankFuzzy
+	}
+}
+
+// hasWordPrefix returns true if a word of name starts with lowerQuery.
+// Words start after '_', '.' or '-', and at upper-case letters that
+// follow a lower-case letter or digit.
+func hasWordPrefix(name, lowerQuery string) bool {
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		prev, r := runes[i-1], runes[i]
+		boundary := prev == '_' || prev == '.' || prev == '-' ||
+			(unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)))
+		if boundary && strings.HasPrefix(strings.ToLower(string(runes[i:])), lowerQuery) {
+			return true
+		}
+	}
+	return false
+}
+
+// symbolKindOrder orders symbol kinds within a rank, lower first.
+// Declarations an agent most often looks up come first.
+func symbolKindOrder(kind SymbolKind) int {
+	switch kind {
+	case SymbolKindClass, SymbolKindStruct, SymbolKindInterface, SymbolKindEnum, SymbolKindTypeParameter:
+		return 0
+	case SymbolKindFunction, SymbolKindMethod, SymbolKindConstructor:
+		return 1
+	case SymbolKindConstant, SymbolKindVariable, SymbolKindEnumMember:
+		return 2
+	case SymbolKindModule, SymbolKindNamespace, SymbolKindPackage:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// sortSymbolMatches sorts matches best first, in a stable total order.
+func sortSymbolMatches(matches []SymbolMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		if ka, kb := symbolKindOrder(a.Kind), symbolKindOrder(b.Kind); ka != kb {
+			return ka < kb
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Location.URI != b.Location.URI {
+			return a.Location.URI < b.Location.URI
+		}
+		if a.Location.Range.Start.Line != b.Location.Range.Start.Line {
+			return a.Location.Range.Start.Line < b.Location.Range.Start.Line
+		}
+		return a.Location.Range.Start.Character < b.Location.Range.Start.Character
+	})
+}
+
+// paginateSymbols returns the page of sorted matches selected by opts.
+func paginateSymbols(matches []SymbolMatch, opts SymbolSearchOptions) *SymbolSearchResult {
+	limit := opts.Limit
+	if limit == 0 {
+		limit = DefaultSymbolPageSize
+	}
+
+	result := &SymbolSearchResult{
+		Symbols: []SymbolMatch{},
+		Total:   len(matches),
+		Offset:  opts.Offset,
+	}
+	if opts.Offset >= len(matches) {
+		return result
+	}
+
+	end := len(matches)
+	if limit > 0 && opts.Offset+limit < end {
+		end = opts.Offset + limit
+		result.NextOffset = end
+	}
+	result.Symbols = matches[opts.Offset:end]
+	return result
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lsp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRankSymbol(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  SymbolRank
+	}{
+		{"Handler", "Handler", SymbolRankExact},
+		{"Handler", "", SymbolRankExact},
+		{"handler", "Handler", SymbolRankExactFold},
+		{"HandlerFunc", "handler", SymbolRankPrefix},
+		{"NewHandler", "handler", SymbolRankWord},
+		{"new_handler", "hand", SymbolRankWord},
+		{"pkg.Handler", "hand", SymbolRankWord},
+		{"HTTPHandler", "handler", SymbolRankSubstring},
+		{"Unhandled", "handl", SymbolRankSubstring},
+		{"HelloWorld", "hw", SymbolRankFuzzy},
+	}
+	for _, tc := range tests {
+		if got := rankSymbol(tc.name, tc.query); got != tc.want {
+			t.Errorf("rankSymbol(%q, %q) = %d, want %d", tc.name, tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestSortSymbolMatches(t *testing.T) {
+	match := func(name string, kind SymbolKind, uri string, line int) SymbolMatch {
+		return SymbolMatch{
+			SymbolInformation: SymbolInformation{
+				Name:     name,
+				Kind:     kind,
+				Location: Location{URI: uri, Range: Range{Start: Position{Line: line}}},
+			},
+			Rank: rankSymbol(name, "parse"),
+		}
+	}
+	matches := []SymbolMatch{
+		match("reparse", SymbolKindFunction, "file:///a", 1),
+		match("ParseFile", SymbolKindFunction, "file:///a", 2),
+		match("parse", SymbolKindVariable, "file:///b", 3),
+		match("Parser", SymbolKindStruct, "file:///a", 4),
+		match("Parse", SymbolKindFunction, "file:///b", 5),
+		match("Parse", SymbolKindFunction, "file:///a", 6),
+	}
+
+	sortSymbolMatches(matches)
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Name+"@"+m.Location.URI[len("file:///"):])
+	}
+	want := "parse@b Parse@a Parse@b Parser@a ParseFile@a reparse@a"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %s, want %s", strings.Join(got, " "), want)
+	}
+}
+
+func TestPaginateSymbols(t *testing.T) {
+	matches := make([]SymbolMatch, 5)
+	tests := []struct {
+		opts     SymbolSearchOptions
+		wantLen  int
+		wantNext int
+	}{
+		{SymbolSearchOptions{}, 5, 0},
+		{SymbolSearchOptions{Limit: 2}, 2, 2},
+		{SymbolSearchOptions{Offset: 2, Limit: 2}, 2, 4},
+		{SymbolSearchOptions{Offset: 4, Limit: 2}, 1, 0},
+		{SymbolSearchOptions{Offset: 9, Limit: 2}, 0, 0},
+		{SymbolSearchOptions{Limit: -1}, 5, 0},
+	}
+	for _, tc := range tests {
+		res := paginateSymbols(matches, tc.opts)
+		if len(res.Symbols) != tc.wantLen || res.NextOffset != tc.wantNext || res.Total != 5 || res.HasMore() != (tc.wantNext > 0) {
+			t.Errorf("paginateSymbols(%+v) = %d symbols, next %d, total %d, want %d, %d, 5",
+				tc.opts, len(res.Symbols), res.NextOffset, res.Total, tc.wantLen, tc.wantNext)
+		}
+	}
+}
+
+func TestOperations_WorkspaceSymbols_RequiresContext(t *testing.T) {
+	ops := NewOperations(NewManager("/tmp", DefaultManagerConfig()))
+
+	_, err := ops.WorkspaceSymbols(nil, "x", SymbolSearchOptions{}) //nolint:staticcheck
+	if err == nil {
+		t.Error("expected error for nil context")
+	}
+}
+
+func TestOperations_WorkspaceSymbols(t *testing.T) {
+	ops := newFakeOperations(t, fakeModePush)
+	writeFakeFile(t, ops, "a.fake", "func NewHandler\ntype Handler\nfunc helper\n")
+	writeFakeFile(t, ops, "b.fake2", "func handle\nfunc HTTPHandler\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ops.WorkspaceSymbols(ctx, "handler", SymbolSearchOptions{})
+	if err != nil {
+		t.Fatalf("WorkspaceSymbols: %v", err)
+	}
+	var names []string
+	for _, sym := range res.Symbols {
+		names = append(names, sym.Name+"/"+sym.Language)
+	}
+	// "handle" is not a subsequence match for "handler".
+	want := "Handler/fake NewHandler/fake HTTPHandler/fake2"
+	if strings.Join(names, " ") != want || res.Total != 3 || res.Errors != nil {
+		t.Errorf("symbols = %s (total %d, errors %v), want %s", strings.Join(names, " "), res.Total, res.Errors, want)
+	}
+
+	page, err := ops.WorkspaceSymbols(ctx, "handler", SymbolSearchOptions{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("WorkspaceSymbols page: %v", err)
+	}
+	if len(page.Symbols) != 1 || page.Symbols[0].Name != "NewHandler" || page.NextOffset != 2 {
+		t.Errorf("page = %+v, want NewHandler with next offset 2", page)
+	}
+
+	partial, err := ops.WorkspaceSymbols(ctx, "handler", SymbolSearchOptions{Languages: []string{"fake", "cobol"}})
+	if err != nil {
+		t.Fatalf("WorkspaceSymbols with unknown language: %v", err)
+	}
+	if partial.Total != 2 || partial.Errors["cobol"] == "" {
+		t.Errorf("partial = %+v, want 2 symbols and an error for cobol", partial)
+	}
+}
@@ -10,6 +10,8 @@
 
 package lsp
 
+import "encoding/json"
+
 // =============================================================================
 // POSITION & RANGE TYPES
 // =============================================================================
@@ -286,6 +288,244 @@ type PrepareRenameResult struct {
 	Placeholder string `json:"placeholder"`
 }
 
+// =============================================================================
+// DIAGNOSTIC TYPES
+// =============================================================================
+
+// Diagnostic represents a compiler error, warning, or hint for a range.
+type Diagnostic struct {
+	// Range is the range the diagnostic applies to.
+	Range Range `json:"range"`
+
+	// Severity is the diagnostic's severity. Zero means the server did
+	// not say; clients usually treat that as an error.
+	Severity DiagnosticSeverity `json:"severity,omitempty"`
+
+	// Code is the diagnostic's code, a number or a string.
+	Code interface{} `json:"code,omitempty"`
+
+	// Source names the tool that produced the diagnostic (e.g., "compiler").
+	Source string `json:"source,omitempty"`
+
+	// Message is the diagnostic's message.
+	Message string `json:"message"`
+
+	// Tags are additional attributes (unnecessary, deprecated).
+	Tags []DiagnosticTag `json:"tags,omitempty"`
+}
+
+// IsError returns true for error diagnostics, including unset severity.
+func (d Diagnostic) IsError() bool {
+	return d.Severity == DiagnosticSeverityError || d.Severity == 0
+}
+
+// DiagnosticSeverity represents the severity of a diagnostic.
+type DiagnosticSeverity int
+
+// Diagnostic severities as defined by the LSP specification.
+const (
+	DiagnosticSeverityError       DiagnosticSeverity = 1
+	DiagnosticSeverityWarning     DiagnosticSeverity = 2
+	DiagnosticSeverityInformation DiagnosticSeverity = 3
+	DiagnosticSeverityHint        DiagnosticSeverity = 4
+)
+
+// String returns a human-readable severity name.
+func (s DiagnosticSeverity) String() string {
+	switch s {
+	case DiagnosticSeverityError, 0:
+		return "error"
+	case DiagnosticSeverityWarning:
+		return "warning"
+	case DiagnosticSeverityInformation:
+		return "information"
+	case DiagnosticSeverityHint:
+		return "hint"
+	default:
+		return "unknown"
+	}
+}
+
+// DiagnosticTag represents additional diagnostic attributes.
+type DiagnosticTag int
+
+// Diagnostic tags as defined by the LSP specification.
+const (
+	DiagnosticTagUnnecessary DiagnosticTag = 1
+	DiagnosticTagDeprecated  DiagnosticTag = 2
+)
+
+// PublishDiagnosticsParams contains params for textDocument/publishDiagnostics.
+type PublishDiagnosticsParams struct {
+	// URI is the document the diagnostics belong to.
+	URI string `json:"uri"`
+
+	// Version is the document version the diagnostics were computed for.
+	Version *int `json:"version,omitempty"`
+
+	// Diagnostics replaces all previously published diagnostics for URI.
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// DocumentDiagnosticParams contains params for textDocument/diagnostic.
+type DocumentDiagnosticParams struct {
+	// TextDocument is the document to compute diagnostics for.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// Identifier is the diagnostic provider's identifier, if registered.
+	Identifier string `json:"identifier,omitempty"`
+
+	// PreviousResultID is the result ID of the last report for this document.
+	PreviousResultID string `json:"previousResultId,omitempty"`
+}
+
+// Document diagnostic report kinds.
+const (
+	// DocumentDiagnosticReportFull is a report with a complete item list.
+	DocumentDiagnosticReportFull = "full"
+
+	// DocumentDiagnosticReportUnchanged means the previous report still holds.
+	DocumentDiagnosticReportUnchanged = "unchanged"
+)
+
+// DocumentDiagnosticReport is the result of a textDocument/diagnostic request.
+type DocumentDiagnosticReport struct {
+	// Kind is "full" or "unchanged".
+	Kind string `json:"kind"`
+
+	// ResultID identifies this report for later unchanged responses.
+	ResultID string `json:"resultId,omitempty"`
+
+	// Items are the diagnostics. Only set for full reports.
+	Items []Diagnostic `json:"items,omitempty"`
+}
+
+// =============================================================================
+// SEMANTIC TOKEN TYPES
+// =============================================================================
+
+// SemanticTokensParams contains params for textDocument/semanticTokens/full.
+type SemanticTokensParams struct {
+	// TextDocument is the document to tokenize.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// SemanticTokensRangeParams contains params for textDocument/semanticTokens/range.
+type SemanticTokensRangeParams struct {
+	// TextDocument is the document to tokenize.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// Range is the range to tokenize.
+	Range Range `json:"range"`
+}
+
+// SemanticTokens is the encoded result of a semantic tokens request.
+type SemanticTokens struct {
+	// ResultID identifies this result for delta requests.
+	ResultID string `json:"resultId,omitempty"`
+
+	// Data holds five integers per token: delta line, delta start
+	// character, length, token type index and token modifier bit set.
+	Data []uint32 `json:"data"`
+}
+
+// SemanticTokensLegend maps the indexes in SemanticTokens.Data to names.
+type SemanticTokensLegend struct {
+	// TokenTypes are the token type names, indexed by token type.
+	TokenTypes []string `json:"tokenTypes"`
+
+	// TokenModifiers are the modifier names, indexed by bit.
+	TokenModifiers []string `json:"tokenModifiers"`
+}
+
+// SemanticTokensOptions describes a server's semantic tokens support.
+type SemanticTokensOptions struct {
+	// Legend is the legend the server encodes tokens with.
+	Legend SemanticTokensLegend `json:"legend"`
+
+	// Range indicates textDocument/semanticTokens/range is supported.
+	Range interface{} `json:"range,omitempty"`
+
+	// Full indicates textDocument/semanticTokens/full is supported.
+	Full interface{} `json:"full,omitempty"`
+}
+
+// SupportsRange returns true if range requests are supported.
+func (o *SemanticTokensOptions) SupportsRange() bool {
+	return o.Range != nil && o.Range != false
+}
+
+// SupportsFull returns true if full document requests are supported.
+func (o *SemanticTokensOptions) SupportsFull() bool {
+	return o.Full != nil && o.Full != false
+}
+
+// =============================================================================
+// CALL HIERARCHY TYPES
+// =============================================================================
+
+// CallHierarchyItem is a function or method in a call hierarchy.
+type CallHierarchyItem struct {
+	// Name is the name of the item.
+	Name string `json:"name"`
+
+	// Kind is the kind of the item.
+	Kind SymbolKind `json:"kind"`
+
+	// Tags are extra attributes, such as deprecated.
+	Tags []int `json:"tags,omitempty"`
+
+	// Detail is extra information, such as the signature.
+	Detail string `json:"detail,omitempty"`
+
+	// URI is the document containing the item.
+	URI string `json:"uri"`
+
+	// Range encloses the item, including its body.
+	Range Range `json:"range"`
+
+	// SelectionRange is the range of the item's name.
+	SelectionRange Range `json:"selectionRange"`
+
+	// Data is server data preserved between prepare and calls requests.
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Path returns the absolute path of the item's document.
+func (i CallHierarchyItem) Path() string {
+	return uriToPath(i.URI)
+}
+
+// CallHierarchyIncomingCallsParams contains params for callHierarchy/incomingCalls.
+type CallHierarchyIncomingCallsParams struct {
+	// Item is the callee, as returned by textDocument/prepareCallHierarchy.
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall is a caller of an item.
+type CallHierarchyIncomingCall struct {
+	// From is the calling function or method.
+	From CallHierarchyItem `json:"from"`
+
+	// FromRanges are the call sites, within From.
+	FromRanges []Range `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCallsParams contains params for callHierarchy/outgoingCalls.
+type CallHierarchyOutgoingCallsParams struct {
+	// Item is the caller, as returned by textDocument/prepareCallHierarchy.
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyOutgoingCall is a callee of an item.
+type CallHierarchyOutgoingCall struct {
+	// To is the called function or method.
+	To CallHierarchyItem `json:"to"`
+
+	// FromRanges are the call sites, within the caller.
+	FromRanges []Range `json:"fromRanges"`
+}
+
 // =============================================================================
 // INITIALIZE TYPES
 // =============================================================================
@@ -348,6 +588,18 @@ type TextDocumentClientCapabilities struct {
 
 	// Rename describes rename support.
 	Rename *RenameCapabilities `json:"rename,omitempty"`
+
+	// PublishDiagnostics describes publishDiagnostics support.
+	PublishDiagnostics *PublishDiagnosticsClientCapabilities `json:"publishDiagnostics,omitempty"`
+
+	// Diagnostic describes pull diagnostics support.
+	Diagnostic *DiagnosticClientCapabilities `json:"diagnostic,omitempty"`
+
+	// SemanticTokens describes semantic tokens support.
+	SemanticTokens *SemanticTokensClientCapabilities `json:"semanticTokens,omitempty"`
+
+	// CallHierarchy describes call hierarchy support.
+	CallHierarchy *CallHierarchyClientCapabilities `json:"callHierarchy,omitempty"`
 }
 
 // TextDocumentSyncClientCapabilities describes sync capabilities.
@@ -422,6 +674,55 @@ type RenameCapabilities struct {
 	PrepareSupport bool `json:"prepareSupport,omitempty"`
 }
 
+// PublishDiagnosticsClientCapabilities describes publishDiagnostics support.
+type PublishDiagnosticsClientCapabilities struct {
+	// RelatedInformation indicates related diagnostic information is supported.
+	RelatedInformation bool `json:"relatedInformation,omitempty"`
+
+	// VersionSupport indicates the client interprets the version property.
+	VersionSupport bool `json:"versionSupport,omitempty"`
+}
+
+// DiagnosticClientCapabilities describes pull diagnostics support.
+type DiagnosticClientCapabilities struct {
+	// DynamicRegistration indicates dynamic registration is supported.
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+
+	// RelatedDocumentSupport indicates related document reports are supported.
+	RelatedDocumentSupport bool `json:"relatedDocumentSupport,omitempty"`
+}
+
+// SemanticTokensClientCapabilities describes semantic tokens support.
+type SemanticTokensClientCapabilities struct {
+	// Requests describes which requests the client sends.
+	Requests SemanticTokensClientRequests `json:"requests"`
+
+	// TokenTypes are the token types the client understands.
+	TokenTypes []string `json:"tokenTypes"`
+
+	// TokenModifiers are the token modifiers the client understands.
+	TokenModifiers []string `json:"tokenModifiers"`
+
+	// Formats are the token formats the client supports ("relative").
+	Formats []string `json:"formats"`
+}
+
+// CallHierarchyClientCapabilities describes call hierarchy support.
+type CallHierarchyClientCapabilities struct {
+	// DynamicRegistration indicates dynamic registration is supported.
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+// SemanticTokensClientRequests describes which semantic tokens requests
+// the client sends.
+type SemanticTokensClientRequests struct {
+	// Range indicates range requests are sent.
+	Range bool `json:"range,omitempty"`
+
+	// Full indicates full document requests are sent.
+	Full bool `json:"full,omitempty"`
+}
+
 // InitializeResult contains the server's response to initialize.
 type InitializeResult struct {
 	// Capabilities describes what the server supports.
@@ -459,6 +760,15 @@ type ServerCapabilities struct {
 
 	// WorkspaceSymbolProvider indicates workspace/symbol is supported.
 	WorkspaceSymbolProvider interface{} `json:"workspaceSymbolProvider,omitempty"`
+
+	// DiagnosticProvider indicates textDocument/diagnostic is supported.
+	DiagnosticProvider interface{} `json:"diagnosticProvider,omitempty"`
+
+	// SemanticTokensProvider describes semantic tokens support and legend.
+	SemanticTokensProvider interface{} `json:"semanticTokensProvider,omitempty"`
+
+	// CallHierarchyProvider indicates call hierarchy requests are supported.
+	CallHierarchyProvider interface{} `json:"callHierarchyProvider,omitempty"`
 }
 
 // HasDefinitionProvider returns true if definition is supported.
@@ -485,3 +795,34 @@ func (c *ServerCapabilities) HasRenameProvider() bool {
 func (c *ServerCapabilities) HasWorkspaceSymbolProvider() bool {
 	return c.WorkspaceSymbolProvider != nil && c.WorkspaceSymbolProvider != false
 }
+
+// HasDiagnosticProvider returns true if textDocument/diagnostic is supported.
+func (c *ServerCapabilities) HasDiagnosticProvider() bool {
+	return c.DiagnosticProvider != nil && c.DiagnosticProvider != false
+}
+
+// HasCallHierarchyProvider returns true if call hierarchy is supported.
+func (c *ServerCapabilities) HasCallHierarchyProvider() bool {
+	return c.CallHierarchyProvider != nil && c.CallHierarchyProvider != false
+}
+
+// SemanticTokensOptions returns the server's semantic tokens options.
+//
+// Description:
+//
+//	Decodes SemanticTokensProvider. Returns false if the server does not
+//	provide semantic tokens or its options cannot be decoded.
+func (c *ServerCapabilities) SemanticTokensOptions() (*SemanticTokensOptions, bool) {
+	if c.SemanticTokensProvider == nil || c.SemanticTokensProvider == false {
+		return nil, false
+	}
+	data, err := json.Marshal(c.SemanticTokensProvider)
+	if err != nil {
+		return nil, false
+	}
+	var opts SemanticTokensOptions
+	if err := json.Unmarshal(data, &opts); err != nil || len(opts.Legend.TokenTypes) == 0 {
+		return nil, false
+	}
+	return &opts, true
+}
@@ -339,3 +339,38 @@ func TestServerCapabilities_HasProviders(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagnosticSeverity_String(t *testing.T) {
+	tests := []struct {
+		severity DiagnosticSeverity
+		want     string
+	}{
+		{0, "error"},
+		{DiagnosticSeverityError, "error"},
+		{DiagnosticSeverityWarning, "warning"},
+		{DiagnosticSeverityInformation, "information"},
+		{DiagnosticSeverityHint, "hint"},
+		{9, "unknown"},
+	}
+	for _, tc := range tests {
+		if got := tc.severity.String(); got != tc.want {
+			t.Errorf("DiagnosticSeverity(%d).String() = %q, want %q", tc.severity, got, tc.want)
+		}
+	}
+}
+
+func TestDocumentDiagnosticReport_UnmarshalJSON(t *testing.T) {
+	data := `{"kind":"full","resultId":"r1","items":[{"range":{"start":{"line":3,"character":1},"end":{"line":3,"character":4}},"severity":2,"code":"SA1000","source":"staticcheck","message":"bad"}]}`
+
+	var report DocumentDiagnosticReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if report.Kind != DocumentDiagnosticReportFull || report.ResultID != "r1" || len(report.Items) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	d := report.Items[0]
+	if d.Severity != DiagnosticSeverityWarning || d.IsError() || d.Code != "SA1000" || d.Range.Start.Line != 3 {
+		t.Errorf("diagnostic = %+v", d)
+	}
+}
@@ -17,6 +17,11 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
 )
 
 // =============================================================================
@@ -35,6 +40,9 @@ func TestService_LSPConfig(t *testing.T) {
 	if config.LSPRequestTimeout != 10*time.Second {
 		t.Errorf("LSPRequestTimeout = %v, want 10s", config.LSPRequestTimeout)
 	}
+	if !config.LSPCallers {
+		t.Error("LSPCallers should be enabled by default")
+	}
 }
 
 func TestNewService_InitializesLSPManagers(t *testing.T) {
@@ -179,6 +187,94 @@ func TestLspSymbolsToAPI(t *testing.T) {
 	})
 }
 
+func TestNameColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(testGoProject), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	col, ok := nameColumn(path, &ast.Symbol{Name: "helper", StartLine: 7})
+	if !ok || col != 5 {
+		t.Errorf("nameColumn(helper) = %d, %v; want 5", col, ok)
+	}
+	if _, ok := nameColumn(path, &ast.Symbol{Name: "missing", StartLine: 7}); ok {
+		t.Error("nameColumn should fail for a name not on the line")
+	}
+	if _, ok := nameColumn(path, &ast.Symbol{Name: "helper", StartLine: 100}); ok {
+		t.Error("nameColumn should fail for a line past the end of the file")
+	}
+}
+
+func TestLspCallerSymbol(t *testing.T) {
+	idx := index.NewSymbolIndex()
+	if err := idx.Add(&ast.Symbol{ID: "main.go:3:main", Name: "main", Kind: ast.SymbolKindFunction,
+		FilePath: "main.go", StartLine: 3, EndLine: 5, Language: "go"}); err != nil {
+		t.Fatal(err)
+	}
+	cached := &CachedGraph{Index: idx, ProjectRoot: "/tmp/project"}
+	fileSymbols := make(map[string][]*ast.Symbol)
+
+	t.Run("graph symbol", func(t *testing.T) {
+		info := lspCallerSymbol(cached, lsp.CallHierarchyItem{
+			Name:           "main",
+			Kind:           lsp.SymbolKindFunction,
+			URI:            "file:///tmp/project/main.go",
+			Range:          lsp.Range{Start: lsp.Position{Line: 2}, End: lsp.Position{Line: 4, Character: 1}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 2, Character: 5}, End: lsp.Position{Line: 2, Character: 9}},
+		}, fileSymbols)
+		if info.ID != "main.go:3:main" {
+			t.Errorf("ID = %q, want the indexed symbol", info.ID)
+		}
+	})
+
+	t.Run("not in graph", func(t *testing.T) {
+		info := lspCallerSymbol(cached, lsp.CallHierarchyItem{
+			Name:           "init",
+			Kind:           lsp.SymbolKindFunction,
+			Detail:         "func()",
+			URI:            "file:///tmp/project/gen/zz.go",
+			Range:          lsp.Range{Start: lsp.Position{Line: 9}, End: lsp.Position{Line: 12}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 9, Character: 5}, End: lsp.Position{Line: 9, Character: 9}},
+		}, fileSymbols)
+		want := SymbolInfo{ID: "gen/zz.go:10:init", Name: "init", Kind: "function", FilePath: "gen/zz.go",
+			StartLine: 10, EndLine: 13, Signature: "func()"}
+		if *info != want {
+			t.Errorf("info = %+v, want %+v", *info, want)
+		}
+	})
+}
+
+func TestService_FindCallers_NoLanguageServer(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	defer svc.Close(context.Background())
+
+	g := graph.NewGraph("/tmp/project")
+	for _, sym := range []*ast.Symbol{
+		{ID: "main.cobol:1:main", Name: "main", Kind: ast.SymbolKindFunction, FilePath: "main.cobol", StartLine: 1, EndLine: 3},
+		{ID: "main.cobol:5:helper", Name: "helper", Kind: ast.SymbolKindFunction, FilePath: "main.cobol", StartLine: 5, EndLine: 7},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge("main.cobol:1:main", "main.cobol:5:helper", graph.EdgeTypeCalls, ast.Location{}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+	svc.mu.Lock()
+	svc.graphs["g1"] = &CachedGraph{Graph: g, ProjectRoot: "/tmp/project"}
+	svc.mu.Unlock()
+
+	// No server handles .cobol, so the graph answers.
+	callers, err := svc.FindCallers(context.Background(), "g1", "helper", 0)
+	if err != nil {
+		t.Fatalf("FindCallers: %v", err)
+	}
+	if len(callers) != 1 || callers[0].Name != "main" {
+		t.Errorf("callers = %+v, want main", callers)
+	}
+}
+
 func TestSymbolKindToString(t *testing.T) {
 	tests := []struct {
 		kind     int
@@ -427,3 +523,38 @@ func TestService_LSPIntegration_CloseShutdownsManagers(t *testing.T) {
 		t.Errorf("expected 0 managers after Close, got %d", managerCount)
 	}
 }
+
+func TestService_LSPIntegration_FindCallers(t *testing.T) {
+	if _, err := exec.LookPath("gopls"); err != nil {
+		t.Skip("gopls not installed")
+	}
+
+	dir := setupTestProject(t)
+
+	svc := NewService(DefaultServiceConfig())
+	defer svc.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	initResp, err := svc.Init(ctx, dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	callers, err := svc.FindCallers(ctx, initResp.GraphID, "helper", 0)
+	if err != nil {
+		t.Fatalf("FindCallers: %v", err)
+	}
+	if len(callers) != 1 || callers[0].Name != "main" {
+		t.Fatalf("callers = %+v, want main", callers)
+	}
+
+	// The answer came from gopls, which started a manager for the graph.
+	svc.lspMu.RLock()
+	_, exists := svc.lspManagers[initResp.GraphID]
+	svc.lspMu.RUnlock()
+	if !exists {
+		t.Error("FindCallers should have used the LSP manager")
+	}
+}
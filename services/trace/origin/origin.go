@@ -0,0 +1,280 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package origin classifies source files as hand-written, generated, or
+// vendored, and provides a shared filter that analyzers use to exclude or
+// down-weight non-authored code.
+//
+// # Description
+//
+// Generated code (protobuf stubs, mocks, stringer output) and vendored
+// third-party trees skew impact, dead-code, duplication, and lint results:
+// they are large, repetitive, and not something the user should edit.
+// Detection happens once at parse time; the result is propagated to every
+// symbol in the file so downstream analyzers only need to consult a Filter.
+//
+// This package has no dependencies on the parser so it can be used by
+// path/content-based consumers (lint) as well as symbol-based ones.
+//
+// # Thread Safety
+//
+// All functions are safe for concurrent use. Filter is a value type.
+package origin
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Kind classifies where a file's code came from.
+type Kind int
+
+const (
+	// KindSource is hand-written project code.
+	KindSource Kind = iota
+
+	// KindGenerated is code emitted by a generator (protoc, mockgen, stringer, ...).
+	KindGenerated
+
+	// KindVendored is third-party code copied into the tree.
+	KindVendored
+)
+
+// String returns the string representation of the Kind.
+func (k Kind) String() string {
+	switch k {
+	case KindSource:
+		return "source"
+	case KindGenerated:
+		return "generated"
+	case KindVendored:
+		return "vendored"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler so Kind serializes as its name.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *Kind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "generated":
+		*k = KindGenerated
+	case "vendored":
+		*k = KindVendored
+	default:
+		*k = KindSource
+	}
+	return nil
+}
+
+// MaxHeaderBytes is how much of a file is scanned for generator markers.
+const MaxHeaderBytes = 4096
+
+// generatedHeader matches the Go convention (https://go.dev/s/generatedcode)
+// plus the common markers emitted by other toolchains.
+var generatedHeader = regexp.MustCompile(
+	`(?m)^\s*(//|#|/\*|\*|--)?\s*(Code generated .* DO NOT EDIT\.?|@generated|Generated by the protocol buffer compiler|This file was automatically generated|Autogenerated by|AUTO-GENERATED FILE|DO NOT EDIT(!|\.)?\s*$)`,
+)
+
+// generatedSuffixes are file name suffixes produced by well-known generators.
+var generatedSuffixes = []string{
+	".pb.go",
+	".pb.gw.go",
+	"_grpc.pb.go",
+	".pb.validate.go",
+	"_gen.go",
+	".gen.go",
+	"_generated.go",
+	"_pb2.py",
+	"_pb2_grpc.py",
+	"_pb2.pyi",
+	".generated.ts",
+}
+
+// generatedPrefixes are file name prefixes produced by mock generators.
+var generatedPrefixes = []string{
+	"mock_",
+	"zz_generated",
+}
+
+// vendorDirs are directory names whose contents are third-party code.
+var vendorDirs = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"third_party":      true,
+	"bower_components": true,
+	"site-packages":    true,
+}
+
+// Detect classifies a file by path and, when content is non-nil, by the
+// generator markers in its header.
+//
+// # Inputs
+//
+//   - filePath: Path relative to the project root (either separator).
+//   - content: File content, or nil to classify by path only.
+//
+// # Outputs
+//
+//   - Kind: KindVendored takes precedence over KindGenerated.
+func Detect(filePath string, content []byte) Kind {
+	if IsVendoredPath(filePath) {
+		return KindVendored
+	}
+	if IsGeneratedPath(filePath) || HasGeneratedHeader(content) {
+		return KindGenerated
+	}
+	return KindSource
+}
+
+// IsVendoredPath reports whether any directory component of the path is a
+// well-known vendor directory.
+func IsVendoredPath(filePath string) bool {
+	dir := filepath.ToSlash(filepath.Dir(filePath))
+	for _, part := range strings.Split(dir, "/") {
+		if vendorDirs[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGeneratedPath reports whether the file name matches a generator naming
+// convention.
+func IsGeneratedPath(filePath string) bool {
+	base := filepath.Base(filePath)
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(base, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasGeneratedHeader reports whether the first MaxHeaderBytes of content
+// contain a generator marker.
+func HasGeneratedHeader(content []byte) bool {
+	if len(content) == 0 {
+		return false
+	}
+	if len(content) > MaxHeaderBytes {
+		content = content[:MaxHeaderBytes]
+		// Avoid matching a marker split across the cut.
+		if i := bytes.LastIndexByte(content, '\n'); i > 0 {
+			content = content[:i]
+		}
+	}
+	return generatedHeader.Match(content)
+}
+
+// Policy controls how a Filter treats a non-source Kind.
+type Policy int
+
+const (
+	// PolicyInclude treats the code like hand-written source.
+	PolicyInclude Policy = iota
+
+	// PolicyDownWeight keeps the code but scales its contribution by
+	// Filter.DownWeight.
+	PolicyDownWeight
+
+	// PolicyExclude drops the code from the analysis.
+	PolicyExclude
+)
+
+// DefaultDownWeight is the weight applied to down-weighted code.
+const DefaultDownWeight = 0.25
+
+// Filter is the shared API analyzers use to decide whether to consider a
+// piece of code and how much it should count.
+type Filter struct {
+	// Generated is the policy for KindGenerated.
+	Generated Policy
+
+	// Vendored is the policy for KindVendored.
+	Vendored Policy
+
+	// DownWeight is the weight (0.0-1.0) for PolicyDownWeight.
+	// Default: 0.25
+	DownWeight float64
+}
+
+// DefaultFilter excludes vendored code and down-weights generated code.
+func DefaultFilter() Filter {
+	return Filter{
+		Generated:  PolicyDownWeight,
+		Vendored:   PolicyExclude,
+		DownWeight: DefaultDownWeight,
+	}
+}
+
+// ExcludeAllFilter excludes both generated and vendored code.
+func ExcludeAllFilter() Filter {
+	return Filter{
+		Generated:  PolicyExclude,
+		Vendored:   PolicyExclude,
+		DownWeight: DefaultDownWeight,
+	}
+}
+
+// IncludeAllFilter treats all code as source.
+func IncludeAllFilter() Filter {
+	return Filter{DownWeight: 1.0}
+}
+
+// policyFor returns the policy for a Kind.
+func (f Filter) policyFor(k Kind) Policy {
+	switch k {
+	case KindGenerated:
+		return f.Generated
+	case KindVendored:
+		return f.Vendored
+	default:
+		return PolicyInclude
+	}
+}
+
+// Include reports whether code of the given Kind should be analyzed.
+func (f Filter) Include(k Kind) bool {
+	return f.policyFor(k) != PolicyExclude
+}
+
+// Weight returns the contribution (0.0-1.0) of code of the given Kind.
+// Excluded kinds weigh 0.
+func (f Filter) Weight(k Kind) float64 {
+	switch f.policyFor(k) {
+	case PolicyExclude:
+		return 0
+	case PolicyDownWeight:
+		if f.DownWeight <= 0 || f.DownWeight > 1 {
+			return DefaultDownWeight
+		}
+		return f.DownWeight
+	default:
+		return 1
+	}
+}
+
+// IncludeFile classifies a file and applies the filter in one step.
+func (f Filter) IncludeFile(filePath string, content []byte) bool {
+	return f.Include(Detect(filePath, content))
+}
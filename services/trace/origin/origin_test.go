@@ -0,0 +1,138 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package origin
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    Kind
+	}{
+		{"plain source", "pkg/server.go", "package pkg\n", KindSource},
+		{"go generated header", "pkg/types.go", "// Code generated by stringer; DO NOT EDIT.\n\npackage pkg\n", KindGenerated},
+		{"protobuf suffix", "api/v1/service.pb.go", "", KindGenerated},
+		{"grpc suffix", "api/v1/service_grpc.pb.go", "", KindGenerated},
+		{"python protobuf", "proto/user_pb2.py", "", KindGenerated},
+		{"mockgen prefix", "internal/mocks/mock_store.go", "", KindGenerated},
+		{"at-generated marker", "src/schema.ts", "/**\n * @generated\n */\nexport {}\n", KindGenerated},
+		{"python header", "gen/models.py", "# This file was automatically generated\nclass A: pass\n", KindGenerated},
+		{"vendor dir", "vendor/github.com/x/y/y.go", "package y\n", KindVendored},
+		{"node_modules", "web/node_modules/react/index.js", "", KindVendored},
+		{"vendor wins over generated", "vendor/x/x.pb.go", "", KindVendored},
+		{"marker mid-file ignored", "pkg/a.go", "package a\n\nvar s = \"Code generated x DO NOT EDIT.\"\n", KindSource},
+		{"file named vendor.go", "pkg/vendor.go", "package pkg\n", KindSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content []byte
+			if tt.content != "" {
+				content = []byte(tt.content)
+			}
+			if got := Detect(tt.path, content); got != tt.want {
+				t.Errorf("Detect(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasGeneratedHeader_OnlyScansHeader(t *testing.T) {
+	content := strings.Repeat("// filler line\n", MaxHeaderBytes/15+10) +
+		"// Code generated by tool. DO NOT EDIT.\n"
+	if HasGeneratedHeader([]byte(content)) {
+		t.Error("marker beyond MaxHeaderBytes should be ignored")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		f := DefaultFilter()
+		if !f.Include(KindSource) || !f.Include(KindGenerated) || f.Include(KindVendored) {
+			t.Error("default should include source and generated, exclude vendored")
+		}
+		if f.Weight(KindSource) != 1 {
+			t.Errorf("source weight = %v, want 1", f.Weight(KindSource))
+		}
+		if f.Weight(KindGenerated) != DefaultDownWeight {
+			t.Errorf("generated weight = %v, want %v", f.Weight(KindGenerated), DefaultDownWeight)
+		}
+		if f.Weight(KindVendored) != 0 {
+			t.Errorf("vendored weight = %v, want 0", f.Weight(KindVendored))
+		}
+	})
+
+	t.Run("exclude all", func(t *testing.T) {
+		f := ExcludeAllFilter()
+		if f.Include(KindGenerated) || f.Include(KindVendored) {
+			t.Error("expected generated and vendored to be excluded")
+		}
+	})
+
+	t.Run("include all", func(t *testing.T) {
+		f := IncludeAllFilter()
+		for _, k := range []Kind{KindSource, KindGenerated, KindVendored} {
+			if !f.Include(k) || f.Weight(k) != 1 {
+				t.Errorf("kind %v: include=%v weight=%v", k, f.Include(k), f.Weight(k))
+			}
+		}
+	})
+
+	t.Run("invalid down weight falls back to default", func(t *testing.T) {
+		f := Filter{Generated: PolicyDownWeight, DownWeight: 5}
+		if f.Weight(KindGenerated) != DefaultDownWeight {
+			t.Errorf("weight = %v, want %v", f.Weight(KindGenerated), DefaultDownWeight)
+		}
+	})
+
+	t.Run("include file", func(t *testing.T) {
+		f := ExcludeAllFilter()
+		if f.IncludeFile("api/service.pb.go", nil) {
+			t.Error("expected protobuf file to be excluded")
+		}
+		if !f.IncludeFile("api/service.go", []byte("package api\n")) {
+			t.Error("expected source file to be included")
+		}
+	})
+}
+
+func TestKind_JSON(t *testing.T) {
+	type wrapper struct {
+		Origin Kind `json:"origin,omitempty"`
+	}
+
+	data, err := json.Marshal(wrapper{Origin: KindGenerated})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"origin":"generated"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+
+	data, _ = json.Marshal(wrapper{})
+	if string(data) != `{}` {
+		t.Errorf("source origin should be omitted, got %s", data)
+	}
+
+	var w wrapper
+	if err := json.Unmarshal([]byte(`{"origin":"vendored"}`), &w); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if w.Origin != KindVendored {
+		t.Errorf("Unmarshal = %v, want vendored", w.Origin)
+	}
+}
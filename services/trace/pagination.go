@@ -0,0 +1,250 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultPageLimit is the page size when the request has no limit.
+	DefaultPageLimit = 50
+
+	// MaxPageLimit is the largest page size a request may ask for.
+	MaxPageLimit = 500
+
+	// cursorVersion prefixes encoded cursors so the format can change.
+	cursorVersion = "v1"
+)
+
+// PageInfo describes the page returned by a list endpoint.
+//
+// Every list endpoint adds it to its response as "page".
+type PageInfo struct {
+	// Limit is the page size used.
+	Limit int `json:"limit"`
+
+	// NextCursor fetches the next page when passed as ?cursor=. Empty on
+	// the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Total is the number of items across all pages, when known.
+	Total *int `json:"total,omitempty"`
+}
+
+// pageRequest is a parsed page request.
+type pageRequest struct {
+	limit  int
+	offset int
+	fields []string
+	route  string
+
+	// base is the offset of the first item in the list being paged. It is
+	// non-zero when the backend already skipped offset items.
+	base int
+}
+
+// parsePage reads the pagination query parameters shared by all list
+// endpoints, writing a 400 response if they are invalid.
+//
+// Description:
+//
+//	limit  - Page size (default DefaultPageLimit, at most MaxPageLimit)
+//	cursor - Opaque cursor from a previous page's next_cursor
+//	fields - Comma-separated item fields to return (default: all)
+//
+//	The parameters are read from the query string on GET and POST alike,
+//	so they do not collide with request body fields. A cursor is only
+//	valid for the route that issued it.
+//
+// Outputs:
+//
+//	pageRequest - The parsed request.
+//	bool - False if a response was already written.
+func parsePage(c *gin.Context) (pageRequest, bool) {
+	p := pageRequest{limit: DefaultPageLimit, route: c.FullPath()}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "limit must be a positive integer",
+				Code:  "INVALID_REQUEST",
+			})
+			return p, false
+		}
+		p.limit = min(n, MaxPageLimit)
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		offset, err := decodeCursor(v, p.route)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   err.Error(),
+				Code:    "INVALID_CURSOR",
+				Details: "Pass next_cursor from the previous page of the same endpoint",
+			})
+			return p, false
+		}
+		p.offset = offset
+	}
+
+	if v := c.Query("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				p.fields = append(p.fields, f)
+			}
+		}
+	}
+	return p, true
+}
+
+// fetchLimit is how many items a backend must return, counting from the
+// start of the list, to fill this page and detect a next page.
+func (p pageRequest) fetchLimit() int {
+	return p.offset + p.limit + 1
+}
+
+// fetchedFromOffset returns the request for a list the backend returned
+// starting at p.offset rather than at the start.
+func (p pageRequest) fetchedFromOffset() pageRequest {
+	p.base = p.offset
+	return p
+}
+
+// writePage writes body as JSON with one of its lists paged.
+//
+// Description:
+//
+//	listPath names the list inside body's JSON encoding, as dot-separated
+//	keys (e.g. "callers" or "result.entry_points"). The list is cut to the
+//	requested page, its items are reduced to the requested fields, and a
+//	"page" object with PageInfo is added at the top level.
+//
+// Inputs:
+//
+//	c - The request context.
+//	body - The full response body. Must encode to a JSON object.
+//	listPath - Location of the list to page.
+//	p - The parsed page request.
+//	complete - True if the list holds every item, so the total is known.
+func writePage(c *gin.Context, body any, listPath string, p pageRequest, complete bool) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to encode response",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	var root map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&root); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to encode response",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	keys := strings.Split(listPath, ".")
+	parent := root
+	for _, k := range keys[:len(keys)-1] {
+		next, _ := parent[k].(map[string]any)
+		if next == nil {
+			next = make(map[string]any)
+		}
+		parent = next
+	}
+	last := keys[len(keys)-1]
+	items, _ := parent[last].([]any)
+
+	info := PageInfo{Limit: p.limit}
+	start := min(max(p.offset-p.base, 0), len(items))
+	end := min(start+p.limit, len(items))
+	if end < len(items) {
+		info.NextCursor = encodeCursor(p.route, p.base+end)
+	}
+	if complete {
+		total := p.base + len(items)
+		info.Total = &total
+	}
+
+	page := make([]any, 0, end-start)
+	for _, item := range items[start:end] {
+		page = append(page, selectFields(item, p.fields))
+	}
+	if _, ok := parent[last]; ok {
+		parent[last] = page
+	}
+	root["page"] = info
+
+	c.JSON(http.StatusOK, root)
+}
+
+// selectFields keeps only the named fields of an object item. Items that
+// are not objects, and all items when fields is empty, are returned as is.
+func selectFields(item any, fields []string) any {
+	obj, ok := item.(map[string]any)
+	if !ok || len(fields) == 0 {
+		return item
+	}
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if v, ok := obj[f]; ok {
+			out[f] = v
+		}
+	}
+	return out
+}
+
+// errCursorMalformed indicates a cursor that was not issued by writePage.
+var errCursorMalformed = errors.New("malformed cursor")
+
+// encodeCursor returns the opaque cursor for offset on route.
+func encodeCursor(route string, offset int) string {
+	raw := cursorVersion + ":" + route + ":" + strconv.Itoa(offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns the offset in a cursor issued for route.
+func decodeCursor(cursor, route string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errCursorMalformed
+	}
+	rest, ok := strings.CutPrefix(string(raw), cursorVersion+":")
+	if !ok {
+		return 0, errors.New("unsupported cursor version")
+	}
+	i := strings.LastIndexByte(rest, ':')
+	if i < 0 {
+		return 0, errCursorMalformed
+	}
+	if rest[:i] != route {
+		return 0, errors.New("cursor was issued by another endpoint")
+	}
+	offset, err := strconv.Atoi(rest[i+1:])
+	if err != nil || offset < 0 {
+		return 0, errCursorMalformed
+	}
+	return offset, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // DeadCodeExclusions configures what to exclude from dead code detection.
@@ -103,6 +104,10 @@ type DeadCodeOptions struct {
 
 	// MaxResults limits the number of results (0 = unlimited).
 	MaxResults int
+
+	// Origin controls how generated and vendored code is treated.
+	// Nil excludes both: unreferenced generated code is expected.
+	Origin *origin.Filter
 }
 
 // DefaultDeadCodeOptions returns sensible defaults.
@@ -194,6 +199,11 @@ func (d *DeadCodeFinder) FindDeadCode(
 		opts.Exclusions = DefaultExclusions()
 	}
 
+	originFilter := origin.ExcludeAllFilter()
+	if opts.Origin != nil {
+		originFilter = *opts.Origin
+	}
+
 	var results []DeadCode
 
 	// Build a set of referenced symbols
@@ -217,6 +227,11 @@ func (d *DeadCodeFinder) FindDeadCode(
 			continue
 		}
 
+		// Skip generated/vendored code unless the filter keeps it
+		if !originFilter.Include(sym.Origin()) {
+			continue
+		}
+
 		// Check exclusions
 		excluded, reason := d.isExcluded(sym, opts)
 		if excluded {
@@ -224,7 +239,7 @@ func (d *DeadCodeFinder) FindDeadCode(
 		}
 
 		// Calculate confidence based on how certain we are
-		confidence := d.calculateConfidence(sym, opts, reason)
+		confidence := d.calculateConfidence(sym, opts, reason) * originFilter.Weight(sym.Origin())
 
 		results = append(results, DeadCode{
 			Type:       sym.Kind.String(),
@@ -22,6 +22,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // DuplicationType categorizes duplicates.
@@ -51,6 +52,10 @@ type DuplicationOptions struct {
 
 	// MaxResults limits the number of results (0 = unlimited).
 	MaxResults int
+
+	// Origin controls how generated and vendored code is treated.
+	// Nil excludes both: generated code is repetitive by construction.
+	Origin *origin.Filter
 }
 
 // DefaultDuplicationOptions returns sensible defaults.
@@ -165,6 +170,11 @@ func (d *DuplicationFinder) BuildIndex(ctx context.Context, opts *DuplicationOpt
 			continue
 		}
 
+		// Skip generated/vendored code unless the filter keeps it
+		if !opts.originFilter().Include(sym.Origin()) {
+			continue
+		}
+
 		// Skip symbols below minimum lines
 		lineCount := sym.EndLine - sym.StartLine + 1
 		if lineCount < opts.MinLines {
@@ -182,6 +192,7 @@ func (d *DuplicationFinder) BuildIndex(ctx context.Context, opts *DuplicationOpt
 		if fp == nil {
 			continue
 		}
+		fp.Origin = sym.Origin()
 
 		d.lshIndex.Add(fp)
 		count++
@@ -288,7 +299,7 @@ func (d *DuplicationFinder) FindDuplication(
 				},
 			},
 			Suggestion: d.generateSuggestion(fp1, fp2, dupType),
-			Confidence: d.calculateConfidence(pair.Similarity, dupType),
+			Confidence: d.calculateConfidence(pair.Similarity, dupType) * originWeight(opts.originFilter(), fp1, fp2),
 		}
 
 		results = append(results, dup)
@@ -369,6 +380,7 @@ func (d *DuplicationFinder) FindDuplicatesOf(
 		if queryFP == nil {
 			return nil, fmt.Errorf("failed to fingerprint symbol")
 		}
+		queryFP.Origin = sym.Origin()
 	}
 
 	// Query for similar
@@ -408,7 +420,7 @@ func (d *DuplicationFinder) FindDuplicatesOf(
 				},
 			},
 			Suggestion: d.generateSuggestion(queryFP, matchFP, dupType),
-			Confidence: d.calculateConfidence(match.Similarity, dupType),
+			Confidence: d.calculateConfidence(match.Similarity, dupType) * originWeight(opts.originFilter(), queryFP, matchFP),
 		}
 
 		results = append(results, dup)
@@ -573,3 +585,17 @@ type DuplicationStats struct {
 	// MaxBucketSize is the largest bucket size.
 	MaxBucketSize int
 }
+
+// originFilter returns the configured origin filter or the default for
+// duplication (exclude generated and vendored code).
+func (o *DuplicationOptions) originFilter() origin.Filter {
+	if o.Origin != nil {
+		return *o.Origin
+	}
+	return origin.ExcludeAllFilter()
+}
+
+// originWeight returns the weight of the less-trusted side of a pair.
+func originWeight(f origin.Filter, fp1, fp2 *CodeFingerprint) float64 {
+	return min(f.Weight(fp1.Origin), f.Weight(fp2.Origin))
+}
@@ -16,6 +16,7 @@ import (
 	"unicode"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/origin"
 )
 
 // FingerprintConfig configures code fingerprinting behavior.
@@ -80,6 +81,9 @@ type CodeFingerprint struct {
 
 	// Complexity is a cyclomatic complexity estimate.
 	Complexity int
+
+	// Origin is the source classification of the symbol's file.
+	Origin origin.Kind
 }
 
 // Fingerprinter creates code fingerprints from symbols.
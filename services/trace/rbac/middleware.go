@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package rbac
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey is the gin context key holding the request's Principal.
+const principalKey = "rbac.principal"
+
+// Middleware returns gin middleware that enforces the policy.
+//
+// # Description
+//
+// The API key is read from "Authorization: Bearer <key>" or "X-API-Key".
+// Routes are matched by their registered pattern, so the middleware must
+// be installed on the router or a group before routes are added.
+//
+// # Responses
+//
+//   - 401 Unauthorized: Missing or unknown key on a protected route.
+//   - 403 Forbidden: The key's role is below the route's required role.
+func Middleware(p *Policy) gin.HandlerFunc {
+	logger := slog.Default().With("component", "rbac")
+
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+
+		principal, err := p.Authorize(APIKey(c.Request), c.Request.Method, path)
+		if err != nil {
+			status, code := http.StatusUnauthorized, "UNAUTHENTICATED"
+			if errors.Is(err, ErrForbidden) {
+				status, code = http.StatusForbidden, "FORBIDDEN"
+			}
+			logger.Warn("request denied",
+				slog.String("method", c.Request.Method),
+				slog.String("path", path),
+				slog.String("key", principal.Name),
+				slog.String("error", err.Error()))
+			c.AbortWithStatusJSON(status, gin.H{
+				"error": err.Error(),
+				"code":  code,
+			})
+			return
+		}
+
+		if principal.Name != "" {
+			c.Set(principalKey, principal)
+		}
+		c.Next()
+	}
+}
+
+// APIKey extracts the API key from a request, or "" if none is present.
+func APIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if scheme, key, ok := strings.Cut(auth, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(key)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// PrincipalFrom returns the authenticated principal for a request handled
+// by Middleware. ok is false for unauthenticated public requests or when
+// RBAC is disabled.
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	p, ok := v.(Principal)
+	return p, ok
+}
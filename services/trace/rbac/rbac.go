@@ -0,0 +1,337 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package rbac enforces role-based access control on trace endpoints.
+//
+// # Description
+//
+// A Policy maps API keys to one of three ordered roles:
+//
+//   - viewer: Query graphs, symbols, and analyses.
+//   - analyst: Everything a viewer can do, plus build graphs, seed
+//     documentation, record memories, and request patch reviews.
+//   - operator: Everything, including running agents (which can apply
+//     patches) and deleting data.
+//
+// Each request's route is matched against the policy's rules, then the
+// built-in DefaultRules; the first match gives the minimum role. Routes
+// that match no rule need operator for non-GET methods and viewer for GET,
+// so newly added mutating endpoints fail closed.
+//
+// The policy file is the same file that lists API keys:
+//
+//	keys:
+//	  - name: dashboard
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    role: viewer
+//	  - name: ci
+//	    sha256: 60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752
+//	    role: operator
+//	rules:
+//	  - method: POST
+//	    path: /v1/codebuddy/init
+//	    role: operator
+//
+// Keys are stored as the hex SHA-256 of the key (see HashKey) so the file
+// does not hold usable credentials.
+//
+// # Thread Safety
+//
+// Policy is immutable after loading and safe for concurrent use.
+package rbac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors returned by the rbac package.
+var (
+	// ErrInvalidPolicy indicates a malformed policy file.
+	ErrInvalidPolicy = errors.New("invalid access policy")
+
+	// ErrUnauthenticated indicates a missing or unknown API key.
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden indicates the key's role is too low for the route.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Role is an access level. Higher roles include all lower permissions.
+type Role string
+
+const (
+	// RolePublic marks routes that need no API key. It cannot be assigned
+	// to a key.
+	RolePublic Role = "public"
+
+	// RoleViewer can query graphs and analyses.
+	RoleViewer Role = "viewer"
+
+	// RoleAnalyst can also build graphs and record memories.
+	RoleAnalyst Role = "analyst"
+
+	// RoleOperator can also run agents and delete data.
+	RoleOperator Role = "operator"
+)
+
+// rank orders roles; zero means unknown.
+func (r Role) rank() int {
+	switch r {
+	case RolePublic:
+		return 1
+	case RoleViewer:
+		return 2
+	case RoleAnalyst:
+		return 3
+	case RoleOperator:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Allows reports whether r meets the required role.
+func (r Role) Allows(required Role) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Principal is an authenticated API key holder.
+type Principal struct {
+	// Name identifies the key in logs and audit records.
+	Name string `yaml:"name" json:"name"`
+
+	// Role is the key's access level.
+	Role Role `yaml:"role" json:"role"`
+}
+
+// Key is an API key entry in the policy file.
+type Key struct {
+	Name string `yaml:"name"`
+
+	// SHA256 is the lowercase hex SHA-256 of the key.
+	SHA256 string `yaml:"sha256"`
+
+	Role Role `yaml:"role"`
+}
+
+// Rule sets the minimum role for matching routes.
+type Rule struct {
+	// Method is the HTTP method, or empty or "*" for any.
+	Method string `yaml:"method"`
+
+	// Path is the route pattern as registered (e.g. /v1/codebuddy/agent/:id).
+	// A trailing "*" matches any suffix.
+	Path string `yaml:"path"`
+
+	// Role is the minimum role. RolePublic allows unauthenticated access.
+	Role Role `yaml:"role"`
+}
+
+// matches reports whether the rule covers method and path.
+func (r Rule) matches(method, path string) bool {
+	if r.Method != "" && r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return r.Path == path
+}
+
+// DefaultRules returns the built-in route rules, applied after policy rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		// Health probes and provider-authenticated webhooks
+		{Method: http.MethodGet, Path: "/v1/codebuddy/health", Role: RolePublic},
+		{Method: http.MethodGet, Path: "/v1/codebuddy/ready", Role: RolePublic},
+		{Method: http.MethodGet, Path: "/v1/trace/version", Role: RolePublic},
+		{Method: http.MethodPost, Path: "/v1/trace/webhook", Role: RolePublic},
+
+		// Agents can edit files and run commands
+		{Method: http.MethodPost, Path: "/v1/codebuddy/agent/*", Role: RoleOperator},
+
+		// Graph building, seeding, memory writes, and reviews
+		{Method: http.MethodPost, Path: "/v1/codebuddy/init", Role: RoleAnalyst},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/seed", Role: RoleAnalyst},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/memories/retrieve", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/memories*", Role: RoleAnalyst},
+		{Method: http.MethodPost, Path: "/v1/trace/agent/review", Role: RoleAnalyst},
+
+		// Jobs may run graph builds and seeding
+		{Method: http.MethodPost, Path: "/v1/trace/jobs", Role: RoleAnalyst},
+		{Method: http.MethodDelete, Path: "/v1/trace/jobs/*", Role: RoleAnalyst},
+
+		// Read-only queries sent as POST
+		{Method: http.MethodPost, Path: "/v1/codebuddy/context", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/explore/*", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/reason/*", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/coordinate/*", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/codebuddy/patterns/*", Role: RoleViewer},
+		{Method: http.MethodPost, Path: "/v1/trace/search/*", Role: RoleViewer},
+	}
+}
+
+// Policy maps API keys to roles and routes to required roles.
+type Policy struct {
+	keys  map[string]Principal // by key hash
+	rules []Rule
+}
+
+// policyFile is the on-disk policy format.
+type policyFile struct {
+	Keys  []Key  `yaml:"keys"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// NewPolicy creates a policy from keys and extra rules.
+//
+// # Inputs
+//
+//   - keys: API keys. Names and hashes must be unique and roles must be
+//     viewer, analyst, or operator.
+//   - rules: Rules checked before DefaultRules. May be nil.
+//
+// # Outputs
+//
+//   - *Policy: The policy.
+//   - error: ErrInvalidPolicy if a key or rule is malformed.
+func NewPolicy(keys []Key, rules []Rule) (*Policy, error) {
+	p := &Policy{keys: make(map[string]Principal, len(keys))}
+	names := make(map[string]bool, len(keys))
+
+	for i, k := range keys {
+		hash := strings.ToLower(strings.TrimSpace(k.SHA256))
+		if k.Name == "" {
+			return nil, fmt.Errorf("%w: key %d has no name", ErrInvalidPolicy, i)
+		}
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("%w: key %q: sha256 must be 64 hex characters", ErrInvalidPolicy, k.Name)
+		}
+		if !k.Role.Valid() || k.Role == RolePublic {
+			return nil, fmt.Errorf("%w: key %q: unknown role %q", ErrInvalidPolicy, k.Name, k.Role)
+		}
+		if names[k.Name] {
+			return nil, fmt.Errorf("%w: duplicate key name %q", ErrInvalidPolicy, k.Name)
+		}
+		if _, dup := p.keys[hash]; dup {
+			return nil, fmt.Errorf("%w: key %q reuses another key's hash", ErrInvalidPolicy, k.Name)
+		}
+		names[k.Name] = true
+		p.keys[hash] = Principal{Name: k.Name, Role: k.Role}
+	}
+
+	for i, r := range rules {
+		if r.Path == "" {
+			return nil, fmt.Errorf("%w: rule %d has no path", ErrInvalidPolicy, i)
+		}
+		if !r.Role.Valid() {
+			return nil, fmt.Errorf("%w: rule %d: unknown role %q", ErrInvalidPolicy, i, r.Role)
+		}
+	}
+	p.rules = append(append([]Rule(nil), rules...), DefaultRules()...)
+
+	return p, nil
+}
+
+// LoadPolicyFile reads a YAML policy file.
+//
+// # Outputs
+//
+//   - *Policy: The policy.
+//   - error: Non-nil if the file cannot be read or is invalid.
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading access policy: %w", err)
+	}
+
+	var f policyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return NewPolicy(f.Keys, f.Rules)
+}
+
+// HashKey returns the hex SHA-256 of an API key, as stored in the policy.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequiredRole returns the minimum role for a route.
+//
+// # Inputs
+//
+//   - method: HTTP method.
+//   - path: Route pattern as registered (e.g. /v1/codebuddy/agent/:id).
+func (p *Policy) RequiredRole(method, path string) Role {
+	for _, r := range p.rules {
+		if r.matches(method, path) {
+			return r.Role
+		}
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return RoleViewer
+	}
+	return RoleOperator
+}
+
+// Authenticate resolves an API key to its principal.
+//
+// # Outputs
+//
+//   - Principal: The key holder.
+//   - error: ErrUnauthenticated if the key is empty or unknown.
+func (p *Policy) Authenticate(key string) (Principal, error) {
+	if key == "" {
+		return Principal{}, fmt.Errorf("%w: missing API key", ErrUnauthenticated)
+	}
+	principal, ok := p.keys[HashKey(key)]
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: unknown API key", ErrUnauthenticated)
+	}
+	return principal, nil
+}
+
+// Authorize checks whether a key may call a route.
+//
+// # Outputs
+//
+//   - Principal: The key holder; zero for public routes called without a key.
+//   - error: ErrUnauthenticated or ErrForbidden on denial.
+func (p *Policy) Authorize(key, method, path string) (Principal, error) {
+	required := p.RequiredRole(method, path)
+	if required == RolePublic && key == "" {
+		return Principal{}, nil
+	}
+
+	principal, err := p.Authenticate(key)
+	if err != nil {
+		return Principal{}, err
+	}
+	if !principal.Role.Allows(required) {
+		return principal, fmt.Errorf("%w: %s %s requires role %s, key %q has %s",
+			ErrForbidden, method, path, required, principal.Name, principal.Role)
+	}
+	return principal, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testPolicy(t *testing.T, rules ...Rule) *Policy {
+	t.Helper()
+	p, err := NewPolicy([]Key{
+		{Name: "view", SHA256: HashKey("v-key"), Role: RoleViewer},
+		{Name: "analyze", SHA256: HashKey("a-key"), Role: RoleAnalyst},
+		{Name: "operate", SHA256: HashKey("o-key"), Role: RoleOperator},
+	}, rules)
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	return p
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	p := testPolicy(t)
+
+	tests := []struct {
+		name    string
+		key     string
+		method  string
+		path    string
+		wantErr error
+	}{
+		{"viewer queries graph", "v-key", http.MethodGet, "/v1/codebuddy/callers", nil},
+		{"viewer explores", "v-key", http.MethodPost, "/v1/codebuddy/explore/data_flow", nil},
+		{"viewer retrieves memories", "v-key", http.MethodPost, "/v1/codebuddy/memories/retrieve", nil},
+		{"viewer cannot run agent", "v-key", http.MethodPost, "/v1/codebuddy/agent/run", ErrForbidden},
+		{"viewer cannot init graph", "v-key", http.MethodPost, "/v1/codebuddy/init", ErrForbidden},
+		{"analyst inits graph", "a-key", http.MethodPost, "/v1/codebuddy/init", nil},
+		{"analyst stores memory", "a-key", http.MethodPost, "/v1/codebuddy/memories", nil},
+		{"analyst cannot run agent", "a-key", http.MethodPost, "/v1/codebuddy/agent/run", ErrForbidden},
+		{"analyst cannot delete", "a-key", http.MethodDelete, "/v1/codebuddy/memories/:id", ErrForbidden},
+		{"viewer polls job", "v-key", http.MethodGet, "/v1/trace/jobs/abc", nil},
+		{"viewer cannot submit job", "v-key", http.MethodPost, "/v1/trace/jobs", ErrForbidden},
+		{"analyst cancels job", "a-key", http.MethodDelete, "/v1/trace/jobs/abc", nil},
+		{"operator runs agent", "o-key", http.MethodPost, "/v1/codebuddy/agent/run", nil},
+		{"operator deletes", "o-key", http.MethodDelete, "/v1/codebuddy/memories/:id", nil},
+		{"unlisted mutation needs operator", "a-key", http.MethodPost, "/v1/new/endpoint", ErrForbidden},
+		{"health is public", "", http.MethodGet, "/v1/codebuddy/health", nil},
+		{"webhook is public", "", http.MethodPost, "/v1/trace/webhook", nil},
+		{"missing key", "", http.MethodGet, "/v1/codebuddy/callers", ErrUnauthenticated},
+		{"unknown key", "nope", http.MethodGet, "/v1/codebuddy/callers", ErrUnauthenticated},
+		{"unknown key on public route", "nope", http.MethodGet, "/v1/codebuddy/health", ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Authorize(tt.key, tt.method, tt.path)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Authorize() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("policy rules take precedence", func(t *testing.T) {
+		p := testPolicy(t, Rule{Method: http.MethodPost, Path: "/v1/codebuddy/init", Role: RoleOperator})
+		if _, err := p.Authorize("a-key", http.MethodPost, "/v1/codebuddy/init"); !errors.Is(err, ErrForbidden) {
+			t.Errorf("Authorize() error = %v, want ErrForbidden", err)
+		}
+	})
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	hash := HashKey("k")
+	tests := []struct {
+		name  string
+		keys  []Key
+		rules []Rule
+	}{
+		{"missing name", []Key{{SHA256: hash, Role: RoleViewer}}, nil},
+		{"bad hash", []Key{{Name: "k", SHA256: "abc", Role: RoleViewer}}, nil},
+		{"unknown role", []Key{{Name: "k", SHA256: hash, Role: "admin"}}, nil},
+		{"public key role", []Key{{Name: "k", SHA256: hash, Role: RolePublic}}, nil},
+		{"duplicate hash", []Key{{Name: "a", SHA256: hash, Role: RoleViewer}, {Name: "b", SHA256: hash, Role: RoleViewer}}, nil},
+		{"rule without path", nil, []Rule{{Role: RoleViewer}}},
+		{"rule with unknown role", nil, []Rule{{Path: "/x", Role: "root"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPolicy(tt.keys, tt.rules); !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("NewPolicy() error = %v, want ErrInvalidPolicy", err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := "keys:\n" +
+		"  - name: dashboard\n" +
+		"    sha256: " + HashKey("secret") + "\n" +
+		"    role: viewer\n" +
+		"rules:\n" +
+		"  - method: GET\n" +
+		"    path: /v1/codebuddy/debug/*\n" +
+		"    role: operator\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadPolicyFile(path)
+	if err != nil {
+		t.Fatalf("LoadPolicyFile: %v", err)
+	}
+	principal, err := p.Authorize("secret", http.MethodGet, "/v1/codebuddy/callers")
+	if err != nil || principal.Name != "dashboard" {
+		t.Errorf("Authorize() = %+v, %v; want dashboard", principal, err)
+	}
+	if _, err := p.Authorize("secret", http.MethodGet, "/v1/codebuddy/debug/cache"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("debug route error = %v, want ErrForbidden", err)
+	}
+
+	if _, err := LoadPolicyFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/v1", Middleware(testPolicy(t)))
+
+	var seen Principal
+	handler := func(c *gin.Context) {
+		seen, _ = PrincipalFrom(c)
+		c.Status(http.StatusOK)
+	}
+	v1.GET("/codebuddy/symbol/:id", handler)
+	v1.POST("/codebuddy/agent/run", handler)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     string
+		value      string
+		wantStatus int
+		wantName   string
+	}{
+		{"bearer viewer reads", http.MethodGet, "/v1/codebuddy/symbol/abc", "Authorization", "Bearer v-key", http.StatusOK, "view"},
+		{"x-api-key viewer reads", http.MethodGet, "/v1/codebuddy/symbol/abc", "X-API-Key", "v-key", http.StatusOK, "view"},
+		{"viewer cannot run agent", http.MethodPost, "/v1/codebuddy/agent/run", "Authorization", "Bearer v-key", http.StatusForbidden, ""},
+		{"operator runs agent", http.MethodPost, "/v1/codebuddy/agent/run", "Authorization", "Bearer o-key", http.StatusOK, "operate"},
+		{"no key", http.MethodGet, "/v1/codebuddy/symbol/abc", "", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = Principal{}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if seen.Name != tt.wantName {
+				t.Errorf("principal = %q, want %q", seen.Name, tt.wantName)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@
 package code_buddy
 
 import (
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,8 +32,10 @@ import (
 //	POST /v1/codebuddy/init - Initialize a code graph
 //	POST /v1/codebuddy/context - Assemble context for LLM prompt
 //	GET  /v1/codebuddy/symbol/:id - Get symbol by ID
+//	POST /v1/codebuddy/symbols/resolve - Resolve positions to symbols in bulk
 //	GET  /v1/codebuddy/callers - Find function callers
 //	GET  /v1/codebuddy/implementations - Find interface implementations
+//	GET  /v1/codebuddy/tests - Find the tests that exercise a function
 //	POST /v1/codebuddy/seed - Seed library documentation
 //
 // Memory Endpoints:
@@ -44,7 +47,7 @@ import (
 //	POST /v1/codebuddy/memories/:id/validate - Validate a memory
 //	POST /v1/codebuddy/memories/:id/contradict - Contradict a memory
 //
-// Agentic Tool Endpoints (24 tools):
+// Agentic Tool Endpoints (25 tools):
 //
 //	GET  /v1/codebuddy/tools - Discover available tools
 //
@@ -57,6 +60,7 @@ import (
 //	POST /v1/codebuddy/explore/summarize_file - Summarize a file
 //	POST /v1/codebuddy/explore/summarize_package - Summarize a package
 //	POST /v1/codebuddy/explore/change_impact - Analyze change impact
+//	POST /v1/codebuddy/explore/call_path - Explain a call path with evidence
 //
 //	POST /v1/codebuddy/reason/breaking_changes - Check breaking changes
 //	POST /v1/codebuddy/reason/simulate_change - Simulate a change
@@ -76,10 +80,49 @@ import (
 //	POST /v1/codebuddy/patterns/conventions - Extract conventions
 //	POST /v1/codebuddy/patterns/dead_code - Find dead code
 //
+//	POST /v1/trace/search/semantic - Natural-language symbol search
+//	POST /v1/trace/webhook - GitHub/GitLab PR webhook receiver
+//
+// Job Endpoints:
+//
+//	POST   /v1/trace/jobs - Submit a long-running operation
+//	GET    /v1/trace/jobs - List jobs and available kinds
+//	GET    /v1/trace/jobs/:id - Job status and progress
+//	GET    /v1/trace/jobs/:id/result - Job output
+//	DELETE /v1/trace/jobs/:id - Cancel a job
+//
+// Pagination:
+//
+//	List endpoints (callers, implementations, tests, memories, tools, jobs,
+//	semantic search, and the explore/pattern tools that return lists)
+//	accept ?limit=, ?cursor=, and ?fields= and add a "page" object with
+//	the next cursor to the response. See PageInfo.
+//
+// Caching:
+//
+//	The symbol, symbol resolve, callers, implementations, tests, graph stats,
+//	explore, reason, pattern and semantic search endpoints are read-only
+//	graph queries.
+//	Their 200 responses carry a weak ETag keyed by the graph's generation
+//	and the request; a request whose If-None-Match holds it gets 304 Not
+//	Modified until the graph is refreshed. Responses of 1 KiB or more are
+//	compressed with zstd or gzip, as the client's Accept-Encoding allows.
+//
+// Idempotency:
+//
+//	POST /v1/codebuddy/init, /v1/trace/jobs, /v1/codebuddy/agent/run,
+//	/v1/trace/agent/review and /v1/trace/agent/:id/{undo,redo} accept an
+//	Idempotency-Key header. A retry with
+//	the same key and body within 10 minutes gets the original response,
+//	marked "Idempotent-Replayed: true", instead of repeating the work; a
+//	retry sent while the original is still running waits for it. 5xx
+//	responses are not kept. See the idempotency package.
+//
 // Health Endpoints:
 //
 //	GET  /v1/codebuddy/health - Health check
 //	GET  /v1/codebuddy/ready - Readiness check
+//	GET  /v1/trace/version - Build of the running binary (see buildinfo)
 //
 // Example:
 //
@@ -89,18 +132,26 @@ import (
 //	v1 := router.Group("/v1")
 //	code_buddy.RegisterRoutes(v1, handlers)
 func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
+	// Read-only graph queries are compressed and answer If-None-Match.
+	graphRead := handlers.graphReadMiddleware()
+	withGraphRead := func(h gin.HandlerFunc) []gin.HandlerFunc {
+		return append(graphRead[:len(graphRead):len(graphRead)], h)
+	}
+
 	codebuddy := rg.Group("/codebuddy")
 	{
 		// Graph lifecycle
-		codebuddy.POST("/init", handlers.HandleInit)
+		codebuddy.POST("/init", idempotent(), handlers.HandleInit)
 
 		// Context assembly
 		codebuddy.POST("/context", handlers.HandleContext)
 
 		// Symbol queries
-		codebuddy.GET("/symbol/:id", handlers.HandleSymbol)
-		codebuddy.GET("/callers", handlers.HandleCallers)
-		codebuddy.GET("/implementations", handlers.HandleImplementations)
+		codebuddy.GET("/symbol/:id", withGraphRead(handlers.HandleSymbol)...)
+		codebuddy.POST("/symbols/resolve", withGraphRead(handlers.HandleResolveSymbols)...)
+		codebuddy.GET("/callers", withGraphRead(handlers.HandleCallers)...)
+		codebuddy.GET("/implementations", withGraphRead(handlers.HandleImplementations)...)
+		codebuddy.GET("/tests", withGraphRead(handlers.HandleTests)...)
 
 		// Library documentation seeding
 		codebuddy.POST("/seed", handlers.HandleSeed)
@@ -123,7 +174,7 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 
 		debug := codebuddy.Group("/debug")
 		{
-			debug.GET("/graph/stats", handlers.HandleGetGraphStats)
+			debug.GET("/graph/stats", withGraphRead(handlers.HandleGetGraphStats)...)
 			debug.GET("/cache", handlers.HandleGetCacheStats)
 		}
 
@@ -134,8 +185,8 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 		// Tool discovery
 		codebuddy.GET("/tools", handlers.HandleGetTools)
 
-		// Exploration tools (9 endpoints)
-		explore := codebuddy.Group("/explore")
+		// Exploration tools (10 endpoints)
+		explore := codebuddy.Group("/explore", graphRead...)
 		{
 			explore.POST("/entry_points", handlers.HandleFindEntryPoints)
 			explore.POST("/data_flow", handlers.HandleTraceDataFlow)
@@ -146,10 +197,11 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 			explore.POST("/summarize_file", handlers.HandleSummarizeFile)
 			explore.POST("/summarize_package", handlers.HandleSummarizePackage)
 			explore.POST("/change_impact", handlers.HandleAnalyzeChangeImpact)
+			explore.POST("/call_path", handlers.HandleExplainCallPath)
 		}
 
 		// Reasoning tools (6 endpoints)
-		reason := codebuddy.Group("/reason")
+		reason := codebuddy.Group("/reason", graphRead...)
 		{
 			reason.POST("/breaking_changes", handlers.HandleCheckBreakingChanges)
 			reason.POST("/simulate_change", handlers.HandleSimulateChange)
@@ -168,7 +220,7 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 		}
 
 		// Pattern tools (6 endpoints)
-		patterns := codebuddy.Group("/patterns")
+		patterns := codebuddy.Group("/patterns", graphRead...)
 		{
 			patterns.POST("/detect", handlers.HandleDetectPatterns)
 			patterns.POST("/code_smells", handlers.HandleFindCodeSmells)
@@ -178,6 +230,25 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 			patterns.POST("/dead_code", handlers.HandleFindDeadCode)
 		}
 	}
+
+	trace := rg.Group("/trace")
+	{
+		// Build of the running binary
+		trace.GET("/version", gin.WrapH(buildinfo.Handler("trace")))
+
+		// Search (complements exact graph queries)
+		trace.POST("/search/semantic", withGraphRead(handlers.HandleSemanticSearch)...)
+
+		// PR analysis webhooks (authenticated by provider signature/token)
+		trace.POST("/webhook", handlers.HandleWebhook)
+
+		// Asynchronous jobs for long-running analyses
+		trace.POST("/jobs", idempotent(), handlers.HandleSubmitJob)
+		trace.GET("/jobs", handlers.HandleListJobs)
+		trace.GET("/jobs/:id", handlers.HandleGetJob)
+		trace.GET("/jobs/:id/result", handlers.HandleGetJobResult)
+		trace.DELETE("/jobs/:id", handlers.HandleCancelJob)
+	}
 }
 
 // RegisterAgentRoutes registers the Code Buddy agent routes with the router.
@@ -201,6 +272,9 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 //	GET  /v1/codebuddy/agent/:id - Get session state
 //	GET  /v1/codebuddy/agent/:id/reasoning - Get reasoning trace
 //	GET  /v1/codebuddy/agent/:id/crs - Get CRS state export
+//	POST /v1/trace/agent/review - Review a diff (structured findings)
+//	POST /v1/trace/agent/:id/undo - Revert the session's last file edits
+//	POST /v1/trace/agent/:id/redo - Re-apply undone file edits
 //
 // Example:
 //
@@ -237,7 +311,7 @@ func RegisterAgentRoutesWithMiddleware(rg *gin.RouterGroup, handlers *AgentHandl
 	}
 	{
 		// Session lifecycle
-		agent.POST("/run", handlers.HandleAgentRun)
+		agent.POST("/run", idempotent(), handlers.HandleAgentRun)
 		agent.POST("/continue", handlers.HandleAgentContinue)
 		agent.POST("/abort", handlers.HandleAgentAbort)
 
@@ -255,4 +329,15 @@ func RegisterAgentRoutesWithMiddleware(rg *gin.RouterGroup, handlers *AgentHandl
 			debug.GET("/history", handlers.HandleDebugHistory)
 		}
 	}
+
+	// Patch review mode
+	review := rg.Group("/trace/agent")
+	if middleware != nil {
+		review.Use(middleware)
+	}
+	review.POST("/review", idempotent(), handlers.HandleAgentReview)
+
+	// Undo/redo of the session's file edits
+	review.POST("/:id/undo", idempotent(), handlers.HandleAgentUndo)
+	review.POST("/:id/redo", idempotent(), handlers.HandleAgentRedo)
 }
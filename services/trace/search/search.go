@@ -0,0 +1,419 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package search provides hybrid lexical and structural symbol search.
+//
+// # Description
+//
+// Search combines three signals into one ranked list:
+//
+//   - Lexical: exact, prefix, substring, and identifier-part matches on the
+//     symbol name, falling back to trigram similarity for typos and partial
+//     names.
+//   - Filters: symbol kind and path prefix restrict the candidate set.
+//   - Structural: a per-symbol centrality score (e.g. PageRank or in-degree
+//     from the call graph) boosts symbols that matter more to the codebase.
+//
+// The package depends only on the standard library so both the CLI
+// (`aleutian search`, backed by the on-disk index) and the agent's
+// exploration tools (backed by the code graph) can share one ranking.
+//
+// # Thread Safety
+//
+// Index is immutable after NewIndex and safe for concurrent Search calls.
+package search
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrEmptyQuery is returned when the query has no searchable text.
+var ErrEmptyQuery = errors.New("empty search query")
+
+// Document is a searchable symbol.
+type Document struct {
+	// ID is the unique symbol identifier.
+	ID string `json:"id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the lowercase symbol kind (function, method, struct, ...).
+	Kind string `json:"kind"`
+
+	// FilePath is the relative path to the file.
+	FilePath string `json:"file_path"`
+
+	// Line is the 1-indexed start line.
+	Line int `json:"line"`
+
+	// Signature is the symbol signature.
+	Signature string `json:"signature,omitempty"`
+
+	// Centrality is a non-negative structural importance score. Only the
+	// relative values matter; they are normalized by the index maximum.
+	Centrality float64 `json:"centrality,omitempty"`
+}
+
+// MatchType describes how the query matched a symbol name.
+type MatchType string
+
+const (
+	// MatchExact is a case-insensitive exact name match.
+	MatchExact MatchType = "exact"
+
+	// MatchPrefix is a case-insensitive name prefix match.
+	MatchPrefix MatchType = "prefix"
+
+	// MatchSubstring is a case-insensitive name substring match.
+	MatchSubstring MatchType = "substring"
+
+	// MatchTokens means every query word matched an identifier part.
+	MatchTokens MatchType = "tokens"
+
+	// MatchFuzzy is a trigram similarity match.
+	MatchFuzzy MatchType = "fuzzy"
+)
+
+// lexicalScores are the base scores for each match type.
+var lexicalScores = map[MatchType]float64{
+	MatchExact:     1.0,
+	MatchPrefix:    0.85,
+	MatchSubstring: 0.7,
+	MatchTokens:    0.6,
+}
+
+// Result is a ranked search hit.
+type Result struct {
+	Document
+
+	// Score is the final ranking score (0.0-1.0).
+	Score float64 `json:"score"`
+
+	// Lexical is the name-match component of the score.
+	Lexical float64 `json:"lexical"`
+
+	// Structural is the normalized centrality component (0.0-1.0).
+	Structural float64 `json:"structural"`
+
+	// Match is how the name matched.
+	Match MatchType `json:"match"`
+}
+
+// Query describes a search.
+type Query struct {
+	// Text is the name or words to search for.
+	Text string
+
+	// Kinds restricts results to these symbol kinds. Empty means all.
+	Kinds []string
+
+	// PathPrefix restricts results to files under this prefix.
+	PathPrefix string
+
+	// Limit is the maximum number of results.
+	// Default: 20
+	Limit int
+}
+
+// Options configures ranking.
+type Options struct {
+	// CentralityWeight is the share of the final score given to the
+	// structural signal (0.0-1.0).
+	// Default: 0.2
+	CentralityWeight float64
+
+	// MinTrigramSimilarity is the minimum trigram Jaccard similarity for a
+	// fuzzy match.
+	// Default: 0.3
+	MinTrigramSimilarity float64
+}
+
+// DefaultOptions returns the default ranking options.
+func DefaultOptions() Options {
+	return Options{
+		CentralityWeight:     0.2,
+		MinTrigramSimilarity: 0.3,
+	}
+}
+
+// DefaultLimit is the default maximum number of results.
+const DefaultLimit = 20
+
+// Index is a trigram index over symbol names.
+type Index struct {
+	docs          []Document
+	names         []string // lowercase names, parallel to docs
+	parts         [][]string
+	trigrams      map[string][]int
+	maxCentrality float64
+	opts          Options
+}
+
+// NewIndex builds an index over docs.
+//
+// # Inputs
+//
+//   - docs: Symbols to index. The slice is copied.
+//   - opts: Ranking options. Zero fields take defaults.
+//
+// # Outputs
+//
+//   - *Index: The immutable index.
+func NewIndex(docs []Document, opts Options) *Index {
+	defaults := DefaultOptions()
+	if opts.CentralityWeight <= 0 || opts.CentralityWeight > 1 {
+		opts.CentralityWeight = defaults.CentralityWeight
+	}
+	if opts.MinTrigramSimilarity <= 0 || opts.MinTrigramSimilarity > 1 {
+		opts.MinTrigramSimilarity = defaults.MinTrigramSimilarity
+	}
+
+	idx := &Index{
+		docs:     append([]Document(nil), docs...),
+		names:    make([]string, len(docs)),
+		parts:    make([][]string, len(docs)),
+		trigrams: make(map[string][]int),
+		opts:     opts,
+	}
+
+	for i, d := range idx.docs {
+		name := strings.ToLower(d.Name)
+		idx.names[i] = name
+		idx.parts[i] = SplitIdentifier(d.Name)
+		for _, tg := range uniqueTrigrams(name) {
+			idx.trigrams[tg] = append(idx.trigrams[tg], i)
+		}
+		if d.Centrality > idx.maxCentrality {
+			idx.maxCentrality = d.Centrality
+		}
+	}
+
+	return idx
+}
+
+// Len returns the number of indexed documents.
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+// Search returns documents ranked by combined lexical and structural score.
+//
+// # Description
+//
+// Every document is tested for exact, prefix, substring, and identifier-part
+// matches; documents sharing trigrams with the query are additionally
+// scored by trigram similarity. The lexical score is then blended with the
+// normalized centrality: score = (1-w)*lexical + w*structural. Ties are
+// broken by name and ID so results are deterministic.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - q: The query.
+//
+// # Outputs
+//
+//   - []Result: Results sorted by descending score.
+//   - error: ErrEmptyQuery, or the context error.
+func (idx *Index) Search(ctx context.Context, q Query) ([]Result, error) {
+	text := strings.ToLower(strings.TrimSpace(q.Text))
+	if text == "" {
+		return nil, ErrEmptyQuery
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	kinds := make(map[string]bool, len(q.Kinds))
+	for _, k := range q.Kinds {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" && k != "all" {
+			kinds[k] = true
+		}
+	}
+
+	// Query without separators is compared against names; query words are
+	// compared against identifier parts.
+	compact := strings.Join(strings.Fields(text), "")
+	words := SplitIdentifier(q.Text)
+	queryTrigrams := uniqueTrigrams(compact)
+
+	// Candidate trigram overlap counts for fuzzy scoring.
+	shared := make(map[int]int)
+	for _, tg := range queryTrigrams {
+		for _, i := range idx.trigrams[tg] {
+			shared[i]++
+		}
+	}
+
+	results := make([]Result, 0, limit)
+	for i, d := range idx.docs {
+		if i%1000 == 0 && ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if len(kinds) > 0 && !kinds[strings.ToLower(d.Kind)] {
+			continue
+		}
+		if q.PathPrefix != "" && !strings.HasPrefix(d.FilePath, q.PathPrefix) {
+			continue
+		}
+
+		match, lexical := idx.lexicalMatch(i, compact, words, queryTrigrams, shared[i])
+		if match == "" {
+			continue
+		}
+
+		structural := 0.0
+		if idx.maxCentrality > 0 && d.Centrality > 0 {
+			structural = d.Centrality / idx.maxCentrality
+		}
+		w := idx.opts.CentralityWeight
+
+		results = append(results, Result{
+			Document:   d,
+			Score:      (1-w)*lexical + w*structural,
+			Lexical:    lexical,
+			Structural: structural,
+			Match:      match,
+		})
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		if results[a].Name != results[b].Name {
+			return results[a].Name < results[b].Name
+		}
+		return results[a].ID < results[b].ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// lexicalMatch scores document i against the query.
+func (idx *Index) lexicalMatch(i int, compact string, words, queryTrigrams []string, sharedTrigrams int) (MatchType, float64) {
+	name := idx.names[i]
+
+	switch {
+	case name == compact:
+		return MatchExact, lexicalScores[MatchExact]
+	case strings.HasPrefix(name, compact):
+		return MatchPrefix, lexicalScores[MatchPrefix] + 0.1*coverage(compact, name)
+	case strings.Contains(name, compact):
+		return MatchSubstring, lexicalScores[MatchSubstring] + 0.1*coverage(compact, name)
+	case len(words) > 1 && allWordsMatchParts(words, idx.parts[i]):
+		return MatchTokens, lexicalScores[MatchTokens]
+	}
+
+	if sharedTrigrams == 0 {
+		return "", 0
+	}
+	nameTrigrams := len(uniqueTrigrams(name))
+	union := len(queryTrigrams) + nameTrigrams - sharedTrigrams
+	if union <= 0 {
+		return "", 0
+	}
+	sim := float64(sharedTrigrams) / float64(union)
+	if sim < idx.opts.MinTrigramSimilarity {
+		return "", 0
+	}
+	// Fuzzy matches rank below all substring-style matches.
+	return MatchFuzzy, 0.5 * sim
+}
+
+// coverage returns the fraction of name covered by the query.
+func coverage(query, name string) float64 {
+	if len(name) == 0 {
+		return 0
+	}
+	return float64(len(query)) / float64(len(name))
+}
+
+// allWordsMatchParts reports whether every query word prefixes some part.
+func allWordsMatchParts(words, parts []string) bool {
+	for _, w := range words {
+		found := false
+		for _, p := range parts {
+			if strings.HasPrefix(p, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueTrigrams returns the distinct trigrams of s, padded so that short
+// strings and word boundaries contribute.
+func uniqueTrigrams(s string) []string {
+	if s == "" {
+		return nil
+	}
+	padded := []rune("  " + s + " ")
+	seen := make(map[string]bool, len(padded))
+	out := make([]string, 0, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		tg := string(padded[i : i+3])
+		if !seen[tg] {
+			seen[tg] = true
+			out = append(out, tg)
+		}
+	}
+	return out
+}
+
+// SplitIdentifier splits an identifier or phrase into lowercase parts.
+//
+// camelCase, PascalCase, acronyms, snake_case, kebab-case, dots, and spaces
+// are all treated as boundaries: "parseHTTPRequest" -> [parse http request].
+func SplitIdentifier(s string) []string {
+	var parts []string
+	var cur []rune
+
+	flush := func() {
+		if len(cur) > 0 {
+			parts = append(parts, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+
+	return parts
+}
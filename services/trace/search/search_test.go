@@ -0,0 +1,132 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testDocs() []Document {
+	return []Document{
+		{ID: "auth/token.go:10:ValidateToken", Name: "ValidateToken", Kind: "function", FilePath: "auth/token.go", Centrality: 10},
+		{ID: "auth/token.go:40:validateTokenClaims", Name: "validateTokenClaims", Kind: "function", FilePath: "auth/token.go", Centrality: 1},
+		{ID: "auth/token.go:5:Token", Name: "Token", Kind: "struct", FilePath: "auth/token.go", Centrality: 5},
+		{ID: "http/server.go:10:HandleRequest", Name: "HandleRequest", Kind: "function", FilePath: "http/server.go", Centrality: 2},
+		{ID: "http/server.go:50:parseHTTPRequest", Name: "parseHTTPRequest", Kind: "function", FilePath: "http/server.go", Centrality: 8},
+		{ID: "db/query.go:10:Query", Name: "Query", Kind: "method", FilePath: "db/query.go"},
+	}
+}
+
+func TestIndex_Search_Ranking(t *testing.T) {
+	idx := NewIndex(testDocs(), Options{})
+	ctx := context.Background()
+
+	t.Run("exact beats prefix and substring", func(t *testing.T) {
+		results, err := idx.Search(ctx, Query{Text: "token"})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) < 2 {
+			t.Fatalf("expected at least 2 results, got %d", len(results))
+		}
+		if results[0].Name != "Token" || results[0].Match != MatchExact {
+			t.Errorf("expected exact Token first, got %s (%s)", results[0].Name, results[0].Match)
+		}
+	})
+
+	t.Run("centrality breaks lexical ties", func(t *testing.T) {
+		results, err := idx.Search(ctx, Query{Text: "validatetoken"})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if results[0].Name != "ValidateToken" {
+			t.Errorf("expected ValidateToken first, got %s", results[0].Name)
+		}
+		if results[0].Structural != 1.0 {
+			t.Errorf("expected structural 1.0 for most central symbol, got %f", results[0].Structural)
+		}
+	})
+
+	t.Run("identifier parts", func(t *testing.T) {
+		results, err := idx.Search(ctx, Query{Text: "parse request"})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) == 0 || results[0].Name != "parseHTTPRequest" || results[0].Match != MatchTokens {
+			t.Errorf("expected token match on parseHTTPRequest, got %+v", results)
+		}
+	})
+
+	t.Run("typo uses trigrams", func(t *testing.T) {
+		results, err := idx.Search(ctx, Query{Text: "HandelRequest"})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) == 0 || results[0].Name != "HandleRequest" || results[0].Match != MatchFuzzy {
+			t.Errorf("expected fuzzy match on HandleRequest, got %+v", results)
+		}
+	})
+}
+
+func TestIndex_Search_Filters(t *testing.T) {
+	idx := NewIndex(testDocs(), Options{})
+	ctx := context.Background()
+
+	results, err := idx.Search(ctx, Query{Text: "token", Kinds: []string{"Function"}})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Kind != "function" {
+			t.Errorf("kind filter leaked %s (%s)", r.Name, r.Kind)
+		}
+	}
+
+	results, err = idx.Search(ctx, Query{Text: "request", PathPrefix: "http/", Limit: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected limit 1, got %d", len(results))
+	}
+
+	if _, err := idx.Search(ctx, Query{Text: "   "}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestIndex_Search_Canceled(t *testing.T) {
+	idx := NewIndex(testDocs(), Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := idx.Search(ctx, Query{Text: "token"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSplitIdentifier(t *testing.T) {
+	tests := map[string][]string{
+		"parseHTTPRequest": {"parse", "http", "request"},
+		"snake_case_name":  {"snake", "case", "name"},
+		"auth.Validate":    {"auth", "validate"},
+		"find callers":     {"find", "callers"},
+		"UTF8Decoder":      {"utf8", "decoder"},
+	}
+	for in, want := range tests {
+		if got := SplitIdentifier(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("SplitIdentifier(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
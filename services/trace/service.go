@@ -21,19 +21,28 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/projectconfig"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graphstore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"golang.org/x/sync/singleflight"
 )
 
 // ServiceConfig configures the Code Buddy service.
@@ -73,6 +82,33 @@ type ServiceConfig struct {
 	// LSPRequestTimeout is the default timeout for LSP requests.
 	// Default: 10 seconds
 	LSPRequestTimeout time.Duration
+
+	// LSPCallers answers caller queries from the language server's call
+	// hierarchy when a server is installed for the function's language.
+	// The graph answers them when it is not, or when the server fails.
+	// Default: true
+	LSPCallers bool
+
+	// EmbeddingURL is the base URL of the embeddings service used for
+	// semantic search (the orchestrator's EMBEDDING_SERVICE_URL).
+	// If empty or unreachable, a local hashing embedder is used.
+	// Default: "" (local embedder)
+	EmbeddingURL string
+
+	// PersistGraphs stores each built graph in <project>/.aleutian/graph.db
+	// and, on the next Init, restores it instead of re-parsing if no
+	// source file changed. See package graphstore.
+	// Default: false
+	PersistGraphs bool
+
+	// MaxTopUpFiles lets a stored graph that is out of date by at most
+	// this many source files be topped up instead of rebuilt: only the
+	// changed files are re-parsed and their nodes replaced. Edges into
+	// and out of those files are not re-resolved, so this trades some
+	// graph precision for start-up time, for example on CI runners that
+	// import a bundle built on another machine. Requires PersistGraphs.
+	// Default: 0 (always rebuild)
+	MaxTopUpFiles int
 }
 
 // DefaultServiceConfig returns sensible defaults.
@@ -86,6 +122,7 @@ func DefaultServiceConfig() ServiceConfig {
 		LSPIdleTimeout:    10 * time.Minute,
 		LSPStartupTimeout: 30 * time.Second,
 		LSPRequestTimeout: 10 * time.Second,
+		LSPCallers:        true,
 	}
 }
 
@@ -101,6 +138,10 @@ type Service struct {
 	mu        sync.RWMutex
 	initLocks sync.Map // projectRoot -> *sync.Mutex
 
+	// generation numbers graph builds. It only increases, so a generation
+	// identifies one build of one graph for the life of the process.
+	generation atomic.Int64
+
 	// registry holds parser instances
 	registry *ast.ParserRegistry
 
@@ -114,6 +155,16 @@ type Service struct {
 	// lspManagers holds LSP managers per graph (graphID -> manager)
 	lspManagers map[string]*lsp.Manager
 	lspMu       sync.RWMutex
+
+	// semanticMu guards the SemanticIndex fields of cached graphs
+	semanticMu sync.Mutex
+
+	// semanticBuilds deduplicates concurrent semantic index builds per graph
+	semanticBuilds singleflight.Group
+
+	// projectConfigs holds each project's .aleutian/config.yaml, reloaded
+	// when the file changes
+	projectConfigs *projectconfig.Cache
 }
 
 // CachedPlan holds a change plan and its associated graph ID.
@@ -144,11 +195,12 @@ type CachedPlan struct {
 //	*Service - The configured service
 func NewService(config ServiceConfig) *Service {
 	svc := &Service{
-		config:      config,
-		graphs:      make(map[string]*CachedGraph),
-		registry:    ast.NewParserRegistry(),
-		plans:       make(map[string]*CachedPlan),
-		lspManagers: make(map[string]*lsp.Manager),
+		config:         config,
+		graphs:         make(map[string]*CachedGraph),
+		registry:       ast.NewParserRegistry(),
+		plans:          make(map[string]*CachedPlan),
+		lspManagers:    make(map[string]*lsp.Manager),
+		projectConfigs: projectconfig.NewCache(),
 	}
 
 	// Register default parsers
@@ -165,20 +217,53 @@ func (s *Service) SetLibraryDocProvider(p cbcontext.LibraryDocProvider) {
 	s.libDocProvider = p
 }
 
+// ProjectConfig returns the settings in a project's .aleutian/config.yaml.
+//
+// Description:
+//
+//	Settings written by `aleutian init` and `aleutian config set` are
+//	reloaded when the file changes, so they apply to the next request
+//	without restarting the service. An invalid file is logged and the
+//	last valid settings are used.
+//
+// Inputs:
+//
+//	projectRoot - Absolute path to the project root
+//
+// Outputs:
+//
+//	*projectconfig.Config - The settings. Never nil; empty if the project
+//	has none. Must not be modified.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) ProjectConfig(projectRoot string) *projectconfig.Config {
+	cfg, err := s.projectConfigs.Get(projectRoot)
+	if err != nil {
+		slog.Warn("Ignoring invalid project config",
+			slog.String("project_root", projectRoot),
+			slog.String("error", err.Error()),
+		)
+	}
+	return cfg
+}
+
 // Init initializes a code graph for a project.
 //
 // Description:
 //
 //	Parses the project, builds the code graph and symbol index, and
 //	caches the result. If a graph already exists for the project, it
-//	is replaced.
+//	is replaced. With ServiceConfig.PersistGraphs, an unchanged project
+//	is restored from .aleutian/graph.db instead of being re-parsed.
 //
 // Inputs:
 //
 //	ctx - Context for cancellation
 //	projectRoot - Absolute path to the project root
-//	languages - Languages to parse (default: ["go"])
-//	excludes - Glob patterns to exclude (default: ["vendor/*", "*_test.go"])
+//	languages - Languages to parse (default: the project's configured
+//	  languages, else ["go"])
+//	excludes - Glob patterns to exclude (default: the project's configured
+//	  exclude patterns, else ["vendor/*", "*_test.go"])
 //
 // Outputs:
 //
@@ -198,7 +283,16 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		return nil, err
 	}
 
-	// Apply defaults
+	// Fall back to the project's settings, then to defaults
+	if len(languages) == 0 || len(excludes) == 0 {
+		settings := s.ProjectConfig(projectRoot)
+		if len(languages) == 0 {
+			languages = settings.Languages
+		}
+		if len(excludes) == 0 {
+			excludes = settings.ExcludePatterns
+		}
+	}
 	if len(languages) == 0 {
 		languages = []string{"go"}
 	}
@@ -237,10 +331,109 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	// Create index
 	idx := index.NewSymbolIndex()
 
+	// Restore the graph from .aleutian/graph.db if it is still current,
+	// otherwise parse the project and build it.
+	params := graphstore.BuildParams{Languages: languages, Excludes: excludes}
+	g, result, current := s.loadPersistedGraph(ctx, projectRoot, params, idx)
+	if g == nil {
+		var incomplete bool
+		var err error
+		g, result, incomplete, err = s.buildGraph(ctx, projectRoot, languages, excludes, idx)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil && !incomplete {
+			s.persistGraph(projectRoot, g, current, params)
+		}
+	}
+
+	// Create assembler
+	assembler := cbcontext.NewAssembler(g, idx)
+	if s.libDocProvider != nil {
+		assembler = assembler.WithLibraryDocProvider(s.libDocProvider)
+	}
+
+	// GR-10: Create CRSGraphAdapter for query caching
+	builtAtMilli := time.Now().UnixMilli()
+	generation := s.generation.Add(1)
+	var adapter *graph.CRSGraphAdapter
+	hg, err := graph.WrapGraph(g)
+	if err != nil {
+		slog.Warn("GR-10: Failed to create hierarchical graph for adapter",
+			slog.String("project_root", projectRoot),
+			slog.String("error", err.Error()),
+		)
+	} else {
+		adapter, err = graph.NewCRSGraphAdapter(hg, idx, generation, builtAtMilli, nil)
+		if err != nil {
+			slog.Warn("GR-10: Failed to create CRS graph adapter",
+				slog.String("project_root", projectRoot),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			slog.Info("GR-10: CRS graph adapter created successfully",
+				slog.String("project_root", projectRoot),
+				slog.Int("nodes", g.NodeCount()),
+				slog.Int("edges", g.EdgeCount()),
+			)
+		}
+	}
+
+	tests, err := analysis.BuildTestMapping(ctx, g, nil)
+	if err != nil {
+		slog.Warn("Failed to build test mapping",
+			slog.String("project_root", projectRoot),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// Cache the graph
+	cached := &CachedGraph{
+		Graph:        g,
+		Index:        idx,
+		Assembler:    assembler,
+		Adapter:      adapter,
+		Tests:        tests,
+		BuiltAtMilli: builtAtMilli,
+		Generation:   generation,
+		ProjectRoot:  projectRoot,
+	}
+
+	if s.config.GraphTTL > 0 {
+		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
+	}
+
+	s.mu.Lock()
+	s.graphs[graphID] = cached
+	s.evictIfNeeded()
+	s.mu.Unlock()
+
+	return &InitResponse{
+		GraphID:          graphID,
+		IsRefresh:        isRefresh,
+		PreviousID:       previousID,
+		FilesParsed:      result.FilesParsed,
+		SymbolsExtracted: result.SymbolsExtracted,
+		EdgesBuilt:       g.EdgeCount(),
+		ParseTimeMs:      time.Since(start).Milliseconds(),
+		Errors:           result.Errors,
+	}, nil
+}
+
+// buildGraph parses the project and builds its graph, adding all parsed
+// symbols to idx.
+//
+// Outputs:
+//
+//	*graph.Graph - The frozen graph
+//	*parseResult - Parse statistics and non-fatal errors
+//	bool - True if the build was cut short (timeout or limits)
+//	error - Non-nil on fatal parse or build errors
+func (s *Service) buildGraph(ctx context.Context, projectRoot string, languages, excludes []string, idx *index.SymbolIndex) (*graph.Graph, *parseResult, bool, error) {
 	// Parse files into ParseResults
 	parseResults, result, err := s.parseProjectToResults(ctx, projectRoot, languages, excludes)
 	if err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
 
 	// Build graph with edges using the Builder
@@ -248,7 +441,7 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	builder := graph.NewBuilder(graph.WithProjectRoot(projectRoot))
 	buildResult, err := builder.Build(ctx, parseResults)
 	if err != nil {
-		return nil, fmt.Errorf("building graph: %w", err)
+		return nil, nil, false, fmt.Errorf("building graph: %w", err)
 	}
 
 	// R-1: Handle incomplete builds (context cancelled or memory limits)
@@ -293,66 +486,202 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		slog.Bool("incomplete", buildResult.Incomplete),
 	)
 
-	// Create assembler
-	assembler := cbcontext.NewAssembler(g, idx)
-	if s.libDocProvider != nil {
-		assembler = assembler.WithLibraryDocProvider(s.libDocProvider)
+	return g, result, buildResult.Incomplete, nil
+}
+
+// loadPersistedGraph restores a graph stored by persistGraph.
+//
+// Description:
+//
+//	Only active with ServiceConfig.PersistGraphs. Scans the project
+//	manifest and loads .aleutian/graph.db if no tracked file changed
+//	since it was written, or if at most ServiceConfig.MaxTopUpFiles
+//	source files changed, in which case those are re-parsed (see
+//	topUpGraph). Symbols of the restored graph (except external
+//	placeholders, which parsing never indexes) are added to idx.
+//
+// Outputs:
+//
+//	*graph.Graph - The restored graph, or nil if the project must be built
+//	*parseResult - Stats for the restored graph (nil if not restored)
+//	*manifest.Manifest - The fresh scan, for persisting a rebuilt graph;
+//	  nil if persistence is disabled or the scan failed
+func (s *Service) loadPersistedGraph(ctx context.Context, projectRoot string, params graphstore.BuildParams, idx *index.SymbolIndex) (*graph.Graph, *parseResult, *manifest.Manifest) {
+	if !s.config.PersistGraphs {
+		return nil, nil, nil
 	}
 
-	// GR-10: Create CRSGraphAdapter for query caching
-	builtAtMilli := time.Now().UnixMilli()
-	var adapter *graph.CRSGraphAdapter
-	hg, err := graph.WrapGraph(g)
+	current, err := manifest.NewManifestManager().Scan(ctx, projectRoot)
 	if err != nil {
-		slog.Warn("GR-10: Failed to create hierarchical graph for adapter",
+		slog.Warn("Graph store: manifest scan failed, persistence skipped",
 			slog.String("project_root", projectRoot),
 			slog.String("error", err.Error()),
 		)
+		return nil, nil, nil
+	}
+
+	var g *graph.Graph
+	var parseErrors []string
+	if s.config.MaxTopUpFiles > 0 {
+		g, parseErrors, err = s.topUpGraph(ctx, projectRoot, current, params)
 	} else {
-		adapter, err = graph.NewCRSGraphAdapter(hg, idx, 1, builtAtMilli, nil)
-		if err != nil {
-			slog.Warn("GR-10: Failed to create CRS graph adapter",
-				slog.String("project_root", projectRoot),
-				slog.String("error", err.Error()),
-			)
-		} else {
-			slog.Info("GR-10: CRS graph adapter created successfully",
+		g, err = graphstore.New(projectRoot).Load(current, params)
+	}
+	if err != nil {
+		if !errors.Is(err, graphstore.ErrNotFound) {
+			slog.Info("Graph store: rebuilding graph",
 				slog.String("project_root", projectRoot),
-				slog.Int("nodes", g.NodeCount()),
-				slog.Int("edges", g.EdgeCount()),
+				slog.String("reason", err.Error()),
 			)
 		}
+		return nil, nil, current
 	}
 
-	// Cache the graph
-	cached := &CachedGraph{
-		Graph:        g,
-		Index:        idx,
-		Assembler:    assembler,
-		Adapter:      adapter,
-		BuiltAtMilli: builtAtMilli,
-		ProjectRoot:  projectRoot,
+	files := make(map[string]struct{})
+	for _, node := range g.Nodes() {
+		if node.Symbol.Kind == ast.SymbolKindExternal {
+			continue
+		}
+		_ = idx.Add(node.Symbol)
+		if node.Symbol.FilePath != "" {
+			files[node.Symbol.FilePath] = struct{}{}
+		}
 	}
 
-	if s.config.GraphTTL > 0 {
-		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
+	slog.Info("Graph store: graph restored",
+		slog.String("project_root", projectRoot),
+		slog.Int("nodes", g.NodeCount()),
+		slog.Int("edges", g.EdgeCount()),
+	)
+	return g, &parseResult{
+		FilesParsed:      len(files),
+		SymbolsExtracted: idx.Stats().TotalSymbols,
+		Errors:           append(make([]string, 0), parseErrors...),
+	}, current
+}
+
+// topUpGraph restores the stored graph and re-parses the source files
+// that changed since it was stored.
+//
+// Description:
+//
+//	Nodes of modified and deleted files are removed, and modified and
+//	added files are parsed and their symbols added. Only files that a
+//	full build would parse count; other changes are ignored. The
+//	topped-up graph is not persisted, so the stored graph stays the
+//	baseline that later Inits top up from.
+//
+// Outputs:
+//
+//	*graph.Graph - The frozen, topped-up graph
+//	[]string - Non-fatal parse errors of changed files
+//	error - graphstore errors, or ErrStale if more than
+//	  ServiceConfig.MaxTopUpFiles source files changed
+func (s *Service) topUpGraph(ctx context.Context, projectRoot string, current *manifest.Manifest, params graphstore.BuildParams) (*graph.Graph, []string, error) {
+	g, changes, err := graphstore.New(projectRoot).LoadWithChanges(current, params)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	s.mu.Lock()
-	s.graphs[graphID] = cached
-	s.evictIfNeeded()
-	s.mu.Unlock()
+	isSource := func(rel string) bool {
+		return s.isLanguageFile(filepath.Ext(rel), params.Languages) && !isExcluded(rel, params.Excludes)
+	}
+	var remove, parse []string
+	changed := 0
+	for _, rel := range changes.Modified {
+		if isSource(rel) {
+			remove = append(remove, rel)
+			parse = append(parse, rel)
+			changed++
+		}
+	}
+	for _, rel := range changes.Deleted {
+		if isSource(rel) {
+			remove = append(remove, rel)
+			changed++
+		}
+	}
+	for _, rel := range changes.Added {
+		if isSource(rel) {
+			parse = append(parse, rel)
+			changed++
+		}
+	}
 
-	return &InitResponse{
-		GraphID:          graphID,
-		IsRefresh:        isRefresh,
-		PreviousID:       previousID,
-		FilesParsed:      result.FilesParsed,
-		SymbolsExtracted: result.SymbolsExtracted,
-		EdgesBuilt:       g.EdgeCount(),
-		ParseTimeMs:      time.Since(start).Milliseconds(),
-		Errors:           result.Errors,
-	}, nil
+	if changed == 0 {
+		return g, nil, nil
+	}
+	if changed > s.config.MaxTopUpFiles {
+		return nil, nil, fmt.Errorf("%w: %d changed source files, top-up limit is %d",
+			graphstore.ErrStale, changed, s.config.MaxTopUpFiles)
+	}
+
+	topped := g.Clone()
+	for _, rel := range remove {
+		if _, err := topped.RemoveFile(rel); err != nil {
+			return nil, nil, fmt.Errorf("removing %s from stored graph: %w", rel, err)
+		}
+	}
+	var parseErrors []string
+	for _, rel := range parse {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		pr, err := s.parseFileToResult(ctx, filepath.Join(projectRoot, rel), rel)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		addSymbolsToGraphRecursive(topped, pr.Symbols)
+	}
+	topped.Freeze()
+
+	slog.Info("Graph store: graph topped up",
+		slog.String("project_root", projectRoot),
+		slog.Int("files_removed", len(remove)),
+		slog.Int("files_parsed", len(parse)),
+		slog.Int("parse_errors", len(parseErrors)),
+	)
+	return topped, parseErrors, nil
+}
+
+// isExcluded reports whether rel or one of its parent directories
+// matches an exclude pattern, as in parseProjectToResults.
+func isExcluded(rel string, excludes []string) bool {
+	for p := rel; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		for _, pattern := range excludes {
+			if matched, _ := filepath.Match(pattern, p); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addSymbolsToGraphRecursive adds symbols and their children as nodes,
+// skipping symbols that already exist.
+func addSymbolsToGraphRecursive(g *graph.Graph, symbols []*ast.Symbol) {
+	for _, sym := range symbols {
+		if sym == nil {
+			continue
+		}
+		_, _ = g.AddNode(sym)
+		if len(sym.Children) > 0 {
+			addSymbolsToGraphRecursive(g, sym.Children)
+		}
+	}
+}
+
+// persistGraph writes g to .aleutian/graph.db. Failures are logged only;
+// persistence is an optimization.
+func (s *Service) persistGraph(projectRoot string, g *graph.Graph, current *manifest.Manifest, params graphstore.BuildParams) {
+	store := graphstore.New(projectRoot)
+	if err := store.Save(g, current, params); err != nil {
+		slog.Warn("Graph store: failed to persist graph",
+			slog.String("path", store.Path()),
+			slog.String("error", err.Error()),
+		)
+	}
 }
 
 // parseResult holds intermediate parsing results.
@@ -511,7 +840,14 @@ func (s *Service) parseFileToResult(ctx context.Context, absPath, relPath string
 	}
 
 	// Parse the file
-	return parser.Parse(ctx, content, relPath)
+	result, err := parser.Parse(ctx, content, relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark generated/vendored files so analyzers can filter them.
+	result.AnnotateOrigin(content)
+	return result, nil
 }
 
 // isLanguageFile checks if a file extension matches any of the specified languages.
@@ -603,7 +939,10 @@ func (s *Service) GetContext(ctx context.Context, graphID, query string, budget
 //
 // Description:
 //
-//	Searches the graph for functions that call the named function.
+//	Asks the language server for the callers of the named function when
+//	LSPCallers is set and a server is installed for its language, since
+//	the server resolves calls the graph cannot, such as calls through
+//	interfaces. Otherwise searches the graph for functions that call it.
 //
 // Inputs:
 //
@@ -626,6 +965,12 @@ func (s *Service) FindCallers(ctx context.Context, graphID, functionName string,
 		limit = 50
 	}
 
+	if s.config.LSPCallers {
+		if callers, ok := s.lspFindCallers(ctx, graphID, cached, functionName, limit); ok {
+			return callers, nil
+		}
+	}
+
 	// GR-10: Use adapter for cached queries when available
 	if cached.Adapter != nil {
 		// Resolve function name to symbol IDs first using secondary index
@@ -693,6 +1038,177 @@ func (s *Service) FindCallers(ctx context.Context, graphID, functionName string,
 	return callers, nil
 }
 
+// FindTests finds the tests that exercise a function.
+//
+// Description:
+//
+//	Looks up every symbol named functionName in the graph's test mapping
+//	and returns the linked tests, highest confidence first. A test linked
+//	to several matching symbols is listed once per symbol.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - ID of the graph to query
+//	functionName - Name of the function to find tests for
+//	limit - Maximum results (<= 0 uses 50)
+//
+// Outputs:
+//
+//	[]analysis.TestLink - The linked tests (may be empty)
+//	error - ErrGraphNotInitialized or ErrGraphExpired if the graph is missing
+func (s *Service) FindTests(ctx context.Context, graphID, functionName string, limit int) ([]analysis.TestLink, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	links := make([]analysis.TestLink, 0)
+	if cached.Tests == nil {
+		return links, nil
+	}
+	for _, node := range cached.Graph.GetNodesByName(functionName) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		links = append(links, cached.Tests.TestsFor(node.ID)...)
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].Confidence > links[j].Confidence
+	})
+	if len(links) > limit {
+		links = links[:limit]
+	}
+	return links, nil
+}
+
+// blastRadiusAnalyzer creates a blast radius analyzer for the graph that
+// selects tests through its test mapping.
+func (c *CachedGraph) blastRadiusAnalyzer() *analysis.BlastRadiusAnalyzer {
+	analyzer := analysis.NewBlastRadiusAnalyzer(c.Graph, c.Index, nil)
+	analyzer.SetTestMapping(c.Tests)
+	return analyzer
+}
+
+// lspFindCallers finds the callers of a function with LSP call hierarchy.
+//
+// Description:
+//
+//	Sends an incoming calls request for every function or method named
+//	functionName. Callers are mapped to graph symbols where the graph has
+//	one at the caller's position. Returns false, so the graph answers
+//	instead, if a function's language has no installed server or any
+//	request fails.
+func (s *Service) lspFindCallers(ctx context.Context, graphID string, cached *CachedGraph, functionName string, limit int) ([]*SymbolInfo, bool) {
+	var targets []*ast.Symbol
+	for _, node := range cached.Graph.GetNodesByName(functionName) {
+		if node.Symbol != nil && (node.Symbol.Kind == ast.SymbolKindFunction || node.Symbol.Kind == ast.SymbolKindMethod) {
+			targets = append(targets, node.Symbol)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, false
+	}
+
+	mgr, err := s.getOrCreateLSPManager(graphID)
+	if err != nil {
+		return nil, false
+	}
+	ops := lsp.NewOperations(mgr)
+
+	callers := []*SymbolInfo{}
+	seen := make(map[string]bool)
+	fileSymbols := make(map[string][]*ast.Symbol)
+	for _, target := range targets {
+		filePath := target.FilePath
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(cached.ProjectRoot, filePath)
+		}
+		language, ok := mgr.Configs().LanguageForExtension(filepath.Ext(filePath))
+		if !ok || !mgr.IsAvailable(language) {
+			return nil, false
+		}
+		col, ok := nameColumn(filePath, target)
+		if !ok {
+			return nil, false
+		}
+
+		calls, err := ops.IncomingCalls(ctx, filePath, target.StartLine, col)
+		if err != nil {
+			slog.Debug("LSP incoming calls failed, using graph",
+				slog.String("symbol_id", target.ID),
+				slog.String("error", err.Error()),
+			)
+			return nil, false
+		}
+		for _, call := range calls {
+			info := lspCallerSymbol(cached, call.From, fileSymbols)
+			if !seen[info.ID] && len(callers) < limit {
+				seen[info.ID] = true
+				callers = append(callers, info)
+			}
+		}
+	}
+	return callers, true
+}
+
+// nameColumn returns the 0-indexed column of the symbol's name on its
+// first line, which is where LSP servers expect call hierarchy positions.
+func nameColumn(filePath string, sym *ast.Symbol) (int, bool) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, false
+	}
+	lines := strings.Split(string(content), "\n")
+	if sym.StartLine < 1 || sym.StartLine > len(lines) {
+		return 0, false
+	}
+	line := lines[sym.StartLine-1]
+	start := sym.StartCol
+	if start < 0 || start > len(line) {
+		start = 0
+	}
+	i := strings.Index(line[start:], sym.Name)
+	if i < 0 {
+		return 0, false
+	}
+	return start + i, true
+}
+
+// lspCallerSymbol returns the graph symbol at a caller's name, or a symbol
+// built from the call hierarchy item if the graph has none there.
+func lspCallerSymbol(cached *CachedGraph, item lsp.CallHierarchyItem, fileSymbols map[string][]*ast.Symbol) *SymbolInfo {
+	filePath := item.Path()
+	if rel, err := filepath.Rel(cached.ProjectRoot, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+		filePath = rel
+	}
+	line := item.SelectionRange.Start.Line + 1
+
+	symbols, ok := fileSymbols[filePath]
+	if !ok {
+		if cached.Index != nil {
+			symbols = cached.Index.GetByFile(filePath)
+		}
+		fileSymbols[filePath] = symbols
+	}
+	if sym := innermostSymbolAt(symbols, line, item.SelectionRange.Start.Character); sym != nil {
+		return SymbolInfoFromAST(sym)
+	}
+
+	return &SymbolInfo{
+		ID:        ast.GenerateID(filePath, item.Range.Start.Line+1, item.Name),
+		Name:      item.Name,
+		Kind:      symbolKindToString(item.Kind),
+		FilePath:  filePath,
+		StartLine: item.Range.Start.Line + 1,
+		EndLine:   item.Range.End.Line + 1,
+		Signature: item.Detail,
+	}
+}
+
 // FindImplementations returns all types that implement the given interface.
 //
 // Description:
@@ -765,6 +1281,129 @@ func (s *Service) GetSymbol(ctx context.Context, graphID, symbolID string) (*Sym
 	return SymbolInfoFromAST(sym), nil
 }
 
+// ResolvePositions resolves source positions to the symbols containing them.
+//
+// Description:
+//
+//	For every position, finds the innermost symbol whose range contains it
+//	(a method rather than its type, a function rather than its file-level
+//	declarations). Positions are resolved independently: one that cannot be
+//	resolved is reported in its SymbolResolution and does not fail the
+//	others. The symbols of each file are looked up once per call.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - ID of the graph to query
+//	positions - The positions to resolve
+//
+// Outputs:
+//
+//	[]SymbolResolution - One resolution per position, in order
+//	error - Non-nil if the graph is not found or ctx is cancelled
+func (s *Service) ResolvePositions(ctx context.Context, graphID string, positions []SymbolPosition) ([]SymbolResolution, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileSymbols := make(map[string][]*ast.Symbol)
+	results := make([]SymbolResolution, len(positions))
+	for i, pos := range positions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results[i] = resolvePosition(cached, pos, fileSymbols)
+	}
+	return results, nil
+}
+
+// resolvePosition resolves one position, caching file symbols in fileSymbols.
+func resolvePosition(cached *CachedGraph, pos SymbolPosition, fileSymbols map[string][]*ast.Symbol) SymbolResolution {
+	result := SymbolResolution{Position: pos}
+	if pos.FilePath == "" || pos.Line < 1 || pos.Column < 0 {
+		result.Error = "file_path, line >= 1 and column >= 0 are required"
+		result.Code = "INVALID_POSITION"
+		return result
+	}
+
+	filePath := filepath.Clean(pos.FilePath)
+	if filepath.IsAbs(filePath) && cached.ProjectRoot != "" {
+		if rel, err := filepath.Rel(cached.ProjectRoot, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			filePath = rel
+		}
+	}
+
+	symbols, ok := fileSymbols[filePath]
+	if !ok {
+		if cached.Index != nil {
+			symbols = cached.Index.GetByFile(filePath)
+		}
+		fileSymbols[filePath] = symbols
+	}
+	if len(symbols) == 0 {
+		result.Error = fmt.Sprintf("file not indexed: %s", pos.FilePath)
+		result.Code = "FILE_NOT_INDEXED"
+		return result
+	}
+
+	if sym := innermostSymbolAt(symbols, pos.Line, pos.Column); sym != nil {
+		result.Symbol = SymbolInfoFromAST(sym)
+		return result
+	}
+	result.Error = fmt.Sprintf("no symbol at %s:%d:%d", pos.FilePath, pos.Line, pos.Column)
+	result.Code = "SYMBOL_NOT_FOUND"
+	return result
+}
+
+// innermostSymbolAt returns the symbol with the smallest range containing
+// line and column, searching nested symbols too. Returns nil if none does.
+func innermostSymbolAt(symbols []*ast.Symbol, line, column int) *ast.Symbol {
+	var best *ast.Symbol
+	var visit func(syms []*ast.Symbol)
+	visit = func(syms []*ast.Symbol) {
+		for _, sym := range syms {
+			if sym == nil {
+				continue
+			}
+			if symbolContains(sym, line, column) && (best == nil || symbolNarrower(sym, best)) {
+				best = sym
+			}
+			visit(sym.Children)
+		}
+	}
+	visit(symbols)
+	return best
+}
+
+// symbolContains returns true if the symbol's range contains the position.
+// An end column of 0 is treated as the end of the line.
+func symbolContains(sym *ast.Symbol, line, column int) bool {
+	if line < sym.StartLine || line > sym.EndLine {
+		return false
+	}
+	if line == sym.StartLine && column < sym.StartCol {
+		return false
+	}
+	if line == sym.EndLine && sym.EndCol > 0 && column > sym.EndCol {
+		return false
+	}
+	return true
+}
+
+// symbolNarrower returns true if a's range is smaller than b's, or starts
+// later for equal line spans.
+func symbolNarrower(a, b *ast.Symbol) bool {
+	spanA, spanB := a.EndLine-a.StartLine, b.EndLine-b.StartLine
+	if spanA != spanB {
+		return spanA < spanB
+	}
+	if a.StartLine != b.StartLine {
+		return a.StartLine > b.StartLine
+	}
+	return a.StartCol > b.StartCol
+}
+
 // GetGraph retrieves a cached graph by ID.
 //
 // Description:
@@ -1052,6 +1691,118 @@ func (s *Service) evictOldPlans() {
 	}
 }
 
+// =============================================================================
+// SEMANTIC SEARCH
+// =============================================================================
+
+// Semantic search backend names reported in responses.
+const (
+	// SemanticBackendService is the remote embeddings service.
+	SemanticBackendService = "embedding_service"
+
+	// SemanticBackendLocal is the built-in hashing embedder.
+	SemanticBackendLocal = "local"
+)
+
+// GetSemanticIndex returns the semantic search index for a graph.
+//
+// Description:
+//
+//	Builds the index on first use and caches it on the CachedGraph, so it
+//	is discarded automatically when the graph is re-initialized or evicted.
+//	Uses the embeddings service when EmbeddingURL is configured and healthy,
+//	otherwise falls back to the local embedder.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - The graph to search
+//
+// Outputs:
+//
+//	*explore.SemanticSearchIndex - The built index
+//	string - The backend used (SemanticBackendService or SemanticBackendLocal)
+//	error - Non-nil if the graph is not found or the build fails
+//
+// Thread Safety:
+//
+//	Safe for concurrent use. Concurrent first calls for a graph build its
+//	index once; builds for different graphs run in parallel. A caller whose
+//	ctx ends stops waiting, but the build continues for the others.
+func (s *Service) GetSemanticIndex(ctx context.Context, graphID string) (*explore.SemanticSearchIndex, string, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.semanticMu.Lock()
+	ssi, backend := cached.SemanticIndex, cached.SemanticBackend
+	s.semanticMu.Unlock()
+	if ssi != nil {
+		return ssi, backend, nil
+	}
+
+	// Keyed by generation so a refreshed graph does not join a build of
+	// the graph it replaced.
+	key := fmt.Sprintf("%s@%d", graphID, cached.Generation)
+	ch := s.semanticBuilds.DoChan(key, func() (any, error) {
+		return s.buildSemanticIndex(context.WithoutCancel(ctx), cached)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, "", res.Err
+		}
+		built := res.Val.(*CachedGraph)
+		return built.SemanticIndex, built.SemanticBackend, nil
+	}
+}
+
+// buildSemanticIndex builds the semantic index for a graph and stores it
+// on the CachedGraph, unless another build already stored one.
+//
+// Outputs:
+//
+//	*CachedGraph - cached, whose SemanticIndex fields are set
+//	error - Non-nil if the build fails
+func (s *Service) buildSemanticIndex(ctx context.Context, cached *CachedGraph) (*CachedGraph, error) {
+	s.semanticMu.Lock()
+	done := cached.SemanticIndex != nil
+	s.semanticMu.Unlock()
+	if done {
+		return cached, nil
+	}
+
+	var embedder explore.Embedder
+	backend := SemanticBackendLocal
+	if s.config.EmbeddingURL != "" {
+		client := explore.NewEmbeddingClient(s.config.EmbeddingURL)
+		if err := client.Health(ctx); err != nil {
+			slog.Warn("Embeddings service unavailable, using local embedder",
+				slog.String("url", s.config.EmbeddingURL),
+				slog.String("error", err.Error()))
+		} else {
+			embedder = client
+			backend = SemanticBackendService
+		}
+	}
+
+	ssi := explore.NewSemanticSearchIndex(cached.Index, embedder)
+	if err := ssi.Build(ctx); err != nil {
+		return nil, fmt.Errorf("build semantic index: %w", err)
+	}
+
+	s.semanticMu.Lock()
+	defer s.semanticMu.Unlock()
+	if cached.SemanticIndex == nil {
+		cached.SemanticIndex = ssi
+		cached.SemanticBackend = backend
+	}
+	return cached, nil
+}
+
 // =============================================================================
 // LSP INTEGRATION METHODS (CB-24)
 // =============================================================================
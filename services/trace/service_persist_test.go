@@ -0,0 +1,125 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graphstore"
+)
+
+// writeProject writes files (relative path to content) under dir.
+func writeProject(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// cachedGraph returns the graph Init cached for graphID.
+func cachedGraph(t *testing.T, svc *Service, graphID string) *graph.Graph {
+	t.Helper()
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	cached, ok := svc.graphs[graphID]
+	if !ok {
+		t.Fatalf("graph %s not cached", graphID)
+	}
+	return cached.Graph
+}
+
+func TestService_Init_TopsUpImportedBundle(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\nfunc start() {\n\thelper()\n\tother()\n}\n\nfunc other() {}\n",
+		"util.go": "package main\n\nfunc helper() {}\n",
+	}
+
+	// Build and export on one machine.
+	src := t.TempDir()
+	writeProject(t, src, files)
+	cfg := DefaultServiceConfig()
+	cfg.PersistGraphs = true
+	if _, err := NewService(cfg).Init(ctx, src, []string{"go"}, nil); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	var bundle bytes.Buffer
+	if _, err := graphstore.New(src).Export(&bundle); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	// Import into a checkout where util.go changed and extra.go is new.
+	importInto := func(t *testing.T) string {
+		dst := t.TempDir()
+		writeProject(t, dst, files)
+		writeProject(t, dst, map[string]string{
+			"util.go":   "package main\n\nfunc helper() {}\n\nfunc renamed() {}\n",
+			"extra.go":  "package main\n\nfunc extra() {}\n",
+			"README.md": "not a source file\n",
+		})
+		if _, err := graphstore.New(dst).Import(bytes.NewReader(bundle.Bytes())); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+		return dst
+	}
+	t.Run("top up", func(t *testing.T) {
+		dst := importInto(t)
+		cfg.MaxTopUpFiles = 2
+		svc := NewService(cfg)
+		resp, err := svc.Init(ctx, dst, []string{"go"}, nil)
+		if err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		g := cachedGraph(t, svc, resp.GraphID)
+		for _, name := range []string{"start", "other", "helper", "renamed", "extra"} {
+			if len(g.GetNodesByName(name)) != 1 {
+				t.Errorf("%s: %d nodes, want 1", name, len(g.GetNodesByName(name)))
+			}
+		}
+		if !g.IsFrozen() {
+			t.Error("topped-up graph is not frozen")
+		}
+		// Edges between unchanged files come from the bundle.
+		if other := g.GetNodesByName("other"); len(other) != 1 || len(other[0].Incoming) != 1 {
+			t.Error("topped-up graph lost the call from start to other")
+		}
+	})
+
+	t.Run("too many changes rebuild", func(t *testing.T) {
+		dst := importInto(t)
+		cfg.MaxTopUpFiles = 1
+		svc := NewService(cfg)
+		resp, err := svc.Init(ctx, dst, []string{"go"}, nil)
+		if err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		g := cachedGraph(t, svc, resp.GraphID)
+		if len(g.GetNodesByName("extra")) != 1 {
+			t.Error("rebuilt graph misses extra")
+		}
+		// A rebuild re-resolves calls into changed files; a top-up does not.
+		if helper := g.GetNodesByName("helper"); len(helper) != 1 || len(helper[0].Incoming) != 1 {
+			t.Error("rebuilt graph misses the call to helper")
+		}
+	})
+}
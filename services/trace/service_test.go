@@ -0,0 +1,83 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
+)
+
+func TestService_GetSemanticIndex_PerGraph(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(DefaultServiceConfig())
+
+	graphIDs := make([]string, 2)
+	for i := range graphIDs {
+		dir := t.TempDir()
+		writeProject(t, dir, map[string]string{
+			"go.mod":  "module example.com/app\n",
+			"main.go": "package main\n\n// start parses the config.\nfunc start() {}\n",
+		})
+		resp, err := svc.Init(ctx, dir, []string{"go"}, nil)
+		if err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		graphIDs[i] = resp.GraphID
+	}
+
+	const callers = 8
+	indexes := make([][]*explore.SemanticSearchIndex, len(graphIDs))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for g, graphID := range graphIDs {
+		for range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ssi, backend, err := svc.GetSemanticIndex(ctx, graphID)
+				if err != nil {
+					t.Errorf("GetSemanticIndex: %v", err)
+					return
+				}
+				if backend != SemanticBackendLocal {
+					t.Errorf("backend = %q, want %q", backend, SemanticBackendLocal)
+				}
+				mu.Lock()
+				indexes[g] = append(indexes[g], ssi)
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+
+	for g, got := range indexes {
+		if len(got) != callers {
+			t.Fatalf("graph %d: %d results, want %d", g, len(got), callers)
+		}
+		for _, ssi := range got {
+			if ssi != got[0] {
+				t.Fatalf("graph %d: concurrent calls built more than one index", g)
+			}
+		}
+	}
+	if indexes[0][0] == indexes[1][0] {
+		t.Error("graphs share a semantic index")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if ssi, _, err := svc.GetSemanticIndex(cancelled, graphIDs[0]); err != nil || ssi != indexes[0][0] {
+		t.Errorf("cached index with a cancelled context = %p, %v; want the cached index", ssi, err)
+	}
+}
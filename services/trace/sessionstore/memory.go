@@ -0,0 +1,137 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package sessionstore
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Memory is a process-local Backend.
+//
+// It shares sessions only between stores in the same process, which makes
+// it useful for tests and single-replica deployments that want the same
+// encode/decode path as a shared store.
+type Memory struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	data   map[string][]byte
+	leases map[string]memoryLease
+	nextID uint64
+}
+
+// memoryLease is one held lease.
+type memoryLease struct {
+	token   uint64
+	expires time.Time
+}
+
+// NewMemory creates an empty in-memory backend.
+func NewMemory() *Memory {
+	return &Memory{
+		now:    time.Now,
+		data:   make(map[string][]byte),
+		leases: make(map[string]memoryLease),
+	}
+}
+
+// Load implements Backend.
+func (m *Memory) Load(_ context.Context, id string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(data), nil
+}
+
+// Save implements Backend.
+func (m *Memory) Save(_ context.Context, id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[id] = slices.Clone(data)
+	return nil
+}
+
+// Delete implements Backend.
+func (m *Memory) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, id)
+	return nil
+}
+
+// List implements Backend.
+func (m *Memory) List(_ context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.data))
+	for id := range m.data {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Lock implements Backend.
+func (m *Memory) Lock(_ context.Context, id string, ttl time.Duration) (Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if l, ok := m.leases[id]; ok && now.Before(l.expires) {
+		return nil, ErrLocked
+	}
+	m.nextID++
+	token := m.nextID
+	m.leases[id] = memoryLease{token: token, expires: now.Add(ttl)}
+	return &memoryHeld{m: m, id: id, token: token}, nil
+}
+
+// memoryHeld is a Lease on a Memory backend.
+type memoryHeld struct {
+	m     *Memory
+	id    string
+	token uint64
+	once  sync.Once
+}
+
+// Renew implements Lease.
+func (h *memoryHeld) Renew(_ context.Context, ttl time.Duration) error {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	l, ok := h.m.leases[h.id]
+	if !ok || l.token != h.token {
+		return ErrLocked
+	}
+	l.expires = h.m.now().Add(ttl)
+	h.m.leases[h.id] = l
+	return nil
+}
+
+// Release implements Lease.
+func (h *memoryHeld) Release() {
+	h.once.Do(func() {
+		h.m.mu.Lock()
+		defer h.m.mu.Unlock()
+		if h.m.leases[h.id].token == h.token {
+			delete(h.m.leases, h.id)
+		}
+	})
+}
+
+// Close implements Backend.
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package sessionstore
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRedisPrefix namespaces session keys.
+	defaultRedisPrefix = "aleutian:session:"
+
+	// redisPoolSize is the number of idle connections kept.
+	redisPoolSize = 8
+
+	// redisTimeout bounds each command when ctx has no deadline.
+	redisTimeout = 5 * time.Second
+)
+
+// unlockScript deletes the lease only if it still holds our token.
+const unlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// renewScript extends the lease only if it still holds our token.
+const renewScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+// Redis is a Backend storing sessions in Redis.
+//
+// Sessions are stored as strings under <prefix><id> with an idle TTL, and
+// leases under <prefix>lock:<id> using SET NX PX. It speaks the RESP
+// protocol directly over a small connection pool.
+type Redis struct {
+	addr     string
+	username string
+	password string
+	db       int
+	prefix   string
+	ttl      time.Duration
+
+	pool chan *redisConn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// redisConn is one pooled connection.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// redisError is an error reply from the server.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// DialRedis connects to Redis.
+//
+// # Inputs
+//
+//   - ctx: Bounds the initial connection.
+//   - u: redis://[[user]:password@]host[:port][/db]. A user is sent to
+//     AUTH with the password (Redis 6 ACLs); a user without a password is
+//     ignored. Query parameters "prefix" (key namespace) and "ttl" (idle
+//     session TTL, e.g. "12h") are optional.
+//
+// # Outputs
+//
+//   - *Redis: The backend, verified with PING.
+//   - error: Non-nil if the URL is invalid or the server is unreachable.
+func DialRedis(ctx context.Context, u *url.URL) (*Redis, error) {
+	r, err := parseRedisURL(u)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.do(ctx, "PING"); err != nil {
+		return nil, fmt.Errorf("connecting to redis at %s: %w", r.addr, err)
+	}
+	return r, nil
+}
+
+// parseRedisURL creates an unconnected backend from a redis:// URL.
+func parseRedisURL(u *url.URL) (*Redis, error) {
+	r := &Redis{
+		addr:   u.Host,
+		prefix: defaultRedisPrefix,
+		ttl:    DefaultTTL,
+		pool:   make(chan *redisConn, redisPoolSize),
+		closed: make(chan struct{}),
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		if pw, ok := u.User.Password(); ok {
+			r.username = u.User.Username()
+			r.password = pw
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+		r.db = n
+	}
+	q := u.Query()
+	if p := q.Get("prefix"); p != "" {
+		r.prefix = p
+	}
+	if t := q.Get("ttl"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid redis ttl %q", t)
+		}
+		r.ttl = d
+	}
+	return r, nil
+}
+
+// Load implements Backend.
+func (r *Redis) Load(ctx context.Context, id string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.prefix+id)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := reply.([]byte)
+	if !ok || data == nil {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// Save implements Backend.
+func (r *Redis) Save(ctx context.Context, id string, data []byte) error {
+	_, err := r.do(ctx, "SET", r.prefix+id, string(data), "PX", strconv.FormatInt(r.ttl.Milliseconds(), 10))
+	return err
+}
+
+// Delete implements Backend.
+func (r *Redis) Delete(ctx context.Context, id string) error {
+	_, err := r.do(ctx, "DEL", r.prefix+id)
+	return err
+}
+
+// List implements Backend.
+func (r *Redis) List(ctx context.Context) ([]string, error) {
+	var ids []string
+	lockPrefix := r.lockKey("")
+	cursor := "0"
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", r.prefix+"*", "COUNT", "100")
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply %T", reply)
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]any)
+		for _, k := range keys {
+			key, _ := k.([]byte)
+			if strings.HasPrefix(string(key), lockPrefix) {
+				continue
+			}
+			ids = append(ids, strings.TrimPrefix(string(key), r.prefix))
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return ids, nil
+		}
+	}
+}
+
+// Lock implements Backend.
+func (r *Redis) Lock(ctx context.Context, id string, ttl time.Duration) (Lease, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, fmt.Errorf("generating lease token: %w", err)
+	}
+	token := hex.EncodeToString(buf[:])
+	key := r.lockKey(id)
+
+	reply, err := r.do(ctx, "SET", key, token, "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrLocked
+	}
+	return &redisLease{r: r, key: key, token: token}, nil
+}
+
+// redisLease is a Lease on a Redis backend.
+type redisLease struct {
+	r     *Redis
+	key   string
+	token string
+	once  sync.Once
+}
+
+// Renew implements Lease.
+func (l *redisLease) Renew(ctx context.Context, ttl time.Duration) error {
+	reply, err := l.r.do(ctx, "EVAL", renewScript, "1", l.key, l.token, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return err
+	}
+	if n, _ := reply.(int64); n == 0 {
+		return ErrLocked
+	}
+	return nil
+}
+
+// Release implements Lease.
+func (l *redisLease) Release() {
+	l.once.Do(func() {
+		// Best effort: an unreleased lease expires after its ttl.
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		defer cancel()
+		_, _ = l.r.do(ctx, "EVAL", unlockScript, "1", l.key, l.token)
+	})
+}
+
+// Close implements Backend.
+func (r *Redis) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+		for {
+			select {
+			case c := <-r.pool:
+				c.conn.Close()
+			default:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func (r *Redis) lockKey(id string) string {
+	return r.prefix + "lock:" + id
+}
+
+// do runs one command and returns its reply: []byte for bulk and simple
+// strings, int64 for integers, []any for arrays, or nil for null.
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	select {
+	case <-r.closed:
+		return nil, errors.New("redis: backend closed")
+	default:
+	}
+
+	c, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	_ = c.conn.SetDeadline(deadline)
+
+	reply, err := c.roundTrip(args)
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		// The connection state is unknown after an I/O error.
+		c.conn.Close()
+		return nil, fmt.Errorf("redis %s: %w", args[0], err)
+	}
+	r.put(c)
+	return reply, err
+}
+
+// get returns a pooled connection or dials a new one.
+func (r *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.pool:
+		return c, nil
+	default:
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	_ = conn.SetDeadline(time.Now().Add(redisTimeout))
+	if r.password != "" {
+		auth := []string{"AUTH", r.password}
+		if r.username != "" {
+			auth = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.roundTrip(auth); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.roundTrip([]string{"SELECT", strconv.Itoa(r.db)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// put returns a connection to the pool, closing it if the pool is full.
+func (r *Redis) put(c *redisConn) {
+	select {
+	case <-r.closed:
+		c.conn.Close()
+		return
+	default:
+	}
+	select {
+	case r.pool <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// roundTrip writes a command and reads its reply.
+func (c *redisConn) roundTrip(args []string) (any, error) {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// readReply parses one RESP reply.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return []byte(body), nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package slo
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric names, as exported to Prometheus.
+const (
+	RequestsMetric = "trace_route_requests_total"
+	DurationMetric = "trace_route_request_duration_seconds"
+)
+
+// unmatchedRoute labels requests that matched no route.
+const unmatchedRoute = "unmatched"
+
+// Metrics records request counts and latencies per route.
+//
+// Thread Safety: Safe for concurrent use.
+type Metrics struct {
+	cfg      *Config
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics registers the route metrics with reg.
+//
+// # Description
+//
+// RequestsMetric is labelled by method, route template, class and status
+// code class ("2xx", "4xx", "5xx", ...). DurationMetric is labelled by
+// method, route and class, with buckets that include every class's latency
+// threshold.
+//
+// # Outputs
+//
+//   - *Metrics: The registered metrics.
+//   - error: The configuration is invalid or registration failed.
+func NewMetrics(reg prometheus.Registerer, cfg *Config) (*Metrics, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	m := &Metrics{
+		cfg: cfg,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: RequestsMetric,
+			Help: "HTTP requests by route template, SLO class and status code class",
+		}, []string{"method", "route", "class", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    DurationMetric,
+			Help:    "HTTP request latency in seconds by route template and SLO class",
+			Buckets: cfg.latencyBuckets(),
+		}, []string{"method", "route", "class"}),
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.duration} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// InitRoutes creates zero-valued series for every route, so burn-rate
+// expressions have data before a route's first error.
+func (m *Metrics) InitRoutes(routes gin.RoutesInfo) {
+	for _, r := range routes {
+		class := m.cfg.Classify(r.Method, r.Path)
+		for _, code := range []string{"2xx", "4xx", "5xx"} {
+			m.requests.WithLabelValues(r.Method, r.Path, class, code)
+		}
+		m.duration.WithLabelValues(r.Method, r.Path, class)
+	}
+}
+
+// Middleware returns gin middleware that records every request.
+//
+// Install it on the engine before any route is added, so it sees every
+// request including ones rejected by later middleware.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		class := m.cfg.Classify(c.Request.Method, route)
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		m.requests.WithLabelValues(method, route, class, codeClass(c.Writer.Status())).Inc()
+		m.duration.WithLabelValues(method, route, class).Observe(time.Since(start).Seconds())
+	}
+}
+
+// codeClass returns the status code class, e.g. "5xx" for 503.
+func codeClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package slo
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// Alert names used in generated rules.
+const (
+	ErrorBurnAlert   = "TraceRouteErrorBudgetBurn"
+	LatencyBurnAlert = "TraceRouteLatencyBudgetBurn"
+)
+
+// RuleFile is a Prometheus rule file.
+type RuleFile struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a named group of rules.
+type RuleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a Prometheus alerting rule.
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// GenerateRules builds burn-rate alerts for a route table.
+//
+// # Description
+//
+// Routes are grouped by class into one rule group per class
+// ("trace-slo-<class>"); ClassNone routes are skipped. Each route gets an
+// error budget alert (5xx responses) and a latency budget alert (requests
+// slower than the class's threshold) for every burn window. An alert
+// fires when the bad-request ratio over both the long and the short window
+// exceeds Factor times the class's error budget.
+//
+// # Outputs
+//
+//   - *RuleFile: The rules, ordered by class, route and window.
+//   - error: The configuration is invalid.
+func GenerateRules(cfg *Config, routes gin.RoutesInfo) (*RuleFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	byClass := make(map[string][]gin.RouteInfo)
+	for _, r := range routes {
+		if class := cfg.Classify(r.Method, r.Path); class != ClassNone {
+			byClass[class] = append(byClass[class], r)
+		}
+	}
+	classes := make([]string, 0, len(byClass))
+	for class := range byClass {
+		classes = append(classes, class)
+	}
+	slices.Sort(classes)
+
+	file := &RuleFile{}
+	for _, class := range classes {
+		obj := cfg.Classes[class]
+		group := RuleGroup{Name: "trace-slo-" + class}
+
+		routes := byClass[class]
+		slices.SortFunc(routes, func(a, b gin.RouteInfo) int {
+			return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+		})
+		for _, r := range routes {
+			sel := fmt.Sprintf(`method=%q,route=%q`, r.Method, r.Path)
+			for _, w := range cfg.BurnWindows {
+				group.Rules = append(group.Rules,
+					errorBurnRule(class, obj, r, sel, w),
+					latencyBurnRule(class, obj, r, sel, w),
+				)
+			}
+		}
+		file.Groups = append(file.Groups, group)
+	}
+	return file, nil
+}
+
+// YAML encodes the rule file for Prometheus.
+func (f *RuleFile) YAML() ([]byte, error) {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// RulesHandler serves the rules for the current route table as YAML.
+//
+// routes is usually the engine's Routes method, so the rules always match
+// the registered routes.
+func RulesHandler(cfg *Config, routes func() gin.RoutesInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, err := GenerateRules(cfg, routes())
+		if err == nil {
+			var data []byte
+			if data, err = file.YAML(); err == nil {
+				c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "code": "SLO_RULES_FAILED"})
+	}
+}
+
+// errorBurnRule alerts when 5xx responses burn the availability budget.
+func errorBurnRule(class string, obj Objective, r gin.RouteInfo, sel string, w BurnWindow) Rule {
+	budget := 1 - obj.Availability
+	ratio := func(window time.Duration) string {
+		return fmt.Sprintf(`sum(rate(%s{%s,code="5xx"}[%s])) / sum(rate(%s{%s}[%s]))`,
+			RequestsMetric, sel, promDuration(window), RequestsMetric, sel, promDuration(window))
+	}
+	return burnRule(ErrorBurnAlert, class, r, w, ratio, budget,
+		fmt.Sprintf("%s %s is burning its error budget", r.Method, r.Path),
+		fmt.Sprintf("More than %s of requests failed over the last %s and %s (objective: %s available).",
+			percent(w.Factor*budget), promDuration(w.Long), promDuration(w.Short), percent(obj.Availability)))
+}
+
+// latencyBurnRule alerts when slow requests burn the latency budget.
+func latencyBurnRule(class string, obj Objective, r gin.RouteInfo, sel string, w BurnWindow) Rule {
+	budget := 1 - obj.LatencyTarget
+	le := strconv.FormatFloat(obj.LatencyThreshold.Seconds(), 'g', -1, 64)
+	ratio := func(window time.Duration) string {
+		return fmt.Sprintf(`1 - sum(rate(%s_bucket{%s,le=%q}[%s])) / sum(rate(%s_count{%s}[%s]))`,
+			DurationMetric, sel, le, promDuration(window), DurationMetric, sel, promDuration(window))
+	}
+	return burnRule(LatencyBurnAlert, class, r, w, ratio, budget,
+		fmt.Sprintf("%s %s is burning its latency budget", r.Method, r.Path),
+		fmt.Sprintf("More than %s of requests took longer than %s over the last %s and %s (objective: %s within %s).",
+			percent(w.Factor*budget), obj.LatencyThreshold, promDuration(w.Long), promDuration(w.Short),
+			percent(obj.LatencyTarget), obj.LatencyThreshold))
+}
+
+// burnRule combines the long and short window ratios into one alert.
+func burnRule(alert, class string, r gin.RouteInfo, w BurnWindow, ratio func(time.Duration) string, budget float64, summary, description string) Rule {
+	threshold := strconv.FormatFloat(w.Factor*budget, 'g', 6, 64)
+	rule := Rule{
+		Alert: alert,
+		Expr:  fmt.Sprintf("(%s) > %s\nand\n(%s) > %s", ratio(w.Long), threshold, ratio(w.Short), threshold),
+		Labels: map[string]string{
+			"severity":    w.Severity,
+			"slo_class":   class,
+			"method":      r.Method,
+			"route":       r.Path,
+			"burn_window": promDuration(w.Long),
+		},
+		Annotations: map[string]string{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+	if w.For > 0 {
+		rule.For = promDuration(w.For)
+	}
+	return rule
+}
+
+// promDuration formats d in Prometheus duration syntax using its largest
+// exact unit, e.g. "3d", "90m".
+func promDuration(d time.Duration) string {
+	for _, u := range []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	} {
+		if d%u.unit == 0 {
+			return strconv.FormatInt(int64(d/u.unit), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
+
+// percent formats a fraction as a percentage, e.g. 0.0144 as "1.44%".
+func percent(f float64) string {
+	return strconv.FormatFloat(f*100, 'g', 4, 64) + "%"
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package slo records per-route Prometheus metrics for the Gin route table
+// and generates SLO burn-rate alerting rules from them.
+//
+// Every route belongs to a class (interactive queries, agent runs, graph
+// builds, ...) with an availability and latency objective. Metrics records
+// request counts and latencies labelled by route template and class, and
+// GenerateRules turns the route table into multiwindow, multi-burn-rate
+// Prometheus alerts, so operators get error and latency budget alerts for
+// every endpoint without writing rules by hand.
+//
+// # Configuration
+//
+// DefaultConfig covers the built-in routes. LoadConfig reads overrides
+// from YAML:
+//
+//	classes:
+//	  query:
+//	    availability: 0.9995
+//	    latency_threshold: 250ms
+//	    latency_target: 0.99
+//	routes:
+//	  - method: POST
+//	    path_prefix: /v1/codebuddy/explore
+//	    class: query
+//	burn_windows:
+//	  - {long: 1h, short: 5m, factor: 14.4, severity: page}
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route classes used by DefaultConfig.
+const (
+	// ClassQuery is for interactive reads and graph queries.
+	ClassQuery = "query"
+
+	// ClassAgent is for routes that run the LLM agent.
+	ClassAgent = "agent"
+
+	// ClassBuild is for routes that build or seed indexes.
+	ClassBuild = "build"
+
+	// ClassNone marks routes that are measured but have no objective.
+	ClassNone = "none"
+)
+
+// ErrInvalidConfig indicates an SLO configuration that cannot be used.
+var ErrInvalidConfig = errors.New("invalid SLO config")
+
+// Objective is the service level objective of a route class.
+type Objective struct {
+	// Availability is the fraction of requests that must not fail with a
+	// 5xx status, e.g. 0.999.
+	Availability float64 `yaml:"availability"`
+
+	// LatencyThreshold is the duration a request must complete within to
+	// count as fast. It is added to the latency histogram buckets.
+	LatencyThreshold time.Duration `yaml:"latency_threshold"`
+
+	// LatencyTarget is the fraction of requests that must be fast, e.g.
+	// 0.99.
+	LatencyTarget float64 `yaml:"latency_target"`
+}
+
+// RouteRule assigns routes to a class.
+type RouteRule struct {
+	// Method matches the HTTP method. Empty matches any method.
+	Method string `yaml:"method"`
+
+	// PathPrefix matches route templates (e.g. "/v1/codebuddy/agent")
+	// that start with it.
+	PathPrefix string `yaml:"path_prefix"`
+
+	// Class is the class of matching routes.
+	Class string `yaml:"class"`
+}
+
+// BurnWindow is one burn-rate alert.
+//
+// The alert fires when both the long and the short window consume error
+// budget Factor times faster than the objective allows. The short window
+// makes the alert resolve quickly once the problem stops.
+type BurnWindow struct {
+	Long     time.Duration `yaml:"long"`
+	Short    time.Duration `yaml:"short"`
+	Factor   float64       `yaml:"factor"`
+	Severity string        `yaml:"severity"`
+
+	// For delays firing until the condition has held this long.
+	For time.Duration `yaml:"for"`
+}
+
+// Config maps routes to classes and classes to objectives.
+type Config struct {
+	// Classes holds the objective of each class.
+	Classes map[string]Objective `yaml:"classes"`
+
+	// Routes assigns routes to classes. The first matching rule wins.
+	Routes []RouteRule `yaml:"routes"`
+
+	// DefaultClass is the class of routes no rule matches.
+	DefaultClass string `yaml:"default_class"`
+
+	// BurnWindows are the alerts generated for each objective.
+	BurnWindows []BurnWindow `yaml:"burn_windows"`
+}
+
+// DefaultConfig returns objectives for the built-in routes.
+//
+// # Description
+//
+// Agent routes wait on the LLM and graph initialization parses whole
+// projects, so both get looser latency objectives than queries. Burn
+// windows follow the usual 30-day budget scheme: page at 14.4x over
+// 1h/5m and 6x over 6h/30m, open a ticket at 1x over 3d/6h.
+func DefaultConfig() *Config {
+	return &Config{
+		Classes: map[string]Objective{
+			ClassQuery: {Availability: 0.999, LatencyThreshold: time.Second, LatencyTarget: 0.99},
+			ClassAgent: {Availability: 0.99, LatencyThreshold: time.Minute, LatencyTarget: 0.9},
+			ClassBuild: {Availability: 0.99, LatencyThreshold: 2 * time.Minute, LatencyTarget: 0.95},
+		},
+		Routes: []RouteRule{
+			{Method: "POST", PathPrefix: "/v1/codebuddy/agent", Class: ClassAgent},
+			{Method: "POST", PathPrefix: "/v1/trace/agent", Class: ClassAgent},
+			{Method: "POST", PathPrefix: "/v1/codebuddy/init", Class: ClassBuild},
+			{Method: "POST", PathPrefix: "/v1/codebuddy/seed", Class: ClassBuild},
+			{PathPrefix: "/metrics", Class: ClassNone},
+		},
+		DefaultClass: ClassQuery,
+		BurnWindows: []BurnWindow{
+			{Long: time.Hour, Short: 5 * time.Minute, Factor: 14.4, Severity: "page"},
+			{Long: 6 * time.Hour, Short: 30 * time.Minute, Factor: 6, Severity: "page"},
+			{Long: 72 * time.Hour, Short: 6 * time.Hour, Factor: 1, Severity: "ticket"},
+		},
+	}
+}
+
+// LoadConfig reads SLO overrides from a YAML file.
+//
+// # Description
+//
+// Classes in the file replace the default class of the same name and add
+// new classes. Route rules in the file are matched before the defaults.
+// default_class and burn_windows replace the defaults when set.
+//
+// # Outputs
+//
+//   - *Config: The merged configuration.
+//   - error: The file could not be read or the result is invalid.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading SLO config: %w", err)
+	}
+	var file Config
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+
+	cfg := DefaultConfig()
+	for name, obj := range file.Classes {
+		cfg.Classes[name] = obj
+	}
+	cfg.Routes = append(file.Routes, cfg.Routes...)
+	if file.DefaultClass != "" {
+		cfg.DefaultClass = file.DefaultClass
+	}
+	if len(file.BurnWindows) > 0 {
+		cfg.BurnWindows = file.BurnWindows
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate reports whether the configuration is usable.
+func (c *Config) Validate() error {
+	for name, obj := range c.Classes {
+		if name == ClassNone {
+			return fmt.Errorf("%w: class %q is reserved", ErrInvalidConfig, ClassNone)
+		}
+		if obj.Availability <= 0 || obj.Availability >= 1 {
+			return fmt.Errorf("%w: class %q: availability must be between 0 and 1", ErrInvalidConfig, name)
+		}
+		if obj.LatencyTarget <= 0 || obj.LatencyTarget >= 1 {
+			return fmt.Errorf("%w: class %q: latency_target must be between 0 and 1", ErrInvalidConfig, name)
+		}
+		if obj.LatencyThreshold <= 0 {
+			return fmt.Errorf("%w: class %q: latency_threshold must be positive", ErrInvalidConfig, name)
+		}
+	}
+	for _, rule := range append(c.Routes, RouteRule{Class: c.DefaultClass}) {
+		if _, ok := c.Classes[rule.Class]; !ok && rule.Class != ClassNone {
+			return fmt.Errorf("%w: unknown class %q", ErrInvalidConfig, rule.Class)
+		}
+	}
+	for _, w := range c.BurnWindows {
+		if w.Short <= 0 || w.Long <= w.Short || w.Factor <= 0 {
+			return fmt.Errorf("%w: burn window %s/%s needs 0 < short < long and a positive factor", ErrInvalidConfig, w.Long, w.Short)
+		}
+	}
+	return nil
+}
+
+// Classify returns the class of a route.
+//
+// route is the Gin route template (e.g. "/v1/codebuddy/symbol/:id").
+// Unmatched requests, with an empty route, are ClassNone.
+func (c *Config) Classify(method, route string) string {
+	if route == "" {
+		return ClassNone
+	}
+	for _, rule := range c.Routes {
+		if (rule.Method == "" || strings.EqualFold(rule.Method, method)) && strings.HasPrefix(route, rule.PathPrefix) {
+			return rule.Class
+		}
+	}
+	return c.DefaultClass
+}
+
+// latencyBuckets returns histogram buckets that include every class's
+// latency threshold, so the fraction of fast requests can be read off a
+// bucket exactly.
+func (c *Config) latencyBuckets() []float64 {
+	buckets := []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+	for _, obj := range c.Classes {
+		buckets = append(buckets, obj.LatencyThreshold.Seconds())
+	}
+	slices.Sort(buckets)
+	return slices.Compact(buckets)
+}
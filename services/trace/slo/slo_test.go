@@ -0,0 +1,225 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package slo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestConfig_Classify(t *testing.T) {
+	cfg := DefaultConfig()
+	tests := []struct {
+		method, route, want string
+	}{
+		{"POST", "/v1/codebuddy/agent/run", ClassAgent},
+		{"GET", "/v1/codebuddy/agent/:id", ClassQuery},
+		{"POST", "/v1/codebuddy/init", ClassBuild},
+		{"GET", "/v1/codebuddy/callers", ClassQuery},
+		{"GET", "/metrics", ClassNone},
+		{"GET", "", ClassNone},
+	}
+	for _, tt := range tests {
+		if got := cfg.Classify(tt.method, tt.route); got != tt.want {
+			t.Errorf("Classify(%s %s) = %q, want %q", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slo.yaml")
+	write := func(s string) {
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`
+classes:
+  query:
+    availability: 0.9995
+    latency_threshold: 250ms
+    latency_target: 0.99
+  bulk:
+    availability: 0.95
+    latency_threshold: 10m
+    latency_target: 0.5
+routes:
+  - path_prefix: /v1/codebuddy/patterns
+    class: bulk
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Classes[ClassQuery].LatencyThreshold; got != 250*time.Millisecond {
+		t.Errorf("query threshold = %v", got)
+	}
+	if _, ok := cfg.Classes[ClassAgent]; !ok {
+		t.Error("default classes dropped")
+	}
+	if got := cfg.Classify("POST", "/v1/codebuddy/patterns/detect"); got != "bulk" {
+		t.Errorf("patterns class = %q, want bulk", got)
+	}
+	if got := cfg.Classify("POST", "/v1/codebuddy/agent/run"); got != ClassAgent {
+		t.Errorf("default rules dropped: agent class = %q", got)
+	}
+
+	for name, body := range map[string]string{
+		"bad availability": "classes: {query: {availability: 1, latency_threshold: 1s, latency_target: 0.9}}",
+		"unknown class":    "routes: [{path_prefix: /x, class: missing}]",
+		"bad window":       "burn_windows: [{long: 5m, short: 1h, factor: 2}]",
+	} {
+		write(body)
+		if _, err := LoadConfig(path); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: err = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewMetrics(reg, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/v1/codebuddy/symbol/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/v1/codebuddy/agent/run", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+	m.InitRoutes(r.Routes())
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/v1/codebuddy/symbol/a", nil),
+		httptest.NewRequest(http.MethodGet, "/v1/codebuddy/symbol/b", nil),
+		httptest.NewRequest(http.MethodPost, "/v1/codebuddy/agent/run", nil),
+		httptest.NewRequest(http.MethodGet, "/nowhere", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/v1/codebuddy/symbol/:id", ClassQuery, "2xx")); got != 2 {
+		t.Errorf("symbol 2xx = %v, want 2 (route template, not path)", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("POST", "/v1/codebuddy/agent/run", ClassAgent, "5xx")); got != 1 {
+		t.Errorf("agent 5xx = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", unmatchedRoute, ClassNone, "4xx")); got != 1 {
+		t.Errorf("unmatched 4xx = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("POST", "/v1/codebuddy/agent/run", ClassAgent, "4xx")); got != 0 {
+		t.Errorf("pre-initialized series = %v, want 0", got)
+	}
+
+	// Every class threshold is a bucket boundary.
+	buckets := DefaultConfig().latencyBuckets()
+	for _, obj := range DefaultConfig().Classes {
+		found := false
+		for _, b := range buckets {
+			found = found || b == obj.LatencyThreshold.Seconds()
+		}
+		if !found {
+			t.Errorf("threshold %v missing from buckets %v", obj.LatencyThreshold, buckets)
+		}
+	}
+}
+
+func TestGenerateRules(t *testing.T) {
+	routes := gin.RoutesInfo{
+		{Method: "GET", Path: "/v1/codebuddy/callers"},
+		{Method: "POST", Path: "/v1/codebuddy/agent/run"},
+		{Method: "GET", Path: "/metrics"},
+	}
+	cfg := DefaultConfig()
+	file, err := GenerateRules(cfg, routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(file.Groups) != 2 || file.Groups[0].Name != "trace-slo-agent" || file.Groups[1].Name != "trace-slo-query" {
+		t.Fatalf("groups = %+v", file.Groups)
+	}
+	query := file.Groups[1].Rules
+	if want := 2 * len(cfg.BurnWindows); len(query) != want {
+		t.Fatalf("query rules = %d, want %d", len(query), want)
+	}
+
+	errRule, latRule := query[0], query[1]
+	if errRule.Alert != ErrorBurnAlert || errRule.Labels["severity"] != "page" || errRule.Labels["burn_window"] != "1h" {
+		t.Errorf("error rule = %+v", errRule)
+	}
+	// 14.4x the 0.1% budget of the query class, over 1h and 5m.
+	for _, want := range []string{`route="/v1/codebuddy/callers"`, `code="5xx"`, "[1h]", "[5m]", "> 0.0144"} {
+		if !strings.Contains(errRule.Expr, want) {
+			t.Errorf("error expr missing %q:\n%s", want, errRule.Expr)
+		}
+	}
+	if latRule.Alert != LatencyBurnAlert || !strings.Contains(latRule.Expr, `le="1"`) || !strings.Contains(latRule.Expr, "> 0.144") {
+		t.Errorf("latency rule = %+v", latRule)
+	}
+	if last := query[len(query)-1]; last.Labels["burn_window"] != "3d" || last.Labels["severity"] != "ticket" {
+		t.Errorf("last rule labels = %v", last.Labels)
+	}
+
+	data, err := file.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded RuleFile
+	if err := yaml.Unmarshal(data, &decoded); err != nil || len(decoded.Groups) != 2 {
+		t.Errorf("YAML does not round-trip: %v", err)
+	}
+	if strings.Contains(string(data), "/metrics\"") {
+		t.Error("ClassNone route has rules")
+	}
+}
+
+func TestRulesHandler(t *testing.T) {
+	r := gin.New()
+	r.GET("/v1/codebuddy/callers", func(c *gin.Context) {})
+	r.GET("/metrics/slo-rules", RulesHandler(DefaultConfig(), r.Routes))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics/slo-rules", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "trace-slo-query") {
+		t.Errorf("status = %d, body:\n%s", w.Code, w.Body.String())
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		72 * time.Hour:          "3d",
+		90 * time.Minute:        "90m",
+		time.Hour:               "1h",
+		30 * time.Second:        "30s",
+		1500 * time.Millisecond: "1500ms",
+	} {
+		if got := promDuration(d); got != want {
+			t.Errorf("promDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// INPUT CASE DETECTION
+// =============================================================================
+
+// minTableCases is the number of input cases that switches test generation
+// to table-driven mode.
+const minTableCases = 2
+
+// Input case patterns
+var (
+	// caseListPattern matches a bulleted or numbered list item.
+	caseListPattern = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+(.+)$`)
+
+	// caseInputPattern matches list items that describe an input and its
+	// outcome, e.g. "Parse(\"\") -> error" or "input 0 should return 1".
+	caseInputPattern = regexp.MustCompile(`(?i)(->|→|=>|\breturns?\b|\bshould\b|\bexpected?\b|\bgives?\b|\bproduces?\b|\bpanics?\b|\bfails?\b|\binput\b|\bgiven\b)`)
+)
+
+// DetectInputCases extracts the input cases listed in a bug description.
+//
+// Description:
+//
+//	Looks for list items ("- ...", "* ...", "1. ...") that describe an
+//	input and its outcome. A description with at least two such items is
+//	a multi-case bug and gets a table-driven test.
+//
+// Inputs:
+//
+//	description - The bug description
+//
+// Outputs:
+//
+//	[]string - The case descriptions in order, or nil if fewer than two
+//	           were found
+func DetectInputCases(description string) []string {
+	var cases []string
+	for _, line := range strings.Split(description, "\n") {
+		matches := caseListPattern.FindStringSubmatch(line)
+		if len(matches) < 2 {
+			continue
+		}
+		item := strings.TrimSpace(matches[1])
+		if caseInputPattern.MatchString(item) {
+			cases = append(cases, item)
+		}
+	}
+	if len(cases) < minTableCases {
+		return nil
+	}
+	return cases
+}
+
+// supportsTableDriven returns true if table-driven tests can be generated
+// and their failing cases identified for the language.
+func supportsTableDriven(language string) bool {
+	return language == "go" || language == "python"
+}
+
+// isTableDriven returns true if test content is a Go table-driven test or
+// a pytest parametrize block.
+func isTableDriven(content, language string) bool {
+	switch language {
+	case "go":
+		return strings.Contains(content, "t.Run(")
+	case "python":
+		return strings.Contains(content, "@pytest.mark.parametrize")
+	default:
+		return false
+	}
+}
+
+// =============================================================================
+// FAILING CASES
+// =============================================================================
+
+// IsTableDriven returns true if the test runs one case per input.
+func (tc *TestCase) IsTableDriven() bool {
+	return len(tc.Cases) > 0
+}
+
+// FailedCases returns the cases of a table-driven test that failed.
+//
+// Description:
+//
+//	Go reports failed subtests as "TestName/case_name"; pytest reports
+//	failed parameters as "path::test_name[case_id]". A failure of the test
+//	as a whole, such as a build error or a panic before the table runs,
+//	is not a failed case.
+//
+// Inputs:
+//
+//	result - Result of running the test
+//
+// Outputs:
+//
+//	[]string - Names of the failed cases, or nil if none failed
+func (tc *TestCase) FailedCases(result *TestResult) []string {
+	if result == nil {
+		return nil
+	}
+	var failed []string
+	seen := make(map[string]bool)
+	for _, name := range result.FailedTests {
+		var prefix string
+		switch tc.Language {
+		case "go":
+			prefix = tc.Name + "/"
+		case "python":
+			if idx := strings.LastIndex(name, "::"); idx != -1 {
+				name = name[idx+2:]
+			}
+			prefix = tc.Name + "["
+		default:
+			continue
+		}
+		if strings.HasPrefix(name, prefix) && !seen[name] {
+			seen[name] = true
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
+
+// =============================================================================
+// PROMPTS
+// =============================================================================
+
+// writeTableDrivenRequirements adds the table-driven instructions for the
+// detected cases to a test generation prompt.
+func writeTableDrivenRequirements(sb *strings.Builder, language string, cases []string) {
+	sb.WriteString("\nThe bug description lists several input cases:\n")
+	for _, c := range cases {
+		sb.WriteString("  - ")
+		sb.WriteString(c)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nWrite ONE parameterized test with one case per input instead of a single monolithic test:\n")
+	switch language {
+	case "go":
+		sb.WriteString("- Use a Go table-driven test: a `tests := []struct{ name string; ... }` table and `for _, tt := range tests { t.Run(tt.name, ...) }`\n")
+	case "python":
+		sb.WriteString("- Use `@pytest.mark.parametrize` with an `ids=` list naming each case\n")
+	}
+	sb.WriteString("- Give every case a short descriptive name\n")
+	sb.WriteString("- At least one case must FAIL on current code; cases that already work may pass\n")
+}
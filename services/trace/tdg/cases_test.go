@@ -0,0 +1,236 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// INPUT CASE DETECTION TESTS
+// =============================================================================
+
+func TestDetectInputCases(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want []string
+	}{
+		{
+			name: "bulleted cases",
+			desc: "ParseDuration mishandles some inputs:\n- \"1h\" should return 1 hour\n- \"\" should return an error\n- \"-5m\" -> panics",
+			want: []string{`"1h" should return 1 hour`, `"" should return an error`, `"-5m" -> panics`},
+		},
+		{
+			name: "numbered cases",
+			desc: "Cases:\n1. input 0 returns 1\n2) input -1 returns error",
+			want: []string{"input 0 returns 1", "input -1 returns error"},
+		},
+		{
+			name: "single case",
+			desc: "ValidateToken crashes:\n- nil claims should return an error",
+			want: nil,
+		},
+		{
+			name: "list without inputs",
+			desc: "Steps:\n- open the file\n- save it",
+			want: nil,
+		},
+		{
+			name: "prose",
+			desc: "ValidateToken panics on nil claims",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectInputCases(tt.desc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectInputCases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestCase_FailedCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		tc     *TestCase
+		failed []string
+		want   []string
+	}{
+		{
+			name:   "go subtests",
+			tc:     &TestCase{Name: "TestParse", Language: "go", Cases: []string{"a", "b"}},
+			failed: []string{"TestParse/empty_input", "TestParse", "TestParseAll"},
+			want:   []string{"TestParse/empty_input"},
+		},
+		{
+			name:   "go build failure",
+			tc:     &TestCase{Name: "TestParse", Language: "go", Cases: []string{"a", "b"}},
+			failed: []string{},
+			want:   nil,
+		},
+		{
+			name:   "pytest parameters",
+			tc:     &TestCase{Name: "test_parse", Language: "python", Cases: []string{"a", "b"}},
+			failed: []string{"tests/test_parse.py::test_parse[empty]", "tests/test_parse.py::test_parse[empty]", "tests/test_parse.py::test_other"},
+			want:   []string{"test_parse[empty]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tc.FailedCases(&TestResult{FailedTests: tt.failed})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FailedCases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// GENERATION TESTS
+// =============================================================================
+
+func TestTestGenerator_GenerateReproducerTest_TableDriven(t *testing.T) {
+	desc := "Abs is wrong for some inputs:\n- Abs(-1) should return 1\n- Abs(0) should return 0"
+
+	t.Run("table-driven response", func(t *testing.T) {
+		llm := &promptRecordingLLM{resp: "TEST_FILE: abs_test.go\n```go\nfunc TestAbs(t *testing.T) {\n\ttests := []struct{ name string }{{\"negative\"}, {\"zero\"}}\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {})\n\t}\n}\n```"}
+		gen := NewTestGenerator(llm, nil, nil)
+
+		tc, err := gen.GenerateReproducerTest(context.Background(), &Request{
+			BugDescription: desc, ProjectRoot: "/p", Language: "go",
+		})
+		if err != nil {
+			t.Fatalf("GenerateReproducerTest() error = %v", err)
+		}
+		if !tc.IsTableDriven() || len(tc.Cases) != 2 {
+			t.Errorf("Cases = %q, want the 2 described cases", tc.Cases)
+		}
+		if !strings.Contains(llm.prompt, "t.Run(tt.name") || !strings.Contains(llm.prompt, "Abs(-1) should return 1") {
+			t.Errorf("prompt lacks table-driven instructions:\n%s", llm.prompt)
+		}
+	})
+
+	t.Run("monolithic response falls back", func(t *testing.T) {
+		llm := &promptRecordingLLM{resp: "```go\nfunc TestAbs(t *testing.T) {}\n```"}
+		gen := NewTestGenerator(llm, nil, nil)
+
+		tc, err := gen.GenerateReproducerTest(context.Background(), &Request{
+			BugDescription: desc, ProjectRoot: "/p", Language: "go",
+		})
+		if err != nil {
+			t.Fatalf("GenerateReproducerTest() error = %v", err)
+		}
+		if tc.IsTableDriven() {
+			t.Errorf("Cases = %q, want none for a single test", tc.Cases)
+		}
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		llm := &promptRecordingLLM{resp: "```typescript\ntest('abs', () => {})\n```"}
+		gen := NewTestGenerator(llm, nil, nil)
+
+		if _, err := gen.GenerateReproducerTest(context.Background(), &Request{
+			BugDescription: desc, ProjectRoot: "/p", Language: "typescript",
+		}); err != nil {
+			t.Fatalf("GenerateReproducerTest() error = %v", err)
+		}
+		if strings.Contains(llm.prompt, "parameterized") {
+			t.Error("prompt asks for a parameterized test in an unsupported language")
+		}
+	})
+}
+
+func TestTestGenerator_RefineTest_TableDriven(t *testing.T) {
+	llm := &promptRecordingLLM{resp: "```python\n@pytest.mark.parametrize(\"x\", [1, 2], ids=[\"a\", \"b\"])\ndef test_abs(x):\n    pass\n```"}
+	gen := NewTestGenerator(llm, nil, nil)
+	req := &Request{
+		BugDescription: "abs is wrong:\n- abs(-1) should return 1\n- abs(0) should return 0",
+		ProjectRoot:    "/p",
+		Language:       "python",
+	}
+	previous := &TestCase{Name: "test_abs", Language: "python", Content: "...", Cases: []string{"a", "b"}}
+
+	tc, err := gen.RefineTest(context.Background(), req, previous, "2 passed")
+	if err != nil {
+		t.Fatalf("RefineTest() error = %v", err)
+	}
+	if !tc.IsTableDriven() {
+		t.Error("refined parametrize test is not table-driven")
+	}
+	if !strings.Contains(llm.prompt, "No case of your previous table-driven test FAILED") {
+		t.Errorf("refinement prompt lacks case feedback:\n%s", llm.prompt)
+	}
+}
+
+// =============================================================================
+// VERIFY_FAIL TESTS
+// =============================================================================
+
+func TestController_VerifyFail_TableDriven(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   State
+	}{
+		{"case fails", "--- FAIL: TestAbs (0.00s)\n    --- FAIL: TestAbs/negative (0.00s)\nFAIL", StateWriteFix},
+		{"build error", "./abs_test.go:3:1: syntax error\nFAIL\tabs [build failed]", StateWriteTest},
+		{"all pass", "--- PASS: TestAbs (0.00s)\n    --- PASS: TestAbs/negative (0.00s)\nok\tabs", StateWriteTest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs := NewLanguageConfigRegistry()
+			configs.Register(&LanguageConfig{
+				Language:    "go",
+				TestCommand: "printf",
+				TestArgs:    []string{"%s", tt.output},
+			})
+			cfg := DefaultConfig()
+			runner := NewTestRunner(cfg, nil)
+			runner.configs = configs
+
+			c := NewController(cfg, runner, NewFileManager(t.TempDir(), nil), nil, nil)
+			c.ctx = NewContext("s", &Request{BugDescription: "d", ProjectRoot: "/p", Language: "go"})
+			c.ctx.TestRetries = 1
+			c.ctx.ReproducerTest = &TestCase{
+				Name: "TestAbs", FilePath: "abs_test.go", Content: "x", Language: "go",
+				Cases: []string{"negative", "zero"},
+			}
+
+			if err := c.stepVerifyFail(context.Background()); err != nil {
+				t.Fatalf("stepVerifyFail() error = %v", err)
+			}
+			if c.ctx.State != tt.want {
+				t.Errorf("state = %v, want %v", c.ctx.State, tt.want)
+			}
+		})
+	}
+}
+
+// promptRecordingLLM returns a fixed response and records the last prompt.
+type promptRecordingLLM struct {
+	resp   string
+	prompt string
+}
+
+func (m *promptRecordingLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	m.prompt = prompt
+	return m.resp, nil
+}
+
+func (m *promptRecordingLLM) GenerateWithSystem(ctx context.Context, system, prompt string) (string, error) {
+	m.prompt = prompt
+	return m.resp, nil
+}
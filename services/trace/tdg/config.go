@@ -54,6 +54,35 @@ type Config struct {
 	// Default: true
 	EnableLintCheck bool
 
+	// EnableMutationTesting runs the reproducer test against mutants of the
+	// changed functions after the regression suite passes.
+	// Default: true
+	EnableMutationTesting bool
+
+	// MinMutantsKilled is the number of mutants the reproducer test must
+	// kill. Capped at the number of mutants generated.
+	// When not met, TDG returns ErrWeakReproducerTest.
+	// Default: 1
+	MinMutantsKilled int
+
+	// MaxMutants is the maximum number of mutants tested per session.
+	// Default: 10
+	MaxMutants int
+
+	// MinTouchedCoverage is the fraction (0-1) of executable lines touched by
+	// the fix that the test suite must execute before TDG is DONE. Coverage
+	// is collected before and after the fix (go test -coverprofile,
+	// pytest-cov). 0 disables the gate.
+	// When not met, TDG returns ErrInsufficientCoverage.
+	// Default: 0
+	MinTouchedCoverage float64
+
+	// MinTestLinkConfidence is the confidence (0-1) a test mapping link
+	// needs for its test to be run by the regression check. Only used
+	// when the controller has a test mapping (Controller.SetTestMapping).
+	// Default: 0.5 (direct calls, naming matches and two-hop calls)
+	MinTestLinkConfidence float64
+
 	// WorkingDir overrides the working directory for test execution.
 	// If empty, uses the project root from the request.
 	WorkingDir string
@@ -74,6 +103,12 @@ func DefaultConfig() *Config {
 		TotalTimeout:       10 * time.Minute,
 		MaxOutputBytes:     64 * 1024, // 64KB
 		EnableLintCheck:    true,
+
+		EnableMutationTesting: true,
+		MinMutantsKilled:      1,
+		MaxMutants:            10,
+
+		MinTestLinkConfidence: 0.5,
 	}
 }
 
@@ -104,6 +139,24 @@ func (c *Config) Validate() error {
 	if c.MaxOutputBytes < 1024 {
 		c.MaxOutputBytes = 1024
 	}
+	if c.MinMutantsKilled < 0 {
+		c.MinMutantsKilled = 0
+	}
+	if c.MaxMutants < 1 {
+		c.MaxMutants = 1
+	}
+	if c.MinTouchedCoverage < 0 {
+		c.MinTouchedCoverage = 0
+	}
+	if c.MinTouchedCoverage > 1 {
+		c.MinTouchedCoverage = 1
+	}
+	if c.MinTestLinkConfidence < 0 {
+		c.MinTestLinkConfidence = 0
+	}
+	if c.MinTestLinkConfidence > 1 {
+		c.MinTestLinkConfidence = 1
+	}
 	return nil
 }
 
@@ -170,6 +223,43 @@ func WithLintCheck(enabled bool) Option {
 	}
 }
 
+// WithMutationTesting enables or disables mutation testing.
+func WithMutationTesting(enabled bool) Option {
+	return func(c *Config) {
+		c.EnableMutationTesting = enabled
+	}
+}
+
+// WithMinMutantsKilled sets the number of mutants the reproducer must kill.
+func WithMinMutantsKilled(n int) Option {
+	return func(c *Config) {
+		c.MinMutantsKilled = n
+	}
+}
+
+// WithMaxMutants sets the maximum number of mutants tested.
+func WithMaxMutants(n int) Option {
+	return func(c *Config) {
+		c.MaxMutants = n
+	}
+}
+
+// WithMinTouchedCoverage sets the required coverage of touched lines
+// (0-1, 0 disables the gate).
+func WithMinTouchedCoverage(f float64) Option {
+	return func(c *Config) {
+		c.MinTouchedCoverage = f
+	}
+}
+
+// WithMinTestLinkConfidence sets the test mapping confidence a test
+// needs to be run by the regression check (0-1).
+func WithMinTestLinkConfidence(f float64) Option {
+	return func(c *Config) {
+		c.MinTestLinkConfidence = f
+	}
+}
+
 // WithWorkingDir sets the working directory for test execution.
 func WithWorkingDir(dir string) Option {
 	return func(c *Config) {
@@ -17,6 +17,9 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 )
 
 // =============================================================================
@@ -32,6 +35,8 @@ type Controller struct {
 	runner    *TestRunner
 	files     *FileManager
 	generator *TestGenerator
+	graph     *graph.Graph
+	tests     *analysis.TestMapping
 	ctx       *Context
 	logger    *slog.Logger
 	running   bool
@@ -76,6 +81,8 @@ func NewController(cfg *Config, runner *TestRunner, files *FileManager, gen *Tes
 //	  3. Generate fix
 //	  4. Verify test passes
 //	  5. Check for regressions
+//	  6. Check the test kills mutants of the fix
+//	  7. Optionally check coverage of the touched lines
 //
 //	Handles retries, timeouts, and rollback on failure.
 //
@@ -291,10 +298,21 @@ func (c *Controller) stepVerifyFail(ctx context.Context) error {
 	c.ctx.Metrics.TotalTestDuration += result.Duration
 	c.ctx.LastTestOutput = result.Output
 
-	if result.Passed {
-		// Test passed when it should fail - bad reproducer
-		c.logger.Warn("Test passed unexpectedly",
+	// A table-driven test reproduces the bug only if one of its cases
+	// fails; a build error or a panic outside the cases does not count.
+	reproduced := !result.Passed
+	var failedCases []string
+	if reproduced && c.ctx.ReproducerTest.IsTableDriven() {
+		failedCases = c.ctx.ReproducerTest.FailedCases(result)
+		reproduced = len(failedCases) > 0
+	}
+
+	if !reproduced {
+		// Test passed (or no case failed) when it should fail - bad reproducer
+		c.logger.Warn("Test did not reproduce the bug",
 			slog.String("test_name", c.ctx.ReproducerTest.Name),
+			slog.Bool("passed", result.Passed),
+			slog.Int("cases", len(c.ctx.ReproducerTest.Cases)),
 			slog.Int("attempt", c.ctx.TestRetries),
 		)
 
@@ -313,8 +331,13 @@ func (c *Controller) stepVerifyFail(ctx context.Context) error {
 	// Test failed as expected - good reproducer!
 	c.logger.Info("Test correctly fails (reproduces bug)",
 		slog.String("test_name", c.ctx.ReproducerTest.Name),
+		slog.Any("failed_cases", failedCases),
 	)
 
+	if c.config.MinTouchedCoverage > 0 && c.ctx.CoverageBaseline == nil {
+		c.collectCoverageBaseline(ctx)
+	}
+
 	c.transition(StateWriteFix)
 	return nil
 }
@@ -421,8 +444,7 @@ func (c *Controller) stepRegression(ctx context.Context) error {
 		slog.String("package", c.ctx.Request.ProjectRoot),
 	)
 
-	c.ctx.Metrics.TestsRun++
-	result, err := c.runner.RunSuite(ctx, c.ctx.Request.Language, ".")
+	result, err := c.runRegressionTests(ctx)
 	if err != nil && err != ErrTestTimeout && err != ErrSuiteTimeout {
 		c.ctx.LastError = err
 		c.transition(StateFailed)
@@ -469,6 +491,51 @@ func (c *Controller) stepRegression(ctx context.Context) error {
 		slog.Int("total_tests", result.TotalTests),
 	)
 
+	if c.config.EnableMutationTesting {
+		report, err := c.runMutationTesting(ctx)
+		if err != nil {
+			c.ctx.LastError = err
+			c.transition(StateFailed)
+			_ = c.files.Rollback()
+			return err
+		}
+		c.ctx.Mutation = report
+
+		if !report.Passed() {
+			c.logger.Warn("Reproducer test is too weak",
+				slog.Int("killed", report.Killed),
+				slog.Int("required", report.Required),
+				slog.Int("survivors", len(report.Survivors)),
+			)
+			c.ctx.LastError = ErrWeakReproducerTest
+			c.transition(StateFailed)
+			_ = c.files.Rollback()
+			return ErrWeakReproducerTest
+		}
+	}
+
+	if c.config.MinTouchedCoverage > 0 {
+		report, err := c.checkCoverage(ctx)
+		if err != nil {
+			c.ctx.LastError = err
+			c.transition(StateFailed)
+			_ = c.files.Rollback()
+			return err
+		}
+		c.ctx.Coverage = report
+
+		if !report.Passed() {
+			c.logger.Warn("Touched lines are not covered enough",
+				slog.Float64("coverage", report.TouchedCoverage),
+				slog.Float64("threshold", report.Threshold),
+			)
+			c.ctx.LastError = ErrInsufficientCoverage
+			c.transition(StateFailed)
+			_ = c.files.Rollback()
+			return ErrInsufficientCoverage
+		}
+	}
+
 	c.transition(StateDone)
 	return nil
 }
@@ -483,6 +550,8 @@ func (c *Controller) buildResult() *Result {
 		State:          c.ctx.State,
 		ReproducerTest: c.ctx.ReproducerTest,
 		AppliedPatches: c.ctx.AppliedPatches,
+		Mutation:       c.ctx.Mutation,
+		Coverage:       c.ctx.Coverage,
 		Duration:       c.ctx.Elapsed(),
 		Metrics:        c.ctx.Metrics,
 	}
@@ -385,20 +385,20 @@ func TestLanguageConfigRegistry(t *testing.T) {
 		registry := NewLanguageConfigRegistry()
 
 		cfg := &LanguageConfig{
-			Language:    "rust",
-			TestCommand: "cargo",
-			TestArgs:    []string{"test", "--", "{name}"},
-			SuiteArgs:   []string{"test"},
+			Language:    "ruby",
+			TestCommand: "rspec",
+			TestArgs:    []string{"-e", "{name}", "{file}"},
+			SuiteArgs:   []string{"{package}"},
 		}
 
 		registry.Register(cfg)
 
-		retrieved, ok := registry.Get("rust")
+		retrieved, ok := registry.Get("ruby")
 		if !ok {
-			t.Fatal("expected rust config to exist after registration")
+			t.Fatal("expected ruby config to exist after registration")
 		}
-		if retrieved.TestCommand != "cargo" {
-			t.Errorf("TestCommand = %q, want cargo", retrieved.TestCommand)
+		if retrieved.TestCommand != "rspec" {
+			t.Errorf("TestCommand = %q, want rspec", retrieved.TestCommand)
 		}
 	})
 }
@@ -0,0 +1,331 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// COVERAGE TYPES
+// =============================================================================
+
+// Coverage profile formats, see LanguageConfig.CoverageFormat.
+const (
+	// CoverageFormatGo is the `go test -coverprofile` text format.
+	CoverageFormatGo = "gocover"
+
+	// CoverageFormatCoveragePy is the coverage.py JSON report written by
+	// `pytest --cov-report=json:<file>`.
+	CoverageFormatCoveragePy = "coveragepy-json"
+)
+
+// CoverageProfile is line coverage per file.
+//
+// Maps the file path as reported by the coverage tool to the executable
+// lines of the file (1-indexed) and whether each was executed. Lines that
+// are not in the map are not executable.
+type CoverageProfile map[string]map[int]bool
+
+// lookup returns the coverage of a project-relative file path. Coverage
+// tools report import paths (Go) or paths relative to the working
+// directory (coverage.py), so the path is matched by suffix.
+func (p CoverageProfile) lookup(filePath string) (map[int]bool, bool) {
+	filePath = filepath.ToSlash(filepath.Clean(filePath))
+	if lines, ok := p[filePath]; ok {
+		return lines, true
+	}
+	for name, lines := range p {
+		if strings.HasSuffix(filepath.ToSlash(name), "/"+filePath) {
+			return lines, true
+		}
+	}
+	return nil, false
+}
+
+// FileCoverage is the coverage of one file touched by the fix.
+type FileCoverage struct {
+	// FilePath is the patched file.
+	FilePath string `json:"file_path"`
+
+	// Before is the file's line coverage before the fix (0-1). Nil if no
+	// baseline was collected or the file was not covered by it.
+	Before *float64 `json:"before,omitempty"`
+
+	// After is the file's line coverage with the fix (0-1).
+	After float64 `json:"after"`
+
+	// TouchedLines is the number of executable lines the fix touched.
+	TouchedLines int `json:"touched_lines"`
+
+	// CoveredTouchedLines is the number of touched lines the suite executed.
+	CoveredTouchedLines int `json:"covered_touched_lines"`
+}
+
+// CoverageReport is the outcome of the coverage gate.
+type CoverageReport struct {
+	// Files is the coverage of each patched file.
+	Files []*FileCoverage `json:"files"`
+
+	// TouchedLines is the number of executable lines touched by the fix.
+	TouchedLines int `json:"touched_lines"`
+
+	// CoveredTouchedLines is the number of touched lines executed.
+	CoveredTouchedLines int `json:"covered_touched_lines"`
+
+	// TouchedCoverage is CoveredTouchedLines / TouchedLines, or 1 if the
+	// fix touched no executable lines.
+	TouchedCoverage float64 `json:"touched_coverage"`
+
+	// Threshold is the required TouchedCoverage.
+	Threshold float64 `json:"threshold"`
+}
+
+// Passed returns true if the touched lines are covered enough.
+func (r *CoverageReport) Passed() bool {
+	return r.TouchedCoverage >= r.Threshold
+}
+
+// =============================================================================
+// COVERAGE PARSERS
+// =============================================================================
+
+// coverageParsers maps coverage formats to their parsers.
+var coverageParsers = map[string]func([]byte) (CoverageProfile, error){
+	CoverageFormatGo:         parseGoCoverProfile,
+	CoverageFormatCoveragePy: parseCoveragePyJSON,
+}
+
+// ParseCoverage parses a coverage profile.
+//
+// Inputs:
+//
+//	format - The profile format (CoverageFormatGo, CoverageFormatCoveragePy)
+//	data - The profile content
+//
+// Outputs:
+//
+//	CoverageProfile - Line coverage per file
+//	error - Non-nil if the format is unknown or the profile is malformed
+func ParseCoverage(format string, data []byte) (CoverageProfile, error) {
+	parser, ok := coverageParsers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrCoverageUnavailable, format)
+	}
+	return parser(data)
+}
+
+// parseGoCoverProfile parses `go test -coverprofile` output.
+//
+// Each block line is "file:startLine.startCol,endLine.endCol numStmts count".
+// A line is covered if any block spanning it has a non-zero count; blocks
+// are repeated per test binary with -coverpkg, so counts are merged.
+func parseGoCoverProfile(data []byte) (CoverageProfile, error) {
+	profile := make(CoverageProfile)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		colon := strings.LastIndex(line, ":")
+		if len(fields) != 3 || colon < 0 {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrCoverageUnavailable, i+1, line)
+		}
+		var startLine, startCol, endLine, endCol int
+		if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d", &startLine, &startCol, &endLine, &endCol); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrCoverageUnavailable, i+1, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrCoverageUnavailable, i+1, err)
+		}
+
+		file := line[:colon]
+		lines := profile[file]
+		if lines == nil {
+			lines = make(map[int]bool)
+			profile[file] = lines
+		}
+		for l := startLine; l <= endLine; l++ {
+			lines[l] = lines[l] || count > 0
+		}
+	}
+	return profile, nil
+}
+
+// coveragePyReport is the part of the coverage.py JSON report TDG reads.
+type coveragePyReport struct {
+	Files map[string]struct {
+		ExecutedLines []int `json:"executed_lines"`
+		MissingLines  []int `json:"missing_lines"`
+	} `json:"files"`
+}
+
+// parseCoveragePyJSON parses a coverage.py JSON report.
+func parseCoveragePyJSON(data []byte) (CoverageProfile, error) {
+	var report coveragePyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCoverageUnavailable, err)
+	}
+	profile := make(CoverageProfile, len(report.Files))
+	for file, cov := range report.Files {
+		lines := make(map[int]bool, len(cov.ExecutedLines)+len(cov.MissingLines))
+		for _, l := range cov.MissingLines {
+			lines[l] = false
+		}
+		for _, l := range cov.ExecutedLines {
+			lines[l] = true
+		}
+		profile[file] = lines
+	}
+	return profile, nil
+}
+
+// =============================================================================
+// COVERAGE GATE
+// =============================================================================
+
+// fileLineCoverage returns the fraction of executable lines executed.
+func fileLineCoverage(lines map[int]bool) float64 {
+	if len(lines) == 0 {
+		return 0
+	}
+	covered := 0
+	for _, hit := range lines {
+		if hit {
+			covered++
+		}
+	}
+	return float64(covered) / float64(len(lines))
+}
+
+// BuildCoverageReport measures how well the suite covers the lines touched
+// by the applied patches.
+//
+// Description:
+//
+//	The touched lines of a patch are the lines of its new content that
+//	differ from the old content (all lines for a new file). Only touched
+//	lines the coverage tool reports as executable count. Files missing
+//	from the profile count as not covered at all.
+//
+// Inputs:
+//
+//	patches - The applied patches
+//	before - Coverage before the fix (may be nil)
+//	after - Coverage with the fix
+//	threshold - Required fraction of touched lines covered
+//
+// Outputs:
+//
+//	*CoverageReport - Per-file and total touched-line coverage
+func BuildCoverageReport(patches []*Patch, before, after CoverageProfile, threshold float64) *CoverageReport {
+	report := &CoverageReport{Threshold: threshold}
+
+	for _, patch := range patches {
+		newLines := strings.Split(patch.NewContent, "\n")
+		start, end := 0, len(newLines)
+		if patch.OldContent != "" {
+			start, end = changedLineRange(strings.Split(patch.OldContent, "\n"), newLines)
+		}
+
+		fc := &FileCoverage{FilePath: patch.FilePath}
+		if lines, ok := before.lookup(patch.FilePath); ok {
+			b := fileLineCoverage(lines)
+			fc.Before = &b
+		}
+
+		lines, ok := after.lookup(patch.FilePath)
+		if ok {
+			fc.After = fileLineCoverage(lines)
+		}
+		for i := start; i < end; i++ {
+			if !ok {
+				// Not in the profile: nothing in the file was compiled
+				// or executed by the suite.
+				if strings.TrimSpace(newLines[i]) != "" {
+					fc.TouchedLines++
+				}
+				continue
+			}
+			hit, executable := lines[i+1]
+			if !executable {
+				continue
+			}
+			fc.TouchedLines++
+			if hit {
+				fc.CoveredTouchedLines++
+			}
+		}
+
+		report.Files = append(report.Files, fc)
+		report.TouchedLines += fc.TouchedLines
+		report.CoveredTouchedLines += fc.CoveredTouchedLines
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].FilePath < report.Files[j].FilePath
+	})
+	report.TouchedCoverage = 1
+	if report.TouchedLines > 0 {
+		report.TouchedCoverage = float64(report.CoveredTouchedLines) / float64(report.TouchedLines)
+	}
+	return report
+}
+
+// collectCoverageBaseline records coverage of the unfixed code. Failures
+// are logged and leave the baseline empty; the gate only needs the
+// coverage after the fix.
+func (c *Controller) collectCoverageBaseline(ctx context.Context) {
+	profile, err := c.runCoverage(ctx)
+	if err != nil {
+		c.logger.Warn("Baseline coverage unavailable", slog.String("error", err.Error()))
+		return
+	}
+	c.ctx.CoverageBaseline = profile
+}
+
+// checkCoverage collects coverage with the fix applied and builds the
+// coverage report for the applied patches.
+func (c *Controller) checkCoverage(ctx context.Context) (*CoverageReport, error) {
+	profile, err := c.runCoverage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := BuildCoverageReport(c.ctx.AppliedPatches, c.ctx.CoverageBaseline, profile, c.config.MinTouchedCoverage)
+
+	c.logger.Info("Coverage of touched lines",
+		slog.Int("touched_lines", report.TouchedLines),
+		slog.Int("covered", report.CoveredTouchedLines),
+		slog.Float64("coverage", report.TouchedCoverage),
+		slog.Float64("threshold", report.Threshold),
+	)
+	return report, nil
+}
+
+// runCoverage runs the suite with coverage and updates the metrics.
+func (c *Controller) runCoverage(ctx context.Context) (CoverageProfile, error) {
+	c.ctx.Metrics.TestsRun++
+	profile, result, err := c.runner.RunCoverage(ctx, c.ctx.Request.Language, ".")
+	if result != nil {
+		c.ctx.Metrics.TotalTestDuration += result.Duration
+	}
+	return profile, err
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// =============================================================================
+// COVERAGE PARSER TESTS
+// =============================================================================
+
+func TestParseCoverage(t *testing.T) {
+	t.Run("go profile", func(t *testing.T) {
+		data := "mode: set\n" +
+			"example.com/abs/abs.go:5.2,6.10 2 1\n" +
+			"example.com/abs/abs.go:6.10,8.3 1 0\n" +
+			"example.com/abs/abs.go:6.10,8.3 1 1\n" +
+			"example.com/abs/other.go:3.1,3.5 1 0\n"
+		profile, err := ParseCoverage(CoverageFormatGo, []byte(data))
+		if err != nil {
+			t.Fatalf("ParseCoverage() error = %v", err)
+		}
+		want := CoverageProfile{
+			"example.com/abs/abs.go":   {5: true, 6: true, 7: true, 8: true},
+			"example.com/abs/other.go": {3: false},
+		}
+		if !reflect.DeepEqual(profile, want) {
+			t.Errorf("profile = %v, want %v", profile, want)
+		}
+	})
+
+	t.Run("coverage.py json", func(t *testing.T) {
+		data := `{"files": {"pkg/abs.py": {"executed_lines": [1, 3], "missing_lines": [4]}}}`
+		profile, err := ParseCoverage(CoverageFormatCoveragePy, []byte(data))
+		if err != nil {
+			t.Fatalf("ParseCoverage() error = %v", err)
+		}
+		want := CoverageProfile{"pkg/abs.py": {1: true, 3: true, 4: false}}
+		if !reflect.DeepEqual(profile, want) {
+			t.Errorf("profile = %v, want %v", profile, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct{ format, data string }{
+			{CoverageFormatGo, "mode: set\nabs.go:bad 1 1\n"},
+			{CoverageFormatCoveragePy, "{"},
+			{"lcov", ""},
+		} {
+			if _, err := ParseCoverage(tc.format, []byte(tc.data)); !errors.Is(err, ErrCoverageUnavailable) {
+				t.Errorf("ParseCoverage(%s, %q) error = %v, want ErrCoverageUnavailable", tc.format, tc.data, err)
+			}
+		}
+	})
+}
+
+func TestBuildCoverageReport(t *testing.T) {
+	patch := &Patch{FilePath: "abs.go", OldContent: mutationOldGo, NewContent: mutationNewGo}
+	before := CoverageProfile{"example.com/abs/abs.go": {5: true, 6: false}}
+	after := CoverageProfile{"example.com/abs/abs.go": {5: true, 6: false, 8: true, 12: true}}
+
+	report := BuildCoverageReport([]*Patch{patch}, before, after, 0.8)
+
+	// Lines 5-7 are touched; 7 ("}") is not executable.
+	if report.TouchedLines != 2 || report.CoveredTouchedLines != 1 || report.TouchedCoverage != 0.5 {
+		t.Errorf("report = %+v, want 1 of 2 touched lines covered", report)
+	}
+	if report.Passed() {
+		t.Error("report passed below threshold")
+	}
+	fc := report.Files[0]
+	if fc.Before == nil || *fc.Before != 0.5 || fc.After != 0.75 {
+		t.Errorf("file coverage = %+v, want before 0.5 and after 0.75", fc)
+	}
+
+	t.Run("file missing from profile", func(t *testing.T) {
+		report := BuildCoverageReport([]*Patch{{FilePath: "new.go", NewContent: "package x\n\nfunc F() {}\n"}}, nil, after, 0.5)
+		if report.TouchedLines != 2 || report.TouchedCoverage != 0 || report.Files[0].Before != nil {
+			t.Errorf("report = %+v, want 2 uncovered touched lines", report)
+		}
+	})
+
+	t.Run("no executable touched lines", func(t *testing.T) {
+		report := BuildCoverageReport([]*Patch{patch}, nil, CoverageProfile{"abs.go": {12: true}}, 1)
+		if report.TouchedCoverage != 1 || !report.Passed() {
+			t.Errorf("report = %+v, want full coverage", report)
+		}
+	})
+}
+
+// =============================================================================
+// COVERAGE GATE TESTS
+// =============================================================================
+
+func TestController_Regression_CoverageGate(t *testing.T) {
+	profile := "mode: set\\nexample.com/abs/abs.go:5.2,5.12 1 1\\nexample.com/abs/abs.go:6.3,6.15 1 0\\n"
+	tests := []struct {
+		name      string
+		coverage  string // shell command run for coverage
+		threshold float64
+		wantState State
+		wantErr   error
+	}{
+		{"covered enough", "printf '" + profile + "' > {coverage}", 0.5, StateDone, nil},
+		{"below threshold", "printf '" + profile + "' > {coverage}", 0.8, StateFailed, ErrInsufficientCoverage},
+		{"no profile", "true", 0.5, StateFailed, ErrCoverageUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "abs.go"), []byte(mutationOldGo), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			configs := NewLanguageConfigRegistry()
+			configs.Register(&LanguageConfig{
+				Language:       "go",
+				TestCommand:    "sh",
+				SuiteArgs:      []string{"-c", "true"},
+				CoverageArgs:   []string{"-c", tt.coverage},
+				CoverageFormat: CoverageFormatGo,
+			})
+			cfg := NewConfig(WithMutationTesting(false), WithMinTouchedCoverage(tt.threshold))
+			runner := NewTestRunner(cfg, nil)
+			runner.configs = configs
+			runner.SetWorkingDir(dir)
+
+			files := NewFileManager(dir, nil)
+			patch := &Patch{FilePath: "abs.go", NewContent: mutationNewGo}
+			if err := files.ApplyPatch(patch); err != nil {
+				t.Fatal(err)
+			}
+
+			c := NewController(cfg, runner, files, nil, nil)
+			c.ctx = NewContext("s", &Request{BugDescription: "d", ProjectRoot: dir, Language: "go"})
+			c.ctx.ReproducerTest = &TestCase{Name: "TestAbs", FilePath: "abs_test.go", Content: "x", Language: "go"}
+			c.ctx.AppliedPatches = []*Patch{patch}
+
+			err := c.stepRegression(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("stepRegression() error = %v, want %v", err, tt.wantErr)
+			}
+			if c.ctx.State != tt.wantState {
+				t.Errorf("state = %v, want %v", c.ctx.State, tt.wantState)
+			}
+			if tt.wantErr != ErrCoverageUnavailable {
+				if report := c.buildResult().Coverage; report == nil || report.TouchedCoverage != 0.5 {
+					t.Errorf("coverage report = %+v, want touched coverage 0.5", report)
+				}
+			}
+		})
+	}
+}
+
+func TestTestRunner_RunCoverage_NotConfigured(t *testing.T) {
+	runner := NewTestRunner(DefaultConfig(), nil)
+	if _, _, err := runner.RunCoverage(context.Background(), "typescript", "."); !errors.Is(err, ErrCoverageUnavailable) {
+		t.Errorf("RunCoverage() error = %v, want ErrCoverageUnavailable", err)
+	}
+}
@@ -17,7 +17,7 @@
 //  3. VERIFY_FAIL - System runs test, must fail (proves bug exists)
 //  4. WRITE_FIX - Agent implements the fix
 //  5. VERIFY_PASS - System runs test, must pass (proves fix works)
-//  6. REGRESSION - System runs full suite (proves no breakage)
+//  6. REGRESSION - System runs the affected tests (proves no breakage)
 //  7. DONE - Fix is proven correct
 //
 // The TDG controller operates as an internal state machine, separate from
@@ -30,6 +30,55 @@
 //   - Go: go test -v -run {name}
 //   - Python: pytest -v -k {name}
 //   - TypeScript: npx jest --testNamePattern {name}
+//   - Rust: cargo test {name} (reproducers in tests/*.rs)
+//   - Java: mvn test -Dtest={name}, or gradle test --tests {name} when the
+//     project root has a build.gradle or gradlew
+//
+// Each language also provides a reproducer scaffold that is included in
+// the test generation prompt.
+//
+// # Table-Driven Tests
+//
+// When the bug description lists several input cases (bulleted or
+// numbered items such as "- Parse(\"\") should return an error"), Go and
+// Python reproducers are generated as one parameterized test: a Go
+// table-driven test with t.Run subtests, or a pytest parametrize block.
+// VERIFY_FAIL then requires at least one case to fail; a build error or a
+// panic outside the cases does not prove the bug exists.
+//
+// # Regression Test Selection
+//
+// Given the code graph and its test mapping (Controller.SetTestMapping),
+// the regression check runs only the existing tests linked to the
+// functions the fix changed, with at least Config.MinTestLinkConfidence,
+// in one go test -run or pytest -k invocation per package or file. It
+// runs the full suite when there is no mapping, no linked test, or the
+// language cannot select tests by name.
+//
+// # Mutation Testing
+//
+// After the regression suite passes, the changed functions are mutated
+// (condition negation, operator flips such as == to != or && to ||) and
+// the reproducer test is run against each mutant. The test must kill at
+// least Config.MinMutantsKilled of them; otherwise TDG fails with
+// ErrWeakReproducerTest. Surviving mutants are reported in Result.Mutation.
+//
+// # Coverage Gating
+//
+// With Config.MinTouchedCoverage set, coverage is collected when the
+// reproducer is confirmed to fail and again after the regression suite
+// passes (go test -coverprofile, pytest --cov). TDG only reaches DONE if
+// the suite executes at least that fraction of the executable lines the
+// fix touched; otherwise it fails with ErrInsufficientCoverage. The
+// per-file before/after coverage is reported in Result.Coverage.
+//
+// # Parallel Sessions
+//
+// SessionPool runs several TDG sessions on one repository at once. Each
+// session gets its own git worktree and Controller, at most
+// PoolConfig.MaxConcurrent run concurrently, and successful fixes are
+// merged into the user's tree one at a time. A fix that conflicts with an
+// earlier merge is reported with ErrMergeConflict and left unmerged.
 //
 // # Iteration Limits
 //
@@ -43,8 +92,9 @@
 // # Thread Safety
 //
 // Controller instances are NOT safe for concurrent use. Each TDG session
-// should use its own Controller instance. The LanguageConfigRegistry is
-// safe for concurrent reads after initialization.
+// should use its own Controller instance; SessionPool does this for
+// parallel sessions. The LanguageConfigRegistry is safe for concurrent
+// reads after initialization.
 //
 // # Example Usage
 //
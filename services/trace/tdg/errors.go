@@ -64,6 +64,18 @@ var (
 	// was applied, meaning the fix doesn't work.
 	ErrTestFailedUnexpectedly = errors.New("test failed after fix applied")
 
+	// ErrWeakReproducerTest indicates the reproducer test killed fewer
+	// mutants of the fix than required, so it barely constrains the fix.
+	ErrWeakReproducerTest = errors.New("reproducer test killed too few mutants")
+
+	// ErrInsufficientCoverage indicates the test suite does not execute
+	// enough of the lines touched by the fix.
+	ErrInsufficientCoverage = errors.New("coverage of touched lines below threshold")
+
+	// ErrCoverageUnavailable indicates coverage could not be collected,
+	// e.g. because the language has no coverage configuration.
+	ErrCoverageUnavailable = errors.New("coverage unavailable")
+
 	// ErrInvalidTestCase indicates the test case is malformed or incomplete.
 	ErrInvalidTestCase = errors.New("invalid test case")
 
@@ -87,6 +99,18 @@ var (
 
 	// ErrNotRunning indicates the controller is not running.
 	ErrNotRunning = errors.New("TDG controller not running")
+
+	// ErrPoolClosed indicates a session was submitted to a closed pool.
+	ErrPoolClosed = errors.New("TDG session pool closed")
+
+	// ErrOutsideRepository indicates a pooled request's project root is
+	// not inside the pool's repository.
+	ErrOutsideRepository = errors.New("project root outside pool repository")
+
+	// ErrMergeConflict indicates a pooled session's fix no longer applies
+	// to the user's tree, usually because an earlier merge changed the
+	// same lines.
+	ErrMergeConflict = errors.New("fix does not apply to the working tree")
 )
 
 // =============================================================================
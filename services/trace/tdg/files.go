@@ -11,11 +11,17 @@
 package tdg
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 // =============================================================================
@@ -29,12 +35,22 @@ import (
 // Each TDG session should have its own FileManager.
 type FileManager struct {
 	projectRoot  string
-	backups      map[string][]byte   // filepath → original content
-	createdFiles map[string]struct{} // files created by TDG
+	backups      map[string]fileBackup // filepath → original content
+	createdFiles map[string]struct{}   // files created by TDG
+	store        *cas.Store            // optional artifact store
+	session      string                // reference namespace in store
 	mu           sync.Mutex
 	logger       *slog.Logger
 }
 
+// fileBackup is the original content of a file TDG modified. With an
+// artifact store the content lives in the store and only its digest is
+// held in memory.
+type fileBackup struct {
+	content []byte
+	digest  cas.Digest
+}
+
 // NewFileManager creates a new file manager.
 //
 // Inputs:
@@ -51,12 +67,43 @@ func NewFileManager(projectRoot string, logger *slog.Logger) *FileManager {
 	}
 	return &FileManager{
 		projectRoot:  projectRoot,
-		backups:      make(map[string][]byte),
+		backups:      make(map[string]fileBackup),
 		createdFiles: make(map[string]struct{}),
 		logger:       logger,
 	}
 }
 
+// NewFileManagerWithStore creates a file manager that keeps backups and
+// written artifacts in a content-addressable store.
+//
+// Description:
+//
+//	Original file contents are stored under "tdg/<session>/backups/<path>"
+//	instead of in memory, and every test file and patch written is kept
+//	under "tdg/<session>/artifacts/<path>". Identical content across
+//	sessions is stored once. Call Release when the session's artifacts
+//	are no longer needed.
+//
+// Inputs:
+//
+//	projectRoot - Root directory of the project
+//	store - Artifact store. If nil, behaves like NewFileManager.
+//	logger - Logger for structured logging
+//
+// Outputs:
+//
+//	*FileManager - Configured file manager
+func NewFileManagerWithStore(projectRoot string, store *cas.Store, logger *slog.Logger) *FileManager {
+	m := NewFileManager(projectRoot, logger)
+	if store != nil {
+		suffix := make([]byte, 4)
+		_, _ = rand.Read(suffix)
+		m.store = store
+		m.session = time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+	}
+	return m
+}
+
 // WriteTest writes a test file to disk.
 //
 // Description:
@@ -106,7 +153,9 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 
 	// Check if file exists (for backup)
 	if existing, err := os.ReadFile(filePath); err == nil {
-		m.backups[filePath] = existing
+		if err := m.saveBackup(filePath, existing); err != nil {
+			return fmt.Errorf("%w: backup: %v", ErrTestWriteFailed, err)
+		}
 		m.logger.Debug("Backed up existing file",
 			slog.String("path", filePath),
 			slog.Int("size", len(existing)),
@@ -138,6 +187,8 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 		m.createdFiles[filePath] = struct{}{}
 	}
 
+	m.saveArtifact(filePath, tc.Content)
+
 	m.logger.Info("Wrote test file",
 		slog.String("path", filePath),
 		slog.Int("size", len(tc.Content)),
@@ -194,7 +245,9 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 
 	// Store backup
 	if existing != nil {
-		m.backups[filePath] = existing
+		if err := m.saveBackup(filePath, existing); err != nil {
+			return fmt.Errorf("%w: backup: %v", ErrPatchApplyFailed, err)
+		}
 		if patch.OldContent == "" {
 			patch.OldContent = string(existing)
 		}
@@ -234,6 +287,7 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	}
 
 	patch.Applied = true
+	m.saveArtifact(filePath, patch.NewContent)
 
 	m.logger.Info("Applied patch",
 		slog.String("path", filePath),
@@ -244,6 +298,30 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	return nil
 }
 
+// overwrite replaces the content of a file TDG already patched, without
+// touching its backup. Used to swap mutants in and out during mutation
+// testing.
+//
+// Thread Safety: Uses internal locking.
+func (m *FileManager) overwrite(filePath, content string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(m.projectRoot, filePath)
+	}
+
+	tempPath := filePath + ".tdg.tmp"
+	if err := os.WriteFile(tempPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("%w: write temp: %v", ErrPatchApplyFailed, err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("%w: rename: %v", ErrPatchApplyFailed, err)
+	}
+	return nil
+}
+
 // Rollback restores all backed-up files to their original state.
 //
 // Description:
@@ -269,8 +347,12 @@ func (m *FileManager) Rollback() error {
 	var lastErr error
 
 	// Restore backed-up files
-	for filePath, content := range m.backups {
-		if err := os.WriteFile(filePath, content, 0644); err != nil {
+	for filePath, backup := range m.backups {
+		content, err := m.loadBackup(backup)
+		if err == nil {
+			err = os.WriteFile(filePath, content, 0644)
+		}
+		if err != nil {
 			m.logger.Error("Failed to restore file",
 				slog.String("path", filePath),
 				slog.String("error", err.Error()),
@@ -304,7 +386,8 @@ func (m *FileManager) Rollback() error {
 	}
 
 	// Clear tracking
-	m.backups = make(map[string][]byte)
+	m.releaseBackups()
+	m.backups = make(map[string]fileBackup)
 	m.createdFiles = make(map[string]struct{})
 
 	if lastErr != nil {
@@ -360,6 +443,99 @@ func (m *FileManager) Cleanup() error {
 	return lastErr
 }
 
+// Release removes this session's backups and artifacts from the store.
+//
+// Description:
+//
+//	Content still referenced by other sessions or backups is kept. Does
+//	nothing without a store. The file manager must not be used for
+//	rollback afterwards.
+//
+// Outputs:
+//
+//	error - Non-nil if a reference could not be removed
+//
+// Thread Safety: Uses internal locking.
+func (m *FileManager) Release() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store == nil {
+		return nil
+	}
+	refs, err := m.store.Refs(cas.RefName("tdg", m.session) + "/")
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, ref := range refs {
+		if err := m.store.Unlink(ref.Name); err != nil {
+			lastErr = err
+		}
+	}
+	m.backups = make(map[string]fileBackup)
+	return lastErr
+}
+
+// saveBackup records the original content of filePath. Callers must hold m.mu.
+func (m *FileManager) saveBackup(filePath string, content []byte) error {
+	if m.store == nil {
+		m.backups[filePath] = fileBackup{content: content}
+		return nil
+	}
+	d, err := m.store.Save(m.storeRef("backups", filePath), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	m.backups[filePath] = fileBackup{digest: d}
+	return nil
+}
+
+// loadBackup returns the original content of a backup.
+func (m *FileManager) loadBackup(backup fileBackup) ([]byte, error) {
+	if backup.digest == "" {
+		return backup.content, nil
+	}
+	return m.store.Get(backup.digest)
+}
+
+// releaseBackups removes stored backups. Callers must hold m.mu.
+func (m *FileManager) releaseBackups() {
+	if m.store == nil {
+		return
+	}
+	for filePath := range m.backups {
+		if err := m.store.Unlink(m.storeRef("backups", filePath)); err != nil {
+			m.logger.Warn("Failed to release backup",
+				slog.String("path", filePath),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// saveArtifact keeps written content in the store. Failures are logged,
+// since the file itself was written. Callers must hold m.mu.
+func (m *FileManager) saveArtifact(filePath, content string) {
+	if m.store == nil {
+		return
+	}
+	if _, err := m.store.Save(m.storeRef("artifacts", filePath), bytes.NewReader([]byte(content))); err != nil {
+		m.logger.Warn("Failed to store artifact",
+			slog.String("path", filePath),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// storeRef returns the store reference for filePath in a session namespace.
+func (m *FileManager) storeRef(kind, filePath string) string {
+	if rel, err := filepath.Rel(m.projectRoot, filePath); err == nil && filepath.IsLocal(rel) {
+		filePath = rel
+	}
+	return cas.RefName("tdg", m.session, kind, filePath)
+}
+
 // HasBackups returns true if there are any backed-up files.
 //
 // Thread Safety: Safe for concurrent use.
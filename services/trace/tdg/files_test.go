@@ -14,6 +14,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
 )
 
 // =============================================================================
@@ -410,6 +412,54 @@ func TestFileManager_Cleanup(t *testing.T) {
 	})
 }
 
+func TestFileManager_Store(t *testing.T) {
+	dir := t.TempDir()
+	store, err := cas.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+	fm := NewFileManagerWithStore(dir, store, nil)
+
+	filePath := filepath.Join(dir, "code.go")
+	originalContent := "// original\n"
+	if err := os.WriteFile(filePath, []byte(originalContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.ApplyPatch(&Patch{FilePath: "code.go", NewContent: "// modified\n"}); err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if err := fm.WriteTest(&TestCase{Name: "TestX", FilePath: "code_test.go", Content: "package code\n", Language: "go"}); err != nil {
+		t.Fatalf("WriteTest() error = %v", err)
+	}
+
+	refs, _ := store.Refs("tdg/")
+	if len(refs) != 3 {
+		t.Fatalf("store refs = %+v, want one backup and two artifacts", refs)
+	}
+
+	if err := fm.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != originalContent {
+		t.Errorf("content after rollback = %q, want %q", string(content), originalContent)
+	}
+
+	// Backups are released by rollback; artifacts remain until Release
+	refs, _ = store.Refs("tdg/")
+	if len(refs) != 2 {
+		t.Errorf("store refs after rollback = %+v, want two artifacts", refs)
+	}
+	if err := fm.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	report, _ := store.Report()
+	if report.Refs != 0 || report.Objects != 0 {
+		t.Errorf("store after Release has %d refs and %d objects, want none", report.Refs, report.Objects)
+	}
+}
+
 func TestFileManager_HasBackups(t *testing.T) {
 	dir := t.TempDir()
 	fm := NewFileManager(dir, nil)
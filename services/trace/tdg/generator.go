@@ -134,7 +134,8 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 	}
 
 	// Build prompt
-	prompt := g.buildTestGenerationPrompt(req, codeContext)
+	cases := tableCases(req)
+	prompt := g.buildTestGenerationPrompt(req, codeContext, cases)
 
 	// Generate via LLM
 	g.callCount.Add(1)
@@ -154,6 +155,7 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 		)
 		return nil, err
 	}
+	g.attachCases(tc, cases)
 
 	// Set package path based on target file or project
 	if req.TargetFile != "" {
@@ -165,6 +167,7 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 	g.logger.Info("Generated reproducer test",
 		slog.String("name", tc.Name),
 		slog.String("file", tc.FilePath),
+		slog.Int("cases", len(tc.Cases)),
 		slog.Duration("duration", time.Since(start)),
 	)
 
@@ -199,7 +202,8 @@ func (g *TestGenerator) RefineTest(ctx context.Context, req *Request, previousTe
 		slog.String("previous_name", previousTest.Name),
 	)
 
-	prompt := g.buildTestRefinementPrompt(req, previousTest, testOutput)
+	cases := tableCases(req)
+	prompt := g.buildTestRefinementPrompt(req, previousTest, testOutput, cases)
 
 	g.callCount.Add(1)
 	response, err := g.llm.GenerateWithSystem(ctx, tdgSystemPrompt, prompt)
@@ -211,6 +215,7 @@ func (g *TestGenerator) RefineTest(ctx context.Context, req *Request, previousTe
 	if err != nil {
 		return nil, err
 	}
+	g.attachCases(tc, cases)
 
 	g.logger.Info("Refined test",
 		slog.String("name", tc.Name),
@@ -370,7 +375,7 @@ RESPONSE FORMAT for fixes:
 // complete file content with fix
 ` + "```" + ``
 
-func (g *TestGenerator) buildTestGenerationPrompt(req *Request, codeContext string) string {
+func (g *TestGenerator) buildTestGenerationPrompt(req *Request, codeContext string, cases []string) string {
 	var sb strings.Builder
 
 	sb.WriteString("Write a test that reproduces this bug:\n\n")
@@ -389,22 +394,34 @@ func (g *TestGenerator) buildTestGenerationPrompt(req *Request, codeContext stri
 	sb.WriteString(fmt.Sprintf("- Use standard %s testing framework\n", req.Language))
 	sb.WriteString("- Name the test descriptively (e.g., TestFunctionName_Scenario_ExpectedBehavior)\n")
 	sb.WriteString("- Include clear arrange/act/assert sections\n")
+	if len(cases) > 0 {
+		writeTableDrivenRequirements(&sb, req.Language, cases)
+	}
+	writeScaffold(&sb, req.Language)
 
 	return sb.String()
 }
 
-func (g *TestGenerator) buildTestRefinementPrompt(req *Request, previousTest *TestCase, testOutput string) string {
+func (g *TestGenerator) buildTestRefinementPrompt(req *Request, previousTest *TestCase, testOutput string, cases []string) string {
 	var sb strings.Builder
 
-	sb.WriteString("Your previous test PASSED when it should have FAILED.\n\n")
+	if previousTest.IsTableDriven() {
+		sb.WriteString("No case of your previous table-driven test FAILED. At least one case must fail.\n\n")
+	} else {
+		sb.WriteString("Your previous test PASSED when it should have FAILED.\n\n")
+	}
 	sb.WriteString("Previous test:\n")
 	sb.WriteString(previousTest.Content)
-	sb.WriteString("\n\nTest output (unexpectedly passed):\n")
+	sb.WriteString("\n\nTest output (no reproducing failure):\n")
 	sb.WriteString(testOutput)
 	sb.WriteString("\n\nBug description:\n")
 	sb.WriteString(req.BugDescription)
 	sb.WriteString("\n\nPlease write a NEW test that actually reproduces the bug.\n")
 	sb.WriteString("The test must FAIL on the current code.\n")
+	if len(cases) > 0 {
+		writeTableDrivenRequirements(&sb, req.Language, cases)
+	}
+	writeScaffold(&sb, req.Language)
 
 	return sb.String()
 }
@@ -481,6 +498,31 @@ func (g *TestGenerator) parseTestFromResponse(response, language string) (*TestC
 	}, nil
 }
 
+// tableCases returns the input cases to generate a table-driven test for,
+// or nil for a single test.
+func tableCases(req *Request) []string {
+	if !supportsTableDriven(req.Language) {
+		return nil
+	}
+	return DetectInputCases(req.BugDescription)
+}
+
+// attachCases records the input cases on a test if the LLM produced a
+// table-driven test. Otherwise the test is verified as a single test.
+func (g *TestGenerator) attachCases(tc *TestCase, cases []string) {
+	if len(cases) == 0 {
+		return
+	}
+	if !isTableDriven(tc.Content, tc.Language) {
+		g.logger.Warn("Expected a table-driven test, got a single test",
+			slog.String("name", tc.Name),
+			slog.Int("cases", len(cases)),
+		)
+		return
+	}
+	tc.Cases = cases
+}
+
 func (g *TestGenerator) parsePatchFromResponse(response string) (*Patch, error) {
 	// Extract file path from FIX_FILE marker
 	filePath := extractMarkedPath(response, "FIX_FILE:")
@@ -564,6 +606,7 @@ func extractCodeBlock(response, language string) string {
 
 func extractTestName(content, language string) string {
 	lines := strings.Split(content, "\n")
+	testAttr := false
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		switch language {
@@ -590,6 +633,22 @@ func extractTestName(content, language string) string {
 					return name
 				}
 			}
+		case "rust":
+			// The first function after a test attribute (#[test], #[tokio::test])
+			if strings.HasPrefix(line, "#[") && strings.HasSuffix(line, "test]") {
+				testAttr = true
+				continue
+			}
+			if testAttr {
+				if name := rustFnName(line); name != "" {
+					return name
+				}
+			}
+		case "java":
+			// The test class: both Maven and Gradle filter by simple class name
+			if name := javaClassName(line); name != "" {
+				return name
+			}
 		case "typescript", "javascript":
 			if strings.Contains(line, "test(") || strings.Contains(line, "it(") {
 				// Extract test description
@@ -611,6 +670,40 @@ func extractTestName(content, language string) string {
 	return ""
 }
 
+// rustFnName returns the name of the function declared on a line, or
+// empty if the line declares none.
+func rustFnName(line string) string {
+	line = strings.TrimPrefix(line, "pub ")
+	line = strings.TrimPrefix(line, "async ")
+	if !strings.HasPrefix(line, "fn ") {
+		return ""
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(line, "fn "))
+	if idx := strings.IndexAny(name, "(<"); idx != -1 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// javaClassName returns the name of the class declared on a line, or
+// empty if the line declares none.
+func javaClassName(line string) string {
+	parts := strings.Fields(line)
+	for i, part := range parts {
+		if part == "class" && i+1 < len(parts) {
+			name := parts[i+1]
+			if idx := strings.IndexAny(name, "{<"); idx != -1 {
+				name = name[:idx]
+			}
+			return name
+		}
+		if part != "public" && part != "final" && part != "abstract" {
+			return ""
+		}
+	}
+	return ""
+}
+
 func inferTestFilePath(language string) string {
 	switch language {
 	case "go":
@@ -621,6 +714,10 @@ func inferTestFilePath(language string) string {
 		return "reproducer.test.ts"
 	case "javascript":
 		return "reproducer.test.js"
+	case "rust":
+		return "tests/reproducer.rs"
+	case "java":
+		return "src/test/java/ReproducerTest.java"
 	default:
 		return "reproducer_test"
 	}
@@ -419,6 +419,24 @@ func TestExtractTestName(t *testing.T) {
 			language: "javascript",
 			want:     "should process data",
 		},
+		{
+			name:     "rust test attribute",
+			content:  "use my_crate::parse;\n\nfn helper() -> i32 { 1 }\n\n#[test]\n#[should_panic]\nfn parse_negative_returns_error() {\n}",
+			language: "rust",
+			want:     "parse_negative_returns_error",
+		},
+		{
+			name:     "rust async test",
+			content:  "#[tokio::test]\nasync fn fetch_times_out() {}",
+			language: "rust",
+			want:     "fetch_times_out",
+		},
+		{
+			name:     "java test class",
+			content:  "package com.example;\n\nimport org.junit.jupiter.api.Test;\n\npublic class ParserTest {\n    @Test\n    void parseNegative() {}\n}",
+			language: "java",
+			want:     "ParserTest",
+		},
 		{
 			name:     "no test found",
 			content:  "package main\n\nfunc main() {}",
@@ -437,6 +455,39 @@ func TestExtractTestName(t *testing.T) {
 	}
 }
 
+func TestTestGenerator_GenerateReproducerTest_Scaffold(t *testing.T) {
+	tests := []struct {
+		language string
+		response string
+		wantName string
+		wantFile string
+		scaffold string
+	}{
+		{"rust", "```rust\n#[test]\nfn parse_empty() {}\n```", "parse_empty", "tests/reproducer.rs", "#[test]"},
+		{"java", "```java\nclass ParserTest {\n    @Test\n    void parseEmpty() {}\n}\n```", "ParserTest", "src/test/java/ReproducerTest.java", "@Test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			llm := &promptRecordingLLM{resp: tt.response}
+			gen := NewTestGenerator(llm, nil, nil)
+
+			tc, err := gen.GenerateReproducerTest(context.Background(), &Request{
+				BugDescription: "parse panics on empty input", ProjectRoot: "/p", Language: tt.language,
+			})
+			if err != nil {
+				t.Fatalf("GenerateReproducerTest() error = %v", err)
+			}
+			if tc.Name != tt.wantName || tc.FilePath != tt.wantFile {
+				t.Errorf("test = %s in %s, want %s in %s", tc.Name, tc.FilePath, tt.wantName, tt.wantFile)
+			}
+			if !strings.Contains(llm.prompt, "```"+tt.language+"\n") || !strings.Contains(llm.prompt, tt.scaffold) {
+				t.Errorf("prompt lacks the %s scaffold:\n%s", tt.language, llm.prompt)
+			}
+		})
+	}
+}
+
 func TestInferTestFilePath(t *testing.T) {
 	tests := []struct {
 		language string
@@ -446,6 +497,8 @@ func TestInferTestFilePath(t *testing.T) {
 		{"python", "test_reproducer.py"},
 		{"typescript", "reproducer.test.ts"},
 		{"javascript", "reproducer.test.js"},
+		{"rust", "tests/reproducer.rs"},
+		{"java", "src/test/java/ReproducerTest.java"},
 		{"unknown", "reproducer_test"},
 	}
 
@@ -474,6 +527,8 @@ func TestLanguageForFile(t *testing.T) {
 		{"app.tsx", "typescript"},
 		{"script.js", "javascript"},
 		{"script.jsx", "javascript"},
+		{"lib.rs", "rust"},
+		{"ParserTest.java", "java"},
 		{"unknown.xyz", ""},
 	}
 
@@ -11,6 +11,7 @@
 package tdg
 
 import (
+	"os"
 	"path/filepath"
 	"sync"
 )
@@ -35,6 +36,15 @@ type LanguageConfig struct {
 	// Use {package} as placeholder for package/directory path.
 	SuiteArgs []string
 
+	// CoverageArgs are arguments for running the full test suite with
+	// coverage. Use {package} as for SuiteArgs and {coverage} as the
+	// placeholder for the profile file. Empty means no coverage support.
+	CoverageArgs []string
+
+	// CoverageFormat is the format of the profile written by CoverageArgs
+	// (CoverageFormatGo or CoverageFormatCoveragePy).
+	CoverageFormat string
+
 	// TestFilePattern is the glob pattern for test files.
 	TestFilePattern string
 
@@ -43,6 +53,19 @@ type LanguageConfig struct {
 
 	// Extensions are file extensions for this language.
 	Extensions []string
+
+	// Scaffold is a skeleton reproducer test shown to the LLM so generated
+	// tests follow the framework's conventions. Empty means no skeleton.
+	Scaffold string
+
+	// ProjectFiles are build files that select this configuration when
+	// present in the project root (e.g., "build.gradle"). Only used for
+	// Variants.
+	ProjectFiles []string
+
+	// Variants are alternative configurations for the same language, such
+	// as another build tool. See LanguageConfigRegistry.Resolve.
+	Variants []*LanguageConfig
 }
 
 // =============================================================================
@@ -62,7 +85,8 @@ type LanguageConfigRegistry struct {
 //
 // Outputs:
 //
-//	*LanguageConfigRegistry - Registry with Go, Python, TypeScript configs
+//	*LanguageConfigRegistry - Registry with Go, Python, TypeScript,
+//	                          JavaScript, Rust and Java configs
 func NewLanguageConfigRegistry() *LanguageConfigRegistry {
 	r := &LanguageConfigRegistry{
 		configs: make(map[string]*LanguageConfig),
@@ -79,9 +103,12 @@ func (r *LanguageConfigRegistry) registerDefaults() {
 		TestCommand:     "go",
 		TestArgs:        []string{"test", "-v", "-run", "{name}", "{package}"},
 		SuiteArgs:       []string{"test", "-v", "{package}/..."},
+		CoverageArgs:    []string{"test", "-coverpkg=./...", "-coverprofile={coverage}", "{package}/..."},
+		CoverageFormat:  CoverageFormatGo,
 		TestFilePattern: "*_test.go",
 		TestNameFlag:    "-run",
 		Extensions:      []string{".go"},
+		Scaffold:        goScaffold,
 	}
 
 	// Python configuration
@@ -90,9 +117,12 @@ func (r *LanguageConfigRegistry) registerDefaults() {
 		TestCommand:     "pytest",
 		TestArgs:        []string{"-v", "-k", "{name}", "{file}"},
 		SuiteArgs:       []string{"-v", "{package}"},
+		CoverageArgs:    []string{"--cov", "--cov-report=json:{coverage}", "{package}"},
+		CoverageFormat:  CoverageFormatCoveragePy,
 		TestFilePattern: "test_*.py",
 		TestNameFlag:    "-k",
 		Extensions:      []string{".py"},
+		Scaffold:        pythonScaffold,
 	}
 
 	// TypeScript configuration
@@ -104,6 +134,7 @@ func (r *LanguageConfigRegistry) registerDefaults() {
 		TestFilePattern: "*.test.ts",
 		TestNameFlag:    "--testNamePattern",
 		Extensions:      []string{".ts", ".tsx"},
+		Scaffold:        jestScaffold,
 	}
 
 	// JavaScript configuration (same as TypeScript but different extensions)
@@ -115,6 +146,58 @@ func (r *LanguageConfigRegistry) registerDefaults() {
 		TestFilePattern: "*.test.js",
 		TestNameFlag:    "--testNamePattern",
 		Extensions:      []string{".js", ".jsx", ".mjs"},
+		Scaffold:        jestScaffold,
+	}
+
+	// Rust configuration. Reproducers are integration tests under tests/,
+	// and cargo filters tests by substring of their path.
+	r.configs["rust"] = &LanguageConfig{
+		Language:        "rust",
+		TestCommand:     "cargo",
+		TestArgs:        []string{"test", "{name}", "--", "--nocapture"},
+		SuiteArgs:       []string{"test"},
+		TestFilePattern: "tests/*.rs",
+		Extensions:      []string{".rs"},
+		Scaffold:        rustScaffold,
+	}
+
+	// Java configuration. Maven is the default; Gradle projects are
+	// selected by their build files. Both accept a simple class name as
+	// the test filter.
+	r.configs["java"] = &LanguageConfig{
+		Language:    "java",
+		TestCommand: "mvn",
+		TestArgs: []string{"test", "-Dtest={name}",
+			"-Dsurefire.failIfNoSpecifiedTests=false", "-DfailIfNoTests=false"},
+		SuiteArgs:       []string{"test"},
+		TestFilePattern: "*Test.java",
+		TestNameFlag:    "-Dtest",
+		Extensions:      []string{".java"},
+		Scaffold:        javaScaffold,
+		Variants: []*LanguageConfig{
+			{
+				Language:        "java",
+				TestCommand:     "./gradlew",
+				TestArgs:        []string{"test", "--tests", "{name}"},
+				SuiteArgs:       []string{"test"},
+				TestFilePattern: "*Test.java",
+				TestNameFlag:    "--tests",
+				Extensions:      []string{".java"},
+				Scaffold:        javaScaffold,
+				ProjectFiles:    []string{"gradlew"},
+			},
+			{
+				Language:        "java",
+				TestCommand:     "gradle",
+				TestArgs:        []string{"test", "--tests", "{name}"},
+				SuiteArgs:       []string{"test"},
+				TestFilePattern: "*Test.java",
+				TestNameFlag:    "--tests",
+				Extensions:      []string{".java"},
+				Scaffold:        javaScaffold,
+				ProjectFiles:    []string{"build.gradle", "build.gradle.kts"},
+			},
+		},
 	}
 }
 
@@ -137,6 +220,40 @@ func (r *LanguageConfigRegistry) Get(language string) (*LanguageConfig, bool) {
 	return cfg, ok
 }
 
+// Resolve returns the configuration for a language in a project.
+//
+// Description:
+//
+//	Returns the first variant whose ProjectFiles include a file that
+//	exists in projectDir, e.g. the Gradle configuration for a Java project
+//	with a build.gradle. Falls back to the language's main configuration.
+//
+// Inputs:
+//
+//	language - The language identifier
+//	projectDir - The project root. Empty means the main configuration.
+//
+// Outputs:
+//
+//	*LanguageConfig - The configuration, or nil if not found
+//	bool - True if configuration exists
+//
+// Thread Safety: Safe for concurrent use.
+func (r *LanguageConfigRegistry) Resolve(language, projectDir string) (*LanguageConfig, bool) {
+	cfg, ok := r.Get(language)
+	if !ok || projectDir == "" {
+		return cfg, ok
+	}
+	for _, variant := range cfg.Variants {
+		for _, name := range variant.ProjectFiles {
+			if _, err := os.Stat(filepath.Join(projectDir, name)); err == nil {
+				return variant, true
+			}
+		}
+	}
+	return cfg, true
+}
+
 // Register adds or updates a language configuration.
 //
 // Inputs:
@@ -11,6 +11,8 @@
 package tdg
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -49,12 +51,12 @@ func TestNewLanguageConfigRegistry(t *testing.T) {
 
 	// Should have default languages
 	langs := r.Languages()
-	if len(langs) < 4 {
-		t.Errorf("expected at least 4 languages, got %d", len(langs))
+	if len(langs) < 6 {
+		t.Errorf("expected at least 6 languages, got %d", len(langs))
 	}
 
 	// Check each default language
-	expectedLangs := []string{"go", "python", "typescript", "javascript"}
+	expectedLangs := []string{"go", "python", "typescript", "javascript", "rust", "java"}
 	for _, expected := range expectedLangs {
 		cfg, ok := r.Get(expected)
 		if !ok {
@@ -90,9 +92,9 @@ func TestLanguageConfigRegistry_Get(t *testing.T) {
 	})
 
 	t.Run("non-existing language", func(t *testing.T) {
-		_, ok := r.Get("rust")
+		_, ok := r.Get("ruby")
 		if ok {
-			t.Error("expected rust to not exist")
+			t.Error("expected ruby to not exist")
 		}
 	})
 }
@@ -102,20 +104,20 @@ func TestLanguageConfigRegistry_Register(t *testing.T) {
 
 	t.Run("register new language", func(t *testing.T) {
 		cfg := &LanguageConfig{
-			Language:    "rust",
-			TestCommand: "cargo",
-			TestArgs:    []string{"test", "--", "{name}"},
-			SuiteArgs:   []string{"test"},
+			Language:    "ruby",
+			TestCommand: "rspec",
+			TestArgs:    []string{"-e", "{name}", "{file}"},
+			SuiteArgs:   []string{"{package}"},
 		}
 
 		r.Register(cfg)
 
-		retrieved, ok := r.Get("rust")
+		retrieved, ok := r.Get("ruby")
 		if !ok {
-			t.Fatal("expected rust to be registered")
+			t.Fatal("expected ruby to be registered")
 		}
-		if retrieved.TestCommand != "cargo" {
-			t.Errorf("TestCommand = %q, want cargo", retrieved.TestCommand)
+		if retrieved.TestCommand != "rspec" {
+			t.Errorf("TestCommand = %q, want rspec", retrieved.TestCommand)
 		}
 	})
 
@@ -195,7 +197,9 @@ func TestLanguageConfigRegistry_GetByExtension(t *testing.T) {
 		{".js", "javascript", true},
 		{".jsx", "javascript", true},
 		{".mjs", "javascript", true},
-		{".rs", "", false},
+		{".rs", "rust", true},
+		{".java", "java", true},
+		{".rb", "", false},
 		{".txt", "", false},
 		{"", "", false},
 	}
@@ -375,3 +379,80 @@ func TestJavaScriptLanguageConfig(t *testing.T) {
 		t.Error("expected .mjs extension")
 	}
 }
+
+// =============================================================================
+// RUST/JAVA CONFIG DETAILS
+// =============================================================================
+
+func TestRustLanguageConfig(t *testing.T) {
+	cfg, ok := GetLanguageConfig("rust")
+	if !ok {
+		t.Fatal("expected rust config")
+	}
+	if cfg.TestCommand != "cargo" {
+		t.Errorf("TestCommand = %q, want cargo", cfg.TestCommand)
+	}
+	if cfg.TestArgs[0] != "test" || cfg.TestArgs[1] != "{name}" {
+		t.Errorf("TestArgs = %v, want cargo test {name} ...", cfg.TestArgs)
+	}
+	if cfg.TestFilePattern != "tests/*.rs" {
+		t.Errorf("TestFilePattern = %q, want tests/*.rs", cfg.TestFilePattern)
+	}
+	if cfg.Scaffold == "" {
+		t.Error("expected a reproducer scaffold")
+	}
+}
+
+func TestLanguageConfigRegistry_Resolve(t *testing.T) {
+	r := NewLanguageConfigRegistry()
+
+	tests := []struct {
+		name        string
+		files       []string
+		wantCommand string
+	}{
+		{"maven project", []string{"pom.xml"}, "mvn"},
+		{"gradle project", []string{"build.gradle"}, "gradle"},
+		{"gradle kotlin dsl", []string{"settings.gradle.kts", "build.gradle.kts"}, "gradle"},
+		{"gradle wrapper", []string{"build.gradle", "gradlew"}, "./gradlew"},
+		{"no build file", nil, "mvn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg, ok := r.Resolve("java", dir)
+			if !ok {
+				t.Fatal("expected java config")
+			}
+			if cfg.TestCommand != tt.wantCommand {
+				t.Errorf("TestCommand = %q, want %q", cfg.TestCommand, tt.wantCommand)
+			}
+			if cfg.Language != "java" {
+				t.Errorf("Language = %q, want java", cfg.Language)
+			}
+		})
+	}
+
+	t.Run("language without variants", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "build.gradle"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, ok := r.Resolve("go", dir)
+		if !ok || cfg.TestCommand != "go" {
+			t.Errorf("Resolve(go) = %+v, %v", cfg, ok)
+		}
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		if _, ok := r.Resolve("ruby", t.TempDir()); ok {
+			t.Error("expected ruby to not resolve")
+		}
+	})
+}
@@ -0,0 +1,477 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// MUTATION TYPES
+// =============================================================================
+
+// Mutant is a small change to the fixed code that the reproducer test
+// should detect.
+type Mutant struct {
+	// FilePath is the mutated file.
+	FilePath string `json:"file_path"`
+
+	// Line is the 1-based line number of the mutation.
+	Line int `json:"line"`
+
+	// Operator describes the mutation (e.g., "== -> !=", "negate condition").
+	Operator string `json:"operator"`
+
+	// Original is the original line, trimmed.
+	Original string `json:"original"`
+
+	// Mutated is the mutated line, trimmed.
+	Mutated string `json:"mutated"`
+
+	// Killed indicates the reproducer test failed on the mutant.
+	Killed bool `json:"killed"`
+
+	// content is the full mutated file content.
+	content string
+}
+
+// MutationReport is the outcome of the mutation testing pass.
+type MutationReport struct {
+	// Total is the number of mutants tested.
+	Total int `json:"total"`
+
+	// Killed is the number of mutants the reproducer test detected.
+	Killed int `json:"killed"`
+
+	// Required is the number of mutants the test had to kill.
+	Required int `json:"required"`
+
+	// Survivors are the mutants the reproducer test did not detect.
+	Survivors []*Mutant `json:"survivors,omitempty"`
+}
+
+// Passed returns true if the reproducer test killed enough mutants.
+func (r *MutationReport) Passed() bool {
+	return r.Killed >= r.Required
+}
+
+// Score returns the fraction of mutants killed, or 1 without mutants.
+func (r *MutationReport) Score() float64 {
+	if r.Total == 0 {
+		return 1
+	}
+	return float64(r.Killed) / float64(r.Total)
+}
+
+// =============================================================================
+// MUTANT GENERATION
+// =============================================================================
+
+// mutationOperator replaces a binary operator with its counterpart.
+type mutationOperator struct {
+	from string
+	to   string
+}
+
+// mutationOperators are the operator flips, in the order they are tried.
+// Operators must be surrounded by spaces so that generics, arrows,
+// increments and unary minus are not mutated.
+var mutationOperators = []mutationOperator{
+	{" === ", " !== "},
+	{" !== ", " === "},
+	{" == ", " != "},
+	{" != ", " == "},
+	{" <= ", " > "},
+	{" >= ", " < "},
+	{" < ", " >= "},
+	{" > ", " <= "},
+	{" && ", " || "},
+	{" || ", " && "},
+	{" and ", " or "},
+	{" or ", " and "},
+	{" + ", " - "},
+	{" - ", " + "},
+}
+
+// Function start patterns per language, used to find the changed functions.
+var functionStartPatterns = map[string]*regexp.Regexp{
+	"go":         regexp.MustCompile(`^func\b`),
+	"python":     regexp.MustCompile(`^\s*(async\s+)?def\s`),
+	"typescript": regexp.MustCompile(`^\s*(export\s+)?(async\s+)?function\b|^\s*(public\s+|private\s+|protected\s+|static\s+|async\s+)*\w+\s*\([^)]*\)\s*(:\s*[\w<>\[\]|, ]+)?\s*\{\s*$`),
+	"javascript": regexp.MustCompile(`^\s*(export\s+)?(async\s+)?function\b|^\s*(static\s+|async\s+)*\w+\s*\([^)]*\)\s*\{\s*$`),
+	"rust":       regexp.MustCompile(`^\s*(pub(\([\w:]+\))?\s+)?(const\s+)?(async\s+)?(unsafe\s+)?fn\s`),
+	"java":       regexp.MustCompile(`^\s*((public|private|protected|static|final|synchronized|abstract)\s+)+[\w<>\[\],.? ]+\s+\w+\s*\(`),
+}
+
+// Condition negation patterns per language. Group 1 is the text before
+// the condition, group 2 the condition and group 3 the text after it.
+var (
+	braceConditionPattern  = regexp.MustCompile(`^(\s*(?:\}\s*else\s+)?if\s+)(.+?)(\s*\{\s*)$`)
+	parenConditionPattern  = regexp.MustCompile(`^(\s*(?:\}\s*else\s+)?if\s*\()(.+)(\)\s*\{?\s*)$`)
+	pythonConditionPattern = regexp.MustCompile(`^(\s*(?:el)?if\s+)(.+?)(\s*:\s*)$`)
+)
+
+// GenerateMutants creates mutants of the functions changed by a patch.
+//
+// Description:
+//
+//	Finds the lines the patch changed, widens them to the enclosing
+//	functions and applies two kinds of mutation to every code line there:
+//	condition negation ("if x" -> "if !(x)") and operator flips
+//	(== <-> !=, < <-> >=, && <-> ||, + <-> -, ...). Each mutant contains
+//	exactly one mutation. Comments and string literals are not mutated.
+//
+// Inputs:
+//
+//	language - The programming language of the patched file
+//	patch - The applied patch. A patch without OldContent is a new file and
+//	        all of it is considered changed.
+//	limit - Maximum number of mutants to return (<= 0 means no limit)
+//
+// Outputs:
+//
+//	[]*Mutant - The mutants in file order, or nil if there is nothing to mutate
+func GenerateMutants(language string, patch *Patch, limit int) []*Mutant {
+	if patch == nil || patch.NewContent == "" {
+		return nil
+	}
+	lines := strings.Split(patch.NewContent, "\n")
+	regions := changedFunctionRegions(language, strings.Split(patch.OldContent, "\n"), lines, patch.OldContent == "")
+
+	lineComment := "//"
+	if language == "python" {
+		lineComment = "#"
+	}
+
+	var mutants []*Mutant
+	add := func(i int, operator, mutatedLine string) bool {
+		mutated := make([]string, len(lines))
+		copy(mutated, lines)
+		mutated[i] = mutatedLine
+		mutants = append(mutants, &Mutant{
+			FilePath: patch.FilePath,
+			Line:     i + 1,
+			Operator: operator,
+			Original: strings.TrimSpace(lines[i]),
+			Mutated:  strings.TrimSpace(mutatedLine),
+			content:  strings.Join(mutated, "\n"),
+		})
+		return limit > 0 && len(mutants) >= limit
+	}
+
+	for _, region := range regions {
+		for i := region[0]; i < region[1]; i++ {
+			line := lines[i]
+			if isCommentLine(line, lineComment) {
+				continue
+			}
+			mask, codeEnd := codeMask(line, lineComment)
+
+			if negated, ok := negateCondition(language, line[:codeEnd]); ok {
+				if add(i, "negate condition", negated+line[codeEnd:]) {
+					return mutants
+				}
+			}
+
+			for _, op := range mutationOperators {
+				for offset := 0; offset < codeEnd; {
+					idx := strings.Index(line[offset:codeEnd], op.from)
+					if idx < 0 {
+						break
+					}
+					idx += offset
+					offset = idx + 1
+					if !isCode(mask, idx, len(op.from)) {
+						continue
+					}
+					mutatedLine := line[:idx] + op.to + line[idx+len(op.from):]
+					if add(i, strings.TrimSpace(op.from)+" -> "+strings.TrimSpace(op.to), mutatedLine) {
+						return mutants
+					}
+				}
+			}
+		}
+	}
+	return mutants
+}
+
+// changedFunctionRegions returns the [start, end) line ranges of the
+// functions containing changed lines, in file order and without overlap.
+// Changed lines outside any function form their own region.
+func changedFunctionRegions(language string, oldLines, newLines []string, newFile bool) [][2]int {
+	if newFile {
+		return [][2]int{{0, len(newLines)}}
+	}
+
+	prefix, changedEnd := changedLineRange(oldLines, newLines)
+	if prefix >= changedEnd {
+		return nil
+	}
+
+	pattern := functionStartPatterns[language]
+	var regions [][2]int
+	for i := prefix; i < changedEnd; i++ {
+		region := [2]int{i, i + 1}
+		if start, end, ok := enclosingFunction(pattern, newLines, i); ok {
+			region = [2]int{start, end}
+		}
+		if n := len(regions); n > 0 && region[0] < regions[n-1][1] {
+			if region[1] > regions[n-1][1] {
+				regions[n-1][1] = region[1]
+			}
+			continue
+		}
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// changedLineRange returns the [start, end) range of newLines that differs
+// from oldLines: everything between their common prefix and common suffix.
+// The range is empty if the contents are equal.
+func changedLineRange(oldLines, newLines []string) (int, int) {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	return prefix, len(newLines) - suffix
+}
+
+// enclosingFunction finds the function around line i. The function ends
+// at the first following line indented no deeper than its start line.
+func enclosingFunction(pattern *regexp.Regexp, lines []string, i int) (int, int, bool) {
+	if pattern == nil {
+		return 0, 0, false
+	}
+	start := -1
+	for j := i; j >= 0; j-- {
+		if pattern.MatchString(lines[j]) {
+			start = j
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+
+	indent := indentWidth(lines[start])
+	end := len(lines)
+	for j := start + 1; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" || strings.HasPrefix(trimmed, ")") {
+			continue
+		}
+		if indentWidth(lines[j]) <= indent {
+			end = j
+			if strings.HasPrefix(trimmed, "}") {
+				end = j + 1
+			}
+			break
+		}
+	}
+	if i >= end {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// negateCondition negates the condition of an if statement.
+func negateCondition(language, code string) (string, bool) {
+	var pattern *regexp.Regexp
+	negation := "!("
+	switch language {
+	case "go", "rust":
+		pattern = braceConditionPattern
+	case "typescript", "javascript", "java":
+		pattern = parenConditionPattern
+	case "python":
+		pattern = pythonConditionPattern
+		negation = "not ("
+	default:
+		return "", false
+	}
+
+	m := pattern.FindStringSubmatch(code)
+	if m == nil {
+		return "", false
+	}
+	cond := strings.TrimSpace(m[2])
+	// Go init statements and Rust pattern matches are not plain conditions.
+	if cond == "" || (language == "go" && strings.Contains(cond, ";")) ||
+		(language == "rust" && strings.HasPrefix(cond, "let ")) {
+		return "", false
+	}
+	return m[1] + negation + cond + ")" + m[3], true
+}
+
+// isCommentLine returns true if the line holds only a comment.
+func isCommentLine(line, lineComment string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, lineComment) ||
+		strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "* ") ||
+		strings.HasPrefix(trimmed, "*/")
+}
+
+// codeMask marks which bytes of a line are code (outside string literals)
+// and returns the offset where a trailing comment starts.
+func codeMask(line, lineComment string) ([]bool, int) {
+	mask := make([]bool, len(line))
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.HasPrefix(line[i:], lineComment):
+			return mask, i
+		default:
+			mask[i] = true
+		}
+	}
+	return mask, len(line)
+}
+
+// isCode returns true if all bytes of [start, start+n) are code.
+func isCode(mask []bool, start, n int) bool {
+	for i := start; i < start+n; i++ {
+		if !mask[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// indentWidth returns the width of the leading whitespace, counting a tab
+// as four columns.
+func indentWidth(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// =============================================================================
+// MUTATION TESTING
+// =============================================================================
+
+// runMutationTesting runs the reproducer test against mutants of the
+// applied patches.
+//
+// Description:
+//
+//	Writes each mutant over the patched file, runs the reproducer test and
+//	restores the patched content. A mutant is killed when the test fails
+//	or times out. At most Config.MaxMutants mutants are tested.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//
+// Outputs:
+//
+//	*MutationReport - The killed and surviving mutants
+//	error - Non-nil if a mutant could not be written, tested or restored
+func (c *Controller) runMutationTesting(ctx context.Context) (*MutationReport, error) {
+	report := &MutationReport{}
+	test := c.ctx.ReproducerTest
+
+	for _, patch := range c.ctx.AppliedPatches {
+		remaining := c.config.MaxMutants - report.Total
+		if remaining <= 0 {
+			break
+		}
+		for _, mutant := range GenerateMutants(c.ctx.Request.Language, patch, remaining) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			killed, err := c.testMutant(ctx, patch, mutant)
+			if err != nil {
+				return nil, err
+			}
+			mutant.Killed = killed
+			report.Total++
+			if killed {
+				report.Killed++
+			} else {
+				report.Survivors = append(report.Survivors, mutant)
+			}
+		}
+	}
+
+	report.Required = c.config.MinMutantsKilled
+	if report.Required > report.Total {
+		report.Required = report.Total
+	}
+
+	c.logger.Info("Mutation testing complete",
+		slog.String("test_name", test.Name),
+		slog.Int("mutants", report.Total),
+		slog.Int("killed", report.Killed),
+		slog.Int("required", report.Required),
+	)
+	for _, m := range report.Survivors {
+		c.logger.Debug("Mutant survived",
+			slog.String("file", m.FilePath),
+			slog.Int("line", m.Line),
+			slog.String("operator", m.Operator),
+			slog.String("mutated", m.Mutated),
+		)
+	}
+
+	return report, nil
+}
+
+// testMutant runs the reproducer test against one mutant and restores the
+// patched file.
+func (c *Controller) testMutant(ctx context.Context, patch *Patch, mutant *Mutant) (bool, error) {
+	if err := c.files.overwrite(patch.FilePath, mutant.content); err != nil {
+		return false, err
+	}
+
+	c.ctx.Metrics.TestsRun++
+	result, runErr := c.runner.RunTest(ctx, c.ctx.ReproducerTest)
+
+	if err := c.files.overwrite(patch.FilePath, patch.NewContent); err != nil {
+		return false, err
+	}
+	if runErr == ErrTestTimeout {
+		return true, nil
+	}
+	if runErr != nil {
+		return false, fmt.Errorf("mutant %s:%d: %w", mutant.FilePath, mutant.Line, runErr)
+	}
+
+	c.ctx.Metrics.TotalTestDuration += result.Duration
+	return !result.Passed, nil
+}
@@ -0,0 +1,216 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// MUTANT GENERATION TESTS
+// =============================================================================
+
+const mutationOldGo = `package abs
+
+// Abs returns the absolute value.
+func Abs(x int) int {
+	return x
+}
+
+func Other(a, b int) bool {
+	return a == b
+}
+`
+
+const mutationNewGo = `package abs
+
+// Abs returns the absolute value.
+func Abs(x int) int {
+	if x < 0 { // x < 0 means negative
+		return 0 - x
+	}
+	return x
+}
+
+func Other(a, b int) bool {
+	return a == b
+}
+`
+
+func TestGenerateMutants(t *testing.T) {
+	patch := &Patch{FilePath: "abs.go", OldContent: mutationOldGo, NewContent: mutationNewGo}
+
+	mutants := GenerateMutants("go", patch, 0)
+
+	var got []string
+	for _, m := range mutants {
+		got = append(got, m.Operator+": "+m.Mutated)
+	}
+	want := []string{
+		"negate condition: if !(x < 0) { // x < 0 means negative",
+		"< -> >=: if x >= 0 { // x < 0 means negative",
+		"- -> +: return 0 + x",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mutants = %q, want %q", got, want)
+	}
+	if mutants[0].Line != 5 || mutants[2].Line != 6 {
+		t.Errorf("lines = %d, %d, want 5, 6", mutants[0].Line, mutants[2].Line)
+	}
+	if !strings.Contains(mutants[2].content, "return 0 + x") || !strings.Contains(mutants[2].content, "return a == b") {
+		t.Error("mutant content should change only the mutated line")
+	}
+
+	if limited := GenerateMutants("go", patch, 1); len(limited) != 1 {
+		t.Errorf("limit 1 returned %d mutants", len(limited))
+	}
+}
+
+func TestGenerateMutants_Languages(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		content  string
+		want     []string
+	}{
+		{
+			name:     "python",
+			language: "python",
+			content:  "def ok(a, b):\n    if a and b:\n        return \"a - b\"\n",
+			want:     []string{"if not (a and b):", "if a or b:"},
+		},
+		{
+			name:     "typescript",
+			language: "typescript",
+			content:  "function ok(a: number): boolean {\n  if (a === 1) {\n    return true;\n  }\n  return false;\n}\n",
+			want:     []string{"if (!(a === 1)) {", "if (a !== 1) {"},
+		},
+		{
+			name:     "rust if let",
+			language: "rust",
+			content:  "fn ok(a: Option<i32>) -> i32 {\n    if let Some(v) = a {\n        return v + 1;\n    }\n    0\n}\n",
+			want:     []string{"return v - 1;"},
+		},
+		{
+			name:     "go init statement",
+			language: "go",
+			content:  "func ok() {\n\tif err := run(); err != nil {\n\t}\n}\n",
+			want:     []string{"if err := run(); err == nil {"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutants := GenerateMutants(tt.language, &Patch{FilePath: "f", NewContent: tt.content}, 0)
+			var got []string
+			for _, m := range mutants {
+				got = append(got, m.Mutated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mutants = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateMutants_NoChanges(t *testing.T) {
+	patch := &Patch{FilePath: "abs.go", OldContent: mutationNewGo, NewContent: mutationNewGo}
+	if mutants := GenerateMutants("go", patch, 0); len(mutants) != 0 {
+		t.Errorf("unchanged file produced %d mutants", len(mutants))
+	}
+	if mutants := GenerateMutants("go", nil, 0); mutants != nil {
+		t.Error("nil patch produced mutants")
+	}
+}
+
+// =============================================================================
+// MUTATION TESTING TESTS
+// =============================================================================
+
+func TestController_Regression_MutationTesting(t *testing.T) {
+	tests := []struct {
+		name      string
+		check     string // shell command that passes only on the correct fix
+		minKilled int
+		wantState State
+		wantErr   error
+		survivors int
+	}{
+		{"strong test", "grep -qF 'if x < 0 {' abs.go && grep -qF 'return 0 - x' abs.go", 3, StateDone, nil, 0},
+		{"weak test", "grep -qF 'return' abs.go", 1, StateFailed, ErrWeakReproducerTest, 3},
+		{"weak test without threshold", "grep -qF 'return' abs.go", 0, StateDone, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, "abs.go")
+			if err := os.WriteFile(target, []byte(mutationOldGo), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			configs := NewLanguageConfigRegistry()
+			configs.Register(&LanguageConfig{
+				Language:    "go",
+				TestCommand: "true",
+			})
+			configs.Register(&LanguageConfig{
+				Language:    "sh",
+				TestCommand: "sh",
+				TestArgs:    []string{"-c", tt.check},
+			})
+			cfg := NewConfig(WithMinMutantsKilled(tt.minKilled))
+			runner := NewTestRunner(cfg, nil)
+			runner.configs = configs
+			runner.SetWorkingDir(dir)
+
+			files := NewFileManager(dir, nil)
+			patch := &Patch{FilePath: "abs.go", NewContent: mutationNewGo}
+			if err := files.ApplyPatch(patch); err != nil {
+				t.Fatal(err)
+			}
+
+			c := NewController(cfg, runner, files, nil, nil)
+			c.ctx = NewContext("s", &Request{BugDescription: "d", ProjectRoot: dir, Language: "go"})
+			c.ctx.ReproducerTest = &TestCase{Name: "TestAbs", FilePath: "abs_test.go", Content: "x", Language: "sh"}
+			c.ctx.AppliedPatches = []*Patch{patch}
+
+			err := c.stepRegression(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("stepRegression() error = %v, want %v", err, tt.wantErr)
+			}
+			if c.ctx.State != tt.wantState {
+				t.Errorf("state = %v, want %v", c.ctx.State, tt.wantState)
+			}
+
+			report := c.buildResult().Mutation
+			if report == nil {
+				t.Fatal("result has no mutation report")
+			}
+			if report.Total != 3 || len(report.Survivors) != tt.survivors {
+				t.Errorf("report = %+v, want 3 mutants and %d survivors", report, tt.survivors)
+			}
+
+			content, _ := os.ReadFile(target)
+			want := mutationNewGo
+			if tt.wantState == StateFailed {
+				want = mutationOldGo
+			}
+			if string(content) != want {
+				t.Errorf("file content after mutation testing:\n%s", content)
+			}
+		})
+	}
+}
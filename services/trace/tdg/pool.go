@@ -0,0 +1,400 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/workspace"
+)
+
+// =============================================================================
+// SESSION POOL TYPES
+// =============================================================================
+
+// DefaultPoolConcurrency is the default number of TDG sessions run at once.
+const DefaultPoolConcurrency = 2
+
+// ControllerFactory builds the Controller for one pooled session.
+//
+// req.ProjectRoot is the session's worktree; the runner and file manager
+// must be rooted there. The factory is called from several goroutines.
+type ControllerFactory func(req *Request) *Controller
+
+// NewControllerFactory returns a factory that builds a controller with its
+// own runner, file manager, and test generator per session.
+//
+// Inputs:
+//
+//	cfg - TDG configuration shared by all sessions
+//	llm - LLM client shared by all sessions; must be safe for concurrent use
+//	assembler - Context assembler shared by all sessions (may be nil)
+//	logger - Logger for structured logging
+//
+// Outputs:
+//
+//	ControllerFactory - The factory
+func NewControllerFactory(cfg *Config, llm LLMClient, assembler ContextAssembler, logger *slog.Logger) ControllerFactory {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return func(req *Request) *Controller {
+		runner := NewTestRunner(cfg, logger)
+		runner.SetWorkingDir(req.ProjectRoot)
+		files := NewFileManager(req.ProjectRoot, logger)
+		gen := NewTestGenerator(llm, assembler, logger)
+		return NewController(cfg, runner, files, gen, logger)
+	}
+}
+
+// PoolConfig configures a SessionPool.
+type PoolConfig struct {
+	// MaxConcurrent is the number of sessions run at once.
+	// Default: DefaultPoolConcurrency
+	MaxConcurrent int
+
+	// Workspace configures the per-session worktrees.
+	Workspace workspace.Config
+}
+
+// PoolResult is the outcome of one pooled session.
+type PoolResult struct {
+	// SessionID identifies the session and its worktree.
+	SessionID string `json:"session_id"`
+
+	// Request is the submitted request, with the user's project root.
+	Request *Request `json:"request"`
+
+	// Result is the TDG result. Nil if the session could not run.
+	Result *Result `json:"result,omitempty"`
+
+	// Diff is the change the session made (reproducer test and fix)
+	// relative to the user's tree. Empty unless the session succeeded.
+	Diff string `json:"diff,omitempty"`
+
+	// Merged indicates Diff was applied to the user's tree.
+	Merged bool `json:"merged"`
+
+	// Error is the message of Err.
+	Error string `json:"error,omitempty"`
+
+	// Err is why the session did not run or its fix was not merged.
+	// A TDG run that completed without a fix leaves Err nil; see Result.
+	Err error `json:"-"`
+}
+
+// fail records err on the result.
+func (r *PoolResult) fail(err error) {
+	r.Err = err
+	r.Error = err.Error()
+}
+
+// PoolSession is a handle on a submitted session.
+type PoolSession struct {
+	id     string
+	done   chan struct{}
+	result *PoolResult
+}
+
+// ID returns the session ID.
+func (s *PoolSession) ID() string {
+	return s.id
+}
+
+// Done is closed when the session has finished, including its merge.
+func (s *PoolSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait blocks until the session has finished.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation of the wait (not the session)
+//
+// Outputs:
+//
+//	*PoolResult - The session outcome
+//	error - Non-nil if ctx ended first
+func (s *PoolSession) Wait(ctx context.Context) (*PoolResult, error) {
+	select {
+	case <-s.done:
+		return s.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// =============================================================================
+// SESSION POOL
+// =============================================================================
+
+// SessionPool runs several TDG sessions on one repository in parallel.
+//
+// Description:
+//
+//	Each session runs in its own git worktree (see package workspace), so
+//	controllers never touch the same files. At most MaxConcurrent sessions
+//	run at once; the rest wait for a slot. When a session succeeds, its
+//	change is merged into the user's tree. Merges are serialized, and a
+//	fix that no longer applies because an earlier merge touched the same
+//	lines is reported with ErrMergeConflict instead of being merged.
+//
+// Thread Safety: Safe for concurrent use.
+type SessionPool struct {
+	workspaces *workspace.Manager
+	factory    ControllerFactory
+	slots      chan struct{}
+	logger     *slog.Logger
+
+	// mergeMu serializes merges into the user's tree.
+	mergeMu sync.Mutex
+
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup
+}
+
+// NewSessionPool creates a session pool for a repository.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	repoRoot - Absolute path inside the user's git repository
+//	factory - Builds the controller for each session
+//	cfg - Pool configuration
+//	logger - Logger for structured logging
+//
+// Outputs:
+//
+//	*SessionPool - The pool
+//	error - Non-nil if repoRoot is not a git repository
+func NewSessionPool(ctx context.Context, repoRoot string, factory ControllerFactory, cfg PoolConfig, logger *slog.Logger) (*SessionPool, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("controller factory must not be nil")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = DefaultPoolConcurrency
+	}
+
+	mgr, err := workspace.NewManager(ctx, repoRoot, cfg.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionPool{
+		workspaces: mgr,
+		factory:    factory,
+		slots:      make(chan struct{}, cfg.MaxConcurrent),
+		logger:     logger.With(slog.String("component", "tdg_pool")),
+	}, nil
+}
+
+// Submit schedules a TDG session.
+//
+// Description:
+//
+//	The session starts once a slot is free. req.ProjectRoot must be inside
+//	the pool's repository; the session runs on the same directory inside
+//	its worktree.
+//
+// Inputs:
+//
+//	ctx - Context for the session; canceling it stops the session
+//	req - The TDG request
+//
+// Outputs:
+//
+//	*PoolSession - Handle to wait for the outcome
+//	error - Non-nil if the request is invalid or the pool is closed
+func (p *SessionPool) Submit(ctx context.Context, req *Request) (*PoolSession, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if req == nil {
+		return nil, ErrEmptyRequest
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	rel, err := p.relativeRoot(req.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	sess := &PoolSession{
+		id:   "tdg-" + uuid.NewString()[:8],
+		done: make(chan struct{}),
+	}
+	sess.result = &PoolResult{SessionID: sess.id, Request: req}
+
+	p.running.Add(1)
+	go p.run(ctx, sess, req, rel)
+	return sess, nil
+}
+
+// Run submits every request and waits for all sessions to finish.
+//
+// Inputs:
+//
+//	ctx - Context for the sessions
+//	reqs - The TDG requests
+//
+// Outputs:
+//
+//	[]*PoolResult - One result per request, in request order
+func (p *SessionPool) Run(ctx context.Context, reqs []*Request) []*PoolResult {
+	sessions := make([]*PoolSession, len(reqs))
+	results := make([]*PoolResult, len(reqs))
+	for i, req := range reqs {
+		sess, err := p.Submit(ctx, req)
+		if err != nil {
+			results[i] = &PoolResult{Request: req}
+			results[i].fail(err)
+			continue
+		}
+		sessions[i] = sess
+	}
+	for i, sess := range sessions {
+		if sess != nil {
+			<-sess.Done()
+			results[i] = sess.result
+		}
+	}
+	return results
+}
+
+// Close waits for running sessions and removes all worktrees. Submit
+// fails with ErrPoolClosed afterwards.
+func (p *SessionPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	p.running.Wait()
+	return p.workspaces.Close(ctx)
+}
+
+// run executes one session and merges its change.
+func (p *SessionPool) run(ctx context.Context, sess *PoolSession, req *Request, rel string) {
+	defer p.running.Done()
+	defer close(sess.done)
+	res := sess.result
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		res.fail(ctx.Err())
+		return
+	}
+	result, diff, err := p.runIsolated(ctx, sess.id, req, rel)
+	<-p.slots
+
+	res.Result = result
+	if err != nil {
+		res.fail(err)
+		p.logger.Warn("TDG session failed to run",
+			slog.String("session_id", sess.id),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if !result.Success || diff == "" {
+		return
+	}
+	res.Diff = diff
+
+	p.mergeMu.Lock()
+	err = p.workspaces.ApplyToRepo(ctx, diff)
+	p.mergeMu.Unlock()
+	if err != nil {
+		res.fail(fmt.Errorf("%w: %v", ErrMergeConflict, err))
+		p.logger.Warn("TDG fix not merged",
+			slog.String("session_id", sess.id),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	res.Merged = true
+	p.logger.Info("TDG fix merged",
+		slog.String("session_id", sess.id),
+		slog.Int("diff_bytes", len(diff)),
+	)
+}
+
+// runIsolated runs the controller in the session's worktree and returns
+// the TDG result and, on success, the worktree diff. The worktree is
+// removed afterwards.
+func (p *SessionPool) runIsolated(ctx context.Context, id string, req *Request, rel string) (*Result, string, error) {
+	ws, err := p.workspaces.Acquire(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := p.workspaces.Release(context.WithoutCancel(ctx), id); err != nil {
+			p.logger.Warn("Releasing TDG worktree failed",
+				slog.String("session_id", id),
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	var result *Result
+	var diff string
+	err = ws.Use(ctx, func(w *workspace.Workspace) error {
+		sessionReq := *req
+		sessionReq.ProjectRoot = filepath.Join(w.Path(), rel)
+
+		var runErr error
+		result, runErr = p.factory(&sessionReq).Run(ctx, &sessionReq)
+		if result == nil {
+			return runErr
+		}
+		if !result.Success {
+			return nil
+		}
+		diff, runErr = w.Diff(ctx)
+		return runErr
+	})
+	return result, diff, err
+}
+
+// relativeRoot returns projectRoot relative to the repository top level.
+func (p *SessionPool) relativeRoot(projectRoot string) (string, error) {
+	top := p.workspaces.RepoRoot()
+	if resolved, err := filepath.EvalSymlinks(projectRoot); err == nil {
+		projectRoot = resolved
+	}
+	rel, err := filepath.Rel(top, projectRoot)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is not in %s", ErrOutsideRepository, projectRoot, top)
+	}
+	return rel, nil
+}
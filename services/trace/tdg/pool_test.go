@@ -0,0 +1,182 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/workspace"
+)
+
+// =============================================================================
+// SESSION POOL TESTS
+// =============================================================================
+
+// poolFix describes the scripted LLM output of one pooled session: the
+// reproducer greps target for want, and the fix writes want to target.
+type poolFix struct {
+	target string
+	want   string
+}
+
+// setupPoolRepo creates a git repository with broken a.txt and b.txt.
+func setupPoolRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("broken\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+		{"add", "."},
+		{"commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+// newTestPool creates a pool whose sessions answer from fixes, keyed by
+// bug description, and run tests with sh.
+func newTestPool(t *testing.T, repo string, fixes map[string]poolFix) *SessionPool {
+	t.Helper()
+	configs := NewLanguageConfigRegistry()
+	configs.Register(&LanguageConfig{
+		Language:    "sh",
+		TestCommand: "sh",
+		TestArgs:    []string{"{file}"},
+		SuiteArgs:   []string{"-c", "true"},
+	})
+	cfg := NewConfig(WithMutationTesting(false))
+
+	factory := func(req *Request) *Controller {
+		fix := fixes[req.BugDescription]
+		llm := &mockLLMClient{responses: []string{
+			"TEST_FILE: check_" + req.BugDescription + ".sh\n```sh\ngrep -qx '" + fix.want + "' " + fix.target + "\n```\n",
+			"FIX_FILE: " + fix.target + "\n```\n" + fix.want + "\n```\n",
+		}}
+		runner := NewTestRunner(cfg, nil)
+		runner.configs = configs
+		runner.SetWorkingDir(req.ProjectRoot)
+		return NewController(cfg, runner, NewFileManager(req.ProjectRoot, nil), NewTestGenerator(llm, nil, nil), nil)
+	}
+
+	pool, err := NewSessionPool(context.Background(), repo, factory, PoolConfig{
+		Workspace: workspace.Config{BaseDir: filepath.Join(t.TempDir(), "ws")},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewSessionPool() error = %v", err)
+	}
+	t.Cleanup(func() { _ = pool.Close(context.Background()) })
+	return pool
+}
+
+func TestSessionPool_Run(t *testing.T) {
+	repo := setupPoolRepo(t)
+	pool := newTestPool(t, repo, map[string]poolFix{
+		"a": {target: "a.txt", want: "fixed a"},
+		"b": {target: "b.txt", want: "fixed b"},
+	})
+
+	results := pool.Run(context.Background(), []*Request{
+		{BugDescription: "a", ProjectRoot: repo, Language: "sh"},
+		{BugDescription: "b", ProjectRoot: repo, Language: "sh"},
+	})
+
+	for i, res := range results {
+		if res.Err != nil || res.Result == nil || !res.Result.Success || !res.Merged {
+			t.Fatalf("result %d = %+v (tdg %+v), want a merged fix", i, res, res.Result)
+		}
+		if res.SessionID == results[1-i].SessionID {
+			t.Error("sessions share an ID")
+		}
+	}
+	for file, want := range map[string]string{
+		"a.txt":      "fixed a",
+		"b.txt":      "fixed b",
+		"check_a.sh": "grep -qx 'fixed a' a.txt",
+		"check_b.sh": "grep -qx 'fixed b' b.txt",
+	} {
+		got, err := os.ReadFile(filepath.Join(repo, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s in user tree = %q, %v, want %q", file, got, err, want)
+		}
+	}
+}
+
+func TestSessionPool_MergeConflict(t *testing.T) {
+	repo := setupPoolRepo(t)
+	pool := newTestPool(t, repo, map[string]poolFix{
+		"one": {target: "a.txt", want: "fixed one way"},
+		"two": {target: "a.txt", want: "fixed another way"},
+	})
+
+	results := pool.Run(context.Background(), []*Request{
+		{BugDescription: "one", ProjectRoot: repo, Language: "sh"},
+		{BugDescription: "two", ProjectRoot: repo, Language: "sh"},
+	})
+
+	var merged, conflicts int
+	for _, res := range results {
+		if res.Result == nil || !res.Result.Success {
+			t.Fatalf("result = %+v, want a successful TDG run", res)
+		}
+		switch {
+		case res.Merged:
+			merged++
+		case errors.Is(res.Err, ErrMergeConflict):
+			conflicts++
+			if res.Diff == "" {
+				t.Error("conflicting result has no diff")
+			}
+		}
+	}
+	if merged != 1 || conflicts != 1 {
+		t.Errorf("merged = %d, conflicts = %d, want 1 and 1", merged, conflicts)
+	}
+}
+
+func TestSessionPool_Submit_Errors(t *testing.T) {
+	repo := setupPoolRepo(t)
+	pool := newTestPool(t, repo, nil)
+	ctx := context.Background()
+
+	if _, err := pool.Submit(ctx, &Request{BugDescription: "a", ProjectRoot: t.TempDir(), Language: "sh"}); !errors.Is(err, ErrOutsideRepository) {
+		t.Errorf("Submit() outside repo error = %v, want ErrOutsideRepository", err)
+	}
+	if _, err := pool.Submit(ctx, &Request{ProjectRoot: repo, Language: "sh"}); !errors.Is(err, ErrEmptyRequest) {
+		t.Errorf("Submit() empty request error = %v, want ErrEmptyRequest", err)
+	}
+
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := pool.Submit(ctx, &Request{BugDescription: "a", ProjectRoot: repo, Language: "sh"}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit() after close error = %v, want ErrPoolClosed", err)
+	}
+}
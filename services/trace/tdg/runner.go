@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -87,7 +88,7 @@ func (r *TestRunner) RunTest(ctx context.Context, tc *TestCase) (*TestResult, er
 		return nil, err
 	}
 
-	langCfg, ok := r.configs.Get(tc.Language)
+	langCfg, ok := r.configs.Resolve(tc.Language, r.workingDir)
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, tc.Language)
 	}
@@ -152,7 +153,7 @@ func (r *TestRunner) RunSuite(ctx context.Context, language, packagePath string)
 		return nil, ErrNilContext
 	}
 
-	langCfg, ok := r.configs.Get(language)
+	langCfg, ok := r.configs.Resolve(language, r.workingDir)
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
 	}
@@ -194,6 +195,158 @@ func (r *TestRunner) RunSuite(ctx context.Context, language, packagePath string)
 	return result, err
 }
 
+// RunNamed executes existing tests by name in one invocation.
+//
+// Description:
+//
+//	Joins the names into the {name} argument of the language's single-test
+//	command (see testNamePatterns) so the regression check can run only
+//	the tests that exercise a fix instead of the full suite.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	language - The programming language
+//	names - Test function names. Must not be empty.
+//	filePath - The test file, substituted for {file}
+//	packagePath - The package or directory path, substituted for {package}
+//
+// Outputs:
+//
+//	*TestResult - Execution result with all failures
+//	error - ErrUnsupportedLanguage if the language cannot select tests by
+//	        name; non-nil on execution failure
+//
+// Thread Safety: Safe for concurrent use.
+func (r *TestRunner) RunNamed(ctx context.Context, language string, names []string, filePath, packagePath string) (*TestResult, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	pattern, ok := testNamePatterns[language]
+	langCfg, resolved := r.configs.Resolve(language, r.workingDir)
+	if !ok || !resolved || len(names) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
+	}
+
+	start := time.Now()
+	r.logger.Debug("Running named tests",
+		slog.String("language", language),
+		slog.String("file", filePath),
+		slog.Any("tests", names),
+	)
+
+	args := r.substituteArgs(langCfg.TestArgs, pattern(names), filePath, packagePath)
+	result, err := r.execute(ctx, langCfg.TestCommand, args, r.suiteTimeout)
+
+	duration := time.Since(start)
+	result.Duration = duration
+
+	parser := GetTestOutputParser(language)
+	if parser != nil {
+		passed, failedTests := parser([]byte(result.Output))
+		result.Passed = passed
+		result.FailedTests = failedTests
+	} else {
+		result.Passed = result.ExitCode == 0
+	}
+
+	r.logger.Info("Named tests completed",
+		slog.String("language", language),
+		slog.String("file", filePath),
+		slog.Int("tests", len(names)),
+		slog.Bool("passed", result.Passed),
+		slog.Duration("duration", duration),
+		slog.Int("exit_code", result.ExitCode),
+	)
+
+	return result, err
+}
+
+// testNamePatterns join test function names into the {name} argument of a
+// language's single-test command. Languages without an entry cannot run a
+// selection of tests and fall back to the full suite.
+var testNamePatterns = map[string]func(names []string) string{
+	// go test -run takes a regular expression.
+	"go": func(names []string) string { return "^(" + strings.Join(names, "|") + ")$" },
+	// pytest -k takes a boolean expression of substrings.
+	"python": func(names []string) string { return strings.Join(names, " or ") },
+}
+
+// RunCoverage runs the test suite with coverage collection.
+//
+// Description:
+//
+//	Runs the language's CoverageArgs, which write a profile to the
+//	{coverage} placeholder path, and parses it with the language's
+//	CoverageFormat. Failing tests do not prevent collection: the tools
+//	write the profile either way.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	language - The programming language
+//	packagePath - The package or directory path
+//
+// Outputs:
+//
+//	CoverageProfile - Line coverage per file
+//	*TestResult - The suite execution result (may be nil on setup errors)
+//	error - ErrCoverageUnavailable if the language has no coverage support
+//	        or no profile was written
+//
+// Thread Safety: Safe for concurrent use.
+func (r *TestRunner) RunCoverage(ctx context.Context, language, packagePath string) (CoverageProfile, *TestResult, error) {
+	if ctx == nil {
+		return nil, nil, ErrNilContext
+	}
+
+	langCfg, ok := r.configs.Resolve(language, r.workingDir)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
+	}
+	if len(langCfg.CoverageArgs) == 0 || langCfg.CoverageFormat == "" {
+		return nil, nil, fmt.Errorf("%w: not configured for %s", ErrCoverageUnavailable, language)
+	}
+
+	profileFile, err := os.CreateTemp("", "tdg-coverage-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrCoverageUnavailable, err)
+	}
+	profilePath := profileFile.Name()
+	_ = profileFile.Close()
+	defer os.Remove(profilePath)
+
+	args := r.substituteArgs(langCfg.CoverageArgs, "", "", packagePath)
+	for i := range args {
+		args[i] = strings.ReplaceAll(args[i], "{coverage}", profilePath)
+	}
+
+	start := time.Now()
+	result, err := r.execute(ctx, langCfg.TestCommand, args, r.suiteTimeout)
+	result.Duration = time.Since(start)
+	if err != nil {
+		return nil, result, err
+	}
+
+	data, err := os.ReadFile(profilePath)
+	if err != nil || len(data) == 0 {
+		return nil, result, fmt.Errorf("%w: no profile written (exit code %d)", ErrCoverageUnavailable, result.ExitCode)
+	}
+	profile, err := ParseCoverage(langCfg.CoverageFormat, data)
+	if err != nil {
+		return nil, result, err
+	}
+
+	r.logger.Info("Coverage collected",
+		slog.String("language", language),
+		slog.Int("files", len(profile)),
+		slog.Duration("duration", result.Duration),
+	)
+
+	return profile, result, nil
+}
+
 // execute runs a command with timeout and output capture.
 func (r *TestRunner) execute(ctx context.Context, command string, args []string, timeout time.Duration) (*TestResult, error) {
 	// Apply timeout
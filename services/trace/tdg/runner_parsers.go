@@ -40,6 +40,8 @@ var (
 		"python":     parsePytestOutput,
 		"typescript": parseJestOutput,
 		"javascript": parseJestOutput,
+		"rust":       parseCargoTestOutput,
+		"java":       parseJavaTestOutput,
 	}
 	parserMu sync.RWMutex
 )
@@ -271,6 +273,147 @@ func parseJestOutput(output []byte) (passed bool, failedTests []string) {
 	return passed, failedTests
 }
 
+// =============================================================================
+// CARGO TEST OUTPUT PARSER
+// =============================================================================
+
+// Cargo test output patterns
+var (
+	cargoFailPattern        = regexp.MustCompile(`^test (\S+) \.\.\. FAILED$`)
+	cargoResultFailPattern  = regexp.MustCompile(`^test result: FAILED\.`)
+	cargoCompileErrPattern  = regexp.MustCompile(`^error(\[E\d+\])?:`)
+	cargoTestTargetsPattern = regexp.MustCompile(`^error: test failed`)
+)
+
+// parseCargoTestOutput parses `cargo test` output.
+//
+// Description:
+//
+//	Extracts test results from cargo test output. Looks for:
+//	  - "test path::name ... FAILED" for failed tests (panics included)
+//	  - "test result: FAILED." summary lines
+//	  - "error:" / "error[E0425]:" compiler errors (treated as failure)
+//
+// Inputs:
+//
+//	output - Raw stdout/stderr from cargo test
+//
+// Outputs:
+//
+//	passed - True if no failures or compile errors
+//	failedTests - Names of failed tests
+func parseCargoTestOutput(output []byte) (passed bool, failedTests []string) {
+	lines := strings.Split(string(output), "\n")
+	failedTests = make([]string, 0)
+	hasFailure := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Check for failed test
+		if matches := cargoFailPattern.FindStringSubmatch(line); len(matches) > 1 {
+			failedTests = append(failedTests, matches[1])
+			hasFailure = true
+		}
+
+		// Check for failed summary, compile errors, and failed test targets
+		if cargoResultFailPattern.MatchString(line) ||
+			cargoCompileErrPattern.MatchString(line) ||
+			cargoTestTargetsPattern.MatchString(line) {
+			hasFailure = true
+		}
+	}
+
+	passed = !hasFailure
+	return passed, failedTests
+}
+
+// =============================================================================
+// JAVA (MAVEN / GRADLE) OUTPUT PARSER
+// =============================================================================
+
+// Maven Surefire and Gradle output patterns
+var (
+	surefireFailPattern    = regexp.MustCompile(`^\[ERROR\]\s+(\S+).*<<< (FAILURE|ERROR)!`)
+	surefireSummaryPattern = regexp.MustCompile(`Tests run: \d+, Failures: (\d+), Errors: (\d+)`)
+	gradleFailPattern      = regexp.MustCompile(`^(\S+) > (.+) FAILED$`)
+	javaBuildFailPattern   = regexp.MustCompile(`^(\[(INFO|ERROR)\] )?BUILD (FAILURE|FAILED)`)
+)
+
+// parseJavaTestOutput parses Maven Surefire and Gradle test output.
+//
+// Description:
+//
+//	Extracts test results from `mvn test` or `gradle test` output. Looks for:
+//	  - "[ERROR] Class.method ... <<< FAILURE!" for failed Surefire tests
+//	  - "Class > method() FAILED" for failed Gradle tests
+//	  - "Tests run: N, Failures: F, Errors: E" with F or E non-zero
+//	  - "BUILD FAILURE" / "BUILD FAILED" (compile errors included)
+//
+//	Failed tests are reported as "Class.method", without parameters.
+//
+// Inputs:
+//
+//	output - Raw stdout/stderr from mvn or gradle
+//
+// Outputs:
+//
+//	passed - True if no failures, errors, or build failure
+//	failedTests - Names of failed tests
+func parseJavaTestOutput(output []byte) (passed bool, failedTests []string) {
+	lines := strings.Split(string(output), "\n")
+	failedTests = make([]string, 0)
+	seen := make(map[string]bool)
+	hasFailure := false
+
+	addFailed := func(name string) {
+		if idx := strings.Index(name, "("); idx != -1 {
+			// JUnit 4 Surefire reports "method(Class)"
+			if class := strings.TrimSuffix(name[idx+1:], ")"); class != "" && !strings.Contains(name[:idx], ".") {
+				name = class + "." + name[:idx]
+			} else {
+				name = name[:idx]
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			failedTests = append(failedTests, name)
+		}
+		hasFailure = true
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Surefire per-test failure (class summaries also end in "<<< FAILURE!")
+		if matches := surefireFailPattern.FindStringSubmatch(line); len(matches) > 1 && !strings.Contains(line, "Tests run:") {
+			addFailed(matches[1])
+			continue
+		}
+
+		// Gradle per-test failure
+		if matches := gradleFailPattern.FindStringSubmatch(line); len(matches) > 2 {
+			addFailed(matches[1] + "." + matches[2])
+			continue
+		}
+
+		// Surefire summary with failures or errors
+		if matches := surefireSummaryPattern.FindStringSubmatch(line); len(matches) > 2 {
+			if matches[1] != "0" || matches[2] != "0" {
+				hasFailure = true
+			}
+		}
+
+		// Build failure
+		if javaBuildFailPattern.MatchString(line) {
+			hasFailure = true
+		}
+	}
+
+	passed = !hasFailure
+	return passed, failedTests
+}
+
 // =============================================================================
 // UTILITY FUNCTIONS
 // =============================================================================
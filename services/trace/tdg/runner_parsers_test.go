@@ -317,6 +317,146 @@ Tests:       1 failed, 0 passed, 1 total`,
 	}
 }
 
+// =============================================================================
+// CARGO TEST OUTPUT PARSER TESTS
+// =============================================================================
+
+func TestParseCargoTestOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantPassed bool
+		wantFailed []string
+	}{
+		{
+			name: "all tests pass",
+			output: `running 2 tests
+test parse_empty ... ok
+test parse_negative ... ok
+
+test result: ok. 2 passed; 0 failed; 0 ignored; 0 measured; 0 filtered out; finished in 0.00s`,
+			wantPassed: true,
+			wantFailed: []string{},
+		},
+		{
+			name: "test panics",
+			output: `running 2 tests
+test parse_empty ... ok
+test tests::parse_negative ... FAILED
+
+failures:
+
+---- tests::parse_negative stdout ----
+thread 'tests::parse_negative' panicked at tests/reproducer.rs:9:5:
+assertion ` + "`left == right`" + ` failed
+
+failures:
+    tests::parse_negative
+
+test result: FAILED. 1 passed; 1 failed; 0 ignored; 0 measured; 0 filtered out; finished in 0.00s
+
+error: test failed, to rerun pass ` + "`--test reproducer`",
+			wantPassed: false,
+			wantFailed: []string{"tests::parse_negative"},
+		},
+		{
+			name: "compile error",
+			output: `   Compiling my_crate v0.1.0 (/p)
+error[E0425]: cannot find function ` + "`parse`" + ` in this scope
+ --> tests/reproducer.rs:5:13
+error: could not compile ` + "`my_crate`" + ` (test "reproducer") due to 1 previous error`,
+			wantPassed: false,
+			wantFailed: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, failed := parseCargoTestOutput([]byte(tt.output))
+			if passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", passed, tt.wantPassed)
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// JAVA OUTPUT PARSER TESTS
+// =============================================================================
+
+func TestParseJavaTestOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantPassed bool
+		wantFailed []string
+	}{
+		{
+			name: "maven all pass",
+			output: `[INFO] Running com.example.ParserTest
+[INFO] Tests run: 2, Failures: 0, Errors: 0, Skipped: 0, Time elapsed: 0.03 s -- in com.example.ParserTest
+[INFO] BUILD SUCCESS`,
+			wantPassed: true,
+			wantFailed: []string{},
+		},
+		{
+			name: "maven junit5 failure",
+			output: `[INFO] Running com.example.ParserTest
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.04 s <<< FAILURE! -- in com.example.ParserTest
+[ERROR] com.example.ParserTest.parseNegative -- Time elapsed: 0.01 s <<< FAILURE!
+org.opentest4j.AssertionFailedError: expected: <1> but was: <-1>
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0
+[ERROR] BUILD FAILURE`,
+			wantPassed: false,
+			wantFailed: []string{"com.example.ParserTest.parseNegative"},
+		},
+		{
+			name: "maven junit4 error",
+			output: `[ERROR] parseEmpty(com.example.ParserTest)  Time elapsed: 0.01 s  <<< ERROR!
+java.lang.NullPointerException
+[ERROR] Tests run: 1, Failures: 0, Errors: 1, Skipped: 0`,
+			wantPassed: false,
+			wantFailed: []string{"com.example.ParserTest.parseEmpty"},
+		},
+		{
+			name: "gradle failure",
+			output: `> Task :test FAILED
+
+ParserTest > parseNegative() FAILED
+    org.opentest4j.AssertionFailedError at ParserTest.java:12
+
+2 tests completed, 1 failed
+
+FAILURE: Build failed with an exception.
+BUILD FAILED in 3s`,
+			wantPassed: false,
+			wantFailed: []string{"ParserTest.parseNegative"},
+		},
+		{
+			name: "compile error",
+			output: `[ERROR] /p/src/test/java/ParserTest.java:[5,9] cannot find symbol
+[INFO] BUILD FAILURE`,
+			wantPassed: false,
+			wantFailed: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, failed := parseJavaTestOutput([]byte(tt.output))
+			if passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", passed, tt.wantPassed)
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // PARSER REGISTRY TESTS
 // =============================================================================
@@ -330,7 +470,9 @@ func TestGetTestOutputParser(t *testing.T) {
 		{"python", false},
 		{"typescript", false},
 		{"javascript", false},
-		{"rust", true},
+		{"rust", false},
+		{"java", false},
+		{"ruby", true},
 		{"unknown", true},
 		{"", true},
 	}
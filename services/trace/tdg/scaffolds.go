@@ -0,0 +1,97 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"strings"
+)
+
+// =============================================================================
+// REPRODUCER SCAFFOLDS
+// =============================================================================
+
+// Reproducer scaffolds show the LLM the expected shape of a test file for
+// each framework: imports, naming, and where the failing assertion goes.
+// The runner's test filter and output parser rely on these conventions.
+const (
+	goScaffold = `package mypackage
+
+import "testing"
+
+func TestFunctionName_Scenario_ExpectedBehavior(t *testing.T) {
+	// Arrange
+	// Act
+	// Assert
+	if got != want {
+		t.Errorf("FunctionName() = %v, want %v", got, want)
+	}
+}`
+
+	pythonScaffold = `from mypackage import function_name
+
+
+def test_function_name_scenario_expected_behavior():
+    # Arrange
+    # Act
+    # Assert
+    assert got == want`
+
+	jestScaffold = `import { functionName } from './module';
+
+test('functionName scenario expected behavior', () => {
+  // Arrange
+  // Act
+  // Assert
+  expect(got).toEqual(want);
+});`
+
+	rustScaffold = `// tests/reproducer.rs - integration test against the crate's public API
+use my_crate::function_name;
+
+#[test]
+fn function_name_scenario_expected_behavior() {
+    // Arrange
+    // Act
+    // Assert
+    assert_eq!(got, want);
+}`
+
+	javaScaffold = `// src/test/java/<package path>/FunctionNameTest.java (JUnit 5)
+package com.example;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+import org.junit.jupiter.api.Test;
+
+class FunctionNameTest {
+    @Test
+    void functionName_scenario_expectedBehavior() {
+        // Arrange
+        // Act
+        // Assert
+        assertEquals(want, got);
+    }
+}`
+)
+
+// writeScaffold adds the language's reproducer scaffold to a test
+// generation prompt, if it has one.
+func writeScaffold(sb *strings.Builder, language string) {
+	cfg, ok := GetLanguageConfig(language)
+	if !ok || cfg.Scaffold == "" {
+		return
+	}
+	sb.WriteString("\nFollow the structure of this skeleton:\n```")
+	sb.WriteString(language)
+	sb.WriteString("\n")
+	sb.WriteString(cfg.Scaffold)
+	sb.WriteString("\n```\n")
+}
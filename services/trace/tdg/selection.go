@@ -0,0 +1,173 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// REGRESSION TEST SELECTION
+// =============================================================================
+
+// SetTestMapping supplies the code graph and the test mapping built from it.
+//
+// Description:
+//
+//	With both set, the regression check runs only the existing tests the
+//	mapping links to the functions the fix changed, instead of the full
+//	suite. The graph must describe the project before the fix: changed
+//	functions are found by the line ranges of its symbols.
+//
+// Inputs:
+//
+//	g - The code graph the mapping was built from. Nil disables selection.
+//	m - The test mapping (see analysis.BuildTestMapping). Nil disables selection.
+func (c *Controller) SetTestMapping(g *graph.Graph, m *analysis.TestMapping) {
+	c.graph = g
+	c.tests = m
+}
+
+// testGroup is a set of tests run in one invocation: the tests of one Go
+// package or one test file.
+type testGroup struct {
+	file    string
+	pkg     string
+	names   []string
+	linkIDs map[string]bool
+}
+
+// runRegressionTests runs the tests linked to the changed functions, or the
+// full suite when no test can be selected.
+func (c *Controller) runRegressionTests(ctx context.Context) (*TestResult, error) {
+	language := c.ctx.Request.Language
+	groups := c.selectRegressionTests()
+	if len(groups) == 0 {
+		c.ctx.Metrics.TestsRun++
+		return c.runner.RunSuite(ctx, language, ".")
+	}
+
+	combined := &TestResult{Passed: true}
+	for _, group := range groups {
+		c.logger.Debug("Running mapped regression tests",
+			slog.String("file", group.file),
+			slog.Any("tests", group.names),
+		)
+		c.ctx.Metrics.TestsRun++
+		result, err := c.runner.RunNamed(ctx, language, group.names, group.file, group.pkg)
+		if result != nil {
+			mergeTestResult(combined, result)
+		}
+		if err != nil {
+			return combined, err
+		}
+	}
+	return combined, nil
+}
+
+// selectRegressionTests returns the tests the test mapping links to the
+// symbols whose lines the applied patches changed, grouped per invocation.
+// Returns nil when there is no mapping, the language cannot run tests by
+// name, or no linked test reaches Config.MinTestLinkConfidence.
+func (c *Controller) selectRegressionTests() []*testGroup {
+	language := c.ctx.Request.Language
+	if c.graph == nil || c.tests == nil {
+		return nil
+	}
+	if _, ok := testNamePatterns[language]; !ok {
+		return nil
+	}
+
+	var groups []*testGroup
+	byKey := make(map[string]*testGroup)
+	for _, patch := range c.ctx.AppliedPatches {
+		first, last, ok := changedOldLines(patch)
+		if !ok {
+			continue
+		}
+		for _, node := range c.graph.GetNodesByFile(patch.FilePath) {
+			sym := node.Symbol
+			if sym.StartLine > last || sym.EndLine < first {
+				continue
+			}
+			for _, link := range c.tests.TestsFor(sym.ID) {
+				if link.Confidence < c.config.MinTestLinkConfidence {
+					continue
+				}
+				dir := filepath.ToSlash(filepath.Dir(link.TestFile))
+				if dir != "." {
+					dir = "./" + dir
+				}
+				key := link.TestFile
+				if language == "go" {
+					key = dir
+				}
+				group, ok := byKey[key]
+				if !ok {
+					group = &testGroup{
+						file:    link.TestFile,
+						pkg:     dir,
+						linkIDs: make(map[string]bool),
+					}
+					byKey[key] = group
+					groups = append(groups, group)
+				}
+				if group.linkIDs[link.TestID] {
+					continue
+				}
+				group.linkIDs[link.TestID] = true
+				group.names = append(group.names, link.TestName)
+			}
+		}
+	}
+	return groups
+}
+
+// changedOldLines returns the 1-based inclusive line range of the patch's
+// original content that the fix replaced. A pure insertion yields the two
+// lines around the insertion point. Returns false for new files, which the
+// graph has no symbols for, and for unchanged content.
+func changedOldLines(patch *Patch) (int, int, bool) {
+	if patch.OldContent == "" || patch.OldContent == patch.NewContent {
+		return 0, 0, false
+	}
+	// With the arguments swapped, the range is reported in the old lines.
+	prefix, end := changedLineRange(strings.Split(patch.NewContent, "\n"), strings.Split(patch.OldContent, "\n"))
+	if end <= prefix {
+		return max(prefix, 1), prefix + 1, true
+	}
+	return prefix + 1, end, true
+}
+
+// mergeTestResult folds the result of one invocation into the combined
+// regression result.
+func mergeTestResult(combined, result *TestResult) {
+	combined.Passed = combined.Passed && result.Passed
+	if combined.Output != "" && result.Output != "" {
+		combined.Output += "\n"
+	}
+	combined.Output += result.Output
+	combined.Duration += result.Duration
+	if combined.ExitCode == 0 {
+		combined.ExitCode = result.ExitCode
+	}
+	combined.FailedTests = append(combined.FailedTests, result.FailedTests...)
+	combined.PassedTests = append(combined.PassedTests, result.PassedTests...)
+	combined.TotalTests += result.TotalTests
+	combined.Truncated = combined.Truncated || result.Truncated
+	combined.TimedOut = combined.TimedOut || result.TimedOut
+}
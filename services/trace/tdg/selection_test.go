@@ -0,0 +1,164 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tdg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// REGRESSION TEST SELECTION TESTS
+// =============================================================================
+
+// createSelectionGraph builds the graph of mutationOldGo (Abs on lines 4-6,
+// Other on lines 8-10) and its tests:
+//
+//	abs_test.go:     TestAbs -> Abs, TestOther -> Other
+//	sub/abs_test.go: TestSubAbs -> Abs
+func createSelectionGraph(t *testing.T) (*graph.Graph, *analysis.TestMapping) {
+	t.Helper()
+
+	g := graph.NewGraph("/test")
+	symbols := []*ast.Symbol{
+		{ID: "abs.go:4:Abs", Name: "Abs", Kind: ast.SymbolKindFunction, FilePath: "abs.go", StartLine: 4, EndLine: 6, Language: "go"},
+		{ID: "abs.go:8:Other", Name: "Other", Kind: ast.SymbolKindFunction, FilePath: "abs.go", StartLine: 8, EndLine: 10, Language: "go"},
+		{ID: "abs_test.go:5:TestAbs", Name: "TestAbs", Kind: ast.SymbolKindFunction, FilePath: "abs_test.go", StartLine: 5, EndLine: 9, Language: "go"},
+		{ID: "abs_test.go:11:TestOther", Name: "TestOther", Kind: ast.SymbolKindFunction, FilePath: "abs_test.go", StartLine: 11, EndLine: 15, Language: "go"},
+		{ID: "sub/abs_test.go:5:TestSubAbs", Name: "TestSubAbs", Kind: ast.SymbolKindFunction, FilePath: "sub/abs_test.go", StartLine: 5, EndLine: 9, Language: "go"},
+	}
+	for _, sym := range symbols {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	calls := [][2]string{
+		{"abs_test.go:5:TestAbs", "abs.go:4:Abs"},
+		{"abs_test.go:11:TestOther", "abs.go:8:Other"},
+		{"sub/abs_test.go:5:TestSubAbs", "abs.go:4:Abs"},
+	}
+	for _, call := range calls {
+		if err := g.AddEdge(call[0], call[1], graph.EdgeTypeCalls, ast.Location{FilePath: "abs_test.go"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+
+	m, err := analysis.BuildTestMapping(context.Background(), g, nil)
+	if err != nil {
+		t.Fatalf("BuildTestMapping failed: %v", err)
+	}
+	return g, m
+}
+
+func TestController_Regression_MappedTests(t *testing.T) {
+	tests := []struct {
+		name      string
+		mapped    bool
+		fail      string // test the fake runner reports as failed
+		wantRuns  []string
+		wantState State
+	}{
+		{"runs only tests linked to the changed function", true, "", []string{"^(TestAbs)$ .", "^(TestSubAbs)$ ./sub"}, StateDone},
+		{"mapped test failure is a regression", true, "TestSubAbs", []string{"^(TestAbs)$ .", "^(TestSubAbs)$ ./sub"}, StateWriteFix},
+		{"full suite without a mapping", false, "", []string{"suite"}, StateDone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "abs.go"), []byte(mutationOldGo), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			report := ""
+			if tt.fail != "" {
+				report = "; case '{name}' in *" + tt.fail + "*) echo '--- FAIL: " + tt.fail + " (0.00s)';; esac"
+			}
+			configs := NewLanguageConfigRegistry()
+			configs.Register(&LanguageConfig{
+				Language:    "go",
+				TestCommand: "sh",
+				TestArgs:    []string{"-c", "echo '{name} {package}' >> runs.log" + report},
+				SuiteArgs:   []string{"-c", "echo suite >> runs.log"},
+			})
+			cfg := NewConfig(WithMutationTesting(false))
+			runner := NewTestRunner(cfg, nil)
+			runner.configs = configs
+			runner.SetWorkingDir(dir)
+
+			files := NewFileManager(dir, nil)
+			patch := &Patch{FilePath: "abs.go", NewContent: mutationNewGo}
+			if err := files.ApplyPatch(patch); err != nil {
+				t.Fatal(err)
+			}
+
+			c := NewController(cfg, runner, files, nil, nil)
+			if tt.mapped {
+				c.SetTestMapping(createSelectionGraph(t))
+			}
+			c.ctx = NewContext("s", &Request{BugDescription: "d", ProjectRoot: dir, Language: "go"})
+			c.ctx.AppliedPatches = []*Patch{patch}
+
+			if err := c.stepRegression(context.Background()); err != nil {
+				t.Fatalf("stepRegression() error = %v", err)
+			}
+			if c.ctx.State != tt.wantState {
+				t.Errorf("state = %v, want %v", c.ctx.State, tt.wantState)
+			}
+
+			log, err := os.ReadFile(filepath.Join(dir, "runs.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			runs := strings.Split(strings.TrimSpace(string(log)), "\n")
+			if strings.Join(runs, "\n") != strings.Join(tt.wantRuns, "\n") {
+				t.Errorf("runs = %q, want %q", runs, tt.wantRuns)
+			}
+			if c.ctx.Metrics.TestsRun != len(tt.wantRuns) {
+				t.Errorf("TestsRun = %d, want %d", c.ctx.Metrics.TestsRun, len(tt.wantRuns))
+			}
+			if tt.fail != "" && !strings.Contains(c.ctx.LastTestOutput, tt.fail) {
+				t.Errorf("LastTestOutput does not name %s:\n%s", tt.fail, c.ctx.LastTestOutput)
+			}
+		})
+	}
+}
+
+func TestChangedOldLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		old, new    string
+		first, last int
+		ok          bool
+	}{
+		{"replaced line", "a\nb\nc\n", "a\nB\nc\n", 2, 2, true},
+		{"deleted lines", "a\nb\nc\nd\n", "a\nd\n", 2, 3, true},
+		{"insertion", "a\nb\n", "a\nx\nb\n", 1, 2, true},
+		{"insertion at start", "a\nb\n", "x\na\nb\n", 1, 1, true},
+		{"new file", "", "a\n", 0, 0, false},
+		{"unchanged", "a\n", "a\n", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, ok := changedOldLines(&Patch{OldContent: tt.old, NewContent: tt.new})
+			if first != tt.first || last != tt.last || ok != tt.ok {
+				t.Errorf("changedOldLines() = %d, %d, %v, want %d, %d, %v", first, last, ok, tt.first, tt.last, tt.ok)
+			}
+		})
+	}
+}
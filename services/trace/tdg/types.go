@@ -143,6 +143,14 @@ type Result struct {
 	// RegressionResults is the full suite test result.
 	RegressionResults *TestResult `json:"regression_results,omitempty"`
 
+	// Mutation is the mutation testing outcome, including surviving mutants.
+	// Nil if mutation testing was disabled or did not run.
+	Mutation *MutationReport `json:"mutation,omitempty"`
+
+	// Coverage is the touched-line coverage outcome. Nil if the coverage
+	// gate was disabled or did not run.
+	Coverage *CoverageReport `json:"coverage,omitempty"`
+
 	// Error contains the error message if TDG failed.
 	Error string `json:"error,omitempty"`
 
@@ -191,6 +199,15 @@ type Context struct {
 	// LastTestOutput is the output from the last test execution.
 	LastTestOutput string
 
+	// Mutation is the outcome of the mutation testing pass.
+	Mutation *MutationReport
+
+	// CoverageBaseline is the coverage before the fix, if collected.
+	CoverageBaseline CoverageProfile
+
+	// Coverage is the outcome of the coverage gate.
+	Coverage *CoverageReport
+
 	// LastError is the last error encountered.
 	LastError error
 
@@ -240,6 +257,10 @@ type TestCase struct {
 
 	// PackagePath is the package/module path for running tests.
 	PackagePath string `json:"package_path,omitempty"`
+
+	// Cases are the input cases of a table-driven test, one per case in
+	// the bug description. Empty for a single monolithic test.
+	Cases []string `json:"cases,omitempty"`
 }
 
 // Validate checks that the test case is complete.
@@ -18,6 +18,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/AleutianAI/AleutianFOSS/pkg/buildinfo"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -82,7 +83,7 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		ServiceName:    "aleutian",
-		ServiceVersion: "1.0.0",
+		ServiceVersion: buildinfo.Version,
 		Environment:    getEnvOr("ALEUTIAN_ENV", "development"),
 		TraceExporter:  getEnvOr("OTEL_TRACES_EXPORTER", "otlp"),
 		MetricExporter: getEnvOr("OTEL_METRICS_EXPORTER", "prometheus"),
@@ -142,13 +143,14 @@ func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error
 		return nil
 	}
 
-	// Build resource (service identity) using standard attribute keys
-	res := resource.NewWithAttributes(
-		"",
+	// Build resource (service identity and build) using standard attribute
+	// keys. The configured ServiceVersion overrides the build's version.
+	attrs := append(buildinfo.Get().Attributes(),
 		attribute.String("service.name", cfg.ServiceName),
 		attribute.String("service.version", cfg.ServiceVersion),
 		attribute.String("deployment.environment", cfg.Environment),
 	)
+	res := resource.NewWithAttributes("", attrs...)
 
 	// Set up W3C TraceContext propagator for distributed tracing
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
@@ -62,7 +62,7 @@ func (r *ToolRegistry) GetToolsByCategory(category string) []ToolDefinition {
 	return result
 }
 
-// allToolDefinitions returns all 24 tool definitions.
+// allToolDefinitions returns all 25 tool definitions.
 func allToolDefinitions() []ToolDefinition {
 	return []ToolDefinition{
 		// ==================== EXPLORATION TOOLS ====================
@@ -177,6 +177,19 @@ func allToolDefinitions() []ToolDefinition {
 			Returns:     "Impact analysis with affected callers, risk level, and recommendations",
 			Performance: "<200ms",
 		},
+		{
+			Name:        "explain_call_path",
+			Description: "Explain how one function reaches another: the mandatory (dominating) call path, the conditions guarding it, and [file:line] evidence snippets for every step, ready to cite.",
+			Category:    "explore",
+			Parameters: []ToolParam{
+				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
+				{Name: "from_id", Type: "string", Description: "Calling symbol ID", Required: true},
+				{Name: "to_id", Type: "string", Description: "Called symbol ID", Required: true},
+				{Name: "context_lines", Type: "integer", Description: "Source lines around each evidence line", Required: false, Default: "2"},
+			},
+			Returns:     "Dominating path, call chain with call-site evidence, guarding conditions, and citations",
+			Performance: "<500ms",
+		},
 
 		// ==================== REASONING TOOLS ====================
 		{
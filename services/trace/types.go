@@ -11,11 +11,16 @@
 package code_buddy
 
 import (
+	"github.com/AleutianAI/AleutianFOSS/pkg/apperr"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/review"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/undo"
 )
 
 // InitRequest is the request body for POST /v1/codebuddy/init.
@@ -133,6 +138,28 @@ type ImplementationsResponse struct {
 	Implementations []*SymbolInfo `json:"implementations"`
 }
 
+// TestsRequest is the query params for GET /v1/codebuddy/tests.
+type TestsRequest struct {
+	// GraphID is the graph to query. Required.
+	GraphID string `form:"graph_id" binding:"required"`
+
+	// Function is the function name to find tests for. Required.
+	Function string `form:"function" binding:"required"`
+
+	// Limit is the maximum number of results. Default: 50.
+	Limit int `form:"limit"`
+}
+
+// TestsResponse is the response for GET /v1/codebuddy/tests.
+type TestsResponse struct {
+	// Function is the function name that was searched.
+	Function string `json:"function"`
+
+	// Tests links each test to the symbol it exercises, highest
+	// confidence first.
+	Tests []analysis.TestLink `json:"tests"`
+}
+
 // SymbolRequest is the query params for GET /v1/codebuddy/symbol/:id.
 type SymbolRequest struct {
 	// GraphID is the graph to query. Required.
@@ -145,6 +172,63 @@ type SymbolResponse struct {
 	Symbol *SymbolInfo `json:"symbol"`
 }
 
+// MaxResolvePositions is the maximum number of positions in one
+// POST /v1/codebuddy/symbols/resolve request.
+const MaxResolvePositions = 1000
+
+// SymbolPosition is a source position to resolve to a symbol.
+type SymbolPosition struct {
+	// FilePath is the file, relative to the project root or absolute
+	// within it.
+	FilePath string `json:"file_path"`
+
+	// Line is the 1-indexed line.
+	Line int `json:"line"`
+
+	// Column is the 0-indexed column. Default: 0.
+	Column int `json:"column"`
+}
+
+// ResolveSymbolsRequest is the request body for POST /v1/codebuddy/symbols/resolve.
+type ResolveSymbolsRequest struct {
+	// GraphID is the graph to query. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Positions are the positions to resolve, at most MaxResolvePositions.
+	// Required.
+	Positions []SymbolPosition `json:"positions" binding:"required"`
+}
+
+// SymbolResolution is the outcome of resolving one position.
+//
+// Exactly one of Symbol and Error is set.
+type SymbolResolution struct {
+	// Position is the requested position.
+	Position SymbolPosition `json:"position"`
+
+	// Symbol is the innermost symbol containing the position.
+	Symbol *SymbolInfo `json:"symbol,omitempty"`
+
+	// Error describes why the position could not be resolved.
+	Error string `json:"error,omitempty"`
+
+	// Code is the error code: INVALID_POSITION, FILE_NOT_INDEXED or
+	// SYMBOL_NOT_FOUND.
+	Code string `json:"code,omitempty"`
+}
+
+// ResolveSymbolsResponse is the response for POST /v1/codebuddy/symbols/resolve.
+type ResolveSymbolsResponse struct {
+	// Results holds one resolution per requested position, in request order.
+	Results []SymbolResolution `json:"results"`
+
+	// Resolved is the number of positions resolved to a symbol.
+	Resolved int `json:"resolved"`
+
+	// Failed is the number of positions that could not be resolved.
+	Failed int `json:"failed"`
+}
+
 // SymbolInfo is a simplified symbol representation for API responses.
 type SymbolInfo struct {
 	// ID is the unique symbol identifier.
@@ -171,6 +255,10 @@ type SymbolInfo struct {
 	// DocComment is the documentation comment.
 	DocComment string `json:"doc_comment,omitempty"`
 
+	// DocSummary is the first sentence of DocComment with comment markers
+	// removed, suitable for compact context assembly.
+	DocSummary string `json:"doc_summary,omitempty"`
+
 	// Package is the package name.
 	Package string `json:"package,omitempty"`
 
@@ -221,16 +309,11 @@ type ReadyResponse struct {
 }
 
 // ErrorResponse is the standard error response format.
-type ErrorResponse struct {
-	// Error is the error message.
-	Error string `json:"error"`
-
-	// Code is the error code (optional).
-	Code string `json:"code,omitempty"`
-
-	// Details provides additional error context (optional).
-	Details string `json:"details,omitempty"`
-}
+//
+// It is the shared apperr.Response: handlers set Error, Code and
+// optionally Details, and the category, retryability and remediation for
+// the code are filled in from the error registry when encoded.
+type ErrorResponse = apperr.Response
 
 // CachedGraph holds a graph and its associated data.
 type CachedGraph struct {
@@ -247,9 +330,24 @@ type CachedGraph struct {
 	// Created lazily on first use. Provides cache statistics via QueryCacheStats().
 	Adapter *graph.CRSGraphAdapter
 
+	// Tests links test functions to the production symbols they
+	// exercise. Built with the graph; nil if building it failed.
+	Tests *analysis.TestMapping
+
+	// SemanticIndex is the embedding index for semantic search.
+	// Created lazily on first search by Service.GetSemanticIndex.
+	SemanticIndex *explore.SemanticSearchIndex
+
+	// SemanticBackend is the embedding backend SemanticIndex was built with.
+	SemanticBackend string
+
 	// BuiltAtMilli is when the graph was built.
 	BuiltAtMilli int64
 
+	// Generation identifies this build of the graph. Every init or refresh
+	// gets a new, higher generation.
+	Generation int64
+
 	// ProjectRoot is the project root path.
 	ProjectRoot string
 
@@ -271,6 +369,7 @@ func SymbolInfoFromAST(s *ast.Symbol) *SymbolInfo {
 		EndLine:    s.EndLine,
 		Signature:  s.Signature,
 		DocComment: s.DocComment,
+		DocSummary: ast.DocSummary(s.DocComment),
 		Package:    s.Package,
 		Exported:   s.Exported,
 	}
@@ -320,6 +419,84 @@ type AgentRunResponse struct {
 	DegradedMode bool `json:"degraded_mode"`
 }
 
+// AgentReviewRequest is the request body for POST /v1/trace/agent/review.
+type AgentReviewRequest struct {
+	// ProjectRoot is the absolute path to the project root, checked out at
+	// the new side of the diff. Required.
+	ProjectRoot string `json:"project_root" binding:"required"`
+
+	// Diff is a unified diff (from a PR or `git diff`). Required.
+	Diff string `json:"diff" binding:"required"`
+
+	// Languages to index (default: ["go"]).
+	Languages []string `json:"languages,omitempty"`
+
+	// GraphOnly skips the agent loop and returns only graph findings.
+	GraphOnly bool `json:"graph_only,omitempty"`
+
+	// Config is optional session configuration overrides.
+	Config *agent.SessionConfig `json:"config,omitempty"`
+}
+
+// AgentReviewResponse is the response for POST /v1/trace/agent/review.
+type AgentReviewResponse struct {
+	// SessionID is the agent session used for the review (empty if GraphOnly).
+	SessionID string `json:"session_id,omitempty"`
+
+	// State is the final agent state (empty if GraphOnly).
+	State string `json:"state,omitempty"`
+
+	// RiskLevel is the highest blast radius risk across changed symbols.
+	RiskLevel string `json:"risk_level"`
+
+	// FilesChanged is the number of files in the diff.
+	FilesChanged int `json:"files_changed"`
+
+	// ChangedSymbols are the symbols touched by the diff.
+	ChangedSymbols []review.ChangedSymbol `json:"changed_symbols"`
+
+	// Findings are sorted by severity (critical first), then location.
+	Findings []review.Finding `json:"findings"`
+
+	// Summary is a short overall assessment.
+	Summary string `json:"summary"`
+
+	// StepsTaken is the number of agent steps completed.
+	StepsTaken int `json:"steps_taken,omitempty"`
+
+	// TokensUsed is the total tokens consumed.
+	TokensUsed int `json:"tokens_used,omitempty"`
+
+	// AgentError explains why agent findings are missing, if they are.
+	AgentError string `json:"agent_error,omitempty"`
+}
+
+// AgentUndoRequest is the request body for POST /v1/trace/agent/:id/undo
+// and /v1/trace/agent/:id/redo.
+type AgentUndoRequest struct {
+	// Count is the number of edits to revert or re-apply (default: 1).
+	Count int `json:"count,omitempty"`
+}
+
+// AgentUndoResponse is the response for POST /v1/trace/agent/:id/undo and
+// /v1/trace/agent/:id/redo.
+type AgentUndoResponse struct {
+	// SessionID is the session whose edits were reverted or re-applied.
+	SessionID string `json:"session_id"`
+
+	// Operations are the edits reverted or re-applied, in order.
+	Operations []undo.Operation `json:"operations"`
+
+	// UndoAvailable is the number of edits that can still be undone.
+	UndoAvailable int `json:"undo_available"`
+
+	// RedoAvailable is the number of edits that can be redone.
+	RedoAvailable int `json:"redo_available"`
+
+	// Error explains why fewer than Count edits were processed, if so.
+	Error string `json:"error,omitempty"`
+}
+
 // AgentContinueRequest is the request body for POST /v1/codebuddy/agent/continue.
 type AgentContinueRequest struct {
 	// SessionID is the session to continue. Required.
@@ -603,6 +780,33 @@ type FindSimilarCodeRequest struct {
 	Limit         int     `json:"limit"`
 }
 
+// SemanticSearchRequest is the request for POST /v1/trace/search/semantic.
+type SemanticSearchRequest struct {
+	GraphID  string   `json:"graph_id" binding:"required"`
+	Query    string   `json:"query" binding:"required"`
+	Limit    int      `json:"limit"`
+	MinScore float64  `json:"min_score"`
+	Kinds    []string `json:"kinds,omitempty"`
+}
+
+// SemanticSearchResponse is the response for POST /v1/trace/search/semantic.
+type SemanticSearchResponse struct {
+	// Query is the query that was searched for.
+	Query string `json:"query"`
+
+	// Results contains the nearest symbols, best match first.
+	Results []explore.SemanticSearchResult `json:"results"`
+
+	// Backend is the embedding backend used ("embedding_service" or "local").
+	Backend string `json:"backend"`
+
+	// IndexedSymbols is the number of symbols in the semantic index.
+	IndexedSymbols int `json:"indexed_symbols"`
+
+	// LatencyMs is the request latency in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
 // BuildMinimalContextRequest is the request for POST /v1/codebuddy/explore/minimal_context.
 type BuildMinimalContextRequest struct {
 	GraphID        string `json:"graph_id" binding:"required"`
@@ -630,6 +834,14 @@ type AnalyzeChangeImpactRequest struct {
 	ChangeType string `json:"change_type"`
 }
 
+// ExplainCallPathRequest is the request for POST /v1/codebuddy/explore/call_path.
+type ExplainCallPathRequest struct {
+	GraphID      string `json:"graph_id" binding:"required"`
+	FromID       string `json:"from_id" binding:"required"`
+	ToID         string `json:"to_id" binding:"required"`
+	ContextLines *int   `json:"context_lines"`
+}
+
 // --- Reasoning Tool Types ---
 
 // CheckBreakingChangesRequest is the request for POST /v1/codebuddy/reason/breaking_changes.
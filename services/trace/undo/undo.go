@@ -0,0 +1,519 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package undo records file edits made by an agent session as reversible
+// operations.
+//
+// # Description
+//
+// Every write by the file tools is recorded with the file content before
+// and after the write. Undo restores the content from before the most
+// recent edits and Redo re-applies them. Both refuse to touch a file whose
+// content no longer matches the recorded state, so changes made outside
+// the session are never overwritten.
+//
+// Content is kept in a content-addressable blob store (pkg/cas, the same
+// store the CLI BackupManager keeps file backups in) when one is
+// configured, and in memory otherwise. With a store, the history itself is
+// saved there as well after every change, and Open resumes it, so edits
+// stay undoable across restarts.
+//
+// # Thread Safety
+//
+// Stack is safe for concurrent use.
+package undo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+)
+
+// DefaultMaxOperations is the number of edits kept for undo.
+const DefaultMaxOperations = 100
+
+// Sentinel errors for undo operations.
+var (
+	// ErrNothingToUndo indicates the undo stack is empty.
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	// ErrNothingToRedo indicates the redo stack is empty.
+	ErrNothingToRedo = errors.New("nothing to redo")
+
+	// ErrConflict indicates a file was changed after the edit being reverted.
+	ErrConflict = errors.New("file changed since the edit")
+
+	// ErrInvalidCount indicates a non-positive operation count.
+	ErrInvalidCount = errors.New("count must be positive")
+)
+
+// Operation is one recorded file edit.
+type Operation struct {
+	// Seq orders operations within a stack, starting at 1.
+	Seq int64 `json:"seq"`
+
+	// Path is the absolute path of the edited file.
+	Path string `json:"path"`
+
+	// Created is true when the edit created the file. Undoing it removes
+	// the file.
+	Created bool `json:"created"`
+
+	// Before is the digest of the content before the edit. Empty when
+	// Created is true.
+	Before cas.Digest `json:"before,omitempty"`
+
+	// After is the digest of the content the edit wrote.
+	After cas.Digest `json:"after"`
+
+	// RecordedAt is when the edit was recorded (Unix milliseconds UTC).
+	RecordedAt int64 `json:"recorded_at"`
+}
+
+// Config configures a Stack.
+type Config struct {
+	// MaxOperations bounds the undo history. The oldest edits are dropped
+	// beyond it. Defaults to DefaultMaxOperations.
+	MaxOperations int
+
+	// Store keeps file content in a blob store. Optional; content is kept
+	// in memory when nil.
+	Store *cas.Store
+
+	// Namespace separates the references of different stacks sharing a
+	// Store, typically the session ID. Must be unique per stack.
+	Namespace string
+}
+
+// historyVersion is the current stored history format.
+const historyVersion = 1
+
+// history is the stored form of a Stack.
+type history struct {
+	Version int         `json:"version"`
+	Seq     int64       `json:"seq"`
+	Done    []Operation `json:"done"`
+	Undone  []Operation `json:"undone"`
+}
+
+// Stack is the undo/redo history of one session.
+type Stack struct {
+	mu     sync.Mutex
+	config Config
+	blobs  blobStore
+	done   []Operation
+	undone []Operation
+	seq    int64
+}
+
+// NewStack creates an empty stack.
+//
+// A history saved in config.Store under the same Namespace is replaced on
+// the first change; use Open to resume it.
+func NewStack(config Config) *Stack {
+	if config.MaxOperations <= 0 {
+		config.MaxOperations = DefaultMaxOperations
+	}
+	var blobs blobStore = newMemoryBlobs()
+	if config.Store != nil {
+		blobs = &casBlobs{store: config.Store}
+	}
+	return &Stack{config: config, blobs: blobs}
+}
+
+// Open creates a stack that resumes the history saved in config.Store.
+//
+// # Outputs
+//
+//   - *Stack: The stack; empty if config.Store is nil or holds no history
+//     for config.Namespace
+//   - error: Non-nil if the saved history cannot be read
+func Open(config Config) (*Stack, error) {
+	s := NewStack(config)
+	if config.Store == nil {
+		return s, nil
+	}
+	data, err := config.Store.GetRef(s.historyRef())
+	if errors.Is(err, cas.ErrRefNotFound) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading undo history: %w", err)
+	}
+	var h history
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("decoding undo history: %w", err)
+	}
+	if h.Version != historyVersion {
+		return nil, fmt.Errorf("decoding undo history: unsupported version %d", h.Version)
+	}
+	s.seq, s.done, s.undone = h.Seq, h.Done, h.Undone
+	return s, nil
+}
+
+// RecordEdit records that path was written.
+//
+// # Inputs
+//
+//   - path: Absolute path of the file
+//   - before: Content before the write; ignored when existed is false
+//   - existed: Whether the file existed before the write
+//   - after: Content written
+//
+// # Outputs
+//
+//   - error: Non-nil if the content or history could not be stored
+//
+// Recording an edit clears the redo history.
+func (s *Stack) RecordEdit(path string, before []byte, existed bool, after []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := Operation{
+		Seq:        s.seq + 1,
+		Path:       path,
+		Created:    !existed,
+		RecordedAt: time.Now().UnixMilli(),
+	}
+	var err error
+	if existed {
+		if op.Before, err = s.blobs.save(s.ref(op, "before"), before); err != nil {
+			return fmt.Errorf("storing content before edit of %s: %w", path, err)
+		}
+	}
+	if op.After, err = s.blobs.save(s.ref(op, "after"), after); err != nil {
+		s.release(op)
+		return fmt.Errorf("storing content after edit of %s: %w", path, err)
+	}
+
+	s.seq = op.Seq
+	s.done = append(s.done, op)
+	for _, dropped := range s.undone {
+		s.release(dropped)
+	}
+	s.undone = nil
+	for len(s.done) > s.config.MaxOperations {
+		s.release(s.done[0])
+		s.done = s.done[1:]
+	}
+	return s.save()
+}
+
+// Undo reverts the last n edits, newest first.
+//
+// # Outputs
+//
+//   - []Operation: The operations reverted, in the order they were reverted
+//   - error: ErrNothingToUndo if there is no edit, ErrInvalidCount if n is
+//     not positive, or an error wrapping ErrConflict for the first edit
+//     whose file changed since; edits before it stay reverted. A failure
+//     to save the history is joined to the result.
+//
+// Fewer than n edits are reverted when the history is shorter.
+func (s *Stack) Undo(n int) (reverted []Operation, err error) {
+	if n <= 0 {
+		return nil, ErrInvalidCount
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.done) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	defer func() { err = errors.Join(err, s.save()) }()
+
+	for len(reverted) < n && len(s.done) > 0 {
+		op := s.done[len(s.done)-1]
+		if err := s.checkContent(op.Path, op.After); err != nil {
+			return reverted, err
+		}
+		if err := s.restore(op.Path, op.Before, op.Created); err != nil {
+			return reverted, err
+		}
+		s.done = s.done[:len(s.done)-1]
+		s.undone = append(s.undone, op)
+		reverted = append(reverted, op)
+	}
+	return reverted, nil
+}
+
+// Redo re-applies the last n undone edits, most recently undone first.
+//
+// # Outputs
+//
+//   - []Operation: The operations re-applied
+//   - error: ErrNothingToRedo, ErrInvalidCount, or an error wrapping
+//     ErrConflict as for Undo
+func (s *Stack) Redo(n int) (applied []Operation, err error) {
+	if n <= 0 {
+		return nil, ErrInvalidCount
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.undone) == 0 {
+		return nil, ErrNothingToRedo
+	}
+	defer func() { err = errors.Join(err, s.save()) }()
+
+	for len(applied) < n && len(s.undone) > 0 {
+		op := s.undone[len(s.undone)-1]
+		if err := s.checkContent(op.Path, op.Before); err != nil {
+			return applied, err
+		}
+		if err := s.restore(op.Path, op.After, false); err != nil {
+			return applied, err
+		}
+		s.undone = s.undone[:len(s.undone)-1]
+		s.done = append(s.done, op)
+		applied = append(applied, op)
+	}
+	return applied, nil
+}
+
+// History returns the edits that can be undone, newest first.
+func (s *Stack) History() []Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]Operation, 0, len(s.done))
+	for i := len(s.done) - 1; i >= 0; i-- {
+		history = append(history, s.done[i])
+	}
+	return history
+}
+
+// Len returns the number of edits that can be undone and redone.
+func (s *Stack) Len() (undo, redo int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.done), len(s.undone)
+}
+
+// Clear drops the whole history and releases stored content.
+func (s *Stack) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, op := range s.done {
+		s.release(op)
+	}
+	for _, op := range s.undone {
+		s.release(op)
+	}
+	s.done, s.undone = nil, nil
+	if s.config.Store != nil {
+		_ = s.config.Store.Unlink(s.historyRef())
+	}
+}
+
+// save writes the history to the store, if one is configured.
+func (s *Stack) save() error {
+	if s.config.Store == nil {
+		return nil
+	}
+	data, err := json.Marshal(history{
+		Version: historyVersion,
+		Seq:     s.seq,
+		Done:    s.done,
+		Undone:  s.undone,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding undo history: %w", err)
+	}
+	if _, err := s.config.Store.Save(s.historyRef(), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("saving undo history: %w", err)
+	}
+	return nil
+}
+
+// checkContent verifies path holds the content with digest want, or does
+// not exist when want is empty.
+func (s *Stack) checkContent(path string, want cas.Digest) error {
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		if want == "" {
+			return nil
+		}
+		return fmt.Errorf("%w: %s was deleted", ErrConflict, path)
+	case err != nil:
+		return fmt.Errorf("reading %s: %w", path, err)
+	case want == "":
+		return fmt.Errorf("%w: %s was created", ErrConflict, path)
+	case digestOf(data) != want:
+		return fmt.Errorf("%w: %s", ErrConflict, path)
+	}
+	return nil
+}
+
+// restore writes the content with digest d to path, or removes path when
+// remove is true.
+func (s *Stack) restore(path string, d cas.Digest, remove bool) error {
+	if remove {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", path, err)
+		}
+		return nil
+	}
+	data, err := s.blobs.load(d)
+	if err != nil {
+		return fmt.Errorf("loading content of %s: %w", path, err)
+	}
+	return writeAtomic(path, data)
+}
+
+// release drops the stored content of op.
+func (s *Stack) release(op Operation) {
+	if !op.Created {
+		s.blobs.drop(s.ref(op, "before"))
+	}
+	s.blobs.drop(s.ref(op, "after"))
+}
+
+// historyRef names the stored history.
+func (s *Stack) historyRef() string {
+	return cas.RefName("undo", s.config.Namespace, "history")
+}
+
+// ref names the stored content of one side of op.
+func (s *Stack) ref(op Operation, side string) string {
+	return cas.RefName("undo", s.config.Namespace, strconv.FormatInt(op.Seq, 10), side)
+}
+
+// writeAtomic replaces path with data, keeping its permissions.
+func writeAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".undo-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Chmod(tmpPath, mode)
+	}
+	if werr == nil {
+		werr = os.Rename(tmpPath, path)
+	}
+	if werr != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing %s: %w", path, werr)
+	}
+	return nil
+}
+
+func digestOf(data []byte) cas.Digest {
+	sum := sha256.Sum256(data)
+	return cas.Digest(hex.EncodeToString(sum[:]))
+}
+
+// =============================================================================
+// Content storage
+// =============================================================================
+
+// blobStore keeps file content under reference names.
+type blobStore interface {
+	save(name string, data []byte) (cas.Digest, error)
+	load(d cas.Digest) ([]byte, error)
+	drop(name string)
+}
+
+// casBlobs stores content in a cas.Store.
+type casBlobs struct {
+	store *cas.Store
+}
+
+func (b *casBlobs) save(name string, data []byte) (cas.Digest, error) {
+	d, err := b.store.PutBytes(data)
+	if err != nil {
+		return "", err
+	}
+	if err := b.store.Link(name, d); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+func (b *casBlobs) load(d cas.Digest) ([]byte, error) {
+	return b.store.Get(d)
+}
+
+func (b *casBlobs) drop(name string) {
+	_ = b.store.Unlink(name)
+}
+
+// memoryBlobs stores content in memory, one copy per distinct content.
+type memoryBlobs struct {
+	names   map[string]cas.Digest // reference name -> digest
+	content map[cas.Digest][]byte
+	uses    map[cas.Digest]int // number of names per digest
+}
+
+func newMemoryBlobs() *memoryBlobs {
+	return &memoryBlobs{
+		names:   make(map[string]cas.Digest),
+		content: make(map[cas.Digest][]byte),
+		uses:    make(map[cas.Digest]int),
+	}
+}
+
+func (b *memoryBlobs) save(name string, data []byte) (cas.Digest, error) {
+	d := digestOf(data)
+	if _, ok := b.content[d]; !ok {
+		b.content[d] = append([]byte(nil), data...)
+	}
+	b.drop(name)
+	b.names[name] = d
+	b.uses[d]++
+	return d, nil
+}
+
+func (b *memoryBlobs) load(d cas.Digest) ([]byte, error) {
+	data, ok := b.content[d]
+	if !ok {
+		return nil, cas.ErrNotFound
+	}
+	return data, nil
+}
+
+func (b *memoryBlobs) drop(name string) {
+	d, ok := b.names[name]
+	if !ok {
+		return
+	}
+	delete(b.names, name)
+	if b.uses[d]--; b.uses[d] <= 0 {
+		delete(b.uses, d)
+		delete(b.content, d)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package undo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/pkg/cas"
+)
+
+// edit writes content to path and records it on s.
+func edit(t *testing.T, s *Stack, path, content string) {
+	t.Helper()
+	before, err := os.ReadFile(path)
+	existed := err == nil
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := s.RecordEdit(path, before, existed, []byte(content)); err != nil {
+		t.Fatalf("RecordEdit: %v", err)
+	}
+}
+
+func assertContent(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, want)
+	}
+}
+
+func stacks(t *testing.T) map[string]*Stack {
+	store, err := cas.Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+	return map[string]*Stack{
+		"memory": NewStack(Config{}),
+		"cas":    NewStack(Config{Store: store, Namespace: "session-1"}),
+	}
+}
+
+func TestStack_UndoRedo(t *testing.T) {
+	for name, s := range stacks(t) {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			a := filepath.Join(dir, "a.go")
+			b := filepath.Join(dir, "b.go")
+			if err := os.WriteFile(a, []byte("v0"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			edit(t, s, a, "v1")
+			edit(t, s, b, "new")
+			edit(t, s, a, "v2")
+
+			reverted, err := s.Undo(2)
+			if err != nil {
+				t.Fatalf("Undo: %v", err)
+			}
+			if len(reverted) != 2 || reverted[0].Seq != 3 || reverted[1].Path != b {
+				t.Errorf("reverted = %+v, want seq 3 then b.go", reverted)
+			}
+			assertContent(t, a, "v1")
+			if _, err := os.Stat(b); !os.IsNotExist(err) {
+				t.Errorf("undoing the creation of b.go should remove it, stat err = %v", err)
+			}
+
+			applied, err := s.Redo(5)
+			if err != nil || len(applied) != 2 {
+				t.Fatalf("Redo = %+v, %v; want 2 operations", applied, err)
+			}
+			assertContent(t, a, "v2")
+			assertContent(t, b, "new")
+
+			if _, err := s.Undo(3); err != nil {
+				t.Fatalf("Undo all: %v", err)
+			}
+			assertContent(t, a, "v0")
+			if _, err := s.Undo(1); !errors.Is(err, ErrNothingToUndo) {
+				t.Errorf("err = %v, want ErrNothingToUndo", err)
+			}
+		})
+	}
+}
+
+func TestStack_RecordClearsRedo(t *testing.T) {
+	s := NewStack(Config{})
+	path := filepath.Join(t.TempDir(), "a.go")
+	edit(t, s, path, "v1")
+	edit(t, s, path, "v2")
+
+	if _, err := s.Undo(1); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	edit(t, s, path, "v3")
+
+	if _, err := s.Redo(1); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("err = %v, want ErrNothingToRedo", err)
+	}
+	if undo, redo := s.Len(); undo != 2 || redo != 0 {
+		t.Errorf("Len() = %d, %d; want 2, 0", undo, redo)
+	}
+}
+
+func TestStack_Conflict(t *testing.T) {
+	s := NewStack(Config{})
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	edit(t, s, a, "a1")
+	edit(t, s, b, "b1")
+
+	// Someone else changes a.go after the session wrote it.
+	if err := os.WriteFile(a, []byte("external"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err := s.Undo(2)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if len(reverted) != 1 || reverted[0].Path != b {
+		t.Errorf("reverted = %+v, want only b.go", reverted)
+	}
+	assertContent(t, a, "external")
+	if undo, redo := s.Len(); undo != 1 || redo != 1 {
+		t.Errorf("Len() = %d, %d; want 1, 1", undo, redo)
+	}
+}
+
+func TestStack_MaxOperations(t *testing.T) {
+	s := NewStack(Config{MaxOperations: 2})
+	path := filepath.Join(t.TempDir(), "a.go")
+	for _, content := range []string{"v1", "v2", "v3"} {
+		edit(t, s, path, content)
+	}
+
+	history := s.History()
+	if len(history) != 2 || history[0].Seq != 3 || history[1].Seq != 2 {
+		t.Fatalf("History() = %+v, want seq 3, 2", history)
+	}
+	if _, err := s.Undo(5); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	assertContent(t, path, "v1")
+}
+
+func TestStack_InvalidCount(t *testing.T) {
+	s := NewStack(Config{})
+	if _, err := s.Undo(0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("Undo(0) err = %v, want ErrInvalidCount", err)
+	}
+	if _, err := s.Redo(-1); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("Redo(-1) err = %v, want ErrInvalidCount", err)
+	}
+}
+
+func TestStack_ClearReleasesStoredContent(t *testing.T) {
+	store, err := cas.Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+	s := NewStack(Config{Store: store, Namespace: "session-1"})
+	path := filepath.Join(t.TempDir(), "a.go")
+	edit(t, s, path, "v1")
+	edit(t, s, path, "v2")
+
+	s.Clear()
+
+	refs, err := store.Refs("undo")
+	if err != nil {
+		t.Fatalf("Refs: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("%d references left after Clear, want 0", len(refs))
+	}
+}
+
+func TestOpen_ResumesHistory(t *testing.T) {
+	store, err := cas.Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("cas.Open: %v", err)
+	}
+	config := Config{Store: store, Namespace: "session-1"}
+	path := filepath.Join(t.TempDir(), "a.go")
+
+	first, err := Open(config)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	edit(t, first, path, "v1")
+	edit(t, first, path, "v2")
+	if _, err := first.Undo(1); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+
+	// A new stack, as after a restart, continues where the first left off.
+	resumed, err := Open(config)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if u, r := resumed.Len(); u != 1 || r != 1 {
+		t.Fatalf("resumed Len = %d, %d; want 1, 1", u, r)
+	}
+	if _, err := resumed.Redo(1); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	assertContent(t, path, "v2")
+	edit(t, resumed, path, "v3")
+	if ops := resumed.History(); len(ops) != 3 || ops[0].Seq != 3 {
+		t.Errorf("History = %+v, want seq 3 first of 3", ops)
+	}
+
+	other, err := Open(Config{Store: store, Namespace: "session-2"})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if u, r := other.Len(); u != 0 || r != 0 {
+		t.Errorf("another namespace has %d, %d operations, want none", u, r)
+	}
+
+	resumed.Clear()
+	if cleared, err := Open(config); err != nil {
+		t.Fatalf("Open: %v", err)
+	} else if u, _ := cleared.Len(); u != 0 {
+		t.Errorf("history survived Clear with %d operations", u)
+	}
+}
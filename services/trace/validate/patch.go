@@ -12,6 +12,7 @@ package validate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -23,9 +24,11 @@ import (
 	"github.com/smacker/go-tree-sitter/golang"
 	"github.com/smacker/go-tree-sitter/javascript"
 	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/rust"
 	"github.com/smacker/go-tree-sitter/typescript/typescript"
 	"github.com/sourcegraph/go-diff/diff"
 
+	tracediff "github.com/AleutianAI/AleutianFOSS/services/trace/diff"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lint"
 )
 
@@ -104,7 +107,7 @@ func (v *PatchValidator) SetLintRunner(runner *lint.LintRunner) {
 //	1. Size check - reject patches over maxLines
 //	2. Diff parsing - validate diff format
 //	3. Syntax validation - parse full file after applying patch
-//	4. Linter check - run external linters (golangci-lint, ruff, eslint)
+//	4. Linter check - run external linters (golangci-lint, ruff, eslint, clippy)
 //	5. Pattern scanning - AST-based dangerous pattern detection
 //	6. Secret scanning - check for hardcoded secrets
 //	7. Permission check - verify files are writable
@@ -171,15 +174,7 @@ func (v *PatchValidator) Validate(ctx context.Context, patchContent, projectRoot
 			return nil, ctx.Err()
 		}
 
-		filePath := fileDiff.NewName
-		if filePath == "" || filePath == "/dev/null" {
-			filePath = fileDiff.OrigName
-		}
-
-		// Strip a/ or b/ prefix from git diffs
-		filePath = strings.TrimPrefix(filePath, "a/")
-		filePath = strings.TrimPrefix(filePath, "b/")
-
+		filePath := diffFilePath(fileDiff)
 		absPath := filepath.Join(projectRoot, filePath)
 		language := detectLanguage(filePath)
 
@@ -292,6 +287,8 @@ func (v *PatchValidator) checkSyntax(ctx context.Context, absPath string, fileDi
 		lang = javascript.GetLanguage()
 	case "typescript":
 		lang = typescript.GetLanguage()
+	case "rust":
+		lang = rust.GetLanguage()
 	default:
 		return nil // Unknown language
 	}
@@ -427,8 +424,17 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 		return
 	}
 
-	// Run linter on content
-	lintResult, err := v.lintRunner.LintContent(ctx, newContent, language)
+	// Run linter on content, in place so project linters see the project
+	var lintResult *lint.LintResult
+	if v.config.LintChangedLinesOnly {
+		lintResult, err = v.lintRunner.LintChanges(ctx, original, newContent, language, absPath,
+			changedLines(fileDiff), lint.ScopeOptions{
+				ContextLines: v.config.LintContextLines,
+				BlockOutside: v.config.BlockOnLintOutsideHunks,
+			})
+	} else {
+		lintResult, err = v.lintRunner.LintContentAt(ctx, newContent, language, absPath)
+	}
 	if err != nil {
 		slog.Warn("Linter execution failed",
 			slog.String("file", relPath),
@@ -648,6 +654,155 @@ func findFirstError(node *sitter.Node) *sitter.Node {
 	return nil
 }
 
+// FixLint folds linter auto-fixes into a patch.
+//
+// Description:
+//
+//	For each file in the patch whose linter has a fix mode, the patched
+//	content is auto-fixed in a sandbox (lint.LintRunner.FixContentAt).
+//	Files the linter changed get a new diff from the file on disk to the
+//	fixed content; other files keep their diff as is. Nothing on disk is
+//	modified. When Validate blocks a patch on lint errors, the agent can
+//	call FixLint and validate the returned patch instead, so trivial lint
+//	issues do not cost it another generation.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	patchContent - The patch content (unified diff format)
+//	projectRoot - Project root directory for file resolution
+//
+// Outputs:
+//
+//	*LintFixResult - The fixed patch and the files that changed
+//	error - Non-nil if the patch cannot be parsed, no lint runner is
+//	        configured, or the context is done
+//
+// Thread Safety: Safe for concurrent use.
+func (v *PatchValidator) FixLint(ctx context.Context, patchContent, projectRoot string) (*LintFixResult, error) {
+	if v.lintRunner == nil {
+		return nil, fmt.Errorf("fixing lint: linter not enabled")
+	}
+	fileDiffs, err := v.parseDiff(patchContent)
+	if err != nil {
+		return nil, fmt.Errorf("fixing lint: %w", err)
+	}
+
+	result := &LintFixResult{}
+	var patch strings.Builder
+	for _, fileDiff := range fileDiffs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		filePath := diffFilePath(fileDiff)
+		absPath := filepath.Join(projectRoot, filePath)
+		if fixed := v.fixFileLint(ctx, absPath, filePath, fileDiff); fixed != "" {
+			patch.WriteString(fixed)
+			result.FixedFiles = append(result.FixedFiles, filePath)
+			continue
+		}
+
+		out, err := diff.PrintFileDiff(fileDiff)
+		if err != nil {
+			return nil, fmt.Errorf("printing diff for %s: %w", filePath, err)
+		}
+		patch.Write(out)
+	}
+
+	result.Patch = patch.String()
+	return result, nil
+}
+
+// fixFileLint returns a diff from the file at absPath to its patched and
+// auto-fixed content, or "" if the linter changed nothing or could not
+// run. Linter failures are logged, not returned: the original diff still
+// stands.
+func (v *PatchValidator) fixFileLint(ctx context.Context, absPath, relPath string, fileDiff *diff.FileDiff) string {
+	language := detectLanguage(relPath)
+	if language == "" || fileDiff.NewName == "/dev/null" {
+		return ""
+	}
+
+	var original []byte
+	if _, err := os.Stat(absPath); err == nil {
+		if original, err = os.ReadFile(absPath); err != nil {
+			return ""
+		}
+	}
+	newContent, err := v.applyDiff(original, fileDiff)
+	if err != nil || len(newContent) == 0 {
+		return ""
+	}
+
+	fix, err := v.lintRunner.FixContentAt(ctx, newContent, language, absPath)
+	if err != nil {
+		if !errors.Is(err, lint.ErrAutoFixUnsupported) {
+			slog.Warn("Linter auto-fix failed",
+				slog.String("file", relPath),
+				slog.String("language", language),
+				slog.String("error", err.Error()),
+			)
+		}
+		return ""
+	}
+	if !fix.Changed() {
+		return ""
+	}
+
+	fixed := tracediff.UnifiedDiff(relPath, string(original), string(fix.Fixed))
+	if len(original) == 0 {
+		fixed = strings.Replace(fixed, "--- a/"+relPath, "--- /dev/null", 1)
+	}
+	return fixed
+}
+
+// changedLines returns the lines of the patched file that a file diff
+// adds or changes. A pure deletion marks the line after it, where the
+// removed code was.
+func changedLines(fileDiff *diff.FileDiff) []lint.LineRange {
+	var ranges []lint.LineRange
+	mark := func(line int) {
+		line = max(line, 1)
+		if n := len(ranges); n > 0 && line <= ranges[n-1].End+1 {
+			ranges[n-1].End = max(ranges[n-1].End, line)
+			return
+		}
+		ranges = append(ranges, lint.LineRange{Start: line, End: line})
+	}
+
+	for _, hunk := range fileDiff.Hunks {
+		line := int(hunk.NewStartLine)
+		for _, text := range strings.Split(strings.TrimSuffix(string(hunk.Body), "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				mark(line)
+				line++
+			case strings.HasPrefix(text, "-"):
+				mark(line)
+			case strings.HasPrefix(text, "\\"):
+				// "\ No newline at end of file"
+			default:
+				line++
+			}
+		}
+	}
+	return ranges
+}
+
+// diffFilePath returns the project-relative path a file diff applies to.
+func diffFilePath(fileDiff *diff.FileDiff) string {
+	filePath := fileDiff.NewName
+	if filePath == "" || filePath == "/dev/null" {
+		filePath = fileDiff.OrigName
+	}
+
+	// Strip a/ or b/ prefix from git diffs
+	filePath = strings.TrimPrefix(filePath, "a/")
+	filePath = strings.TrimPrefix(filePath, "b/")
+	return filePath
+}
+
 // detectLanguage detects the programming language from file extension.
 func detectLanguage(filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
@@ -660,6 +815,8 @@ func detectLanguage(filePath string) string {
 		return "javascript"
 	case ".ts", ".tsx", ".mts", ".cts":
 		return "typescript"
+	case ".rs":
+		return "rust"
 	default:
 		return ""
 	}
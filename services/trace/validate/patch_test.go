@@ -14,7 +14,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lint"
 )
 
 func TestNewPatchValidator(t *testing.T) {
@@ -572,6 +575,103 @@ func main() {
 	}
 }
 
+func TestPatchValidator_FixLint(t *testing.T) {
+	v, _ := NewPatchValidator(DefaultValidatorConfig())
+
+	// A python "linter" whose fix mode strips trailing spaces
+	runner := lint.NewLintRunner()
+	runner.Configs().Register(&lint.LinterConfig{
+		Language:   "python",
+		Command:    "sh",
+		Args:       []string{"-c", "echo '[]'"},
+		Extensions: []string{".py"},
+		FixArgs:    []string{"-c", `sed -i 's/ *$//' "$0"`},
+	})
+	runner.DetectAvailableLinters()
+	v.SetLintRunner(runner)
+
+	tmpDir := t.TempDir()
+	original := "a = 1\nb = 2\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.py"), []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "notes.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := `--- a/app.py
++++ b/app.py
+@@ -1,2 +1,3 @@
+ a = 1
++c = 3  
+ b = 2
+--- a/notes.txt
++++ b/notes.txt
+@@ -1 +1,2 @@
+ one
++two  
+`
+
+	ctx := context.Background()
+	fixed, err := v.FixLint(ctx, patch, tmpDir)
+	if err != nil {
+		t.Fatalf("FixLint error: %v", err)
+	}
+	if len(fixed.FixedFiles) != 1 || fixed.FixedFiles[0] != "app.py" {
+		t.Errorf("FixedFiles = %v, want [app.py]", fixed.FixedFiles)
+	}
+	if !strings.Contains(fixed.Patch, "+c = 3\n") || strings.Contains(fixed.Patch, "+c = 3  ") {
+		t.Errorf("app.py diff not fixed:\n%s", fixed.Patch)
+	}
+	// Files without a fixing linter keep their diff
+	if !strings.Contains(fixed.Patch, "+two  \n") {
+		t.Errorf("notes.txt diff changed:\n%s", fixed.Patch)
+	}
+
+	// The fixed patch validates, and nothing on disk changed
+	result, err := v.Validate(ctx, fixed.Patch, tmpDir)
+	if err != nil || !result.Valid {
+		t.Errorf("Validate(fixed) = %+v, %v", result, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(tmpDir, "app.py")); string(got) != original {
+		t.Errorf("app.py changed to %q", got)
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	v, _ := NewPatchValidator(DefaultValidatorConfig())
+	patch := `--- a/app.py
++++ b/app.py
+@@ -1,4 +1,5 @@
+ a = 1
++b = 2
++c = 3
+ d = 4
+-e = 5
+ f = 6
+@@ -20,3 +21,3 @@
+ x = 1
+-y = 2
++y = 3
+ z = 4
+`
+	fileDiffs, err := v.parseDiff(patch)
+	if err != nil {
+		t.Fatalf("parseDiff: %v", err)
+	}
+
+	got := changedLines(fileDiffs[0])
+	want := []lint.LineRange{{Start: 2, End: 3}, {Start: 5, End: 5}, {Start: 22, End: 22}}
+	if len(got) != len(want) {
+		t.Fatalf("changedLines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("changedLines()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
 func BenchmarkValidate_500Lines(b *testing.B) {
 	config := DefaultValidatorConfig()
 	v, _ := NewPatchValidator(config)
@@ -71,6 +71,15 @@ type ValidationResult struct {
 	ValidatedAt int64 `json:"validated_at"`
 }
 
+// LintFixResult contains the result of PatchValidator.FixLint.
+type LintFixResult struct {
+	// Patch is the input patch with linter auto-fixes folded in.
+	Patch string `json:"patch"`
+
+	// FixedFiles lists the files whose diff the auto-fixes changed.
+	FixedFiles []string `json:"fixed_files,omitempty"`
+}
+
 // ValidationError represents a blocking validation error.
 type ValidationError struct {
 	// Type is the error type.
@@ -161,6 +170,19 @@ type ValidatorConfig struct {
 	// BlockOnLintErrors blocks patches that have linter errors.
 	// Only applies when EnableLinter is true.
 	BlockOnLintErrors bool
+
+	// LintChangedLinesOnly reports only lint issues on lines the patch
+	// changes, plus LintContextLines around them. The whole file is
+	// still linted.
+	LintChangedLinesOnly bool
+
+	// LintContextLines widens each changed hunk for LintChangedLinesOnly.
+	LintContextLines int
+
+	// BlockOnLintOutsideHunks keeps lint errors outside the changed lines
+	// when the patch introduced them, e.g. an import left unused by a
+	// removed call. Only applies with LintChangedLinesOnly.
+	BlockOnLintOutsideHunks bool
 }
 
 // DefaultValidatorConfig returns the default configuration.
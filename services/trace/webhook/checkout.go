@@ -0,0 +1,205 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Checkout is a working tree at the PR head.
+type Checkout struct {
+	// Dir is the absolute path of the working tree.
+	Dir string
+
+	// BaseSHA is the merge base of the PR and its target branch.
+	BaseSHA string
+
+	// ChangedFiles are repository-relative paths added, copied, modified,
+	// or renamed by the PR.
+	ChangedFiles []string
+}
+
+// Checkouter prepares working trees for PR analysis.
+type Checkouter interface {
+	// Checkout fetches the PR and checks out its head commit.
+	Checkout(ctx context.Context, ev *PREvent) (*Checkout, error)
+
+	// Remove deletes any working tree kept for the PR.
+	Remove(ev *PREvent) error
+}
+
+// GitCheckouter keeps one working tree per PR under BaseDir and updates it
+// with `git fetch` on each push, so repeated pushes only transfer new
+// objects and the analyzed path (and therefore graph ID) stays stable.
+//
+// # Thread Safety
+//
+// Safe for concurrent use on different PRs. The Receiver serializes work
+// per PR.
+type GitCheckouter struct {
+	baseDir string
+	tokens  map[Provider]string
+
+	// allowLocal permits file:// clone URLs. Only tests set it.
+	allowLocal bool
+}
+
+// NewGitCheckouter creates a checkouter rooted at baseDir.
+//
+// # Inputs
+//
+//   - baseDir: Absolute directory for working trees. Created if missing.
+//   - tokens: Optional access tokens per provider for private repositories.
+func NewGitCheckouter(baseDir string, tokens map[Provider]string) *GitCheckouter {
+	return &GitCheckouter{baseDir: baseDir, tokens: tokens}
+}
+
+// Dir returns the working tree directory for a PR.
+func (g *GitCheckouter) Dir(ev *PREvent) string {
+	return filepath.Join(g.baseDir, string(ev.Provider), safePathComponent(ev.Repo), "pr-"+strconv.Itoa(ev.Number))
+}
+
+// Checkout implements Checkouter.
+func (g *GitCheckouter) Checkout(ctx context.Context, ev *PREvent) (*Checkout, error) {
+	dir := g.Dir(ev)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating checkout dir: %w", err)
+		}
+		if _, err := g.git(ctx, dir, "init", "-q"); err != nil {
+			return nil, err
+		}
+	}
+
+	remote, err := g.authURL(ev)
+	if err != nil {
+		return nil, err
+	}
+	baseRef := "refs/remotes/origin/" + ev.BaseRef
+	if _, err := g.git(ctx, dir, "fetch", "--quiet", "--no-tags", "--force", remote,
+		"+refs/heads/"+ev.BaseRef+":"+baseRef,
+		"+"+ev.PullRef()+":refs/remotes/origin/pr",
+	); err != nil {
+		return nil, err
+	}
+
+	if _, err := g.git(ctx, dir, "checkout", "--quiet", "--force", "--detach", ev.HeadSHA); err != nil {
+		return nil, err
+	}
+	if _, err := g.git(ctx, dir, "clean", "-fdqx"); err != nil {
+		return nil, err
+	}
+
+	base, err := g.git(ctx, dir, "merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := g.git(ctx, dir, "diff", "--name-only", "--diff-filter=ACMR", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checkout{
+		Dir:          dir,
+		BaseSHA:      base,
+		ChangedFiles: splitLines(diff),
+	}, nil
+}
+
+// Remove implements Checkouter.
+func (g *GitCheckouter) Remove(ev *PREvent) error {
+	return os.RemoveAll(g.Dir(ev))
+}
+
+// git runs a git command in dir and returns trimmed stdout. Tokens are
+// redacted from error output.
+func (g *GitCheckouter) git(ctx context.Context, dir string, args ...string) (string, error) {
+	args = append([]string{"-c", "credential.helper=", "-c", "core.hooksPath=/dev/null"}, args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: git %s: %s", ErrCheckoutFailed, args[4], g.redact(strings.TrimSpace(stderr.String())))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// authURL returns the clone URL with credentials for the provider, if any.
+func (g *GitCheckouter) authURL(ev *PREvent) (string, error) {
+	u, err := url.Parse(ev.CloneURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: unsupported clone URL", ErrInvalidPayload)
+	}
+	switch {
+	case u.Scheme == "https" || u.Scheme == "http":
+	case u.Scheme == "file" && g.allowLocal:
+		return u.String(), nil
+	default:
+		return "", fmt.Errorf("%w: unsupported clone URL scheme %q", ErrInvalidPayload, u.Scheme)
+	}
+	token := g.tokens[ev.Provider]
+	if token == "" {
+		return u.String(), nil
+	}
+	user := "x-access-token"
+	if ev.Provider == ProviderGitLab {
+		user = "oauth2"
+	}
+	u.User = url.UserPassword(user, token)
+	return u.String(), nil
+}
+
+// redact removes configured tokens from s.
+func (g *GitCheckouter) redact(s string) string {
+	for _, t := range g.tokens {
+		if t != "" {
+			s = strings.ReplaceAll(s, t, "***")
+		}
+	}
+	return s
+}
+
+// safePathComponent makes a repository name safe to use as one directory.
+func safePathComponent(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// splitLines splits non-empty lines.
+func splitLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default API endpoints.
+const (
+	DefaultGitHubAPIURL = "https://api.github.com"
+	DefaultGitLabURL    = "https://gitlab.com"
+)
+
+// commentsPerPage is the page size used when searching for an existing comment.
+const commentsPerPage = 100
+
+// maxCommentPages bounds the search for an existing comment.
+const maxCommentPages = 10
+
+// Commenter posts review comments on a code host.
+type Commenter interface {
+	// Upsert creates the PR's analysis comment, or updates it in place if
+	// a comment containing CommentMarker already exists.
+	Upsert(ctx context.Context, ev *PREvent, body string) error
+}
+
+// apiClient is shared by the GitHub and GitLab commenters.
+type apiClient struct {
+	baseURL string
+	client  *http.Client
+	auth    func(*http.Request)
+}
+
+// do sends a JSON request and decodes a JSON response into out (if non-nil).
+func (c *apiClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.auth(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s %s: %v", ErrCommentFailed, method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s %s: status %d: %s",
+			ErrCommentFailed, method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("%w: decoding %s response: %v", ErrCommentFailed, path, err)
+		}
+	}
+	return nil
+}
+
+// remoteComment is the common shape of GitHub issue comments and GitLab notes.
+type remoteComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// findMarked pages through list results and returns the first comment
+// containing CommentMarker, or 0.
+func (c *apiClient) findMarked(ctx context.Context, listPath string) (int64, error) {
+	sep := "?"
+	if strings.Contains(listPath, "?") {
+		sep = "&"
+	}
+	for page := 1; page <= maxCommentPages; page++ {
+		var comments []remoteComment
+		path := fmt.Sprintf("%s%sper_page=%d&page=%d", listPath, sep, commentsPerPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, cm := range comments {
+			if strings.Contains(cm.Body, CommentMarker) {
+				return cm.ID, nil
+			}
+		}
+		if len(comments) < commentsPerPage {
+			break
+		}
+	}
+	return 0, nil
+}
+
+// GitHubCommenter posts PR comments through the GitHub REST API.
+type GitHubCommenter struct {
+	api apiClient
+}
+
+// NewGitHubCommenter creates a GitHub commenter.
+//
+// # Inputs
+//
+//   - baseURL: API root. Empty uses DefaultGitHubAPIURL; GitHub Enterprise
+//     uses "https://HOST/api/v3".
+//   - token: Token with pull request write access.
+func NewGitHubCommenter(baseURL, token string) *GitHubCommenter {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	return &GitHubCommenter{api: apiClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		auth: func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+token)
+			r.Header.Set("Accept", "application/vnd.github+json")
+		},
+	}}
+}
+
+// Upsert implements Commenter.
+func (g *GitHubCommenter) Upsert(ctx context.Context, ev *PREvent, body string) error {
+	repo := escapeRepoPath(ev.Repo)
+	id, err := g.api.findMarked(ctx, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, ev.Number))
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{"body": body}
+	if id != 0 {
+		return g.api.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", repo, id), payload, nil)
+	}
+	return g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, ev.Number), payload, nil)
+}
+
+// GitLabCommenter posts merge request notes through the GitLab REST API.
+type GitLabCommenter struct {
+	api apiClient
+}
+
+// NewGitLabCommenter creates a GitLab commenter.
+//
+// # Inputs
+//
+//   - baseURL: Instance root (e.g. "https://gitlab.example.com"). Empty
+//     uses DefaultGitLabURL.
+//   - token: Token with api scope.
+func NewGitLabCommenter(baseURL, token string) *GitLabCommenter {
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+	return &GitLabCommenter{api: apiClient{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v4",
+		client:  &http.Client{Timeout: 30 * time.Second},
+		auth: func(r *http.Request) {
+			r.Header.Set("PRIVATE-TOKEN", token)
+		},
+	}}
+}
+
+// Upsert implements Commenter.
+func (g *GitLabCommenter) Upsert(ctx context.Context, ev *PREvent, body string) error {
+	project := ev.ProjectID
+	if project == "" {
+		project = url.PathEscape(ev.Repo)
+	}
+	notes := fmt.Sprintf("/projects/%s/merge_requests/%d/notes", project, ev.Number)
+
+	id, err := g.api.findMarked(ctx, notes+"?sort=asc")
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{"body": body}
+	if id != 0 {
+		return g.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", notes, id), payload, nil)
+	}
+	return g.api.do(ctx, http.MethodPost, notes, payload, nil)
+}
+
+// escapeRepoPath escapes each segment of "owner/name".
+func escapeRepoPath(repo string) string {
+	parts := strings.Split(repo, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
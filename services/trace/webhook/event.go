@@ -0,0 +1,309 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Provider identifies the code host that sent a webhook.
+type Provider string
+
+const (
+	// ProviderGitHub is github.com or GitHub Enterprise.
+	ProviderGitHub Provider = "github"
+
+	// ProviderGitLab is gitlab.com or a self-managed GitLab.
+	ProviderGitLab Provider = "gitlab"
+)
+
+// Action is the normalized pull/merge request action.
+type Action string
+
+const (
+	// ActionOpened is a newly opened or reopened PR.
+	ActionOpened Action = "opened"
+
+	// ActionUpdated is a PR that received new commits.
+	ActionUpdated Action = "updated"
+
+	// ActionClosed is a closed or merged PR.
+	ActionClosed Action = "closed"
+)
+
+// PREvent is a provider-neutral pull/merge request event.
+type PREvent struct {
+	// Provider is the code host.
+	Provider Provider `json:"provider"`
+
+	// Action is what happened to the PR.
+	Action Action `json:"action"`
+
+	// DeliveryID is the provider's delivery identifier, if any.
+	DeliveryID string `json:"delivery_id,omitempty"`
+
+	// Repo is the full repository name ("owner/name" or "group/project").
+	Repo string `json:"repo"`
+
+	// ProjectID is the GitLab numeric project ID. Empty for GitHub.
+	ProjectID string `json:"project_id,omitempty"`
+
+	// Number is the PR number (GitHub) or merge request IID (GitLab).
+	Number int `json:"number"`
+
+	// Title is the PR title.
+	Title string `json:"title,omitempty"`
+
+	// URL is the PR web URL.
+	URL string `json:"url,omitempty"`
+
+	// CloneURL is the HTTPS clone URL of the target repository.
+	CloneURL string `json:"clone_url"`
+
+	// BaseRef is the target branch name.
+	BaseRef string `json:"base_ref"`
+
+	// HeadRef is the source branch name.
+	HeadRef string `json:"head_ref"`
+
+	// HeadSHA is the commit to analyze.
+	HeadSHA string `json:"head_sha"`
+}
+
+// Key identifies the PR across events, so pushes to the same PR update one
+// comment.
+func (e *PREvent) Key() string {
+	return fmt.Sprintf("%s/%s#%d", e.Provider, e.Repo, e.Number)
+}
+
+// PullRef returns the ref the target repository exposes for the PR head,
+// which also works for PRs opened from forks.
+func (e *PREvent) PullRef() string {
+	if e.Provider == ProviderGitLab {
+		return fmt.Sprintf("refs/merge-requests/%d/head", e.Number)
+	}
+	return fmt.Sprintf("refs/pull/%d/head", e.Number)
+}
+
+// DetectProvider identifies the provider from request headers.
+//
+// # Outputs
+//
+//   - Provider: The provider.
+//   - bool: False if the request is not from a known provider.
+func DetectProvider(h http.Header) (Provider, bool) {
+	switch {
+	case h.Get("X-GitHub-Event") != "":
+		return ProviderGitHub, true
+	case h.Get("X-Gitlab-Event") != "":
+		return ProviderGitLab, true
+	default:
+		return "", false
+	}
+}
+
+// VerifyGitHubSignature checks the X-Hub-Signature-256 HMAC of body.
+func VerifyGitHubSignature(secret, signature string, body []byte) error {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyGitLabToken checks the X-Gitlab-Token shared secret.
+func VerifyGitLabToken(secret, token string) error {
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// githubPullRequestPayload is the subset of the pull_request event we use.
+type githubPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		Draft   bool   `json:"draft"`
+		Head    struct {
+			SHA string `json:"sha"`
+			Ref string `json:"ref"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	} `json:"repository"`
+}
+
+// ParseGitHub parses a GitHub webhook delivery.
+//
+// # Outputs
+//
+//   - *PREvent: The normalized event.
+//   - error: ErrIgnoredEvent for events or actions that need no analysis,
+//     or a parse error.
+func ParseGitHub(h http.Header, body []byte) (*PREvent, error) {
+	if h.Get("X-GitHub-Event") != "pull_request" {
+		return nil, fmt.Errorf("%w: github event %q", ErrIgnoredEvent, h.Get("X-GitHub-Event"))
+	}
+
+	var p githubPullRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing github payload: %w", err)
+	}
+
+	var action Action
+	switch p.Action {
+	case "opened", "reopened", "ready_for_review":
+		action = ActionOpened
+	case "synchronize":
+		action = ActionUpdated
+	case "closed":
+		action = ActionClosed
+	default:
+		return nil, fmt.Errorf("%w: github action %q", ErrIgnoredEvent, p.Action)
+	}
+
+	ev := &PREvent{
+		Provider:   ProviderGitHub,
+		Action:     action,
+		DeliveryID: h.Get("X-GitHub-Delivery"),
+		Repo:       p.Repository.FullName,
+		Number:     p.Number,
+		Title:      p.PullRequest.Title,
+		URL:        p.PullRequest.HTMLURL,
+		CloneURL:   p.Repository.CloneURL,
+		BaseRef:    p.PullRequest.Base.Ref,
+		HeadRef:    p.PullRequest.Head.Ref,
+		HeadSHA:    p.PullRequest.Head.SHA,
+	}
+	return ev, ev.validate()
+}
+
+// gitlabMergeRequestPayload is the subset of the Merge Request Hook we use.
+type gitlabMergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitHTTPURL        string `json:"git_http_url"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		URL          string `json:"url"`
+		Action       string `json:"action"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		OldRev       string `json:"oldrev"`
+		LastCommit   struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+// ParseGitLab parses a GitLab webhook delivery.
+//
+// Update events without "oldrev" (title or label edits) are ignored; only
+// updates that push new commits trigger analysis.
+func ParseGitLab(h http.Header, body []byte) (*PREvent, error) {
+	var p gitlabMergeRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing gitlab payload: %w", err)
+	}
+	if p.ObjectKind != "merge_request" {
+		return nil, fmt.Errorf("%w: gitlab event %q", ErrIgnoredEvent, p.ObjectKind)
+	}
+
+	attrs := p.ObjectAttributes
+	var action Action
+	switch attrs.Action {
+	case "open", "reopen":
+		action = ActionOpened
+	case "update":
+		if attrs.OldRev == "" {
+			return nil, fmt.Errorf("%w: gitlab update without new commits", ErrIgnoredEvent)
+		}
+		action = ActionUpdated
+	case "close", "merge":
+		action = ActionClosed
+	default:
+		return nil, fmt.Errorf("%w: gitlab action %q", ErrIgnoredEvent, attrs.Action)
+	}
+
+	ev := &PREvent{
+		Provider:   ProviderGitLab,
+		Action:     action,
+		DeliveryID: h.Get("X-Gitlab-Event-UUID"),
+		Repo:       p.Project.PathWithNamespace,
+		ProjectID:  strconv.Itoa(p.Project.ID),
+		Number:     attrs.IID,
+		Title:      attrs.Title,
+		URL:        attrs.URL,
+		CloneURL:   p.Project.GitHTTPURL,
+		BaseRef:    attrs.TargetBranch,
+		HeadRef:    attrs.SourceBranch,
+		HeadSHA:    attrs.LastCommit.ID,
+	}
+	return ev, ev.validate()
+}
+
+// validate checks the fields required to analyze the PR.
+func (e *PREvent) validate() error {
+	if e.Repo == "" || e.Number <= 0 {
+		return fmt.Errorf("%w: missing repository or PR number", ErrInvalidPayload)
+	}
+	if e.Action == ActionClosed {
+		return nil
+	}
+	if e.CloneURL == "" || e.BaseRef == "" || e.HeadSHA == "" {
+		return fmt.Errorf("%w: missing clone URL, base ref, or head SHA", ErrInvalidPayload)
+	}
+	if !isHexSHA(e.HeadSHA) {
+		return fmt.Errorf("%w: invalid head SHA %q", ErrInvalidPayload, e.HeadSHA)
+	}
+	if strings.HasPrefix(e.BaseRef, "-") {
+		return fmt.Errorf("%w: invalid base ref %q", ErrInvalidPayload, e.BaseRef)
+	}
+	return nil
+}
+
+// isHexSHA reports whether s looks like a git object ID.
+func isHexSHA(s string) bool {
+	if len(s) < 7 || len(s) > 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s[:len(s)&^1])
+	return err == nil
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// CommentMarker is embedded in every review comment so later pushes can
+// find and update it instead of posting a new one.
+const CommentMarker = "<!-- aleutian:pr-analysis -->"
+
+// Report is the analysis of one PR head.
+type Report struct {
+	// Event is the PR that was analyzed.
+	Event *PREvent `json:"event"`
+
+	// BaseSHA is the merge base the change was computed against.
+	BaseSHA string `json:"base_sha"`
+
+	// ChangedFiles are the files changed by the PR.
+	ChangedFiles []string `json:"changed_files"`
+
+	// RiskLevel is the highest risk across changed symbols
+	// (LOW, MEDIUM, HIGH, CRITICAL).
+	RiskLevel string `json:"risk_level"`
+
+	// Impacts are the changed symbols with the widest blast radius first.
+	Impacts []SymbolImpact `json:"impacts,omitempty"`
+
+	// LintIssues are lint findings in changed files.
+	LintIssues []LintIssue `json:"lint_issues,omitempty"`
+
+	// AffectedTests are test files that exercise the changed code.
+	AffectedTests []string `json:"affected_tests,omitempty"`
+
+	// Warnings are non-fatal problems encountered during analysis.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// DurationMs is how long the analysis took.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// SymbolImpact is the blast radius of one changed symbol.
+type SymbolImpact struct {
+	Symbol          string `json:"symbol"`
+	FilePath        string `json:"file_path"`
+	RiskLevel       string `json:"risk_level"`
+	DirectCallers   int    `json:"direct_callers"`
+	IndirectCallers int    `json:"indirect_callers"`
+	FilesAffected   int    `json:"files_affected"`
+}
+
+// LintIssue is a single lint finding.
+type LintIssue struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+}
+
+// DefaultCommentTemplate is the default review comment, in GitHub/GitLab
+// flavored Markdown.
+const DefaultCommentTemplate = `## {{riskEmoji .RiskLevel}} Aleutian analysis: {{.RiskLevel}} risk
+
+Analyzed ` + "`{{shortSHA .Event.HeadSHA}}`" + ` against ` + "`{{.Event.BaseRef}}`" + ` — {{len .ChangedFiles}} changed file(s).
+{{if .Impacts}}
+### Impact
+
+| Symbol | Risk | Direct callers | Indirect callers | Files affected |
+|---|---|---|---|---|
+{{range limitImpacts .Impacts 10}}| ` + "`{{.Symbol}}`" + ` | {{.RiskLevel}} | {{.DirectCallers}} | {{.IndirectCallers}} | {{.FilesAffected}} |
+{{end}}{{if gt (len .Impacts) 10}}
+_…and {{sub (len .Impacts) 10}} more changed symbol(s)._
+{{end}}{{end}}{{if .AffectedTests}}
+### Tests to run
+
+{{range limitStrings .AffectedTests 15}}- ` + "`{{.}}`" + `
+{{end}}{{end}}{{if .LintIssues}}
+### Lint ({{len .LintIssues}})
+
+{{range limitLint .LintIssues 20}}- ` + "`{{.FilePath}}:{{.Line}}`" + ` **{{.Severity}}** {{.Message}}{{if .Rule}} ({{.Rule}}){{end}}
+{{end}}{{end}}{{if .Warnings}}
+<details><summary>Warnings</summary>
+
+{{range .Warnings}}- {{.}}
+{{end}}
+</details>
+{{end}}`
+
+// Renderer renders reports into review comments.
+type Renderer struct {
+	tmpl *template.Template
+}
+
+// NewRenderer parses a comment template.
+//
+// # Inputs
+//
+//   - text: Go text/template source executed with a *Report. Empty uses
+//     DefaultCommentTemplate. Helpers: riskEmoji, shortSHA, limitImpacts,
+//     limitStrings, limitLint, sub.
+//
+// # Outputs
+//
+//   - *Renderer: The renderer.
+//   - error: Non-nil if the template does not parse.
+func NewRenderer(text string) (*Renderer, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultCommentTemplate
+	}
+	tmpl, err := template.New("comment").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing comment template: %w", err)
+	}
+	return &Renderer{tmpl: tmpl}, nil
+}
+
+// Render produces the comment body, always prefixed with CommentMarker.
+func (r *Renderer) Render(report *Report) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(CommentMarker + "\n")
+	if err := r.tmpl.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("rendering comment: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderFailure produces a comment for a failed analysis.
+func (r *Renderer) RenderFailure(ev *PREvent, err error) string {
+	return fmt.Sprintf("%s\n## ⚠️ Aleutian analysis failed\n\nCould not analyze `%s`: %s\n",
+		CommentMarker, shortSHA(ev.HeadSHA), err)
+}
+
+var templateFuncs = template.FuncMap{
+	"riskEmoji": riskEmoji,
+	"shortSHA":  shortSHA,
+	"sub":       func(a, b int) int { return a - b },
+	"limitImpacts": func(s []SymbolImpact, n int) []SymbolImpact {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+	"limitStrings": func(s []string, n int) []string {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+	"limitLint": func(s []LintIssue, n int) []LintIssue {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+}
+
+// riskEmoji returns a status emoji for a risk level.
+func riskEmoji(level string) string {
+	switch strings.ToUpper(level) {
+	case "CRITICAL":
+		return "🔴"
+	case "HIGH":
+		return "🟠"
+	case "MEDIUM":
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// shortSHA abbreviates a commit SHA.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package webhook receives GitHub and GitLab pull request webhooks and posts
+// an analysis comment on each PR.
+//
+// # Description
+//
+// On a PR open or push, the Receiver verifies the delivery, acknowledges it
+// immediately (providers time out after ~10s), and in the background:
+//
+//  1. Checks out the PR head (Checkouter).
+//  2. Runs the impact/risk/lint pipeline (Analyzer, supplied by the trace
+//     service so this package stays free of parser dependencies).
+//  3. Renders a Markdown summary from a configurable template (Renderer).
+//  4. Creates or updates a single marked comment on the PR (Commenter).
+//
+// Work is serialized per PR. If more pushes arrive while a PR is being
+// analyzed, only the newest head is analyzed next; intermediate heads are
+// skipped. Closing a PR removes its working tree.
+//
+// # Thread Safety
+//
+// Receiver is safe for concurrent use.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Errors returned by the webhook package.
+var (
+	// ErrInvalidSignature indicates the delivery failed authentication.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrIgnoredEvent indicates an event that does not trigger analysis.
+	ErrIgnoredEvent = errors.New("event ignored")
+
+	// ErrInvalidPayload indicates a payload missing required fields.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+
+	// ErrCheckoutFailed indicates the PR could not be checked out.
+	ErrCheckoutFailed = errors.New("checkout failed")
+
+	// ErrCommentFailed indicates the review comment could not be posted.
+	ErrCommentFailed = errors.New("posting comment failed")
+)
+
+// MaxPayloadBytes bounds the webhook body size.
+const MaxPayloadBytes = 5 << 20
+
+// DefaultAnalysisTimeout bounds one checkout + analysis + comment cycle.
+const DefaultAnalysisTimeout = 10 * time.Minute
+
+// Analyzer runs the analysis pipeline on a checked-out PR.
+type Analyzer interface {
+	// Analyze examines the working tree and returns a report. The report's
+	// Event, BaseSHA, and ChangedFiles are filled in by the Receiver.
+	Analyze(ctx context.Context, co *Checkout, ev *PREvent) (*Report, error)
+}
+
+// Config configures a Receiver.
+type Config struct {
+	// GitHubSecret is the webhook secret for X-Hub-Signature-256. If empty,
+	// GitHub deliveries are rejected.
+	GitHubSecret string
+
+	// GitLabToken is the webhook secret token for X-Gitlab-Token. If empty,
+	// GitLab deliveries are rejected.
+	GitLabToken string
+
+	// AnalysisTimeout bounds each analysis cycle.
+	// Default: DefaultAnalysisTimeout
+	AnalysisTimeout time.Duration
+}
+
+// Receiver handles webhook deliveries.
+type Receiver struct {
+	cfg        Config
+	checkout   Checkouter
+	analyzer   Analyzer
+	renderer   *Renderer
+	commenters map[Provider]Commenter
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	pending map[string]*prQueue
+	wg      sync.WaitGroup
+}
+
+// prQueue tracks in-flight work for one PR.
+type prQueue struct {
+	next *PREvent
+}
+
+// NewReceiver creates a webhook receiver.
+//
+// # Inputs
+//
+//   - cfg: Secrets and timeouts.
+//   - checkout: Prepares working trees. Must not be nil.
+//   - analyzer: Runs the pipeline. Must not be nil.
+//   - renderer: Renders comments. Nil uses the default template.
+//   - commenters: Comment clients per provider. A provider without a
+//     commenter is analyzed but not commented on.
+func NewReceiver(cfg Config, checkout Checkouter, analyzer Analyzer, renderer *Renderer, commenters map[Provider]Commenter) *Receiver {
+	if cfg.AnalysisTimeout <= 0 {
+		cfg.AnalysisTimeout = DefaultAnalysisTimeout
+	}
+	if renderer == nil {
+		renderer, _ = NewRenderer("")
+	}
+	return &Receiver{
+		cfg:        cfg,
+		checkout:   checkout,
+		analyzer:   analyzer,
+		renderer:   renderer,
+		commenters: commenters,
+		logger:     slog.Default().With("component", "webhook"),
+		pending:    make(map[string]*prQueue),
+	}
+}
+
+// ServeHTTP authenticates and parses a delivery, then schedules analysis.
+//
+// # Responses
+//
+//   - 202 Accepted: Analysis scheduled (or PR cleanup on close).
+//   - 204 No Content: Authenticated event that needs no analysis.
+//   - 400 Bad Request: Unknown provider or malformed payload.
+//   - 401 Unauthorized: Signature or token mismatch.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ev, err := r.parse(req)
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrIgnoredEvent):
+		r.logger.Debug("ignoring webhook", "reason", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.logger.Info("webhook accepted",
+		"pr", ev.Key(), "action", ev.Action, "head", shortSHA(ev.HeadSHA), "delivery", ev.DeliveryID)
+	r.Enqueue(ev)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// parse authenticates the request and returns the normalized event.
+func (r *Receiver) parse(req *http.Request) (*PREvent, error) {
+	provider, ok := DetectProvider(req.Header)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown provider", ErrInvalidPayload)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, MaxPayloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading body: %v", ErrInvalidPayload, err)
+	}
+	if len(body) > MaxPayloadBytes {
+		return nil, fmt.Errorf("%w: payload too large", ErrInvalidPayload)
+	}
+
+	switch provider {
+	case ProviderGitHub:
+		if r.cfg.GitHubSecret == "" {
+			return nil, fmt.Errorf("%w: github secret not configured", ErrInvalidSignature)
+		}
+		if err := VerifyGitHubSignature(r.cfg.GitHubSecret, req.Header.Get("X-Hub-Signature-256"), body); err != nil {
+			return nil, err
+		}
+		return ParseGitHub(req.Header, body)
+	default:
+		if r.cfg.GitLabToken == "" {
+			return nil, fmt.Errorf("%w: gitlab token not configured", ErrInvalidSignature)
+		}
+		if err := VerifyGitLabToken(r.cfg.GitLabToken, req.Header.Get("X-Gitlab-Token")); err != nil {
+			return nil, err
+		}
+		return ParseGitLab(req.Header, body)
+	}
+}
+
+// Enqueue schedules processing of ev, coalescing with in-flight work for
+// the same PR.
+func (r *Receiver) Enqueue(ev *PREvent) {
+	key := ev.Key()
+
+	r.mu.Lock()
+	if q, busy := r.pending[key]; busy {
+		q.next = ev // newest event wins
+		r.mu.Unlock()
+		return
+	}
+	r.pending[key] = &prQueue{}
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for ev != nil {
+			r.process(ev)
+
+			r.mu.Lock()
+			q := r.pending[key]
+			ev, q.next = q.next, nil
+			if ev == nil {
+				delete(r.pending, key)
+			}
+			r.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until all scheduled work has finished.
+func (r *Receiver) Wait() {
+	r.wg.Wait()
+}
+
+// process handles one event end to end.
+func (r *Receiver) process(ev *PREvent) {
+	logger := r.logger.With("pr", ev.Key(), "head", shortSHA(ev.HeadSHA))
+
+	if ev.Action == ActionClosed {
+		if err := r.checkout.Remove(ev); err != nil {
+			logger.Warn("removing checkout failed", "error", err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.AnalysisTimeout)
+	defer cancel()
+
+	start := time.Now()
+	body, err := r.analyze(ctx, ev)
+	if err != nil {
+		logger.Error("PR analysis failed", "error", err)
+		body = r.renderer.RenderFailure(ev, err)
+	}
+
+	commenter := r.commenters[ev.Provider]
+	if commenter == nil {
+		logger.Warn("no commenter configured for provider; skipping comment")
+		return
+	}
+	if err := commenter.Upsert(ctx, ev, body); err != nil {
+		logger.Error("posting PR comment failed", "error", err)
+		return
+	}
+	logger.Info("PR analysis posted", "duration_ms", time.Since(start).Milliseconds())
+}
+
+// analyze checks out, analyzes, and renders the comment body.
+func (r *Receiver) analyze(ctx context.Context, ev *PREvent) (string, error) {
+	start := time.Now()
+
+	co, err := r.checkout.Checkout(ctx, ev)
+	if err != nil {
+		return "", err
+	}
+
+	report, err := r.analyzer.Analyze(ctx, co, ev)
+	if err != nil {
+		return "", fmt.Errorf("analysis: %w", err)
+	}
+	report.Event = ev
+	report.BaseSHA = co.BaseSHA
+	report.ChangedFiles = co.ChangedFiles
+	if report.RiskLevel == "" {
+		report.RiskLevel = "LOW"
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	return r.renderer.Render(report)
+}
@@ -0,0 +1,570 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func githubPayload(action string, number int, sha string) []byte {
+	p := map[string]any{
+		"action": action,
+		"number": number,
+		"pull_request": map[string]any{
+			"title":    "Add feature",
+			"html_url": "https://github.com/acme/app/pull/7",
+			"head":     map[string]any{"sha": sha, "ref": "feature"},
+			"base":     map[string]any{"ref": "main"},
+		},
+		"repository": map[string]any{
+			"full_name": "acme/app",
+			"clone_url": "https://github.com/acme/app.git",
+		},
+	}
+	data, _ := json.Marshal(p)
+	return data
+}
+
+func gitlabPayload(action, oldrev string) []byte {
+	p := map[string]any{
+		"object_kind": "merge_request",
+		"project": map[string]any{
+			"id":                  42,
+			"path_with_namespace": "group/app",
+			"git_http_url":        "https://gitlab.com/group/app.git",
+		},
+		"object_attributes": map[string]any{
+			"iid":           3,
+			"title":         "Fix bug",
+			"action":        action,
+			"source_branch": "fix",
+			"target_branch": "main",
+			"oldrev":        oldrev,
+			"last_commit":   map[string]any{"id": testSHA},
+		},
+	}
+	data, _ := json.Marshal(p)
+	return data
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := []byte(`{"a":1}`)
+
+	if err := VerifyGitHubSignature("s3cret", sign("s3cret", body), body); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	for name, sig := range map[string]string{
+		"wrong secret": sign("other", body),
+		"no prefix":    strings.TrimPrefix(sign("s3cret", body), "sha256="),
+		"not hex":      "sha256=zz",
+		"empty":        "",
+	} {
+		if err := VerifyGitHubSignature("s3cret", sig, body); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+
+	if err := VerifyGitLabToken("tok", "tok"); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+	if err := VerifyGitLabToken("tok", "nope"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestParseGitHub(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-GitHub-Event", "pull_request")
+	h.Set("X-GitHub-Delivery", "d-1")
+
+	ev, err := ParseGitHub(h, githubPayload("synchronize", 7, testSHA))
+	if err != nil {
+		t.Fatalf("ParseGitHub failed: %v", err)
+	}
+	if ev.Action != ActionUpdated || ev.Repo != "acme/app" || ev.Number != 7 || ev.BaseRef != "main" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if ev.Key() != "github/acme/app#7" {
+		t.Errorf("unexpected key %q", ev.Key())
+	}
+	if ev.PullRef() != "refs/pull/7/head" {
+		t.Errorf("unexpected pull ref %q", ev.PullRef())
+	}
+
+	t.Run("ignored action", func(t *testing.T) {
+		_, err := ParseGitHub(h, githubPayload("labeled", 7, testSHA))
+		if !errors.Is(err, ErrIgnoredEvent) {
+			t.Errorf("expected ErrIgnoredEvent, got %v", err)
+		}
+	})
+
+	t.Run("ignored event type", func(t *testing.T) {
+		ph := http.Header{}
+		ph.Set("X-GitHub-Event", "push")
+		if _, err := ParseGitHub(ph, []byte(`{}`)); !errors.Is(err, ErrIgnoredEvent) {
+			t.Errorf("expected ErrIgnoredEvent, got %v", err)
+		}
+	})
+
+	t.Run("invalid sha", func(t *testing.T) {
+		_, err := ParseGitHub(h, githubPayload("opened", 7, "--upload-pack=x"))
+		if !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("expected ErrInvalidPayload, got %v", err)
+		}
+	})
+}
+
+func TestParseGitLab(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", "Merge Request Hook")
+
+	ev, err := ParseGitLab(h, gitlabPayload("update", "abc1234"))
+	if err != nil {
+		t.Fatalf("ParseGitLab failed: %v", err)
+	}
+	if ev.Action != ActionUpdated || ev.ProjectID != "42" || ev.Number != 3 || ev.HeadSHA != testSHA {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if ev.PullRef() != "refs/merge-requests/3/head" {
+		t.Errorf("unexpected pull ref %q", ev.PullRef())
+	}
+
+	if _, err := ParseGitLab(h, gitlabPayload("update", "")); !errors.Is(err, ErrIgnoredEvent) {
+		t.Errorf("metadata-only update: expected ErrIgnoredEvent, got %v", err)
+	}
+	if ev, err := ParseGitLab(h, gitlabPayload("merge", "")); err != nil || ev.Action != ActionClosed {
+		t.Errorf("merge: expected closed, got %v, %v", ev, err)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	r, err := NewRenderer("")
+	if err != nil {
+		t.Fatalf("NewRenderer failed: %v", err)
+	}
+
+	report := &Report{
+		Event:        &PREvent{HeadSHA: testSHA, BaseRef: "main"},
+		ChangedFiles: []string{"a.go", "b.go"},
+		RiskLevel:    "HIGH",
+		Impacts: []SymbolImpact{
+			{Symbol: "Parse", RiskLevel: "HIGH", DirectCallers: 12, FilesAffected: 4},
+		},
+		AffectedTests: []string{"a_test.go"},
+		LintIssues:    []LintIssue{{FilePath: "a.go", Line: 3, Severity: "error", Message: "unused"}},
+	}
+	body, err := r.Render(report)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{CommentMarker, "HIGH risk", "`01234567`", "2 changed file(s)", "`Parse`", "`a_test.go`", "`a.go:3`"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("rendered comment missing %q:\n%s", want, body)
+		}
+	}
+
+	t.Run("custom template", func(t *testing.T) {
+		r, err := NewRenderer("risk={{.RiskLevel}}")
+		if err != nil {
+			t.Fatalf("NewRenderer failed: %v", err)
+		}
+		body, _ := r.Render(report)
+		if body != CommentMarker+"\nrisk=HIGH" {
+			t.Errorf("unexpected body %q", body)
+		}
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		if _, err := NewRenderer("{{.Nope"); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+}
+
+// fakeAPI is an in-memory comment store served over HTTP.
+type fakeAPI struct {
+	mu       sync.Mutex
+	comments map[int64]string
+	nextID   int64
+	methods  []string
+}
+
+func (f *fakeAPI) handler(listPath, itemPrefix string, updateMethod string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.methods = append(f.methods, r.Method)
+
+		var in struct {
+			Body string `json:"body"`
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == listPath:
+			var out []remoteComment
+			if r.URL.Query().Get("page") == "1" {
+				for id, body := range f.comments {
+					out = append(out, remoteComment{ID: id, Body: body})
+				}
+			}
+			json.NewEncoder(w).Encode(out)
+		case r.Method == http.MethodPost && r.URL.Path == listPath:
+			json.NewDecoder(r.Body).Decode(&in)
+			f.nextID++
+			f.comments[f.nextID] = in.Body
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == updateMethod && strings.HasPrefix(r.URL.Path, itemPrefix):
+			var id int64
+			fmt.Sscanf(strings.TrimPrefix(r.URL.Path, itemPrefix), "%d", &id)
+			json.NewDecoder(r.Body).Decode(&in)
+			f.comments[id] = in.Body
+		default:
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		}
+	})
+}
+
+func TestCommenters_Upsert(t *testing.T) {
+	tests := []struct {
+		name      string
+		ev        *PREvent
+		list      string
+		item      string
+		update    string
+		commenter func(url string) Commenter
+	}{
+		{
+			name:   "github",
+			ev:     &PREvent{Provider: ProviderGitHub, Repo: "acme/app", Number: 7},
+			list:   "/repos/acme/app/issues/7/comments",
+			item:   "/repos/acme/app/issues/comments/",
+			update: http.MethodPatch,
+			commenter: func(url string) Commenter {
+				return NewGitHubCommenter(url, "tok")
+			},
+		},
+		{
+			name:   "gitlab",
+			ev:     &PREvent{Provider: ProviderGitLab, Repo: "group/app", ProjectID: "42", Number: 3},
+			list:   "/api/v4/projects/42/merge_requests/3/notes",
+			item:   "/api/v4/projects/42/merge_requests/3/notes/",
+			update: http.MethodPut,
+			commenter: func(url string) Commenter {
+				return NewGitLabCommenter(url, "tok")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{comments: map[int64]string{}}
+			srv := httptest.NewServer(api.handler(tt.list, tt.item, tt.update))
+			defer srv.Close()
+			c := tt.commenter(srv.URL)
+			ctx := context.Background()
+
+			if err := c.Upsert(ctx, tt.ev, CommentMarker+"\nfirst"); err != nil {
+				t.Fatalf("first Upsert failed: %v", err)
+			}
+			if err := c.Upsert(ctx, tt.ev, CommentMarker+"\nsecond"); err != nil {
+				t.Fatalf("second Upsert failed: %v", err)
+			}
+
+			if len(api.comments) != 1 {
+				t.Fatalf("expected exactly one comment, got %d", len(api.comments))
+			}
+			if api.comments[1] != CommentMarker+"\nsecond" {
+				t.Errorf("comment not updated: %q", api.comments[1])
+			}
+			want := []string{http.MethodGet, http.MethodPost, http.MethodGet, tt.update}
+			if !reflect.DeepEqual(api.methods, want) {
+				t.Errorf("methods = %v, want %v", api.methods, want)
+			}
+		})
+	}
+
+	t.Run("error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+		err := NewGitHubCommenter(srv.URL, "tok").Upsert(context.Background(), tests[0].ev, "x")
+		if !errors.Is(err, ErrCommentFailed) {
+			t.Errorf("expected ErrCommentFailed, got %v", err)
+		}
+	})
+}
+
+type fakeCheckouter struct {
+	mu      sync.Mutex
+	heads   []string
+	removed int
+	block   chan struct{}
+	err     error
+}
+
+func (f *fakeCheckouter) Checkout(ctx context.Context, ev *PREvent) (*Checkout, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.heads = append(f.heads, ev.HeadSHA)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Checkout{Dir: "/tmp/pr", BaseSHA: "base", ChangedFiles: []string{"main.go"}}, nil
+}
+
+func (f *fakeCheckouter) Remove(ev *PREvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed++
+	return nil
+}
+
+type fakeAnalyzer struct{}
+
+func (fakeAnalyzer) Analyze(ctx context.Context, co *Checkout, ev *PREvent) (*Report, error) {
+	return &Report{RiskLevel: "MEDIUM"}, nil
+}
+
+type fakeCommenter struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (f *fakeCommenter) Upsert(ctx context.Context, ev *PREvent, body string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bodies = append(f.bodies, body)
+	return nil
+}
+
+func githubRequest(secret string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", sign(secret, body))
+	return req
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	co := &fakeCheckouter{}
+	cm := &fakeCommenter{}
+	r := NewReceiver(Config{GitHubSecret: "s3cret"}, co, fakeAnalyzer{}, nil,
+		map[Provider]Commenter{ProviderGitHub: cm})
+
+	t.Run("accepted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, githubRequest("s3cret", githubPayload("opened", 7, testSHA)))
+		r.Wait()
+
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", w.Code)
+		}
+		if len(cm.bodies) != 1 || !strings.Contains(cm.bodies[0], "MEDIUM risk") {
+			t.Errorf("unexpected comments: %q", cm.bodies)
+		}
+		if !strings.Contains(cm.bodies[0], "1 changed file(s)") {
+			t.Errorf("checkout files not merged into report: %q", cm.bodies[0])
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := githubRequest("wrong", githubPayload("opened", 7, testSHA))
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("gitlab not configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(gitlabPayload("open", "")))
+		req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+		req.Header.Set("X-Gitlab-Token", "")
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, githubRequest("s3cret", githubPayload("labeled", 7, testSHA)))
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("{}")))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("closed removes checkout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, githubRequest("s3cret", githubPayload("closed", 7, testSHA)))
+		r.Wait()
+		if co.removed != 1 {
+			t.Errorf("expected checkout removal, got %d", co.removed)
+		}
+	})
+}
+
+func TestReceiver_CoalescesPushes(t *testing.T) {
+	co := &fakeCheckouter{block: make(chan struct{})}
+	cm := &fakeCommenter{}
+	r := NewReceiver(Config{}, co, fakeAnalyzer{}, nil, map[Provider]Commenter{ProviderGitHub: cm})
+
+	ev := func(sha string) *PREvent {
+		return &PREvent{Provider: ProviderGitHub, Action: ActionUpdated, Repo: "acme/app", Number: 1, HeadSHA: sha}
+	}
+	r.Enqueue(ev("aaaaaaa"))
+	r.Enqueue(ev("bbbbbbb"))
+	r.Enqueue(ev("ccccccc"))
+	close(co.block)
+	r.Wait()
+
+	want := []string{"aaaaaaa", "ccccccc"}
+	if !reflect.DeepEqual(co.heads, want) {
+		t.Errorf("analyzed heads = %v, want %v", co.heads, want)
+	}
+	if len(cm.bodies) != 2 {
+		t.Errorf("expected 2 comment upserts, got %d", len(cm.bodies))
+	}
+}
+
+func TestReceiver_FailureComment(t *testing.T) {
+	co := &fakeCheckouter{err: fmt.Errorf("%w: git fetch: not found", ErrCheckoutFailed)}
+	cm := &fakeCommenter{}
+	r := NewReceiver(Config{}, co, fakeAnalyzer{}, nil, map[Provider]Commenter{ProviderGitHub: cm})
+
+	r.Enqueue(&PREvent{Provider: ProviderGitHub, Action: ActionOpened, Repo: "acme/app", Number: 1, HeadSHA: testSHA})
+	r.Wait()
+
+	if len(cm.bodies) != 1 || !strings.Contains(cm.bodies[0], "analysis failed") ||
+		!strings.HasPrefix(cm.bodies[0], CommentMarker) {
+		t.Errorf("expected marked failure comment, got %q", cm.bodies)
+	}
+}
+
+func TestGitCheckouter(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	remote := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = remote
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(remote, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("a.go", "package a\n")
+	write("b.go", "package a\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "base")
+	run("checkout", "-q", "-b", "feature")
+	write("b.go", "package a\n\nfunc B() {}\n")
+	write("c.go", "package a\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "change")
+	head := run("rev-parse", "HEAD")
+	run("update-ref", "refs/pull/5/head", head)
+	run("checkout", "-q", "main")
+
+	g := NewGitCheckouter(t.TempDir(), nil)
+	g.allowLocal = true
+	ev := &PREvent{
+		Provider: ProviderGitHub,
+		Action:   ActionOpened,
+		Repo:     "acme/app",
+		Number:   5,
+		CloneURL: "file://" + remote,
+		BaseRef:  "main",
+		HeadSHA:  head,
+	}
+
+	co, err := g.Checkout(context.Background(), ev)
+	if err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if !reflect.DeepEqual(co.ChangedFiles, []string{"b.go", "c.go"}) {
+		t.Errorf("ChangedFiles = %v", co.ChangedFiles)
+	}
+	data, err := os.ReadFile(filepath.Join(co.Dir, "b.go"))
+	if err != nil || !strings.Contains(string(data), "func B") {
+		t.Errorf("head not checked out: %q, %v", data, err)
+	}
+
+	// A second checkout reuses the same directory.
+	co2, err := g.Checkout(context.Background(), ev)
+	if err != nil || co2.Dir != co.Dir {
+		t.Errorf("re-checkout: dir %q vs %q, err %v", co2.Dir, co.Dir, err)
+	}
+
+	if err := g.Remove(ev); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(co.Dir); !os.IsNotExist(err) {
+		t.Errorf("checkout dir not removed")
+	}
+
+	t.Run("rejects file URLs by default", func(t *testing.T) {
+		_, err := NewGitCheckouter(t.TempDir(), nil).Checkout(context.Background(), ev)
+		if !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("expected ErrInvalidPayload, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package code_buddy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lint"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/webhook"
+)
+
+// webhookRiskRank orders risk levels for picking the PR's overall risk.
+var webhookRiskRank = map[analysis.RiskLevel]int{
+	analysis.RiskLow:      0,
+	analysis.RiskMedium:   1,
+	analysis.RiskHigh:     2,
+	analysis.RiskCritical: 3,
+}
+
+// WebhookAnalyzer runs the impact/risk/lint pipeline for PR webhooks.
+//
+// Description:
+//
+//	Indexes the PR checkout with the service's graph cache (the checkout
+//	path is stable per PR, so each push refreshes the same graph), computes
+//	the blast radius of every symbol in the changed files, and lints the
+//	changed files. The PR risk is the highest per-symbol risk.
+//
+// Thread Safety: Safe for concurrent use.
+type WebhookAnalyzer struct {
+	svc       *Service
+	languages []string
+	lint      *lint.LintRunner
+}
+
+// NewWebhookAnalyzer creates the analyzer used by the webhook receiver.
+//
+// Inputs:
+//
+//	svc - The service whose graph cache is used. Its AllowedRoots (if set)
+//	      must include the webhook work directory.
+//	languages - Languages to index (default: ["go"]).
+//
+// Outputs:
+//
+//	*WebhookAnalyzer - The analyzer
+func NewWebhookAnalyzer(svc *Service, languages []string) *WebhookAnalyzer {
+	return &WebhookAnalyzer{
+		svc:       svc,
+		languages: languages,
+		lint:      lint.NewLintRunner(),
+	}
+}
+
+// Analyze implements webhook.Analyzer.
+func (a *WebhookAnalyzer) Analyze(ctx context.Context, co *webhook.Checkout, ev *webhook.PREvent) (*webhook.Report, error) {
+	logger := slog.With("component", "webhook_analyzer", "pr", ev.Key())
+	report := &webhook.Report{}
+
+	// Test files are kept in the graph so blast radius can report them.
+	initResp, err := a.svc.Init(ctx, co.Dir, a.languages, []string{"vendor/*"})
+	if err != nil {
+		return nil, fmt.Errorf("indexing checkout: %w", err)
+	}
+	cached, err := a.svc.GetGraph(initResp.GraphID)
+	if err != nil {
+		return nil, err
+	}
+
+	blast := cached.blastRadiusAnalyzer()
+	overall := analysis.RiskLow
+	tests := make(map[string]bool)
+
+	for _, file := range co.ChangedFiles {
+		for _, sym := range cached.Index.GetByFile(file) {
+			if !sym.Kind.IsDocumentable() {
+				continue
+			}
+			br, err := blast.Analyze(ctx, sym.ID, nil)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				logger.Debug("blast radius failed", "symbol", sym.ID, "error", err)
+				continue
+			}
+			if webhookRiskRank[br.RiskLevel] > webhookRiskRank[overall] {
+				overall = br.RiskLevel
+			}
+			for _, t := range br.TestFiles {
+				tests[t] = true
+			}
+			report.Impacts = append(report.Impacts, webhook.SymbolImpact{
+				Symbol:          sym.Name,
+				FilePath:        sym.FilePath,
+				RiskLevel:       string(br.RiskLevel),
+				DirectCallers:   len(br.DirectCallers),
+				IndirectCallers: len(br.IndirectCallers),
+				FilesAffected:   len(br.FilesAffected),
+			})
+		}
+	}
+
+	sort.SliceStable(report.Impacts, func(i, j int) bool {
+		a, b := report.Impacts[i], report.Impacts[j]
+		if a.DirectCallers+a.IndirectCallers != b.DirectCallers+b.IndirectCallers {
+			return a.DirectCallers+a.IndirectCallers > b.DirectCallers+b.IndirectCallers
+		}
+		return a.Symbol < b.Symbol
+	})
+	report.RiskLevel = string(overall)
+
+	for t := range tests {
+		report.AffectedTests = append(report.AffectedTests, t)
+	}
+	sort.Strings(report.AffectedTests)
+
+	report.LintIssues, report.Warnings = a.lintFiles(ctx, co)
+	return report, nil
+}
+
+// lintFiles lints the changed files under the project's lint profile.
+// Unsupported languages and missing linters are skipped; other failures
+// become report warnings.
+func (a *WebhookAnalyzer) lintFiles(ctx context.Context, co *webhook.Checkout) ([]webhook.LintIssue, []string) {
+	var issues []webhook.LintIssue
+	var warnings []string
+	profile := lint.Profile(a.svc.ProjectConfig(co.Dir).EffectiveLintProfile())
+
+	for _, file := range co.ChangedFiles {
+		result, err := a.lint.Lint(ctx, filepath.Join(co.Dir, file))
+		if err != nil {
+			if errors.Is(err, lint.ErrUnsupportedLanguage) || errors.Is(err, lint.ErrLinterNotInstalled) {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("lint %s: %v", file, err))
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		lint.ApplyProfile(result, profile)
+		for _, group := range [][]lint.LintIssue{result.Errors, result.Warnings} {
+			for _, issue := range group {
+				issues = append(issues, webhook.LintIssue{
+					FilePath: file,
+					Line:     issue.Line,
+					Severity: issue.Severity.String(),
+					Rule:     issue.Rule,
+					Message:  issue.Message,
+				})
+			}
+		}
+	}
+	return issues, warnings
+}
@@ -0,0 +1,493 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package workspace manages session-scoped scratch workspaces.
+//
+// Each agent session gets an isolated git worktree of the user's repository
+// in which candidate patches can be applied, tests run, and the result
+// diffed against the user's tree without touching the user's checkout. The
+// worktree is created once per session and reset between uses, so TDG runs
+// and MCTS simulations in the same session share it instead of paying for a
+// fresh checkout each time.
+//
+// The worktree starts from the user's current tree: HEAD plus uncommitted
+// changes to tracked files (captured with `git stash create`, which does not
+// modify the user's checkout). Untracked files are not included.
+//
+// Thread Safety: Manager is safe for concurrent use. A Workspace must be
+// used through Workspace.Use, which serializes callers.
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/transaction"
+)
+
+var (
+	// ErrNotGitRepository is returned when the project root is not a git repository.
+	ErrNotGitRepository = errors.New("not a git repository")
+
+	// ErrInvalidSessionID is returned for session IDs that cannot name a directory.
+	ErrInvalidSessionID = errors.New("invalid session id")
+
+	// ErrManagerClosed is returned after Close.
+	ErrManagerClosed = errors.New("workspace manager is closed")
+
+	// ErrPatchFailed is returned when a patch does not apply.
+	ErrPatchFailed = errors.New("patch does not apply")
+)
+
+// DefaultGitTimeout bounds each git command.
+const DefaultGitTimeout = 2 * time.Minute
+
+// validSessionID restricts session IDs to safe directory names.
+var validSessionID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Config configures a Manager.
+type Config struct {
+	// BaseDir is the directory that holds the worktrees, one per session.
+	// Default: <os.TempDir()>/aleutian-workspaces/<repo hash>
+	BaseDir string
+
+	// GitTimeout bounds each git command. Default: DefaultGitTimeout.
+	GitTimeout time.Duration
+
+	// HeadOnly starts worktrees from HEAD, ignoring uncommitted changes in
+	// the user's tree. Default: false.
+	HeadOnly bool
+}
+
+// Manager creates and tracks one worktree per agent session.
+//
+// Thread Safety: Safe for concurrent use.
+type Manager struct {
+	repoRoot string
+	config   Config
+	git      *transaction.DefaultGitClient
+	logger   *slog.Logger
+
+	mu         sync.Mutex
+	workspaces map[string]*Workspace
+	closed     bool
+}
+
+// NewManager creates a workspace manager for a repository.
+//
+// Inputs:
+//
+//   - repoRoot: Absolute path to the user's repository (any directory
+//     inside the work tree).
+//   - config: Manager configuration. Zero values use defaults.
+//
+// Outputs:
+//
+//   - *Manager: The manager.
+//   - error: ErrNotGitRepository if repoRoot is not inside a git work tree.
+func NewManager(ctx context.Context, repoRoot string, config Config) (*Manager, error) {
+	if !filepath.IsAbs(repoRoot) {
+		return nil, fmt.Errorf("repoRoot must be absolute: %s", repoRoot)
+	}
+	if config.GitTimeout <= 0 {
+		config.GitTimeout = DefaultGitTimeout
+	}
+
+	top, err := runGit(ctx, repoRoot, config.GitTimeout, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotGitRepository, repoRoot)
+	}
+
+	if config.BaseDir == "" {
+		sum := sha256.Sum256([]byte(top))
+		config.BaseDir = filepath.Join(os.TempDir(), "aleutian-workspaces", hex.EncodeToString(sum[:8]))
+	}
+
+	git, err := transaction.NewGitClient(top, config.GitTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		repoRoot:   top,
+		config:     config,
+		git:        git,
+		logger:     slog.Default().With(slog.String("component", "workspace"), slog.String("repo", top)),
+		workspaces: make(map[string]*Workspace),
+	}, nil
+}
+
+// RepoRoot returns the top level of the user's repository.
+func (m *Manager) RepoRoot() string {
+	return m.repoRoot
+}
+
+// Acquire returns the session's workspace, creating it on first use.
+//
+// Description:
+//
+//	Later calls for the same session return the same workspace; callers
+//	reset it through Workspace.Use. A worktree left on disk by a previous
+//	process for the same session is removed and recreated.
+//
+// Inputs:
+//
+//   - ctx: Context for cancellation.
+//   - sessionID: The agent session. Must match [A-Za-z0-9._-]{1,128}.
+//
+// Outputs:
+//
+//   - *Workspace: The session's workspace.
+//   - error: Non-nil if the worktree could not be created.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *Manager) Acquire(ctx context.Context, sessionID string) (*Workspace, error) {
+	if !validSessionID.MatchString(sessionID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+	if ws, ok := m.workspaces[sessionID]; ok {
+		return ws, nil
+	}
+
+	ws, err := m.create(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	m.workspaces[sessionID] = ws
+	return ws, nil
+}
+
+// create adds the worktree for a session. Caller must hold m.mu.
+func (m *Manager) create(ctx context.Context, sessionID string) (*Workspace, error) {
+	path := filepath.Join(m.config.BaseDir, sessionID)
+
+	// A stale worktree from a crashed process would make `worktree add` fail.
+	if _, err := os.Stat(path); err == nil {
+		m.logger.Info("removing stale workspace", slog.String("path", path))
+		if err := m.removeWorktree(ctx, path); err != nil {
+			return nil, err
+		}
+	}
+
+	baseRef, err := m.baseRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(m.config.BaseDir, 0750); err != nil {
+		return nil, fmt.Errorf("create workspace dir: %w", err)
+	}
+	if err := m.git.CreateWorktree(ctx, path, baseRef); err != nil {
+		return nil, fmt.Errorf("create worktree: %w", err)
+	}
+
+	m.logger.Info("workspace created",
+		slog.String("session_id", sessionID),
+		slog.String("path", path),
+		slog.String("base_ref", baseRef),
+	)
+
+	return &Workspace{
+		sessionID: sessionID,
+		path:      path,
+		baseRef:   baseRef,
+		timeout:   m.config.GitTimeout,
+	}, nil
+}
+
+// baseRef returns the commit to start a worktree from: a stash commit of
+// the user's tracked changes if there are any, otherwise HEAD.
+func (m *Manager) baseRef(ctx context.Context) (string, error) {
+	if !m.config.HeadOnly {
+		stash, err := runGit(ctx, m.repoRoot, m.config.GitTimeout, nil, "stash", "create")
+		if err != nil {
+			return "", fmt.Errorf("snapshot working tree: %w", err)
+		}
+		if stash != "" {
+			return stash, nil
+		}
+	}
+	head, err := m.git.RevParse(ctx, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("resolve HEAD: %w", err)
+	}
+	return head, nil
+}
+
+// Get returns the session's workspace if it exists.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *Manager) Get(sessionID string) (*Workspace, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ws, ok := m.workspaces[sessionID]
+	return ws, ok
+}
+
+// Release removes the session's worktree. Releasing an unknown session is
+// a no-op.
+//
+// Description:
+//
+//	Waits for any in-progress Use of the workspace to finish before
+//	removing it. Call when the agent session ends.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *Manager) Release(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	ws, ok := m.workspaces[sessionID]
+	delete(m.workspaces, sessionID)
+	m.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.released = true
+
+	if err := m.removeWorktree(ctx, ws.path); err != nil {
+		return err
+	}
+	m.logger.Info("workspace released",
+		slog.String("session_id", sessionID),
+		slog.String("path", ws.path),
+	)
+	return nil
+}
+
+// removeWorktree force-removes a worktree, falling back to deleting the
+// directory and pruning git's worktree metadata.
+func (m *Manager) removeWorktree(ctx context.Context, path string) error {
+	if err := m.git.RemoveWorktree(ctx, path, true); err != nil {
+		m.logger.Debug("worktree remove failed, deleting directory",
+			slog.String("path", path),
+			slog.String("error", err.Error()),
+		)
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("remove workspace: %w", err)
+		}
+		if _, err := runGit(ctx, m.repoRoot, m.config.GitTimeout, nil, "worktree", "prune"); err != nil {
+			return fmt.Errorf("prune worktrees: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyToRepo applies a unified diff to the user's working tree.
+//
+// Description:
+//
+//	Merges a change produced in a workspace (see Workspace.Diff) back into
+//	the user's checkout. git apply is atomic: if any hunk does not apply,
+//	the user's tree is left unchanged. Callers merging several patches
+//	must serialize the calls themselves.
+//
+// Outputs:
+//
+//   - error: Wraps ErrPatchFailed if the patch does not apply.
+func (m *Manager) ApplyToRepo(ctx context.Context, patch string) error {
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+	if _, err := runGit(ctx, m.repoRoot, m.config.GitTimeout, strings.NewReader(patch), "apply", "--whitespace=nowarn", "-"); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatchFailed, err)
+	}
+	return nil
+}
+
+// Close releases every workspace and rejects further Acquire calls.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	sessions := make([]string, 0, len(m.workspaces))
+	for id := range m.workspaces {
+		sessions = append(sessions, id)
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, id := range sessions {
+		if err := m.Release(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("release %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Workspace is one session's scratch worktree.
+//
+// Thread Safety: Use serializes access; the methods on Workspace other than
+// the accessors must only be called inside a Use callback.
+type Workspace struct {
+	sessionID string
+	path      string
+	baseRef   string
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	released bool
+}
+
+// SessionID returns the owning session.
+func (w *Workspace) SessionID() string {
+	return w.sessionID
+}
+
+// Path returns the worktree directory. Pass it as the project root to
+// test runners.
+func (w *Workspace) Path() string {
+	return w.path
+}
+
+// BaseRef returns the commit the worktree was created from, which
+// represents the user's tree at creation time.
+func (w *Workspace) BaseRef() string {
+	return w.baseRef
+}
+
+// Use runs fn with exclusive access to a clean workspace.
+//
+// Description:
+//
+//	Waits for other users of the workspace, resets it to BaseRef, and
+//	calls fn. Changes made by fn are left in place until the next Use or
+//	Reset, so fn should read results (Diff, test output) before returning.
+//
+// Inputs:
+//
+//   - ctx: Context for cancellation.
+//   - fn: Work to do in the workspace.
+//
+// Outputs:
+//
+//   - error: The reset error, or fn's error.
+func (w *Workspace) Use(ctx context.Context, fn func(*Workspace) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.released {
+		return ErrManagerClosed
+	}
+	if err := w.Reset(ctx); err != nil {
+		return err
+	}
+	return fn(w)
+}
+
+// Reset discards all changes, restoring the worktree to BaseRef. Ignored
+// files (build caches) are kept.
+func (w *Workspace) Reset(ctx context.Context) error {
+	if _, err := runGit(ctx, w.path, w.timeout, nil, "reset", "--hard", "--quiet", w.baseRef); err != nil {
+		return fmt.Errorf("reset workspace: %w", err)
+	}
+	if _, err := runGit(ctx, w.path, w.timeout, nil, "clean", "-fd", "--quiet"); err != nil {
+		return fmt.Errorf("clean workspace: %w", err)
+	}
+	return nil
+}
+
+// ApplyPatch applies a unified diff (paths relative to the repository root).
+//
+// Outputs:
+//
+//   - error: Wraps ErrPatchFailed if the patch does not apply; the
+//     workspace is unchanged in that case.
+func (w *Workspace) ApplyPatch(ctx context.Context, patch string) error {
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+	if _, err := runGit(ctx, w.path, w.timeout, strings.NewReader(patch), "apply", "--whitespace=nowarn", "-"); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatchFailed, err)
+	}
+	return nil
+}
+
+// Diff returns the changes in the workspace relative to BaseRef (the
+// user's tree), including new files, as a unified diff.
+func (w *Workspace) Diff(ctx context.Context) (string, error) {
+	if _, err := runGit(ctx, w.path, w.timeout, nil, "add", "-A"); err != nil {
+		return "", fmt.Errorf("stage workspace: %w", err)
+	}
+	out, err := runGit(ctx, w.path, w.timeout, nil, "diff", "--cached", "--binary", w.baseRef)
+	if err != nil {
+		return "", fmt.Errorf("diff workspace: %w", err)
+	}
+	if out != "" {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// ChangedFiles returns the paths changed relative to BaseRef.
+func (w *Workspace) ChangedFiles(ctx context.Context) ([]string, error) {
+	if _, err := runGit(ctx, w.path, w.timeout, nil, "add", "-A"); err != nil {
+		return nil, fmt.Errorf("stage workspace: %w", err)
+	}
+	out, err := runGit(ctx, w.path, w.timeout, nil, "diff", "--cached", "--name-only", w.baseRef)
+	if err != nil {
+		return nil, fmt.Errorf("diff workspace: %w", err)
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// runGit runs a git command in dir and returns trimmed stdout.
+func runGit(ctx context.Context, dir string, timeout time.Duration, stdin io.Reader, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("git %s: timeout after %v", args[0], timeout)
+		}
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupRepo creates a git repository with one committed file.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		if _, err := runGit(ctx, dir, time.Minute, nil, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	if _, err := runGit(ctx, dir, time.Minute, nil, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(ctx, dir, time.Minute, nil, "commit", "-q", "-m", "init"); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func newTestManager(t *testing.T, repo string) *Manager {
+	t.Helper()
+	m, err := NewManager(context.Background(), repo, Config{BaseDir: filepath.Join(t.TempDir(), "ws")})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	t.Cleanup(func() { m.Close(context.Background()) })
+	return m
+}
+
+const addLinePatch = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1,2 @@
+ package main
++// patched
+`
+
+func TestManager_Acquire(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("includes uncommitted changes and isolates the user tree", func(t *testing.T) {
+		repo := setupRepo(t)
+		writeFile(t, filepath.Join(repo, "main.go"), "package main\n\nfunc main() {}\n")
+		m := newTestManager(t, repo)
+
+		ws, err := m.Acquire(ctx, "s1")
+		if err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+		if got := readFile(t, filepath.Join(ws.Path(), "main.go")); !strings.Contains(got, "func main") {
+			t.Errorf("workspace missing uncommitted change: %q", got)
+		}
+
+		err = ws.Use(ctx, func(w *Workspace) error {
+			writeFile(t, filepath.Join(w.Path(), "new.go"), "package main\n")
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(repo, "new.go")); !os.IsNotExist(err) {
+			t.Error("workspace change leaked into user tree")
+		}
+	})
+
+	t.Run("reuses the session workspace", func(t *testing.T) {
+		m := newTestManager(t, setupRepo(t))
+		a, err := m.Acquire(ctx, "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := m.Acquire(ctx, "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b {
+			t.Error("Acquire returned a different workspace for the same session")
+		}
+		c, err := m.Acquire(ctx, "s2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Path() == a.Path() {
+			t.Error("sessions share a worktree")
+		}
+	})
+
+	t.Run("rejects unsafe session ids", func(t *testing.T) {
+		m := newTestManager(t, setupRepo(t))
+		for _, id := range []string{"", "../x", "a/b"} {
+			if _, err := m.Acquire(ctx, id); !errors.Is(err, ErrInvalidSessionID) {
+				t.Errorf("Acquire(%q) error = %v, want ErrInvalidSessionID", id, err)
+			}
+		}
+	})
+
+	t.Run("not a repository", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git not available")
+		}
+		_, err := NewManager(ctx, t.TempDir(), Config{})
+		if !errors.Is(err, ErrNotGitRepository) {
+			t.Errorf("error = %v, want ErrNotGitRepository", err)
+		}
+	})
+}
+
+func TestWorkspace_PatchDiffReset(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, setupRepo(t))
+	ws, err := m.Acquire(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ws.Use(ctx, func(w *Workspace) error {
+		if err := w.ApplyPatch(ctx, addLinePatch); err != nil {
+			return err
+		}
+		writeFile(t, filepath.Join(w.Path(), "extra.go"), "package main\n")
+
+		diff, err := w.Diff(ctx)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(diff, "+// patched") || !strings.Contains(diff, "b/extra.go") {
+			t.Errorf("diff missing changes:\n%s", diff)
+		}
+		files, err := w.ChangedFiles(ctx)
+		if err != nil {
+			return err
+		}
+		if len(files) != 2 {
+			t.Errorf("changed files = %v, want 2", files)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+
+	// The next Use starts from a clean tree.
+	err = ws.Use(ctx, func(w *Workspace) error {
+		if _, err := os.Stat(filepath.Join(w.Path(), "extra.go")); !os.IsNotExist(err) {
+			t.Error("untracked file survived reset")
+		}
+		if diff, _ := w.Diff(ctx); diff != "" {
+			t.Errorf("diff after reset = %q, want empty", diff)
+		}
+		if err := w.ApplyPatch(ctx, "diff --git a/nope.go b/nope.go\n--- a/nope.go\n+++ b/nope.go\n@@ -1 +1 @@\n-x\n+y\n"); !errors.Is(err, ErrPatchFailed) {
+			t.Errorf("ApplyPatch error = %v, want ErrPatchFailed", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+}
+
+func TestManager_Release(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, setupRepo(t))
+	ws, err := m.Acquire(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Release(ctx, "s1"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(ws.Path()); !os.IsNotExist(err) {
+		t.Error("worktree directory still exists")
+	}
+	if _, ok := m.Get("s1"); ok {
+		t.Error("released workspace still tracked")
+	}
+	if err := ws.Use(ctx, func(*Workspace) error { return nil }); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("Use after release error = %v, want ErrManagerClosed", err)
+	}
+	if err := m.Release(ctx, "unknown"); err != nil {
+		t.Errorf("Release(unknown) = %v, want nil", err)
+	}
+
+	if err := m.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Acquire(ctx, "s2"); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("Acquire after Close error = %v, want ErrManagerClosed", err)
+	}
+}
+
+func TestManager_ApplyToRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := setupRepo(t)
+	m := newTestManager(t, repo)
+
+	if err := m.ApplyToRepo(ctx, addLinePatch); err != nil {
+		t.Fatalf("ApplyToRepo: %v", err)
+	}
+	if got := readFile(t, filepath.Join(repo, "main.go")); got != "package main\n// patched\n" {
+		t.Errorf("user tree after apply = %q", got)
+	}
+
+	// The same patch no longer applies and must leave the tree unchanged.
+	if err := m.ApplyToRepo(ctx, addLinePatch); !errors.Is(err, ErrPatchFailed) {
+		t.Errorf("second ApplyToRepo error = %v, want ErrPatchFailed", err)
+	}
+	if got := readFile(t, filepath.Join(repo, "main.go")); got != "package main\n// patched\n" {
+		t.Errorf("user tree changed by failed apply: %q", got)
+	}
+}